@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/secrets"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// checkDialTimeout bounds how long the mail server checks wait for a connection
+const checkDialTimeout = 5 * time.Second
+
+// checkResult is the outcome of one self-check: detail describes what passed,
+// problems what did not
+type checkResult struct {
+	name     string
+	detail   string
+	problems []string
+}
+
+// runChecks validates the configuration and the resources it points at.
+// configFile may be empty when configured from the environment. The database
+// check is skipped when withDatabase is false, e.g. at startup where the
+// connection has already been made.
+func runChecks(cfg *config.Config, configFile string, withDatabase bool) []checkResult {
+	var results []checkResult
+
+	if configFile != "" {
+		result := checkResult{name: "Config file", detail: configFile}
+		keys, err := config.UnknownKeys(configFile)
+		if err != nil {
+			result.problems = append(result.problems, err.Error())
+		}
+		for _, key := range keys {
+			result.problems = append(result.problems, "unknown key "+key)
+		}
+		results = append(results, result)
+	}
+
+	results = append(results, checkResult{name: "Settings", detail: "valid", problems: cfg.Validate()})
+
+	if key, err := cfg.GetEncryptionKey(); err != nil || key != "" {
+		result := checkResult{name: "Encryption key", detail: "valid"}
+		if err == nil {
+			_, err = secrets.NewKeyring(key, cfg.Encryption.PreviousKeys...)
+		}
+		if err != nil {
+			result.problems = append(result.problems, err.Error())
+		}
+		results = append(results, result)
+	}
+
+	if withDatabase {
+		result := checkResult{name: "Database", detail: "connected"}
+		if err := checkDatabase(cfg.DatabaseURL()); err != nil {
+			result.problems = append(result.problems, err.Error())
+		}
+		results = append(results, result)
+	}
+
+	dirs := []string{"./attachments"}
+	if cfg.Tenancy.Enabled {
+		dirs = append(dirs, cfg.GetTenantDataDir())
+	}
+	for _, dir := range dirs {
+		result := checkResult{name: "Storage " + dir, detail: "writable"}
+		if err := checkWritableDir(dir); err != nil {
+			result.problems = append(result.problems, err.Error())
+		}
+		results = append(results, result)
+	}
+
+	if cfg.Plugins.Dir != "" {
+		result := checkResult{name: "Plugins " + cfg.Plugins.Dir, detail: "compiled"}
+		if _, err := services.NewPluginService(nil, cfg.Plugins.Dir, cfg.GetPluginTimeout()); err != nil {
+			result.problems = append(result.problems, err.Error())
+		}
+		results = append(results, result)
+	}
+
+	if cfg.Email.IMAPHost != "" {
+		results = append(results, checkReachable("IMAP server", cfg.Email.IMAPHost, cfg.Email.IMAPPort))
+	}
+	if cfg.Email.SMTPHost != "" {
+		results = append(results, checkReachable("SMTP server", cfg.Email.SMTPHost, cfg.Email.SMTPPort))
+	}
+
+	return results
+}
+
+// checkDatabase connects to the database and pings it
+func checkDatabase(dbURL string) error {
+	db, err := openDatabase(dbURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+	if err := sqlDB.Ping(); err != nil {
+		return fmt.Errorf("failed to ping: %w", err)
+	}
+	return nil
+}
+
+// checkWritableDir reports whether files can be written to dir, or to the
+// directory it would be created in if it does not exist yet
+func checkWritableDir(dir string) error {
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		parent := filepath.Dir(filepath.Clean(dir))
+		if err := checkWritableDir(parent); err != nil {
+			return fmt.Errorf("%s does not exist and cannot be created: %w", dir, err)
+		}
+		return nil
+	case err != nil:
+		return err
+	case !info.IsDir():
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	file, err := os.CreateTemp(dir, ".jats-check-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", dir, err)
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// checkReachable opens a TCP connection to a server
+func checkReachable(name, host, port string) checkResult {
+	address := net.JoinHostPort(host, port)
+	result := checkResult{name: name, detail: address + " reachable"}
+	conn, err := net.DialTimeout("tcp", address, checkDialTimeout)
+	if err != nil {
+		result.problems = append(result.problems, fmt.Sprintf("%s is unreachable: %v", address, err))
+		return result
+	}
+	conn.Close()
+	return result
+}
+
+// countProblems returns the number of problems the checks found
+func countProblems(results []checkResult) int {
+	count := 0
+	for _, result := range results {
+		count += len(result.problems)
+	}
+	return count
+}
+
+// printCheckReport prints the outcome of every check for jatsd -check
+func printCheckReport(results []checkResult) {
+	fmt.Println("JATS configuration check")
+	fmt.Println("========================")
+	for _, result := range results {
+		if len(result.problems) == 0 {
+			fmt.Printf("✓ %s: %s\n", result.name, result.detail)
+			continue
+		}
+		fmt.Printf("✗ %s\n", result.name)
+		for _, problem := range result.problems {
+			fmt.Printf("    - %s\n", problem)
+		}
+	}
+
+	if problems := countProblems(results); problems > 0 {
+		fmt.Printf("\n%d problem(s) found\n", problems)
+	} else {
+		fmt.Println("\nNo problems found")
+	}
+}
+
+// logCheckProblems logs the problems found by the startup checks as warnings
+func logCheckProblems(results []checkResult) {
+	for _, result := range results {
+		for _, problem := range result.problems {
+			log.Printf("Warning: %s: %s", result.name, problem)
+		}
+	}
+}
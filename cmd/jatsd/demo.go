@@ -0,0 +1,343 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+	"github.com/soarinferret/jats/internal/services"
+	"gorm.io/gorm"
+)
+
+// demoDatabaseURL keeps the demo data in memory; it is gone when jatsd exits
+const demoDatabaseURL = "file:jats-demo?mode=memory&cache=shared"
+
+// demoPassword is the password of every demo user
+const demoPassword = "jats-demo"
+
+// demoUsers are the people tasks are assigned to in the demo, besides jats-admin
+var demoUsers = []string{"alice", "bob", "carol"}
+
+// demoCustomers write in by email
+var demoCustomers = []struct {
+	name, email, organization string
+}{
+	{"Dana Whitfield", "dana@northwind.example", "Northwind Traders"},
+	{"Eli Moreno", "eli@contoso.example", "Contoso"},
+	{"Farah Haddad", "farah@fabrikam.example", "Fabrikam"},
+	{"Gus Lindqvist", "gus@tailspin.example", "Tailspin Toys"},
+}
+
+// demoTasks are the tasks seeded, each with its tags; support tasks arrive by email
+var demoTasks = []struct {
+	name string
+	tags []string
+}{
+	{"Renew TLS certificate for the status page", []string{"infra"}},
+	{"Upgrade PostgreSQL to 16", []string{"infra", "database"}},
+	{"Nightly backup job failing on db-02", []string{"infra", "database"}},
+	{"Rotate the deploy SSH keys", []string{"infra", "security"}},
+	{"Move CI runners to the new cluster", []string{"infra"}},
+	{"Disk usage alert on logs-01", []string{"infra", "ops"}},
+	{"Audit admin accounts for the quarterly review", []string{"security"}},
+	{"Enable MFA for the shared mailbox", []string{"security"}},
+	{"Invoice shows the wrong VAT rate", []string{"support", "billing"}},
+	{"Cannot reset my password", []string{"support"}},
+	{"Export of last month's report is empty", []string{"support", "reports"}},
+	{"Request for an extra user seat", []string{"support", "billing"}},
+	{"Login page is slow in the morning", []string{"support", "performance"}},
+	{"Refund for duplicate charge", []string{"support", "billing"}},
+	{"CSV import skips rows with accents", []string{"support", "bug"}},
+	{"Email notifications arrive twice", []string{"support", "bug"}},
+	{"Redesign the pricing page", []string{"website"}},
+	{"Fix broken links in the docs", []string{"website", "docs"}},
+	{"Write the onboarding guide", []string{"docs"}},
+	{"Update the API reference for v2", []string{"docs"}},
+	{"Add dark mode to the dashboard", []string{"feature"}},
+	{"Weekly report by email", []string{"feature", "reports"}},
+	{"Bulk edit for tags", []string{"feature"}},
+	{"Investigate memory growth in the worker", []string{"bug", "performance"}},
+	{"Timezone off by one hour in reports", []string{"bug", "reports"}},
+	{"Plan the Q3 roadmap", []string{"planning"}},
+	{"Prepare the board update", []string{"planning"}},
+	{"Interview candidates for the SRE role", []string{"hiring"}},
+	{"Set up on-call rotation", []string{"ops"}},
+	{"Tidy up the monitoring dashboards", []string{"ops"}},
+	{"Migrate DNS to the new provider", []string{"infra"}},
+	{"Review vendor contracts", []string{"billing"}},
+	{"Clean up stale feature flags", []string{"bug"}},
+	{"Load test the new search endpoint", []string{"performance"}},
+	{"Document the incident from last Tuesday", []string{"ops", "docs"}},
+	{"Replace the office printer", []string{"support"}},
+}
+
+// demoWork describes the time entries logged on demo tasks
+var demoWork = []string{
+	"Investigation", "Implementation", "Call with the customer", "Code review",
+	"Testing", "Writing it up", "Pairing", "Deploy and verify",
+}
+
+// demoSubtasks are added, with estimates, to some demo tasks
+var demoSubtasks = []string{"Investigate", "Fix", "Test", "Document", "Follow up"}
+
+// applyDemoConfig points a configuration at an in-memory database and turns off
+// everything that reaches outside jatsd
+func applyDemoConfig(cfg *config.Config) {
+	cfg.DBURL = demoDatabaseURL
+	cfg.Email.IMAPHost = ""
+	cfg.Email.SMTPHost = ""
+	cfg.Inbound = nil
+	cfg.Alertmanager = config.AlertmanagerConfig{}
+	cfg.Sync = nil
+	cfg.Tenancy.Enabled = false
+}
+
+// seedDemoData fills a fresh database with a realistic dataset: users, tasks
+// across every status over the last 90 days, time entries, email conversations
+// and saved queries. The data is generated from a fixed seed, so every demo
+// looks the same apart from dates, which are relative to now.
+func seedDemoData(db *gorm.DB, authService *services.AuthService, now time.Time) error {
+	rng := rand.New(rand.NewPCG(3236, 90))
+
+	for _, username := range append([]string{"jats-admin"}, demoUsers...) {
+		if _, err := authService.RegisterUser(username, username+"@jats.example", demoPassword); err != nil {
+			return fmt.Errorf("failed to create demo user %s: %w", username, err)
+		}
+	}
+
+	for _, customer := range demoCustomers {
+		contact := &models.Contact{Name: customer.name, Email: customer.email, Organization: customer.organization}
+		if err := db.Create(contact).Error; err != nil {
+			return fmt.Errorf("failed to create demo contact: %w", err)
+		}
+	}
+
+	milestone := &models.Milestone{Name: "Q3 release", Description: "Everything going out with the Q3 release"}
+	due := now.AddDate(0, 0, 21)
+	milestone.DueDate = &due
+	if err := db.Create(milestone).Error; err != nil {
+		return fmt.Errorf("failed to create demo milestone: %w", err)
+	}
+
+	priorities := []models.TaskPriority{"", models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh}
+	statuses := []models.TaskStatus{
+		models.TaskStatusOpen, models.TaskStatusOpen, models.TaskStatusInProgress, models.TaskStatusInProgress,
+		models.TaskStatusResolved, models.TaskStatusClosed, models.TaskStatusClosed,
+	}
+
+	for i, spec := range demoTasks {
+		createdAt := workingTime(rng, now.AddDate(0, 0, -rng.IntN(88)-2))
+		task := &models.Task{
+			Name:      spec.name,
+			Tags:      spec.tags,
+			Status:    statuses[rng.IntN(len(statuses))],
+			Priority:  priorities[rng.IntN(len(priorities))],
+			Assignee:  demoUsers[rng.IntN(len(demoUsers))],
+			CreatedAt: createdAt,
+		}
+		if i%3 == 0 {
+			task.MilestoneID = &milestone.ID
+		}
+
+		// Status history: open, possibly in progress, then resolved or closed
+		var changes []*models.TaskStatusChange
+		changedAt := createdAt
+		for _, next := range statusPath(task.Status) {
+			changedAt = changedAt.Add(time.Duration(1+rng.IntN(int(now.Sub(changedAt).Hours()/2)+1)) * time.Hour)
+			if changedAt.After(now) {
+				changedAt = now.Add(-time.Hour)
+			}
+			from := models.TaskStatusOpen
+			if len(changes) > 0 {
+				from = changes[len(changes)-1].ToStatus
+			}
+			changes = append(changes, &models.TaskStatusChange{FromStatus: from, ToStatus: next, ChangedAt: changedAt})
+		}
+		if len(changes) > 0 {
+			task.StatusChangedAt = &changedAt
+		}
+		if task.Status == models.TaskStatusResolved || task.Status == models.TaskStatusClosed {
+			task.ResolvedAt = &changedAt
+		}
+		task.UpdatedAt = changedAt
+
+		customer := demoCustomers[rng.IntN(len(demoCustomers))]
+		support := slices.Contains(spec.tags, "support")
+		if support {
+			task.EmailMessageID = fmt.Sprintf("<demo-%d@%s>", i+1, strings.SplitN(customer.email, "@", 2)[1])
+			task.Description = fmt.Sprintf("Hi,\n\n%s. Could you take a look?\n\nThanks,\n%s", spec.name, customer.name)
+		}
+
+		if err := db.Create(task).Error; err != nil {
+			return fmt.Errorf("failed to create demo task: %w", err)
+		}
+		for _, change := range changes {
+			change.TaskID = task.ID
+		}
+		if len(changes) > 0 {
+			if err := db.Create(changes).Error; err != nil {
+				return fmt.Errorf("failed to create demo status history: %w", err)
+			}
+		}
+
+		if err := seedDemoSubtasksAndTime(db, rng, task, now); err != nil {
+			return err
+		}
+		if support {
+			if err := seedDemoConversation(db, rng, task, customer.name, customer.email, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, query := range []*models.SavedQuery{
+		{Name: "Support inbox", IncludedTags: []string{"support"}, Position: 0},
+		{Name: "Infrastructure", IncludedTags: []string{"infra"}, Position: 1},
+		{Name: "Bugs", IncludedTags: []string{"bug"}, ExcludedTags: []string{"support"}, Position: 2},
+	} {
+		token, err := services.GenerateFeedToken()
+		if err != nil {
+			return err
+		}
+		query.FeedToken = token
+		if err := db.Create(query).Error; err != nil {
+			return fmt.Errorf("failed to create demo saved query: %w", err)
+		}
+	}
+
+	// Seeding runs after the startup backfill, so the demo tasks join the default project here
+	if _, err := repository.NewTaskRepository(db).EnsureDefaultProject(); err != nil {
+		return fmt.Errorf("failed to add demo tasks to the default project: %w", err)
+	}
+	return nil
+}
+
+// seedDemoSubtasksAndTime adds subtasks to some tasks and logs time on every
+// task that has been worked on, between its creation and its resolution
+func seedDemoSubtasksAndTime(db *gorm.DB, rng *rand.Rand, task *models.Task, now time.Time) error {
+	var subtasks []*models.Subtask
+	if rng.IntN(2) == 0 {
+		for j := 0; j < 2+rng.IntN(3); j++ {
+			subtasks = append(subtasks, &models.Subtask{
+				TaskID:          task.ID,
+				Name:            demoSubtasks[j],
+				Completed:       task.ResolvedAt != nil || rng.IntN(3) == 0,
+				EstimateMinutes: 30 * (1 + rng.IntN(6)),
+				CreatedAt:       task.CreatedAt,
+			})
+		}
+		if err := db.Create(subtasks).Error; err != nil {
+			return fmt.Errorf("failed to create demo subtasks: %w", err)
+		}
+	}
+
+	if task.Status == models.TaskStatusOpen && rng.IntN(2) == 0 {
+		return nil
+	}
+	end := now
+	if task.ResolvedAt != nil {
+		end = *task.ResolvedAt
+	}
+	span := end.Sub(task.CreatedAt)
+	var entries []*models.TimeEntry
+	for j := 0; j < 1+rng.IntN(6); j++ {
+		entry := &models.TimeEntry{
+			TaskID:      task.ID,
+			Description: demoWork[rng.IntN(len(demoWork))],
+			Duration:    15 * (1 + rng.IntN(12)),
+			CreatedAt:   workingTime(rng, task.CreatedAt.Add(time.Duration(rng.Int64N(int64(span)+1)))),
+		}
+		if len(subtasks) > 0 && rng.IntN(2) == 0 {
+			entry.SubtaskID = &subtasks[rng.IntN(len(subtasks))].ID
+		}
+		if entry.CreatedAt.Before(task.CreatedAt) {
+			entry.CreatedAt = task.CreatedAt.Add(time.Hour)
+		}
+		if entry.CreatedAt.After(now) {
+			entry.CreatedAt = now.Add(-time.Hour)
+		}
+		entries = append(entries, entry)
+	}
+	if err := db.Create(entries).Error; err != nil {
+		return fmt.Errorf("failed to create demo time entries: %w", err)
+	}
+	return nil
+}
+
+// seedDemoConversation records the email a support task was opened by, and a
+// back and forth of public replies and private notes
+func seedDemoConversation(db *gorm.DB, rng *rand.Rand, task *models.Task, name, email string, now time.Time) error {
+	inbound := &models.EmailMessage{
+		MessageID:   task.EmailMessageID,
+		TaskID:      &task.ID,
+		Direction:   models.EmailDirectionInbound,
+		Subject:     task.Name,
+		From:        email,
+		To:          []string{"support@jats.example"},
+		Body:        task.Description,
+		Processed:   true,
+		ProcessedAt: &task.CreatedAt,
+		ReceivedAt:  task.CreatedAt,
+	}
+	if err := db.Create(inbound).Error; err != nil {
+		return fmt.Errorf("failed to create demo email: %w", err)
+	}
+	if err := db.Create(&models.TaskSubscriber{TaskID: task.ID, Email: email}).Error; err != nil {
+		return fmt.Errorf("failed to create demo subscriber: %w", err)
+	}
+	if err := db.Exec("INSERT INTO contact_tasks (contact_id, task_id) SELECT id, ? FROM contacts WHERE email = ?", task.ID, email).Error; err != nil {
+		return fmt.Errorf("failed to link demo contact: %w", err)
+	}
+
+	firstName := strings.Fields(name)[0]
+	comments := []*models.Comment{
+		{Content: fmt.Sprintf("Hi %s, thanks for reaching out. We're looking into it and will get back to you shortly.", firstName)},
+		{Content: "Reproduced on staging, looks related to last week's release.", IsPrivate: true},
+		{Content: "Thanks for the quick reply! Let me know if you need anything else from us.", FromEmail: email},
+	}
+	if task.ResolvedAt != nil {
+		comments = append(comments,
+			&models.Comment{Content: fmt.Sprintf("Hi %s, this is fixed now. Please let us know if you still see the problem.", firstName)},
+			&models.Comment{Content: "Confirmed, all good on our side. Thank you!", FromEmail: email})
+	}
+
+	at := task.CreatedAt
+	for _, comment := range comments {
+		at = at.Add(time.Duration(30+rng.IntN(600)) * time.Minute)
+		if at.After(now) {
+			at = now.Add(-time.Minute)
+		}
+		comment.TaskID = task.ID
+		comment.CreatedAt = at
+	}
+	if err := db.Create(comments).Error; err != nil {
+		return fmt.Errorf("failed to create demo comments: %w", err)
+	}
+	return nil
+}
+
+// statusPath returns the status changes that lead from open to status
+func statusPath(status models.TaskStatus) []models.TaskStatus {
+	switch status {
+	case models.TaskStatusInProgress:
+		return []models.TaskStatus{models.TaskStatusInProgress}
+	case models.TaskStatusResolved:
+		return []models.TaskStatus{models.TaskStatusInProgress, models.TaskStatusResolved}
+	case models.TaskStatusClosed:
+		return []models.TaskStatus{models.TaskStatusInProgress, models.TaskStatusResolved, models.TaskStatusClosed}
+	}
+	return nil
+}
+
+// workingTime moves a time onto a weekday between 9:00 and 17:00
+func workingTime(rng *rand.Rand, t time.Time) time.Time {
+	for t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		t = t.AddDate(0, 0, -1)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 9+rng.IntN(8), rng.IntN(60), 0, 0, t.Location())
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+	"github.com/soarinferret/jats/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSeedDemoData(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrate(db); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	authService := services.NewAuthService(repository.NewAuthRepository(db), nil)
+
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	if err := seedDemoData(db, authService, now); err != nil {
+		t.Fatalf("seedDemoData failed: %v", err)
+	}
+
+	counts := func() map[string]int64 {
+		counts := make(map[string]int64)
+		for name, model := range map[string]interface{}{
+			"users": &models.User{}, "projects": &models.Project{}, "tasks": &models.Task{}, "time entries": &models.TimeEntry{},
+		} {
+			var count int64
+			db.Model(model).Count(&count)
+			counts[name] = count
+		}
+		return counts
+	}
+	seeded := counts()
+	// The data comes from a fixed seed, so with a fixed now it is always the same
+	for name, want := range map[string]int64{
+		"users": int64(1 + len(demoUsers)), "projects": 1, "tasks": int64(len(demoTasks)), "time entries": 79,
+	} {
+		if seeded[name] != want {
+			t.Errorf("Expected %d %s, got %d", want, name, seeded[name])
+		}
+	}
+	var orphans int64
+	db.Model(&models.Task{}).Where("project_id IS NULL").Count(&orphans)
+	if orphans != 0 {
+		t.Errorf("Expected every demo task in the default project, got %d without one", orphans)
+	}
+
+	// The demo users already exist, so a second run is rejected and adds nothing
+	if err := seedDemoData(db, authService, now); err == nil || !strings.Contains(err.Error(), "demo user") {
+		t.Errorf("Expected seeding twice to be rejected, got %v", err)
+	}
+	for name, count := range counts() {
+		if count != seeded[name] {
+			t.Errorf("Expected %d %s after the rejected run, got %d", seeded[name], name, count)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+	"github.com/soarinferret/jats/internal/routes"
+	"github.com/soarinferret/jats/internal/services"
+	"gorm.io/gorm"
+)
+
+// migrate creates or updates the tables of an instance database
+func migrate(db *gorm.DB) error {
+	// Feed tokens are unique, so queries from before they were get one first
+	if err := repository.NewTaskRepository(db).EnsureFeedTokens(services.GenerateFeedToken); err != nil {
+		return err
+	}
+
+	err := db.AutoMigrate(
+		&models.Task{},
+		&models.Subtask{},
+		&models.TimeEntry{},
+		&models.Comment{},
+		&models.EmailMessage{},
+		&models.TaskSubscriber{},
+		&models.Attachment{},
+		&models.SavedQuery{},
+		&models.SavedQuerySchedule{},
+		&models.SavedQueryWebhook{},
+		&models.TaskStatusChange{},
+		&models.TaskReference{},
+		&models.Milestone{},
+		&models.User{},
+		&models.Session{},
+		&models.APIKey{},
+		&models.LoginAttempt{},
+		&models.BrandingSettings{},
+		&models.CannedResponse{},
+		&models.Contact{},
+		&models.QuarantinedEmail{},
+		&models.ExternalIssue{},
+		&models.ExternalComment{},
+		&models.NotificationMute{},
+		&models.OutOfOffice{},
+		&models.Asset{},
+		&models.Project{},
+		&models.TaskReview{},
+		&models.TaskDependency{},
+		&models.EmailReceipt{},
+		&models.ScheduledAction{},
+		&models.AutomationRule{},
+		&models.TaskAssignment{},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Tasks from before projects existed go to the default project
+	_, err = repository.NewTaskRepository(db).EnsureDefaultProject()
+	return err
+}
+
+// instance is one set of JATS services over a database: the main instance or a tenant
+type instance struct {
+	cfg            *config.Config
+	attachmentsDir string
+
+	taskRepo            *repository.TaskRepository
+	authRepo            *repository.AuthRepository
+	settingsService     *services.SettingsService
+	smtpService         *services.SMTPService
+	notificationService *services.NotificationService
+	taskService         *services.TaskService
+	authService         *services.AuthService
+	reportService       *services.ReportService
+	contactService      *services.ContactService
+	spamService         *services.SpamService
+	emailService        *services.EmailService
+	storageService      *services.StorageService
+	inboundService      *services.InboundService
+	syncService         *services.SyncService
+	retentionService    *services.RetentionService
+	privacyService      *services.PrivacyService
+	pluginService       *services.PluginService
+}
+
+// newInstance wires up the services of an instance over db, keeping email
+// attachments under attachmentsDir
+func newInstance(cfg *config.Config, db *gorm.DB, attachmentsDir string) (*instance, error) {
+	in := &instance{cfg: cfg, attachmentsDir: attachmentsDir}
+
+	// Initialize repositories
+	in.taskRepo = repository.NewTaskRepository(db)
+	in.authRepo = repository.NewAuthRepository(db)
+	settingsRepo := repository.NewSettingsRepository(db)
+	contactRepo := repository.NewContactRepository(db)
+	quarantineRepo := repository.NewQuarantineRepository(db)
+
+	// Instance branding is shared by the web UI and notification emails
+	in.settingsService = services.NewSettingsService(settingsRepo)
+
+	// Initialize storage service for email attachments and uploads
+	in.storageService = services.NewStorageService(attachmentsDir)
+	if cfg.Media.TranscodeAudio {
+		in.storageService.SetAudioTranscoder(services.NewAudioTranscoder(cfg.GetFFmpegPath()))
+	}
+
+	// Initialize SMTP service for sending notifications
+	in.smtpService = services.NewSMTPService(&cfg.Email)
+	in.smtpService.Templates().SetBrandingSource(in.settingsService)
+
+	// Initialize notification service
+	in.notificationService = services.NewNotificationService(in.taskRepo, in.authRepo, in.smtpService)
+
+	// Initialize services with notification support
+	in.taskService = services.NewTaskService(in.taskRepo, in.notificationService)
+	if err := in.taskService.SetWIPLimits(cfg.Kanban.WIPLimits, cfg.Kanban.EnforceWIPLimits); err != nil {
+		return nil, fmt.Errorf("invalid kanban configuration: %w", err)
+	}
+	in.taskService.SetAgingDays(cfg.Kanban.AgingDays)
+	in.taskService.SetNonBillableTags(cfg.Billing.NonBillableTags)
+	in.taskService.SetRuleSource(in.settingsService)
+	if err := in.taskService.SetAssignmentPolicies(cfg.Assignment); err != nil {
+		return nil, fmt.Errorf("invalid assignment configuration: %w", err)
+	}
+	if err := in.taskService.SetReviewPolicies(cfg.Review); err != nil {
+		return nil, fmt.Errorf("invalid review configuration: %w", err)
+	}
+	in.authService = services.NewAuthService(in.authRepo, nil)
+	in.reportService = services.NewReportService(in.taskRepo)
+	in.contactService = services.NewContactService(contactRepo)
+
+	// Spam filtering is optional; the quarantine list is always available to admins
+	var spamChecker services.SpamChecker
+	if cfg.Spam.RspamdURL != "" {
+		spamChecker = services.NewRspamdClient(cfg.Spam.RspamdURL, cfg.Spam.RspamdPassword, cfg.GetSpamTimeout())
+	}
+	in.spamService = services.NewSpamService(spamChecker, quarantineRepo, &cfg.Spam)
+
+	// The email service also backs the admin inbound email simulator, so it
+	// exists even when the mailbox is not polled
+	in.emailService = services.NewEmailService(in.taskService, in.taskRepo, in.authRepo, in.storageService, cfg)
+	in.emailService.SetContactRecorder(in.contactService)
+	in.emailService.SetSpamFilter(in.spamService)
+
+	// Webhook channels that let monitoring systems open and resolve tasks
+	var err error
+	in.inboundService, err = services.NewInboundService(in.taskService, cfg.Inbound)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inbound configuration: %w", err)
+	}
+	if err := in.inboundService.SetAlertmanager(cfg.Alertmanager); err != nil {
+		return nil, fmt.Errorf("invalid alertmanager configuration: %w", err)
+	}
+
+	// Mirrors saved queries into external issue trackers
+	in.syncService, err = services.NewSyncService(in.taskService, in.taskRepo, cfg.Sync)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sync configuration: %w", err)
+	}
+
+	in.retentionService = services.NewRetentionService(in.taskRepo, in.authRepo, &cfg.Retention)
+	in.privacyService = services.NewPrivacyService(repository.NewPrivacyRepository(db))
+
+	// Operator scripts hooked into task events and serving their own routes
+	in.pluginService, err = services.NewPluginService(in.taskService, cfg.Plugins.Dir, cfg.GetPluginTimeout())
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugins: %w", err)
+	}
+
+	return in, nil
+}
+
+// registerJobs registers the instance's background jobs, with names prefixed
+// by prefix, and starts running plugins on task events. The retention janitor
+// always runs; jobs that send mail only run when outgoing mail is configured.
+func (in *instance) registerJobs(jobRunner *services.JobRunner, prefix string) {
+	in.pluginService.Start()
+	jobRunner.Every(prefix+"retention", in.cfg.GetRetentionInterval(), in.retentionService.Run)
+	automationService := services.NewAutomationService(in.taskService, in.notificationService, in.cfg.Automation)
+	if automationService.Enabled() {
+		jobRunner.Every(prefix+"automation", in.cfg.GetAutomationInterval(), automationService.Run)
+	}
+	jobRunner.Every(prefix+"issue-sync", time.Minute, in.syncService.Run)
+	jobRunner.Every(prefix+"unmute", time.Minute, in.taskService.ExpireMutes)
+	jobRunner.Every(prefix+"scheduled-actions", time.Minute, in.taskService.RunScheduledActions)
+	jobRunner.Every(prefix+"saved-query-webhooks", time.Minute, services.NewSavedQueryExporter(in.taskService).Run)
+	if in.cfg.Email.SMTPHost != "" && in.cfg.Email.FromEmail != "" {
+		standupMailer := services.NewStandupMailer(in.reportService, in.authRepo, in.smtpService, in.cfg.Email.StandupHour)
+		jobRunner.Every(prefix+"standup-email", time.Minute, standupMailer.Run)
+
+		savedQueryReporter := services.NewSavedQueryReporter(in.taskService, in.smtpService)
+		jobRunner.Every(prefix+"saved-query-reports", time.Minute, savedQueryReporter.Run)
+
+		in.taskService.SetEmailSender(in.smtpService)
+		in.taskService.SetTrackingURL(in.cfg.Email.TrackingURL)
+	}
+}
+
+// handler sets up the instance's routes. tenantService is only given to the
+// main instance of a multi-tenant jatsd.
+func (in *instance) handler(accessLog *middleware.AccessLogger, tenantService *services.TenantService) http.Handler {
+	return routes.SetupRoutes(routes.Services{
+		TaskService:      in.taskService,
+		AuthService:      in.authService,
+		AuthRepo:         in.authRepo,
+		ReportService:    in.reportService,
+		SettingsService:  in.settingsService,
+		ContactService:   in.contactService,
+		SpamService:      in.spamService,
+		EmailService:     in.emailService,
+		InboundService:   in.inboundService,
+		RetentionService: in.retentionService,
+		PrivacyService:   in.privacyService,
+		SyncService:      in.syncService,
+		AccessLog:        accessLog,
+		AttachmentsDir:   in.attachmentsDir,
+		StorageService:   in.storageService,
+		CaptureService:   services.NewCaptureService(in.taskService, in.storageService, in.cfg.Capture.Places),
+		TenantService:    tenantService,
+		PluginService:    in.pluginService,
+	})
+}
@@ -7,24 +7,33 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"golang.org/x/term"
 	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/repository"
-	"github.com/soarinferret/jats/internal/routes"
+	"github.com/soarinferret/jats/internal/secrets"
 	"github.com/soarinferret/jats/internal/services"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
+// isSQLite reports whether a database URL is for SQLite rather than PostgreSQL
+func isSQLite(dbURL string) bool {
+	return strings.HasPrefix(dbURL, "sqlite:") || strings.HasSuffix(dbURL, ".db") || strings.Contains(dbURL, "file:")
+}
+
 // openDatabase opens a database connection with the appropriate driver based on the URL scheme
 func openDatabase(dbURL string) (*gorm.DB, error) {
 	// Detect database type from URL scheme
-	if strings.HasPrefix(dbURL, "sqlite:") || strings.HasSuffix(dbURL, ".db") || strings.Contains(dbURL, "file:") {
+	if isSQLite(dbURL) {
 		// SQLite database
 		// Remove sqlite: prefix if present
 		dbPath := strings.TrimPrefix(dbURL, "sqlite:")
@@ -49,7 +58,7 @@ func generateRandomPassword(length int) string {
 }
 
 // createDefaultAdminUser creates the default admin user if no users exist
-func createDefaultAdminUser(authService *services.AuthService, port string) error {
+func createDefaultAdminUser(authService *services.AuthService, localURL string) error {
 	// Check if any users already exist
 	// We'll use a simple approach - try to get the admin user
 	// If it fails, we assume no users exist and create the admin
@@ -80,7 +89,7 @@ func createDefaultAdminUser(authService *services.AuthService, port string) erro
 	log.Printf("  Password: %s", password)
 	log.Printf("  Email:    admin@localhost")
 	log.Println("=====================================")
-	log.Printf("Login at: http://localhost:%s/login", port)
+	log.Printf("Login at: %s/login", localURL)
 	log.Println("⚠️  Please save these credentials and change the password after first login!")
 	log.Println("=====================================")
 
@@ -174,6 +183,117 @@ func handleListUsers(authRepo *repository.AuthRepository) error {
 	return nil
 }
 
+// setupEncryption sets the keyring sensitive database columns are encrypted with
+func setupEncryption(cfg *config.Config) error {
+	key, err := cfg.GetEncryptionKey()
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		if len(cfg.Encryption.PreviousKeys) > 0 {
+			return fmt.Errorf("previous_keys are set but no current key is")
+		}
+		return nil
+	}
+	keyring, err := secrets.NewKeyring(key, cfg.Encryption.PreviousKeys...)
+	if err != nil {
+		return err
+	}
+	secrets.SetDefault(keyring)
+	return nil
+}
+
+// handleRotateEncryptionKey re-encrypts the stored secrets of the main
+// instance and of every tenant with the current key
+func handleRotateEncryptionKey(cfg *config.Config, db *gorm.DB, authRepo *repository.AuthRepository) error {
+	if secrets.Default() == nil {
+		return fmt.Errorf("no encryption key configured")
+	}
+
+	count, err := authRepo.ReencryptSecrets()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Re-encrypted %d secret(s)\n", count)
+
+	if !cfg.Tenancy.Enabled {
+		return nil
+	}
+	if err := db.AutoMigrate(&models.Tenant{}); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	tenantService := services.NewTenantService(repository.NewTenantRepository(db))
+	tenantList, err := tenantService.ListTenants()
+	if err != nil {
+		return fmt.Errorf("failed to list tenants: %w", err)
+	}
+	tenants := newTenantHost(cfg, db, tenantService, nil, nil)
+	for _, tenant := range tenantList {
+		tenantDB, err := tenants.openDatabase(tenant)
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", tenant.Slug, err)
+		}
+		count, err := repository.NewAuthRepository(tenantDB).ReencryptSecrets()
+		if sqlDB, dbErr := tenantDB.DB(); dbErr == nil {
+			sqlDB.Close()
+		}
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", tenant.Slug, err)
+		}
+		fmt.Printf("✓ Re-encrypted %d secret(s) of tenant %s\n", count, tenant.Slug)
+	}
+	return nil
+}
+
+// handleExportSite writes the static HTML archive of the main instance into
+// dir and, with tenancy enabled, that of every tenant into dir/tenants/<slug>
+func handleExportSite(cfg *config.Config, db *gorm.DB, primary *instance, dir string) error {
+	summary, err := primary.taskService.ExportSite(dir, primary.storageService)
+	if err != nil {
+		return err
+	}
+	printSiteExport(dir, summary)
+
+	if !cfg.Tenancy.Enabled {
+		return nil
+	}
+	if err := db.AutoMigrate(&models.Tenant{}); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	tenantService := services.NewTenantService(repository.NewTenantRepository(db))
+	tenantList, err := tenantService.ListTenants()
+	if err != nil {
+		return fmt.Errorf("failed to list tenants: %w", err)
+	}
+	tenants := newTenantHost(cfg, db, tenantService, nil, nil)
+	for _, tenant := range tenantList {
+		tenantDB, err := tenants.openDatabase(tenant)
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", tenant.Slug, err)
+		}
+		taskService := services.NewTaskService(repository.NewTaskRepository(tenantDB), nil)
+		storage := services.NewStorageService(filepath.Join(cfg.GetTenantDataDir(), tenant.Slug, "attachments"))
+		tenantDir := filepath.Join(dir, "tenants", tenant.Slug)
+		summary, err := taskService.ExportSite(tenantDir, storage)
+		if sqlDB, dbErr := tenantDB.DB(); dbErr == nil {
+			sqlDB.Close()
+		}
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", tenant.Slug, err)
+		}
+		printSiteExport(tenantDir, summary)
+	}
+	return nil
+}
+
+// printSiteExport reports what a static HTML archive holds
+func printSiteExport(dir string, summary *services.SiteExport) {
+	fmt.Printf("✓ Exported %d task(s) and %d attachment(s) to %s\n", summary.Tasks, summary.Attachments, filepath.Join(dir, "index.html"))
+	if summary.MissingAttachments > 0 {
+		fmt.Printf("⚠️  %d attachment file(s) were missing from storage and are listed without a link\n", summary.MissingAttachments)
+	}
+}
+
 // getAllUsers gets all users from the repository
 func getAllUsers(authRepo *repository.AuthRepository) ([]models.User, error) {
 	return authRepo.GetAllUsers()
@@ -184,11 +304,19 @@ func main() {
 	var configFile string
 	var resetPasswordUser string
 	var listUsers bool
+	var rotateEncryptionKey bool
+	var checkOnly bool
+	var demo bool
+	var exportSiteDir string
 	
 	flag.StringVar(&configFile, "c", "", "Path to TOML configuration file")
 	flag.StringVar(&configFile, "config", "", "Path to TOML configuration file")
 	flag.StringVar(&resetPasswordUser, "reset-password", "", "Reset password for specified username")
 	flag.BoolVar(&listUsers, "list-users", false, "List all users")
+	flag.BoolVar(&demo, "demo", false, "Start with generated sample data in an in-memory database")
+	flag.BoolVar(&checkOnly, "check", false, "Validate the configuration and exit without starting the server")
+	flag.BoolVar(&rotateEncryptionKey, "rotate-encryption-key", false, "Re-encrypt stored secrets with the current encryption key")
+	flag.StringVar(&exportSiteDir, "export-site", "", "Export all tasks as a static HTML archive into the given directory and exit")
 	
 	flag.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), "JATS - Just Another To-do System\n\n")
@@ -197,11 +325,17 @@ func main() {
 		fmt.Fprintf(flag.CommandLine.Output(), "\nExamples:\n")
 		fmt.Fprintf(flag.CommandLine.Output(), "  jatsd                              # Start server\n")
 		fmt.Fprintf(flag.CommandLine.Output(), "  jatsd -c config.toml               # Start server with config file\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  jatsd -c config.toml -check        # Check the configuration and exit\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  jatsd -demo                        # Try JATS out with sample data\n")
 		fmt.Fprintf(flag.CommandLine.Output(), "  jatsd -reset-password username     # Reset user password\n")
 		fmt.Fprintf(flag.CommandLine.Output(), "  jatsd -list-users                  # List all users\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  jatsd -rotate-encryption-key       # Re-encrypt secrets after changing the key\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  jatsd -export-site archive/        # Archive all tasks as static HTML\n")
 		fmt.Fprintf(flag.CommandLine.Output(), "\nConfig files:\n")
 		fmt.Fprintf(flag.CommandLine.Output(), "  See config.example.toml for full configuration options\n")
 		fmt.Fprintf(flag.CommandLine.Output(), "  Environment variables override config file values\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  Values may reference environment variables as ${NAME}; secrets can be\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  read from files with *_file settings or *_FILE variables\n")
 	}
 	flag.Parse()
 
@@ -211,18 +345,31 @@ func main() {
 
 	if configFile != "" {
 		log.Printf("Loading configuration from: %s", configFile)
-		cfg, err = config.LoadFromFile(configFile)
-		if err != nil {
-			log.Fatal("Failed to load configuration file:", err)
-		}
 	} else {
 		log.Println("Loading configuration from environment variables")
-		cfg = config.Load()
+	}
+	// Without a file this still reads the secrets named by *_FILE variables
+	cfg, err = config.LoadFromFile(configFile)
+	if err != nil {
+		log.Fatal("Failed to load configuration:", err)
+	}
+
+	// Demo mode runs on throwaway sample data, with no mail or other integrations
+	if demo {
+		applyDemoConfig(cfg)
+	}
+
+	if checkOnly {
+		results := runChecks(cfg, configFile, true)
+		printCheckReport(results)
+		if countProblems(results) > 0 {
+			os.Exit(1)
+		}
+		return
 	}
 
 	dbURL := cfg.DatabaseURL()
 	log.Printf("Server configuration - Port: %s, Database: %s", cfg.Port, dbURL)
-
 	// Log email configuration status
 	if cfg.Email.IMAPHost != "" {
 		log.Printf("Email configuration found:")
@@ -231,6 +378,14 @@ func main() {
 		log.Printf("  Poll interval: %s", cfg.Email.PollInterval)
 	}
 
+	// Sensitive columns are encrypted at rest when a key is configured
+	if err := setupEncryption(cfg); err != nil {
+		log.Fatal("Invalid encryption configuration:", err)
+	}
+	if secrets.Default() == nil {
+		log.Println("Encryption at rest disabled - no encryption key configured")
+	}
+
 	// Setup database connection with appropriate driver
 	db, err := openDatabase(dbURL)
 	if err != nil {
@@ -238,95 +393,188 @@ func main() {
 	}
 
 	// Auto-migrate database schema
-	err = db.AutoMigrate(
-		&models.Task{},
-		&models.Subtask{},
-		&models.TimeEntry{},
-		&models.Comment{},
-		&models.EmailMessage{},
-		&models.TaskSubscriber{},
-		&models.Attachment{},
-		&models.SavedQuery{},
-		&models.User{},
-		&models.Session{},
-		&models.APIKey{},
-		&models.LoginAttempt{},
-	)
-	if err != nil {
+	if err := migrate(db); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
-	// Initialize repositories
-	taskRepo := repository.NewTaskRepository(db)
-	authRepo := repository.NewAuthRepository(db)
-
-	// Initialize storage service for email attachments
-	storageService := services.NewStorageService("./attachments")
-	log.Println("Initialized storage service at ./attachments")
-
-	// Initialize SMTP service for sending notifications
-	smtpService := services.NewSMTPService(&cfg.Email)
-
-	// Initialize notification service
-	notificationService := services.NewNotificationService(taskRepo, authRepo, smtpService)
+	// Initialize services
+	attachmentsDir := "./attachments"
+	if demo {
+		// The in-memory database lives as long as one connection to it stays open
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.SetMaxOpenConns(1)
+		}
+		if attachmentsDir, err = os.MkdirTemp("", "jats-demo-"); err != nil {
+			log.Fatal("Failed to create demo attachments directory:", err)
+		}
+	}
+	primary, err := newInstance(cfg, db, attachmentsDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Initialized storage service at %s", attachmentsDir)
 
-	// Initialize services with notification support
-	taskService := services.NewTaskService(taskRepo, notificationService)
-	authService := services.NewAuthService(authRepo, nil)
-	reportService := services.NewReportService(taskRepo)
+	if demo {
+		if err := seedDemoData(db, primary.authService, time.Now()); err != nil {
+			log.Fatal("Failed to seed demo data:", err)
+		}
+		log.Printf("Seeded demo data: %d tasks over the last 90 days", len(demoTasks))
+	}
 
 	// Handle admin commands if provided
 	if resetPasswordUser != "" {
-		if err := handlePasswordReset(authService, resetPasswordUser); err != nil {
+		if err := handlePasswordReset(primary.authService, resetPasswordUser); err != nil {
 			log.Fatal("Password reset failed:", err)
 		}
 		return
 	}
 	
 	if listUsers {
-		if err := handleListUsers(authRepo); err != nil {
+		if err := handleListUsers(primary.authRepo); err != nil {
 			log.Fatal("List users failed:", err)
 		}
 		return
 	}
 
+	if rotateEncryptionKey {
+		if err := handleRotateEncryptionKey(cfg, db, primary.authRepo); err != nil {
+			log.Fatal("Encryption key rotation failed:", err)
+		}
+		return
+	}
+
+	if exportSiteDir != "" {
+		if err := handleExportSite(cfg, db, primary, exportSiteDir); err != nil {
+			log.Fatal("Site export failed:", err)
+		}
+		return
+	}
+
 	log.Println("Starting JATS server...")
 
-	// Initialize email service if email configuration is provided
-	var emailService *services.EmailService
-	if cfg.Email.IMAPHost != "" && cfg.Email.IMAPUsername != "" && cfg.Email.IMAPPassword != "" {
-		emailService = services.NewEmailService(taskService, taskRepo, authRepo, storageService, cfg)
+	// The same checks as jatsd -check, less the database, which is already connected
+	logCheckProblems(runChecks(cfg, configFile, false))
+
+	for channel := range cfg.Inbound {
+		log.Printf("Inbound webhook channel enabled: /api/v1/inbound/%s", channel)
+	}
+	if cfg.Alertmanager.Secret != "" {
+		log.Printf("Alertmanager receiver enabled: /api/v1/inbound/%s", services.AlertmanagerChannel)
+	}
+	for _, target := range primary.syncService.Targets() {
+		log.Printf("Issue sync enabled: %s (%s) for saved query %d every %s", target.Name, target.Type, target.SavedQueryID, target.Interval)
+	}
+
+	// Poll the mailbox if email configuration is provided
+	pollEmail := cfg.Email.IMAPHost != "" && cfg.Email.IMAPUsername != "" && cfg.Email.IMAPPassword != ""
+	if pollEmail {
+		if primary.spamService.Enabled() {
+			log.Printf("Spam filtering enabled via rspamd at %s (quarantine score %.1f)", cfg.Spam.RspamdURL, cfg.Spam.QuarantineScore)
+		}
 		log.Printf("Initialized email service for %s@%s:%s", cfg.Email.IMAPUsername, cfg.Email.IMAPHost, cfg.Email.IMAPPort)
 
 		// Start email polling in a separate goroutine
 		go func() {
 			log.Printf("Starting email polling with interval: %s", cfg.Email.PollInterval)
-			if err := emailService.StartPolling(); err != nil {
+			if err := primary.emailService.StartPolling(); err != nil {
 				log.Printf("Email polling stopped with error: %v", err)
 			}
 		}()
 	} else {
-		log.Println("Email service disabled - IMAP configuration not provided")
+		log.Println("Email polling disabled - IMAP configuration not provided")
+	}
+
+	jobRunner := services.NewJobRunner()
+	primary.registerJobs(jobRunner, "")
+	if cfg.Email.SMTPHost != "" && cfg.Email.FromEmail != "" {
+		log.Printf("Standup emails scheduled for %02d:00 on weekdays", cfg.Email.StandupHour)
 	}
+	go jobRunner.Start()
+
+	// Where the server can be reached locally, for the startup messages
+	localURL := fmt.Sprintf("http://localhost:%s%s", cfg.Port, cfg.GetBasePath())
 
 	// Create default admin user on first startup
-	if err := createDefaultAdminUser(authService, cfg.Port); err != nil {
+	if err := createDefaultAdminUser(primary.authService, localURL); err != nil {
 		log.Printf("Warning: Failed to create default admin user: %v", err)
 	}
 
+	// Forwarding headers are only believed from the configured reverse proxies
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatal("Invalid trusted_proxies configuration:", err)
+	}
+
+	// Optional JSON access log for debugging and log shipping
+	var accessLog *middleware.AccessLogger
+	if cfg.AccessLog.Enabled {
+		accessLog, err = middleware.OpenAccessLog(cfg.AccessLog.Output)
+		if err != nil {
+			log.Fatal("Invalid access_log configuration:", err)
+		}
+		defer accessLog.Close()
+	}
+
+	// In multi-tenant mode the main instance provisions tenants, each served
+	// from its own database
+	var tenantService *services.TenantService
+	var tenants *tenantHost
+	if cfg.Tenancy.Enabled {
+		if cfg.GetTenancyMode() == "subdomain" && cfg.Tenancy.Domain == "" {
+			log.Fatal("Invalid tenancy configuration: subdomain mode needs a domain")
+		}
+		if err := db.AutoMigrate(&models.Tenant{}); err != nil {
+			log.Fatal("Failed to migrate database:", err)
+		}
+		tenantService = services.NewTenantService(repository.NewTenantRepository(db))
+		tenants = newTenantHost(cfg, db, tenantService, jobRunner, accessLog)
+		tenantService.SetHost(tenants)
+	}
+
 	// Setup routes and handlers with dependencies
-	mux := routes.SetupRoutes(taskService, authService, authRepo, reportService)
+	mux := primary.handler(accessLog, tenantService)
+	if tenants != nil {
+		mux = middleware.Tenants(cfg.GetTenancyMode(), cfg.Tenancy.Domain, mux, tenants.Handler)
+	}
+
+	// Start HTTP server, on the sockets systemd passed in if socket activated
+	listeners, err := listen(cfg.ListenAddr())
+	if err != nil {
+		log.Fatal("Failed to listen:", err)
+	}
+	addrs := make([]string, len(listeners))
+	for i, listener := range listeners {
+		addrs[i] = listener.Addr().String()
+	}
 
-	// Start HTTP server
 	log.Println("==============================================")
-	log.Printf("🚀 JATS Server starting on port %s", cfg.Port)
-	log.Printf("📱 Web interface: http://localhost:%s/", cfg.Port)
-	log.Printf("🔌 API endpoints: http://localhost:%s/api/v1/", cfg.Port)
-	if emailService != nil {
+	log.Printf("🚀 JATS Server listening on %s", strings.Join(addrs, ", "))
+	log.Printf("📱 Web interface: %s/", localURL)
+	log.Printf("🔌 API endpoints: %s/api/v1/", localURL)
+	if demo {
+		log.Printf("🧪 Demo mode: sign in as jats-admin, %s with password %q; data is lost on exit", strings.Join(demoUsers, ", "), demoPassword)
+	}
+	if pollEmail {
 		log.Printf("📧 Email integration: ACTIVE (polling %s inbox)", cfg.Email.IMAPUsername)
 	} else {
 		log.Printf("📧 Email integration: DISABLED")
 	}
+	if accessLog != nil {
+		log.Printf("📝 Access log: %s", cfg.AccessLog.Output)
+	}
+	if tenants != nil {
+		log.Printf("🏢 Multi-tenant mode: tenants served by %s", cfg.GetTenancyMode())
+	}
 	log.Println("==============================================")
-	log.Fatal(http.ListenAndServe(":"+cfg.Port, mux))
+	handler := middleware.SecurityHeaders(&cfg.Security, middleware.CORS(&cfg.CORS, mux))
+	server := &http.Server{Handler: middleware.RealClient(trustedProxies, middleware.StripBasePath(cfg.GetBasePath(), handler))}
+
+	// The systemd watchdog is only pinged while the database answers
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal("Failed to get database connection:", err)
+	}
+	if err := serve(server, listeners, sqlDB.Ping); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/soarinferret/jats/internal/api"
+	"github.com/soarinferret/jats/internal/systemd"
+)
+
+// shutdownTimeout is how long requests in flight get to finish on shutdown
+const shutdownTimeout = 30 * time.Second
+
+// listen returns the sockets passed by systemd socket activation, or a new
+// listener on addr when the server was started directly
+func listen(addr string) ([]net.Listener, error) {
+	listeners, err := systemd.Listeners()
+	if err != nil || len(listeners) > 0 {
+		return listeners, err
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return []net.Listener{listener}, nil
+}
+
+// serve runs the server on the listeners until SIGINT or SIGTERM, then lets
+// requests in flight finish. Under systemd it reports readiness once the
+// server is accepting connections, pings the watchdog while healthy reports no
+// error, and reports when it starts stopping. With socket activation the
+// socket stays open across restarts, so no connection is refused meanwhile.
+func serve(server *http.Server, listeners []net.Listener, healthy func() error) error {
+	endStreamsOnShutdown(server)
+	errs := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		go func(listener net.Listener) {
+			errs <- server.Serve(listener)
+		}(listener)
+	}
+
+	if _, err := systemd.Notify(systemd.Ready); err != nil {
+		log.Printf("Warning: Failed to notify systemd of readiness: %v", err)
+	}
+	stopWatchdog := make(chan struct{})
+	defer close(stopWatchdog)
+	go func() {
+		if err := systemd.RunWatchdog(healthy, stopWatchdog); err != nil {
+			log.Printf("Warning: systemd watchdog stopped: %v", err)
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case err := <-errs:
+		return err
+	case sig := <-signals:
+		log.Printf("Received %s, shutting down", sig)
+	}
+
+	systemd.Notify(systemd.Stopping)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// endStreamsOnShutdown makes the server's event streams end as soon as
+// Shutdown is called. Shutdown waits for requests in flight but does not
+// cancel them, so an open browser tab would otherwise hold it up until the
+// timeout. It must be called before the server starts serving.
+func endStreamsOnShutdown(server *http.Server) {
+	done := make(chan struct{})
+	server.BaseContext = func(net.Listener) context.Context {
+		return api.WithShutdown(context.Background(), done)
+	}
+	server.RegisterOnShutdown(func() { close(done) })
+}
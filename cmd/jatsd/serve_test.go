@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/api"
+	"github.com/soarinferret/jats/internal/repository"
+	"github.com/soarinferret/jats/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestShutdownEndsEventStreams(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	taskService := services.NewTaskService(repository.NewTaskRepository(db), nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(api.NewEventHandlers(taskService).StreamEvents)}
+	endStreamsOnShutdown(server)
+	go server.Serve(listener)
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/api/v1/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	// The stream is open once its first line arrives
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "retry:") {
+		t.Fatalf("Expected the stream to start, got %q, %v", line, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected Shutdown to succeed with a stream open, got %v after %s", err, time.Since(start))
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected Shutdown to return promptly, took %s", elapsed)
+	}
+}
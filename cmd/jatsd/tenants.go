@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// tenantHost opens tenant databases and serves their instances, see services.TenantHost
+type tenantHost struct {
+	cfg       *config.Config
+	mainDB    *gorm.DB
+	service   *services.TenantService
+	jobRunner *services.JobRunner
+	accessLog *middleware.AccessLogger
+
+	mu        sync.Mutex
+	instances map[string]*tenantInstance
+}
+
+// tenantInstance is an open tenant
+type tenantInstance struct {
+	db      *gorm.DB
+	handler http.Handler
+	plugins *services.PluginService
+}
+
+// newTenantHost creates the host for tenants of the main instance configured by cfg
+func newTenantHost(cfg *config.Config, mainDB *gorm.DB, service *services.TenantService, jobRunner *services.JobRunner, accessLog *middleware.AccessLogger) *tenantHost {
+	// Mailbox polling, webhooks, issue sync and assignment groups are set up by
+	// the operator for the main instance, so tenants do without them
+	tenantCfg := *cfg
+	tenantCfg.Inbound = nil
+	tenantCfg.Alertmanager = config.AlertmanagerConfig{}
+	tenantCfg.Sync = nil
+	tenantCfg.Assignment = nil
+
+	return &tenantHost{
+		cfg:       &tenantCfg,
+		mainDB:    mainDB,
+		service:   service,
+		jobRunner: jobRunner,
+		accessLog: accessLog,
+		instances: make(map[string]*tenantInstance),
+	}
+}
+
+// Handler returns the handler serving a tenant, opening the tenant on first use
+func (h *tenantHost) Handler(slug string) (http.Handler, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	slug = strings.ToLower(slug)
+	if in, ok := h.instances[slug]; ok {
+		return in.handler, true
+	}
+
+	tenant, err := h.service.GetTenant(slug)
+	if err != nil {
+		if !errors.Is(err, services.ErrTenantNotFound) {
+			log.Printf("Failed to look up tenant %q: %v", slug, err)
+		}
+		return nil, false
+	}
+	if _, err := h.open(tenant); err != nil {
+		log.Printf("Failed to open tenant %q: %v", slug, err)
+		return nil, false
+	}
+	return h.instances[tenant.Slug].handler, true
+}
+
+// Provision opens a new tenant and creates its initial admin user
+func (h *tenantHost) Provision(tenant *models.Tenant) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	in, err := h.open(tenant)
+	if err != nil {
+		return "", err
+	}
+
+	password := generateRandomPassword(16)
+	authService := services.NewAuthService(in.authRepo, nil)
+	if _, err := authService.RegisterUser(services.TenantAdminUsername, "admin@localhost", password); err != nil {
+		return "", fmt.Errorf("failed to create admin user: %w", err)
+	}
+	log.Printf("Provisioned tenant %q (%s)", tenant.Slug, tenant.Name)
+	return password, nil
+}
+
+// Evict stops serving a deleted tenant and its background jobs
+func (h *tenantHost) Evict(tenant *models.Tenant) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	in, ok := h.instances[tenant.Slug]
+	if !ok {
+		return
+	}
+	delete(h.instances, tenant.Slug)
+	h.jobRunner.RemovePrefix(tenantJobPrefix(tenant))
+	in.plugins.Stop()
+	if sqlDB, err := in.db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
+
+// open opens a tenant's database and wires up its instance. h.mu must be held.
+func (h *tenantHost) open(tenant *models.Tenant) (*instance, error) {
+	db, err := h.openDatabase(tenant)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate tenant database: %w", err)
+	}
+
+	in, err := newInstance(h.cfg, db, filepath.Join(h.cfg.GetTenantDataDir(), tenant.Slug, "attachments"))
+	if err != nil {
+		return nil, err
+	}
+	in.registerJobs(h.jobRunner, tenantJobPrefix(tenant))
+
+	h.instances[tenant.Slug] = &tenantInstance{db: db, handler: in.handler(h.accessLog, nil), plugins: in.pluginService}
+	return in, nil
+}
+
+// openDatabase opens a tenant's own database: a SQLite file under the tenant
+// data directory, or a schema of the main PostgreSQL database
+func (h *tenantHost) openDatabase(tenant *models.Tenant) (*gorm.DB, error) {
+	dbURL := h.cfg.DatabaseURL()
+	if isSQLite(dbURL) {
+		dir := filepath.Join(h.cfg.GetTenantDataDir(), tenant.Slug)
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create tenant directory: %w", err)
+		}
+		return gorm.Open(sqlite.Open(filepath.Join(dir, "jats.db")), &gorm.Config{})
+	}
+
+	// Slugs are validated to letters, digits and dashes, so the schema name is safe to quote
+	schema := "tenant_" + strings.ReplaceAll(tenant.Slug, "-", "_")
+	if err := h.mainDB.Exec(`CREATE SCHEMA IF NOT EXISTS "` + schema + `"`).Error; err != nil {
+		return nil, fmt.Errorf("failed to create tenant schema: %w", err)
+	}
+
+	if strings.HasPrefix(dbURL, "postgres://") || strings.HasPrefix(dbURL, "postgresql://") {
+		u, err := url.Parse(dbURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid database URL: %w", err)
+		}
+		query := u.Query()
+		query.Set("search_path", schema)
+		u.RawQuery = query.Encode()
+		dbURL = u.String()
+	} else {
+		dbURL += " search_path=" + schema
+	}
+	return gorm.Open(postgres.Open(dbURL), &gorm.Config{})
+}
+
+// tenantJobPrefix names the background jobs of a tenant
+func tenantJobPrefix(tenant *models.Tenant) string {
+	return "tenant/" + tenant.Slug + "/"
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// MaxActivityWait caps how long GET /api/v1/activity?wait=N holds a request open
+const MaxActivityWait = 30 * time.Second
+
+// activityPollInterval is how often a waiting request checks for new events
+const activityPollInterval = time.Second
+
+type ActivityHandlers struct {
+	taskService *services.TaskService
+}
+
+func NewActivityHandlers(taskService *services.TaskService) *ActivityHandlers {
+	return &ActivityHandlers{
+		taskService: taskService,
+	}
+}
+
+// GetActivity handles GET /api/v1/activity
+// Query parameters: since, until (dates such as "yesterday" or "2025-12-01"),
+// type (comma separated), task_id, tag, query (saved query ID), mention
+// (username mentioned as @username or assigned the task), limit, offset.
+// after (RFC 3339) returns only newer events; with wait=N seconds the request is
+// held until one arrives, so clients can long-poll the feed.
+func (h *ActivityHandlers) GetActivity(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	since, until, err := services.ParseActivityRange(values.Get("since"), values.Get("until"))
+	if err != nil {
+		SendBadRequest(w, "Invalid date format", err.Error())
+		return
+	}
+
+	filter := services.ActivityFilter{
+		Since: since,
+		Until: until,
+		Tag:   strings.TrimSpace(values.Get("tag")),
+		// Accept the username with or without its @
+		Mention: strings.TrimPrefix(strings.TrimSpace(values.Get("mention")), "@"),
+	}
+
+	if typesStr := values.Get("type"); typesStr != "" {
+		valid := make(map[services.ActivityType]bool)
+		for _, t := range services.ActivityTypes {
+			valid[t] = true
+		}
+		for _, part := range strings.Split(typesStr, ",") {
+			activityType := services.ActivityType(strings.TrimSpace(part))
+			if !valid[activityType] {
+				SendBadRequest(w, "Invalid activity type", part)
+				return
+			}
+			filter.Types = append(filter.Types, activityType)
+		}
+	}
+
+	if afterStr := values.Get("after"); afterStr != "" {
+		after, err := time.Parse(time.RFC3339Nano, afterStr)
+		if err != nil {
+			SendBadRequest(w, "Invalid after timestamp", "use RFC 3339, e.g. 2025-12-01T09:30:00Z")
+			return
+		}
+		filter.After = after
+	}
+
+	var wait time.Duration
+	if waitStr := values.Get("wait"); waitStr != "" {
+		seconds, err := strconv.Atoi(waitStr)
+		if err != nil || seconds < 0 {
+			SendBadRequest(w, "Invalid wait", nil)
+			return
+		}
+		wait = min(time.Duration(seconds)*time.Second, MaxActivityWait)
+	}
+
+	if queryStr := values.Get("query"); queryStr != "" {
+		queryID, err := strconv.ParseUint(queryStr, 10, 32)
+		if err != nil {
+			SendBadRequest(w, "Invalid saved query ID", nil)
+			return
+		}
+		query, err := h.taskService.GetSavedQueryByID(uint(queryID))
+		if err != nil {
+			SendNotFound(w, "Saved query not found")
+			return
+		}
+		filter.SavedQuery = query
+	}
+
+	if taskIDStr := values.Get("task_id"); taskIDStr != "" {
+		taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+		if err != nil {
+			SendBadRequest(w, "Invalid task ID", nil)
+			return
+		}
+		filter.TaskID = uint(taskID)
+	}
+
+	limit := 50
+	if limitStr := values.Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	offset := 0
+	if offsetStr := values.Get("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	events, err := h.waitForActivity(r, filter, wait)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve activity")
+		return
+	}
+
+	total := len(events)
+	if offset >= total {
+		events = []services.ActivityEvent{}
+	} else {
+		end := offset + limit
+		if end > total {
+			end = total
+		}
+		events = events[offset:end]
+	}
+
+	pagination := &PaginationMeta{
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+		Pages:  (total + limit - 1) / limit,
+	}
+
+	SendPaginatedSuccess(w, events, pagination, "Activity retrieved successfully")
+}
+
+// waitForActivity returns the matching events, polling for up to wait while
+// there are none. It gives up early when the client disconnects.
+func (h *ActivityHandlers) waitForActivity(r *http.Request, filter services.ActivityFilter, wait time.Duration) ([]services.ActivityEvent, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		events, err := h.taskService.GetActivity(filter)
+		if err != nil || len(events) > 0 || !time.Now().Before(deadline) {
+			return events, err
+		}
+
+		select {
+		case <-r.Context().Done():
+			return events, nil
+		case <-time.After(min(activityPollInterval, time.Until(deadline))):
+		}
+	}
+}
@@ -80,7 +80,7 @@ type UpdateUserRequest struct {
 	IsActive *bool   `json:"is_active,omitempty"`
 }
 
-// CreateUser creates a new user (admin only)
+// CreateUser handles POST /api/v1/admin/users, creating a new user (admin only)
 func (h *GinAdminHandlers) CreateUser(c *gin.Context) {
 	// Check admin permissions
 	if _, ok := h.checkAdminPermission(c); !ok {
@@ -153,7 +153,7 @@ func (h *GinAdminHandlers) CreateUser(c *gin.Context) {
 	})
 }
 
-// GetAllUsers returns all users (admin only)
+// GetAllUsers handles GET /api/v1/admin/users, returning all users (admin only)
 func (h *GinAdminHandlers) GetAllUsers(c *gin.Context) {
 	// Check admin permissions
 	if _, ok := h.checkAdminPermission(c); !ok {
@@ -185,7 +185,7 @@ func (h *GinAdminHandlers) GetAllUsers(c *gin.Context) {
 	})
 }
 
-// GetUser returns a specific user (admin only)
+// GetUser handles GET /api/v1/admin/users/{id}, returning a specific user (admin only)
 func (h *GinAdminHandlers) GetUser(c *gin.Context) {
 	// Check admin permissions
 	if _, ok := h.checkAdminPermission(c); !ok {
@@ -238,7 +238,7 @@ func (h *GinAdminHandlers) GetUser(c *gin.Context) {
 	})
 }
 
-// UpdateUser updates a user (admin only)
+// UpdateUser handles PUT /api/v1/admin/users/{id}, updating a user (admin only)
 func (h *GinAdminHandlers) UpdateUser(c *gin.Context) {
 	// Check admin permissions
 	if _, ok := h.checkAdminPermission(c); !ok {
@@ -325,7 +325,7 @@ func (h *GinAdminHandlers) UpdateUser(c *gin.Context) {
 	})
 }
 
-// DeleteUser deletes a user (admin only)
+// DeleteUser handles DELETE /api/v1/admin/users/{id}, deleting a user (admin only)
 func (h *GinAdminHandlers) DeleteUser(c *gin.Context) {
 	// Check admin permissions
 	authContext, ok := h.checkAdminPermission(c)
@@ -399,7 +399,8 @@ func (h *GinAdminHandlers) DeleteUser(c *gin.Context) {
 	})
 }
 
-// ResetUserPassword resets a user's password (admin only)
+// ResetUserPassword handles POST /api/v1/admin/users/{id}/reset-password,
+// resetting a user's password (admin only)
 func (h *GinAdminHandlers) ResetUserPassword(c *gin.Context) {
 	// Check admin permissions
 	if _, ok := h.checkAdminPermission(c); !ok {
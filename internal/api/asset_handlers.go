@@ -0,0 +1,225 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type AssetHandlers struct {
+	taskService *services.TaskService
+}
+
+func NewAssetHandlers(taskService *services.TaskService) *AssetHandlers {
+	return &AssetHandlers{
+		taskService: taskService,
+	}
+}
+
+// AssetRequest represents an asset create or update request
+type AssetRequest struct {
+	Name     string `json:"name"`
+	Serial   string `json:"serial,omitempty"`
+	Location string `json:"location,omitempty"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+// apply copies the request onto an asset
+func (req *AssetRequest) apply(asset *models.Asset) {
+	asset.Name = req.Name
+	asset.Serial = req.Serial
+	asset.Location = req.Location
+	asset.Notes = req.Notes
+}
+
+// TaskAssetRequest links a task to an asset
+type TaskAssetRequest struct {
+	AssetID uint `json:"asset_id"`
+}
+
+// GetAssets handles GET /api/v1/assets
+func (h *AssetHandlers) GetAssets(w http.ResponseWriter, r *http.Request) {
+	assets, err := h.taskService.GetAssets()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve assets")
+		return
+	}
+
+	SendSuccess(w, assets, "Assets retrieved successfully")
+}
+
+// GetAsset handles GET /api/v1/assets/{id}, including the asset's task history
+func (h *AssetHandlers) GetAsset(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid asset ID", nil)
+		return
+	}
+
+	asset, err := h.taskService.GetAsset(id)
+	if err != nil {
+		h.sendAssetError(w, err)
+		return
+	}
+
+	SendSuccess(w, asset, "Asset retrieved successfully")
+}
+
+// CreateAsset handles POST /api/v1/assets
+func (h *AssetHandlers) CreateAsset(w http.ResponseWriter, r *http.Request) {
+	var req AssetRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	asset := &models.Asset{}
+	req.apply(asset)
+	if err := h.taskService.CreateAsset(asset); err != nil {
+		h.sendAssetError(w, err)
+		return
+	}
+
+	SendCreated(w, asset, "Asset created successfully")
+}
+
+// UpdateAsset handles PUT /api/v1/assets/{id}
+func (h *AssetHandlers) UpdateAsset(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid asset ID", nil)
+		return
+	}
+
+	var req AssetRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	asset, err := h.taskService.GetAsset(id)
+	if err != nil {
+		h.sendAssetError(w, err)
+		return
+	}
+	req.apply(asset)
+
+	if err := h.taskService.UpdateAsset(asset); err != nil {
+		h.sendAssetError(w, err)
+		return
+	}
+
+	SendSuccess(w, asset, "Asset updated successfully")
+}
+
+// DeleteAsset handles DELETE /api/v1/assets/{id}; its tasks are kept
+func (h *AssetHandlers) DeleteAsset(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid asset ID", nil)
+		return
+	}
+
+	if err := h.taskService.DeleteAsset(id); err != nil {
+		h.sendAssetError(w, err)
+		return
+	}
+
+	SendNoContent(w)
+}
+
+// GetTaskAssets handles GET /api/v1/tasks/{id}/assets
+func (h *AssetHandlers) GetTaskAssets(w http.ResponseWriter, r *http.Request) {
+	taskID, err := GetIDFromPath(r)
+	if err != nil || taskID == 0 {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	if _, err := h.taskService.GetTask(taskID); err != nil {
+		SendNotFound(w, "Task not found")
+		return
+	}
+
+	assets, err := h.taskService.GetTaskAssets(taskID)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve task assets")
+		return
+	}
+
+	SendSuccess(w, assets, "Task assets retrieved successfully")
+}
+
+// LinkTaskAsset handles POST /api/v1/tasks/{id}/assets
+func (h *AssetHandlers) LinkTaskAsset(w http.ResponseWriter, r *http.Request) {
+	taskID, err := GetIDFromPath(r)
+	if err != nil || taskID == 0 {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	var req TaskAssetRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+	if req.AssetID == 0 {
+		SendValidationError(w, "Validation failed", []string{"asset_id is required"})
+		return
+	}
+
+	if _, err := h.taskService.GetTask(taskID); err != nil {
+		SendNotFound(w, "Task not found")
+		return
+	}
+
+	if err := h.taskService.LinkTaskAsset(taskID, req.AssetID); err != nil {
+		h.sendAssetError(w, err)
+		return
+	}
+
+	assets, err := h.taskService.GetTaskAssets(taskID)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve task assets")
+		return
+	}
+
+	SendSuccess(w, assets, "Task linked to asset successfully")
+}
+
+// UnlinkTaskAsset handles DELETE /api/v1/tasks/{id}/assets/{assetId}
+func (h *AssetHandlers) UnlinkTaskAsset(w http.ResponseWriter, r *http.Request) {
+	taskID, err := GetIDFromPath(r)
+	if err != nil || taskID == 0 {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	assetID, err := GetAssetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid asset ID", nil)
+		return
+	}
+
+	if err := h.taskService.UnlinkTaskAsset(taskID, assetID); err != nil {
+		h.sendAssetError(w, err)
+		return
+	}
+
+	SendNoContent(w)
+}
+
+func (h *AssetHandlers) sendAssetError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrAssetNotFound):
+		SendNotFound(w, "Asset not found")
+	case errors.Is(err, services.ErrAssetNotLinked):
+		SendNotFound(w, err.Error())
+	case errors.Is(err, services.ErrAssetNameRequired):
+		SendValidationError(w, "Validation failed", []string{err.Error()})
+	default:
+		SendInternalError(w, "Failed to process asset")
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/soarinferret/jats/internal/services"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+type AttachmentHandlers struct {
+	taskService    *services.TaskService
+	storage        *services.StorageService
+	attachmentPath string
+}
+
+func NewAttachmentHandlers(taskService *services.TaskService, storage *services.StorageService, attachmentPath string) *AttachmentHandlers {
+	return &AttachmentHandlers{
+		taskService:    taskService,
+		storage:        storage,
+		attachmentPath: attachmentPath,
+	}
+}
+
+// GetTaskAttachments handles GET /api/v1/tasks/{id}/attachments
+func (h *AttachmentHandlers) GetTaskAttachments(w http.ResponseWriter, r *http.Request) {
+	taskID, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	// Verify task exists
+	if _, err := h.taskService.GetTask(taskID); err != nil {
+		SendNotFound(w, "Task not found")
+		return
+	}
+
+	attachments, err := h.taskService.GetTaskAttachments(taskID)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve attachments")
+		return
+	}
+
+	SendSuccess(w, attachments, "Attachments retrieved successfully")
+}
+
+// UploadAttachment handles POST /api/v1/tasks/{id}/attachments, a multipart
+// form with the file in its "file" field. The web UI uploads pasted
+// screenshots this way and references them in notes as ![name](attachment:{id}).
+func (h *AttachmentHandlers) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	if _, err := h.taskService.GetTask(taskID); err != nil {
+		SendNotFound(w, "Task not found")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			SendPayloadTooLarge(w, tooLarge.Limit)
+			return
+		}
+		SendBadRequest(w, "A file is required", err.Error())
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		SendInternalError(w, "Failed to read file")
+		return
+	}
+	if len(data) == 0 {
+		SendBadRequest(w, "The file is empty", nil)
+		return
+	}
+
+	// Pasted images come with a type; fall back to sniffing for anything else
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+
+	attachment, err := h.storage.SaveAttachment(header.Filename, contentType, data)
+	if err != nil {
+		SendInternalError(w, "Failed to save attachment")
+		return
+	}
+	attachment.TaskID = &taskID
+	if err := h.taskService.AddAttachment(attachment); err != nil {
+		h.storage.DeleteAttachment(attachment.FilePath)
+		SendInternalError(w, "Failed to save attachment")
+		return
+	}
+
+	SendCreated(w, attachment, "Attachment uploaded successfully")
+}
+
+// DownloadAttachment handles GET /api/v1/attachments/{id}/download
+func (h *AttachmentHandlers) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	attachmentID, err := GetIDFromPath(r)
+	if err != nil || attachmentID == 0 {
+		SendBadRequest(w, "Invalid attachment ID", nil)
+		return
+	}
+
+	attachment, err := h.taskService.GetAttachment(attachmentID)
+	if err != nil {
+		SendNotFound(w, "Attachment not found")
+		return
+	}
+
+	// Stream the file with range support so interrupted downloads can resume
+	filePath := filepath.Join(h.attachmentPath, attachment.FilePath)
+	if err := utils.ServeAttachment(w, r, filePath, attachment, false); err != nil {
+		if os.IsNotExist(err) {
+			SendNotFound(w, "File not found on disk")
+			return
+		}
+		SendInternalError(w, "Failed to read attachment")
+	}
+}
@@ -1,26 +1,31 @@
 package api
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/soarinferret/jats/internal/common"
+	"github.com/soarinferret/jats/internal/i18n"
 	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/services"
+	"github.com/soarinferret/jats/internal/utils"
 )
 
 // AuthHandlers handles authentication-related HTTP endpoints
 type AuthHandlers struct {
 	authService *services.AuthService
+	taskService *services.TaskService // checks the saved queries named in preferences
 }
 
 // NewAuthHandlers creates a new auth handlers instance
-func NewAuthHandlers(authService *services.AuthService) *AuthHandlers {
+func NewAuthHandlers(authService *services.AuthService, taskService *services.TaskService) *AuthHandlers {
 	return &AuthHandlers{
 		authService: authService,
+		taskService: taskService,
 	}
 }
 
@@ -58,11 +63,11 @@ type APIKeyResponse struct {
 	Key    string         `json:"key"` // The actual key (only returned once)
 }
 
-// Register handles user registration
+// Register handles POST /api/v1/auth/register, registering a new user
 func (h *AuthHandlers) Register(w http.ResponseWriter, r *http.Request) {
 	var req RegisterRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		common.SendErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", nil)
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
 		return
 	}
 	
@@ -83,17 +88,17 @@ func (h *AuthHandlers) Register(w http.ResponseWriter, r *http.Request) {
 	common.SendSuccessResponse(w, http.StatusCreated, user, "User registered successfully")
 }
 
-// Login handles user authentication
+// Login handles POST /api/v1/auth/login, starting a session
 func (h *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		common.SendErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", nil)
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
 		return
 	}
 	
 	// Get client info
 	userAgent := r.Header.Get("User-Agent")
-	ipAddress := r.RemoteAddr
+	ipAddress := middleware.ClientIP(r)
 	
 	loginReq := &services.LoginRequest{
 		Username:  req.Username,
@@ -132,9 +137,9 @@ func (h *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 		Value:    result.Session.Token,
 		Expires:  result.Session.ExpiresAt,
 		HttpOnly: true,
-		Secure:   false, // Set to true in production with HTTPS
+		Secure:   middleware.IsSecure(r),
 		SameSite: http.SameSiteLaxMode,
-		Path:     "/",
+		Path:     middleware.CookiePath(r),
 	})
 	
 	// Remove sensitive fields before returning
@@ -148,7 +153,7 @@ func (h *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 	}, "Login successful")
 }
 
-// Logout handles user logout
+// Logout handles POST /api/v1/auth/logout, ending the current session
 func (h *AuthHandlers) Logout(w http.ResponseWriter, r *http.Request) {
 	// Get session token from cookie or header
 	var sessionToken string
@@ -166,15 +171,15 @@ func (h *AuthHandlers) Logout(w http.ResponseWriter, r *http.Request) {
 		Value:    "",
 		Expires:  time.Unix(0, 0),
 		HttpOnly: true,
-		Secure:   false, // Set to true in production with HTTPS
+		Secure:   middleware.IsSecure(r),
 		SameSite: http.SameSiteLaxMode,
-		Path:     "/",
+		Path:     middleware.CookiePath(r),
 	})
 	
 	common.SendSuccessResponse(w, http.StatusOK, nil, "Logout successful")
 }
 
-// GetProfile returns the current user's profile
+// GetProfile handles GET /api/v1/auth/profile, the current user's profile
 func (h *AuthHandlers) GetProfile(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetCurrentUser(r)
 	if user == nil {
@@ -189,7 +194,98 @@ func (h *AuthHandlers) GetProfile(w http.ResponseWriter, r *http.Request) {
 	common.SendSuccessResponse(w, http.StatusOK, user, "Profile retrieved successfully")
 }
 
-// SetupTOTP initiates TOTP setup for a user
+// UpdateProfileRequest represents a profile update request
+type UpdateProfileRequest struct {
+	Language       string  `json:"language"`
+	StandupEmail   *bool   `json:"standup_email"`   // Opt in/out of the morning standup email
+	WeeklyCapacity *string `json:"weekly_capacity"` // Work available per week, e.g. "30h"; "0" clears it
+	LandingView    *string `json:"landing_view"`     // tasks, kanban, reports or dashboard; "" resets to tasks
+	LandingQueryID *uint   `json:"landing_query_id"` // saved query to open on; 0 clears it
+	PinnedQueryIDs *[]uint `json:"pinned_query_ids"` // saved queries pinned to the top of the sidebar
+}
+
+// UpdateProfile handles PATCH /api/v1/auth/profile, updating the current user's preferences
+func (h *AuthHandlers) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetCurrentUser(r)
+	if user == nil {
+		common.SendErrorResponse(w, http.StatusUnauthorized, "NOT_AUTHENTICATED", "Not authenticated", nil)
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	update := services.ProfileUpdate{
+		StandupEmail:   req.StandupEmail,
+		LandingQueryID: req.LandingQueryID,
+		PinnedQueryIDs: req.PinnedQueryIDs,
+	}
+	if req.Language != "" {
+		update.Language = &req.Language
+	}
+	if req.LandingView != nil {
+		view := strings.TrimSpace(*req.LandingView)
+		update.LandingView = &view
+	}
+	if req.WeeklyCapacity != nil {
+		minutes, err := utils.ParseDuration(strings.TrimSpace(*req.WeeklyCapacity))
+		if err != nil {
+			common.SendErrorResponse(w, http.StatusBadRequest, "INVALID_CAPACITY", err.Error(), nil)
+			return
+		}
+		update.WeeklyCapacity = &minutes
+	}
+	if req.LandingQueryID != nil && *req.LandingQueryID != 0 && !h.savedQueriesExist(w, *req.LandingQueryID) {
+		return
+	}
+	if req.PinnedQueryIDs != nil && !h.savedQueriesExist(w, *req.PinnedQueryIDs...) {
+		return
+	}
+
+	user, err := h.authService.UpdateProfile(user.ID, update)
+	if err != nil {
+		switch err {
+		case services.ErrUnsupportedLanguage:
+			common.SendErrorResponse(w, http.StatusBadRequest, "UNSUPPORTED_LANGUAGE", "Unsupported language", map[string]interface{}{
+				"supported_languages": i18n.SupportedLanguages(),
+			})
+		case services.ErrInvalidCapacity:
+			common.SendErrorResponse(w, http.StatusBadRequest, "INVALID_CAPACITY", err.Error(), nil)
+		case services.ErrInvalidLandingView:
+			common.SendErrorResponse(w, http.StatusBadRequest, "INVALID_LANDING_VIEW", err.Error(), map[string]interface{}{
+				"landing_views": services.LandingViews,
+			})
+		default:
+			common.SendErrorResponse(w, http.StatusInternalServerError, "PROFILE_UPDATE_FAILED", err.Error(), nil)
+		}
+		return
+	}
+
+	// Remove sensitive fields
+	user.HashedPassword = ""
+	user.TOTPSecret = ""
+
+	common.SendSuccessResponse(w, http.StatusOK, user, "Profile updated successfully")
+}
+
+// savedQueriesExist checks that preferences name existing saved queries,
+// sending an error response if one does not
+func (h *AuthHandlers) savedQueriesExist(w http.ResponseWriter, ids ...uint) bool {
+	if err := h.taskService.ValidateSavedQueryIDs(ids); err != nil {
+		if errors.Is(err, services.ErrSavedQueryNotFound) {
+			common.SendErrorResponse(w, http.StatusBadRequest, "SAVED_QUERY_NOT_FOUND", err.Error(), nil)
+			return false
+		}
+		common.SendErrorResponse(w, http.StatusInternalServerError, "PROFILE_UPDATE_FAILED", err.Error(), nil)
+		return false
+	}
+	return true
+}
+
+// SetupTOTP handles POST /api/v1/auth/totp/setup, initiating TOTP setup for a user
 func (h *AuthHandlers) SetupTOTP(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetCurrentUser(r)
 	if user == nil {
@@ -215,7 +311,7 @@ func (h *AuthHandlers) SetupTOTP(w http.ResponseWriter, r *http.Request) {
 	common.SendSuccessResponse(w, http.StatusOK, response, "TOTP setup initiated")
 }
 
-// EnableTOTP enables TOTP for a user after verification
+// EnableTOTP handles POST /api/v1/auth/totp/enable, enabling TOTP for a user after verification
 func (h *AuthHandlers) EnableTOTP(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetCurrentUser(r)
 	if user == nil {
@@ -226,8 +322,8 @@ func (h *AuthHandlers) EnableTOTP(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		TOTPCode string `json:"totp_code"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		common.SendErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", nil)
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
 		return
 	}
 	
@@ -243,7 +339,7 @@ func (h *AuthHandlers) EnableTOTP(w http.ResponseWriter, r *http.Request) {
 	common.SendSuccessResponse(w, http.StatusOK, nil, "TOTP enabled successfully")
 }
 
-// DisableTOTP disables TOTP for a user
+// DisableTOTP handles DELETE /api/v1/auth/totp/disable, disabling TOTP for a user
 func (h *AuthHandlers) DisableTOTP(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetCurrentUser(r)
 	if user == nil {
@@ -259,7 +355,7 @@ func (h *AuthHandlers) DisableTOTP(w http.ResponseWriter, r *http.Request) {
 	common.SendSuccessResponse(w, http.StatusOK, nil, "TOTP disabled successfully")
 }
 
-// CreateAPIKey creates a new API key
+// CreateAPIKey handles POST /api/v1/auth/api-keys, creating a new API key
 func (h *AuthHandlers) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetCurrentUser(r)
 	if user == nil {
@@ -268,8 +364,8 @@ func (h *AuthHandlers) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	var req APIKeyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		common.SendErrorResponse(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", nil)
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
 		return
 	}
 	
@@ -292,7 +388,7 @@ func (h *AuthHandlers) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	common.SendSuccessResponse(w, http.StatusCreated, response, "API key created successfully")
 }
 
-// GetAPIKeys returns all API keys for the current user
+// GetAPIKeys handles GET /api/v1/auth/api-keys, all API keys for the current user
 func (h *AuthHandlers) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetCurrentUser(r)
 	if user == nil {
@@ -309,7 +405,7 @@ func (h *AuthHandlers) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
 	common.SendSuccessResponse(w, http.StatusOK, apiKeys, "API keys retrieved successfully")
 }
 
-// DeleteAPIKey deletes an API key
+// DeleteAPIKey handles DELETE /api/v1/auth/api-keys, deleting an API key
 func (h *AuthHandlers) DeleteAPIKey(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetCurrentUser(r)
 	if user == nil {
@@ -338,7 +434,7 @@ func (h *AuthHandlers) DeleteAPIKey(w http.ResponseWriter, r *http.Request) {
 	common.SendSuccessResponse(w, http.StatusOK, nil, "API key deleted successfully")
 }
 
-// GetSessions returns all active sessions for the current user
+// GetSessions handles GET /api/v1/auth/sessions, all active sessions for the current user
 func (h *AuthHandlers) GetSessions(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetCurrentUser(r)
 	if user == nil {
@@ -360,7 +456,7 @@ func (h *AuthHandlers) GetSessions(w http.ResponseWriter, r *http.Request) {
 	common.SendSuccessResponse(w, http.StatusOK, sessions, "Sessions retrieved successfully")
 }
 
-// LogoutAll logs out all sessions for the current user
+// LogoutAll handles DELETE /api/v1/auth/sessions/all, logging out all sessions for the current user
 func (h *AuthHandlers) LogoutAll(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetCurrentUser(r)
 	if user == nil {
@@ -379,9 +475,9 @@ func (h *AuthHandlers) LogoutAll(w http.ResponseWriter, r *http.Request) {
 		Value:    "",
 		Expires:  time.Unix(0, 0),
 		HttpOnly: true,
-		Secure:   false, // Set to true in production with HTTPS
+		Secure:   middleware.IsSecure(r),
 		SameSite: http.SameSiteLaxMode,
-		Path:     "/",
+		Path:     middleware.CookiePath(r),
 	})
 	
 	common.SendSuccessResponse(w, http.StatusOK, nil, "All sessions logged out successfully")
@@ -56,7 +56,7 @@ func setupAuthTestServices() (*services.TaskService, *services.AuthService) {
 
 func TestUserRegistration(t *testing.T) {
 	taskService, authService := setupAuthTestServices()
-	authHandlers := NewAuthHandlers(authService)
+	authHandlers := NewAuthHandlers(authService, nil)
 
 	t.Run("Successful registration", func(t *testing.T) {
 		reqBody := RegisterRequest{
@@ -168,7 +168,7 @@ func TestUserRegistration(t *testing.T) {
 
 func TestUserLogin(t *testing.T) {
 	taskService, authService := setupAuthTestServices()
-	authHandlers := NewAuthHandlers(authService)
+	authHandlers := NewAuthHandlers(authService, nil)
 	
 	// Create test user using the service
 	_, err := authService.RegisterUser("logintest", "logintest@example.com", "testpassword")
@@ -270,7 +270,7 @@ func TestUserLogin(t *testing.T) {
 
 func TestTOTPSetup(t *testing.T) {
 	taskService, authService := setupAuthTestServices()
-	authHandlers := NewAuthHandlers(authService)
+	authHandlers := NewAuthHandlers(authService, nil)
 
 	// Create test user using the service
 	testUser, err := authService.RegisterUser("totptest", "totptest@example.com", "testpassword")
@@ -360,7 +360,7 @@ func TestTOTPSetup(t *testing.T) {
 
 func TestAPIKeyManagement(t *testing.T) {
 	taskService, authService := setupAuthTestServices()
-	authHandlers := NewAuthHandlers(authService)
+	authHandlers := NewAuthHandlers(authService, nil)
 
 	// Create test user using the service
 	testUser, err := authService.RegisterUser("apikeytest", "apikeytest@example.com", "testpassword")
@@ -446,7 +446,7 @@ func TestAPIKeyManagement(t *testing.T) {
 
 func TestSessionManagement(t *testing.T) {
 	taskService, authService := setupAuthTestServices()
-	authHandlers := NewAuthHandlers(authService)
+	authHandlers := NewAuthHandlers(authService, nil)
 
 	// Create test user using the service
 	testUser, err := authService.RegisterUser("sessiontest", "sessiontest@example.com", "testpassword")
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/services"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+type CapacityHandlers struct {
+	taskService *services.TaskService
+}
+
+func NewCapacityHandlers(taskService *services.TaskService) *CapacityHandlers {
+	return &CapacityHandlers{
+		taskService: taskService,
+	}
+}
+
+// GetCapacityPlan handles GET /api/v1/reports/capacity
+// Uses the current user's weekly capacity unless ?capacity= (e.g. 30h) is given
+func (h *CapacityHandlers) GetCapacityPlan(w http.ResponseWriter, r *http.Request) {
+	capacity := 0
+	if user := middleware.GetCurrentUser(r); user != nil {
+		capacity = user.WeeklyCapacity
+	}
+
+	if capacityStr := r.URL.Query().Get("capacity"); capacityStr != "" {
+		minutes, err := utils.ParseDuration(capacityStr)
+		if err != nil || minutes < 0 {
+			SendBadRequest(w, "Invalid capacity", "expected a duration such as 30h")
+			return
+		}
+		capacity = minutes
+	}
+
+	plan, err := h.taskService.GetCapacityPlan(capacity, time.Now())
+	if err != nil {
+		SendInternalError(w, "Failed to generate capacity plan: "+err.Error())
+		return
+	}
+
+	SendSuccess(w, plan, "Capacity plan generated successfully")
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// captureFormMemory is how much of a multipart capture is held in memory
+// before the photo spills to a temporary file
+const captureFormMemory = 8 << 20
+
+type CaptureHandlers struct {
+	captureService *services.CaptureService
+}
+
+func NewCaptureHandlers(captureService *services.CaptureService) *CaptureHandlers {
+	return &CaptureHandlers{
+		captureService: captureService,
+	}
+}
+
+// MobileCaptureRequest is the JSON body of a mobile capture. Multipart forms,
+// which can also carry a photo in the "photo" field, use the same field names.
+type MobileCaptureRequest struct {
+	Text      string   `json:"text"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Location  string   `json:"location"`
+}
+
+// MobileCapture handles POST /api/v1/capture/mobile, meant for phone
+// automations such as iOS Shortcuts or Tasker. It creates a task from the
+// text, tagged with where it was sent from and with the photo attached.
+func (h *CaptureHandlers) MobileCapture(w http.ResponseWriter, r *http.Request) {
+	capture, err := parseMobileCapture(r)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			SendPayloadTooLarge(w, tooLarge.Limit)
+			return
+		}
+		SendBadRequest(w, "Invalid capture", err.Error())
+		return
+	}
+	if strings.TrimSpace(capture.Text) == "" {
+		SendValidationError(w, "Validation failed", []string{"text is required"})
+		return
+	}
+
+	task, err := h.captureService.Capture(capture)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWIPLimitReached):
+			SendConflict(w, "Work-in-progress limit reached", nil)
+		case errors.Is(err, services.ErrInvalidCoordinates), errors.Is(err, services.ErrInvalidPhoto),
+			errors.Is(err, services.ErrEmptyTaskName), errors.Is(err, services.ErrInvalidCaptureText):
+			SendValidationError(w, "Validation failed", []string{err.Error()})
+		default:
+			SendInternalError(w, "Failed to capture task")
+		}
+		return
+	}
+
+	SendCreated(w, task, "Task captured successfully")
+}
+
+// parseMobileCapture reads a capture sent as JSON or as a multipart form
+func parseMobileCapture(r *http.Request) (*services.MobileCapture, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		var req MobileCaptureRequest
+		if err := ParseJSON(r, &req); err != nil {
+			return nil, err
+		}
+		return &services.MobileCapture{Text: req.Text, Latitude: req.Latitude, Longitude: req.Longitude, Location: req.Location}, nil
+	}
+
+	if err := r.ParseMultipartForm(captureFormMemory); err != nil {
+		return nil, err
+	}
+	capture := &services.MobileCapture{Text: r.FormValue("text"), Location: r.FormValue("location")}
+	for field, value := range map[string]**float64{"latitude": &capture.Latitude, "longitude": &capture.Longitude} {
+		if raw := strings.TrimSpace(r.FormValue(field)); raw != "" {
+			parsed, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, errors.New(field + " must be a number")
+			}
+			*value = &parsed
+		}
+	}
+
+	file, header, err := r.FormFile("photo")
+	if errors.Is(err, http.ErrMissingFile) {
+		return capture, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if capture.Photo, err = io.ReadAll(file); err != nil {
+		return nil, err
+	}
+	capture.PhotoName = header.Filename
+	// Phones label photos reliably, but fall back to sniffing
+	capture.PhotoContentType = header.Header.Get("Content-Type")
+	if capture.PhotoContentType == "" || capture.PhotoContentType == "application/octet-stream" {
+		capture.PhotoContentType = http.DetectContentType(capture.Photo)
+	}
+	return capture, nil
+}
@@ -4,17 +4,20 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/services"
 )
 
 type CommentHandlers struct {
-	taskService *services.TaskService
+	taskService     *services.TaskService
+	settingsService *services.SettingsService
 }
 
-func NewCommentHandlers(taskService *services.TaskService) *CommentHandlers {
+func NewCommentHandlers(taskService *services.TaskService, settingsService *services.SettingsService) *CommentHandlers {
 	return &CommentHandlers{
-		taskService: taskService,
+		taskService:     taskService,
+		settingsService: settingsService,
 	}
 }
 
@@ -26,16 +29,17 @@ func (h *CommentHandlers) GetComments(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// Verify task exists
-	_, err = h.taskService.GetTask(taskID)
+	task, err := h.taskService.GetTask(taskID)
 	if err != nil {
 		SendNotFound(w, "Task not found")
 		return
 	}
-	
-	// This would need repository method to get comments
-	// For now, return empty array as placeholder
-	SendSuccess(w, []models.Comment{}, "Comments retrieved successfully")
+
+	comments := task.Comments
+	if comments == nil {
+		comments = []models.Comment{}
+	}
+	SendSuccess(w, comments, "Comments retrieved successfully")
 }
 
 // CreateComment handles POST /api/v1/tasks/{id}/comments
@@ -48,7 +52,7 @@ func (h *CommentHandlers) CreateComment(w http.ResponseWriter, r *http.Request)
 	
 	var req CommentRequest
 	if err := ParseJSON(r, &req); err != nil {
-		SendBadRequest(w, "Invalid JSON", err.Error())
+		SendInvalidJSON(w, err)
 		return
 	}
 	
@@ -58,21 +62,37 @@ func (h *CommentHandlers) CreateComment(w http.ResponseWriter, r *http.Request)
 	}
 	
 	// Verify task exists
-	_, err = h.taskService.GetTask(taskID)
+	task, err := h.taskService.GetTask(taskID)
 	if err != nil {
 		SendNotFound(w, "Task not found")
 		return
 	}
 	
+	content := req.Content
+	if req.Canned != "" {
+		content, err = h.settingsService.RenderCannedResponse(req.Canned, task)
+		if err == services.ErrCannedResponseNotFound {
+			SendNotFound(w, "Canned response not found")
+			return
+		}
+		if err != nil {
+			SendInternalError(w, "Failed to render canned response")
+			return
+		}
+	}
+	
 	// Create comment - all comments are now private (internal notes only)
 	comment := &models.Comment{
 		TaskID:    taskID,
-		Content:   req.Content,
+		Content:   content,
 		IsPrivate: true, // Force all comments to be private
 		FromEmail: req.FromEmail,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	if user := middleware.GetCurrentUser(r); user != nil {
+		comment.CreatedBy = user.Username
+	}
 	
 	if err := h.taskService.AddComment(taskID, comment); err != nil {
 		SendInternalError(w, "Failed to create comment")
@@ -92,7 +112,7 @@ func (h *CommentHandlers) UpdateComment(w http.ResponseWriter, r *http.Request)
 	
 	var req CommentRequest
 	if err := ParseJSON(r, &req); err != nil {
-		SendBadRequest(w, "Invalid JSON", err.Error())
+		SendInvalidJSON(w, err)
 		return
 	}
 	
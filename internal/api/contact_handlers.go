@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type ContactHandlers struct {
+	contactService *services.ContactService
+}
+
+func NewContactHandlers(contactService *services.ContactService) *ContactHandlers {
+	return &ContactHandlers{
+		contactService: contactService,
+	}
+}
+
+// GetContacts handles GET /api/v1/contacts
+func (h *ContactHandlers) GetContacts(w http.ResponseWriter, r *http.Request) {
+	contacts, err := h.contactService.GetContacts()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve contacts")
+		return
+	}
+
+	SendSuccess(w, contacts, "Contacts retrieved successfully")
+}
+
+// GetContact handles GET /api/v1/contacts/{id}
+func (h *ContactHandlers) GetContact(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid contact ID", nil)
+		return
+	}
+
+	contact, err := h.contactService.GetContact(id)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve contact")
+		return
+	}
+	if contact == nil {
+		SendNotFound(w, "Contact not found")
+		return
+	}
+
+	SendSuccess(w, contact, "Contact retrieved successfully")
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type DashboardHandlers struct {
+	taskService *services.TaskService
+	authService *services.AuthService
+}
+
+func NewDashboardHandlers(taskService *services.TaskService, authService *services.AuthService) *DashboardHandlers {
+	return &DashboardHandlers{
+		taskService: taskService,
+		authService: authService,
+	}
+}
+
+// GetDashboard handles GET /api/v1/reports/dashboard
+// Returns the data for each widget of the current user's dashboard layout
+func (h *DashboardHandlers) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	var layout *models.DashboardLayout
+	if user := middleware.GetCurrentUser(r); user != nil {
+		layout = user.DashboardLayout
+	}
+
+	dashboard, err := h.taskService.GetDashboard(layout, time.Now())
+	if err != nil {
+		SendInternalError(w, "Failed to generate dashboard: "+err.Error())
+		return
+	}
+
+	SendSuccess(w, dashboard, "Dashboard generated successfully")
+}
+
+// GetLayout handles GET /api/v1/dashboard/layout
+func (h *DashboardHandlers) GetLayout(w http.ResponseWriter, r *http.Request) {
+	layout := services.DefaultDashboardLayout()
+	if user := middleware.GetCurrentUser(r); user != nil && user.DashboardLayout != nil {
+		layout = user.DashboardLayout
+	}
+
+	SendSuccess(w, layout, "Dashboard layout retrieved successfully")
+}
+
+// UpdateLayout handles PUT /api/v1/dashboard/layout
+func (h *DashboardHandlers) UpdateLayout(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetCurrentUser(r)
+	if user == nil {
+		SendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required", nil)
+		return
+	}
+
+	var layout models.DashboardLayout
+	if err := ParseJSON(r, &layout); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	if err := h.taskService.NormalizeDashboardLayout(&layout); err != nil {
+		if errors.Is(err, services.ErrInvalidDashboardLayout) {
+			SendValidationError(w, err.Error(), nil)
+			return
+		}
+		SendInternalError(w, "Failed to validate dashboard layout")
+		return
+	}
+
+	if _, err := h.authService.SetDashboardLayout(user.ID, &layout); err != nil {
+		SendInternalError(w, "Failed to save dashboard layout")
+		return
+	}
+
+	SendSuccess(w, layout, "Dashboard layout saved successfully")
+}
+
+// ResetLayout handles DELETE /api/v1/dashboard/layout
+func (h *DashboardHandlers) ResetLayout(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetCurrentUser(r)
+	if user == nil {
+		SendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required", nil)
+		return
+	}
+
+	if _, err := h.authService.SetDashboardLayout(user.ID, nil); err != nil {
+		SendInternalError(w, "Failed to reset dashboard layout")
+		return
+	}
+
+	SendSuccess(w, services.DefaultDashboardLayout(), "Dashboard layout reset to the default")
+}
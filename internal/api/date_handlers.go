@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+type DateHandlers struct{}
+
+// ParsedDateResponse describes how a date expression was interpreted
+type ParsedDateResponse struct {
+	Input    string    `json:"input"`
+	Date     string    `json:"date"`
+	Weekday  string    `json:"weekday"`
+	DateTime time.Time `json:"datetime"`
+}
+
+func NewDateHandlers() *DateHandlers {
+	return &DateHandlers{}
+}
+
+// ParseDate handles GET /api/v1/dates/parse?q=next+friday
+func (h *DateHandlers) ParseDate(w http.ResponseWriter, r *http.Request) {
+	input := strings.TrimSpace(r.URL.Query().Get("q"))
+	if input == "" {
+		SendBadRequest(w, "Query parameter 'q' is required", nil)
+		return
+	}
+
+	parsed, err := utils.ParseDate(input)
+	if err != nil {
+		SendBadRequest(w, "Invalid date format", err.Error())
+		return
+	}
+
+	SendSuccess(w, ParsedDateResponse{
+		Input:    input,
+		Date:     parsed.Format("2006-01-02"),
+		Weekday:  parsed.Weekday().String(),
+		DateTime: parsed,
+	}, "Date parsed successfully")
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"path"
+	"runtime"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// MaxCPUProfileSeconds caps how long a CPU profile or execution trace may record
+const MaxCPUProfileSeconds = 60
+
+// debugProfiles are the runtime profiles served by GetProfile. "profile" (CPU)
+// and "trace" record for ?seconds=N; the rest are snapshots.
+var debugProfiles = []string{"heap", "allocs", "goroutine", "block", "mutex", "threadcreate", "profile", "trace"}
+
+type DebugHandlers struct {
+	startedAt time.Time
+}
+
+// RuntimeStats is a snapshot of the server process for support tickets
+type RuntimeStats struct {
+	GoVersion     string     `json:"go_version"`
+	StartedAt     time.Time  `json:"started_at"`
+	UptimeSeconds int64      `json:"uptime_seconds"`
+	Goroutines    int        `json:"goroutines"`
+	GOMAXPROCS    int        `json:"gomaxprocs"`
+	NumCPU        int        `json:"num_cpu"`
+	HeapAlloc     uint64     `json:"heap_alloc_bytes"`
+	HeapInuse     uint64     `json:"heap_inuse_bytes"`
+	HeapObjects   uint64     `json:"heap_objects"`
+	TotalAlloc    uint64     `json:"total_alloc_bytes"`
+	Mallocs       uint64     `json:"mallocs"`
+	Sys           uint64     `json:"sys_bytes"`
+	NumGC         uint32     `json:"num_gc"`
+	GCPauseTotal  uint64     `json:"gc_pause_total_ns"`
+	LastGC        *time.Time `json:"last_gc,omitempty"`
+	Profiles      []string   `json:"profiles"`
+}
+
+func NewDebugHandlers() *DebugHandlers {
+	return &DebugHandlers{
+		startedAt: time.Now(),
+	}
+}
+
+// GetRuntimeStats handles GET /api/v1/admin/debug
+func (h *DebugHandlers) GetRuntimeStats(w http.ResponseWriter, r *http.Request) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := RuntimeStats{
+		GoVersion:     runtime.Version(),
+		StartedAt:     h.startedAt,
+		UptimeSeconds: int64(time.Since(h.startedAt).Seconds()),
+		Goroutines:    runtime.NumGoroutine(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		NumCPU:        runtime.NumCPU(),
+		HeapAlloc:     mem.HeapAlloc,
+		HeapInuse:     mem.HeapInuse,
+		HeapObjects:   mem.HeapObjects,
+		TotalAlloc:    mem.TotalAlloc,
+		Mallocs:       mem.Mallocs,
+		Sys:           mem.Sys,
+		NumGC:         mem.NumGC,
+		GCPauseTotal:  mem.PauseTotalNs,
+		Profiles:      debugProfiles,
+	}
+	if mem.LastGC > 0 {
+		lastGC := time.Unix(0, int64(mem.LastGC))
+		stats.LastGC = &lastGC
+	}
+
+	SendSuccess(w, stats, "Runtime stats retrieved successfully")
+}
+
+// GetProfile handles GET /api/v1/admin/debug/pprof/{profile}. Responses are in
+// pprof format (or text with ?debug=1) for `go tool pprof`.
+func (h *DebugHandlers) GetProfile(w http.ResponseWriter, r *http.Request) {
+	name := path.Base(r.URL.Path)
+	if !slices.Contains(debugProfiles, name) {
+		SendNotFound(w, "Unknown profile: "+name)
+		return
+	}
+
+	switch name {
+	case "profile", "trace":
+		if value := r.URL.Query().Get("seconds"); value != "" {
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds < 1 || seconds > MaxCPUProfileSeconds {
+				SendBadRequest(w, "seconds must be between 1 and "+strconv.Itoa(MaxCPUProfileSeconds), nil)
+				return
+			}
+		}
+		if name == "profile" {
+			pprof.Profile(w, r)
+		} else {
+			pprof.Trace(w, r)
+		}
+	default:
+		pprof.Handler(name).ServeHTTP(w, r)
+	}
+}
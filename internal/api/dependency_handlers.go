@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// DependencyRequest links a task to another; exactly one of the fields is set
+type DependencyRequest struct {
+	Blocks    uint `json:"blocks,omitempty"`     // task this one blocks
+	BlockedBy uint `json:"blocked_by,omitempty"` // task blocking this one
+}
+
+// TaskDependencies lists the tasks a task blocks and those blocking it
+type TaskDependencies struct {
+	Blocks    []models.TaskLink `json:"blocks"`
+	BlockedBy []models.TaskLink `json:"blocked_by"`
+}
+
+// GetDependencies handles GET /api/v1/tasks/{id}/dependencies
+func (h *TaskHandlers) GetDependencies(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	task, err := h.taskService.GetTask(id)
+	if err != nil {
+		SendNotFound(w, "Task not found")
+		return
+	}
+
+	SendSuccess(w, taskDependencies(task), "Dependencies retrieved successfully")
+}
+
+// AddDependency handles POST /api/v1/tasks/{id}/dependencies
+// The body names either the task this one blocks or the task blocking it. A
+// task cannot be resolved while a task blocking it is still open.
+func (h *TaskHandlers) AddDependency(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	var req DependencyRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+	if (req.Blocks == 0) == (req.BlockedBy == 0) {
+		SendValidationError(w, "Validation failed", []string{"set one of blocks or blocked_by"})
+		return
+	}
+
+	blocking, blocked := id, req.Blocks
+	if req.BlockedBy != 0 {
+		blocking, blocked = req.BlockedBy, id
+	}
+	for _, taskID := range []uint{blocking, blocked} {
+		if _, err := h.taskService.GetTask(taskID); err != nil {
+			SendNotFound(w, "Task not found")
+			return
+		}
+	}
+
+	if err := h.taskService.AddDependency(blocking, blocked); err != nil {
+		h.sendDependencyError(w, err)
+		return
+	}
+
+	task, err := h.taskService.GetTask(id)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve dependencies")
+		return
+	}
+
+	SendCreated(w, taskDependencies(task), "Dependency added successfully")
+}
+
+// RemoveDependency handles DELETE /api/v1/tasks/{id}/dependencies/{otherId}
+// Removes the dependency between the two tasks, whichever blocks the other.
+func (h *TaskHandlers) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	otherID, err := getDependencyIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid dependency task ID", nil)
+		return
+	}
+
+	if err := h.taskService.RemoveDependency(id, otherID); err != nil {
+		h.sendDependencyError(w, err)
+		return
+	}
+
+	SendNoContent(w)
+}
+
+func (h *TaskHandlers) sendDependencyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrDependencyNotFound):
+		SendNotFound(w, err.Error())
+	case errors.Is(err, services.ErrDependencySelf), errors.Is(err, services.ErrDependencyCycle):
+		SendValidationError(w, "Validation failed", []string{err.Error()})
+	default:
+		SendInternalError(w, "Failed to process dependency")
+	}
+}
+
+// taskDependencies returns a task's dependencies with empty lists rather than nulls
+func taskDependencies(task *models.Task) TaskDependencies {
+	dependencies := TaskDependencies{Blocks: task.Blocks, BlockedBy: task.BlockedBy}
+	if dependencies.Blocks == nil {
+		dependencies.Blocks = []models.TaskLink{}
+	}
+	if dependencies.BlockedBy == nil {
+		dependencies.BlockedBy = []models.TaskLink{}
+	}
+	return dependencies
+}
+
+// getDependencyIDFromPath extracts the other task's ID from paths like
+// /api/v1/tasks/{id}/dependencies/{otherId}
+func getDependencyIDFromPath(r *http.Request) (uint, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	for i, part := range parts {
+		if part == "dependencies" && i+1 < len(parts) {
+			if id, err := strconv.ParseUint(parts[i+1], 10, 32); err == nil && id > 0 {
+				return uint(id), nil
+			}
+		}
+	}
+
+	return 0, errors.New("dependency task ID not found in path")
+}
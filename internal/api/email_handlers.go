@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type EmailHandlers struct {
+	emailService *services.EmailService
+}
+
+func NewEmailHandlers(emailService *services.EmailService) *EmailHandlers {
+	return &EmailHandlers{
+		emailService: emailService,
+	}
+}
+
+// SimulateInboundEmail handles POST /api/v1/admin/email/simulate. The body is
+// a raw RFC 822 message; the response describes what inbound processing would
+// create from it, without creating anything.
+func (h *EmailHandlers) SimulateInboundEmail(w http.ResponseWriter, r *http.Request) {
+	if h.emailService == nil {
+		SendConflict(w, "email processing is not enabled on this server", nil)
+		return
+	}
+
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		SendBadRequest(w, "Failed to read message", err.Error())
+		return
+	}
+	if len(raw) == 0 {
+		SendBadRequest(w, "Request body must be a raw RFC 822 message", nil)
+		return
+	}
+
+	simulation, err := h.emailService.Simulate(raw)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidEmail) {
+			SendBadRequest(w, err.Error(), nil)
+			return
+		}
+		SendInternalError(w, "Failed to simulate email processing")
+		return
+	}
+
+	SendSuccess(w, simulation, "Email processing simulated; nothing was created")
+}
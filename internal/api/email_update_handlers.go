@@ -0,0 +1,149 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// EmailUpdateRequest represents an email update to send from an email-originated task
+type EmailUpdateRequest struct {
+	To         []string `json:"to,omitempty"` // Defaults to everyone on the email thread
+	Subject    string   `json:"subject,omitempty"`
+	Message    string   `json:"message"`
+	Canned     string   `json:"canned,omitempty"` // Name of a canned response to use instead of message
+	CommentIDs []uint   `json:"comment_ids,omitempty"`
+}
+
+func (er *EmailUpdateRequest) Validate() []string {
+	var errors []string
+
+	if strings.TrimSpace(er.Message) == "" && strings.TrimSpace(er.Canned) == "" {
+		errors = append(errors, "message or canned is required")
+	}
+
+	return errors
+}
+
+// EmailUpdatePreview is a composed email update that has not been sent
+type EmailUpdatePreview struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Text    string   `json:"text"`
+}
+
+// PreviewEmailUpdate handles POST /api/v1/tasks/{id}/email-update/preview
+func (h *CommentHandlers) PreviewEmailUpdate(w http.ResponseWriter, r *http.Request) {
+	taskID, update, ok := h.parseEmailUpdate(w, r)
+	if !ok {
+		return
+	}
+
+	email, err := h.taskService.ComposeEmailUpdate(taskID, update)
+	if err != nil {
+		sendEmailUpdateError(w, err)
+		return
+	}
+
+	SendSuccess(w, EmailUpdatePreview{To: update.To, Subject: email.Subject, Text: email.Text}, "Email update composed successfully")
+}
+
+// SendEmailUpdate handles POST /api/v1/tasks/{id}/email-update
+func (h *CommentHandlers) SendEmailUpdate(w http.ResponseWriter, r *http.Request) {
+	taskID, update, ok := h.parseEmailUpdate(w, r)
+	if !ok {
+		return
+	}
+
+	comment, err := h.taskService.SendEmailUpdate(taskID, update)
+	if err != nil {
+		sendEmailUpdateError(w, err)
+		return
+	}
+
+	SendCreated(w, comment, "Email update sent successfully")
+}
+
+// parseEmailUpdate reads an email update request, expanding a canned response
+// into the message. It writes the error response itself when it fails.
+func (h *CommentHandlers) parseEmailUpdate(w http.ResponseWriter, r *http.Request) (uint, *services.EmailUpdate, bool) {
+	taskID, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return 0, nil, false
+	}
+
+	var req EmailUpdateRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return 0, nil, false
+	}
+
+	if errors := req.Validate(); len(errors) > 0 {
+		SendValidationError(w, "Validation failed", errors)
+		return 0, nil, false
+	}
+
+	task, err := h.taskService.GetTask(taskID)
+	if err != nil {
+		SendNotFound(w, "Task not found")
+		return 0, nil, false
+	}
+
+	message := req.Message
+	if req.Canned != "" {
+		message, err = h.settingsService.RenderCannedResponse(req.Canned, task)
+		if err == services.ErrCannedResponseNotFound {
+			SendNotFound(w, "Canned response not found")
+			return 0, nil, false
+		}
+		if err != nil {
+			SendInternalError(w, "Failed to render canned response")
+			return 0, nil, false
+		}
+	}
+
+	return taskID, &services.EmailUpdate{
+		To:         req.To,
+		Subject:    req.Subject,
+		Message:    message,
+		CommentIDs: req.CommentIDs,
+	}, true
+}
+
+func sendEmailUpdateError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrEmailSendingDisabled):
+		SendError(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", err.Error(), nil)
+	case errors.Is(err, services.ErrTaskNotFromEmail), errors.Is(err, services.ErrNoEmailRecipients),
+		errors.Is(err, services.ErrEmptyEmailUpdate), errors.Is(err, services.ErrInvalidRecipient),
+		errors.Is(err, services.ErrCommentNotOnTask):
+		SendBadRequest(w, err.Error(), nil)
+	default:
+		SendInternalError(w, "Failed to send email update")
+	}
+}
+
+// GetTaskEmails handles GET /api/v1/tasks/{id}/emails
+func (h *CommentHandlers) GetTaskEmails(w http.ResponseWriter, r *http.Request) {
+	taskID, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	if _, err := h.taskService.GetTask(taskID); err != nil {
+		SendNotFound(w, "Task not found")
+		return
+	}
+
+	messages, err := h.taskService.GetTaskEmails(taskID)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve emails")
+		return
+	}
+
+	SendSuccess(w, messages, "Emails retrieved successfully")
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// eventKeepAlive is how often an idle event stream sends a comment, so
+// proxies do not close it
+const eventKeepAlive = 25 * time.Second
+
+// shutdownKey is the context key of the channel closed when the server starts
+// shutting down, see WithShutdown
+type shutdownKey struct{}
+
+// WithShutdown returns a base context for the server's requests that carries
+// done, which is closed when the server starts shutting down. Event streams
+// only otherwise end when the client disconnects, so they end on it instead
+// of holding up the shutdown.
+func WithShutdown(ctx context.Context, done <-chan struct{}) context.Context {
+	return context.WithValue(ctx, shutdownKey{}, done)
+}
+
+// shuttingDown returns the channel closed when the server handling r starts
+// shutting down, or nil, which never fires, if it was not given one
+func shuttingDown(r *http.Request) <-chan struct{} {
+	done, _ := r.Context().Value(shutdownKey{}).(<-chan struct{})
+	return done
+}
+
+type EventHandlers struct {
+	taskService *services.TaskService
+}
+
+func NewEventHandlers(taskService *services.TaskService) *EventHandlers {
+	return &EventHandlers{
+		taskService: taskService,
+	}
+}
+
+// StreamEvents handles GET /api/v1/events
+// Streams task events as Server-Sent Events until the client disconnects or
+// the server shuts down:
+// task.created, task.updated, task.commented and task.deleted, each named by
+// its type with the event as JSON data. Query parameters: type (comma
+// separated) and task_id. A client reconnecting with Last-Event-ID first gets
+// the recent events it missed.
+func (h *EventHandlers) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	var types []string
+	if typesStr := values.Get("type"); typesStr != "" {
+		for _, part := range strings.Split(typesStr, ",") {
+			eventType := strings.TrimSpace(part)
+			if !slices.Contains(services.TaskEventTypes, eventType) {
+				SendBadRequest(w, "Invalid event type", part)
+				return
+			}
+			types = append(types, eventType)
+		}
+	}
+
+	var taskID uint
+	if taskIDStr := values.Get("task_id"); taskIDStr != "" {
+		id, err := strconv.ParseUint(taskIDStr, 10, 32)
+		if err != nil {
+			SendBadRequest(w, "Invalid task ID", nil)
+			return
+		}
+		taskID = uint(id)
+	}
+
+	var lastID uint64
+	if lastIDStr := r.Header.Get("Last-Event-ID"); lastIDStr != "" {
+		lastID, _ = strconv.ParseUint(lastIDStr, 10, 64)
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		SendInternalError(w, "Streaming is not supported")
+		return
+	}
+
+	events, missed, cancel := h.taskService.Events().Subscribe(lastID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Keep nginx from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	// Tell EventSource how long to wait before reconnecting
+	fmt.Fprint(w, "retry: 3000\n\n")
+
+	send := func(event services.TaskEvent) {
+		if len(types) > 0 && !slices.Contains(types, event.Type) {
+			return
+		}
+		if taskID != 0 && event.TaskID != taskID {
+			return
+		}
+		data, _ := json.Marshal(event)
+		fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	}
+	for _, event := range missed {
+		send(event)
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+	shutdown := shuttingDown(r)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-shutdown:
+			return
+		case event := <-events:
+			send(event)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
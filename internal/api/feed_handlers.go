@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+const (
+	defaultFeedDays = 7
+	maxFeedDays     = 90
+)
+
+var feedChangeLabels = map[string]string{
+	"created":  "Created",
+	"resolved": "Resolved",
+}
+
+type FeedHandlers struct {
+	taskService *services.TaskService
+}
+
+func NewFeedHandlers(taskService *services.TaskService) *FeedHandlers {
+	return &FeedHandlers{
+		taskService: taskService,
+	}
+}
+
+// AtomFeed represents an Atom 1.0 feed document
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  AtomPerson  `xml:"author"`
+	Links   []AtomLink  `xml:"link"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+// AtomPerson represents an Atom author
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+// AtomLink represents an Atom link element
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// AtomEntry represents a single Atom feed entry
+type AtomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary,omitempty"`
+	Categories []AtomCategory `xml:"category"`
+}
+
+// AtomCategory represents an Atom category element
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// GetSavedQueryFeed handles GET /api/v1/feeds/saved-queries/{token}
+// The feed token acts as the credential, so feed readers do not need an API key.
+func (h *FeedHandlers) GetSavedQueryFeed(w http.ResponseWriter, r *http.Request) {
+	token := getFeedTokenFromPath(r)
+
+	query, err := h.taskService.GetSavedQueryByFeedToken(token)
+	if err != nil {
+		SendNotFound(w, "Feed not found")
+		return
+	}
+
+	days := defaultFeedDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 1 || parsed > maxFeedDays {
+			SendBadRequest(w, fmt.Sprintf("days must be between 1 and %d", maxFeedDays), nil)
+			return
+		}
+		days = parsed
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	changes, err := h.taskService.GetSavedQueryChanges(query, since)
+	if err != nil {
+		SendInternalError(w, "Failed to build feed")
+		return
+	}
+
+	updated := query.UpdatedAt
+	if len(changes) > 0 {
+		updated = changes[0].Timestamp
+	}
+
+	feed := AtomFeed{
+		ID:      fmt.Sprintf("urn:jats:saved-query:%d", query.ID),
+		Title:   fmt.Sprintf("JATS - %s", query.Name),
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  AtomPerson{Name: "JATS"},
+		Links: []AtomLink{
+			{Href: requestURL(r), Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+
+	for _, change := range changes {
+		entry := AtomEntry{
+			ID:      fmt.Sprintf("urn:jats:task:%d:%s", change.Task.ID, change.Kind),
+			Title:   fmt.Sprintf("%s: #%d %s", feedChangeLabels[change.Kind], change.Task.ID, change.Task.Name),
+			Updated: change.Timestamp.UTC().Format(time.RFC3339),
+			Summary: change.Task.Description,
+		}
+		for _, tag := range change.Task.Tags {
+			entry.Categories = append(entry.Categories, AtomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(feed)
+}
+
+// getFeedTokenFromPath extracts the token from /api/v1/feeds/saved-queries/{token}[.atom]
+func getFeedTokenFromPath(r *http.Request) string {
+	parts := strings.Split(r.URL.Path, "/")
+	for i, part := range parts {
+		if part == "saved-queries" && i+1 < len(parts) {
+			return strings.TrimSuffix(parts[i+1], ".atom")
+		}
+	}
+	return ""
+}
+
+// requestURL reconstructs the absolute URL of the current request
+func requestURL(r *http.Request) string {
+	return serverURL(r) + r.URL.RequestURI()
+}
+
+// serverURL returns the absolute URL of the server the request came in on,
+// including the base path
+func serverURL(r *http.Request) string {
+	return fmt.Sprintf("%s://%s%s", middleware.RequestScheme(r), r.Host, middleware.BasePath(r))
+}
@@ -0,0 +1,510 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// graphQLSchema is the schema served at /api/v1/graphql. Its fields resolve
+// to the methods of the resolvers below with the same names.
+const graphQLSchema = `"An instant, in RFC 3339 format"
+scalar Time
+
+type Query {
+  "Tasks matching the filters of GET /api/v1/tasks, 20 at a time unless limit says otherwise"
+  tasks(status: [String!], priority: [String!], tags: [String!], tagExpr: String, search: String, project: ID, milestone: String, context: String, dueBefore: String, dueAfter: String, overdue: Boolean, limit: Int, offset: Int): [Task!]!
+  task(id: ID!): Task
+  "Tags in use, by name"
+  tags: [Tag!]!
+  savedQueries: [SavedQuery!]!
+  savedQuery(id: ID!): SavedQuery
+}
+
+type Task {
+  id: ID!
+  name: String!
+  description: String
+  status: String!
+  priority: String
+  tags: [String!]!
+  assignee: String
+  context: String
+  size: String
+  projectId: ID
+  milestoneId: ID
+  dueDate: Time
+  overdue: Boolean!
+  estimateMinutes: Int!
+  loggedMinutes: Int!
+  createdAt: Time!
+  updatedAt: Time!
+  resolvedAt: Time
+  "Notes and emails, oldest first"
+  comments: [Comment!]!
+  timeEntries: [TimeEntry!]!
+}
+
+type Comment {
+  id: ID!
+  content: String!
+  isPrivate: Boolean!
+  fromEmail: String
+  createdBy: String
+  createdAt: Time!
+}
+
+type TimeEntry {
+  id: ID!
+  description: String
+  "Minutes"
+  duration: Int!
+  createdBy: String
+  billable: Boolean!
+  createdAt: Time!
+}
+
+type Tag {
+  name: String!
+  "Tasks with the tag"
+  count: Int!
+  tasks: [Task!]!
+}
+
+type SavedQuery {
+  id: ID!
+  name: String!
+  includedTags: [String!]!
+  excludedTags: [String!]!
+  expression: String
+  projectId: ID
+  "Tasks the saved query matches"
+  tasks: [Task!]!
+}
+`
+
+// GraphQLHandlers serve a GraphQL view of tasks, comments, time entries, tags
+// and saved queries, so clients can fetch nested data in one request
+type GraphQLHandlers struct {
+	taskService *services.TaskService
+	schema      *graphql.Schema
+}
+
+func NewGraphQLHandlers(taskService *services.TaskService) *GraphQLHandlers {
+	h := &GraphQLHandlers{
+		taskService: taskService,
+	}
+	// The schema is fixed, so a mismatch with the resolvers is a programming error
+	h.schema = graphql.MustParseSchema(graphQLSchema, &queryResolver{taskService: taskService}, graphql.UseStringDescriptions())
+	return h
+}
+
+// graphQLRequest is the body of a GraphQL request
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query handles POST /api/v1/graphql
+// Runs a GraphQL query, {"query": "...", "variables": {...}, "operationName": "..."}.
+// Queries may select tasks, task, tags, savedQueries and savedQuery; see GET
+// /api/v1/graphql/schema, or ask the schema itself with an introspection
+// query. Invalid queries get 400 with errors and no data.
+func (h *GraphQLHandlers) Query(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		SendBadRequest(w, "Query is required", nil)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), commentLoaderKey{}, &commentLoader{taskService: h.taskService})
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetSchema handles GET /api/v1/graphql/schema
+// The GraphQL schema in the schema definition language, as text/plain.
+func (h *GraphQLHandlers) GetSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, graphQLSchema)
+}
+
+// commentLoader loads the comments of the tasks a query lists together, the
+// first time one of their comments is selected, rather than one task at a time
+type commentLoader struct {
+	taskService *services.TaskService
+	// Fields are resolved concurrently
+	mu      sync.Mutex
+	pending []uint
+	loaded  map[uint][]*models.Comment
+}
+
+type commentLoaderKey struct{}
+
+// expect notes tasks whose comments may be selected
+func (l *commentLoader) expect(tasks []*models.Task) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, task := range tasks {
+		if _, ok := l.loaded[task.ID]; !ok {
+			l.pending = append(l.pending, task.ID)
+		}
+	}
+}
+
+func (l *commentLoader) comments(taskID uint) ([]*models.Comment, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if comments, ok := l.loaded[taskID]; ok {
+		return comments, nil
+	}
+	ids := append(l.pending, taskID)
+	byTask, err := l.taskService.GetCommentsForTasks(ids)
+	if err != nil {
+		return nil, err
+	}
+	if l.loaded == nil {
+		l.loaded = make(map[uint][]*models.Comment)
+	}
+	for _, id := range ids {
+		l.loaded[id] = byTask[id]
+	}
+	l.pending = nil
+	return l.loaded[taskID], nil
+}
+
+func loaderFrom(ctx context.Context) *commentLoader {
+	return ctx.Value(commentLoaderKey{}).(*commentLoader)
+}
+
+// queryResolver resolves the fields of the Query type
+type queryResolver struct {
+	taskService *services.TaskService
+}
+
+// tasksArgs are the filters of the tasks field, as in GET /api/v1/tasks
+type tasksArgs struct {
+	Status    *[]string
+	Priority  *[]string
+	Tags      *[]string
+	TagExpr   *string
+	Search    *string
+	Project   *graphql.ID
+	Milestone *string
+	Context   *string
+	DueBefore *string
+	DueAfter  *string
+	Overdue   *bool
+	Limit     *int32
+	Offset    *int32
+}
+
+// values returns the arguments as the query parameters GetTasks takes
+func (a tasksArgs) values() url.Values {
+	values := url.Values{}
+	for param, list := range map[string]*[]string{"status": a.Status, "priority": a.Priority, "tags": a.Tags} {
+		if list != nil {
+			values.Set(param, strings.Join(*list, ","))
+		}
+	}
+	for param, s := range map[string]*string{"tag_expr": a.TagExpr, "search": a.Search, "milestone": a.Milestone,
+		"context": a.Context, "due_before": a.DueBefore, "due_after": a.DueAfter} {
+		if s != nil {
+			values.Set(param, *s)
+		}
+	}
+	if a.Project != nil {
+		values.Set("project", string(*a.Project))
+	}
+	if a.Overdue != nil {
+		values.Set("overdue", strconv.FormatBool(*a.Overdue))
+	}
+	for param, n := range map[string]*int32{"limit": a.Limit, "offset": a.Offset} {
+		if n != nil {
+			values.Set(param, strconv.Itoa(int(*n)))
+		}
+	}
+	return values
+}
+
+// Tasks filters and pages tasks like GetTasks, from the same filters
+func (q *queryResolver) Tasks(ctx context.Context, args tasksArgs) ([]*taskResolver, error) {
+	filters := ParseTaskFilters(args.values())
+	if err := prepareFilters(q.taskService, &filters); err != nil {
+		return nil, err
+	}
+
+	tasks, err := q.taskService.GetTasks()
+	if err != nil {
+		return nil, err
+	}
+	tasks = applyTaskFilters(tasks, filters)
+	if filters.Offset >= len(tasks) {
+		return []*taskResolver{}, nil
+	}
+	tasks = tasks[filters.Offset:min(filters.Offset+filters.Limit, len(tasks))]
+
+	loaderFrom(ctx).expect(tasks)
+	return taskResolvers(tasks), nil
+}
+
+func (q *queryResolver) Task(args struct{ ID graphql.ID }) (*taskResolver, error) {
+	id, err := parseGraphQLID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := q.taskService.GetTask(id)
+	if err != nil {
+		return nil, errors.New("task not found")
+	}
+	return &taskResolver{task}, nil
+}
+
+func (q *queryResolver) Tags() ([]*tagResolver, error) {
+	tasks, err := q.taskService.GetTasks()
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		for _, tag := range task.Tags {
+			counts[tag]++
+		}
+	}
+	tags := make([]*tagResolver, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, &tagResolver{taskService: q.taskService, tag: TagInfo{Name: tag, Count: count}})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].tag.Name < tags[j].tag.Name })
+	return tags, nil
+}
+
+func (q *queryResolver) SavedQueries() ([]*savedQueryResolver, error) {
+	queries, err := q.taskService.GetSavedQueries()
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*savedQueryResolver, len(queries))
+	for i, query := range queries {
+		resolvers[i] = &savedQueryResolver{taskService: q.taskService, query: query}
+	}
+	return resolvers, nil
+}
+
+func (q *queryResolver) SavedQuery(args struct{ ID graphql.ID }) (*savedQueryResolver, error) {
+	id, err := parseGraphQLID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	query, err := q.taskService.GetSavedQueryByID(id)
+	if err != nil {
+		return nil, errors.New("saved query not found")
+	}
+	return &savedQueryResolver{taskService: q.taskService, query: query}, nil
+}
+
+// taskResolver resolves the fields of the Task type
+type taskResolver struct {
+	task *models.Task
+}
+
+func taskResolvers(tasks []*models.Task) []*taskResolver {
+	resolvers := make([]*taskResolver, len(tasks))
+	for i, task := range tasks {
+		resolvers[i] = &taskResolver{task}
+	}
+	return resolvers
+}
+
+func (t *taskResolver) ID() graphql.ID            { return graphQLID(t.task.ID) }
+func (t *taskResolver) Name() string              { return t.task.Name }
+func (t *taskResolver) Description() *string      { return optionalString(t.task.Description) }
+func (t *taskResolver) Status() string            { return string(t.task.Status) }
+func (t *taskResolver) Priority() *string         { return optionalString(string(t.task.Priority)) }
+func (t *taskResolver) Assignee() *string         { return optionalString(t.task.Assignee) }
+func (t *taskResolver) Context() *string          { return optionalString(t.task.Context) }
+func (t *taskResolver) Size() *string             { return optionalString(string(t.task.Size)) }
+func (t *taskResolver) ProjectID() *graphql.ID    { return optionalID(t.task.ProjectID) }
+func (t *taskResolver) MilestoneID() *graphql.ID  { return optionalID(t.task.MilestoneID) }
+func (t *taskResolver) DueDate() *graphql.Time    { return optionalTime(t.task.DueDate) }
+func (t *taskResolver) Overdue() bool             { return t.task.Overdue }
+func (t *taskResolver) EstimateMinutes() int32    { return int32(t.task.EstimateMinutes) }
+func (t *taskResolver) LoggedMinutes() int32      { return int32(t.task.LoggedMinutes) }
+func (t *taskResolver) CreatedAt() graphql.Time   { return graphql.Time{Time: t.task.CreatedAt} }
+func (t *taskResolver) UpdatedAt() graphql.Time   { return graphql.Time{Time: t.task.UpdatedAt} }
+func (t *taskResolver) ResolvedAt() *graphql.Time { return optionalTime(t.task.ResolvedAt) }
+
+func (t *taskResolver) Tags() []string {
+	if t.task.Tags == nil {
+		return []string{}
+	}
+	return t.task.Tags
+}
+
+func (t *taskResolver) Comments(ctx context.Context) ([]*commentResolver, error) {
+	comments, err := loaderFrom(ctx).comments(t.task.ID)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*commentResolver, len(comments))
+	for i, comment := range comments {
+		resolvers[i] = &commentResolver{comment}
+	}
+	return resolvers, nil
+}
+
+func (t *taskResolver) TimeEntries() []*timeEntryResolver {
+	resolvers := make([]*timeEntryResolver, len(t.task.TimeEntries))
+	for i := range t.task.TimeEntries {
+		resolvers[i] = &timeEntryResolver{&t.task.TimeEntries[i]}
+	}
+	return resolvers
+}
+
+// commentResolver resolves the fields of the Comment type
+type commentResolver struct {
+	comment *models.Comment
+}
+
+func (c *commentResolver) ID() graphql.ID          { return graphQLID(c.comment.ID) }
+func (c *commentResolver) Content() string         { return c.comment.Content }
+func (c *commentResolver) IsPrivate() bool         { return c.comment.IsPrivate }
+func (c *commentResolver) FromEmail() *string      { return optionalString(c.comment.FromEmail) }
+func (c *commentResolver) CreatedBy() *string      { return optionalString(c.comment.CreatedBy) }
+func (c *commentResolver) CreatedAt() graphql.Time { return graphql.Time{Time: c.comment.CreatedAt} }
+
+// timeEntryResolver resolves the fields of the TimeEntry type
+type timeEntryResolver struct {
+	entry *models.TimeEntry
+}
+
+func (e *timeEntryResolver) ID() graphql.ID          { return graphQLID(e.entry.ID) }
+func (e *timeEntryResolver) Description() *string    { return optionalString(e.entry.Description) }
+func (e *timeEntryResolver) Duration() int32         { return int32(e.entry.Duration) }
+func (e *timeEntryResolver) CreatedBy() *string      { return optionalString(e.entry.CreatedBy) }
+func (e *timeEntryResolver) Billable() bool          { return e.entry.IsBillable() }
+func (e *timeEntryResolver) CreatedAt() graphql.Time { return graphql.Time{Time: e.entry.CreatedAt} }
+
+// tagResolver resolves the fields of the Tag type
+type tagResolver struct {
+	taskService *services.TaskService
+	tag         TagInfo
+}
+
+func (t *tagResolver) Name() string { return t.tag.Name }
+func (t *tagResolver) Count() int32 { return int32(t.tag.Count) }
+
+func (t *tagResolver) Tasks(ctx context.Context) ([]*taskResolver, error) {
+	tasks, err := t.taskService.GetTasks()
+	if err != nil {
+		return nil, err
+	}
+	tagged := []*models.Task{}
+	for _, task := range tasks {
+		for _, tag := range task.Tags {
+			if tag == t.tag.Name {
+				tagged = append(tagged, task)
+				break
+			}
+		}
+	}
+	loaderFrom(ctx).expect(tagged)
+	return taskResolvers(tagged), nil
+}
+
+// savedQueryResolver resolves the fields of the SavedQuery type
+type savedQueryResolver struct {
+	taskService *services.TaskService
+	query       *models.SavedQuery
+}
+
+func (s *savedQueryResolver) ID() graphql.ID         { return graphQLID(s.query.ID) }
+func (s *savedQueryResolver) Name() string           { return s.query.Name }
+func (s *savedQueryResolver) Expression() *string    { return optionalString(s.query.Expression) }
+func (s *savedQueryResolver) ProjectID() *graphql.ID { return optionalID(s.query.ProjectID) }
+
+func (s *savedQueryResolver) IncludedTags() []string {
+	if s.query.IncludedTags == nil {
+		return []string{}
+	}
+	return s.query.IncludedTags
+}
+
+func (s *savedQueryResolver) ExcludedTags() []string {
+	if s.query.ExcludedTags == nil {
+		return []string{}
+	}
+	return s.query.ExcludedTags
+}
+
+func (s *savedQueryResolver) Tasks(ctx context.Context) ([]*taskResolver, error) {
+	tasks, err := s.taskService.GetTasksBySavedQuery(s.query)
+	if err != nil {
+		return nil, err
+	}
+	loaderFrom(ctx).expect(tasks)
+	return taskResolvers(tasks), nil
+}
+
+// parseGraphQLID returns an ID argument as a database ID
+func parseGraphQLID(id graphql.ID) (uint, error) {
+	n, err := strconv.ParseUint(string(id), 10, 32)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid id %q", id)
+	}
+	return uint(n), nil
+}
+
+func graphQLID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+// optionalID returns a nullable ID, null for nil
+func optionalID(id *uint) *graphql.ID {
+	if id == nil {
+		return nil
+	}
+	gid := graphQLID(*id)
+	return &gid
+}
+
+// optionalString returns a nullable String, null for ""
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// optionalTime returns a nullable Time, null for nil
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
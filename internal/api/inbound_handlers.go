@@ -0,0 +1,113 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type InboundHandlers struct {
+	inboundService *services.InboundService
+}
+
+func NewInboundHandlers(inboundService *services.InboundService) *InboundHandlers {
+	return &InboundHandlers{
+		inboundService: inboundService,
+	}
+}
+
+// Receive handles POST /api/v1/inbound/{channel}, which monitoring systems call
+// with their JSON webhook payload. It is authenticated by the channel's secret
+// rather than a user's API key. The alertmanager channel takes Prometheus
+// Alertmanager's webhook payload.
+func (h *InboundHandlers) Receive(w http.ResponseWriter, r *http.Request) {
+	if h.inboundService == nil {
+		SendNotFound(w, services.ErrInboundChannelNotFound.Error())
+		return
+	}
+	channel := getInboundChannelFromPath(r)
+	if channel == services.AlertmanagerChannel {
+		h.receiveAlertmanager(w, r)
+		return
+	}
+
+	var payload map[string]interface{}
+	if err := ParseJSON(r, &payload); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	result, err := h.inboundService.Receive(channel, inboundSecret(r), payload)
+	if err != nil {
+		sendInboundError(w, err)
+		return
+	}
+
+	if result.Action == services.InboundActionCreated {
+		SendCreated(w, result, "Task created")
+		return
+	}
+	SendSuccess(w, result, "Delivery processed")
+}
+
+// receiveAlertmanager applies an Alertmanager notification, one task per alert
+func (h *InboundHandlers) receiveAlertmanager(w http.ResponseWriter, r *http.Request) {
+	var webhook services.AlertmanagerWebhook
+	if err := ParseJSON(r, &webhook); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	result, err := h.inboundService.ReceiveAlertmanager(inboundSecret(r), &webhook)
+	if err != nil {
+		sendInboundError(w, err)
+		return
+	}
+
+	if result.Created() {
+		SendCreated(w, result, "Task created")
+		return
+	}
+	SendSuccess(w, result, "Delivery processed")
+}
+
+// sendInboundError maps an inbound service error onto its HTTP status
+func sendInboundError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInboundChannelNotFound):
+		SendNotFound(w, err.Error())
+	case errors.Is(err, services.ErrInboundUnauthorized):
+		SendError(w, http.StatusUnauthorized, "UNAUTHORIZED", err.Error(), nil)
+	case errors.Is(err, services.ErrInvalidInboundPayload):
+		SendBadRequest(w, err.Error(), nil)
+	case errors.Is(err, services.ErrWIPLimitReached):
+		SendConflict(w, err.Error(), nil)
+	default:
+		SendInternalError(w, "Failed to process inbound delivery")
+	}
+}
+
+// inboundSecret returns the channel secret from the Authorization header, the
+// X-Inbound-Secret header or the secret query parameter, for senders that
+// cannot set headers
+func inboundSecret(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	if secret := r.Header.Get("X-Inbound-Secret"); secret != "" {
+		return secret
+	}
+	return r.URL.Query().Get("secret")
+}
+
+func getInboundChannelFromPath(r *http.Request) string {
+	parts := strings.Split(r.URL.Path, "/")
+	for i, part := range parts {
+		if part == "inbound" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
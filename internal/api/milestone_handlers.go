@@ -0,0 +1,213 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+type MilestoneHandlers struct {
+	taskService *services.TaskService
+}
+
+func NewMilestoneHandlers(taskService *services.TaskService) *MilestoneHandlers {
+	return &MilestoneHandlers{
+		taskService: taskService,
+	}
+}
+
+// MilestoneRequest represents a milestone create or update request
+type MilestoneRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	DueDate     string `json:"due_date,omitempty"` // any format accepted by utils.ParseDate; empty for none
+}
+
+// apply copies the request onto a milestone, parsing the due date
+func (req *MilestoneRequest) apply(milestone *models.Milestone) error {
+	milestone.Name = req.Name
+	milestone.Description = req.Description
+	milestone.DueDate = nil
+	if strings.TrimSpace(req.DueDate) != "" {
+		due, err := utils.ParseDate(req.DueDate)
+		if err != nil {
+			return err
+		}
+		milestone.DueDate = &due
+	}
+	return nil
+}
+
+// GetMilestones handles GET /api/v1/milestones and includes each milestone's progress
+func (h *MilestoneHandlers) GetMilestones(w http.ResponseWriter, r *http.Request) {
+	milestones, err := h.taskService.GetMilestones()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve milestones")
+		return
+	}
+
+	progress := make([]*services.MilestoneProgress, 0, len(milestones))
+	for _, milestone := range milestones {
+		p, err := h.taskService.GetMilestoneProgress(milestone.ID)
+		if err != nil {
+			SendInternalError(w, "Failed to retrieve milestone progress")
+			return
+		}
+		progress = append(progress, p)
+	}
+
+	SendSuccess(w, progress, "Milestones retrieved successfully")
+}
+
+// GetMilestone handles GET /api/v1/milestones/{id}
+func (h *MilestoneHandlers) GetMilestone(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid milestone ID", nil)
+		return
+	}
+
+	milestone, err := h.taskService.GetMilestone(id)
+	if err != nil {
+		h.sendMilestoneError(w, err)
+		return
+	}
+
+	SendSuccess(w, milestone, "Milestone retrieved successfully")
+}
+
+// CreateMilestone handles POST /api/v1/milestones
+func (h *MilestoneHandlers) CreateMilestone(w http.ResponseWriter, r *http.Request) {
+	var req MilestoneRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	milestone := &models.Milestone{}
+	if err := req.apply(milestone); err != nil {
+		SendBadRequest(w, "Invalid due date", err.Error())
+		return
+	}
+
+	if err := h.taskService.CreateMilestone(milestone); err != nil {
+		h.sendMilestoneError(w, err)
+		return
+	}
+
+	SendCreated(w, milestone, "Milestone created successfully")
+}
+
+// UpdateMilestone handles PUT /api/v1/milestones/{id}
+func (h *MilestoneHandlers) UpdateMilestone(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid milestone ID", nil)
+		return
+	}
+
+	var req MilestoneRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	milestone, err := h.taskService.GetMilestone(id)
+	if err != nil {
+		h.sendMilestoneError(w, err)
+		return
+	}
+	if err := req.apply(milestone); err != nil {
+		SendBadRequest(w, "Invalid due date", err.Error())
+		return
+	}
+
+	if err := h.taskService.UpdateMilestone(milestone); err != nil {
+		h.sendMilestoneError(w, err)
+		return
+	}
+
+	SendSuccess(w, milestone, "Milestone updated successfully")
+}
+
+// DeleteMilestone handles DELETE /api/v1/milestones/{id}; its tasks are kept
+func (h *MilestoneHandlers) DeleteMilestone(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid milestone ID", nil)
+		return
+	}
+
+	if err := h.taskService.DeleteMilestone(id); err != nil {
+		h.sendMilestoneError(w, err)
+		return
+	}
+
+	SendNoContent(w)
+}
+
+// GetMilestoneTasks handles GET /api/v1/milestones/{id}/tasks
+func (h *MilestoneHandlers) GetMilestoneTasks(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid milestone ID", nil)
+		return
+	}
+
+	tasks, err := h.taskService.GetMilestoneTasks(id)
+	if err != nil {
+		h.sendMilestoneError(w, err)
+		return
+	}
+
+	SendSuccess(w, tasks, "Milestone tasks retrieved successfully")
+}
+
+// GetMilestoneProgress handles GET /api/v1/milestones/{id}/progress
+func (h *MilestoneHandlers) GetMilestoneProgress(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid milestone ID", nil)
+		return
+	}
+
+	progress, err := h.taskService.GetMilestoneProgress(id)
+	if err != nil {
+		h.sendMilestoneError(w, err)
+		return
+	}
+
+	SendSuccess(w, progress, "Milestone progress retrieved successfully")
+}
+
+// GetMilestoneBurndown handles GET /api/v1/milestones/{id}/burndown
+func (h *MilestoneHandlers) GetMilestoneBurndown(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid milestone ID", nil)
+		return
+	}
+
+	burndown, err := h.taskService.GetMilestoneBurndown(id, time.Now())
+	if err != nil {
+		h.sendMilestoneError(w, err)
+		return
+	}
+
+	SendSuccess(w, burndown, "Milestone burndown retrieved successfully")
+}
+
+func (h *MilestoneHandlers) sendMilestoneError(w http.ResponseWriter, err error) {
+	switch err {
+	case services.ErrMilestoneNotFound:
+		SendNotFound(w, "Milestone not found")
+	case services.ErrMilestoneNameRequired:
+		SendValidationError(w, "Validation failed", []string{err.Error()})
+	default:
+		SendInternalError(w, "Failed to process milestone")
+	}
+}
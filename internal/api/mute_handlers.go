@@ -0,0 +1,108 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type MuteHandlers struct {
+	taskService *services.TaskService
+}
+
+func NewMuteHandlers(taskService *services.TaskService) *MuteHandlers {
+	return &MuteHandlers{taskService: taskService}
+}
+
+// MuteRequest mutes either a task or a tag for a duration such as "4h"
+type MuteRequest struct {
+	TaskID   uint   `json:"task_id,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// GetMutes handles GET /api/v1/mutes
+func (h *MuteHandlers) GetMutes(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetCurrentUser(r)
+	if user == nil {
+		SendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required", nil)
+		return
+	}
+
+	mutes, err := h.taskService.GetMutes(user.ID)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve mutes")
+		return
+	}
+
+	SendSuccess(w, mutes, "Mutes retrieved successfully")
+}
+
+// CreateMute handles POST /api/v1/mutes
+func (h *MuteHandlers) CreateMute(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetCurrentUser(r)
+	if user == nil {
+		SendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required", nil)
+		return
+	}
+
+	var req MuteRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+	if (req.TaskID == 0) == (req.Tag == "") {
+		SendValidationError(w, "Validation failed", []string{"exactly one of task_id or tag is required"})
+		return
+	}
+
+	var mute *models.NotificationMute
+	var err error
+	if req.TaskID != 0 {
+		mute, err = h.taskService.MuteTask(user.ID, req.TaskID, req.Duration)
+	} else {
+		mute, err = h.taskService.MuteTag(user.ID, req.Tag, req.Duration)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidMuteDuration), errors.Is(err, services.ErrMuteTagRequired):
+			SendValidationError(w, err.Error(), nil)
+		case req.TaskID != 0:
+			SendNotFound(w, "Task not found")
+		default:
+			SendInternalError(w, "Failed to create mute")
+		}
+		return
+	}
+
+	SendCreated(w, mute, "Mute created successfully")
+}
+
+// DeleteMute handles DELETE /api/v1/mutes/{id}
+func (h *MuteHandlers) DeleteMute(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetCurrentUser(r)
+	if user == nil {
+		SendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required", nil)
+		return
+	}
+
+	id, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid mute ID", nil)
+		return
+	}
+
+	if err := h.taskService.Unmute(user.ID, id); err != nil {
+		if errors.Is(err, services.ErrMuteNotFound) {
+			SendNotFound(w, "Mute not found")
+			return
+		}
+		SendInternalError(w, "Failed to delete mute")
+		return
+	}
+
+	SendNoContent(w)
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/services"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+type OutOfOfficeHandlers struct {
+	taskService *services.TaskService
+}
+
+func NewOutOfOfficeHandlers(taskService *services.TaskService) *OutOfOfficeHandlers {
+	return &OutOfOfficeHandlers{taskService: taskService}
+}
+
+// OutOfOfficeRequest marks the current user as away from start_date through
+// end_date, given as dates such as "2025-12-22" or "next monday"
+type OutOfOfficeRequest struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Note      string `json:"note,omitempty"`
+}
+
+// GetOutOfOffice handles GET /api/v1/out-of-office, the current user's ranges
+// that have not ended yet
+func (h *OutOfOfficeHandlers) GetOutOfOffice(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetCurrentUser(r)
+	if user == nil {
+		SendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required", nil)
+		return
+	}
+
+	ranges, err := h.taskService.GetOutOfOffice(user.ID)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve out-of-office ranges")
+		return
+	}
+
+	SendSuccess(w, ranges, "Out-of-office ranges retrieved successfully")
+}
+
+// GetTeamOutOfOffice handles GET /api/v1/out-of-office/team?week=, who is away
+// in the week containing week (default this week)
+func (h *OutOfOfficeHandlers) GetTeamOutOfOffice(w http.ResponseWriter, r *http.Request) {
+	week := time.Now()
+	if value := strings.TrimSpace(r.URL.Query().Get("week")); value != "" {
+		parsed, err := utils.ParseDate(value)
+		if err != nil {
+			SendBadRequest(w, "Invalid week", err.Error())
+			return
+		}
+		week = parsed
+	}
+
+	ranges, err := h.taskService.GetTeamOutOfOffice(week)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve out-of-office ranges")
+		return
+	}
+
+	SendSuccess(w, ranges, "Out-of-office ranges retrieved successfully")
+}
+
+// CreateOutOfOffice handles POST /api/v1/out-of-office
+func (h *OutOfOfficeHandlers) CreateOutOfOffice(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetCurrentUser(r)
+	if user == nil {
+		SendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required", nil)
+		return
+	}
+
+	var req OutOfOfficeRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	start, err := utils.ParseDate(strings.TrimSpace(req.StartDate))
+	if err != nil || strings.TrimSpace(req.StartDate) == "" {
+		SendValidationError(w, "Validation failed", []string{"start_date must be a date"})
+		return
+	}
+	end := start
+	if strings.TrimSpace(req.EndDate) != "" {
+		if end, err = utils.ParseDate(strings.TrimSpace(req.EndDate)); err != nil {
+			SendValidationError(w, "Validation failed", []string{"end_date must be a date"})
+			return
+		}
+	}
+
+	ooo, err := h.taskService.AddOutOfOffice(user, start, end, req.Note)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidOutOfOfficeRange) {
+			SendValidationError(w, err.Error(), nil)
+			return
+		}
+		SendInternalError(w, "Failed to create out-of-office range")
+		return
+	}
+
+	SendCreated(w, ooo, "Out-of-office range created successfully")
+}
+
+// DeleteOutOfOffice handles DELETE /api/v1/out-of-office/{id}
+func (h *OutOfOfficeHandlers) DeleteOutOfOffice(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetCurrentUser(r)
+	if user == nil {
+		SendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required", nil)
+		return
+	}
+
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid out-of-office ID", nil)
+		return
+	}
+
+	if err := h.taskService.DeleteOutOfOffice(user.ID, id); err != nil {
+		if errors.Is(err, services.ErrOutOfOfficeNotFound) {
+			SendNotFound(w, "Out-of-office range not found")
+			return
+		}
+		SendInternalError(w, "Failed to delete out-of-office range")
+		return
+	}
+
+	SendNoContent(w)
+}
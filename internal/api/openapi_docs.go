@@ -0,0 +1,175 @@
+// Code generated by gendocs from the handler doc comments; DO NOT EDIT.
+
+package api
+
+import "github.com/soarinferret/jats/internal/openapi"
+
+// HandlerDocs documents the API operations, keyed by openapi.Key
+var HandlerDocs = map[string]openapi.HandlerDoc{
+	"DELETE /api/v1/admin/canned-responses/{}":   {Handler: "DeleteCannedResponse", Doc: "DeleteCannedResponse handles DELETE /api/v1/admin/canned-responses/{id}"},
+	"DELETE /api/v1/admin/quarantine/{}":         {Handler: "DeleteQuarantined", Doc: "DeleteQuarantined handles DELETE /api/v1/admin/quarantine/{id}"},
+	"DELETE /api/v1/admin/query-webhooks/{}":     {Handler: "DeleteWebhook", Doc: "DeleteWebhook handles DELETE /api/v1/admin/query-webhooks/{id}"},
+	"DELETE /api/v1/admin/rules/{}":              {Handler: "DeleteAutomationRule", Doc: "DeleteAutomationRule handles DELETE /api/v1/admin/rules/{id}"},
+	"DELETE /api/v1/admin/tenants/{}":            {Handler: "DeleteTenant", Doc: "DeleteTenant handles DELETE /api/v1/admin/tenants/{slug}"},
+	"DELETE /api/v1/admin/users/{}":              {Handler: "DeleteUser", Doc: "DeleteUser handles DELETE /api/v1/admin/users/{id}, deleting a user (admin only)"},
+	"DELETE /api/v1/assets/{}":                   {Handler: "DeleteAsset", Doc: "DeleteAsset handles DELETE /api/v1/assets/{id}; its tasks are kept"},
+	"DELETE /api/v1/auth/api-keys":               {Handler: "DeleteAPIKey", Doc: "DeleteAPIKey handles DELETE /api/v1/auth/api-keys, deleting an API key"},
+	"DELETE /api/v1/auth/sessions/all":           {Handler: "LogoutAll", Doc: "LogoutAll handles DELETE /api/v1/auth/sessions/all, logging out all sessions for the current user"},
+	"DELETE /api/v1/auth/totp/disable":           {Handler: "DisableTOTP", Doc: "DisableTOTP handles DELETE /api/v1/auth/totp/disable, disabling TOTP for a user"},
+	"DELETE /api/v1/dashboard/layout":            {Handler: "ResetLayout", Doc: "ResetLayout handles DELETE /api/v1/dashboard/layout"},
+	"DELETE /api/v1/milestones/{}":               {Handler: "DeleteMilestone", Doc: "DeleteMilestone handles DELETE /api/v1/milestones/{id}; its tasks are kept"},
+	"DELETE /api/v1/mutes/{}":                    {Handler: "DeleteMute", Doc: "DeleteMute handles DELETE /api/v1/mutes/{id}"},
+	"DELETE /api/v1/out-of-office/{}":            {Handler: "DeleteOutOfOffice", Doc: "DeleteOutOfOffice handles DELETE /api/v1/out-of-office/{id}"},
+	"DELETE /api/v1/projects/{}":                 {Handler: "DeleteProject", Doc: "DeleteProject handles DELETE /api/v1/projects/{id} Its tasks and saved queries move to the default project, which cannot be deleted."},
+	"DELETE /api/v1/saved-queries/{}":            {Handler: "DeleteSavedQuery", Doc: "DeleteSavedQuery handles DELETE /api/v1/saved-queries/{id}"},
+	"DELETE /api/v1/saved-queries/{}/schedule":   {Handler: "DeleteSchedule", Doc: "DeleteSchedule handles DELETE /api/v1/saved-queries/{id}/schedule"},
+	"DELETE /api/v1/tasks/{}":                    {Handler: "DeleteTask", Doc: "DeleteTask handles DELETE /api/v1/tasks/{id}"},
+	"DELETE /api/v1/tasks/{}/assets/{}":          {Handler: "UnlinkTaskAsset", Doc: "UnlinkTaskAsset handles DELETE /api/v1/tasks/{id}/assets/{assetId}"},
+	"DELETE /api/v1/tasks/{}/comments/{}":        {Handler: "DeleteComment", Doc: "DeleteComment handles DELETE /api/v1/tasks/{taskId}/comments/{id}"},
+	"DELETE /api/v1/tasks/{}/dependencies/{}":    {Handler: "RemoveDependency", Doc: "RemoveDependency handles DELETE /api/v1/tasks/{id}/dependencies/{otherId} Removes the dependency between the two tasks, whichever blocks the other."},
+	"DELETE /api/v1/tasks/{}/scheduled/{}":       {Handler: "DeleteScheduledAction", Doc: "DeleteScheduledAction handles DELETE /api/v1/tasks/{id}/scheduled/{actionId}"},
+	"DELETE /api/v1/tasks/{}/subtasks/{}":        {Handler: "DeleteSubtask", Doc: "DeleteSubtask handles DELETE /api/v1/tasks/{taskId}/subtasks/{id}"},
+	"DELETE /api/v1/tasks/{}/tags/{}":            {Handler: "RemoveTaskTag", Doc: "RemoveTaskTag handles DELETE /api/v1/tasks/{id}/tags/{tag}"},
+	"DELETE /api/v1/tasks/{}/time/{}":            {Handler: "DeleteTimeEntry", Doc: "DeleteTimeEntry handles DELETE /api/v1/tasks/{taskId}/time/{id}"},
+	"GET /api/v1/activity":                       {Handler: "GetActivity", Doc: "GetActivity handles GET /api/v1/activity Query parameters: since, until (dates such as \"yesterday\" or \"2025-12-01\"), type (comma separated), task_id, tag, query (saved query ID), mention (username mentioned as @username or assigned the task), limit, offset. after (RFC 3339) returns only newer events; with wait=N seconds the request is held until one arrives, so clients can long-poll the feed."},
+	"GET /api/v1/admin/debug":                    {Handler: "GetRuntimeStats", Doc: "GetRuntimeStats handles GET /api/v1/admin/debug"},
+	"GET /api/v1/admin/debug/pprof/{}":           {Handler: "GetProfile", Doc: "GetProfile handles GET /api/v1/admin/debug/pprof/{profile}. Responses are in pprof format (or text with ?debug=1) for `go tool pprof`."},
+	"GET /api/v1/admin/plugins":                  {Handler: "GetPlugins", Doc: "GetPlugins handles GET /api/v1/admin/plugins"},
+	"GET /api/v1/admin/quarantine":               {Handler: "GetQuarantine", Doc: "GetQuarantine handles GET /api/v1/admin/quarantine"},
+	"GET /api/v1/admin/query-webhooks":           {Handler: "GetWebhooks", Doc: "GetWebhooks handles GET /api/v1/admin/query-webhooks"},
+	"GET /api/v1/admin/retention":                {Handler: "GetRetention", Doc: "GetRetention handles GET /api/v1/admin/retention"},
+	"GET /api/v1/admin/rules":                    {Handler: "GetAutomationRules", Doc: "GetAutomationRules handles GET /api/v1/admin/rules"},
+	"GET /api/v1/admin/sync":                     {Handler: "GetTargets", Doc: "GetTargets handles GET /api/v1/admin/sync"},
+	"GET /api/v1/admin/tenants":                  {Handler: "GetTenants", Doc: "GetTenants handles GET /api/v1/admin/tenants"},
+	"GET /api/v1/admin/users":                    {Handler: "GetAllUsers", Doc: "GetAllUsers handles GET /api/v1/admin/users, returning all users (admin only)"},
+	"GET /api/v1/admin/users/{}":                 {Handler: "GetUser", Doc: "GetUser handles GET /api/v1/admin/users/{id}, returning a specific user (admin only)"},
+	"GET /api/v1/admin/users/{}/export":          {Handler: "ExportUserData", Doc: "ExportUserData handles GET /api/v1/admin/users/{id}/export, returning a ZIP of the user's data. ?include=sessions,emails limits the sections exported."},
+	"GET /api/v1/assets":                         {Handler: "GetAssets", Doc: "GetAssets handles GET /api/v1/assets"},
+	"GET /api/v1/assets/{}":                      {Handler: "GetAsset", Doc: "GetAsset handles GET /api/v1/assets/{id}, including the asset's task history"},
+	"GET /api/v1/attachments/{}/download":        {Handler: "DownloadAttachment", Doc: "DownloadAttachment handles GET /api/v1/attachments/{id}/download"},
+	"GET /api/v1/auth/api-keys":                  {Handler: "GetAPIKeys", Doc: "GetAPIKeys handles GET /api/v1/auth/api-keys, all API keys for the current user"},
+	"GET /api/v1/auth/profile":                   {Handler: "GetProfile", Doc: "GetProfile handles GET /api/v1/auth/profile, the current user's profile"},
+	"GET /api/v1/auth/sessions":                  {Handler: "GetSessions", Doc: "GetSessions handles GET /api/v1/auth/sessions, all active sessions for the current user"},
+	"GET /api/v1/branding":                       {Handler: "GetBranding", Doc: "GetBranding handles GET /api/v1/branding"},
+	"GET /api/v1/canned-responses":               {Handler: "GetCannedResponses", Doc: "GetCannedResponses handles GET /api/v1/canned-responses"},
+	"GET /api/v1/contacts":                       {Handler: "GetContacts", Doc: "GetContacts handles GET /api/v1/contacts"},
+	"GET /api/v1/contacts/{}":                    {Handler: "GetContact", Doc: "GetContact handles GET /api/v1/contacts/{id}"},
+	"GET /api/v1/contexts":                       {Handler: "GetContexts", Doc: "GetContexts handles GET /api/v1/contexts Lists the task contexts in use, such as @home, with their open task counts"},
+	"GET /api/v1/dashboard/layout":               {Handler: "GetLayout", Doc: "GetLayout handles GET /api/v1/dashboard/layout"},
+	"GET /api/v1/dates/parse":                    {Handler: "ParseDate", Doc: "ParseDate handles GET /api/v1/dates/parse?q=next+friday"},
+	"GET /api/v1/docs":                           {Handler: "GetDocs", Doc: "GetDocs handles GET /api/v1/docs, Swagger UI for browsing and trying out the API"},
+	"GET /api/v1/events":                         {Handler: "StreamEvents", Doc: "StreamEvents handles GET /api/v1/events Streams task events as Server-Sent Events until the client disconnects or the server shuts down: task.created, task.updated, task.commented and task.deleted, each named by its type with the event as JSON data. Query parameters: type (comma separated) and task_id. A client reconnecting with Last-Event-ID first gets the recent events it missed."},
+	"GET /api/v1/feeds/saved-queries/{}":         {Handler: "GetSavedQueryFeed", Doc: "GetSavedQueryFeed handles GET /api/v1/feeds/saved-queries/{token} The feed token acts as the credential, so feed readers do not need an API key."},
+	"GET /api/v1/graphql/schema":                 {Handler: "GetSchema", Doc: "GetSchema handles GET /api/v1/graphql/schema The GraphQL schema in the schema definition language, as text/plain."},
+	"GET /api/v1/kanban":                         {Handler: "GetKanban", Doc: "GetKanban handles GET /api/v1/kanban Query parameters: saved_query_id, plus the task list filters (status, priority, tags, all_tags, exclude_tags, milestone, search, in). Columns and statistics only count the matching tasks; WIP counts cover the whole board."},
+	"GET /api/v1/kanban/{}":                      {Handler: "GetKanbanByTag", Doc: "GetKanbanByTag handles GET /api/v1/kanban/{tag}, taking the same query parameters as GetKanban"},
+	"GET /api/v1/milestones":                     {Handler: "GetMilestones", Doc: "GetMilestones handles GET /api/v1/milestones and includes each milestone's progress"},
+	"GET /api/v1/milestones/{}":                  {Handler: "GetMilestone", Doc: "GetMilestone handles GET /api/v1/milestones/{id}"},
+	"GET /api/v1/milestones/{}/burndown":         {Handler: "GetMilestoneBurndown", Doc: "GetMilestoneBurndown handles GET /api/v1/milestones/{id}/burndown"},
+	"GET /api/v1/milestones/{}/progress":         {Handler: "GetMilestoneProgress", Doc: "GetMilestoneProgress handles GET /api/v1/milestones/{id}/progress"},
+	"GET /api/v1/milestones/{}/tasks":            {Handler: "GetMilestoneTasks", Doc: "GetMilestoneTasks handles GET /api/v1/milestones/{id}/tasks"},
+	"GET /api/v1/mutes":                          {Handler: "GetMutes", Doc: "GetMutes handles GET /api/v1/mutes"},
+	"GET /api/v1/openapi.json":                   {Handler: "GetSpec", Doc: "GetSpec handles GET /api/v1/openapi.json, the OpenAPI 3.0 description of every /api/v1 endpoint"},
+	"GET /api/v1/out-of-office":                  {Handler: "GetOutOfOffice", Doc: "GetOutOfOffice handles GET /api/v1/out-of-office, the current user's ranges that have not ended yet"},
+	"GET /api/v1/out-of-office/team":             {Handler: "GetTeamOutOfOffice", Doc: "GetTeamOutOfOffice handles GET /api/v1/out-of-office/team?week=, who is away in the week containing week (default this week)"},
+	"GET /api/v1/plugins/{}/{}":                  {Handler: "ServePlugin", Doc: "ServePlugin handles GET /api/v1/plugins/{plugin}/{path} and POST /api/v1/plugins/{plugin}/{path}, running the plugin's script on the request; only a POST may change tasks. The script answers by setting response to {status: 200, body: ...}; the body is sent as the data of a success, or as the message of an error status."},
+	"GET /api/v1/projects":                       {Handler: "GetProjects", Doc: "GetProjects handles GET /api/v1/projects The default project comes first; each project has its open task count."},
+	"GET /api/v1/projects/{}":                    {Handler: "GetProject", Doc: "GetProject handles GET /api/v1/projects/{id}"},
+	"GET /api/v1/reports/capacity":               {Handler: "GetCapacityPlan", Doc: "GetCapacityPlan handles GET /api/v1/reports/capacity Uses the current user's weekly capacity unless ?capacity= (e.g. 30h) is given"},
+	"GET /api/v1/reports/dashboard":              {Handler: "GetDashboard", Doc: "GetDashboard handles GET /api/v1/reports/dashboard Returns the data for each widget of the current user's dashboard layout"},
+	"GET /api/v1/reports/sizes":                  {Handler: "GetSizeBreakdown", Doc: "GetSizeBreakdown handles GET /api/v1/reports/sizes Open work by size; ?query= reports on a saved query instead of every task"},
+	"GET /api/v1/reports/standup":                {Handler: "GetStandupReport", Doc: "GetStandupReport handles GET /api/v1/reports/standup?date=, the team's standup for a day (default today)"},
+	"GET /api/v1/reports/time-breakdown":         {Handler: "GetTimeBreakdownReport", Doc: "GetTimeBreakdownReport handles GET /api/v1/reports/time-breakdown"},
+	"GET /api/v1/reports/velocity":               {Handler: "GetVelocity", Doc: "GetVelocity handles GET /api/v1/reports/velocity Story points completed per week. Query parameters: weeks (default 8, up to 52, the current week included) and query, a saved query ID to report on instead of every task."},
+	"GET /api/v1/saved-queries":                  {Handler: "GetSavedQueries", Doc: "GetSavedQueries handles GET /api/v1/saved-queries With ?project=ID only the queries of that project and those for every project are listed."},
+	"GET /api/v1/saved-queries/{}":               {Handler: "GetSavedQuery", Doc: "GetSavedQuery handles GET /api/v1/saved-queries/{id}"},
+	"GET /api/v1/saved-queries/{}/schedule":      {Handler: "GetSchedule", Doc: "GetSchedule handles GET /api/v1/saved-queries/{id}/schedule"},
+	"GET /api/v1/saved-queries/{}/tasks":         {Handler: "GetTasksBySavedQuery", Doc: "GetTasksBySavedQuery handles GET /api/v1/saved-queries/{id}/tasks, the tasks matching a saved query"},
+	"GET /api/v1/search":                         {Handler: "Search", Doc: "Search handles GET /api/v1/search"},
+	"GET /api/v1/summary/tasks":                  {Handler: "GetTaskSummary", Doc: "GetTaskSummary handles GET /api/v1/summary/tasks Query parameters: saved_query_id, and either window (today, 7d, 30d or quarter; default 7d) or from/to dates (see utils.ParseDate, to is inclusive and defaults to now)."},
+	"GET /api/v1/tags":                           {Handler: "GetTags", Doc: "GetTags handles GET /api/v1/tags"},
+	"GET /api/v1/tags/stats":                     {Handler: "GetTagStats", Doc: "GetTagStats handles GET /api/v1/tags/stats Query parameters: since, until (dates such as \"yesterday\" or \"2025-12-01\", until inclusive) bound the resolved and logged counts; the default is the last 7 days."},
+	"GET /api/v1/tags/{}/tasks":                  {Handler: "GetTasksByTag", Doc: "GetTasksByTag handles GET /api/v1/tags/{tag}/tasks"},
+	"GET /api/v1/tasks":                          {Handler: "GetTasks", Doc: "GetTasks handles GET /api/v1/tasks"},
+	"GET /api/v1/tasks/{}":                       {Handler: "GetTask", Doc: "GetTask handles GET /api/v1/tasks/{id}"},
+	"GET /api/v1/tasks/{}/assets":                {Handler: "GetTaskAssets", Doc: "GetTaskAssets handles GET /api/v1/tasks/{id}/assets"},
+	"GET /api/v1/tasks/{}/attachments":           {Handler: "GetTaskAttachments", Doc: "GetTaskAttachments handles GET /api/v1/tasks/{id}/attachments"},
+	"GET /api/v1/tasks/{}/comments":              {Handler: "GetComments", Doc: "GetComments handles GET /api/v1/tasks/{id}/comments"},
+	"GET /api/v1/tasks/{}/dependencies":          {Handler: "GetDependencies", Doc: "GetDependencies handles GET /api/v1/tasks/{id}/dependencies"},
+	"GET /api/v1/tasks/{}/emails":                {Handler: "GetTaskEmails", Doc: "GetTaskEmails handles GET /api/v1/tasks/{id}/emails"},
+	"GET /api/v1/tasks/{}/job-sheet":             {Handler: "GetJobSheet", Doc: "GetJobSheet handles GET /api/v1/tasks/{id}/job-sheet, a printable PDF of the task whose QR code holds its short link"},
+	"GET /api/v1/tasks/{}/reviews":               {Handler: "GetTaskReviews", Doc: "GetTaskReviews handles GET /api/v1/tasks/{id}/reviews Returns the approvals and reopenings of the task, oldest first."},
+	"GET /api/v1/tasks/{}/scheduled":             {Handler: "GetScheduledActions", Doc: "GetScheduledActions handles GET /api/v1/tasks/{id}/scheduled"},
+	"GET /api/v1/tasks/{}/short-link":            {Handler: "GetShortLink", Doc: "GetShortLink handles GET /api/v1/tasks/{id}/short-link, the stable short link that opens the task after signing in, for printing on asset labels"},
+	"GET /api/v1/tasks/{}/status-history":        {Handler: "GetStatusHistory", Doc: "GetStatusHistory handles GET /api/v1/tasks/{id}/status-history"},
+	"GET /api/v1/tasks/{}/subtasks":              {Handler: "GetSubtasks", Doc: "GetSubtasks handles GET /api/v1/tasks/{id}/subtasks"},
+	"GET /api/v1/tasks/{}/time":                  {Handler: "GetTimeEntries", Doc: "GetTimeEntries handles GET /api/v1/tasks/{id}/time Query parameters: billable (true or false) to only list billable or non-billable time"},
+	"GET /api/v1/time":                           {Handler: "GetAllTimeEntries", Doc: "GetAllTimeEntries handles GET /api/v1/time Query parameters: since, until (dates such as \"yesterday\" or \"2025-12-01\"; until is inclusive), billable (true or false). Without since, the last week is returned. Entries are newest first and carry the name of their task."},
+	"GET /api/v1/time/timesheet":                 {Handler: "GetTimesheet", Doc: "GetTimesheet handles GET /api/v1/time/timesheet Query parameters: week (any date in the week, such as \"2025-12-01\" or \"last monday\"; defaults to this week). Returns the current user's time in the week, Monday to Sunday, as minutes per task and day."},
+	"PATCH /api/v1/auth/profile":                 {Handler: "UpdateProfile", Doc: "UpdateProfile handles PATCH /api/v1/auth/profile, updating the current user's preferences"},
+	"PATCH /api/v1/tasks/{}":                     {Handler: "PartialUpdateTask", Doc: "PartialUpdateTask handles PATCH /api/v1/tasks/{id}"},
+	"PATCH /api/v1/tasks/{}/subtasks/{}/toggle":  {Handler: "ToggleSubtask", Doc: "ToggleSubtask handles PATCH /api/v1/tasks/{taskId}/subtasks/{id}/toggle"},
+	"POST /api/v1/admin/canned-responses":        {Handler: "CreateCannedResponse", Doc: "CreateCannedResponse handles POST /api/v1/admin/canned-responses"},
+	"POST /api/v1/admin/email/simulate":          {Handler: "SimulateInboundEmail", Doc: "SimulateInboundEmail handles POST /api/v1/admin/email/simulate. The body is a raw RFC 822 message; the response describes what inbound processing would create from it, without creating anything."},
+	"POST /api/v1/admin/quarantine/{}/release":   {Handler: "ReleaseQuarantined", Doc: "ReleaseQuarantined handles POST /api/v1/admin/quarantine/{id}/release"},
+	"POST /api/v1/admin/query-webhooks":          {Handler: "CreateWebhook", Doc: "CreateWebhook handles POST /api/v1/admin/query-webhooks"},
+	"POST /api/v1/admin/retention/run":           {Handler: "RunRetention", Doc: "RunRetention handles POST /api/v1/admin/retention/run"},
+	"POST /api/v1/admin/rules":                   {Handler: "CreateAutomationRule", Doc: "CreateAutomationRule handles POST /api/v1/admin/rules"},
+	"POST /api/v1/admin/sync/{}/run":             {Handler: "RunTarget", Doc: "RunTarget handles POST /api/v1/admin/sync/{target}/run"},
+	"POST /api/v1/admin/tenants":                 {Handler: "CreateTenant", Doc: "CreateTenant handles POST /api/v1/admin/tenants"},
+	"POST /api/v1/admin/users":                   {Handler: "CreateUser", Doc: "CreateUser handles POST /api/v1/admin/users, creating a new user (admin only)"},
+	"POST /api/v1/admin/users/{}/erase":          {Handler: "EraseUser", Doc: "EraseUser handles POST /api/v1/admin/users/{id}/erase, anonymizing a departing user while keeping the task history they are part of"},
+	"POST /api/v1/admin/users/{}/reset-password": {Handler: "ResetUserPassword", Doc: "ResetUserPassword handles POST /api/v1/admin/users/{id}/reset-password, resetting a user's password (admin only)"},
+	"POST /api/v1/assets":                        {Handler: "CreateAsset", Doc: "CreateAsset handles POST /api/v1/assets"},
+	"POST /api/v1/auth/api-keys":                 {Handler: "CreateAPIKey", Doc: "CreateAPIKey handles POST /api/v1/auth/api-keys, creating a new API key"},
+	"POST /api/v1/auth/login":                    {Handler: "Login", Doc: "Login handles POST /api/v1/auth/login, starting a session"},
+	"POST /api/v1/auth/logout":                   {Handler: "Logout", Doc: "Logout handles POST /api/v1/auth/logout, ending the current session"},
+	"POST /api/v1/auth/register":                 {Handler: "Register", Doc: "Register handles POST /api/v1/auth/register, registering a new user"},
+	"POST /api/v1/auth/totp/enable":              {Handler: "EnableTOTP", Doc: "EnableTOTP handles POST /api/v1/auth/totp/enable, enabling TOTP for a user after verification"},
+	"POST /api/v1/auth/totp/setup":               {Handler: "SetupTOTP", Doc: "SetupTOTP handles POST /api/v1/auth/totp/setup, initiating TOTP setup for a user"},
+	"POST /api/v1/capture/mobile":                {Handler: "MobileCapture", Doc: "MobileCapture handles POST /api/v1/capture/mobile, meant for phone automations such as iOS Shortcuts or Tasker. It creates a task from the text, tagged with where it was sent from and with the photo attached."},
+	"POST /api/v1/graphql":                       {Handler: "Query", Doc: "Query handles POST /api/v1/graphql Runs a GraphQL query, {\"query\": \"...\", \"variables\": {...}, \"operationName\": \"...\"}. Queries may select tasks, task, tags, savedQueries and savedQuery; see GET /api/v1/graphql/schema, or ask the schema itself with an introspection query. Invalid queries get 400 with errors and no data."},
+	"POST /api/v1/inbound/{}":                    {Handler: "Receive", Doc: "Receive handles POST /api/v1/inbound/{channel}, which monitoring systems call with their JSON webhook payload. It is authenticated by the channel's secret rather than a user's API key. The alertmanager channel takes Prometheus Alertmanager's webhook payload."},
+	"POST /api/v1/milestones":                    {Handler: "CreateMilestone", Doc: "CreateMilestone handles POST /api/v1/milestones"},
+	"POST /api/v1/mutes":                         {Handler: "CreateMute", Doc: "CreateMute handles POST /api/v1/mutes"},
+	"POST /api/v1/out-of-office":                 {Handler: "CreateOutOfOffice", Doc: "CreateOutOfOffice handles POST /api/v1/out-of-office"},
+	"POST /api/v1/plugins/{}/{}":                 {Handler: "ServePlugin", Doc: "ServePlugin handles GET /api/v1/plugins/{plugin}/{path} and POST /api/v1/plugins/{plugin}/{path}, running the plugin's script on the request; only a POST may change tasks. The script answers by setting response to {status: 200, body: ...}; the body is sent as the data of a success, or as the message of an error status."},
+	"POST /api/v1/projects":                      {Handler: "CreateProject", Doc: "CreateProject handles POST /api/v1/projects"},
+	"POST /api/v1/saved-queries":                 {Handler: "CreateSavedQuery", Doc: "CreateSavedQuery handles POST /api/v1/saved-queries"},
+	"POST /api/v1/saved-queries/{}/feed-token":   {Handler: "RegenerateFeedToken", Doc: "RegenerateFeedToken handles POST /api/v1/saved-queries/{id}/feed-token"},
+	"POST /api/v1/tags/{}/apply":                 {Handler: "ApplyTag", Doc: "ApplyTag handles POST /api/v1/tags/{tag}/apply"},
+	"POST /api/v1/tags/{}/remove":                {Handler: "RemoveTag", Doc: "RemoveTag handles POST /api/v1/tags/{tag}/remove"},
+	"POST /api/v1/tasks":                         {Handler: "CreateTask", Doc: "CreateTask handles POST /api/v1/tasks"},
+	"POST /api/v1/tasks/bulk":                    {Handler: "CreateTasks", Doc: "CreateTasks handles POST /api/v1/tasks/bulk. If an entry fails after earlier ones were created, the error details list the IDs of the created tasks."},
+	"POST /api/v1/tasks/import":                  {Handler: "ImportTasks", Doc: "ImportTasks handles POST /api/v1/tasks/import Creates the tasks of an org-mode file or a Markdown checklist with their subtasks. Headings become tags, or with \"headings\": \"projects\" the outermost heading names the project, created if needed."},
+	"POST /api/v1/tasks/quick":                   {Handler: "QuickAddTask", Doc: "QuickAddTask handles POST /api/v1/tasks/quick"},
+	"POST /api/v1/tasks/{}/assets":               {Handler: "LinkTaskAsset", Doc: "LinkTaskAsset handles POST /api/v1/tasks/{id}/assets"},
+	"POST /api/v1/tasks/{}/attachments":          {Handler: "UploadAttachment", Doc: "UploadAttachment handles POST /api/v1/tasks/{id}/attachments, a multipart form with the file in its \"file\" field. The web UI uploads pasted screenshots this way and references them in notes as ![name](attachment:{id})."},
+	"POST /api/v1/tasks/{}/comments":             {Handler: "CreateComment", Doc: "CreateComment handles POST /api/v1/tasks/{id}/comments"},
+	"POST /api/v1/tasks/{}/dependencies":         {Handler: "AddDependency", Doc: "AddDependency handles POST /api/v1/tasks/{id}/dependencies The body names either the task this one blocks or the task blocking it. A task cannot be resolved while a task blocking it is still open."},
+	"POST /api/v1/tasks/{}/email-update":         {Handler: "SendEmailUpdate", Doc: "SendEmailUpdate handles POST /api/v1/tasks/{id}/email-update"},
+	"POST /api/v1/tasks/{}/email-update/preview": {Handler: "PreviewEmailUpdate", Doc: "PreviewEmailUpdate handles POST /api/v1/tasks/{id}/email-update/preview"},
+	"POST /api/v1/tasks/{}/review":               {Handler: "ReviewTask", Doc: "ReviewTask handles POST /api/v1/tasks/{id}/review A reviewer of the task's tags approves it, resolving it, or reopens it with a comment."},
+	"POST /api/v1/tasks/{}/scheduled":            {Handler: "CreateScheduledAction", Doc: "CreateScheduledAction handles POST /api/v1/tasks/{id}/scheduled"},
+	"POST /api/v1/tasks/{}/subtasks":             {Handler: "CreateSubtask", Doc: "CreateSubtask handles POST /api/v1/tasks/{id}/subtasks"},
+	"POST /api/v1/tasks/{}/tags":                 {Handler: "AddTaskTags", Doc: "AddTaskTags handles POST /api/v1/tasks/{id}/tags"},
+	"POST /api/v1/tasks/{}/time":                 {Handler: "CreateTimeEntry", Doc: "CreateTimeEntry handles POST /api/v1/tasks/{id}/time"},
+	"POST /api/v1/time/bulk":                     {Handler: "CreateTimeEntries", Doc: "CreateTimeEntries handles POST /api/v1/time/bulk"},
+	"PUT /api/v1/admin/canned-responses/{}":      {Handler: "UpdateCannedResponse", Doc: "UpdateCannedResponse handles PUT /api/v1/admin/canned-responses/{id}"},
+	"PUT /api/v1/admin/query-webhooks/{}":        {Handler: "UpdateWebhook", Doc: "UpdateWebhook handles PUT /api/v1/admin/query-webhooks/{id}"},
+	"PUT /api/v1/admin/rules/{}":                 {Handler: "UpdateAutomationRule", Doc: "UpdateAutomationRule handles PUT /api/v1/admin/rules/{id}"},
+	"PUT /api/v1/admin/settings/branding":        {Handler: "UpdateBranding", Doc: "UpdateBranding handles PUT /api/v1/admin/settings/branding"},
+	"PUT /api/v1/admin/users/{}":                 {Handler: "UpdateUser", Doc: "UpdateUser handles PUT /api/v1/admin/users/{id}, updating a user (admin only)"},
+	"PUT /api/v1/assets/{}":                      {Handler: "UpdateAsset", Doc: "UpdateAsset handles PUT /api/v1/assets/{id}"},
+	"PUT /api/v1/dashboard/layout":               {Handler: "UpdateLayout", Doc: "UpdateLayout handles PUT /api/v1/dashboard/layout"},
+	"PUT /api/v1/milestones/{}":                  {Handler: "UpdateMilestone", Doc: "UpdateMilestone handles PUT /api/v1/milestones/{id}"},
+	"PUT /api/v1/projects/{}":                    {Handler: "UpdateProject", Doc: "UpdateProject handles PUT /api/v1/projects/{id}"},
+	"PUT /api/v1/saved-queries/order":            {Handler: "ReorderSavedQueries", Doc: "ReorderSavedQueries handles PUT /api/v1/saved-queries/order"},
+	"PUT /api/v1/saved-queries/{}":               {Handler: "UpdateSavedQuery", Doc: "UpdateSavedQuery handles PUT /api/v1/saved-queries/{id}"},
+	"PUT /api/v1/saved-queries/{}/schedule":      {Handler: "PutSchedule", Doc: "PutSchedule handles PUT /api/v1/saved-queries/{id}/schedule"},
+	"PUT /api/v1/tasks/{}":                       {Handler: "UpdateTask", Doc: "UpdateTask handles PUT /api/v1/tasks/{id}"},
+	"PUT /api/v1/tasks/{}/comments/{}":           {Handler: "UpdateComment", Doc: "UpdateComment handles PUT /api/v1/tasks/{taskId}/comments/{id}"},
+	"PUT /api/v1/tasks/{}/subtasks/{}":           {Handler: "UpdateSubtask", Doc: "UpdateSubtask handles PUT /api/v1/tasks/{taskId}/subtasks/{id}"},
+	"PUT /api/v1/tasks/{}/time/{}":               {Handler: "UpdateTimeEntry", Doc: "UpdateTimeEntry handles PUT /api/v1/tasks/{taskId}/time/{id}. The duration and description are replaced; a date moves the entry to that day."},
+	"PUT /api/v1/time/timesheet":                 {Handler: "UpdateTimesheet", Doc: "UpdateTimesheet handles PUT /api/v1/time/timesheet. Each cell sets the current user's total time on a task for a day, creating, updating or deleting their entries to match; 0 clears the cell. Returns the timesheet of the week of the first cell."},
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/openapi"
+)
+
+func TestHandlerDocsUpToDate(t *testing.T) {
+	docs, err := openapi.ParseHandlerDocs(".")
+	if err != nil {
+		t.Fatalf("ParseHandlerDocs failed: %v", err)
+	}
+	if !reflect.DeepEqual(docs, HandlerDocs) {
+		t.Errorf("openapi_docs.go is out of date with the handler doc comments; run go generate ./internal/api")
+	}
+}
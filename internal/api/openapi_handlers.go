@@ -0,0 +1,63 @@
+package api
+
+//go:generate go run ../openapi/gendocs -o openapi_docs.go
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/openapi"
+)
+
+// OpenAPIHandlers serve the OpenAPI description of the API and Swagger UI for it
+type OpenAPIHandlers struct {
+	spec func() *openapi.Document
+}
+
+// NewOpenAPIHandlers creates handlers serving the document spec returns
+func NewOpenAPIHandlers(spec func() *openapi.Document) *OpenAPIHandlers {
+	return &OpenAPIHandlers{
+		spec: spec,
+	}
+}
+
+// GetSpec handles GET /api/v1/openapi.json, the OpenAPI 3.0 description of
+// every /api/v1 endpoint
+func (h *OpenAPIHandlers) GetSpec(w http.ResponseWriter, r *http.Request) {
+	server := middleware.BasePath(r)
+	if server == "" {
+		server = "/"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.spec().WithServer(server))
+}
+
+// GetDocs handles GET /api/v1/docs, Swagger UI for browsing and trying out the API
+func (h *OpenAPIHandlers) GetDocs(w http.ResponseWriter, r *http.Request) {
+	specURL := html.EscapeString(middleware.BasePath(r) + "/api/v1/openapi.json")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>JATS API</title>
+	<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui" data-url="%s"></div>
+	<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+	<script>
+		const root = document.getElementById('swagger-ui');
+		SwaggerUIBundle({url: root.dataset.url, dom_id: '#swagger-ui'});
+	</script>
+</body>
+</html>
+`, specURL)
+}
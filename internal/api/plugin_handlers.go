@@ -0,0 +1,129 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// pluginRoutePrefix is where plugin routes start; the next path segment is the plugin name
+const pluginRoutePrefix = "/api/v1/plugins/"
+
+type PluginHandlers struct {
+	pluginService *services.PluginService
+}
+
+func NewPluginHandlers(pluginService *services.PluginService) *PluginHandlers {
+	return &PluginHandlers{
+		pluginService: pluginService,
+	}
+}
+
+// GetPlugins handles GET /api/v1/admin/plugins
+func (h *PluginHandlers) GetPlugins(w http.ResponseWriter, r *http.Request) {
+	plugins := []services.PluginStatus{}
+	if h.pluginService != nil {
+		plugins = h.pluginService.Plugins()
+	}
+	SendSuccess(w, plugins, "Plugins retrieved successfully")
+}
+
+// ServePlugin handles GET /api/v1/plugins/{plugin}/{path} and POST /api/v1/plugins/{plugin}/{path},
+// running the plugin's script on the request; only a POST may change tasks.
+// The script answers by setting
+// response to {status: 200, body: ...}; the body is sent as the data of a
+// success, or as the message of an error status.
+func (h *PluginHandlers) ServePlugin(w http.ResponseWriter, r *http.Request) {
+	name, path := getPluginFromPath(r)
+	if h.pluginService == nil || name == "" {
+		SendNotFound(w, services.ErrPluginNotFound.Error())
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			SendPayloadTooLarge(w, tooLarge.Limit)
+			return
+		}
+		SendBadRequest(w, "Failed to read request body", nil)
+		return
+	}
+	req := &services.PluginRequest{
+		Method: r.Method,
+		Path:   path,
+		Query:  make(map[string]string),
+		Body:   string(body),
+	}
+	for key := range r.URL.Query() {
+		req.Query[key] = r.URL.Query().Get(key)
+	}
+	if user := middleware.GetCurrentUser(r); user != nil {
+		req.User = user.Username
+	}
+
+	resp, err := h.pluginService.Serve(name, req)
+	if err != nil {
+		if errors.Is(err, services.ErrPluginNotFound) {
+			SendNotFound(w, err.Error())
+			return
+		}
+		log.Printf("Failed to serve %s %s: %v", r.Method, r.URL.Path, err)
+		SendInternalError(w, "Plugin failed")
+		return
+	}
+
+	if resp.Status >= 400 {
+		message, ok := resp.Body.(string)
+		if !ok {
+			message = http.StatusText(resp.Status)
+		}
+		SendError(w, resp.Status, pluginErrorCode(resp.Status), message, nil)
+		return
+	}
+	switch resp.Status {
+	case http.StatusOK:
+		SendSuccess(w, resp.Body, "")
+	case http.StatusCreated:
+		SendCreated(w, resp.Body, "")
+	case http.StatusNoContent:
+		SendNoContent(w)
+	default:
+		w.WriteHeader(resp.Status)
+	}
+}
+
+// pluginErrorCode returns the API error code of an error status set by a plugin
+func pluginErrorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "BAD_REQUEST"
+	case http.StatusForbidden:
+		return "FORBIDDEN"
+	case http.StatusNotFound:
+		return "NOT_FOUND"
+	case http.StatusConflict:
+		return "CONFLICT"
+	}
+	if status >= 500 {
+		return "INTERNAL_ERROR"
+	}
+	return "PLUGIN_ERROR"
+}
+
+// getPluginFromPath returns the plugin name and the rest of the path, e.g.
+// "hello" and "/greet" for /api/v1/plugins/hello/greet
+func getPluginFromPath(r *http.Request) (name, path string) {
+	i := strings.Index(r.URL.Path, pluginRoutePrefix)
+	if i < 0 {
+		return "", ""
+	}
+	name, path, _ = strings.Cut(r.URL.Path[i+len(pluginRoutePrefix):], "/")
+	return name, "/" + path
+}
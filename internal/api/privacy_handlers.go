@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type PrivacyHandlers struct {
+	privacyService *services.PrivacyService
+}
+
+func NewPrivacyHandlers(privacyService *services.PrivacyService) *PrivacyHandlers {
+	return &PrivacyHandlers{
+		privacyService: privacyService,
+	}
+}
+
+// ExportUserData handles GET /api/v1/admin/users/{id}/export, returning a ZIP
+// of the user's data. ?include=sessions,emails limits the sections exported.
+func (h *PrivacyHandlers) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetIDFromPath(r)
+	if err != nil || userID == 0 {
+		SendBadRequest(w, "Invalid user ID", nil)
+		return
+	}
+
+	var sections []string
+	if include := r.URL.Query().Get("include"); include != "" {
+		for _, section := range strings.Split(include, ",") {
+			sections = append(sections, strings.TrimSpace(section))
+		}
+	}
+
+	var archive bytes.Buffer
+	if err := h.privacyService.ExportUserData(userID, sections, &archive); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			SendNotFound(w, "User not found")
+		case errors.Is(err, services.ErrUnknownExportSection):
+			SendValidationError(w, err.Error(), map[string]interface{}{"sections": services.UserDataSections})
+		default:
+			SendInternalError(w, "Failed to export user data")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%d-export.zip"`, userID))
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive.Bytes())
+}
+
+// EraseUser handles POST /api/v1/admin/users/{id}/erase, anonymizing a
+// departing user while keeping the task history they are part of
+func (h *PrivacyHandlers) EraseUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := GetIDFromPath(r)
+	if err != nil || userID == 0 {
+		SendBadRequest(w, "Invalid user ID", nil)
+		return
+	}
+
+	actor := middleware.GetCurrentUser(r)
+	if actor == nil {
+		SendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required", nil)
+		return
+	}
+
+	if err := h.privacyService.EraseUser(userID, actor.ID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			SendNotFound(w, "User not found")
+		case errors.Is(err, services.ErrCannotEraseSelf):
+			SendBadRequest(w, err.Error(), nil)
+		default:
+			SendInternalError(w, "Failed to erase user")
+		}
+		return
+	}
+
+	SendSuccess(w, map[string]string{
+		"username": services.ErasedUsername(userID),
+		"email":    services.ErasedEmail(userID),
+	}, "User erased successfully")
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type ProjectHandlers struct {
+	taskService *services.TaskService
+}
+
+func NewProjectHandlers(taskService *services.TaskService) *ProjectHandlers {
+	return &ProjectHandlers{
+		taskService: taskService,
+	}
+}
+
+// ProjectRequest represents a project create or update request
+type ProjectRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// GetProjects handles GET /api/v1/projects
+// The default project comes first; each project has its open task count.
+func (h *ProjectHandlers) GetProjects(w http.ResponseWriter, r *http.Request) {
+	projects, err := h.taskService.GetProjects()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve projects")
+		return
+	}
+
+	SendSuccess(w, projects, "Projects retrieved successfully")
+}
+
+// GetProject handles GET /api/v1/projects/{id}
+func (h *ProjectHandlers) GetProject(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid project ID", nil)
+		return
+	}
+
+	project, err := h.taskService.GetProject(id)
+	if err != nil {
+		h.sendProjectError(w, err)
+		return
+	}
+
+	SendSuccess(w, project, "Project retrieved successfully")
+}
+
+// CreateProject handles POST /api/v1/projects
+func (h *ProjectHandlers) CreateProject(w http.ResponseWriter, r *http.Request) {
+	var req ProjectRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	project := &models.Project{Name: req.Name, Description: req.Description}
+	if err := h.taskService.CreateProject(project); err != nil {
+		h.sendProjectError(w, err)
+		return
+	}
+
+	SendCreated(w, project, "Project created successfully")
+}
+
+// UpdateProject handles PUT /api/v1/projects/{id}
+func (h *ProjectHandlers) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid project ID", nil)
+		return
+	}
+
+	var req ProjectRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	project, err := h.taskService.GetProject(id)
+	if err != nil {
+		h.sendProjectError(w, err)
+		return
+	}
+	project.Name = req.Name
+	project.Description = req.Description
+
+	if err := h.taskService.UpdateProject(project); err != nil {
+		h.sendProjectError(w, err)
+		return
+	}
+
+	SendSuccess(w, project, "Project updated successfully")
+}
+
+// DeleteProject handles DELETE /api/v1/projects/{id}
+// Its tasks and saved queries move to the default project, which cannot be
+// deleted.
+func (h *ProjectHandlers) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid project ID", nil)
+		return
+	}
+
+	if err := h.taskService.DeleteProject(id); err != nil {
+		h.sendProjectError(w, err)
+		return
+	}
+
+	SendNoContent(w)
+}
+
+func (h *ProjectHandlers) sendProjectError(w http.ResponseWriter, err error) {
+	switch err {
+	case services.ErrProjectNotFound:
+		SendNotFound(w, "Project not found")
+	case services.ErrProjectNameRequired, services.ErrProjectNameTaken, services.ErrDefaultProjectDelete:
+		SendValidationError(w, "Validation failed", []string{err.Error()})
+	default:
+		SendInternalError(w, "Failed to process project")
+	}
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type QuarantineHandlers struct {
+	spamService *services.SpamService
+}
+
+func NewQuarantineHandlers(spamService *services.SpamService) *QuarantineHandlers {
+	return &QuarantineHandlers{
+		spamService: spamService,
+	}
+}
+
+// GetQuarantine handles GET /api/v1/admin/quarantine
+func (h *QuarantineHandlers) GetQuarantine(w http.ResponseWriter, r *http.Request) {
+	emails, err := h.spamService.ListQuarantined()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve quarantined emails")
+		return
+	}
+
+	SendSuccess(w, emails, "Quarantined emails retrieved successfully")
+}
+
+// ReleaseQuarantined handles POST /api/v1/admin/quarantine/{id}/release
+func (h *QuarantineHandlers) ReleaseQuarantined(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid quarantined email ID", nil)
+		return
+	}
+
+	if err := h.spamService.ReleaseQuarantined(id); err != nil {
+		switch err {
+		case services.ErrQuarantinedEmailNotFound:
+			SendNotFound(w, err.Error())
+		case services.ErrReleaseUnavailable:
+			SendConflict(w, err.Error(), nil)
+		default:
+			SendInternalError(w, "Failed to release quarantined email")
+		}
+		return
+	}
+
+	SendSuccess(w, nil, "Email released successfully")
+}
+
+// DeleteQuarantined handles DELETE /api/v1/admin/quarantine/{id}
+func (h *QuarantineHandlers) DeleteQuarantined(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid quarantined email ID", nil)
+		return
+	}
+
+	if err := h.spamService.DeleteQuarantined(id); err != nil {
+		if err == services.ErrQuarantinedEmailNotFound {
+			SendNotFound(w, err.Error())
+			return
+		}
+		SendInternalError(w, "Failed to delete quarantined email")
+		return
+	}
+
+	SendSuccess(w, nil, "Quarantined email deleted successfully")
+}
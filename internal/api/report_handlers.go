@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/soarinferret/jats/internal/services"
+	"github.com/soarinferret/jats/internal/utils"
 )
 
 type ReportHandlers struct {
@@ -91,8 +92,14 @@ func (h *ReportHandlers) GetTimeBreakdownReport(w http.ResponseWriter, r *http.R
 		}
 	}
 
+	billable, err := parseBillableFilter(r)
+	if err != nil {
+		SendBadRequest(w, err.Error(), nil)
+		return
+	}
+
 	// Generate report
-	report, err := h.reportService.GenerateTimeBreakdownReport(startDate, endDate, savedQueryIDs, excludedTags)
+	report, err := h.reportService.GenerateTimeBreakdownReport(startDate, endDate, savedQueryIDs, excludedTags, billable)
 	if err != nil {
 		SendInternalError(w, "Failed to generate report: "+err.Error())
 		return
@@ -100,3 +107,25 @@ func (h *ReportHandlers) GetTimeBreakdownReport(w http.ResponseWriter, r *http.R
 
 	SendSuccess(w, report, "Time breakdown report generated successfully")
 }
+
+// GetStandupReport handles GET /api/v1/reports/standup?date=, the team's
+// standup for a day (default today)
+func (h *ReportHandlers) GetStandupReport(w http.ResponseWriter, r *http.Request) {
+	date := time.Now()
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		parsed, err := utils.ParseDate(dateStr)
+		if err != nil {
+			SendBadRequest(w, "invalid date: "+err.Error(), nil)
+			return
+		}
+		date = parsed
+	}
+
+	report, err := h.reportService.GenerateStandupReport(date)
+	if err != nil {
+		SendInternalError(w, "Failed to generate report: "+err.Error())
+		return
+	}
+
+	SendSuccess(w, report, "Standup report generated successfully")
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 )
@@ -96,11 +97,26 @@ func SendNotFound(w http.ResponseWriter, message string) {
 	SendError(w, http.StatusNotFound, "NOT_FOUND", message, nil)
 }
 
+// SendForbidden sends a 403 Forbidden response
+func SendForbidden(w http.ResponseWriter, message string) {
+	SendError(w, http.StatusForbidden, "FORBIDDEN", message, nil)
+}
+
+// SendConflict sends a 409 Conflict response
+func SendConflict(w http.ResponseWriter, message string, details interface{}) {
+	SendError(w, http.StatusConflict, "CONFLICT", message, details)
+}
+
 // SendInternalError sends a 500 Internal Server Error response
 func SendInternalError(w http.ResponseWriter, message string) {
 	SendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", message, nil)
 }
 
+// SendPayloadTooLarge sends a 413 Request Entity Too Large response
+func SendPayloadTooLarge(w http.ResponseWriter, limit int64) {
+	SendError(w, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", fmt.Sprintf("Request body exceeds the limit of %d bytes", limit), nil)
+}
+
 // SendValidationError sends a 422 Unprocessable Entity response
 func SendValidationError(w http.ResponseWriter, message string, details interface{}) {
 	SendError(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", message, details)
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type RetentionHandlers struct {
+	retentionService *services.RetentionService
+}
+
+// RetentionStatus is the enforced retention policy and the janitor's last result
+type RetentionStatus struct {
+	Policy  services.RetentionPolicy `json:"policy"`
+	LastRun *services.RetentionRun   `json:"last_run"`
+}
+
+func NewRetentionHandlers(retentionService *services.RetentionService) *RetentionHandlers {
+	return &RetentionHandlers{
+		retentionService: retentionService,
+	}
+}
+
+// GetRetention handles GET /api/v1/admin/retention
+func (h *RetentionHandlers) GetRetention(w http.ResponseWriter, r *http.Request) {
+	SendSuccess(w, RetentionStatus{
+		Policy:  h.retentionService.Policy(),
+		LastRun: h.retentionService.LastRun(),
+	}, "Retention status retrieved successfully")
+}
+
+// RunRetention handles POST /api/v1/admin/retention/run
+func (h *RetentionHandlers) RunRetention(w http.ResponseWriter, r *http.Request) {
+	// Failures are part of the run result, so the caller always gets the status back
+	h.retentionService.Run(time.Now())
+
+	SendSuccess(w, RetentionStatus{
+		Policy:  h.retentionService.Policy(),
+		LastRun: h.retentionService.LastRun(),
+	}, "Retention cleanup completed")
+}
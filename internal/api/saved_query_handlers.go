@@ -1,11 +1,12 @@
 package api
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
-	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/services"
 )
@@ -20,27 +21,76 @@ func NewSavedQueryHandlers(taskService *services.TaskService) *SavedQueryHandler
 	}
 }
 
+// GetSavedQueries handles GET /api/v1/saved-queries
+// With ?project=ID only the queries of that project and those for every
+// project are listed.
 func (h *SavedQueryHandlers) GetSavedQueries(w http.ResponseWriter, r *http.Request) {
-	queries, err := h.taskService.GetSavedQueries()
+	var queries []*models.SavedQuery
+	var err error
+	if projectStr := r.URL.Query().Get("project"); projectStr != "" {
+		projectID, parseErr := strconv.ParseUint(projectStr, 10, 32)
+		if parseErr != nil {
+			SendBadRequest(w, "Invalid project ID", nil)
+			return
+		}
+		queries, err = h.taskService.GetProjectSavedQueries(uint(projectID))
+	} else {
+		queries, err = h.taskService.GetSavedQueries()
+	}
 	if err != nil {
 		SendInternalError(w, "Failed to retrieve saved queries")
 		return
 	}
 
+	if counts, _ := strconv.ParseBool(r.URL.Query().Get("counts")); counts {
+		if err := h.taskService.CountBySavedQueries(queries); err != nil {
+			SendInternalError(w, "Failed to count tasks")
+			return
+		}
+	}
+
+	if user := middleware.GetCurrentUser(r); user != nil {
+		queries = services.PinSavedQueries(queries, user.PinnedQueryIDs)
+	}
+
 	SendSuccess(w, queries, "Saved queries retrieved successfully")
 }
 
-func (h *SavedQueryHandlers) GetSavedQuery(w http.ResponseWriter, r *http.Request) {
-	c := r.Context().Value("gin").(*gin.Context)
-	idParam := c.Param("id")
-	
-	id, err := strconv.ParseUint(idParam, 10, 32)
+// ReorderSavedQueriesRequest is the body of PUT /api/v1/saved-queries/order
+type ReorderSavedQueriesRequest struct {
+	IDs []uint `json:"ids"`
+}
+
+// ReorderSavedQueries handles PUT /api/v1/saved-queries/order
+func (h *SavedQueryHandlers) ReorderSavedQueries(w http.ResponseWriter, r *http.Request) {
+	var req ReorderSavedQueriesRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	queries, err := h.taskService.ReorderSavedQueries(req.IDs)
 	if err != nil {
+		if err == services.ErrInvalidSavedQueryOrder {
+			SendValidationError(w, err.Error(), nil)
+			return
+		}
+		SendInternalError(w, "Failed to reorder saved queries")
+		return
+	}
+
+	SendSuccess(w, queries, "Saved queries reordered successfully")
+}
+
+// GetSavedQuery handles GET /api/v1/saved-queries/{id}
+func (h *SavedQueryHandlers) GetSavedQuery(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
 		SendBadRequest(w, "Invalid query ID", nil)
 		return
 	}
 	
-	query, err := h.taskService.GetSavedQueryByID(uint(id))
+	query, err := h.taskService.GetSavedQueryByID(id)
 	if err != nil {
 		SendNotFound(w, "Saved query not found")
 		return
@@ -49,11 +99,12 @@ func (h *SavedQueryHandlers) GetSavedQuery(w http.ResponseWriter, r *http.Reques
 	SendSuccess(w, query, "Saved query retrieved successfully")
 }
 
+// CreateSavedQuery handles POST /api/v1/saved-queries
 func (h *SavedQueryHandlers) CreateSavedQuery(w http.ResponseWriter, r *http.Request) {
 	var query models.SavedQuery
 	
-	if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
-		SendBadRequest(w, "Invalid JSON", nil)
+	if err := ParseJSON(r, &query); err != nil {
+		SendInvalidJSON(w, err)
 		return
 	}
 
@@ -63,6 +114,10 @@ func (h *SavedQueryHandlers) CreateSavedQuery(w http.ResponseWriter, r *http.Req
 	}
 
 	createdQuery, err := h.taskService.CreateSavedQuery(&query)
+	if errors.Is(err, services.ErrInvalidTagExpression) || errors.Is(err, services.ErrProjectNotFound) {
+		SendValidationError(w, "Validation failed", []string{err.Error()})
+		return
+	}
 	if err != nil {
 		SendInternalError(w, "Failed to create saved query")
 		return
@@ -71,25 +126,29 @@ func (h *SavedQueryHandlers) CreateSavedQuery(w http.ResponseWriter, r *http.Req
 	SendCreated(w, createdQuery, "Saved query created successfully")
 }
 
+// UpdateSavedQuery handles PUT /api/v1/saved-queries/{id}
 func (h *SavedQueryHandlers) UpdateSavedQuery(w http.ResponseWriter, r *http.Request) {
-	c := r.Context().Value("gin").(*gin.Context)
-	idParam := c.Param("id")
-	
-	id, err := strconv.ParseUint(idParam, 10, 32)
-	if err != nil {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
 		SendBadRequest(w, "Invalid query ID", nil)
 		return
 	}
 
-	existing, err := h.taskService.GetSavedQueryByID(uint(id))
+	existing, err := h.taskService.GetSavedQueryByID(id)
 	if err != nil {
 		SendNotFound(w, "Saved query not found")
 		return
 	}
 
-	var updates models.SavedQuery
-	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
-		SendBadRequest(w, "Invalid JSON", nil)
+	var updates struct {
+		models.SavedQuery
+		// A pointer, so an empty expression clears it and an omitted one keeps it
+		Expression *string `json:"expression"`
+		// 0 makes the query apply to every project; omitted keeps its project
+		ProjectID *uint `json:"project_id"`
+	}
+	if err := ParseJSON(r, &updates); err != nil {
+		SendInvalidJSON(w, err)
 		return
 	}
 
@@ -102,8 +161,21 @@ func (h *SavedQueryHandlers) UpdateSavedQuery(w http.ResponseWriter, r *http.Req
 	if updates.ExcludedTags != nil {
 		existing.ExcludedTags = updates.ExcludedTags
 	}
+	if updates.Expression != nil {
+		existing.Expression = *updates.Expression
+	}
+	if updates.ProjectID != nil {
+		existing.ProjectID = updates.ProjectID
+		if *updates.ProjectID == 0 {
+			existing.ProjectID = nil
+		}
+	}
 
 	updatedQuery, err := h.taskService.UpdateSavedQuery(existing)
+	if errors.Is(err, services.ErrInvalidTagExpression) || errors.Is(err, services.ErrProjectNotFound) {
+		SendValidationError(w, "Validation failed", []string{err.Error()})
+		return
+	}
 	if err != nil {
 		SendInternalError(w, "Failed to update saved query")
 		return
@@ -112,17 +184,15 @@ func (h *SavedQueryHandlers) UpdateSavedQuery(w http.ResponseWriter, r *http.Req
 	SendSuccess(w, updatedQuery, "Saved query updated successfully")
 }
 
+// DeleteSavedQuery handles DELETE /api/v1/saved-queries/{id}
 func (h *SavedQueryHandlers) DeleteSavedQuery(w http.ResponseWriter, r *http.Request) {
-	c := r.Context().Value("gin").(*gin.Context)
-	idParam := c.Param("id")
-	
-	id, err := strconv.ParseUint(idParam, 10, 32)
-	if err != nil {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
 		SendBadRequest(w, "Invalid query ID", nil)
 		return
 	}
 
-	err = h.taskService.DeleteSavedQuery(uint(id))
+	err = h.taskService.DeleteSavedQuery(id)
 	if err != nil {
 		SendInternalError(w, "Failed to delete saved query")
 		return
@@ -131,17 +201,16 @@ func (h *SavedQueryHandlers) DeleteSavedQuery(w http.ResponseWriter, r *http.Req
 	SendNoContent(w)
 }
 
+// GetTasksBySavedQuery handles GET /api/v1/saved-queries/{id}/tasks, the tasks
+// matching a saved query
 func (h *SavedQueryHandlers) GetTasksBySavedQuery(w http.ResponseWriter, r *http.Request) {
-	c := r.Context().Value("gin").(*gin.Context)
-	idParam := c.Param("id")
-	
-	id, err := strconv.ParseUint(idParam, 10, 32)
-	if err != nil {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
 		SendBadRequest(w, "Invalid query ID", nil)
 		return
 	}
 
-	query, err := h.taskService.GetSavedQueryByID(uint(id))
+	query, err := h.taskService.GetSavedQueryByID(id)
 	if err != nil {
 		SendNotFound(w, "Saved query not found")
 		return
@@ -154,4 +223,211 @@ func (h *SavedQueryHandlers) GetTasksBySavedQuery(w http.ResponseWriter, r *http
 	}
 
 	SendSuccess(w, tasks, "Tasks retrieved successfully")
-}
\ No newline at end of file
+}
+// RegenerateFeedToken handles POST /api/v1/saved-queries/{id}/feed-token
+func (h *SavedQueryHandlers) RegenerateFeedToken(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid query ID", nil)
+		return
+	}
+
+	query, err := h.taskService.RegenerateFeedToken(id)
+	if err != nil {
+		SendNotFound(w, "Saved query not found")
+		return
+	}
+
+	SendSuccess(w, query, "Feed token regenerated successfully")
+}
+
+// SavedQueryScheduleRequest is the body of PUT /api/v1/saved-queries/{id}/schedule
+type SavedQueryScheduleRequest struct {
+	Cron       string   `json:"cron"`
+	Recipients []string `json:"recipients"`
+	Subject    string   `json:"subject,omitempty"`
+	Enabled    *bool    `json:"enabled,omitempty"` // defaults to true
+}
+
+// GetSchedule handles GET /api/v1/saved-queries/{id}/schedule
+func (h *SavedQueryHandlers) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid query ID", nil)
+		return
+	}
+
+	schedule, err := h.taskService.GetSavedQuerySchedule(id)
+	if err != nil {
+		if err == services.ErrScheduleNotFound {
+			SendNotFound(w, "Saved query has no schedule")
+			return
+		}
+		SendInternalError(w, "Failed to retrieve schedule")
+		return
+	}
+
+	SendSuccess(w, schedule, "Schedule retrieved successfully")
+}
+
+// PutSchedule handles PUT /api/v1/saved-queries/{id}/schedule
+func (h *SavedQueryHandlers) PutSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid query ID", nil)
+		return
+	}
+
+	var req SavedQueryScheduleRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	schedule, err := h.taskService.SetSavedQuerySchedule(id, &models.SavedQuerySchedule{
+		Cron:       req.Cron,
+		Recipients: req.Recipients,
+		Subject:    req.Subject,
+		Enabled:    enabled,
+	}, time.Now())
+	if err != nil {
+		if err == services.ErrSavedQueryNotFound {
+			SendNotFound(w, "Saved query not found")
+			return
+		}
+		if err == services.ErrScheduleRecipientsRequired || errors.Is(err, services.ErrInvalidRecipient) ||
+			errors.Is(err, services.ErrInvalidCron) {
+			SendValidationError(w, err.Error(), nil)
+			return
+		}
+		SendInternalError(w, "Failed to save schedule")
+		return
+	}
+
+	SendSuccess(w, schedule, "Schedule saved successfully")
+}
+
+// DeleteSchedule handles DELETE /api/v1/saved-queries/{id}/schedule
+func (h *SavedQueryHandlers) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid query ID", nil)
+		return
+	}
+
+	if err := h.taskService.DeleteSavedQuerySchedule(id); err != nil {
+		if err == services.ErrScheduleNotFound {
+			SendNotFound(w, "Saved query has no schedule")
+			return
+		}
+		SendInternalError(w, "Failed to delete schedule")
+		return
+	}
+
+	SendNoContent(w)
+}
+
+// SavedQueryWebhookRequest is the body of POST /api/v1/admin/query-webhooks
+// and PUT /api/v1/admin/query-webhooks/{id}
+type SavedQueryWebhookRequest struct {
+	SavedQueryID uint   `json:"saved_query_id"`
+	URL          string `json:"url"`
+	Cron         string `json:"cron"`
+	Secret       string `json:"secret,omitempty"`
+	Enabled      *bool  `json:"enabled,omitempty"` // defaults to true
+}
+
+func (req *SavedQueryWebhookRequest) webhook(id uint) *models.SavedQueryWebhook {
+	return &models.SavedQueryWebhook{
+		ID:           id,
+		SavedQueryID: req.SavedQueryID,
+		URL:          req.URL,
+		Cron:         req.Cron,
+		Secret:       req.Secret,
+		Enabled:      req.Enabled == nil || *req.Enabled,
+	}
+}
+
+// GetWebhooks handles GET /api/v1/admin/query-webhooks
+func (h *SavedQueryHandlers) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.taskService.ListSavedQueryWebhooks()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve saved query webhooks")
+		return
+	}
+
+	SendSuccess(w, webhooks, "Saved query webhooks retrieved successfully")
+}
+
+// CreateWebhook handles POST /api/v1/admin/query-webhooks
+func (h *SavedQueryHandlers) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req SavedQueryWebhookRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	webhook, err := h.taskService.SaveSavedQueryWebhook(req.webhook(0), time.Now())
+	if err != nil {
+		h.sendWebhookError(w, err, "Failed to create saved query webhook")
+		return
+	}
+
+	SendCreated(w, webhook, "Saved query webhook created successfully")
+}
+
+// UpdateWebhook handles PUT /api/v1/admin/query-webhooks/{id}
+func (h *SavedQueryHandlers) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid webhook ID", nil)
+		return
+	}
+
+	var req SavedQueryWebhookRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	webhook, err := h.taskService.SaveSavedQueryWebhook(req.webhook(id), time.Now())
+	if err != nil {
+		h.sendWebhookError(w, err, "Failed to update saved query webhook")
+		return
+	}
+
+	SendSuccess(w, webhook, "Saved query webhook updated successfully")
+}
+
+// DeleteWebhook handles DELETE /api/v1/admin/query-webhooks/{id}
+func (h *SavedQueryHandlers) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid webhook ID", nil)
+		return
+	}
+
+	if err := h.taskService.DeleteSavedQueryWebhook(id); err != nil {
+		h.sendWebhookError(w, err, "Failed to delete saved query webhook")
+		return
+	}
+
+	SendNoContent(w)
+}
+
+// sendWebhookError maps saved query webhook service errors to API responses
+func (h *SavedQueryHandlers) sendWebhookError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case err == services.ErrSavedQueryWebhookNotFound:
+		SendNotFound(w, err.Error())
+	case err == services.ErrSavedQueryNotFound, err == services.ErrInvalidSavedQueryWebhookURL, errors.Is(err, services.ErrInvalidCron):
+		SendValidationError(w, err.Error(), nil)
+	default:
+		SendInternalError(w, fallback)
+	}
+}
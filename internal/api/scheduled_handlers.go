@@ -0,0 +1,110 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type ScheduledActionHandlers struct {
+	taskService *services.TaskService
+}
+
+func NewScheduledActionHandlers(taskService *services.TaskService) *ScheduledActionHandlers {
+	return &ScheduledActionHandlers{taskService: taskService}
+}
+
+// ScheduledActionRequest schedules a comment (kind "comment" with content) or
+// a status change (kind "status" with status) for run_at
+type ScheduledActionRequest struct {
+	Kind        string            `json:"kind"`
+	Content     string            `json:"content,omitempty"`
+	Status      models.TaskStatus `json:"status,omitempty"`
+	RunAt       time.Time         `json:"run_at"`
+	UnlessReply bool              `json:"unless_reply,omitempty"`
+}
+
+// GetScheduledActions handles GET /api/v1/tasks/{id}/scheduled
+func (h *ScheduledActionHandlers) GetScheduledActions(w http.ResponseWriter, r *http.Request) {
+	taskID, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	actions, err := h.taskService.GetScheduledActions(taskID)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve scheduled actions")
+		return
+	}
+
+	SendSuccess(w, actions, "Scheduled actions retrieved successfully")
+}
+
+// CreateScheduledAction handles POST /api/v1/tasks/{id}/scheduled
+func (h *ScheduledActionHandlers) CreateScheduledAction(w http.ResponseWriter, r *http.Request) {
+	taskID, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	var req ScheduledActionRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	action := &models.ScheduledAction{
+		Kind:        req.Kind,
+		Content:     req.Content,
+		Status:      req.Status,
+		RunAt:       req.RunAt,
+		UnlessReply: req.UnlessReply,
+	}
+	if user := middleware.GetCurrentUser(r); user != nil {
+		action.CreatedBy = user.Username
+	}
+
+	action, err = h.taskService.ScheduleAction(taskID, action)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidScheduledAction), errors.Is(err, services.ErrScheduleInPast):
+			SendValidationError(w, err.Error(), nil)
+		default:
+			SendNotFound(w, "Task not found")
+		}
+		return
+	}
+
+	SendCreated(w, action, "Action scheduled successfully")
+}
+
+// DeleteScheduledAction handles DELETE /api/v1/tasks/{id}/scheduled/{actionId}
+func (h *ScheduledActionHandlers) DeleteScheduledAction(w http.ResponseWriter, r *http.Request) {
+	taskID, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+	actionID, err := GetScheduledActionIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid scheduled action ID", nil)
+		return
+	}
+
+	if err := h.taskService.CancelScheduledAction(taskID, actionID); err != nil {
+		if errors.Is(err, services.ErrScheduledActionNotFound) {
+			SendNotFound(w, "Scheduled action not found")
+			return
+		}
+		SendInternalError(w, "Failed to cancel scheduled action")
+		return
+	}
+
+	SendNoContent(w)
+}
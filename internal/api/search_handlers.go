@@ -1,7 +1,9 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/soarinferret/jats/internal/models"
@@ -25,8 +27,11 @@ type SearchResult struct {
 	Name      string  `json:"name,omitempty"`
 	Content   string  `json:"content,omitempty"`
 	TaskID    *uint   `json:"task_id,omitempty"`
-	MatchType string  `json:"match_type"` // "name", "description", "content"
+	MatchType string  `json:"match_type"` // "name", "description", "content", or "filters" for a search without text
 	Score     float64 `json:"score"`
+
+	// Snippet is the matched field around the match, with the matches highlighted
+	Snippet *services.SearchSnippet `json:"snippet,omitempty"`
 }
 
 // SearchResponse represents the complete search response
@@ -57,6 +62,27 @@ func (h *SearchHandlers) Search(w http.ResponseWriter, r *http.Request) {
 	filters := ParseTaskFilters(r.URL.Query())
 	filteredTasks := h.applyFilters(tasks, filters)
 	
+	// q takes the same query syntax as the task list's search filter
+	search, err := h.taskService.NewTaskSearch(query, nil)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSearch) {
+			SendBadRequest(w, "Invalid search", err.Error())
+			return
+		}
+		SendInternalError(w, "Failed to search tasks")
+		return
+	}
+	terms := search.Query.Terms
+
+	matchingTasks := make(map[uint]*models.Task)
+	var matching []*models.Task
+	for _, task := range filteredTasks {
+		if search.Matches(task) {
+			matchingTasks[task.ID] = task
+			matching = append(matching, task)
+		}
+	}
+
 	// Perform search
 	response := SearchResponse{
 		Query:   query,
@@ -64,35 +90,38 @@ func (h *SearchHandlers) Search(w http.ResponseWriter, r *http.Request) {
 		Total:   0,
 	}
 	
-	queryLower := strings.ToLower(query)
-	
 	// Search in tasks (if type not specified or is "task")
 	if searchType == "" || searchType == "task" {
 		var taskResults []SearchResult
 		
-		for _, task := range filteredTasks {
-			// Search in task name
-			if strings.Contains(strings.ToLower(task.Name), queryLower) {
-				result := SearchResult{
-					ID:        task.ID,
-					Type:      "task",
-					Name:      task.Name,
-					MatchType: "name",
-					Score:     calculateScore(task.Name, query),
-				}
-				taskResults = append(taskResults, result)
-			} else if strings.Contains(strings.ToLower(task.Description), queryLower) {
+		for _, task := range matching {
+			result := SearchResult{
+				ID:   task.ID,
+				Type: "task",
+				Name: task.Name,
+			}
+			nameSnippet := services.MatchSnippet(task.Name, terms...)
+			descriptionSnippet := services.MatchSnippet(task.Description, terms...)
+			switch {
+			case len(terms) == 0:
+				// Only filters such as tag: or status:, so every task matches as a whole
+				result.MatchType = "filters"
+				result.Score = 1.0
+			case nameSnippet != nil && search.SearchesIn(services.SearchInName):
+				result.MatchType = "name"
+				result.Score = termsScore(task.Name, terms)
+				result.Snippet = nameSnippet
+			case descriptionSnippet != nil && search.SearchesIn(services.SearchInDescription):
 				// Search in description if not found in name
-				result := SearchResult{
-					ID:        task.ID,
-					Type:      "task", 
-					Name:      task.Name,
-					Content:   task.Description,
-					MatchType: "description",
-					Score:     calculateScore(task.Description, query),
-				}
-				taskResults = append(taskResults, result)
+				result.Content = task.Description
+				result.MatchType = "description"
+				result.Score = termsScore(task.Description, terms)
+				result.Snippet = descriptionSnippet
+			default:
+				// Matched in comments or time entries only
+				continue
 			}
+			taskResults = append(taskResults, result)
 		}
 		
 		response.Results["tasks"] = taskResults
@@ -101,9 +130,42 @@ func (h *SearchHandlers) Search(w http.ResponseWriter, r *http.Request) {
 	
 	// Search in comments (if type not specified or is "comment")
 	if searchType == "" || searchType == "comment" {
-		// This would need to be implemented when comment retrieval is available
-		// For now, return empty results
-		response.Results["comments"] = []SearchResult{}
+		commentResults := []SearchResult{}
+		seen := make(map[uint]bool)
+		commentTerms := terms
+		if !search.SearchesIn(services.SearchInComments) {
+			commentTerms = nil
+		}
+		for _, term := range commentTerms {
+			comments, err := h.taskService.SearchComments(term)
+			if err != nil {
+				SendInternalError(w, "Failed to search comments")
+				return
+			}
+
+			// Only comments on tasks that match the search and filters
+			for _, comment := range comments {
+				task, ok := matchingTasks[comment.TaskID]
+				if !ok || seen[comment.ID] {
+					continue
+				}
+				seen[comment.ID] = true
+				taskID := comment.TaskID
+				commentResults = append(commentResults, SearchResult{
+					ID:        comment.ID,
+					Type:      "comment",
+					Name:      task.Name,
+					Content:   comment.Content,
+					TaskID:    &taskID,
+					MatchType: "content",
+					Score:     termsScore(comment.Content, terms),
+					Snippet:   services.MatchSnippet(comment.Content, terms...),
+				})
+			}
+		}
+
+		response.Results["comments"] = commentResults
+		response.Total += len(commentResults)
 	}
 	
 	SendSuccess(w, response, "Search completed successfully")
@@ -112,66 +174,44 @@ func (h *SearchHandlers) Search(w http.ResponseWriter, r *http.Request) {
 // KanbanResponse represents a kanban board view
 type KanbanResponse struct {
 	Project    string                              `json:"project,omitempty"`
+	SavedQuery string                              `json:"saved_query,omitempty"`
 	Columns    map[string][]*models.Task          `json:"columns"`
 	Statistics map[string]int                     `json:"statistics"`
+	WIP        map[string]services.WIPColumn      `json:"wip"`
+	AgingDays  int                                 `json:"aging_days,omitempty"`
 }
 
 // GetKanban handles GET /api/v1/kanban
+// Query parameters: saved_query_id, plus the task list filters (status,
+// priority, tags, all_tags, exclude_tags, milestone, search, in). Columns and
+// statistics only count the matching tasks; WIP counts cover the whole board.
 func (h *SearchHandlers) GetKanban(w http.ResponseWriter, r *http.Request) {
-	// Get all tasks
-	tasks, err := h.taskService.GetTasks()
-	if err != nil {
-		SendInternalError(w, "Failed to retrieve tasks")
+	tasks, allTasks, query, ok := h.kanbanTasks(w, r)
+	if !ok {
 		return
 	}
-	
-	// Apply filters
+
 	filters := ParseTaskFilters(r.URL.Query())
-	filteredTasks := h.applyFilters(tasks, filters)
-	
-	// Organize into kanban columns
-	columns := map[string][]*models.Task{
-		"open":        {},
-		"in-progress": {},
-		"resolved":    {},
-		"closed":      {},
-	}
-	
-	statistics := map[string]int{
-		"total":       0,
-		"open":        0,
-		"in-progress": 0,
-		"resolved":    0,
-		"closed":      0,
-	}
-	
-	for _, task := range filteredTasks {
-		statusStr := string(task.Status)
-		columns[statusStr] = append(columns[statusStr], task)
-		statistics[statusStr]++
-		statistics["total"]++
+	if !resolveSearch(w, h.taskService, &filters) {
+		return
 	}
-	
-	response := KanbanResponse{
-		Columns:    columns,
-		Statistics: statistics,
+	response := h.buildKanbanResponse(applyTaskFilters(tasks, filters), allTasks)
+	if query != nil {
+		response.SavedQuery = query.Name
 	}
-	
 	SendSuccess(w, response, "Kanban board retrieved successfully")
 }
 
-// GetKanbanByTag handles GET /api/v1/kanban/{tag}
+// GetKanbanByTag handles GET /api/v1/kanban/{tag}, taking the same query parameters as GetKanban
 func (h *SearchHandlers) GetKanbanByTag(w http.ResponseWriter, r *http.Request) {
-	tag := r.PathValue("tag")
+	tag := GetTagFromPath(r)
 	if tag == "" {
 		SendBadRequest(w, "Tag is required", nil)
 		return
 	}
 	
-	// Get all tasks
-	tasks, err := h.taskService.GetTasks()
-	if err != nil {
-		SendInternalError(w, "Failed to retrieve tasks")
+	tasks, allTasks, query, ok := h.kanbanTasks(w, r)
+	if !ok {
 		return
 	}
 	
@@ -188,38 +228,97 @@ func (h *SearchHandlers) GetKanbanByTag(w http.ResponseWriter, r *http.Request)
 	
 	// Apply additional filters
 	filters := ParseTaskFilters(r.URL.Query())
-	filteredTasks := h.applyFiltersForKanban(taggedTasks, filters)
-	
-	// Organize into kanban columns
+	if !resolveSearch(w, h.taskService, &filters) {
+		return
+	}
+	response := h.buildKanbanResponse(applyTaskFilters(taggedTasks, filters), allTasks)
+	response.Project = tag
+	if query != nil {
+		response.SavedQuery = query.Name
+	}
+	SendSuccess(w, response, "Kanban board retrieved successfully")
+}
+
+// kanbanTasks returns the tasks a board starts from (those matching the
+// saved_query_id parameter, if given) and all tasks for the WIP counts. It
+// sends an error response and returns false on failure.
+func (h *SearchHandlers) kanbanTasks(w http.ResponseWriter, r *http.Request) ([]*models.Task, []*models.Task, *models.SavedQuery, bool) {
+	allTasks, err := h.taskService.GetTasks()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve tasks")
+		return nil, nil, nil, false
+	}
+
+	idStr := r.URL.Query().Get("saved_query_id")
+	if idStr == "" {
+		return allTasks, allTasks, nil, true
+	}
+
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		SendBadRequest(w, "Invalid saved query ID", nil)
+		return nil, nil, nil, false
+	}
+	query, err := h.taskService.GetSavedQueryByID(uint(id))
+	if err != nil {
+		SendNotFound(w, "Saved query not found")
+		return nil, nil, nil, false
+	}
+	tasks, err := h.taskService.GetTasksBySavedQuery(query)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve tasks")
+		return nil, nil, nil, false
+	}
+	return tasks, allTasks, query, true
+}
+
+// buildKanbanResponse organizes tasks into kanban columns with counts and WIP limits.
+// WIP counts are taken from allTasks because limits apply to the whole board, not a filtered view.
+func (h *SearchHandlers) buildKanbanResponse(tasks []*models.Task, allTasks []*models.Task) KanbanResponse {
 	columns := map[string][]*models.Task{
-		"open":        {},
-		"in-progress": {},
-		"resolved":    {},
-		"closed":      {},
+		"open":           {},
+		"in-progress":    {},
+		"pending-review": {},
+		"resolved":       {},
+		"closed":         {},
 	}
 	
 	statistics := map[string]int{
-		"total":       0,
-		"open":        0,
-		"in-progress": 0,
-		"resolved":    0,
-		"closed":      0,
+		"total":          0,
+		"open":           0,
+		"in-progress":    0,
+		"pending-review": 0,
+		"resolved":       0,
+		"closed":         0,
 	}
 	
-	for _, task := range filteredTasks {
+	for _, task := range tasks {
 		statusStr := string(task.Status)
 		columns[statusStr] = append(columns[statusStr], task)
 		statistics[statusStr]++
 		statistics["total"]++
 	}
 	
-	response := KanbanResponse{
-		Project:    tag,
+	wip := make(map[string]services.WIPColumn)
+	for status, column := range h.taskService.WIPColumns(allTasks) {
+		wip[string(status)] = column
+	}
+	
+	return KanbanResponse{
 		Columns:    columns,
 		Statistics: statistics,
+		WIP:        wip,
+		AgingDays:  h.taskService.AgingDays(),
 	}
-	
-	SendSuccess(w, response, "Kanban board retrieved successfully")
+}
+
+// termsScore is the best relevance score of text for any of the search terms
+func termsScore(text string, terms []string) float64 {
+	best := 0.0
+	for _, term := range terms {
+		best = max(best, calculateScore(text, term))
+	}
+	return best
 }
 
 // Helper function to calculate search relevance score
@@ -257,6 +356,10 @@ func (h *SearchHandlers) applyFilters(tasks []*models.Task, filters TaskFilters)
 	var filtered []*models.Task
 	
 	for _, task := range tasks {
+		if !filters.matchesMilestone(task) || !filters.matchesProject(task) {
+			continue
+		}
+
 		// Status filter
 		if len(filters.Status) > 0 {
 			found := false
@@ -309,28 +412,3 @@ func (h *SearchHandlers) applyFilters(tasks []*models.Task, filters TaskFilters)
 	
 	return filtered
 }
-
-// Helper method for kanban filtering (similar to applyFilters but without search)
-func (h *SearchHandlers) applyFiltersForKanban(tasks []*models.Task, filters TaskFilters) []*models.Task {
-	var filtered []*models.Task
-	
-	for _, task := range tasks {
-		// Priority filter
-		if len(filters.Priority) > 0 {
-			found := false
-			for _, priority := range filters.Priority {
-				if task.Priority == priority {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
-		
-		filtered = append(filtered, task)
-	}
-	
-	return filtered
-}
\ No newline at end of file
@@ -0,0 +1,268 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type SettingsHandlers struct {
+	settingsService *services.SettingsService
+}
+
+func NewSettingsHandlers(settingsService *services.SettingsService) *SettingsHandlers {
+	return &SettingsHandlers{
+		settingsService: settingsService,
+	}
+}
+
+// BrandingRequest represents a branding settings update
+type BrandingRequest struct {
+	InstanceName string `json:"instance_name"`
+	Tagline      string `json:"tagline"`
+	LogoURL      string `json:"logo_url"`
+	PrimaryColor string `json:"primary_color"`
+	FooterText   string `json:"footer_text"`
+}
+
+// GetBranding handles GET /api/v1/branding
+func (h *SettingsHandlers) GetBranding(w http.ResponseWriter, r *http.Request) {
+	SendSuccess(w, h.settingsService.GetBranding(), "Branding retrieved successfully")
+}
+
+// UpdateBranding handles PUT /api/v1/admin/settings/branding
+func (h *SettingsHandlers) UpdateBranding(w http.ResponseWriter, r *http.Request) {
+	var req BrandingRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	settings := &models.BrandingSettings{
+		InstanceName: req.InstanceName,
+		Tagline:      req.Tagline,
+		LogoURL:      req.LogoURL,
+		PrimaryColor: req.PrimaryColor,
+		FooterText:   req.FooterText,
+	}
+
+	updated, err := h.settingsService.UpdateBranding(settings)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidInstanceName, services.ErrInvalidPrimaryColor, services.ErrInvalidLogoURL:
+			SendValidationError(w, err.Error(), nil)
+		default:
+			SendInternalError(w, "Failed to update branding")
+		}
+		return
+	}
+
+	SendSuccess(w, updated, "Branding updated successfully")
+}
+
+// CannedResponseRequest represents a canned response creation/update request
+type CannedResponseRequest struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// GetCannedResponses handles GET /api/v1/canned-responses
+func (h *SettingsHandlers) GetCannedResponses(w http.ResponseWriter, r *http.Request) {
+	responses, err := h.settingsService.ListCannedResponses()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve canned responses")
+		return
+	}
+
+	SendSuccess(w, responses, "Canned responses retrieved successfully")
+}
+
+// CreateCannedResponse handles POST /api/v1/admin/canned-responses
+func (h *SettingsHandlers) CreateCannedResponse(w http.ResponseWriter, r *http.Request) {
+	var req CannedResponseRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	response, err := h.settingsService.SaveCannedResponse(&models.CannedResponse{
+		Name:    req.Name,
+		Content: req.Content,
+	})
+	if err != nil {
+		h.sendCannedResponseError(w, err, "Failed to create canned response")
+		return
+	}
+
+	SendCreated(w, response, "Canned response created successfully")
+}
+
+// UpdateCannedResponse handles PUT /api/v1/admin/canned-responses/{id}
+func (h *SettingsHandlers) UpdateCannedResponse(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid canned response ID", nil)
+		return
+	}
+
+	var req CannedResponseRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	response, err := h.settingsService.SaveCannedResponse(&models.CannedResponse{
+		ID:      id,
+		Name:    req.Name,
+		Content: req.Content,
+	})
+	if err != nil {
+		h.sendCannedResponseError(w, err, "Failed to update canned response")
+		return
+	}
+
+	SendSuccess(w, response, "Canned response updated successfully")
+}
+
+// DeleteCannedResponse handles DELETE /api/v1/admin/canned-responses/{id}
+func (h *SettingsHandlers) DeleteCannedResponse(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid canned response ID", nil)
+		return
+	}
+
+	if err := h.settingsService.DeleteCannedResponse(id); err != nil {
+		h.sendCannedResponseError(w, err, "Failed to delete canned response")
+		return
+	}
+
+	SendSuccess(w, nil, "Canned response deleted successfully")
+}
+
+// sendCannedResponseError maps canned response service errors to API responses
+func (h *SettingsHandlers) sendCannedResponseError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case services.ErrCannedResponseNotFound:
+		SendNotFound(w, err.Error())
+	case services.ErrDuplicateCannedResponse:
+		SendConflict(w, err.Error(), nil)
+	case services.ErrInvalidCannedResponseName, services.ErrEmptyCannedResponse:
+		SendValidationError(w, err.Error(), nil)
+	default:
+		SendInternalError(w, fallback)
+	}
+}
+
+// AutomationRuleRequest represents an automation rule creation/update request
+type AutomationRuleRequest struct {
+	Name        string              `json:"name"`
+	Enabled     *bool               `json:"enabled,omitempty"` // Defaults to true
+	Trigger     string              `json:"trigger"`
+	Tag         string              `json:"tag,omitempty"`
+	Priority    models.TaskPriority `json:"priority,omitempty"`
+	Status      models.TaskStatus   `json:"status,omitempty"`
+	Sender      string              `json:"sender,omitempty"`
+	SetAssignee string              `json:"set_assignee,omitempty"`
+	AddTag      string              `json:"add_tag,omitempty"`
+	Notify      bool                `json:"notify"`
+	WebhookURL  string              `json:"webhook_url,omitempty"`
+}
+
+func (req *AutomationRuleRequest) rule(id uint) *models.AutomationRule {
+	return &models.AutomationRule{
+		ID:          id,
+		Name:        req.Name,
+		Enabled:     req.Enabled == nil || *req.Enabled,
+		Trigger:     req.Trigger,
+		Tag:         req.Tag,
+		Priority:    req.Priority,
+		Status:      req.Status,
+		Sender:      req.Sender,
+		SetAssignee: req.SetAssignee,
+		AddTag:      req.AddTag,
+		Notify:      req.Notify,
+		WebhookURL:  req.WebhookURL,
+	}
+}
+
+// GetAutomationRules handles GET /api/v1/admin/rules
+func (h *SettingsHandlers) GetAutomationRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.settingsService.ListAutomationRules()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve automation rules")
+		return
+	}
+
+	SendSuccess(w, rules, "Automation rules retrieved successfully")
+}
+
+// CreateAutomationRule handles POST /api/v1/admin/rules
+func (h *SettingsHandlers) CreateAutomationRule(w http.ResponseWriter, r *http.Request) {
+	var req AutomationRuleRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	rule, err := h.settingsService.SaveAutomationRule(req.rule(0))
+	if err != nil {
+		h.sendAutomationRuleError(w, err, "Failed to create automation rule")
+		return
+	}
+
+	SendCreated(w, rule, "Automation rule created successfully")
+}
+
+// UpdateAutomationRule handles PUT /api/v1/admin/rules/{id}
+func (h *SettingsHandlers) UpdateAutomationRule(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid automation rule ID", nil)
+		return
+	}
+
+	var req AutomationRuleRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	rule, err := h.settingsService.SaveAutomationRule(req.rule(id))
+	if err != nil {
+		h.sendAutomationRuleError(w, err, "Failed to update automation rule")
+		return
+	}
+
+	SendSuccess(w, rule, "Automation rule updated successfully")
+}
+
+// DeleteAutomationRule handles DELETE /api/v1/admin/rules/{id}
+func (h *SettingsHandlers) DeleteAutomationRule(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil || id == 0 {
+		SendBadRequest(w, "Invalid automation rule ID", nil)
+		return
+	}
+
+	if err := h.settingsService.DeleteAutomationRule(id); err != nil {
+		h.sendAutomationRuleError(w, err, "Failed to delete automation rule")
+		return
+	}
+
+	SendSuccess(w, nil, "Automation rule deleted successfully")
+}
+
+// sendAutomationRuleError maps automation rule service errors to API responses
+func (h *SettingsHandlers) sendAutomationRuleError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case services.ErrAutomationRuleNotFound:
+		SendNotFound(w, err.Error())
+	case services.ErrInvalidRuleName, services.ErrInvalidRuleTrigger, services.ErrInvalidRuleCondition,
+		services.ErrRuleSenderTrigger, services.ErrRuleNoActions, services.ErrInvalidRuleWebhookURL:
+		SendValidationError(w, err.Error(), nil)
+	default:
+		SendInternalError(w, fallback)
+	}
+}
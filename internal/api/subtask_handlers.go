@@ -45,7 +45,7 @@ func (h *SubtaskHandlers) CreateSubtask(w http.ResponseWriter, r *http.Request)
 	
 	var req SubtaskRequest
 	if err := ParseJSON(r, &req); err != nil {
-		SendBadRequest(w, "Invalid JSON", err.Error())
+		SendInvalidJSON(w, err)
 		return
 	}
 	
@@ -63,8 +63,9 @@ func (h *SubtaskHandlers) CreateSubtask(w http.ResponseWriter, r *http.Request)
 
 	// Create subtask
 	subtask := &models.Subtask{
-		Name:      req.Name,
-		Completed: req.Completed,
+		Name:            req.Name,
+		Completed:       req.Completed,
+		EstimateMinutes: req.EstimateMinutes,
 	}
 
 	err = h.taskService.AddSubtask(taskID, subtask)
@@ -92,7 +93,7 @@ func (h *SubtaskHandlers) UpdateSubtask(w http.ResponseWriter, r *http.Request)
 
 	var req SubtaskRequest
 	if err := ParseJSON(r, &req); err != nil {
-		SendBadRequest(w, "Invalid JSON", err.Error())
+		SendInvalidJSON(w, err)
 		return
 	}
 
@@ -110,6 +111,7 @@ func (h *SubtaskHandlers) UpdateSubtask(w http.ResponseWriter, r *http.Request)
 
 	// Update fields
 	subtask.Name = req.Name
+	subtask.EstimateMinutes = req.EstimateMinutes
 
 	err = h.taskService.UpdateSubtask(taskID, subtask)
 	if err != nil {
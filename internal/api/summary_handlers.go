@@ -1,8 +1,11 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/soarinferret/jats/internal/models"
@@ -16,8 +19,39 @@ type SummaryHandlers struct {
 type TaskSummaryResponse struct {
 	OpenTasks           int `json:"open_tasks"`
 	InProgressTasks     int `json:"in_progress_tasks"`
-	RecentlyAddedTasks  int `json:"recently_added_tasks"`  // Last 7 days
-	RecentlyResolvedTasks int `json:"recently_resolved_tasks"` // Last 7 days
+	OverdueTasks        int `json:"overdue_tasks"` // Open or in progress past their due day
+	RecentlyAddedTasks  int `json:"recently_added_tasks"`  // In the window
+	RecentlyResolvedTasks int `json:"recently_resolved_tasks"` // In the window
+	RecentlyLoggedMinutes int `json:"recently_logged_minutes"` // In the window
+
+	// The window counted: a preset name or "custom", and its bounds
+	Window string    `json:"window"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+
+	// The same counts for the equally long period before the window, and how
+	// the window compares
+	Previous SummaryPeriod `json:"previous"`
+	Trends   SummaryPeriod `json:"trends"` // window minus the previous period
+}
+
+// Summary window presets
+const (
+	SummaryWindowToday   = "today"
+	SummaryWindow7Days   = "7d"
+	SummaryWindow30Days  = "30d"
+	SummaryWindowQuarter = "quarter"
+	SummaryWindowCustom  = "custom"
+)
+
+// SummaryWindows lists the window presets GetTaskSummary accepts
+var SummaryWindows = []string{SummaryWindowToday, SummaryWindow7Days, SummaryWindow30Days, SummaryWindowQuarter}
+
+// SummaryPeriod holds the per-period counts of a task summary
+type SummaryPeriod struct {
+	AddedTasks    int `json:"added_tasks"`
+	ResolvedTasks int `json:"resolved_tasks"`
+	LoggedMinutes int `json:"logged_minutes"`
 }
 
 func NewSummaryHandlers(taskService *services.TaskService) *SummaryHandlers {
@@ -27,9 +61,18 @@ func NewSummaryHandlers(taskService *services.TaskService) *SummaryHandlers {
 }
 
 // GetTaskSummary handles GET /api/v1/summary/tasks
+// Query parameters: saved_query_id, and either window (today, 7d, 30d or
+// quarter; default 7d) or from/to dates (see utils.ParseDate, to is inclusive
+// and defaults to now).
 func (h *SummaryHandlers) GetTaskSummary(w http.ResponseWriter, r *http.Request) {
 	// Get query parameters for filtering
 	savedQueryID := r.URL.Query().Get("saved_query_id")
+
+	window, from, to, err := parseSummaryWindow(r.URL.Query(), time.Now())
+	if err != nil {
+		SendBadRequest(w, "Invalid summary window", err.Error())
+		return
+	}
 	
 	// Get all tasks (we'll need this for filtering)
 	allTasks, err := h.taskService.GetTasks()
@@ -61,7 +104,8 @@ func (h *SummaryHandlers) GetTaskSummary(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Calculate summary statistics
-	summary := h.calculateSummary(filteredTasks)
+	summary := h.calculateSummary(filteredTasks, from, to)
+	summary.Window = window
 
 	SendSuccess(w, summary, "Task summary retrieved successfully")
 }
@@ -81,43 +125,71 @@ func (h *SummaryHandlers) applyQueryFilters(tasks []*models.Task, query *models.
 
 // taskMatchesSavedQuery checks if a task matches a saved query's criteria
 func (h *SummaryHandlers) taskMatchesSavedQuery(task *models.Task, query *models.SavedQuery) bool {
-	// Check included tags
-	if len(query.IncludedTags) > 0 {
-		hasIncludedTag := false
-		for _, includedTag := range query.IncludedTags {
-			for _, taskTag := range task.Tags {
-				if taskTag == includedTag {
-					hasIncludedTag = true
-					break
-				}
-			}
-			if hasIncludedTag {
-				break
-			}
+	return services.MatchesSavedQuery(task, query)
+}
+
+// parseSummaryWindow returns the window preset name and bounds requested by
+// the window or from/to parameters
+func parseSummaryWindow(values url.Values, now time.Time) (string, time.Time, time.Time, error) {
+	fromStr, toStr := strings.TrimSpace(values.Get("from")), strings.TrimSpace(values.Get("to"))
+	if fromStr != "" || toStr != "" {
+		if values.Get("window") != "" {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("use either window or from/to")
 		}
-		if !hasIncludedTag {
-			return false
+		if fromStr == "" {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("from is required with to")
 		}
-	}
-
-	// Check excluded tags
-	for _, excludedTag := range query.ExcludedTags {
-		for _, taskTag := range task.Tags {
-			if taskTag == excludedTag {
-				return false
-			}
+		from, to, err := services.ParseActivityRange(fromStr, toStr)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, err
+		}
+		if to.IsZero() || to.After(now) {
+			to = now
+		}
+		if !from.Before(to) {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
 		}
+		return SummaryWindowCustom, from, to, nil
 	}
 
-	return true
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch window := values.Get("window"); window {
+	case SummaryWindowToday:
+		return window, startOfToday, now, nil
+	case "", SummaryWindow7Days:
+		return SummaryWindow7Days, now.AddDate(0, 0, -7), now, nil
+	case SummaryWindow30Days:
+		return window, now.AddDate(0, 0, -30), now, nil
+	case SummaryWindowQuarter:
+		quarterStart := time.Date(now.Year(), (now.Month()-1)/3*3+1, 1, 0, 0, 0, 0, now.Location())
+		return window, quarterStart, now, nil
+	default:
+		return "", time.Time{}, time.Time{}, fmt.Errorf("unknown window %q (expected %s)", window, strings.Join(SummaryWindows, ", "))
+	}
 }
 
-// calculateSummary calculates summary statistics from filtered tasks
-func (h *SummaryHandlers) calculateSummary(tasks []*models.Task) *TaskSummaryResponse {
-	var openTasks, inProgressTasks, recentlyAdded, recentlyResolved int
+// calculateSummary calculates summary statistics from filtered tasks, comparing
+// the window from-to with the equally long period before it
+func (h *SummaryHandlers) calculateSummary(tasks []*models.Task, from, to time.Time) *TaskSummaryResponse {
+	var openTasks, inProgressTasks, overdueTasks int
+	now := time.Now()
+	var current, previous SummaryPeriod
 	
 	// Calculate time boundaries
-	sevenDaysAgo := time.Now().AddDate(0, 0, -7)
+	previousFrom := from.Add(-to.Sub(from))
+
+	// period returns the counts a timestamp falls into, or nil if it is outside both
+	period := func(t time.Time) *SummaryPeriod {
+		switch {
+		case t.After(to):
+			return nil
+		case t.After(from):
+			return &current
+		case t.After(previousFrom):
+			return &previous
+		}
+		return nil
+	}
 
 	for _, task := range tasks {
 		// Count open tasks
@@ -130,21 +202,43 @@ func (h *SummaryHandlers) calculateSummary(tasks []*models.Task) *TaskSummaryRes
 			inProgressTasks++
 		}
 
-		// Count recently added tasks (created in last 7 days)
-		if task.CreatedAt.After(sevenDaysAgo) {
-			recentlyAdded++
+		if task.IsOverdue(now) {
+			overdueTasks++
 		}
 
-		// Count recently resolved tasks (resolved in last 7 days)
-		if task.Status == models.TaskStatusResolved && task.ResolvedAt != nil && task.ResolvedAt.After(sevenDaysAgo) {
-			recentlyResolved++
+		// Count added tasks by the period they were created in
+		if p := period(task.CreatedAt); p != nil {
+			p.AddedTasks++
+		}
+
+		// Count resolved tasks by the period they were resolved in
+		if task.Status == models.TaskStatusResolved && task.ResolvedAt != nil {
+			if p := period(*task.ResolvedAt); p != nil {
+				p.ResolvedTasks++
+			}
+		}
+
+		for _, entry := range task.TimeEntries {
+			if p := period(entry.CreatedAt); p != nil {
+				p.LoggedMinutes += entry.Duration
+			}
 		}
 	}
 
 	return &TaskSummaryResponse{
 		OpenTasks:             openTasks,
 		InProgressTasks:       inProgressTasks,
-		RecentlyAddedTasks:    recentlyAdded,
-		RecentlyResolvedTasks: recentlyResolved,
+		OverdueTasks:          overdueTasks,
+		RecentlyAddedTasks:    current.AddedTasks,
+		RecentlyResolvedTasks: current.ResolvedTasks,
+		RecentlyLoggedMinutes: current.LoggedMinutes,
+		From:                  from,
+		To:                    to,
+		Previous:              previous,
+		Trends: SummaryPeriod{
+			AddedTasks:    current.AddedTasks - previous.AddedTasks,
+			ResolvedTasks: current.ResolvedTasks - previous.ResolvedTasks,
+			LoggedMinutes: current.LoggedMinutes - previous.LoggedMinutes,
+		},
 	}
-}
\ No newline at end of file
+}
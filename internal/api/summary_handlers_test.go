@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+func TestCalculateSummaryTrends(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	resolvedAt := daysAgo(2)
+	prevResolvedAt := daysAgo(10)
+
+	tasks := []*models.Task{
+		{Status: models.TaskStatusOpen, CreatedAt: daysAgo(1), TimeEntries: []models.TimeEntry{
+			{Duration: 30, CreatedAt: daysAgo(1)},
+			{Duration: 90, CreatedAt: daysAgo(9)},
+		}},
+		{Status: models.TaskStatusResolved, CreatedAt: daysAgo(3), ResolvedAt: &resolvedAt},
+		{Status: models.TaskStatusResolved, CreatedAt: daysAgo(12), ResolvedAt: &prevResolvedAt},
+		{Status: models.TaskStatusInProgress, CreatedAt: daysAgo(20), TimeEntries: []models.TimeEntry{
+			{Duration: 45, CreatedAt: daysAgo(30)},
+		}},
+	}
+
+	summary := (&SummaryHandlers{}).calculateSummary(tasks, daysAgo(7), now)
+
+	if summary.OpenTasks != 1 || summary.InProgressTasks != 1 {
+		t.Errorf("Expected 1 open and 1 in progress task, got %d and %d", summary.OpenTasks, summary.InProgressTasks)
+	}
+	if summary.RecentlyAddedTasks != 2 || summary.RecentlyResolvedTasks != 1 || summary.RecentlyLoggedMinutes != 30 {
+		t.Errorf("Unexpected current period: added %d, resolved %d, logged %d",
+			summary.RecentlyAddedTasks, summary.RecentlyResolvedTasks, summary.RecentlyLoggedMinutes)
+	}
+
+	wantPrevious := SummaryPeriod{AddedTasks: 1, ResolvedTasks: 1, LoggedMinutes: 90}
+	if summary.Previous != wantPrevious {
+		t.Errorf("Expected previous period %+v, got %+v", wantPrevious, summary.Previous)
+	}
+	wantTrends := SummaryPeriod{AddedTasks: 1, ResolvedTasks: 0, LoggedMinutes: -60}
+	if summary.Trends != wantTrends {
+		t.Errorf("Expected trends %+v, got %+v", wantTrends, summary.Trends)
+	}
+}
+
+func TestParseSummaryWindow(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		query      string
+		wantWindow string
+		wantFrom   time.Time
+		wantTo     time.Time
+	}{
+		{"", "7d", now.AddDate(0, 0, -7), now},
+		{"window=today", "today", time.Date(2024, 5, 15, 0, 0, 0, 0, time.Local), now},
+		{"window=30d", "30d", now.AddDate(0, 0, -30), now},
+		{"window=quarter", "quarter", time.Date(2024, 4, 1, 0, 0, 0, 0, time.Local), now},
+		{"from=2024-05-01&to=2024-05-07", "custom", time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local), time.Date(2024, 5, 8, 0, 0, 0, 0, time.Local)},
+		{"from=2024-05-10", "custom", time.Date(2024, 5, 10, 0, 0, 0, 0, time.Local), now},
+	}
+
+	for _, tt := range tests {
+		values, _ := url.ParseQuery(tt.query)
+		window, from, to, err := parseSummaryWindow(values, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.query, err)
+			continue
+		}
+		if window != tt.wantWindow || !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+			t.Errorf("%q: got %s %v - %v, want %s %v - %v", tt.query, window, from, to, tt.wantWindow, tt.wantFrom, tt.wantTo)
+		}
+	}
+
+	for _, query := range []string{"window=year", "to=2024-05-07", "window=7d&from=2024-05-01", "from=2024-05-10&to=2024-05-01"} {
+		values, _ := url.ParseQuery(query)
+		if _, _, _, err := parseSummaryWindow(values, now); err == nil {
+			t.Errorf("%q: expected an error", query)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type SyncHandlers struct {
+	syncService *services.SyncService
+}
+
+func NewSyncHandlers(syncService *services.SyncService) *SyncHandlers {
+	return &SyncHandlers{
+		syncService: syncService,
+	}
+}
+
+// GetTargets handles GET /api/v1/admin/sync
+func (h *SyncHandlers) GetTargets(w http.ResponseWriter, r *http.Request) {
+	targets := []services.SyncTargetStatus{}
+	if h.syncService != nil {
+		targets = h.syncService.Targets()
+	}
+	SendSuccess(w, targets, "Sync targets retrieved successfully")
+}
+
+// RunTarget handles POST /api/v1/admin/sync/{target}/run
+func (h *SyncHandlers) RunTarget(w http.ResponseWriter, r *http.Request) {
+	if h.syncService == nil {
+		SendNotFound(w, services.ErrSyncTargetNotFound.Error())
+		return
+	}
+
+	// Failures are part of the run result, so the caller always gets it back
+	run, err := h.syncService.SyncTarget(getSyncTargetFromPath(r), time.Now())
+	if err != nil {
+		if errors.Is(err, services.ErrSyncTargetNotFound) {
+			SendNotFound(w, err.Error())
+			return
+		}
+		SendInternalError(w, "Failed to sync")
+		return
+	}
+
+	SendSuccess(w, run, "Sync completed")
+}
+
+func getSyncTargetFromPath(r *http.Request) string {
+	parts := strings.Split(r.URL.Path, "/")
+	for i, part := range parts {
+		if part == "sync" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
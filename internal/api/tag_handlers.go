@@ -2,7 +2,9 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/services"
@@ -65,6 +67,37 @@ func (h *TagHandlers) GetTags(w http.ResponseWriter, r *http.Request) {
 	SendSuccess(w, response, "Tags retrieved successfully")
 }
 
+// TagStatsResponse is the workload per tag over a period
+type TagStatsResponse struct {
+	Since time.Time           `json:"since"`
+	Until *time.Time          `json:"until,omitempty"`
+	Tags  []services.TagStats `json:"tags"`
+}
+
+// GetTagStats handles GET /api/v1/tags/stats
+// Query parameters: since, until (dates such as "yesterday" or "2025-12-01",
+// until inclusive) bound the resolved and logged counts; the default is the
+// last 7 days.
+func (h *TagHandlers) GetTagStats(w http.ResponseWriter, r *http.Request) {
+	since, until, err := services.ParseActivityRange(r.URL.Query().Get("since"), r.URL.Query().Get("until"))
+	if err != nil {
+		SendBadRequest(w, "Invalid date format", err.Error())
+		return
+	}
+
+	stats, err := h.taskService.GetTagStats(since, until)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve tag statistics")
+		return
+	}
+
+	response := TagStatsResponse{Since: since, Tags: stats}
+	if !until.IsZero() {
+		response.Until = &until
+	}
+	SendSuccess(w, response, "Tag statistics retrieved successfully")
+}
+
 // GetTasksByTag handles GET /api/v1/tags/{tag}/tasks
 func (h *TagHandlers) GetTasksByTag(w http.ResponseWriter, r *http.Request) {
 	tag := r.PathValue("tag")
@@ -105,7 +138,7 @@ func (h *TagHandlers) AddTaskTags(w http.ResponseWriter, r *http.Request) {
 		Tags []string `json:"tags"`
 	}
 	if err := ParseJSON(r, &req); err != nil {
-		SendBadRequest(w, "Invalid JSON", err.Error())
+		SendInvalidJSON(w, err)
 		return
 	}
 	
@@ -190,4 +223,58 @@ func (h *TagHandlers) RemoveTaskTag(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	SendSuccess(w, task, "Tag removed successfully")
-}
\ No newline at end of file
+}
+// ApplyTag handles POST /api/v1/tags/{tag}/apply
+func (h *TagHandlers) ApplyTag(w http.ResponseWriter, r *http.Request) {
+	h.bulkUpdateTag(w, r, services.BulkTagApply)
+}
+
+// RemoveTag handles POST /api/v1/tags/{tag}/remove
+func (h *TagHandlers) RemoveTag(w http.ResponseWriter, r *http.Request) {
+	h.bulkUpdateTag(w, r, services.BulkTagRemove)
+}
+
+// bulkUpdateTag adds or removes a tag on every task matching the task list
+// filters (status, priority, tags, milestone, search, in). At least one filter is
+// required unless all=true; dry_run=true only reports the affected tasks.
+func (h *TagHandlers) bulkUpdateTag(w http.ResponseWriter, r *http.Request, action services.BulkTagAction) {
+	tag := strings.TrimSpace(GetTagFromPath(r))
+	if tag == "" {
+		SendBadRequest(w, "Tag is required", nil)
+		return
+	}
+
+	values := r.URL.Query()
+	filters := ParseTaskFilters(values)
+	all, _ := strconv.ParseBool(values.Get("all"))
+	dryRun, _ := strconv.ParseBool(values.Get("dry_run"))
+
+	hasFilter := len(filters.Status) > 0 || len(filters.Priority) > 0 || len(filters.Tags) > 0 ||
+		filters.Search != "" || filters.MilestoneID != 0 || filters.NoMilestone || filters.ProjectID != 0
+	if !hasFilter && !all {
+		SendBadRequest(w, "A filter is required", "pass status, priority, tags, milestone, project or search, or all=true to change every task")
+		return
+	}
+
+	if !resolveSearch(w, h.taskService, &filters) {
+		return
+	}
+
+	tasks, err := h.taskService.GetTasks()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve tasks")
+		return
+	}
+
+	result, err := h.taskService.BulkUpdateTag(tag, action, applyTaskFilters(tasks, filters), dryRun)
+	if err != nil {
+		SendInternalError(w, "Failed to update tags")
+		return
+	}
+
+	message := "Tags updated successfully"
+	if dryRun {
+		message = "Dry run: no tasks were changed"
+	}
+	SendSuccess(w, result, message)
+}
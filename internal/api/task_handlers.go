@@ -1,11 +1,18 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/outline"
+	"github.com/soarinferret/jats/internal/quickadd"
+	"github.com/soarinferret/jats/internal/repository"
 	"github.com/soarinferret/jats/internal/services"
 	"github.com/soarinferret/jats/internal/utils"
 )
@@ -23,6 +30,9 @@ func NewTaskHandlers(taskService *services.TaskService) *TaskHandlers {
 // GetTasks handles GET /api/v1/tasks
 func (h *TaskHandlers) GetTasks(w http.ResponseWriter, r *http.Request) {
 	filters := ParseTaskFilters(r.URL.Query())
+	if !resolveSearch(w, h.taskService, &filters) {
+		return
+	}
 	
 	// For now, implement basic filtering - can be enhanced later
 	tasks, err := h.taskService.GetTasks()
@@ -33,6 +43,18 @@ func (h *TaskHandlers) GetTasks(w http.ResponseWriter, r *http.Request) {
 
 	// Apply filters (basic implementation)
 	filteredTasks := h.applyFilters(tasks, filters)
+
+	if filters.Sort == SortRecentlyTouched {
+		user := middleware.GetCurrentUser(r)
+		if user == nil {
+			SendBadRequest(w, "Sorting by recently touched needs a signed-in user", nil)
+			return
+		}
+		if err := h.taskService.SortByLastTouched(filteredTasks, user.Username); err != nil {
+			SendInternalError(w, "Failed to sort tasks")
+			return
+		}
+	}
 	
 	// Apply pagination
 	total := len(filteredTasks)
@@ -81,7 +103,7 @@ func (h *TaskHandlers) GetTask(w http.ResponseWriter, r *http.Request) {
 func (h *TaskHandlers) CreateTask(w http.ResponseWriter, r *http.Request) {
 	var req TaskRequest
 	if err := ParseJSON(r, &req); err != nil {
-		SendBadRequest(w, "Invalid JSON", err.Error())
+		SendInvalidJSON(w, err)
 		return
 	}
 	
@@ -103,6 +125,22 @@ func (h *TaskHandlers) CreateTask(w http.ResponseWriter, r *http.Request) {
 		createdAt = time.Now()
 	}
 
+	if req.MilestoneID != nil && !h.milestoneExists(w, *req.MilestoneID) {
+		return
+	}
+	if req.ProjectID != nil && !h.projectExists(w, *req.ProjectID) {
+		return
+	}
+
+	var dueDate *time.Time
+	if req.DueDate != nil {
+		var err error
+		if dueDate, err = services.ParseDueDate(strings.TrimSpace(*req.DueDate)); err != nil {
+			SendBadRequest(w, "Invalid due date", err.Error())
+			return
+		}
+	}
+
 	// Create task using service
 	task, err := h.taskService.CreateTaskWithDate(req.Name, createdAt)
 	if err != nil {
@@ -111,7 +149,7 @@ func (h *TaskHandlers) CreateTask(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	// Update additional fields if provided
-	if req.Description != "" || req.Status != "" || req.Priority != "" || len(req.Tags) > 0 {
+	if req.Description != "" || req.Status != "" || req.Priority != "" || len(req.Tags) > 0 || req.MilestoneID != nil || req.ProjectID != nil || req.Assignee != nil || req.Context != nil || req.Size != nil || dueDate != nil {
 		if req.Description != "" {
 			task.Description = req.Description
 		}
@@ -122,12 +160,34 @@ func (h *TaskHandlers) CreateTask(w http.ResponseWriter, r *http.Request) {
 			task.Priority = req.Priority
 		}
 		if len(req.Tags) > 0 {
-			task.Tags = req.Tags
+			// Keep tags added by automation rules on creation
+			task.Tags = append(task.Tags, req.Tags...)
+		}
+		if req.Assignee != nil {
+			task.Assignee = strings.TrimSpace(*req.Assignee)
+		}
+		if req.Context != nil {
+			task.Context = *req.Context
+		}
+		if req.Size != nil {
+			task.Size = models.TaskSize(*req.Size)
 		}
+		task.MilestoneID = req.MilestoneID
+		if req.ProjectID != nil {
+			task.ProjectID = req.ProjectID
+		}
+		task.DueDate = dueDate
 		
 		task.UpdatedAt = time.Now()
+		if user := middleware.GetCurrentUser(r); user != nil {
+			task.ChangedBy = user.Username
+		}
 		
 		if err := h.taskService.UpdateTask(task); err != nil {
+			if err == services.ErrWIPLimitReached {
+				h.sendWIPLimitReached(w, task)
+				return
+			}
 			SendInternalError(w, "Failed to update task details")
 			return
 		}
@@ -136,6 +196,154 @@ func (h *TaskHandlers) CreateTask(w http.ResponseWriter, r *http.Request) {
 	SendCreated(w, task, "Task created successfully")
 }
 
+// QuickAddTask handles POST /api/v1/tasks/quick
+func (h *TaskHandlers) QuickAddTask(w http.ResponseWriter, r *http.Request) {
+	var req QuickAddRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	entry, err := quickadd.Parse(req.Input)
+	if err != nil {
+		SendValidationError(w, "Validation failed", []string{err.Error()})
+		return
+	}
+
+	task, err := h.taskService.CreateQuickTask(entry)
+	if err != nil {
+		if err == services.ErrWIPLimitReached {
+			SendConflict(w, "Work-in-progress limit reached", nil)
+			return
+		}
+		SendInternalError(w, "Failed to create task")
+		return
+	}
+
+	SendCreated(w, task, "Task created successfully")
+}
+
+// BulkTaskRequest is a batch of parsed quick-add entries, created in order
+type BulkTaskRequest struct {
+	Tasks []*quickadd.Task `json:"tasks"`
+}
+
+// CreateTasks handles POST /api/v1/tasks/bulk. If an entry fails after earlier
+// ones were created, the error details list the IDs of the created tasks.
+func (h *TaskHandlers) CreateTasks(w http.ResponseWriter, r *http.Request) {
+	var req BulkTaskRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	if len(req.Tasks) == 0 {
+		SendValidationError(w, "Validation failed", []string{"tasks is required"})
+		return
+	}
+	for i, entry := range req.Tasks {
+		if entry == nil {
+			SendValidationError(w, "Validation failed", []string{fmt.Sprintf("entry %d: task is required", i+1)})
+			return
+		}
+	}
+
+	tasks, err := h.taskService.CreateQuickTasks(req.Tasks)
+	if err != nil {
+		var details interface{}
+		if len(tasks) > 0 {
+			created := make([]uint, len(tasks))
+			for i, task := range tasks {
+				created[i] = task.ID
+			}
+			details = map[string]interface{}{"created": created}
+		}
+
+		switch {
+		case errors.Is(err, services.ErrWIPLimitReached):
+			SendConflict(w, err.Error(), details)
+		case errors.Is(err, services.ErrEmptyTaskName), errors.Is(err, services.ErrInvalidPriority),
+			errors.Is(err, services.ErrInvalidDuration), errors.Is(err, services.ErrInvalidDate),
+			errors.Is(err, services.ErrTooManyBulkEntries):
+			SendBadRequest(w, err.Error(), nil)
+		default:
+			SendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to create tasks", details)
+		}
+		return
+	}
+
+	SendCreated(w, tasks, fmt.Sprintf("%d tasks created successfully", len(tasks)))
+}
+
+// ImportTasksRequest is a todo file to import, in org-mode or Markdown
+type ImportTasksRequest struct {
+	Format   string `json:"format"`   // org or markdown
+	Content  string `json:"content"`
+	Headings string `json:"headings"` // tags (the default) or projects
+}
+
+// ImportTasks handles POST /api/v1/tasks/import
+// Creates the tasks of an org-mode file or a Markdown checklist with their
+// subtasks. Headings become tags, or with "headings": "projects" the
+// outermost heading names the project, created if needed.
+func (h *TaskHandlers) ImportTasks(w http.ResponseWriter, r *http.Request) {
+	var req ImportTasksRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	format, err := outline.ParseFormat(req.Format)
+	if err != nil {
+		SendValidationError(w, "Validation failed", []string{err.Error()})
+		return
+	}
+	var headingProjects bool
+	switch req.Headings {
+	case "", "tags":
+	case "projects":
+		headingProjects = true
+	default:
+		SendValidationError(w, "Validation failed", []string{"headings must be tags or projects"})
+		return
+	}
+
+	items, err := outline.Parse(format, strings.NewReader(req.Content))
+	if err != nil {
+		SendBadRequest(w, err.Error(), nil)
+		return
+	}
+	if len(items) == 0 {
+		SendValidationError(w, "Validation failed", []string{"no tasks found in content"})
+		return
+	}
+
+	tasks, err := h.taskService.ImportOutline(items, headingProjects)
+	if err != nil {
+		var details interface{}
+		if len(tasks) > 0 {
+			created := make([]uint, len(tasks))
+			for i, task := range tasks {
+				created[i] = task.ID
+			}
+			details = map[string]interface{}{"created": created}
+		}
+
+		switch {
+		case errors.Is(err, services.ErrWIPLimitReached):
+			SendConflict(w, err.Error(), details)
+		case errors.Is(err, services.ErrEmptyTaskName), errors.Is(err, services.ErrInvalidDate),
+			errors.Is(err, services.ErrTooManyBulkEntries):
+			SendBadRequest(w, err.Error(), nil)
+		default:
+			SendError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to import tasks", details)
+		}
+		return
+	}
+
+	SendCreated(w, tasks, fmt.Sprintf("%d tasks imported successfully", len(tasks)))
+}
+
 // UpdateTask handles PUT /api/v1/tasks/{id}
 func (h *TaskHandlers) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	id, err := GetIDFromPath(r)
@@ -146,7 +354,7 @@ func (h *TaskHandlers) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	
 	var req TaskRequest
 	if err := ParseJSON(r, &req); err != nil {
-		SendBadRequest(w, "Invalid JSON", err.Error())
+		SendInvalidJSON(w, err)
 		return
 	}
 	
@@ -174,10 +382,48 @@ func (h *TaskHandlers) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	if len(req.Tags) > 0 {
 		task.Tags = req.Tags
 	}
+	if req.MilestoneID != nil {
+		if !h.milestoneExists(w, *req.MilestoneID) {
+			return
+		}
+		task.MilestoneID = req.MilestoneID
+	}
+	if req.ProjectID != nil {
+		if !h.projectExists(w, *req.ProjectID) {
+			return
+		}
+		task.ProjectID = req.ProjectID
+	}
+	if req.Assignee != nil {
+		task.Assignee = strings.TrimSpace(*req.Assignee)
+	}
+	if req.Context != nil {
+		task.Context = *req.Context
+	}
+	if req.Size != nil {
+		task.Size = models.TaskSize(*req.Size)
+	}
+	if req.DueDate != nil {
+		if task.DueDate, err = services.ParseDueDate(strings.TrimSpace(*req.DueDate)); err != nil {
+			SendBadRequest(w, "Invalid due date", err.Error())
+			return
+		}
+	}
 	
 	task.UpdatedAt = time.Now()
+	if user := middleware.GetCurrentUser(r); user != nil {
+		task.ChangedBy = user.Username
+	}
 	
 	if err := h.taskService.UpdateTask(task); err != nil {
+		if err == services.ErrWIPLimitReached {
+			h.sendWIPLimitReached(w, task)
+			return
+		}
+		if errors.Is(err, services.ErrOpenBlockers) {
+			SendConflict(w, "Task is blocked by open tasks", err.Error())
+			return
+		}
 		SendInternalError(w, "Failed to update task")
 		return
 	}
@@ -196,7 +442,7 @@ func (h *TaskHandlers) PartialUpdateTask(w http.ResponseWriter, r *http.Request)
 	// Parse partial update as map
 	var updates map[string]interface{}
 	if err := ParseJSON(r, &updates); err != nil {
-		SendBadRequest(w, "Invalid JSON", err.Error())
+		SendInvalidJSON(w, err)
 		return
 	}
 	
@@ -228,10 +474,92 @@ func (h *TaskHandlers) PartialUpdateTask(w http.ResponseWriter, r *http.Request)
 			}
 		}
 	}
+	if milestone, ok := updates["milestone_id"]; ok {
+		// null detaches the task from its milestone
+		if milestone == nil {
+			task.MilestoneID = nil
+		} else if idFloat, ok := milestone.(float64); ok && idFloat > 0 {
+			milestoneID := uint(idFloat)
+			if !h.milestoneExists(w, milestoneID) {
+				return
+			}
+			task.MilestoneID = &milestoneID
+		} else {
+			SendBadRequest(w, "Invalid milestone_id", nil)
+			return
+		}
+	}
+	if project, ok := updates["project_id"]; ok {
+		// null moves the task back to the default project
+		if project == nil {
+			task.ProjectID = nil
+		} else if idFloat, ok := project.(float64); ok && idFloat > 0 {
+			projectID := uint(idFloat)
+			if !h.projectExists(w, projectID) {
+				return
+			}
+			task.ProjectID = &projectID
+		} else {
+			SendBadRequest(w, "Invalid project_id", nil)
+			return
+		}
+	}
+	if context, ok := updates["context"]; ok {
+		// null or "" clears the context
+		value, isString := context.(string)
+		if context != nil && !isString {
+			SendBadRequest(w, "Invalid context", nil)
+			return
+		}
+		task.Context = value
+	}
+	if size, ok := updates["size"]; ok {
+		// null or "" clears the size; points may be given as a number
+		var value string
+		switch size := size.(type) {
+		case nil:
+		case string:
+			value = size
+		case float64:
+			value = strconv.FormatFloat(size, 'f', -1, 64)
+		default:
+			SendBadRequest(w, "Invalid size", nil)
+			return
+		}
+		parsed, err := services.ParseTaskSize(value)
+		if err != nil {
+			SendValidationError(w, "Validation failed", []string{err.Error()})
+			return
+		}
+		task.Size = parsed
+	}
+	if due, ok := updates["due_date"]; ok {
+		// null or "" clears the due date
+		value, isString := due.(string)
+		if due != nil && !isString {
+			SendBadRequest(w, "Invalid due_date", nil)
+			return
+		}
+		if task.DueDate, err = services.ParseDueDate(strings.TrimSpace(value)); err != nil {
+			SendBadRequest(w, "Invalid due_date", err.Error())
+			return
+		}
+	}
 	
 	task.UpdatedAt = time.Now()
+	if user := middleware.GetCurrentUser(r); user != nil {
+		task.ChangedBy = user.Username
+	}
 	
 	if err := h.taskService.UpdateTask(task); err != nil {
+		if err == services.ErrWIPLimitReached {
+			h.sendWIPLimitReached(w, task)
+			return
+		}
+		if errors.Is(err, services.ErrOpenBlockers) {
+			SendConflict(w, "Task is blocked by open tasks", err.Error())
+			return
+		}
 		SendInternalError(w, "Failed to update task")
 		return
 	}
@@ -255,11 +583,37 @@ func (h *TaskHandlers) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	SendNoContent(w)
 }
 
+// GetContexts handles GET /api/v1/contexts
+// Lists the task contexts in use, such as @home, with their open task counts
+func (h *TaskHandlers) GetContexts(w http.ResponseWriter, r *http.Request) {
+	contexts, err := h.taskService.GetContexts()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve contexts")
+		return
+	}
+	if contexts == nil {
+		contexts = []repository.ContextCount{}
+	}
+
+	SendSuccess(w, contexts, "Contexts retrieved successfully")
+}
+
 // Helper method to apply filters (basic implementation)
 func (h *TaskHandlers) applyFilters(tasks []*models.Task, filters TaskFilters) []*models.Task {
+	return applyTaskFilters(tasks, filters)
+}
+
+// applyTaskFilters returns the tasks matching the status, priority, tag,
+// milestone, context and search filters
+func applyTaskFilters(tasks []*models.Task, filters TaskFilters) []*models.Task {
 	var filtered []*models.Task
 	
+	now := time.Now()
 	for _, task := range tasks {
+		if !filters.matchesMilestone(task) || !filters.matchesProject(task) || !services.MatchesContext(task.Context, filters.Context) || !filters.matchesTagSets(task) || !filters.matchesDue(task, now) {
+			continue
+		}
+
 		// Status filter
 		if len(filters.Status) > 0 {
 			found := false
@@ -307,18 +661,186 @@ func (h *TaskHandlers) applyFilters(tasks []*models.Task, filters TaskFilters) [
 			}
 		}
 		
-		// Search filter (simple text search in name and description)
-		if filters.Search != "" {
-			searchLower := strings.ToLower(filters.Search)
-			nameLower := strings.ToLower(task.Name)
-			descLower := strings.ToLower(task.Description)
-			if !strings.Contains(nameLower, searchLower) && !strings.Contains(descLower, searchLower) {
-				continue
-			}
+		if !filters.matchesSearch(task) {
+			continue
 		}
 		
 		filtered = append(filtered, task)
 	}
 	
 	return filtered
-}
\ No newline at end of file
+}
+
+// milestoneExists checks that a milestone exists, sending an error response if it does not
+func (h *TaskHandlers) milestoneExists(w http.ResponseWriter, id uint) bool {
+	if _, err := h.taskService.GetMilestone(id); err != nil {
+		if err == services.ErrMilestoneNotFound {
+			SendBadRequest(w, "Milestone not found", nil)
+			return false
+		}
+		SendInternalError(w, "Failed to retrieve milestone")
+		return false
+	}
+	return true
+}
+
+// projectExists checks a project ID from a request, sending an error response
+// and returning false if there is no such project
+func (h *TaskHandlers) projectExists(w http.ResponseWriter, id uint) bool {
+	if err := h.taskService.CheckProject(&id); err != nil {
+		if err == services.ErrProjectNotFound {
+			SendBadRequest(w, "Project not found", nil)
+			return false
+		}
+		SendInternalError(w, "Failed to retrieve project")
+		return false
+	}
+	return true
+}
+
+// GetStatusHistory handles GET /api/v1/tasks/{id}/status-history
+func (h *TaskHandlers) GetStatusHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	if _, err := h.taskService.GetTask(id); err != nil {
+		SendNotFound(w, "Task not found")
+		return
+	}
+
+	history, err := h.taskService.GetStatusHistory(id)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve status history")
+		return
+	}
+
+	SendSuccess(w, history, "Status history retrieved successfully")
+}
+
+// ReviewRequest is a reviewer's decision on a task pending review
+type ReviewRequest struct {
+	Decision string `json:"decision"` // approve or reopen
+	Comment  string `json:"comment,omitempty"`
+}
+
+// ReviewTask handles POST /api/v1/tasks/{id}/review
+// A reviewer of the task's tags approves it, resolving it, or reopens it with
+// a comment.
+func (h *TaskHandlers) ReviewTask(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	var req ReviewRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+	if req.Decision != "approve" && req.Decision != "reopen" {
+		SendValidationError(w, "Validation failed", []string{"decision must be approve or reopen"})
+		return
+	}
+
+	if _, err := h.taskService.GetTask(id); err != nil {
+		SendNotFound(w, "Task not found")
+		return
+	}
+
+	user := middleware.GetCurrentUser(r)
+	if user == nil {
+		SendForbidden(w, "Only a user can review tasks")
+		return
+	}
+
+	task, err := h.taskService.ReviewTask(id, user.Username, req.Decision == "approve", req.Comment)
+	switch err {
+	case nil:
+		SendSuccess(w, task, "Task reviewed successfully")
+	case services.ErrNotReviewer:
+		SendForbidden(w, "You are not a reviewer of this task")
+	case services.ErrNotPendingReview, services.ErrReviewCommentRequired:
+		SendValidationError(w, "Validation failed", []string{err.Error()})
+	case services.ErrWIPLimitReached:
+		SendConflict(w, "Work-in-progress limit reached", nil)
+	default:
+		SendInternalError(w, "Failed to review task")
+	}
+}
+
+// GetTaskReviews handles GET /api/v1/tasks/{id}/reviews
+// Returns the approvals and reopenings of the task, oldest first.
+func (h *TaskHandlers) GetTaskReviews(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	if _, err := h.taskService.GetTask(id); err != nil {
+		SendNotFound(w, "Task not found")
+		return
+	}
+
+	reviews, err := h.taskService.GetTaskReviews(id)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve reviews")
+		return
+	}
+
+	SendSuccess(w, reviews, "Reviews retrieved successfully")
+}
+
+// GetJobSheet handles GET /api/v1/tasks/{id}/job-sheet, a printable PDF of the
+// task whose QR code holds its short link
+func (h *TaskHandlers) GetJobSheet(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	sheet, err := h.taskService.GetJobSheet(id, serverURL(r))
+	if err != nil {
+		SendNotFound(w, "Task not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="job-sheet-%d.pdf"`, id))
+	w.WriteHeader(http.StatusOK)
+	w.Write(sheet.PDF())
+}
+
+// GetShortLink handles GET /api/v1/tasks/{id}/short-link, the stable short
+// link that opens the task after signing in, for printing on asset labels
+func (h *TaskHandlers) GetShortLink(w http.ResponseWriter, r *http.Request) {
+	id, err := GetIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid task ID", nil)
+		return
+	}
+
+	code, err := h.taskService.TaskShortCode(id)
+	if err != nil {
+		SendNotFound(w, "Task not found")
+		return
+	}
+
+	SendSuccess(w, map[string]string{
+		"code": code,
+		"url":  serverURL(r) + services.TaskShortPath(code),
+	}, "Short link retrieved successfully")
+}
+
+// sendWIPLimitReached reports a status change rejected by an enforced WIP limit
+func (h *TaskHandlers) sendWIPLimitReached(w http.ResponseWriter, task *models.Task) {
+	SendConflict(w, "Work-in-progress limit reached", map[string]interface{}{
+		"status": task.Status,
+		"limit":  h.taskService.WIPLimit(task.Status),
+	})
+}
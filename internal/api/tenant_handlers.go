@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+type TenantHandlers struct {
+	tenantService *services.TenantService
+}
+
+func NewTenantHandlers(tenantService *services.TenantService) *TenantHandlers {
+	return &TenantHandlers{
+		tenantService: tenantService,
+	}
+}
+
+// TenantRequest represents a tenant provisioning request
+type TenantRequest struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// ProvisionedTenant is a new tenant with the credentials of its initial admin
+// user, which are only ever shown here
+type ProvisionedTenant struct {
+	Tenant        *models.Tenant `json:"tenant"`
+	AdminUsername string         `json:"admin_username"`
+	AdminPassword string         `json:"admin_password"`
+}
+
+// GetTenants handles GET /api/v1/admin/tenants
+func (h *TenantHandlers) GetTenants(w http.ResponseWriter, r *http.Request) {
+	tenants, err := h.tenantService.ListTenants()
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve tenants")
+		return
+	}
+
+	SendSuccess(w, tenants, "Tenants retrieved successfully")
+}
+
+// CreateTenant handles POST /api/v1/admin/tenants
+func (h *TenantHandlers) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	var req TenantRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	tenant, password, err := h.tenantService.CreateTenant(req.Slug, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTenantSlug), errors.Is(err, services.ErrInvalidTenantName):
+			SendValidationError(w, err.Error(), nil)
+		case errors.Is(err, services.ErrDuplicateTenant):
+			SendConflict(w, err.Error(), nil)
+		default:
+			SendInternalError(w, "Failed to create tenant")
+		}
+		return
+	}
+
+	SendCreated(w, ProvisionedTenant{
+		Tenant:        tenant,
+		AdminUsername: services.TenantAdminUsername,
+		AdminPassword: password,
+	}, "Tenant created successfully")
+}
+
+// DeleteTenant handles DELETE /api/v1/admin/tenants/{slug}
+func (h *TenantHandlers) DeleteTenant(w http.ResponseWriter, r *http.Request) {
+	if err := h.tenantService.DeleteTenant(getTenantSlugFromPath(r)); err != nil {
+		if errors.Is(err, services.ErrTenantNotFound) {
+			SendNotFound(w, err.Error())
+			return
+		}
+		SendInternalError(w, "Failed to delete tenant")
+		return
+	}
+
+	SendSuccess(w, nil, "Tenant deleted successfully")
+}
+
+func getTenantSlugFromPath(r *http.Request) string {
+	parts := strings.Split(r.URL.Path, "/")
+	for i, part := range parts {
+		if part == "tenants" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
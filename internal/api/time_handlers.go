@@ -1,9 +1,14 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/services"
 	"github.com/soarinferret/jats/internal/utils"
@@ -20,12 +25,20 @@ func NewTimeHandlers(taskService *services.TaskService) *TimeHandlers {
 }
 
 // GetTimeEntries handles GET /api/v1/tasks/{id}/time
+// Query parameters: billable (true or false) to only list billable or
+// non-billable time
 func (h *TimeHandlers) GetTimeEntries(w http.ResponseWriter, r *http.Request) {
 	taskID, err := GetIDFromPath(r)
 	if err != nil {
 		SendBadRequest(w, "Invalid task ID", nil)
 		return
 	}
+
+	billable, err := parseBillableFilter(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid billable filter", err.Error())
+		return
+	}
 	
 	// Verify task exists
 	_, err = h.taskService.GetTask(taskID)
@@ -34,9 +47,16 @@ func (h *TimeHandlers) GetTimeEntries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// This would need to be implemented in the repository
-	// For now, return empty array as placeholder
-	SendSuccess(w, []models.TimeEntry{}, "Time entries retrieved successfully")
+	entries, err := h.taskService.GetTimeEntries(taskID)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve time entries")
+		return
+	}
+	if billable != nil {
+		entries = services.FilterBillable(entries, *billable)
+	}
+	
+	SendSuccess(w, entries, "Time entries retrieved successfully")
 }
 
 // CreateTimeEntry handles POST /api/v1/tasks/{id}/time
@@ -49,7 +69,7 @@ func (h *TimeHandlers) CreateTimeEntry(w http.ResponseWriter, r *http.Request) {
 	
 	var req TimeEntryRequest
 	if err := ParseJSON(r, &req); err != nil {
-		SendBadRequest(w, "Invalid JSON", err.Error())
+		SendInvalidJSON(w, err)
 		return
 	}
 	
@@ -81,11 +101,20 @@ func (h *TimeHandlers) CreateTimeEntry(w http.ResponseWriter, r *http.Request) {
 	// Create time entry
 	timeEntry := &models.TimeEntry{
 		TaskID:      taskID,
+		SubtaskID:   req.SubtaskID,
 		Description: req.Description,
 		Duration:    req.Duration,
+		Billable:    req.Billable,
+	}
+	if user := middleware.GetCurrentUser(r); user != nil {
+		timeEntry.CreatedBy = user.Username
 	}
 	
 	if err := h.taskService.AddTimeEntryWithDate(taskID, timeEntry, createdAt); err != nil {
+		if err == services.ErrSubtaskNotInTask {
+			SendBadRequest(w, "Invalid subtask", err.Error())
+			return
+		}
 		SendInternalError(w, "Failed to create time entry")
 		return
 	}
@@ -93,25 +122,24 @@ func (h *TimeHandlers) CreateTimeEntry(w http.ResponseWriter, r *http.Request) {
 	SendCreated(w, timeEntry, "Time entry created successfully")
 }
 
-// UpdateTimeEntry handles PUT /api/v1/tasks/{taskId}/time/{id}
+// UpdateTimeEntry handles PUT /api/v1/tasks/{taskId}/time/{id}. The duration
+// and description are replaced; a date moves the entry to that day.
 func (h *TimeHandlers) UpdateTimeEntry(w http.ResponseWriter, r *http.Request) {
 	taskID, err := GetIDFromPath(r)
-	if err != nil {
+	if err != nil || taskID == 0 {
 		SendBadRequest(w, "Invalid task ID", nil)
 		return
 	}
 	
-	// Get time entry ID from path (would need custom path parsing)
-	// This is a simplified implementation
-	timeEntryIDStr := r.URL.Query().Get("time_id")
-	if timeEntryIDStr == "" {
-		SendBadRequest(w, "Time entry ID required", nil)
+	entryID, err := GetTimeEntryIDFromPath(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid time entry ID", nil)
 		return
 	}
 	
 	var req TimeEntryRequest
 	if err := ParseJSON(r, &req); err != nil {
-		SendBadRequest(w, "Invalid JSON", err.Error())
+		SendInvalidJSON(w, err)
 		return
 	}
 	
@@ -120,21 +148,28 @@ func (h *TimeHandlers) UpdateTimeEntry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// Verify task exists
-	_, err = h.taskService.GetTask(taskID)
-	if err != nil {
-		SendNotFound(w, "Task not found")
-		return
+	update := services.TimeEntryUpdate{
+		Duration:    &req.Duration,
+		Description: &req.Description,
+		Billable:    req.Billable,
+	}
+	if req.Date != "" {
+		parsed, err := utils.ParseDate(req.Date)
+		if err != nil {
+			SendBadRequest(w, "Invalid date format", err.Error())
+			return
+		}
+		update.Date = &parsed
 	}
 	
-	// This would need repository method to update time entry
-	// For now, just return success
-	timeEntry := &models.TimeEntry{
-		ID:          1, // placeholder
-		TaskID:      taskID,
-		Description: req.Description,
-		Duration:    req.Duration,
-		UpdatedAt:   time.Now(),
+	timeEntry, err := h.taskService.UpdateTimeEntry(taskID, entryID, update)
+	if err != nil {
+		if err == services.ErrTimeEntryNotFound {
+			SendNotFound(w, "Time entry not found")
+			return
+		}
+		SendInternalError(w, "Failed to update time entry")
+		return
 	}
 	
 	SendSuccess(w, timeEntry, "Time entry updated successfully")
@@ -143,28 +178,231 @@ func (h *TimeHandlers) UpdateTimeEntry(w http.ResponseWriter, r *http.Request) {
 // DeleteTimeEntry handles DELETE /api/v1/tasks/{taskId}/time/{id}
 func (h *TimeHandlers) DeleteTimeEntry(w http.ResponseWriter, r *http.Request) {
 	taskID, err := GetIDFromPath(r)
-	if err != nil {
+	if err != nil || taskID == 0 {
 		SendBadRequest(w, "Invalid task ID", nil)
 		return
 	}
 	
-	// Verify task exists
-	_, err = h.taskService.GetTask(taskID)
+	entryID, err := GetTimeEntryIDFromPath(r)
 	if err != nil {
-		SendNotFound(w, "Task not found")
+		SendBadRequest(w, "Invalid time entry ID", nil)
+		return
+	}
+	
+	if err := h.taskService.DeleteTimeEntry(taskID, entryID); err != nil {
+		if err == services.ErrTimeEntryNotFound {
+			SendNotFound(w, "Time entry not found")
+			return
+		}
+		SendInternalError(w, "Failed to delete time entry")
 		return
 	}
 	
-	// This would need repository method to delete time entry
-	// For now, just return success
 	SendNoContent(w)
 }
 
 // GetAllTimeEntries handles GET /api/v1/time
+// Query parameters: since, until (dates such as "yesterday" or "2025-12-01";
+// until is inclusive), billable (true or false). Without since, the last week
+// is returned. Entries are newest first and carry the name of their task.
 func (h *TimeHandlers) GetAllTimeEntries(w http.ResponseWriter, r *http.Request) {
-	// Parse query parameters for filtering
-	// This would need more sophisticated filtering implementation
+	values := r.URL.Query()
+	
+	since, until, err := services.ParseActivityRange(values.Get("since"), values.Get("until"))
+	if err != nil {
+		SendBadRequest(w, "Invalid date format", err.Error())
+		return
+	}
+
+	billable, err := parseBillableFilter(r)
+	if err != nil {
+		SendBadRequest(w, "Invalid billable filter", err.Error())
+		return
+	}
+	
+	entries, err := h.taskService.ListTimeEntries(since, until, billable)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve time entries")
+		return
+	}
 	
-	// For now, return empty array as placeholder
-	SendSuccess(w, []models.TimeEntry{}, "Time entries retrieved successfully")
-}
\ No newline at end of file
+	SendSuccess(w, entries, "Time entries retrieved successfully")
+}
+
+// BulkTimeEntryRequest is a batch of time entries, each naming its task
+type BulkTimeEntryRequest struct {
+	Entries []BulkTimeEntry `json:"entries"`
+}
+
+// BulkTimeEntry is one entry of a BulkTimeEntryRequest
+type BulkTimeEntry struct {
+	TaskID uint `json:"task_id"`
+	TimeEntryRequest
+}
+
+// CreateTimeEntries handles POST /api/v1/time/bulk
+func (h *TimeHandlers) CreateTimeEntries(w http.ResponseWriter, r *http.Request) {
+	var req BulkTimeEntryRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	if len(req.Entries) == 0 {
+		SendValidationError(w, "Validation failed", []string{"entries is required"})
+		return
+	}
+
+	entries := make([]*models.TimeEntry, 0, len(req.Entries))
+	for i, item := range req.Entries {
+		if item.TaskID == 0 {
+			SendValidationError(w, "Validation failed", []string{fmt.Sprintf("entry %d: task_id is required", i+1)})
+			return
+		}
+		if problems := item.Validate(); len(problems) > 0 {
+			SendValidationError(w, "Validation failed", []string{fmt.Sprintf("entry %d: %s", i+1, strings.Join(problems, ", "))})
+			return
+		}
+
+		entry := &models.TimeEntry{
+			TaskID:      item.TaskID,
+			SubtaskID:   item.SubtaskID,
+			Description: item.Description,
+			Duration:    item.Duration,
+			Billable:    item.Billable,
+		}
+		if item.Date != "" {
+			parsed, err := utils.ParseDate(item.Date)
+			if err != nil {
+				SendBadRequest(w, "Invalid date format", fmt.Sprintf("entry %d: %v", i+1, err))
+				return
+			}
+			entry.CreatedAt = parsed
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := h.taskService.AddTimeEntries(entries); err != nil {
+		switch {
+		case errors.Is(err, services.ErrBulkTaskNotFound):
+			SendNotFound(w, err.Error())
+		case errors.Is(err, services.ErrSubtaskNotInTask), errors.Is(err, services.ErrInvalidDuration),
+			errors.Is(err, services.ErrTooManyBulkEntries):
+			SendBadRequest(w, err.Error(), nil)
+		default:
+			SendInternalError(w, "Failed to create time entries")
+		}
+		return
+	}
+
+	SendCreated(w, entries, fmt.Sprintf("%d time entries created successfully", len(entries)))
+}
+
+// TimesheetRequest sets cells of the current user's timesheet
+type TimesheetRequest struct {
+	Cells []TimesheetCellRequest `json:"cells"`
+}
+
+// TimesheetCellRequest is the total time logged on a task in one day
+type TimesheetCellRequest struct {
+	TaskID  uint   `json:"task_id"`
+	Date    string `json:"date"`
+	Minutes int    `json:"minutes"`
+}
+
+// GetTimesheet handles GET /api/v1/time/timesheet
+// Query parameters: week (any date in the week, such as "2025-12-01" or "last
+// monday"; defaults to this week). Returns the current user's time in the
+// week, Monday to Sunday, as minutes per task and day.
+func (h *TimeHandlers) GetTimesheet(w http.ResponseWriter, r *http.Request) {
+	week, err := utils.ParseDate(r.URL.Query().Get("week"))
+	if err != nil {
+		SendBadRequest(w, "Invalid date format", err.Error())
+		return
+	}
+
+	sheet, err := h.taskService.GetTimesheet(timesheetUser(r), week)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve timesheet")
+		return
+	}
+
+	SendSuccess(w, sheet, "Timesheet retrieved successfully")
+}
+
+// UpdateTimesheet handles PUT /api/v1/time/timesheet. Each cell sets the
+// current user's total time on a task for a day, creating, updating or
+// deleting their entries to match; 0 clears the cell. Returns the timesheet
+// of the week of the first cell.
+func (h *TimeHandlers) UpdateTimesheet(w http.ResponseWriter, r *http.Request) {
+	var req TimesheetRequest
+	if err := ParseJSON(r, &req); err != nil {
+		SendInvalidJSON(w, err)
+		return
+	}
+
+	if len(req.Cells) == 0 {
+		SendValidationError(w, "Validation failed", []string{"cells is required"})
+		return
+	}
+
+	cells := make([]services.TimesheetCell, 0, len(req.Cells))
+	for i, item := range req.Cells {
+		if item.TaskID == 0 {
+			SendValidationError(w, "Validation failed", []string{fmt.Sprintf("cell %d: task_id is required", i+1)})
+			return
+		}
+		if item.Date == "" {
+			SendValidationError(w, "Validation failed", []string{fmt.Sprintf("cell %d: date is required", i+1)})
+			return
+		}
+		date, err := utils.ParseDate(item.Date)
+		if err != nil {
+			SendBadRequest(w, "Invalid date format", fmt.Sprintf("cell %d: %v", i+1, err))
+			return
+		}
+		cells = append(cells, services.TimesheetCell{TaskID: item.TaskID, Date: date, Minutes: item.Minutes})
+	}
+
+	username := timesheetUser(r)
+	if err := h.taskService.SetTimesheetCells(username, cells); err != nil {
+		switch {
+		case errors.Is(err, services.ErrBulkTaskNotFound):
+			SendNotFound(w, err.Error())
+		case errors.Is(err, services.ErrInvalidTimesheetMinutes), errors.Is(err, services.ErrTooManyBulkEntries):
+			SendBadRequest(w, err.Error(), nil)
+		default:
+			SendInternalError(w, "Failed to update timesheet")
+		}
+		return
+	}
+
+	sheet, err := h.taskService.GetTimesheet(username, cells[0].Date)
+	if err != nil {
+		SendInternalError(w, "Failed to retrieve timesheet")
+		return
+	}
+
+	SendSuccess(w, sheet, "Timesheet updated successfully")
+}
+
+// timesheetUser names whose timesheet a request reads or writes
+func timesheetUser(r *http.Request) string {
+	if user := middleware.GetCurrentUser(r); user != nil {
+		return user.Username
+	}
+	return ""
+}
+
+// parseBillableFilter reads the billable query parameter; nil means all time
+func parseBillableFilter(r *http.Request) (*bool, error) {
+	value := r.URL.Query().Get("billable")
+	if value == "" {
+		return nil, nil
+	}
+	billable, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("billable must be true or false")
+	}
+	return &billable, nil
+}
@@ -2,25 +2,52 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+	"github.com/soarinferret/jats/internal/tagexpr"
 )
 
+// SortRecentlyTouched orders tasks by the signed-in user's latest interaction with them
+const SortRecentlyTouched = "recently_touched"
+
 // ParseTaskFilters parses query parameters for task filtering
 type TaskFilters struct {
-	Status   []models.TaskStatus   `json:"status"`
-	Priority []models.TaskPriority `json:"priority"`
-	Tags     []string              `json:"tags"`
-	Search   string                `json:"search"`
-	Limit    int                   `json:"limit"`
-	Offset   int                   `json:"offset"`
-	Sort     string                `json:"sort"`
-	Order    string                `json:"order"`
+	Status      []models.TaskStatus   `json:"status"`
+	Priority    []models.TaskPriority `json:"priority"`
+	Tags        []string              `json:"tags"`         // tasks with any of these tags
+	AllTags     []string              `json:"all_tags"`     // tasks with every one of these tags
+	ExcludeTags []string              `json:"exclude_tags"` // tasks with none of these tags
+	TagExpr     string                `json:"tag_expr"`     // boolean tag expression, e.g. (a OR b) AND NOT c
+	Search      string                `json:"search"`
+	In          []string              `json:"in"` // fields search looks in; empty means all of services.SearchFields
+	MilestoneID uint                  `json:"milestone_id"` // tasks on this milestone
+	NoMilestone bool                  `json:"no_milestone"` // milestone=none: tasks without a milestone
+	ProjectID   uint                  `json:"project_id"`   // tasks in this project
+	Context     string                `json:"context"`      // tasks in this context, e.g. @home; "none" for tasks without one
+	DueBefore   string                `json:"due_before"`   // tasks due on or before this date
+	DueAfter    string                `json:"due_after"`    // tasks due on or after this date
+	Overdue     bool                  `json:"overdue"`      // open tasks whose due day has passed
+	Limit       int                   `json:"limit"`
+	Offset      int                   `json:"offset"`
+	Sort        string                `json:"sort"`
+	Order       string                `json:"order"`
+
+	// search is the parsed Search, tagExpr the parsed TagExpr and dueBefore
+	// and dueAfter the parsed due date bounds, set by resolveSearch
+	search    *services.TaskSearch
+	tagExpr   tagexpr.Expr
+	dueBefore *time.Time
+	dueAfter  *time.Time
 }
 
 // ParseTaskFilters extracts task filters from query parameters
@@ -56,8 +83,35 @@ func ParseTaskFilters(values url.Values) TaskFilters {
 		}
 	}
 
+	filters.AllTags = parseTagParam(values.Get("all_tags"))
+	filters.ExcludeTags = parseTagParam(values.Get("exclude_tags"))
+	filters.TagExpr = values.Get("tag_expr")
+
 	// Parse search
 	filters.Search = values.Get("search")
+	filters.In = parseTagParam(values.Get("in"))
+
+	// Parse milestone filter
+	if milestoneStr := values.Get("milestone"); milestoneStr != "" {
+		if milestoneStr == "none" {
+			filters.NoMilestone = true
+		} else if id, err := strconv.ParseUint(milestoneStr, 10, 32); err == nil {
+			filters.MilestoneID = uint(id)
+		}
+	}
+
+	// Parse project filter
+	if id, err := strconv.ParseUint(values.Get("project"), 10, 32); err == nil {
+		filters.ProjectID = uint(id)
+	}
+
+	// Parse context filter
+	filters.Context = strings.TrimSpace(values.Get("context"))
+
+	// Parse due date filters
+	filters.DueBefore = strings.TrimSpace(values.Get("due_before"))
+	filters.DueAfter = strings.TrimSpace(values.Get("due_after"))
+	filters.Overdue, _ = strconv.ParseBool(values.Get("overdue"))
 
 	// Parse pagination
 	if limitStr := values.Get("limit"); limitStr != "" {
@@ -82,11 +136,178 @@ func ParseTaskFilters(values url.Values) TaskFilters {
 	return filters
 }
 
-// ParseJSON parses JSON request body into the provided interface
+// matchesMilestone reports whether a task passes the milestone filter
+func (f TaskFilters) matchesMilestone(task *models.Task) bool {
+	if f.NoMilestone {
+		return task.MilestoneID == nil
+	}
+	if f.MilestoneID != 0 {
+		return task.MilestoneID != nil && *task.MilestoneID == f.MilestoneID
+	}
+	return true
+}
+
+// matchesProject reports whether a task passes the project filter
+func (f TaskFilters) matchesProject(task *models.Task) bool {
+	return f.ProjectID == 0 || (task.ProjectID != nil && *task.ProjectID == f.ProjectID)
+}
+
+// matchesDue reports whether a task passes the due date and overdue filters.
+// Tasks without a due date only pass when neither bound is set.
+func (f TaskFilters) matchesDue(task *models.Task, now time.Time) bool {
+	if f.Overdue && !task.IsOverdue(now) {
+		return false
+	}
+	if f.dueBefore == nil && f.dueAfter == nil {
+		return true
+	}
+	if task.DueDate == nil {
+		return false
+	}
+	if f.dueBefore != nil && task.DueDate.After(*f.dueBefore) {
+		return false
+	}
+	return f.dueAfter == nil || !task.DueDate.Before(*f.dueAfter)
+}
+
+// matchesTagSets reports whether a task has every AllTags tag, none of the
+// ExcludeTags tags, and satisfies the tag expression
+func (f TaskFilters) matchesTagSets(task *models.Task) bool {
+	if f.tagExpr != nil && !f.tagExpr.Matches(task.Tags) {
+		return false
+	}
+	for _, tag := range f.AllTags {
+		if !slices.Contains(task.Tags, tag) {
+			return false
+		}
+	}
+	for _, tag := range f.ExcludeTags {
+		if slices.Contains(task.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesSearch reports whether a task passes the search filter. Without
+// resolveSearch the search is only looked for in the name and description.
+func (f TaskFilters) matchesSearch(task *models.Task) bool {
+	if f.Search == "" {
+		return true
+	}
+	if f.search != nil {
+		return f.search.Matches(task)
+	}
+	searchLower := strings.ToLower(f.Search)
+	return strings.Contains(strings.ToLower(task.Name), searchLower) ||
+		strings.Contains(strings.ToLower(task.Description), searchLower)
+}
+
+// resolveSearch parses the search filter's query syntax (tag:, status:, quoted
+// phrases and so on) and looks up the tasks containing its text in the fields
+// of In, including comments and time entries. It also parses the tag
+// expression and due date bounds. It sends an error response and returns
+// false if any is invalid or the search fails.
+func resolveSearch(w http.ResponseWriter, taskService *services.TaskService, filters *TaskFilters) bool {
+	err := prepareFilters(taskService, filters)
+	var invalid *invalidFilterError
+	switch {
+	case err == nil:
+		return true
+	case errors.As(err, &invalid):
+		SendBadRequest(w, invalid.message, invalid.err.Error())
+	default:
+		SendInternalError(w, "Failed to search tasks")
+	}
+	return false
+}
+
+// invalidFilterError is a task filter that does not parse
+type invalidFilterError struct {
+	message string
+	err     error
+}
+
+func (e *invalidFilterError) Error() string {
+	return e.message + ": " + e.err.Error()
+}
+
+// prepareFilters does the parsing and lookups of resolveSearch, returning an
+// *invalidFilterError for an invalid filter
+func prepareFilters(taskService *services.TaskService, filters *TaskFilters) error {
+	for _, bound := range []struct {
+		name   string
+		value  string
+		parsed **time.Time
+	}{{"due_before", filters.DueBefore, &filters.dueBefore}, {"due_after", filters.DueAfter, &filters.dueAfter}} {
+		due, err := services.ParseDueDate(bound.value)
+		if err != nil {
+			return &invalidFilterError{"Invalid " + bound.name, err}
+		}
+		*bound.parsed = due
+	}
+	if filters.TagExpr != "" {
+		expr, err := tagexpr.Parse(filters.TagExpr)
+		if err != nil {
+			return &invalidFilterError{"Invalid tag expression", err}
+		}
+		filters.tagExpr = expr
+	}
+	if filters.Search == "" {
+		return nil
+	}
+	search, err := taskService.NewTaskSearch(filters.Search, filters.In)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSearch) {
+			return &invalidFilterError{"Invalid search", err}
+		}
+		return err
+	}
+	filters.search = search
+	return nil
+}
+
+// parseTagParam splits a comma-separated tag parameter, dropping empty entries
+func parseTagParam(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ParseJSON parses JSON request body into the provided interface. Unknown
+// fields, empty bodies and trailing data after the JSON value are rejected.
 func ParseJSON(r *http.Request, v interface{}) error {
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields()
-	return decoder.Decode(v)
+	if err := decoder.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("request body is empty")
+		}
+		return err
+	}
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return err
+		}
+		return errors.New("request body must contain a single JSON value")
+	}
+	return nil
+}
+
+// SendInvalidJSON reports a body rejected by ParseJSON: 413 when it exceeded the
+// route's size limit, 400 otherwise
+func SendInvalidJSON(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		SendPayloadTooLarge(w, tooLarge.Limit)
+		return
+	}
+	SendBadRequest(w, "Invalid JSON", err.Error())
 }
 
 // GetIDFromPath extracts ID parameter from URL path
@@ -98,7 +319,7 @@ func GetIDFromPath(r *http.Request) (uint, error) {
 	
 	// Find the numeric ID part - it should be after "/tasks" or "/saved-queries"
 	for i, part := range parts {
-		if (part == "tasks" || part == "saved-queries") && i+1 < len(parts) {
+		if (part == "tasks" || part == "saved-queries" || part == "contacts" || part == "canned-responses" || part == "quarantine" || part == "attachments" || part == "milestones" || part == "assets" || part == "projects" || part == "mutes" || part == "out-of-office" || part == "rules" || part == "query-webhooks" || part == "users") && i+1 < len(parts) {
 			idStr := parts[i+1]
 			// Check if this part is actually an ID and not another path segment
 			if id, err := strconv.ParseUint(idStr, 10, 32); err == nil {
@@ -129,6 +350,64 @@ func GetSubtaskIDFromPath(r *http.Request) (uint, error) {
 	return 0, fmt.Errorf("subtask ID not found in path")
 }
 
+// GetTimeEntryIDFromPath extracts the time entry ID from URL paths like /api/v1/tasks/{id}/time/{timeId}
+func GetTimeEntryIDFromPath(r *http.Request) (uint, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	for i, part := range parts {
+		if part == "time" && i+1 < len(parts) {
+			if id, err := strconv.ParseUint(parts[i+1], 10, 32); err == nil && id > 0 {
+				return uint(id), nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("time entry ID not found in path")
+}
+
+// GetScheduledActionIDFromPath extracts the action ID from URL paths like /api/v1/tasks/{id}/scheduled/{actionId}
+func GetScheduledActionIDFromPath(r *http.Request) (uint, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	for i, part := range parts {
+		if part == "scheduled" && i+1 < len(parts) {
+			if id, err := strconv.ParseUint(parts[i+1], 10, 32); err == nil && id > 0 {
+				return uint(id), nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("scheduled action ID not found in path")
+}
+
+// GetAssetIDFromPath extracts the asset ID from URL paths like /api/v1/tasks/{id}/assets/{assetId}
+func GetAssetIDFromPath(r *http.Request) (uint, error) {
+	parts := strings.Split(r.URL.Path, "/")
+	for i, part := range parts {
+		if part == "assets" && i+1 < len(parts) {
+			if id, err := strconv.ParseUint(parts[i+1], 10, 32); err == nil && id > 0 {
+				return uint(id), nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("asset ID not found in path")
+}
+
+// GetTagFromPath extracts the tag from URL paths like /api/v1/tags/{tag}/apply
+// and /api/v1/kanban/{tag}
+func GetTagFromPath(r *http.Request) string {
+	parts := strings.Split(r.URL.EscapedPath(), "/")
+	for i, part := range parts {
+		if (part == "tags" || part == "kanban") && i+1 < len(parts) {
+			tag, err := url.PathUnescape(parts[i+1])
+			if err != nil {
+				return ""
+			}
+			return tag
+		}
+	}
+	return ""
+}
+
 // ValidateTaskRequest validates task creation/update request
 type TaskRequest struct {
 	Name        string                `json:"name"`
@@ -137,6 +416,12 @@ type TaskRequest struct {
 	Priority    models.TaskPriority   `json:"priority,omitempty"`
 	Tags        []string              `json:"tags,omitempty"`
 	Date        string                `json:"date,omitempty"`
+	MilestoneID *uint                 `json:"milestone_id,omitempty"`
+	ProjectID   *uint                 `json:"project_id,omitempty"` // new tasks default to the default project
+	Assignee    *string               `json:"assignee,omitempty"` // Empty string unassigns
+	Context     *string               `json:"context,omitempty"`  // e.g. "@home"; empty string clears
+	Size        *string               `json:"size,omitempty"`     // XS-XL or story points; empty string clears
+	DueDate     *string               `json:"due_date,omitempty"` // any format accepted by utils.ParseDate; empty string clears
 }
 
 func (tr *TaskRequest) Validate() []string {
@@ -148,10 +433,11 @@ func (tr *TaskRequest) Validate() []string {
 	
 	if tr.Status != "" {
 		validStatuses := map[models.TaskStatus]bool{
-			models.TaskStatusOpen:       true,
-			models.TaskStatusInProgress: true,
-			models.TaskStatusResolved:   true,
-			models.TaskStatusClosed:     true,
+			models.TaskStatusOpen:          true,
+			models.TaskStatusInProgress:    true,
+			models.TaskStatusPendingReview: true,
+			models.TaskStatusResolved:      true,
+			models.TaskStatusClosed:        true,
 		}
 		if !validStatuses[tr.Status] {
 			errors = append(errors, "invalid status")
@@ -168,15 +454,31 @@ func (tr *TaskRequest) Validate() []string {
 			errors = append(errors, "invalid priority")
 		}
 	}
+
+	if tr.Size != nil {
+		if _, err := services.ParseTaskSize(*tr.Size); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
 	
 	return errors
 }
 
+// QuickAddRequest represents a one-line task creation request, e.g.
+// "Fix login +auth -p high -t 30m -d yesterday"
+type QuickAddRequest struct {
+	Input string `json:"input"`
+}
+
 // TimeEntryRequest represents a time entry creation/update request
 type TimeEntryRequest struct {
 	Description string `json:"description,omitempty"`
 	Duration    int    `json:"duration"`
 	Date        string `json:"date,omitempty"`
+	SubtaskID   *uint  `json:"subtask_id,omitempty"`
+	// Defaults to whether the task's tags make its time billable when
+	// creating, and is kept when updating
+	Billable *bool `json:"billable,omitempty"`
 }
 
 func (ter *TimeEntryRequest) Validate() []string {
@@ -192,6 +494,7 @@ func (ter *TimeEntryRequest) Validate() []string {
 // CommentRequest represents a comment creation/update request
 type CommentRequest struct {
 	Content   string `json:"content"`
+	Canned    string `json:"canned,omitempty"` // Name of a canned response to use instead of content
 	IsPrivate bool   `json:"is_private,omitempty"`
 	FromEmail string `json:"from_email,omitempty"`
 }
@@ -199,8 +502,8 @@ type CommentRequest struct {
 func (cr *CommentRequest) Validate() []string {
 	var errors []string
 	
-	if strings.TrimSpace(cr.Content) == "" {
-		errors = append(errors, "content is required")
+	if strings.TrimSpace(cr.Content) == "" && strings.TrimSpace(cr.Canned) == "" {
+		errors = append(errors, "content or canned is required")
 	}
 	
 	return errors
@@ -208,8 +511,9 @@ func (cr *CommentRequest) Validate() []string {
 
 // SubtaskRequest represents a subtask creation/update request
 type SubtaskRequest struct {
-	Name      string `json:"name"`
-	Completed bool   `json:"completed,omitempty"`
+	Name            string `json:"name"`
+	Completed       bool   `json:"completed,omitempty"`
+	EstimateMinutes int    `json:"estimate_minutes,omitempty"`
 }
 
 func (sr *SubtaskRequest) Validate() []string {
@@ -218,6 +522,10 @@ func (sr *SubtaskRequest) Validate() []string {
 	if strings.TrimSpace(sr.Name) == "" {
 		errors = append(errors, "name is required")
 	}
+
+	if sr.EstimateMinutes < 0 {
+		errors = append(errors, "estimate_minutes cannot be negative")
+	}
 	
 	return errors
 }
\ No newline at end of file
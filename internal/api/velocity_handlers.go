@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// maxVelocityWeeks bounds how far back the velocity report looks
+const maxVelocityWeeks = 52
+
+type VelocityHandlers struct {
+	taskService *services.TaskService
+}
+
+func NewVelocityHandlers(taskService *services.TaskService) *VelocityHandlers {
+	return &VelocityHandlers{
+		taskService: taskService,
+	}
+}
+
+// GetVelocity handles GET /api/v1/reports/velocity
+// Story points completed per week. Query parameters: weeks (default 8, up to
+// 52, the current week included) and query, a saved query ID to report on
+// instead of every task.
+func (h *VelocityHandlers) GetVelocity(w http.ResponseWriter, r *http.Request) {
+	weeks := 8
+	if weeksStr := r.URL.Query().Get("weeks"); weeksStr != "" {
+		parsed, err := strconv.Atoi(weeksStr)
+		if err != nil || parsed < 1 || parsed > maxVelocityWeeks {
+			SendBadRequest(w, "Invalid weeks", "expected a number from 1 to 52")
+			return
+		}
+		weeks = parsed
+	}
+
+	query, ok := h.savedQuery(w, r)
+	if !ok {
+		return
+	}
+
+	velocity, err := h.taskService.GetVelocity(query, weeks, time.Now())
+	if err != nil {
+		SendInternalError(w, "Failed to generate velocity report")
+		return
+	}
+
+	SendSuccess(w, velocity, "Velocity report generated successfully")
+}
+
+// GetSizeBreakdown handles GET /api/v1/reports/sizes
+// Open work by size; ?query= reports on a saved query instead of every task
+func (h *VelocityHandlers) GetSizeBreakdown(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.savedQuery(w, r)
+	if !ok {
+		return
+	}
+
+	breakdown, err := h.taskService.GetSizeBreakdown(query)
+	if err != nil {
+		SendInternalError(w, "Failed to generate size breakdown")
+		return
+	}
+
+	SendSuccess(w, breakdown, "Size breakdown generated successfully")
+}
+
+// savedQuery returns the saved query named by ?query=, or nil when there is
+// none, sending an error response if it is invalid or not found
+func (h *VelocityHandlers) savedQuery(w http.ResponseWriter, r *http.Request) (*models.SavedQuery, bool) {
+	queryStr := r.URL.Query().Get("query")
+	if queryStr == "" {
+		return nil, true
+	}
+	id, err := strconv.ParseUint(queryStr, 10, 32)
+	if err != nil {
+		SendBadRequest(w, "Invalid saved query ID", nil)
+		return nil, false
+	}
+	query, err := h.taskService.GetSavedQueryByID(uint(id))
+	if err != nil {
+		SendNotFound(w, "Saved query not found")
+		return nil, false
+	}
+	return query, true
+}
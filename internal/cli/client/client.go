@@ -2,14 +2,14 @@ package client
 
 import (
 	"bufio"
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
-	"net/http/cookiejar"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -18,12 +18,15 @@ import (
 	"golang.org/x/term"
 	"github.com/soarinferret/jats/internal/cli/config"
 	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/quickadd"
+	"github.com/soarinferret/jats/internal/utils"
+	jats "github.com/soarinferret/jats/pkg/client"
 )
 
+// Client is the CLI's view of the API: the public Go client underneath, with
+// the config handling and the re-login prompt the CLI adds on top
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	apiToken   string
+	api *jats.Client
 }
 
 type LoginRequest struct {
@@ -64,12 +67,17 @@ type CreateTaskRequest struct {
 	Priority string   `json:"priority,omitempty"`
 	Tags     []string `json:"tags,omitempty"`
 	Date     string   `json:"date,omitempty"`
+	DueDate  string   `json:"due_date,omitempty"`
+	Context  string   `json:"context,omitempty"` // e.g. "@home"
+	Size     string   `json:"size,omitempty"`    // XS-XL or story points
 }
 
 type LogTimeRequest struct {
 	Duration    int    `json:"duration"` // minutes
 	Description string `json:"description,omitempty"`
 	Date        string `json:"date,omitempty"`
+	// nil leaves it to the server: the task's tags when logging, unchanged when editing
+	Billable *bool `json:"billable,omitempty"`
 }
 
 func New() *Client {
@@ -78,67 +86,40 @@ func New() *Client {
 		cfg = &config.Config{ServerURL: "http://localhost:8081"}
 	}
 
-	// Create cookie jar for session-based auth during login
-	jar, _ := cookiejar.New(nil)
-
 	return &Client{
-		baseURL:  strings.TrimSuffix(cfg.ServerURL, "/"),
-		apiToken: cfg.Token,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-			Jar:     jar,
-		},
+		api: jats.New(cfg.ServerURL, jats.WithToken(cfg.Token), jats.WithUserAgent("jats-cli")),
 	}
 }
 
 func (c *Client) Login(username, password string) (*LoginResponse, error) {
-	// Step 1: Login with session to get authenticated
-	req := LoginRequest{
-		Username: username,
-		Password: password,
-	}
-
-	var loginResp struct {
-		Success bool `json:"success"`
-		Data    LoginResponse `json:"data"`
-		Message string `json:"message"`
-	}
-	
-	err := c.post("/api/v1/auth/login", req, &loginResp)
+	result, err := c.api.Login(context.Background(), username, password, "")
 	if err != nil {
 		return nil, err
 	}
 
-	if !loginResp.Success {
-		return nil, fmt.Errorf("login failed: %s", loginResp.Message)
-	}
-
-	// Extract session token from cookies and save it
-	baseURL, _ := url.Parse(c.baseURL)
-	cookies := c.httpClient.Jar.Cookies(baseURL)
-	var sessionToken string
-	for _, cookie := range cookies {
-		if cookie.Name == "session_token" {
-			sessionToken = cookie.Value
-			break
-		}
+	resp := &LoginResponse{}
+	if result.User != nil {
+		resp.User.ID = result.User.ID
+		resp.User.Username = result.User.Username
+		resp.User.Email = result.User.Email
 	}
-
-	if sessionToken == "" {
-		return nil, fmt.Errorf("login succeeded but no session token found")
+	if result.Session != nil {
+		resp.Session.ID = result.Session.ID
+		resp.Session.ExpiresAt = result.Session.ExpiresAt
 	}
 
 	// Update config with session token and username
 	cfg := config.GetCurrent()
 	if cfg != nil {
 		cfg.Username = username
-		cfg.Token = sessionToken
+		cfg.Token = result.Token
+		if !resp.Session.ExpiresAt.IsZero() {
+			expiresAt := resp.Session.ExpiresAt
+			cfg.TokenExpiresAt = &expiresAt
+		}
 	}
 
-	// Update client token for immediate use
-	c.apiToken = sessionToken
-
-	return &loginResp.Data, nil
+	return resp, nil
 }
 
 // promptReauth prompts the user to re-authenticate when their session has expired
@@ -219,13 +200,45 @@ func (c *Client) CreateTask(req *CreateTaskRequest) (*models.Task, error) {
 	return &apiResp.Data, nil
 }
 
+// QuickAddTask creates a task from a quick-add line such as
+// "Fix login +auth -p high -t 30m -c", parsed by the server
+func (c *Client) QuickAddTask(input string) (*models.Task, error) {
+	var apiResp struct {
+		Success bool        `json:"success"`
+		Data    models.Task `json:"data"`
+		Message string      `json:"message"`
+	}
+
+	err := c.post("/api/v1/tasks/quick", map[string]string{"input": input}, &apiResp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("quick add failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
 type TaskFilters struct {
 	Status   []string `json:"status,omitempty"`
 	Priority []string `json:"priority,omitempty"`
-	Tags     []string `json:"tags,omitempty"`
+	Tags     []string `json:"tags,omitempty"`         // any of these tags
+	AllTags  []string `json:"all_tags,omitempty"`     // every one of these tags
+	ExcludeTags []string `json:"exclude_tags,omitempty"` // none of these tags
+	TagExpr  string   `json:"tag_expr,omitempty"`     // boolean tag expression, e.g. (a OR b) AND NOT c
 	Search   string   `json:"search,omitempty"`
+	In       []string `json:"in,omitempty"`        // fields to search: name, description, comments, time_entries; default all
+	Milestone string  `json:"milestone,omitempty"` // milestone ID or "none"
+	Context   string  `json:"context,omitempty"`   // context such as "@home", or "none"
+	Project   uint    `json:"project,omitempty"`   // project ID; 0 for every project
 	Limit    int      `json:"limit,omitempty"`
 	Offset   int      `json:"offset,omitempty"`
+	Sort     string   `json:"sort,omitempty"` // "recently_touched" for my latest interactions first
+	DueBefore string  `json:"due_before,omitempty"` // due on or before this date
+	DueAfter  string  `json:"due_after,omitempty"`  // due on or after this date
+	Overdue   bool    `json:"overdue,omitempty"`
 }
 
 type Task struct {
@@ -235,17 +248,35 @@ type Task struct {
 	Status      models.TaskStatus `json:"status"`
 	Priority    models.TaskPriority `json:"priority"`
 	Tags        []string          `json:"tags"`
+	MilestoneID *uint             `json:"milestone_id,omitempty"`
+	ProjectID   *uint             `json:"project_id,omitempty"`
+	Context     string            `json:"context,omitempty"`
+	Size        models.TaskSize   `json:"size,omitempty"`
 	CreatedAt   time.Time         `json:"created_at"`
 	UpdatedAt   time.Time         `json:"updated_at"`
 	TimeEntries []TimeEntry       `json:"time_entries"`
 	Comments    []Comment         `json:"comments"`
 	Subtasks    []Subtask         `json:"subtasks"`
+
+	EstimateMinutes int `json:"estimate_minutes"`
+	LoggedMinutes   int `json:"logged_minutes"`
+
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	StatusAgeDays   int        `json:"status_age_days"`
+
+	DueDate *time.Time `json:"due_date,omitempty"`
+	Overdue bool       `json:"overdue,omitempty"`
 }
 
 type TimeEntry struct {
 	ID          uint      `json:"id"`
+	TaskID      uint      `json:"task_id"`
+	TaskName    string    `json:"task_name,omitempty"` // only set by GetTimeEntries
+	SubtaskID   *uint     `json:"subtask_id,omitempty"`
 	Description string    `json:"description"`
 	Duration    int       `json:"duration"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	Billable    bool      `json:"billable"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -257,12 +288,14 @@ type Comment struct {
 }
 
 type Subtask struct {
-	ID        uint      `json:"id"`
-	TaskID    uint      `json:"task_id"`
-	Name      string    `json:"name"`
-	Completed bool      `json:"completed"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uint      `json:"id"`
+	TaskID          uint      `json:"task_id"`
+	Name            string    `json:"name"`
+	Completed       bool      `json:"completed"`
+	EstimateMinutes int       `json:"estimate_minutes"`
+	LoggedMinutes   int       `json:"logged_minutes"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 type SavedQuery struct {
@@ -270,6 +303,13 @@ type SavedQuery struct {
 	Name         string    `json:"name"`
 	IncludedTags []string  `json:"included_tags"`
 	ExcludedTags []string  `json:"excluded_tags"`
+	Expression   string    `json:"expression,omitempty"`
+	ProjectID    *uint     `json:"project_id,omitempty"` // nil for every project
+	Position     int       `json:"position"`
+	OpenCount    *int      `json:"open_count,omitempty"`
+	InProgress   *int      `json:"in_progress_count,omitempty"`
+	WeekMinutes  *int      `json:"week_minutes,omitempty"`
+	Pinned       bool      `json:"pinned,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -285,6 +325,8 @@ type TimeBreakdownReport struct {
 type DailyTimeBreakdown struct {
 	Date       string               `json:"date"`
 	TotalTime  int                  `json:"total_time"`
+	BillableTime    int             `json:"billable_time"`
+	NonBillableTime int             `json:"non_billable_time"`
 	QueryTimes []QueryTimeBreakdown `json:"query_times"`
 	OtherTime  int                  `json:"other_time"`
 	OtherTags  []string             `json:"other_tags"`
@@ -299,6 +341,8 @@ type QueryTimeBreakdown struct {
 
 type QueryTotals struct {
 	TotalTime   int          `json:"total_time"`
+	BillableTime    int      `json:"billable_time"`
+	NonBillableTime int      `json:"non_billable_time"`
 	QueryTotals []QueryTotal `json:"query_totals"`
 	OtherTotal  OtherTotal   `json:"other_total"`
 }
@@ -329,15 +373,48 @@ func (c *Client) GetTasks(filters *TaskFilters) ([]Task, error) {
 		if len(filters.Tags) > 0 {
 			query.Add("tags", strings.Join(filters.Tags, ","))
 		}
+		if len(filters.AllTags) > 0 {
+			query.Add("all_tags", strings.Join(filters.AllTags, ","))
+		}
+		if len(filters.ExcludeTags) > 0 {
+			query.Add("exclude_tags", strings.Join(filters.ExcludeTags, ","))
+		}
+		if filters.TagExpr != "" {
+			query.Add("tag_expr", filters.TagExpr)
+		}
 		if filters.Search != "" {
 			query.Add("search", filters.Search)
 		}
+		if len(filters.In) > 0 {
+			query.Add("in", strings.Join(filters.In, ","))
+		}
+		if filters.Milestone != "" {
+			query.Add("milestone", filters.Milestone)
+		}
+		if filters.Context != "" {
+			query.Add("context", filters.Context)
+		}
+		if filters.Project != 0 {
+			query.Add("project", strconv.FormatUint(uint64(filters.Project), 10))
+		}
 		if filters.Limit > 0 {
 			query.Add("limit", strconv.Itoa(filters.Limit))
 		}
 		if filters.Offset > 0 {
 			query.Add("offset", strconv.Itoa(filters.Offset))
 		}
+		if filters.Sort != "" {
+			query.Add("sort", filters.Sort)
+		}
+		if filters.DueBefore != "" {
+			query.Add("due_before", filters.DueBefore)
+		}
+		if filters.DueAfter != "" {
+			query.Add("due_after", filters.DueAfter)
+		}
+		if filters.Overdue {
+			query.Add("overdue", "true")
+		}
 	}
 
 	endpoint := "/api/v1/tasks"
@@ -413,6 +490,31 @@ func (c *Client) UpdateTaskStatus(id uint, status string) (*models.Task, error)
 	return &apiResp.Data, nil
 }
 
+// ReviewTask approves a task pending review, or reopens it with a comment;
+// decision is approve or reopen
+func (c *Client) ReviewTask(id uint, decision, comment string) (*models.Task, error) {
+	req := map[string]interface{}{
+		"decision": decision,
+		"comment":  comment,
+	}
+
+	var apiResp struct {
+		Success bool        `json:"success"`
+		Data    models.Task `json:"data"`
+		Message string      `json:"message"`
+	}
+
+	if err := c.post(fmt.Sprintf("/api/v1/tasks/%d/review", id), req, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("review task failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
 func (c *Client) LogTime(taskID uint, req *LogTimeRequest) error {
 	var apiResp struct {
 		Success bool `json:"success"`
@@ -431,14 +533,82 @@ func (c *Client) LogTime(taskID uint, req *LogTimeRequest) error {
 	return nil
 }
 
+// GetTimeEntries returns the time logged on any task between since and until
+// (date expressions such as "today" or "2025-12-01", until inclusive), newest first
+func (c *Client) GetTimeEntries(since, until string) ([]TimeEntry, error) {
+	query := url.Values{}
+	if since != "" {
+		query.Set("since", since)
+	}
+	if until != "" {
+		query.Set("until", until)
+	}
+
+	endpoint := "/api/v1/time"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var apiResp struct {
+		Success bool        `json:"success"`
+		Data    []TimeEntry `json:"data"`
+		Message string      `json:"message"`
+	}
+
+	if err := c.get(endpoint, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get time entries failed: %s", apiResp.Message)
+	}
+
+	return apiResp.Data, nil
+}
+
+// UpdateTimeEntry replaces the duration and description of a time entry; a date
+// moves it to that day
+func (c *Client) UpdateTimeEntry(taskID, entryID uint, req *LogTimeRequest) (*TimeEntry, error) {
+	var apiResp struct {
+		Success bool      `json:"success"`
+		Data    TimeEntry `json:"data"`
+		Message string    `json:"message"`
+	}
+
+	if err := c.put(fmt.Sprintf("/api/v1/tasks/%d/time/%d", taskID, entryID), req, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("update time entry failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+// DeleteTimeEntry removes a time entry from a task
+func (c *Client) DeleteTimeEntry(taskID, entryID uint) error {
+	return c.delete(fmt.Sprintf("/api/v1/tasks/%d/time/%d", taskID, entryID))
+}
+
 func (c *Client) GetSavedQueries() ([]SavedQuery, error) {
+	return c.getSavedQueries("/api/v1/saved-queries")
+}
+
+// GetSavedQueriesWithCounts returns the saved queries with their open and in-progress
+// counts and the time logged this week set
+func (c *Client) GetSavedQueriesWithCounts() ([]SavedQuery, error) {
+	return c.getSavedQueries("/api/v1/saved-queries?counts=true")
+}
+
+func (c *Client) getSavedQueries(endpoint string) ([]SavedQuery, error) {
 	var apiResp struct {
 		Success bool `json:"success"`
 		Data    []SavedQuery `json:"data"`
 		Message string `json:"message"`
 	}
 	
-	err := c.get("/api/v1/saved-queries", &apiResp)
+	err := c.get(endpoint, &apiResp)
 	if err != nil {
 		return nil, err
 	}
@@ -454,6 +624,8 @@ type CreateSavedQueryRequest struct {
 	Name         string   `json:"name"`
 	IncludedTags []string `json:"included_tags,omitempty"`
 	ExcludedTags []string `json:"excluded_tags,omitempty"`
+	Expression   string   `json:"expression,omitempty"`
+	ProjectID    *uint    `json:"project_id,omitempty"` // nil for every project
 }
 
 func (c *Client) CreateSavedQuery(req *CreateSavedQueryRequest) (*SavedQuery, error) {
@@ -475,187 +647,1017 @@ func (c *Client) CreateSavedQuery(req *CreateSavedQueryRequest) (*SavedQuery, er
 	return &apiResp.Data, nil
 }
 
-type AddCommentRequest struct {
-	Content   string `json:"content"`
-	IsPrivate bool   `json:"is_private,omitempty"`
-}
-
-func (c *Client) AddComment(taskID uint, req *AddCommentRequest) error {
-	var apiResp struct {
-		Success bool `json:"success"`
-		Message string `json:"message"`
-	}
-	
-	err := c.post(fmt.Sprintf("/api/v1/tasks/%d/comments", taskID), req, &apiResp)
-	if err != nil {
-		return err
-	}
-	
-	if !apiResp.Success {
-		return fmt.Errorf("add comment failed: %s", apiResp.Message)
-	}
-	
-	return nil
+// UpdateSavedQueryRequest replaces the name, tags and tag expression of a
+// saved query; empty tag lists and an empty expression clear them
+type UpdateSavedQueryRequest struct {
+	Name         string   `json:"name"`
+	IncludedTags []string `json:"included_tags"`
+	ExcludedTags []string `json:"excluded_tags"`
+	Expression   string   `json:"expression"`
 }
 
-// GetSubtasks retrieves all subtasks for a task
-func (c *Client) GetSubtasks(taskID uint) ([]Subtask, error) {
+// UpdateSavedQuery changes the name and tags of a saved query
+func (c *Client) UpdateSavedQuery(queryID uint, req *UpdateSavedQueryRequest) (*SavedQuery, error) {
 	var apiResp struct {
-		Success bool      `json:"success"`
-		Data    []Subtask `json:"data"`
-		Message string    `json:"message"`
+		Success bool       `json:"success"`
+		Data    SavedQuery `json:"data"`
+		Message string     `json:"message"`
 	}
 
-	err := c.get(fmt.Sprintf("/api/v1/tasks/%d/subtasks", taskID), &apiResp)
-	if err != nil {
+	if err := c.put(fmt.Sprintf("/api/v1/saved-queries/%d", queryID), req, &apiResp); err != nil {
 		return nil, err
 	}
 
 	if !apiResp.Success {
-		return nil, fmt.Errorf("get subtasks failed: %s", apiResp.Message)
+		return nil, fmt.Errorf("update saved query failed: %s", apiResp.Message)
 	}
 
-	return apiResp.Data, nil
+	return &apiResp.Data, nil
 }
 
-// CreateSubtask creates a new subtask for a task
-func (c *Client) CreateSubtask(taskID uint, name string) (*Subtask, error) {
-	req := map[string]interface{}{
-		"name": name,
-	}
+// DeleteSavedQuery deletes a saved query and its email schedule
+func (c *Client) DeleteSavedQuery(queryID uint) error {
+	return c.delete(fmt.Sprintf("/api/v1/saved-queries/%d", queryID))
+}
 
+// ReorderSavedQueries sets the order of the saved queries; ids must list every query
+func (c *Client) ReorderSavedQueries(ids []uint) ([]SavedQuery, error) {
 	var apiResp struct {
-		Success bool     `json:"success"`
-		Data    Subtask  `json:"data"`
-		Message string   `json:"message"`
+		Success bool         `json:"success"`
+		Data    []SavedQuery `json:"data"`
+		Message string       `json:"message"`
 	}
 
-	err := c.post(fmt.Sprintf("/api/v1/tasks/%d/subtasks", taskID), req, &apiResp)
-	if err != nil {
+	if err := c.put("/api/v1/saved-queries/order", map[string][]uint{"ids": ids}, &apiResp); err != nil {
 		return nil, err
 	}
 
 	if !apiResp.Success {
-		return nil, fmt.Errorf("create subtask failed: %s", apiResp.Message)
+		return nil, fmt.Errorf("reorder saved queries failed: %s", apiResp.Message)
 	}
 
-	return &apiResp.Data, nil
+	return apiResp.Data, nil
 }
 
-// UpdateSubtask updates a subtask's name
-func (c *Client) UpdateSubtask(taskID, subtaskID uint, name string) (*Subtask, error) {
-	req := map[string]interface{}{
-		"name": name,
-	}
+// SavedQueryScheduleRequest schedules a saved query to be emailed on a cron expression
+type SavedQueryScheduleRequest struct {
+	Cron       string   `json:"cron"`
+	Recipients []string `json:"recipients"`
+	Subject    string   `json:"subject,omitempty"`
+}
 
+// SetSavedQuerySchedule creates or replaces the email schedule of a saved query
+func (c *Client) SetSavedQuerySchedule(queryID uint, req *SavedQueryScheduleRequest) (*models.SavedQuerySchedule, error) {
 	var apiResp struct {
-		Success bool     `json:"success"`
-		Data    Subtask  `json:"data"`
-		Message string   `json:"message"`
+		Success bool                      `json:"success"`
+		Data    models.SavedQuerySchedule `json:"data"`
+		Message string                    `json:"message"`
 	}
 
-	err := c.put(fmt.Sprintf("/api/v1/tasks/%d/subtasks/%d", taskID, subtaskID), req, &apiResp)
-	if err != nil {
+	if err := c.put(fmt.Sprintf("/api/v1/saved-queries/%d/schedule", queryID), req, &apiResp); err != nil {
 		return nil, err
 	}
 
 	if !apiResp.Success {
-		return nil, fmt.Errorf("update subtask failed: %s", apiResp.Message)
+		return nil, fmt.Errorf("set schedule failed: %s", apiResp.Message)
 	}
 
 	return &apiResp.Data, nil
 }
 
-// ToggleSubtask toggles a subtask's completion status
-func (c *Client) ToggleSubtask(taskID, subtaskID uint) (*Subtask, error) {
+// DeleteSavedQuerySchedule stops emailing a saved query
+func (c *Client) DeleteSavedQuerySchedule(queryID uint) error {
+	return c.delete(fmt.Sprintf("/api/v1/saved-queries/%d/schedule", queryID))
+}
+
+type AddCommentRequest struct {
+	Content   string `json:"content,omitempty"`
+	Canned    string `json:"canned,omitempty"` // Name of a canned response rendered by the server
+	IsPrivate bool   `json:"is_private,omitempty"`
+}
+
+func (c *Client) AddComment(taskID uint, req *AddCommentRequest) error {
 	var apiResp struct {
-		Success bool     `json:"success"`
-		Data    Subtask  `json:"data"`
-		Message string   `json:"message"`
+		Success bool `json:"success"`
+		Message string `json:"message"`
 	}
-
-	err := c.patch(fmt.Sprintf("/api/v1/tasks/%d/subtasks/%d/toggle", taskID, subtaskID), nil, &apiResp)
+	
+	err := c.post(fmt.Sprintf("/api/v1/tasks/%d/comments", taskID), req, &apiResp)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
+	
 	if !apiResp.Success {
-		return nil, fmt.Errorf("toggle subtask failed: %s", apiResp.Message)
+		return fmt.Errorf("add comment failed: %s", apiResp.Message)
 	}
-
-	return &apiResp.Data, nil
+	
+	return nil
 }
 
-// DeleteSubtask deletes a subtask
-func (c *Client) DeleteSubtask(taskID, subtaskID uint) error {
-	return c.delete(fmt.Sprintf("/api/v1/tasks/%d/subtasks/%d", taskID, subtaskID))
+// ActivityEvent is an entry in the global activity feed
+type ActivityEvent struct {
+	Type      string    `json:"type"`
+	TaskID    uint      `json:"task_id"`
+	TaskName  string    `json:"task_name"`
+	Timestamp time.Time `json:"timestamp"`
+	Detail    string    `json:"detail,omitempty"`
+	Minutes   int       `json:"minutes,omitempty"`
 }
 
-type UpdateTaskRequest struct {
-	Name        string   `json:"name,omitempty"`
-	Description string   `json:"description,omitempty"`
-	Priority    string   `json:"priority,omitempty"`
-	Tags        []string `json:"tags,omitempty"`
+// TaskEvent is a task change from the live event stream
+type TaskEvent = jats.TaskEvent
+
+// StreamEvents follows the live task event stream, calling handle with each
+// event, until ctx is done or the connection drops. A lastID other than 0
+// resumes after that event, getting the recent ones missed. It returns the
+// ID of the last event handled, to resume from.
+func (c *Client) StreamEvents(ctx context.Context, lastID uint64, handle func(TaskEvent)) (uint64, error) {
+	return c.api.StreamEvents(ctx, lastID, handle)
 }
 
-type TaskSummaryResponse struct {
-	OpenTasks             int `json:"open_tasks"`
-	InProgressTasks       int `json:"in_progress_tasks"`
-	RecentlyAddedTasks    int `json:"recently_added_tasks"`
-	RecentlyResolvedTasks int `json:"recently_resolved_tasks"`
+// ActivityFilters narrows the activity feed; dates use the same formats as -d
+type ActivityFilters struct {
+	Since   string
+	Until   string
+	Types   []string
+	TaskID  uint
+	Tag     string
+	Limit   int
+	QueryID uint
+	// Mention returns only events mentioning this username as @username
+	Mention string
+	// After returns only events newer than this; Wait (seconds) makes the server
+	// hold the request until one arrives
+	After time.Time
+	Wait  int
 }
 
-func (c *Client) UpdateTask(taskID uint, req *UpdateTaskRequest) (*Task, error) {
+// GetActivity retrieves the global activity feed, newest first
+func (c *Client) GetActivity(filters *ActivityFilters) ([]ActivityEvent, int, error) {
+	query := url.Values{}
+	if filters != nil {
+		if filters.Since != "" {
+			query.Add("since", filters.Since)
+		}
+		if filters.Until != "" {
+			query.Add("until", filters.Until)
+		}
+		if len(filters.Types) > 0 {
+			query.Add("type", strings.Join(filters.Types, ","))
+		}
+		if filters.TaskID > 0 {
+			query.Add("task_id", strconv.FormatUint(uint64(filters.TaskID), 10))
+		}
+		if filters.Tag != "" {
+			query.Add("tag", filters.Tag)
+		}
+		if filters.Limit > 0 {
+			query.Add("limit", strconv.Itoa(filters.Limit))
+		}
+		if filters.QueryID > 0 {
+			query.Add("query", strconv.FormatUint(uint64(filters.QueryID), 10))
+		}
+		if filters.Mention != "" {
+			query.Add("mention", filters.Mention)
+		}
+		if !filters.After.IsZero() {
+			query.Add("after", filters.After.Format(time.RFC3339Nano))
+		}
+		if filters.Wait > 0 {
+			query.Add("wait", strconv.Itoa(filters.Wait))
+		}
+	}
+
+	endpoint := "/api/v1/activity"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
 	var apiResp struct {
 		Success bool `json:"success"`
-		Data    Task `json:"data"`
+		Data    struct {
+			Items      []ActivityEvent `json:"items"`
+			Pagination struct {
+				Total int `json:"total"`
+			} `json:"pagination"`
+		} `json:"data"`
 		Message string `json:"message"`
 	}
-	
-	err := c.patch(fmt.Sprintf("/api/v1/tasks/%d", taskID), req, &apiResp)
-	if err != nil {
-		return nil, err
+
+	if err := c.get(endpoint, &apiResp); err != nil {
+		return nil, 0, err
 	}
-	
+
 	if !apiResp.Success {
-		return nil, fmt.Errorf("update task failed: %s", apiResp.Message)
+		return nil, 0, fmt.Errorf("get activity failed: %s", apiResp.Message)
 	}
-	
-	return &apiResp.Data, nil
+
+	return apiResp.Data.Items, apiResp.Data.Pagination.Total, nil
 }
 
-func (c *Client) GetTaskSummary(savedQueryID *uint) (*TaskSummaryResponse, error) {
-	endpoint := "/api/v1/summary/tasks"
-	if savedQueryID != nil {
-		endpoint += fmt.Sprintf("?saved_query_id=%d", *savedQueryID)
+// StandupReport is the standup summary returned by the server
+type StandupReport struct {
+	Date          string `json:"date"`
+	Since         string `json:"since"`
+	LoggedMinutes int    `json:"logged_minutes"`
+	Markdown      string `json:"markdown"`
+}
+
+// GetStandupReport retrieves the standup report for a date (YYYY-MM-DD, empty for today)
+func (c *Client) GetStandupReport(date string) (*StandupReport, error) {
+	query := url.Values{}
+	if date != "" {
+		query.Add("date", date)
+	}
+
+	endpoint := "/api/v1/reports/standup"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
 	}
 
 	var apiResp struct {
-		Success bool                 `json:"success"`
-		Data    TaskSummaryResponse  `json:"data"`
-		Message string               `json:"message"`
+		Success bool          `json:"success"`
+		Data    StandupReport `json:"data"`
+		Message string        `json:"message"`
 	}
 
-	err := c.get(endpoint, &apiResp)
-	if err != nil {
+	if err := c.get(endpoint, &apiResp); err != nil {
 		return nil, err
 	}
 
 	if !apiResp.Success {
-		return nil, fmt.Errorf("get task summary failed: %s", apiResp.Message)
+		return nil, fmt.Errorf("get standup report failed: %s", apiResp.Message)
 	}
 
 	return &apiResp.Data, nil
 }
 
-func (c *Client) GetTimeBreakdownReport(startDate, endDate, queryIDs, excludeTags string) (*TimeBreakdownReport, error) {
-	query := url.Values{}
-	query.Add("start_date", startDate)
-	query.Add("end_date", endDate)
-	query.Add("saved_query_ids", queryIDs)
+// CapacityTask is an open task counted against capacity
+type CapacityTask struct {
+	ID               uint   `json:"id"`
+	Name             string `json:"name"`
+	Status           string `json:"status"`
+	RemainingMinutes int    `json:"remaining_minutes"`
+}
+
+// CapacityPlan compares remaining estimated work with the weekly capacity
+type CapacityPlan struct {
+	WeekStart        string         `json:"week_start"`
+	WeekEnd          string         `json:"week_end"`
+	CapacityMinutes  int            `json:"capacity_minutes"`
+	RemainingMinutes int            `json:"remaining_minutes"`
+	Overcommitted    bool           `json:"overcommitted"`
+	OverByMinutes    int            `json:"over_by_minutes"`
+	UnestimatedTasks int            `json:"unestimated_tasks"`
+	Tasks            []CapacityTask `json:"tasks"`
+}
+
+// GetCapacityPlan retrieves the capacity plan for the coming week
+func (c *Client) GetCapacityPlan() (*CapacityPlan, error) {
+	var apiResp struct {
+		Success bool         `json:"success"`
+		Data    CapacityPlan `json:"data"`
+		Message string       `json:"message"`
+	}
+
+	if err := c.get("/api/v1/reports/capacity", &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get capacity plan failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+// SetWeeklyCapacity sets the current user's weekly capacity, e.g. "30h"; "0" clears it
+func (c *Client) SetWeeklyCapacity(capacity string) error {
+	var apiResp struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+	}
+
+	req := map[string]string{"weekly_capacity": capacity}
+	if err := c.patch("/api/v1/auth/profile", req, &apiResp); err != nil {
+		return err
+	}
+
+	if !apiResp.Success {
+		return fmt.Errorf("set weekly capacity failed: %s", apiResp.Message)
+	}
+
+	return nil
+}
+
+// Preferences are the current user's landing view and pinned saved queries
+type Preferences struct {
+	LandingView    string `json:"landing_view"`
+	LandingQueryID *uint  `json:"landing_query_id"`
+	PinnedQueryIDs []uint `json:"pinned_query_ids"`
+}
+
+// GetPreferences retrieves the current user's preferences from their profile
+func (c *Client) GetPreferences() (*Preferences, error) {
+	var apiResp struct {
+		Success bool        `json:"success"`
+		Data    Preferences `json:"data"`
+		Message string      `json:"message"`
+	}
+
+	if err := c.get("/api/v1/auth/profile", &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get preferences failed: %s", apiResp.Message)
+	}
+
+	if apiResp.Data.LandingView == "" {
+		apiResp.Data.LandingView = "tasks"
+	}
+	return &apiResp.Data, nil
+}
+
+// SetLandingView sets the view the web UI and TUI open on, optionally
+// narrowed to a saved query; a queryID of 0 clears it
+func (c *Client) SetLandingView(view string, queryID uint) error {
+	var apiResp struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+	}
+
+	req := map[string]interface{}{"landing_view": view, "landing_query_id": queryID}
+	if err := c.patch("/api/v1/auth/profile", req, &apiResp); err != nil {
+		return err
+	}
+
+	if !apiResp.Success {
+		return fmt.Errorf("set landing view failed: %s", apiResp.Message)
+	}
+
+	return nil
+}
+
+// SetPinnedQueries replaces the saved queries pinned to the top of the sidebar
+func (c *Client) SetPinnedQueries(ids []uint) error {
+	var apiResp struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+	}
+
+	if ids == nil {
+		ids = []uint{}
+	}
+	req := map[string][]uint{"pinned_query_ids": ids}
+	if err := c.patch("/api/v1/auth/profile", req, &apiResp); err != nil {
+		return err
+	}
+
+	if !apiResp.Success {
+		return fmt.Errorf("set pinned queries failed: %s", apiResp.Message)
+	}
+
+	return nil
+}
+
+// MilestoneProgress is a milestone with its task and time totals
+type MilestoneProgress struct {
+	Milestone       models.Milestone `json:"milestone"`
+	TotalTasks      int              `json:"total_tasks"`
+	ResolvedTasks   int              `json:"resolved_tasks"`
+	Percent         float64          `json:"percent"`
+	LoggedMinutes   int              `json:"logged_minutes"`
+	EstimateMinutes int              `json:"estimate_minutes"`
+	Overdue         bool             `json:"overdue"`
+}
+
+// CreateMilestoneRequest creates a milestone; DueDate accepts the same formats as -d
+type CreateMilestoneRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	DueDate     string `json:"due_date,omitempty"`
+}
+
+// GetMilestones retrieves all milestones with their progress
+func (c *Client) GetMilestones() ([]MilestoneProgress, error) {
+	var apiResp struct {
+		Success bool                `json:"success"`
+		Data    []MilestoneProgress `json:"data"`
+		Message string              `json:"message"`
+	}
+
+	if err := c.get("/api/v1/milestones", &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get milestones failed: %s", apiResp.Message)
+	}
+
+	return apiResp.Data, nil
+}
+
+// GetMilestoneProgress retrieves the progress of a milestone
+func (c *Client) GetMilestoneProgress(id uint) (*MilestoneProgress, error) {
+	var apiResp struct {
+		Success bool              `json:"success"`
+		Data    MilestoneProgress `json:"data"`
+		Message string            `json:"message"`
+	}
+
+	if err := c.get(fmt.Sprintf("/api/v1/milestones/%d/progress", id), &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get milestone progress failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+// CreateMilestone creates a new milestone
+func (c *Client) CreateMilestone(req *CreateMilestoneRequest) (*models.Milestone, error) {
+	var apiResp struct {
+		Success bool             `json:"success"`
+		Data    models.Milestone `json:"data"`
+		Message string           `json:"message"`
+	}
+
+	if err := c.post("/api/v1/milestones", req, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("create milestone failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+// SetTaskMilestone attaches a task to a milestone, or detaches it when milestoneID is nil
+func (c *Client) SetTaskMilestone(taskID uint, milestoneID *uint) error {
+	var apiResp struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+	}
+
+	req := map[string]interface{}{"milestone_id": milestoneID}
+	if err := c.patch(fmt.Sprintf("/api/v1/tasks/%d", taskID), req, &apiResp); err != nil {
+		return err
+	}
+
+	if !apiResp.Success {
+		return fmt.Errorf("set milestone failed: %s", apiResp.Message)
+	}
+
+	return nil
+}
+
+// BulkTagResult reports the tasks a bulk tag operation changed
+type BulkTagResult struct {
+	Tag      string `json:"tag"`
+	Action   string `json:"action"`
+	DryRun   bool   `json:"dry_run"`
+	Matched  int    `json:"matched"`
+	Affected int    `json:"affected"`
+	TaskIDs  []uint `json:"task_ids"`
+}
+
+// BulkUpdateTag adds (action "apply") or removes (action "remove") a tag on every
+// task matching filter, given as task list query parameters (status, priority,
+// tags, milestone, search, all)
+func (c *Client) BulkUpdateTag(tag, action string, filter url.Values, dryRun bool) (*BulkTagResult, error) {
+	query := url.Values{}
+	for key, values := range filter {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+	if dryRun {
+		query.Set("dry_run", "true")
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/tags/%s/%s", url.PathEscape(tag), action)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var apiResp struct {
+		Success bool          `json:"success"`
+		Data    BulkTagResult `json:"data"`
+		Message string        `json:"message"`
+	}
+
+	if err := c.post(endpoint, nil, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("bulk tag update failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+// TagInfo is a tag with the number of tasks using it
+type TagInfo struct {
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	LastUsed string `json:"last_used"`
+}
+
+// GetTags returns every tag in use, sorted by name
+func (c *Client) GetTags() ([]TagInfo, error) {
+	var apiResp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Tags []TagInfo `json:"tags"`
+		} `json:"data"`
+		Message string `json:"message"`
+	}
+
+	if err := c.get("/api/v1/tags", &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get tags failed: %s", apiResp.Message)
+	}
+
+	tags := apiResp.Data.Tags
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+// ContextCount is a task context in use and its open task count
+type ContextCount struct {
+	Context   string `json:"context"`
+	OpenTasks int    `json:"open_tasks"`
+}
+
+// GetContexts returns the task contexts in use, sorted by name
+func (c *Client) GetContexts() ([]ContextCount, error) {
+	var apiResp struct {
+		Success bool           `json:"success"`
+		Data    []ContextCount `json:"data"`
+		Message string         `json:"message"`
+	}
+
+	if err := c.get("/api/v1/contexts", &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get contexts failed: %s", apiResp.Message)
+	}
+
+	return apiResp.Data, nil
+}
+
+// Project is a workspace grouping tasks and saved queries
+type Project struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	IsDefault   bool   `json:"is_default"`
+	OpenTasks   int    `json:"open_tasks"`
+}
+
+// GetProjects returns the projects, the default one first
+func (c *Client) GetProjects() ([]Project, error) {
+	var apiResp struct {
+		Success bool      `json:"success"`
+		Data    []Project `json:"data"`
+		Message string    `json:"message"`
+	}
+
+	if err := c.get("/api/v1/projects", &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get projects failed: %s", apiResp.Message)
+	}
+
+	return apiResp.Data, nil
+}
+
+// TagStats is the workload on a tag over a period
+type TagStats struct {
+	Tag             string `json:"tag"`
+	OpenTasks       int    `json:"open_tasks"`
+	InProgressTasks int    `json:"in_progress_tasks"`
+	ResolvedTasks   int    `json:"resolved_tasks"`
+	LoggedMinutes   int    `json:"logged_minutes"`
+}
+
+// GetTagStats returns the workload per tag, sorted by tag. since and until use
+// the same formats as -d; empty since means the last 7 days.
+func (c *Client) GetTagStats(since, until string) ([]TagStats, error) {
+	query := url.Values{}
+	if since != "" {
+		query.Add("since", since)
+	}
+	if until != "" {
+		query.Add("until", until)
+	}
+	endpoint := "/api/v1/tags/stats"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var apiResp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Tags []TagStats `json:"tags"`
+		} `json:"data"`
+		Message string `json:"message"`
+	}
+
+	if err := c.get(endpoint, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get tag stats failed: %s", apiResp.Message)
+	}
+
+	return apiResp.Data.Tags, nil
+}
+
+// SearchSnippet is the part of a field around a search match; highlights are
+// rune offsets into Text, End exclusive
+type SearchSnippet struct {
+	Text       string `json:"text"`
+	Highlights []struct {
+		Start int `json:"start"`
+		End   int `json:"end"`
+	} `json:"highlights"`
+}
+
+// SearchResult is a task or comment matching a search
+type SearchResult struct {
+	ID        uint           `json:"id"`
+	Type      string         `json:"type"` // "task", "comment"
+	Name      string         `json:"name"`
+	TaskID    *uint          `json:"task_id,omitempty"`
+	MatchType string         `json:"match_type"` // "name", "description", "content"
+	Score     float64        `json:"score"`
+	Snippet   *SearchSnippet `json:"snippet,omitempty"`
+}
+
+// Search finds the tasks whose name or description, and the comments whose
+// content, contain query. Results are grouped as "tasks" and "comments".
+func (c *Client) Search(query string) (map[string][]SearchResult, error) {
+	var apiResp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Results map[string][]SearchResult `json:"results"`
+		} `json:"data"`
+		Message string `json:"message"`
+	}
+
+	if err := c.get("/api/v1/search?"+url.Values{"q": {query}}.Encode(), &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("search failed: %s", apiResp.Message)
+	}
+
+	return apiResp.Data.Results, nil
+}
+
+// GetTaskAttachments retrieves the attachments of a task, including those on its comments
+func (c *Client) GetTaskAttachments(taskID uint) ([]models.Attachment, error) {
+	var apiResp struct {
+		Success bool                `json:"success"`
+		Data    []models.Attachment `json:"data"`
+		Message string              `json:"message"`
+	}
+
+	err := c.get(fmt.Sprintf("/api/v1/tasks/%d/attachments", taskID), &apiResp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get attachments failed: %s", apiResp.Message)
+	}
+
+	return apiResp.Data, nil
+}
+
+// DownloadAttachment streams an attachment to w and returns its original filename.
+// progress, if not nil, is called as data arrives with the bytes written so far
+// and the total size (-1 when the server does not report it).
+func (c *Client) DownloadAttachment(attachmentID uint, w io.Writer, progress func(written, total int64)) (string, error) {
+	var download *jats.Download
+	err := c.withReauth(func() (err error) {
+		download, err = c.api.OpenAttachment(context.Background(), attachmentID)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	defer download.Body.Close()
+
+	reader := io.Reader(download.Body)
+	if progress != nil {
+		reader = &progressReader{reader: download.Body, total: download.Size, progress: progress}
+	}
+
+	if _, err := io.Copy(w, reader); err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+
+	return download.Filename, nil
+}
+
+// DownloadProfile streams a server runtime profile (heap, goroutine, allocs,
+// profile for CPU, ...) in pprof format to w. seconds applies to the CPU profile
+// and trace; 0 uses the server default.
+func (c *Client) DownloadProfile(profile string, seconds int, w io.Writer) error {
+	return c.withReauth(func() error {
+		return c.api.DownloadProfile(context.Background(), profile, seconds, w)
+	})
+}
+
+// EmailSimulation describes what the server would do with an inbound email
+type EmailSimulation = jats.EmailSimulation
+
+// SimulateInboundEmail runs a raw RFC 822 message through the server's inbound
+// email processing without creating anything (admin only)
+func (c *Client) SimulateInboundEmail(raw []byte) (*EmailSimulation, error) {
+	var simulation *EmailSimulation
+	err := c.withReauth(func() (err error) {
+		simulation, err = c.api.SimulateInboundEmail(context.Background(), raw)
+		return err
+	})
+	return simulation, err
+}
+
+// progressReader reports how many bytes have been read from the wrapped reader
+type progressReader struct {
+	reader   io.Reader
+	total    int64
+	read     int64
+	progress func(written, total int64)
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.reader.Read(buf)
+	p.read += int64(n)
+	p.progress(p.read, p.total)
+	return n, err
+}
+
+// GetSubtasks retrieves all subtasks for a task
+func (c *Client) GetSubtasks(taskID uint) ([]Subtask, error) {
+	var apiResp struct {
+		Success bool      `json:"success"`
+		Data    []Subtask `json:"data"`
+		Message string    `json:"message"`
+	}
+
+	err := c.get(fmt.Sprintf("/api/v1/tasks/%d/subtasks", taskID), &apiResp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get subtasks failed: %s", apiResp.Message)
+	}
+
+	return apiResp.Data, nil
+}
+
+// CreateSubtask creates a new subtask for a task
+func (c *Client) CreateSubtask(taskID uint, name string) (*Subtask, error) {
+	req := map[string]interface{}{
+		"name": name,
+	}
+
+	var apiResp struct {
+		Success bool     `json:"success"`
+		Data    Subtask  `json:"data"`
+		Message string   `json:"message"`
+	}
+
+	err := c.post(fmt.Sprintf("/api/v1/tasks/%d/subtasks", taskID), req, &apiResp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("create subtask failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+// UpdateSubtask updates a subtask's name
+func (c *Client) UpdateSubtask(taskID, subtaskID uint, name string) (*Subtask, error) {
+	req := map[string]interface{}{
+		"name": name,
+	}
+
+	var apiResp struct {
+		Success bool     `json:"success"`
+		Data    Subtask  `json:"data"`
+		Message string   `json:"message"`
+	}
+
+	err := c.put(fmt.Sprintf("/api/v1/tasks/%d/subtasks/%d", taskID, subtaskID), req, &apiResp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("update subtask failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+// ToggleSubtask toggles a subtask's completion status
+func (c *Client) ToggleSubtask(taskID, subtaskID uint) (*Subtask, error) {
+	var apiResp struct {
+		Success bool     `json:"success"`
+		Data    Subtask  `json:"data"`
+		Message string   `json:"message"`
+	}
+
+	err := c.patch(fmt.Sprintf("/api/v1/tasks/%d/subtasks/%d/toggle", taskID, subtaskID), nil, &apiResp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("toggle subtask failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+// DeleteSubtask deletes a subtask
+func (c *Client) DeleteSubtask(taskID, subtaskID uint) error {
+	return c.delete(fmt.Sprintf("/api/v1/tasks/%d/subtasks/%d", taskID, subtaskID))
+}
+
+type UpdateTaskRequest struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+type TaskSummaryResponse struct {
+	OpenTasks             int           `json:"open_tasks"`
+	InProgressTasks       int           `json:"in_progress_tasks"`
+	RecentlyAddedTasks    int           `json:"recently_added_tasks"`
+	RecentlyResolvedTasks int           `json:"recently_resolved_tasks"`
+	RecentlyLoggedMinutes int           `json:"recently_logged_minutes"`
+	OverdueTasks          int           `json:"overdue_tasks"`
+	Window                string        `json:"window"` // today, 7d, 30d, quarter or custom
+	From                  time.Time     `json:"from"`
+	To                    time.Time     `json:"to"`
+	Previous              SummaryPeriod `json:"previous"` // the equally long period before the window
+	Trends                SummaryPeriod `json:"trends"`   // window minus the period before
+}
+
+// SummaryPeriod holds the per-period counts of a task summary
+type SummaryPeriod struct {
+	AddedTasks    int `json:"added_tasks"`
+	ResolvedTasks int `json:"resolved_tasks"`
+	LoggedMinutes int `json:"logged_minutes"`
+}
+
+func (c *Client) UpdateTask(taskID uint, req *UpdateTaskRequest) (*Task, error) {
+	var apiResp struct {
+		Success bool `json:"success"`
+		Data    Task `json:"data"`
+		Message string `json:"message"`
+	}
+	
+	err := c.patch(fmt.Sprintf("/api/v1/tasks/%d", taskID), req, &apiResp)
+	if err != nil {
+		return nil, err
+	}
+	
+	if !apiResp.Success {
+		return nil, fmt.Errorf("update task failed: %s", apiResp.Message)
+	}
+	
+	return &apiResp.Data, nil
+}
+
+// CreateTasks creates tasks from parsed quick-add entries in one request,
+// returning them in the same order
+func (c *Client) CreateTasks(entries []*quickadd.Task) ([]models.Task, error) {
+	var apiResp struct {
+		Success bool          `json:"success"`
+		Data    []models.Task `json:"data"`
+		Message string        `json:"message"`
+	}
+
+	if err := c.post("/api/v1/tasks/bulk", map[string]interface{}{"tasks": entries}, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("create tasks failed: %s", apiResp.Message)
+	}
+
+	return apiResp.Data, nil
+}
+
+// ImportTasks creates the tasks of an org-mode or Markdown todo file, with
+// their subtasks; headings is "tags" or "projects"
+func (c *Client) ImportTasks(format, content, headings string) ([]models.Task, error) {
+	var apiResp struct {
+		Success bool          `json:"success"`
+		Data    []models.Task `json:"data"`
+		Message string        `json:"message"`
+	}
+
+	body := map[string]string{"format": format, "content": content, "headings": headings}
+	if err := c.post("/api/v1/tasks/import", body, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("import tasks failed: %s", apiResp.Message)
+	}
+
+	return apiResp.Data, nil
+}
+
+// PatchTask applies a partial update, sending only the given fields
+func (c *Client) PatchTask(taskID uint, updates map[string]interface{}) (*models.Task, error) {
+	var apiResp struct {
+		Success bool        `json:"success"`
+		Data    models.Task `json:"data"`
+		Message string      `json:"message"`
+	}
+
+	if err := c.patch(fmt.Sprintf("/api/v1/tasks/%d", taskID), updates, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("update task failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+func (c *Client) GetTaskSummary(savedQueryID *uint, window string) (*TaskSummaryResponse, error) {
+	query := url.Values{}
+	if savedQueryID != nil {
+		query.Add("saved_query_id", strconv.FormatUint(uint64(*savedQueryID), 10))
+	}
+	if window != "" {
+		query.Add("window", window)
+	}
+	endpoint := "/api/v1/summary/tasks"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var apiResp struct {
+		Success bool                 `json:"success"`
+		Data    TaskSummaryResponse  `json:"data"`
+		Message string               `json:"message"`
+	}
+
+	err := c.get(endpoint, &apiResp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !apiResp.Success {
+		return nil, fmt.Errorf("get task summary failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+// GetTimeBreakdownReport returns the time logged per day and saved query; a
+// non-nil billable only counts billable or non-billable time
+func (c *Client) GetTimeBreakdownReport(startDate, endDate, queryIDs, excludeTags string, billable *bool) (*TimeBreakdownReport, error) {
+	query := url.Values{}
+	query.Add("start_date", startDate)
+	query.Add("end_date", endDate)
+	query.Add("saved_query_ids", queryIDs)
 	if excludeTags != "" {
 		query.Add("excluded_tags", excludeTags)
 	}
+	if billable != nil {
+		query.Add("billable", strconv.FormatBool(*billable))
+	}
 
 	endpoint := "/api/v1/reports/time-breakdown?" + query.Encode()
 
@@ -721,58 +1723,13 @@ func (c *Client) Delete(endpoint string) error {
 }
 
 func (c *Client) request(method, endpoint string, body interface{}, response interface{}) error {
-	return c.requestWithRetry(method, endpoint, body, response, false)
-}
-
-func (c *Client) requestWithRetry(method, endpoint string, body interface{}, response interface{}, isRetry bool) error {
-	var reqBody io.Reader
-
-	if body != nil {
-		jsonData, err := json.Marshal(body)
-		if err != nil {
-			return fmt.Errorf("failed to marshal request: %w", err)
-		}
-		reqBody = bytes.NewBuffer(jsonData)
-	}
-
-	req, err := http.NewRequest(method, c.baseURL+endpoint, reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-
-	// Use session token if available (will be treated as Bearer token)
-	if c.apiToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.apiToken)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	var respBody []byte
+	err := c.withReauth(func() (err error) {
+		respBody, err = c.api.Raw(context.Background(), method, endpoint, body)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Handle 401 Unauthorized - prompt for re-authentication
-	if resp.StatusCode == 401 && !isRetry && endpoint != "/api/v1/auth/login" {
-		// Prompt user to re-authenticate
-		if err := c.promptReauth(); err != nil {
-			return fmt.Errorf("re-authentication failed: %w", err)
-		}
-
-		// Retry the request once after successful re-authentication
-		return c.requestWithRetry(method, endpoint, body, response, true)
-	}
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBody))
+		return err
 	}
 
 	if response != nil && len(respBody) > 0 {
@@ -784,30 +1741,22 @@ func (c *Client) requestWithRetry(method, endpoint string, body interface{}, res
 	return nil
 }
 
-// ParseDuration parses duration strings like "30m", "1h", "2h30m" and returns minutes
-func ParseDuration(duration string) (int, error) {
-	// Try parsing as Go duration first
-	if d, err := time.ParseDuration(duration); err == nil {
-		return int(d.Minutes()), nil
-	}
-
-	// Handle formats like "1.5h" or just numbers (assume minutes)
-	if strings.Contains(duration, ".") {
-		// Try parsing as decimal hours
-		if strings.HasSuffix(duration, "h") {
-			hourStr := strings.TrimSuffix(duration, "h")
-			if hours, err := strconv.ParseFloat(hourStr, 64); err == nil {
-				return int(hours * 60), nil
-			}
+// withReauth runs an API call, and when the session has expired prompts the
+// user to log in again and retries it once
+func (c *Client) withReauth(call func() error) error {
+	err := call()
+	if errors.Is(err, jats.ErrUnauthorized) {
+		if err := c.promptReauth(); err != nil {
+			return fmt.Errorf("re-authentication failed: %w", err)
 		}
+		return call()
 	}
+	return err
+}
 
-	// If just a number, assume minutes
-	if num, err := strconv.Atoi(duration); err == nil {
-		return num, nil
-	}
-
-	return 0, fmt.Errorf("invalid duration format: %s (examples: 30m, 1h, 2h30m, 1.5h, or just 30 for minutes)", duration)
+// ParseDuration parses duration strings like "30m", "1h", "2h30m" and returns minutes
+func ParseDuration(duration string) (int, error) {
+	return utils.ParseDuration(duration)
 }
 
 func min(a, b int) int {
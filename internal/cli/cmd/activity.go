@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/soarinferret/jats/internal/cli/client"
+)
+
+var (
+	activitySince string
+	activityUntil string
+	activityTypes []string
+	activityTask  uint
+	activityTag   string
+	activityLimit int
+)
+
+var activityLabels = map[string]string{
+	"created":        "created",
+	"status_changed": "status",
+	"commented":      "note",
+	"time_logged":    "time",
+	"assigned":       "assign",
+	"reviewed":       "review",
+}
+
+var activityCmd = &cobra.Command{
+	Use:   "activity",
+	Short: "Show recent activity across all tasks",
+	Long: `Show what happened across all tasks: tasks created, status changes,
+notes added, time logged, auto-assignments and reviews, newest first.
+Defaults to the last 7 days.
+
+Activity types: created, status_changed, commented, time_logged, assigned,
+reviewed
+
+Examples:
+  jats activity --since yesterday
+  jats activity --since "last monday" --until yesterday
+  jats activity --type status_changed,time_logged --tag client1
+  jats activity --task 42`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, err := resolveDate(activitySince)
+		if err != nil {
+			return err
+		}
+		until, err := resolveDate(activityUntil)
+		if err != nil {
+			return err
+		}
+
+		c := client.New()
+
+		events, total, err := c.GetActivity(&client.ActivityFilters{
+			Since:  since,
+			Until:  until,
+			Types:  activityTypes,
+			TaskID: activityTask,
+			Tag:    activityTag,
+			Limit:  activityLimit,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get activity: %w", err)
+		}
+
+		if len(events) == 0 {
+			fmt.Println("No activity in this period")
+			return nil
+		}
+
+		// Print oldest first so the output reads like a log
+		fmt.Println()
+		for i := len(events) - 1; i >= 0; i-- {
+			fmt.Println(formatActivityLine(events[i]))
+		}
+
+		if total > len(events) {
+			fmt.Printf("\nShowing the latest %d of %d events (use --limit to see more)\n", len(events), total)
+		}
+		fmt.Println()
+
+		return nil
+	},
+}
+
+// formatActivityLine renders an activity event as a single log-style line
+func formatActivityLine(event client.ActivityEvent) string {
+	detail := event.Detail
+	if event.Type == "time_logged" {
+		detail = strings.TrimSpace(formatDurationDisplay(time.Duration(event.Minutes)*time.Minute) + " " + detail)
+	}
+	line := fmt.Sprintf("%s  %-7s  #%-5d %s", event.Timestamp.Local().Format("2006-01-02 15:04"),
+		activityLabels[event.Type], event.TaskID, truncate(event.TaskName, 50))
+	if detail != "" {
+		line += "  — " + truncate(detail, 60)
+	}
+	return line
+}
+
+func init() {
+	rootCmd.AddCommand(activityCmd)
+	activityCmd.Flags().StringVar(&activitySince, "since", "", "Start date (yesterday, \"last monday\", -2d, 2025-12-01; default: last 7 days)")
+	activityCmd.Flags().StringVar(&activityUntil, "until", "", "End date, inclusive")
+	activityCmd.Flags().StringSliceVar(&activityTypes, "type", nil, "Only these activity types (comma separated)")
+	activityCmd.Flags().UintVar(&activityTask, "task", 0, "Only activity on this task")
+	activityCmd.Flags().StringVar(&activityTag, "tag", "", "Only activity on tasks with this tag")
+	activityCmd.Flags().IntVarP(&activityLimit, "limit", "l", 50, "Maximum number of events")
+}
@@ -1,20 +1,37 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
+	"regexp"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/soarinferret/jats/internal/cli/client"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/quickadd"
+	"github.com/soarinferret/jats/internal/utils"
 )
 
 var (
-	priority  string
-	timeSpent string
-	completed bool
-	date      string
+	priority    string
+	timeSpent   string
+	completed   bool
+	date        string
+	dueDate     string
+	taskContext string
+	taskSize    string
+	fromFile    string
+	fromStdin   bool
 )
 
+// addBatchSize is how many tasks one bulk request creates, matching the server limit
+const addBatchSize = 1000
+
 var addCmd = &cobra.Command{
 	Use:   "add <task name>",
 	Short: "Create a new task",
@@ -27,29 +44,58 @@ Task names can include inline tags:
 Workflow flags:
   -t      - Log time immediately (30m, 1h, 2h30m, etc.)
   -c      - Mark task as resolved after creation
-  -d      - Set creation date (-1d, +2w, 2025-12-01)
+  -d      - Set creation date (-1d, 2025-12-01, yesterday, "last friday")
+  --due   - Set a due date (tomorrow, 2025-12-15, "next friday")
+  --context - Set where the task can be done (@home, @office, @datacenter)
+  --size  - Set the effort (XS, S, M, L, XL or story points)
 
 Examples:
   jats add Fix authentication bug
   jats add Update documentation +docs +urgent --priority high
   jats add @client1 restart +docker container -t 45m -c
   jats add testing new +framework -t 30m -c -d -1d
-  jats add "Fix bug with spaces" -t 1h -d 2025-12-01`,
-	Args: cobra.MinimumNArgs(1),
+  jats add "Fix bug with spaces" -t 1h -d 2025-12-01
+  jats add Quarterly review +reports -d "last monday"
+  jats add Send invoices +billing --due "next friday"
+  jats add Swap failed disk +client1 --context @datacenter
+  jats add Migrate the mail server +client2 --size XL
+
+Batch mode creates one task per line, using the same syntax, in a single
+request. Blank lines and lines starting with # are skipped, and markdown list
+markers are stripped ("- [x] ..." marks the task resolved). Flags apply to
+every task:
+  jats add --from-file tasks.md
+  cat tasks.txt | jats add --stdin @imported`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if fromFile != "" || fromStdin {
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Join all arguments to form the full task name
-		fullTaskName := strings.Join(args, " ")
-		
-		// Parse inline tags from the task name
-		taskName, tags := parseTaskNameAndTags(fullTaskName)
+		if fromFile != "" || fromStdin {
+			return addBatch(args)
+		}
+
+		// Parse inline tags, plus any quick-add flags inside quoted arguments
+		entry, err := quickadd.Parse(strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		if err := applyAddFlags(entry); err != nil {
+			return err
+		}
 
 		c := client.New()
 		
 		req := &client.CreateTaskRequest{
-			Name:     taskName,
-			Priority: priority,
-			Tags:     tags,
-			Date:     date,
+			Name:     entry.Name,
+			Priority: string(entry.Priority),
+			Tags:     entry.Tags,
+			Date:     entry.Date,
+			DueDate:  entry.DueDate,
+			Context:  entry.Context,
+			Size:     string(entry.Size),
 		}
 
 		task, err := c.CreateTask(req)
@@ -57,25 +103,29 @@ Examples:
 			return fmt.Errorf("failed to create task: %w", err)
 		}
 
-		fmt.Printf("✓ Created task #%d: %s\n", task.ID, task.Name)
+		fmt.Println(tr("cli_task_created", map[string]interface{}{"ID": task.ID, "Name": task.Name}))
 		if len(task.Tags) > 0 {
-			fmt.Printf("  Tags: %s\n", strings.Join(task.Tags, ", "))
+			fmt.Printf("  %s: %s\n", tr("cli_label_tags"), strings.Join(task.Tags, ", "))
 		}
 		if task.Priority != "" {
-			fmt.Printf("  Priority: %s\n", task.Priority)
+			fmt.Printf("  %s: %s\n", tr("cli_label_priority"), localizer().Priority(string(task.Priority)))
+		}
+		if task.DueDate != nil {
+			fmt.Printf("  Due: %s\n", task.DueDate.Format("2006-01-02"))
+		}
+		if task.Context != "" {
+			fmt.Printf("  Context: %s\n", task.Context)
+		}
+		if task.Size != "" {
+			fmt.Printf("  Size: %s\n", task.Size)
 		}
 
 		// Log time if specified
-		if timeSpent != "" {
-			durationMinutes, err := client.ParseDuration(timeSpent)
-			if err != nil {
-				return fmt.Errorf("invalid duration format: %w", err)
-			}
-
+		if entry.Duration > 0 {
 			timeReq := &client.LogTimeRequest{
-				Duration:    durationMinutes,
+				Duration:    entry.Duration,
 				Description: "Time logged during task creation",
-				Date:        date,
+				Date:        entry.Date,
 			}
 
 			err = c.LogTime(task.ID, timeReq)
@@ -83,11 +133,11 @@ Examples:
 				return fmt.Errorf("failed to log time: %w", err)
 			}
 
-			fmt.Printf("  ⏱️  Logged %s\n", timeSpent)
+			fmt.Printf("  ⏱️  Logged %s\n", formatDurationDisplay(time.Duration(entry.Duration)*time.Minute))
 		}
 
 		// Mark as completed if specified
-		if completed {
+		if entry.Complete {
 			updatedTask, err := c.UpdateTaskStatus(task.ID, "resolved")
 			if err != nil {
 				return fmt.Errorf("failed to mark task as completed: %w", err)
@@ -100,35 +150,153 @@ Examples:
 	},
 }
 
-// parseTaskNameAndTags extracts tags from task name and returns cleaned name and tags
-func parseTaskNameAndTags(input string) (string, []string) {
-	words := strings.Fields(input)
-	var cleanWords []string
-	var tags []string
-	
-	for _, word := range words {
-		if strings.HasPrefix(word, "+") {
-			// +tag format: add tag and remove + from name
-			tag := strings.TrimPrefix(word, "+")
-			if tag != "" {
-				tags = append(tags, tag)
-				cleanWords = append(cleanWords, tag) // Add the tag word without + to the name
+// applyAddFlags applies the command-line flags to a parsed entry; they take
+// precedence over inline ones
+func applyAddFlags(entry *quickadd.Task) error {
+	var err error
+	if priority != "" {
+		entry.Priority = models.TaskPriority(priority)
+	}
+	if date != "" {
+		if entry.Date, err = resolveDate(date); err != nil {
+			return err
+		}
+	}
+	if dueDate != "" {
+		if entry.DueDate, err = resolveDate(dueDate); err != nil {
+			return err
+		}
+	}
+	if taskContext != "" {
+		entry.Context = taskContext
+	}
+	if taskSize != "" {
+		entry.Size = models.TaskSize(strings.ToUpper(strings.TrimSpace(taskSize)))
+	}
+	if timeSpent != "" {
+		if entry.Duration, err = client.ParseDuration(timeSpent); err != nil {
+			return fmt.Errorf("invalid duration format: %w", err)
+		}
+	}
+	if completed {
+		entry.Complete = true
+	}
+	return nil
+}
+
+// addBatch creates a task for every line of the --from-file or --stdin input.
+// Extra arguments (e.g. @tag) are appended to every line.
+func addBatch(args []string) error {
+	if fromFile != "" && fromStdin {
+		return fmt.Errorf("use either --from-file or --stdin, not both")
+	}
+
+	var input io.Reader = os.Stdin
+	if fromFile != "" && fromFile != "-" {
+		file, err := os.Open(fromFile)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", fromFile, err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	entries, err := parseBatchLines(input, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no tasks found in input")
+	}
+
+	c := client.New()
+
+	var created []models.Task
+	for start := 0; start < len(entries); start += addBatchSize {
+		end := min(start+addBatchSize, len(entries))
+		tasks, err := c.CreateTasks(entries[start:end])
+		if err != nil {
+			if len(created) > 0 {
+				printCreatedTasks(created)
 			}
-		} else if strings.HasPrefix(word, "@") {
-			// @tag format: add tag but remove entire @tag from name
-			tag := strings.TrimPrefix(word, "@")
-			if tag != "" {
-				tags = append(tags, tag)
+			return fmt.Errorf("failed to create tasks %d-%d: %w", start+1, end, err)
+		}
+		created = append(created, tasks...)
+	}
+
+	printCreatedTasks(created)
+	fmt.Printf("\n✓ Created %d tasks\n", len(created))
+	return nil
+}
+
+// listMarker matches markdown list and checkbox prefixes such as "- ", "* [ ] " and "1. "
+var listMarker = regexp.MustCompile(`^(?:[-*]|\d+[.)])\s+(?:\[([ xX])\]\s+)?`)
+
+// parseBatchLines parses one quick-add entry per line, reporting every invalid
+// line at once so nothing is created from a broken file
+func parseBatchLines(input io.Reader, suffix string) ([]*quickadd.Task, error) {
+	var entries []*quickadd.Task
+	var problems []string
+
+	scanner := bufio.NewScanner(input)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		checked := false
+		if match := listMarker.FindStringSubmatch(line); match != nil {
+			checked = strings.EqualFold(match[1], "x")
+			line = strings.TrimSpace(line[len(match[0]):])
+			if line == "" {
+				continue
 			}
-			// Don't add this word to cleanWords (it gets removed from name)
-		} else {
-			// Regular word, keep in name
-			cleanWords = append(cleanWords, word)
 		}
+
+		entry, err := quickadd.Parse(strings.TrimSpace(line + " " + suffix))
+		if err == nil {
+			err = applyAddFlags(entry)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", lineNumber, err))
+			continue
+		}
+		entry.Complete = entry.Complete || checked
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid tasks, nothing was created:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	return entries, nil
+}
+
+// printCreatedTasks prints a table of created tasks
+func printCreatedTasks(tasks []models.Task) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tNAME\tSTATUS\tPRIORITY\tTAGS\n")
+	for _, task := range tasks {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", task.ID, truncate(task.Name, 50), task.Status, task.Priority, strings.Join(task.Tags, ", "))
+	}
+	w.Flush()
+}
+
+// resolveDate turns a date flag such as "tomorrow" or "-1d" into YYYY-MM-DD so
+// it is interpreted in the user's local time zone rather than the server's
+func resolveDate(value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+
+	parsed, err := utils.ParseDate(value)
+	if err != nil {
+		return "", err
 	}
-	
-	cleanName := strings.Join(cleanWords, " ")
-	return strings.TrimSpace(cleanName), tags
+	return parsed.Format("2006-01-02"), nil
 }
 
 func init() {
@@ -136,5 +304,10 @@ func init() {
 	addCmd.Flags().StringVarP(&priority, "priority", "p", "", "Priority level (low, medium, high)")
 	addCmd.Flags().StringVarP(&timeSpent, "time", "t", "", "Log time immediately (30m, 1h, 2h30m, etc.)")
 	addCmd.Flags().BoolVarP(&completed, "complete", "c", false, "Mark task as resolved after creation")
-	addCmd.Flags().StringVarP(&date, "date", "d", "", "Creation date (-1d, +2w, 2025-12-01)")
+	addCmd.Flags().StringVarP(&date, "date", "d", "", "Creation date (-1d, 2025-12-01, yesterday, \"last friday\")")
+	addCmd.Flags().StringVar(&dueDate, "due", "", "Due date (tomorrow, 2025-12-15, \"next friday\")")
+	addCmd.Flags().StringVar(&taskContext, "context", "", "Context where the task can be done (@home, @office, @datacenter)")
+	addCmd.Flags().StringVar(&taskSize, "size", "", "Effort in t-shirt sizes (XS, S, M, L, XL) or story points")
+	addCmd.Flags().StringVar(&fromFile, "from-file", "", "Create one task per line of this file (- for stdin)")
+	addCmd.Flags().BoolVar(&fromStdin, "stdin", false, "Create one task per line read from stdin")
 }
\ No newline at end of file
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+func TestParseBatchLines(t *testing.T) {
+	input := `# Sprint backlog
+
+- [ ] Fix login +auth -p high
+- [x] Write release notes
+* Call @client1 about invoices
+1. Restart +docker container -t 30m
+plain line
+`
+
+	entries, err := parseBatchLines(strings.NewReader(input), "@imported")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 entries, got %d", len(entries))
+	}
+
+	if entries[0].Name != "Fix login auth" || entries[0].Priority != models.TaskPriorityHigh || entries[0].Complete {
+		t.Errorf("Unexpected first entry %+v", entries[0])
+	}
+	if entries[1].Name != "Write release notes" || !entries[1].Complete {
+		t.Errorf("Expected checked item to be resolved, got %+v", entries[1])
+	}
+	if entries[2].Name != "Call about invoices" {
+		t.Errorf("Expected list marker stripped, got %q", entries[2].Name)
+	}
+	if entries[3].Duration != 30 {
+		t.Errorf("Expected 30 minutes logged, got %d", entries[3].Duration)
+	}
+	for _, entry := range entries {
+		if len(entry.Tags) == 0 || entry.Tags[len(entry.Tags)-1] != "imported" {
+			t.Errorf("Expected every entry tagged imported, got %v", entry.Tags)
+		}
+	}
+}
+
+func TestParseBatchLinesReportsEveryInvalidLine(t *testing.T) {
+	_, err := parseBatchLines(strings.NewReader("Good task\n-p high\nBad time -t soon\n"), "")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if !strings.Contains(err.Error(), "line 2") || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected both invalid lines reported, got %v", err)
+	}
+}
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"text/tabwriter"
+	"time"
 
 	"golang.org/x/term"
 	"github.com/spf13/cobra"
@@ -80,7 +83,7 @@ Examples:
 			return fmt.Errorf("failed to create user: %w", err)
 		}
 
-		fmt.Printf("✓ User '%s' created successfully (ID: %v)\n", username, resp["data"].(map[string]interface{})["id"])
+		fmt.Println(tr("cli_user_created", map[string]interface{}{"Username": username, "ID": resp["data"].(map[string]interface{})["id"]}))
 		return nil
 	},
 }
@@ -225,7 +228,7 @@ Examples:
 			return fmt.Errorf("failed to update user: %w", err)
 		}
 
-		fmt.Printf("✓ User %s updated successfully\n", userID)
+		fmt.Println(tr("cli_user_updated", map[string]interface{}{"ID": userID}))
 		return nil
 	},
 }
@@ -255,7 +258,7 @@ var userDeleteCmd = &cobra.Command{
 			return fmt.Errorf("failed to delete user: %w", err)
 		}
 
-		fmt.Printf("✓ User %s deleted successfully\n", userID)
+		fmt.Println(tr("cli_user_deleted", map[string]interface{}{"ID": userID}))
 		return nil
 	},
 }
@@ -292,7 +295,146 @@ var userResetPasswordCmd = &cobra.Command{
 			return fmt.Errorf("failed to reset password: %w", err)
 		}
 
-		fmt.Printf("✓ Password reset successfully for user %s\n", userID)
+		fmt.Println(tr("cli_password_reset", map[string]interface{}{"ID": userID}))
+		return nil
+	},
+}
+
+// Profile download variables
+var (
+	profileOutput  string
+	profileSeconds int
+)
+
+var adminProfileCmd = &cobra.Command{
+	Use:   "profile [heap|goroutine|allocs|block|mutex|threadcreate|cpu|trace]",
+	Short: "Save a server runtime profile for a support ticket",
+	Long: `Fetch a pprof profile from the server and save it to a file, for attaching
+to support tickets or inspecting with "go tool pprof". Defaults to the heap
+profile. cpu and trace record for --seconds before returning.
+
+Examples:
+  jats admin profile
+  jats admin profile goroutine -o goroutines.pb.gz
+  jats admin profile cpu --seconds 20`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile := "heap"
+		if len(args) > 0 {
+			profile = args[0]
+		}
+		name := profile
+		if profile == "cpu" {
+			// The server uses pprof's name for the CPU profile
+			name = "profile"
+		}
+
+		output := profileOutput
+		if output == "" {
+			ext := ".pb.gz"
+			if profile == "trace" {
+				ext = ".trace"
+			}
+			output = fmt.Sprintf("jats-%s-%s%s", profile, time.Now().Format("20060102-150405"), ext)
+		}
+		if _, err := os.Stat(output); err == nil {
+			return fmt.Errorf("%s already exists", output)
+		}
+
+		// Download into a temporary file first so failed downloads leave nothing behind
+		tmp, err := os.CreateTemp(filepath.Dir(output), ".jats-profile-*")
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+
+		if name == "profile" || name == "trace" {
+			seconds := profileSeconds
+			if seconds == 0 {
+				seconds = 30
+			}
+			fmt.Fprintf(os.Stderr, "Recording %s for %ds...\n", profile, seconds)
+		}
+
+		c := client.New()
+		err = c.DownloadProfile(name, profileSeconds, tmp)
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s profile: %w", profile, err)
+		}
+		if err := os.Rename(tmp.Name(), output); err != nil {
+			return fmt.Errorf("failed to save %s: %w", output, err)
+		}
+
+		fmt.Println(tr("cli_profile_saved", map[string]interface{}{"Profile": profile, "Path": output}))
+		return nil
+	},
+}
+
+var adminEmailSimulateCmd = &cobra.Command{
+	Use:   "email-simulate <file|->",
+	Short: "Show what the server would do with an inbound email",
+	Long: `Send a raw email (.eml) to the server and show the task, comment and
+attachments its inbound email processing would create from it, without
+creating anything or touching the mailbox. Use - to read from stdin.
+
+Examples:
+  jats admin email-simulate customer.eml
+  cat customer.eml | jats admin email-simulate -`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var raw []byte
+		var err error
+		if args[0] == "-" {
+			raw, err = io.ReadAll(os.Stdin)
+		} else {
+			raw, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read message: %w", err)
+		}
+
+		c := client.New()
+		sim, err := c.SimulateInboundEmail(raw)
+		if err != nil {
+			return fmt.Errorf("failed to simulate email: %w", err)
+		}
+
+		fmt.Printf("From:    %s\n", sim.From)
+		fmt.Printf("Subject: %s\n", sim.Subject)
+		if sim.InReplyTo != "" {
+			fmt.Printf("Reply to: %s\n", sim.InReplyTo)
+		}
+		if sim.Spam != nil {
+			fmt.Printf("Spam:    %s (score %.1f)\n", sim.Spam.Verdict, sim.Spam.Score)
+		}
+		fmt.Println()
+
+		switch sim.Outcome {
+		case "create_task":
+			fmt.Println(tr("cli_simulate_would_create", map[string]interface{}{"Name": sim.TaskName}))
+		case "comment":
+			fmt.Println(tr("cli_simulate_would_comment", map[string]interface{}{"ID": sim.TaskID, "Name": sim.TaskName}))
+		default:
+			fmt.Println(tr("cli_simulate_would_be", map[string]interface{}{"Outcome": sim.Outcome}))
+		}
+		if sim.Reason != "" {
+			fmt.Printf("  %s\n", sim.Reason)
+		}
+		if sim.Sender != "" {
+			fmt.Printf("  Sent by %s\n", sim.Sender)
+		}
+		if sim.Comment != "" {
+			fmt.Printf("\nComment:\n%s\n", strings.TrimSpace(sim.Comment))
+		}
+		if len(sim.Attachments) > 0 {
+			fmt.Printf("\nAttachments:\n")
+			for _, attachment := range sim.Attachments {
+				fmt.Printf("  %s (%s, %d bytes) on the %s\n", attachment.Filename, attachment.ContentType, attachment.Size, attachment.AttachedTo)
+			}
+		}
 		return nil
 	},
 }
@@ -303,6 +445,8 @@ func init() {
 	
 	// Add user subcommand to admin
 	adminCmd.AddCommand(userCmd)
+	adminCmd.AddCommand(adminProfileCmd)
+	adminCmd.AddCommand(adminEmailSimulateCmd)
 	
 	// Add user management commands
 	userCmd.AddCommand(userCreateCmd)
@@ -320,4 +464,8 @@ func init() {
 	userUpdateCmd.Flags().StringVar(&userEmail, "email", "", "New email address")
 	userUpdateCmd.Flags().BoolVar(&userActive, "active", false, "Set user as active")
 	userUpdateCmd.Flags().BoolVar(&userInactive, "inactive", false, "Set user as inactive")
-}
\ No newline at end of file
+
+	// Flags for profile
+	adminProfileCmd.Flags().StringVarP(&profileOutput, "output", "o", "", "Output file (default: jats-<profile>-<timestamp>.pb.gz)")
+	adminProfileCmd.Flags().IntVar(&profileSeconds, "seconds", 0, "Recording time for cpu and trace (server default 30, max 60)")
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"github.com/soarinferret/jats/internal/cli/client"
+)
+
+var (
+	attachmentOutput string
+	attachmentForce  bool
+)
+
+var attachmentsCmd = &cobra.Command{
+	Use:   "attachments <task-id>",
+	Short: "List attachments of a task",
+	Long: `List the files attached to a task, including those on its notes.
+
+Examples:
+  jats attachments 42
+  jats attachments get 17 -o report.pdf`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := client.New()
+
+		var taskID uint
+		if _, err := fmt.Sscanf(args[0], "%d", &taskID); err != nil {
+			return fmt.Errorf("invalid task ID: %s", args[0])
+		}
+
+		attachments, err := c.GetTaskAttachments(taskID)
+		if err != nil {
+			return fmt.Errorf("failed to get attachments: %w", err)
+		}
+
+		if len(attachments) == 0 {
+			fmt.Printf("No attachments on task #%d\n", taskID)
+			return nil
+		}
+
+		fmt.Printf("\nAttachments on task #%d:\n", taskID)
+		fmt.Printf("%-6s | %-40s | %-10s | %-30s | %-16s\n", "ID", "Name", "Size", "Type", "Added")
+		fmt.Printf("%s\n", "-------------------------------------------------------------------------------------------------------------------")
+
+		for _, a := range attachments {
+			size := "-"
+			if a.Size > 0 {
+				size = formatBytes(a.Size)
+			}
+			fmt.Printf("%-6d | %-40s | %-10s | %-30s | %-16s\n",
+				a.ID, truncate(a.OriginalName, 40), size, truncate(a.ContentType, 30), a.CreatedAt.Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("\nDownload with: jats attachments get <id> [-o file]\n\n")
+
+		return nil
+	},
+}
+
+var attachmentsGetCmd = &cobra.Command{
+	Use:   "get <attachment-id>",
+	Short: "Download an attachment",
+	Long: `Download an attachment by ID. The file is saved under its original name
+in the current directory unless -o is given. Use -o - to write to stdout.
+
+Examples:
+  jats attachments get 17
+  jats attachments get 17 -o report.pdf
+  jats attachments get 17 -o - | less`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := client.New()
+
+		var attachmentID uint
+		if _, err := fmt.Sscanf(args[0], "%d", &attachmentID); err != nil {
+			return fmt.Errorf("invalid attachment ID: %s", args[0])
+		}
+
+		if attachmentOutput == "-" {
+			_, err := c.DownloadAttachment(attachmentID, os.Stdout, nil)
+			return err
+		}
+
+		// Download into a temporary file first so failed downloads leave nothing behind
+		tmp, err := os.CreateTemp(".", ".jats-download-*")
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+
+		var progress func(written, total int64)
+		if term.IsTerminal(int(os.Stderr.Fd())) {
+			progress = printDownloadProgress
+		}
+
+		filename, err := c.DownloadAttachment(attachmentID, tmp, progress)
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if progress != nil {
+			fmt.Fprintln(os.Stderr)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to download attachment: %w", err)
+		}
+
+		output := attachmentOutput
+		if output == "" {
+			// Never trust a server-supplied name to pick the directory
+			output = filepath.Base(filename)
+			if output == "." || output == string(filepath.Separator) || output == "" {
+				output = fmt.Sprintf("attachment-%d", attachmentID)
+			}
+		}
+
+		if _, err := os.Stat(output); err == nil && !attachmentForce {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", output)
+		}
+		if err := os.Rename(tmp.Name(), output); err != nil {
+			return fmt.Errorf("failed to save %s: %w", output, err)
+		}
+
+		fmt.Println(tr("cli_attachment_saved", map[string]interface{}{"ID": attachmentID, "Path": output}))
+		return nil
+	},
+}
+
+// printDownloadProgress renders a single-line progress indicator on stderr
+func printDownloadProgress(written, total int64) {
+	if total > 0 {
+		fmt.Fprintf(os.Stderr, "\rDownloading... %s / %s (%d%%)", formatBytes(written), formatBytes(total), written*100/total)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\rDownloading... %s", formatBytes(written))
+}
+
+// truncate shortens s to at most max runes, marking the cut with an ellipsis
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// formatBytes formats a byte count using binary units, e.g. 1.5 MB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for value := n / unit; value >= unit; value /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	rootCmd.AddCommand(attachmentsCmd)
+	attachmentsCmd.AddCommand(attachmentsGetCmd)
+	attachmentsGetCmd.Flags().StringVarP(&attachmentOutput, "output", "o", "", "Output file (default: original filename, - for stdout)")
+	attachmentsGetCmd.Flags().BoolVarP(&attachmentForce, "force", "f", false, "Overwrite an existing file")
+}
@@ -5,11 +5,13 @@ import (
 	"os"
 	"strings"
 	"syscall"
+	"time"
 
 	"golang.org/x/term"
 	"github.com/spf13/cobra"
 	"github.com/soarinferret/jats/internal/cli/client"
 	"github.com/soarinferret/jats/internal/cli/config"
+	"github.com/soarinferret/jats/internal/cli/keyring"
 )
 
 var authCmd = &cobra.Command{
@@ -79,7 +81,7 @@ Examples:
 			}
 		}
 
-		fmt.Printf("✓ Logged in successfully as %s (%s)\n", resp.User.Username, resp.User.Email)
+		fmt.Println(tr("cli_logged_in", map[string]interface{}{"Username": resp.User.Username, "Email": resp.User.Email}))
 		return nil
 	},
 }
@@ -93,13 +95,14 @@ var logoutCmd = &cobra.Command{
 		if cfg != nil {
 			cfg.Username = ""
 			cfg.Token = ""
+			cfg.TokenExpiresAt = nil
 			
 			if err := config.Save(cfg, ""); err != nil {
 				return fmt.Errorf("failed to save config: %w", err)
 			}
 		}
 
-		fmt.Println("✓ Logged out successfully")
+		fmt.Println(tr("cli_logged_out"))
 		return nil
 	},
 }
@@ -119,11 +122,26 @@ var statusCmd = &cobra.Command{
 		
 		if cfg.Username != "" && cfg.Token != "" {
 			fmt.Printf("Logged in as: %s\n", cfg.Username)
-			fmt.Println("Status: Authenticated (session token)")
+			if cfg.TokenExpiresAt != nil && time.Now().After(*cfg.TokenExpiresAt) {
+				fmt.Printf("Status: Session expired %s (run 'jats auth login')\n", cfg.TokenExpiresAt.Local().Format("2006-01-02 15:04"))
+			} else {
+				fmt.Println("Status: Authenticated (session token)")
+				if cfg.TokenExpiresAt != nil {
+					fmt.Printf("Expires: %s (in %s)\n", cfg.TokenExpiresAt.Local().Format("2006-01-02 15:04"),
+						formatDurationDisplay(time.Until(*cfg.TokenExpiresAt).Truncate(time.Minute)))
+				}
+			}
+			fmt.Printf("Credentials: %s\n", cfg.TokenStore())
 		} else {
 			fmt.Println("Status: Not logged in")
 		}
 
+		if backend := keyring.Backend(); backend != "" {
+			fmt.Printf("OS keyring: %s\n", backend)
+		} else {
+			fmt.Println("OS keyring: not available (tokens are kept in the config file)")
+		}
+
 		return nil
 	},
 }
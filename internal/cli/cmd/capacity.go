@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/soarinferret/jats/internal/cli/client"
+)
+
+var capacitySet string
+
+var capacityCmd = &cobra.Command{
+	Use:   "capacity",
+	Short: "Compare remaining estimated work with your weekly capacity",
+	Long: `Show the estimated work left on open and in-progress tasks (subtask
+estimates minus logged time) against your weekly capacity for the coming week,
+and flag overcommitment.
+
+Examples:
+  jats capacity
+  jats capacity --set 30h
+  jats capacity --set 0     # clear capacity`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := client.New()
+
+		if capacitySet != "" {
+			if err := c.SetWeeklyCapacity(capacitySet); err != nil {
+				return fmt.Errorf("failed to set capacity: %w", err)
+			}
+			fmt.Println(tr("cli_capacity_set", map[string]interface{}{"Capacity": capacitySet}))
+		}
+
+		plan, err := c.GetCapacityPlan()
+		if err != nil {
+			return fmt.Errorf("failed to get capacity plan: %w", err)
+		}
+
+		minutes := func(m int) string {
+			return formatDurationDisplay(time.Duration(m) * time.Minute)
+		}
+
+		fmt.Printf("\n%s\n\n", tr("cli_capacity_heading", map[string]interface{}{"Start": plan.WeekStart, "End": plan.WeekEnd}))
+		for _, task := range plan.Tasks {
+			fmt.Printf("  #%-5d %-50s %8s\n", task.ID, truncate(task.Name, 50), minutes(task.RemainingMinutes))
+		}
+		if len(plan.Tasks) > 0 {
+			fmt.Println()
+		}
+
+		fmt.Println(tr("cli_capacity_remaining", map[string]interface{}{"Duration": minutes(plan.RemainingMinutes)}))
+		switch {
+		case plan.CapacityMinutes == 0:
+			fmt.Println(tr("cli_capacity_not_set"))
+		case plan.Overcommitted:
+			fmt.Println(tr("cli_capacity_total", map[string]interface{}{"Duration": minutes(plan.CapacityMinutes)}))
+			fmt.Println(tr("cli_capacity_over", map[string]interface{}{"Duration": minutes(plan.OverByMinutes)}))
+		default:
+			fmt.Println(tr("cli_capacity_total", map[string]interface{}{"Duration": minutes(plan.CapacityMinutes)}))
+			fmt.Println(tr("cli_capacity_free", map[string]interface{}{"Duration": minutes(plan.CapacityMinutes - plan.RemainingMinutes)}))
+		}
+		if plan.UnestimatedTasks > 0 {
+			fmt.Printf("\n%s\n", localizer().N("cli_capacity_unestimated", plan.UnestimatedTasks, nil))
+		}
+		fmt.Println()
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(capacityCmd)
+	capacityCmd.Flags().StringVar(&capacitySet, "set", "", "Set your weekly capacity (e.g. 30h, 0 to clear)")
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/soarinferret/jats/internal/cli/client"
+)
+
+var commentCanned string
+
+var commentCmd = &cobra.Command{
+	Use:   "comment <task-id> [note]",
+	Short: "Add a note to a task",
+	Long: `Add an internal note to a task, either as free text or from a canned response.
+
+Canned responses are managed by admins in the web interface. Placeholders such as
+{{task.id}} are filled in by the server for the given task.
+
+Examples:
+  jats comment 42 "Waiting on vendor"
+  jats comment --canned thanks 42`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := client.New()
+
+		var taskID uint
+		if _, err := fmt.Sscanf(args[0], "%d", &taskID); err != nil {
+			return fmt.Errorf("invalid task ID: %s", args[0])
+		}
+
+		content := strings.TrimSpace(strings.Join(args[1:], " "))
+		if content == "" && commentCanned == "" {
+			return fmt.Errorf("provide a note or --canned <name>")
+		}
+		if content != "" && commentCanned != "" {
+			return fmt.Errorf("use either a note or --canned, not both")
+		}
+
+		req := &client.AddCommentRequest{
+			Content:   content,
+			Canned:    commentCanned,
+			IsPrivate: true,
+		}
+		if err := c.AddComment(taskID, req); err != nil {
+			return fmt.Errorf("failed to add note: %w", err)
+		}
+
+		fmt.Println(tr("cli_comment_added", map[string]interface{}{"ID": taskID}))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(commentCmd)
+	commentCmd.Flags().StringVar(&commentCanned, "canned", "", "Name of a canned response to post")
+}
@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/soarinferret/jats/internal/cli/config"
+	"github.com/soarinferret/jats/internal/i18n"
 )
 
 var configCmd = &cobra.Command{
@@ -20,10 +24,23 @@ var setCmd = &cobra.Command{
 
 Available keys:
   server_url  - JATS server URL (e.g., http://localhost:8081)
+  language    - CLI message language (en, de, es); defaults to $LANG
+  aging_days  - Days in progress before the TUI flags a task as aging (0 disables)
+  credential_store - Where the login token is kept: auto (OS keyring when
+                available, else the config file), keyring or file
+  theme       - TUI color theme: dark, light, high-contrast or no-color
+                (NO_COLOR in the environment always disables color)
+  notifications - How mentions are reported while the TUI or jats notify
+                runs: desktop (falls back to the bell), bell or off
 
 Examples:
   jats config set server_url http://localhost:8080
-  jats config set server_url https://jats.example.com`,
+  jats config set server_url https://jats.example.com
+  jats config set language de
+  jats config set aging_days 5
+  jats config set credential_store file
+  jats config set theme high-contrast
+  jats config set notifications bell`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
@@ -37,6 +54,34 @@ Examples:
 		switch key {
 		case "server_url":
 			cfg.ServerURL = value
+		case "language":
+			if !i18n.IsSupported(value) {
+				return fmt.Errorf("unsupported language: %s (supported: %s)", value, strings.Join(i18n.SupportedLanguages(), ", "))
+			}
+			cfg.Language = value
+		case "aging_days":
+			days, err := strconv.Atoi(value)
+			if err != nil || days < 0 {
+				return fmt.Errorf("aging_days must be a non-negative number of days")
+			}
+			cfg.AgingDays = &days
+		case "credential_store":
+			switch value {
+			case config.CredentialStoreAuto, config.CredentialStoreKeyring, config.CredentialStoreFile:
+				cfg.CredentialStore = value
+			default:
+				return fmt.Errorf("credential_store must be auto, keyring or file")
+			}
+		case "theme":
+			if !slices.Contains(config.Themes, value) {
+				return fmt.Errorf("theme must be one of: %s", strings.Join(config.Themes, ", "))
+			}
+			cfg.Theme = value
+		case "notifications":
+			if !slices.Contains(config.NotificationModes, value) {
+				return fmt.Errorf("notifications must be one of: %s", strings.Join(config.NotificationModes, ", "))
+			}
+			cfg.Notifications = value
 		default:
 			return fmt.Errorf("unknown configuration key: %s", key)
 		}
@@ -48,7 +93,7 @@ Examples:
 		// Update current config
 		config.SetCurrent(cfg)
 
-		fmt.Printf("✓ Set %s = %s\n", key, value)
+		fmt.Println(tr("cli_config_set", map[string]interface{}{"Key": key, "Value": value}))
 		return nil
 	},
 }
@@ -72,6 +117,13 @@ Examples:
 		if len(args) == 0 {
 			// Show all configuration
 			fmt.Printf("server_url = %s\n", cfg.ServerURL)
+			if cfg.Language != "" {
+				fmt.Printf("language = %s\n", cfg.Language)
+			}
+			fmt.Printf("aging_days = %d\n", cfg.GetAgingDays())
+			fmt.Printf("credential_store = %s\n", cfg.GetCredentialStore())
+			fmt.Printf("theme = %s\n", cfg.GetTheme())
+			fmt.Printf("notifications = %s\n", cfg.GetNotifications())
 			if cfg.Username != "" {
 				fmt.Printf("username = %s\n", cfg.Username)
 			}
@@ -85,6 +137,16 @@ Examples:
 			fmt.Println(cfg.ServerURL)
 		case "username":
 			fmt.Println(cfg.Username)
+		case "language":
+			fmt.Println(localizer().Language())
+		case "aging_days":
+			fmt.Println(cfg.GetAgingDays())
+		case "credential_store":
+			fmt.Println(cfg.GetCredentialStore())
+		case "theme":
+			fmt.Println(cfg.GetTheme())
+		case "notifications":
+			fmt.Println(cfg.GetNotifications())
 		case "authenticated":
 			fmt.Printf("%t\n", cfg.Username != "" && cfg.Token != "")
 		default:
@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/soarinferret/jats/internal/cli/client"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+// taskDocument is the editable form of a task: front matter fields followed by
+// the description as markdown
+type taskDocument struct {
+	Name        string
+	Status      string
+	Priority    string
+	Tags        []string
+	MilestoneID *uint
+	Context     string // e.g. @home, or empty for none
+	Size        string // XS-XL or story points, or empty for none
+	Due         string // YYYY-MM-DD, or empty for none
+	Description string
+}
+
+const editHelp = `# Edit the task below and save to apply your changes; lines starting with
+# '#' above the front matter are ignored. Quit without saving to cancel.
+# status: open, in-progress, pending-review, resolved, closed
+# priority: low, medium, high
+# milestone: a milestone ID, or empty for none
+# context: where the task can be done, e.g. @home, or empty for none
+# size: XS, S, M, L, XL or story points, or empty for none
+# due: a date such as 2025-12-15 or "next friday", or empty for none
+`
+
+var editCmd = &cobra.Command{
+	Use:   "edit <task-id>",
+	Short: "Edit a task in your editor",
+	Long: `Open a task in $VISUAL or $EDITOR (vi by default) as front matter plus a
+markdown description, and apply what changed when you save and quit. If the
+edited task is invalid the editor opens again with the error at the top.
+
+Examples:
+  jats edit 42
+  EDITOR="code --wait" jats edit 42`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var taskID uint
+		if _, err := fmt.Sscanf(args[0], "%d", &taskID); err != nil {
+			return fmt.Errorf("invalid task ID: %s", args[0])
+		}
+
+		c := client.New()
+
+		task, err := c.GetTask(taskID)
+		if err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+		original := taskDocumentFromTask(task)
+
+		file, err := os.CreateTemp("", fmt.Sprintf("jats-task-%d-*.md", taskID))
+		if err != nil {
+			return fmt.Errorf("failed to create temporary file: %w", err)
+		}
+		path := file.Name()
+		file.Close()
+		defer os.Remove(path)
+
+		content := editHelp + original.render()
+		var edited taskDocument
+		for {
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				return fmt.Errorf("failed to write temporary file: %w", err)
+			}
+			if err := runEditor(path); err != nil {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read edited task: %w", err)
+			}
+			if string(data) == content {
+				fmt.Println("Edit cancelled, no changes made")
+				return nil
+			}
+
+			edited, err = parseTaskDocument(string(data))
+			if err == nil {
+				break
+			}
+			content = fmt.Sprintf("# Error: %v\n", err) + stripErrorComments(string(data))
+		}
+
+		updates := original.diff(edited)
+		if len(updates) == 0 {
+			fmt.Println("No changes made")
+			return nil
+		}
+
+		// Refuse to overwrite changes someone else saved while the editor was open
+		current, err := c.GetTask(taskID)
+		if err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+		if !current.UpdatedAt.Equal(task.UpdatedAt) {
+			saved, saveErr := os.CreateTemp("", fmt.Sprintf("jats-task-%d-*.md", taskID))
+			if saveErr == nil {
+				saved.WriteString(edited.render())
+				saved.Close()
+				return fmt.Errorf("task #%d was changed on the server while you were editing; your version is saved in %s", taskID, saved.Name())
+			}
+			return fmt.Errorf("task #%d was changed on the server while you were editing", taskID)
+		}
+
+		if _, err := c.PatchTask(taskID, updates); err != nil {
+			return fmt.Errorf("failed to update task: %w", err)
+		}
+
+		fields := make([]string, 0, len(updates))
+		for field := range updates {
+			fields = append(fields, field)
+		}
+		slices.Sort(fields)
+		fmt.Println(tr("cli_task_updated", map[string]interface{}{"ID": taskID, "Fields": strings.Join(fields, ", ")}))
+		return nil
+	},
+}
+
+// runEditor opens path in the user's editor and waits for it to exit
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor setting may carry arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	editorCmd := exec.Command(parts[0], append(parts[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+func taskDocumentFromTask(task *models.Task) taskDocument {
+	doc := taskDocument{
+		Name:        task.Name,
+		Status:      string(task.Status),
+		Priority:    string(task.Priority),
+		Tags:        task.Tags,
+		MilestoneID: task.MilestoneID,
+		Context:     task.Context,
+		Size:        string(task.Size),
+		Description: task.Description,
+	}
+	if task.DueDate != nil {
+		doc.Due = task.DueDate.Format("2006-01-02")
+	}
+	return doc
+}
+
+// render writes the document as front matter followed by the description
+func (d taskDocument) render() string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "name: %s\n", d.Name)
+	fmt.Fprintf(&b, "status: %s\n", d.Status)
+	fmt.Fprintf(&b, "priority: %s\n", d.Priority)
+	fmt.Fprintf(&b, "tags: %s\n", strings.Join(d.Tags, ", "))
+	if d.MilestoneID != nil {
+		fmt.Fprintf(&b, "milestone: %d\n", *d.MilestoneID)
+	} else {
+		b.WriteString("milestone:\n")
+	}
+	fmt.Fprintf(&b, "context: %s\n", d.Context)
+	fmt.Fprintf(&b, "size: %s\n", d.Size)
+	fmt.Fprintf(&b, "due: %s\n", d.Due)
+	b.WriteString("---\n\n")
+	b.WriteString(d.Description)
+	if d.Description != "" && !strings.HasSuffix(d.Description, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// parseTaskDocument reads an edited document back, validating its fields
+func parseTaskDocument(content string) (taskDocument, error) {
+	var doc taskDocument
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	// Skip the help and error comments before the front matter
+	started := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line != "---" {
+			return doc, fmt.Errorf("expected front matter starting with ---")
+		}
+		started = true
+		break
+	}
+	if !started {
+		return doc, fmt.Errorf("the task is empty")
+	}
+
+	seen := make(map[string]bool)
+	closed := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "---" {
+			closed = true
+			break
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return doc, fmt.Errorf("invalid front matter line %q (expected key: value)", line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if seen[key] {
+			return doc, fmt.Errorf("%s is given twice", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "name":
+			doc.Name = value
+		case "status":
+			switch models.TaskStatus(value) {
+			case models.TaskStatusOpen, models.TaskStatusInProgress, models.TaskStatusPendingReview, models.TaskStatusResolved, models.TaskStatusClosed:
+				doc.Status = value
+			default:
+				return doc, fmt.Errorf("invalid status %q", value)
+			}
+		case "priority":
+			switch models.TaskPriority(value) {
+			case "", models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh:
+				doc.Priority = value
+			default:
+				return doc, fmt.Errorf("invalid priority %q", value)
+			}
+		case "tags":
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					doc.Tags = append(doc.Tags, tag)
+				}
+			}
+		case "milestone":
+			if value != "" {
+				id, err := strconv.ParseUint(strings.TrimPrefix(value, "#"), 10, 32)
+				if err != nil || id == 0 {
+					return doc, fmt.Errorf("invalid milestone %q (expected an ID)", value)
+				}
+				milestoneID := uint(id)
+				doc.MilestoneID = &milestoneID
+			}
+		case "context":
+			doc.Context = value
+		case "size":
+			doc.Size = strings.ToUpper(value)
+		case "due":
+			if value != "" {
+				due, err := utils.ParseDate(value)
+				if err != nil {
+					return doc, fmt.Errorf("invalid due date %q", value)
+				}
+				doc.Due = due.Format("2006-01-02")
+			}
+		default:
+			return doc, fmt.Errorf("unknown field %q", key)
+		}
+	}
+	if !closed {
+		return doc, fmt.Errorf("front matter is not closed with ---")
+	}
+	if doc.Name == "" {
+		return doc, fmt.Errorf("name is required")
+	}
+
+	var description []string
+	for scanner.Scan() {
+		description = append(description, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return doc, err
+	}
+	doc.Description = strings.TrimSpace(strings.Join(description, "\n"))
+
+	return doc, nil
+}
+
+// diff returns the PATCH body for the fields that changed from d to edited
+func (d taskDocument) diff(edited taskDocument) map[string]interface{} {
+	updates := make(map[string]interface{})
+	if edited.Name != d.Name {
+		updates["name"] = edited.Name
+	}
+	if edited.Status != d.Status {
+		updates["status"] = edited.Status
+	}
+	// The API cannot clear a priority, so an emptied one is left as it was
+	if edited.Priority != d.Priority && edited.Priority != "" {
+		updates["priority"] = edited.Priority
+	}
+	if !slices.Equal(edited.Tags, d.Tags) && (len(edited.Tags) > 0 || len(d.Tags) > 0) {
+		tags := edited.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		updates["tags"] = tags
+	}
+	if (edited.MilestoneID == nil) != (d.MilestoneID == nil) || (edited.MilestoneID != nil && *edited.MilestoneID != *d.MilestoneID) {
+		updates["milestone_id"] = edited.MilestoneID
+	}
+	// An empty context, size or due date clears it
+	if edited.Context != d.Context {
+		updates["context"] = edited.Context
+	}
+	if edited.Size != d.Size {
+		updates["size"] = edited.Size
+	}
+	if edited.Due != d.Due {
+		updates["due_date"] = edited.Due
+	}
+	if edited.Description != strings.TrimSpace(d.Description) {
+		updates["description"] = edited.Description
+	}
+	return updates
+}
+
+// stripErrorComments removes the error lines added by a previous failed attempt
+func stripErrorComments(content string) string {
+	lines := strings.SplitAfter(content, "\n")
+	for len(lines) > 0 && strings.HasPrefix(lines[0], "# Error:") {
+		lines = lines[1:]
+	}
+	return strings.Join(lines, "")
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
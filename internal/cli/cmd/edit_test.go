@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTaskDocumentRoundTrip(t *testing.T) {
+	milestoneID := uint(3)
+	original := taskDocument{
+		Name:        "Fix login",
+		Status:      "open",
+		Priority:    "high",
+		Tags:        []string{"auth", "client1"},
+		MilestoneID: &milestoneID,
+		Context:     "@home",
+		Size:        "M",
+		Due:         "2025-12-15",
+		Description: "Users get logged out.\n\n- check cookies",
+	}
+
+	parsed, err := parseTaskDocument(editHelp + original.render())
+	if err != nil {
+		t.Fatalf("Failed to parse rendered document: %v", err)
+	}
+	if updates := original.diff(parsed); len(updates) != 0 {
+		t.Errorf("Expected no changes after a round trip, got %v", updates)
+	}
+
+	edited := strings.Replace(original.render(), "status: open", "status: in-progress", 1)
+	edited = strings.Replace(edited, "tags: auth, client1", "tags: auth", 1)
+	edited = strings.Replace(edited, "milestone: 3", "milestone:", 1)
+	edited = strings.Replace(edited, "due: 2025-12-15", "due:", 1)
+	edited = strings.Replace(edited, "context: @home", "context: @office", 1)
+	edited = strings.Replace(edited, "size: M", "size: xl", 1)
+	edited += "- clear cache\n"
+	parsed, err = parseTaskDocument(edited)
+	if err != nil {
+		t.Fatalf("Failed to parse edited document: %v", err)
+	}
+	updates := original.diff(parsed)
+	if len(updates) != 7 || updates["status"] != "in-progress" || updates["milestone_id"] != (*uint)(nil) || updates["due_date"] != "" || updates["context"] != "@office" || updates["size"] != "XL" {
+		t.Errorf("Unexpected updates %v", updates)
+	}
+	if tags, ok := updates["tags"].([]string); !ok || len(tags) != 1 || tags[0] != "auth" {
+		t.Errorf("Expected tags [auth], got %v", updates["tags"])
+	}
+	if !strings.HasSuffix(updates["description"].(string), "- clear cache") {
+		t.Errorf("Expected the description change, got %q", updates["description"])
+	}
+}
+
+func TestParseTaskDocumentErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "empty", content: "# only comments\n"},
+		{name: "no front matter", content: "just text\n"},
+		{name: "unclosed", content: "---\nname: x\n"},
+		{name: "missing name", content: "---\nname:\n---\n"},
+		{name: "invalid status", content: "---\nname: x\nstatus: done\n---\n"},
+		{name: "invalid priority", content: "---\nname: x\npriority: urgent\n---\n"},
+		{name: "invalid milestone", content: "---\nname: x\nmilestone: soon\n---\n"},
+		{name: "invalid due date", content: "---\nname: x\ndue: someday\n---\n"},
+		{name: "unknown field", content: "---\nname: x\nowner: me\n---\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseTaskDocument(tt.content); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/soarinferret/jats/internal/cli/client"
+	"github.com/soarinferret/jats/internal/outline"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormat   string
+	importProjects bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file|->",
+	Short: "Import tasks from an org-mode file or Markdown checklist",
+	Long: `Import the todo items of an org-mode file or a Markdown checklist as
+tasks, so plain-text todo lists can move to JATS without typing them in again.
+
+In Markdown, each "- [ ]" item is a task and the items indented under it are
+its subtasks; "- [x]" items are imported resolved. In org-mode, headlines with
+a TODO keyword are tasks, with the TODO headlines and checkbox items below
+them as subtasks, and checkbox items outside them are tasks as in Markdown.
+Org-mode tags, [#A]-[#C] priorities and deadlines are kept.
+
+The headings above a task become tags, e.g. "Home Improvements" becomes
+home-improvements. With --projects the outermost heading names the task's
+project instead, created if no project has that name.
+
+The format is taken from the file extension (.org, .md) unless given.
+
+Examples:
+  jats import ~/notes/todo.org
+  jats import --projects TODO.md
+  cat checklist.txt | jats import - --format markdown`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, ok := outline.FormatOf(args[0])
+		if importFormat != "" {
+			var err error
+			if format, err = outline.ParseFormat(importFormat); err != nil {
+				return err
+			}
+		} else if !ok {
+			return fmt.Errorf("cannot tell the format of %s, use --format org or --format markdown", args[0])
+		}
+
+		var input io.Reader = os.Stdin
+		if args[0] != "-" {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", args[0], err)
+			}
+			defer file.Close()
+			input = file
+		}
+		content, err := io.ReadAll(input)
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		// Parse locally first, so an empty file is reported without a request
+		items, err := outline.Parse(format, bytes.NewReader(content))
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return fmt.Errorf("no tasks found in input")
+		}
+
+		headings := "tags"
+		if importProjects {
+			headings = "projects"
+		}
+		tasks, err := client.New().ImportTasks(string(format), string(content), headings)
+		if err != nil {
+			return fmt.Errorf("failed to import tasks: %w", err)
+		}
+
+		printCreatedTasks(tasks)
+		fmt.Printf("\n✓ Imported %d tasks\n", len(tasks))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVar(&importFormat, "format", "", "Format of the file (org, markdown); guessed from the extension by default")
+	importCmd.Flags().BoolVar(&importProjects, "projects", false, "Use the outermost heading as the project instead of a tag")
+}
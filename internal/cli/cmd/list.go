@@ -7,13 +7,22 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/soarinferret/jats/internal/cli/client"
+	"github.com/soarinferret/jats/internal/models"
 )
 
 var (
 	listStatus   string
 	listTag      string
-	listPriority string
-	listLimit    int
+	listPriority  string
+	listMilestone string
+	listContext   string
+	listSearch    string
+	listIn        []string
+	listLimit     int
+	listRecent    bool
+	listOverdue   bool
+	listDueBefore string
+	listDueAfter  string
 )
 
 var listCmd = &cobra.Command{
@@ -26,7 +35,15 @@ Examples:
   jats list --status open      # List open tasks
   jats list --tag urgent       # List tasks with 'urgent' tag
   jats list --priority high    # List high priority tasks
-  jats list --limit 10         # Limit to 10 tasks`,
+  jats list --milestone 3      # List tasks on milestone 3 ("none" for no milestone)
+  jats list --context @home    # List tasks that can be done at home ("none" for no context)
+  jats list --search toner     # Tasks mentioning toner, including in notes and time entries
+  jats list --search toner --in comments   # Only where a note mentions it
+  jats list --search 'tag:client1 status:open "paper jam" -tag:internal created>-30d'
+  jats list --limit 10         # Limit to 10 tasks
+  jats list --recent           # Tasks I last commented on, logged time on or moved first
+  jats list --overdue          # Open tasks whose due date has passed
+  jats list --due-before "next friday"   # Tasks due by next Friday`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		c := client.New()
 		
@@ -40,9 +57,24 @@ Examples:
 		if listPriority != "" {
 			filters.Priority = []string{listPriority}
 		}
+		filters.Milestone = listMilestone
+		filters.Context = listContext
+		filters.Search = listSearch
+		filters.In = listIn
 		if listLimit > 0 {
 			filters.Limit = listLimit
 		}
+		if listRecent {
+			filters.Sort = "recently_touched"
+		}
+		filters.Overdue = listOverdue
+		var err error
+		if filters.DueBefore, err = resolveDate(listDueBefore); err != nil {
+			return err
+		}
+		if filters.DueAfter, err = resolveDate(listDueAfter); err != nil {
+			return err
+		}
 
 		tasks, err := c.GetTasks(filters)
 		if err != nil {
@@ -50,12 +82,12 @@ Examples:
 		}
 
 		if len(tasks) == 0 {
-			fmt.Println("No tasks found")
+			fmt.Println(tr("cli_no_tasks"))
 			return nil
 		}
 
 		// Print header
-		fmt.Printf("%-4s %-10s %-8s %-15s %s\n", "ID", "STATUS", "PRIORITY", "TAGS", "NAME")
+		fmt.Printf("%-4s %-10s %-8s %-11s %-15s %s\n", "ID", "STATUS", "PRIORITY", "DUE", "TAGS", "NAME")
 		fmt.Println(strings.Repeat("-", 80))
 
 		for _, task := range tasks {
@@ -77,16 +109,24 @@ Examples:
 				}
 			}
 
+			dueStr := "-"
+			if task.DueDate != nil {
+				dueStr = task.DueDate.Format("2006-01-02")
+				if task.Overdue {
+					dueStr += "!"
+				}
+			}
+
 			nameStr := task.Name
 			if len(nameStr) > 40 {
 				nameStr = nameStr[:37] + "..."
 			}
 
-			fmt.Printf("%-4d %-10s %-8s %-15s %s\n", 
-				task.ID, statusStr, priorityStr, tagsStr, nameStr)
+			fmt.Printf("%-4d %-10s %-8s %-11s %-15s %s\n", 
+				task.ID, statusStr, priorityStr, dueStr, tagsStr, nameStr)
 		}
 
-		fmt.Printf("\nTotal: %d tasks\n", len(tasks))
+		fmt.Printf("\n%s\n", localizer().N("cli_total_tasks", len(tasks), nil))
 
 		return nil
 	},
@@ -121,10 +161,36 @@ Examples:
 		fmt.Printf("Priority:    %s\n", getPriority(string(task.Priority)))
 		fmt.Printf("Created:     %s\n", task.CreatedAt.Format("2006-01-02 15:04"))
 		fmt.Printf("Updated:     %s\n", task.UpdatedAt.Format("2006-01-02 15:04"))
+		if task.DueDate != nil {
+			overdue := ""
+			if task.Overdue {
+				overdue = " (overdue)"
+			}
+			fmt.Printf("Due:         %s%s\n", task.DueDate.Format("2006-01-02"), overdue)
+		}
 		
 		if len(task.Tags) > 0 {
 			fmt.Printf("Tags:        %s\n", strings.Join(task.Tags, ", "))
 		}
+		if task.Context != "" {
+			fmt.Printf("Context:     %s\n", task.Context)
+		}
+		if task.Size != "" {
+			fmt.Printf("Size:        %s (%d points)\n", task.Size, task.Size.Points())
+		}
+
+		printTaskLinks := func(label string, links []models.TaskLink) {
+			for i, link := range links {
+				if i == 0 {
+					fmt.Printf("%-13s", label+":")
+				} else {
+					fmt.Printf("%-13s", "")
+				}
+				fmt.Printf("#%d %s (%s)\n", link.ID, link.Name, getStatus(string(link.Status)))
+			}
+		}
+		printTaskLinks("Blocked by", task.BlockedBy)
+		printTaskLinks("Blocks", task.Blocks)
 
 		// Calculate total time
 		var totalMinutes int
@@ -163,7 +229,11 @@ Examples:
 		if len(task.Comments) > 0 {
 			fmt.Printf("\nComments:\n")
 			for _, comment := range task.Comments {
-				fmt.Printf("  %s: %s\n", comment.CreatedAt.Format("2006-01-02 15:04"), comment.Content)
+				receipt := ""
+				if comment.Receipt != "" {
+					receipt = fmt.Sprintf(" [%s]", comment.Receipt)
+				}
+				fmt.Printf("  %s%s: %s\n", comment.CreatedAt.Format("2006-01-02 15:04"), receipt, comment.Content)
 			}
 		}
 
@@ -175,10 +245,18 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(showCmd)
 	
-	listCmd.Flags().StringVarP(&listStatus, "status", "s", "", "Filter by status (open, in-progress, resolved, closed)")
+	listCmd.Flags().StringVarP(&listStatus, "status", "s", "", "Filter by status (open, in-progress, pending-review, resolved, closed)")
 	listCmd.Flags().StringVarP(&listTag, "tag", "t", "", "Filter by tag")
 	listCmd.Flags().StringVarP(&listPriority, "priority", "p", "", "Filter by priority (low, medium, high)")
+	listCmd.Flags().StringVarP(&listMilestone, "milestone", "m", "", "Filter by milestone ID (none for tasks without one)")
+	listCmd.Flags().StringVar(&listContext, "context", "", "Filter by context, e.g. @home (none for tasks without one)")
+	listCmd.Flags().StringVar(&listSearch, "search", "", "Only tasks matching this search: words, \"phrases\", tag:, status:, priority:, milestone:, in:, created<date (- negates)")
+	listCmd.Flags().StringSliceVar(&listIn, "in", nil, "Fields to search: name, description, comments, time_entries (default: all)")
 	listCmd.Flags().IntVarP(&listLimit, "limit", "l", 0, "Limit number of results")
+	listCmd.Flags().BoolVar(&listOverdue, "overdue", false, "Only open and in-progress tasks whose due date has passed")
+	listCmd.Flags().StringVar(&listDueBefore, "due-before", "", "Only tasks due on or before this date")
+	listCmd.Flags().StringVar(&listDueAfter, "due-after", "", "Only tasks due on or after this date")
+	listCmd.Flags().BoolVar(&listRecent, "recent", false, "Order by my latest comment, time entry or status change")
 }
 
 func getStatus(status string) string {
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/soarinferret/jats/internal/cli/config"
+	"github.com/soarinferret/jats/internal/i18n"
+)
+
+var cliLocalizer *i18n.Localizer
+
+// localizer returns the CLI localizer, resolved from the configured language
+// and then the standard locale environment variables
+func localizer() *i18n.Localizer {
+	if cliLocalizer != nil {
+		return cliLocalizer
+	}
+
+	var candidates []string
+	if cfg := config.GetCurrent(); cfg != nil && cfg.Language != "" {
+		candidates = append(candidates, cfg.Language)
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if val := os.Getenv(env); val != "" {
+			candidates = append(candidates, val)
+		}
+	}
+
+	cliLocalizer = i18n.NewLocalizer(candidates...)
+	return cliLocalizer
+}
+
+// tr translates a CLI message ID
+func tr(id string, data ...interface{}) string {
+	return localizer().T(id, data...)
+}
@@ -9,8 +9,10 @@ import (
 )
 
 var (
-	logNote string
-	logDate string
+	logNote        string
+	logDate        string
+	logBillable    bool
+	logNonBillable bool
 )
 
 var logCmd = &cobra.Command{
@@ -26,7 +28,11 @@ Examples:
   jats log 123 1h --note "debugging"    # Log 1 hour with note
   jats log 123 2.5h                     # Log 2.5 hours
   jats log 123 1h -d -1d                # Log 1 hour yesterday
-  jats log 123 45m -d 2025-12-01        # Log 45 minutes on specific date`,
+  jats log 123 45m -d 2025-12-01        # Log 45 minutes on specific date
+  jats log 123 2h -d "last friday"      # Log 2 hours last Friday
+  jats log 123 1h --non-billable        # Log internal work on a client task
+
+Time is billable unless the task has one of the server's non-billable tags.`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		c := client.New()
@@ -41,10 +47,21 @@ Examples:
 			return err
 		}
 
+		entryDate, err := resolveDate(logDate)
+		if err != nil {
+			return err
+		}
+
+		billable, err := billableFlag(logBillable, logNonBillable)
+		if err != nil {
+			return err
+		}
+
 		req := &client.LogTimeRequest{
 			Duration:    durationMinutes,
 			Description: logNote,
-			Date:        logDate,
+			Date:        entryDate,
+			Billable:    billable,
 		}
 
 		err = c.LogTime(taskID, req)
@@ -54,12 +71,12 @@ Examples:
 
 		// Format duration for display
 		duration := time.Duration(durationMinutes) * time.Minute
-		fmt.Printf("✓ Logged %s to task #%d\n", formatDurationDisplay(duration), taskID)
+		fmt.Println(tr("cli_time_logged", map[string]interface{}{"Duration": formatDurationDisplay(duration), "ID": taskID}))
 		if logNote != "" {
-			fmt.Printf("  Note: %s\n", logNote)
+			fmt.Printf("  %s: %s\n", tr("cli_label_note"), logNote)
 		}
-		if logDate != "" {
-			fmt.Printf("  Date: %s\n", logDate)
+		if entryDate != "" {
+			fmt.Printf("  %s: %s\n", tr("cli_label_date"), entryDate)
 		}
 
 		return nil
@@ -69,7 +86,21 @@ Examples:
 func init() {
 	rootCmd.AddCommand(logCmd)
 	logCmd.Flags().StringVarP(&logNote, "note", "n", "", "Note describing the work done")
-	logCmd.Flags().StringVarP(&logDate, "date", "d", "", "Entry date (-1d, +2w, 2025-12-01)")
+	logCmd.Flags().StringVarP(&logDate, "date", "d", "", "Entry date (-1d, 2025-12-01, yesterday, \"last friday\")")
+	logCmd.Flags().BoolVar(&logBillable, "billable", false, "Bill the time to the client")
+	logCmd.Flags().BoolVar(&logNonBillable, "non-billable", false, "Do not bill the time to the client")
+}
+
+// billableFlag turns a --billable/--non-billable flag pair into a billable
+// value, nil when neither is given
+func billableFlag(billable, nonBillable bool) (*bool, error) {
+	switch {
+	case billable && nonBillable:
+		return nil, fmt.Errorf("--billable and --non-billable cannot be used together")
+	case billable || nonBillable:
+		return &billable, nil
+	}
+	return nil, nil
 }
 
 func formatDurationDisplay(d time.Duration) string {
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/soarinferret/jats/internal/cli/client"
+)
+
+var (
+	milestoneDue         string
+	milestoneDescription string
+	milestoneClear       bool
+)
+
+var milestonesCmd = &cobra.Command{
+	Use:     "milestones",
+	Aliases: []string{"milestone"},
+	Short:   "List milestones and their progress",
+	Long: `List milestones with resolved/total tasks and time logged.
+
+Examples:
+  jats milestones
+  jats milestones add "v2.0 release" --due "end of month"
+  jats milestones assign 3 42 43
+  jats milestones assign --clear 42
+  jats list --milestone 3`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := client.New()
+
+		milestones, err := c.GetMilestones()
+		if err != nil {
+			return fmt.Errorf("failed to get milestones: %w", err)
+		}
+
+		if len(milestones) == 0 {
+			fmt.Println("No milestones found")
+			return nil
+		}
+
+		fmt.Printf("\n%-5s | %-30s | %-12s | %-10s | %-5s | %-10s\n", "ID", "Name", "Due", "Resolved", "%", "Logged")
+		fmt.Printf("%s\n", strings.Repeat("-", 90))
+
+		for _, p := range milestones {
+			due := "-"
+			if p.Milestone.DueDate != nil {
+				due = p.Milestone.DueDate.Format("2006-01-02")
+				if p.Overdue {
+					due += " !"
+				}
+			}
+			fmt.Printf("%-5d | %-30s | %-12s | %-10s | %-5.0f | %-10s\n",
+				p.Milestone.ID, truncate(p.Milestone.Name, 30), due,
+				fmt.Sprintf("%d/%d", p.ResolvedTasks, p.TotalTasks), p.Percent,
+				formatDurationDisplay(time.Duration(p.LoggedMinutes)*time.Minute))
+		}
+		fmt.Println()
+
+		return nil
+	},
+}
+
+var milestonesAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Create a milestone",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		due, err := resolveDate(milestoneDue)
+		if err != nil {
+			return err
+		}
+
+		c := client.New()
+
+		milestone, err := c.CreateMilestone(&client.CreateMilestoneRequest{
+			Name:        strings.Join(args, " "),
+			Description: milestoneDescription,
+			DueDate:     due,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create milestone: %w", err)
+		}
+
+		fmt.Println(tr("cli_milestone_created", map[string]interface{}{"ID": milestone.ID, "Name": milestone.Name}))
+		return nil
+	},
+}
+
+var milestonesAssignCmd = &cobra.Command{
+	Use:   "assign <milestone-id> <task-id>...",
+	Short: "Attach tasks to a milestone",
+	Long: `Attach tasks to a milestone. With --clear, all arguments are task IDs
+and the tasks are detached from their milestone.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var milestoneID *uint
+		taskArgs := args
+		if !milestoneClear {
+			if len(args) < 2 {
+				return fmt.Errorf("expected a milestone ID and at least one task ID")
+			}
+			var id uint
+			if _, err := fmt.Sscanf(args[0], "%d", &id); err != nil {
+				return fmt.Errorf("invalid milestone ID: %s", args[0])
+			}
+			milestoneID = &id
+			taskArgs = args[1:]
+		}
+
+		c := client.New()
+
+		for _, arg := range taskArgs {
+			var taskID uint
+			if _, err := fmt.Sscanf(arg, "%d", &taskID); err != nil {
+				return fmt.Errorf("invalid task ID: %s", arg)
+			}
+			if err := c.SetTaskMilestone(taskID, milestoneID); err != nil {
+				return fmt.Errorf("failed to update task #%d: %w", taskID, err)
+			}
+			if milestoneID == nil {
+				fmt.Println(tr("cli_milestone_task_removed", map[string]interface{}{"ID": taskID}))
+			} else {
+				fmt.Println(tr("cli_milestone_task_added", map[string]interface{}{"ID": taskID, "MilestoneID": *milestoneID}))
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(milestonesCmd)
+	milestonesCmd.AddCommand(milestonesAddCmd)
+	milestonesCmd.AddCommand(milestonesAssignCmd)
+	milestonesAddCmd.Flags().StringVarP(&milestoneDue, "due", "d", "", "Due date (e.g. 2025-06-30, \"end of month\")")
+	milestonesAddCmd.Flags().StringVar(&milestoneDescription, "description", "", "Milestone description")
+	milestonesAssignCmd.Flags().BoolVar(&milestoneClear, "clear", false, "Detach the given tasks from their milestone")
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/soarinferret/jats/internal/cli/client"
+	"github.com/soarinferret/jats/internal/cli/config"
+	"github.com/soarinferret/jats/internal/cli/notify"
+)
+
+var (
+	notifyDaemon bool
+	notifySince  string
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Show or follow mentions of you",
+	Long: `List recent activity that mentions you as @username: notes, time entries and
+new tasks, along with tasks assigned to you by a tag's assignment policy.
+
+With --daemon, keep watching and show a desktop notification for each new
+mention (notify-send on Linux, osascript on macOS, otherwise the terminal
+bell) until interrupted with Ctrl+C. The TUI does the same while it runs.
+Set 'jats config set notifications bell' or 'off' to change how mentions are
+reported.
+
+Examples:
+  jats notify
+  jats notify --since monday
+  jats notify --daemon`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetCurrent()
+		if cfg == nil || cfg.Username == "" {
+			return fmt.Errorf("not logged in; run 'jats auth login' first")
+		}
+		c := client.New()
+
+		if !notifyDaemon {
+			events, _, err := c.GetActivity(&client.ActivityFilters{
+				Since:   notifySince,
+				Mention: cfg.Username,
+				Limit:   100,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get mentions: %w", err)
+			}
+			if len(events) == 0 {
+				fmt.Println("No mentions found")
+				return nil
+			}
+			for i := len(events) - 1; i >= 0; i-- {
+				fmt.Println(formatActivityLine(events[i]))
+			}
+			return nil
+		}
+
+		watcher, err := newMentionWatcher(c, cfg.Username)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Watching for mentions of @%s (Ctrl+C to stop)...\n", cfg.Username)
+
+		for {
+			events, err := watcher.next()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v (retrying in %s)\n", err, watchRetryDelay)
+				time.Sleep(watchRetryDelay)
+				continue
+			}
+			for _, event := range events {
+				fmt.Println(formatActivityLine(event))
+				title, body := mentionNotification(event)
+				notify.Send(cfg.GetNotifications(), title, body)
+			}
+		}
+	},
+}
+
+// mentionWatcher long-polls the activity feed for new events mentioning a user
+type mentionWatcher struct {
+	client  *client.Client
+	filters client.ActivityFilters
+}
+
+// newMentionWatcher starts following mentions of username from the latest one
+func newMentionWatcher(c *client.Client, username string) (*mentionWatcher, error) {
+	w := &mentionWatcher{
+		client:  c,
+		filters: client.ActivityFilters{Mention: username, Limit: 1},
+	}
+
+	// Follow on from the latest mention, by the server's clock where there is one
+	events, _, err := c.GetActivity(&w.filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+	if len(events) > 0 {
+		w.filters.After = events[0].Timestamp
+	} else {
+		w.filters.After = time.Now()
+	}
+
+	w.filters.Limit = 100
+	w.filters.Wait = watchWait
+	return w, nil
+}
+
+// next waits for new mentions and returns them oldest first; it returns none
+// when the long-poll times out
+func (w *mentionWatcher) next() ([]client.ActivityEvent, error) {
+	events, _, err := w.client.GetActivity(&w.filters)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+	w.filters.After = events[0].Timestamp
+
+	oldestFirst := make([]client.ActivityEvent, len(events))
+	for i, event := range events {
+		oldestFirst[len(events)-1-i] = event
+	}
+	return oldestFirst, nil
+}
+
+// mentionNotification returns the title and body of the notification for a mention
+func mentionNotification(event client.ActivityEvent) (string, string) {
+	title := fmt.Sprintf("Mentioned on #%d", event.TaskID)
+	switch event.Type {
+	case "created":
+		title = fmt.Sprintf("New task mentions you: #%d", event.TaskID)
+	case "assigned":
+		title = fmt.Sprintf("Assigned to you: #%d", event.TaskID)
+	}
+
+	body := event.TaskName
+	if event.Detail != "" {
+		body += "\n" + event.Detail
+	}
+	return title, body
+}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+	notifyCmd.Flags().BoolVar(&notifyDaemon, "daemon", false, "Keep watching and show a desktop notification for each new mention")
+	notifyCmd.Flags().StringVar(&notifySince, "since", "", "Start date for listed mentions (yesterday, \"last monday\", -2d; default: last 7 days)")
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -38,7 +40,14 @@ var queriesCmd = &cobra.Command{
 				excludedTags = "(none)"
 			}
 
-			fmt.Printf("%-5d | %-30s | %-40s | %-40s\n", q.ID, q.Name, includedTags, excludedTags)
+			name := q.Name
+			if q.Pinned {
+				name = "📌 " + name
+			}
+			fmt.Printf("%-5d | %-30s | %-40s | %-40s\n", q.ID, name, includedTags, excludedTags)
+			if q.Expression != "" {
+				fmt.Printf("%-5s | %-30s | where %s\n", "", "", q.Expression)
+			}
 		}
 		fmt.Printf("\n")
 
@@ -46,6 +55,156 @@ var queriesCmd = &cobra.Command{
 	},
 }
 
+var (
+	scheduleCron    string
+	scheduleTo      []string
+	scheduleSubject string
+	scheduleOff     bool
+)
+
+var queriesScheduleCmd = &cobra.Command{
+	Use:   "schedule <query-id>",
+	Short: "Email a saved query on a schedule",
+	Long: `Email the tasks matching a saved query as a table on a cron schedule
+(minute hour day-of-month month day-of-week, server time).
+
+Examples:
+  jats queries schedule 3 --cron "0 8 * * mon" --to client@example.com
+  jats queries schedule 3 --cron @daily --to a@example.com --to b@example.com --subject "Daily digest"
+  jats queries schedule 3 --off`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseUint(args[0], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid query ID: %s", args[0])
+		}
+
+		c := client.New()
+
+		if scheduleOff {
+			if err := c.DeleteSavedQuerySchedule(uint(id)); err != nil {
+				return fmt.Errorf("failed to remove schedule: %w", err)
+			}
+			fmt.Println(tr("cli_query_unscheduled", map[string]interface{}{"ID": id}))
+			return nil
+		}
+
+		if scheduleCron == "" || len(scheduleTo) == 0 {
+			return fmt.Errorf("--cron and --to are required (or --off to remove the schedule)")
+		}
+
+		schedule, err := c.SetSavedQuerySchedule(uint(id), &client.SavedQueryScheduleRequest{
+			Cron:       scheduleCron,
+			Recipients: scheduleTo,
+			Subject:    scheduleSubject,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to schedule saved query: %w", err)
+		}
+
+		fmt.Println(tr("cli_query_scheduled", map[string]interface{}{"ID": id, "Recipients": strings.Join(schedule.Recipients, ", "), "Cron": schedule.Cron}))
+		if schedule.NextRunAt != nil {
+			fmt.Printf("  %s\n", tr("cli_query_next_run", map[string]interface{}{"Time": schedule.NextRunAt.Format("Mon 2006-01-02 15:04")}))
+		}
+		return nil
+	},
+}
+
+// setQueryPinned pins or unpins a saved query for the current user
+func setQueryPinned(arg string, pinned bool) error {
+	id, err := strconv.ParseUint(arg, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid query ID: %s", arg)
+	}
+
+	c := client.New()
+	prefs, err := c.GetPreferences()
+	if err != nil {
+		return fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	ids := slices.DeleteFunc(prefs.PinnedQueryIDs, func(pinnedID uint) bool { return pinnedID == uint(id) })
+	if pinned {
+		ids = append(ids, uint(id))
+	}
+	if err := c.SetPinnedQueries(ids); err != nil {
+		return fmt.Errorf("failed to update pinned queries: %w", err)
+	}
+
+	if pinned {
+		fmt.Println(tr("cli_query_pinned", map[string]interface{}{"ID": id}))
+	} else {
+		fmt.Println(tr("cli_query_unpinned", map[string]interface{}{"ID": id}))
+	}
+	return nil
+}
+
+var queriesPinCmd = &cobra.Command{
+	Use:   "pin <query-id>",
+	Short: "Pin a saved query to the top of the sidebar",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setQueryPinned(args[0], true)
+	},
+}
+
+var queriesUnpinCmd = &cobra.Command{
+	Use:   "unpin <query-id>",
+	Short: "Unpin a saved query",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setQueryPinned(args[0], false)
+	},
+}
+
+var queriesLandingCmd = &cobra.Command{
+	Use:   "landing [tasks|kanban|reports|dashboard] [query-id]",
+	Short: "Show or set the view the web UI and TUI open on",
+	Long: `Show or set the view the web UI and TUI open on, optionally narrowed to a
+saved query. The TUI opens the list on the saved query whatever the view.
+
+Examples:
+  jats queries landing
+  jats queries landing kanban
+  jats queries landing tasks 3`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := client.New()
+
+		if len(args) > 0 {
+			var queryID uint64
+			if len(args) == 2 {
+				var err error
+				if queryID, err = strconv.ParseUint(args[1], 10, 32); err != nil {
+					return fmt.Errorf("invalid query ID: %s", args[1])
+				}
+			}
+			if err := c.SetLandingView(args[0], uint(queryID)); err != nil {
+				return fmt.Errorf("failed to set landing view: %w", err)
+			}
+		}
+
+		prefs, err := c.GetPreferences()
+		if err != nil {
+			return fmt.Errorf("failed to get preferences: %w", err)
+		}
+		if prefs.LandingQueryID != nil {
+			fmt.Printf("Landing view: %s (saved query %d)\n", prefs.LandingView, *prefs.LandingQueryID)
+		} else {
+			fmt.Printf("Landing view: %s\n", prefs.LandingView)
+		}
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(queriesCmd)
+	queriesCmd.AddCommand(queriesScheduleCmd)
+	queriesCmd.AddCommand(queriesPinCmd)
+	queriesCmd.AddCommand(queriesUnpinCmd)
+	queriesCmd.AddCommand(queriesLandingCmd)
+	queriesScheduleCmd.Flags().StringVar(&scheduleCron, "cron", "", "Cron expression, e.g. \"0 8 * * mon\" or @weekly")
+	queriesScheduleCmd.Flags().StringArrayVar(&scheduleTo, "to", nil, "Recipient email address (repeatable)")
+	queriesScheduleCmd.Flags().StringVar(&scheduleSubject, "subject", "", "Email subject (defaults to the query name)")
+	queriesScheduleCmd.Flags().BoolVar(&scheduleOff, "off", false, "Remove the schedule")
 }
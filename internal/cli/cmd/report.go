@@ -31,6 +31,9 @@ Examples:
   # Report excluding specific tags
   jats report time-breakdown --start 2024-01-01 --end 2024-01-07 --queries 1,2,3 --exclude personal,internal
 
+  # Only count time billed to clients
+  jats report time-breakdown --start 2024-01-01 --end 2024-01-07 --queries 1,2 --billable
+
   # Export report to CSV file
   jats report time-breakdown --start 2024-01-01 --end 2024-01-07 --queries 1,2,3 --csv report.csv`,
 	RunE: runTimeBreakdownReport,
@@ -42,6 +45,8 @@ var (
 	queryIDs     string
 	excludeTags  string
 	csvOutput    string
+	reportBillable    bool
+	reportNonBillable bool
 )
 
 func runTimeBreakdownReport(cmd *cobra.Command, args []string) error {
@@ -61,9 +66,14 @@ func runTimeBreakdownReport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid end date format (expected YYYY-MM-DD): %w", err)
 	}
 
+	billable, err := billableFlag(reportBillable, reportNonBillable)
+	if err != nil {
+		return err
+	}
+
 	// Fetch report from API
 	c := client.New()
-	report, err := c.GetTimeBreakdownReport(startDate, endDate, queryIDs, excludeTags)
+	report, err := c.GetTimeBreakdownReport(startDate, endDate, queryIDs, excludeTags, billable)
 	if err != nil {
 		return fmt.Errorf("failed to fetch report: %w", err)
 	}
@@ -88,7 +98,7 @@ func displayTimeBreakdownReport(report *client.TimeBreakdownReport, start, end t
 	fmt.Printf("================================================================================\n\n")
 
 	// Display header row
-	fmt.Printf("%-10s | %-12s", "Date", "Total Time")
+	fmt.Printf("%-10s | %-12s | %-12s | %-12s", "Date", "Total Time", "Billable", "Non-billable")
 	for _, queryName := range report.QueryNames {
 		// Truncate long query names
 		displayName := queryName
@@ -101,12 +111,13 @@ func displayTimeBreakdownReport(report *client.TimeBreakdownReport, start, end t
 	fmt.Printf("\n")
 
 	// Display separator
-	totalWidth := 10 + 3 + 12 + (len(report.QueryNames) * (3 + 20)) + 3 + 20
+	totalWidth := 10 + 3 + 12 + 3 + 12 + 3 + 12 + (len(report.QueryNames) * (3 + 20)) + 3 + 20
 	fmt.Printf("%s\n", strings.Repeat("-", totalWidth))
 
 	// Display daily data
 	for _, daily := range report.DailyData {
-		fmt.Printf("%-10s | %-12s", daily.Date, formatMinutes(daily.TotalTime))
+		fmt.Printf("%-10s | %-12s | %-12s | %-12s", daily.Date, formatMinutes(daily.TotalTime),
+			formatMinutes(daily.BillableTime), formatMinutes(daily.NonBillableTime))
 
 		for _, queryTime := range daily.QueryTimes {
 			timeStr := formatMinutes(queryTime.Time)
@@ -149,7 +160,8 @@ func displayTimeBreakdownReport(report *client.TimeBreakdownReport, start, end t
 	fmt.Printf("%s\n", strings.Repeat("-", totalWidth))
 
 	// Display totals row
-	fmt.Printf("%-10s | %-12s", "Total", formatMinutes(report.Totals.TotalTime))
+	fmt.Printf("%-10s | %-12s | %-12s | %-12s", "Total", formatMinutes(report.Totals.TotalTime),
+		formatMinutes(report.Totals.BillableTime), formatMinutes(report.Totals.NonBillableTime))
 	for _, queryTotal := range report.Totals.QueryTotals {
 		fmt.Printf(" | %-20s", formatMinutes(queryTotal.TotalTime))
 	}
@@ -157,7 +169,9 @@ func displayTimeBreakdownReport(report *client.TimeBreakdownReport, start, end t
 	fmt.Printf("\n")
 
 	// Display percentages row
-	fmt.Printf("%-10s | %-12s", "Percent", "100%")
+	billablePercent, nonBillablePercent := billableShares(report.Totals)
+	fmt.Printf("%-10s | %-12s | %-12s | %-12s", "Percent", "100%",
+		fmt.Sprintf("%.1f%%", billablePercent), fmt.Sprintf("%.1f%%", nonBillablePercent))
 	for _, queryTotal := range report.Totals.QueryTotals {
 		fmt.Printf(" | %-20s", fmt.Sprintf("%.1f%%", queryTotal.Percentage))
 	}
@@ -194,7 +208,7 @@ func exportReportToCSV(report *client.TimeBreakdownReport, filename string) erro
 	defer writer.Flush()
 
 	// Write header row
-	header := []string{"Date", "Total Time (hours)"}
+	header := []string{"Date", "Total Time (hours)", "Billable (hours)", "Non-billable (hours)"}
 	for _, queryName := range report.QueryNames {
 		header = append(header, queryName+" (hours)")
 		header = append(header, queryName+" Tags")
@@ -211,6 +225,8 @@ func exportReportToCSV(report *client.TimeBreakdownReport, filename string) erro
 		row := []string{
 			daily.Date,
 			minutesToHoursDecimal(daily.TotalTime),
+			minutesToHoursDecimal(daily.BillableTime),
+			minutesToHoursDecimal(daily.NonBillableTime),
 		}
 
 		for _, queryTime := range daily.QueryTimes {
@@ -227,7 +243,8 @@ func exportReportToCSV(report *client.TimeBreakdownReport, filename string) erro
 	}
 
 	// Write totals row
-	totalsRow := []string{"Total", minutesToHoursDecimal(report.Totals.TotalTime)}
+	totalsRow := []string{"Total", minutesToHoursDecimal(report.Totals.TotalTime),
+		minutesToHoursDecimal(report.Totals.BillableTime), minutesToHoursDecimal(report.Totals.NonBillableTime)}
 	for _, queryTotal := range report.Totals.QueryTotals {
 		totalsRow = append(totalsRow, minutesToHoursDecimal(queryTotal.TotalTime))
 		totalsRow = append(totalsRow, "") // No tags in totals
@@ -240,7 +257,8 @@ func exportReportToCSV(report *client.TimeBreakdownReport, filename string) erro
 	}
 
 	// Write percentages row
-	percentRow := []string{"Percent", "100"}
+	billablePercent, nonBillablePercent := billableShares(report.Totals)
+	percentRow := []string{"Percent", "100", fmt.Sprintf("%.1f", billablePercent), fmt.Sprintf("%.1f", nonBillablePercent)}
 	for _, queryTotal := range report.Totals.QueryTotals {
 		percentRow = append(percentRow, fmt.Sprintf("%.1f", queryTotal.Percentage))
 		percentRow = append(percentRow, "") // No tags in percentages
@@ -255,6 +273,16 @@ func exportReportToCSV(report *client.TimeBreakdownReport, filename string) erro
 	return nil
 }
 
+// billableShares returns the percentages of the total time that is billable
+// and non-billable
+func billableShares(totals client.QueryTotals) (float64, float64) {
+	if totals.TotalTime == 0 {
+		return 0, 0
+	}
+	total := float64(totals.TotalTime)
+	return float64(totals.BillableTime) / total * 100, float64(totals.NonBillableTime) / total * 100
+}
+
 func minutesToHoursDecimal(minutes int) string {
 	hours := float64(minutes) / 60.0
 	return fmt.Sprintf("%.2f", hours)
@@ -270,6 +298,8 @@ func init() {
 	timeBreakdownCmd.Flags().StringVar(&queryIDs, "queries", "", "Comma-separated list of saved query IDs")
 	timeBreakdownCmd.Flags().StringVar(&excludeTags, "exclude", "", "Comma-separated list of tags to exclude")
 	timeBreakdownCmd.Flags().StringVar(&csvOutput, "csv", "", "Export report to CSV file (e.g., report.csv)")
+	timeBreakdownCmd.Flags().BoolVar(&reportBillable, "billable", false, "Only count billable time")
+	timeBreakdownCmd.Flags().BoolVar(&reportNonBillable, "non-billable", false, "Only count non-billable time")
 
 	timeBreakdownCmd.MarkFlagRequired("start")
 	timeBreakdownCmd.MarkFlagRequired("end")
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/soarinferret/jats/internal/cli/client"
+	"github.com/spf13/cobra"
+)
+
+var reviewComment string
+
+var reviewCmd = &cobra.Command{
+	Use:   "review <task-id> <approve|reopen>",
+	Short: "Approve or reopen a task pending review",
+	Long: `Approve or reopen a task waiting for review. Tasks with a tag that has
+reviewers configured move to pending-review when resolved; one of the
+reviewers then approves them, resolving them, or reopens them with a comment.
+
+Examples:
+  jats review 42 approve
+  jats review 42 reopen -m "The backup job still fails on Sundays"`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var taskID uint
+		if _, err := fmt.Sscanf(args[0], "%d", &taskID); err != nil {
+			return fmt.Errorf("invalid task ID: %s", args[0])
+		}
+
+		decision := args[1]
+		if decision != "approve" && decision != "reopen" {
+			return fmt.Errorf("decision must be approve or reopen, not %q", decision)
+		}
+		if decision == "reopen" && reviewComment == "" {
+			return fmt.Errorf("reopening a task needs a comment, given with -m")
+		}
+
+		task, err := client.New().ReviewTask(taskID, decision, reviewComment)
+		if err != nil {
+			return fmt.Errorf("failed to review task: %w", err)
+		}
+
+		messageID := "cli_task_closed"
+		if decision == "reopen" {
+			messageID = "cli_task_reopened"
+		}
+		fmt.Println(tr(messageID, map[string]interface{}{"ID": task.ID, "Name": task.Name}))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+	reviewCmd.Flags().StringVarP(&reviewComment, "message", "m", "", "Comment added to the task as a note; required to reopen")
+}
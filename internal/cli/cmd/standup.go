@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/soarinferret/jats/internal/cli/client"
+)
+
+var standupDate string
+
+var standupCmd = &cobra.Command{
+	Use:   "standup",
+	Short: "Print a standup report as markdown",
+	Long: `Print what the team resolved on the previous working day, what is in
+progress and what is blocked (open or in-progress tasks tagged "blocked").
+
+Examples:
+  jats standup
+  jats standup --date "last friday"
+  jats standup > standup.md`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, err := resolveDate(standupDate)
+		if err != nil {
+			return err
+		}
+
+		c := client.New()
+
+		report, err := c.GetStandupReport(date)
+		if err != nil {
+			return fmt.Errorf("failed to get standup report: %w", err)
+		}
+
+		fmt.Print(report.Markdown)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(standupCmd)
+	standupCmd.Flags().StringVarP(&standupDate, "date", "d", "", "Day of the standup (default today, e.g. yesterday, 2024-03-01)")
+}
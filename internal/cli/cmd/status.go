@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/soarinferret/jats/internal/cli/client"
+	"github.com/soarinferret/jats/internal/models"
 )
 
 var closeCmd = &cobra.Command{
@@ -59,19 +60,23 @@ func updateTaskStatus(taskIDStr, status string) error {
 		return fmt.Errorf("failed to update task status: %w", err)
 	}
 
-	statusVerbs := map[string]string{
-		"open":        "reopened",
-		"in-progress": "started",
-		"resolved":    "closed",
-		"closed":      "closed",
+	statusMessages := map[string]string{
+		"open":        "cli_task_reopened",
+		"in-progress": "cli_task_started",
+		"resolved":    "cli_task_closed",
+		"closed":      "cli_task_closed",
 	}
 
-	verb := statusVerbs[status]
-	if verb == "" {
-		verb = fmt.Sprintf("marked as %s", status)
+	data := map[string]interface{}{"ID": task.ID, "Name": task.Name, "Status": localizer().Status(status)}
+	messageID := statusMessages[status]
+	if task.Status == models.TaskStatusPendingReview {
+		// Resolving a task that needs review leaves it waiting for a reviewer
+		messageID = "cli_task_pending_review"
+	} else if messageID == "" {
+		messageID = "cli_task_marked"
 	}
 
-	fmt.Printf("✓ Task #%d %s: %s\n", task.ID, verb, task.Name)
+	fmt.Println(tr(messageID, data))
 	return nil
 }
 
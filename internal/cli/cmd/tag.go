@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/soarinferret/jats/internal/cli/client"
+)
+
+var (
+	tagFilters []string
+	tagAll     bool
+	tagDryRun  bool
+	tagSince   string
+	tagUntil   string
+)
+
+var tagFilterKeys = map[string]bool{
+	"status":    true,
+	"priority":  true,
+	"tags":      true,
+	"milestone": true,
+	"search":    true,
+	"in":        true,
+}
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Add or remove a tag on many tasks at once, or show tag workload",
+	Long: `Add or remove a tag on every task matching a filter, or show the workload
+on each tag.
+
+Filters use key=value with the keys status, priority, tags, milestone and
+search; comma-separated values match any of them. Give --filter several times
+to combine filters. Search looks in names, descriptions, notes and time
+entries; narrow it with in, e.g. in=comments.
+
+Examples:
+  jats tag add urgent --filter status=open --filter search=outage
+  jats tag add q3 --filter milestone=2 --dry-run
+  jats tag remove stale --filter status=resolved,closed
+  jats tag add reviewed --all
+  jats tag stats --since "last monday"`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add <tag>",
+	Short: "Add a tag to all tasks matching a filter",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBulkTag(args[0], "apply")
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove <tag>",
+	Short: "Remove a tag from all tasks matching a filter",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBulkTag(args[0], "remove")
+	},
+}
+
+var tagStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show open tasks, resolved tasks and time logged per tag",
+	Long: `Show per tag how many tasks are open and in progress, and how many were
+resolved and how much time was logged in a period (the last 7 days by default).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := client.New()
+
+		stats, err := c.GetTagStats(tagSince, tagUntil)
+		if err != nil {
+			return fmt.Errorf("failed to get tag stats: %w", err)
+		}
+		if len(stats) == 0 {
+			fmt.Println("No tags in use")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(w, "TAG	OPEN	IN PROGRESS	RESOLVED	LOGGED\n")
+		for _, tag := range stats {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", tag.Tag, tag.OpenTasks, tag.InProgressTasks, tag.ResolvedTasks,
+				formatDurationDisplay(time.Duration(tag.LoggedMinutes)*time.Minute))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+func runBulkTag(tag, action string) error {
+	filter, err := parseTagFilters(tagFilters)
+	if err != nil {
+		return err
+	}
+	if len(filter) == 0 && !tagAll {
+		return fmt.Errorf("a --filter is required (or --all to change every task)")
+	}
+	if tagAll {
+		filter.Set("all", "true")
+	}
+
+	c := client.New()
+
+	result, err := c.BulkUpdateTag(tag, action, filter, tagDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+
+	verb, preposition := "Added", "to"
+	if action == "remove" {
+		verb, preposition = "Removed", "from"
+	}
+	if result.DryRun {
+		verb = map[string]string{"apply": "Would add", "remove": "Would remove"}[action]
+	}
+
+	fmt.Printf("%s '%s' %s %d of %d matching tasks\n", verb, result.Tag, preposition, result.Affected, result.Matched)
+	if len(result.TaskIDs) > 0 {
+		ids := make([]string, len(result.TaskIDs))
+		for i, id := range result.TaskIDs {
+			ids[i] = fmt.Sprintf("#%d", id)
+		}
+		fmt.Printf("  %s\n", strings.Join(ids, " "))
+	}
+
+	return nil
+}
+
+// parseTagFilters turns key=value arguments into task list query parameters
+func parseTagFilters(filters []string) (url.Values, error) {
+	values := url.Values{}
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		key = strings.TrimSpace(key)
+		if !ok || strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("invalid filter %q (expected key=value)", filter)
+		}
+		if !tagFilterKeys[key] {
+			return nil, fmt.Errorf("unknown filter %q (expected status, priority, tags, milestone, search or in)", key)
+		}
+		values.Set(key, strings.TrimSpace(value))
+	}
+	return values, nil
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+	tagCmd.AddCommand(tagStatsCmd)
+	for _, bulkCmd := range []*cobra.Command{tagAddCmd, tagRemoveCmd} {
+		bulkCmd.Flags().StringArrayVarP(&tagFilters, "filter", "f", nil, "Filter as key=value (status, priority, tags, milestone, search, in)")
+		bulkCmd.Flags().BoolVar(&tagAll, "all", false, "Change every task (no filter)")
+		bulkCmd.Flags().BoolVarP(&tagDryRun, "dry-run", "n", false, "Show how many tasks would change without changing them")
+	}
+	tagStatsCmd.Flags().StringVar(&tagSince, "since", "", "Start date (yesterday, \"last monday\", -2d, 2025-12-01; default: last 7 days)")
+	tagStatsCmd.Flags().StringVar(&tagUntil, "until", "", "End date, inclusive")
+}
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -10,6 +13,9 @@ import (
 	"github.com/rivo/tview"
 	"github.com/spf13/cobra"
 	"github.com/soarinferret/jats/internal/cli/client"
+	"github.com/soarinferret/jats/internal/cli/config"
+	"github.com/soarinferret/jats/internal/cli/notify"
+	"github.com/soarinferret/jats/internal/models"
 )
 
 var tuiCmd = &cobra.Command{
@@ -33,9 +39,13 @@ type TUI struct {
 	statusBar   *tview.TextView
 	timeDialog  *tview.Modal
 	tasks       []client.Task
-	savedQueries []client.SavedQuery
+	savedQueries []client.SavedQuery // shown in the sidebar: those of the project and those for every project
+	allSavedQueries []client.SavedQuery
 	selectedQuery string
 	globalInputHandler func(event *tcell.EventKey) *tcell.EventKey
+
+	// Colors of the configured theme
+	theme palette
 	
 	// Pagination fields
 	currentPage int
@@ -45,6 +55,32 @@ type TUI struct {
 	// Search fields
 	searchQuery string
 	searchActive bool
+	searchMatches map[uint]client.SearchResult // best match per listed task, while searching
+
+	// Tag filter applied on top of the selected query
+	tagFilter map[string]tagFilterMode
+
+	// Context quick filter, e.g. "@home" or "none"; cycled with @
+	contextFilter string
+
+	// Project the sidebar and task list are narrowed to, 0 for every
+	// project; switched with w in the sidebar
+	projectID   uint
+	projectName string
+
+	// Order tasks by my latest comment, time entry or status change
+	sortRecent bool
+
+	// Index into summaryWindows of the header's window
+	summaryWindow int
+
+	// Async loading fields, only touched on the UI goroutine
+	loadSeq          map[string]int // latest load started per kind; older results are dropped
+	loading          int            // loads in flight
+	spinnerFrame     int
+	spinnerStop      chan struct{}
+	queryTimer       *time.Timer
+	focusTasksOnLoad bool
 	
 	// Layout fields
 	showSidebar bool
@@ -55,15 +91,41 @@ type TUI struct {
 	stopRefresh   chan bool
 }
 
+// tagFilterMode is how the tag filter overlay applies a tag to the task list
+type tagFilterMode int
+
+const (
+	tagFilterOff tagFilterMode = iota
+	tagFilterInclude
+	tagFilterExclude
+)
+
+// builtinQueries is how many built-in queries sit above the saved queries in
+// the sidebar: All Active Tasks, Overdue and Resolved
+const builtinQueries = 3
+
+// summaryWindows are the header's summary windows, cycled with W
+var summaryWindows = []string{"7d", "30d", "quarter", "today"}
+
+// spinnerFrames animate the tasks table title while data loads
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// queryDebounce delays loading after a query switch, so flicking through the
+// sidebar only loads the query that is finally selected
+const queryDebounce = 200 * time.Millisecond
+
 // NewTUI creates a new TUI instance
 func NewTUI() *TUI {
 	return &TUI{
 		app:         tview.NewApplication(),
 		client:      client.New(),
+		theme:       configuredPalette(),
 		currentPage: 0,
 		pageSize:    20,
 		searchQuery: "",
 		searchActive: false,
+		tagFilter:   make(map[string]tagFilterMode),
+		loadSeq:     make(map[string]int),
 		showSidebar: true,  // Default to true, will be updated in Run()
 		stopRefresh: make(chan bool),
 	}
@@ -81,6 +143,9 @@ func (t *TUI) Run() error {
 		}
 	}
 
+	// Apply the theme before creating components, which take tview's styles
+	t.theme.apply()
+
 	// Initialize components
 	t.setupHeader()
 	t.setupSidebar()
@@ -89,8 +154,9 @@ func (t *TUI) Run() error {
 	t.setupLayout()
 	t.setupKeyBindings()
 
-	// Load initial data
-	if err := t.refreshData(); err != nil {
+	// Load initial data before taking over the terminal, so a login prompt
+	// still works; later loads run in the background
+	if err := t.loadInitialData(); err != nil {
 		return fmt.Errorf("failed to load initial data: %w", err)
 	}
 
@@ -101,6 +167,12 @@ func (t *TUI) Run() error {
 	// Start auto-refresh goroutine
 	t.startAutoRefresh()
 
+	// Notify about mentions while the TUI runs
+	t.startMentionNotifications()
+
+	// Refresh as tasks change on the server
+	t.startLiveUpdates()
+
 	// Ensure cleanup when app stops
 	defer t.stopAutoRefresh()
 
@@ -121,6 +193,7 @@ func (t *TUI) setupHeader() {
 func (t *TUI) setupSidebar() {
 	t.sidebar = tview.NewList()
 	t.sidebar.SetBorder(true).SetTitle("Saved Queries")
+	t.sidebar.SetSelectedStyle(t.theme.selectedStyle())
 
 	// Set default selection (sidebar will be populated by loadSavedQueries)
 	t.selectedQuery = "active"
@@ -131,12 +204,13 @@ func (t *TUI) setupTasksTable() {
 	t.tasksTable = tview.NewTable()
 	t.tasksTable.SetBorder(true).SetTitle("Tasks")
 	t.tasksTable.SetSelectable(true, false)
+	t.tasksTable.SetSelectedStyle(t.theme.selectedStyle())
 	
 	// Set headers
-	headers := []string{"✓", "Name", "Tags", "Subtasks", "Time", "Priority", "Status"}
+	headers := []string{"✓", "Name", "Tags", "Subtasks", "Time", "Due", "Priority", "Status"}
 	for i, header := range headers {
 		cell := tview.NewTableCell(header).
-			SetTextColor(tcell.ColorYellow).
+			SetTextColor(t.theme.Accent).
 			SetAlign(tview.AlignCenter).
 			SetSelectable(false)
 		t.tasksTable.SetCell(0, i, cell)
@@ -146,7 +220,7 @@ func (t *TUI) setupTasksTable() {
 // setupStatusBar creates the bottom status bar
 func (t *TUI) setupStatusBar() {
 	t.statusBar = tview.NewTextView().
-		SetText("[yellow]r[white]: Resolve | [yellow]c[white]: Comment | [yellow]s[white]: Subtasks | [yellow]t[white]: Add Time | [yellow]Enter[white]: Details | [yellow]q[white]: Quit").
+		SetText(t.theme.hints("r", "Resolve", "c", "Comment", "s", "Subtasks", "t", "Add Time", "Enter", "Details", "q", "Quit")).
 		SetDynamicColors(true)
 	t.statusBar.SetBorder(false)
 }
@@ -212,6 +286,12 @@ func (t *TUI) setupKeyBindings() {
 		case 'A':
 			t.showCreateTaskModal()
 			return nil
+		case 'T':
+			t.showTimeEntries(false)
+			return nil
+		case 'W':
+			t.cycleSummaryWindow()
+			return nil
 		}
 		
 		switch event.Key() {
@@ -274,6 +354,15 @@ func (t *TUI) handleTaskPaneKeys(event *tcell.EventKey) *tcell.EventKey {
 	case 'x':
 		t.clearSearch()
 		return nil
+	case 'f':
+		t.showTagFilterDialog()
+		return nil
+	case 'o':
+		t.toggleRecentSort()
+		return nil
+	case '@':
+		t.cycleContextFilter()
+		return nil
 	}
 	
 	switch event.Key() {
@@ -297,12 +386,46 @@ func (t *TUI) handleQueryPaneKeys(event *tcell.EventKey) *tcell.EventKey {
 	case 'n':
 		t.showNewQueryDialog()
 		return nil
+	case 'f':
+		t.showTagFilterDialog()
+		return nil
+	case 'e':
+		if query := t.currentSavedQuery(); query != nil {
+			t.showQueryDialog(query)
+		}
+		return nil
+	case 'd':
+		if query := t.currentSavedQuery(); query != nil {
+			t.showQueryDeleteConfirm(query)
+		}
+		return nil
+	case 'K':
+		t.moveCurrentQuery(-1)
+		return nil
+	case 'J':
+		t.moveCurrentQuery(1)
+		return nil
+	case 'P':
+		t.togglePinCurrentQuery()
+		return nil
+	case 'w':
+		t.cycleProject()
+		return nil
 	}
 	
 	switch event.Key() {
 	case tcell.KeyEnter:
 		t.selectCurrentQuery()
 		return nil
+	case tcell.KeyUp, tcell.KeyDown:
+		if event.Modifiers()&tcell.ModShift != 0 {
+			if event.Key() == tcell.KeyUp {
+				t.moveCurrentQuery(-1)
+			} else {
+				t.moveCurrentQuery(1)
+			}
+			return nil
+		}
 	}
 	
 	return event
@@ -326,35 +449,115 @@ func (t *TUI) selectCurrentQuery() {
 	switch currentItem {
 	case 0: // All Active Tasks
 		t.selectedQuery = "active"
-	case 1: // Resolved
+	case 1: // Overdue
+		t.selectedQuery = "overdue"
+	case 2: // Resolved
 		t.selectedQuery = "resolved"
 	default:
-		// Saved query (currentItem - 2 since we have 2 default queries)
-		savedIndex := currentItem - 2
+		// Saved queries follow the built-in ones
+		savedIndex := currentItem - builtinQueries
 		if savedIndex >= 0 && savedIndex < len(t.savedQueries) {
 			t.selectedQuery = fmt.Sprintf("saved:%d", t.savedQueries[savedIndex].ID)
 		}
 	}
 
+	t.setStatus(fmt.Sprintf("Selected query: %s", selected))
+
+	// Move focus to the tasks table once they have loaded, if there are any
+	t.focusTasksOnLoad = true
+	t.scheduleQueryLoad()
+}
+
+// scheduleQueryLoad reloads the tasks and header for the selected query once
+// the selection has settled
+func (t *TUI) scheduleQueryLoad() {
 	// Reset pagination when changing query
 	t.currentPage = 0
 
-	t.refreshTasksOnly()
-	if err := t.updateHeader(); err != nil {
-		t.setStatus(fmt.Sprintf("Error updating header: %v", err))
-	} else {
-		t.setStatus(fmt.Sprintf("Selected query: %s", selected))
+	if t.queryTimer != nil {
+		t.queryTimer.Stop()
+	}
+	t.queryTimer = time.AfterFunc(queryDebounce, func() {
+		t.app.QueueUpdateDraw(func() {
+			t.refreshTasksOnly()
+			t.updateHeader()
+		})
+	})
+}
+
+// loadAsync runs fetch on a background goroutine with the spinner showing and
+// hands its result to apply on the UI goroutine. Only the latest load of each
+// kind is applied, so a slow response cannot overwrite a newer one.
+func loadAsync[T any](t *TUI, kind string, fetch func() (T, error), apply func(T, error)) {
+	t.loadSeq[kind]++
+	seq := t.loadSeq[kind]
+	t.startLoading()
+
+	go func() {
+		value, err := fetch()
+		t.app.QueueUpdateDraw(func() {
+			t.stopLoading()
+			if seq == t.loadSeq[kind] {
+				apply(value, err)
+			}
+		})
+	}()
+}
+
+// startLoading shows the spinner while at least one load is in flight
+func (t *TUI) startLoading() {
+	t.loading++
+	if t.loading > 1 {
+		return
 	}
 
-	// Only switch focus to tasks table if there are tasks to display
-	// Otherwise keep focus on sidebar to avoid freeze
-	if len(t.tasks) > 0 {
-		t.app.SetFocus(t.tasksTable)
-		t.updateStatusForPane("tasks")
+	stop := make(chan struct{})
+	t.spinnerStop = stop
+	t.updateTasksTitle()
+
+	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				t.app.QueueUpdateDraw(func() {
+					t.spinnerFrame = (t.spinnerFrame + 1) % len(spinnerFrames)
+					t.updateTasksTitle()
+				})
+			}
+		}
+	}()
+}
+
+// stopLoading hides the spinner when the last load finishes
+func (t *TUI) stopLoading() {
+	t.loading--
+	if t.loading == 0 {
+		close(t.spinnerStop)
+		t.updateTasksTitle()
+	}
+}
+
+// updateTasksTitle shows the spinner in the tasks table title while loading
+func (t *TUI) updateTasksTitle() {
+	if t.loading > 0 {
+		t.tasksTable.SetTitle(fmt.Sprintf("Tasks %c Loading...", spinnerFrames[t.spinnerFrame]))
 	} else {
-		// Keep focus on sidebar when no tasks found
-		t.setStatus(fmt.Sprintf("No tasks found for query: %s", selected))
-		t.updateStatusForPane("queries")
+		title := "Tasks"
+		if t.sortRecent {
+			title += " (recently touched)"
+		}
+		switch t.contextFilter {
+		case "":
+		case "none":
+			title += " [no context]"
+		default:
+			title += " [" + t.contextFilter + "]"
+		}
+		t.tasksTable.SetTitle(tview.Escape(title))
 	}
 }
 
@@ -362,13 +565,13 @@ func (t *TUI) selectCurrentQuery() {
 func (t *TUI) updateStatusForPane(pane string) {
 	tabText := ""
 	if t.showSidebar {
-		tabText = " | [yellow]Tab[white]: Switch Panes"
+		tabText = " | " + t.theme.hints("Tab", "Switch Panes")
 	}
 	
 	if pane == "tasks" {
-		t.statusBar.SetText("[yellow]A[white]: Add Task | [yellow]r[white]: Resolve/Reopen | [yellow]e[white]: Edit | [yellow]c[white]: Comment | [yellow]t[white]: Add Time | [yellow]/[white]: Search | [yellow]n/p[white]: Next/Prev Page | [yellow]x[white]: Clear Search | [yellow]Enter[white]: Details" + tabText + " | [yellow]Q[white]: Toggle Sidebar | [yellow]q[white]: Quit")
+		t.statusBar.SetText(t.theme.hints("A", "Add Task", "T", "Time Entries", "r", "Resolve/Reopen", "e", "Edit", "c", "Comment", "t", "Add Time", "/", "Search", "f", "Filter Tags", "@", "Context", "o", "Recently Touched", "n/p", "Next/Prev Page", "x", "Clear Search", "W", "Summary Window", "Enter", "Details") + tabText + " | " + t.theme.hints("Q", "Toggle Sidebar", "q", "Quit"))
 	} else if pane == "queries" {
-		t.statusBar.SetText("[yellow]A[white]: Add Task | [yellow]n[white]: New Query | [yellow]Enter[white]: Select Query" + tabText + " | [yellow]Q[white]: Toggle Sidebar | [yellow]q[white]: Quit")
+		t.statusBar.SetText(t.theme.hints("A", "Add Task", "n", "New Query", "e", "Edit", "d", "Delete", "J/K", "Move Down/Up", "P", "Pin", "w", "Project", "Enter", "Select Query") + tabText + " | " + t.theme.hints("Q", "Toggle Sidebar", "q", "Quit"))
 	}
 }
 
@@ -382,43 +585,85 @@ func (t *TUI) enableGlobalKeys() {
 	t.app.SetInputCapture(t.globalInputHandler)
 }
 
-// refreshData refreshes all data
-func (t *TUI) refreshData() error {
-	// Load saved queries and update sidebar
-	if err := t.loadSavedQueries(); err != nil {
+// loadInitialData loads the sidebar, header and tasks synchronously, before the
+// application starts
+func (t *TUI) loadInitialData() error {
+	// Open on the user's landing saved query, if they have one
+	if prefs, err := t.client.GetPreferences(); err == nil && prefs.LandingQueryID != nil {
+		t.selectedQuery = fmt.Sprintf("saved:%d", *prefs.LandingQueryID)
+	}
+
+	if savedQueries, err := t.client.GetSavedQueriesWithCounts(); err != nil {
 		t.setStatus(fmt.Sprintf("Error loading saved queries: %v", err))
+	} else {
+		t.renderSavedQueries(savedQueries)
 	}
-	
-	// Update header with task counts
-	if err := t.updateHeader(); err != nil {
+
+	savedQueryID := t.selectedSavedQueryID()
+	if summary, err := t.client.GetTaskSummary(savedQueryID, summaryWindows[t.summaryWindow]); err != nil {
 		t.setStatus(fmt.Sprintf("Error updating header: %v", err))
+	} else {
+		t.renderHeader(summary, savedQueryID)
 	}
-	
-	// Refresh tasks
-	return t.refreshTasks()
-}
 
-// loadSavedQueries loads saved queries from the API and adds them to the sidebar
-func (t *TUI) loadSavedQueries() error {
-	savedQueries, err := t.client.GetSavedQueries()
+	filters := t.taskFilters()
+	tasks, err := t.client.GetTasks(filters)
 	if err != nil {
 		return err
 	}
-	
-	t.savedQueries = savedQueries
-	
+	t.searchMatches = t.fetchSearchMatches(filters.Search)
+	t.renderTasks(tasks)
+	return nil
+}
+
+// refreshData reloads the sidebar, header and tasks in the background
+func (t *TUI) refreshData() {
+	t.loadSavedQueries()
+	t.updateHeader()
+	t.refreshTasksOnly()
+}
+
+// loadSavedQueries loads saved queries from the API in the background and
+// rebuilds the sidebar
+func (t *TUI) loadSavedQueries() {
+	loadAsync(t, "queries", t.client.GetSavedQueriesWithCounts, func(savedQueries []client.SavedQuery, err error) {
+		if err != nil {
+			t.setStatus(fmt.Sprintf("Error loading saved queries: %v", err))
+			return
+		}
+		t.renderSavedQueries(savedQueries)
+	})
+}
+
+// renderSavedQueries rebuilds the sidebar from every saved query, showing
+// those of the selected project
+func (t *TUI) renderSavedQueries(allSavedQueries []client.SavedQuery) {
+	t.allSavedQueries = allSavedQueries
+	t.savedQueries = projectSavedQueries(allSavedQueries, t.projectID)
+	savedQueries := t.savedQueries
+
 	// Clear existing sidebar and rebuild it
 	t.sidebar.Clear()
+	title := "Saved Queries"
+	if t.projectID != 0 {
+		title += " · " + t.projectName
+	}
+	t.sidebar.SetTitle(tview.Escape(title))
 
 	// Re-add default queries first
 	t.sidebar.AddItem("All Active Tasks", "Show open and in-progress tasks", 'a', func() {
 		t.selectedQuery = "active"
-		t.refreshTasksOnly()
+		t.scheduleQueryLoad()
+	})
+
+	t.sidebar.AddItem("Overdue", "Show open tasks past their due date", 'o', func() {
+		t.selectedQuery = "overdue"
+		t.scheduleQueryLoad()
 	})
 
 	t.sidebar.AddItem("Resolved", "Show resolved tasks", 'r', func() {
 		t.selectedQuery = "resolved"
-		t.refreshTasksOnly()
+		t.scheduleQueryLoad()
 	})
 	
 	// Add saved queries to sidebar after default ones
@@ -433,16 +678,121 @@ func (t *TUI) loadSavedQueries() error {
 			shortcut = 0 // No shortcut for 10th and beyond
 		}
 
-		t.sidebar.AddItem(query.Name, fmt.Sprintf("Tags: %s", strings.Join(query.IncludedTags, ", ")), shortcut, func() {
+		// Show how many open tasks each query matches next to its name, with
+		// the number in progress and the time logged this week
+		label := tview.Escape(query.Name)
+		if query.Pinned {
+			label = t.theme.color(t.theme.Accent, "📌") + " " + label
+		}
+		if query.OpenCount != nil {
+			badge := fmt.Sprintf("%d", *query.OpenCount)
+			if query.InProgress != nil && *query.InProgress > 0 {
+				badge += fmt.Sprintf(" · %d▶", *query.InProgress)
+			}
+			if query.WeekMinutes != nil && *query.WeekMinutes > 0 {
+				badge += " · " + formatMinutes(*query.WeekMinutes)
+			}
+			label += " " + t.theme.color(t.theme.Muted, "("+badge+")")
+		}
+
+		description := fmt.Sprintf("Tags: %s", strings.Join(query.IncludedTags, ", "))
+		if query.Expression != "" && len(query.IncludedTags) == 0 {
+			description = "Where: " + query.Expression
+		} else if query.Expression != "" {
+			description += " · " + query.Expression
+		}
+		t.sidebar.AddItem(label, description, shortcut, func() {
 			t.selectedQuery = fmt.Sprintf("saved:%d", query.ID)
-			t.refreshTasksOnly()
+			t.scheduleQueryLoad()
 		})
 	}
 	
 	// Restore selection to the currently active query
 	t.restoreSidebarSelection()
-	
-	return nil
+}
+
+// currentSavedQuery returns the saved query highlighted in the sidebar, or nil
+// when a built-in query is highlighted
+func (t *TUI) currentSavedQuery() *client.SavedQuery {
+	savedIndex := t.sidebar.GetCurrentItem() - builtinQueries
+	if savedIndex < 0 || savedIndex >= len(t.savedQueries) {
+		t.setStatus("Built-in queries cannot be changed")
+		return nil
+	}
+	return &t.savedQueries[savedIndex]
+}
+
+// moveCurrentQuery moves the highlighted saved query up (-1) or down (1) and
+// saves the new order
+func (t *TUI) moveCurrentQuery(delta int) {
+	query := t.currentSavedQuery()
+	if query == nil {
+		return
+	}
+	from := t.sidebar.GetCurrentItem() - builtinQueries
+	to := from + delta
+	if to < 0 || to >= len(t.savedQueries) {
+		return
+	}
+
+	if t.savedQueries[from].Pinned != t.savedQueries[to].Pinned {
+		t.setStatus("Pinned queries stay above the rest; press P to unpin")
+		return
+	}
+
+	// Swap the two queries in the full list, which may hold queries of other
+	// projects between them
+	reordered := slices.Clone(t.allSavedQueries)
+	fromID, toID := t.savedQueries[from].ID, t.savedQueries[to].ID
+	a := slices.IndexFunc(reordered, func(sq client.SavedQuery) bool { return sq.ID == fromID })
+	b := slices.IndexFunc(reordered, func(sq client.SavedQuery) bool { return sq.ID == toID })
+	reordered[a], reordered[b] = reordered[b], reordered[a]
+
+	// Pinning is per user, so swap the two queries in the shared order rather
+	// than saving the pinned-first order everyone would then see
+	shared := slices.Clone(t.allSavedQueries)
+	slices.SortStableFunc(shared, func(a, b client.SavedQuery) int { return a.Position - b.Position })
+	ids := make([]uint, len(shared))
+	for i, sq := range shared {
+		ids[i] = sq.ID
+	}
+	i, j := slices.Index(ids, fromID), slices.Index(ids, toID)
+	ids[i], ids[j] = ids[j], ids[i]
+
+	if _, err := t.client.ReorderSavedQueries(ids); err != nil {
+		t.setStatus(fmt.Sprintf("Error reordering saved queries: %v", err))
+		return
+	}
+	t.renderSavedQueries(reordered)
+	// Keep the moved query highlighted rather than the active one
+	t.sidebar.SetCurrentItem(builtinQueries + to)
+}
+
+// togglePinCurrentQuery pins the highlighted saved query to the top of the
+// sidebar, or unpins it
+func (t *TUI) togglePinCurrentQuery() {
+	query := t.currentSavedQuery()
+	if query == nil {
+		return
+	}
+
+	var ids []uint
+	for _, sq := range t.allSavedQueries {
+		if sq.Pinned != (sq.ID == query.ID) {
+			ids = append(ids, sq.ID)
+		}
+	}
+	if err := t.client.SetPinnedQueries(ids); err != nil {
+		t.setStatus(fmt.Sprintf("Error pinning saved query: %v", err))
+		return
+	}
+
+	if query.Pinned {
+		t.setStatus(fmt.Sprintf("Unpinned %s", query.Name))
+	} else {
+		t.setStatus(fmt.Sprintf("Pinned %s", query.Name))
+	}
+	t.loadSavedQueries()
 }
 
 // restoreSidebarSelection restores the sidebar selection based on current selectedQuery
@@ -450,8 +800,10 @@ func (t *TUI) restoreSidebarSelection() {
 	switch t.selectedQuery {
 	case "active":
 		t.sidebar.SetCurrentItem(0)
-	case "resolved":
+	case "overdue":
 		t.sidebar.SetCurrentItem(1)
+	case "resolved":
+		t.sidebar.SetCurrentItem(2)
 	default:
 		// Check if it's a saved query
 		if strings.HasPrefix(t.selectedQuery, "saved:") {
@@ -460,7 +812,7 @@ func (t *TUI) restoreSidebarSelection() {
 				// Find the saved query index
 				for i, sq := range t.savedQueries {
 					if sq.ID == uint(id) {
-						t.sidebar.SetCurrentItem(2 + i) // 2 default queries + saved query index
+						t.sidebar.SetCurrentItem(builtinQueries + i)
 						return
 					}
 				}
@@ -471,31 +823,49 @@ func (t *TUI) restoreSidebarSelection() {
 	}
 }
 
-// updateHeader updates the header with current task counts using the summary API
-func (t *TUI) updateHeader() error {
-	// Determine saved query ID for filtering
-	var savedQueryID *uint = nil
-	
+// selectedSavedQueryID returns the ID of the selected saved query, or nil for
+// the built-in queries
+func (t *TUI) selectedSavedQueryID() *uint {
 	// Check if current selection is a saved query
 	if strings.HasPrefix(t.selectedQuery, "saved:") {
 		idStr := strings.TrimPrefix(t.selectedQuery, "saved:")
 		if id, err := strconv.ParseUint(idStr, 10, 32); err == nil {
 			queryID := uint(id)
-			savedQueryID = &queryID
+			return &queryID
 		}
 	}
-	
-	// Get summary from API endpoint
-	summary, err := t.client.GetTaskSummary(savedQueryID)
-	if err != nil {
-		return err
-	}
-	
+	return nil
+}
+
+// updateHeader updates the header with current task counts using the summary API
+func (t *TUI) updateHeader() {
+	savedQueryID := t.selectedSavedQueryID()
+	window := summaryWindows[t.summaryWindow]
+	loadAsync(t, "header", func() (*client.TaskSummaryResponse, error) {
+		return t.client.GetTaskSummary(savedQueryID, window)
+	}, func(summary *client.TaskSummaryResponse, err error) {
+		if err != nil {
+			t.setStatus(fmt.Sprintf("Error updating header: %v", err))
+			return
+		}
+		t.renderHeader(summary, savedQueryID)
+	})
+}
+
+// cycleSummaryWindow switches the header to the next summary window
+func (t *TUI) cycleSummaryWindow() {
+	t.summaryWindow = (t.summaryWindow + 1) % len(summaryWindows)
+	t.updateHeader()
+	t.setStatus(fmt.Sprintf("Summary window: %s", summaryWindows[t.summaryWindow]))
+}
+
+// renderHeader shows the task counts of a summary in the header
+func (t *TUI) renderHeader(summary *client.TaskSummaryResponse, savedQueryID *uint) {
 	// Build header text with current filter context
 	filterText := ""
 	if savedQueryID != nil {
 		// Find saved query name for display
-		for _, sq := range t.savedQueries {
+		for _, sq := range t.allSavedQueries {
 			if sq.ID == *savedQueryID {
 				filterText = fmt.Sprintf(" (Filtered: %s)", sq.Name)
 				break
@@ -503,29 +873,122 @@ func (t *TUI) updateHeader() error {
 		}
 	}
 	
+	window := summary.Window
+	if window == "" {
+		window = summaryWindows[t.summaryWindow]
+	}
+
+	p := t.theme
+	overdueText := ""
+	if summary.OverdueTasks > 0 {
+		overdueText = " | " + p.color(p.Danger, fmt.Sprintf("Overdue: %d", summary.OverdueTasks))
+	}
 	headerText := fmt.Sprintf(
-		"[green]Open: %d[white] | [yellow]In Progress: %d[white] | [cyan]Added (7d): %d[white] | [blue]Resolved (7d): %d[white]%s",
-		summary.OpenTasks,
-		summary.InProgressTasks,
-		summary.RecentlyAddedTasks,
-		summary.RecentlyResolvedTasks,
+		"%s | %s | %s | %s | %s%s%s",
+		p.color(p.Success, fmt.Sprintf("Open: %d", summary.OpenTasks)),
+		p.color(p.Accent, fmt.Sprintf("In Progress: %d", summary.InProgressTasks)),
+		p.color(p.Info, fmt.Sprintf("Added (%s): %d%s", window, summary.RecentlyAddedTasks, trendMarker(summary.Trends.AddedTasks, strconv.Itoa(abs(summary.Trends.AddedTasks))))),
+		p.color(p.Secondary, fmt.Sprintf("Resolved (%s): %d%s", window, summary.RecentlyResolvedTasks, trendMarker(summary.Trends.ResolvedTasks, strconv.Itoa(abs(summary.Trends.ResolvedTasks))))),
+		p.color(p.Highlight, fmt.Sprintf("Logged (%s): %s%s", window,
+			formatDurationDisplay(time.Duration(summary.RecentlyLoggedMinutes)*time.Minute),
+			trendMarker(summary.Trends.LoggedMinutes, formatDurationDisplay(time.Duration(abs(summary.Trends.LoggedMinutes))*time.Minute)))),
+		overdueText,
 		filterText,
 	)
 	
 	t.header.SetText(headerText)
-	return nil
 }
 
-// refreshTasks refreshes the tasks table based on selected query
-func (t *TUI) refreshTasks() error {
-	return t.refreshTasksOnly()
+// trendMarker shows how a count changed against the previous 7 days, e.g. " ▲3"
+func trendMarker(delta int, amount string) string {
+	switch {
+	case delta > 0:
+		return " ▲" + amount
+	case delta < 0:
+		return " ▼" + amount
+	}
+	return ""
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
 }
 
 // refreshTasksOnly refreshes only the tasks without reloading saved queries (prevents infinite loops)
-func (t *TUI) refreshTasksOnly() error {
+func (t *TUI) refreshTasksOnly() {
+	t.loadTasks(nil)
+}
+
+// loadTasks loads the current page of tasks in the background, calling
+// onError on the UI goroutine if that fails
+func (t *TUI) loadTasks(onError func()) {
+	filters := t.taskFilters()
+	loadAsync(t, "tasks", func() (taskPage, error) {
+		tasks, err := t.client.GetTasks(filters)
+		if err != nil {
+			return taskPage{}, err
+		}
+		return taskPage{tasks: tasks, matches: t.fetchSearchMatches(filters.Search)}, nil
+	}, func(page taskPage, err error) {
+		if err != nil {
+			t.setStatus(fmt.Sprintf("Error loading tasks: %v", err))
+			if onError != nil {
+				onError()
+			}
+			return
+		}
+		t.searchMatches = page.matches
+		t.renderTasks(page.tasks)
+	})
+}
+
+// taskPage is a loaded page of tasks with where the search matched them
+type taskPage struct {
+	tasks   []client.Task
+	matches map[uint]client.SearchResult
+}
+
+// fetchSearchMatches returns the best search match per task: the name or
+// description, else the first matching comment. Snippets are only context for
+// the list, so failing to get them is not an error.
+func (t *TUI) fetchSearchMatches(query string) map[uint]client.SearchResult {
+	if query == "" {
+		return nil
+	}
+	results, err := t.client.Search(query)
+	if err != nil {
+		return nil
+	}
+
+	matches := make(map[uint]client.SearchResult)
+	for _, result := range results["tasks"] {
+		matches[result.ID] = result
+	}
+	for _, result := range results["comments"] {
+		if result.TaskID == nil {
+			continue
+		}
+		if _, ok := matches[*result.TaskID]; !ok {
+			matches[*result.TaskID] = result
+		}
+	}
+	return matches
+}
+
+// taskFilters builds the task list filters for the selected query, page,
+// search, tag filter, context and project
+func (t *TUI) taskFilters() *client.TaskFilters {
 	filters := &client.TaskFilters{
-		Limit:  t.pageSize,
-		Offset: t.currentPage * t.pageSize,
+		Limit:   t.pageSize,
+		Offset:  t.currentPage * t.pageSize,
+		Context: t.contextFilter,
+		Project: t.projectID,
+	}
+	if t.sortRecent {
+		filters.Sort = "recently_touched"
 	}
 	
 	// Add search filter if active
@@ -539,12 +1002,16 @@ func (t *TUI) refreshTasksOnly() error {
 		idStr := strings.TrimPrefix(t.selectedQuery, "saved:")
 		if id, err := strconv.ParseUint(idStr, 10, 32); err == nil {
 			// Find the saved query
-			for _, sq := range t.savedQueries {
+			for _, sq := range t.allSavedQueries {
 				if sq.ID == uint(id) {
 					if len(sq.IncludedTags) > 0 {
 						filters.Tags = sq.IncludedTags
 					}
-					// Note: ExcludedTags not currently supported in TaskFilters
+					if sq.ProjectID != nil {
+						filters.Project = *sq.ProjectID
+					}
+					filters.ExcludeTags = append(filters.ExcludeTags, sq.ExcludedTags...)
+					filters.TagExpr = sq.Expression
 					break
 				}
 			}
@@ -556,6 +1023,8 @@ func (t *TUI) refreshTasksOnly() error {
 		switch t.selectedQuery {
 		case "active":
 			filters.Status = []string{"open", "in-progress"}
+		case "overdue":
+			filters.Overdue = true
 		case "resolved":
 			filters.Status = []string{"resolved"}
 		default:
@@ -563,12 +1032,16 @@ func (t *TUI) refreshTasksOnly() error {
 		}
 	}
 	
-	tasks, err := t.client.GetTasks(filters)
-	if err != nil {
-		t.setStatus(fmt.Sprintf("Error loading tasks: %v", err))
-		return err
-	}
+	// Narrow the query further by the tag filter overlay
+	included, excluded := t.tagFilterTags()
+	filters.AllTags = included
+	filters.ExcludeTags = append(filters.ExcludeTags, excluded...)
 	
+	return filters
+}
+
+// renderTasks shows a loaded page of tasks in the table
+func (t *TUI) renderTasks(tasks []client.Task) {
 	t.tasks = tasks
 	t.populateTasksTable()
 	
@@ -577,11 +1050,27 @@ func (t *TUI) refreshTasksOnly() error {
 	if t.searchActive && t.searchQuery != "" {
 		searchInfo = fmt.Sprintf(" (search: %s)", t.searchQuery)
 	}
+	if summary := t.tagFilterSummary(); summary != "" {
+		searchInfo += fmt.Sprintf(" (tags: %s)", summary)
+	}
+	if t.contextFilter != "" {
+		searchInfo += fmt.Sprintf(" (context: %s)", t.contextFilter)
+	}
 	
 	pageInfo := fmt.Sprintf("Page %d%s", t.currentPage+1, searchInfo)
 	t.setStatus(fmt.Sprintf("Loaded %d tasks - %s", len(tasks), pageInfo))
-	
-	return nil
+
+	// After a query switch, only move focus to the tasks table if there are
+	// tasks to display, otherwise keep it on the sidebar to avoid a freeze
+	if t.focusTasksOnLoad {
+		t.focusTasksOnLoad = false
+		if len(tasks) > 0 {
+			t.app.SetFocus(t.tasksTable)
+			t.updateStatusForPane("tasks")
+		} else {
+			t.setStatus("No tasks found for this query")
+		}
+	}
 }
 
 // populateTasksTable populates the tasks table with current tasks
@@ -634,43 +1123,67 @@ func (t *TUI) populateTasksTable() {
 			subtasksStr = "-"
 		}
 
+		// Due date, flagged once it has passed
+		dueText := "-"
+		if task.DueDate != nil {
+			dueText = task.DueDate.Format("Jan 2")
+			if task.Overdue {
+				dueText = t.theme.tag(t.theme.Danger) + dueText + "!"
+			}
+		}
+
 		// Priority color
-		priorityColor := ""
+		priorityColor := t.theme.Text
 		switch task.Priority {
 		case "high":
-			priorityColor = "[red]"
+			priorityColor = t.theme.Danger
 		case "medium":
-			priorityColor = "[yellow]"
+			priorityColor = t.theme.Accent
 		case "low":
-			priorityColor = "[green]"
-		default:
-			priorityColor = "[white]"
+			priorityColor = t.theme.Success
 		}
 		
 		// Status color
-		statusColor := ""
+		statusColor := t.theme.Text
 		switch task.Status {
-		case "open":
-			statusColor = "[white]"
 		case "in-progress":
-			statusColor = "[yellow]"
+			statusColor = t.theme.Accent
+		case "pending-review":
+			statusColor = t.theme.Highlight
 		case "resolved":
-			statusColor = "[green]"
+			statusColor = t.theme.Success
 		case "closed":
-			statusColor = "[gray]"
+			statusColor = t.theme.Muted
+		}
+		statusText := t.theme.tag(statusColor) + string(task.Status)
+
+		// Flag tasks that have been in progress for too long
+		if agingDays := t.agingDays(); agingDays > 0 && task.Status == "in-progress" && task.StatusAgeDays > agingDays {
+			statusText = t.theme.tag(t.theme.Danger) + fmt.Sprintf("%s (%dd)", task.Status, task.StatusAgeDays)
 		}
 		
+		// While searching, show where the task matched
+		nameText := task.Name
+		if match, ok := t.searchMatches[task.ID]; ok && match.Snippet != nil {
+			if match.MatchType == "name" {
+				nameText = t.theme.snippet(match.Snippet, t.theme.Text)
+			} else {
+				nameText = tview.Escape(task.Name) + "  " + t.theme.snippet(match.Snippet, t.theme.Muted) + t.theme.tag(t.theme.Text)
+			}
+		}
+
 		cells := []struct {
 			text  string
 			align int
 		}{
 			{completeSymbol, tview.AlignCenter},
-			{task.Name, tview.AlignLeft},
+			{nameText, tview.AlignLeft},
 			{tagsStr, tview.AlignLeft},
 			{subtasksStr, tview.AlignCenter},
 			{timeStr, tview.AlignRight},
-			{priorityColor + string(task.Priority), tview.AlignCenter},
-			{statusColor + string(task.Status), tview.AlignCenter},
+			{dueText, tview.AlignCenter},
+			{t.theme.tag(priorityColor) + string(task.Priority), tview.AlignCenter},
+			{statusText, tview.AlignCenter},
 		}
 		
 		for col, cell := range cells {
@@ -687,6 +1200,14 @@ func (t *TUI) populateTasksTable() {
 	}
 }
 
+// agingDays returns the configured aging threshold for highlighting stale in-progress tasks
+func (t *TUI) agingDays() int {
+	if cfg := config.GetCurrent(); cfg != nil {
+		return cfg.GetAgingDays()
+	}
+	return config.DefaultAgingDays
+}
+
 // getSelectedTask returns the currently selected task
 func (t *TUI) getSelectedTask() *client.Task {
 	row, _ := t.tasksTable.GetSelection()
@@ -738,17 +1259,44 @@ func (t *TUI) showTimeDialog() {
 	form := tview.NewForm()
 	form.SetBorder(true).SetTitle(fmt.Sprintf("Add Time - %s", task.Name))
 	
-	var duration, description, date string
+	// Start from what I last logged on this task. Until then, or until the
+	// box is toggled, the server decides from the task's tags.
+	username := currentUsername()
+	description := lastTimeDescription(task, username)
+	var duration, date string
+	billable, billableSet := true, false
+	if last := lastTimeBillable(task, username); last != nil {
+		billable, billableSet = *last, true
+	}
 	
-	form.AddInputField("Duration", "", 20, nil, func(text string) {
+	durationField := tview.NewInputField().SetLabel("Duration").SetFieldWidth(20)
+	durationField.SetChangedFunc(func(text string) {
 		duration = text
 	})
-	form.AddInputField("Description", "Time logged via TUI", 50, nil, func(text string) {
+	durationField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case '+':
+			durationField.SetText(adjustDuration(duration, timeStep))
+			return nil
+		case '-':
+			durationField.SetText(adjustDuration(duration, -timeStep))
+			return nil
+		case '=':
+			t.fillSinceLastEntry(durationField, username)
+			return nil
+		}
+		return event
+	})
+	form.AddFormItem(durationField)
+	form.AddInputField("Description", description, 50, nil, func(text string) {
 		description = text
 	})
 	form.AddInputField("Date (optional)", "", 30, nil, func(text string) {
 		date = text
 	})
+	form.AddCheckbox("Billable", billable, func(checked bool) {
+		billable, billableSet = checked, true
+	})
 	
 	originalRoot := t.root
 	
@@ -765,7 +1313,7 @@ func (t *TUI) showTimeDialog() {
 		}
 		
 		if description == "" {
-			description = "Time logged via TUI"
+			description = defaultTimeDescription
 		}
 		
 		timeReq := &client.LogTimeRequest{
@@ -773,6 +1321,9 @@ func (t *TUI) showTimeDialog() {
 			Description: description,
 			Date:        date,
 		}
+		if billableSet {
+			timeReq.Billable = &billable
+		}
 		
 		err = t.client.LogTime(task.ID, timeReq)
 		if err != nil {
@@ -791,11 +1342,249 @@ func (t *TUI) showTimeDialog() {
 		t.app.SetRoot(originalRoot, true)
 	})
 	
+	help := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(t.theme.hints("+/-", "15 Minutes", "=", "Since Last Entry"))
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(help, 1, 0, false)
+	
 	// Disable global keys while form is active
 	t.disableGlobalKeys()
+	t.app.SetRoot(layout, true)
+}
+
+// fillSinceLastEntry sets the time dialog's duration to the time since my
+// latest time entry today ended
+func (t *TUI) fillSinceLastEntry(field *tview.InputField, username string) {
+	entries, err := t.client.GetTimeEntries("today", "")
+	if err != nil {
+		t.setStatus(fmt.Sprintf("Error loading time entries: %v", err))
+		return
+	}
+	minutes, ok := minutesSinceLastEntry(entries, username, time.Now())
+	if !ok {
+		t.setStatus("No earlier time entry today")
+		return
+	}
+	field.SetText(tuiFormatDuration(minutes))
+}
+
+// showTimeEntries shows the time logged across tasks today, or this week,
+// grouped by day with daily totals
+func (t *TUI) showTimeEntries(week bool) {
+	today := time.Now()
+	since, title := today.Format("2006-01-02"), "Time Entries - Today"
+	if week {
+		// Weeks start on Monday
+		weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		since, title = weekStart.Format("2006-01-02"), "Time Entries - This Week"
+	}
+
+	table := tview.NewTable().SetSelectable(true, false)
+	table.SetSelectedStyle(t.theme.selectedStyle())
+	table.SetBorder(true).SetTitle(title)
+	table.SetCell(0, 0, tview.NewTableCell("Loading...").SetSelectable(false))
+
+	help := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(t.theme.hints("w", "Today/Week", "e", "Edit", "d", "Delete", "y", "Copy to Today", "Escape", "Back"))
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(help, 1, 0, false)
+
+	// entries holds the time entry shown on each table row, nil for day headers
+	var entries []*client.TimeEntry
+	selected := func() *client.TimeEntry {
+		row, _ := table.GetSelection()
+		if row < 0 || row >= len(entries) {
+			return nil
+		}
+		return entries[row]
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			t.enableGlobalKeys()
+			t.app.SetRoot(t.root, true)
+			t.app.SetFocus(t.tasksTable)
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'w':
+			t.showTimeEntries(!week)
+			return nil
+		case 'e':
+			if entry := selected(); entry != nil {
+				t.showTimeEntryEditDialog(entry, week)
+			}
+			return nil
+		case 'd':
+			if entry := selected(); entry != nil {
+				t.showTimeEntryDeleteConfirm(entry, week)
+			}
+			return nil
+		case 'y':
+			if entry := selected(); entry != nil {
+				t.copyTimeEntryToToday(entry, week)
+			}
+			return nil
+		}
+		return event
+	})
+
+	t.disableGlobalKeys()
+	t.app.SetRoot(layout, true)
+	t.app.SetFocus(table)
+
+	loadAsync(t, "time", func() ([]client.TimeEntry, error) {
+		return t.client.GetTimeEntries(since, "")
+	}, func(loaded []client.TimeEntry, err error) {
+		table.Clear()
+		if err != nil {
+			table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf("Error loading time entries: %v", err)).SetSelectable(false))
+			return
+		}
+		entries = populateTimeEntriesTable(table, loaded, t.theme)
+	})
+}
+
+// populateTimeEntriesTable lists time entries, newest first, under a header row
+// for each day showing its total. It returns the entry on each row.
+func populateTimeEntriesTable(table *tview.Table, loaded []client.TimeEntry, theme palette) []*client.TimeEntry {
+	if len(loaded) == 0 {
+		table.SetCell(0, 0, tview.NewTableCell("No time logged").SetSelectable(false))
+		return nil
+	}
+
+	totals := make(map[string]int)
+	for _, entry := range loaded {
+		totals[entry.CreatedAt.Local().Format("2006-01-02")] += entry.Duration
+	}
+
+	var rows []*client.TimeEntry
+	day := ""
+	for i := range loaded {
+		entry := &loaded[i]
+		created := entry.CreatedAt.Local()
+		if key := created.Format("2006-01-02"); key != day {
+			day = key
+			row := len(rows)
+			table.SetCell(row, 0, tview.NewTableCell(created.Format("Mon Jan 2")).SetTextColor(theme.Accent).SetSelectable(false))
+			table.SetCell(row, 1, tview.NewTableCell(tuiFormatDuration(totals[key])).SetTextColor(theme.Accent).SetAlign(tview.AlignRight).SetSelectable(false))
+			rows = append(rows, nil)
+		}
+
+		row := len(rows)
+		table.SetCell(row, 0, tview.NewTableCell("  "+created.Format("15:04")))
+		table.SetCell(row, 1, tview.NewTableCell(tuiFormatDuration(entry.Duration)).SetAlign(tview.AlignRight))
+		table.SetCell(row, 2, tview.NewTableCell(tview.Escape(fmt.Sprintf("#%d %s", entry.TaskID, entry.TaskName))).SetMaxWidth(40))
+		description := entry.Description
+		if !entry.Billable {
+			description += " (non-billable)"
+		}
+		table.SetCell(row, 3, tview.NewTableCell(tview.Escape(description)).SetExpansion(1))
+		rows = append(rows, entry)
+	}
+
+	// Start on the newest entry rather than its day header
+	table.Select(1, 0)
+	return rows
+}
+
+// showTimeEntryEditDialog shows a form to change a time entry's duration,
+// description and day
+func (t *TUI) showTimeEntryEditDialog(entry *client.TimeEntry, week bool) {
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle(fmt.Sprintf("Edit Time - %s", entry.TaskName))
+
+	duration := tuiFormatDuration(entry.Duration)
+	description := entry.Description
+	date := entry.CreatedAt.Local().Format("2006-01-02")
+	billable := entry.Billable
+
+	form.AddInputField("Duration", duration, 20, nil, func(text string) {
+		duration = text
+	})
+	form.AddInputField("Description", description, 50, nil, func(text string) {
+		description = text
+	})
+	form.AddInputField("Date", date, 30, nil, func(text string) {
+		date = text
+	})
+	form.AddCheckbox("Billable", billable, func(checked bool) {
+		billable = checked
+	})
+
+	form.AddButton("Save", func() {
+		durationMinutes, err := client.ParseDuration(duration)
+		if err != nil {
+			t.setStatus(fmt.Sprintf("Invalid duration format: %v", err))
+			return
+		}
+
+		// Only move the entry when the date was changed, to keep its time of day
+		req := &client.LogTimeRequest{Duration: durationMinutes, Description: description, Billable: &billable}
+		if date != entry.CreatedAt.Local().Format("2006-01-02") {
+			req.Date = date
+		}
+
+		if _, err := t.client.UpdateTimeEntry(entry.TaskID, entry.ID, req); err != nil {
+			t.setStatus(fmt.Sprintf("Error updating time entry: %v", err))
+			return
+		}
+		t.setStatus("Time entry updated")
+		t.refreshTasksOnly()
+		t.showTimeEntries(week)
+	})
+
+	form.AddButton("Cancel", func() {
+		t.showTimeEntries(week)
+	})
+
 	t.app.SetRoot(form, true)
 }
 
+// showTimeEntryDeleteConfirm shows a confirmation dialog for deleting a time entry
+func (t *TUI) showTimeEntryDeleteConfirm(entry *client.TimeEntry, week bool) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete %s logged on '%s'?", tuiFormatDuration(entry.Duration), entry.TaskName)).
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonLabel == "Delete" {
+				if err := t.client.DeleteTimeEntry(entry.TaskID, entry.ID); err != nil {
+					t.setStatus(fmt.Sprintf("Error deleting time entry: %v", err))
+				} else {
+					t.setStatus("Time entry deleted")
+					t.refreshTasksOnly()
+				}
+			}
+			t.showTimeEntries(week)
+		})
+
+	t.app.SetRoot(modal, true)
+}
+
+// copyTimeEntryToToday logs the same time on the same task again today, for
+// work that carries on from yesterday
+func (t *TUI) copyTimeEntryToToday(entry *client.TimeEntry, week bool) {
+	err := t.client.LogTime(entry.TaskID, &client.LogTimeRequest{
+		Duration:    entry.Duration,
+		Description: entry.Description,
+		Billable:    &entry.Billable,
+	})
+	if err != nil {
+		t.setStatus(fmt.Sprintf("Error logging time: %v", err))
+		return
+	}
+	t.setStatus(fmt.Sprintf("Logged %s on task: %s", tuiFormatDuration(entry.Duration), entry.TaskName))
+	t.refreshTasksOnly()
+	t.showTimeEntries(week)
+}
+
 // showCommentDialog shows the comment entry dialog
 func (t *TUI) showCommentDialog() {
 	task := t.getSelectedTask()
@@ -953,82 +1742,113 @@ func (t *TUI) showEditDialog() {
 
 // showNewQueryDialog shows the new saved query dialog
 func (t *TUI) showNewQueryDialog() {
+	t.showQueryDialog(nil)
+}
+
+// showQueryDialog shows the saved query form, creating a new query when
+// existing is nil and editing it otherwise
+func (t *TUI) showQueryDialog(existing *client.SavedQuery) {
 	form := tview.NewForm()
-	form.SetBorder(true).SetTitle("New Saved Query")
 	
-	var name, includedTags, excludedTags string
+	var name, includedTags, excludedTags, expression string
+	title, button := "New Saved Query", "Create"
+	if existing != nil {
+		name = existing.Name
+		includedTags = strings.Join(existing.IncludedTags, ", ")
+		excludedTags = strings.Join(existing.ExcludedTags, ", ")
+		expression = existing.Expression
+		title, button = "Edit Saved Query", "Save"
+	}
+	form.SetBorder(true).SetTitle(title)
 	
-	form.AddInputField("Name", "", 60, nil, func(text string) {
+	form.AddInputField("Name", name, 60, nil, func(text string) {
 		name = text
 	})
 	
-	form.AddInputField("Included Tags", "", 60, nil, func(text string) {
+	form.AddInputField("Included Tags", includedTags, 60, nil, func(text string) {
 		includedTags = text
 	})
 	
-	form.AddInputField("Excluded Tags", "", 60, nil, func(text string) {
+	form.AddInputField("Excluded Tags", excludedTags, 60, nil, func(text string) {
 		excludedTags = text
 	})
+
+	expressionField := tview.NewInputField().SetLabel("Tag Expression").SetText(expression).SetFieldWidth(60).
+		SetChangedFunc(func(text string) {
+			expression = text
+		})
+	form.AddFormItem(expressionField)
 	
 	// Add help text
-	form.AddTextView("Help", "Enter comma-separated tags. Example: urgent, backend", 60, 2, true, false)
+	form.AddTextView("Help", "Enter comma-separated tags. Example: urgent, backend\n"+
+		"Tag Expression combines tags with AND, OR, NOT and parentheses, e.g. (client1 OR client2) AND NOT internal; Add Group builds one", 60, 4, true, false)
 	
 	originalRoot := t.root
 	
-	form.AddButton("Create", func() {
+	form.AddButton(button, func() {
 		if strings.TrimSpace(name) == "" {
 			t.setStatus("Query name is required")
 			return
 		}
 		
-		// Parse included tags
-		var includedTagsList []string
-		if strings.TrimSpace(includedTags) != "" {
-			tagParts := strings.Split(includedTags, ",")
-			for _, tag := range tagParts {
-				trimmedTag := strings.TrimSpace(tag)
-				if trimmedTag != "" {
-					includedTagsList = append(includedTagsList, trimmedTag)
-				}
+		var savedQuery *client.SavedQuery
+		var err error
+		if existing == nil {
+			// New queries belong to the project being shown
+			var projectID *uint
+			if t.projectID != 0 {
+				projectID = &t.projectID
 			}
+			savedQuery, err = t.client.CreateSavedQuery(&client.CreateSavedQueryRequest{
+				Name:         name,
+				IncludedTags: parseTagList(includedTags),
+				ExcludedTags: parseTagList(excludedTags),
+				Expression:   strings.TrimSpace(expression),
+				ProjectID:    projectID,
+			})
+		} else {
+			savedQuery, err = t.client.UpdateSavedQuery(existing.ID, &client.UpdateSavedQueryRequest{
+				Name:         name,
+				IncludedTags: parseTagList(includedTags),
+				ExcludedTags: parseTagList(excludedTags),
+				Expression:   strings.TrimSpace(expression),
+			})
 		}
 		
-		// Parse excluded tags
-		var excludedTagsList []string
-		if strings.TrimSpace(excludedTags) != "" {
-			tagParts := strings.Split(excludedTags, ",")
-			for _, tag := range tagParts {
-				trimmedTag := strings.TrimSpace(tag)
-				if trimmedTag != "" {
-					excludedTagsList = append(excludedTagsList, trimmedTag)
-				}
-			}
+		if err != nil {
+			// Keep the form open, with the error in its title, so a mistyped
+			// expression can be fixed
+			form.SetTitle(fmt.Sprintf("%s: %v", title, err))
+			return
 		}
-		
-		createReq := &client.CreateSavedQueryRequest{
-			Name:         name,
-			IncludedTags: includedTagsList,
-			ExcludedTags: excludedTagsList,
+		if existing == nil {
+			t.setStatus(fmt.Sprintf("Created saved query: %s", savedQuery.Name))
+		} else {
+			t.setStatus(fmt.Sprintf("Updated saved query: %s", savedQuery.Name))
 		}
-		
-		savedQuery, err := t.client.CreateSavedQuery(createReq)
-		if err != nil {
-			t.setStatus(fmt.Sprintf("Error creating saved query: %v", err))
+		// Show the saved query in the sidebar straight away, so the tasks
+		// load with its tags, then fetch the list again for the open counts
+		queries := slices.Clone(t.allSavedQueries)
+		if index := slices.IndexFunc(queries, func(sq client.SavedQuery) bool { return sq.ID == savedQuery.ID }); index >= 0 {
+			queries[index] = *savedQuery
 		} else {
-			t.setStatus(fmt.Sprintf("Created saved query: %s", savedQuery.Name))
-			// Select the new query after creation and refresh
-			t.selectedQuery = fmt.Sprintf("saved:%d", savedQuery.ID)
-			// Only reload saved queries and refresh tasks, don't reload header to avoid potential loops
-			if err := t.loadSavedQueries(); err != nil {
-				t.setStatus(fmt.Sprintf("Error loading saved queries: %v", err))
-			} else {
-				t.refreshTasksOnly()
-			}
+			queries = append(queries, *savedQuery)
 		}
+		t.selectedQuery = fmt.Sprintf("saved:%d", savedQuery.ID)
+		t.renderSavedQueries(queries)
+		// Only reload saved queries and refresh tasks, don't reload header to avoid potential loops
+		t.refreshTasksOnly()
+		t.loadSavedQueries()
 		
 		t.enableGlobalKeys()
 		t.app.SetRoot(originalRoot, true)
 	})
+
+	form.AddButton("Add Group", func() {
+		t.showTagGroupDialog(form, func(group string) {
+			expressionField.SetText(appendTagGroup(expression, group))
+		})
+	})
 	
 	form.AddButton("Cancel", func() {
 		t.enableGlobalKeys()
@@ -1040,6 +1860,51 @@ func (t *TUI) showNewQueryDialog() {
 	t.app.SetRoot(form, true)
 }
 
+// showQueryDeleteConfirm shows a confirmation dialog for deleting a saved query
+func (t *TUI) showQueryDeleteConfirm(query *client.SavedQuery) {
+	queryID, queryName := query.ID, query.Name
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete saved query '%s'? Its email schedule is deleted too.", queryName)).
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.enableGlobalKeys()
+			t.app.SetRoot(t.root, true)
+			t.app.SetFocus(t.sidebar)
+			if buttonLabel != "Delete" {
+				return
+			}
+
+			if err := t.client.DeleteSavedQuery(queryID); err != nil {
+				t.setStatus(fmt.Sprintf("Error deleting saved query: %v", err))
+				return
+			}
+			// Fall back to the active tasks when the shown query is gone
+			if t.selectedQuery == fmt.Sprintf("saved:%d", queryID) {
+				t.selectedQuery = "active"
+				t.currentPage = 0
+				t.refreshTasksOnly()
+			}
+			t.renderSavedQueries(slices.DeleteFunc(slices.Clone(t.allSavedQueries), func(sq client.SavedQuery) bool {
+				return sq.ID == queryID
+			}))
+			t.setStatus(fmt.Sprintf("Deleted saved query: %s", queryName))
+		})
+
+	t.disableGlobalKeys()
+	t.app.SetRoot(modal, true)
+}
+
+// parseTagList splits a comma-separated tag list, dropping empty entries
+func parseTagList(input string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(input, ",") {
+		if trimmed := strings.TrimSpace(tag); trimmed != "" {
+			tags = append(tags, trimmed)
+		}
+	}
+	return tags
+}
+
 // showCreateTaskModal shows a modal for creating a new task with inline tag parsing
 func (t *TUI) showCreateTaskModal() {
 	// Create an input field for the task input
@@ -1063,7 +1928,7 @@ func (t *TUI) showCreateTaskModal() {
 
 	// Create a flex container for the input
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(tview.NewTextView().SetText("Create new task (supports +tag, @tag, -c, -t 15m, -d -1d):").SetTextAlign(tview.AlignCenter), 1, 0, false).
+		AddItem(tview.NewTextView().SetText("Create new task (supports +tag, @tag, -c, -t 15m, -d yesterday):").SetTextAlign(tview.AlignCenter), 1, 0, false).
 		AddItem(tview.NewTextView(), 1, 0, false). // Spacer
 		AddItem(inputField, 1, 0, true).
 		AddItem(tview.NewTextView(), 1, 0, false). // Spacer
@@ -1087,142 +1952,27 @@ func (t *TUI) showCreateTaskModal() {
 	t.app.SetFocus(inputField)
 }
 
-// createTaskFromInput parses the input and creates a task with inline tags and flags
+// createTaskFromInput creates a task from quick-add input (+tag, @tag, -p, -t, -d, -c);
+// the server parses it so every client behaves the same
 func (t *TUI) createTaskFromInput(input string) {
-	// Parse flags first
-	var shouldComplete bool
-	var timeSpent string
-	var priority string
-	var date string
-
-	// Split input into words and process flags
-	words := strings.Fields(input)
-	var filteredWords []string
-	
-	for i := 0; i < len(words); i++ {
-		word := words[i]
-		if word == "-c" {
-			shouldComplete = true
-		} else if word == "-t" && i+1 < len(words) {
-			timeSpent = words[i+1]
-			i++ // Skip next word as it's the time value
-		} else if word == "-p" && i+1 < len(words) {
-			priority = words[i+1]
-			i++ // Skip next word as it's the priority value
-		} else if word == "-d" && i+1 < len(words) {
-			date = words[i+1]
-			i++ // Skip next word as it's the date value
-		} else if strings.HasPrefix(word, "-t") && len(word) > 2 {
-			// Handle -t15m format
-			timeSpent = word[2:]
-		} else if strings.HasPrefix(word, "-p") && len(word) > 2 {
-			// Handle -phigh format
-			priority = word[2:]
-		} else if strings.HasPrefix(word, "-d") && len(word) > 2 {
-			// Handle -d-1d format
-			date = word[2:]
-		} else if !strings.HasPrefix(word, "-") {
-			filteredWords = append(filteredWords, word)
-		}
-	}
-
-	// Rejoin the filtered input for tag parsing
-	filteredInput := strings.Join(filteredWords, " ")
-	
-	// Parse task name and tags using the same logic as CLI add command
-	taskName, tags := t.parseTaskNameAndTags(filteredInput)
-	
-	if strings.TrimSpace(taskName) == "" {
-		t.setStatus("Task name cannot be empty")
-		return
-	}
-
-	// Create the task
-	req := &client.CreateTaskRequest{
-		Name:     taskName,
-		Priority: priority,
-		Tags:     tags,
-		Date:     date,
-	}
-
-	task, err := t.client.CreateTask(req)
+	task, err := t.client.QuickAddTask(input)
 	if err != nil {
 		t.setStatus(fmt.Sprintf("Error creating task: %v", err))
 		return
 	}
 
-	t.setStatus(fmt.Sprintf("✓ Created task #%d: %s", task.ID, task.Name))
-
-	// Log time if specified
-	if timeSpent != "" {
-		durationMinutes, err := client.ParseDuration(timeSpent)
-		if err != nil {
-			t.setStatus(fmt.Sprintf("Invalid duration format: %v", err))
-			return
-		}
-
-		timeReq := &client.LogTimeRequest{
-			Duration:    durationMinutes,
-			Description: "Time logged during task creation",
-			Date:        date,
-		}
-
-		err = t.client.LogTime(task.ID, timeReq)
-		if err != nil {
-			t.setStatus(fmt.Sprintf("Error logging time: %v", err))
-		} else {
-			t.setStatus(fmt.Sprintf("✓ Created task #%d: %s, logged %s", task.ID, task.Name, timeSpent))
-		}
+	status := fmt.Sprintf("✓ Created task #%d: %s", task.ID, task.Name)
+	if task.Status == models.TaskStatusResolved {
+		status = fmt.Sprintf("✓ Created and completed task #%d: %s", task.ID, task.Name)
 	}
-
-	// Mark as completed if specified
-	if shouldComplete {
-		_, err := t.client.UpdateTaskStatus(task.ID, "resolved")
-		if err != nil {
-			t.setStatus(fmt.Sprintf("Error completing task: %v", err))
-		} else {
-			status := fmt.Sprintf("✓ Created and completed task #%d: %s", task.ID, task.Name)
-			if timeSpent != "" {
-				status += fmt.Sprintf(", logged %s", timeSpent)
-			}
-			t.setStatus(status)
-		}
+	if task.LoggedMinutes > 0 {
+		status += fmt.Sprintf(", logged %s", formatDurationDisplay(time.Duration(task.LoggedMinutes)*time.Minute))
 	}
+	t.setStatus(status)
 
 	t.refreshData()
 }
 
-// parseTaskNameAndTags extracts tags from task name and returns cleaned name and tags (copied from add.go)
-func (t *TUI) parseTaskNameAndTags(input string) (string, []string) {
-	words := strings.Fields(input)
-	var cleanWords []string
-	var tags []string
-	
-	for _, word := range words {
-		if strings.HasPrefix(word, "+") {
-			// +tag format: add tag and remove + from name
-			tag := strings.TrimPrefix(word, "+")
-			if tag != "" {
-				tags = append(tags, tag)
-				cleanWords = append(cleanWords, tag) // Add the tag word without + to the name
-			}
-		} else if strings.HasPrefix(word, "@") {
-			// @tag format: add tag but remove entire @tag from name
-			tag := strings.TrimPrefix(word, "@")
-			if tag != "" {
-				tags = append(tags, tag)
-			}
-			// Don't add this word to cleanWords (it gets removed from name)
-		} else {
-			// Regular word, keep in name
-			cleanWords = append(cleanWords, word)
-		}
-	}
-	
-	cleanName := strings.Join(cleanWords, " ")
-	return strings.TrimSpace(cleanName), tags
-}
-
 // viewTaskDetails shows detailed view of the selected task
 func (t *TUI) viewTaskDetails() {
 	selectedTask := t.getSelectedTask()
@@ -1271,23 +2021,28 @@ func (t *TUI) viewTaskDetails() {
 	}
 	
 	// Create detailed view
-	detailsText := fmt.Sprintf(`[yellow]Task #%d[white]
-[white]Name:[white] %s
-[white]Status:[white] %s
-[white]Priority:[white] %s
-[white]Tags:[white] %s
-[white]Created:[white] %s
-[white]Updated:[white] %s
-
-[yellow]Description:[white]
+	heading := func(text string) string {
+		return t.theme.color(t.theme.Accent, text)
+	}
+	detailsText := fmt.Sprintf(`%s
+Name: %s
+Status: %s
+Priority: %s
+Tags: %s
+Created: %s
+Updated: %s
+
+%s
 %s
 
-[yellow]Time Entries:[white]
-`, task.ID, task.Name, task.Status, task.Priority, 
+%s
+`, heading(fmt.Sprintf("Task #%d", task.ID)), task.Name, task.Status, task.Priority, 
 	strings.Join(task.Tags, ", "), 
 	task.CreatedAt.Format("2006-01-02 15:04:05"),
 	task.UpdatedAt.Format("2006-01-02 15:04:05"),
-	task.Description)
+	heading("Description:"),
+	task.Description,
+	heading("Time Entries:"))
 	
 	totalTime := 0
 	for _, entry := range task.TimeEntries {
@@ -1302,10 +2057,10 @@ func (t *TUI) viewTaskDetails() {
 		detailsText += "No time entries\n"
 	}
 	
-	detailsText += fmt.Sprintf("\n[yellow]Total Time:[white] %s", tuiFormatDuration(totalTime))
+	detailsText += fmt.Sprintf("\n%s %s", heading("Total Time:"), tuiFormatDuration(totalTime))
 	
 	if len(task.Comments) > 0 {
-		detailsText += "\n\n[yellow]Comments:[white]\n"
+		detailsText += "\n\n" + heading("Comments:") + "\n"
 		for _, comment := range task.Comments {
 			detailsText += fmt.Sprintf("• [%s] %s\n", 
 				comment.CreatedAt.Format("2006-01-02 15:04"), 
@@ -1338,7 +2093,7 @@ func (t *TUI) viewTaskDetails() {
 // setStatus updates the status bar with a temporary message, then restores context-aware status
 func (t *TUI) setStatus(message string) {
 	// Show the message temporarily
-	t.statusBar.SetText(fmt.Sprintf("[white]%s", message))
+	t.statusBar.SetText(t.theme.tag(t.theme.Text) + message)
 	
 	// After a short delay, restore the context-appropriate status bar
 	go func() {
@@ -1373,20 +2128,18 @@ func tuiFormatDuration(minutes int) string {
 // nextPage moves to the next page of tasks
 func (t *TUI) nextPage() {
 	t.currentPage++
-	if err := t.refreshTasksOnly(); err != nil {
-		t.setStatus(fmt.Sprintf("Error loading next page: %v", err))
+	t.loadTasks(func() {
 		t.currentPage-- // Revert on error
-	}
+	})
 }
 
 // prevPage moves to the previous page of tasks
 func (t *TUI) prevPage() {
 	if t.currentPage > 0 {
 		t.currentPage--
-		if err := t.refreshTasksOnly(); err != nil {
-			t.setStatus(fmt.Sprintf("Error loading previous page: %v", err))
+		t.loadTasks(func() {
 			t.currentPage++ // Revert on error
-		}
+		})
 	} else {
 		t.setStatus("Already on first page")
 	}
@@ -1397,6 +2150,7 @@ func (t *TUI) showSearchDialog() {
 	inputField := tview.NewInputField().
 		SetLabel("Search tasks: ").
 		SetText(t.searchQuery).
+		SetPlaceholder(`tag:client1 status:open "exact phrase" -tag:internal`).
 		SetFieldWidth(50)
 		
 	inputField.SetBorder(true).SetTitle("Search (ESC to cancel)")
@@ -1414,13 +2168,12 @@ func (t *TUI) showSearchDialog() {
 			t.enableGlobalKeys()
 			t.app.SetRoot(originalRoot, true)
 			
-			if err := t.refreshTasksOnly(); err != nil {
-				t.setStatus(fmt.Sprintf("Search error: %v", err))
-			} else if searchText == "" {
+			if searchText == "" {
 				t.setStatus("Search cleared")
 			} else {
 				t.setStatus(fmt.Sprintf("Searching for: %s", searchText))
 			}
+			t.refreshTasksOnly()
 			
 		case tcell.KeyEscape:
 			t.enableGlobalKeys()
@@ -1442,16 +2195,267 @@ func (t *TUI) clearSearch() {
 		t.searchActive = false
 		t.currentPage = 0
 		
-		if err := t.refreshTasksOnly(); err != nil {
-			t.setStatus(fmt.Sprintf("Error clearing search: %v", err))
-		} else {
-			t.setStatus("Search cleared")
-		}
+		t.setStatus("Search cleared")
+		t.refreshTasksOnly()
 	} else {
 		t.setStatus("No active search to clear")
 	}
 }
 
+// toggleRecentSort switches the task list between its usual order and the
+// tasks I touched most recently first, for picking up where I left off
+func (t *TUI) toggleRecentSort() {
+	t.sortRecent = !t.sortRecent
+	t.currentPage = 0
+	t.updateTasksTitle()
+	if t.sortRecent {
+		t.setStatus("Sorted by recently touched")
+	} else {
+		t.setStatus("Default sort order")
+	}
+	t.refreshTasksOnly()
+}
+
+// cycleContextFilter moves the context quick filter to the next context in
+// use: all tasks, then each context, then tasks without one
+func (t *TUI) cycleContextFilter() {
+	contexts, err := t.client.GetContexts()
+	if err != nil {
+		t.setStatus(fmt.Sprintf("Error loading contexts: %v", err))
+		return
+	}
+	if len(contexts) == 0 && t.contextFilter == "" {
+		t.setStatus("No contexts in use")
+		return
+	}
+
+	t.contextFilter = nextContextFilter(t.contextFilter, contexts)
+	t.currentPage = 0
+	t.updateTasksTitle()
+	t.refreshTasksOnly()
+}
+
+// cycleProject switches the sidebar and task list to the next project: every
+// project, then each project in turn
+func (t *TUI) cycleProject() {
+	projects, err := t.client.GetProjects()
+	if err != nil {
+		t.setStatus(fmt.Sprintf("Error loading projects: %v", err))
+		return
+	}
+
+	next := nextProject(t.projectID, projects)
+	t.projectID, t.projectName = 0, ""
+	if next != nil {
+		t.projectID, t.projectName = next.ID, next.Name
+		t.setStatus(fmt.Sprintf("Project: %s", next.Name))
+	} else {
+		t.setStatus("Showing every project")
+	}
+
+	t.renderSavedQueries(t.allSavedQueries)
+	// The selected saved query may belong to another project
+	if strings.HasPrefix(t.selectedQuery, "saved:") && t.sidebar.GetCurrentItem() < builtinQueries {
+		t.selectedQuery = "active"
+	}
+	t.currentPage = 0
+	t.refreshTasksOnly()
+}
+
+// nextProject returns the project after currentID in the cycle of every
+// project (nil), then each project in turn
+func nextProject(currentID uint, projects []client.Project) *client.Project {
+	if currentID == 0 {
+		if len(projects) == 0 {
+			return nil
+		}
+		return &projects[0]
+	}
+	for i, project := range projects {
+		if project.ID == currentID && i+1 < len(projects) {
+			return &projects[i+1]
+		}
+	}
+	// Past the last project, or the project is gone, so start over
+	return nil
+}
+
+// projectSavedQueries returns the saved queries shown for a project: its own
+// and those for every project. Project 0 shows every saved query.
+func projectSavedQueries(queries []client.SavedQuery, projectID uint) []client.SavedQuery {
+	if projectID == 0 {
+		return queries
+	}
+	var shown []client.SavedQuery
+	for _, query := range queries {
+		if query.ProjectID == nil || *query.ProjectID == projectID {
+			shown = append(shown, query)
+		}
+	}
+	return shown
+}
+
+// nextContextFilter returns the context filter after current in the cycle ""
+// (all tasks), each context in use, then "none"
+func nextContextFilter(current string, contexts []client.ContextCount) string {
+	cycle := []string{""}
+	for _, context := range contexts {
+		cycle = append(cycle, context.Context)
+	}
+	cycle = append(cycle, "none")
+
+	for i, value := range cycle {
+		if value == current {
+			return cycle[(i+1)%len(cycle)]
+		}
+	}
+	// The context is no longer in use, so start over
+	return ""
+}
+
+// tagFilterTags returns the tags the overlay requires and the tags it hides, sorted
+func (t *TUI) tagFilterTags() (included, excluded []string) {
+	for tag, mode := range t.tagFilter {
+		switch mode {
+		case tagFilterInclude:
+			included = append(included, tag)
+		case tagFilterExclude:
+			excluded = append(excluded, tag)
+		}
+	}
+	sort.Strings(included)
+	sort.Strings(excluded)
+	return included, excluded
+}
+
+// tagFilterSummary describes the tag filter as e.g. "+backend -blocked"
+func (t *TUI) tagFilterSummary() string {
+	included, excluded := t.tagFilterTags()
+	var parts []string
+	for _, tag := range included {
+		parts = append(parts, "+"+tag)
+	}
+	for _, tag := range excluded {
+		parts = append(parts, "-"+tag)
+	}
+	return strings.Join(parts, " ")
+}
+
+// showTagFilterDialog shows a checklist of every tag to require (+) or hide (-)
+// on top of the selected query, without saving a query
+func (t *TUI) showTagFilterDialog() {
+	tags, err := t.client.GetTagStats("", "")
+	if err != nil {
+		t.setStatus(fmt.Sprintf("Error loading tags: %v", err))
+		return
+	}
+	if len(tags) == 0 {
+		t.setStatus("No tags in use")
+		return
+	}
+
+	// Work on a copy so Escape leaves the current filter untouched
+	pending := make(map[string]tagFilterMode, len(tags))
+	for _, tag := range tags {
+		pending[tag.Tag] = t.tagFilter[tag.Tag]
+	}
+
+	table := tview.NewTable().SetSelectable(true, false)
+	table.SetSelectedStyle(t.theme.selectedStyle())
+	renderRow := func(row int) {
+		tag := tags[row]
+		mark, color := "[ ]", t.theme.Text
+		switch pending[tag.Tag] {
+		case tagFilterInclude:
+			mark, color = "[+]", t.theme.Success
+		case tagFilterExclude:
+			mark, color = "[-]", t.theme.Danger
+		}
+		table.SetCell(row, 0, tview.NewTableCell(mark).SetTextColor(color))
+		table.SetCell(row, 1, tview.NewTableCell(tview.Escape(tag.Tag)).SetTextColor(color).SetExpansion(1))
+		table.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%d open", tag.OpenTasks+tag.InProgressTasks)).SetAlign(tview.AlignRight))
+		table.SetCell(row, 3, tview.NewTableCell(tuiFormatDuration(tag.LoggedMinutes)+" (7d)").SetTextColor(t.theme.Muted).SetAlign(tview.AlignRight))
+	}
+	for row := range tags {
+		renderRow(row)
+	}
+
+	setMode := func(mode tagFilterMode) {
+		row, _ := table.GetSelection()
+		if row >= 0 && row < len(tags) {
+			pending[tags[row].Tag] = mode
+			renderRow(row)
+		}
+	}
+	closeDialog := func() {
+		t.enableGlobalKeys()
+		t.app.SetRoot(t.root, true)
+		t.app.SetFocus(t.tasksTable)
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			t.tagFilter = make(map[string]tagFilterMode)
+			for tag, mode := range pending {
+				if mode != tagFilterOff {
+					t.tagFilter[tag] = mode
+				}
+			}
+			t.currentPage = 0
+			closeDialog()
+			t.refreshTasksOnly()
+			return nil
+		case tcell.KeyEscape:
+			closeDialog()
+			t.setStatus("Tag filter unchanged")
+			return nil
+		}
+
+		switch event.Rune() {
+		case ' ':
+			// Cycle off -> include -> exclude -> off
+			row, _ := table.GetSelection()
+			if row >= 0 && row < len(tags) {
+				setMode((pending[tags[row].Tag] + 1) % 3)
+			}
+			return nil
+		case '+':
+			setMode(tagFilterInclude)
+			return nil
+		case '-':
+			setMode(tagFilterExclude)
+			return nil
+		case 'c':
+			for row, tag := range tags {
+				pending[tag.Tag] = tagFilterOff
+				renderRow(row)
+			}
+			return nil
+		}
+		return event
+	})
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(tview.NewTextView().SetText("Space: Cycle | +: Require | -: Hide | c: Clear | Enter: Apply | Escape: Cancel").SetTextAlign(tview.AlignCenter), 1, 0, false)
+	flex.SetBorder(true).SetTitle("Filter by Tags")
+
+	// Create a centered modal sized to the tag list
+	height := min(len(tags), 20) + 3
+	modalContainer := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(flex, height, 0, true).
+			AddItem(nil, 0, 1, false), 80, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	t.disableGlobalKeys()
+	t.app.SetRoot(modalContainer, true)
+	t.app.SetFocus(table)
+}
+
 // showSubtaskManager opens the subtask management modal
 func (t *TUI) showSubtaskManager() {
 	task := t.getSelectedTask()
@@ -1463,6 +2467,7 @@ func (t *TUI) showSubtaskManager() {
 	// Create subtask list
 	subtaskList := tview.NewList()
 	subtaskList.ShowSecondaryText(false)
+	subtaskList.SetSelectedStyle(t.theme.selectedStyle())
 	subtaskList.SetBorder(true).SetTitle(fmt.Sprintf("Subtasks for: %s", task.Name))
 
 	// Load subtasks
@@ -1485,7 +2490,7 @@ func (t *TUI) showSubtaskManager() {
 
 	// Create help text
 	helpText := tview.NewTextView().
-		SetText("[yellow]Space[white]: Toggle | [yellow]c[white]: Create | [yellow]e[white]: Edit | [yellow]d[white]: Delete | [yellow]Esc[white]: Close").
+		SetText(t.theme.hints("Space", "Toggle", "c", "Create", "e", "Edit", "d", "Delete", "Esc", "Close")).
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
 
@@ -1699,6 +2704,85 @@ func (t *TUI) startAutoRefresh() {
 	}()
 }
 
+// startMentionNotifications follows mentions of the logged-in user in the
+// background, notifying per the notifications setting and in the status bar
+func (t *TUI) startMentionNotifications() {
+	cfg := config.GetCurrent()
+	if cfg == nil || cfg.Username == "" || cfg.GetNotifications() == config.NotificationsOff {
+		return
+	}
+
+	go func() {
+		watcher, err := newMentionWatcher(t.client, cfg.Username)
+		if err != nil {
+			return
+		}
+		for {
+			select {
+			case <-t.stopRefresh:
+				return
+			default:
+			}
+
+			events, err := watcher.next()
+			if err != nil {
+				time.Sleep(watchRetryDelay)
+				continue
+			}
+			for _, event := range events {
+				title, body := mentionNotification(event)
+				notify.Send(cfg.GetNotifications(), title, body)
+				t.app.QueueUpdateDraw(func() {
+					t.setStatus(fmt.Sprintf("%s: %s", title, event.TaskName))
+				})
+			}
+		}
+	}()
+}
+
+// liveUpdateDelay gathers the task events arriving close together, such as
+// from a bulk import, into one refresh
+const liveUpdateDelay = 500 * time.Millisecond
+
+// startLiveUpdates follows the server's task event stream in the background,
+// refreshing the header and tasks shortly after tasks change, and reconnects
+// when the stream drops
+func (t *TUI) startLiveUpdates() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-t.stopRefresh
+		cancel()
+	}()
+
+	go func() {
+		var pending *time.Timer
+		refresh := func(client.TaskEvent) {
+			if pending != nil {
+				return
+			}
+			pending = time.AfterFunc(liveUpdateDelay, func() {
+				t.app.QueueUpdateDraw(func() {
+					pending = nil
+					t.updateHeader()
+					t.refreshTasksOnly()
+				})
+			})
+		}
+
+		var lastID uint64
+		for ctx.Err() == nil {
+			var err error
+			lastID, err = t.client.StreamEvents(ctx, lastID, refresh)
+			if err != nil {
+				select {
+				case <-ctx.Done():
+				case <-time.After(watchRetryDelay):
+				}
+			}
+		}
+	}()
+}
+
 // stopAutoRefresh stops the auto-refresh goroutine and cleans up resources
 func (t *TUI) stopAutoRefresh() {
 	if t.refreshTicker != nil {
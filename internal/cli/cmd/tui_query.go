@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// showTagGroupDialog asks for a group of tags to match any or none of, and
+// passes its expression to add before returning to the saved query form
+func (t *TUI) showTagGroupDialog(queryForm *tview.Form, add func(group string)) {
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle("Add Tag Group")
+
+	exclude := false
+	var tags string
+	form.AddDropDown("Tasks with", []string{"any of", "none of"}, 0, func(option string, index int) {
+		exclude = index == 1
+	})
+	form.AddInputField("Tags", "", 40, nil, func(text string) {
+		tags = text
+	})
+	form.AddTextView("Help", "Comma-separated tags; the group is ANDed with the expression", 40, 2, true, false)
+
+	form.AddButton("Add", func() {
+		if group := tagGroupExpression(parseTagList(tags), exclude); group != "" {
+			add(group)
+		}
+		t.app.SetRoot(queryForm, true)
+	})
+	form.AddButton("Cancel", func() {
+		t.app.SetRoot(queryForm, true)
+	})
+
+	t.app.SetRoot(form, true)
+}
+
+// tagGroupExpression returns the tag expression matching tasks with any of the
+// tags, or with none of them when exclude is set
+func tagGroupExpression(tags []string, exclude bool) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(tags))
+	for i, tag := range tags {
+		quoted[i] = quoteExpressionTag(tag)
+	}
+	group := strings.Join(quoted, " OR ")
+	if len(quoted) > 1 {
+		group = "(" + group + ")"
+	}
+	if exclude {
+		return "NOT " + group
+	}
+	return group
+}
+
+// appendTagGroup ANDs a group onto a tag expression
+func appendTagGroup(expression, group string) string {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return group
+	}
+	// OR binds looser than AND, so an expression using it is kept together
+	if hasTopLevelOr(expression) {
+		expression = "(" + expression + ")"
+	}
+	return expression + " AND " + group
+}
+
+// hasTopLevelOr reports whether an expression has an OR outside parentheses
+// and quotes
+func hasTopLevelOr(expression string) bool {
+	depth, quoted := 0, false
+	for i, c := range expression {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && (i == 0 || expression[i-1] == ' ') && len(expression) >= i+3 &&
+			strings.EqualFold(expression[i:i+2], "or") && expression[i+2] == ' ':
+			return true
+		}
+	}
+	return false
+}
+
+// quoteExpressionTag quotes a tag the expression syntax would otherwise read
+// as several tags or as a keyword
+func quoteExpressionTag(tag string) string {
+	switch strings.ToUpper(tag) {
+	case "AND", "OR", "NOT":
+		return `"` + tag + `"`
+	}
+	if strings.ContainsAny(tag, " \t()") {
+		return `"` + strings.ReplaceAll(tag, `"`, "") + `"`
+	}
+	return tag
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/soarinferret/jats/internal/cli/client"
+)
+
+func TestTagGroupExpression(t *testing.T) {
+	tests := []struct {
+		tags    []string
+		exclude bool
+		want    string
+	}{
+		{[]string{"client1"}, false, "client1"},
+		{[]string{"client1", "client2"}, false, "(client1 OR client2)"},
+		{[]string{"internal"}, true, "NOT internal"},
+		{[]string{"on hold", "or"}, true, `NOT ("on hold" OR "or")`},
+		{nil, false, ""},
+	}
+	for _, tt := range tests {
+		if got := tagGroupExpression(tt.tags, tt.exclude); got != tt.want {
+			t.Errorf("tagGroupExpression(%v, %v) = %q, want %q", tt.tags, tt.exclude, got, tt.want)
+		}
+	}
+}
+
+func TestAppendTagGroup(t *testing.T) {
+	tests := []struct {
+		expression, group, want string
+	}{
+		{"", "(client1 OR client2)", "(client1 OR client2)"},
+		{"(client1 OR client2)", "NOT internal", "(client1 OR client2) AND NOT internal"},
+		{`"x or y" AND z`, "c", `"x or y" AND z AND c`},
+		{"a OR b", "c", "(a OR b) AND c"},
+		{"a AND NOT b", "c", "a AND NOT b AND c"},
+	}
+	for _, tt := range tests {
+		if got := appendTagGroup(tt.expression, tt.group); got != tt.want {
+			t.Errorf("appendTagGroup(%q, %q) = %q, want %q", tt.expression, tt.group, got, tt.want)
+		}
+	}
+}
+
+func TestProjectSwitching(t *testing.T) {
+	projects := []client.Project{{ID: 1, Name: "Default", IsDefault: true}, {ID: 4, Name: "Acme"}}
+	var cycle []uint
+	current := uint(0)
+	for range 3 {
+		next := nextProject(current, projects)
+		current = 0
+		if next != nil {
+			current = next.ID
+		}
+		cycle = append(cycle, current)
+	}
+	if cycle[0] != 1 || cycle[1] != 4 || cycle[2] != 0 {
+		t.Errorf("Expected every project to cycle 1, 4, then all, got %v", cycle)
+	}
+	if next := nextProject(9, projects); next != nil {
+		t.Errorf("Expected a deleted project to go back to all projects, got %+v", next)
+	}
+
+	acme := uint(4)
+	queries := []client.SavedQuery{{ID: 1, Name: "Everything"}, {ID: 2, Name: "Acme", ProjectID: &acme}}
+	if shown := projectSavedQueries(queries, 1); len(shown) != 1 || shown[0].ID != 1 {
+		t.Errorf("Expected only the query for every project, got %+v", shown)
+	}
+	if shown := projectSavedQueries(queries, 4); len(shown) != 2 {
+		t.Errorf("Expected both queries in Acme, got %+v", shown)
+	}
+	if shown := projectSavedQueries(queries, 0); len(shown) != 2 {
+		t.Errorf("Expected every query without a project, got %+v", shown)
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/soarinferret/jats/internal/cli/client"
+	"github.com/soarinferret/jats/internal/cli/config"
+)
+
+// palette maps the roles colors play in the TUI to the colors of a theme, so
+// no screen hard-codes a color of its own
+type palette struct {
+	Background tcell.Color
+	Border     tcell.Color
+	Text       tcell.Color // primary text and hint labels
+	Muted      tcell.Color // counts, closed tasks
+	Accent     tcell.Color // key names, headings, in-progress work
+	Success    tcell.Color // open counts, resolved tasks, low priority, included tags
+	Danger     tcell.Color // high priority, aging tasks, excluded tags
+	Info       tcell.Color // tasks added
+	Secondary  tcell.Color // tasks resolved
+	Highlight  tcell.Color // time logged
+	Field      tcell.Color // input field background
+
+	// noColor leaves every color to the terminal's defaults
+	noColor bool
+}
+
+var themes = map[string]palette{
+	config.ThemeDark: {
+		Background: tcell.ColorBlack,
+		Border:     tcell.ColorWhite,
+		Text:       tcell.ColorWhite,
+		Muted:      tcell.ColorGray,
+		Accent:     tcell.ColorYellow,
+		Success:    tcell.ColorGreen,
+		Danger:     tcell.ColorRed,
+		Info:       tcell.ColorAqua,
+		Secondary:  tcell.ColorBlue,
+		Highlight:  tcell.ColorFuchsia,
+		Field:      tcell.ColorBlue,
+	},
+	config.ThemeLight: {
+		Background: tcell.ColorWhite,
+		Border:     tcell.ColorBlack,
+		Text:       tcell.ColorBlack,
+		Muted:      tcell.ColorGray,
+		Accent:     tcell.ColorOlive,
+		Success:    tcell.ColorGreen,
+		Danger:     tcell.ColorMaroon,
+		Info:       tcell.ColorTeal,
+		Secondary:  tcell.ColorNavy,
+		Highlight:  tcell.ColorPurple,
+		Field:      tcell.ColorSilver,
+	},
+	config.ThemeHighContrast: {
+		Background: tcell.ColorBlack,
+		Border:     tcell.ColorWhite,
+		Text:       tcell.ColorWhite,
+		Muted:      tcell.ColorSilver,
+		Accent:     tcell.ColorYellow,
+		Success:    tcell.ColorLime,
+		Danger:     tcell.ColorRed,
+		Info:       tcell.ColorAqua,
+		Secondary:  tcell.ColorLightSkyBlue,
+		Highlight:  tcell.ColorFuchsia,
+		Field:      tcell.ColorNavy,
+	},
+	config.ThemeNoColor: {
+		Background: tcell.ColorDefault,
+		Border:     tcell.ColorDefault,
+		Text:       tcell.ColorDefault,
+		Muted:      tcell.ColorDefault,
+		Accent:     tcell.ColorDefault,
+		Success:    tcell.ColorDefault,
+		Danger:     tcell.ColorDefault,
+		Info:       tcell.ColorDefault,
+		Secondary:  tcell.ColorDefault,
+		Highlight:  tcell.ColorDefault,
+		Field:      tcell.ColorDefault,
+		noColor:    true,
+	},
+}
+
+// resolvePalette returns the palette for a theme setting. A non-empty NO_COLOR
+// (https://no-color.org) wins over the setting; unknown themes fall back to dark.
+func resolvePalette(theme string) palette {
+	if os.Getenv("NO_COLOR") != "" {
+		theme = config.ThemeNoColor
+	}
+	if p, ok := themes[theme]; ok {
+		return p
+	}
+	return themes[config.ThemeDark]
+}
+
+// configuredPalette returns the palette for the CLI config's theme
+func configuredPalette() palette {
+	theme := config.ThemeDark
+	if cfg := config.GetCurrent(); cfg != nil {
+		theme = cfg.GetTheme()
+	}
+	return resolvePalette(theme)
+}
+
+// apply sets tview's default styles, which every primitive created afterwards picks up
+func (p palette) apply() {
+	tview.Styles = tview.Theme{
+		PrimitiveBackgroundColor:    p.Background,
+		ContrastBackgroundColor:     p.Field,
+		MoreContrastBackgroundColor: p.Success,
+		BorderColor:                 p.Border,
+		TitleColor:                  p.Text,
+		GraphicsColor:               p.Border,
+		PrimaryTextColor:            p.Text,
+		SecondaryTextColor:          p.Accent,
+		TertiaryTextColor:           p.Success,
+		InverseTextColor:            p.Background,
+		ContrastSecondaryTextColor:  p.Text,
+	}
+}
+
+// selectedStyle is the style of the selected row in lists and tables; without
+// colors it is shown reversed
+func (p palette) selectedStyle() tcell.Style {
+	if p.noColor {
+		return tcell.StyleDefault.Reverse(true)
+	}
+	return tcell.StyleDefault.Foreground(p.Background).Background(p.Text)
+}
+
+// tag returns the tview color tag for c, or nothing without colors
+func (p palette) tag(c tcell.Color) string {
+	if p.noColor {
+		return ""
+	}
+	if c == tcell.ColorDefault {
+		return "[-]"
+	}
+	return "[" + c.String() + "]"
+}
+
+// color wraps text in the color tag for c, switching back to the text color after
+func (p palette) color(c tcell.Color, text string) string {
+	return p.tag(c) + text + p.tag(p.Text)
+}
+
+// hints formats key and label pairs for a status bar, e.g. "q: Quit | Enter: Details"
+func (p palette) hints(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, p.color(p.Accent, pairs[i])+": "+pairs[i+1])
+	}
+	return strings.Join(parts, " | ")
+}
+
+// snippet renders a search snippet in the base color with its matches in bold
+// accent, escaping the text for tview
+func (p palette) snippet(s *client.SearchSnippet, base tcell.Color) string {
+	runes := []rune(s.Text)
+	var b strings.Builder
+	b.WriteString(p.tag(base))
+	pos := 0
+	for _, h := range s.Highlights {
+		if h.Start < pos || h.End > len(runes) || h.Start >= h.End {
+			continue
+		}
+		b.WriteString(tview.Escape(string(runes[pos:h.Start])))
+		b.WriteString(p.tag(p.Accent) + "[::b]" + tview.Escape(string(runes[h.Start:h.End])) + "[::-]" + p.tag(base))
+		pos = h.End
+	}
+	b.WriteString(tview.Escape(string(runes[pos:])))
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/soarinferret/jats/internal/cli/client"
+	"github.com/soarinferret/jats/internal/cli/config"
+)
+
+func TestResolvePalette(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	for _, theme := range config.Themes {
+		if _, ok := themes[theme]; !ok {
+			t.Errorf("Theme %q has no palette", theme)
+		}
+	}
+
+	dark := resolvePalette(config.ThemeDark)
+	if got := dark.hints("q", "Quit"); got != "[yellow]q[white]: Quit" {
+		t.Errorf("Unexpected dark hints %q", got)
+	}
+	if resolvePalette("solarized") != dark {
+		t.Error("Expected an unknown theme to fall back to dark")
+	}
+
+	plain := resolvePalette(config.ThemeNoColor)
+	if got := plain.hints("q", "Quit", "Enter", "Details"); got != "q: Quit | Enter: Details" {
+		t.Errorf("Expected no color tags, got %q", got)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if !resolvePalette(config.ThemeHighContrast).noColor {
+		t.Error("Expected NO_COLOR to override the configured theme")
+	}
+}
+
+func TestPaletteSnippet(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	snippet := &client.SearchSnippet{Text: "[x] printer down"}
+	snippet.Highlights = append(snippet.Highlights, struct {
+		Start int `json:"start"`
+		End   int `json:"end"`
+	}{Start: 4, End: 11})
+
+	got := resolvePalette(config.ThemeNoColor).snippet(snippet, tcell.ColorGray)
+	want := "[x[] [::b]printer[::-] down"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
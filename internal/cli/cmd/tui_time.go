@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/soarinferret/jats/internal/cli/client"
+	"github.com/soarinferret/jats/internal/cli/config"
+)
+
+// timeStep is how much + and - change the duration in the time dialog
+const timeStep = 15
+
+// defaultTimeDescription is the description of time logged from the TUI
+// when none is given
+const defaultTimeDescription = "Time logged via TUI"
+
+// adjustDuration adds delta minutes to a duration typed in the time dialog,
+// rounding to the step first so repeated presses land on quarter hours. An
+// unparseable duration counts as zero; the result never goes below zero,
+// where it is empty.
+func adjustDuration(text string, delta int) string {
+	minutes, err := client.ParseDuration(text)
+	if err != nil {
+		minutes = 0
+	}
+	if rem := minutes % timeStep; rem != 0 {
+		if delta > 0 {
+			minutes -= rem
+		} else {
+			minutes += timeStep - rem
+		}
+	}
+	minutes += delta
+	if minutes <= 0 {
+		return ""
+	}
+	return tuiFormatDuration(minutes)
+}
+
+// minutesSinceLastEntry returns the minutes between now and the end of the
+// user's latest time entry, taking an entry's creation time as the moment the
+// work it records ended. entries are newest first, as GetTimeEntries returns
+// them. Without a username every entry counts.
+func minutesSinceLastEntry(entries []client.TimeEntry, username string, now time.Time) (int, bool) {
+	for _, entry := range entries {
+		if username != "" && entry.CreatedBy != username {
+			continue
+		}
+		minutes := int(now.Sub(entry.CreatedAt).Minutes())
+		if minutes <= 0 {
+			return 0, false
+		}
+		return minutes, true
+	}
+	return 0, false
+}
+
+// lastTimeDescription returns the description of the user's latest time entry
+// on a task, for logging more of the same work. Entries logged before authors
+// were recorded count as the user's.
+func lastTimeDescription(task *client.Task, username string) string {
+	var latest *client.TimeEntry
+	for i := range task.TimeEntries {
+		entry := &task.TimeEntries[i]
+		if entry.Description == "" || (entry.CreatedBy != "" && entry.CreatedBy != username) {
+			continue
+		}
+		if latest == nil || entry.CreatedAt.After(latest.CreatedAt) {
+			latest = entry
+		}
+	}
+	if latest == nil {
+		return defaultTimeDescription
+	}
+	return latest.Description
+}
+
+// lastTimeBillable returns whether the user's latest time entry on a task was
+// billable, so more of the same work is billed the same way; nil when the user
+// has logged no time on it yet
+func lastTimeBillable(task *client.Task, username string) *bool {
+	var latest *client.TimeEntry
+	for i := range task.TimeEntries {
+		entry := &task.TimeEntries[i]
+		if entry.CreatedBy != "" && entry.CreatedBy != username {
+			continue
+		}
+		if latest == nil || entry.CreatedAt.After(latest.CreatedAt) {
+			latest = entry
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	return &latest.Billable
+}
+
+// currentUsername returns the username the CLI is logged in as, if known
+func currentUsername() string {
+	if cfg := config.GetCurrent(); cfg != nil {
+		return cfg.Username
+	}
+	return ""
+}
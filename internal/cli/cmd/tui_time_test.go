@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/cli/client"
+)
+
+func TestAdjustDuration(t *testing.T) {
+	for _, tt := range []struct {
+		text  string
+		delta int
+		want  string
+	}{
+		{"", timeStep, "15m"},
+		{"45m", timeStep, "1h"},
+		{"1h", timeStep, "1h15m"},
+		{"20m", timeStep, "30m"},
+		{"20m", -timeStep, "15m"},
+		{"15m", -timeStep, ""},
+		{"", -timeStep, ""},
+		{"soon", timeStep, "15m"},
+	} {
+		if got := adjustDuration(tt.text, tt.delta); got != tt.want {
+			t.Errorf("adjustDuration(%q, %d) = %q, want %q", tt.text, tt.delta, got, tt.want)
+		}
+	}
+}
+
+func TestMinutesSinceLastEntry(t *testing.T) {
+	now := time.Date(2025, 3, 4, 15, 0, 0, 0, time.UTC)
+	entries := []client.TimeEntry{
+		{CreatedBy: "bob", CreatedAt: now.Add(-10 * time.Minute)},
+		{CreatedBy: "alice", CreatedAt: now.Add(-95 * time.Minute)},
+		{CreatedBy: "alice", CreatedAt: now.Add(-3 * time.Hour)},
+	}
+
+	if minutes, ok := minutesSinceLastEntry(entries, "alice", now); !ok || minutes != 95 {
+		t.Errorf("Expected 95 minutes since alice's last entry, got %d (%t)", minutes, ok)
+	}
+	if minutes, ok := minutesSinceLastEntry(entries, "", now); !ok || minutes != 10 {
+		t.Errorf("Expected 10 minutes since the last entry, got %d (%t)", minutes, ok)
+	}
+	if _, ok := minutesSinceLastEntry(entries, "carol", now); ok {
+		t.Error("Expected no entry for carol")
+	}
+}
+
+func TestLastTimeDescription(t *testing.T) {
+	now := time.Now()
+	task := &client.Task{TimeEntries: []client.TimeEntry{
+		{Description: "Triage", CreatedAt: now.Add(-2 * time.Hour)},
+		{Description: "Printer driver", CreatedBy: "alice", CreatedAt: now.Add(-time.Hour)},
+		{Description: "Bob's call", CreatedBy: "bob", CreatedAt: now},
+	}}
+
+	if got := lastTimeDescription(task, "alice"); got != "Printer driver" {
+		t.Errorf("Expected alice's latest description, got %q", got)
+	}
+	if got := lastTimeDescription(task, "carol"); got != "Triage" {
+		t.Errorf("Expected the unattributed entry for carol, got %q", got)
+	}
+	if got := lastTimeDescription(&client.Task{}, "alice"); got != defaultTimeDescription {
+		t.Errorf("Expected the default description, got %q", got)
+	}
+}
+
+func TestLastTimeBillable(t *testing.T) {
+	now := time.Now()
+	task := &client.Task{TimeEntries: []client.TimeEntry{
+		{Billable: true, CreatedBy: "alice", CreatedAt: now.Add(-2 * time.Hour)},
+		{Billable: false, CreatedBy: "alice", CreatedAt: now.Add(-time.Hour)},
+		{Billable: true, CreatedBy: "bob", CreatedAt: now},
+	}}
+
+	if got := lastTimeBillable(task, "alice"); got == nil || *got {
+		t.Errorf("Expected alice's latest entry to be non-billable, got %v", got)
+	}
+	if got := lastTimeBillable(task, "bob"); got == nil || !*got {
+		t.Errorf("Expected bob's latest entry to be billable, got %v", got)
+	}
+	if got := lastTimeBillable(task, "carol"); got != nil {
+		t.Errorf("Expected no billable default for carol, got %v", *got)
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/soarinferret/jats/internal/cli/client"
+)
+
+// watchWait is how long each long-poll asks the server to hold the request,
+// kept below the client's request timeout
+const watchWait = 25
+
+// watchRetryDelay is how long to back off after a failed poll
+const watchRetryDelay = 5 * time.Second
+
+var (
+	watchQuery string
+	watchTypes []string
+	watchTag   string
+	watchTask  uint
+	watchLast  int
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Follow task activity as it happens",
+	Long: `Print task events (tasks created, status changes, notes, time logged) as
+they happen, like tail -f for the task queue. Starts with the most recent
+events and keeps going until interrupted with Ctrl+C.
+
+Activity types: created, status_changed, commented, time_logged, assigned,
+reviewed
+
+Examples:
+  jats watch
+  jats watch --query support
+  jats watch --type created,commented --tag client1`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := client.New()
+
+		filters := &client.ActivityFilters{
+			Types:  watchTypes,
+			TaskID: watchTask,
+			Tag:    watchTag,
+		}
+		if watchQuery != "" {
+			queryID, err := resolveSavedQueryID(c, watchQuery)
+			if err != nil {
+				return err
+			}
+			filters.QueryID = queryID
+		}
+
+		// Show some context first; it also tells us where to follow on from
+		filters.Limit = max(watchLast, 1)
+		events, _, err := c.GetActivity(filters)
+		if err != nil {
+			return fmt.Errorf("failed to get activity: %w", err)
+		}
+		if len(events) > 0 {
+			filters.After = events[0].Timestamp
+		}
+		for i := min(watchLast, len(events)) - 1; i >= 0; i-- {
+			fmt.Println(formatActivityLine(events[i]))
+		}
+		fmt.Fprintln(os.Stderr, "Watching for activity (Ctrl+C to stop)...")
+
+		filters.Limit = 100
+		filters.Wait = watchWait
+		for {
+			events, total, err := c.GetActivity(filters)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v (retrying in %s)\n", err, watchRetryDelay)
+				time.Sleep(watchRetryDelay)
+				continue
+			}
+			if len(events) == 0 {
+				continue
+			}
+
+			if total > len(events) {
+				fmt.Printf("… %d earlier events skipped\n", total-len(events))
+			}
+			for i := len(events) - 1; i >= 0; i-- {
+				fmt.Println(formatActivityLine(events[i]))
+			}
+			filters.After = events[0].Timestamp
+		}
+	},
+}
+
+// resolveSavedQueryID accepts a saved query ID or name (case-insensitive)
+func resolveSavedQueryID(c *client.Client, query string) (uint, error) {
+	if id, err := strconv.ParseUint(query, 10, 32); err == nil {
+		return uint(id), nil
+	}
+
+	queries, err := c.GetSavedQueries()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get saved queries: %w", err)
+	}
+	for _, q := range queries {
+		if strings.EqualFold(q.Name, query) {
+			return q.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("saved query not found: %s (see 'jats queries')", query)
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().StringVarP(&watchQuery, "query", "q", "", "Only activity on tasks matching this saved query (name or ID)")
+	watchCmd.Flags().StringSliceVar(&watchTypes, "type", nil, "Only these activity types (comma separated)")
+	watchCmd.Flags().StringVar(&watchTag, "tag", "", "Only activity on tasks with this tag")
+	watchCmd.Flags().UintVar(&watchTask, "task", 0, "Only activity on this task")
+	watchCmd.Flags().IntVarP(&watchLast, "last", "n", 10, "Number of recent events to show before following")
+}
@@ -1,36 +1,143 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/pelletier/go-toml/v2"
+	"github.com/soarinferret/jats/internal/cli/keyring"
 )
 
 var currentConfig *Config
 
 type Config struct {
 	ServerURL string `toml:"server_url"`
-	Token     string `toml:"token"`
+	Token     string `toml:"token,omitempty"`
 	Username  string `toml:"username"`
+	Language  string `toml:"language,omitempty"`
+	AgingDays *int   `toml:"aging_days,omitempty"`
+	// TUI color theme: "dark", "light", "high-contrast" or "no-color"
+	Theme string `toml:"theme,omitempty"`
+	// How the TUI and jats notify report mentions: "desktop", "bell" or "off"
+	Notifications string `toml:"notifications,omitempty"`
+
+	// Where the token is kept: "auto" (OS keyring when available, else this
+	// file), "keyring" or "file"
+	CredentialStore string `toml:"credential_store,omitempty"`
+	// When the saved session token expires
+	TokenExpiresAt *time.Time `toml:"token_expires_at,omitempty"`
+
+	// File the config was loaded from, used when saving
+	path string
+	// Where the token was loaded from or saved to, and the keyring's copy
+	tokenStore   string
+	keyringToken string
 }
 
-// Load loads configuration from file or creates default config
-func Load(configFile string) (*Config, error) {
+// Credential store settings
+const (
+	CredentialStoreAuto    = "auto"
+	CredentialStoreKeyring = "keyring"
+	CredentialStoreFile    = "file"
+)
+
+// keyringService names the CLI's entries in the OS keyring; the account is the
+// config file's path so several config files keep separate tokens
+const keyringService = "jats"
+
+// GetCredentialStore returns the credential_store setting, defaulting to auto
+func (c *Config) GetCredentialStore() string {
+	if c.CredentialStore == "" {
+		return CredentialStoreAuto
+	}
+	return c.CredentialStore
+}
+
+// TokenStore describes where the token is kept, e.g. "macOS Keychain" or the config file path
+func (c *Config) TokenStore() string {
+	if c.tokenStore != "" {
+		return c.tokenStore
+	}
+	return c.path
+}
+
+// DefaultAgingDays is how long a task may stay in progress before the TUI flags it
+const DefaultAgingDays = 3
+
+// GetAgingDays returns the aging threshold in days, or DefaultAgingDays if unset. Zero disables aging.
+func (c *Config) GetAgingDays() int {
+	if c.AgingDays == nil {
+		return DefaultAgingDays
+	}
+	return *c.AgingDays
+}
+
+// TUI color themes
+const (
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeNoColor      = "no-color"
+)
+
+// Themes lists the supported theme settings
+var Themes = []string{ThemeDark, ThemeLight, ThemeHighContrast, ThemeNoColor}
+
+// GetTheme returns the theme setting, defaulting to dark
+func (c *Config) GetTheme() string {
+	if c.Theme == "" {
+		return ThemeDark
+	}
+	return c.Theme
+}
+
+// Notification settings
+const (
+	NotificationsDesktop = "desktop"
+	NotificationsBell    = "bell"
+	NotificationsOff     = "off"
+)
+
+// NotificationModes lists the supported notifications settings
+var NotificationModes = []string{NotificationsDesktop, NotificationsBell, NotificationsOff}
+
+// GetNotifications returns the notifications setting, defaulting to desktop
+func (c *Config) GetNotifications() string {
+	if c.Notifications == "" {
+		return NotificationsDesktop
+	}
+	return c.Notifications
+}
+
+// configPath returns the absolute path of configFile, defaulting to ~/.jats.toml
+func configPath(configFile string) (string, error) {
 	if configFile == "" {
 		home, err := os.UserHomeDir()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
+			return "", fmt.Errorf("failed to get home directory: %w", err)
 		}
 		configFile = filepath.Join(home, ".jats.toml")
 	}
+	return filepath.Abs(configFile)
+}
+
+// Load loads configuration from file or creates default config. A token kept in
+// the OS keyring is loaded from there.
+func Load(configFile string) (*Config, error) {
+	configFile, err := configPath(configFile)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if config file exists
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
 		// Create default config
 		cfg := &Config{
 			ServerURL: "http://localhost:8081",
+			path:      configFile,
 		}
 		
 		// Try to save default config
@@ -50,18 +157,39 @@ func Load(configFile string) (*Config, error) {
 	if err := toml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	cfg.path = configFile
+
+	if cfg.Token == "" && cfg.CredentialStore != CredentialStoreFile {
+		// A locked or unreachable keyring leaves the CLI logged out rather than failing
+		if token, err := keyring.Get(keyringService, configFile); err == nil {
+			cfg.Token = token
+			cfg.keyringToken = token
+			cfg.tokenStore = keyring.Backend()
+		}
+	}
 
 	return &cfg, nil
 }
 
-// Save saves configuration to file
+// Save saves configuration to file, defaulting to the file it was loaded from.
+// The token goes to the OS keyring unless credential_store is "file"; with
+// "auto" it falls back to the file when no keyring is available.
 func Save(cfg *Config, configFile string) error {
 	if configFile == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("failed to get home directory: %w", err)
-		}
-		configFile = filepath.Join(home, ".jats.toml")
+		configFile = cfg.path
+	}
+	configFile, err := configPath(configFile)
+	if err != nil {
+		return err
+	}
+	cfg.path = configFile
+
+	stored := *cfg
+	if err := cfg.storeToken(); err != nil {
+		return err
+	}
+	if cfg.tokenStore != configFile {
+		stored.Token = ""
 	}
 
 	// Ensure directory exists
@@ -70,7 +198,7 @@ func Save(cfg *Config, configFile string) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	data, err := toml.Marshal(cfg)
+	data, err := toml.Marshal(&stored)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -82,6 +210,37 @@ func Save(cfg *Config, configFile string) error {
 	return nil
 }
 
+// storeToken saves or removes the token in the OS keyring and records where it
+// is kept; tokenStore is set to the config file path when the file keeps it
+func (c *Config) storeToken() error {
+	if c.CredentialStore == CredentialStoreFile || c.Token == "" {
+		// Drop any copy left in the keyring
+		if c.keyringToken != "" || c.tokenStore == "" {
+			if err := keyring.Delete(keyringService, c.path); err != nil && !errors.Is(err, keyring.ErrNotFound) && !errors.Is(err, keyring.ErrUnsupported) {
+				return fmt.Errorf("failed to remove token from %s: %w", keyring.Backend(), err)
+			}
+		}
+		c.keyringToken = ""
+		c.tokenStore = c.path
+		return nil
+	}
+
+	if c.Token == c.keyringToken {
+		return nil
+	}
+	err := keyring.Set(keyringService, c.path, c.Token)
+	if err == nil {
+		c.keyringToken = c.Token
+		c.tokenStore = keyring.Backend()
+		return nil
+	}
+	if c.CredentialStore == CredentialStoreKeyring {
+		return fmt.Errorf("failed to save token in the OS keyring: %w", err)
+	}
+	c.tokenStore = c.path
+	return nil
+}
+
 // SetCurrent sets the current global config
 func SetCurrent(cfg *Config) {
 	currentConfig = cfg
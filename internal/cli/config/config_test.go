@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/cli/keyring"
+)
+
+func TestTokenStoredInKeyring(t *testing.T) {
+	keyring.MockInit()
+	path := filepath.Join(t.TempDir(), "jats.toml")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.Username = "alice"
+	cfg.Token = "session-secret"
+	if err := Save(cfg, ""); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "session-secret") {
+		t.Errorf("Expected the token to stay out of the config file:\n%s", data)
+	}
+	if cfg.TokenStore() != "mock keyring" {
+		t.Errorf("Expected the token in the keyring, got %q", cfg.TokenStore())
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if loaded.Token != "session-secret" || loaded.Username != "alice" {
+		t.Errorf("Expected the token back from the keyring, got %+v", loaded)
+	}
+
+	// Logging out removes the keyring entry
+	loaded.Token = ""
+	if err := Save(loaded, ""); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if _, err := keyring.Get(keyringService, path); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("Expected the keyring entry removed, got %v", err)
+	}
+}
+
+func TestTokenFallsBackToFile(t *testing.T) {
+	keyring.MockInitWithError(keyring.ErrUnsupported)
+	path := filepath.Join(t.TempDir(), "jats.toml")
+
+	cfg := &Config{ServerURL: "http://localhost:8081", Token: "session-secret"}
+	if err := Save(cfg, path); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if cfg.TokenStore() != path {
+		t.Errorf("Expected the token in the config file, got %q", cfg.TokenStore())
+	}
+	loaded, err := Load(path)
+	if err != nil || loaded.Token != "session-secret" {
+		t.Errorf("Expected the token from the file, got %+v (%v)", loaded, err)
+	}
+
+	cfg.CredentialStore = CredentialStoreKeyring
+	if err := Save(cfg, path); err == nil {
+		t.Error("Expected an error when the keyring is required but unavailable")
+	}
+}
+
+func TestCredentialStoreFile(t *testing.T) {
+	keyring.MockInit()
+	path := filepath.Join(t.TempDir(), "jats.toml")
+
+	cfg := &Config{ServerURL: "http://localhost:8081", Token: "session-secret"}
+	if err := Save(cfg, path); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	// Switching to file moves the token out of the keyring
+	cfg.CredentialStore = CredentialStoreFile
+	if err := Save(cfg, path); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if _, err := keyring.Get(keyringService, path); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("Expected the keyring entry removed, got %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "session-secret") {
+		t.Errorf("Expected the token in the config file:\n%s", data)
+	}
+}
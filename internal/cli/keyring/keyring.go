@@ -0,0 +1,91 @@
+// Package keyring stores secrets in the operating system's credential store
+// through github.com/zalando/go-keyring: the macOS Keychain, the freedesktop
+// Secret Service (GNOME Keyring, KWallet) or the Windows Credential Manager.
+package keyring
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+
+	gokeyring "github.com/zalando/go-keyring"
+)
+
+var (
+	// ErrNotFound is returned when the keyring holds no secret for the service and user
+	ErrNotFound = gokeyring.ErrNotFound
+	// ErrUnsupported is returned when no OS keyring is available
+	ErrUnsupported = errors.New("no OS keyring available")
+)
+
+// probeService is looked up to find out whether the OS keyring answers
+const probeService = "jats-keyring-probe"
+
+var (
+	name     string
+	nameOnce sync.Once
+)
+
+// Backend returns the name of the OS keyring in use, or "" if none is available
+func Backend() string {
+	nameOnce.Do(func() {
+		// A missing secret is the answer of a working keyring; anything else
+		// means there is none, or it is unreachable
+		if _, err := gokeyring.Get(probeService, ""); err == nil || errors.Is(err, gokeyring.ErrNotFound) {
+			name = platformName()
+		}
+	})
+	return name
+}
+
+// platformName names the credential store go-keyring uses on this platform
+func platformName() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "macOS Keychain"
+	case "windows":
+		return "Windows Credential Manager"
+	}
+	return "Secret Service"
+}
+
+// Set stores secret for service and user, replacing any existing one
+func Set(service, user, secret string) error {
+	if Backend() == "" {
+		return ErrUnsupported
+	}
+	return gokeyring.Set(service, user, secret)
+}
+
+// Get returns the secret for service and user
+func Get(service, user string) (string, error) {
+	if Backend() == "" {
+		return "", ErrUnsupported
+	}
+	return gokeyring.Get(service, user)
+}
+
+// Delete removes the secret for service and user
+func Delete(service, user string) error {
+	if Backend() == "" {
+		return ErrUnsupported
+	}
+	return gokeyring.Delete(service, user)
+}
+
+// MockInit replaces the OS keyring with an in-memory one, for tests
+func MockInit() {
+	gokeyring.MockInit()
+	mock()
+}
+
+// MockInitWithError makes every keyring call fail with err, for tests
+func MockInitWithError(err error) {
+	gokeyring.MockInitWithError(err)
+	mock()
+}
+
+func mock() {
+	nameOnce.Do(func() {})
+	name = "mock keyring"
+}
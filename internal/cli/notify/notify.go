@@ -0,0 +1,70 @@
+// Package notify shows desktop notifications through the platform's command
+// line tools: notify-send (libnotify) on Linux and the BSDs, and osascript on
+// macOS. Where neither is available it falls back to the terminal bell.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/cli/config"
+)
+
+// ErrUnsupported is returned when no desktop notification tool is available
+var ErrUnsupported = errors.New("no desktop notification tool available")
+
+// Desktop shows a desktop notification with title and body
+func Desktop(title, body string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		path, err := exec.LookPath("osascript")
+		if err != nil {
+			return ErrUnsupported
+		}
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptQuote(body), appleScriptQuote(title))
+		if out, err := exec.Command(path, "-e", script).CombinedOutput(); err != nil {
+			return fmt.Errorf("osascript: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		path, err := exec.LookPath("notify-send")
+		if err != nil {
+			return ErrUnsupported
+		}
+		if out, err := exec.Command(path, "--app-name", "jats", title, body).CombinedOutput(); err != nil {
+			return fmt.Errorf("notify-send: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	return ErrUnsupported
+}
+
+// Bell rings the terminal bell
+func Bell() {
+	os.Stderr.WriteString("\a")
+}
+
+// Send notifies according to a notifications setting: a desktop notification,
+// falling back to the bell when none can be shown, just the bell, or nothing
+func Send(mode, title, body string) {
+	switch mode {
+	case config.NotificationsOff:
+		return
+	case config.NotificationsBell:
+		Bell()
+	default:
+		if err := Desktop(title, body); err != nil {
+			Bell()
+		}
+	}
+}
+
+// appleScriptQuote quotes s as an AppleScript string literal
+func appleScriptQuote(s string) string {
+	return strconv.Quote(s)
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pelletier/go-toml/v2"
@@ -11,13 +13,302 @@ import (
 
 type Config struct {
 	Port       string      `toml:"port"`
+	// Interface to bind, e.g. 127.0.0.1; empty listens on all interfaces
+	ListenAddress string   `toml:"listen_address"`
+	// URL prefix when served from a reverse proxy sub-path, e.g. /jats; empty serves at /
+	BasePath   string      `toml:"base_path"`
+	// Reverse proxies (IPs or CIDR ranges) whose X-Forwarded-For/-Proto headers are trusted
+	TrustedProxies []string `toml:"trusted_proxies"`
 	DBHost     string      `toml:"db_host"`
 	DBPort     string      `toml:"db_port"`
 	DBUser     string      `toml:"db_user"`
 	DBPassword string      `toml:"db_password"`
+	// File holding the database password, e.g. a Docker or Kubernetes secret
+	DBPasswordFile string  `toml:"db_password_file"`
 	DBName     string      `toml:"db_name"`
 	DBURL      string      `toml:"db_url"`
+	DBURLFile  string      `toml:"db_url_file"`
 	Email      EmailConfig `toml:"email"`
+	Kanban     KanbanConfig `toml:"kanban"`
+	Spam       SpamConfig   `toml:"spam"`
+	Retention  RetentionConfig `toml:"retention"`
+	Automation AutomationConfig `toml:"automation"`
+	Security   SecurityConfig  `toml:"security"`
+	CORS       CORSConfig      `toml:"cors"`
+	AccessLog  AccessLogConfig `toml:"access_log"`
+	// Webhook channels that open and resolve tasks, keyed by the channel name
+	// in POST /api/v1/inbound/{channel}, e.g. [inbound.grafana]
+	Inbound    map[string]InboundChannelConfig `toml:"inbound"`
+	// Prometheus Alertmanager receiver at POST /api/v1/inbound/alertmanager
+	Alertmanager AlertmanagerConfig `toml:"alertmanager"`
+	// External issue trackers that mirror a saved query's tasks, keyed by
+	// target name, e.g. [sync.jira]
+	Sync map[string]SyncTargetConfig `toml:"sync"`
+	// Auto-assignment of tasks arriving by email or inbound webhook, keyed by
+	// tag, e.g. [assignment.support]
+	Assignment map[string]AssignmentConfig `toml:"assignment"`
+	// Review gate on resolving tasks, keyed by tag, e.g. [review.change]
+	Review map[string]ReviewConfig `toml:"review"`
+	// Serve several clients from one jatsd, each with its own database
+	Tenancy TenancyConfig `toml:"tenancy"`
+	// Encryption at rest of sensitive database columns such as TOTP secrets
+	Encryption EncryptionConfig `toml:"encryption"`
+	// Server-side processing of audio attachments
+	Media MediaConfig `toml:"media"`
+	// Quick capture from phones at POST /api/v1/capture/mobile
+	Capture CaptureConfig `toml:"capture"`
+	// Whether time is billable by default
+	Billing BillingConfig `toml:"billing"`
+	// Tengo scripts that react to task events and serve custom API routes
+	Plugins PluginsConfig `toml:"plugins"`
+}
+
+// PluginsConfig loads every *.tengo script in a directory as a plugin. A
+// plugin runs on each task event, with the event in its "event" variable, and
+// on each request to /api/v1/plugins/{name}/..., with the request in its
+// "request" variable; it answers a request by setting "response". Scripts can
+// import the "jats" module to read and change tasks (changes fail in GET
+// requests), and the text, times,
+// math, json, enum, base64 and hex modules of the Tengo standard library, but
+// not os or files.
+type PluginsConfig struct {
+	// Directory of the scripts; empty disables plugins
+	Dir string `toml:"dir"`
+	// How long a script may run for one event or request
+	Timeout string `toml:"timeout"`
+}
+
+// BillingConfig sets whether new time entries are billable when they do not
+// say. Time is billable unless its task has one of the non-billable tags.
+type BillingConfig struct {
+	// Tags of internal work, e.g. ["internal", "admin"]
+	NonBillableTags []string `toml:"non_billable_tags"`
+}
+
+// CaptureConfig names the places mobile captures are tagged with. A capture
+// sent from within a place's radius gets the tag location:{name}.
+type CaptureConfig struct {
+	// Known places keyed by name, e.g. [capture.places.office]
+	Places map[string]CapturePlaceConfig `toml:"places"`
+}
+
+type CapturePlaceConfig struct {
+	Latitude  float64 `toml:"latitude"`
+	Longitude float64 `toml:"longitude"`
+	// Distance from the coordinates still counted as the place; 0 means 250
+	RadiusMeters float64 `toml:"radius_meters"`
+}
+
+// MediaConfig enables transcoding of audio attachments, such as voicemail
+// forwarded by email, into AAC with normalized loudness, which every browser
+// plays inline at a comfortable volume. It needs ffmpeg on the server.
+type MediaConfig struct {
+	TranscodeAudio bool `toml:"transcode_audio"`
+	// ffmpeg binary to run; empty looks up "ffmpeg" on the PATH
+	FFmpegPath string `toml:"ffmpeg_path"`
+}
+
+// EncryptionConfig sets the AES-256-GCM key sensitive columns are encrypted
+// with. Without a key they are stored in plain text. To rotate, set the new
+// key, move the old one to PreviousKeys, run jatsd -rotate-encryption-key,
+// then drop the old key.
+type EncryptionConfig struct {
+	// Base64-encoded 32-byte key, e.g. from "openssl rand -base64 32"
+	Key string `toml:"key"`
+	// File to read the key from instead, e.g. one written by a KMS or secrets
+	// manager agent
+	KeyFile string `toml:"key_file"`
+	// Retired keys, still used to decrypt values written before a rotation
+	PreviousKeys []string `toml:"previous_keys"`
+}
+
+// TenancyConfig enables multi-tenant mode. Each tenant gets its own users,
+// tasks, tags and saved queries in a database of its own (a SQLite file under
+// DataDir, or a schema of the PostgreSQL database), and is reached by subdomain
+// or path. Requests that name no tenant go to the main instance, whose admins
+// provision tenants.
+type TenancyConfig struct {
+	Enabled bool `toml:"enabled"`
+	// "path" serves tenants under /t/{slug}/, "subdomain" under {slug}.{domain}
+	Mode string `toml:"mode"`
+	// Domain tenant subdomains belong to, e.g. "jats.example.com"; subdomain mode only
+	Domain string `toml:"domain"`
+	// Where tenant SQLite databases and attachments are kept; defaults to ./tenants
+	DataDir string `toml:"data_dir"`
+}
+
+// AssignmentConfig assigns unassigned tasks with a tag, arriving by email or
+// inbound webhook, to one of a group of users
+type AssignmentConfig struct {
+	// "round_robin" takes turns; "least_loaded" picks the user with the fewest
+	// open and in-progress tasks
+	Policy string   `toml:"policy"`
+	Users  []string `toml:"users"`
+}
+
+// ReviewConfig makes tasks with a tag wait for review when resolved: they move
+// to pending-review and the reviewers are emailed. One of the reviewers then
+// approves the task, resolving it, or reopens it with a comment.
+type ReviewConfig struct {
+	// Usernames of the users who may approve or reopen the tag's tasks
+	Reviewers []string `toml:"reviewers"`
+}
+
+// SyncTargetConfig mirrors the tasks of a saved query into a Jira project or a
+// GitLab issue tracker. Issues are created, updated, closed and reopened with
+// their tasks, public comments are copied both ways, and issues closed or
+// reopened in the tracker close or reopen their tasks.
+type SyncTargetConfig struct {
+	// "jira" or "gitlab"
+	Type string `toml:"type"`
+	// Base URL, e.g. https://example.atlassian.net or https://gitlab.com
+	URL string `toml:"url"`
+	// Jira account email for basic auth with an API token; empty sends the
+	// token as a bearer token (GitLab, Jira personal access tokens)
+	Username string `toml:"username"`
+	Token    string `toml:"token"`
+	TokenFile string `toml:"token_file"`
+	// Jira project key, or GitLab project ID or path (group/project)
+	Project string `toml:"project"`
+	// Saved query whose tasks are mirrored
+	SavedQueryID uint `toml:"saved_query_id"`
+	// Jira issue type for new issues (default "Task")
+	IssueType string `toml:"issue_type"`
+	// Jira transitions used to close and reopen issues (default "Done" and "To Do")
+	CloseTransition  string `toml:"close_transition"`
+	ReopenTransition string `toml:"reopen_transition"`
+	// How often to sync (default 5m)
+	Interval string `toml:"interval"`
+}
+
+// InboundChannelConfig maps a monitoring system's webhook payload onto a task.
+// Name, Description, Priority, Tags, Key and Resolve are Go templates over the
+// JSON payload, e.g. "{{.title}}" or "{{.monitor.name}} is down".
+type InboundChannelConfig struct {
+	// Shared secret, sent as "Authorization: Bearer <secret>", an X-Inbound-Secret header or ?secret=
+	Secret      string   `toml:"secret"`
+	SecretFile  string   `toml:"secret_file"`
+	Name        string   `toml:"name"`
+	Description string   `toml:"description"`
+	Priority    string   `toml:"priority"`
+	Tags        []string `toml:"tags"`
+	// Identifies the alert across deliveries, so repeats update the task it opened
+	// instead of opening another; empty opens a task per delivery
+	Key string `toml:"key"`
+	// Renders "true" when the delivery reports the alert has cleared, resolving
+	// the task, e.g. '{{eq .status "resolved"}}'
+	Resolve string `toml:"resolve"`
+}
+
+// AlertmanagerConfig configures the Prometheus Alertmanager receiver, which
+// opens a task per alert, keyed by its fingerprint, and resolves it when the
+// alert resolves
+type AlertmanagerConfig struct {
+	// Shared secret, sent as "Authorization: Bearer <secret>" (the receiver's
+	// http_config.authorization) or ?secret=. Empty disables the receiver.
+	Secret     string `toml:"secret"`
+	SecretFile string `toml:"secret_file"`
+	// Tags added to every alert's task
+	Tags []string `toml:"tags"`
+	// Priority and extra tags by the alerts' severity label, e.g.
+	// [alertmanager.severity.critical] priority = "high", tags = ["oncall"]
+	Severity map[string]AlertSeverityConfig `toml:"severity"`
+}
+
+type AlertSeverityConfig struct {
+	Priority string   `toml:"priority"`
+	Tags     []string `toml:"tags"`
+}
+
+type SecurityConfig struct {
+	// Content-Security-Policy sent with web UI pages; empty uses the built-in policy
+	ContentSecurityPolicy string `toml:"content_security_policy"`
+	// Send Strict-Transport-Security on HTTPS requests
+	HSTS bool `toml:"hsts"`
+}
+
+type CORSConfig struct {
+	// Origins allowed to call the API from a browser, e.g. https://app.example.com or
+	// chrome-extension://<id>. "*" allows any origin but never with credentials.
+	AllowedOrigins []string `toml:"allowed_origins"`
+	// Let allowed origins send cookies (Access-Control-Allow-Credentials)
+	AllowCredentials bool `toml:"allow_credentials"`
+	// How long browsers may cache preflight responses, in seconds
+	MaxAge int `toml:"max_age"`
+}
+
+type AccessLogConfig struct {
+	// Write a JSON line per request (method, route, status, latency, user); bodies of
+	// failed requests are included with passwords and tokens redacted
+	Enabled bool `toml:"enabled"`
+	// Where to write the log: "stdout", "stderr" or a file path
+	Output string `toml:"output"`
+}
+
+type RetentionConfig struct {
+	// How often the background janitor enforces the retention settings below
+	Interval string `toml:"interval"`
+	// Login attempts older than this many hours are deleted (minimum 1, they drive rate limiting)
+	LoginAttemptsHours int `toml:"login_attempts_hours"`
+	// Expired sessions are kept this many days as a sign-in history (0 deletes them on expiry)
+	SessionDays int `toml:"session_days"`
+	// Task status history older than this many days is deleted (0 keeps it forever)
+	ActivityDays int `toml:"activity_days"`
+	// Resolved and closed tasks are archived (soft-deleted) this many days after resolution (0 disables)
+	ArchiveResolvedDays int `toml:"archive_resolved_days"`
+}
+
+// AutomationConfig closes long-resolved tasks, nags about open tasks that
+// have gone quiet, and escalates old open tasks. Day values of 0 disable a rule.
+type AutomationConfig struct {
+	// How often the automation rules are applied
+	Interval string `toml:"interval"`
+	// Resolved tasks untouched for this many days are closed
+	CloseResolvedDays int `toml:"close_resolved_days"`
+	// Open and in-progress tasks with no activity for this many days get a
+	// reminder note, and JATS users are notified
+	StaleOpenDays int `toml:"stale_open_days"`
+	// Reminder note posted on stale tasks; empty uses a default
+	StaleMessage string `toml:"stale_message"`
+	// Open and in-progress tasks get their priority raised one step (low to
+	// medium to high) each time this many days pass without being resolved, and
+	// an escalation note is posted. Tasks without a priority count as low.
+	EscalateDays int `toml:"escalate_days"`
+	// Escalation note posted on escalated tasks; empty uses a default
+	EscalateMessage string `toml:"escalate_message"`
+	// Per-tag overrides of the day values, keyed by tag, e.g. [automation.tags.waiting]
+	Tags map[string]AutomationTagConfig `toml:"tags"`
+}
+
+// AutomationTagConfig overrides the automation day values for tasks with a
+// tag; unset values fall back to the global ones
+type AutomationTagConfig struct {
+	CloseResolvedDays *int `toml:"close_resolved_days"`
+	StaleOpenDays     *int `toml:"stale_open_days"`
+	EscalateDays      *int `toml:"escalate_days"`
+}
+
+type SpamConfig struct {
+	// Base URL of the rspamd controller, e.g. http://localhost:11334. Empty disables spam filtering.
+	RspamdURL      string `toml:"rspamd_url"`
+	RspamdPassword string `toml:"rspamd_password"`
+	RspamdPasswordFile string `toml:"rspamd_password_file"`
+	// Mail scoring at or above this is held in the quarantine list instead of creating tasks
+	QuarantineScore float64 `toml:"quarantine_score"`
+	// Mail scoring at or above this is discarded outright (0 disables)
+	RejectScore float64 `toml:"reject_score"`
+	Timeout     string  `toml:"timeout"`
+	// Process mail normally when rspamd is unreachable instead of retrying on the next poll
+	FailOpen bool `toml:"fail_open"`
+}
+
+type KanbanConfig struct {
+	// Maximum number of tasks per status column, e.g. { "in-progress" = 5 }. Zero or missing means unlimited.
+	WIPLimits map[string]int `toml:"wip_limits"`
+	// Reject status changes into a column that is already at its limit
+	EnforceWIPLimits bool `toml:"enforce_wip_limits"`
+	// Flag in-progress tasks that have not changed status for more than this many days (0 disables)
+	AgingDays int `toml:"aging_days"`
 }
 
 type EmailConfig struct {
@@ -26,6 +317,7 @@ type EmailConfig struct {
 	IMAPPort           string        `toml:"imap_port"`
 	IMAPUsername       string        `toml:"imap_username"`
 	IMAPPassword       string        `toml:"imap_password"`
+	IMAPPasswordFile   string        `toml:"imap_password_file"`
 	UseSSL             bool          `toml:"imap_use_ssl"`
 	IMAPInsecure       bool          `toml:"imap_insecure_skip_verify"`
 	InboxFolder        string        `toml:"imap_inbox_folder"`
@@ -39,11 +331,24 @@ type EmailConfig struct {
 	SMTPInsecure       bool   `toml:"smtp_insecure_skip_verify"`
 	SMTPUsername       string `toml:"smtp_username"`
 	SMTPPassword       string `toml:"smtp_password"`
+	SMTPPasswordFile   string `toml:"smtp_password_file"`
 	FromName           string `toml:"smtp_from_name"`
 	FromEmail          string `toml:"smtp_from_email"`
+
+	// Directory with email template overrides (see internal/services/templates/email)
+	TemplatesDir       string `toml:"templates_dir"`
+
+	// Hour of the day (0-23, server time) when opt-in standup emails are sent
+	StandupHour        int    `toml:"standup_hour"`
+
+	// Public URL of jatsd, e.g. https://jats.example.com, for the image that
+	// marks email updates as seen when opened; empty leaves the image out
+	TrackingURL        string `toml:"tracking_url"`
 }
 
-// LoadFromFile loads configuration from a TOML file, with environment variable fallbacks
+// LoadFromFile loads configuration from a TOML file, with environment variable
+// fallbacks. String values in the file may reference environment variables as
+// ${NAME}, and secrets may be read from files named by their *_file settings.
 func LoadFromFile(configPath string) (*Config, error) {
 	// Start with default config
 	config := getDefaultConfig()
@@ -58,10 +363,17 @@ func LoadFromFile(configPath string) (*Config, error) {
 		if err := toml.Unmarshal(data, config); err != nil {
 			return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
 		}
+		if err := config.interpolateEnv(); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
+		}
 	}
 	
 	// Override with environment variables if they exist
 	config.applyEnvOverrides()
+
+	if err := config.loadSecretFiles(); err != nil {
+		return nil, err
+	}
 	
 	return config, nil
 }
@@ -104,6 +416,26 @@ func getDefaultConfig() *Config {
 			SMTPPassword: "",
 			FromName:     "JATS",
 			FromEmail:    "",
+			StandupHour:  8,
+		},
+		Kanban: KanbanConfig{
+			AgingDays: 3,
+		},
+		Spam: SpamConfig{
+			QuarantineScore: 6,
+			RejectScore:     15,
+			Timeout:         "10s",
+		},
+		Retention: RetentionConfig{
+			Interval:           "1h",
+			LoginAttemptsHours: 24,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+			MaxAge:         600,
+		},
+		AccessLog: AccessLogConfig{
+			Output: "stdout",
 		},
 	}
 }
@@ -113,6 +445,15 @@ func (c *Config) applyEnvOverrides() {
 	if val := os.Getenv("PORT"); val != "" {
 		c.Port = val
 	}
+	if val := os.Getenv("LISTEN_ADDRESS"); val != "" {
+		c.ListenAddress = val
+	}
+	if val := os.Getenv("BASE_PATH"); val != "" {
+		c.BasePath = val
+	}
+	if val := os.Getenv("TRUSTED_PROXIES"); val != "" {
+		c.TrustedProxies = strings.Split(val, ",")
+	}
 	if val := os.Getenv("DB_HOST"); val != "" {
 		c.DBHost = val
 	}
@@ -123,13 +464,19 @@ func (c *Config) applyEnvOverrides() {
 		c.DBUser = val
 	}
 	if val := os.Getenv("DB_PASSWORD"); val != "" {
-		c.DBPassword = val
+		c.DBPassword, c.DBPasswordFile = val, ""
+	}
+	if val := os.Getenv("DB_PASSWORD_FILE"); val != "" {
+		c.DBPasswordFile = val
 	}
 	if val := os.Getenv("DB_NAME"); val != "" {
 		c.DBName = val
 	}
 	if val := os.Getenv("DB_URL"); val != "" {
-		c.DBURL = val
+		c.DBURL, c.DBURLFile = val, ""
+	}
+	if val := os.Getenv("DB_URL_FILE"); val != "" {
+		c.DBURLFile = val
 	}
 	
 	// Email IMAP settings
@@ -143,7 +490,10 @@ func (c *Config) applyEnvOverrides() {
 		c.Email.IMAPUsername = val
 	}
 	if val := os.Getenv("IMAP_PASSWORD"); val != "" {
-		c.Email.IMAPPassword = val
+		c.Email.IMAPPassword, c.Email.IMAPPasswordFile = val, ""
+	}
+	if val := os.Getenv("IMAP_PASSWORD_FILE"); val != "" {
+		c.Email.IMAPPasswordFile = val
 	}
 	if val := os.Getenv("IMAP_USE_SSL"); val != "" {
 		c.Email.UseSSL = getEnvBool("IMAP_USE_SSL", true)
@@ -183,7 +533,10 @@ func (c *Config) applyEnvOverrides() {
 		c.Email.SMTPUsername = val
 	}
 	if val := os.Getenv("SMTP_PASSWORD"); val != "" {
-		c.Email.SMTPPassword = val
+		c.Email.SMTPPassword, c.Email.SMTPPasswordFile = val, ""
+	}
+	if val := os.Getenv("SMTP_PASSWORD_FILE"); val != "" {
+		c.Email.SMTPPasswordFile = val
 	}
 	if val := os.Getenv("SMTP_FROM_NAME"); val != "" {
 		c.Email.FromName = val
@@ -191,6 +544,168 @@ func (c *Config) applyEnvOverrides() {
 	if val := os.Getenv("SMTP_FROM_EMAIL"); val != "" {
 		c.Email.FromEmail = val
 	}
+	if val := os.Getenv("EMAIL_TEMPLATES_DIR"); val != "" {
+		c.Email.TemplatesDir = val
+	}
+	if val := os.Getenv("STANDUP_EMAIL_HOUR"); val != "" {
+		c.Email.StandupHour = getEnvInt("STANDUP_EMAIL_HOUR", 8)
+	}
+	if val := os.Getenv("EMAIL_TRACKING_URL"); val != "" {
+		c.Email.TrackingURL = val
+	}
+	
+	// Kanban settings
+	if val := os.Getenv("KANBAN_WIP_LIMITS"); val != "" {
+		c.Kanban.WIPLimits = parseWIPLimits(val)
+	}
+	if val := os.Getenv("KANBAN_ENFORCE_WIP_LIMITS"); val != "" {
+		c.Kanban.EnforceWIPLimits = getEnvBool("KANBAN_ENFORCE_WIP_LIMITS", false)
+	}
+	if val := os.Getenv("KANBAN_AGING_DAYS"); val != "" {
+		c.Kanban.AgingDays = getEnvInt("KANBAN_AGING_DAYS", 3)
+	}
+	
+	// Spam filtering settings
+	if val := os.Getenv("RSPAMD_URL"); val != "" {
+		c.Spam.RspamdURL = val
+	}
+	if val := os.Getenv("RSPAMD_PASSWORD"); val != "" {
+		c.Spam.RspamdPassword, c.Spam.RspamdPasswordFile = val, ""
+	}
+	if val := os.Getenv("RSPAMD_PASSWORD_FILE"); val != "" {
+		c.Spam.RspamdPasswordFile = val
+	}
+	if val := os.Getenv("SPAM_QUARANTINE_SCORE"); val != "" {
+		c.Spam.QuarantineScore = getEnvFloat("SPAM_QUARANTINE_SCORE", 6)
+	}
+	if val := os.Getenv("SPAM_REJECT_SCORE"); val != "" {
+		c.Spam.RejectScore = getEnvFloat("SPAM_REJECT_SCORE", 15)
+	}
+	if val := os.Getenv("SPAM_TIMEOUT"); val != "" {
+		c.Spam.Timeout = val
+	}
+	if val := os.Getenv("SPAM_FAIL_OPEN"); val != "" {
+		c.Spam.FailOpen = getEnvBool("SPAM_FAIL_OPEN", false)
+	}
+	
+	// Retention settings
+	if val := os.Getenv("RETENTION_INTERVAL"); val != "" {
+		c.Retention.Interval = val
+	}
+	if val := os.Getenv("RETENTION_LOGIN_ATTEMPTS_HOURS"); val != "" {
+		c.Retention.LoginAttemptsHours = getEnvInt("RETENTION_LOGIN_ATTEMPTS_HOURS", 24)
+	}
+	if val := os.Getenv("RETENTION_SESSION_DAYS"); val != "" {
+		c.Retention.SessionDays = getEnvInt("RETENTION_SESSION_DAYS", 0)
+	}
+	if val := os.Getenv("RETENTION_ACTIVITY_DAYS"); val != "" {
+		c.Retention.ActivityDays = getEnvInt("RETENTION_ACTIVITY_DAYS", 0)
+	}
+	if val := os.Getenv("RETENTION_ARCHIVE_RESOLVED_DAYS"); val != "" {
+		c.Retention.ArchiveResolvedDays = getEnvInt("RETENTION_ARCHIVE_RESOLVED_DAYS", 0)
+	}
+
+	// Security headers and CORS
+	if val := os.Getenv("CONTENT_SECURITY_POLICY"); val != "" {
+		c.Security.ContentSecurityPolicy = val
+	}
+	if val := os.Getenv("HSTS"); val != "" {
+		c.Security.HSTS = getEnvBool("HSTS", false)
+	}
+	if val := os.Getenv("CORS_ALLOWED_ORIGINS"); val != "" {
+		c.CORS.AllowedOrigins = strings.Split(val, ",")
+	}
+	if val := os.Getenv("CORS_ALLOW_CREDENTIALS"); val != "" {
+		c.CORS.AllowCredentials = getEnvBool("CORS_ALLOW_CREDENTIALS", false)
+	}
+	if val := os.Getenv("CORS_MAX_AGE"); val != "" {
+		c.CORS.MaxAge = getEnvInt("CORS_MAX_AGE", 600)
+	}
+
+	// Access log
+	if val := os.Getenv("ACCESS_LOG_ENABLED"); val != "" {
+		c.AccessLog.Enabled = getEnvBool("ACCESS_LOG_ENABLED", false)
+	}
+	if val := os.Getenv("ACCESS_LOG_OUTPUT"); val != "" {
+		c.AccessLog.Output = val
+	}
+
+	// Encryption at rest
+	if val := os.Getenv("ENCRYPTION_KEY"); val != "" {
+		c.Encryption.Key = val
+	}
+	if val := os.Getenv("ENCRYPTION_KEY_FILE"); val != "" {
+		c.Encryption.KeyFile = val
+	}
+	if val := os.Getenv("ENCRYPTION_PREVIOUS_KEYS"); val != "" {
+		c.Encryption.PreviousKeys = strings.Split(val, ",")
+	}
+
+	// Media
+	if val := os.Getenv("MEDIA_TRANSCODE_AUDIO"); val != "" {
+		c.Media.TranscodeAudio = getEnvBool("MEDIA_TRANSCODE_AUDIO", false)
+	}
+	if val := os.Getenv("FFMPEG_PATH"); val != "" {
+		c.Media.FFmpegPath = val
+	}
+
+	// Billing
+	if val := os.Getenv("BILLING_NON_BILLABLE_TAGS"); val != "" {
+		c.Billing.NonBillableTags = strings.Split(val, ",")
+	}
+
+	// Plugins
+	if val := os.Getenv("PLUGINS_DIR"); val != "" {
+		c.Plugins.Dir = val
+	}
+	if val := os.Getenv("PLUGINS_TIMEOUT"); val != "" {
+		c.Plugins.Timeout = val
+	}
+}
+
+// parseWIPLimits parses a list such as "in-progress=5,open=20"; malformed pairs are skipped
+func parseWIPLimits(value string) map[string]int {
+	limits := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		status, limitStr, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			continue
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+		if err != nil {
+			continue
+		}
+		limits[strings.TrimSpace(status)] = limit
+	}
+	return limits
+}
+
+// ListenAddr returns the host:port the server binds to
+func (c *Config) ListenAddr() string {
+	return net.JoinHostPort(c.ListenAddress, c.Port)
+}
+
+// GetBasePath returns the base path with a leading slash and no trailing slash,
+// or an empty string when the app is served at the root
+func (c *Config) GetBasePath() string {
+	path := strings.Trim(strings.TrimSpace(c.BasePath), "/")
+	if path == "" {
+		return ""
+	}
+	return "/" + path
+}
+
+// GetEncryptionKey returns the configured encryption key, read from KeyFile
+// when one is set, or an empty string when encryption is off
+func (c *Config) GetEncryptionKey() (string, error) {
+	if c.Encryption.KeyFile == "" {
+		return strings.TrimSpace(c.Encryption.Key), nil
+	}
+	data, err := os.ReadFile(c.Encryption.KeyFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read encryption key file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
 }
 
 func (c *Config) DatabaseURL() string {
@@ -237,6 +752,15 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolVal, err := strconv.ParseBool(value); err == nil {
@@ -245,3 +769,63 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// GetSpamTimeout returns the rspamd request timeout, defaulting to 10 seconds
+func (c *Config) GetSpamTimeout() time.Duration {
+	duration, err := time.ParseDuration(c.Spam.Timeout)
+	if err != nil || duration <= 0 {
+		return 10 * time.Second
+	}
+	return duration
+}
+
+// GetAutomationInterval returns how often the automation rules run, defaulting to one hour
+func (c *Config) GetAutomationInterval() time.Duration {
+	duration, err := time.ParseDuration(c.Automation.Interval)
+	if err != nil || duration <= 0 {
+		return time.Hour
+	}
+	return duration
+}
+
+// GetTenancyMode returns how tenants are addressed, "path" or "subdomain", defaulting to path
+func (c *Config) GetTenancyMode() string {
+	if c.Tenancy.Mode == "subdomain" {
+		return "subdomain"
+	}
+	return "path"
+}
+
+// GetTenantDataDir returns where tenant databases and attachments are kept, defaulting to ./tenants
+func (c *Config) GetTenantDataDir() string {
+	if c.Tenancy.DataDir == "" {
+		return "./tenants"
+	}
+	return c.Tenancy.DataDir
+}
+
+// GetFFmpegPath returns the ffmpeg binary audio attachments are transcoded with, defaulting to ffmpeg on the PATH
+func (c *Config) GetFFmpegPath() string {
+	if c.Media.FFmpegPath == "" {
+		return "ffmpeg"
+	}
+	return c.Media.FFmpegPath
+}
+
+// GetPluginTimeout returns how long a plugin script may run, defaulting to 5 seconds
+func (c *Config) GetPluginTimeout() time.Duration {
+	duration, err := time.ParseDuration(c.Plugins.Timeout)
+	if err != nil || duration <= 0 {
+		return 5 * time.Second
+	}
+	return duration
+}
+
+// GetRetentionInterval returns how often the retention janitor runs, defaulting to one hour
+func (c *Config) GetRetentionInterval() time.Duration {
+	duration, err := time.ParseDuration(c.Retention.Interval)
+	if err != nil || duration <= 0 {
+		return time.Hour
+	}
+	return duration
+}
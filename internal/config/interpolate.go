@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// envReference matches ${NAME} references to environment variables, and $$,
+// which stands for a literal $
+var envReference = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// interpolateEnv replaces ${NAME} references in every string setting with the
+// value of the environment variable. A reference to an unset variable is an
+// error rather than an empty value, so a missing secret is caught at startup.
+func (c *Config) interpolateEnv() error {
+	return interpolateValue(reflect.ValueOf(c).Elem(), "")
+}
+
+func interpolateValue(v reflect.Value, key string) error {
+	switch v.Kind() {
+	case reflect.String:
+		expanded, err := expandEnv(v.String(), key)
+		if err != nil {
+			return err
+		}
+		v.SetString(expanded)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+			if !field.IsExported() || name == "" || name == "-" {
+				continue
+			}
+			if err := interpolateValue(v.Field(i), joinKey(key, name)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := interpolateValue(v.Index(i), fmt.Sprintf("%s[%d]", key, i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		// Map values are not addressable, so each is expanded in a copy
+		for _, mapKey := range v.MapKeys() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(v.MapIndex(mapKey))
+			if err := interpolateValue(value, joinKey(key, fmt.Sprint(mapKey.Interface()))); err != nil {
+				return err
+			}
+			v.SetMapIndex(mapKey, value)
+		}
+	}
+	return nil
+}
+
+// expandEnv expands the environment variable references in the value of key
+func expandEnv(value, key string) (string, error) {
+	var missing string
+	expanded := envReference.ReplaceAllStringFunc(value, func(ref string) string {
+		if ref == "$$" {
+			return "$"
+		}
+		name := ref[2 : len(ref)-1]
+		val, ok := os.LookupEnv(name)
+		if !ok && missing == "" {
+			missing = name
+		}
+		return val
+	})
+	if missing != "" {
+		return "", fmt.Errorf("%s references environment variable %s, which is not set", key, missing)
+	}
+	return expanded, nil
+}
+
+func joinKey(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// loadSecretFiles reads the secrets whose *_file setting names a file, such
+// as a Docker or Kubernetes secret mounted into the container. A file replaces
+// any value set inline; its trailing newline is dropped.
+func (c *Config) loadSecretFiles() error {
+	secrets := []struct {
+		key   string
+		file  string
+		value *string
+	}{
+		{"db_password_file", c.DBPasswordFile, &c.DBPassword},
+		{"db_url_file", c.DBURLFile, &c.DBURL},
+		{"email.imap_password_file", c.Email.IMAPPasswordFile, &c.Email.IMAPPassword},
+		{"email.smtp_password_file", c.Email.SMTPPasswordFile, &c.Email.SMTPPassword},
+		{"spam.rspamd_password_file", c.Spam.RspamdPasswordFile, &c.Spam.RspamdPassword},
+		{"alertmanager.secret_file", c.Alertmanager.SecretFile, &c.Alertmanager.Secret},
+	}
+	for _, secret := range secrets {
+		if err := readSecretFile(secret.key, secret.file, secret.value); err != nil {
+			return err
+		}
+	}
+
+	for name, channel := range c.Inbound {
+		if err := readSecretFile("inbound."+name+".secret_file", channel.SecretFile, &channel.Secret); err != nil {
+			return err
+		}
+		c.Inbound[name] = channel
+	}
+	for name, target := range c.Sync {
+		if err := readSecretFile("sync."+name+".token_file", target.TokenFile, &target.Token); err != nil {
+			return err
+		}
+		c.Sync[name] = target
+	}
+	return nil
+}
+
+func readSecretFile(key, path string, value *string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	*value = strings.TrimRight(string(data), "\r\n")
+	return nil
+}
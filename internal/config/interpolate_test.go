@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFromFileInterpolatesEnv(t *testing.T) {
+	t.Setenv("JATS_TEST_SMTP_HOST", "mail.example.com")
+	t.Setenv("JATS_TEST_GRAFANA_SECRET", "s3cret")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	content := `[email]
+smtp_host = "${JATS_TEST_SMTP_HOST}"
+smtp_from_name = "Costs $$5"
+
+[inbound.grafana]
+secret = "${JATS_TEST_GRAFANA_SECRET}"
+tags = ["${JATS_TEST_SMTP_HOST}"]
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	if cfg.Email.SMTPHost != "mail.example.com" {
+		t.Errorf("Expected the SMTP host from the environment, got %q", cfg.Email.SMTPHost)
+	}
+	if cfg.Email.FromName != "Costs $5" {
+		t.Errorf("Expected $$ to become $, got %q", cfg.Email.FromName)
+	}
+	if channel := cfg.Inbound["grafana"]; channel.Secret != "s3cret" || channel.Tags[0] != "mail.example.com" {
+		t.Errorf("Expected the inbound channel to be interpolated, got %+v", channel)
+	}
+
+	content = "db_password = \"${JATS_TEST_UNSET_VARIABLE}\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadFromFile(path); err == nil || !strings.Contains(err.Error(), "db_password references environment variable JATS_TEST_UNSET_VARIABLE") {
+		t.Errorf("Expected an unset variable to be reported, got %v", err)
+	}
+}
+
+func TestLoadFromFileReadsSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeSecret := func(name, value string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+			t.Fatalf("Failed to write secret: %v", err)
+		}
+		return path
+	}
+	smtpFile := writeSecret("smtp_password", "hunter2\n")
+	tokenFile := writeSecret("jira_token", "tok")
+	dbFile := writeSecret("db_password", "from-env-file")
+
+	path := filepath.Join(dir, "config.toml")
+	content := `[email]
+smtp_password = "inline"
+smtp_password_file = "` + smtpFile + `"
+
+[sync.jira]
+token_file = "` + tokenFile + `"
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	t.Setenv("DB_PASSWORD_FILE", dbFile)
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	if cfg.Email.SMTPPassword != "hunter2" {
+		t.Errorf("Expected the SMTP password from its file, got %q", cfg.Email.SMTPPassword)
+	}
+	if cfg.Sync["jira"].Token != "tok" {
+		t.Errorf("Expected the sync token from its file, got %q", cfg.Sync["jira"].Token)
+	}
+	if cfg.DBPassword != "from-env-file" {
+		t.Errorf("Expected the database password from DB_PASSWORD_FILE, got %q", cfg.DBPassword)
+	}
+
+	// A plain environment variable still wins over a file set in the config
+	t.Setenv("SMTP_PASSWORD", "from-env")
+	cfg, err = LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	if cfg.Email.SMTPPassword != "from-env" {
+		t.Errorf("Expected SMTP_PASSWORD to override the file, got %q", cfg.Email.SMTPPassword)
+	}
+
+	t.Setenv("DB_PASSWORD_FILE", filepath.Join(dir, "missing"))
+	if _, err := LoadFromFile(path); err == nil || !strings.Contains(err.Error(), "db_password_file") {
+		t.Errorf("Expected a missing secret file to be reported, got %v", err)
+	}
+}
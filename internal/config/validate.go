@@ -0,0 +1,93 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// UnknownKeys returns the keys of a TOML config file that match no setting,
+// such as a misspelled "smtp_hots", with the line they are on. Unknown keys
+// are otherwise ignored when the file is loaded.
+func UnknownKeys(configPath string) ([]string, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	decoder := toml.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(getDefaultConfig())
+
+	var strict *toml.StrictMissingError
+	if !errors.As(err, &strict) {
+		return nil, err
+	}
+	keys := make([]string, len(strict.Errors))
+	for i, keyErr := range strict.Errors {
+		line, _ := keyErr.Position()
+		keys[i] = fmt.Sprintf("%s (line %d)", strings.Join(keyErr.Key(), "."), line)
+	}
+	return keys, nil
+}
+
+// Validate returns the settings that are invalid. Most of them would
+// otherwise silently fall back to their defaults.
+func (c *Config) Validate() []string {
+	var problems []string
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		problems = append(problems, fmt.Sprintf("port %q is not a valid port number", c.Port))
+	}
+
+	durations := []struct {
+		key, value string
+	}{
+		{"spam.timeout", c.Spam.Timeout},
+		{"retention.interval", c.Retention.Interval},
+		{"automation.interval", c.Automation.Interval},
+		{"plugins.timeout", c.Plugins.Timeout},
+	}
+	// The poll interval also accepts a bare number of minutes
+	if _, err := strconv.Atoi(c.Email.PollInterval); err != nil {
+		durations = append(durations, struct{ key, value string }{"email.imap_poll_interval", c.Email.PollInterval})
+	}
+	for name, target := range c.Sync {
+		durations = append(durations, struct{ key, value string }{"sync." + name + ".interval", target.Interval})
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		if duration, err := time.ParseDuration(d.value); err != nil || duration <= 0 {
+			problems = append(problems, fmt.Sprintf("%s %q is not a valid duration, e.g. \"30s\", \"5m\" or \"1h\"", d.key, d.value))
+		}
+	}
+
+	if c.Email.StandupHour < 0 || c.Email.StandupHour > 23 {
+		problems = append(problems, fmt.Sprintf("email.standup_hour %d is not an hour of the day (0-23)", c.Email.StandupHour))
+	}
+	if c.Email.IMAPHost != "" && (c.Email.IMAPUsername == "" || c.Email.IMAPPassword == "") {
+		problems = append(problems, "email.imap_host is set but imap_username or imap_password is not, so the mailbox is not polled")
+	}
+	if c.Email.SMTPHost != "" && c.Email.FromEmail == "" {
+		problems = append(problems, "email.smtp_host is set but smtp_from_email is not, so no email is sent")
+	}
+
+	if c.Tenancy.Enabled {
+		if c.Tenancy.Mode != "" && c.Tenancy.Mode != "path" && c.Tenancy.Mode != "subdomain" {
+			problems = append(problems, fmt.Sprintf("tenancy.mode %q must be \"path\" or \"subdomain\"", c.Tenancy.Mode))
+		}
+		if c.Tenancy.Mode == "subdomain" && c.Tenancy.Domain == "" {
+			problems = append(problems, "tenancy.domain is required in subdomain mode")
+		}
+	}
+
+	return problems
+}
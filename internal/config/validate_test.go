@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `port = "8080"
+colour = "blue"
+
+[email]
+smtp_host = "mail.example.com"
+smtp_hots = "typo.example.com"
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	keys, err := UnknownKeys(path)
+	if err != nil {
+		t.Fatalf("UnknownKeys failed: %v", err)
+	}
+	if strings.Join(keys, ", ") != "colour (line 2), email.smtp_hots (line 6)" {
+		t.Errorf("Unexpected unknown keys: %v", keys)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := getDefaultConfig()
+	if problems := cfg.Validate(); len(problems) != 0 {
+		t.Errorf("Expected the defaults to be valid, got %v", problems)
+	}
+
+	cfg.Port = "http"
+	cfg.Retention.Interval = "1 hour"
+	cfg.Email.PollInterval = "10" // bare minutes are accepted
+	cfg.Email.StandupHour = 24
+	cfg.Sync = map[string]SyncTargetConfig{"jira": {Interval: "-5m"}}
+	cfg.Tenancy = TenancyConfig{Enabled: true, Mode: "subdomain"}
+
+	problems := strings.Join(cfg.Validate(), "\n")
+	for _, want := range []string{"port", "retention.interval", "email.standup_hour", "sync.jira.interval", "tenancy.domain"} {
+		if !strings.Contains(problems, want) {
+			t.Errorf("Expected a problem with %s, got:\n%s", want, problems)
+		}
+	}
+	if strings.Contains(problems, "imap_poll_interval") {
+		t.Errorf("Expected a bare number of minutes to be accepted, got:\n%s", problems)
+	}
+}
@@ -0,0 +1,175 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// activityPageSize is how many events the activity page shows at a time
+const activityPageSize = 50
+
+var activityLabels = map[services.ActivityType]string{
+	services.ActivityTaskCreated:   "Created",
+	services.ActivityStatusChanged: "Status changed",
+	services.ActivityCommented:     "Note added",
+	services.ActivityTimeLogged:    "Time logged",
+	services.ActivityAssigned:      "Assigned",
+	services.ActivityReviewed:      "Reviewed",
+}
+
+var activityBadgeClasses = map[services.ActivityType]string{
+	services.ActivityTaskCreated:   "bg-green-100 text-green-800",
+	services.ActivityStatusChanged: "bg-blue-100 text-blue-800",
+	services.ActivityCommented:     "bg-yellow-100 text-yellow-800",
+	services.ActivityTimeLogged:    "bg-purple-100 text-purple-800",
+	services.ActivityAssigned:      "bg-indigo-100 text-indigo-800",
+	services.ActivityReviewed:      "bg-teal-100 text-teal-800",
+}
+
+// ActivityHandler handles the global activity feed page
+type ActivityHandler struct {
+	taskService *services.TaskService
+	templates   map[string]*template.Template
+}
+
+// NewActivityHandler creates a new activity handler
+func NewActivityHandler(taskService *services.TaskService, templates map[string]*template.Template) *ActivityHandler {
+	return &ActivityHandler{
+		taskService: taskService,
+		templates:   templates,
+	}
+}
+
+// ActivityPageHandler renders the activity feed with its filters
+func (h *ActivityHandler) ActivityPageHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	sinceStr := strings.TrimSpace(c.Query("since"))
+	activityType := services.ActivityType(c.Query("type"))
+	tag := strings.TrimSpace(c.Query("tag"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	since, until, err := services.ParseActivityRange(sinceStr, "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := services.ActivityFilter{Since: since, Until: until, Tag: tag}
+	if _, ok := activityLabels[activityType]; ok {
+		filter.Types = []services.ActivityType{activityType}
+	}
+
+	events, err := h.taskService.GetActivity(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get activity"})
+		return
+	}
+
+	typeOptions := `<option value="">All activity</option>`
+	for _, t := range services.ActivityTypes {
+		selected := ""
+		if t == activityType {
+			selected = " selected"
+		}
+		typeOptions += fmt.Sprintf(`<option value="%s"%s>%s</option>`, t, selected, activityLabels[t])
+	}
+
+	pageHTML := fmt.Sprintf(`
+	<div class="p-6">
+		<h2 class="text-2xl font-bold text-gray-900 mb-6">Activity</h2>
+		<form hx-get="/app/activity" hx-target="#main-content" hx-trigger="change, submit" class="mb-6 flex flex-wrap gap-4">
+			<input type="text" name="since" value="%s" placeholder="Since (default: last 7 days)" class="rounded-md border-gray-300 text-sm">
+			<select name="type" class="rounded-md border-gray-300 text-sm">%s</select>
+			<input type="text" name="tag" value="%s" placeholder="Tag" class="rounded-md border-gray-300 text-sm">
+		</form>`, html.EscapeString(sinceStr), typeOptions, html.EscapeString(tag))
+
+	if len(events) == 0 {
+		pageHTML += `
+		<p class="text-sm text-gray-500">No activity in this period.</p>
+	</div>`
+		c.Header("Content-Type", "text/html")
+		c.String(http.StatusOK, pageHTML)
+		return
+	}
+
+	total := len(events)
+	if offset >= total {
+		offset = 0
+	}
+	end := offset + activityPageSize
+	if end > total {
+		end = total
+	}
+
+	pageHTML += `
+		<div class="bg-white shadow rounded-lg divide-y divide-gray-200">`
+
+	for _, event := range events[offset:end] {
+		detail := event.Detail
+		if event.Type == services.ActivityTimeLogged {
+			detail = strings.TrimSpace(formatMinutes(event.Minutes) + " " + event.Detail)
+		}
+		pageHTML += fmt.Sprintf(`
+			<div class="px-4 py-3 flex items-start justify-between hover:bg-gray-50 cursor-pointer" onclick="showTaskDetail(%d)">
+				<div class="min-w-0">
+					<p class="text-sm text-gray-900">
+						<span class="inline-flex items-center px-2 py-0.5 rounded-full text-xs font-medium %s mr-2">%s</span>
+						#%d %s
+					</p>
+					<p class="mt-1 text-xs text-gray-500 truncate">%s</p>
+				</div>
+				<span class="ml-4 text-xs text-gray-400 whitespace-nowrap">%s</span>
+			</div>`,
+			event.TaskID, activityBadgeClasses[event.Type], activityLabels[event.Type],
+			event.TaskID, html.EscapeString(event.TaskName), html.EscapeString(detail),
+			event.Timestamp.Format("Jan 2, 3:04 PM"))
+	}
+
+	pageHTML += `
+		</div>`
+
+	// Pagination controls keep the current filters
+	pageLink := func(label string, pageOffset int) string {
+		query := url.Values{}
+		query.Set("since", sinceStr)
+		query.Set("type", string(activityType))
+		query.Set("tag", tag)
+		query.Set("offset", strconv.Itoa(pageOffset))
+		return fmt.Sprintf(`<button hx-get="/app/activity?%s" hx-target="#main-content" class="text-sm text-blue-600 hover:text-blue-800">%s</button>`,
+			html.EscapeString(query.Encode()), label)
+	}
+
+	pageHTML += fmt.Sprintf(`
+		<div class="mt-4 flex items-center justify-between">
+			<span class="text-xs text-gray-500">Showing %d–%d of %d</span>
+			<div class="space-x-4">`, offset+1, end, total)
+	if offset > 0 {
+		pageHTML += pageLink("&larr; Newer", max(offset-activityPageSize, 0))
+	}
+	if end < total {
+		pageHTML += pageLink("Older &rarr;", end)
+	}
+	pageHTML += `
+			</div>
+		</div>
+	</div>`
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, pageHTML)
+}
@@ -0,0 +1,412 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// AdminHandler handles admin settings frontend requests
+type AdminHandler struct {
+	settingsService  *services.SettingsService
+	taskService      *services.TaskService
+	spamService      *services.SpamService
+	retentionService *services.RetentionService
+	templates        map[string]*template.Template
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(settingsService *services.SettingsService, taskService *services.TaskService, spamService *services.SpamService, retentionService *services.RetentionService, templates map[string]*template.Template) *AdminHandler {
+	return &AdminHandler{
+		settingsService:  settingsService,
+		taskService:      taskService,
+		spamService:      spamService,
+		retentionService: retentionService,
+		templates:        templates,
+	}
+}
+
+// requireAdmin checks that the current user is an administrator
+func (h *AdminHandler) requireAdmin(c *gin.Context) bool {
+	authContext, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return false
+	}
+
+	auth, ok := authContext.(*models.AuthContext)
+	if !ok || !auth.HasPermission(models.PermissionAdmin) {
+		c.Header("Content-Type", "text/html")
+		c.String(http.StatusForbidden, `<div class="p-6 text-sm text-red-600">Admin permissions required</div>`)
+		return false
+	}
+
+	return true
+}
+
+// AdminPageHandler renders the admin settings page
+func (h *AdminHandler) AdminPageHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderPage(h.settingsService.GetBranding(), ""))
+}
+
+// UpdateBrandingHandler handles branding form submission
+func (h *AdminHandler) UpdateBrandingHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	settings := &models.BrandingSettings{
+		InstanceName: c.PostForm("instance_name"),
+		Tagline:      c.PostForm("tagline"),
+		LogoURL:      c.PostForm("logo_url"),
+		PrimaryColor: c.PostForm("primary_color"),
+		FooterText:   c.PostForm("footer_text"),
+	}
+
+	updated, err := h.settingsService.UpdateBranding(settings)
+	if err != nil {
+		c.Header("Content-Type", "text/html")
+		c.String(http.StatusOK, h.renderPage(settings, err.Error()))
+		return
+	}
+
+	// Reload so the new branding applies to the whole layout
+	c.Header("HX-Refresh", "true")
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderPage(updated, ""))
+}
+
+// CreateCannedResponseHandler handles the canned response form submission
+func (h *AdminHandler) CreateCannedResponseHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	errorMessage := ""
+	_, err := h.settingsService.SaveCannedResponse(&models.CannedResponse{
+		Name:    c.PostForm("name"),
+		Content: c.PostForm("content"),
+	})
+	if err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderCannedResponses(errorMessage))
+}
+
+// DeleteCannedResponseHandler deletes a canned response
+func (h *AdminHandler) DeleteCannedResponseHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid canned response ID"})
+		return
+	}
+
+	errorMessage := ""
+	if err := h.settingsService.DeleteCannedResponse(uint(id)); err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderCannedResponses(errorMessage))
+}
+
+// ReleaseQuarantinedHandler releases a quarantined email into the task stream
+func (h *AdminHandler) ReleaseQuarantinedHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quarantined email ID"})
+		return
+	}
+
+	errorMessage := ""
+	if err := h.spamService.ReleaseQuarantined(uint(id)); err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderQuarantine(errorMessage))
+}
+
+// DeleteQuarantinedHandler permanently discards a quarantined email
+func (h *AdminHandler) DeleteQuarantinedHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quarantined email ID"})
+		return
+	}
+
+	errorMessage := ""
+	if err := h.spamService.DeleteQuarantined(uint(id)); err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderQuarantine(errorMessage))
+}
+
+// RunRetentionHandler runs the retention janitor immediately
+func (h *AdminHandler) RunRetentionHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	// Failures are listed in the rendered run result
+	h.retentionService.Run(time.Now())
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderRetention())
+}
+
+// renderPage renders all admin sections
+func (h *AdminHandler) renderPage(branding *models.BrandingSettings, brandingError string) string {
+	return h.renderBrandingForm(branding, brandingError) + h.renderCannedResponses("") + h.renderAutomationRules("") + h.renderQueryWebhooks("") + h.renderQuarantine("") + h.renderRetention()
+}
+
+// renderBrandingForm renders the branding settings form
+func (h *AdminHandler) renderBrandingForm(settings *models.BrandingSettings, errorMessage string) string {
+	errorHTML := ""
+	if errorMessage != "" {
+		errorHTML = fmt.Sprintf(`<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">%s</div>`, html.EscapeString(errorMessage))
+	}
+
+	inputClass := "mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md text-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500"
+
+	return fmt.Sprintf(`
+	<div class="p-6 max-w-2xl">
+		<h2 class="text-xl font-semibold text-gray-900 mb-1">Admin</h2>
+		<p class="text-sm text-gray-500 mb-6">Instance branding applies to the web interface, login page, and outgoing emails.</p>
+		%s
+		<form hx-post="/app/admin/branding" hx-target="#main-content" class="space-y-4 bg-white shadow rounded-lg p-6">
+			<div>
+				<label for="instance_name" class="block text-sm font-medium text-gray-700">Instance name</label>
+				<input id="instance_name" name="instance_name" type="text" required value="%s" class="%s">
+			</div>
+			<div>
+				<label for="tagline" class="block text-sm font-medium text-gray-700">Tagline</label>
+				<input id="tagline" name="tagline" type="text" value="%s" class="%s">
+			</div>
+			<div>
+				<label for="logo_url" class="block text-sm font-medium text-gray-700">Logo URL</label>
+				<input id="logo_url" name="logo_url" type="text" placeholder="https://example.com/logo.png" value="%s" class="%s">
+			</div>
+			<div>
+				<label for="primary_color" class="block text-sm font-medium text-gray-700">Primary color</label>
+				<div class="flex items-center gap-2">
+					<input id="primary_color" name="primary_color" type="color" value="%s" class="mt-1 h-9 w-16 border border-gray-300 rounded-md">
+					<span class="text-xs text-gray-500">Used for buttons, active navigation, and email accents</span>
+				</div>
+			</div>
+			<div>
+				<label for="footer_text" class="block text-sm font-medium text-gray-700">Footer text</label>
+				<textarea id="footer_text" name="footer_text" rows="2" class="%s">%s</textarea>
+			</div>
+			<div class="flex justify-end">
+				<button type="submit" class="brand-button px-4 py-2 text-sm font-medium text-white rounded-md">Save branding</button>
+			</div>
+		</form>
+	</div>`,
+		errorHTML,
+		html.EscapeString(settings.InstanceName), inputClass,
+		html.EscapeString(settings.Tagline), inputClass,
+		html.EscapeString(settings.LogoURL), inputClass,
+		html.EscapeString(settings.PrimaryColor),
+		inputClass, html.EscapeString(settings.FooterText),
+	)
+}
+
+// renderCannedResponses renders the canned response list and creation form
+func (h *AdminHandler) renderCannedResponses(errorMessage string) string {
+	errorHTML := ""
+	if errorMessage != "" {
+		errorHTML = fmt.Sprintf(`<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">%s</div>`, html.EscapeString(errorMessage))
+	}
+
+	responses, err := h.settingsService.ListCannedResponses()
+	if err != nil {
+		errorHTML += `<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">Failed to load canned responses</div>`
+	}
+
+	listHTML := ""
+	for _, response := range responses {
+		listHTML += fmt.Sprintf(`
+				<li class="py-3 flex items-start justify-between gap-4">
+					<div class="min-w-0">
+						<p class="text-sm font-medium text-gray-900">%s</p>
+						<p class="mt-1 text-sm text-gray-600 whitespace-pre-wrap">%s</p>
+					</div>
+					<button hx-delete="/app/admin/canned-responses/%d" hx-target="#canned-responses" hx-swap="outerHTML"
+							hx-confirm="Delete this canned response?"
+							class="text-sm text-red-600 hover:text-red-800">Delete</button>
+				</li>`, html.EscapeString(response.Name), html.EscapeString(response.Content), response.ID)
+	}
+	if listHTML == "" {
+		listHTML = `
+				<li class="py-3 text-sm text-gray-500">No canned responses yet.</li>`
+	}
+
+	inputClass := "mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md text-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500"
+
+	return fmt.Sprintf(`
+	<div id="canned-responses" class="p-6 pt-0 max-w-2xl">
+		<h3 class="text-lg font-semibold text-gray-900 mb-1">Canned responses</h3>
+		<p class="text-sm text-gray-500 mb-4">Reusable replies for task notes. Use {{task.id}}, {{task.name}}, {{task.status}}, {{task.priority}}, and {{instance.name}} as placeholders.</p>
+		%s
+		<div class="bg-white shadow rounded-lg p-6 space-y-4">
+			<ul class="divide-y divide-gray-200">%s
+			</ul>
+			<form hx-post="/app/admin/canned-responses" hx-target="#canned-responses" hx-swap="outerHTML" class="space-y-4 border-t border-gray-200 pt-4">
+				<div>
+					<label for="canned_name" class="block text-sm font-medium text-gray-700">Name</label>
+					<input id="canned_name" name="name" type="text" required placeholder="thanks" class="%s">
+				</div>
+				<div>
+					<label for="canned_content" class="block text-sm font-medium text-gray-700">Content</label>
+					<textarea id="canned_content" name="content" rows="3" required placeholder="Thanks for reporting this, we are tracking it as task #{{task.id}}." class="%s"></textarea>
+				</div>
+				<div class="flex justify-end">
+					<button type="submit" class="brand-button px-4 py-2 text-sm font-medium text-white rounded-md">Add canned response</button>
+				</div>
+			</form>
+		</div>
+	</div>`,
+		errorHTML, listHTML, inputClass, inputClass,
+	)
+}
+
+// renderQuarantine renders the list of emails held back by the spam filter
+func (h *AdminHandler) renderQuarantine(errorMessage string) string {
+	errorHTML := ""
+	if errorMessage != "" {
+		errorHTML = fmt.Sprintf(`<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">%s</div>`, html.EscapeString(errorMessage))
+	}
+
+	statusText := "Spam filtering is disabled. Set rspamd_url in the [spam] config section to enable it."
+	if h.spamService.Enabled() {
+		statusText = "Inbound email scoring above the quarantine threshold is held here instead of creating tasks."
+	}
+
+	emails, err := h.spamService.ListQuarantined()
+	if err != nil {
+		errorHTML += `<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">Failed to load quarantined emails</div>`
+	}
+
+	listHTML := ""
+	for _, email := range emails {
+		sender := email.From
+		if email.FromName != "" {
+			sender = email.FromName + " <" + email.From + ">"
+		}
+		listHTML += fmt.Sprintf(`
+				<li class="py-3 flex items-start justify-between gap-4">
+					<div class="min-w-0">
+						<p class="text-sm font-medium text-gray-900 truncate">%s</p>
+						<p class="text-xs text-gray-500">%s &middot; %s</p>
+						<p class="mt-1 text-xs text-red-700" title="%s">Score %.1f</p>
+					</div>
+					<div class="flex gap-3 flex-shrink-0">
+						<button hx-post="/app/admin/quarantine/%d/release" hx-target="#quarantine" hx-swap="outerHTML"
+								class="text-sm text-blue-600 hover:text-blue-800">Release</button>
+						<button hx-delete="/app/admin/quarantine/%d" hx-target="#quarantine" hx-swap="outerHTML"
+								hx-confirm="Delete this email permanently?"
+								class="text-sm text-red-600 hover:text-red-800">Delete</button>
+					</div>
+				</li>`,
+			html.EscapeString(email.Subject), html.EscapeString(sender), email.ReceivedAt.Format("Jan 2, 2006 15:04"),
+			html.EscapeString(strings.Join(email.Symbols, ", ")), email.Score, email.ID, email.ID)
+	}
+	if listHTML == "" {
+		listHTML = `
+				<li class="py-3 text-sm text-gray-500">No quarantined emails.</li>`
+	}
+
+	return fmt.Sprintf(`
+	<div id="quarantine" class="p-6 pt-0 max-w-2xl">
+		<h3 class="text-lg font-semibold text-gray-900 mb-1">Spam quarantine</h3>
+		<p class="text-sm text-gray-500 mb-4">%s</p>
+		%s
+		<div class="bg-white shadow rounded-lg px-6 py-2">
+			<ul class="divide-y divide-gray-200">%s
+			</ul>
+		</div>
+	</div>`,
+		statusText, errorHTML, listHTML,
+	)
+}
+
+// renderRetention renders the retention policy and the result of the last janitor run
+func (h *AdminHandler) renderRetention() string {
+	policy := h.retentionService.Policy()
+	keep := func(days int, forever string) string {
+		if days == 0 {
+			return forever
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+
+	lastRunHTML := `<p class="text-sm text-gray-500">The janitor has not run since the server started.</p>`
+	if run := h.retentionService.LastRun(); run != nil {
+		errorsHTML := ""
+		for _, message := range run.Errors {
+			errorsHTML += fmt.Sprintf(`
+				<p class="mt-2 rounded-md bg-red-50 p-2 text-xs text-red-700">%s</p>`, html.EscapeString(message))
+		}
+		lastRunHTML = fmt.Sprintf(`
+			<p class="text-sm text-gray-700">Last run %s (%s)</p>
+			<p class="mt-1 text-xs text-gray-500">%d login attempts and %d sessions deleted, %d status changes deleted, %d tasks archived</p>%s`,
+			run.StartedAt.Format("Jan 2, 2006 15:04"), run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond),
+			run.LoginAttemptsDeleted, run.SessionsDeleted, run.StatusChangesDeleted, run.TasksArchived, errorsHTML)
+	}
+
+	return fmt.Sprintf(`
+	<div id="retention" class="p-6 pt-0 max-w-2xl">
+		<h3 class="text-lg font-semibold text-gray-900 mb-1">Data retention</h3>
+		<p class="text-sm text-gray-500 mb-4">Set in the [retention] config section and enforced by the background janitor.</p>
+		<div class="bg-white shadow rounded-lg p-6 space-y-4">
+			<dl class="grid grid-cols-2 gap-x-4 gap-y-2 text-sm">
+				<dt class="text-gray-500">Login attempts</dt><dd class="text-gray-900">%d hours</dd>
+				<dt class="text-gray-500">Expired sessions</dt><dd class="text-gray-900">%s</dd>
+				<dt class="text-gray-500">Status history</dt><dd class="text-gray-900">%s</dd>
+				<dt class="text-gray-500">Archive resolved tasks after</dt><dd class="text-gray-900">%s</dd>
+			</dl>
+			<div class="border-t border-gray-200 pt-4 flex items-start justify-between gap-4">
+				<div>%s
+				</div>
+				<button hx-post="/app/admin/retention/run" hx-target="#retention" hx-swap="outerHTML"
+						class="flex-shrink-0 text-sm text-blue-600 hover:text-blue-800">Run now</button>
+			</div>
+		</div>
+	</div>`,
+		policy.LoginAttemptsHours,
+		keep(policy.SessionDays, "Removed on expiry"),
+		keep(policy.ActivityDays, "Kept forever"),
+		keep(policy.ArchiveResolvedDays, "Never"),
+		lastRunHTML,
+	)
+}
@@ -0,0 +1,272 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// ruleTriggers are the automation rule triggers offered in the admin form
+var ruleTriggers = []struct {
+	Value string
+	Label string
+}{
+	{models.RuleTriggerCreated, "Task created"},
+	{models.RuleTriggerTagAdded, "Tag added"},
+	{models.RuleTriggerStatusChanged, "Status changed"},
+	{models.RuleTriggerEmailReceived, "Email received"},
+}
+
+// CreateAutomationRuleHandler handles the automation rule form submission
+func (h *AdminHandler) CreateAutomationRuleHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	errorMessage := ""
+	_, err := h.settingsService.SaveAutomationRule(&models.AutomationRule{
+		Name:        c.PostForm("name"),
+		Enabled:     true,
+		Trigger:     c.PostForm("trigger"),
+		Tag:         c.PostForm("tag"),
+		Priority:    models.TaskPriority(c.PostForm("priority")),
+		Status:      models.TaskStatus(c.PostForm("status")),
+		Sender:      c.PostForm("sender"),
+		SetAssignee: c.PostForm("set_assignee"),
+		AddTag:      c.PostForm("add_tag"),
+		Notify:      c.PostForm("notify") == "true",
+		WebhookURL:  c.PostForm("webhook_url"),
+	})
+	if err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderAutomationRules(errorMessage))
+}
+
+// ToggleAutomationRuleHandler enables or disables an automation rule
+func (h *AdminHandler) ToggleAutomationRuleHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid automation rule ID"})
+		return
+	}
+
+	errorMessage := ""
+	rule, err := h.settingsService.GetAutomationRule(uint(id))
+	if err == nil {
+		rule.Enabled = !rule.Enabled
+		_, err = h.settingsService.SaveAutomationRule(rule)
+	}
+	if err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderAutomationRules(errorMessage))
+}
+
+// DeleteAutomationRuleHandler deletes an automation rule
+func (h *AdminHandler) DeleteAutomationRuleHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid automation rule ID"})
+		return
+	}
+
+	errorMessage := ""
+	if err := h.settingsService.DeleteAutomationRule(uint(id)); err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderAutomationRules(errorMessage))
+}
+
+// describeRule summarizes a rule's conditions and actions for the admin list
+func describeRule(rule *models.AutomationRule) string {
+	trigger := rule.Trigger
+	for _, t := range ruleTriggers {
+		if t.Value == rule.Trigger {
+			trigger = t.Label
+		}
+	}
+
+	var conditions []string
+	if rule.Tag != "" {
+		conditions = append(conditions, "tag "+rule.Tag)
+	}
+	if rule.Priority != "" {
+		conditions = append(conditions, "priority "+string(rule.Priority))
+	}
+	if rule.Status != "" {
+		conditions = append(conditions, "status "+string(rule.Status))
+	}
+	if rule.Sender != "" {
+		conditions = append(conditions, "sender contains "+rule.Sender)
+	}
+
+	var actions []string
+	if rule.SetAssignee != "" {
+		actions = append(actions, "assign to "+rule.SetAssignee)
+	}
+	if rule.AddTag != "" {
+		actions = append(actions, "add tag "+rule.AddTag)
+	}
+	if rule.Notify {
+		actions = append(actions, "notify users")
+	}
+	if rule.WebhookURL != "" {
+		actions = append(actions, "call "+rule.WebhookURL)
+	}
+
+	description := trigger
+	if len(conditions) > 0 {
+		description += " with " + strings.Join(conditions, ", ")
+	}
+	return description + ": " + strings.Join(actions, ", ")
+}
+
+// renderAutomationRules renders the automation rule list and creation form
+func (h *AdminHandler) renderAutomationRules(errorMessage string) string {
+	errorHTML := ""
+	if errorMessage != "" {
+		errorHTML = fmt.Sprintf(`<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">%s</div>`, html.EscapeString(errorMessage))
+	}
+
+	rules, err := h.settingsService.ListAutomationRules()
+	if err != nil {
+		errorHTML += `<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">Failed to load automation rules</div>`
+	}
+
+	listHTML := ""
+	for _, rule := range rules {
+		toggleLabel, nameClass := "Disable", "text-gray-900"
+		if !rule.Enabled {
+			toggleLabel, nameClass = "Enable", "text-gray-400 line-through"
+		}
+		listHTML += fmt.Sprintf(`
+				<li class="py-3 flex items-start justify-between gap-4">
+					<div class="min-w-0">
+						<p class="text-sm font-medium %s">%s</p>
+						<p class="mt-1 text-sm text-gray-600 break-words">%s</p>
+					</div>
+					<div class="flex gap-3">
+						<button hx-post="/app/admin/rules/%d/toggle" hx-target="#automation-rules" hx-swap="outerHTML"
+								class="text-sm text-blue-600 hover:text-blue-800">%s</button>
+						<button hx-delete="/app/admin/rules/%d" hx-target="#automation-rules" hx-swap="outerHTML"
+								hx-confirm="Delete this rule?"
+								class="text-sm text-red-600 hover:text-red-800">Delete</button>
+					</div>
+				</li>`, nameClass, html.EscapeString(rule.Name), html.EscapeString(describeRule(rule)), rule.ID, toggleLabel, rule.ID)
+	}
+	if listHTML == "" {
+		listHTML = `
+				<li class="py-3 text-sm text-gray-500">No automation rules yet.</li>`
+	}
+
+	inputClass := "mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md text-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500"
+
+	triggerOptions := ""
+	for _, t := range ruleTriggers {
+		triggerOptions += fmt.Sprintf(`
+						<option value="%s">%s</option>`, t.Value, t.Label)
+	}
+	statusOptions := `
+						<option value="">Any status</option>`
+	for _, status := range services.KanbanStatuses {
+		statusOptions += fmt.Sprintf(`
+						<option value="%s">%s</option>`, status, status)
+	}
+
+	return fmt.Sprintf(`
+	<div id="automation-rules" class="p-6 pt-0 max-w-2xl">
+		<h3 class="text-lg font-semibold text-gray-900 mb-1">Automation rules</h3>
+		<p class="text-sm text-gray-500 mb-4">Rules run in order when their trigger fires on a task that meets every condition. Changes made by rules do not trigger other rules.</p>
+		%s
+		<div class="bg-white shadow rounded-lg p-6 space-y-4">
+			<ul class="divide-y divide-gray-200">%s
+			</ul>
+			<form hx-post="/app/admin/rules" hx-target="#automation-rules" hx-swap="outerHTML" class="space-y-4 border-t border-gray-200 pt-4">
+				<div class="grid grid-cols-2 gap-4">
+					<div>
+						<label for="rule_name" class="block text-sm font-medium text-gray-700">Name</label>
+						<input id="rule_name" name="name" type="text" required placeholder="Route billing emails" class="%s">
+					</div>
+					<div>
+						<label for="rule_trigger" class="block text-sm font-medium text-gray-700">When</label>
+						<select id="rule_trigger" name="trigger" class="%s">%s
+						</select>
+					</div>
+				</div>
+				<fieldset class="grid grid-cols-2 gap-4">
+					<legend class="text-sm font-medium text-gray-700 mb-1">Conditions</legend>
+					<div>
+						<label for="rule_tag" class="block text-xs text-gray-600">Tag</label>
+						<input id="rule_tag" name="tag" type="text" class="%s">
+					</div>
+					<div>
+						<label for="rule_priority" class="block text-xs text-gray-600">Priority</label>
+						<select id="rule_priority" name="priority" class="%s">
+							<option value="">Any priority</option>
+							<option value="low">low</option>
+							<option value="medium">medium</option>
+							<option value="high">high</option>
+						</select>
+					</div>
+					<div>
+						<label for="rule_status" class="block text-xs text-gray-600">Status</label>
+						<select id="rule_status" name="status" class="%s">%s
+						</select>
+					</div>
+					<div>
+						<label for="rule_sender" class="block text-xs text-gray-600">Sender contains (email received)</label>
+						<input id="rule_sender" name="sender" type="text" placeholder="@example.com" class="%s">
+					</div>
+				</fieldset>
+				<fieldset class="grid grid-cols-2 gap-4">
+					<legend class="text-sm font-medium text-gray-700 mb-1">Actions</legend>
+					<div>
+						<label for="rule_assignee" class="block text-xs text-gray-600">Assign to</label>
+						<input id="rule_assignee" name="set_assignee" type="text" placeholder="username" class="%s">
+					</div>
+					<div>
+						<label for="rule_add_tag" class="block text-xs text-gray-600">Add tag</label>
+						<input id="rule_add_tag" name="add_tag" type="text" class="%s">
+					</div>
+					<div>
+						<label for="rule_webhook" class="block text-xs text-gray-600">Call webhook</label>
+						<input id="rule_webhook" name="webhook_url" type="url" placeholder="https://example.com/hook" class="%s">
+					</div>
+					<label class="flex items-center gap-2 text-sm text-gray-700 pt-5">
+						<input type="checkbox" name="notify" value="true" class="rounded border-gray-300">
+						Send a notification
+					</label>
+				</fieldset>
+				<div class="flex justify-end">
+					<button type="submit" class="brand-button px-4 py-2 text-sm font-medium text-white rounded-md">Add rule</button>
+				</div>
+			</form>
+		</div>
+	</div>`,
+		errorHTML, listHTML,
+		inputClass, inputClass, triggerOptions,
+		inputClass, inputClass, inputClass, statusOptions, inputClass,
+		inputClass, inputClass, inputClass,
+	)
+}
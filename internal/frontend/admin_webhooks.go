@@ -0,0 +1,192 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// CreateQueryWebhookHandler handles the saved query webhook form submission
+func (h *AdminHandler) CreateQueryWebhookHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	errorMessage := ""
+	queryID, err := strconv.ParseUint(c.PostForm("saved_query_id"), 10, 32)
+	if err != nil {
+		errorMessage = "Choose a saved query"
+	} else if _, err := h.taskService.SaveSavedQueryWebhook(&models.SavedQueryWebhook{
+		SavedQueryID: uint(queryID),
+		URL:          c.PostForm("url"),
+		Cron:         c.PostForm("cron"),
+		Secret:       c.PostForm("secret"),
+		Enabled:      true,
+	}, time.Now()); err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderQueryWebhooks(errorMessage))
+}
+
+// ToggleQueryWebhookHandler enables or disables a saved query webhook
+func (h *AdminHandler) ToggleQueryWebhookHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	errorMessage := ""
+	webhook, err := h.taskService.GetSavedQueryWebhook(uint(id))
+	if err == nil {
+		webhook.Enabled = !webhook.Enabled
+		_, err = h.taskService.SaveSavedQueryWebhook(webhook, time.Now())
+	}
+	if err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderQueryWebhooks(errorMessage))
+}
+
+// DeleteQueryWebhookHandler deletes a saved query webhook
+func (h *AdminHandler) DeleteQueryWebhookHandler(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	errorMessage := ""
+	if err := h.taskService.DeleteSavedQueryWebhook(uint(id)); err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderQueryWebhooks(errorMessage))
+}
+
+// describeQueryWebhook summarizes a webhook's schedule and last run for the admin list
+func describeQueryWebhook(webhook *models.SavedQueryWebhook) string {
+	description := "Posts to " + webhook.URL + " on " + webhook.Cron
+	if webhook.Secret != "" {
+		description += ", signed"
+	}
+	if webhook.LastRunAt != nil {
+		description += ". Last run " + webhook.LastRunAt.Format("Jan 2 15:04")
+		if webhook.LastError != "" {
+			description += " failed: " + webhook.LastError
+		}
+	}
+	if webhook.Enabled && webhook.NextRunAt != nil {
+		description += ". Next run " + webhook.NextRunAt.Format("Jan 2 15:04")
+	}
+	return description
+}
+
+// renderQueryWebhooks renders the saved query webhook list and creation form
+func (h *AdminHandler) renderQueryWebhooks(errorMessage string) string {
+	errorHTML := ""
+	if errorMessage != "" {
+		errorHTML = fmt.Sprintf(`<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">%s</div>`, html.EscapeString(errorMessage))
+	}
+
+	queries, err := h.taskService.GetSavedQueries()
+	if err != nil {
+		errorHTML += `<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">Failed to load saved queries</div>`
+	}
+	queryNames := make(map[uint]string)
+	queryOptions := ""
+	for _, query := range queries {
+		queryNames[query.ID] = query.Name
+		queryOptions += fmt.Sprintf(`
+						<option value="%d">%s</option>`, query.ID, html.EscapeString(query.Name))
+	}
+
+	webhooks, err := h.taskService.ListSavedQueryWebhooks()
+	if err != nil {
+		errorHTML += `<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">Failed to load saved query webhooks</div>`
+	}
+
+	listHTML := ""
+	for _, webhook := range webhooks {
+		toggleLabel, nameClass := "Disable", "text-gray-900"
+		if !webhook.Enabled {
+			toggleLabel, nameClass = "Enable", "text-gray-400 line-through"
+		}
+		listHTML += fmt.Sprintf(`
+				<li class="py-3 flex items-start justify-between gap-4">
+					<div class="min-w-0">
+						<p class="text-sm font-medium %s">%s</p>
+						<p class="mt-1 text-sm text-gray-600 break-words">%s</p>
+					</div>
+					<div class="flex gap-3">
+						<button hx-post="/app/admin/query-webhooks/%d/toggle" hx-target="#query-webhooks" hx-swap="outerHTML"
+								class="text-sm text-blue-600 hover:text-blue-800">%s</button>
+						<button hx-delete="/app/admin/query-webhooks/%d" hx-target="#query-webhooks" hx-swap="outerHTML"
+								hx-confirm="Delete this webhook?"
+								class="text-sm text-red-600 hover:text-red-800">Delete</button>
+					</div>
+				</li>`, nameClass, html.EscapeString(queryNames[webhook.SavedQueryID]), html.EscapeString(describeQueryWebhook(webhook)), webhook.ID, toggleLabel, webhook.ID)
+	}
+	if listHTML == "" {
+		listHTML = `
+				<li class="py-3 text-sm text-gray-500">No saved query webhooks yet.</li>`
+	}
+
+	inputClass := "mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md text-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500"
+
+	return fmt.Sprintf(`
+	<div id="query-webhooks" class="p-6 pt-0 max-w-2xl">
+		<h3 class="text-lg font-semibold text-gray-900 mb-1">Saved query webhooks</h3>
+		<p class="text-sm text-gray-500 mb-4">Posts every task matching a saved query as JSON to an external system on a cron schedule, for example to feed a BI pipeline. With a secret, each post carries an HMAC-SHA256 signature of its body in the X-JATS-Signature header.</p>
+		%s
+		<div class="bg-white shadow rounded-lg p-6 space-y-4">
+			<ul class="divide-y divide-gray-200">%s
+			</ul>
+			<form hx-post="/app/admin/query-webhooks" hx-target="#query-webhooks" hx-swap="outerHTML" class="space-y-4 border-t border-gray-200 pt-4">
+				<div class="grid grid-cols-2 gap-4">
+					<div>
+						<label for="query_webhook_query" class="block text-sm font-medium text-gray-700">Saved query</label>
+						<select id="query_webhook_query" name="saved_query_id" required class="%s">%s
+						</select>
+					</div>
+					<div>
+						<label for="query_webhook_cron" class="block text-sm font-medium text-gray-700">Schedule</label>
+						<input id="query_webhook_cron" name="cron" type="text" required placeholder="0 6 * * *" class="%s">
+					</div>
+					<div>
+						<label for="query_webhook_url" class="block text-sm font-medium text-gray-700">URL</label>
+						<input id="query_webhook_url" name="url" type="url" required placeholder="https://example.com/ingest" class="%s">
+					</div>
+					<div>
+						<label for="query_webhook_secret" class="block text-sm font-medium text-gray-700">Secret (optional)</label>
+						<input id="query_webhook_secret" name="secret" type="text" autocomplete="off" class="%s">
+					</div>
+				</div>
+				<div class="flex justify-end">
+					<button type="submit" class="brand-button px-4 py-2 text-sm font-medium text-white rounded-md">Add webhook</button>
+				</div>
+			</form>
+		</div>
+	</div>`,
+		errorHTML, listHTML,
+		inputClass, queryOptions, inputClass, inputClass, inputClass,
+	)
+}
@@ -1,25 +1,34 @@
 package frontend
 
 import (
+	"fmt"
 	"html/template"
+	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/services"
 )
 
 // AppHandler handles main application page requests
 type AppHandler struct {
-	authService *services.AuthService
-	templates   map[string]*template.Template
+	authService     *services.AuthService
+	taskService     *services.TaskService
+	settingsService *services.SettingsService
+	templates       map[string]*template.Template
 }
 
 // NewAppHandler creates a new app handler
-func NewAppHandler(authService *services.AuthService, templates map[string]*template.Template) *AppHandler {
+func NewAppHandler(authService *services.AuthService, taskService *services.TaskService, settingsService *services.SettingsService, templates map[string]*template.Template) *AppHandler {
 	return &AppHandler{
-		authService: authService,
-		templates:   templates,
+		authService:     authService,
+		taskService:     taskService,
+		settingsService: settingsService,
+		templates:       templates,
 	}
 }
 
@@ -27,13 +36,32 @@ func NewAppHandler(authService *services.AuthService, templates map[string]*temp
 func (h *AppHandler) AppHandler(c *gin.Context) {
 	authContext, exists := c.Get("auth")
 	if !exists {
-		c.Redirect(http.StatusFound, "/login")
+		c.Redirect(http.StatusFound, appURL(c, "/login"))
 		return
 	}
 
 	auth := authContext.(*models.AuthContext)
+
+	// Fall back to the whole view if the landing saved query has been deleted
+	landingQueryID := auth.User.LandingQueryID
+	if landingQueryID != nil && h.taskService.ValidateSavedQueryIDs([]uint{*landingQueryID}) != nil {
+		landingQueryID = nil
+	}
+
 	data := gin.H{
-		"User": auth.User,
+		"User":     auth.User,
+		"L":        localizerFor(c),
+		"Branding": h.settingsService.GetBranding(),
+		"BasePath": middleware.BasePath(c.Request),
+		// The view #main-content opens on, from the user's landing preference
+		"LandingURL":  landingURL(landingView(auth.User), landingQueryID),
+		"LandingView": landingView(auth.User),
+		// Sent by HTMX and fetch requests, see RequireCSRF
+		"CSRFToken": middleware.RequestCSRFToken(c.Request),
+	}
+	// ?task= opens a task's detail panel, see services.TaskAppPath
+	if taskID, err := strconv.ParseUint(c.Query("task"), 10, 32); err == nil {
+		data["OpenTaskID"] = taskID
 	}
 
 	c.Header("Content-Type", "text/html")
@@ -41,4 +69,80 @@ func (h *AppHandler) AppHandler(c *gin.Context) {
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
-}
\ No newline at end of file
+}
+
+// ShortLinkHandler opens the task behind a /s/{code} short link, such as one
+// scanned from a job sheet QR code. Visitors who are not signed in go to the
+// login page first and come back here afterwards.
+func (h *AppHandler) ShortLinkHandler(c *gin.Context) {
+	sessionToken, _ := c.Cookie("session_token")
+	if _, err := h.authService.ValidateSession(sessionToken); sessionToken == "" || err != nil {
+		next := services.TaskShortPath(c.Param("code"))
+		c.Redirect(http.StatusFound, appURL(c, "/login?next="+url.QueryEscape(next)))
+		return
+	}
+
+	task, err := h.taskService.GetTaskByShortCode(c.Param("code"))
+	if err != nil {
+		c.String(http.StatusNotFound, "Task not found")
+		return
+	}
+
+	c.Redirect(http.StatusFound, appURL(c, services.TaskAppPath(task.ID)))
+}
+
+// receiptPixel is a transparent 1x1 GIF
+var receiptPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// ReceiptPixelHandler marks an email update as seen when its tracking image is
+// loaded. The image is served whatever the token, so it says nothing about
+// which tokens exist.
+func (h *AppHandler) ReceiptPixelHandler(c *gin.Context) {
+	if _, err := h.taskService.RecordEmailOpen(c.Param("token")); err != nil {
+		log.Printf("Failed to record email open: %v", err)
+	}
+
+	c.Header("Cache-Control", "no-store, no-cache, must-revalidate, private")
+	c.Data(http.StatusOK, "image/gif", receiptPixel)
+}
+
+// landingView returns the user's landing view, defaulting to the task list
+func landingView(user *models.User) string {
+	if user == nil || user.LandingView == "" {
+		return services.LandingViewTasks
+	}
+	return user.LandingView
+}
+
+// landingURL returns the app path of a landing view, narrowed to the landing
+// saved query where the view supports one
+func landingURL(view string, landingQueryID *uint) string {
+	var queryID uint
+	if landingQueryID != nil {
+		queryID = *landingQueryID
+	}
+
+	switch view {
+	case services.LandingViewKanban:
+		if queryID != 0 {
+			return fmt.Sprintf("/app/kanban?saved_query_id=%d", queryID)
+		}
+		return "/app/kanban"
+	case services.LandingViewReports:
+		if queryID != 0 {
+			return fmt.Sprintf("/app/reports?query=%d", queryID)
+		}
+		return "/app/reports"
+	case services.LandingViewDashboard:
+		return "/app/dashboard"
+	default:
+		if queryID != 0 {
+			return fmt.Sprintf("/app/saved-queries/%d/tasks", queryID)
+		}
+		return "/app/tasks"
+	}
+}
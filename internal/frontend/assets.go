@@ -0,0 +1,365 @@
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// AssetHandler handles the asset registry pages: the equipment tasks are done
+// on and each asset's maintenance history
+type AssetHandler struct {
+	taskService *services.TaskService
+	templates   map[string]*template.Template
+}
+
+// NewAssetHandler creates a new asset handler
+func NewAssetHandler(taskService *services.TaskService, templates map[string]*template.Template) *AssetHandler {
+	return &AssetHandler{
+		taskService: taskService,
+		templates:   templates,
+	}
+}
+
+const assetInputClass = "mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md text-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500"
+
+// assetFromForm reads an asset's fields from a submitted form
+func assetFromForm(c *gin.Context, asset *models.Asset) {
+	asset.Name = c.PostForm("name")
+	asset.Serial = c.PostForm("serial")
+	asset.Location = c.PostForm("location")
+	asset.Notes = c.PostForm("notes")
+}
+
+// assetFormFields renders the inputs shared by the create and edit forms
+func assetFormFields(asset *models.Asset) string {
+	return fmt.Sprintf(`
+				<div class="grid grid-cols-3 gap-4">
+					<div>
+						<label class="block text-sm font-medium text-gray-700">Name</label>
+						<input name="name" type="text" required value="%s" placeholder="NAS" class="%s">
+					</div>
+					<div>
+						<label class="block text-sm font-medium text-gray-700">Serial</label>
+						<input name="serial" type="text" value="%s" class="%s">
+					</div>
+					<div>
+						<label class="block text-sm font-medium text-gray-700">Location</label>
+						<input name="location" type="text" value="%s" placeholder="Rack, shelf 2" class="%s">
+					</div>
+				</div>
+				<div>
+					<label class="block text-sm font-medium text-gray-700">Notes</label>
+					<textarea name="notes" rows="2" class="%s">%s</textarea>
+				</div>`,
+		html.EscapeString(asset.Name), assetInputClass,
+		html.EscapeString(asset.Serial), assetInputClass,
+		html.EscapeString(asset.Location), assetInputClass,
+		assetInputClass, html.EscapeString(asset.Notes))
+}
+
+// AssetsPageHandler renders the asset registry
+func (h *AssetHandler) AssetsPageHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderAssetsPage(""))
+}
+
+// CreateAssetHandler adds an asset to the registry
+func (h *AssetHandler) CreateAssetHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	errorMessage := ""
+	asset := &models.Asset{}
+	assetFromForm(c, asset)
+	if err := h.taskService.CreateAsset(asset); err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderAssetsPage(errorMessage))
+}
+
+// renderAssetsPage renders the asset table and the form to add one
+func (h *AssetHandler) renderAssetsPage(errorMessage string) string {
+	errorHTML := ""
+	if errorMessage != "" {
+		errorHTML = fmt.Sprintf(`<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">%s</div>`, html.EscapeString(errorMessage))
+	}
+
+	assets, err := h.taskService.GetAssets()
+	if err != nil {
+		errorHTML += `<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">Failed to load assets</div>`
+	}
+
+	tableHTML := `
+			<p class="text-sm text-gray-500">No assets yet. Add the equipment you maintain to keep its task history in one place.</p>`
+	if len(assets) > 0 {
+		rows := ""
+		for _, asset := range assets {
+			rows += fmt.Sprintf(`
+					<tr class="hover:bg-gray-50 cursor-pointer"
+						hx-get="/app/assets/%d"
+						hx-target="#main-content">
+						<td class="px-6 py-4 text-sm font-medium text-gray-900">%s</td>
+						<td class="px-6 py-4 text-sm text-gray-600">%s</td>
+						<td class="px-6 py-4 text-sm text-gray-600">%s</td>
+						<td class="px-6 py-4 text-sm text-gray-600 text-right">%d</td>
+					</tr>`,
+				asset.ID, html.EscapeString(asset.Name), html.EscapeString(asset.Serial),
+				html.EscapeString(asset.Location), asset.TaskCount)
+		}
+		tableHTML = fmt.Sprintf(`
+		<div class="bg-white shadow rounded-lg overflow-hidden">
+			<table class="min-w-full divide-y divide-gray-200">
+				<thead class="bg-gray-50">
+					<tr>
+						<th class="px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">Name</th>
+						<th class="px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">Serial</th>
+						<th class="px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">Location</th>
+						<th class="px-6 py-3 text-right text-xs font-medium text-gray-500 uppercase tracking-wider">Tasks</th>
+					</tr>
+				</thead>
+				<tbody class="bg-white divide-y divide-gray-200">%s
+				</tbody>
+			</table>
+		</div>`, rows)
+	}
+
+	return fmt.Sprintf(`
+	<div class="p-6">
+		<h2 class="text-2xl font-bold text-gray-900 mb-6">Assets</h2>
+		%s
+		%s
+		<div class="bg-white shadow rounded-lg p-6 mt-6 max-w-3xl">
+			<h3 class="text-lg font-semibold text-gray-900">Add asset</h3>
+			<form hx-post="/app/assets" hx-target="#main-content" class="mt-4 space-y-4">%s
+				<div class="flex justify-end">
+					<button type="submit" class="brand-button px-4 py-2 text-sm font-medium text-white rounded-md">Add asset</button>
+				</div>
+			</form>
+		</div>
+	</div>`, errorHTML, tableHTML, assetFormFields(&models.Asset{}))
+}
+
+// AssetDetailHandler renders an asset with its maintenance history
+func (h *AssetHandler) AssetDetailHandler(c *gin.Context) {
+	h.respondAssetDetail(c, "")
+}
+
+// UpdateAssetHandler saves changes to an asset
+func (h *AssetHandler) UpdateAssetHandler(c *gin.Context) {
+	asset, ok := h.loadAsset(c)
+	if !ok {
+		return
+	}
+
+	errorMessage := ""
+	assetFromForm(c, asset)
+	if err := h.taskService.UpdateAsset(asset); err != nil {
+		errorMessage = err.Error()
+	}
+	h.respondAssetDetail(c, errorMessage)
+}
+
+// DeleteAssetHandler deletes an asset, keeping its tasks, and returns to the registry
+func (h *AssetHandler) DeleteAssetHandler(c *gin.Context) {
+	asset, ok := h.loadAsset(c)
+	if !ok {
+		return
+	}
+
+	errorMessage := ""
+	if err := h.taskService.DeleteAsset(asset.ID); err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderAssetsPage(errorMessage))
+}
+
+// LinkAssetTaskHandler adds a task, by ID, to an asset's history
+func (h *AssetHandler) LinkAssetTaskHandler(c *gin.Context) {
+	asset, ok := h.loadAsset(c)
+	if !ok {
+		return
+	}
+
+	errorMessage := ""
+	taskID, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(c.PostForm("task_id")), "#"), 10, 32)
+	if err != nil || taskID == 0 {
+		errorMessage = "Enter a task number"
+	} else if err := h.taskService.LinkTaskAsset(uint(taskID), asset.ID); err != nil {
+		errorMessage = fmt.Sprintf("Task #%d not found", taskID)
+	}
+	h.respondAssetDetail(c, errorMessage)
+}
+
+// UnlinkAssetTaskHandler removes a task from an asset's history
+func (h *AssetHandler) UnlinkAssetTaskHandler(c *gin.Context) {
+	asset, ok := h.loadAsset(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("taskId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	errorMessage := ""
+	if err := h.taskService.UnlinkTaskAsset(uint(taskID), asset.ID); err != nil {
+		errorMessage = err.Error()
+	}
+	h.respondAssetDetail(c, errorMessage)
+}
+
+// loadAsset looks up the asset named in the path, responding with an error if
+// there is none
+func (h *AssetHandler) loadAsset(c *gin.Context) (*models.Asset, bool) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	assetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid asset ID"})
+		return nil, false
+	}
+
+	asset, err := h.taskService.GetAsset(uint(assetID))
+	if errors.Is(err, services.ErrAssetNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Asset not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get asset"})
+		return nil, false
+	}
+	return asset, true
+}
+
+// respondAssetDetail renders the asset named in the path with its task history
+func (h *AssetHandler) respondAssetDetail(c *gin.Context, errorMessage string) {
+	asset, ok := h.loadAsset(c)
+	if !ok {
+		return
+	}
+
+	errorHTML := ""
+	if errorMessage != "" {
+		errorHTML = fmt.Sprintf(`<div class="mt-4 rounded-md bg-red-50 p-3 text-sm text-red-700">%s</div>`, html.EscapeString(errorMessage))
+	}
+
+	subtitle := []string{}
+	if asset.Serial != "" {
+		subtitle = append(subtitle, "Serial "+html.EscapeString(asset.Serial))
+	}
+	if asset.Location != "" {
+		subtitle = append(subtitle, html.EscapeString(asset.Location))
+	}
+	notesHTML := ""
+	if asset.Notes != "" {
+		notesHTML = fmt.Sprintf(`<p class="mt-2 text-sm text-gray-700 whitespace-pre-wrap">%s</p>`, html.EscapeString(asset.Notes))
+	}
+
+	historyHTML := ""
+	for _, task := range asset.Tasks {
+		resolved := ""
+		if task.ResolvedAt != nil {
+			resolved = " &middot; resolved " + task.ResolvedAt.Format("Jan 2, 2006")
+		}
+		historyHTML += fmt.Sprintf(`
+			<div class="bg-white rounded-md border border-gray-200 p-3 flex items-center justify-between gap-4">
+				<div class="cursor-pointer flex-1" onclick="showTaskDetail(%d)">
+					<p class="text-sm font-medium text-gray-900">#%d %s</p>
+					<p class="text-xs text-gray-500">Opened %s%s</p>
+				</div>
+				<span class="inline-flex items-center px-2.5 py-0.5 rounded-full text-xs font-medium bg-blue-100 text-blue-800">%s</span>
+				<button hx-delete="/app/assets/%d/tasks/%d" hx-target="#main-content"
+						hx-confirm="Remove this task from the asset's history?"
+						class="text-sm text-red-600 hover:text-red-800">Unlink</button>
+			</div>`, task.ID, task.ID, html.EscapeString(task.Name), task.CreatedAt.Format("Jan 2, 2006"), resolved,
+			html.EscapeString(string(task.Status)), asset.ID, task.ID)
+	}
+	if historyHTML == "" {
+		historyHTML = `
+			<p class="text-sm text-gray-500">No tasks linked to this asset yet.</p>`
+	}
+
+	pageHTML := fmt.Sprintf(`
+	<div class="p-6 max-w-4xl">
+		<button hx-get="/app/assets" hx-target="#main-content" class="text-sm text-blue-600 hover:text-blue-800 mb-4">&larr; All assets</button>
+		<div class="flex items-start justify-between">
+			<div>
+				<h2 class="text-2xl font-bold text-gray-900">%s</h2>
+				<p class="mt-1 text-sm text-gray-600">%s</p>
+				%s
+			</div>
+			<button hx-delete="/app/assets/%d" hx-target="#main-content"
+					hx-confirm="Delete this asset? Its tasks are kept."
+					class="text-sm text-red-600 hover:text-red-800">Delete</button>
+		</div>
+		%s
+		<div class="mt-6 flex items-center justify-between">
+			<h3 class="text-lg font-medium text-gray-900">Maintenance history (%d)</h3>
+			<form hx-post="/app/assets/%d/tasks" hx-target="#main-content" class="flex items-center gap-2">
+				<input name="task_id" type="text" required placeholder="Task #" class="w-28 px-3 py-1.5 border border-gray-300 rounded-md text-sm">
+				<button type="submit" class="brand-button px-3 py-1.5 text-sm font-medium text-white rounded-md">Link task</button>
+			</form>
+		</div>
+		<div class="mt-3 space-y-2">%s
+		</div>
+		<details class="mt-6 bg-white shadow rounded-lg p-6">
+			<summary class="text-sm font-medium text-gray-900 cursor-pointer">Edit asset</summary>
+			<form hx-put="/app/assets/%d" hx-target="#main-content" class="mt-4 space-y-4">%s
+				<div class="flex justify-end">
+					<button type="submit" class="brand-button px-4 py-2 text-sm font-medium text-white rounded-md">Save</button>
+				</div>
+			</form>
+		</details>
+	</div>`,
+		html.EscapeString(asset.Name), strings.Join(subtitle, " &middot; "), notesHTML,
+		asset.ID,
+		errorHTML,
+		len(asset.Tasks), asset.ID, historyHTML,
+		asset.ID, assetFormFields(asset),
+	)
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, pageHTML)
+}
+
+// renderTaskAssets links the assets a task was done on to their pages
+func renderTaskAssets(assets []*models.Asset) string {
+	if len(assets) == 0 {
+		return ""
+	}
+	items := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		items = append(items, fmt.Sprintf(`<a href="#" hx-get="/app/assets/%d" hx-target="#main-content" class="text-blue-600 hover:underline">%s</a>`,
+			asset.ID, html.EscapeString(asset.Name)))
+	}
+	return fmt.Sprintf(`
+					<p class="mt-3 text-xs text-gray-500"><span class="font-medium text-gray-700">Assets:</span> %s</p>`, strings.Join(items, ", "))
+}
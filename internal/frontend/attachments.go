@@ -1,7 +1,6 @@
 package frontend
 
 import (
-	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -9,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/soarinferret/jats/internal/services"
+	"github.com/soarinferret/jats/internal/utils"
 )
 
 // AttachmentHandler handles attachment-related requests
@@ -24,7 +24,7 @@ func NewAttachmentHandler(taskService *services.TaskService, attachmentPath stri
 	}
 }
 
-// ServeAttachment serves attachment files
+// ServeAttachment serves attachment files, previewing safe types inline
 func (h *AttachmentHandler) ServeAttachment(c *gin.Context) {
 	attachmentIDStr := c.Param("id")
 	attachmentID, err := strconv.ParseUint(attachmentIDStr, 10, 32)
@@ -40,19 +40,13 @@ func (h *AttachmentHandler) ServeAttachment(c *gin.Context) {
 		return
 	}
 
-	// Construct file path
+	// Stream the file with range and conditional request support
 	filePath := filepath.Join(h.attachmentPath, attachment.FilePath)
-
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "File not found on disk"})
-		return
+	if err := utils.ServeAttachment(c.Writer, c.Request, filePath, attachment, true); err != nil {
+		if os.IsNotExist(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "File not found on disk"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read attachment"})
 	}
-
-	// Set content type and filename
-	c.Header("Content-Type", attachment.ContentType)
-	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, attachment.OriginalName))
-
-	// Serve the file
-	c.File(filePath)
 }
\ No newline at end of file
@@ -3,38 +3,52 @@ package frontend
 import (
 	"html/template"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/services"
 )
 
 // AuthHandler handles authentication-related frontend requests
 type AuthHandler struct {
-	authService *services.AuthService
-	templates   map[string]*template.Template
+	authService     *services.AuthService
+	settingsService *services.SettingsService
+	templates       map[string]*template.Template
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *services.AuthService, templates map[string]*template.Template) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, settingsService *services.SettingsService, templates map[string]*template.Template) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		templates:   templates,
+		authService:     authService,
+		settingsService: settingsService,
+		templates:       templates,
 	}
 }
 
 // LoginPageHandler serves the login page
 func (h *AuthHandler) LoginPageHandler(c *gin.Context) {
+	next := loginNext(c.Query("next"))
+
 	// Check if user is already logged in
 	if sessionToken := h.getSessionToken(c); sessionToken != "" {
 		if _, err := h.authService.ValidateSession(sessionToken); err == nil {
-			c.Redirect(http.StatusFound, "/")
+			c.Redirect(http.StatusFound, appURL(c, next))
 			return
 		}
 	}
 
+	data := gin.H{
+		"L":        localizerFor(c),
+		"Branding": h.settingsService.GetBranding(),
+		"BasePath": middleware.BasePath(c.Request),
+		// Where to go after signing in, such as a task short link
+		"Next": next,
+	}
+
 	c.Header("Content-Type", "text/html")
-	if err := h.templates["login"].Execute(c.Writer, nil); err != nil {
+	if err := h.templates["login"].Execute(c.Writer, data); err != nil {
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
@@ -61,7 +75,7 @@ func (h *AuthHandler) LoginHandler(c *gin.Context) {
 		Password:  password,
 		TOTPCode:  totpCode,
 		UserAgent: c.GetHeader("User-Agent"),
-		IPAddress: c.ClientIP(),
+		IPAddress: middleware.ClientIP(c.Request),
 	}
 
 	result, err := h.authService.Login(req)
@@ -86,7 +100,7 @@ func (h *AuthHandler) LoginHandler(c *gin.Context) {
 	}
 
 	// Set session cookie
-	c.SetCookie("session_token", result.Session.Token, int(24*time.Hour.Seconds()), "/", "", false, true)
+	c.SetCookie("session_token", result.Session.Token, int(24*time.Hour.Seconds()), middleware.CookiePath(c.Request), "", middleware.IsSecure(c.Request), true)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -102,10 +116,20 @@ func (h *AuthHandler) LogoutHandler(c *gin.Context) {
 	}
 
 	// Clear session cookie
-	c.SetCookie("session_token", "", -1, "/", "", false, true)
+	c.SetCookie("session_token", "", -1, middleware.CookiePath(c.Request), "", middleware.IsSecure(c.Request), true)
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
+// loginNext returns the path to go to after signing in, defaulting to the app.
+// Only paths on this server are allowed, so the login page cannot be used to
+// redirect elsewhere.
+func loginNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.ContainsAny(next, "\\\r\n") {
+		return "/"
+	}
+	return next
+}
+
 // getSessionToken extracts session token from cookie
 func (h *AuthHandler) getSessionToken(c *gin.Context) string {
 	token, err := c.Cookie("session_token")
@@ -0,0 +1,161 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// ContactHandler handles the contact directory pages
+type ContactHandler struct {
+	contactService *services.ContactService
+	templates      map[string]*template.Template
+}
+
+// NewContactHandler creates a new contact handler
+func NewContactHandler(contactService *services.ContactService, templates map[string]*template.Template) *ContactHandler {
+	return &ContactHandler{
+		contactService: contactService,
+		templates:      templates,
+	}
+}
+
+// ContactsPageHandler renders the contact directory
+func (h *ContactHandler) ContactsPageHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	contacts, err := h.contactService.GetContacts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get contacts"})
+		return
+	}
+
+	pageHTML := `
+	<div class="p-6">
+		<h2 class="text-2xl font-bold text-gray-900 mb-6">Contacts</h2>`
+
+	if len(contacts) == 0 {
+		pageHTML += `
+		<p class="text-sm text-gray-500">No contacts yet. Senders of email-originated tasks will appear here.</p>
+	</div>`
+		c.Header("Content-Type", "text/html")
+		c.String(http.StatusOK, pageHTML)
+		return
+	}
+
+	pageHTML += `
+		<div class="bg-white shadow rounded-lg overflow-hidden">
+			<table class="min-w-full divide-y divide-gray-200">
+				<thead class="bg-gray-50">
+					<tr>
+						<th class="px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">Name</th>
+						<th class="px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">Email</th>
+						<th class="px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider">Organization</th>
+						<th class="px-6 py-3 text-right text-xs font-medium text-gray-500 uppercase tracking-wider">Tasks</th>
+					</tr>
+				</thead>
+				<tbody class="bg-white divide-y divide-gray-200">`
+
+	for _, contact := range contacts {
+		name := contact.Name
+		if name == "" {
+			name = "—"
+		}
+		pageHTML += fmt.Sprintf(`
+					<tr class="hover:bg-gray-50 cursor-pointer"
+						hx-get="/app/contacts/%d"
+						hx-target="#main-content">
+						<td class="px-6 py-4 text-sm font-medium text-gray-900">%s</td>
+						<td class="px-6 py-4 text-sm text-gray-600">%s</td>
+						<td class="px-6 py-4 text-sm text-gray-600">%s</td>
+						<td class="px-6 py-4 text-sm text-gray-600 text-right">%d</td>
+					</tr>`,
+			contact.ID, html.EscapeString(name), html.EscapeString(contact.Email),
+			html.EscapeString(contact.Organization), contact.TaskCount)
+	}
+
+	pageHTML += `
+				</tbody>
+			</table>
+		</div>
+	</div>`
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, pageHTML)
+}
+
+// ContactDetailHandler renders a contact with its task history
+func (h *ContactHandler) ContactDetailHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	contactID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contact ID"})
+		return
+	}
+
+	contact, err := h.contactService.GetContact(uint(contactID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get contact"})
+		return
+	}
+	if contact == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contact not found"})
+		return
+	}
+
+	title := contact.Name
+	if title == "" {
+		title = contact.Email
+	}
+
+	pageHTML := fmt.Sprintf(`
+	<div class="p-6">
+		<button hx-get="/app/contacts" hx-target="#main-content" class="text-sm text-blue-600 hover:text-blue-800 mb-4">&larr; All contacts</button>
+		<h2 class="text-2xl font-bold text-gray-900">%s</h2>
+		<p class="mt-1 text-sm text-gray-600">%s`, html.EscapeString(title), html.EscapeString(contact.Email))
+	if contact.Organization != "" {
+		pageHTML += ` &middot; ` + html.EscapeString(contact.Organization)
+	}
+	pageHTML += fmt.Sprintf(`</p>
+		<p class="mt-1 text-xs text-gray-500">First seen %s</p>
+		<h3 class="mt-6 mb-3 text-lg font-medium text-gray-900">Tasks (%d)</h3>
+		<div class="space-y-2">`, contact.CreatedAt.Format("Jan 2, 2006"), len(contact.Tasks))
+
+	for _, task := range contact.Tasks {
+		pageHTML += fmt.Sprintf(`
+			<div class="bg-white rounded-md border border-gray-200 p-3 flex items-center justify-between cursor-pointer hover:shadow-md transition-shadow"
+				 onclick="showTaskDetail(%d)">
+				<div>
+					<p class="text-sm font-medium text-gray-900">#%d %s</p>
+					<p class="text-xs text-gray-500">%s</p>
+				</div>
+				<span class="inline-flex items-center px-2.5 py-0.5 rounded-full text-xs font-medium bg-blue-100 text-blue-800">%s</span>
+			</div>`, task.ID, task.ID, html.EscapeString(task.Name), task.CreatedAt.Format("Jan 2, 2006"), html.EscapeString(string(task.Status)))
+	}
+
+	if len(contact.Tasks) == 0 {
+		pageHTML += `
+			<p class="text-sm text-gray-500">No tasks linked to this contact.</p>`
+	}
+
+	pageHTML += `
+		</div>
+	</div>`
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, pageHTML)
+}
@@ -0,0 +1,356 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+var dashboardWidgetLabels = map[models.DashboardWidgetType]string{
+	models.DashboardWidgetTaskCount:  "Task count",
+	models.DashboardWidgetQueryChart: "Saved query chart",
+	models.DashboardWidgetActivity:   "Recent activity",
+	models.DashboardWidgetTimeLogged: "My time",
+}
+
+var dashboardWidgetTypes = []models.DashboardWidgetType{
+	models.DashboardWidgetTaskCount,
+	models.DashboardWidgetQueryChart,
+	models.DashboardWidgetActivity,
+	models.DashboardWidgetTimeLogged,
+}
+
+// DashboardHandler handles the configurable dashboard page
+type DashboardHandler struct {
+	taskService *services.TaskService
+	authService *services.AuthService
+	templates   map[string]*template.Template
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(taskService *services.TaskService, authService *services.AuthService, templates map[string]*template.Template) *DashboardHandler {
+	return &DashboardHandler{
+		taskService: taskService,
+		authService: authService,
+		templates:   templates,
+	}
+}
+
+// currentUser returns the signed-in user, or nil
+func currentUser(c *gin.Context) *models.User {
+	authContext, exists := c.Get("auth")
+	if !exists {
+		return nil
+	}
+	auth, ok := authContext.(*models.AuthContext)
+	if !ok {
+		return nil
+	}
+	return auth.User
+}
+
+// userDashboardLayout returns a copy of the user's layout, or the default one
+func userDashboardLayout(user *models.User) *models.DashboardLayout {
+	if user.DashboardLayout == nil {
+		return services.DefaultDashboardLayout()
+	}
+	layout := &models.DashboardLayout{Widgets: make([]models.DashboardWidget, len(user.DashboardLayout.Widgets))}
+	copy(layout.Widgets, user.DashboardLayout.Widgets)
+	return layout
+}
+
+// DashboardPageHandler renders the signed-in user's dashboard
+func (h *DashboardHandler) DashboardPageHandler(c *gin.Context) {
+	user := currentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	h.renderDashboard(c, user.DashboardLayout, c.Query("edit") == "1", "")
+}
+
+// AddWidgetHandler appends a widget from the customize form
+func (h *DashboardHandler) AddWidgetHandler(c *gin.Context) {
+	user := currentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	widget := models.DashboardWidget{
+		Type:   models.DashboardWidgetType(c.PostForm("type")),
+		Title:  c.PostForm("title"),
+		Status: models.TaskStatus(c.PostForm("status")),
+	}
+	if id, err := strconv.ParseUint(c.PostForm("saved_query_id"), 10, 32); err == nil {
+		widget.SavedQueryID = uint(id)
+	}
+	widget.Days, _ = strconv.Atoi(c.PostForm("days"))
+	widget.Limit, _ = strconv.Atoi(c.PostForm("limit"))
+	widget.Width, _ = strconv.Atoi(c.PostForm("width"))
+
+	layout := userDashboardLayout(user)
+	layout.Widgets = append(layout.Widgets, widget)
+	h.saveAndRender(c, user, layout)
+}
+
+// MoveWidgetHandler moves a widget one position up or down (?dir=up|down)
+func (h *DashboardHandler) MoveWidgetHandler(c *gin.Context) {
+	user := currentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	layout := userDashboardLayout(user)
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 || index >= len(layout.Widgets) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid widget"})
+		return
+	}
+
+	target := index - 1
+	if c.Query("dir") == "down" {
+		target = index + 1
+	}
+	if target >= 0 && target < len(layout.Widgets) {
+		layout.Widgets[index], layout.Widgets[target] = layout.Widgets[target], layout.Widgets[index]
+	}
+	h.saveAndRender(c, user, layout)
+}
+
+// RemoveWidgetHandler removes a widget from the dashboard
+func (h *DashboardHandler) RemoveWidgetHandler(c *gin.Context) {
+	user := currentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	layout := userDashboardLayout(user)
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 || index >= len(layout.Widgets) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid widget"})
+		return
+	}
+
+	layout.Widgets = append(layout.Widgets[:index], layout.Widgets[index+1:]...)
+	h.saveAndRender(c, user, layout)
+}
+
+// ResetDashboardHandler restores the default layout
+func (h *DashboardHandler) ResetDashboardHandler(c *gin.Context) {
+	user := currentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if _, err := h.authService.SetDashboardLayout(user.ID, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset dashboard"})
+		return
+	}
+	h.renderDashboard(c, nil, true, "")
+}
+
+// saveAndRender validates and stores a layout, then re-renders the dashboard in
+// edit mode; validation errors are shown above the widgets
+func (h *DashboardHandler) saveAndRender(c *gin.Context, user *models.User, layout *models.DashboardLayout) {
+	if err := h.taskService.NormalizeDashboardLayout(layout); err != nil {
+		h.renderDashboard(c, user.DashboardLayout, true, err.Error())
+		return
+	}
+	if _, err := h.authService.SetDashboardLayout(user.ID, layout); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save dashboard"})
+		return
+	}
+	h.renderDashboard(c, layout, true, "")
+}
+
+func (h *DashboardHandler) renderDashboard(c *gin.Context, layout *models.DashboardLayout, editing bool, formError string) {
+	dashboard, err := h.taskService.GetDashboard(layout, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load dashboard"})
+		return
+	}
+
+	savedQueries, err := h.taskService.GetSavedQueries()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get saved queries"})
+		return
+	}
+
+	editLink := `<button hx-get="/app/dashboard?edit=1" hx-target="#main-content" class="text-sm text-blue-600 hover:text-blue-800">Customize</button>`
+	if editing {
+		editLink = `<button hx-get="/app/dashboard" hx-target="#main-content" class="text-sm text-blue-600 hover:text-blue-800">Done</button>`
+	}
+
+	pageHTML := fmt.Sprintf(`
+	<div class="p-6">
+		<div class="mb-6 flex items-center justify-between">
+			<h2 class="text-2xl font-bold text-gray-900">Dashboard</h2>
+			%s
+		</div>`, editLink)
+
+	if formError != "" {
+		pageHTML += fmt.Sprintf(`
+		<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">%s</div>`, html.EscapeString(formError))
+	}
+
+	if editing {
+		pageHTML += renderAddWidgetForm(savedQueries)
+	}
+
+	if len(dashboard.Widgets) == 0 {
+		pageHTML += `
+		<p class="text-sm text-gray-500">Your dashboard is empty. Use Customize to add widgets.</p>`
+	}
+
+	pageHTML += `
+		<div class="grid grid-cols-1 gap-5 md:grid-cols-3">`
+	for i, data := range dashboard.Widgets {
+		pageHTML += renderDashboardWidget(i, len(dashboard.Widgets), data, editing)
+	}
+	pageHTML += `
+		</div>
+	</div>`
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, pageHTML)
+}
+
+func renderAddWidgetForm(savedQueries []*models.SavedQuery) string {
+	typeOptions := ""
+	for _, t := range dashboardWidgetTypes {
+		typeOptions += fmt.Sprintf(`<option value="%s">%s</option>`, t, dashboardWidgetLabels[t])
+	}
+
+	statusOptions := `<option value="">Any status</option>`
+	for _, status := range []models.TaskStatus{models.TaskStatusOpen, models.TaskStatusInProgress, models.TaskStatusPendingReview, models.TaskStatusResolved, models.TaskStatusClosed} {
+		statusOptions += fmt.Sprintf(`<option value="%s">%s</option>`, status, status)
+	}
+
+	queryOptions := `<option value="">No saved query</option>`
+	for _, query := range savedQueries {
+		queryOptions += fmt.Sprintf(`<option value="%d">%s</option>`, query.ID, html.EscapeString(query.Name))
+	}
+
+	return fmt.Sprintf(`
+		<form hx-post="/app/dashboard/widgets" hx-target="#main-content" class="mb-6 bg-white shadow rounded-lg p-4 flex flex-wrap items-end gap-3">
+			<label class="text-xs text-gray-600">Widget<br><select name="type" class="rounded-md border-gray-300 text-sm">%s</select></label>
+			<label class="text-xs text-gray-600">Title<br><input type="text" name="title" placeholder="Optional" class="rounded-md border-gray-300 text-sm"></label>
+			<label class="text-xs text-gray-600">Status<br><select name="status" class="rounded-md border-gray-300 text-sm">%s</select></label>
+			<label class="text-xs text-gray-600">Saved query<br><select name="saved_query_id" class="rounded-md border-gray-300 text-sm">%s</select></label>
+			<label class="text-xs text-gray-600">Width<br><select name="width" class="rounded-md border-gray-300 text-sm"><option value="1">1</option><option value="2">2</option><option value="3">3</option></select></label>
+			<button type="submit" class="px-3 py-2 text-sm font-medium rounded-md text-white bg-blue-600 hover:bg-blue-700">Add widget</button>
+			<button type="button" hx-post="/app/dashboard/reset" hx-target="#main-content" hx-confirm="Restore the default dashboard?" class="ml-auto text-sm text-gray-500 hover:text-gray-700">Reset to default</button>
+		</form>`, typeOptions, statusOptions, queryOptions)
+}
+
+func renderDashboardWidget(index, total int, data services.DashboardWidgetData, editing bool) string {
+	controls := ""
+	if editing {
+		if index > 0 {
+			controls += fmt.Sprintf(`<button hx-post="/app/dashboard/widgets/%d/move?dir=up" hx-target="#main-content" class="text-gray-400 hover:text-gray-600" title="Move up">&uarr;</button>`, index)
+		}
+		if index < total-1 {
+			controls += fmt.Sprintf(`<button hx-post="/app/dashboard/widgets/%d/move?dir=down" hx-target="#main-content" class="text-gray-400 hover:text-gray-600" title="Move down">&darr;</button>`, index)
+		}
+		controls += fmt.Sprintf(`<button hx-delete="/app/dashboard/widgets/%d" hx-target="#main-content" class="text-red-400 hover:text-red-600" title="Remove">&times;</button>`, index)
+	}
+
+	var body string
+	switch {
+	case data.Error != "":
+		body = fmt.Sprintf(`<p class="text-sm text-red-600">%s</p>`, html.EscapeString(data.Error))
+	case data.Count != nil:
+		body = fmt.Sprintf(`<p class="text-3xl font-semibold text-gray-900">%d</p>`, *data.Count)
+	case data.Widget.Type == models.DashboardWidgetQueryChart:
+		body = renderDashboardChart(data.Chart)
+	case data.Widget.Type == models.DashboardWidgetActivity:
+		body = renderDashboardActivity(data.Activity)
+	case data.TimeLogged != nil:
+		body = fmt.Sprintf(`
+				<dl class="grid grid-cols-2 gap-4">
+					<div><dt class="text-xs text-gray-500">Today</dt><dd class="text-2xl font-semibold text-gray-900">%s</dd></div>
+					<div><dt class="text-xs text-gray-500">This week</dt><dd class="text-2xl font-semibold text-gray-900">%s</dd></div>
+				</dl>`, formatMinutes(data.TimeLogged.TodayMinutes), formatMinutes(data.TimeLogged.WeekMinutes))
+	}
+
+	width := data.Widget.Width
+	if width < 1 || width > 3 {
+		width = 1
+	}
+
+	return fmt.Sprintf(`
+			<div class="md:col-span-%d bg-white shadow rounded-lg p-5">
+				<div class="flex items-center justify-between mb-3">
+					<h3 class="text-sm font-medium text-gray-500 truncate">%s</h3>
+					<div class="space-x-2 text-sm">%s</div>
+				</div>
+				%s
+			</div>`, width, html.EscapeString(data.Title), controls, body)
+}
+
+// renderDashboardChart draws the minutes per day as a simple bar chart
+func renderDashboardChart(points []services.DashboardChartPoint) string {
+	maxMinutes, totalMinutes := 0, 0
+	for _, point := range points {
+		totalMinutes += point.Minutes
+		if point.Minutes > maxMinutes {
+			maxMinutes = point.Minutes
+		}
+	}
+
+	var bars strings.Builder
+	for _, point := range points {
+		height := 0
+		if maxMinutes > 0 {
+			height = point.Minutes * 100 / maxMinutes
+		}
+		label := point.Date
+		if day, err := time.Parse("2006-01-02", point.Date); err == nil {
+			label = day.Format("Jan 2")
+		}
+		fmt.Fprintf(&bars, `
+					<div class="flex-1 flex flex-col items-center justify-end h-full" title="%s: %s">
+						<div class="w-full bg-blue-500 rounded-t" style="height: %d%%"></div>
+					</div>`, label, formatMinutes(point.Minutes), height)
+	}
+
+	return fmt.Sprintf(`
+				<div class="flex items-end gap-1 h-32">%s
+				</div>
+				<p class="mt-2 text-xs text-gray-500">%s logged in the last %d days</p>`, bars.String(), formatMinutes(totalMinutes), len(points))
+}
+
+func renderDashboardActivity(events []services.ActivityEvent) string {
+	if len(events) == 0 {
+		return `<p class="text-sm text-gray-500">No activity in the last week.</p>`
+	}
+
+	var list strings.Builder
+	list.WriteString(`<div class="divide-y divide-gray-100">`)
+	for _, event := range events {
+		fmt.Fprintf(&list, `
+					<div class="py-2 flex items-center justify-between cursor-pointer hover:bg-gray-50" onclick="showTaskDetail(%d)">
+						<p class="text-sm text-gray-900 truncate">
+							<span class="inline-flex items-center px-2 py-0.5 rounded-full text-xs font-medium %s mr-2">%s</span>
+							#%d %s
+						</p>
+						<span class="ml-4 text-xs text-gray-400 whitespace-nowrap">%s</span>
+					</div>`,
+			event.TaskID, activityBadgeClasses[event.Type], activityLabels[event.Type],
+			event.TaskID, html.EscapeString(event.TaskName), event.Timestamp.Format("Jan 2, 3:04 PM"))
+	}
+	list.WriteString(`</div>`)
+	return list.String()
+}
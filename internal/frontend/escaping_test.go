@@ -0,0 +1,298 @@
+package frontend
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+	"github.com/soarinferret/jats/internal/services"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// xssPayload breaks out of attributes and text alike when rendered unescaped
+const xssPayload = `"'><script>alert(1)</script>`
+
+// setupEscapingTest stores the payload in every user-controlled field the web UI
+// renders and returns a router serving the frontend as an administrator, the
+// seeded task and the seeded milestone
+func setupEscapingTest(t *testing.T) (*gin.Engine, *models.Task, *models.Milestone) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	err = db.AutoMigrate(
+		&models.Task{},
+		&models.Subtask{},
+		&models.TimeEntry{},
+		&models.Comment{},
+		&models.TaskSubscriber{},
+		&models.Attachment{},
+		&models.SavedQuery{},
+		&models.SavedQuerySchedule{},
+		&models.SavedQueryWebhook{},
+		&models.TaskStatusChange{},
+		&models.TaskReference{},
+		&models.Milestone{},
+		&models.User{},
+		&models.Session{},
+		&models.APIKey{},
+		&models.LoginAttempt{},
+		&models.BrandingSettings{},
+		&models.CannedResponse{},
+		&models.Contact{},
+		&models.QuarantinedEmail{},
+		&models.ExternalIssue{},
+		&models.ExternalComment{},
+		&models.NotificationMute{},
+		&models.OutOfOffice{},
+		&models.Asset{},
+		&models.Project{},
+		&models.TaskReview{},
+		&models.TaskDependency{},
+		&models.EmailReceipt{},
+		&models.ScheduledAction{},
+		&models.AutomationRule{},
+		&models.TaskAssignment{},
+		&models.EmailMessage{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	taskRepo := repository.NewTaskRepository(db)
+	authRepo := repository.NewAuthRepository(db)
+	taskService := services.NewTaskService(taskRepo, nil)
+	authService := services.NewAuthService(authRepo, nil)
+	settingsService := services.NewSettingsService(repository.NewSettingsRepository(db))
+	contactService := services.NewContactService(repository.NewContactRepository(db))
+	spamService := services.NewSpamService(nil, repository.NewQuarantineRepository(db), &config.SpamConfig{})
+	retentionService := services.NewRetentionService(taskRepo, authRepo, &config.RetentionConfig{})
+
+	user, err := authService.RegisterUser("admin", "admin@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	milestone := &models.Milestone{Name: xssPayload}
+	if err := taskService.CreateMilestone(milestone); err != nil {
+		t.Fatalf("Failed to create milestone: %v", err)
+	}
+
+	task, err := taskService.CreateTask(xssPayload)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	task.Description = xssPayload + " see #" + fmt.Sprint(task.ID)
+	task.Tags = []string{xssPayload}
+	task.MilestoneID = &milestone.ID
+	if err := taskService.UpdateTask(task); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	if err := taskService.AddSubtask(task.ID, &models.Subtask{Name: xssPayload, EstimateMinutes: 30}); err != nil {
+		t.Fatalf("Failed to add subtask: %v", err)
+	}
+	subtaskTask, err := taskService.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload task: %v", err)
+	}
+	subtaskID := subtaskTask.Subtasks[0].ID
+	if err := taskService.AddTimeEntry(task.ID, &models.TimeEntry{TaskID: task.ID, SubtaskID: &subtaskID, Duration: 15, Description: xssPayload}); err != nil {
+		t.Fatalf("Failed to add time entry: %v", err)
+	}
+	if err := taskService.AddComment(task.ID, &models.Comment{TaskID: task.ID, Content: xssPayload, FromEmail: xssPayload}); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+	if err := taskService.AddAttachment(&models.Attachment{TaskID: &task.ID, FileName: "x", OriginalName: xssPayload, FilePath: "x"}); err != nil {
+		t.Fatalf("Failed to add attachment: %v", err)
+	}
+	if _, err := taskService.CreateSavedQuery(&models.SavedQuery{Name: xssPayload, IncludedTags: []string{xssPayload}}); err != nil {
+		t.Fatalf("Failed to create saved query: %v", err)
+	}
+	if _, err := settingsService.SaveCannedResponse(&models.CannedResponse{Name: "thanks", Content: xssPayload}); err != nil {
+		t.Fatalf("Failed to create canned response: %v", err)
+	}
+	if _, err := settingsService.UpdateBranding(&models.BrandingSettings{InstanceName: xssPayload, Tagline: xssPayload, FooterText: xssPayload}); err != nil {
+		t.Fatalf("Failed to update branding: %v", err)
+	}
+	if err := contactService.RecordSender(xssPayload, "sender@example.com", task.ID); err != nil {
+		t.Fatalf("Failed to record contact: %v", err)
+	}
+	if err := spamService.Quarantine(&models.QuarantinedEmail{From: "spam@example.com", FromName: xssPayload, Subject: xssPayload, Symbols: []string{xssPayload}}); err != nil {
+		t.Fatalf("Failed to quarantine email: %v", err)
+	}
+	if err := retentionService.Run(task.CreatedAt); err != nil {
+		t.Fatalf("Failed to run retention: %v", err)
+	}
+
+	handler := NewHandler(authService, taskService, settingsService, contactService, spamService, retentionService)
+	if err := handler.LoadTemplates("../../frontend/templates"); err != nil {
+		t.Fatalf("Failed to load templates: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("auth", &models.AuthContext{
+			User:        user,
+			Permissions: append(models.DefaultPermissions(), models.PermissionAdmin),
+			AuthMethod:  "session",
+		})
+		c.Next()
+	})
+
+	router.GET("/", handler.App.AppHandler)
+	router.GET("/login", handler.Auth.LoginPageHandler)
+	router.GET("/app/tasks", handler.Tasks.TaskListHandler)
+	router.GET("/app/tasks/new", handler.Tasks.NewTaskFormHandler)
+	router.GET("/app/tasks/:id/edit", handler.Tasks.EditTaskFormHandler)
+	router.POST("/app/tasks/:id/toggle-complete", handler.Tasks.TaskToggleCompleteHandler)
+	router.GET("/app/tasks/:id/detail", handler.Tasks.TaskDetailHandler)
+	router.GET("/app/tasks/:id/subtasks", handler.Tasks.TaskSubtasksHandler)
+	router.GET("/app/tasks/:id/timeline", handler.Tasks.TaskTimelineHandler)
+	router.GET("/app/saved-queries", handler.Saved.SavedQueriesListHandler)
+	router.GET("/app/saved-queries/new", handler.Saved.NewSavedQueryFormHandler)
+	router.GET("/app/saved-queries/:id/tasks", handler.SavedQueryTasksHandler)
+	router.GET("/app/reports", handler.Reports.ReportPageHandler)
+	router.GET("/app/dashboard", handler.Dashboard.DashboardPageHandler)
+	router.GET("/app/kanban", handler.Kanban.KanbanPageHandler)
+	router.GET("/app/activity", handler.Activity.ActivityPageHandler)
+	router.GET("/app/contacts", handler.Contacts.ContactsPageHandler)
+	router.GET("/app/contacts/:id", handler.Contacts.ContactDetailHandler)
+	router.GET("/app/admin", handler.Admin.AdminPageHandler)
+	router.POST("/app/import/preview", handler.Import.ImportPreviewHandler)
+
+	return router, task, milestone
+}
+
+func TestFrontendEscapesUserContent(t *testing.T) {
+	router, task, milestone := setupEscapingTest(t)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		htmx     bool
+		contains string
+	}{
+		{name: "app shell", method: "GET", path: "/"},
+		{name: "login page", method: "GET", path: "/login"},
+		{name: "task list page", method: "GET", path: "/app/tasks?status="},
+		{name: "task list fragment", method: "GET", path: "/app/tasks?status=", htmx: true, contains: "&lt;script&gt;"},
+		{name: "task list search fragment", method: "GET", path: "/app/tasks?status=&search=script", htmx: true, contains: "<mark class=\"bg-yellow-200 rounded\">script</mark>"},
+		{name: "new task form", method: "GET", path: "/app/tasks/new"},
+		{name: "edit task form", method: "GET", path: fmt.Sprintf("/app/tasks/%d/edit", task.ID), contains: "&lt;script&gt;"},
+		{name: "task detail", method: "GET", path: fmt.Sprintf("/app/tasks/%d/detail", task.ID), contains: fmt.Sprintf("showTaskDetail(%d)", task.ID)},
+		{name: "subtasks", method: "GET", path: fmt.Sprintf("/app/tasks/%d/subtasks", task.ID), contains: "&lt;script&gt;"},
+		{name: "timeline", method: "GET", path: fmt.Sprintf("/app/tasks/%d/timeline", task.ID), contains: "&lt;script&gt;"},
+		{name: "saved queries", method: "GET", path: "/app/saved-queries", contains: "&lt;script&gt;"},
+		{name: "new saved query form", method: "GET", path: "/app/saved-queries/new"},
+		{name: "saved query tasks", method: "GET", path: "/app/saved-queries/1/tasks?status="},
+		{name: "reports", method: "GET", path: fmt.Sprintf("/app/reports?query=1&milestone=%d", milestone.ID), contains: "&lt;script&gt;"},
+		{name: "dashboard", method: "GET", path: "/app/dashboard?edit=1", contains: "&lt;script&gt;"},
+		{name: "kanban", method: "GET", path: "/app/kanban", contains: "&lt;script&gt;"},
+		{name: "activity", method: "GET", path: "/app/activity?tag=" + url.QueryEscape(xssPayload), contains: "&lt;script&gt;"},
+		{name: "contacts", method: "GET", path: "/app/contacts", contains: "&lt;script&gt;"},
+		{name: "contact detail", method: "GET", path: "/app/contacts/1", contains: "&lt;script&gt;"},
+		{name: "admin", method: "GET", path: "/app/admin", contains: "&lt;script&gt;"},
+		{name: "toggle complete", method: "POST", path: fmt.Sprintf("/app/tasks/%d/toggle-complete", task.ID), contains: "&lt;script&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.htmx {
+				req.Header.Set("HX-Request", "true")
+				req.Header.Set("HX-Target", "tasks-list")
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			body := rec.Body.String()
+			if strings.Contains(body, "<script>alert(1)") {
+				t.Errorf("Response contains the unescaped payload:\n%s", body)
+			}
+			if tt.contains != "" && !strings.Contains(body, tt.contains) {
+				t.Errorf("Expected response to contain %q", tt.contains)
+			}
+		})
+	}
+}
+
+func TestImportPreviewEscapesCSV(t *testing.T) {
+	router, _, _ := setupEscapingTest(t)
+
+	form := url.Values{}
+	quoted := `"` + strings.ReplaceAll(xssPayload, `"`, `""`) + `"`
+	form.Set("csv", "name,"+quoted+"\n"+quoted+",\"a,b\"\n")
+	form.Set("name", "0")
+	form.Set("priority", "1")
+	req := httptest.NewRequest("POST", "/app/import/preview", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if strings.Contains(body, "<script>alert(1)") {
+		t.Errorf("Response contains the unescaped payload:\n%s", body)
+	}
+	if !strings.Contains(body, `name="priority_value:a,b"`) {
+		t.Errorf("Expected the unknown priority value to be offered for mapping:\n%s", body)
+	}
+}
+
+func TestRenderNoteContentInlinesPastedImages(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/app/tasks/1/detail", nil)
+
+	images := map[uint]models.Attachment{7: {ID: 7, ContentType: "image/png"}}
+	inlined := make(map[uint]bool)
+	note := "Error below, see #3\n![" + xssPayload + "](attachment:7)\n![other](attachment:8)"
+
+	got := renderNoteContent(c, note, images, inlined)
+	if !strings.Contains(got, `<img src="/app/attachments/7"`) {
+		t.Errorf("Expected the pasted image to be shown inline, got %s", got)
+	}
+	if strings.Contains(got, "<script>") {
+		t.Errorf("Expected the image name to be escaped, got %s", got)
+	}
+	if !strings.Contains(got, "showTaskDetail(3)") {
+		t.Errorf("Expected task references to still be linked, got %s", got)
+	}
+	if !strings.Contains(got, "![other](attachment:8)") {
+		t.Errorf("Expected a reference to an unknown attachment to stay as typed, got %s", got)
+	}
+	if !inlined[7] || inlined[8] {
+		t.Errorf("Expected only attachment 7 to be recorded as inlined, got %v", inlined)
+	}
+}
+
+func TestAudioPlayerHTML(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/app/tasks/1/detail", nil)
+
+	got := audioPlayerHTML(c, models.Attachment{ID: 4, ContentType: "audio/mp4", OriginalName: xssPayload})
+	if !strings.Contains(got, `<audio controls preload="metadata" src="/app/attachments/4"`) {
+		t.Errorf("Expected an audio player, got %s", got)
+	}
+	if strings.Contains(got, "<script>") {
+		t.Errorf("Expected the file name to be escaped, got %s", got)
+	}
+	if got := audioPlayerHTML(c, models.Attachment{ID: 5, ContentType: "image/png"}); got != "" {
+		t.Errorf("Expected no player for an image, got %s", got)
+	}
+}
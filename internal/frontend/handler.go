@@ -5,14 +5,21 @@ import (
 	"path/filepath"
 
 	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/i18n"
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/services"
 )
 
 // Handler coordinates all frontend request handling
 type Handler struct {
-	authService *services.AuthService
-	taskService *services.TaskService
-	templates   map[string]*template.Template
+	authService     *services.AuthService
+	taskService     *services.TaskService
+	settingsService *services.SettingsService
+	contactService  *services.ContactService
+	spamService     *services.SpamService
+	retentionService *services.RetentionService
+	templates       map[string]*template.Template
 
 	// Sub-handlers for different areas
 	Auth        *AuthHandler
@@ -21,23 +28,45 @@ type Handler struct {
 	App         *AppHandler
 	Attachments *AttachmentHandler
 	Reports     *ReportHandler
+	Admin       *AdminHandler
+	Kanban      *KanbanHandler
+	Contacts    *ContactHandler
+	Activity    *ActivityHandler
+	Timesheet   *TimesheetHandler
+	Import      *ImportHandler
+	Team        *TeamHandler
+	Assets      *AssetHandler
+	Dashboard   *DashboardHandler
 }
 
 // NewHandler creates a new frontend handler with all sub-handlers
-func NewHandler(authService *services.AuthService, taskService *services.TaskService) *Handler {
+func NewHandler(authService *services.AuthService, taskService *services.TaskService, settingsService *services.SettingsService, contactService *services.ContactService, spamService *services.SpamService, retentionService *services.RetentionService) *Handler {
 	h := &Handler{
-		authService: authService,
-		taskService: taskService,
-		templates:   make(map[string]*template.Template),
+		authService:     authService,
+		taskService:     taskService,
+		settingsService: settingsService,
+		contactService:  contactService,
+		spamService:     spamService,
+		retentionService: retentionService,
+		templates:       make(map[string]*template.Template),
 	}
 
 	// Initialize sub-handlers (they share the same templates map)
-	h.Auth = NewAuthHandler(authService, h.templates)
-	h.Tasks = NewTaskHandler(taskService, h.templates)
-	h.Saved = NewSavedQueryHandler(taskService, h.templates)
-	h.App = NewAppHandler(authService, h.templates)
+	h.Auth = NewAuthHandler(authService, settingsService, h.templates)
+	h.Tasks = NewTaskHandler(taskService, settingsService, h.templates)
+	h.Saved = NewSavedQueryHandler(taskService, authService, h.templates)
+	h.App = NewAppHandler(authService, taskService, settingsService, h.templates)
 	h.Attachments = NewAttachmentHandler(taskService, "./attachments")
 	h.Reports = NewReportHandler(taskService, h.templates)
+	h.Admin = NewAdminHandler(settingsService, taskService, spamService, retentionService, h.templates)
+	h.Kanban = NewKanbanHandler(taskService, h.templates)
+	h.Contacts = NewContactHandler(contactService, h.templates)
+	h.Activity = NewActivityHandler(taskService, h.templates)
+	h.Timesheet = NewTimesheetHandler(taskService, h.templates)
+	h.Import = NewImportHandler(taskService, h.templates)
+	h.Team = NewTeamHandler(taskService, h.templates)
+	h.Assets = NewAssetHandler(taskService, h.templates)
+	h.Dashboard = NewDashboardHandler(taskService, authService, h.templates)
 
 	return h
 }
@@ -73,6 +102,32 @@ func (h *Handler) SavedQueryTasksHandler(c *gin.Context) {
 	h.Saved.SavedQueryTasksHandler(c, h.Tasks)
 }
 
+// OverdueTasksHandler delegates to the saved query handler, which lists
+// through the task handler
+func (h *Handler) OverdueTasksHandler(c *gin.Context) {
+	h.Saved.OverdueTasksHandler(c, h.Tasks)
+}
+
+// localizerFor returns a localizer for the current request, preferring the
+// signed-in user's language and falling back to the Accept-Language header
+func localizerFor(c *gin.Context) *i18n.Localizer {
+	var candidates []string
+	if authContext, exists := c.Get("auth"); exists {
+		if auth, ok := authContext.(*models.AuthContext); ok && auth.User != nil && auth.User.Language != "" {
+			candidates = append(candidates, auth.User.Language)
+		}
+	}
+	candidates = append(candidates, i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))...)
+	return i18n.NewLocalizer(candidates...)
+}
+
+// appURL prefixes an absolute app path such as /app/tasks with the base path the
+// request was served under. HTMX requests are prefixed in the browser by app.html,
+// so this is for plain links, redirects and scripts.
+func appURL(c *gin.Context, path string) string {
+	return middleware.BasePath(c.Request) + path
+}
+
 // getSessionToken extracts session token from cookie - shared utility
 func getSessionToken(c interface{}) string {
 	// This will be implemented based on your gin context interface
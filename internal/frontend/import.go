@@ -0,0 +1,442 @@
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/middleware"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// ImportHandler handles importing tasks from a CSV file. The file travels
+// with the mapping form, so nothing is kept between the upload, the preview
+// and the import.
+type ImportHandler struct {
+	taskService *services.TaskService
+	templates   map[string]*template.Template
+}
+
+// NewImportHandler creates a new CSV import handler
+func NewImportHandler(taskService *services.TaskService, templates map[string]*template.Template) *ImportHandler {
+	return &ImportHandler{
+		taskService: taskService,
+		templates:   templates,
+	}
+}
+
+// importPreviewRows is how many mapped rows the preview shows
+const importPreviewRows = 50
+
+// dueDateFormats are the due date layouts offered for the due date column
+var dueDateFormats = []struct {
+	layout string
+	label  string
+}{
+	{"", "As typed in JATS (2006-01-02, tomorrow, friday, +3d)"},
+	{"01/02/2006", "MM/DD/YYYY"},
+	{"02/01/2006", "DD/MM/YYYY"},
+	{"02.01.2006", "DD.MM.YYYY"},
+	{"2006/01/02", "YYYY/MM/DD"},
+	{"Jan 2, 2006", "Jan 2, 2006"},
+	{"2 Jan 2006", "2 Jan 2006"},
+}
+
+// ImportPageHandler renders the CSV upload form
+func (h *ImportHandler) ImportPageHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, importUploadHTML(""))
+}
+
+func importUploadHTML(errorHTML string) string {
+	return fmt.Sprintf(`
+	<div class="p-6">
+		<h2 class="text-2xl font-bold text-gray-900 mb-6">Import tasks</h2>
+		%s
+		<form hx-post="/app/import/preview" hx-target="#main-content" hx-encoding="multipart/form-data" class="bg-white shadow rounded-lg p-6 space-y-4">
+			<p class="text-sm text-gray-600">Upload a CSV file whose first row names its columns. Commas, semicolons and tabs are recognized as the delimiter. You map the columns to task fields and preview the tasks before anything is created; up to %d rows are imported at a time.</p>
+			<input type="file" name="file" accept=".csv,text/csv,text/tab-separated-values" required class="block text-sm text-gray-700">
+			<button type="submit" class="brand-button px-4 py-2 text-sm font-medium text-white rounded-md">Upload</button>
+		</form>
+	</div>`, errorHTML, services.MaxBulkEntries)
+}
+
+func importErrorHTML(message string) string {
+	return fmt.Sprintf(`<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">%s</div>`, html.EscapeString(message))
+}
+
+// readImport reads the CSV file and its mapping from the form. A newly
+// uploaded file gets the mapping guessed from its header.
+func readImport(c *gin.Context) (string, *services.CSVTable, services.CSVImportMapping, error) {
+	var mapping services.CSVImportMapping
+
+	content := c.PostForm("csv")
+	uploaded := false
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			return "", nil, mapping, errors.New("failed to read the uploaded file")
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return "", nil, mapping, errors.New("failed to read the uploaded file")
+		}
+		content = string(data)
+		uploaded = true
+	}
+	if strings.TrimSpace(content) == "" {
+		return "", nil, mapping, errors.New("choose a CSV file to import")
+	}
+
+	table, err := services.ReadCSVImport([]byte(content))
+	if err != nil {
+		return "", nil, mapping, err
+	}
+	if uploaded {
+		return content, table, services.GuessCSVMapping(table.Header), nil
+	}
+
+	column := func(field string) int {
+		i, err := strconv.Atoi(c.PostForm(field))
+		if err != nil {
+			return -1
+		}
+		return i
+	}
+	mapping = services.CSVImportMapping{
+		Name:          column("name"),
+		Tags:          column("tags"),
+		Priority:      column("priority"),
+		DueDate:       column("due_date"),
+		Context:       column("context"),
+		Size:          column("size"),
+		TagDelimiter:  c.PostForm("tag_delimiter"),
+		DueDateFormat: c.PostForm("due_date_format"),
+		Priorities:    make(map[string]models.TaskPriority),
+	}
+	for _, value := range mapping.PriorityValues(table) {
+		if priority := c.PostForm("priority_value:" + value); priority != "" {
+			mapping.Priorities[value] = models.TaskPriority(priority)
+		}
+	}
+	return content, table, mapping, nil
+}
+
+// ImportPreviewHandler maps the columns of an uploaded CSV file to task
+// fields and previews the tasks they make. Changing the mapping posts the
+// form again for a new preview.
+func (h *ImportHandler) ImportPreviewHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	content, table, mapping, err := readImport(c)
+	if err != nil {
+		c.Header("Content-Type", "text/html")
+		c.String(http.StatusOK, importUploadHTML(importErrorHTML(err.Error())))
+		return
+	}
+
+	rows, err := services.MapCSVRows(table, mapping)
+	errorHTML := ""
+	if err != nil {
+		errorHTML = importErrorHTML(err.Error())
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderPreview(c, content, table, mapping, rows, errorHTML))
+}
+
+func (h *ImportHandler) renderPreview(c *gin.Context, content string, table *services.CSVTable, mapping services.CSVImportMapping, rows []services.CSVImportRow, errorHTML string) string {
+	columnSelect := func(label, field string, selected int, required bool) string {
+		none := "Not imported"
+		if required {
+			none = "Choose a column"
+		}
+		options := fmt.Sprintf(`<option value="">%s</option>`, none)
+		for i, name := range table.Header {
+			if name == "" {
+				name = fmt.Sprintf("Column %d", i+1)
+			}
+			sel := ""
+			if i == selected {
+				sel = " selected"
+			}
+			options += fmt.Sprintf(`<option value="%d"%s>%s</option>`, i, sel, html.EscapeString(name))
+		}
+		return fmt.Sprintf(`
+				<label class="block text-sm font-medium text-gray-700">%s
+					<select name="%s" class="mt-1 block w-full px-3 py-2 text-sm border border-gray-300 rounded-md">%s</select>
+				</label>`, label, field, options)
+	}
+
+	formatOptions := ""
+	for _, format := range dueDateFormats {
+		sel := ""
+		if format.layout == mapping.DueDateFormat {
+			sel = " selected"
+		}
+		formatOptions += fmt.Sprintf(`<option value="%s"%s>%s</option>`, html.EscapeString(format.layout), sel, html.EscapeString(format.label))
+	}
+
+	tagDelimiter := mapping.TagDelimiter
+	if tagDelimiter == "" {
+		tagDelimiter = ","
+	}
+
+	// Priority values other than low, medium and high are mapped one by one
+	priorityHTML := ""
+	if mapping.Priority >= 0 && mapping.Priority < len(table.Header) {
+		for _, value := range mapping.PriorityValues(table) {
+			options := `<option value="">Choose a priority</option>`
+			for _, priority := range []models.TaskPriority{models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh} {
+				sel := ""
+				if mapping.Priorities[value] == priority {
+					sel = " selected"
+				}
+				options += fmt.Sprintf(`<option value="%s"%s>%s</option>`, priority, sel, priority)
+			}
+			priorityHTML += fmt.Sprintf(`
+				<label class="flex items-center gap-2 text-sm text-gray-700"><span class="w-32 truncate">%s</span>
+					<select name="priority_value:%s" class="px-3 py-1 text-sm border border-gray-300 rounded-md">%s</select>
+				</label>`, html.EscapeString(value), html.EscapeString(value), options)
+		}
+		if priorityHTML != "" {
+			priorityHTML = fmt.Sprintf(`
+			<div class="mt-4 space-y-2">
+				<p class="text-sm font-medium text-gray-700">Priority values</p>%s
+			</div>`, priorityHTML)
+		}
+	}
+
+	valid, invalid := 0, 0
+	rowsHTML := ""
+	for i, row := range rows {
+		if row.Error != "" {
+			invalid++
+		} else {
+			valid++
+		}
+		if i >= importPreviewRows {
+			continue
+		}
+		if row.Error != "" {
+			rowsHTML += fmt.Sprintf(`
+					<tr class="bg-red-50">
+						<td class="px-3 py-2 text-sm text-gray-500">%d</td>
+						<td colspan="5" class="px-3 py-2 text-sm text-red-700">%s</td>
+					</tr>`, row.Line, html.EscapeString(row.Error))
+			continue
+		}
+		rowsHTML += fmt.Sprintf(`
+					<tr>
+						<td class="px-3 py-2 text-sm text-gray-500">%d</td>
+						<td class="px-3 py-2 text-sm text-gray-900">%s</td>
+						<td class="px-3 py-2 text-sm text-gray-700">%s</td>
+						<td class="px-3 py-2 text-sm text-gray-700">%s</td>
+						<td class="px-3 py-2 text-sm text-gray-700">%s</td>
+						<td class="px-3 py-2 text-sm text-gray-700">%s</td>
+					</tr>`, row.Line, html.EscapeString(row.Task.Name), html.EscapeString(strings.Join(row.Task.Tags, ", ")),
+			html.EscapeString(string(row.Task.Priority)), html.EscapeString(row.Task.DueDate), html.EscapeString(row.Task.Context))
+	}
+
+	summaryHTML := ""
+	moreHTML := ""
+	actionsHTML := ""
+	if rows != nil {
+		summaryHTML = fmt.Sprintf(`<p class="text-sm text-gray-700">%d of %d rows are ready to import; %d have errors and are skipped.</p>`, valid, len(rows), invalid)
+		if len(rows) > importPreviewRows {
+			moreHTML = fmt.Sprintf(`<p class="p-3 text-xs text-gray-500">Showing the first %d of %d rows.</p>`, importPreviewRows, len(rows))
+		}
+		if valid > 0 {
+			actionsHTML += fmt.Sprintf(`<button type="button" hx-post="/app/import" hx-target="#main-content" class="brand-button px-4 py-2 text-sm font-medium text-white rounded-md">Import %d tasks</button>`, valid)
+		}
+		if invalid > 0 {
+			actionsHTML += `<button type="submit" class="px-4 py-2 text-sm text-blue-600 hover:text-blue-800">Download error report</button>`
+		}
+	}
+
+	// Changes re-post the form through HTMX; submitting it natively downloads
+	// the error report
+	return fmt.Sprintf(`
+	<div class="p-6">
+		<div class="mb-6 flex items-center justify-between gap-4">
+			<h2 class="text-2xl font-bold text-gray-900">Import tasks</h2>
+			<button type="button" hx-get="/app/import" hx-target="#main-content" class="text-sm text-blue-600 hover:text-blue-800">Choose another file</button>
+		</div>
+		%s
+		<form method="post" action="%s" hx-post="/app/import/preview" hx-trigger="change" hx-target="#main-content" class="space-y-4">
+			<input type="hidden" name="%s" value="%s">
+			<input type="hidden" name="csv" value="%s">
+			<div class="bg-white shadow rounded-lg p-6">
+				<h3 class="text-lg font-medium text-gray-900 mb-4">Columns</h3>
+				<div class="grid grid-cols-1 md:grid-cols-3 gap-4">%s%s%s%s%s%s
+					<label class="block text-sm font-medium text-gray-700">Tag delimiter
+						<input type="text" name="tag_delimiter" value="%s" maxlength="5" class="mt-1 block w-full px-3 py-2 text-sm border border-gray-300 rounded-md">
+					</label>
+					<label class="block text-sm font-medium text-gray-700">Due date format
+						<select name="due_date_format" class="mt-1 block w-full px-3 py-2 text-sm border border-gray-300 rounded-md">%s</select>
+					</label>
+				</div>%s
+			</div>
+			<div class="bg-white shadow rounded-lg overflow-x-auto">
+				<div class="p-4 flex flex-wrap items-center justify-between gap-4 border-b border-gray-200">
+					%s
+					<div class="flex items-center gap-2">%s</div>
+				</div>
+				<table class="min-w-full divide-y divide-gray-200">
+					<thead class="bg-gray-50">
+						<tr>
+							<th class="px-3 py-2 text-left text-xs font-medium text-gray-500 uppercase">Line</th>
+							<th class="px-3 py-2 text-left text-xs font-medium text-gray-500 uppercase">Name</th>
+							<th class="px-3 py-2 text-left text-xs font-medium text-gray-500 uppercase">Tags</th>
+							<th class="px-3 py-2 text-left text-xs font-medium text-gray-500 uppercase">Priority</th>
+							<th class="px-3 py-2 text-left text-xs font-medium text-gray-500 uppercase">Due</th>
+							<th class="px-3 py-2 text-left text-xs font-medium text-gray-500 uppercase">Context</th>
+						</tr>
+					</thead>
+					<tbody class="divide-y divide-gray-100">%s
+					</tbody>
+				</table>
+				%s
+			</div>
+		</form>
+	</div>`,
+		errorHTML,
+		appURL(c, "/app/import/errors"),
+		middleware.CSRFFormField, html.EscapeString(middleware.RequestCSRFToken(c.Request)),
+		html.EscapeString(content),
+		columnSelect("Name", "name", mapping.Name, true),
+		columnSelect("Tags", "tags", mapping.Tags, false),
+		columnSelect("Priority", "priority", mapping.Priority, false),
+		columnSelect("Due date", "due_date", mapping.DueDate, false),
+		columnSelect("Context", "context", mapping.Context, false),
+		columnSelect("Size", "size", mapping.Size, false),
+		html.EscapeString(tagDelimiter), formatOptions, priorityHTML,
+		summaryHTML, actionsHTML, rowsHTML, moreHTML,
+	)
+}
+
+// ImportTasksHandler creates the tasks of the rows that map, through the same
+// path as the bulk task API, and reports the rows that were not imported
+func (h *ImportHandler) ImportTasksHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	content, table, mapping, err := readImport(c)
+	if err != nil {
+		c.Header("Content-Type", "text/html")
+		c.String(http.StatusOK, importUploadHTML(importErrorHTML(err.Error())))
+		return
+	}
+	rows, err := services.MapCSVRows(table, mapping)
+	if err != nil {
+		c.Header("Content-Type", "text/html")
+		c.String(http.StatusOK, h.renderPreview(c, content, table, mapping, rows, importErrorHTML(err.Error())))
+		return
+	}
+
+	tasks, importErr := h.taskService.ImportCSVRows(rows)
+
+	failed := 0
+	for _, row := range rows {
+		if row.Error != "" {
+			failed++
+		}
+	}
+
+	resultHTML := ""
+	if importErr != nil {
+		resultHTML = importErrorHTML(fmt.Sprintf("The import stopped after %d tasks: %v", len(tasks), importErr))
+	}
+	resultHTML += fmt.Sprintf(`<p class="text-sm text-gray-700">Created %d tasks.`, len(tasks))
+	if failed > 0 {
+		resultHTML += fmt.Sprintf(` %d rows were not imported; download the error report to fix and import them again.`, failed)
+	}
+	resultHTML += `</p>`
+
+	tasksHTML := ""
+	for _, task := range tasks {
+		tasksHTML += fmt.Sprintf(`
+				<li class="px-4 py-2 text-sm"><a href="#" onclick="showTaskDetail(%d); return false;" class="text-gray-900 hover:text-blue-600">#%d %s</a></li>`,
+			task.ID, task.ID, html.EscapeString(task.Name))
+	}
+	if tasksHTML != "" {
+		tasksHTML = fmt.Sprintf(`
+			<ul class="mt-4 bg-white shadow rounded-lg divide-y divide-gray-100">%s
+			</ul>`, tasksHTML)
+	}
+
+	// The report carries the rows that failed to import as well as those
+	// that did not map, so it is sent with the page rather than recomputed
+	reportHTML := ""
+	if failed > 0 {
+		reportHTML = fmt.Sprintf(`
+			<form method="post" action="%s" class="mt-4">
+				<input type="hidden" name="%s" value="%s">
+				<input type="hidden" name="report" value="%s">
+				<button type="submit" class="px-4 py-2 text-sm text-blue-600 hover:text-blue-800">Download error report</button>
+			</form>`,
+			appURL(c, "/app/import/errors"),
+			middleware.CSRFFormField, html.EscapeString(middleware.RequestCSRFToken(c.Request)),
+			html.EscapeString(string(services.CSVErrorReport(table.Header, rows))))
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, fmt.Sprintf(`
+	<div class="p-6">
+		<div class="mb-6 flex items-center justify-between gap-4">
+			<h2 class="text-2xl font-bold text-gray-900">Import tasks</h2>
+			<button type="button" hx-get="/app/import" hx-target="#main-content" class="text-sm text-blue-600 hover:text-blue-800">Import another file</button>
+		</div>
+		%s%s%s
+	</div>`, resultHTML, reportHTML, tasksHTML))
+}
+
+// ImportErrorsHandler downloads the rows of an import that failed as CSV,
+// with each row's line and error, from the import result's report or by
+// mapping the file in the preview form again
+func (h *ImportHandler) ImportErrorsHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	report := []byte(c.PostForm("report"))
+	if len(report) == 0 {
+		_, table, mapping, err := readImport(c)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		rows, err := services.MapCSVRows(table, mapping)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		report = services.CSVErrorReport(table.Header, rows)
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="import-errors.csv"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", report)
+}
@@ -0,0 +1,235 @@
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// KanbanHandler handles the kanban board view
+type KanbanHandler struct {
+	taskService *services.TaskService
+	templates   map[string]*template.Template
+	hub         *KanbanHub
+}
+
+// NewKanbanHandler creates a new kanban handler
+func NewKanbanHandler(taskService *services.TaskService, templates map[string]*template.Template) *KanbanHandler {
+	return &KanbanHandler{
+		taskService: taskService,
+		templates:   templates,
+		hub:         NewKanbanHub(taskService.Events()),
+	}
+}
+
+// kanbanColumnTitles maps statuses to their column headings
+var kanbanColumnTitles = map[models.TaskStatus]string{
+	models.TaskStatusOpen:          "Open",
+	models.TaskStatusInProgress:    "In Progress",
+	models.TaskStatusPendingReview: "Pending Review",
+	models.TaskStatusResolved:      "Resolved",
+	models.TaskStatusClosed:        "Closed",
+}
+
+// KanbanPageHandler renders the kanban board into the main content area
+func (h *KanbanHandler) KanbanPageHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	allTasks, err := h.taskService.GetTasks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tasks"})
+		return
+	}
+
+	// Narrow the board with the same saved_query_id, tags and search
+	// parameters as the kanban API; WIP counts still cover every task
+	tasks := allTasks
+	var savedQueryID uint
+	if idStr := c.Query("saved_query_id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved query ID"})
+			return
+		}
+		query, err := h.taskService.GetSavedQueryByID(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Saved query not found"})
+			return
+		}
+		if tasks, err = h.taskService.GetTasksBySavedQuery(query); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tasks"})
+			return
+		}
+		savedQueryID = query.ID
+	}
+	tagsParam := strings.TrimSpace(c.Query("tags"))
+	search := strings.TrimSpace(c.Query("search"))
+	var taskSearch *services.TaskSearch
+	if search != "" {
+		if taskSearch, err = h.taskService.NewTaskSearch(search, nil); err != nil {
+			if errors.Is(err, services.ErrInvalidSearch) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search tasks"})
+			return
+		}
+	}
+	tasks = filterKanbanTasks(tasks, tagsParam, taskSearch)
+
+	columns := make(map[models.TaskStatus][]*models.Task)
+	for _, task := range tasks {
+		columns[task.Status] = append(columns[task.Status], task)
+	}
+	wip := h.taskService.WIPColumns(allTasks)
+
+	// The board reloads itself with the same filters as tasks change, see KanbanSocketHandler
+	refresh := url.Values{}
+	if savedQueryID != 0 {
+		refresh.Set("saved_query_id", strconv.FormatUint(uint64(savedQueryID), 10))
+	}
+	if tagsParam != "" {
+		refresh.Set("tags", tagsParam)
+	}
+	if search != "" {
+		refresh.Set("search", search)
+	}
+	refreshURL := "/app/kanban"
+	if len(refresh) > 0 {
+		refreshURL += "?" + refresh.Encode()
+	}
+
+	boardHTML := `
+	<div id="kanban-board" class="p-6 h-full flex flex-col" data-refresh-url="` + html.EscapeString(refreshURL) + `">
+		<div class="flex items-center justify-between mb-6">
+			<h2 class="text-2xl font-bold text-gray-900">Kanban</h2>` +
+		h.renderFilters(savedQueryID, tagsParam, search) + `
+		</div>
+		<div class="flex-1 grid grid-cols-5 gap-4 min-h-0">`
+
+	for _, status := range services.KanbanStatuses {
+		boardHTML += h.renderColumn(status, columns[status], wip[status], savedQueryID != 0 || tagsParam != "" || search != "")
+	}
+
+	boardHTML += `
+		</div>
+	</div>`
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, boardHTML)
+}
+
+// renderFilters renders the saved query, tag and search filters, reloading the board on change
+func (h *KanbanHandler) renderFilters(savedQueryID uint, tags, search string) string {
+	options := `<option value="">All tasks</option>`
+	if queries, err := h.taskService.GetSavedQueries(); err == nil {
+		for _, query := range queries {
+			selected := ""
+			if query.ID == savedQueryID {
+				selected = " selected"
+			}
+			options += fmt.Sprintf(`<option value="%d"%s>%s</option>`, query.ID, selected, html.EscapeString(query.Name))
+		}
+	}
+
+	return fmt.Sprintf(`
+			<form class="flex items-center gap-2" hx-get="/app/kanban" hx-target="#main-content" hx-trigger="change, submit">
+				<select name="saved_query_id" class="text-sm border-gray-300 rounded-md">%s</select>
+				<input type="text" name="tags" value="%s" placeholder="Tags" class="text-sm border-gray-300 rounded-md w-32">
+				<input type="search" name="search" value="%s" placeholder="Search" class="text-sm border-gray-300 rounded-md w-40">
+			</form>`, options, html.EscapeString(tags), html.EscapeString(search))
+}
+
+// filterKanbanTasks keeps the tasks with any of the comma separated tags that
+// match search, ignoring empty filters
+func filterKanbanTasks(tasks []*models.Task, tagsParam string, search *services.TaskSearch) []*models.Task {
+	var tags []string
+	for _, tag := range strings.Split(tagsParam, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	var filtered []*models.Task
+	for _, task := range tasks {
+		if len(tags) > 0 && !slices.ContainsFunc(task.Tags, func(tag string) bool { return slices.Contains(tags, tag) }) {
+			continue
+		}
+		if search != nil && !search.Matches(task) {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+	return filtered
+}
+
+// renderColumn renders a single kanban column, highlighting it when its WIP limit is exceeded.
+// A filtered column shows how many of the column's tasks match.
+func (h *KanbanHandler) renderColumn(status models.TaskStatus, tasks []*models.Task, wip services.WIPColumn, filtered bool) string {
+	columnClass := "bg-gray-100"
+	countClass := "bg-gray-200 text-gray-700"
+	countText := fmt.Sprintf("%d", wip.Count)
+	if wip.Limit > 0 {
+		countText = fmt.Sprintf("%d / %d", wip.Count, wip.Limit)
+		if wip.Exceeded {
+			columnClass = "bg-red-50 ring-2 ring-red-400"
+			countClass = "bg-red-100 text-red-800"
+		} else if wip.Count == wip.Limit {
+			countClass = "bg-yellow-100 text-yellow-800"
+		}
+	}
+	if filtered {
+		countText = fmt.Sprintf("%d of %s", len(tasks), countText)
+	}
+
+	columnHTML := fmt.Sprintf(`
+			<div class="%s rounded-lg flex flex-col min-h-0" data-status="%s">
+				<div class="p-3 flex items-center justify-between">
+					<h3 class="text-sm font-semibold text-gray-700">%s</h3>
+					<span class="text-xs font-medium px-2 py-0.5 rounded-full %s" title="Tasks / WIP limit">%s</span>
+				</div>
+				<div class="flex-1 overflow-auto custom-scrollbar px-3 pb-3 space-y-2">`,
+		columnClass, status, kanbanColumnTitles[status], countClass, countText)
+
+	for _, task := range tasks {
+		// Aging tasks get an amber border and show how long they have been stuck
+		cardClass := "border-gray-200"
+		ageHTML := ""
+		if h.taskService.IsAging(task) {
+			cardClass = "border-amber-400 border-l-4"
+			ageHTML = fmt.Sprintf(`<span class="ml-2 text-amber-700" title="Days in %s">%dd</span>`, html.EscapeString(string(task.Status)), task.StatusAgeDays)
+		}
+
+		columnHTML += fmt.Sprintf(`
+					<div class="bg-white rounded-md border %s p-3 cursor-pointer hover:shadow-md transition-shadow"
+						 data-kanban-task-id="%d" onclick="showTaskDetail(%d)">
+						<p class="text-sm font-medium text-gray-900">%s</p>
+						<p class="mt-1 text-xs text-gray-500">#%d %s%s<span class="kanban-comment-badge hidden ml-2 text-blue-600" title="New comment">&#128172;</span></p>
+					</div>`, cardClass, task.ID, task.ID, html.EscapeString(task.Name), task.ID, html.EscapeString(string(task.Priority)), ageHTML)
+	}
+
+	if len(tasks) == 0 {
+		columnHTML += `
+					<p class="text-xs text-gray-400 text-center py-4">No tasks</p>`
+	}
+
+	columnHTML += `
+				</div>
+			</div>`
+
+	return columnHTML
+}
@@ -0,0 +1,166 @@
+package frontend
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+	"golang.org/x/net/websocket"
+)
+
+// kanbanSocketLifetime is how long a board's WebSocket stays open before it
+// is closed, so the browser reconnects and its session is checked again
+const kanbanSocketLifetime = 10 * time.Minute
+
+// kanbanSocketBuffer is how many events a board may fall behind by before
+// it misses some
+const kanbanSocketBuffer = 32
+
+// KanbanHub sends task events to the kanban boards open in browsers over
+// WebSocket, so every session sees cards move and comments arrive as they
+// happen. It subscribes to the task event bus while any board is connected.
+type KanbanHub struct {
+	events *services.EventBus
+
+	mu      sync.Mutex
+	clients map[chan services.TaskEvent]struct{}
+	cancel  func() // ends the event bus subscription, nil while no board is connected
+}
+
+// NewKanbanHub creates a hub relaying the events of an event bus
+func NewKanbanHub(events *services.EventBus) *KanbanHub {
+	return &KanbanHub{
+		events:  events,
+		clients: make(map[chan services.TaskEvent]struct{}),
+	}
+}
+
+// register adds a board, subscribing to the event bus for the first one
+func (h *KanbanHub) register() chan services.TaskEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client := make(chan services.TaskEvent, kanbanSocketBuffer)
+	h.clients[client] = struct{}{}
+	if h.cancel == nil {
+		events, _, cancel := h.events.Subscribe(0)
+		done := make(chan struct{})
+		h.cancel = func() {
+			cancel()
+			close(done)
+		}
+		go h.run(events, done)
+	}
+	return client
+}
+
+// unregister removes a board, ending the subscription after the last one
+func (h *KanbanHub) unregister(client chan services.TaskEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.clients, client)
+	if len(h.clients) == 0 && h.cancel != nil {
+		h.cancel()
+		h.cancel = nil
+	}
+}
+
+// run relays events to the connected boards until done is closed. A board
+// that falls behind misses events rather than holding up the others.
+func (h *KanbanHub) run(events <-chan services.TaskEvent, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case event := <-events:
+			h.mu.Lock()
+			for client := range h.clients {
+				select {
+				case client <- event:
+				default:
+				}
+			}
+			h.mu.Unlock()
+		}
+	}
+}
+
+// serve sends events to a board's WebSocket until the browser disconnects
+// or the deadline passes
+func (h *KanbanHub) serve(ws *websocket.Conn, deadline time.Time) {
+	defer ws.Close()
+
+	client := h.register()
+	defer h.unregister(client)
+
+	// The browser sends nothing; reading only notices it going away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var message string
+		for websocket.Message.Receive(ws, &message) == nil {
+		}
+	}()
+
+	expired := time.NewTimer(time.Until(deadline))
+	defer expired.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-expired.C:
+			return
+		case event := <-client:
+			if err := websocket.JSON.Send(ws, event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// KanbanSocketHandler upgrades to a WebSocket streaming task events as JSON,
+// for a user allowed to read tasks. The connection closes after
+// kanbanSocketLifetime, or sooner when the session expires, and is only
+// accepted from pages served by this host.
+func (h *KanbanHandler) KanbanSocketHandler(c *gin.Context) {
+	authContext, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	auth, ok := authContext.(*models.AuthContext)
+	if !ok || !auth.HasPermission(models.PermissionReadTasks) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	deadline := time.Now().Add(kanbanSocketLifetime)
+	if auth.Session != nil && auth.Session.ExpiresAt.Before(deadline) {
+		deadline = auth.Session.ExpiresAt
+	}
+
+	server := websocket.Server{
+		Handshake: checkSocketOrigin,
+		Handler: func(ws *websocket.Conn) {
+			h.hub.serve(ws, deadline)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkSocketOrigin refuses WebSocket connections opened by pages of other
+// sites, which would otherwise ride on the user's session cookie
+func checkSocketOrigin(config *websocket.Config, r *http.Request) error {
+	origin, err := url.Parse(r.Header.Get("Origin"))
+	if err != nil || origin.Host != r.Host {
+		return fmt.Errorf("origin %q not allowed", r.Header.Get("Origin"))
+	}
+	config.Origin = origin
+	return nil
+}
@@ -0,0 +1,96 @@
+package frontend
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+	"github.com/soarinferret/jats/internal/services"
+	"golang.org/x/net/websocket"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestKanbanSocket(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Task{}, &models.Subtask{}, &models.TimeEntry{}, &models.Comment{}, &models.TaskStatusChange{}, &models.Project{}); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	taskService := services.NewTaskService(repository.NewTaskRepository(db), nil)
+	handler := NewKanbanHandler(taskService, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		permissions := []string{models.PermissionReadTasks}
+		if c.Query("as") == "writer" {
+			permissions = []string{models.PermissionWriteTasks}
+		}
+		c.Set("auth", &models.AuthContext{User: &models.User{Username: "alice"}, Permissions: permissions, AuthMethod: "session"})
+		c.Next()
+	})
+	router.GET("/app/kanban/ws", handler.KanbanSocketHandler)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	socketURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/app/kanban/ws"
+
+	resp, err := http.Get(server.URL + "/app/kanban/ws?as=writer")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 without permission to read tasks, got %d", resp.StatusCode)
+	}
+
+	if _, err := websocket.Dial(socketURL, "", "https://evil.example.com"); err == nil {
+		t.Error("Expected a connection from another site's page to be refused")
+	}
+
+	// Two boards, as in two browser sessions, both see the change
+	var boards []*websocket.Conn
+	for range 2 {
+		ws, err := websocket.Dial(socketURL, "", server.URL)
+		if err != nil {
+			t.Fatalf("Dial failed: %v", err)
+		}
+		defer ws.Close()
+		boards = append(boards, ws)
+	}
+
+	// The hub subscribes once the handler runs, after the handshake
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		handler.hub.mu.Lock()
+		connected := len(handler.hub.clients)
+		handler.hub.mu.Unlock()
+		if connected == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	task, err := taskService.CreateTask("Move me")
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	for i, ws := range boards {
+		ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var event services.TaskEvent
+		if err := websocket.JSON.Receive(ws, &event); err != nil {
+			t.Fatalf("Board %d: failed to receive event: %v", i, err)
+		}
+		if event.Type != services.TaskEventCreated || event.TaskID != task.ID {
+			t.Errorf("Board %d: unexpected event %+v", i, event)
+		}
+	}
+}
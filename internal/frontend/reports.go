@@ -2,14 +2,21 @@ package frontend
 
 import (
 	"fmt"
+	"html"
 	"html/template"
+	"math"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-echarts/go-echarts/v2/charts"
 	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/render"
+	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/services"
 )
@@ -35,8 +42,16 @@ type ReportData struct {
 	OpenTasks         int
 	CompletedTasks    int
 	TotalTimeSpent    float64 // hours
+	CompletedTrend    int     // completed in the last 7 days minus the 7 days before
+	TimeSpentTrend    float64 // hours, last 7 days minus the 7 days before
 	TimeSpentChart    template.HTML
+	ChartView         string // one of the timeChartViews
 	Last7Days         []string
+	Capacity          *services.CapacityPlan
+	Milestones        []*services.MilestoneProgress
+	Burndown          *services.MilestoneBurndown
+	Velocity          *services.Velocity
+	Sizes             *services.SizeBreakdown
 }
 
 // ReportPageHandler renders the main report page
@@ -65,7 +80,7 @@ func (h *ReportHandler) ReportPageHandler(c *gin.Context) {
 	}
 
 	// Generate report data
-	reportData, err := h.generateReportData(selectedQuery)
+	reportData, err := h.generateReportData(selectedQuery, c.Query("chart"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate report data"})
 		return
@@ -74,6 +89,51 @@ func (h *ReportHandler) ReportPageHandler(c *gin.Context) {
 	reportData.SavedQueries = savedQueries
 	reportData.SelectedQuery = selectedQuery
 
+	// Compare open work with the signed-in user's weekly capacity
+	capacity := 0
+	if authContext, exists := c.Get("auth"); exists {
+		if auth, ok := authContext.(*models.AuthContext); ok && auth.User != nil {
+			capacity = auth.User.WeeklyCapacity
+		}
+	}
+	reportData.Capacity, err = h.taskService.GetCapacityPlan(capacity, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate capacity plan"})
+		return
+	}
+
+	// Story points completed per week, and open work by size, of the selected query
+	reportData.Velocity, err = h.taskService.GetVelocity(selectedQuery, velocityWeeks, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate velocity report"})
+		return
+	}
+	reportData.Sizes, err = h.taskService.GetSizeBreakdown(selectedQuery)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate size breakdown"})
+		return
+	}
+
+	// Milestone progress, with a burndown for the selected milestone
+	milestones, err := h.taskService.GetMilestones()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get milestones"})
+		return
+	}
+	for _, milestone := range milestones {
+		progress, err := h.taskService.GetMilestoneProgress(milestone.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get milestone progress"})
+			return
+		}
+		reportData.Milestones = append(reportData.Milestones, progress)
+	}
+	if milestoneIDStr := c.Query("milestone"); milestoneIDStr != "" {
+		if milestoneID, err := strconv.ParseUint(milestoneIDStr, 10, 32); err == nil {
+			reportData.Burndown, _ = h.taskService.GetMilestoneBurndown(uint(milestoneID), time.Now())
+		}
+	}
+
 	// Always render just the content area for main app integration
 	h.renderReportContentForApp(c, reportData)
 }
@@ -103,7 +163,7 @@ func (h *ReportHandler) renderReportHTML(c *gin.Context, data *ReportData) {
 					return "border-blue-500 text-blue-700 bg-blue-50" 
 				}
 				return "border-transparent text-gray-600" 
-			}(), query.Name)
+			}(), html.EscapeString(query.Name))
 	}
 
 	if len(data.SavedQueries) == 0 {
@@ -217,6 +277,7 @@ func (h *ReportHandler) renderReportHTML(c *gin.Context, data *ReportData) {
                             <p class="text-xs text-gray-500 truncate">user@example.com</p>
                         </div>
                         <form method="POST" action="/logout" class="ml-3">
+                            <input type="hidden" name="csrf_token" value="%s">
                             <button type="submit" class="text-gray-400 hover:text-gray-600 p-1 rounded" title="Logout">
                                 <svg class="h-5 w-5" fill="none" viewBox="0 0 24 24" stroke="currentColor">
                                     <path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M17 16l4-4m0 0l-4-4m4 4H7m6 4v1a3 3 0 01-3 3H6a3 3 0 01-3-3V7a3 3 0 013-3h4a3 3 0 013 3v1" />
@@ -253,8 +314,14 @@ func (h *ReportHandler) renderReportHTML(c *gin.Context, data *ReportData) {
 			return "border-transparent text-gray-600" 
 		}(),
 		navItems,
+		middleware.RequestCSRFToken(c.Request),
 		h.renderReportContentHTML(data))
 
+	// This standalone page does not load app.html's HTMX base path hook
+	if basePath := middleware.BasePath(c.Request); basePath != "" {
+		html = strings.ReplaceAll(html, `="/`, `="`+basePath+`/`)
+	}
+
 	c.Header("Content-Type", "text/html")
 	c.String(http.StatusOK, html)
 }
@@ -270,7 +337,7 @@ func (h *ReportHandler) renderReportContent(c *gin.Context, data *ReportData) {
 func (h *ReportHandler) renderReportContentForApp(c *gin.Context, data *ReportData) {
 	queryName := "All Tasks"
 	if data.SelectedQuery != nil {
-		queryName = data.SelectedQuery.Name
+		queryName = html.EscapeString(data.SelectedQuery.Name)
 	}
 
 	content := fmt.Sprintf(`
@@ -313,7 +380,7 @@ func (h *ReportHandler) renderReportContentForApp(c *gin.Context, data *ReportDa
                     <div class="ml-5 w-0 flex-1">
                         <dl>
                             <dt class="text-sm font-medium text-gray-500 truncate">Completed (7d)</dt>
-                            <dd class="text-lg font-medium text-gray-900">%d</dd>
+                            <dd class="text-lg font-medium text-gray-900">%d %s</dd>
                         </dl>
                     </div>
                 </div>
@@ -332,7 +399,7 @@ func (h *ReportHandler) renderReportContentForApp(c *gin.Context, data *ReportDa
                     <div class="ml-5 w-0 flex-1">
                         <dl>
                             <dt class="text-sm font-medium text-gray-500 truncate">Time Spent (7d)</dt>
-                            <dd class="text-lg font-medium text-gray-900">%.1f hrs</dd>
+                            <dd class="text-lg font-medium text-gray-900">%.1f hrs %s</dd>
                         </dl>
                     </div>
                 </div>
@@ -340,16 +407,29 @@ func (h *ReportHandler) renderReportContentForApp(c *gin.Context, data *ReportDa
         </div>
     </div>
 
+    %s
+
+    %s
+
+    %s
+
     <!-- Chart Section -->
     <div class="bg-white shadow rounded-lg">
         <div class="px-4 py-5 sm:p-6">
-            <h3 class="text-lg leading-6 font-medium text-gray-900 mb-4">Daily Time Tracking</h3>
+            <div class="flex items-center justify-between mb-4">
+                <h3 class="text-lg leading-6 font-medium text-gray-900">Daily Time Tracking</h3>
+                %s
+            </div>
             <div class="mt-2">
                 %s
             </div>
         </div>
     </div>
-</div>`, queryName, data.OpenTasks, data.CompletedTasks, data.TotalTimeSpent, data.TimeSpentChart)
+</div>`, queryName, data.OpenTasks,
+		data.CompletedTasks, renderTrendBadge(float64(data.CompletedTrend), fmt.Sprintf("%d", absInt(data.CompletedTrend))),
+		data.TotalTimeSpent, renderTrendBadge(data.TimeSpentTrend, fmt.Sprintf("%.1f hrs", math.Abs(data.TimeSpentTrend))),
+		renderCapacityCard(data.Capacity), renderMilestonesSection(data.Milestones, data.Burndown),
+		renderVelocitySection(data.Velocity, data.Sizes), renderChartViewSwitcher(data), data.TimeSpentChart)
 
 	c.Header("Content-Type", "text/html")
 	c.String(http.StatusOK, content)
@@ -359,7 +439,7 @@ func (h *ReportHandler) renderReportContentForApp(c *gin.Context, data *ReportDa
 func (h *ReportHandler) renderReportContentHTML(data *ReportData) string {
 	queryName := "All Tasks"
 	if data.SelectedQuery != nil {
-		queryName = data.SelectedQuery.Name
+		queryName = html.EscapeString(data.SelectedQuery.Name)
 	}
 
 	return fmt.Sprintf(`
@@ -447,8 +527,9 @@ func (h *ReportHandler) renderReportContentHTML(data *ReportData) string {
 </div>`, queryName, data.OpenTasks, data.CompletedTasks, data.TotalTimeSpent, data.TimeSpentChart)
 }
 
-// generateReportData calculates report metrics and generates charts
-func (h *ReportHandler) generateReportData(savedQuery *models.SavedQuery) (*ReportData, error) {
+// generateReportData calculates report metrics and generates charts; chartView
+// selects how the daily time chart is drawn and defaults to bars
+func (h *ReportHandler) generateReportData(savedQuery *models.SavedQuery, chartView string) (*ReportData, error) {
 	// Get all tasks or filtered tasks
 	var tasks []*models.Task
 	var err error
@@ -463,17 +544,21 @@ func (h *ReportHandler) generateReportData(savedQuery *models.SavedQuery) (*Repo
 		return nil, err
 	}
 
-	// Calculate date range for last 7 days
+	// Calculate date range for last 7 days, and the 7 days before for trends
 	now := time.Now()
 	sevenDaysAgo := now.AddDate(0, 0, -7)
+	fourteenDaysAgo := now.AddDate(0, 0, -14)
+	prevCompletedTasks := 0
+	prevTimeSpent := 0.0
 
 	// Calculate metrics
 	openTasks := 0
 	completedTasks := 0
 	totalTimeSpent := 0.0
 
-	// Group time entries by day
+	// Group time entries by day, and by task for the stacked view
 	dailyTime := make(map[string]float64)
+	taskTime := make(map[string]map[string]float64)
 	last7Days := make([]string, 7)
 	
 	// Initialize last 7 days
@@ -494,10 +579,16 @@ func (h *ReportHandler) generateReportData(savedQuery *models.SavedQuery) (*Repo
 		if (task.Status == models.TaskStatusResolved || task.Status == models.TaskStatusClosed) && 
 		   task.ResolvedAt != nil && task.ResolvedAt.After(sevenDaysAgo) {
 			completedTasks++
+		} else if (task.Status == models.TaskStatusResolved || task.Status == models.TaskStatusClosed) &&
+			task.ResolvedAt != nil && task.ResolvedAt.After(fourteenDaysAgo) {
+			prevCompletedTasks++
 		}
 
 		// Sum time entries for last 7 days
 		for _, timeEntry := range task.TimeEntries {
+			if !timeEntry.CreatedAt.After(sevenDaysAgo) && timeEntry.CreatedAt.After(fourteenDaysAgo) {
+				prevTimeSpent += float64(timeEntry.Duration) / 60.0
+			}
 			if timeEntry.CreatedAt.After(sevenDaysAgo) {
 				hours := float64(timeEntry.Duration) / 60.0
 				totalTimeSpent += hours
@@ -505,37 +596,65 @@ func (h *ReportHandler) generateReportData(savedQuery *models.SavedQuery) (*Repo
 				// Add to daily breakdown
 				dateStr := timeEntry.CreatedAt.Format("2006-01-02")
 				dailyTime[dateStr] += hours
+
+				taskLabel := fmt.Sprintf("#%d %s", task.ID, task.Name)
+				if taskTime[taskLabel] == nil {
+					taskTime[taskLabel] = make(map[string]float64)
+				}
+				taskTime[taskLabel][dateStr] += hours
 			}
 		}
 	}
 
+	if !isTimeChartView(chartView) {
+		chartView = timeChartBar
+	}
+
 	// Generate chart
-	chartHTML := h.generateTimeSpentChart(last7Days, dailyTime)
+	chartHTML := h.generateTimeSpentChart(chartView, last7Days, dailyTime, taskTime)
 
 	return &ReportData{
 		OpenTasks:      openTasks,
 		CompletedTasks: completedTasks,
 		TotalTimeSpent: totalTimeSpent,
+		CompletedTrend: completedTasks - prevCompletedTasks,
+		TimeSpentTrend: totalTimeSpent - prevTimeSpent,
 		TimeSpentChart: template.HTML(chartHTML),
+		ChartView:      chartView,
 		Last7Days:      last7Days,
 	}, nil
 }
 
-// generateTimeSpentChart creates a bar chart for daily time spent
-func (h *ReportHandler) generateTimeSpentChart(days []string, dailyTime map[string]float64) string {
-	// Create bar chart
-	bar := charts.NewBar()
-	
-	// Set global options
-	bar.SetGlobalOptions(charts.WithTitleOpts(opts.Title{
-		Title: "Daily Time Spent",
-		Subtitle: "Hours tracked over the last 7 days",
-	}))
+// Views of the daily time chart
+const (
+	timeChartBar     = "bar"
+	timeChartLine    = "line"
+	timeChartStacked = "stacked"
+)
+
+var timeChartViews = []struct{ view, label string }{
+	{timeChartBar, "Bar"},
+	{timeChartLine, "Line"},
+	{timeChartStacked, "Stacked by task"},
+}
 
-	// Prepare data
+// stackedChartTasks is how many tasks get their own series in the stacked view;
+// the rest are summed as "Other"
+const stackedChartTasks = 5
+
+func isTimeChartView(view string) bool {
+	for _, v := range timeChartViews {
+		if v.view == view {
+			return true
+		}
+	}
+	return false
+}
+
+// generateTimeSpentChart renders an interactive go-echarts chart of the hours
+// logged per day, with tooltips and zooming
+func (h *ReportHandler) generateTimeSpentChart(view string, days []string, dailyTime map[string]float64, taskTime map[string]map[string]float64) string {
 	xAxis := make([]string, len(days))
-	yData := make([]opts.BarData, len(days))
-	
 	for i, day := range days {
 		// Format day for display (Mon 01/02)
 		if date, err := time.Parse("2006-01-02", day); err == nil {
@@ -543,57 +662,422 @@ func (h *ReportHandler) generateTimeSpentChart(days []string, dailyTime map[stri
 		} else {
 			xAxis[i] = day
 		}
-		
-		hours := dailyTime[day]
-		yData[i] = opts.BarData{Value: hours}
 	}
 
-	// Add data to chart
-	bar.SetXAxis(xAxis).
-		AddSeries("Hours", yData).
-		SetSeriesOptions(charts.WithBarChartOpts(opts.BarChart{
-			XAxisIndex: 0,
-			YAxisIndex: 0,
-		}))
+	globalOpts := []charts.GlobalOpts{
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+		charts.WithDataZoomOpts(opts.DataZoom{Type: "inside"}, opts.DataZoom{Type: "slider"}),
+		charts.WithYAxisOpts(opts.YAxis{Name: "Hours"}),
+	}
 
-	// Generate HTML
-	bar.Validate()
-	
-	// Since we can't easily get the HTML output, we'll create a simple HTML chart
-	// This is a fallback implementation
-	maxHours := 0.0
-	for _, hours := range dailyTime {
-		if hours > maxHours {
-			maxHours = hours
+	var chartID string
+	var snippet render.ChartSnippet
+	switch view {
+	case timeChartLine:
+		line := charts.NewLine()
+		line.SetGlobalOptions(globalOpts...)
+		data := make([]opts.LineData, len(days))
+		for i, day := range days {
+			data[i] = opts.LineData{Value: roundHours(dailyTime[day])}
+		}
+		line.SetXAxis(xAxis).
+			AddSeries("Hours", data).
+			SetSeriesOptions(
+				charts.WithLineChartOpts(opts.LineChart{Smooth: opts.Bool(true)}),
+				charts.WithAreaStyleOpts(opts.AreaStyle{Opacity: opts.Float(0.2)}),
+			)
+		snippet = line.RenderSnippet()
+		chartID = line.ChartID
+
+	case timeChartStacked:
+		bar := charts.NewBar()
+		bar.SetGlobalOptions(append(globalOpts, charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true), Type: "scroll"}))...)
+		bar.SetXAxis(xAxis)
+		for _, series := range stackedTaskSeries(days, taskTime) {
+			data := make([]opts.BarData, len(days))
+			for i, hours := range series.hours {
+				data[i] = opts.BarData{Value: roundHours(hours)}
+			}
+			bar.AddSeries(series.name, data, charts.WithBarChartOpts(opts.BarChart{Stack: "tasks"}))
+		}
+		snippet = bar.RenderSnippet()
+		chartID = bar.ChartID
+
+	default:
+		bar := charts.NewBar()
+		bar.SetGlobalOptions(globalOpts...)
+		data := make([]opts.BarData, len(days))
+		for i, day := range days {
+			data[i] = opts.BarData{Value: roundHours(dailyTime[day])}
 		}
+		bar.SetXAxis(xAxis).AddSeries("Hours", data)
+		snippet = bar.RenderSnippet()
+		chartID = bar.ChartID
 	}
-	
-	chartBars := ""
-	for _, day := range days {
-		// Format day for display
-		displayDay := day
-		if date, err := time.Parse("2006-01-02", day); err == nil {
-			displayDay = date.Format("Mon 01/02")
+
+	return renderEcharts(chartID, snippet)
+}
+
+// renderEcharts renders the chart element and initializes it from the snippet's
+// option JSON. The snippet's own element uses a "container" class that clashes
+// with Tailwind, and its script embeds task names unescaped, so neither is used.
+func renderEcharts(id string, snippet render.ChartSnippet) string {
+	option := strings.TrimSuffix(strings.TrimSpace(snippet.Option), ";")
+	option = strings.NewReplacer("<", `\u003c`, ">", `\u003e`, "&", `\u0026`).Replace(option)
+
+	return fmt.Sprintf(`
+<div id="%s" style="width: 100%%; height: 320px;"></div>
+<script type="text/javascript">
+    (function () {
+        var el = document.getElementById(%q);
+        if (!el || typeof echarts === "undefined") { return; }
+        var chart = echarts.init(el);
+        chart.setOption(%s);
+        window.addEventListener("resize", function () { chart.resize(); });
+    })();
+</script>`, html.EscapeString(id), id, option)
+}
+
+type taskSeries struct {
+	name  string
+	hours []float64
+}
+
+// stackedTaskSeries returns one series per task with the most time, plus "Other"
+func stackedTaskSeries(days []string, taskTime map[string]map[string]float64) []taskSeries {
+	type taskTotal struct {
+		name  string
+		total float64
+	}
+	var totals []taskTotal
+	for name, daily := range taskTime {
+		total := 0.0
+		for _, hours := range daily {
+			total += hours
+		}
+		totals = append(totals, taskTotal{name, total})
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].total != totals[j].total {
+			return totals[i].total > totals[j].total
+		}
+		return totals[i].name < totals[j].name
+	})
+
+	var series []taskSeries
+	other := taskSeries{name: "Other", hours: make([]float64, len(days))}
+	for i, total := range totals {
+		hours := make([]float64, len(days))
+		for d, day := range days {
+			hours[d] = taskTime[total.name][day]
+		}
+		if i < stackedChartTasks {
+			series = append(series, taskSeries{name: total.name, hours: hours})
+			continue
 		}
-		
-		hours := dailyTime[day]
-		heightPercent := 0.0
-		if maxHours > 0 {
-			heightPercent = (hours / maxHours) * 100
+		for d := range hours {
+			other.hours[d] += hours[d]
 		}
-		
-		chartBars += fmt.Sprintf(`
-			<div class="flex flex-col items-center">
-				<div class="h-32 w-12 bg-gray-100 rounded flex items-end justify-center relative">
-					<div class="bg-blue-500 w-10 rounded-sm" style="height: %.1f%%" title="%.1f hours"></div>
-				</div>
-				<div class="mt-2 text-xs text-gray-600">%s</div>
-				<div class="text-xs font-medium text-gray-900">%.1fh</div>
-			</div>`, heightPercent, hours, displayDay, hours)
 	}
-	
+	if len(totals) > stackedChartTasks {
+		series = append(series, other)
+	}
+	if len(series) == 0 {
+		series = append(series, taskSeries{name: "Hours", hours: make([]float64, len(days))})
+	}
+	return series
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+// renderTrendBadge shows the change against the previous 7 days as an up or down
+// arrow with the amount; nothing is shown when there is no change
+func renderTrendBadge(delta float64, amount string) string {
+	switch {
+	case delta > 0.05:
+		return fmt.Sprintf(`<span class="ml-1 text-xs font-medium text-green-600" title="vs. previous 7 days">&#9650; %s</span>`, amount)
+	case delta < -0.05:
+		return fmt.Sprintf(`<span class="ml-1 text-xs font-medium text-red-600" title="vs. previous 7 days">&#9660; %s</span>`, amount)
+	}
+	return ""
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// renderChartViewSwitcher links to the same report with a different chart view
+func renderChartViewSwitcher(data *ReportData) string {
+	links := ""
+	for _, v := range timeChartViews {
+		query := url.Values{}
+		if data.SelectedQuery != nil {
+			query.Set("query", strconv.FormatUint(uint64(data.SelectedQuery.ID), 10))
+		}
+		if data.Burndown != nil && data.Burndown.Milestone != nil {
+			query.Set("milestone", strconv.FormatUint(uint64(data.Burndown.Milestone.ID), 10))
+		}
+		query.Set("chart", v.view)
+
+		class := "text-gray-600 hover:bg-gray-100"
+		if v.view == data.ChartView {
+			class = "bg-blue-100 text-blue-700"
+		}
+		links += fmt.Sprintf(`<button hx-get="/app/reports?%s" hx-target="#main-content" class="px-3 py-1 text-sm rounded-md %s">%s</button>`,
+			html.EscapeString(query.Encode()), class, v.label)
+	}
+	return `<div class="flex space-x-1">` + links + `</div>`
+}
+
+// renderCapacityCard compares the remaining estimated work with the user's weekly
+// capacity for the coming week, with a form to change the capacity
+func renderCapacityCard(plan *services.CapacityPlan) string {
+	if plan == nil {
+		return ""
+	}
+
+	var summary, barClass string
+	percent := 0
+	switch {
+	case plan.CapacityMinutes == 0:
+		summary = fmt.Sprintf("%s of estimated work left on open tasks. Set a weekly capacity to check for overcommitment.",
+			formatMinutes(plan.RemainingMinutes))
+	case plan.Overcommitted:
+		summary = fmt.Sprintf("Overcommitted by %s: %s of estimated work against %s of capacity.",
+			formatMinutes(plan.OverByMinutes), formatMinutes(plan.RemainingMinutes), formatMinutes(plan.CapacityMinutes))
+		barClass = "bg-red-500"
+		percent = 100
+	default:
+		summary = fmt.Sprintf("%s of estimated work against %s of capacity.",
+			formatMinutes(plan.RemainingMinutes), formatMinutes(plan.CapacityMinutes))
+		barClass = "bg-green-500"
+		percent = int(plan.Utilization * 100)
+	}
+
+	summaryClass := "text-gray-600"
+	if plan.Overcommitted {
+		summaryClass = "text-red-600 font-medium"
+	}
+
+	bar := ""
+	if plan.CapacityMinutes > 0 {
+		bar = fmt.Sprintf(`<div class="mt-3 h-2 w-full bg-gray-200 rounded-full overflow-hidden"><div class="h-2 %s" style="width: %d%%"></div></div>`,
+			barClass, percent)
+	}
+
+	unestimated := ""
+	if plan.UnestimatedTasks > 0 {
+		unestimated = fmt.Sprintf(`<p class="mt-2 text-xs text-gray-500">%d open tasks have no estimate and are not counted.</p>`, plan.UnestimatedTasks)
+	}
+
+	capacityValue := ""
+	if plan.CapacityMinutes > 0 {
+		capacityValue = formatMinutes(plan.CapacityMinutes)
+	}
+
 	return fmt.Sprintf(`
-		<div class="flex justify-between items-end space-x-2 px-4">
-			%s
-		</div>`, chartBars)
-}
\ No newline at end of file
+    <!-- Capacity Section -->
+    <div class="bg-white shadow rounded-lg mb-8">
+        <div class="px-4 py-5 sm:p-6">
+            <div class="flex items-center justify-between">
+                <h3 class="text-lg leading-6 font-medium text-gray-900">Capacity (%s – %s)</h3>
+                <form class="flex items-center space-x-2" onsubmit="saveWeeklyCapacity(event, this)">
+                    <label for="weekly-capacity" class="text-sm text-gray-500">Weekly capacity</label>
+                    <input id="weekly-capacity" name="weekly_capacity" type="text" value="%s" placeholder="30h"
+                           class="w-20 px-2 py-1 border border-gray-300 rounded-md text-sm">
+                    <button type="submit" class="px-3 py-1 text-sm bg-blue-600 text-white rounded-md hover:bg-blue-700">Save</button>
+                </form>
+            </div>
+            <p class="mt-2 text-sm %s">%s</p>
+            %s
+            %s
+        </div>
+    </div>`, plan.WeekStart, plan.WeekEnd, html.EscapeString(capacityValue), summaryClass, summary, bar, unestimated)
+}
+
+// renderMilestonesSection lists milestone progress and, when one is selected,
+// its burndown chart
+func renderMilestonesSection(milestones []*services.MilestoneProgress, burndown *services.MilestoneBurndown) string {
+	if len(milestones) == 0 {
+		return ""
+	}
+
+	rows := ""
+	for _, progress := range milestones {
+		due := "No due date"
+		if progress.Milestone.DueDate != nil {
+			due = "Due " + progress.Milestone.DueDate.Format("Jan 2, 2006")
+		}
+		dueClass := "text-gray-500"
+		if progress.Overdue {
+			dueClass = "text-red-600 font-medium"
+			due += " (overdue)"
+		}
+
+		rows += fmt.Sprintf(`
+            <a href="#" hx-get="/app/reports?milestone=%d" hx-target="#main-content"
+               class="block py-3 hover:bg-gray-50 px-2 rounded">
+                <div class="flex items-center justify-between text-sm">
+                    <span class="font-medium text-gray-900">%s</span>
+                    <span class="%s">%s</span>
+                </div>
+                <div class="mt-2 h-2 w-full bg-gray-200 rounded-full overflow-hidden">
+                    <div class="h-2 bg-blue-500" style="width: %.0f%%"></div>
+                </div>
+                <div class="mt-1 text-xs text-gray-500">%d/%d resolved · %s logged</div>
+            </a>`, progress.Milestone.ID, html.EscapeString(progress.Milestone.Name), dueClass, due,
+			progress.Percent, progress.ResolvedTasks, progress.TotalTasks, formatMinutes(progress.LoggedMinutes))
+	}
+
+	chart := ""
+	if burndown != nil {
+		chart = fmt.Sprintf(`
+            <div class="mt-6">
+                <h4 class="text-sm font-medium text-gray-900 mb-2">Burndown: %s</h4>
+                %s
+            </div>`, html.EscapeString(burndown.Milestone.Name), renderBurndownChart(burndown))
+	}
+
+	return fmt.Sprintf(`
+    <!-- Milestones Section -->
+    <div class="bg-white shadow rounded-lg mb-8">
+        <div class="px-4 py-5 sm:p-6">
+            <h3 class="text-lg leading-6 font-medium text-gray-900 mb-2">Milestones</h3>
+            <div class="divide-y divide-gray-100">%s</div>
+            %s
+        </div>
+    </div>`, rows, chart)
+}
+
+// renderBurndownChart draws remaining tasks per day as an SVG line, with the
+// ideal line to the due date dashed
+func renderBurndownChart(burndown *services.MilestoneBurndown) string {
+	const width, height = 600.0, 160.0
+	points := burndown.Points
+	if len(points) == 0 {
+		return `<p class="text-sm text-gray-500">No tasks on this milestone yet.</p>`
+	}
+
+	maxValue := 1.0
+	for _, point := range points {
+		if point.Remaining != nil && float64(*point.Remaining) > maxValue {
+			maxValue = float64(*point.Remaining)
+		}
+		if point.Ideal != nil && *point.Ideal > maxValue {
+			maxValue = *point.Ideal
+		}
+	}
+
+	step := width
+	if len(points) > 1 {
+		step = width / float64(len(points)-1)
+	}
+	y := func(value float64) float64 {
+		return height - value/maxValue*height
+	}
+
+	remaining, ideal := "", ""
+	for i, point := range points {
+		x := float64(i) * step
+		if point.Remaining != nil {
+			remaining += fmt.Sprintf("%.1f,%.1f ", x, y(float64(*point.Remaining)))
+		}
+		if point.Ideal != nil {
+			ideal += fmt.Sprintf("%.1f,%.1f ", x, y(*point.Ideal))
+		}
+	}
+
+	return fmt.Sprintf(`
+                <svg viewBox="-4 -4 %.0f %.0f" class="w-full h-40" preserveAspectRatio="none">
+                    <polyline points="%s" fill="none" stroke="#9ca3af" stroke-width="1.5" stroke-dasharray="6 4"/>
+                    <polyline points="%s" fill="none" stroke="#3b82f6" stroke-width="2.5"/>
+                </svg>
+                <div class="flex justify-between text-xs text-gray-500 mt-1">
+                    <span>%s</span>
+                    <span>%s</span>
+                </div>`, width+8, height+8, ideal, remaining, points[0].Date, points[len(points)-1].Date)
+}
+
+// velocityWeeks is how many weeks the velocity chart shows, the current one included
+const velocityWeeks = 8
+
+// renderVelocitySection charts the story points completed per week and breaks
+// the open work down by size. It is left out until some task is sized.
+func renderVelocitySection(velocity *services.Velocity, sizes *services.SizeBreakdown) string {
+	if velocity == nil || sizes == nil {
+		return ""
+	}
+	completedPoints := 0
+	for _, week := range velocity.Weeks {
+		completedPoints += week.Points
+	}
+	if completedPoints == 0 && len(sizes.Sizes) == 0 {
+		return ""
+	}
+
+	xAxis := make([]string, len(velocity.Weeks))
+	data := make([]opts.BarData, len(velocity.Weeks))
+	for i, week := range velocity.Weeks {
+		xAxis[i] = week.WeekStart
+		if start, err := time.Parse("2006-01-02", week.WeekStart); err == nil {
+			xAxis[i] = start.Format("Jan 2")
+		}
+		data[i] = opts.BarData{Value: week.Points}
+	}
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+		charts.WithYAxisOpts(opts.YAxis{Name: "Points"}),
+	)
+	bar.SetXAxis(xAxis).AddSeries("Completed points", data)
+
+	rows := ""
+	for _, size := range sizes.Sizes {
+		rows += fmt.Sprintf(`
+                    <tr>
+                        <td class="py-1 pr-4 font-medium text-gray-900">%s</td>
+                        <td class="py-1 pr-4 text-right">%d</td>
+                        <td class="py-1 text-right">%d</td>
+                    </tr>`, html.EscapeString(string(size.Size)), size.Tasks, size.Points)
+	}
+	if sizes.UnsizedTasks > 0 {
+		rows += fmt.Sprintf(`
+                    <tr class="text-gray-500">
+                        <td class="py-1 pr-4">Unsized</td>
+                        <td class="py-1 pr-4 text-right">%d</td>
+                        <td class="py-1 text-right">–</td>
+                    </tr>`, sizes.UnsizedTasks)
+	}
+
+	return fmt.Sprintf(`
+    <!-- Velocity Section -->
+    <div class="bg-white shadow rounded-lg mb-8">
+        <div class="px-4 py-5 sm:p-6 grid grid-cols-1 gap-6 lg:grid-cols-3">
+            <div class="lg:col-span-2">
+                <h3 class="text-lg leading-6 font-medium text-gray-900">Velocity</h3>
+                <p class="text-sm text-gray-500">Story points completed per week; %.1f a week on average over the last %d full weeks</p>
+                %s
+            </div>
+            <div>
+                <h3 class="text-lg leading-6 font-medium text-gray-900">Open work by size</h3>
+                <p class="text-sm text-gray-500">%d points open</p>
+                <table class="mt-2 w-full text-sm text-gray-700">
+                    <thead>
+                        <tr class="text-left text-gray-500">
+                            <th class="py-1 pr-4 font-medium">Size</th>
+                            <th class="py-1 pr-4 font-medium text-right">Tasks</th>
+                            <th class="py-1 font-medium text-right">Points</th>
+                        </tr>
+                    </thead>
+                    <tbody>%s
+                    </tbody>
+                </table>
+            </div>
+        </div>
+    </div>`, velocity.AveragePoints, len(velocity.Weeks)-1, renderEcharts(bar.ChartID, bar.RenderSnippet()), sizes.OpenPoints, rows)
+}
@@ -1,13 +1,17 @@
 package frontend
 
 import (
+	"errors"
 	"fmt"
+	"html"
 	"html/template"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/services"
 )
@@ -15,13 +19,15 @@ import (
 // SavedQueryHandler handles saved query-related frontend requests
 type SavedQueryHandler struct {
 	taskService *services.TaskService
+	authService *services.AuthService
 	templates   map[string]*template.Template
 }
 
 // NewSavedQueryHandler creates a new saved query handler
-func NewSavedQueryHandler(taskService *services.TaskService, templates map[string]*template.Template) *SavedQueryHandler {
+func NewSavedQueryHandler(taskService *services.TaskService, authService *services.AuthService, templates map[string]*template.Template) *SavedQueryHandler {
 	return &SavedQueryHandler{
 		taskService: taskService,
+		authService: authService,
 		templates:   templates,
 	}
 }
@@ -33,6 +39,16 @@ func (h *SavedQueryHandler) SavedQueriesListHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get saved queries"})
 		return
 	}
+	if authContext, exists := c.Get("auth"); exists {
+		if auth, ok := authContext.(*models.AuthContext); ok && auth.User != nil {
+			queries = services.PinSavedQueries(queries, auth.User.PinnedQueryIDs)
+		}
+	}
+
+	// Work-in-progress badges; the list still renders without them
+	if err := h.taskService.CountBySavedQueries(queries); err != nil {
+		log.Printf("Failed to count saved query tasks: %v", err)
+	}
 
 	// Check context to determine link targets
 	context := c.PostForm("context")
@@ -61,6 +77,44 @@ func (h *SavedQueryHandler) SavedQueriesListHandler(c *gin.Context) {
 			onclickAction = fmt.Sprintf("setActiveTaskView(this, 'query-%d')", query.ID)
 		}
 
+		feedLinkHTML := fmt.Sprintf(`
+		<button type="button"
+				title="Atom feed of recent changes"
+				onclick="event.stopPropagation(); window.open('%s/api/v1/feeds/saved-queries/%s.atom', '_blank')"
+				class="opacity-0 group-hover:opacity-100 text-gray-400 hover:text-orange-500 p-1 rounded">
+			<svg class="h-2 w-2" fill="none" viewBox="0 0 24 24" stroke="currentColor">
+				<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M6 5c7.18 0 13 5.82 13 13M6 11a7 7 0 017 7m-6 0a1 1 0 11-2 0 1 1 0 012 0z" />
+			</svg>
+		</button>`, middleware.BasePath(c.Request), html.EscapeString(query.FeedToken))
+
+		landingView := services.LandingViewTasks
+		if context == "reports" {
+			landingView = services.LandingViewReports
+		}
+		pinClass, pinTitle := "opacity-0 group-hover:opacity-100 text-gray-400 hover:text-blue-600", "Pin to the top"
+		if query.Pinned {
+			pinClass, pinTitle = "text-blue-600 hover:text-gray-400", "Unpin"
+		}
+		actionsHTML := fmt.Sprintf(`
+			<button type="button"
+					title="%s"
+					hx-post="/app/saved-queries/%d/pin"
+					hx-swap="none"
+					onclick="event.stopPropagation()"
+					class="%s p-1 rounded">
+				<svg class="h-2 w-2" fill="none" viewBox="0 0 24 24" stroke="currentColor">
+					<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M5 5a2 2 0 012-2h10a2 2 0 012 2v16l-7-3.5L5 21V5z" />
+				</svg>
+			</button>
+			<button type="button"
+					title="Open on this query"
+					onclick="event.stopPropagation(); setLandingView('%s', %d)"
+					class="opacity-0 group-hover:opacity-100 text-gray-400 hover:text-green-600 p-1 rounded">
+				<svg class="h-2 w-2" fill="none" viewBox="0 0 24 24" stroke="currentColor">
+					<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M3 12l2-2m0 0l7-7 7 7M5 10v10a1 1 0 001 1h3m10-11l2 2m-2-2v10a1 1 0 01-1 1h-3m-6 0a1 1 0 001-1v-4a1 1 0 011-1h2a1 1 0 011 1v4a1 1 0 001 1m-6 0h6" />
+				</svg>
+			</button>`, pinTitle, query.ID, pinClass, landingView, query.ID)
+
 		queriesHTML += fmt.Sprintf(`
 		<a href="#"
 		   hx-get="%s"
@@ -71,7 +125,7 @@ func (h *SavedQueryHandler) SavedQueriesListHandler(c *gin.Context) {
 			<svg class="mr-2 h-3 w-3" fill="none" viewBox="0 0 24 24" stroke="currentColor">
 				<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="%s" />
 			</svg>
-			<span class="flex-1 truncate">%s</span>
+			<span class="flex-1 truncate">%s</span>%s%s%s
 			<button hx-delete="/api/v1/saved-queries/%d"
 					hx-target="closest .task-view-item"
 					hx-swap="outerHTML"
@@ -82,7 +136,7 @@ func (h *SavedQueryHandler) SavedQueriesListHandler(c *gin.Context) {
 					<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M19 7l-.867 12.142A2 2 0 0116.138 21H7.862a2 2 0 01-1.995-1.858L5 7m5 4v6m4-6v6m1-10V4a1 1 0 00-1-1h-4a1 1 0 00-1 1v3M4 7h16" />
 				</svg>
 			</button>
-		</a>`, linkURL, linkTarget, onclickAction, iconPath, query.Name, query.ID)
+		</a>`, linkURL, linkTarget, onclickAction, iconPath, html.EscapeString(query.Name), savedQueryBadgeHTML(query), actionsHTML, feedLinkHTML, query.ID)
 	}
 
 	if len(queries) == 0 {
@@ -93,6 +147,24 @@ func (h *SavedQueryHandler) SavedQueriesListHandler(c *gin.Context) {
 	c.String(http.StatusOK, queriesHTML)
 }
 
+// savedQueryBadgeHTML renders a saved query's open task count, with the tasks
+// in progress and the time logged this week in its tooltip
+func savedQueryBadgeHTML(query *models.SavedQuery) string {
+	if query.OpenCount == nil || query.InProgressCount == nil || query.WeekMinutes == nil {
+		return ""
+	}
+	title := fmt.Sprintf("%d open, %d in progress, %s logged this week",
+		*query.OpenCount-*query.InProgressCount, *query.InProgressCount, formatMinutes(*query.WeekMinutes))
+
+	badgeHTML := fmt.Sprintf(`
+			<span class="ml-1 inline-flex items-center gap-1 text-gray-500" title="%s">`, title)
+	if *query.InProgressCount > 0 {
+		badgeHTML += `<span class="h-1.5 w-1.5 rounded-full bg-blue-500" aria-hidden="true"></span>`
+	}
+	badgeHTML += fmt.Sprintf(`<span class="rounded-full bg-gray-100 px-1.5">%d</span></span>`, *query.OpenCount)
+	return badgeHTML
+}
+
 // NewSavedQueryFormHandler shows the new saved query form modal
 func (h *SavedQueryHandler) NewSavedQueryFormHandler(c *gin.Context) {
 	formHTML := `
@@ -133,6 +205,17 @@ func (h *SavedQueryHandler) NewSavedQueryFormHandler(c *gin.Context) {
 							   placeholder="e.g., archived, completed (comma-separated)">
 						<p class="mt-1 text-xs text-gray-500">Tasks with any of these tags will be filtered out</p>
 					</div>
+
+					<div>
+						<label for="expression" class="block text-sm font-medium text-gray-700">Tag Expression</label>
+						<input type="text" name="expression" id="expression"
+							   class="mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500"
+							   placeholder="e.g., (client1 OR client2) AND NOT internal">
+						<p class="mt-1 text-xs text-gray-500">Tasks must also match this; combine tags with AND, OR, NOT and parentheses, or build it below</p>
+						<div id="expression-builder" class="mt-2 space-y-2"></div>
+						<button type="button" onclick="addExpressionGroup('expression-builder', 'expression')"
+								class="mt-1 text-xs text-blue-600 hover:text-blue-800">+ Add tag group</button>
+					</div>
 				</div>
 
 				<div class="mt-6 flex items-center justify-end space-x-3">
@@ -165,6 +248,7 @@ func (h *SavedQueryHandler) CreateSavedQueryHandler(c *gin.Context) {
 	name := strings.TrimSpace(c.PostForm("name"))
 	includedTagsStr := strings.TrimSpace(c.PostForm("included_tags"))
 	excludedTagsStr := strings.TrimSpace(c.PostForm("excluded_tags"))
+	expression := strings.TrimSpace(c.PostForm("expression"))
 
 	// Validate required fields
 	if name == "" {
@@ -198,9 +282,14 @@ func (h *SavedQueryHandler) CreateSavedQueryHandler(c *gin.Context) {
 		Name:         name,
 		IncludedTags: includedTags,
 		ExcludedTags: excludedTags,
+		Expression:   expression,
 	}
 
 	_, err := h.taskService.CreateSavedQuery(query)
+	if errors.Is(err, services.ErrInvalidTagExpression) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create saved query"})
 		return
@@ -210,6 +299,35 @@ func (h *SavedQueryHandler) CreateSavedQueryHandler(c *gin.Context) {
 	h.SavedQueriesListHandler(c)
 }
 
+// PinSavedQueryHandler pins a saved query to the top of the sidebar for the
+// signed-in user, or unpins it, then has both sidebar lists reload
+func (h *SavedQueryHandler) PinSavedQueryHandler(c *gin.Context) {
+	authContext, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	auth := authContext.(*models.AuthContext)
+
+	queryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query ID"})
+		return
+	}
+	if err := h.taskService.ValidateSavedQueryIDs([]uint{uint(queryID)}); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved query not found"})
+		return
+	}
+
+	if _, err := h.authService.TogglePinnedQuery(auth.User.ID, uint(queryID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to pin saved query"})
+		return
+	}
+
+	c.Header("HX-Trigger", "savedQueriesChanged")
+	c.Status(http.StatusNoContent)
+}
+
 // SavedQueryTasksHandler shows tasks for a specific saved query
 func (h *SavedQueryHandler) SavedQueryTasksHandler(c *gin.Context, taskHandler *TaskHandler) {
 	_, exists := c.Get("auth")
@@ -246,4 +364,25 @@ func (h *SavedQueryHandler) SavedQueryTasksHandler(c *gin.Context, taskHandler *
 
 	// Reuse the existing TaskListHandler logic but with saved query applied
 	taskHandler.TaskListHandler(c)
+}
+
+// OverdueTasksHandler lists the tasks past their due date as a virtual saved
+// query, longest overdue first
+func (h *SavedQueryHandler) OverdueTasksHandler(c *gin.Context, taskHandler *TaskHandler) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	overdueTasks, err := h.taskService.GetOverdueTasks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tasks"})
+		return
+	}
+
+	c.Set("savedQuery", &models.SavedQuery{Name: localizerFor(c).T("nav_overdue")})
+	c.Set("savedQueryTasks", overdueTasks)
+	c.Set("taskListURL", "/app/tasks/overdue")
+	taskHandler.TaskListHandler(c)
 }
\ No newline at end of file
@@ -2,13 +2,17 @@ package frontend
 
 import (
 	"fmt"
+	"html"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+	"github.com/soarinferret/jats/internal/utils"
 )
 
 // TaskDetailHandler serves the task detail panel
@@ -71,10 +75,10 @@ func (h *TaskHandler) TaskDetailHandler(c *gin.Context) {
 							%s
 						</span>
 						<span>Total Time: %dh %dm</span>
-						%s
+						%s%s
 					</div>`,
-		task.Name,
-		string(task.Status),
+		html.EscapeString(task.Name),
+		html.EscapeString(string(task.Status)),
 		hours,
 		minutes,
 		func() string {
@@ -87,15 +91,54 @@ func (h *TaskHandler) TaskDetailHandler(c *gin.Context) {
 				</span>`
 			}
 			return ""
+		}(),
+		func() string {
+			if task.Context != "" {
+				return fmt.Sprintf(`<span class="inline-flex items-center px-2 py-1 rounded-full text-xs bg-purple-100 text-purple-800">%s</span>`, html.EscapeString(task.Context))
+			}
+			return ""
 		}())
 
 	if task.Description != "" {
-		detailHTML += fmt.Sprintf(`<p class="mt-3 text-sm text-gray-600">%s</p>`, task.Description)
+		detailHTML += fmt.Sprintf(`<p class="mt-3 text-sm text-gray-600">%s</p>`, linkTaskReferences(task.Description))
+	}
+
+	detailHTML += renderTaskLinks(task)
+	if assets, err := h.taskService.GetTaskAssets(task.ID); err == nil {
+		detailHTML += renderTaskAssets(assets)
 	}
+	detailHTML += renderTimeBreakdown(task)
+	detailHTML += h.renderReviewControls(c, task, taskIDStr)
+	detailHTML += h.renderMuteControls(c, task, taskIDStr)
+	detailHTML += h.renderScheduledActions(task, taskIDStr)
 
 	detailHTML += `
 				</div>
-				<div class="flex items-center space-x-2">
+				<div class="flex items-center space-x-2">`
+
+	if isEmailThread && canReadEmails(c) {
+		detailHTML += `
+					<button hx-get="/app/tasks/` + taskIDStr + `/emails"
+							hx-target="#timeline-content-` + taskIDStr + `"
+							hx-swap="innerHTML"
+							class="text-blue-600 hover:text-blue-800 p-2 rounded-md hover:bg-blue-50"
+							title="Emails">
+						<svg class="w-5 h-5" fill="none" viewBox="0 0 24 24" stroke="currentColor">
+							<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M3 8l7.89 4.26a2 2 0 002.22 0L21 8M5 19h14a2 2 0 002-2V7a2 2 0 00-2-2H5a2 2 0 00-2 2v10a2 2 0 002 2z" />
+						</svg>
+					</button>`
+	}
+
+	detailHTML += `
+					<a href="` + appURL(c, "/app/tasks/"+taskIDStr+"/print") + `" target="_blank"
+							class="text-gray-600 hover:text-gray-800 p-2 rounded-md hover:bg-gray-50"
+							title="Print Job Sheet">
+						<svg class="w-5 h-5" fill="none" viewBox="0 0 24 24" stroke="currentColor">
+							<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M17 17h2a2 2 0 002-2v-4a2 2 0 00-2-2H5a2 2 0 00-2 2v4a2 2 0 002 2h2m2 4h6a2 2 0 002-2v-4a2 2 0 00-2-2H9a2 2 0 00-2 2v4a2 2 0 002 2zm8-12V5a2 2 0 00-2-2H9a2 2 0 00-2 2v4h10z" />
+						</svg>
+					</a>`
+
+	detailHTML += `
 					<button hx-get="/app/tasks/` + taskIDStr + `/edit" 
 							hx-target="#task-edit-modal" 
 							hx-trigger="click"
@@ -120,166 +163,7 @@ func (h *TaskHandler) TaskDetailHandler(c *gin.Context) {
 			<h4 class="text-md font-medium text-gray-900 mb-4">Timeline</h4>
 			<div id="timeline-content-` + taskIDStr + `" class="space-y-4">`
 
-	// Combine and sort timeline items
-	type TimelineItem struct {
-		Type      string
-		Time      time.Time
-		Content   string
-		IsPrivate bool
-	}
-
-	var timeline []TimelineItem
-
-	// Add time entries
-	for _, entry := range timeEntries {
-		content := fmt.Sprintf(`
-		<div class="flex items-start space-x-3">
-			<div class="flex-shrink-0">
-				<div class="w-8 h-8 bg-blue-100 rounded-full flex items-center justify-center">
-					<svg class="w-4 h-4 text-blue-600" fill="none" viewBox="0 0 24 24" stroke="currentColor">
-						<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M12 8v4l3 3m6-3a9 9 0 11-18 0 9 9 0 0118 0z" />
-					</svg>
-				</div>
-			</div>
-			<div class="flex-1 min-w-0">
-				<p class="text-sm font-medium text-gray-900">Time logged: %d minutes</p>
-				%s
-				<p class="text-xs text-gray-500 mt-1">%s</p>
-			</div>
-		</div>`, entry.Duration,
-			func() string {
-				if entry.Description != "" {
-					return fmt.Sprintf(`<p class="text-sm text-gray-600 mt-1">%s</p>`, entry.Description)
-				}
-				return ""
-			}(),
-			entry.CreatedAt.Format("Jan 2, 2006 at 3:04 PM"))
-
-		timeline = append(timeline, TimelineItem{
-			Type:    "time",
-			Time:    entry.CreatedAt,
-			Content: content,
-		})
-	}
-
-	// Add comments
-	for _, comment := range comments {
-		iconClass := "bg-gray-100"
-		iconSvg := `<svg class="w-4 h-4 text-gray-600" fill="none" viewBox="0 0 24 24" stroke="currentColor">
-			<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M8 12h.01M12 12h.01M16 12h.01M21 12c0 4.418-3.582 8-8 8a8.955 8.955 0 01-4.126-.98L3 21l1.98-5.874A8.955 8.955 0 013 12a8 8 0 018-8 8 8 0 018 8z" />
-		</svg>`
-
-		// All comments are now internal notes - use consistent styling
-		iconClass = "bg-blue-100"
-		iconSvg = `<svg class="w-4 h-4 text-blue-600" fill="none" viewBox="0 0 24 24" stroke="currentColor">
-			<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M8 12h.01M12 12h.01M16 12h.01M21 12c0 4.418-3.582 8-8 8a8.955 8.955 0 01-4.126-.98L3 21l1.98-5.874A8.955 8.955 0 013 12a8 8 0 018-8 8 8 0 018 8z" />
-		</svg>`
-		privateLabel := ""
-
-		fromLabel := ""
-		if comment.FromEmail != "" {
-			fromLabel = fmt.Sprintf(" from %s", comment.FromEmail)
-		}
-
-		attachmentHTML := ""
-		if len(comment.Attachments) > 0 {
-			attachmentHTML = `<div class="mt-2 space-y-2">`
-			for _, attachment := range comment.Attachments {
-				fileIcon := "📎"
-				if strings.HasPrefix(attachment.ContentType, "image/") {
-					fileIcon = "🖼️"
-				} else if strings.Contains(attachment.ContentType, "pdf") {
-					fileIcon = "📄"
-				}
-				attachmentHTML += fmt.Sprintf(`
-					<div class="inline-flex items-center px-3 py-1 rounded-md bg-gray-100 text-sm">
-						<span class="mr-1">%s</span>
-						<a href="/app/attachments/%d" target="_blank" class="text-blue-600 hover:text-blue-800">%s</a>
-					</div>`, fileIcon, attachment.ID, attachment.OriginalName)
-			}
-			attachmentHTML += `</div>`
-		}
-
-		content := fmt.Sprintf(`
-		<div class="flex items-start space-x-3">
-			<div class="flex-shrink-0">
-				<div class="w-8 h-8 %s rounded-full flex items-center justify-center">
-					%s
-				</div>
-			</div>
-			<div class="flex-1 min-w-0">
-				<div class="flex items-center">
-					<p class="text-sm font-medium text-gray-900">Note%s</p>
-					%s
-				</div>
-				<div class="mt-1 text-sm text-gray-700 whitespace-pre-wrap">%s</div>
-				%s
-				<p class="text-xs text-gray-500 mt-2">%s</p>
-			</div>
-		</div>`, iconClass, iconSvg, fromLabel, privateLabel, comment.Content, attachmentHTML, comment.CreatedAt.Format("Jan 2, 2006 at 3:04 PM"))
-
-		timeline = append(timeline, TimelineItem{
-			Type:      "comment",
-			Time:      comment.CreatedAt,
-			Content:   content,
-			IsPrivate: comment.IsPrivate,
-		})
-	}
-
-	// Add task attachments (not linked to comments)
-	for _, attachment := range attachments {
-		fileIcon := "📎"
-		if strings.HasPrefix(attachment.ContentType, "image/") {
-			fileIcon = "🖼️"
-		} else if strings.Contains(attachment.ContentType, "pdf") {
-			fileIcon = "📄"
-		}
-
-		content := fmt.Sprintf(`
-		<div class="flex items-start space-x-3">
-			<div class="flex-shrink-0">
-				<div class="w-8 h-8 bg-green-100 rounded-full flex items-center justify-center">
-					<svg class="w-4 h-4 text-green-600" fill="none" viewBox="0 0 24 24" stroke="currentColor">
-						<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M15.172 7l-6.586 6.586a2 2 0 102.828 2.828l6.414-6.586a4 4 0 00-5.656-5.656l-6.415 6.585a6 6 0 108.486 8.486L20.5 13" />
-					</svg>
-				</div>
-			</div>
-			<div class="flex-1 min-w-0">
-				<p class="text-sm font-medium text-gray-900">Attachment added</p>
-				<div class="mt-1">
-					<div class="inline-flex items-center px-3 py-1 rounded-md bg-gray-100 text-sm">
-						<span class="mr-1">%s</span>
-						<a href="/app/attachments/%d" target="_blank" class="text-blue-600 hover:text-blue-800">%s</a>
-					</div>
-				</div>
-				<p class="text-xs text-gray-500 mt-2">%s</p>
-			</div>
-		</div>`, fileIcon, attachment.ID, attachment.OriginalName, attachment.CreatedAt.Format("Jan 2, 2006 at 3:04 PM"))
-
-		timeline = append(timeline, TimelineItem{
-			Type:    "attachment",
-			Time:    attachment.CreatedAt,
-			Content: content,
-		})
-	}
-
-	// Sort timeline by time (newest first)
-	for i := 0; i < len(timeline); i++ {
-		for j := i + 1; j < len(timeline); j++ {
-			if timeline[j].Time.After(timeline[i].Time) {
-				timeline[i], timeline[j] = timeline[j], timeline[i]
-			}
-		}
-	}
-
-	// Add timeline items to HTML
-	if len(timeline) == 0 {
-		detailHTML += `<p class="text-sm text-gray-500">No activity yet.</p>`
-	} else {
-		for _, item := range timeline {
-			detailHTML += `<div class="border-l-2 border-gray-200 pl-4 pb-4">` + item.Content + `</div>`
-		}
-	}
+	detailHTML += h.generateTimelineHTML(c, timeEntries, comments, attachments)
 
 	detailHTML += `
 			</div>
@@ -299,11 +183,12 @@ func (h *TaskHandler) TaskDetailHandler(c *gin.Context) {
 					<div>
 						<label for="comment-content-` + taskIDStr + `" class="sr-only">Add an internal note</label>
 						<textarea name="content" id="comment-content-` + taskIDStr + `" rows="3" required
-								  placeholder="Add an internal note... (Ctrl+Enter to submit)"
+								  placeholder="Add an internal note... (Ctrl+Enter to submit, paste screenshots to attach them)"
 								  onkeydown="handleCommentKeydown(event, this.form)"
+								  onpaste="pasteCommentImage(event, this, ` + taskIDStr + `)"
 								  class="w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500"></textarea>
 					</div>
-					<input type="hidden" name="is_private" value="true">
+					<input type="hidden" name="is_private" value="true">` + h.renderCannedResponsePicker(task, "comment-content-"+taskIDStr) + `
 					<div class="flex justify-between items-center">
 						<div id="submit-indicator-` + taskIDStr + `" class="htmx-indicator text-sm text-gray-600">
 							<svg class="animate-spin -ml-1 mr-2 h-4 w-4 text-gray-600 inline" xmlns="http://www.w3.org/2000/svg" fill="none" viewBox="0 0 24 24">
@@ -330,6 +215,9 @@ func (h *TaskHandler) TaskDetailHandler(c *gin.Context) {
 				</div>
 			</form>
 		</div>`
+		if isEmailThread && h.taskService.CanSendEmailUpdates() {
+			detailHTML += h.renderEmailUpdateForm(task, taskIDStr)
+		}
 	} else {
 		// Show message for closed/resolved tasks
 		detailHTML += `
@@ -340,7 +228,7 @@ func (h *TaskHandler) TaskDetailHandler(c *gin.Context) {
 					<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M12 15v2m-6 4h12a2 2 0 002-2v-6a2 2 0 00-2-2H6a2 2 0 00-2 2v6a2 2 0 002 2zm10-10V7a4 4 0 00-8 0v4h8z" />
 				</svg>
 				<p class="mt-2 text-sm font-medium text-gray-900">Notes disabled</p>
-				<p class="text-sm text-gray-500">Cannot add notes to ` + string(task.Status) + ` tasks</p>
+				<p class="text-sm text-gray-500">Cannot add notes to ` + html.EscapeString(string(task.Status)) + ` tasks</p>
 			</div>
 		</div>`
 	}
@@ -352,6 +240,34 @@ func (h *TaskHandler) TaskDetailHandler(c *gin.Context) {
 	c.String(http.StatusOK, detailHTML)
 }
 
+// renderCannedResponsePicker renders a select that inserts a canned response,
+// with its placeholders already filled in for this task, into the given textarea
+func (h *TaskHandler) renderCannedResponsePicker(task *models.Task, textareaID string) string {
+	responses, err := h.settingsService.ListCannedResponses()
+	if err != nil || len(responses) == 0 {
+		return ""
+	}
+
+	pickerHTML := `
+					<div>
+						<label for="canned-` + textareaID + `" class="sr-only">Insert canned response</label>
+						<select id="canned-` + textareaID + `"
+								onchange="insertCannedResponse(this, '` + textareaID + `')"
+								class="w-full rounded-md border-gray-300 text-sm text-gray-700 shadow-sm focus:border-blue-500 focus:ring-blue-500">
+							<option value="">Insert canned response...</option>`
+	for _, response := range responses {
+		pickerHTML += fmt.Sprintf(`
+							<option value="%s">%s</option>`,
+			html.EscapeString(h.settingsService.ExpandCannedResponse(response.Content, task)),
+			html.EscapeString(response.Name))
+	}
+	pickerHTML += `
+						</select>
+					</div>`
+
+	return pickerHTML
+}
+
 // AddTaskCommentHandler handles adding comments to tasks
 func (h *TaskHandler) AddTaskCommentHandler(c *gin.Context) {
 	_, exists := c.Get("auth")
@@ -401,6 +317,9 @@ func (h *TaskHandler) AddTaskCommentHandler(c *gin.Context) {
 		Content:   content,
 		IsPrivate: isPrivate,
 	}
+	if user := currentUser(c); user != nil {
+		comment.CreatedBy = user.Username
+	}
 
 	err = h.taskService.AddComment(uint(taskID), comment)
 	if err != nil {
@@ -446,14 +365,30 @@ func (h *TaskHandler) TaskTimelineHandler(c *gin.Context) {
 	}
 
 	// Generate timeline HTML (reuse the same logic from TaskDetailHandler)
-	timelineHTML := h.generateTimelineHTML(timeEntries, comments, attachments)
+	timelineHTML := h.generateTimelineHTML(c, timeEntries, comments, attachments)
 
 	c.Header("Content-Type", "text/html")
 	c.String(http.StatusOK, timelineHTML)
 }
 
+// receiptBadge shows how far an emailed comment got: delivered, seen or failed
+func receiptBadge(receipt string) string {
+	var class string
+	switch receipt {
+	case models.EmailReceiptSeen:
+		class = "bg-green-100 text-green-800"
+	case models.EmailReceiptDelivered:
+		class = "bg-blue-100 text-blue-800"
+	case models.EmailReceiptFailed:
+		class = "bg-red-100 text-red-800"
+	default:
+		return ""
+	}
+	return fmt.Sprintf(`<span class="ml-2 px-1.5 py-0.5 text-xs rounded %s" title="Email %s">%s</span>`, class, receipt, receipt)
+}
+
 // generateTimelineHTML extracts the timeline generation logic for reuse
-func (h *TaskHandler) generateTimelineHTML(timeEntries []models.TimeEntry, comments []models.Comment, attachments []models.Attachment) string {
+func (h *TaskHandler) generateTimelineHTML(c *gin.Context, timeEntries []models.TimeEntry, comments []models.Comment, attachments []models.Attachment) string {
 	// Combine and sort timeline items
 	type TimelineItem struct {
 		Type      string
@@ -464,6 +399,15 @@ func (h *TaskHandler) generateTimelineHTML(timeEntries []models.TimeEntry, comme
 
 	var timeline []TimelineItem
 
+	// Images pasted into notes are shown inline rather than as attachments of their own
+	images := make(map[uint]models.Attachment)
+	for _, attachment := range attachments {
+		if strings.HasPrefix(attachment.ContentType, "image/") {
+			images[attachment.ID] = attachment
+		}
+	}
+	inlined := make(map[uint]bool)
+
 	// Add time entries
 	for _, entry := range timeEntries {
 		content := fmt.Sprintf(`
@@ -476,14 +420,20 @@ func (h *TaskHandler) generateTimelineHTML(timeEntries []models.TimeEntry, comme
 				</div>
 			</div>
 			<div class="flex-1 min-w-0">
-				<p class="text-sm font-medium text-gray-900">Time logged: %d minutes</p>
+				<p class="text-sm font-medium text-gray-900">Time logged: %d minutes%s</p>
 				%s
 				<p class="text-xs text-gray-500 mt-1">%s</p>
 			</div>
 		</div>`, entry.Duration,
+			func() string {
+				if !entry.IsBillable() {
+					return ` <span class="ml-1 px-1.5 py-0.5 text-xs font-normal rounded bg-gray-100 text-gray-600">non-billable</span>`
+				}
+				return ""
+			}(),
 			func() string {
 				if entry.Description != "" {
-					return fmt.Sprintf(`<p class="text-sm text-gray-600 mt-1">%s</p>`, entry.Description)
+					return fmt.Sprintf(`<p class="text-sm text-gray-600 mt-1">%s</p>`, html.EscapeString(entry.Description))
 				}
 				return ""
 			}(),
@@ -507,7 +457,7 @@ func (h *TaskHandler) generateTimelineHTML(timeEntries []models.TimeEntry, comme
 
 		fromLabel := ""
 		if comment.FromEmail != "" {
-			fromLabel = fmt.Sprintf(" from %s", comment.FromEmail)
+			fromLabel = fmt.Sprintf(" from %s", html.EscapeString(comment.FromEmail))
 		}
 
 		attachmentHTML := ""
@@ -517,14 +467,16 @@ func (h *TaskHandler) generateTimelineHTML(timeEntries []models.TimeEntry, comme
 				fileIcon := "📎"
 				if strings.HasPrefix(attachment.ContentType, "image/") {
 					fileIcon = "🖼️"
+				} else if strings.HasPrefix(attachment.ContentType, "audio/") {
+					fileIcon = "🔊"
 				} else if strings.Contains(attachment.ContentType, "pdf") {
 					fileIcon = "📄"
 				}
 				attachmentHTML += fmt.Sprintf(`
 					<div class="inline-flex items-center px-3 py-1 rounded-md bg-gray-100 text-sm">
 						<span class="mr-1">%s</span>
-						<a href="/app/attachments/%d" target="_blank" class="text-blue-600 hover:text-blue-800">%s</a>
-					</div>`, fileIcon, attachment.ID, attachment.OriginalName)
+						<a href="%s" target="_blank" class="text-blue-600 hover:text-blue-800">%s</a>
+					</div>%s`, fileIcon, appURL(c, fmt.Sprintf("/app/attachments/%d", attachment.ID)), html.EscapeString(attachment.OriginalName), audioPlayerHTML(c, attachment))
 			}
 			attachmentHTML += `</div>`
 		}
@@ -539,13 +491,13 @@ func (h *TaskHandler) generateTimelineHTML(timeEntries []models.TimeEntry, comme
 			<div class="flex-1 min-w-0">
 				<div class="flex items-center">
 					<p class="text-sm font-medium text-gray-900">Note%s</p>
-					%s
+					%s%s
 				</div>
 				<div class="mt-1 text-sm text-gray-700 whitespace-pre-wrap">%s</div>
 				%s
 				<p class="text-xs text-gray-500 mt-2">%s</p>
 			</div>
-		</div>`, iconClass, iconSvg, fromLabel, privateLabel, comment.Content, attachmentHTML, comment.CreatedAt.Format("Jan 2, 2006 at 3:04 PM"))
+		</div>`, iconClass, iconSvg, fromLabel, privateLabel, receiptBadge(comment.Receipt), renderNoteContent(c, comment.Content, images, inlined), attachmentHTML, comment.CreatedAt.Format("Jan 2, 2006 at 3:04 PM"))
 
 		timeline = append(timeline, TimelineItem{
 			Type:      "comment",
@@ -557,9 +509,14 @@ func (h *TaskHandler) generateTimelineHTML(timeEntries []models.TimeEntry, comme
 
 	// Add task attachments (not linked to comments)
 	for _, attachment := range attachments {
+		if inlined[attachment.ID] {
+			continue
+		}
 		fileIcon := "📎"
 		if strings.HasPrefix(attachment.ContentType, "image/") {
 			fileIcon = "🖼️"
+		} else if strings.HasPrefix(attachment.ContentType, "audio/") {
+			fileIcon = "🔊"
 		} else if strings.Contains(attachment.ContentType, "pdf") {
 			fileIcon = "📄"
 		}
@@ -578,12 +535,12 @@ func (h *TaskHandler) generateTimelineHTML(timeEntries []models.TimeEntry, comme
 				<div class="mt-1">
 					<div class="inline-flex items-center px-3 py-1 rounded-md bg-gray-100 text-sm">
 						<span class="mr-1">%s</span>
-						<a href="/app/attachments/%d" target="_blank" class="text-blue-600 hover:text-blue-800">%s</a>
-					</div>
+						<a href="%s" target="_blank" class="text-blue-600 hover:text-blue-800">%s</a>
+					</div>%s
 				</div>
 				<p class="text-xs text-gray-500 mt-2">%s</p>
 			</div>
-		</div>`, fileIcon, attachment.ID, attachment.OriginalName, attachment.CreatedAt.Format("Jan 2, 2006 at 3:04 PM"))
+		</div>`, fileIcon, appURL(c, fmt.Sprintf("/app/attachments/%d", attachment.ID)), html.EscapeString(attachment.OriginalName), audioPlayerHTML(c, attachment), attachment.CreatedAt.Format("Jan 2, 2006 at 3:04 PM"))
 
 		timeline = append(timeline, TimelineItem{
 			Type:    "attachment",
@@ -664,7 +621,25 @@ func (h *TaskHandler) AddTimeEntryHandler(c *gin.Context) {
 		Description: description,
 	}
 
+	// Optionally book the time against a subtask
+	if subtaskIDStr := strings.TrimSpace(c.PostForm("subtask_id")); subtaskIDStr != "" {
+		subtaskID, err := strconv.ParseUint(subtaskIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subtask ID"})
+			return
+		}
+		id := uint(subtaskID)
+		timeEntry.SubtaskID = &id
+	}
+	if user := currentUser(c); user != nil {
+		timeEntry.CreatedBy = user.Username
+	}
+
 	err = h.taskService.AddTimeEntry(uint(taskID), timeEntry)
+	if err == services.ErrSubtaskNotInTask {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Subtask does not belong to this task"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add time entry"})
 		return
@@ -672,4 +647,160 @@ func (h *TaskHandler) AddTimeEntryHandler(c *gin.Context) {
 
 	// Return success
 	c.JSON(http.StatusOK, gin.H{"success": true})
-}
\ No newline at end of file
+}
+
+// breakdownColors cycles through the segment colors of the time breakdown bar
+var breakdownColors = []string{"bg-blue-500", "bg-green-500", "bg-purple-500", "bg-yellow-500", "bg-pink-500", "bg-indigo-500"}
+
+// renderTimeBreakdown renders a stacked bar of logged time per subtask against the
+// rolled-up subtask estimate. Returns an empty string when there is nothing to show.
+// renderTaskLinks lists the tasks this one mentions, the tasks mentioning it and
+// the external issues mirroring it
+func renderTaskLinks(task *models.Task) string {
+	if len(task.References) == 0 && len(task.ReferencedBy) == 0 && len(task.ExternalIssues) == 0 &&
+		len(task.BlockedBy) == 0 && len(task.Blocks) == 0 {
+		return ""
+	}
+
+	linkList := func(label string, links []models.TaskLink) string {
+		if len(links) == 0 {
+			return ""
+		}
+		items := make([]string, 0, len(links))
+		for _, link := range links {
+			items = append(items, fmt.Sprintf(`<a href="#" onclick="showTaskDetail(%d); return false;" class="text-blue-600 hover:underline" title="%s">#%d %s</a>`,
+				link.ID, html.EscapeString(string(link.Status)), link.ID, html.EscapeString(link.Name)))
+		}
+		return fmt.Sprintf(`<p><span class="font-medium text-gray-700">%s:</span> %s</p>`, label, strings.Join(items, ", "))
+	}
+
+	return `
+					<div class="mt-3 space-y-1 text-xs text-gray-500">` +
+		linkList("Blocked by", task.BlockedBy) +
+		linkList("Blocks", task.Blocks) +
+		linkList("References", task.References) +
+		linkList("Referenced by", task.ReferencedBy) +
+		renderExternalIssues(task.ExternalIssues) + `
+					</div>`
+}
+
+// renderExternalIssues links the issues mirroring a task in external trackers
+func renderExternalIssues(issues []models.ExternalIssue) string {
+	if len(issues) == 0 {
+		return ""
+	}
+	items := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		label := html.EscapeString(issue.Target + " " + issue.Key)
+		if issue.URL == "" {
+			items = append(items, label)
+			continue
+		}
+		items = append(items, fmt.Sprintf(`<a href="%s" target="_blank" rel="noopener noreferrer" class="text-blue-600 hover:underline">%s</a>`,
+			html.EscapeString(issue.URL), label))
+	}
+	return fmt.Sprintf(`<p><span class="font-medium text-gray-700">Tracked in:</span> %s</p>`, strings.Join(items, ", "))
+}
+
+// linkTaskReferences escapes user text and turns its #ID mentions into links that
+// open the referenced task
+func linkTaskReferences(text string) string {
+	return utils.LinkTaskReferences(html.EscapeString(text), func(id uint) string {
+		return fmt.Sprintf(`<a href="#" onclick="showTaskDetail(%d); return false;" class="text-blue-600 hover:underline">#%d</a>`, id, id)
+	})
+}
+
+// inlineImageReference matches the ![name](attachment:ID) reference the
+// comment box inserts for a pasted image
+var inlineImageReference = regexp.MustCompile(`!\[([^\]]*)\]\(attachment:(\d+)\)`)
+
+// renderNoteContent renders a note like linkTaskReferences, showing references
+// to the task's image attachments as the images. The IDs of the images shown
+// are recorded in inlined.
+func renderNoteContent(c *gin.Context, text string, images map[uint]models.Attachment, inlined map[uint]bool) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range inlineImageReference.FindAllStringSubmatchIndex(text, -1) {
+		id, err := strconv.ParseUint(text[match[4]:match[5]], 10, 32)
+		if err != nil {
+			continue
+		}
+		image, ok := images[uint(id)]
+		if !ok {
+			// Not an image of this task, so the reference stays as typed
+			continue
+		}
+		b.WriteString(linkTaskReferences(text[last:match[0]]))
+		src := appURL(c, fmt.Sprintf("/app/attachments/%d", image.ID))
+		fmt.Fprintf(&b, `<a href="%s" target="_blank"><img src="%s" alt="%s" class="mt-2 max-w-full rounded border"></a>`,
+			src, src, html.EscapeString(text[match[2]:match[3]]))
+		inlined[image.ID] = true
+		last = match[1]
+	}
+	b.WriteString(linkTaskReferences(text[last:]))
+	return b.String()
+}
+
+// audioPlayerHTML returns an inline player for an audio attachment, such as
+// voicemail forwarded by email, and nothing for other attachments
+func audioPlayerHTML(c *gin.Context, attachment models.Attachment) string {
+	if !strings.HasPrefix(attachment.ContentType, "audio/") {
+		return ""
+	}
+	src := appURL(c, fmt.Sprintf("/app/attachments/%d", attachment.ID))
+	return fmt.Sprintf(`
+					<audio controls preload="metadata" src="%s" class="block mt-2 w-full max-w-md">
+						<a href="%s">Download %s</a>
+					</audio>`, src, src, html.EscapeString(attachment.OriginalName))
+}
+
+func renderTimeBreakdown(task *models.Task) string {
+	if task.EstimateMinutes == 0 && len(task.Subtasks) == 0 {
+		return ""
+	}
+
+	// The bar spans the estimate, or the logged time once it overruns
+	scale := task.EstimateMinutes
+	if task.LoggedMinutes > scale {
+		scale = task.LoggedMinutes
+	}
+	if scale == 0 {
+		return ""
+	}
+
+	var segments, legend string
+	unassigned := task.LoggedMinutes
+	for i, subtask := range task.Subtasks {
+		unassigned -= subtask.LoggedMinutes
+		if subtask.LoggedMinutes == 0 {
+			continue
+		}
+		color := breakdownColors[i%len(breakdownColors)]
+		segments += fmt.Sprintf(`<div class="%s h-full" style="width: %.2f%%" title="%s: %s"></div>`,
+			color, float64(subtask.LoggedMinutes)*100/float64(scale), html.EscapeString(subtask.Name), formatMinutes(subtask.LoggedMinutes))
+		legend += fmt.Sprintf(`<span class="inline-flex items-center mr-3"><span class="w-2 h-2 rounded-full %s mr-1"></span>%s %s</span>`,
+			color, html.EscapeString(subtask.Name), formatMinutes(subtask.LoggedMinutes))
+	}
+	if unassigned > 0 {
+		segments += fmt.Sprintf(`<div class="bg-gray-400 h-full" style="width: %.2f%%" title="Task: %s"></div>`,
+			float64(unassigned)*100/float64(scale), formatMinutes(unassigned))
+		legend += fmt.Sprintf(`<span class="inline-flex items-center mr-3"><span class="w-2 h-2 rounded-full bg-gray-400 mr-1"></span>Task %s</span>`,
+			formatMinutes(unassigned))
+	}
+
+	summaryClass := "text-gray-500"
+	if task.EstimateMinutes > 0 && task.LoggedMinutes > task.EstimateMinutes {
+		summaryClass = "text-red-600"
+	}
+
+	return fmt.Sprintf(`
+					<div class="mt-4">
+						<div class="flex justify-between text-xs %s mb-1">
+							<span>Logged %s</span>
+							<span>Estimate %s</span>
+						</div>
+						<div class="flex w-full h-2 bg-gray-200 rounded-full overflow-hidden">%s</div>
+						<div class="mt-2 text-xs text-gray-600 flex flex-wrap">%s</div>
+					</div>`,
+		summaryClass, formatMinutes(task.LoggedMinutes), formatMinutes(task.EstimateMinutes), segments, legend)
+}
@@ -0,0 +1,198 @@
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// renderEmailUpdateForm renders the collapsible form for sending an email
+// update into the thread an email-originated task came from
+func (h *TaskHandler) renderEmailUpdateForm(task *models.Task, taskIDStr string) string {
+	formHTML := `
+		<!-- Email Update Form -->
+		<details class="border-t border-gray-200 px-6 py-4 flex-shrink-0">
+			<summary class="cursor-pointer text-sm font-medium text-blue-600 hover:text-blue-800">Send email update</summary>
+			<form hx-post="/app/tasks/` + taskIDStr + `/email-update"
+				  hx-target="#timeline-content-` + taskIDStr + `"
+				  hx-swap="innerHTML"
+				  hx-on::after-request="if(event.detail.xhr.status === 200) { this.reset(); this.closest('details').open = false; }"
+				  class="mt-3 space-y-3">
+				<div>
+					<label for="email-to-` + taskIDStr + `" class="block text-xs font-medium text-gray-700">To</label>
+					<input type="text" name="to" id="email-to-` + taskIDStr + `" required
+						   value="` + html.EscapeString(strings.Join(services.EmailUpdateRecipients(task), ", ")) + `"
+						   class="w-full rounded-md border-gray-300 text-sm shadow-sm focus:border-blue-500 focus:ring-blue-500">
+				</div>
+				<div>
+					<label for="email-subject-` + taskIDStr + `" class="block text-xs font-medium text-gray-700">Subject</label>
+					<input type="text" name="subject" id="email-subject-` + taskIDStr + `"
+						   value="` + html.EscapeString(services.EmailUpdateSubject(task)) + `"
+						   class="w-full rounded-md border-gray-300 text-sm shadow-sm focus:border-blue-500 focus:ring-blue-500">
+				</div>` + h.renderCannedResponsePicker(task, "email-message-"+taskIDStr) + `
+				<div>
+					<label for="email-message-` + taskIDStr + `" class="sr-only">Message</label>
+					<textarea name="message" id="email-message-` + taskIDStr + `" rows="4" required
+							  placeholder="Message to send..."
+							  class="w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500"></textarea>
+				</div>`
+
+	if len(task.Comments) > 0 {
+		formHTML += `
+				<fieldset>
+					<legend class="text-xs font-medium text-gray-700">Quote comments</legend>`
+		for _, comment := range task.Comments {
+			excerpt := comment.Content
+			if len(excerpt) > 80 {
+				excerpt = excerpt[:80] + "..."
+			}
+			formHTML += fmt.Sprintf(`
+					<label class="flex items-start gap-2 text-sm text-gray-600">
+						<input type="checkbox" name="comment_ids" value="%d" class="mt-1 rounded border-gray-300">
+						<span>%s</span>
+					</label>`, comment.ID, html.EscapeString(excerpt))
+		}
+		formHTML += `
+				</fieldset>`
+	}
+
+	formHTML += `
+				<div class="flex justify-end">
+					<button type="submit"
+							class="px-4 py-2 bg-blue-600 text-white text-sm font-medium rounded-md hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500">
+						Send Email
+					</button>
+				</div>
+			</form>
+		</details>`
+
+	return formHTML
+}
+
+// SendEmailUpdateHandler sends an email update from the task detail form
+func (h *TaskHandler) SendEmailUpdateHandler(c *gin.Context) {
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	update := &services.EmailUpdate{
+		Subject: strings.TrimSpace(c.PostForm("subject")),
+		Message: c.PostForm("message"),
+	}
+	for _, recipient := range strings.Split(c.PostForm("to"), ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			update.To = append(update.To, recipient)
+		}
+	}
+	for _, idStr := range c.PostFormArray("comment_ids") {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+			return
+		}
+		update.CommentIDs = append(update.CommentIDs, uint(id))
+	}
+
+	if _, err := h.taskService.SendEmailUpdate(uint(taskID), update); err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmailSendingDisabled), errors.Is(err, services.ErrTaskNotFromEmail),
+			errors.Is(err, services.ErrNoEmailRecipients), errors.Is(err, services.ErrEmptyEmailUpdate),
+			errors.Is(err, services.ErrInvalidRecipient), errors.Is(err, services.ErrCommentNotOnTask):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send email update"})
+		}
+		return
+	}
+
+	h.TaskTimelineHandler(c)
+}
+
+// canReadEmails reports whether the current user may see raw email exchanges
+func canReadEmails(c *gin.Context) bool {
+	authContext, exists := c.Get("auth")
+	if !exists {
+		return false
+	}
+	auth, ok := authContext.(*models.AuthContext)
+	return ok && auth.HasPermission(models.PermissionReadEmails)
+}
+
+// TaskEmailsHandler lists the raw emails received and sent on a task, in place of its timeline
+func (h *TaskHandler) TaskEmailsHandler(c *gin.Context) {
+	if !canReadEmails(c) {
+		c.Header("Content-Type", "text/html")
+		c.String(http.StatusForbidden, `<div class="p-6 text-sm text-red-600">The emails:read permission is required</div>`)
+		return
+	}
+
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	messages, err := h.taskService.GetTaskEmails(uint(taskID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load emails"})
+		return
+	}
+
+	emailsHTML := `
+		<div class="flex items-center justify-between">
+			<p class="text-sm font-medium text-gray-900">Emails</p>
+			<button hx-get="/app/tasks/` + taskIDStr + `/timeline"
+					hx-target="#timeline-content-` + taskIDStr + `"
+					hx-swap="innerHTML"
+					class="text-sm text-blue-600 hover:text-blue-800">Back to timeline</button>
+		</div>`
+
+	if len(messages) == 0 {
+		emailsHTML += `
+		<p class="text-sm text-gray-500">No emails have been recorded for this task.</p>`
+	}
+
+	for _, message := range messages {
+		directionLabel := "Received"
+		directionClass := "bg-green-100 text-green-800"
+		if message.Direction == models.EmailDirectionOutbound {
+			directionLabel = "Sent"
+			directionClass = "bg-blue-100 text-blue-800"
+		}
+
+		emailsHTML += fmt.Sprintf(`
+		<details class="rounded-md border border-gray-200 p-3">
+			<summary class="cursor-pointer">
+				<span class="inline-flex items-center px-2 py-0.5 rounded-full text-xs font-medium %s">%s</span>
+				<span class="ml-2 text-sm font-medium text-gray-900">%s</span>
+				<span class="block text-xs text-gray-500 mt-1">%s &middot; %s</span>
+			</summary>
+			<dl class="mt-2 text-xs text-gray-600">
+				<div><dt class="inline font-medium">To:</dt> <dd class="inline">%s</dd></div>
+				<div><dt class="inline font-medium">Message-ID:</dt> <dd class="inline">%s</dd></div>
+			</dl>
+			<pre class="mt-2 whitespace-pre-wrap text-sm text-gray-700">%s</pre>
+		</details>`,
+			directionClass, directionLabel,
+			html.EscapeString(message.Subject),
+			html.EscapeString(message.From),
+			message.ReceivedAt.Format("Jan 2, 2006 at 3:04 PM"),
+			html.EscapeString(strings.Join(append(message.To, message.CC...), ", ")),
+			html.EscapeString(message.MessageID),
+			html.EscapeString(message.Body))
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, emailsHTML)
+}
@@ -2,14 +2,27 @@ package frontend
 
 import (
 	"fmt"
+	"html"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/quickadd"
+	"github.com/soarinferret/jats/internal/services"
+	"github.com/soarinferret/jats/internal/utils"
 )
 
+// taskSizeOptions renders a datalist suggesting the t-shirt sizes for a size input
+func taskSizeOptions(id string) string {
+	options := ""
+	for _, size := range models.TaskSizes {
+		options += fmt.Sprintf(`<option value="%s">%d points</option>`, size, size.Points())
+	}
+	return fmt.Sprintf(`<datalist id="%s">%s</datalist>`, id, options)
+}
+
 // NewTaskFormHandler serves the new task form modal
 func (h *TaskHandler) NewTaskFormHandler(c *gin.Context) {
 	formHTML := `
@@ -69,6 +82,37 @@ func (h *TaskHandler) NewTaskFormHandler(c *gin.Context) {
 					   placeholder="project, urgent, client-name (comma separated)">
 			</div>
 
+			<div>
+				<label for="task-context" class="block text-sm font-medium text-gray-700">Context (Optional)</label>
+				<input type="text"
+					   id="task-context"
+					   name="context"
+					   class="mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500 sm:text-sm"
+					   placeholder="@home, @office, @datacenter">
+			</div>
+
+			<div>
+				<label for="task-size" class="block text-sm font-medium text-gray-700">Size (Optional)</label>
+				<input type="text"
+					   id="task-size"
+					   name="size"
+					   list="task-size-options"
+					   class="mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500 sm:text-sm"
+					   placeholder="XS, S, M, L, XL or story points">
+				` + taskSizeOptions("task-size-options") + `
+			</div>
+
+			<div>
+				<label for="task-date" class="block text-sm font-medium text-gray-700">Date (Optional)</label>
+				<input type="text"
+					   id="task-date"
+					   name="date"
+					   oninput="previewDate(this, 'task-date-preview')"
+					   class="mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500 sm:text-sm"
+					   placeholder="today, yesterday, last friday, 2025-12-01...">
+				<p id="task-date-preview" class="mt-1 text-xs text-gray-500"></p>
+			</div>
+
 			<div class="flex justify-end space-x-3 pt-4">
 				<button type="button"
 						onclick="hideModal('task-form-modal')"
@@ -100,6 +144,14 @@ func (h *TaskHandler) CreateTaskHandler(c *gin.Context) {
 	description := strings.TrimSpace(c.PostForm("description"))
 	priority := c.PostForm("priority")
 	tagsStr := strings.TrimSpace(c.PostForm("tags"))
+	context := c.PostForm("context")
+	dateStr := strings.TrimSpace(c.PostForm("date"))
+
+	size, err := services.ParseTaskSize(c.PostForm("size"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Validate required fields
 	if name == "" {
@@ -107,6 +159,12 @@ func (h *TaskHandler) CreateTaskHandler(c *gin.Context) {
 		return
 	}
 
+	createdAt, err := utils.ParseDate(dateStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Parse tags
 	var tags []string
 	if tagsStr != "" {
@@ -119,7 +177,7 @@ func (h *TaskHandler) CreateTaskHandler(c *gin.Context) {
 	}
 
 	// Create the task using the simple TaskService interface
-	task, err := h.taskService.CreateTask(name)
+	task, err := h.taskService.CreateTaskWithDate(name, createdAt)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task"})
 		return
@@ -135,10 +193,12 @@ func (h *TaskHandler) CreateTaskHandler(c *gin.Context) {
 	if len(tags) > 0 {
 		task.Tags = tags
 	}
+	task.Context = context
+	task.Size = size
 
 	// Save the updated task
 	if err := h.taskService.UpdateTask(task); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task details"})
+		h.respondUpdateError(c, task, err, "Failed to update task details")
 		return
 	}
 
@@ -146,6 +206,32 @@ func (h *TaskHandler) CreateTaskHandler(c *gin.Context) {
 	h.renderTaskList(c, authContext.(*models.AuthContext))
 }
 
+// QuickAddTaskHandler creates a task from the one-line quick-add box
+func (h *TaskHandler) QuickAddTaskHandler(c *gin.Context) {
+	authContext, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	entry, err := quickadd.Parse(c.PostForm("input"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.taskService.CreateQuickTask(entry); err != nil {
+		if err == services.ErrWIPLimitReached {
+			c.JSON(http.StatusConflict, gin.H{"error": "The task was created, but the in-progress column is at its work-in-progress limit"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task"})
+		return
+	}
+
+	h.renderTaskList(c, authContext.(*models.AuthContext))
+}
+
 // EditTaskFormHandler serves the edit task form modal
 func (h *TaskHandler) EditTaskFormHandler(c *gin.Context) {
 	taskIDStr := c.Param("id")
@@ -206,6 +292,7 @@ func (h *TaskHandler) EditTaskFormHandler(c *gin.Context) {
 						class="mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500 sm:text-sm">
 					<option value="open" %s>Open</option>
 					<option value="in-progress" %s>In Progress</option>
+					<option value="pending-review" %s>Pending Review</option>
 					<option value="resolved" %s>Resolved</option>
 					<option value="closed" %s>Closed</option>
 				</select>
@@ -232,6 +319,28 @@ func (h *TaskHandler) EditTaskFormHandler(c *gin.Context) {
 					   placeholder="project, urgent, client-name (comma separated)">
 			</div>
 
+			<div>
+				<label for="edit-task-context" class="block text-sm font-medium text-gray-700">Context</label>
+				<input type="text"
+					   id="edit-task-context"
+					   name="context"
+					   value="%s"
+					   class="mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500 sm:text-sm"
+					   placeholder="@home, @office, @datacenter">
+			</div>
+
+			<div>
+				<label for="edit-task-size" class="block text-sm font-medium text-gray-700">Size</label>
+				<input type="text"
+					   id="edit-task-size"
+					   name="size"
+					   value="%s"
+					   list="edit-task-size-options"
+					   class="mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500 sm:text-sm"
+					   placeholder="XS, S, M, L, XL or story points">
+				%s
+			</div>
+
 			<div class="flex justify-end space-x-3 pt-4">
 				<button type="button"
 						onclick="hideModal('task-edit-modal')"
@@ -246,16 +355,20 @@ func (h *TaskHandler) EditTaskFormHandler(c *gin.Context) {
 		</form>
 	</div>`,
 		taskIDStr,
-		task.Name,
-		task.Description,
+		html.EscapeString(task.Name),
+		html.EscapeString(task.Description),
 		func() string { if task.Status == models.TaskStatusOpen { return "selected" }; return "" }(),
 		func() string { if task.Status == models.TaskStatusInProgress { return "selected" }; return "" }(),
+		func() string { if task.Status == models.TaskStatusPendingReview { return "selected" }; return "" }(),
 		func() string { if task.Status == models.TaskStatusResolved { return "selected" }; return "" }(),
 		func() string { if task.Status == models.TaskStatusClosed { return "selected" }; return "" }(),
 		func() string { if task.Priority == models.TaskPriorityLow { return "selected" }; return "" }(),
 		func() string { if task.Priority == models.TaskPriorityMedium { return "selected" }; return "" }(),
 		func() string { if task.Priority == models.TaskPriorityHigh { return "selected" }; return "" }(),
-		tagsStr)
+		html.EscapeString(tagsStr),
+		html.EscapeString(task.Context),
+		html.EscapeString(string(task.Size)),
+		taskSizeOptions("edit-task-size-options"))
 
 	c.Header("Content-Type", "text/html")
 	c.String(http.StatusOK, formHTML)
@@ -311,10 +424,18 @@ func (h *TaskHandler) UpdateTaskHandler(c *gin.Context) {
 		}
 	}
 	task.Tags = tags
+	task.Context = c.PostForm("context")
+	if task.Size, err = services.ParseTaskSize(c.PostForm("size")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if user := currentUser(c); user != nil {
+		task.ChangedBy = user.Username
+	}
 
 	// Save the updated task
 	if err := h.taskService.UpdateTask(task); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
+		h.respondUpdateError(c, task, err, "Failed to update task")
 		return
 	}
 
@@ -0,0 +1,134 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// muteDurations are the durations offered when muting from the task detail
+var muteDurations = []struct {
+	Value string
+	Label string
+}{
+	{"1h", "1 hour"},
+	{"4h", "4 hours"},
+	{"24h", "1 day"},
+	{"168h", "1 week"},
+}
+
+// renderMuteControls renders the muted indicator with an unmute button, or
+// the form to mute the task or one of its tags
+func (h *TaskHandler) renderMuteControls(c *gin.Context, task *models.Task, taskIDStr string) string {
+	user := currentUser(c)
+	if user == nil {
+		return ""
+	}
+
+	mute, err := h.taskService.ActiveMute(user.ID, task)
+	if err != nil {
+		return ""
+	}
+
+	if mute != nil {
+		scope := "this task"
+		if mute.TaskID == nil {
+			scope = "tag " + mute.Tag
+		}
+		return fmt.Sprintf(`
+				<div class="mt-2 flex items-center gap-2 text-xs">
+					<span class="inline-flex items-center px-2 py-1 rounded-full bg-yellow-100 text-yellow-800" title="Notifications muted for %s">
+						🔕 Muted until %s
+					</span>
+					<button hx-delete="/app/tasks/%s/mute" hx-target="#task-detail" hx-swap="innerHTML"
+							class="text-blue-600 hover:text-blue-800">Unmute</button>
+				</div>`,
+			html.EscapeString(scope), mute.Until.Format("Jan 2, 3:04 PM"), taskIDStr)
+	}
+
+	controlsHTML := `
+				<form hx-post="/app/tasks/` + taskIDStr + `/mute" hx-target="#task-detail" hx-swap="innerHTML"
+					  class="mt-2 flex items-center gap-2 text-xs text-gray-600">
+					<label for="mute-target-` + taskIDStr + `" class="sr-only">Mute</label>
+					<select name="target" id="mute-target-` + taskIDStr + `" class="rounded-md border-gray-300 text-xs">
+						<option value="task">Mute this task</option>`
+	for _, tag := range task.Tags {
+		controlsHTML += fmt.Sprintf(`
+						<option value="tag:%s">Mute tag %s</option>`, html.EscapeString(tag), html.EscapeString(tag))
+	}
+	controlsHTML += `
+					</select>
+					<label for="mute-duration-` + taskIDStr + `" class="sr-only">For</label>
+					<select name="duration" id="mute-duration-` + taskIDStr + `" class="rounded-md border-gray-300 text-xs">`
+	for _, d := range muteDurations {
+		controlsHTML += fmt.Sprintf(`
+						<option value="%s">for %s</option>`, d.Value, d.Label)
+	}
+	controlsHTML += `
+					</select>
+					<button type="submit" class="text-blue-600 hover:text-blue-800">Mute</button>
+				</form>`
+
+	return controlsHTML
+}
+
+// MuteTaskHandler mutes the task, or one of its tags, for the signed-in user
+func (h *TaskHandler) MuteTaskHandler(c *gin.Context) {
+	user := currentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	duration := c.PostForm("duration")
+	if tag, ok := strings.CutPrefix(c.PostForm("target"), "tag:"); ok {
+		_, err = h.taskService.MuteTag(user.ID, tag, duration)
+	} else {
+		_, err = h.taskService.MuteTask(user.ID, uint(taskID), duration)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.TaskDetailHandler(c)
+}
+
+// UnmuteTaskHandler ends the signed-in user's mutes covering the task
+func (h *TaskHandler) UnmuteTaskHandler(c *gin.Context) {
+	user := currentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	task, err := h.taskService.GetTask(uint(taskID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	if err := h.taskService.UnmuteTask(user.ID, task); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unmute task"})
+		return
+	}
+
+	h.TaskDetailHandler(c)
+}
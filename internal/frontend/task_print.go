@@ -0,0 +1,177 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/middleware"
+)
+
+// serverURL returns the absolute URL of the server the request came in on,
+// including the base path
+func serverURL(c *gin.Context) string {
+	return fmt.Sprintf("%s://%s%s", middleware.RequestScheme(c.Request), c.Request.Host, middleware.BasePath(c.Request))
+}
+
+// renderQRCodeSVG draws QR code modules as an SVG with a quiet zone around them
+func renderQRCodeSVG(modules [][]bool, size int) string {
+	const quiet = 4
+	dimension := len(modules) + 2*quiet
+	var path strings.Builder
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x+quiet, y+quiet)
+			}
+		}
+	}
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" shape-rendering="crispEdges"><rect width="100%%" height="100%%" fill="#fff"/><path d="%s" fill="#000"/></svg>`,
+		dimension, dimension, size, size, path.String())
+}
+
+// TaskPrintHandler renders a task's job sheet as a standalone printable page
+func (h *TaskHandler) TaskPrintHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	sheet, err := h.taskService.GetJobSheet(uint(id), serverURL(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+	task := sheet.Task
+
+	details := fmt.Sprintf("Status: %s", html.EscapeString(string(task.Status)))
+	if task.Priority != "" {
+		details += fmt.Sprintf(" &middot; Priority: %s", html.EscapeString(string(task.Priority)))
+	}
+	if task.DueDate != nil {
+		details += " &middot; Due: " + task.DueDate.Format("Jan 2, 2006")
+	}
+	if len(task.Tags) > 0 {
+		details += " &middot; Tags: " + html.EscapeString(strings.Join(task.Tags, ", "))
+	}
+
+	description := `<p class="muted">No description.</p>`
+	if strings.TrimSpace(task.Description) != "" {
+		description = fmt.Sprintf(`<p class="text">%s</p>`, html.EscapeString(task.Description))
+	}
+
+	checklistHTML := ""
+	if len(sheet.Checklist) > 0 {
+		items := ""
+		for _, item := range sheet.Checklist {
+			box := "&#9744;"
+			if item.Completed {
+				box = "&#9745;"
+			}
+			items += fmt.Sprintf(`
+				<li><span class="box">%s</span> %s</li>`, box, html.EscapeString(item.Name))
+		}
+		checklistHTML = fmt.Sprintf(`
+		<h2>Checklist</h2>
+		<ul class="checklist">%s
+		</ul>`, items)
+	}
+
+	commentsHTML := ""
+	if len(sheet.Comments) > 0 {
+		comments := ""
+		for _, comment := range sheet.Comments {
+			author := comment.CreatedBy
+			if author == "" {
+				author = comment.FromEmail
+			}
+			heading := comment.CreatedAt.Format("Jan 2, 2006 15:04")
+			if author != "" {
+				heading += " &middot; " + html.EscapeString(author)
+			}
+			comments += fmt.Sprintf(`
+			<div class="comment">
+				<p class="muted">%s</p>
+				<p class="text">%s</p>
+			</div>`, heading, html.EscapeString(comment.Content))
+		}
+		commentsHTML = fmt.Sprintf(`
+		<h2>Comments</h2>%s`, comments)
+	}
+
+	pageHTML := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>Job sheet #%d</title>
+	<style>
+		body { font-family: Helvetica, Arial, sans-serif; color: #111; max-width: 800px; margin: 2rem auto; padding: 0 1rem; }
+		header { display: flex; justify-content: space-between; gap: 1rem; align-items: flex-start; }
+		h1 { font-size: 1.5rem; margin: 0 0 0.5rem; }
+		h2 { font-size: 1.1rem; border-bottom: 1px solid #ccc; padding-bottom: 0.25rem; margin-top: 1.5rem; }
+		.muted { color: #555; font-size: 0.85rem; margin: 0; }
+		.text { white-space: pre-wrap; margin: 0.25rem 0 0; }
+		.checklist { list-style: none; padding: 0; }
+		.checklist li { margin: 0.35rem 0; }
+		.box { font-size: 1.2rem; }
+		.comment { margin-bottom: 0.75rem; }
+		.notes div { border-bottom: 1px solid #ccc; height: 2rem; }
+		.actions { margin-bottom: 1rem; display: flex; gap: 0.75rem; }
+		.actions a, .actions button { font-size: 0.9rem; padding: 0.4rem 0.8rem; border: 1px solid #ccc; border-radius: 4px; background: #fff; color: #111; text-decoration: none; cursor: pointer; }
+		@media print { .actions { display: none; } body { margin: 0; } }
+	</style>
+</head>
+<body>
+	<div class="actions">
+		<button type="button" onclick="window.print()">Print</button>
+		<a href="%s">Download PDF</a>
+	</div>
+	<header>
+		<div>
+			<h1>#%d %s</h1>
+			<p class="muted">%s</p>
+		</div>
+		%s
+	</header>
+	<h2>Description</h2>
+	%s
+	%s
+	%s
+	<h2>Notes</h2>
+	<div class="notes"><div></div><div></div><div></div><div></div></div>
+	<p class="muted">%s</p>
+</body>
+</html>`,
+		task.ID,
+		appURL(c, fmt.Sprintf("/app/tasks/%d/print/pdf", task.ID)),
+		task.ID, html.EscapeString(task.Name), details,
+		renderQRCodeSVG(sheet.QR, 120),
+		description, checklistHTML, commentsHTML,
+		html.EscapeString(sheet.URL),
+	)
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, pageHTML)
+}
+
+// TaskPrintPDFHandler downloads a task's job sheet as a PDF
+func (h *TaskHandler) TaskPrintPDFHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	sheet, err := h.taskService.GetJobSheet(uint(id), serverURL(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="job-sheet-%d.pdf"`, id))
+	c.Data(http.StatusOK, "application/pdf", sheet.PDF())
+}
@@ -2,14 +2,26 @@ package frontend
 
 import (
 	"fmt"
+	"html"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/searchquery"
+	"github.com/soarinferret/jats/internal/services"
 )
 
 // generateTaskCardHTML generates HTML for a single task card
 func (h *TaskHandler) generateTaskCardHTML(task models.Task) string {
+	return h.generateSearchTaskCardHTML(task, nil)
+}
+
+// generateSearchTaskCardHTML generates HTML for a task card in search results:
+// the search terms are highlighted in the name, and a matching description is
+// shortened to a snippet around the match
+func (h *TaskHandler) generateSearchTaskCardHTML(task models.Task, terms []string) string {
 	// Task completion checkbox
 	checkboxClass := "flex-shrink-0 h-5 w-5 rounded-full border-2 focus:outline-none focus:ring-2 focus:ring-blue-500"
 	checkboxContent := ""
@@ -58,13 +70,13 @@ func (h *TaskHandler) generateTaskCardHTML(task models.Task) string {
 				</div>`,
 		task.ID, task.ID, task.ID,
 		checkboxClass, checkboxContent,
-		taskNameClass, task.Name,
-		priorityClass, task.Priority)
+		taskNameClass, highlightHTML(task.Name, terms, false),
+		priorityClass, html.EscapeString(string(task.Priority)))
 
 	// Add description if present
 	if task.Description != "" {
 		taskHTML += fmt.Sprintf(`
-				<p class="mt-2 text-gray-600 text-sm">%s</p>`, task.Description)
+				<p class="mt-2 text-gray-600 text-sm">%s</p>`, highlightHTML(task.Description, terms, true))
 	}
 
 	// Add metadata section
@@ -73,7 +85,23 @@ func (h *TaskHandler) generateTaskCardHTML(task models.Task) string {
 					<span class="inline-flex items-center">
 						<svg class="mr-1 h-4 w-4" fill="none" viewBox="0 0 24 24" stroke="currentColor">
 							<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M7 4V2a1 1 0 011-1h8a1 1 0 011 1v2h4a1 1 0 110 2h-1v14a2 2 0 01-2 2H6a2 2 0 01-2-2V6H3a1 1 0 110-2h4z"/>
-						</svg>` + string(task.Status) + `</span>`
+						</svg>` + html.EscapeString(string(task.Status)) + `</span>`
+
+	// Add context if set
+	if task.Context != "" {
+		taskHTML += fmt.Sprintf(`
+					<span class="inline-flex items-center px-2 py-0.5 rounded text-xs font-medium bg-purple-100 text-purple-800">%s</span>`, html.EscapeString(task.Context))
+	}
+
+	// Add size if set, labelling story points so they are not read as an ID
+	if task.Size != "" {
+		label := string(task.Size)
+		if !slices.Contains(models.TaskSizes, task.Size) {
+			label += " pts"
+		}
+		taskHTML += fmt.Sprintf(`
+					<span class="inline-flex items-center px-2 py-0.5 rounded text-xs font-medium bg-gray-100 text-gray-800" title="%d points">%s</span>`, task.Size.Points(), html.EscapeString(label))
+	}
 
 	// Add tags if present
 	if len(task.Tags) > 0 {
@@ -81,12 +109,22 @@ func (h *TaskHandler) generateTaskCardHTML(task models.Task) string {
 					<div class="flex flex-wrap gap-1">`
 		for _, tag := range task.Tags {
 			taskHTML += fmt.Sprintf(`
-						<span class="inline-flex items-center px-2 py-0.5 rounded text-xs font-medium bg-blue-100 text-blue-800">%s</span>`, tag)
+						<span class="inline-flex items-center px-2 py-0.5 rounded text-xs font-medium bg-blue-100 text-blue-800">%s</span>`, html.EscapeString(tag))
 		}
 		taskHTML += `
 					</div>`
 	}
 
+	// Add due date, in red once overdue
+	if task.DueDate != nil {
+		dueClass := "text-gray-500"
+		if task.Overdue {
+			dueClass = "text-red-600 font-medium"
+		}
+		taskHTML += fmt.Sprintf(`
+					<span class="inline-flex items-center %s">Due %s</span>`, dueClass, task.DueDate.Format("Jan 2, 2006"))
+	}
+
 	// Add creation date
 	taskHTML += fmt.Sprintf(`
 					<span class="inline-flex items-center">
@@ -116,8 +154,12 @@ func (h *TaskHandler) renderFilteredTaskList(c *gin.Context, tasks []models.Task
 			<p class="mt-1 text-sm text-gray-500">No tasks match the current filters.</p>
 		</div>`
 	} else {
+		var terms []string
+		if query, err := searchquery.Parse(c.Query("search")); err == nil {
+			terms = query.Terms
+		}
 		for _, task := range tasks {
-			tasksHTML += h.generateTaskCardHTML(task)
+			tasksHTML += h.generateSearchTaskCardHTML(task, terms)
 		}
 	}
 
@@ -125,6 +167,32 @@ func (h *TaskHandler) renderFilteredTaskList(c *gin.Context, tasks []models.Task
 	c.String(http.StatusOK, tasksHTML)
 }
 
+// highlightHTML escapes text, marking the matches of the search terms. With
+// snippet, a matching text is shortened to the part around the first match.
+func highlightHTML(text string, terms []string, snippet bool) string {
+	highlight := services.HighlightMatches
+	if snippet {
+		highlight = services.MatchSnippet
+	}
+	match := highlight(text, terms...)
+	if match == nil {
+		return html.EscapeString(text)
+	}
+
+	runes := []rune(match.Text)
+	var b strings.Builder
+	pos := 0
+	for _, hl := range match.Highlights {
+		b.WriteString(html.EscapeString(string(runes[pos:hl.Start])))
+		b.WriteString(`<mark class="bg-yellow-200 rounded">`)
+		b.WriteString(html.EscapeString(string(runes[hl.Start:hl.End])))
+		b.WriteString(`</mark>`)
+		pos = hl.End
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:])))
+	return b.String()
+}
+
 // renderTaskList renders just the task list HTML for HTMX updates
 func (h *TaskHandler) renderTaskList(c *gin.Context, auth *models.AuthContext) {
 	// Get all tasks
@@ -0,0 +1,76 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// renderReviewControls renders, for a task pending review, who it waits for
+// and, to its reviewers, the form to approve or reopen it
+func (h *TaskHandler) renderReviewControls(c *gin.Context, task *models.Task, taskIDStr string) string {
+	if task.Status != models.TaskStatusPendingReview {
+		return ""
+	}
+
+	reviewers := h.taskService.Reviewers(task)
+	controlsHTML := fmt.Sprintf(`
+				<div class="mt-2 text-xs text-gray-600">Waiting for review by %s</div>`,
+		html.EscapeString(strings.Join(reviewers, ", ")))
+
+	user := currentUser(c)
+	if user == nil || !slices.ContainsFunc(reviewers, func(r string) bool { return strings.EqualFold(r, user.Username) }) {
+		return controlsHTML
+	}
+
+	controlsHTML += `
+				<form hx-post="/app/tasks/` + taskIDStr + `/review" hx-target="#task-detail" hx-swap="innerHTML"
+					  class="mt-2 flex items-center gap-2 text-xs">
+					<label for="review-comment-` + taskIDStr + `" class="sr-only">Review comment</label>
+					<input type="text" name="comment" id="review-comment-` + taskIDStr + `" placeholder="Comment (required to reopen)"
+						   class="flex-1 rounded-md border-gray-300 text-xs">
+					<button type="submit" name="decision" value="approve" class="text-green-600 hover:text-green-800">Approve</button>
+					<button type="submit" name="decision" value="reopen" class="text-red-600 hover:text-red-800">Reopen</button>
+				</form>`
+
+	return controlsHTML
+}
+
+// ReviewTaskHandler approves or reopens a task pending review as the
+// signed-in user
+func (h *TaskHandler) ReviewTaskHandler(c *gin.Context) {
+	user := currentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	_, err = h.taskService.ReviewTask(uint(taskID), user.Username, c.PostForm("decision") == "approve", c.PostForm("comment"))
+	switch err {
+	case nil:
+	case services.ErrNotReviewer:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case services.ErrNotPendingReview, services.ErrReviewCommentRequired, services.ErrWIPLimitReached:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review task"})
+		return
+	}
+
+	h.TaskDetailHandler(c)
+}
@@ -0,0 +1,146 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// scheduleInputLayout is the value format of a datetime-local input
+const scheduleInputLayout = "2006-01-02T15:04"
+
+// renderScheduledActions lists a task's pending scheduled actions, with a form
+// to schedule another one
+func (h *TaskHandler) renderScheduledActions(task *models.Task, taskIDStr string) string {
+	actions, err := h.taskService.GetScheduledActions(task.ID)
+	if err != nil {
+		return ""
+	}
+
+	scheduledHTML := `
+				<div class="mt-3 text-sm">`
+	if len(actions) > 0 {
+		scheduledHTML += `
+					<p class="text-xs font-medium text-gray-700">Scheduled</p>
+					<ul class="mt-1 space-y-1">`
+		for _, action := range actions {
+			description := "Move to " + string(action.Status)
+			if action.Kind == models.ScheduledActionComment {
+				description = "Post note: " + action.Content
+			}
+			if action.UnlessReply {
+				description += " (unless a reply arrives)"
+			}
+			scheduledHTML += fmt.Sprintf(`
+						<li class="flex items-center justify-between gap-2 text-gray-600">
+							<span><span class="font-medium">%s</span> &middot; %s</span>
+							<button hx-delete="/app/tasks/%s/scheduled/%d" hx-target="#task-detail" hx-swap="innerHTML"
+									class="text-xs text-red-600 hover:text-red-800">Cancel</button>
+						</li>`,
+				action.RunAt.Format("Jan 2, 3:04 PM"), html.EscapeString(description), taskIDStr, action.ID)
+		}
+		scheduledHTML += `
+					</ul>`
+	}
+
+	scheduledHTML += `
+					<details class="mt-1">
+						<summary class="cursor-pointer text-xs text-blue-600 hover:text-blue-800">Schedule an action</summary>
+						<form hx-post="/app/tasks/` + taskIDStr + `/scheduled" hx-target="#task-detail" hx-swap="innerHTML"
+							  class="mt-2 space-y-2 text-xs">
+							<div class="flex gap-2">
+								<label for="schedule-kind-` + taskIDStr + `" class="sr-only">Action</label>
+								<select name="kind" id="schedule-kind-` + taskIDStr + `" class="rounded-md border-gray-300 text-xs">
+									<option value="comment">Post note</option>
+									<option value="status">Change status</option>
+								</select>
+								<label for="schedule-status-` + taskIDStr + `" class="sr-only">Status</label>
+								<select name="status" id="schedule-status-` + taskIDStr + `" class="rounded-md border-gray-300 text-xs">`
+	for _, status := range services.KanbanStatuses {
+		scheduledHTML += fmt.Sprintf(`
+									<option value="%s">%s</option>`, status, status)
+	}
+	scheduledHTML += `
+								</select>
+								<label for="schedule-at-` + taskIDStr + `" class="sr-only">When</label>
+								<input type="datetime-local" name="run_at" id="schedule-at-` + taskIDStr + `" required
+									   min="` + time.Now().Format(scheduleInputLayout) + `"
+									   class="rounded-md border-gray-300 text-xs">
+							</div>
+							<label for="schedule-content-` + taskIDStr + `" class="sr-only">Note</label>
+							<textarea name="content" id="schedule-content-` + taskIDStr + `" rows="2"
+									  placeholder="Note to post (for Post note)"
+									  class="w-full rounded-md border-gray-300 text-xs"></textarea>
+							<div class="flex items-center justify-between">
+								<label class="flex items-center gap-1 text-gray-600">
+									<input type="checkbox" name="unless_reply" value="true" class="rounded border-gray-300">
+									Skip if a reply arrives first
+								</label>
+								<button type="submit" class="text-blue-600 hover:text-blue-800">Schedule</button>
+							</div>
+						</form>
+					</details>
+				</div>`
+
+	return scheduledHTML
+}
+
+// ScheduleActionHandler schedules a note or status change from the task detail form
+func (h *TaskHandler) ScheduleActionHandler(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	runAt, err := time.ParseInLocation(scheduleInputLayout, c.PostForm("run_at"), time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date and time"})
+		return
+	}
+
+	action := &models.ScheduledAction{
+		Kind:        c.PostForm("kind"),
+		Content:     c.PostForm("content"),
+		Status:      models.TaskStatus(c.PostForm("status")),
+		RunAt:       runAt,
+		UnlessReply: c.PostForm("unless_reply") == "true",
+	}
+	if user := currentUser(c); user != nil {
+		action.CreatedBy = user.Username
+	}
+
+	if _, err := h.taskService.ScheduleAction(uint(taskID), action); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.TaskDetailHandler(c)
+}
+
+// CancelScheduledActionHandler cancels a pending scheduled action
+func (h *TaskHandler) CancelScheduledActionHandler(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+	actionID, err := strconv.ParseUint(c.Param("actionId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scheduled action ID"})
+		return
+	}
+
+	if err := h.taskService.CancelScheduledAction(uint(taskID), uint(actionID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.TaskDetailHandler(c)
+}
@@ -2,6 +2,7 @@ package frontend
 
 import (
 	"fmt"
+	"html"
 	"net/http"
 	"strconv"
 	"strings"
@@ -59,6 +60,13 @@ func (h *TaskHandler) TaskSubtasksHandler(c *gin.Context) {
 						   required
 						   class="flex-1 border-0 focus:ring-0 text-sm placeholder-gray-400 bg-transparent"
 						   onkeydown="handleSubtaskEnter(event, this.form)">
+					<input type="number"
+						   name="estimate"
+						   min="0"
+						   placeholder="Est. min"
+						   title="Estimate in minutes"
+						   class="w-20 border-0 focus:ring-0 text-sm placeholder-gray-400 bg-transparent"
+						   onkeydown="handleSubtaskEnter(event, this.form)">
 				</div>
 			</form>
 		</div>`, taskIDStr)
@@ -71,7 +79,7 @@ func (h *TaskHandler) TaskSubtasksHandler(c *gin.Context) {
 				<svg class="w-4 h-4" fill="none" viewBox="0 0 24 24" stroke="currentColor">
 					<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M12 15v2m-6 4h12a2 2 0 002-2v-6a2 2 0 00-2-2H6a2 2 0 00-2 2v6a2 2 0 002 2zm10-10V7a4 4 0 00-8 0v4h8z" />
 				</svg>
-				<span class="flex-1 text-sm">Subtask editing disabled for ` + string(task.Status) + ` tasks</span>
+				<span class="flex-1 text-sm">Subtask editing disabled for ` + html.EscapeString(string(task.Status)) + ` tasks</span>
 			</div>
 		</div>`
 	}
@@ -119,7 +127,7 @@ func (h *TaskHandler) getSubtasksContentHTML(task *models.Task, taskIDStr string
 					<path stroke-linecap="round" stroke-linejoin="round" stroke-width="2" d="M9 5H7a2 2 0 00-2 2v12a2 2 0 002 2h10a2 2 0 002-2V7a2 2 0 00-2-2h-2M9 5a2 2 0 002 2h2a2 2 0 002-2M9 5a2 2 0 012-2h2a2 2 0 012 2m-6 9l2 2 4-4" />
 				</svg>
 				<p class="mt-2 text-sm font-medium text-gray-900">No subtasks</p>
-				<p class="text-sm text-gray-500">Task is ` + string(task.Status) + ` - no subtask modifications allowed</p>
+				<p class="text-sm text-gray-500">Task is ` + html.EscapeString(string(task.Status)) + ` - no subtask modifications allowed</p>
 			</div>`
 		}
 	} else {
@@ -168,14 +176,26 @@ func (h *TaskHandler) getSubtasksContentHTML(task *models.Task, taskIDStr string
 					checkedAttr, disabledAttr, checkboxClass)
 			}
 
+			// Show logged vs. estimated time when either is known
+			timeHTML := ""
+			if subtask.EstimateMinutes > 0 || subtask.LoggedMinutes > 0 {
+				timeClass := "text-gray-500"
+				if subtask.EstimateMinutes > 0 && subtask.LoggedMinutes > subtask.EstimateMinutes {
+					timeClass = "text-red-600"
+				}
+				timeHTML = fmt.Sprintf(`<span class="text-xs %s whitespace-nowrap">%s / %s</span>`,
+					timeClass, formatMinutes(subtask.LoggedMinutes), formatMinutes(subtask.EstimateMinutes))
+			}
+
 			contentHTML += fmt.Sprintf(`
 			<div class="flex items-center justify-between group">
 				<div class="flex items-center space-x-3 flex-1">
 					%s
 					<span class="text-sm %s flex-1">%s</span>
+					%s
 				</div>
 				%s
-			</div>`, checkboxHTML, completedClass, subtask.Name, actionButtons)
+			</div>`, checkboxHTML, completedClass, html.EscapeString(subtask.Name), timeHTML, actionButtons)
 		}
 		contentHTML += `</div>`
 	}
@@ -218,11 +238,22 @@ func (h *TaskHandler) AddSubtaskHandler(c *gin.Context) {
 		return
 	}
 
+	// Estimate is optional and given in minutes
+	estimate := 0
+	if estimateStr := strings.TrimSpace(c.PostForm("estimate")); estimateStr != "" {
+		estimate, err = strconv.Atoi(estimateStr)
+		if err != nil || estimate < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Estimate must be a non-negative number of minutes"})
+			return
+		}
+	}
+
 	// Create subtask using the task service
 	subtask := &models.Subtask{
-		TaskID:    uint(taskID),
-		Name:      name,
-		Completed: false,
+		TaskID:          uint(taskID),
+		Name:            name,
+		Completed:       false,
+		EstimateMinutes: estimate,
 	}
 
 	err = h.taskService.AddSubtask(uint(taskID), subtask)
@@ -349,4 +380,15 @@ func (h *TaskHandler) DeleteSubtaskHandler(c *gin.Context) {
 	// Return just the subtasks content with allowModifications=true since we already validated the status
 	c.Header("Content-Type", "text/html")
 	c.String(http.StatusOK, h.getSubtasksContentHTML(updatedTask, taskIDStr, true))
-}
\ No newline at end of file
+}
+
+// formatMinutes renders a duration in minutes as e.g. "1h 30m"
+func formatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
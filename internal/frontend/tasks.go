@@ -1,6 +1,8 @@
 package frontend
 
 import (
+	"errors"
+	"fmt"
 	"html/template"
 	"net/http"
 	"strconv"
@@ -15,15 +17,17 @@ import (
 
 // TaskHandler handles task-related frontend requests
 type TaskHandler struct {
-	taskService *services.TaskService
-	templates   map[string]*template.Template
+	taskService     *services.TaskService
+	settingsService *services.SettingsService
+	templates       map[string]*template.Template
 }
 
 // NewTaskHandler creates a new task handler
-func NewTaskHandler(taskService *services.TaskService, templates map[string]*template.Template) *TaskHandler {
+func NewTaskHandler(taskService *services.TaskService, settingsService *services.SettingsService, templates map[string]*template.Template) *TaskHandler {
 	return &TaskHandler{
-		taskService: taskService,
-		templates:   templates,
+		taskService:     taskService,
+		settingsService: settingsService,
+		templates:       templates,
 	}
 }
 
@@ -41,6 +45,7 @@ func (h *TaskHandler) TaskListHandler(c *gin.Context) {
 	status := c.Query("status")
 	priority := c.Query("priority")
 	search := c.Query("search")
+	context := c.Query("context")
 	tags := c.QueryArray("tags")
 
 	// Default to "open" status if no status filter is specified
@@ -88,8 +93,33 @@ func (h *TaskHandler) TaskListHandler(c *gin.Context) {
 
 	// Check if this is a saved query request
 	var savedQuery *models.SavedQuery
+	listURL := "/app/tasks"
 	if sq, exists := c.Get("savedQuery"); exists {
 		savedQuery = sq.(*models.SavedQuery)
+		listURL = fmt.Sprintf("/app/saved-queries/%d/tasks", savedQuery.ID)
+	}
+	if url, exists := c.Get("taskListURL"); exists {
+		listURL = url.(string)
+	}
+
+	// Search names, descriptions, comments and time entries (or the fields in
+	// ?in=), with the query syntax of the API's search filter
+	var taskSearch *services.TaskSearch
+	if search != "" {
+		var in []string
+		if inStr := c.Query("in"); inStr != "" {
+			in = strings.Split(inStr, ",")
+		}
+		var err error
+		taskSearch, err = h.taskService.NewTaskSearch(search, in)
+		if errors.Is(err, services.ErrInvalidSearch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search tasks"})
+			return
+		}
 	}
 
 	// Apply user filters to the tasks (saved query filtering already applied)
@@ -103,13 +133,11 @@ func (h *TaskHandler) TaskListHandler(c *gin.Context) {
 		if priority != "" && string(task.Priority) != priority {
 			continue
 		}
-		// Simple search in name and description
-		if search != "" {
-			searchLower := strings.ToLower(search)
-			if !strings.Contains(strings.ToLower(task.Name), searchLower) &&
-				!strings.Contains(strings.ToLower(task.Description), searchLower) {
-				continue
-			}
+		if !services.MatchesContext(task.Context, context) {
+			continue
+		}
+		if taskSearch != nil && !taskSearch.Matches(&task) {
+			continue
 		}
 		// Filter by tags (basic implementation)
 		if len(tags) > 0 {
@@ -161,6 +189,7 @@ func (h *TaskHandler) TaskListHandler(c *gin.Context) {
 		"Status":   originalStatus, // Use original status for template dropdown selection
 		"Priority": priority,
 		"Search":   search,
+		"Context":  context,
 		"Tags":     tags,
 	}
 
@@ -173,13 +202,23 @@ func (h *TaskHandler) TaskListHandler(c *gin.Context) {
 		return
 	}
 
+	// Contexts in use, for the context quick filter
+	contexts, err := h.taskService.GetContexts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get contexts"})
+		return
+	}
+
 	// For full page requests, return the complete template
 	data := gin.H{
 		"Tasks":      paginatedTasks,
+		"Contexts":   contexts,
 		"Pagination": pagination,
 		"Filters":    filters,
 		"User":       auth.User,
+		"L":          localizerFor(c),
 		"SavedQuery": savedQuery, // Add saved query for template header
+		"ListURL":    listURL,    // Where the filters reload the list from
 	}
 
 	c.Header("Content-Type", "text/html")
@@ -210,10 +249,13 @@ func (h *TaskHandler) TaskToggleCompleteHandler(c *gin.Context) {
 	} else {
 		task.Status = models.TaskStatusResolved
 	}
+	if user := currentUser(c); user != nil {
+		task.ChangedBy = user.Username
+	}
 
 	err = h.taskService.UpdateTask(task)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
+		h.respondUpdateError(c, task, err, "Failed to update task")
 		return
 	}
 
@@ -221,6 +263,41 @@ func (h *TaskHandler) TaskToggleCompleteHandler(c *gin.Context) {
 	h.renderSingleTask(c, *task)
 }
 
+// TaskCardHandler serves a task's card, for refreshing it when the task
+// changes
+func (h *TaskHandler) TaskCardHandler(c *gin.Context) {
+	taskIDStr := c.Param("id")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	task, err := h.taskService.GetTask(uint(taskID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	h.renderSingleTask(c, *task)
+}
+
+// respondUpdateError reports a failed task update, explaining WIP limit and
+// open blocker rejections
+func (h *TaskHandler) respondUpdateError(c *gin.Context, task *models.Task, err error, message string) {
+	if err == services.ErrWIPLimitReached {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("The %s column is at its work-in-progress limit of %d", task.Status, h.taskService.WIPLimit(task.Status)),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrOpenBlockers) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
 // renderSingleTask renders a complete task card HTML for HTMX updates
 func (h *TaskHandler) renderSingleTask(c *gin.Context, task models.Task) {
 	c.Header("Content-Type", "text/html")
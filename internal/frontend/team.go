@@ -0,0 +1,205 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/services"
+)
+
+// TeamHandler handles the team page: who is away this week, and the signed-in
+// user's own vacation and holiday ranges
+type TeamHandler struct {
+	taskService *services.TaskService
+	templates   map[string]*template.Template
+}
+
+// NewTeamHandler creates a new team handler
+func NewTeamHandler(taskService *services.TaskService, templates map[string]*template.Template) *TeamHandler {
+	return &TeamHandler{
+		taskService: taskService,
+		templates:   templates,
+	}
+}
+
+// formatOutOfOffice describes an out-of-office range, such as "Dec 22 – Jan 2"
+func formatOutOfOffice(ooo *models.OutOfOffice) string {
+	if ooo.EndDate.Equal(ooo.StartDate) {
+		return ooo.StartDate.Format("Mon Jan 2")
+	}
+	return ooo.StartDate.Format("Mon Jan 2") + " – " + ooo.EndDate.Format("Mon Jan 2, 2006")
+}
+
+// TeamPageHandler renders the team page
+func (h *TeamHandler) TeamPageHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderTeamPage(c, ""))
+}
+
+// CreateOutOfOfficeHandler adds an out-of-office range for the signed-in user
+func (h *TeamHandler) CreateOutOfOfficeHandler(c *gin.Context) {
+	user := currentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	errorMessage := ""
+	start, err := time.ParseInLocation(time.DateOnly, c.PostForm("start_date"), time.Local)
+	if err != nil {
+		errorMessage = "Choose the first day you are away"
+	} else {
+		end := start
+		if value := c.PostForm("end_date"); value != "" {
+			if end, err = time.ParseInLocation(time.DateOnly, value, time.Local); err != nil {
+				errorMessage = "Choose the last day you are away"
+			}
+		}
+		if errorMessage == "" {
+			if _, err := h.taskService.AddOutOfOffice(user, start, end, c.PostForm("note")); err != nil {
+				errorMessage = err.Error()
+			}
+		}
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderTeamPage(c, errorMessage))
+}
+
+// DeleteOutOfOfficeHandler removes one of the signed-in user's out-of-office ranges
+func (h *TeamHandler) DeleteOutOfOfficeHandler(c *gin.Context) {
+	user := currentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid out-of-office ID"})
+		return
+	}
+
+	errorMessage := ""
+	if err := h.taskService.DeleteOutOfOffice(user.ID, uint(id)); err != nil {
+		errorMessage = err.Error()
+	}
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, h.renderTeamPage(c, errorMessage))
+}
+
+// renderTeamPage renders who is away this week and the user's own ranges
+func (h *TeamHandler) renderTeamPage(c *gin.Context, errorMessage string) string {
+	errorHTML := ""
+	if errorMessage != "" {
+		errorHTML = fmt.Sprintf(`<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">%s</div>`, html.EscapeString(errorMessage))
+	}
+
+	now := time.Now()
+	weekStart := services.StartOfWeek(now)
+	awayHTML := ""
+	team, err := h.taskService.GetTeamOutOfOffice(now)
+	if err != nil {
+		errorHTML += `<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">Failed to load who is away</div>`
+	}
+	for _, ooo := range team {
+		badge := ""
+		if ooo.Covers(now) {
+			badge = ` <span class="ml-2 px-2 py-0.5 text-xs rounded-full bg-yellow-100 text-yellow-800">Away today</span>`
+		}
+		note := ""
+		if ooo.Note != "" {
+			note = fmt.Sprintf(`<p class="text-sm text-gray-500">%s</p>`, html.EscapeString(ooo.Note))
+		}
+		awayHTML += fmt.Sprintf(`
+				<li class="py-3">
+					<p class="text-sm font-medium text-gray-900">%s%s</p>
+					<p class="text-sm text-gray-600">%s</p>
+					%s
+				</li>`, html.EscapeString(ooo.Username), badge, formatOutOfOffice(ooo), note)
+	}
+	if awayHTML == "" {
+		awayHTML = `
+				<li class="py-3 text-sm text-gray-500">Everyone is in this week.</li>`
+	}
+
+	mineHTML := ""
+	if user := currentUser(c); user != nil {
+		mine, err := h.taskService.GetOutOfOffice(user.ID)
+		if err != nil {
+			errorHTML += `<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">Failed to load your time off</div>`
+		}
+		for _, ooo := range mine {
+			note := ""
+			if ooo.Note != "" {
+				note = " · " + html.EscapeString(ooo.Note)
+			}
+			mineHTML += fmt.Sprintf(`
+				<li class="py-3 flex items-center justify-between gap-4">
+					<p class="text-sm text-gray-900">%s<span class="text-gray-500">%s</span></p>
+					<button hx-delete="/app/team/out-of-office/%d" hx-target="#main-content"
+							hx-confirm="Remove this time off?"
+							class="text-sm text-red-600 hover:text-red-800">Remove</button>
+				</li>`, formatOutOfOffice(ooo), note, ooo.ID)
+		}
+	}
+	if mineHTML == "" {
+		mineHTML = `
+				<li class="py-3 text-sm text-gray-500">No time off planned.</li>`
+	}
+
+	inputClass := "mt-1 block w-full px-3 py-2 border border-gray-300 rounded-md text-sm focus:outline-none focus:ring-blue-500 focus:border-blue-500"
+
+	return fmt.Sprintf(`
+	<div class="p-6 max-w-3xl">
+		<h2 class="text-2xl font-bold text-gray-900 mb-6">Team</h2>
+		%s
+		<div class="bg-white shadow rounded-lg p-6 mb-6">
+			<h3 class="text-lg font-semibold text-gray-900">Away this week</h3>
+			<p class="text-sm text-gray-500">%s – %s. People who are away are skipped by auto-assignment and the standup email, and automation leaves their tasks alone.</p>
+			<ul class="mt-2 divide-y divide-gray-200">%s
+			</ul>
+		</div>
+		<div class="bg-white shadow rounded-lg p-6">
+			<h3 class="text-lg font-semibold text-gray-900">Your time off</h3>
+			<ul class="mt-2 divide-y divide-gray-200">%s
+			</ul>
+			<form hx-post="/app/team/out-of-office" hx-target="#main-content" class="mt-4 space-y-4 border-t border-gray-200 pt-4">
+				<div class="grid grid-cols-2 gap-4">
+					<div>
+						<label for="ooo_start" class="block text-sm font-medium text-gray-700">First day away</label>
+						<input id="ooo_start" name="start_date" type="date" required class="%s">
+					</div>
+					<div>
+						<label for="ooo_end" class="block text-sm font-medium text-gray-700">Last day away</label>
+						<input id="ooo_end" name="end_date" type="date" class="%s">
+					</div>
+				</div>
+				<div>
+					<label for="ooo_note" class="block text-sm font-medium text-gray-700">Note (optional)</label>
+					<input id="ooo_note" name="note" type="text" placeholder="Vacation" class="%s">
+				</div>
+				<div class="flex justify-end">
+					<button type="submit" class="brand-button px-4 py-2 text-sm font-medium text-white rounded-md">Add time off</button>
+				</div>
+			</form>
+		</div>
+	</div>`,
+		errorHTML,
+		weekStart.Format("Mon Jan 2"), weekStart.AddDate(0, 0, 6).Format("Mon Jan 2, 2006"), awayHTML,
+		mineHTML, inputClass, inputClass, inputClass,
+	)
+}
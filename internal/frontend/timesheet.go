@@ -0,0 +1,193 @@
+package frontend
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/services"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+// TimesheetHandler handles the week grid time editor
+type TimesheetHandler struct {
+	taskService *services.TaskService
+	templates   map[string]*template.Template
+}
+
+// NewTimesheetHandler creates a new timesheet handler
+func NewTimesheetHandler(taskService *services.TaskService, templates map[string]*template.Template) *TimesheetHandler {
+	return &TimesheetHandler{
+		taskService: taskService,
+		templates:   templates,
+	}
+}
+
+// formatClock formats minutes as hours and minutes, such as 1:30; zero is blank
+func formatClock(minutes int) string {
+	if minutes == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// TimesheetPageHandler renders the current user's time for a week as a grid
+// of tasks by days. Cells are edited in place and saved in one batch through
+// the timesheet API; add names tasks to show as empty rows.
+func (h *TimesheetHandler) TimesheetPageHandler(c *gin.Context) {
+	_, exists := c.Get("auth")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	week, err := utils.ParseDate(strings.TrimSpace(c.Query("week")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	username := ""
+	if user := currentUser(c); user != nil {
+		username = user.Username
+	}
+	sheet, err := h.taskService.GetTimesheet(username, week)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get timesheet"})
+		return
+	}
+
+	// Tasks added to the grid get empty rows until time is logged on them
+	errorHTML := ""
+	var added []string
+	for _, value := range c.QueryArray("add") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), "#")
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			errorHTML = fmt.Sprintf(`<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">Invalid task ID %s</div>`, html.EscapeString(value))
+			continue
+		}
+		task, err := h.taskService.GetTask(uint(id))
+		if err != nil {
+			errorHTML = fmt.Sprintf(`<div class="mb-4 rounded-md bg-red-50 p-3 text-sm text-red-700">Task #%d not found</div>`, id)
+			continue
+		}
+		if slices.ContainsFunc(sheet.Rows, func(row *services.TimesheetRow) bool { return row.TaskID == task.ID }) {
+			continue
+		}
+		sheet.Rows = append(sheet.Rows, &services.TimesheetRow{TaskID: task.ID, TaskName: task.Name, Minutes: make([]int, 7)})
+		added = append(added, strconv.FormatUint(id, 10))
+	}
+
+	weekParam := sheet.WeekStart.Format(time.DateOnly)
+	weekLink := func(label string, start time.Time) string {
+		return fmt.Sprintf(`<button type="button" hx-get="/app/timesheet?week=%s" hx-target="#main-content" class="text-sm text-blue-600 hover:text-blue-800">%s</button>`,
+			start.Format(time.DateOnly), label)
+	}
+	weekEnd := sheet.WeekStart.AddDate(0, 0, 6)
+
+	headerHTML := ""
+	for _, day := range sheet.Days {
+		headerHTML += fmt.Sprintf(`
+						<th class="px-2 py-2 text-center text-xs font-medium text-gray-500 uppercase">%s<br><span class="font-normal normal-case">%s</span></th>`,
+			day.Format("Mon"), day.Format("Jan 2"))
+	}
+
+	rowsHTML := ""
+	for _, row := range sheet.Rows {
+		name := row.TaskName
+		if name == "" {
+			name = fmt.Sprintf("Task #%d", row.TaskID)
+		}
+		cellsHTML := ""
+		for i, day := range sheet.Days {
+			cellsHTML += fmt.Sprintf(`
+						<td class="px-1 py-1"><input type="text" inputmode="decimal" value="%s" data-task="%d" data-date="%s" data-minutes="%d" aria-label="%s %s" class="w-16 px-2 py-1 text-sm text-right border border-gray-300 rounded-md focus:outline-none focus:ring-blue-500 focus:border-blue-500"></td>`,
+				formatClock(row.Minutes[i]), row.TaskID, day.Format(time.DateOnly), row.Minutes[i],
+				html.EscapeString(name), day.Format("Mon Jan 2"))
+		}
+		rowsHTML += fmt.Sprintf(`
+					<tr>
+						<td class="px-3 py-1 text-sm text-gray-900 max-w-xs truncate"><a href="#" onclick="showTaskDetail(%d); return false;" class="hover:text-blue-600">#%d %s</a></td>%s
+						<td class="px-3 py-1 text-sm text-right font-medium text-gray-900">%s</td>
+					</tr>`, row.TaskID, row.TaskID, html.EscapeString(name), cellsHTML, formatClock(row.Total))
+	}
+	if rowsHTML == "" {
+		rowsHTML = `
+					<tr><td colspan="9" class="px-3 py-4 text-sm text-gray-500">No time logged this week. Add a task to start filling in the grid.</td></tr>`
+	}
+
+	totalsHTML := ""
+	for _, minutes := range sheet.Totals {
+		totalsHTML += fmt.Sprintf(`
+						<td class="px-2 py-2 text-sm text-right font-medium text-gray-900">%s</td>`, formatClock(minutes))
+	}
+
+	// The add form keeps the rows added so far
+	keptHTML := ""
+	for _, id := range added {
+		keptHTML += fmt.Sprintf(`<input type="hidden" name="add" value="%s">`, id)
+	}
+
+	pageHTML := fmt.Sprintf(`
+	<div class="p-6">
+		<div class="mb-6 flex flex-wrap items-center justify-between gap-4">
+			<h2 class="text-2xl font-bold text-gray-900">Timesheet</h2>
+			<div class="flex items-center gap-4">
+				%s
+				<span class="text-sm font-medium text-gray-700">%s – %s</span>
+				%s
+				%s
+			</div>
+		</div>
+		%s
+		<form data-week="%s" onsubmit="saveTimesheet(this); return false;" class="bg-white shadow rounded-lg overflow-x-auto">
+			<table class="min-w-full divide-y divide-gray-200">
+				<thead class="bg-gray-50">
+					<tr>
+						<th class="px-3 py-2 text-left text-xs font-medium text-gray-500 uppercase">Task</th>%s
+						<th class="px-3 py-2 text-right text-xs font-medium text-gray-500 uppercase">Total</th>
+					</tr>
+				</thead>
+				<tbody class="divide-y divide-gray-100">%s
+				</tbody>
+				<tfoot class="bg-gray-50">
+					<tr>
+						<td class="px-3 py-2 text-sm font-medium text-gray-700">Total</td>%s
+						<td class="px-3 py-2 text-sm text-right font-bold text-gray-900">%s</td>
+					</tr>
+				</tfoot>
+			</table>
+			<div class="p-4 flex items-center justify-between gap-4 border-t border-gray-200">
+				<p class="text-xs text-gray-500" data-timesheet-status>Enter durations such as 1:30, 1h30m, 45m or 1.5 hours. Clearing a cell deletes its time.</p>
+				<button type="submit" class="brand-button px-4 py-2 text-sm font-medium text-white rounded-md">Save</button>
+			</div>
+		</form>
+		<form hx-get="/app/timesheet" hx-target="#main-content" class="mt-4 flex items-center gap-2">
+			<input type="hidden" name="week" value="%s">%s
+			<input type="text" name="add" placeholder="Task ID" required class="w-28 px-3 py-2 text-sm border border-gray-300 rounded-md">
+			<button type="submit" class="px-3 py-2 text-sm text-blue-600 hover:text-blue-800">Add row</button>
+			<span class="text-xs text-gray-500">Save your changes first; adding a row reloads the grid.</span>
+		</form>
+	</div>`,
+		weekLink("&larr; Previous", sheet.WeekStart.AddDate(0, 0, -7)),
+		sheet.WeekStart.Format("Jan 2"), weekEnd.Format("Jan 2, 2006"),
+		weekLink("Next &rarr;", sheet.WeekStart.AddDate(0, 0, 7)),
+		weekLink("This week", services.StartOfWeek(time.Now())),
+		errorHTML,
+		weekParam, headerHTML, rowsHTML, totalsHTML, formatClock(sheet.Total),
+		weekParam, keptHTML,
+	)
+
+	c.Header("Content-Type", "text/html")
+	c.String(http.StatusOK, pageHTML)
+}
@@ -0,0 +1,194 @@
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pelletier/go-toml/v2"
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is used when no supported language can be negotiated
+const DefaultLanguage = "en"
+
+// Catalogs are go-i18n TOML message files, active.<lang>.toml. A message is
+// either id = "text" or a table of plural forms (one, other, ...).
+//
+//go:embed locales/*.toml
+var localeFS embed.FS
+
+var (
+	loadOnce sync.Once
+	bundle   *goi18n.Bundle
+	catalogs map[string][]*goi18n.Message // each language's messages
+	loadErr  error
+)
+
+// Localizer translates message IDs for a single language, falling back to English
+type Localizer struct {
+	lang      string
+	localizer *goi18n.Localizer
+}
+
+// load parses every embedded catalog into the bundle once
+func load() {
+	bundle = goi18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
+	catalogs = make(map[string][]*goi18n.Message)
+
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		loadErr = fmt.Errorf("failed to read locales: %w", err)
+		return
+	}
+
+	for _, entry := range entries {
+		file, err := bundle.LoadMessageFileFS(localeFS, path.Join("locales", entry.Name()))
+		if err != nil {
+			loadErr = fmt.Errorf("failed to parse %s: %w", entry.Name(), err)
+			return
+		}
+		base, _ := file.Tag.Base()
+		catalogs[base.String()] = file.Messages
+	}
+}
+
+// SupportedLanguages returns the language codes that have a message catalog
+func SupportedLanguages() []string {
+	loadOnce.Do(load)
+
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// IsSupported reports whether a catalog exists for the language
+func IsSupported(lang string) bool {
+	loadOnce.Do(load)
+	_, ok := catalogs[normalize(lang)]
+	return ok
+}
+
+// Match returns the first supported language from the candidates, or DefaultLanguage
+func Match(candidates ...string) string {
+	loadOnce.Do(load)
+
+	for _, candidate := range candidates {
+		lang := normalize(candidate)
+		if _, ok := catalogs[lang]; ok {
+			return lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// ParseAcceptLanguage returns the language tags from an Accept-Language header in order of preference
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		tag := part
+		q := 1.0
+		if idx := strings.Index(part, ";"); idx >= 0 {
+			tag = strings.TrimSpace(part[:idx])
+			fmt.Sscanf(strings.TrimSpace(part[idx+1:]), "q=%g", &q)
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, t.tag)
+	}
+	return result
+}
+
+// normalize reduces a language tag such as "de-DE" or "es_MX.UTF-8" to its base language
+func normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if idx := strings.IndexAny(tag, "-_."); idx >= 0 {
+		tag = tag[:idx]
+	}
+	return tag
+}
+
+// NewLocalizer creates a localizer for the first supported language among the candidates
+func NewLocalizer(candidates ...string) *Localizer {
+	lang := Match(candidates...)
+	return &Localizer{
+		lang:      lang,
+		localizer: goi18n.NewLocalizer(bundle, lang, DefaultLanguage),
+	}
+}
+
+// Language returns the language code the localizer resolved to
+func (l *Localizer) Language() string {
+	return l.lang
+}
+
+// T translates a message ID. Optional data (a map or struct) fills in the
+// message's template fields, e.g. "Task #{{.ID}}". Unknown IDs are returned unchanged.
+func (l *Localizer) T(id string, data ...interface{}) string {
+	config := &goi18n.LocalizeConfig{MessageID: id}
+	if len(data) > 0 {
+		config.TemplateData = data[0]
+	}
+	return l.localize(id, config)
+}
+
+// N translates a message with plural forms for count, which the message can
+// use as {{.Count}} alongside the fields of data
+func (l *Localizer) N(id string, count int, data map[string]interface{}) string {
+	fields := map[string]interface{}{"Count": count}
+	for key, value := range data {
+		fields[key] = value
+	}
+	return l.localize(id, &goi18n.LocalizeConfig{MessageID: id, PluralCount: count, TemplateData: fields})
+}
+
+// localize returns the translation, the English message when the language
+// lacks it, or the ID when no catalog has it
+func (l *Localizer) localize(id string, config *goi18n.LocalizeConfig) string {
+	msg, err := l.localizer.Localize(config)
+	if msg == "" && err != nil {
+		return id
+	}
+	return msg
+}
+
+// LoadError returns any error encountered while parsing the embedded catalogs
+func LoadError() error {
+	loadOnce.Do(load)
+	return loadErr
+}
+
+// Status translates a task status value such as "in-progress"
+func (l *Localizer) Status(status string) string {
+	return l.T("status_" + strings.ReplaceAll(status, "-", "_"))
+}
+
+// Priority translates a task priority value
+func (l *Localizer) Priority(priority string) string {
+	return l.T("priority_" + priority)
+}
@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func TestCatalogsLoad(t *testing.T) {
+	if err := LoadError(); err != nil {
+		t.Fatalf("Failed to load catalogs: %v", err)
+	}
+
+	for _, lang := range []string{"en", "de", "es"} {
+		if !IsSupported(lang) {
+			t.Errorf("Expected %s to be supported", lang)
+		}
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	loadOnce.Do(load)
+
+	for lang, messages := range catalogs {
+		ids := make(map[string]bool, len(messages))
+		for _, msg := range messages {
+			ids[msg.ID] = true
+		}
+
+		for _, msg := range catalogs[DefaultLanguage] {
+			if !ids[msg.ID] {
+				t.Errorf("Catalog %s is missing message %q", lang, msg.ID)
+				continue
+			}
+			// Plural messages need every form the language uses
+			if msg.One != "" {
+				localizer := goi18n.NewLocalizer(bundle, lang)
+				for _, count := range []int{0, 1, 2, 5} {
+					if _, err := localizer.Localize(&goi18n.LocalizeConfig{MessageID: msg.ID, PluralCount: count}); err != nil {
+						t.Errorf("Catalog %s: message %q for %d: %v", lang, msg.ID, count, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		want       string
+	}{
+		{"exact", []string{"de"}, "de"},
+		{"region tag", []string{"es-MX"}, "es"},
+		{"posix locale", []string{"de_DE.UTF-8"}, "de"},
+		{"first supported wins", []string{"fr", "es", "de"}, "es"},
+		{"unsupported falls back", []string{"fr"}, DefaultLanguage},
+		{"empty falls back", []string{""}, DefaultLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(tt.candidates...); got != tt.want {
+				t.Errorf("Match(%v) = %s, want %s", tt.candidates, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := ParseAcceptLanguage("fr-CH, fr;q=0.9, de;q=0.95, *;q=0.5")
+	want := []string{"fr-CH", "de", "fr", "*"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAcceptLanguage() = %v, want %v", got, want)
+	}
+}
+
+func TestLocalizer_T(t *testing.T) {
+	de := NewLocalizer("de")
+
+	if got := de.T("nav_tasks"); got != "Aufgaben" {
+		t.Errorf("Expected German translation, got %q", got)
+	}
+
+	got := de.T("email_task_created_subject", map[string]interface{}{"Name": "Server neu starten"})
+	if got != "Neue Aufgabe: Server neu starten" {
+		t.Errorf("Expected templated translation, got %q", got)
+	}
+
+	if got := de.T("does_not_exist"); got != "does_not_exist" {
+		t.Errorf("Expected unknown ID to be returned unchanged, got %q", got)
+	}
+
+	if got := NewLocalizer("es").Status("in-progress"); got != "en curso" {
+		t.Errorf("Expected translated status, got %q", got)
+	}
+}
+
+func TestLocalizer_N(t *testing.T) {
+	en := NewLocalizer("en")
+	if got := en.N("cli_total_tasks", 1, nil); got != "Total: 1 task" {
+		t.Errorf("Expected singular, got %q", got)
+	}
+	if got := en.N("cli_total_tasks", 3, nil); got != "Total: 3 tasks" {
+		t.Errorf("Expected plural, got %q", got)
+	}
+	if got := NewLocalizer("de").N("cli_total_tasks", 1, nil); got != "Gesamt: 1 Aufgabe" {
+		t.Errorf("Expected German singular, got %q", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/soarinferret/jats/internal/config"
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/repository"
 	"github.com/soarinferret/jats/internal/routes"
@@ -51,6 +52,27 @@ func setupIntegrationTest() (*IntegrationTestSuite, error) {
 		&models.TaskSubscriber{},
 		&models.Attachment{},
 		&models.SavedQuery{},
+		&models.SavedQuerySchedule{},
+		&models.SavedQueryWebhook{},
+		&models.TaskStatusChange{},
+		&models.TaskReference{},
+		&models.Milestone{},
+		&models.BrandingSettings{},
+		&models.CannedResponse{},
+		&models.Contact{},
+		&models.QuarantinedEmail{},
+		&models.ExternalIssue{},
+		&models.ExternalComment{},
+		&models.NotificationMute{},
+		&models.OutOfOffice{},
+		&models.Asset{},
+		&models.Project{},
+		&models.TaskReview{},
+		&models.TaskDependency{},
+		&models.EmailReceipt{},
+		&models.ScheduledAction{},
+		&models.AutomationRule{},
+		&models.TaskAssignment{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
@@ -61,9 +83,23 @@ func setupIntegrationTest() (*IntegrationTestSuite, error) {
 	authRepo := repository.NewAuthRepository(db)
 	taskService := services.NewTaskService(taskRepo, nil)
 	authService := services.NewAuthService(authRepo, nil)
+	reportService := services.NewReportService(taskRepo)
+	settingsService := services.NewSettingsService(repository.NewSettingsRepository(db))
+	contactService := services.NewContactService(repository.NewContactRepository(db))
+	spamService := services.NewSpamService(nil, repository.NewQuarantineRepository(db), &config.SpamConfig{})
+	retentionService := services.NewRetentionService(taskRepo, authRepo, &config.RetentionConfig{})
 
 	// Setup test server
-	handler := routes.SetupRoutes(taskService, authService, authRepo)
+	handler := routes.SetupRoutes(routes.Services{
+		TaskService:      taskService,
+		AuthService:      authService,
+		AuthRepo:         authRepo,
+		ReportService:    reportService,
+		SettingsService:  settingsService,
+		ContactService:   contactService,
+		SpamService:      spamService,
+		RetentionService: retentionService,
+	})
 	server := httptest.NewServer(handler)
 
 	suite := &IntegrationTestSuite{
@@ -148,7 +184,7 @@ func (suite *IntegrationTestSuite) makeAuthenticatedRequest(method, path string,
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("X-API-Key", apiKey)
 
 	client := &http.Client{}
 	return client.Do(req)
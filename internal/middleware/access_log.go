@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// accessLogBodyLimit caps how much of a request body is kept for the log
+const accessLogBodyLimit = 8 << 10
+
+// redacted replaces secret values in logged bodies and paths
+const redacted = "[REDACTED]"
+
+// AccessLogEntry is one line of the access log
+type AccessLogEntry struct {
+	Time       time.Time   `json:"time"`
+	Method     string      `json:"method"`
+	Path       string      `json:"path"`
+	Route      string      `json:"route,omitempty"`
+	Status     int         `json:"status"`
+	LatencyMS  float64     `json:"latency_ms"`
+	Bytes      int         `json:"bytes"`
+	ClientIP   string      `json:"client_ip"`
+	User       string      `json:"user,omitempty"`
+	AuthMethod string      `json:"auth_method,omitempty"`
+	Body       interface{} `json:"request_body,omitempty"`
+	Errors     string      `json:"errors,omitempty"`
+}
+
+// AccessLogger writes a JSON line per request. Bodies are only logged for
+// failed requests (status 400 and up), with passwords, tokens and other secrets
+// redacted.
+type AccessLogger struct {
+	mu     sync.Mutex
+	out    io.Writer
+	closer io.Closer
+}
+
+// NewAccessLogger creates an access logger writing to w
+func NewAccessLogger(w io.Writer) *AccessLogger {
+	return &AccessLogger{out: w}
+}
+
+// OpenAccessLog creates an access logger for an output setting: "stdout",
+// "stderr" or the path of a file to append to
+func OpenAccessLog(output string) (*AccessLogger, error) {
+	switch output {
+	case "", "stdout":
+		return NewAccessLogger(os.Stdout), nil
+	case "stderr":
+		return NewAccessLogger(os.Stderr), nil
+	}
+
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open access log: %w", err)
+	}
+	logger := NewAccessLogger(file)
+	logger.closer = file
+	return logger, nil
+}
+
+// Close closes the log file, if any
+func (l *AccessLogger) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// Middleware logs every request passing through the router
+func (l *AccessLogger) Middleware() gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		start := time.Now()
+
+		var body bytes.Buffer
+		if c.Request.Body != nil {
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.TeeReader(c.Request.Body, &limitedBuffer{buf: &body, limit: accessLogBodyLimit}), c.Request.Body}
+		}
+
+		c.Next()
+
+		entry := AccessLogEntry{
+			Time:      start.UTC(),
+			Method:    c.Request.Method,
+			Path:      redactPath(c),
+			Route:     c.FullPath(),
+			Status:    c.Writer.Status(),
+			LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			Bytes:     max(c.Writer.Size(), 0),
+			ClientIP:  ClientIP(c.Request),
+			Errors:    c.Errors.String(),
+		}
+		if value, ok := c.Get("auth"); ok {
+			if auth, ok := value.(*models.AuthContext); ok && auth.User != nil {
+				entry.User = auth.User.Username
+				entry.AuthMethod = auth.AuthMethod
+			}
+		}
+		if entry.Status >= 400 && body.Len() > 0 {
+			entry.Body = RedactBody(c.ContentType(), body.Bytes(), body.Len() >= accessLogBodyLimit)
+		}
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		json.NewEncoder(l.out).Encode(entry)
+	})
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+// isSecretKey reports whether a JSON field, form field or route parameter holds a secret
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range []string{"password", "token", "secret", "totp", "authorization", "cookie"} {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return key == "key" || strings.HasSuffix(key, "_key") || strings.HasSuffix(key, "apikey")
+}
+
+// redactPath returns the request path with secret route parameters, such as feed
+// tokens, replaced
+func redactPath(c *gin.Context) string {
+	path := c.Request.URL.Path
+	for _, param := range c.Params {
+		if param.Value != "" && isSecretKey(param.Key) {
+			path = strings.ReplaceAll(path, param.Value, redacted)
+		}
+	}
+	return path
+}
+
+// RedactBody decodes a JSON or form body and replaces the values of secret
+// fields. Bodies that are truncated or cannot be decoded are left out entirely,
+// since their secrets cannot be found reliably.
+func RedactBody(contentType string, body []byte, truncated bool) interface{} {
+	if truncated {
+		return "[body too large to log]"
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "application/json"):
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			return "[invalid JSON omitted]"
+		}
+		return redactValue(value)
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "[invalid form omitted]"
+		}
+		fields := make(map[string]interface{}, len(values))
+		for key, list := range values {
+			if isSecretKey(key) {
+				fields[key] = redacted
+			} else if len(list) == 1 {
+				fields[key] = list[0]
+			} else {
+				fields[key] = list
+			}
+		}
+		return fields
+	}
+	return fmt.Sprintf("[%d bytes of %s omitted]", len(body), contentType)
+}
+
+// redactValue replaces secret fields throughout a decoded JSON value
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSecretKey(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+func TestAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var out bytes.Buffer
+	router := gin.New()
+	router.Use(NewAccessLogger(&out).Middleware())
+	router.Use(func(c *gin.Context) {
+		c.Set("auth", &models.AuthContext{User: &models.User{Username: "alice"}, AuthMethod: "api_key"})
+		c.Next()
+	})
+	router.POST("/login", func(c *gin.Context) {
+		c.Request.ParseForm()
+		c.Status(http.StatusUnauthorized)
+	})
+	router.POST("/api/v1/tasks", func(c *gin.Context) {
+		var body map[string]interface{}
+		c.ShouldBindJSON(&body)
+		c.Status(http.StatusBadRequest)
+	})
+	router.GET("/feeds/:token", func(c *gin.Context) {
+		c.String(http.StatusOK, "feed")
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		wantRoute   string
+		wantStatus  int
+		wantBody    bool
+		secret      string
+	}{
+		{name: "form login", method: "POST", path: "/login", contentType: "application/x-www-form-urlencoded", body: "username=alice&password=hunter2&totp_code=123456", wantRoute: "/login", wantStatus: 401, wantBody: true, secret: "hunter2"},
+		{name: "json body", method: "POST", path: "/api/v1/tasks", contentType: "application/json", body: `{"name":"x","nested":[{"api_key":"jats_secret"}],"token":"abc"}`, wantRoute: "/api/v1/tasks", wantStatus: 400, wantBody: true, secret: "jats_secret"},
+		{name: "feed token in path", method: "GET", path: "/feeds/supersecrettoken", wantRoute: "/feeds/:token", wantStatus: 200, secret: "supersecrettoken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out.Reset()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			line := out.String()
+			if strings.Contains(line, tt.secret) {
+				t.Fatalf("Access log leaks %q: %s", tt.secret, line)
+			}
+			var entry AccessLogEntry
+			if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+				t.Fatalf("Expected one JSON line, got %q: %v", line, err)
+			}
+			if entry.Method != tt.method || entry.Route != tt.wantRoute || entry.Status != tt.wantStatus {
+				t.Errorf("Unexpected entry %+v", entry)
+			}
+			if entry.User != "alice" || entry.AuthMethod != "api_key" {
+				t.Errorf("Expected user alice via api_key, got %q via %q", entry.User, entry.AuthMethod)
+			}
+			if (entry.Body != nil) != tt.wantBody {
+				t.Errorf("Expected request body logged: %v, got %v", tt.wantBody, entry.Body)
+			}
+			if tt.wantBody && !strings.Contains(line, redacted) {
+				t.Errorf("Expected redacted fields in %s", line)
+			}
+		})
+	}
+}
@@ -179,39 +179,6 @@ func IsAuthenticated(r *http.Request) bool {
 	return false
 }
 
-// CORS middleware to handle CORS headers
-func CORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
-		
-		// Handle preflight requests
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-		
-		next.ServeHTTP(w, r)
-	})
-}
-
-// SecurityHeaders middleware adds security headers
-func SecurityHeaders(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Security headers
-		w.Header().Set("X-Content-Type-Options", "nosniff")
-		w.Header().Set("X-Frame-Options", "DENY")
-		w.Header().Set("X-XSS-Protection", "1; mode=block")
-		w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
-		w.Header().Set("Content-Security-Policy", "default-src 'self'")
-		
-		next.ServeHTTP(w, r)
-	})
-}
-
 // RateLimiting middleware (basic implementation)
 func RateLimiting(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -221,15 +188,6 @@ func RateLimiting(next http.Handler) http.Handler {
 	})
 }
 
-// Logging middleware for request logging
-func Logging(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Basic request logging could be implemented here
-		// For now, just pass through
-		next.ServeHTTP(w, r)
-	})
-}
-
 // GinAuthMiddleware provides Gin-compatible authentication middleware
 type GinAuthMiddleware struct {
 	authService *services.AuthService
@@ -271,7 +229,7 @@ func (m *GinAuthMiddleware) RequireAuth() gin.HandlerFunc {
 		}
 		
 		// Add auth context to Gin context
-		c.Set(string(AuthContextKey), authContext)
+		setGinAuthContext(c, authContext)
 		c.Next()
 	})
 }
@@ -319,11 +277,20 @@ func (m *GinAuthMiddleware) RequirePermission(permission string) gin.HandlerFunc
 		}
 		
 		// Add auth context to Gin context
-		c.Set(string(AuthContextKey), authContext)
+		setGinAuthContext(c, authContext)
 		c.Next()
 	})
 }
 
+// setGinAuthContext exposes the auth context to native Gin handlers, frontend
+// handlers, and wrapped net/http handlers (via the request context)
+func setGinAuthContext(c *gin.Context, authContext *models.AuthContext) {
+	c.Set(string(AuthContextKey), authContext)
+	c.Set(AuthContextKey, authContext)
+	c.Set("auth", authContext)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), AuthContextKey, authContext))
+}
+
 // authenticateGin tries to authenticate the Gin request using session or API key
 func (m *GinAuthMiddleware) authenticateGin(c *gin.Context) (*models.AuthContext, error) {
 	// Try session authentication first
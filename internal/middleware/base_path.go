@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// BasePathContextKey holds the URL prefix a request was served under
+const BasePathContextKey contextKey = "base_path"
+
+// StripBasePath serves next under a URL prefix such as "/jats", for running
+// behind a reverse proxy sub-path. Handlers see paths without the prefix and can
+// get it back with BasePath to build links, redirects and cookie paths. The bare
+// prefix redirects to prefix + "/" and paths outside the prefix are not found.
+func StripBasePath(basePath string, next http.Handler) http.Handler {
+	if basePath == "" {
+		return next
+	}
+
+	stripped := http.StripPrefix(basePath, next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == basePath {
+			http.Redirect(w, r, basePath+"/", http.StatusMovedPermanently)
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, basePath+"/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), BasePathContextKey, basePath)
+		stripped.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// BasePath returns the URL prefix the request was served under, or an empty
+// string when the app is served at the root
+func BasePath(r *http.Request) string {
+	if basePath, ok := r.Context().Value(BasePathContextKey).(string); ok {
+		return basePath
+	}
+	return ""
+}
+
+// CookiePath returns the path cookies should be scoped to for this request
+func CookiePath(r *http.Request) string {
+	if basePath := BasePath(r); basePath != "" {
+		return basePath
+	}
+	return "/"
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStripBasePath(t *testing.T) {
+	var gotPath, gotBase string
+	handler := StripBasePath("/jats", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotBase = BasePath(r)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		path     string
+		status   int
+		location string
+		handled  string
+	}{
+		{path: "/jats/app/tasks", status: http.StatusOK, handled: "/app/tasks"},
+		{path: "/jats/", status: http.StatusOK, handled: "/"},
+		{path: "/jats", status: http.StatusMovedPermanently, location: "/jats/"},
+		{path: "/app/tasks", status: http.StatusNotFound},
+		{path: "/jatsx/app", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		gotPath, gotBase = "", ""
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.status, w.Code)
+		}
+		if tt.location != "" && w.Header().Get("Location") != tt.location {
+			t.Errorf("%s: expected redirect to %s, got %s", tt.path, tt.location, w.Header().Get("Location"))
+		}
+		if gotPath != tt.handled {
+			t.Errorf("%s: expected handler to see %q, got %q", tt.path, tt.handled, gotPath)
+		}
+		if tt.handled != "" && gotBase != "/jats" {
+			t.Errorf("%s: expected base path /jats, got %q", tt.path, gotBase)
+		}
+	}
+}
+
+func TestStripBasePathAtRoot(t *testing.T) {
+	handler := StripBasePath("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if BasePath(r) != "" || CookiePath(r) != "/" {
+			t.Errorf("Expected no base path and cookie path /, got %q and %q", BasePath(r), CookiePath(r))
+		}
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/app/tasks", nil))
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request body limits used by the routes
+const (
+	// JSONBodyLimit caps API request bodies
+	JSONBodyLimit int64 = 1 << 20 // 1 MiB
+	// BulkBodyLimit caps bulk import endpoints, which carry up to
+	// services.MaxBulkEntries entries
+	BulkBodyLimit int64 = 8 << 20 // 8 MiB
+	// FormBodyLimit caps web UI form posts
+	FormBodyLimit int64 = 1 << 20 // 1 MiB
+	// UploadBodyLimit caps routes that accept file uploads
+	UploadBodyLimit int64 = 32 << 20 // 32 MiB
+)
+
+// MaxBodySize rejects requests whose declared Content-Length exceeds limit with
+// 413 and caps the body read by handlers at limit bytes, so chunked or lying
+// requests fail instead of exhausting memory. Limits do not stack upwards, so a
+// route needing more than its group allows must be registered outside the group.
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"success": false,
+				"error": map[string]string{
+					"code":    "PAYLOAD_TOO_LARGE",
+					"message": fmt.Sprintf("Request body exceeds the limit of %d bytes", limit),
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+
+		c.Next()
+	})
+}
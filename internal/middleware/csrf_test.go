@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireCSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use((&GinAuthMiddleware{}).RequireCSRF())
+	router.Any("/app/tasks", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	session := &http.Cookie{Name: "session_token", Value: "session-abc"}
+	token := CSRFToken(session.Value)
+
+	tests := []struct {
+		name   string
+		method string
+		cookie bool
+		header string
+		form   string
+		want   int
+	}{
+		{name: "safe method", method: "GET", cookie: true, want: http.StatusOK},
+		{name: "no session cookie", method: "POST", want: http.StatusOK},
+		{name: "missing token", method: "POST", cookie: true, want: http.StatusForbidden},
+		{name: "wrong token", method: "DELETE", cookie: true, header: CSRFToken("other-session"), want: http.StatusForbidden},
+		{name: "header token", method: "PUT", cookie: true, header: token, want: http.StatusOK},
+		{name: "form token", method: "POST", cookie: true, form: token, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := url.Values{}
+			if tt.form != "" {
+				body.Set(CSRFFormField, tt.form)
+			}
+			req := httptest.NewRequest(tt.method, "/app/tasks", strings.NewReader(body.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.cookie {
+				req.AddCookie(session)
+			}
+			if tt.header != "" {
+				req.Header.Set(CSRFHeader, tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
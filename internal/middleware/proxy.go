@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientContextKey holds the resolved client of a request
+const ClientContextKey contextKey = "client"
+
+// Client is the real client of a request, as seen through trusted reverse proxies
+type Client struct {
+	IP     string
+	Scheme string // "http" or "https"
+}
+
+// TrustedProxies is the set of reverse proxy addresses whose X-Forwarded-For and
+// X-Forwarded-Proto headers are believed
+type TrustedProxies struct {
+	networks []*net.IPNet
+}
+
+// ParseTrustedProxies parses IP addresses and CIDR ranges such as "10.0.0.1" or "172.16.0.0/12"
+func ParseTrustedProxies(entries []string) (*TrustedProxies, error) {
+	proxies := &TrustedProxies{}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			if ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+		}
+		proxies.networks = append(proxies.networks, network)
+	}
+	return proxies, nil
+}
+
+// Contains reports whether ip is one of the trusted proxies
+func (p *TrustedProxies) Contains(ip string) bool {
+	if p == nil {
+		return false
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range p.networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve determines the real client of a request. Forwarding headers are only
+// used when the connection comes from a trusted proxy; X-Forwarded-For is read
+// right to left, skipping trusted proxies, so a client cannot spoof its address
+// by sending the header itself.
+func (p *TrustedProxies) Resolve(r *http.Request) Client {
+	remoteIP := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		remoteIP = host
+	}
+
+	client := Client{IP: remoteIP, Scheme: "http"}
+	if r.TLS != nil {
+		client.Scheme = "https"
+	}
+	if !p.Contains(remoteIP) {
+		return client
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		client.IP = hop
+		if !p.Contains(hop) {
+			break
+		}
+	}
+
+	if proto := strings.ToLower(strings.TrimSpace(r.Header.Get("X-Forwarded-Proto"))); proto == "http" || proto == "https" {
+		client.Scheme = proto
+	}
+
+	return client
+}
+
+// RealClient resolves the client address and scheme of each request through the
+// trusted proxies and makes them available via ClientIP, IsSecure and RequestScheme.
+// RemoteAddr is rewritten to the client address so that other code sees it too.
+func RealClient(proxies *TrustedProxies, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := proxies.Resolve(r)
+		r = r.WithContext(context.WithValue(r.Context(), ClientContextKey, client))
+		r.RemoteAddr = net.JoinHostPort(client.IP, "0")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientOf returns the resolved client of a request, falling back to the direct
+// connection when RealClient is not in use (e.g. in tests)
+func clientOf(r *http.Request) Client {
+	if client, ok := r.Context().Value(ClientContextKey).(Client); ok {
+		return client
+	}
+	var none *TrustedProxies
+	return none.Resolve(r)
+}
+
+// ClientIP returns the IP address of the client that made the request
+func ClientIP(r *http.Request) string {
+	return clientOf(r).IP
+}
+
+// RequestScheme returns "https" when the client connected over TLS, directly or
+// through a trusted proxy, and "http" otherwise
+func RequestScheme(r *http.Request) string {
+	return clientOf(r).Scheme
+}
+
+// IsSecure reports whether the client connected over HTTPS; cookies set on such
+// requests are marked Secure
+func IsSecure(r *http.Request) bool {
+	return RequestScheme(r) == "https"
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrustedProxiesResolve(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.1", "172.16.0.0/12"})
+	if err != nil {
+		t.Fatalf("Failed to parse trusted proxies: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		proto      string
+		wantIP     string
+		wantScheme string
+	}{
+		{name: "direct connection", remoteAddr: "203.0.113.5:4000", wantIP: "203.0.113.5", wantScheme: "http"},
+		{name: "untrusted peer cannot spoof", remoteAddr: "203.0.113.5:4000", forwarded: "1.2.3.4", proto: "https", wantIP: "203.0.113.5", wantScheme: "http"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.1:4000", forwarded: "198.51.100.7", proto: "https", wantIP: "198.51.100.7", wantScheme: "https"},
+		{name: "proxy chain", remoteAddr: "10.0.0.1:4000", forwarded: "1.2.3.4, 198.51.100.7, 172.20.0.3", wantIP: "198.51.100.7", wantScheme: "http"},
+		{name: "garbage header", remoteAddr: "10.0.0.1:4000", forwarded: "not-an-ip", wantIP: "10.0.0.1", wantScheme: "http"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+
+			client := proxies.Resolve(req)
+			if client.IP != tt.wantIP || client.Scheme != tt.wantScheme {
+				t.Errorf("Expected %s over %s, got %s over %s", tt.wantIP, tt.wantScheme, client.IP, client.Scheme)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesRejectsInvalid(t *testing.T) {
+	if _, err := ParseTrustedProxies([]string{"proxy.local"}); err == nil {
+		t.Error("Expected an error for a host name")
+	}
+}
+
+func TestRealClient(t *testing.T) {
+	proxies, _ := ParseTrustedProxies([]string{"127.0.0.1"})
+	handler := RealClient(proxies, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ClientIP(r) != "198.51.100.7" || !IsSecure(r) {
+			t.Errorf("Expected secure request from 198.51.100.7, got %s (secure %t)", ClientIP(r), IsSecure(r))
+		}
+		if r.RemoteAddr != "198.51.100.7:0" {
+			t.Errorf("Expected RemoteAddr to be rewritten, got %s", r.RemoteAddr)
+		}
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "127.0.0.1:5000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
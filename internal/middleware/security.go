@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/config"
+)
+
+// DefaultContentSecurityPolicy allows the web UI's own scripts and the CDNs it
+// loads htmx, Tailwind, ECharts and Swagger UI from. Inline scripts and styles
+// are allowed because the pages use them throughout; framing is forbidden.
+const DefaultContentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' 'unsafe-inline' https://unpkg.com https://cdn.tailwindcss.com https://go-echarts.github.io; " +
+	"style-src 'self' 'unsafe-inline' https://unpkg.com; " +
+	"img-src 'self' data: https:; " +
+	"connect-src 'self'; " +
+	"frame-ancestors 'none'; " +
+	"base-uri 'self'; " +
+	"form-action 'self'"
+
+// apiContentSecurityPolicy applies to JSON responses, which never load anything
+const apiContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+
+// isAPIPath reports whether a (base path stripped) request path belongs to the
+// JSON API. The API docs are a web page and get the web UI's policy.
+func isAPIPath(path string) bool {
+	return strings.HasPrefix(path, "/api/") && path != "/api/v1/docs"
+}
+
+// SecurityHeaders sets Content-Security-Policy, X-Frame-Options, Referrer-Policy
+// and related headers on every response, and Strict-Transport-Security on HTTPS
+// requests when enabled.
+func SecurityHeaders(cfg *config.SecurityConfig, next http.Handler) http.Handler {
+	policy := DefaultContentSecurityPolicy
+	hsts := false
+	if cfg != nil {
+		if strings.TrimSpace(cfg.ContentSecurityPolicy) != "" {
+			policy = cfg.ContentSecurityPolicy
+		}
+		hsts = cfg.HSTS
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		if isAPIPath(r.URL.Path) {
+			header.Set("Content-Security-Policy", apiContentSecurityPolicy)
+		} else {
+			header.Set("Content-Security-Policy", policy)
+		}
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+		header.Set("Permissions-Policy", "camera=(), geolocation=(), payment=()")
+		if hsts && IsSecure(r) {
+			header.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// corsAllowedHeaders are the request headers API clients may send cross-origin
+const corsAllowedHeaders = "Content-Type, Authorization, X-API-Key, " + CSRFHeader
+
+// CORS answers cross-origin requests to the API from the configured origins, so
+// browser apps and extensions can call it. Origins are matched exactly; "*"
+// allows any origin but is never combined with credentials. Preflight requests
+// are answered directly; requests from other origins get no CORS headers and are
+// blocked by the browser.
+func CORS(cfg *config.CORSConfig, next http.Handler) http.Handler {
+	allowed := make(map[string]bool)
+	anyOrigin := false
+	credentials := false
+	maxAge := 0
+	if cfg != nil {
+		for _, origin := range cfg.AllowedOrigins {
+			origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+			if origin == "*" {
+				anyOrigin = true
+			} else if origin != "" {
+				allowed[strings.ToLower(origin)] = true
+			}
+		}
+		credentials = cfg.AllowCredentials
+		maxAge = cfg.MaxAge
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if !isAPIPath(r.URL.Path) || origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		listed := allowed[strings.ToLower(origin)]
+		if listed || anyOrigin {
+			if listed && credentials {
+				header.Set("Access-Control-Allow-Origin", origin)
+				header.Set("Access-Control-Allow-Credentials", "true")
+			} else if listed {
+				header.Set("Access-Control-Allow-Origin", origin)
+			} else {
+				header.Set("Access-Control-Allow-Origin", "*")
+			}
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if header.Get("Access-Control-Allow-Origin") != "" {
+				header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+				header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+				if maxAge > 0 {
+					header.Set("Access-Control-Max-Age", strconv.Itoa(maxAge))
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
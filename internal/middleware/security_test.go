@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/config"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := SecurityHeaders(&config.SecurityConfig{HSTS: true}, next)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/app", nil))
+	if got := rec.Header().Get("Content-Security-Policy"); got != DefaultContentSecurityPolicy {
+		t.Errorf("Expected default CSP on UI pages, got %q", got)
+	}
+	if got := rec.Header().Get("X-Frame-Options"); got != "DENY" {
+		t.Errorf("Expected X-Frame-Options DENY, got %q", got)
+	}
+	if got := rec.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("Expected no HSTS over plain HTTP, got %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "https://example.com/api/v1/tasks", nil)
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Content-Security-Policy"); got != apiContentSecurityPolicy {
+		t.Errorf("Expected API CSP, got %q", got)
+	}
+	if got := rec.Header().Get("Strict-Transport-Security"); got == "" {
+		t.Error("Expected HSTS over HTTPS")
+	}
+}
+
+func TestCORS(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name            string
+		cfg             config.CORSConfig
+		path            string
+		origin          string
+		preflight       bool
+		wantOrigin      string
+		wantCredentials bool
+		wantStatus      int
+	}{
+		{name: "wildcard", cfg: config.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, path: "/api/v1/tasks", origin: "https://a.example", wantOrigin: "*", wantStatus: http.StatusOK},
+		{name: "listed origin with credentials", cfg: config.CORSConfig{AllowedOrigins: []string{"https://a.example/"}, AllowCredentials: true}, path: "/api/v1/tasks", origin: "https://a.example", wantOrigin: "https://a.example", wantCredentials: true, wantStatus: http.StatusOK},
+		{name: "unlisted origin", cfg: config.CORSConfig{AllowedOrigins: []string{"https://a.example"}}, path: "/api/v1/tasks", origin: "https://evil.example", wantStatus: http.StatusOK},
+		{name: "web UI is not shared", cfg: config.CORSConfig{AllowedOrigins: []string{"*"}}, path: "/app", origin: "https://a.example", wantStatus: http.StatusOK},
+		{name: "preflight", cfg: config.CORSConfig{AllowedOrigins: []string{"chrome-extension://abc"}}, path: "/api/v1/tasks", origin: "chrome-extension://abc", preflight: true, wantOrigin: "chrome-extension://abc", wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.preflight {
+				req.Method = http.MethodOptions
+				req.Header.Set("Access-Control-Request-Method", "POST")
+			}
+			req.Header.Set("Origin", tt.origin)
+
+			rec := httptest.NewRecorder()
+			CORS(&tt.cfg, next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.wantOrigin, got)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Credentials") == "true"; got != tt.wantCredentials {
+				t.Errorf("Expected credentials %v, got %v", tt.wantCredentials, got)
+			}
+			if tt.preflight && rec.Header().Get("Access-Control-Allow-Headers") == "" {
+				t.Error("Expected allowed headers on preflight")
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TenantContextKey holds the slug of the tenant a request is for
+const TenantContextKey contextKey = "tenant"
+
+// TenantLookup returns the handler serving a tenant, or false if there is no such tenant
+type TenantLookup func(slug string) (http.Handler, bool)
+
+// Tenants routes requests for a tenant to that tenant's handler and everything
+// else to main. In "path" mode tenants live under /t/{slug}/, which is added to
+// the base path so links and cookies stay within the tenant. In "subdomain"
+// mode they live at {slug}.{domain}. Unknown tenants are not found.
+func Tenants(mode, domain string, main http.Handler, lookup TenantLookup) http.Handler {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var slug string
+		if mode == "subdomain" {
+			host := r.Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			label, ok := strings.CutSuffix(strings.ToLower(host), "."+domain)
+			if !ok || domain == "" {
+				main.ServeHTTP(w, r)
+				return
+			}
+			slug = label
+		} else {
+			rest, ok := strings.CutPrefix(r.URL.Path, "/t/")
+			if !ok {
+				main.ServeHTTP(w, r)
+				return
+			}
+			var found bool
+			slug, rest, found = strings.Cut(rest, "/")
+			if !found {
+				http.Redirect(w, r, BasePath(r)+r.URL.Path+"/", http.StatusMovedPermanently)
+				return
+			}
+			r = stripTenantPath(r, "/t/"+slug, "/"+rest)
+		}
+
+		handler, ok := lookup(slug)
+		if slug == "" || strings.Contains(slug, ".") || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), TenantContextKey, slug)))
+	})
+}
+
+// stripTenantPath serves r at path, adding prefix to its base path
+func stripTenantPath(r *http.Request, prefix, path string) *http.Request {
+	ctx := context.WithValue(r.Context(), BasePathContextKey, BasePath(r)+prefix)
+	stripped := r.Clone(ctx)
+	stripped.URL.Path = path
+	stripped.URL.RawPath = ""
+	return stripped
+}
+
+// Tenant returns the slug of the tenant a request is for, or an empty string
+// for the main instance
+func Tenant(r *http.Request) string {
+	slug, _ := r.Context().Value(TenantContextKey).(string)
+	return slug
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTenants(t *testing.T) {
+	var served, gotPath, gotBase string
+	serve := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			served, gotPath, gotBase = name, r.URL.Path, BasePath(r)
+		})
+	}
+	lookup := func(slug string) (http.Handler, bool) {
+		if slug == "acme" {
+			return serve("acme"), true
+		}
+		return nil, false
+	}
+
+	tests := []struct {
+		mode, host, path string
+		base             string // Base path set by StripBasePath
+		status           int
+		served           string
+		handled          string
+		handledBase      string
+	}{
+		{mode: "path", path: "/app/tasks", status: http.StatusOK, served: "main", handled: "/app/tasks"},
+		{mode: "path", path: "/t/acme/app/tasks", status: http.StatusOK, served: "acme", handled: "/app/tasks", handledBase: "/t/acme"},
+		{mode: "path", path: "/t/acme/app/tasks", base: "/jats", status: http.StatusOK, served: "acme", handled: "/app/tasks", handledBase: "/jats/t/acme"},
+		{mode: "path", path: "/t/acme", status: http.StatusMovedPermanently},
+		{mode: "path", path: "/t/other/app/tasks", status: http.StatusNotFound},
+		{mode: "subdomain", host: "jats.example.com", path: "/app/tasks", status: http.StatusOK, served: "main", handled: "/app/tasks"},
+		{mode: "subdomain", host: "acme.jats.example.com:8080", path: "/app/tasks", status: http.StatusOK, served: "acme", handled: "/app/tasks"},
+		{mode: "subdomain", host: "x.acme.jats.example.com", path: "/", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		served, gotPath, gotBase = "", "", ""
+		handler := Tenants(tt.mode, "jats.example.com", serve("main"), lookup)
+
+		req := httptest.NewRequest("GET", tt.path, nil)
+		if tt.host != "" {
+			req.Host = tt.host
+		}
+		if tt.base != "" {
+			req = req.WithContext(context.WithValue(req.Context(), BasePathContextKey, tt.base))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s%s: expected status %d, got %d", tt.host, tt.path, tt.status, w.Code)
+		}
+		if served != tt.served || gotPath != tt.handled || gotBase != tt.handledBase {
+			t.Errorf("%s%s: expected %s to see %q under %q, got %s seeing %q under %q",
+				tt.host, tt.path, tt.served, tt.handled, tt.handledBase, served, gotPath, gotBase)
+		}
+	}
+}
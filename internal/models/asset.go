@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Asset is a piece of equipment that tasks are done on, such as a server or a
+// boiler, so its maintenance history can be looked up in one place
+type Asset struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"not null"`
+	Serial    string         `json:"serial,omitempty" gorm:"index"`
+	Location  string         `json:"location,omitempty"`
+	Notes     string         `json:"notes,omitempty"`
+	Tasks     []Task         `json:"tasks,omitempty" gorm:"many2many:asset_tasks;"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	// Number of linked tasks, filled in when listing assets
+	TaskCount int `json:"task_count" gorm:"-"`
+}
@@ -12,9 +12,16 @@ type User struct {
 	Username        string         `json:"username" gorm:"uniqueIndex;not null"`
 	Email           string         `json:"email" gorm:"uniqueIndex;not null"`
 	HashedPassword  string         `json:"-" gorm:"not null"` // Never return in JSON
-	TOTPSecret      string         `json:"-" gorm:"column:totp_secret"` // Never return in JSON
+	TOTPSecret      string         `json:"-" gorm:"column:totp_secret;serializer:encrypted"` // Never return in JSON; encrypted at rest
 	TOTPEnabled     bool           `json:"totp_enabled" gorm:"default:false"`
 	IsActive        bool           `json:"is_active" gorm:"default:true"`
+	Language        string         `json:"language" gorm:"default:en"` // Preferred UI/email language
+	StandupEmail    bool           `json:"standup_email" gorm:"default:false"` // Opted in to the morning standup email
+	WeeklyCapacity  int            `json:"weekly_capacity" gorm:"default:0"` // Minutes of work available per week, 0 when not set
+	DashboardLayout *DashboardLayout `json:"dashboard_layout,omitempty" gorm:"serializer:json"` // nil until the user customizes their dashboard
+	LandingView     string         `json:"landing_view,omitempty"`                          // view the web UI and TUI open on: tasks, kanban, reports or dashboard; empty for tasks
+	LandingQueryID  *uint          `json:"landing_query_id,omitempty"`                      // saved query the landing view opens with
+	PinnedQueryIDs  []uint         `json:"pinned_query_ids,omitempty" gorm:"serializer:json"` // saved queries shown first in the sidebar
 	LastLoginAt     *time.Time     `json:"last_login_at,omitempty"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
@@ -77,6 +84,7 @@ const (
 	PermissionDeleteTasks = "tasks:delete"
 	PermissionReadTime    = "time:read"
 	PermissionWriteTime   = "time:write"
+	PermissionReadEmails  = "emails:read" // Raw email exchanges, which may hold customer data
 	PermissionAdmin       = "admin:all"
 )
 
@@ -98,6 +106,7 @@ func AdminPermissions() []string {
 		PermissionDeleteTasks,
 		PermissionReadTime,
 		PermissionWriteTime,
+		PermissionReadEmails,
 		PermissionAdmin,
 	}
 }
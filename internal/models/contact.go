@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Contact is a person who has written in by email
+type Contact struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email" gorm:"uniqueIndex;not null"`
+	Organization string    `json:"organization,omitempty"`
+	Tasks        []Task    `json:"tasks,omitempty" gorm:"many2many:contact_tasks;"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Number of linked tasks, filled in when listing contacts
+	TaskCount int `json:"task_count" gorm:"-"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// DashboardWidgetType identifies what a dashboard widget shows
+type DashboardWidgetType string
+
+const (
+	DashboardWidgetTaskCount  DashboardWidgetType = "task_count"      // number of tasks, optionally by status and saved query
+	DashboardWidgetQueryChart DashboardWidgetType = "query_chart"     // time logged per day on a saved query's tasks
+	DashboardWidgetActivity   DashboardWidgetType = "recent_activity" // latest entries of the activity feed
+	DashboardWidgetTimeLogged DashboardWidgetType = "time_logged"     // time logged today and this week
+)
+
+// DashboardWidget is one configurable tile on a user's dashboard. Only the
+// fields relevant to its type are used.
+type DashboardWidget struct {
+	Type         DashboardWidgetType `json:"type"`
+	Title        string              `json:"title,omitempty"`
+	Status       TaskStatus          `json:"status,omitempty"`         // task_count
+	SavedQueryID uint                `json:"saved_query_id,omitempty"` // task_count (optional), query_chart
+	Days         int                 `json:"days,omitempty"`           // query_chart
+	Limit        int                 `json:"limit,omitempty"`          // recent_activity
+	Width        int                 `json:"width,omitempty"`          // grid columns, 1-3
+}
+
+// DashboardLayout is the ordered list of widgets a user has on their dashboard
+type DashboardLayout struct {
+	Widgets []DashboardWidget `json:"widgets"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/soarinferret/jats/internal/secrets"
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// EncryptedSerializer stores a string column encrypted with the default
+// keyring (see secrets.SetDefault). Tag sensitive fields with
+// `gorm:"serializer:encrypted"`.
+type EncryptedSerializer struct{}
+
+// Scan decrypts a stored value into the field
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return fmt.Errorf("unsupported value for encrypted column %s: %T", field.DBName, dbValue)
+	}
+
+	plaintext, err := secrets.Default().Decrypt(stored)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", field.DBName, err)
+	}
+	return field.Set(ctx, dst, plaintext)
+}
+
+// Value encrypts the field for storage
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted column %s must be a string, got %T", field.DBName, fieldValue)
+	}
+	return secrets.Default().Encrypt(plaintext)
+}
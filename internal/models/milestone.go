@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Milestone groups tasks working towards a target date
+type Milestone struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"not null"`
+	Description string         `json:"description,omitempty"`
+	DueDate     *time.Time     `json:"due_date,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// IsOverdue reports whether the due date has passed
+func (m *Milestone) IsOverdue(now time.Time) bool {
+	return m.DueDate != nil && now.After(*m.DueDate)
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// NotificationMute silences notifications for one user about a task, or about
+// every task with a tag, until it expires
+type NotificationMute struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	TaskID    *uint     `json:"task_id,omitempty" gorm:"index"` // Set for a task mute
+	Tag       string    `json:"tag,omitempty"`                  // Set for a tag mute
+	Until     time.Time `json:"until" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Covers reports whether the mute applies to the task
+func (m *NotificationMute) Covers(task *Task) bool {
+	if m.TaskID != nil {
+		return *m.TaskID == task.ID
+	}
+	for _, tag := range task.Tags {
+		if strings.EqualFold(tag, m.Tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// OutOfOffice marks a user as away, for a vacation or a public holiday, from
+// the start day through the end day. Unavailable users are skipped by
+// auto-assignment, the standup email and the automation rules.
+type OutOfOffice struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	Username  string    `json:"username" gorm:"not null;index"`
+	StartDate time.Time `json:"start_date" gorm:"not null;index"` // Midnight of the first day away
+	EndDate   time.Time `json:"end_date" gorm:"not null;index"`   // Midnight of the last day away
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Covers reports whether the user is away at t
+func (o *OutOfOffice) Covers(t time.Time) bool {
+	return !t.Before(o.StartDate) && t.Before(o.EndDate.AddDate(0, 0, 1))
+}
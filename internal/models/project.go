@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultProjectName is the name of the project created for the tasks that
+// existed before projects did. New tasks go to the default project unless
+// given another.
+const DefaultProjectName = "Default"
+
+// Project is a workspace grouping tasks and saved queries, such as a client or
+// a line of work. Every task belongs to exactly one project.
+type Project struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"not null"`
+	Description string         `json:"description,omitempty"`
+	IsDefault   bool           `json:"is_default" gorm:"not null;default:false"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+
+	// Number of open and in-progress tasks, filled in when listing projects
+	OpenTasks int `json:"open_tasks" gorm:"-"`
+}
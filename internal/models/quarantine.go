@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// QuarantinedEmail is an inbound email held back from the task stream because
+// the spam filter scored it above the quarantine threshold
+type QuarantinedEmail struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	MessageID  string    `json:"message_id" gorm:"index"`
+	InReplyTo  string    `json:"in_reply_to,omitempty"`
+	Subject    string    `json:"subject"`
+	FromName   string    `json:"from_name,omitempty"`
+	From       string    `json:"from" gorm:"not null"`
+	Score      float64   `json:"score"`
+	Action     string    `json:"action"`                                   // Action suggested by the spam filter, e.g. "add header"
+	Symbols    []string  `json:"symbols,omitempty" gorm:"serializer:json"` // Rules that matched
+	RawMessage []byte    `json:"-"`                                        // Full RFC 822 message, kept so it can be released
+	ReceivedAt time.Time `json:"received_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
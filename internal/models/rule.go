@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Triggers an AutomationRule can fire on
+const (
+	RuleTriggerCreated       = "created"
+	RuleTriggerTagAdded      = "tag_added"
+	RuleTriggerStatusChanged = "status_changed"
+	RuleTriggerEmailReceived = "email_received"
+)
+
+// AutomationRule is an admin-defined rule: when its trigger fires on a task
+// that meets every set condition, its actions are applied. Empty conditions
+// match anything and empty actions are skipped.
+type AutomationRule struct {
+	ID      uint   `json:"id" gorm:"primaryKey"`
+	Name    string `json:"name" gorm:"not null"`
+	Enabled bool   `json:"enabled"`
+	Trigger string `json:"trigger" gorm:"not null;index"`
+
+	// Conditions
+	Tag      string       `json:"tag,omitempty"`      // Task has this tag; for tag_added, the added tag
+	Priority TaskPriority `json:"priority,omitempty"` // Task has this priority
+	Status   TaskStatus   `json:"status,omitempty"`   // Task is in this status; for status_changed, the new status
+	Sender   string       `json:"sender,omitempty"`   // Email sender contains this, e.g. "@example.com"; email_received only
+
+	// Actions
+	SetAssignee string `json:"set_assignee,omitempty"`
+	AddTag      string `json:"add_tag,omitempty"`
+	Notify      bool   `json:"notify"`                // Email active JATS users
+	WebhookURL  string `json:"webhook_url,omitempty"` // POST the task as JSON
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
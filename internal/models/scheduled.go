@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Kinds of ScheduledAction
+const (
+	ScheduledActionComment = "comment"
+	ScheduledActionStatus  = "status"
+)
+
+// ScheduledAction is a comment or status change deferred until RunAt, run by
+// the job scheduler
+type ScheduledAction struct {
+	ID      uint       `json:"id" gorm:"primaryKey"`
+	TaskID  uint       `json:"task_id" gorm:"not null;index"`
+	Kind    string     `json:"kind" gorm:"not null"`
+	Content string     `json:"content,omitempty" gorm:"type:text"` // Comment to post
+	Status  TaskStatus `json:"status,omitempty"`                   // Status to move to
+	// Skip the action if a reply arrives by email after it was scheduled,
+	// e.g. "close in 14 days if no reply"
+	UnlessReply bool      `json:"unless_reply"`
+	RunAt       time.Time `json:"run_at" gorm:"not null;index"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// BrandingSettingsID is the primary key of the single branding settings row
+const BrandingSettingsID = 1
+
+// BrandingSettings holds instance-wide white-label settings
+type BrandingSettings struct {
+	ID           uint      `json:"-" gorm:"primaryKey"`
+	InstanceName string    `json:"instance_name" gorm:"not null"`
+	Tagline      string    `json:"tagline"`
+	LogoURL      string    `json:"logo_url"`
+	PrimaryColor string    `json:"primary_color"` // Hex color, e.g. #2563eb
+	FooterText   string    `json:"footer_text"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DefaultBrandingSettings returns the stock JATS branding
+func DefaultBrandingSettings() *BrandingSettings {
+	return &BrandingSettings{
+		ID:           BrandingSettingsID,
+		InstanceName: "JATS",
+		PrimaryColor: "#2563eb",
+	}
+}
+
+// CannedResponse is an admin-managed reply snippet. Content may reference the
+// task being replied to with placeholders such as {{task.id}} and {{task.name}}.
+type CannedResponse struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"uniqueIndex;not null"` // Short handle, e.g. "thanks"
+	Content   string    `json:"content" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ExternalIssue links a task to the issue mirroring it in an external tracker
+// such as Jira or GitLab
+type ExternalIssue struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	TaskID uint   `json:"task_id" gorm:"not null;uniqueIndex:idx_external_issue_task_target"`
+	Target string `json:"target" gorm:"not null;uniqueIndex:idx_external_issue_task_target"` // name of the [sync.<name>] target
+	Key    string `json:"key" gorm:"not null"`                                               // e.g. OPS-123, or the GitLab issue IID
+	URL    string `json:"url,omitempty"`
+	// Whether the issue was closed when last synced, so the side that changed can be told apart
+	Closed       bool       `json:"closed"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ExternalComment records a public comment mirrored to or from an external issue,
+// so it is only copied once
+type ExternalComment struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	ExternalIssueID uint      `json:"external_issue_id" gorm:"not null;index"`
+	CommentID       uint      `json:"comment_id" gorm:"not null;index"`
+	ExternalID      string    `json:"external_id" gorm:"not null"`
+	CreatedAt       time.Time `json:"created_at"`
+}
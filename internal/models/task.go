@@ -2,6 +2,7 @@ package models
 
 import (
 	"gorm.io/gorm"
+	"strconv"
 	"time"
 )
 
@@ -10,8 +11,10 @@ type TaskStatus string
 const (
 	TaskStatusOpen       TaskStatus = "open"
 	TaskStatusInProgress TaskStatus = "in-progress"
-	TaskStatusResolved   TaskStatus = "resolved"
-	TaskStatusClosed     TaskStatus = "closed"
+	// Resolved, but waiting for a reviewer to approve it; see config.ReviewConfig
+	TaskStatusPendingReview TaskStatus = "pending-review"
+	TaskStatusResolved      TaskStatus = "resolved"
+	TaskStatusClosed        TaskStatus = "closed"
 )
 
 type TaskPriority string
@@ -22,6 +25,43 @@ const (
 	TaskPriorityHigh   TaskPriority = "high"
 )
 
+// TaskSize is an effort estimate for teams that size work in points rather
+// than hours: a t-shirt size or a number of story points such as "5"
+type TaskSize string
+
+const (
+	TaskSizeXS TaskSize = "XS"
+	TaskSizeS  TaskSize = "S"
+	TaskSizeM  TaskSize = "M"
+	TaskSizeL  TaskSize = "L"
+	TaskSizeXL TaskSize = "XL"
+)
+
+// TaskSizes are the t-shirt sizes, smallest first
+var TaskSizes = []TaskSize{TaskSizeXS, TaskSizeS, TaskSizeM, TaskSizeL, TaskSizeXL}
+
+// taskSizePoints are the story points each t-shirt size counts as
+var taskSizePoints = map[TaskSize]int{
+	TaskSizeXS: 1,
+	TaskSizeS:  2,
+	TaskSizeM:  3,
+	TaskSizeL:  5,
+	TaskSizeXL: 8,
+}
+
+// Points returns the story points of the size: the number itself, or what a
+// t-shirt size counts as. Unsized and unknown sizes are 0.
+func (s TaskSize) Points() int {
+	if points, ok := taskSizePoints[s]; ok {
+		return points
+	}
+	points, err := strconv.Atoi(string(s))
+	if err != nil || points < 0 {
+		return 0
+	}
+	return points
+}
+
 type Task struct {
 	ID             uint             `json:"id" gorm:"primaryKey"`
 	Name           string           `json:"name" gorm:"not null"`
@@ -29,8 +69,14 @@ type Task struct {
 	Status         TaskStatus       `json:"status" gorm:"default:open"`
 	Priority       TaskPriority     `json:"priority,omitempty"`
 	Tags           []string         `json:"tags,omitempty" gorm:"serializer:json"`
+	MilestoneID    *uint            `json:"milestone_id,omitempty" gorm:"index"`
+	ProjectID      *uint            `json:"project_id,omitempty" gorm:"index"` // nil only until the task is saved into the default project
+	Assignee       string           `json:"assignee,omitempty" gorm:"index"` // Username of the user working the task
+	Context        string           `json:"context,omitempty" gorm:"index"`  // Where the work can be done, e.g. "@home"; see services.NormalizeContext
+	Size           TaskSize         `json:"size,omitempty"`                  // Effort in t-shirt sizes or story points
 	Subtasks       []Subtask        `json:"subtasks,omitempty" gorm:"foreignKey:TaskID"`
 	EmailMessageID string           `json:"email_message_id,omitempty"`
+	InboundKey     string           `json:"inbound_key,omitempty" gorm:"index"` // channel and alert key of the webhook that opened it
 	TimeEntries    []TimeEntry      `json:"time_entries,omitempty" gorm:"foreignKey:TaskID"`
 	Comments       []Comment        `json:"comments,omitempty" gorm:"foreignKey:TaskID"`
 	Subscribers    []TaskSubscriber `json:"subscribers,omitempty" gorm:"foreignKey:TaskID"`
@@ -39,51 +85,256 @@ type Task struct {
 	UpdatedAt      time.Time        `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt   `json:"deleted_at,omitempty" gorm:"index"`
 	ResolvedAt     *time.Time       `json:"resolved_at,omitempty"`
+
+	// When the task entered its current status; nil for tasks predating status history
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+
+	// When automation last raised the task's priority, see AutomationConfig.EscalateDays
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
+
+	// Day the task should be done by, at midnight; nil for no deadline
+	DueDate *time.Time `json:"due_date,omitempty" gorm:"index"`
+
+	// Stable code of the task's /s/{code} short link, assigned the first time it is needed
+	ShortCode string `json:"short_code,omitempty" gorm:"index"`
+
+	// Username recorded in the status history when an update changes the status
+	ChangedBy string `json:"-" gorm:"-"`
+
+	// Time rollups computed from subtasks and time entries when the task is loaded
+	EstimateMinutes int `json:"estimate_minutes" gorm:"-"`
+	LoggedMinutes   int `json:"logged_minutes" gorm:"-"`
+
+	// Whole days spent in the current status, computed when the task is loaded
+	StatusAgeDays int `json:"status_age_days" gorm:"-"`
+
+	// Whether the due date has passed while the task is still open, computed when the task is loaded
+	Overdue bool `json:"overdue,omitempty" gorm:"-"`
+
+	// Tasks mentioned as #ID in this task's description or notes, and tasks
+	// mentioning this one; filled in by TaskService.GetTask
+	References   []TaskLink `json:"references,omitempty" gorm:"-"`
+	ReferencedBy []TaskLink `json:"referenced_by,omitempty" gorm:"-"`
+
+	// Issues mirroring this task in external trackers; filled in by TaskService.GetTask
+	ExternalIssues []ExternalIssue `json:"external_issues,omitempty" gorm:"-"`
+
+	// Tasks this one blocks and tasks blocking it, see TaskDependency; filled
+	// in by TaskService.GetTask
+	Blocks    []TaskLink `json:"blocks,omitempty" gorm:"-"`
+	BlockedBy []TaskLink `json:"blocked_by,omitempty" gorm:"-"`
+}
+
+// TaskReference records that a task's description or one of its notes mentions another task as #ID
+type TaskReference struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	SourceTaskID uint      `json:"source_task_id" gorm:"not null;index"`
+	TargetTaskID uint      `json:"target_task_id" gorm:"not null;index"`
+	CommentID    *uint     `json:"comment_id,omitempty" gorm:"index"` // nil when mentioned in the description
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TaskLink is a lightweight view of a referenced or referencing task
+type TaskLink struct {
+	ID     uint       `json:"id"`
+	Name   string     `json:"name"`
+	Status TaskStatus `json:"status"`
+}
+
+// TaskDependency records that a task blocks another: the blocked task cannot
+// be resolved while the blocking task is still open
+type TaskDependency struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	BlockingTaskID uint      `json:"blocking_task_id" gorm:"not null;uniqueIndex:idx_task_dependency"`
+	BlockedTaskID  uint      `json:"blocked_task_id" gorm:"not null;uniqueIndex:idx_task_dependency;index"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TaskStatusChange records a task moving from one status to another
+type TaskStatusChange struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	TaskID     uint       `json:"task_id" gorm:"not null;index"`
+	FromStatus TaskStatus `json:"from_status"`
+	ToStatus   TaskStatus `json:"to_status" gorm:"not null"`
+	ChangedAt  time.Time  `json:"changed_at" gorm:"not null"`
+	ChangedBy  string     `json:"changed_by,omitempty" gorm:"index"` // Username, empty for automation and email
+}
+
+// Review decisions, see TaskReview
+const (
+	ReviewApproved = "approved"
+	ReviewReopened = "reopened"
+)
+
+// TaskReview records a reviewer approving a task that was pending review, or
+// reopening it with a comment
+type TaskReview struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	TaskID     uint      `json:"task_id" gorm:"not null;index"`
+	Reviewer   string    `json:"reviewer" gorm:"not null"`
+	Decision   string    `json:"decision" gorm:"not null"` // ReviewApproved or ReviewReopened
+	Comment    string    `json:"comment,omitempty"`
+	ReviewedAt time.Time `json:"reviewed_at" gorm:"not null;index"`
+}
+
+// TaskAssignment records a task being assigned by an auto-assignment policy
+type TaskAssignment struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	TaskID     uint      `json:"task_id" gorm:"not null;index"`
+	Assignee   string    `json:"assignee" gorm:"not null"`
+	Tag        string    `json:"tag" gorm:"not null;index"` // Tag whose policy made the assignment
+	Policy     string    `json:"policy" gorm:"not null"`
+	AssignedAt time.Time `json:"assigned_at" gorm:"not null;index"`
 }
 
 type Subtask struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	TaskID    uint      `json:"task_id" gorm:"not null"`
-	Name      string    `json:"name" gorm:"not null"`
-	Completed bool      `json:"completed" gorm:"default:false"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	TaskID          uint      `json:"task_id" gorm:"not null"`
+	Name            string    `json:"name" gorm:"not null"`
+	Completed       bool      `json:"completed" gorm:"default:false"`
+	EstimateMinutes int       `json:"estimate_minutes" gorm:"default:0"`
+	LoggedMinutes   int       `json:"logged_minutes" gorm:"-"` // computed from time entries
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 type TimeEntry struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
 	TaskID      uint      `json:"task_id" gorm:"not null"`
+	SubtaskID   *uint     `json:"subtask_id,omitempty" gorm:"index"`
 	Description string    `json:"description,omitempty"`
-	Duration    int       `json:"duration" gorm:"not null"` // minutes
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Duration    int       `json:"duration" gorm:"not null"`          // minutes
+	CreatedBy   string    `json:"created_by,omitempty" gorm:"index"` // Username of who logged it
+	// Whether the time is billed to a client; nil when creating an entry takes
+	// the default from the task's tags
+	Billable  *bool     `json:"billable" gorm:"not null;default:true;index"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsBillable reports whether the time is billed to a client
+func (e *TimeEntry) IsBillable() bool {
+	return e.Billable == nil || *e.Billable
+}
+
+// AfterFind rolls subtask estimates and logged time up to the task and computes its status age.
+// Rollups are only as complete as the associations that were preloaded.
+func (t *Task) AfterFind(tx *gorm.DB) error {
+	t.ComputeTimeRollups()
+	t.StatusAgeDays = int(time.Since(t.StatusSince()).Hours() / 24)
+	t.Overdue = t.IsOverdue(time.Now())
+	return nil
+}
+
+// IsOverdue reports whether the task is open or in progress after its due day
+// has ended
+func (t *Task) IsOverdue(now time.Time) bool {
+	if t.DueDate == nil || t.Status == TaskStatusResolved || t.Status == TaskStatusClosed {
+		return false
+	}
+	due := t.DueDate.In(now.Location())
+	return !now.Before(time.Date(due.Year(), due.Month(), due.Day()+1, 0, 0, 0, 0, now.Location()))
+}
+
+// StatusSince returns when the task entered its current status. Tasks created before
+// status history was recorded fall back to their resolution or creation time.
+func (t *Task) StatusSince() time.Time {
+	if t.StatusChangedAt != nil {
+		return *t.StatusChangedAt
+	}
+	if t.ResolvedAt != nil && (t.Status == TaskStatusResolved || t.Status == TaskStatusClosed) {
+		return *t.ResolvedAt
+	}
+	return t.CreatedAt
+}
+
+// ComputeTimeRollups recalculates the per-subtask logged time and the task
+// totals from the loaded subtasks and time entries
+func (t *Task) ComputeTimeRollups() {
+	logged := make(map[uint]int)
+	t.LoggedMinutes = 0
+	for _, entry := range t.TimeEntries {
+		t.LoggedMinutes += entry.Duration
+		if entry.SubtaskID != nil {
+			logged[*entry.SubtaskID] += entry.Duration
+		}
+	}
+
+	t.EstimateMinutes = 0
+	for i := range t.Subtasks {
+		t.Subtasks[i].LoggedMinutes = logged[t.Subtasks[i].ID]
+		t.EstimateMinutes += t.Subtasks[i].EstimateMinutes
+	}
 }
 
 type Comment struct {
-	ID          uint         `json:"id" gorm:"primaryKey"`
-	TaskID      uint         `json:"task_id" gorm:"not null"`
-	Content     string       `json:"content" gorm:"type:text;not null"`
-	IsPrivate   bool         `json:"is_private" gorm:"default:false"`
-	FromEmail   string       `json:"from_email,omitempty"`
-	Attachments []Attachment `json:"attachments,omitempty" gorm:"foreignKey:CommentID"`
-	CreatedAt   time.Time    `json:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at"`
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	TaskID    uint   `json:"task_id" gorm:"not null"`
+	Content   string `json:"content" gorm:"type:text;not null"`
+	IsPrivate bool   `json:"is_private" gorm:"default:false"`
+	FromEmail string `json:"from_email,omitempty"`
+	// Message-ID of the email this comment was sent as, so replies thread back to the task
+	EmailMessageID string       `json:"email_message_id,omitempty" gorm:"index"`
+	CreatedBy      string       `json:"created_by,omitempty" gorm:"index"` // Username of who wrote it, empty for email and automation
+	Attachments    []Attachment `json:"attachments,omitempty" gorm:"foreignKey:CommentID"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+
+	// Token matching delivery reports, read receipts and the tracking pixel
+	// to the email this comment was sent as, see EmailReceipt
+	TrackingToken string `json:"-" gorm:"index"`
+	// Furthest the email got: EmailReceiptSeen, EmailReceiptDelivered or
+	// EmailReceiptFailed; filled in by TaskService.GetTask
+	Receipt string `json:"receipt,omitempty" gorm:"-"`
 }
 
+// EmailDirection tells whether an email was received or sent by JATS
+type EmailDirection string
+
+const (
+	EmailDirectionInbound  EmailDirection = "inbound"
+	EmailDirectionOutbound EmailDirection = "outbound"
+)
+
 type EmailMessage struct {
-	ID          uint       `json:"id" gorm:"primaryKey"`
-	MessageID   string     `json:"message_id" gorm:"uniqueIndex;not null"`
-	TaskID      *uint      `json:"task_id,omitempty"`
-	Subject     string     `json:"subject" gorm:"not null"`
-	From        string     `json:"from" gorm:"not null"`
-	To          []string   `json:"to,omitempty" gorm:"serializer:json"`
-	CC          []string   `json:"cc,omitempty" gorm:"serializer:json"`
-	Body        string     `json:"body" gorm:"type:text"`
-	Processed   bool       `json:"processed" gorm:"default:false"`
-	ProcessedAt *time.Time `json:"processed_at,omitempty"`
-	ReceivedAt  time.Time  `json:"received_at" gorm:"not null"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	MessageID   string         `json:"message_id" gorm:"uniqueIndex;not null"`
+	TaskID      *uint          `json:"task_id,omitempty" gorm:"index"`
+	Direction   EmailDirection `json:"direction" gorm:"default:inbound"`
+	Subject     string         `json:"subject" gorm:"not null"`
+	From        string         `json:"from" gorm:"not null"`
+	To          []string       `json:"to,omitempty" gorm:"serializer:json"`
+	CC          []string       `json:"cc,omitempty" gorm:"serializer:json"`
+	Body        string         `json:"body" gorm:"type:text"`
+	Processed   bool           `json:"processed" gorm:"default:false"`
+	ProcessedAt *time.Time     `json:"processed_at,omitempty"`
+	ReceivedAt  time.Time      `json:"received_at" gorm:"not null"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// Email receipt events, see EmailReceipt
+const (
+	EmailReceiptDelivered = "delivered"
+	EmailReceiptFailed    = "failed"
+	EmailReceiptSeen      = "seen"
+)
+
+// Where an email receipt came from
+const (
+	EmailReceiptSourceDSN   = "dsn"   // delivery status notification from a mail server
+	EmailReceiptSourceMDN   = "mdn"   // read receipt sent by the recipient's mail client
+	EmailReceiptSourcePixel = "pixel" // tracking image loaded when the email was opened
+)
+
+// EmailReceipt records the delivery or opening of a public comment sent by email
+type EmailReceipt struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	CommentID  uint      `json:"comment_id" gorm:"not null;index"`
+	Event      string    `json:"event" gorm:"not null"`
+	Source     string    `json:"source" gorm:"not null"`
+	Recipient  string    `json:"recipient,omitempty"` // empty when the source does not say, as for the pixel
+	OccurredAt time.Time `json:"occurred_at" gorm:"not null"`
 }
 
 type TaskSubscriber struct {
@@ -101,6 +352,7 @@ type Attachment struct {
 	FileName     string    `json:"filename" gorm:"not null"`
 	OriginalName string    `json:"original_name" gorm:"not null"`
 	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"` // Bytes; 0 for attachments stored before sizes were recorded
 	FilePath     string    `json:"file_path" gorm:"not null"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
@@ -111,6 +363,50 @@ type SavedQuery struct {
 	Name         string   `json:"name" gorm:"not null"`
 	IncludedTags []string `json:"included_tags,omitempty" gorm:"serializer:json"`
 	ExcludedTags []string `json:"excluded_tags,omitempty" gorm:"serializer:json"`
+	// Boolean tag expression matches must also satisfy, e.g.
+	// (client1 OR client2) AND NOT internal; see package tagexpr
+	Expression   string   `json:"expression,omitempty"`
+	ProjectID    *uint    `json:"project_id,omitempty" gorm:"index"` // only matches tasks of this project; nil for every project
+	FeedToken    string   `json:"feed_token,omitempty" gorm:"uniqueIndex"` // set by the server, never by clients
+	Position     int      `json:"position" gorm:"not null;default:0"` // sidebar order, lowest first
+	OpenCount    *int     `json:"open_count,omitempty" gorm:"-"`      // open and in-progress matches, when requested
+	// Of those, the ones in progress, and the minutes logged on matches since
+	// Monday, when requested with OpenCount
+	InProgressCount *int  `json:"in_progress_count,omitempty" gorm:"-"`
+	WeekMinutes     *int  `json:"week_minutes,omitempty" gorm:"-"`
+	Pinned       bool     `json:"pinned,omitempty" gorm:"-"`          // pinned by the current user
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
+
+// SavedQuerySchedule emails the tasks matching a saved query on a cron schedule
+type SavedQuerySchedule struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	SavedQueryID uint       `json:"saved_query_id" gorm:"not null;uniqueIndex"`
+	Cron         string     `json:"cron" gorm:"not null"` // five-field cron expression, server time
+	Recipients   []string   `json:"recipients" gorm:"serializer:json"`
+	Subject      string     `json:"subject,omitempty"` // defaults to the saved query name
+	Enabled      bool       `json:"enabled"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt    *time.Time `json:"next_run_at,omitempty" gorm:"index"`
+	LastError    string     `json:"last_error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// SavedQueryWebhook posts every task matching a saved query to an external
+// system on a cron schedule, for example to feed a BI pipeline
+type SavedQueryWebhook struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	SavedQueryID uint       `json:"saved_query_id" gorm:"not null;index"`
+	URL          string     `json:"url" gorm:"not null"`
+	Cron         string     `json:"cron" gorm:"not null"` // five-field cron expression, server time
+	// Signs payloads with HMAC-SHA256 in the X-JATS-Signature header when set
+	Secret       string     `json:"secret,omitempty"`
+	Enabled      bool       `json:"enabled"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt    *time.Time `json:"next_run_at,omitempty" gorm:"index"`
+	LastError    string     `json:"last_error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
@@ -128,6 +128,39 @@ func TestTimeEntryCreation(t *testing.T) {
 	}
 }
 
+func TestTaskComputeTimeRollups(t *testing.T) {
+	subtaskID := uint(10)
+	task := Task{
+		Subtasks: []Subtask{
+			{ID: 10, Name: "Design", EstimateMinutes: 60},
+			{ID: 11, Name: "Build", EstimateMinutes: 120},
+		},
+		TimeEntries: []TimeEntry{
+			{Duration: 45, SubtaskID: &subtaskID},
+			{Duration: 30, SubtaskID: &subtaskID},
+			{Duration: 15},
+		},
+	}
+
+	task.ComputeTimeRollups()
+
+	if task.EstimateMinutes != 180 {
+		t.Errorf("Expected estimate 180, got %d", task.EstimateMinutes)
+	}
+
+	if task.LoggedMinutes != 90 {
+		t.Errorf("Expected logged 90, got %d", task.LoggedMinutes)
+	}
+
+	if task.Subtasks[0].LoggedMinutes != 75 {
+		t.Errorf("Expected subtask logged 75, got %d", task.Subtasks[0].LoggedMinutes)
+	}
+
+	if task.Subtasks[1].LoggedMinutes != 0 {
+		t.Errorf("Expected subtask logged 0, got %d", task.Subtasks[1].LoggedMinutes)
+	}
+}
+
 func TestCommentCreation(t *testing.T) {
 	now := time.Now()
 	comment := Comment{
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Tenant is a client served by a multi-tenant jatsd. Each tenant has its own
+// database, so its users, tasks, tags and saved queries are invisible to the
+// others. Tenants are recorded in the main instance's database.
+type Tenant struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex;not null"` // Subdomain or path segment, e.g. "acme"
+	Name      string    `json:"name" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// handlesRoute finds the routes a handler's doc comment says it handles, as in
+// "GetTask handles GET /api/v1/tasks/{id}"
+var handlesRoute = regexp.MustCompile(`\b(GET|POST|PUT|PATCH|DELETE) (/api/v1[^\s,;]*)`)
+
+// ParseHandlerDocs reads the doc comments of the functions in the Go package in
+// dir, keyed by the routes they name
+func ParseHandlerDocs(dir string) (map[string]HandlerDoc, error) {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]HandlerDoc)
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Doc == nil {
+					continue
+				}
+				text := strings.Join(strings.Fields(fn.Doc.Text()), " ")
+				for _, match := range handlesRoute.FindAllStringSubmatch(text, -1) {
+					path, _, _ := strings.Cut(match[2], "?")
+					path = strings.TrimRight(path, ".:)")
+					docs[Key(match[1], path)] = HandlerDoc{Handler: fn.Name.Name, Doc: text}
+				}
+			}
+		}
+	}
+	return docs, nil
+}
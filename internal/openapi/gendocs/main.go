@@ -0,0 +1,53 @@
+// Command gendocs writes the handler doc comments of an API package to a Go
+// file, for the OpenAPI spec built at runtime. Run it with go generate in
+// internal/api after changing a handler's doc comment.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/soarinferret/jats/internal/openapi"
+)
+
+func main() {
+	output := flag.String("o", "openapi_docs.go", "file to write")
+	pkg := flag.String("package", "api", "package of the written file")
+	variable := flag.String("var", "HandlerDocs", "name of the variable holding the docs")
+	flag.Parse()
+
+	docs, err := openapi.ParseHandlerDocs(".")
+	if err != nil {
+		log.Fatalf("gendocs: %v", err)
+	}
+
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gendocs from the handler doc comments; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", *pkg)
+	fmt.Fprintf(&b, "import \"github.com/soarinferret/jats/internal/openapi\"\n\n")
+	fmt.Fprintf(&b, "// %s documents the API operations, keyed by openapi.Key\n", *variable)
+	fmt.Fprintf(&b, "var %s = map[string]openapi.HandlerDoc{\n", *variable)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\t%q: {Handler: %q, Doc: %q},\n", key, docs[key].Handler, docs[key].Doc)
+	}
+	fmt.Fprintf(&b, "}\n")
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("gendocs: %v", err)
+	}
+	if err := os.WriteFile(*output, source, 0o644); err != nil {
+		log.Fatalf("gendocs: %v", err)
+	}
+}
@@ -0,0 +1,371 @@
+// Package openapi builds the OpenAPI 3.0 description of the JSON API from the
+// registered routes and the doc comments of their handlers, so the spec stays
+// in step with the code.
+package openapi
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Version is the OpenAPI version the documents are written in
+const Version = "3.0.3"
+
+// Document is an OpenAPI document, limited to what the API needs
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Tags       []Tag                 `json:"tags,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem holds the operations on a path, keyed by lower-case method
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+	// An empty list marks an operation that needs no authentication
+	Security *[]map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref                  string            `json:"$ref,omitempty"`
+	Type                 string            `json:"type,omitempty"`
+	Format               string            `json:"format,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	Properties           map[string]Schema `json:"properties,omitempty"`
+	Required             []string          `json:"required,omitempty"`
+	AdditionalProperties *bool             `json:"additionalProperties,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]Schema         `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// Route is a registered API route, with gin style :name and *name parameters
+type Route struct {
+	Method string
+	Path   string
+}
+
+// HandlerDoc is the doc comment of the handler serving an operation
+type HandlerDoc struct {
+	Handler string // function name, such as GetTask
+	Doc     string
+}
+
+// Options describe the API as a whole
+type Options struct {
+	Title       string
+	Description string
+	Version     string
+	// Operations anyone may call, as "METHOD /path" keys like the docs
+	Public []string
+	// Operations that take a multipart/form-data upload instead of JSON
+	Multipart []string
+}
+
+var (
+	pathParam    = regexp.MustCompile(`[:*]([A-Za-z_]+)`)
+	openAPIParam = regexp.MustCompile(`\{[^}]*\}`)
+	bareHandles  = regexp.MustCompile(`^\w+ handles [A-Z]+ \S+$`)
+)
+
+// Key identifies an operation as "METHOD /path", with the path parameters
+// written in OpenAPI style but without names, so handler comments naming a
+// parameter differently from the route still match
+func Key(method, path string) string {
+	path = pathParam.ReplaceAllString(path, "{}")
+	path = openAPIParam.ReplaceAllString(path, "{}")
+	return strings.ToUpper(method) + " " + path
+}
+
+// Build describes the routes, documenting each from its handler's doc comment
+func Build(routes []Route, docs map[string]HandlerDoc, opts Options) *Document {
+	public := make(map[string]bool, len(opts.Public))
+	for _, key := range opts.Public {
+		fields := strings.Fields(key)
+		public[Key(fields[0], fields[1])] = true
+	}
+	multipart := make(map[string]bool, len(opts.Multipart))
+	for _, key := range opts.Multipart {
+		fields := strings.Fields(key)
+		multipart[Key(fields[0], fields[1])] = true
+	}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: opts.Title, Description: opts.Description, Version: opts.Version},
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas:         envelopeSchemas(),
+			SecuritySchemes: securitySchemes(),
+		},
+		Security: []map[string][]string{{"apiKey": {}}, {"bearer": {}}, {"session": {}}},
+	}
+
+	tags := make(map[string]bool)
+	operationIDs := make(map[string]int)
+	for _, route := range routes {
+		key := Key(route.Method, route.Path)
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		handlerDoc := docs[key]
+
+		op := &Operation{
+			Summary:     summaryOf(handlerDoc, route.Method, path),
+			Description: descriptionOf(handlerDoc),
+			Parameters:  pathParameters(route.Path),
+			Responses:   responses(route.Method),
+		}
+
+		op.OperationID = handlerDoc.Handler
+		if op.OperationID == "" {
+			op.OperationID = operationIDOf(route.Method, path)
+		}
+		// Handlers serving several routes get a numbered ID for each after the first
+		if n := operationIDs[op.OperationID]; n > 0 {
+			operationIDs[op.OperationID]++
+			op.OperationID += "_" + strconv.Itoa(n+1)
+		} else {
+			operationIDs[op.OperationID] = 1
+		}
+
+		if tag := tagOf(path); tag != "" {
+			op.Tags = []string{tag}
+			tags[tag] = true
+		}
+		switch route.Method {
+		case "POST", "PUT", "PATCH":
+			op.RequestBody = requestBody(multipart[key])
+		}
+		if public[key] {
+			op.Security = &[]map[string][]string{}
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = op
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	return doc
+}
+
+// WithServer returns a copy of the document served from url, such as the base
+// path the request came in under
+func (d *Document) WithServer(url string) *Document {
+	copied := *d
+	copied.Servers = []Server{{URL: url}}
+	return &copied
+}
+
+// summaryOf turns a handler name such as GetTaskAssets into "Get task assets",
+// falling back to the method and path for undocumented routes
+func summaryOf(doc HandlerDoc, method, path string) string {
+	if doc.Handler == "" {
+		return method + " " + path
+	}
+	var words []string
+	start := 0
+	name := []rune(doc.Handler)
+	for i := 1; i < len(name); i++ {
+		// Split before an upper-case letter that starts a word, keeping
+		// acronyms such as TOTP or API together
+		if unicode.IsUpper(name[i]) && (unicode.IsLower(name[i-1]) || (i+1 < len(name) && unicode.IsLower(name[i+1]))) {
+			words = append(words, string(name[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(name[start:]))
+	for i := 1; i < len(words); i++ {
+		if !isAcronym(words[i]) {
+			words[i] = strings.ToLower(words[i])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func isAcronym(word string) bool {
+	return len(word) > 1 && strings.ToUpper(word) == word
+}
+
+// descriptionOf returns a handler's doc comment, unless all it says is which
+// route the handler serves
+func descriptionOf(doc HandlerDoc) string {
+	if bareHandles.MatchString(doc.Doc) {
+		return ""
+	}
+	return doc.Doc
+}
+
+// operationIDOf names an undocumented operation after its method and path
+func operationIDOf(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '_' || r == '.'
+	}) {
+		if part == "api" || part == "v1" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// tagOf groups operations by the first path segment after /api/v1
+func tagOf(path string) string {
+	parts := strings.Split(strings.TrimPrefix(path, "/api/v1/"), "/")
+	if len(parts) == 0 || strings.HasPrefix(parts[0], "{") {
+		return ""
+	}
+	return strings.TrimSuffix(parts[0], ".json")
+}
+
+func pathParameters(path string) []Parameter {
+	var params []Parameter
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		schema := Schema{Type: "string"}
+		if name := match[1]; name == "id" || strings.HasSuffix(name, "Id") {
+			schema = Schema{Type: "integer", Format: "int64"}
+		}
+		params = append(params, Parameter{Name: match[1], In: "path", Required: true, Schema: schema})
+	}
+	return params
+}
+
+func requestBody(multipart bool) *RequestBody {
+	if multipart {
+		return &RequestBody{Required: true, Content: map[string]MediaType{
+			"multipart/form-data": {Schema: Schema{Type: "object"}},
+		}}
+	}
+	return &RequestBody{Required: false, Content: map[string]MediaType{
+		"application/json": {Schema: Schema{Type: "object"}},
+	}}
+}
+
+func responses(method string) map[string]Response {
+	success := Response{
+		Description: "Success",
+		Content: map[string]MediaType{
+			"application/json": {Schema: Schema{Ref: "#/components/schemas/Response"}},
+		},
+	}
+	failure := Response{
+		Description: "The request was invalid, not allowed or failed",
+		Content: map[string]MediaType{
+			"application/json": {Schema: Schema{Ref: "#/components/schemas/ErrorResponse"}},
+		},
+	}
+	codes := map[string]Response{"2XX": success, "4XX": failure, "5XX": failure}
+	if method == "DELETE" {
+		codes["204"] = Response{Description: "Deleted"}
+	}
+	return codes
+}
+
+// envelopeSchemas describe the envelope every JSON response is wrapped in,
+// see api.APIResponse
+func envelopeSchemas() map[string]Schema {
+	return map[string]Schema{
+		"Response": {
+			Type:     "object",
+			Required: []string{"success", "timestamp"},
+			Properties: map[string]Schema{
+				"success":   {Type: "boolean"},
+				"data":      {Description: "The result, which varies by operation"},
+				"message":   {Type: "string"},
+				"timestamp": {Type: "string", Format: "date-time"},
+			},
+		},
+		"ErrorResponse": {
+			Type:     "object",
+			Required: []string{"success", "error", "timestamp"},
+			Properties: map[string]Schema{
+				"success":   {Type: "boolean"},
+				"error":     {Ref: "#/components/schemas/Error"},
+				"timestamp": {Type: "string", Format: "date-time"},
+			},
+		},
+		"Error": {
+			Type:     "object",
+			Required: []string{"code", "message"},
+			Properties: map[string]Schema{
+				"code":    {Type: "string"},
+				"message": {Type: "string"},
+				"details": {Description: "Further details, such as validation errors"},
+			},
+		},
+	}
+}
+
+// securitySchemes are the ways the API accepts credentials, see
+// middleware.GinAuthMiddleware
+func securitySchemes() map[string]SecurityScheme {
+	return map[string]SecurityScheme{
+		"apiKey":  {Type: "apiKey", In: "header", Name: "X-API-Key"},
+		"bearer":  {Type: "http", Scheme: "bearer"},
+		"session": {Type: "apiKey", In: "cookie", Name: "session_token"},
+	}
+}
@@ -0,0 +1,118 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	for _, path := range []string{"/api/v1/tasks/:id/subtasks/:subtaskId", "/api/v1/tasks/{id}/subtasks/{sid}"} {
+		if got := Key("get", path); got != "GET /api/v1/tasks/{}/subtasks/{}" {
+			t.Errorf("Key(%q) = %q", path, got)
+		}
+	}
+}
+
+func TestParseHandlerDocs(t *testing.T) {
+	dir := t.TempDir()
+	source := `package api
+
+// GetTask handles GET /api/v1/tasks/{id}
+func GetTask() {}
+
+// ParseDate handles GET /api/v1/dates/parse?q=next+friday, turning a
+// natural language date into a timestamp.
+func ParseDate() {}
+
+// helper has no route
+func helper() {}
+`
+	if err := os.WriteFile(filepath.Join(dir, "handlers.go"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := ParseHandlerDocs(dir)
+	if err != nil {
+		t.Fatalf("ParseHandlerDocs failed: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("Expected 2 documented routes, got %+v", docs)
+	}
+	if doc := docs["GET /api/v1/tasks/{}"]; doc.Handler != "GetTask" {
+		t.Errorf("Expected GetTask, got %+v", doc)
+	}
+	doc := docs["GET /api/v1/dates/parse"]
+	if doc.Handler != "ParseDate" || doc.Doc != "ParseDate handles GET /api/v1/dates/parse?q=next+friday, turning a natural language date into a timestamp." {
+		t.Errorf("Expected the query string to be left out of the key and the doc joined into one line, got %+v", doc)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	routes := []Route{
+		{Method: "GET", Path: "/api/v1/tasks/:id"},
+		{Method: "POST", Path: "/api/v1/auth/login"},
+		{Method: "POST", Path: "/api/v1/tasks/:id/attachments"},
+		{Method: "GET", Path: "/api/v1/auth/totp/setup"},
+		{Method: "DELETE", Path: "/api/v1/undocumented/:slug"},
+	}
+	docs := map[string]HandlerDoc{
+		"GET /api/v1/tasks/{}":              {Handler: "GetTask", Doc: "GetTask handles GET /api/v1/tasks/{id}"},
+		"POST /api/v1/auth/login":           {Handler: "Login", Doc: "Login handles POST /api/v1/auth/login, starting a session"},
+		"GET /api/v1/auth/totp/setup":       {Handler: "SetupTOTP", Doc: "SetupTOTP handles GET /api/v1/auth/totp/setup"},
+		"POST /api/v1/tasks/{}/attachments": {Handler: "UploadAttachment"},
+	}
+	doc := Build(routes, docs, Options{
+		Title:     "Test",
+		Version:   "v1",
+		Public:    []string{"POST /api/v1/auth/login"},
+		Multipart: []string{"POST /api/v1/tasks/{id}/attachments"},
+	})
+
+	get := doc.Paths["/api/v1/tasks/{id}"]["get"]
+	if get == nil {
+		t.Fatalf("Expected GET /api/v1/tasks/{id}, got paths %v", doc.Paths)
+	}
+	if get.OperationID != "GetTask" || get.Summary != "Get task" || get.Description != "" {
+		t.Errorf("Expected the operation to be named after its handler without a bare description, got %+v", get)
+	}
+	if len(get.Parameters) != 1 || get.Parameters[0].Name != "id" || get.Parameters[0].Schema.Type != "integer" {
+		t.Errorf("Expected an integer id path parameter, got %+v", get.Parameters)
+	}
+	if get.Security != nil || len(get.Tags) != 1 || get.Tags[0] != "tasks" {
+		t.Errorf("Expected the default security and a tasks tag, got %+v", get)
+	}
+
+	login := doc.Paths["/api/v1/auth/login"]["post"]
+	if login.Security == nil || len(*login.Security) != 0 {
+		t.Errorf("Expected login to need no authentication")
+	}
+	if login.Description != "Login handles POST /api/v1/auth/login, starting a session" {
+		t.Errorf("Expected the doc comment as the description, got %q", login.Description)
+	}
+	if _, ok := login.RequestBody.Content["application/json"]; !ok {
+		t.Errorf("Expected a JSON request body, got %+v", login.RequestBody)
+	}
+	if upload := doc.Paths["/api/v1/tasks/{id}/attachments"]["post"]; upload.RequestBody.Content["multipart/form-data"].Schema.Type != "object" {
+		t.Errorf("Expected a multipart request body, got %+v", upload.RequestBody)
+	}
+
+	if totp := doc.Paths["/api/v1/auth/totp/setup"]["get"]; totp.Summary != "Setup TOTP" {
+		t.Errorf("Expected acronyms to be kept together, got %q", totp.Summary)
+	}
+
+	undocumented := doc.Paths["/api/v1/undocumented/{slug}"]["delete"]
+	if undocumented.Summary != "DELETE /api/v1/undocumented/{slug}" || undocumented.OperationID != "deleteUndocumentedSlug" {
+		t.Errorf("Expected an undocumented route to be named after its path, got %+v", undocumented)
+	}
+	if undocumented.Parameters[0].Schema.Type != "string" {
+		t.Errorf("Expected a string slug parameter, got %+v", undocumented.Parameters)
+	}
+	if _, ok := undocumented.Responses["204"]; !ok {
+		t.Errorf("Expected DELETE to document 204")
+	}
+
+	if served := doc.WithServer("/jats"); served.Servers[0].URL != "/jats" || doc.Servers != nil {
+		t.Errorf("Expected WithServer to set the server on a copy")
+	}
+}
@@ -0,0 +1,74 @@
+package outline
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// markdownHeading matches an ATX heading such as "## Errands", with optional
+// closing hashes
+var markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+
+// ParseMarkdown reads the checkbox items of a Markdown document. A "- [ ]"
+// item is a task, items indented under it are its subtasks, and "[x]" marks
+// an item done. Tasks are under the headings above them. Other list items,
+// text and fenced code blocks are skipped.
+func ParseMarkdown(r io.Reader) ([]*Task, error) {
+	var tasks []*Task
+	var headings []string
+	var levels []int
+	var list checklist
+	fence := ""
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
+
+		if match := markdownHeading.FindStringSubmatch(line); match != nil {
+			level := len(match[1])
+			for len(levels) > 0 && levels[len(levels)-1] >= level {
+				levels, headings = levels[:len(levels)-1], headings[:len(headings)-1]
+			}
+			levels, headings = append(levels, level), append(headings, match[2])
+			list.task = nil
+			continue
+		}
+
+		indent := indentWidth(line)
+		match := listItem.FindStringSubmatch(line)
+		if match == nil || match[2] == "" || match[3] == "" {
+			// Plain items and text end the list unless they are indented
+			// under its task
+			list.end(indent)
+			continue
+		}
+		task := list.item(indent, match[3], strings.EqualFold(match[2], "x"), func() *Task {
+			return &Task{Line: lineNumber, Headings: append([]string(nil), headings...)}
+		})
+		if task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,223 @@
+package outline
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var (
+	// orgHeadline matches a headline, its stars and the rest of the line
+	orgHeadline = regexp.MustCompile(`^(\*+)\s+(.*?)\s*$`)
+	// orgTags matches the tags at the end of a headline, e.g. ":work:urgent:"
+	orgTags = regexp.MustCompile(`(?:^|\s+)(:(?:[\w@#%]+:)+)$`)
+	// orgPriority matches a priority cookie such as [#A]
+	orgPriority = regexp.MustCompile(`^\[#([A-Za-z0-9])\]\s*`)
+	// orgDeadline matches the date of a DEADLINE planning entry
+	orgDeadline = regexp.MustCompile(`DEADLINE:\s*[<\[](\d{4}-\d{2}-\d{2})`)
+	// orgTodoSetting matches the #+TODO line, and its SEQ_TODO and TYP_TODO
+	// variants, that declares the todo keywords of a file
+	orgTodoSetting = regexp.MustCompile(`(?i)^#\+(?:SEQ_|TYP_)?TODO:\s*(.*)$`)
+	// orgFileTags matches the #+FILETAGS line that tags every headline
+	orgFileTags = regexp.MustCompile(`(?i)^#\+FILETAGS:\s*(.*)$`)
+)
+
+// orgPriorities maps the default priority cookies to priorities
+var orgPriorities = map[string]models.TaskPriority{
+	"A": models.TaskPriorityHigh,
+	"B": models.TaskPriorityMedium,
+	"C": models.TaskPriorityLow,
+}
+
+// orgHeading is a headline enclosing the current line
+type orgHeading struct {
+	level int
+	title string
+	tags  []string
+	// task is the task the headline is part of: its own, or the task of a
+	// headline above it. Headlines outside every task have none.
+	task *Task
+	// own is set when the headline is itself a task
+	own bool
+}
+
+// ParseOrg reads the todo items of an org-mode file. A headline with a todo
+// keyword, TODO or DONE unless the file declares others with #+TODO, is a
+// task; todo headlines and checkbox items below it are its subtasks. Outside
+// todo headlines, a checkbox item is a task and the items indented under it
+// are its subtasks. Tasks are under the headlines above them and keep their
+// tags, including inherited ones, their priority cookie and their deadline.
+func ParseOrg(r io.Reader) ([]*Task, error) {
+	todo := map[string]bool{"TODO": true, "NEXT": true, "STARTED": true, "WAITING": true}
+	done := map[string]bool{"DONE": true, "CANCELLED": true, "CANCELED": true}
+	declared := false
+
+	var tasks []*Task
+	var stack []orgHeading
+	var fileTags []string
+	var list checklist
+	block := false
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		upper := strings.ToUpper(trimmed)
+
+		if block {
+			block = !strings.HasPrefix(upper, "#+END_")
+			continue
+		}
+		if strings.HasPrefix(upper, "#+BEGIN_") {
+			block = true
+			continue
+		}
+		if match := orgTodoSetting.FindStringSubmatch(trimmed); match != nil {
+			if !declared {
+				todo, done, declared = map[string]bool{}, map[string]bool{}, true
+			}
+			declareOrgKeywords(match[1], todo, done)
+			continue
+		}
+		if match := orgFileTags.FindStringSubmatch(trimmed); match != nil {
+			fileTags = append(fileTags, splitOrgTags(match[1])...)
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if match := orgHeadline.FindStringSubmatch(line); match != nil {
+			level := len(match[1])
+			for len(stack) > 0 && stack[len(stack)-1].level >= level {
+				stack = stack[:len(stack)-1]
+			}
+			list.task = nil
+
+			heading := orgHeading{level: level, title: match[2]}
+			if len(stack) > 0 {
+				heading.task = stack[len(stack)-1].task
+			}
+			keyword, rest, _ := strings.Cut(heading.title, " ")
+			isTodo, isDone := todo[keyword], done[keyword]
+			if isTodo || isDone {
+				heading.title = strings.TrimSpace(rest)
+			}
+			priority := ""
+			if match := orgPriority.FindStringSubmatch(heading.title); match != nil {
+				priority = strings.ToUpper(match[1])
+				heading.title = heading.title[len(match[0]):]
+			}
+			if match := orgTags.FindStringSubmatch(heading.title); match != nil {
+				heading.tags = splitOrgTags(match[1])
+				heading.title = strings.TrimSpace(heading.title[:len(heading.title)-len(match[0])])
+			}
+
+			switch {
+			case (!isTodo && !isDone) || heading.title == "":
+				// A plain headline groups what is below it
+			case heading.task != nil:
+				heading.task.Subtasks = append(heading.task.Subtasks, Subtask{Name: heading.title, Done: isDone})
+			default:
+				task := newOrgTask(lineNumber, stack, fileTags)
+				task.Name = heading.title
+				task.Done = isDone
+				task.Tags = appendTags(task.Tags, heading.tags)
+				task.Priority = orgPriorities[priority]
+				heading.task, heading.own = task, true
+				tasks = append(tasks, task)
+			}
+			stack = append(stack, heading)
+			continue
+		}
+
+		if match := orgDeadline.FindStringSubmatch(line); match != nil {
+			if len(stack) > 0 && stack[len(stack)-1].own {
+				stack[len(stack)-1].task.DueDate = match[1]
+			}
+			continue
+		}
+
+		indent := indentWidth(line)
+		match := listItem.FindStringSubmatch(line)
+		if match == nil || match[2] == "" || match[3] == "" {
+			list.end(indent)
+			continue
+		}
+		name, checked := match[3], strings.EqualFold(match[2], "x")
+		if len(stack) > 0 && stack[len(stack)-1].task != nil {
+			owner := stack[len(stack)-1].task
+			owner.Subtasks = append(owner.Subtasks, Subtask{Name: name, Done: checked})
+			continue
+		}
+		task := list.item(indent, name, checked, func() *Task {
+			return newOrgTask(lineNumber, stack, fileTags)
+		})
+		if task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	return tasks, nil
+}
+
+// newOrgTask starts a task under the headlines of the stack, inheriting
+// their tags
+func newOrgTask(line int, stack []orgHeading, fileTags []string) *Task {
+	task := &Task{Line: line}
+	task.Tags = appendTags(task.Tags, fileTags)
+	for _, heading := range stack {
+		task.Headings = append(task.Headings, heading.title)
+		task.Tags = appendTags(task.Tags, heading.tags)
+	}
+	return task
+}
+
+// declareOrgKeywords adds the keywords of a #+TODO line, e.g.
+// "TODO(t) WAIT(w@) | DONE(d) CANCELED(c)", where those after the bar, or the
+// last one without a bar, are done states
+func declareOrgKeywords(setting string, todo, done map[string]bool) {
+	open, closed, found := strings.Cut(setting, "|")
+	keywords := func(s string) []string {
+		var words []string
+		for _, word := range strings.Fields(s) {
+			if i := strings.Index(word, "("); i > 0 {
+				word = word[:i]
+			}
+			words = append(words, word)
+		}
+		return words
+	}
+	openWords, closedWords := keywords(open), keywords(closed)
+	if !found && len(openWords) > 0 {
+		openWords, closedWords = openWords[:len(openWords)-1], openWords[len(openWords)-1:]
+	}
+	for _, word := range openWords {
+		todo[word] = true
+	}
+	for _, word := range closedWords {
+		done[word] = true
+	}
+}
+
+// splitOrgTags splits tags written as ":a:b:" or "a b"
+func splitOrgTags(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ':' || r == ' ' || r == '\t' })
+}
+
+// appendTags appends the tags not already in the list
+func appendTags(tags []string, more []string) []string {
+	for _, tag := range more {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,124 @@
+// Package outline reads plain-text todo files, org-mode files and Markdown
+// checklists, into tasks with subtasks, so they can be imported without
+// typing them in again.
+package outline
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// Format is the syntax of a todo file
+type Format string
+
+const (
+	FormatOrg      Format = "org"
+	FormatMarkdown Format = "markdown"
+)
+
+// Task is a todo item of a file, with the items nested under it as subtasks
+type Task struct {
+	Name     string
+	Done     bool
+	Line     int      // 1-based line of the item in the file
+	Headings []string // headings the item is under, outermost first
+	Tags     []string // org-mode tags, including those inherited from headings
+	Priority models.TaskPriority
+	DueDate  string // YYYY-MM-DD from an org-mode deadline, empty for none
+	Subtasks []Subtask
+}
+
+// Subtask is an item nested under a task. Deeper nesting is flattened, as
+// subtasks do not have subtasks of their own.
+type Subtask struct {
+	Name string
+	Done bool
+}
+
+// Parse reads the tasks of a todo file in the given format
+func Parse(format Format, r io.Reader) ([]*Task, error) {
+	switch format {
+	case FormatOrg:
+		return ParseOrg(r)
+	case FormatMarkdown:
+		return ParseMarkdown(r)
+	}
+	return nil, fmt.Errorf("unknown format %q, expected org or markdown", format)
+}
+
+// ParseFormat returns the format named by a --format flag or API field,
+// accepting md as well as markdown
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "org":
+		return FormatOrg, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown format %q, expected org or markdown", name)
+}
+
+// FormatOf guesses the format of a file from its extension
+func FormatOf(filename string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".org":
+		return FormatOrg, true
+	case ".md", ".markdown":
+		return FormatMarkdown, true
+	}
+	return "", false
+}
+
+// listItem matches a list item, with its indent, checkbox, if any, and text,
+// e.g. "  - [x] Buy milk" or "1. Call Bob"
+var listItem = regexp.MustCompile(`^(\s*)(?:[-+*]|\d+[.)])\s+(?:\[([ xX-])\](?:\s+|$))?(.*?)\s*$`)
+
+// indentWidth returns the width of a line's leading whitespace, counting a
+// tab as four columns
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// checklist tracks the list a task made of a top-level checkbox item is in,
+// so items indented under it become its subtasks
+type checklist struct {
+	task   *Task
+	indent int
+}
+
+// item adds a checkbox item, as a subtask of the item it is indented under
+// or as a new task. It returns the new task, if any.
+func (l *checklist) item(indent int, name string, done bool, newTask func() *Task) *Task {
+	if l.task != nil && indent > l.indent {
+		l.task.Subtasks = append(l.task.Subtasks, Subtask{Name: name, Done: done})
+		return nil
+	}
+	task := newTask()
+	task.Name = name
+	task.Done = done
+	l.task, l.indent = task, indent
+	return task
+}
+
+// end ends the list when a line is not indented under its current task
+func (l *checklist) end(indent int) {
+	if l.task != nil && indent <= l.indent {
+		l.task = nil
+	}
+}
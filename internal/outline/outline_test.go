@@ -0,0 +1,136 @@
+package outline
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	input := `# Home
+
+Some notes about the house.
+
+- [ ] Fix the fence
+  - [x] Buy posts
+  - [ ] Dig holes
+    - [ ] Rent an auger
+- [X] Paint the shed
+- Groceries
+  - [ ] Milk
+
+## Garden ##
+
+* [ ] Plant tomatoes
+
+` + "```" + `
+- [ ] Not a task
+` + "```" + `
+
+# Work
+1. [ ] Send invoices
+`
+	tasks, err := ParseMarkdown(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseMarkdown failed: %v", err)
+	}
+
+	want := []*Task{
+		{Name: "Fix the fence", Line: 5, Headings: []string{"Home"}, Subtasks: []Subtask{
+			{Name: "Buy posts", Done: true}, {Name: "Dig holes"}, {Name: "Rent an auger"},
+		}},
+		{Name: "Paint the shed", Done: true, Line: 9, Headings: []string{"Home"}},
+		{Name: "Milk", Line: 11, Headings: []string{"Home"}},
+		{Name: "Plant tomatoes", Line: 15, Headings: []string{"Home", "Garden"}},
+		{Name: "Send invoices", Line: 22, Headings: []string{"Work"}},
+	}
+	if !reflect.DeepEqual(tasks, want) {
+		t.Errorf("Unexpected tasks:\n%s\nwant:\n%s", dump(tasks), dump(want))
+	}
+}
+
+func TestParseOrg(t *testing.T) {
+	input := `#+TITLE: Chores
+#+FILETAGS: :home:
+
+* Errands                                                         :out:
+** TODO [#A] Renew passport                                      :admin:
+   DEADLINE: <2025-03-04 Tue>
+*** DONE Book a photo
+*** TODO Fill in the form
+    - [ ] Find the old passport
+    - [X] Get a pen
+** DONE Return library books
+* Projects
+** Shed
+   - [ ] Paint the shed
+     - [-] Buy paint
+   - [x] Oil the hinges
+#+BEGIN_SRC sh
+- [ ] not a task
+#+END_SRC
+* TODO Call Bob
+`
+	tasks, err := ParseOrg(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseOrg failed: %v", err)
+	}
+
+	want := []*Task{
+		{Name: "Renew passport", Line: 5, Headings: []string{"Errands"}, Tags: []string{"home", "out", "admin"},
+			Priority: models.TaskPriorityHigh, DueDate: "2025-03-04", Subtasks: []Subtask{
+				{Name: "Book a photo", Done: true}, {Name: "Fill in the form"}, {Name: "Find the old passport"}, {Name: "Get a pen", Done: true},
+			}},
+		{Name: "Return library books", Done: true, Line: 11, Headings: []string{"Errands"}, Tags: []string{"home", "out"}},
+		{Name: "Paint the shed", Line: 14, Headings: []string{"Projects", "Shed"}, Tags: []string{"home"}, Subtasks: []Subtask{{Name: "Buy paint"}}},
+		{Name: "Oil the hinges", Done: true, Line: 16, Headings: []string{"Projects", "Shed"}, Tags: []string{"home"}},
+		{Name: "Call Bob", Line: 20, Tags: []string{"home"}},
+	}
+	if !reflect.DeepEqual(tasks, want) {
+		t.Errorf("Unexpected tasks:\n%s\nwant:\n%s", dump(tasks), dump(want))
+	}
+}
+
+func TestParseOrg_DeclaredKeywords(t *testing.T) {
+	input := `#+TODO: OPEN(o) WAIT | CLOSED(c)
+* OPEN First
+* TODO Not a keyword here
+* CLOSED Second
+`
+	tasks, err := ParseOrg(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseOrg failed: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Name != "First" || tasks[0].Done || tasks[1].Name != "Second" || !tasks[1].Done {
+		t.Errorf("Expected the declared keywords to replace the defaults, got:\n%s", dump(tasks))
+	}
+}
+
+func TestFormats(t *testing.T) {
+	if format, ok := FormatOf("notes/TODO.org"); !ok || format != FormatOrg {
+		t.Errorf("Expected .org to be org, got %q", format)
+	}
+	if format, ok := FormatOf("README.md"); !ok || format != FormatMarkdown {
+		t.Errorf("Expected .md to be markdown, got %q", format)
+	}
+	if _, ok := FormatOf("tasks.txt"); ok {
+		t.Error("Expected .txt to have no format")
+	}
+	if format, err := ParseFormat("MD"); err != nil || format != FormatMarkdown {
+		t.Errorf("Expected md to be markdown, got %q, %v", format, err)
+	}
+	if _, err := Parse("txt", strings.NewReader("")); err == nil {
+		t.Error("Expected an unknown format to fail")
+	}
+}
+
+func dump(tasks []*Task) string {
+	var b strings.Builder
+	for _, task := range tasks {
+		fmt.Fprintf(&b, "%+v\n", *task)
+	}
+	return b.String()
+}
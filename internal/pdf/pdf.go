@@ -0,0 +1,178 @@
+// Package pdf writes simple A4 documents: text in the standard Helvetica
+// fonts, rectangles and rules. It is just enough for printable job sheets and
+// needs no fonts or images embedded.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page size and margin, in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+	Margin     = 50.0
+)
+
+// Document is a PDF being built page by page. Coordinates are in points from
+// the top left corner of the page.
+type Document struct {
+	pages []*bytes.Buffer
+}
+
+// New creates an empty document; call AddPage before drawing
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage starts a new page that later drawing goes to
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text draws one line of text with its baseline at y
+func (d *Document) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page(), "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, PageHeight-y, escape(text))
+}
+
+// Rect fills a black rectangle whose top left corner is at x, y
+func (d *Document) Rect(x, y, width, height float64) {
+	fmt.Fprintf(d.page(), "%.2f %.2f %.2f %.2f re f\n", x, PageHeight-y-height, width, height)
+}
+
+// Box outlines a rectangle whose top left corner is at x, y
+func (d *Document) Box(x, y, width, height float64) {
+	fmt.Fprintf(d.page(), "0.8 w %.2f %.2f %.2f %.2f re S\n", x, PageHeight-y-height, width, height)
+}
+
+// Line draws a thin grey horizontal rule at y
+func (d *Document) Line(x1, x2, y float64) {
+	fmt.Fprintf(d.page(), "q 0.7 G 0.5 w %.2f %.2f m %.2f %.2f l S Q\n", x1, PageHeight-y, x2, PageHeight-y)
+}
+
+// Bytes returns the finished document
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	// Objects: 1 catalog, 2 page tree, 3 and 4 fonts, then a page and its
+	// content stream for every page
+	var objects []string
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, content := range d.pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				PageWidth, PageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
+
+// TextWidth estimates the width of text in Helvetica at size. It uses an
+// average glyph width, which is close enough for wrapping.
+func TextWidth(text string, size float64) float64 {
+	return float64(len([]rune(text))) * size * 0.52
+}
+
+// Wrap splits text into lines no wider than width at size, keeping the
+// paragraph breaks of the original
+func Wrap(text string, size, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			// Break words that do not fit on a line of their own
+			for TextWidth(word, size) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				cut := max(1, int(width/(size*0.52)))
+				runes := []rune(word)
+				lines = append(lines, string(runes[:cut]))
+				word = string(runes[cut:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case TextWidth(line+" "+word, size) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// escape encodes text for a PDF string in WinAnsiEncoding; characters it
+// cannot show are replaced with ?
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\t':
+			b.WriteString("    ")
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			if code, ok := winAnsiExtras[r]; ok {
+				fmt.Fprintf(&b, "\\%03o", code)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
+
+// winAnsiExtras maps the characters WinAnsiEncoding places in 0x80-0x9f
+var winAnsiExtras = map[rune]int{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
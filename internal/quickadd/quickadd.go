@@ -0,0 +1,147 @@
+// Package quickadd parses the one-line task syntax shared by the CLI, TUI, web
+// quick-add box and API, e.g. "Fix login +auth @client1 -p high -t 30m -d yesterday -c".
+package quickadd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+// Task is the result of parsing a quick-add line
+type Task struct {
+	Name     string              `json:"name"`
+	Tags     []string            `json:"tags,omitempty"`
+	Priority models.TaskPriority `json:"priority,omitempty"`
+	Duration int                 `json:"duration,omitempty"` // minutes to log on creation
+	Date     string              `json:"date,omitempty"`     // YYYY-MM-DD, empty for today
+	Complete bool                `json:"complete,omitempty"`
+	DueDate  string              `json:"due_date,omitempty"` // any format accepted by utils.ParseDate, empty for none
+	Context  string              `json:"context,omitempty"`  // e.g. "@home", empty for none
+	Size     models.TaskSize     `json:"size,omitempty"`     // XS-XL or story points, empty for none
+}
+
+// Parse parses a quick-add line. Supported syntax:
+//
+//	+tag     adds "tag" and keeps the word (without +) in the name
+//	@tag     adds "tag" and removes the word from the name
+//	-p high  sets the priority (also -phigh)
+//	-t 30m   logs time on creation (also -t30m)
+//	-d date  sets the creation date; natural-language dates may span words (also -d-1d)
+//	-c       marks the task resolved after creation
+//
+// Other words starting with "-" are ignored.
+func Parse(input string) (*Task, error) {
+	task := &Task{}
+	words := strings.Fields(input)
+	var nameWords []string
+
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case word == "-c":
+			task.Complete = true
+		case word == "-t" && i+1 < len(words):
+			if err := task.setDuration(words[i+1]); err != nil {
+				return nil, err
+			}
+			i++ // Skip next word as it's the time value
+		case word == "-p" && i+1 < len(words):
+			if err := task.setPriority(words[i+1]); err != nil {
+				return nil, err
+			}
+			i++ // Skip next word as it's the priority value
+		case word == "-d" && i+1 < len(words):
+			// Dates may span several words, e.g. "-d next friday"
+			parsed, consumed, err := utils.ParseDatePrefix(words[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			task.Date = parsed.Format("2006-01-02")
+			i += consumed // Skip the words that made up the date
+		case strings.HasPrefix(word, "-t") && len(word) > 2:
+			// Handle -t15m format
+			if err := task.setDuration(word[2:]); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(word, "-p") && len(word) > 2:
+			// Handle -phigh format
+			if err := task.setPriority(word[2:]); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(word, "-d") && len(word) > 2:
+			// Handle -d-1d and -dtomorrow formats
+			parsed, err := utils.ParseDate(word[2:])
+			if err != nil {
+				return nil, err
+			}
+			task.Date = parsed.Format("2006-01-02")
+		case !strings.HasPrefix(word, "-"):
+			nameWords = append(nameWords, word)
+		}
+	}
+
+	task.Name, task.Tags = ParseNameAndTags(strings.Join(nameWords, " "))
+	if task.Name == "" {
+		return nil, fmt.Errorf("task name cannot be empty")
+	}
+
+	return task, nil
+}
+
+// ParseNameAndTags extracts +tag and @tag words from a task name and returns the
+// cleaned name and the tags
+func ParseNameAndTags(input string) (string, []string) {
+	words := strings.Fields(input)
+	var cleanWords []string
+	var tags []string
+
+	for _, word := range words {
+		if strings.HasPrefix(word, "+") {
+			// +tag format: add tag and remove + from name
+			tag := strings.TrimPrefix(word, "+")
+			if tag != "" {
+				tags = append(tags, tag)
+				cleanWords = append(cleanWords, tag) // Add the tag word without + to the name
+			}
+		} else if strings.HasPrefix(word, "@") {
+			// @tag format: add tag but remove entire @tag from name
+			tag := strings.TrimPrefix(word, "@")
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+			// Don't add this word to cleanWords (it gets removed from name)
+		} else {
+			// Regular word, keep in name
+			cleanWords = append(cleanWords, word)
+		}
+	}
+
+	cleanName := strings.Join(cleanWords, " ")
+	return strings.TrimSpace(cleanName), tags
+}
+
+func (t *Task) setDuration(value string) error {
+	minutes, err := utils.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	if minutes <= 0 {
+		return fmt.Errorf("duration must be greater than 0: %s", value)
+	}
+	t.Duration = minutes
+	return nil
+}
+
+func (t *Task) setPriority(value string) error {
+	priority := models.TaskPriority(strings.ToLower(value))
+	switch priority {
+	case models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh:
+		t.Priority = priority
+		return nil
+	default:
+		return fmt.Errorf("invalid priority: %s (expected low, medium, high)", value)
+	}
+}
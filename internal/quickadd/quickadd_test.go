@@ -0,0 +1,71 @@
+package quickadd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	input := "@client1 restart +docker container -t 45m -p high -c"
+
+	task, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q) unexpected error: %v", input, err)
+	}
+
+	if task.Name != "restart docker container" {
+		t.Errorf("Name = %q, want %q", task.Name, "restart docker container")
+	}
+	if !reflect.DeepEqual(task.Tags, []string{"client1", "docker"}) {
+		t.Errorf("Tags = %v, want [client1 docker]", task.Tags)
+	}
+	if task.Duration != 45 {
+		t.Errorf("Duration = %d, want 45", task.Duration)
+	}
+	if task.Priority != "high" {
+		t.Errorf("Priority = %q, want high", task.Priority)
+	}
+	if !task.Complete {
+		t.Error("Complete = false, want true")
+	}
+	if task.Date != "" {
+		t.Errorf("Date = %q, want empty", task.Date)
+	}
+}
+
+func TestParse_CompactFlagsAndDates(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	task, err := Parse("Write report -t1.5h -plow -d-1d")
+	if err != nil {
+		t.Fatalf("Parse unexpected error: %v", err)
+	}
+	if task.Name != "Write report" || task.Duration != 90 || task.Priority != "low" || task.Date != yesterday {
+		t.Errorf("Parse = %+v, want name 'Write report', 90 minutes, low priority, date %s", task, yesterday)
+	}
+
+	task, err = Parse("Deploy -d 1 day ago +release")
+	if err != nil {
+		t.Fatalf("Parse unexpected error: %v", err)
+	}
+	if task.Name != "Deploy release" || task.Date != yesterday {
+		t.Errorf("Parse = %+v, want name 'Deploy release' and date %s", task, yesterday)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	inputs := []string{
+		"",
+		"@only-tags -c",
+		"Fix bug -p critical",
+		"Fix bug -t soon",
+		"Fix bug -d someday",
+	}
+
+	for _, input := range inputs {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected error, got nil", input)
+		}
+	}
+}
@@ -82,6 +82,21 @@ func (r *AuthRepository) UpdateUser(user *models.User) error {
 	return nil
 }
 
+// ReencryptSecrets rewrites every stored TOTP secret, including those of deleted
+// users, with the current encryption key. It returns how many were rewritten.
+func (r *AuthRepository) ReencryptSecrets() (int, error) {
+	var users []models.User
+	if err := r.db.Unscoped().Select("id", "totp_secret").Where("totp_secret <> ''").Find(&users).Error; err != nil {
+		return 0, fmt.Errorf("failed to read TOTP secrets: %w", err)
+	}
+	for i := range users {
+		if err := r.db.Unscoped().Model(&users[i]).Select("TOTPSecret").UpdateColumns(&users[i]).Error; err != nil {
+			return i, fmt.Errorf("failed to re-encrypt TOTP secret of user %d: %w", users[i].ID, err)
+		}
+	}
+	return len(users), nil
+}
+
 // UpdateUserLastLogin updates the user's last login time
 func (r *AuthRepository) UpdateUserLastLogin(userID uint) error {
 	now := time.Now()
@@ -154,12 +169,14 @@ func (r *AuthRepository) DeleteUserSessions(userID uint) error {
 	return nil
 }
 
-// DeleteExpiredSessions deletes all expired sessions
-func (r *AuthRepository) DeleteExpiredSessions() error {
-	if err := r.db.Where("expires_at <= ?", time.Now()).Delete(&models.Session{}).Error; err != nil {
-		return fmt.Errorf("failed to delete expired sessions: %w", err)
+// PurgeSessionsEndedBefore permanently deletes sessions that expired or were revoked
+// before the given time and returns how many were removed
+func (r *AuthRepository) PurgeSessionsEndedBefore(before time.Time) (int64, error) {
+	result := r.db.Unscoped().Where("expires_at <= ? OR deleted_at <= ?", before, before).Delete(&models.Session{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge sessions: %w", result.Error)
 	}
-	return nil
+	return result.RowsAffected, nil
 }
 
 // GetUserSessions retrieves all sessions for a user
@@ -308,10 +325,12 @@ func (r *AuthRepository) GetFailedLoginAttempts(username, ipAddress string, sinc
 	return count, nil
 }
 
-// CleanupOldLoginAttempts removes old login attempt records
-func (r *AuthRepository) CleanupOldLoginAttempts(before time.Time) error {
-	if err := r.db.Where("created_at < ?", before).Delete(&models.LoginAttempt{}).Error; err != nil {
-		return fmt.Errorf("failed to cleanup old login attempts: %w", err)
+// CleanupOldLoginAttempts removes login attempt records created before the given time
+// and returns how many were removed
+func (r *AuthRepository) CleanupOldLoginAttempts(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.LoginAttempt{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to cleanup old login attempts: %w", result.Error)
 	}
-	return nil
+	return result.RowsAffected, nil
 }
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/secrets"
+)
+
+func testKeyring(t *testing.T, previous ...string) (*secrets.Keyring, string) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	key := base64.StdEncoding.EncodeToString(raw)
+	keyring, err := secrets.NewKeyring(key, previous...)
+	if err != nil {
+		t.Fatalf("Failed to create keyring: %v", err)
+	}
+	return keyring, key
+}
+
+func TestAuthRepository_EncryptedSecrets(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("Failed to migrate users: %v", err)
+	}
+	repo := NewAuthRepository(db)
+	t.Cleanup(func() { secrets.SetDefault(nil) })
+
+	// A secret stored before encryption was enabled
+	legacy := &models.User{Username: "legacy", Email: "legacy@example.com", HashedPassword: "x", TOTPSecret: "LEGACYSECRET"}
+	if err := repo.CreateUser(legacy); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	oldKeyring, oldKey := testKeyring(t)
+	secrets.SetDefault(oldKeyring)
+	user := &models.User{Username: "alice", Email: "alice@example.com", HashedPassword: "x", TOTPSecret: "ALICESECRET"}
+	if err := repo.CreateUser(user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	storedSecret := func(id uint) string {
+		var stored string
+		db.Raw("SELECT totp_secret FROM users WHERE id = ?", id).Scan(&stored)
+		return stored
+	}
+	if stored := storedSecret(user.ID); !strings.HasPrefix(stored, "enc:") || !oldKeyring.Current(stored) {
+		t.Errorf("Expected the secret to be stored encrypted, got %q", stored)
+	}
+	if loaded, _ := repo.GetUserByID(user.ID); loaded.TOTPSecret != "ALICESECRET" {
+		t.Errorf("Expected the secret to be decrypted on load, got %q", loaded.TOTPSecret)
+	}
+
+	// Rotate to a new key, keeping the old one to read existing rows
+	newKeyring, _ := testKeyring(t, oldKey)
+	secrets.SetDefault(newKeyring)
+	count, err := repo.ReencryptSecrets()
+	if err != nil {
+		t.Fatalf("ReencryptSecrets failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 secrets re-encrypted, got %d", count)
+	}
+	for _, u := range []*models.User{legacy, user} {
+		if stored := storedSecret(u.ID); !newKeyring.Current(stored) || !strings.HasPrefix(stored, "enc:") {
+			t.Errorf("Expected %s's secret under the new key, got %q", u.Username, stored)
+		}
+	}
+	if loaded, _ := repo.GetUserByUsername("legacy"); loaded.TOTPSecret != "LEGACYSECRET" {
+		t.Errorf("Expected the legacy secret to survive rotation, got %q", loaded.TOTPSecret)
+	}
+}
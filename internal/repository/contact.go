@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/soarinferret/jats/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ContactRepository handles contact directory database operations
+type ContactRepository struct {
+	db *gorm.DB
+}
+
+// NewContactRepository creates a new contact repository
+func NewContactRepository(db *gorm.DB) *ContactRepository {
+	return &ContactRepository{db: db}
+}
+
+// GetByEmail retrieves a contact by email address, or nil if none exists
+func (r *ContactRepository) GetByEmail(email string) (*models.Contact, error) {
+	var contact models.Contact
+	err := r.db.Where("email = ?", email).First(&contact).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get contact by email: %w", err)
+	}
+	return &contact, nil
+}
+
+// GetByID retrieves a contact with its tasks, newest first, or nil if none exists
+func (r *ContactRepository) GetByID(id uint) (*models.Contact, error) {
+	var contact models.Contact
+	err := r.db.Preload("Tasks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("tasks.created_at DESC")
+	}).First(&contact, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get contact: %w", err)
+	}
+	contact.TaskCount = len(contact.Tasks)
+	return &contact, nil
+}
+
+// List retrieves all contacts ordered by name, with their task counts
+func (r *ContactRepository) List() ([]*models.Contact, error) {
+	var contacts []*models.Contact
+	if err := r.db.Order("name ASC, email ASC").Find(&contacts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+
+	type taskCount struct {
+		ContactID uint
+		Count     int
+	}
+	var counts []taskCount
+	err := r.db.Table("contact_tasks").
+		Select("contact_id, COUNT(*) AS count").
+		Group("contact_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count contact tasks: %w", err)
+	}
+
+	countByContact := make(map[uint]int, len(counts))
+	for _, c := range counts {
+		countByContact[c.ContactID] = c.Count
+	}
+	for _, contact := range contacts {
+		contact.TaskCount = countByContact[contact.ID]
+	}
+
+	return contacts, nil
+}
+
+// Save creates or updates a contact
+func (r *ContactRepository) Save(contact *models.Contact) error {
+	if err := r.db.Save(contact).Error; err != nil {
+		return fmt.Errorf("failed to save contact: %w", err)
+	}
+	return nil
+}
+
+// LinkTask associates a task with a contact; linking twice is a no-op
+func (r *ContactRepository) LinkTask(contact *models.Contact, taskID uint) error {
+	link := map[string]interface{}{"contact_id": contact.ID, "task_id": taskID}
+	err := r.db.Table("contact_tasks").Clauses(clause.OnConflict{DoNothing: true}).Create(link).Error
+	if err != nil {
+		return fmt.Errorf("failed to link task to contact: %w", err)
+	}
+	return nil
+}
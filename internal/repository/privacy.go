@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PrivacyRepository reads and anonymizes the data attributable to a user, by
+// username or email address, across the tables that hold it
+type PrivacyRepository struct {
+	db *gorm.DB
+}
+
+// NewPrivacyRepository creates a new privacy repository
+func NewPrivacyRepository(db *gorm.DB) *PrivacyRepository {
+	return &PrivacyRepository{db: db}
+}
+
+// GetUser retrieves a user by ID, including deleted users, or nil if none exists
+func (r *PrivacyRepository) GetUser(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.Unscoped().First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &user, nil
+}
+
+// GetSessions retrieves a user's sessions, including ended ones
+func (r *PrivacyRepository) GetSessions(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Unscoped().Where("user_id = ?", userID).Order("created_at").Find(&sessions).Error
+	return sessions, err
+}
+
+// GetAPIKeys retrieves a user's API keys, including deleted ones
+func (r *PrivacyRepository) GetAPIKeys(userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.Unscoped().Where("user_id = ?", userID).Order("created_at").Find(&keys).Error
+	return keys, err
+}
+
+// GetLoginAttempts retrieves the sign-in attempts made with a username
+func (r *PrivacyRepository) GetLoginAttempts(username string) ([]models.LoginAttempt, error) {
+	var attempts []models.LoginAttempt
+	err := r.db.Where("username = ?", username).Order("created_at").Find(&attempts).Error
+	return attempts, err
+}
+
+// GetMutes retrieves a user's notification mutes
+func (r *PrivacyRepository) GetMutes(userID uint) ([]models.NotificationMute, error) {
+	var mutes []models.NotificationMute
+	err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&mutes).Error
+	return mutes, err
+}
+
+// GetOutOfOffice retrieves a user's out-of-office ranges
+func (r *PrivacyRepository) GetOutOfOffice(userID uint) ([]models.OutOfOffice, error) {
+	var ranges []models.OutOfOffice
+	err := r.db.Where("user_id = ?", userID).Order("start_date").Find(&ranges).Error
+	return ranges, err
+}
+
+// GetAssignedTasks retrieves the tasks assigned to a username, including archived ones
+func (r *PrivacyRepository) GetAssignedTasks(username string) ([]models.Task, error) {
+	var tasks []models.Task
+	err := r.db.Unscoped().Where("assignee = ?", username).Order("id").Find(&tasks).Error
+	return tasks, err
+}
+
+// GetTasksCreatedByEmail retrieves the tasks opened by emails from an address
+func (r *PrivacyRepository) GetTasksCreatedByEmail(email string) ([]models.Task, error) {
+	messageIDs := r.db.Model(&models.EmailMessage{}).Select("message_id").
+		Where("LOWER(?) = ?", clause.Column{Name: "from"}, strings.ToLower(email))
+	var tasks []models.Task
+	err := r.db.Unscoped().Where("email_message_id IN (?)", messageIDs).Order("id").Find(&tasks).Error
+	return tasks, err
+}
+
+// GetTimeEntries retrieves the time logged on the given tasks or by a username
+func (r *PrivacyRepository) GetTimeEntries(taskIDs []uint, username string) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	query := r.db.Where("LOWER(created_by) = ?", strings.ToLower(username))
+	if len(taskIDs) > 0 {
+		query = query.Or("task_id IN ?", taskIDs)
+	}
+	err := query.Order("created_at").Find(&entries).Error
+	return entries, err
+}
+
+// GetComments retrieves the comments received from an email address or
+// written by a username
+func (r *PrivacyRepository) GetComments(email, username string) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.Where("LOWER(from_email) = ?", strings.ToLower(email)).
+		Or("LOWER(created_by) = ?", strings.ToLower(username)).
+		Order("created_at").Find(&comments).Error
+	return comments, err
+}
+
+// GetEmails retrieves the emails sent from, to or copied to an address
+func (r *PrivacyRepository) GetEmails(email string) ([]models.EmailMessage, error) {
+	var emails []models.EmailMessage
+	err := r.emailsQuery(r.db, email).Order("received_at").Find(&emails).Error
+	return emails, err
+}
+
+// emailsQuery matches emails involving an address. Recipients are stored as
+// JSON arrays, so they are matched as quoted strings.
+func (r *PrivacyRepository) emailsQuery(db *gorm.DB, email string) *gorm.DB {
+	email = strings.ToLower(email)
+	quoted := "%" + `"` + email + `"` + "%"
+	return db.Where("LOWER(?) = ? OR LOWER(?) LIKE ? OR LOWER(?) LIKE ?",
+		clause.Column{Name: "from"}, email,
+		clause.Column{Name: "to"}, quoted,
+		clause.Column{Name: "cc"}, quoted)
+}
+
+// GetSubscriptions retrieves the tasks an email address is subscribed to
+func (r *PrivacyRepository) GetSubscriptions(email string) ([]models.TaskSubscriber, error) {
+	var subscribers []models.TaskSubscriber
+	err := r.db.Where("LOWER(email) = ?", strings.ToLower(email)).Order("created_at").Find(&subscribers).Error
+	return subscribers, err
+}
+
+// GetScheduledActions retrieves the pending actions scheduled by a username
+func (r *PrivacyRepository) GetScheduledActions(username string) ([]models.ScheduledAction, error) {
+	var actions []models.ScheduledAction
+	err := r.db.Where("created_by = ?", username).Order("run_at").Find(&actions).Error
+	return actions, err
+}
+
+// GetContact retrieves the contact record for an email address, or nil if none exists
+func (r *PrivacyRepository) GetContact(email string) (*models.Contact, error) {
+	var contact models.Contact
+	if err := r.db.Where("LOWER(email) = ?", strings.ToLower(email)).First(&contact).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &contact, nil
+}
+
+// EraseUser anonymizes a user in one transaction. Their account is renamed to
+// username and email, deactivated and deleted; their sessions, API keys, sign-in
+// attempts, mutes, subscriptions and quarantined mail are removed; and the
+// tasks, comments, emails and scheduled actions naming them are kept with the
+// placeholders in place of their name and address.
+func (r *PrivacyRepository) EraseUser(user *models.User, username, email string) error {
+	oldUsername, oldEmail := user.Username, strings.ToLower(user.Email)
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"username":         username,
+			"email":            email,
+			"hashed_password":  "",
+			"totp_secret":      "",
+			"totp_enabled":     false,
+			"is_active":        false,
+			"standup_email":    false,
+			"dashboard_layout": nil,
+			"landing_view":     "",
+			"landing_query_id": nil,
+			"pinned_query_ids": nil,
+			"last_login_at":    nil,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to anonymize user: %w", err)
+		}
+		if err := tx.Delete(&models.User{}, user.ID).Error; err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+
+		for _, step := range []struct {
+			what  string
+			query *gorm.DB
+			model interface{}
+		}{
+			{"sessions", tx.Unscoped().Where("user_id = ?", user.ID), &models.Session{}},
+			{"API keys", tx.Unscoped().Where("user_id = ?", user.ID), &models.APIKey{}},
+			{"login attempts", tx.Where("username = ?", oldUsername), &models.LoginAttempt{}},
+			{"mutes", tx.Where("user_id = ?", user.ID), &models.NotificationMute{}},
+			{"out-of-office ranges", tx.Where("user_id = ?", user.ID), &models.OutOfOffice{}},
+			{"subscriptions", tx.Where("LOWER(email) = ?", oldEmail), &models.TaskSubscriber{}},
+			{"quarantined emails", tx.Where("LOWER(?) = ?", clause.Column{Name: "from"}, oldEmail), &models.QuarantinedEmail{}},
+		} {
+			if err := step.query.Delete(step.model).Error; err != nil {
+				return fmt.Errorf("failed to delete %s: %w", step.what, err)
+			}
+		}
+
+		for _, step := range []struct {
+			what          string
+			model         interface{}
+			column, value string
+			replacement   string
+		}{
+			{"assigned tasks", &models.Task{}, "assignee", oldUsername, username},
+			{"assignment history", &models.TaskAssignment{}, "assignee", oldUsername, username},
+			{"scheduled actions", &models.ScheduledAction{}, "created_by", oldUsername, username},
+			{"note authors", &models.Comment{}, "created_by", oldUsername, username},
+			{"time entry authors", &models.TimeEntry{}, "created_by", oldUsername, username},
+			{"status history", &models.TaskStatusChange{}, "changed_by", oldUsername, username},
+			{"review history", &models.TaskReview{}, "reviewer", oldUsername, username},
+			{"comments", &models.Comment{}, "from_email", oldEmail, email},
+		} {
+			query := tx.Unscoped().Model(step.model).Where("LOWER(?) = ?", clause.Column{Name: step.column}, strings.ToLower(step.value))
+			if err := query.UpdateColumn(step.column, step.replacement).Error; err != nil {
+				return fmt.Errorf("failed to anonymize %s: %w", step.what, err)
+			}
+		}
+
+		if err := tx.Model(&models.Contact{}).Where("LOWER(email) = ?", oldEmail).
+			Updates(map[string]interface{}{"name": "", "email": email, "organization": ""}).Error; err != nil {
+			return fmt.Errorf("failed to anonymize contact: %w", err)
+		}
+
+		var emails []models.EmailMessage
+		if err := r.emailsQuery(tx, oldEmail).Find(&emails).Error; err != nil {
+			return fmt.Errorf("failed to read emails: %w", err)
+		}
+		for i := range emails {
+			message := &emails[i]
+			if strings.EqualFold(message.From, oldEmail) {
+				message.From = email
+			}
+			message.To = replaceAddress(message.To, oldEmail, email)
+			message.CC = replaceAddress(message.CC, oldEmail, email)
+			if err := tx.Model(message).Select("From", "To", "CC").UpdateColumns(message).Error; err != nil {
+				return fmt.Errorf("failed to anonymize email %d: %w", message.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// replaceAddress replaces an address in a recipient list, ignoring case
+func replaceAddress(addresses []string, old, replacement string) []string {
+	for i, address := range addresses {
+		if strings.EqualFold(address, old) {
+			addresses[i] = replacement
+		}
+	}
+	return addresses
+}
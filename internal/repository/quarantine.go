@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/soarinferret/jats/internal/models"
+	"gorm.io/gorm"
+)
+
+// QuarantineRepository handles quarantined email database operations
+type QuarantineRepository struct {
+	db *gorm.DB
+}
+
+// NewQuarantineRepository creates a new quarantine repository
+func NewQuarantineRepository(db *gorm.DB) *QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+// Create stores a quarantined email
+func (r *QuarantineRepository) Create(email *models.QuarantinedEmail) error {
+	if err := r.db.Create(email).Error; err != nil {
+		return fmt.Errorf("failed to quarantine email: %w", err)
+	}
+	return nil
+}
+
+// List retrieves quarantined emails, newest first, without their raw messages
+func (r *QuarantineRepository) List() ([]*models.QuarantinedEmail, error) {
+	var emails []*models.QuarantinedEmail
+	err := r.db.Omit("raw_message").Order("received_at DESC").Find(&emails).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined emails: %w", err)
+	}
+	return emails, nil
+}
+
+// GetByID retrieves a quarantined email including its raw message, or nil if none exists
+func (r *QuarantineRepository) GetByID(id uint) (*models.QuarantinedEmail, error) {
+	var email models.QuarantinedEmail
+	err := r.db.First(&email, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get quarantined email: %w", err)
+	}
+	return &email, nil
+}
+
+// Delete removes a quarantined email
+func (r *QuarantineRepository) Delete(id uint) error {
+	if err := r.db.Delete(&models.QuarantinedEmail{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete quarantined email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/soarinferret/jats/internal/models"
+	"gorm.io/gorm"
+)
+
+// SettingsRepository handles instance settings database operations
+type SettingsRepository struct {
+	db *gorm.DB
+}
+
+// NewSettingsRepository creates a new settings repository
+func NewSettingsRepository(db *gorm.DB) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+// GetBranding retrieves the branding settings, or nil if none have been saved
+func (r *SettingsRepository) GetBranding() (*models.BrandingSettings, error) {
+	var settings models.BrandingSettings
+	err := r.db.First(&settings, models.BrandingSettingsID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get branding settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SaveBranding creates or updates the branding settings
+func (r *SettingsRepository) SaveBranding(settings *models.BrandingSettings) error {
+	settings.ID = models.BrandingSettingsID
+	if err := r.db.Save(settings).Error; err != nil {
+		return fmt.Errorf("failed to save branding settings: %w", err)
+	}
+	return nil
+}
+
+// ListCannedResponses retrieves all canned responses ordered by name
+func (r *SettingsRepository) ListCannedResponses() ([]*models.CannedResponse, error) {
+	var responses []*models.CannedResponse
+	if err := r.db.Order("name ASC").Find(&responses).Error; err != nil {
+		return nil, fmt.Errorf("failed to list canned responses: %w", err)
+	}
+	return responses, nil
+}
+
+// GetCannedResponse retrieves a canned response by ID, or nil if none exists
+func (r *SettingsRepository) GetCannedResponse(id uint) (*models.CannedResponse, error) {
+	var response models.CannedResponse
+	err := r.db.First(&response, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get canned response: %w", err)
+	}
+	return &response, nil
+}
+
+// GetCannedResponseByName retrieves a canned response by name, or nil if none exists
+func (r *SettingsRepository) GetCannedResponseByName(name string) (*models.CannedResponse, error) {
+	var response models.CannedResponse
+	err := r.db.Where("name = ?", name).First(&response).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get canned response by name: %w", err)
+	}
+	return &response, nil
+}
+
+// SaveCannedResponse creates or updates a canned response
+func (r *SettingsRepository) SaveCannedResponse(response *models.CannedResponse) error {
+	if err := r.db.Save(response).Error; err != nil {
+		return fmt.Errorf("failed to save canned response: %w", err)
+	}
+	return nil
+}
+
+// DeleteCannedResponse deletes a canned response by ID
+func (r *SettingsRepository) DeleteCannedResponse(id uint) error {
+	if err := r.db.Delete(&models.CannedResponse{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete canned response: %w", err)
+	}
+	return nil
+}
+
+// ListAutomationRules retrieves automation rules in the order they were created,
+// which is the order they are applied in
+func (r *SettingsRepository) ListAutomationRules() ([]*models.AutomationRule, error) {
+	var rules []*models.AutomationRule
+	if err := r.db.Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list automation rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetAutomationRule retrieves an automation rule by ID, or nil if none exists
+func (r *SettingsRepository) GetAutomationRule(id uint) (*models.AutomationRule, error) {
+	var rule models.AutomationRule
+	err := r.db.First(&rule, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get automation rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// SaveAutomationRule creates or updates an automation rule
+func (r *SettingsRepository) SaveAutomationRule(rule *models.AutomationRule) error {
+	if err := r.db.Save(rule).Error; err != nil {
+		return fmt.Errorf("failed to save automation rule: %w", err)
+	}
+	return nil
+}
+
+// DeleteAutomationRule deletes an automation rule by ID
+func (r *SettingsRepository) DeleteAutomationRule(id uint) error {
+	if err := r.db.Delete(&models.AutomationRule{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete automation rule: %w", err)
+	}
+	return nil
+}
@@ -1,8 +1,15 @@
 package repository
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/tagexpr"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskRepository struct {
@@ -36,6 +43,32 @@ func (r *TaskRepository) GetAll() ([]*models.Task, error) {
 	return tasks, err
 }
 
+func (r *TaskRepository) CountByStatus(status models.TaskStatus) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Task{}).Where("status = ?", status).Count(&count).Error
+	return count, err
+}
+
+// UpdateWithStatusChange saves a task and, unless change is nil, appends it to
+// the task's status history in the same transaction
+func (r *TaskRepository) UpdateWithStatusChange(task *models.Task, change *models.TaskStatusChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(task).Error; err != nil {
+			return err
+		}
+		if change == nil {
+			return nil
+		}
+		return tx.Create(change).Error
+	})
+}
+
+func (r *TaskRepository) GetStatusChanges(taskID uint) ([]*models.TaskStatusChange, error) {
+	var changes []*models.TaskStatusChange
+	err := r.db.Where("task_id = ?", taskID).Order("changed_at ASC").Find(&changes).Error
+	return changes, err
+}
+
 func (r *TaskRepository) Update(task *models.Task) error {
 	return r.db.Save(task).Error
 }
@@ -53,9 +86,38 @@ func (r *TaskRepository) GetByEmailMessageID(messageID string) (*models.Task, er
 	return &task, nil
 }
 
+// GetByShortCode returns the task a short link code belongs to
+func (r *TaskRepository) GetByShortCode(code string) (*models.Task, error) {
+	var task models.Task
+	err := r.db.Where("short_code = ?", code).First(&task).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// SetShortCode gives a task its short link code unless it already has one,
+// leaving updated_at alone. It reports whether the code was set.
+func (r *TaskRepository) SetShortCode(id uint, code string) (bool, error) {
+	result := r.db.Model(&models.Task{}).
+		Where("id = ? AND (short_code = '' OR short_code IS NULL)", id).
+		UpdateColumn("short_code", code)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetByInboundKey returns the most recent task opened by a webhook alert
+func (r *TaskRepository) GetByInboundKey(key string) (*models.Task, error) {
+	var task models.Task
+	err := r.db.Where("inbound_key = ?", key).Order("id DESC").First(&task).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
 func (r *TaskRepository) GetTimeEntries(taskID uint) ([]*models.TimeEntry, error) {
 	var entries []*models.TimeEntry
-	err := r.db.Where("task_id = ?", taskID).Find(&entries).Error
+	err := r.db.Where("task_id = ?", taskID).Order("created_at").Find(&entries).Error
 	return entries, err
 }
 
@@ -63,16 +125,205 @@ func (r *TaskRepository) AddTimeEntry(entry *models.TimeEntry) error {
 	return r.db.Create(entry).Error
 }
 
+func (r *TaskRepository) GetTimeEntry(id uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := r.db.First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *TaskRepository) UpdateTimeEntry(entry *models.TimeEntry) error {
+	return r.db.Save(entry).Error
+}
+
+func (r *TaskRepository) DeleteTimeEntry(id uint) error {
+	return r.db.Delete(&models.TimeEntry{}, id).Error
+}
+
+// batchInsertSize is how many rows go into each multi-row INSERT
+const batchInsertSize = 100
+
+// CreateTimeEntries inserts time entries with multi-row inserts and saves the
+// tasks they were logged against, with any status history, in one transaction
+func (r *TaskRepository) CreateTimeEntries(entries []*models.TimeEntry, tasks []*models.Task, changes []*models.TaskStatusChange) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(entries, batchInsertSize).Error; err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if err := tx.Save(task).Error; err != nil {
+				return err
+			}
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return tx.Create(changes).Error
+	})
+}
+
+// SaveTimesheet creates, updates and deletes time entries and saves the tasks
+// they were logged against, with any status history, in one transaction
+func (r *TaskRepository) SaveTimesheet(created, updated []*models.TimeEntry, deleted []uint, tasks []*models.Task, changes []*models.TaskStatusChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(created) > 0 {
+			if err := tx.CreateInBatches(created, batchInsertSize).Error; err != nil {
+				return err
+			}
+		}
+		for _, entry := range updated {
+			if err := tx.Save(entry).Error; err != nil {
+				return err
+			}
+		}
+		if len(deleted) > 0 {
+			if err := tx.Delete(&models.TimeEntry{}, deleted).Error; err != nil {
+				return err
+			}
+		}
+		// The tasks' preloaded entries are stale; saving them would undo the changes
+		for _, task := range tasks {
+			if err := tx.Omit(clause.Associations).Save(task).Error; err != nil {
+				return err
+			}
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return tx.Create(changes).Error
+	})
+}
+
 func (r *TaskRepository) GetComments(taskID uint) ([]*models.Comment, error) {
 	var comments []*models.Comment
 	err := r.db.Where("task_id = ?", taskID).Order("created_at asc").Find(&comments).Error
 	return comments, err
 }
 
+// GetCommentsByTaskIDs returns the comments of several tasks, oldest first
+func (r *TaskRepository) GetCommentsByTaskIDs(taskIDs []uint) ([]*models.Comment, error) {
+	var comments []*models.Comment
+	if len(taskIDs) == 0 {
+		return comments, nil
+	}
+	err := r.db.Where("task_id IN ?", taskIDs).Order("created_at asc").Find(&comments).Error
+	return comments, err
+}
+
+// SearchComments returns the comments containing query, ignoring case
+func (r *TaskRepository) SearchComments(query string) ([]*models.Comment, error) {
+	var comments []*models.Comment
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(query)) + "%"
+	err := r.db.Where("LOWER(content) LIKE ? ESCAPE '\\'", pattern).Order("created_at asc").Find(&comments).Error
+	return comments, err
+}
+
+// SearchTaskIDs returns the IDs of the tasks where query appears, ignoring
+// case, in any of the fields: name, description, comments or time_entries
+func (r *TaskRepository) SearchTaskIDs(query string, fields []string) ([]uint, error) {
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(query)) + "%"
+	seen := make(map[uint]bool)
+	var ids []uint
+	collect := func(tx *gorm.DB, column string) error {
+		var found []uint
+		if err := tx.Distinct(column).Pluck(column, &found).Error; err != nil {
+			return err
+		}
+		for _, id := range found {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return nil
+	}
+
+	for _, field := range fields {
+		var err error
+		switch field {
+		case "name", "description":
+			err = collect(r.db.Model(&models.Task{}).Where("LOWER("+field+") LIKE ? ESCAPE '\\'", pattern), "id")
+		case "comments":
+			err = collect(r.db.Model(&models.Comment{}).Where("LOWER(content) LIKE ? ESCAPE '\\'", pattern), "task_id")
+		case "time_entries":
+			err = collect(r.db.Model(&models.TimeEntry{}).Where("LOWER(description) LIKE ? ESCAPE '\\'", pattern), "task_id")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// likeEscaper escapes the LIKE wildcards in a search term
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// GetCommentByEmailMessageID returns the comment sent as the email with the given Message-ID
+func (r *TaskRepository) GetCommentByEmailMessageID(messageID string) (*models.Comment, error) {
+	var comment models.Comment
+	err := r.db.Where("email_message_id = ?", messageID).First(&comment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetCommentByTrackingToken returns the comment sent as the email with the given receipt token
+func (r *TaskRepository) GetCommentByTrackingToken(token string) (*models.Comment, error) {
+	var comment models.Comment
+	err := r.db.Where("tracking_token = ?", token).First(&comment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *TaskRepository) CreateEmailReceipt(receipt *models.EmailReceipt) error {
+	return r.db.Create(receipt).Error
+}
+
+// GetTaskEmailReceipts returns the receipts for the comments of a task, oldest first
+func (r *TaskRepository) GetTaskEmailReceipts(taskID uint) ([]*models.EmailReceipt, error) {
+	var receipts []*models.EmailReceipt
+	err := r.db.Joins("JOIN comments ON comments.id = email_receipts.comment_id").
+		Where("comments.task_id = ?", taskID).
+		Order("email_receipts.occurred_at, email_receipts.id").
+		Find(&receipts).Error
+	return receipts, err
+}
+
+// SaveEmailMessage records an email exchanged on a task. A message that was
+// already recorded, e.g. when a mailbox is re-read, is left untouched.
+func (r *TaskRepository) SaveEmailMessage(message *models.EmailMessage) error {
+	return r.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "message_id"}}, DoNothing: true}).Create(message).Error
+}
+
+// GetTaskEmailMessages returns the emails exchanged on a task, oldest first
+func (r *TaskRepository) GetTaskEmailMessages(taskID uint) ([]*models.EmailMessage, error) {
+	var messages []*models.EmailMessage
+	err := r.db.Where("task_id = ?", taskID).Order("received_at ASC, id ASC").Find(&messages).Error
+	return messages, err
+}
+
 func (r *TaskRepository) AddComment(comment *models.Comment) error {
 	return r.db.Create(comment).Error
 }
 
+// CreateComments inserts comments with multi-row inserts in one transaction
+func (r *TaskRepository) CreateComments(comments []*models.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(comments, batchInsertSize).Error
+	})
+}
+
 func (r *TaskRepository) AddSubscriber(subscriber *models.TaskSubscriber) error {
 	return r.db.FirstOrCreate(subscriber, "task_id = ? AND email = ?", subscriber.TaskID, subscriber.Email).Error
 }
@@ -87,12 +338,74 @@ func (r *TaskRepository) CreateSavedQuery(query *models.SavedQuery) error {
 	return r.db.Create(query).Error
 }
 
+// EnsureFeedTokens gives every saved query without a feed token one from
+// generate, and drops the index feed tokens had before they were unique. It
+// runs before the saved_queries table is migrated, which adds the unique index.
+func (r *TaskRepository) EnsureFeedTokens(generate func() (string, error)) error {
+	migrator := r.db.Migrator()
+	if !migrator.HasTable(&models.SavedQuery{}) {
+		return nil
+	}
+	if !migrator.HasColumn(&models.SavedQuery{}, "FeedToken") {
+		if err := migrator.AddColumn(&models.SavedQuery{}, "FeedToken"); err != nil {
+			return err
+		}
+	}
+
+	var ids []uint
+	if err := r.db.Model(&models.SavedQuery{}).Where("feed_token IS NULL OR feed_token = ''").Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		token, err := generate()
+		if err != nil {
+			return err
+		}
+		if err := r.db.Model(&models.SavedQuery{}).Where("id = ?", id).UpdateColumn("feed_token", token).Error; err != nil {
+			return err
+		}
+	}
+
+	indexes, err := migrator.GetIndexes(&models.SavedQuery{})
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if unique, _ := index.Unique(); index.Name() == "idx_saved_queries_feed_token" && !unique {
+			return migrator.DropIndex(&models.SavedQuery{}, index.Name())
+		}
+	}
+	return nil
+}
+
 func (r *TaskRepository) GetSavedQueries() ([]*models.SavedQuery, error) {
 	var queries []*models.SavedQuery
-	err := r.db.Order("name").Find(&queries).Error
+	err := r.db.Order("position, name").Find(&queries).Error
 	return queries, err
 }
 
+// NextSavedQueryPosition returns the position that places a new saved query last
+func (r *TaskRepository) NextSavedQueryPosition() (int, error) {
+	var position *int
+	err := r.db.Model(&models.SavedQuery{}).Select("MAX(position)").Scan(&position).Error
+	if err != nil || position == nil {
+		return 0, err
+	}
+	return *position + 1, nil
+}
+
+// SetSavedQueryPositions stores the order of the given saved queries in one transaction
+func (r *TaskRepository) SetSavedQueryPositions(ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for position, id := range ids {
+			if err := tx.Model(&models.SavedQuery{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *TaskRepository) GetSavedQueryByID(id uint) (*models.SavedQuery, error) {
 	var query models.SavedQuery
 	err := r.db.First(&query, id).Error
@@ -102,14 +415,341 @@ func (r *TaskRepository) GetSavedQueryByID(id uint) (*models.SavedQuery, error)
 	return &query, nil
 }
 
+func (r *TaskRepository) GetSavedQueryByFeedToken(token string) (*models.SavedQuery, error) {
+	var query models.SavedQuery
+	err := r.db.Where("feed_token = ?", token).First(&query).Error
+	if err != nil {
+		return nil, err
+	}
+	return &query, nil
+}
+
 func (r *TaskRepository) UpdateSavedQuery(query *models.SavedQuery) error {
 	return r.db.Save(query).Error
 }
 
 func (r *TaskRepository) DeleteSavedQuery(id uint) error {
+	if err := r.db.Where("saved_query_id = ?", id).Delete(&models.SavedQuerySchedule{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("saved_query_id = ?", id).Delete(&models.SavedQueryWebhook{}).Error; err != nil {
+		return err
+	}
 	return r.db.Delete(&models.SavedQuery{}, id).Error
 }
 
+// GetSavedQuerySchedule returns the schedule of a saved query, or nil if it has none
+func (r *TaskRepository) GetSavedQuerySchedule(queryID uint) (*models.SavedQuerySchedule, error) {
+	var schedule models.SavedQuerySchedule
+	err := r.db.Where("saved_query_id = ?", queryID).First(&schedule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *TaskRepository) SaveSavedQuerySchedule(schedule *models.SavedQuerySchedule) error {
+	return r.db.Save(schedule).Error
+}
+
+func (r *TaskRepository) DeleteSavedQuerySchedule(queryID uint) error {
+	return r.db.Where("saved_query_id = ?", queryID).Delete(&models.SavedQuerySchedule{}).Error
+}
+
+// GetDueSavedQuerySchedules returns enabled schedules whose next run is at or before now
+func (r *TaskRepository) GetDueSavedQuerySchedules(now time.Time) ([]*models.SavedQuerySchedule, error) {
+	var schedules []*models.SavedQuerySchedule
+	err := r.db.Where("enabled = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, now).
+		Order("next_run_at").Find(&schedules).Error
+	return schedules, err
+}
+
+// ListSavedQueryWebhooks returns every saved query webhook, grouped by saved query
+func (r *TaskRepository) ListSavedQueryWebhooks() ([]*models.SavedQueryWebhook, error) {
+	var webhooks []*models.SavedQueryWebhook
+	err := r.db.Order("saved_query_id, id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *TaskRepository) GetSavedQueryWebhook(id uint) (*models.SavedQueryWebhook, error) {
+	var webhook models.SavedQueryWebhook
+	if err := r.db.First(&webhook, id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *TaskRepository) SaveSavedQueryWebhook(webhook *models.SavedQueryWebhook) error {
+	return r.db.Save(webhook).Error
+}
+
+func (r *TaskRepository) DeleteSavedQueryWebhook(id uint) error {
+	return r.db.Delete(&models.SavedQueryWebhook{}, id).Error
+}
+
+// GetDueSavedQueryWebhooks returns enabled webhooks whose next run is at or before now
+func (r *TaskRepository) GetDueSavedQueryWebhooks(now time.Time) ([]*models.SavedQueryWebhook, error) {
+	var webhooks []*models.SavedQueryWebhook
+	err := r.db.Where("enabled = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, now).
+		Order("next_run_at").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *TaskRepository) CreateMilestone(milestone *models.Milestone) error {
+	return r.db.Create(milestone).Error
+}
+
+func (r *TaskRepository) GetMilestones() ([]*models.Milestone, error) {
+	var milestones []*models.Milestone
+	// Milestones without a due date sort last
+	err := r.db.Order("due_date IS NULL, due_date, name").Find(&milestones).Error
+	return milestones, err
+}
+
+func (r *TaskRepository) GetMilestoneByID(id uint) (*models.Milestone, error) {
+	var milestone models.Milestone
+	err := r.db.First(&milestone, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+func (r *TaskRepository) UpdateMilestone(milestone *models.Milestone) error {
+	return r.db.Save(milestone).Error
+}
+
+// DeleteMilestone deletes a milestone and detaches its tasks
+func (r *TaskRepository) DeleteMilestone(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Task{}).Where("milestone_id = ?", id).Update("milestone_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Milestone{}, id).Error
+	})
+}
+
+func (r *TaskRepository) GetTasksByMilestone(milestoneID uint) ([]*models.Task, error) {
+	var tasks []*models.Task
+	err := r.db.Preload("Subtasks").
+		Preload("TimeEntries").
+		Where("milestone_id = ?", milestoneID).
+		Order("created_at").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *TaskRepository) CreateAsset(asset *models.Asset) error {
+	return r.db.Create(asset).Error
+}
+
+// GetAssets returns all assets ordered by name, with their task counts
+func (r *TaskRepository) GetAssets() ([]*models.Asset, error) {
+	var assets []*models.Asset
+	if err := r.db.Order("name, serial").Find(&assets).Error; err != nil {
+		return nil, err
+	}
+
+	type taskCount struct {
+		AssetID uint
+		Count   int
+	}
+	var counts []taskCount
+	err := r.db.Table("asset_tasks").
+		Joins("JOIN tasks ON tasks.id = asset_tasks.task_id AND tasks.deleted_at IS NULL").
+		Select("asset_id, COUNT(*) AS count").
+		Group("asset_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	countByAsset := make(map[uint]int, len(counts))
+	for _, c := range counts {
+		countByAsset[c.AssetID] = c.Count
+	}
+	for _, asset := range assets {
+		asset.TaskCount = countByAsset[asset.ID]
+	}
+	return assets, nil
+}
+
+// GetAssetByID returns an asset with its tasks, newest first, or nil if none exists
+func (r *TaskRepository) GetAssetByID(id uint) (*models.Asset, error) {
+	var asset models.Asset
+	err := r.db.Preload("Tasks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("tasks.created_at DESC")
+	}).First(&asset, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	asset.TaskCount = len(asset.Tasks)
+	return &asset, nil
+}
+
+func (r *TaskRepository) UpdateAsset(asset *models.Asset) error {
+	return r.db.Omit("Tasks").Save(asset).Error
+}
+
+// DeleteAsset deletes an asset and unlinks its tasks
+func (r *TaskRepository) DeleteAsset(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM asset_tasks WHERE asset_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Asset{}, id).Error
+	})
+}
+
+// LinkTaskAsset links a task to an asset; linking twice is a no-op
+func (r *TaskRepository) LinkTaskAsset(taskID, assetID uint) error {
+	link := map[string]interface{}{"asset_id": assetID, "task_id": taskID}
+	return r.db.Table("asset_tasks").Clauses(clause.OnConflict{DoNothing: true}).Create(link).Error
+}
+
+// UnlinkTaskAsset removes the link between a task and an asset, reporting
+// whether there was one
+func (r *TaskRepository) UnlinkTaskAsset(taskID, assetID uint) (bool, error) {
+	result := r.db.Exec("DELETE FROM asset_tasks WHERE asset_id = ? AND task_id = ?", assetID, taskID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetTaskAssets returns the assets a task is linked to, ordered by name
+func (r *TaskRepository) GetTaskAssets(taskID uint) ([]*models.Asset, error) {
+	var assets []*models.Asset
+	err := r.db.Joins("JOIN asset_tasks ON asset_tasks.asset_id = assets.id").
+		Where("asset_tasks.task_id = ?", taskID).
+		Order("assets.name").
+		Find(&assets).Error
+	return assets, err
+}
+
+func (r *TaskRepository) CreateProject(project *models.Project) error {
+	return r.db.Create(project).Error
+}
+
+// GetProjects returns all projects, the default one first and the rest by
+// name, with their open task counts
+func (r *TaskRepository) GetProjects() ([]*models.Project, error) {
+	var projects []*models.Project
+	if err := r.db.Order("is_default DESC, name").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+
+	type openCount struct {
+		ProjectID uint
+		Count     int
+	}
+	var counts []openCount
+	err := r.db.Model(&models.Task{}).
+		Select("project_id, COUNT(*) AS count").
+		Where("project_id IS NOT NULL AND status IN ?", []models.TaskStatus{models.TaskStatusOpen, models.TaskStatusInProgress}).
+		Group("project_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	countByProject := make(map[uint]int, len(counts))
+	for _, c := range counts {
+		countByProject[c.ProjectID] = c.Count
+	}
+	for _, project := range projects {
+		project.OpenTasks = countByProject[project.ID]
+	}
+	return projects, nil
+}
+
+// GetProjectByID returns a project, or nil if none exists
+func (r *TaskRepository) GetProjectByID(id uint) (*models.Project, error) {
+	var project models.Project
+	err := r.db.First(&project, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetProjectByName returns the project with a name, ignoring case, or nil if
+// none exists
+func (r *TaskRepository) GetProjectByName(name string) (*models.Project, error) {
+	var project models.Project
+	err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&project).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *TaskRepository) UpdateProject(project *models.Project) error {
+	return r.db.Save(project).Error
+}
+
+// DeleteProject deletes a project, moving its tasks and saved queries to the
+// project moveTo
+func (r *TaskRepository) DeleteProject(id, moveTo uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Task{}).Where("project_id = ?", id).Update("project_id", moveTo).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SavedQuery{}).Where("project_id = ?", id).Update("project_id", moveTo).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Project{}, id).Error
+	})
+}
+
+// GetDefaultProject returns the default project, or nil if there is none yet
+func (r *TaskRepository) GetDefaultProject() (*models.Project, error) {
+	var project models.Project
+	err := r.db.Where("is_default = ?", true).First(&project).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// EnsureDefaultProject returns the default project, creating it if there is
+// none, and moves the tasks without a project into it. It updates the whole
+// tasks table, so it runs once at startup rather than on every request.
+func (r *TaskRepository) EnsureDefaultProject() (*models.Project, error) {
+	var project models.Project
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("is_default = ?", true).First(&project).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			project = models.Project{Name: models.DefaultProjectName, IsDefault: true}
+			err = tx.Create(&project).Error
+		}
+		if err != nil {
+			return err
+		}
+		// Not an edit of the tasks, so their updated_at is left alone
+		return tx.Model(&models.Task{}).Where("project_id IS NULL").UpdateColumn("project_id", project.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
 func (r *TaskRepository) AddSubtask(subtask *models.Subtask) error {
 	return r.db.Create(subtask).Error
 }
@@ -138,7 +778,13 @@ func (r *TaskRepository) ToggleSubtask(subtaskID uint) error {
 }
 
 func (r *TaskRepository) DeleteSubtask(subtaskID uint) error {
-	return r.db.Delete(&models.Subtask{}, subtaskID).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Keep logged time on the parent task when its subtask goes away
+		if err := tx.Model(&models.TimeEntry{}).Where("subtask_id = ?", subtaskID).Update("subtask_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Subtask{}, subtaskID).Error
+	})
 }
 
 func (r *TaskRepository) GetAttachment(attachmentID uint) (*models.Attachment, error) {
@@ -150,6 +796,499 @@ func (r *TaskRepository) GetAttachment(attachmentID uint) (*models.Attachment, e
 	return &attachment, nil
 }
 
+func (r *TaskRepository) GetAttachmentsByTask(taskID uint) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	commentIDs := r.db.Model(&models.Comment{}).Select("id").Where("task_id = ?", taskID)
+	err := r.db.Where("task_id = ? OR comment_id IN (?)", taskID, commentIDs).
+		Order("created_at ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
 func (r *TaskRepository) AddAttachment(attachment *models.Attachment) error {
 	return r.db.Create(attachment).Error
 }
+
+func (r *TaskRepository) ReplaceTaskReferences(sourceTaskID uint, commentID *uint, targetTaskIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("source_task_id = ?", sourceTaskID)
+		if commentID == nil {
+			query = query.Where("comment_id IS NULL")
+		} else {
+			query = query.Where("comment_id = ?", *commentID)
+		}
+		if err := query.Delete(&models.TaskReference{}).Error; err != nil {
+			return err
+		}
+
+		if len(targetTaskIDs) == 0 {
+			return nil
+		}
+
+		// Only link tasks that exist
+		var existing []uint
+		if err := tx.Model(&models.Task{}).Where("id IN ?", targetTaskIDs).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		if len(existing) == 0 {
+			return nil
+		}
+
+		references := make([]models.TaskReference, 0, len(existing))
+		for _, targetID := range existing {
+			references = append(references, models.TaskReference{
+				SourceTaskID: sourceTaskID,
+				TargetTaskID: targetID,
+				CommentID:    commentID,
+			})
+		}
+		return tx.Create(&references).Error
+	})
+}
+
+func (r *TaskRepository) GetReferencedTasks(taskID uint) ([]models.TaskLink, error) {
+	var links []models.TaskLink
+	err := r.db.Model(&models.Task{}).
+		Distinct("tasks.id", "tasks.name", "tasks.status").
+		Joins("JOIN task_references ON task_references.target_task_id = tasks.id").
+		Where("task_references.source_task_id = ?", taskID).
+		Order("tasks.id").
+		Scan(&links).Error
+	return links, err
+}
+
+func (r *TaskRepository) GetReferencingTasks(taskID uint) ([]models.TaskLink, error) {
+	var links []models.TaskLink
+	err := r.db.Model(&models.Task{}).
+		Distinct("tasks.id", "tasks.name", "tasks.status").
+		Joins("JOIN task_references ON task_references.source_task_id = tasks.id").
+		Where("task_references.target_task_id = ?", taskID).
+		Order("tasks.id").
+		Scan(&links).Error
+	return links, err
+}
+
+// AddTaskDependency records that one task blocks another; adding it twice is a no-op
+func (r *TaskRepository) AddTaskDependency(dependency *models.TaskDependency) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(dependency).Error
+}
+
+// RemoveTaskDependency removes the dependency between two tasks, whichever
+// blocks the other, reporting whether there was one
+func (r *TaskRepository) RemoveTaskDependency(taskID, otherTaskID uint) (bool, error) {
+	result := r.db.Where("(blocking_task_id = ? AND blocked_task_id = ?) OR (blocking_task_id = ? AND blocked_task_id = ?)",
+		taskID, otherTaskID, otherTaskID, taskID).Delete(&models.TaskDependency{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetTaskDependencies returns every dependency between tasks that exist
+func (r *TaskRepository) GetTaskDependencies() ([]*models.TaskDependency, error) {
+	var dependencies []*models.TaskDependency
+	err := r.db.Joins("JOIN tasks blocking ON blocking.id = task_dependencies.blocking_task_id AND blocking.deleted_at IS NULL").
+		Joins("JOIN tasks blocked ON blocked.id = task_dependencies.blocked_task_id AND blocked.deleted_at IS NULL").
+		Find(&dependencies).Error
+	return dependencies, err
+}
+
+// GetBlockingTasks returns the tasks blocking a task
+func (r *TaskRepository) GetBlockingTasks(taskID uint) ([]models.TaskLink, error) {
+	var links []models.TaskLink
+	err := r.db.Model(&models.Task{}).
+		Select("tasks.id", "tasks.name", "tasks.status").
+		Joins("JOIN task_dependencies ON task_dependencies.blocking_task_id = tasks.id").
+		Where("task_dependencies.blocked_task_id = ?", taskID).
+		Order("tasks.id").
+		Scan(&links).Error
+	return links, err
+}
+
+// GetBlockedTasks returns the tasks a task blocks
+func (r *TaskRepository) GetBlockedTasks(taskID uint) ([]models.TaskLink, error) {
+	var links []models.TaskLink
+	err := r.db.Model(&models.Task{}).
+		Select("tasks.id", "tasks.name", "tasks.status").
+		Joins("JOIN task_dependencies ON task_dependencies.blocked_task_id = tasks.id").
+		Where("task_dependencies.blocking_task_id = ?", taskID).
+		Order("tasks.id").
+		Scan(&links).Error
+	return links, err
+}
+
+// betweenScope limits a query to rows whose column falls in [since, until); zero bounds are open
+func betweenScope(column string, since, until time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !since.IsZero() {
+			db = db.Where(column+" >= ?", since)
+		}
+		if !until.IsZero() {
+			db = db.Where(column+" < ?", until)
+		}
+		return db
+	}
+}
+
+func (r *TaskRepository) GetTasksCreatedBetween(since, until time.Time) ([]*models.Task, error) {
+	var tasks []*models.Task
+	err := r.db.Scopes(betweenScope("created_at", since, until)).Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *TaskRepository) GetStatusChangesBetween(since, until time.Time) ([]*models.TaskStatusChange, error) {
+	var changes []*models.TaskStatusChange
+	err := r.db.Scopes(betweenScope("changed_at", since, until)).Find(&changes).Error
+	return changes, err
+}
+
+func (r *TaskRepository) GetTaskAssignmentsBetween(since, until time.Time) ([]*models.TaskAssignment, error) {
+	var assignments []*models.TaskAssignment
+	err := r.db.Scopes(betweenScope("assigned_at", since, until)).Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *TaskRepository) GetCommentsBetween(since, until time.Time) ([]*models.Comment, error) {
+	var comments []*models.Comment
+	err := r.db.Scopes(betweenScope("created_at", since, until)).Find(&comments).Error
+	return comments, err
+}
+
+func (r *TaskRepository) GetTimeEntriesBetween(since, until time.Time) ([]*models.TimeEntry, error) {
+	var entries []*models.TimeEntry
+	err := r.db.Scopes(betweenScope("created_at", since, until)).Find(&entries).Error
+	return entries, err
+}
+
+// GetTaskIDsTouchedBy returns the IDs of the tasks a user has commented on,
+// logged time on or changed the status of, most recently touched first
+func (r *TaskRepository) GetTaskIDsTouchedBy(username string) ([]uint, error) {
+	var ids []uint
+	err := r.db.Raw(`SELECT task_id FROM (
+			SELECT task_id, updated_at AS touched_at FROM comments WHERE created_by = ?
+			UNION ALL SELECT task_id, updated_at FROM time_entries WHERE created_by = ?
+			UNION ALL SELECT task_id, changed_at FROM task_status_changes WHERE changed_by = ?
+		) touches GROUP BY task_id ORDER BY MAX(touched_at) DESC, task_id`,
+		username, username, username).Scan(&ids).Error
+	return ids, err
+}
+
+func (r *TaskRepository) GetByIDs(ids []uint) ([]*models.Task, error) {
+	var tasks []*models.Task
+	if len(ids) == 0 {
+		return tasks, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&tasks).Error
+	return tasks, err
+}
+
+// DeleteStatusChangesBefore removes status history recorded before the given time
+func (r *TaskRepository) DeleteStatusChangesBefore(before time.Time) (int64, error) {
+	result := r.db.Where("changed_at < ?", before).Delete(&models.TaskStatusChange{})
+	return result.RowsAffected, result.Error
+}
+
+// ArchiveResolvedBefore soft-deletes resolved and closed tasks resolved before the given time
+func (r *TaskRepository) ArchiveResolvedBefore(before time.Time) (int64, error) {
+	result := r.db.Where("status IN ? AND resolved_at IS NOT NULL AND resolved_at < ?",
+		[]models.TaskStatus{models.TaskStatusResolved, models.TaskStatusClosed}, before).
+		Delete(&models.Task{})
+	return result.RowsAffected, result.Error
+}
+
+// GetExternalIssues returns the external issues mirroring a task
+func (r *TaskRepository) GetExternalIssues(taskID uint) ([]models.ExternalIssue, error) {
+	var issues []models.ExternalIssue
+	err := r.db.Where("task_id = ?", taskID).Order("target").Find(&issues).Error
+	return issues, err
+}
+
+// GetExternalIssue returns the issue mirroring a task in a sync target, or nil if it has none
+func (r *TaskRepository) GetExternalIssue(taskID uint, target string) (*models.ExternalIssue, error) {
+	var issue models.ExternalIssue
+	err := r.db.Where("task_id = ? AND target = ?", taskID, target).First(&issue).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &issue, nil
+}
+
+func (r *TaskRepository) SaveExternalIssue(issue *models.ExternalIssue) error {
+	return r.db.Save(issue).Error
+}
+
+// GetExternalComments returns the comments already mirrored for an external issue
+func (r *TaskRepository) GetExternalComments(externalIssueID uint) ([]models.ExternalComment, error) {
+	var comments []models.ExternalComment
+	err := r.db.Where("external_issue_id = ?", externalIssueID).Find(&comments).Error
+	return comments, err
+}
+
+func (r *TaskRepository) AddExternalComment(comment *models.ExternalComment) error {
+	return r.db.Create(comment).Error
+}
+
+func (r *TaskRepository) CreateMute(mute *models.NotificationMute) error {
+	return r.db.Create(mute).Error
+}
+
+// GetActiveMutes returns the mutes still in force at now, for one user or for
+// everyone when userID is 0
+func (r *TaskRepository) GetActiveMutes(userID uint, now time.Time) ([]*models.NotificationMute, error) {
+	var mutes []*models.NotificationMute
+	query := r.db.Where("until > ?", now)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	err := query.Order("until ASC").Find(&mutes).Error
+	return mutes, err
+}
+
+// DeleteMute removes one of a user's mutes, reporting whether it existed
+func (r *TaskRepository) DeleteMute(userID, id uint) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.NotificationMute{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteExpiredMutes removes the mutes that ran out at or before now
+func (r *TaskRepository) DeleteExpiredMutes(now time.Time) (int64, error) {
+	result := r.db.Where("until <= ?", now).Delete(&models.NotificationMute{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *TaskRepository) CreateOutOfOffice(ooo *models.OutOfOffice) error {
+	return r.db.Create(ooo).Error
+}
+
+// GetOutOfOffice returns the out-of-office ranges overlapping from up to to,
+// for one user or for everyone when userID is 0, earliest first
+func (r *TaskRepository) GetOutOfOffice(userID uint, from, to time.Time) ([]*models.OutOfOffice, error) {
+	var ranges []*models.OutOfOffice
+	// A range lasts until the end of its last day
+	query := r.db.Where("start_date < ? AND end_date > ?", to, from.AddDate(0, 0, -1))
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	err := query.Order("start_date ASC, id ASC").Find(&ranges).Error
+	return ranges, err
+}
+
+// DeleteOutOfOffice removes one of a user's out-of-office ranges, reporting whether it existed
+func (r *TaskRepository) DeleteOutOfOffice(userID, id uint) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.OutOfOffice{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *TaskRepository) CreateScheduledAction(action *models.ScheduledAction) error {
+	return r.db.Create(action).Error
+}
+
+// GetScheduledActions returns a task's pending scheduled actions, soonest first
+func (r *TaskRepository) GetScheduledActions(taskID uint) ([]*models.ScheduledAction, error) {
+	var actions []*models.ScheduledAction
+	err := r.db.Where("task_id = ?", taskID).Order("run_at ASC, id ASC").Find(&actions).Error
+	return actions, err
+}
+
+// GetDueScheduledActions returns the scheduled actions due at or before now, oldest first
+func (r *TaskRepository) GetDueScheduledActions(now time.Time) ([]*models.ScheduledAction, error) {
+	var actions []*models.ScheduledAction
+	err := r.db.Where("run_at <= ?", now).Order("run_at ASC, id ASC").Find(&actions).Error
+	return actions, err
+}
+
+// DeleteScheduledAction removes a task's scheduled action, reporting whether it existed
+func (r *TaskRepository) DeleteScheduledAction(taskID, id uint) (bool, error) {
+	result := r.db.Where("id = ? AND task_id = ?", id, taskID).Delete(&models.ScheduledAction{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// AssignTask saves a task's new assignee and records the assignment in the same transaction
+func (r *TaskRepository) AssignTask(task *models.Task, assignment *models.TaskAssignment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(task).Error; err != nil {
+			return err
+		}
+		return tx.Create(assignment).Error
+	})
+}
+
+// CreateTaskReview records a reviewer's decision on a task
+func (r *TaskRepository) CreateTaskReview(review *models.TaskReview) error {
+	return r.db.Create(review).Error
+}
+
+// GetTaskReviews returns the reviews of a task, oldest first
+func (r *TaskRepository) GetTaskReviews(taskID uint) ([]*models.TaskReview, error) {
+	var reviews []*models.TaskReview
+	err := r.db.Where("task_id = ?", taskID).Order("reviewed_at ASC, id ASC").Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *TaskRepository) GetTaskReviewsBetween(since, until time.Time) ([]*models.TaskReview, error) {
+	var reviews []*models.TaskReview
+	err := r.db.Scopes(betweenScope("reviewed_at", since, until)).Find(&reviews).Error
+	return reviews, err
+}
+
+// GetLastTaskAssignment returns the latest assignment made for a tag, or nil if none
+func (r *TaskRepository) GetLastTaskAssignment(tag string) (*models.TaskAssignment, error) {
+	var assignment models.TaskAssignment
+	err := r.db.Where("tag = ?", tag).Order("assigned_at DESC, id DESC").First(&assignment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// CountOpenTasksByAssignee counts the open and in-progress tasks of each of
+// the given users; users without any are left out
+func (r *TaskRepository) CountOpenTasksByAssignee(assignees []string) (map[string]int, error) {
+	var rows []struct {
+		Assignee string
+		Count    int
+	}
+	err := r.db.Model(&models.Task{}).
+		Select("assignee, COUNT(*) AS count").
+		Where("assignee IN ? AND status IN ?", assignees, []models.TaskStatus{models.TaskStatusOpen, models.TaskStatusInProgress}).
+		Group("assignee").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Assignee] = row.Count
+	}
+	return counts, nil
+}
+
+// ContextCount is a task context with how many open and in-progress tasks it has
+type ContextCount struct {
+	Context   string `json:"context"`
+	OpenTasks int    `json:"open_tasks"`
+}
+
+// GetContextCounts returns every context in use, in alphabetical order
+func (r *TaskRepository) GetContextCounts() ([]ContextCount, error) {
+	var counts []ContextCount
+	err := r.db.Model(&models.Task{}).
+		Select("context, SUM(CASE WHEN status IN ? THEN 1 ELSE 0 END) AS open_tasks",
+			[]models.TaskStatus{models.TaskStatusOpen, models.TaskStatusInProgress}).
+		Where("context <> ''").
+		Group("context").
+		Order("context").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// SavedQueryMetrics are the work-in-progress figures of a saved query
+type SavedQueryMetrics struct {
+	Open        int // open and in-progress tasks
+	InProgress  int
+	WeekMinutes int // minutes logged on matching tasks since the given time
+}
+
+// GetSavedQueryMetrics computes the metrics of several saved queries in one
+// aggregate query, with a column per query and figure. Tags are stored as a
+// JSON array, so a tag is matched as its quoted JSON string.
+func (r *TaskRepository) GetSavedQueryMetrics(queries []*models.SavedQuery, since time.Time) (map[uint]SavedQueryMetrics, error) {
+	metrics := make(map[uint]SavedQueryMetrics, len(queries))
+	if len(queries) == 0 {
+		return metrics, nil
+	}
+
+	open, inProgress := models.TaskStatusOpen, models.TaskStatusInProgress
+	var columns []string
+	var args []interface{}
+	for _, query := range queries {
+		match, matchArgs := savedQueryCondition(query)
+		columns = append(columns,
+			"COALESCE(SUM(CASE WHEN tasks.status IN (?, ?) AND "+match+" THEN 1 ELSE 0 END), 0)",
+			"COALESCE(SUM(CASE WHEN tasks.status = ? AND "+match+" THEN 1 ELSE 0 END), 0)",
+			"COALESCE(SUM(CASE WHEN "+match+" THEN COALESCE(week.minutes, 0) ELSE 0 END), 0)")
+		args = append(args, open, inProgress)
+		args = append(args, matchArgs...)
+		args = append(args, inProgress)
+		args = append(args, matchArgs...)
+		args = append(args, matchArgs...)
+	}
+	args = append(args, since)
+
+	sql := "SELECT " + strings.Join(columns, ", ") + ` FROM tasks
+		LEFT JOIN (SELECT task_id, SUM(duration) AS minutes FROM time_entries WHERE created_at >= ? GROUP BY task_id) week
+		ON week.task_id = tasks.id
+		WHERE tasks.deleted_at IS NULL`
+
+	rows, err := r.db.Raw(sql, args...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]int64, len(columns))
+	targets := make([]interface{}, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	if rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, query := range queries {
+		metrics[query.ID] = SavedQueryMetrics{
+			Open:        int(values[3*i]),
+			InProgress:  int(values[3*i+1]),
+			WeekMinutes: int(values[3*i+2]),
+		}
+	}
+	return metrics, nil
+}
+
+// savedQueryCondition returns the SQL condition matching a saved query's
+// tasks: any of the included tags, none of the excluded ones, and the tag
+// expression. A query whose expression does not parse matches nothing.
+func savedQueryCondition(query *models.SavedQuery) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+
+	tagPattern := func(tag string) string {
+		quoted, _ := json.Marshal(tag)
+		return "%" + likeEscaper.Replace(string(quoted)) + "%"
+	}
+	if len(query.IncludedTags) > 0 {
+		var included []string
+		for _, tag := range query.IncludedTags {
+			included = append(included, `tasks.tags LIKE ? ESCAPE '\'`)
+			args = append(args, tagPattern(tag))
+		}
+		conditions = append(conditions, "("+strings.Join(included, " OR ")+")")
+	}
+	for _, tag := range query.ExcludedTags {
+		conditions = append(conditions, `(tasks.tags IS NULL OR tasks.tags NOT LIKE ? ESCAPE '\')`)
+		args = append(args, tagPattern(tag))
+	}
+	if query.ProjectID != nil {
+		conditions = append(conditions, "tasks.project_id = ?")
+		args = append(args, *query.ProjectID)
+	}
+	if expr, err := tagexpr.Parse(query.Expression); err != nil {
+		conditions = append(conditions, "1 = 0")
+	} else {
+		condition, exprArgs := expr.Condition(func(tag string) (string, []interface{}) {
+			return `COALESCE(tasks.tags, '') LIKE ? ESCAPE '\'`, []interface{}{tagPattern(tag)}
+		})
+		conditions = append(conditions, "("+condition+")")
+		args = append(args, exprArgs...)
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")", args
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -364,3 +365,86 @@ func TestTaskRepository_GetByEmailMessageID(t *testing.T) {
 		t.Error("Expected error for non-existent message ID, but got none")
 	}
 }
+
+func TestTaskRepository_SaveEmailMessage(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTaskRepository(db)
+
+	task := &models.Task{Name: "Email task", Status: models.TaskStatusOpen}
+	if err := repo.Create(task); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	received := time.Now().Add(-time.Hour)
+	first := &models.EmailMessage{MessageID: "<a@example.com>", TaskID: &task.ID, Subject: "Help", From: "a@example.com", ReceivedAt: received}
+	reply := &models.EmailMessage{MessageID: "<b@example.com>", TaskID: &task.ID, Direction: models.EmailDirectionOutbound, Subject: "Re: Help", From: "support@example.com", ReceivedAt: time.Now()}
+	for _, message := range []*models.EmailMessage{reply, first} {
+		if err := repo.SaveEmailMessage(message); err != nil {
+			t.Fatalf("Failed to save email message: %v", err)
+		}
+	}
+
+	// Re-reading the same message must not fail or duplicate it
+	again := &models.EmailMessage{MessageID: "<a@example.com>", TaskID: &task.ID, Subject: "Help", From: "a@example.com", ReceivedAt: received}
+	if err := repo.SaveEmailMessage(again); err != nil {
+		t.Fatalf("Expected saving a known message to be a no-op, got %v", err)
+	}
+
+	messages, err := repo.GetTaskEmailMessages(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get email messages: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 email messages, got %d", len(messages))
+	}
+	if messages[0].MessageID != "<a@example.com>" || messages[0].Direction != models.EmailDirectionInbound {
+		t.Errorf("Expected the inbound message first, got %+v", messages[0])
+	}
+	if messages[1].Direction != models.EmailDirectionOutbound {
+		t.Errorf("Expected the reply to be outbound, got %s", messages[1].Direction)
+	}
+}
+
+func TestTaskRepository_EnsureFeedTokens(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTaskRepository(db)
+
+	// Saved queries as they were before feed tokens were unique and required
+	type oldSavedQuery struct {
+		ID        uint   `gorm:"primaryKey"`
+		Name      string `gorm:"not null"`
+		FeedToken string `gorm:"index"`
+	}
+	if err := db.Table("saved_queries").AutoMigrate(&oldSavedQuery{}); err != nil {
+		t.Fatalf("Failed to create old saved queries: %v", err)
+	}
+	db.Table("saved_queries").Create(&oldSavedQuery{Name: "Backend"})
+	db.Table("saved_queries").Create(&oldSavedQuery{Name: "Frontend"})
+	db.Table("saved_queries").Create(&oldSavedQuery{Name: "Ops", FeedToken: "kept"})
+
+	generated := 0
+	err := repo.EnsureFeedTokens(func() (string, error) {
+		generated++
+		return fmt.Sprintf("token-%d", generated), nil
+	})
+	if err != nil {
+		t.Fatalf("EnsureFeedTokens failed: %v", err)
+	}
+	if err := db.AutoMigrate(&models.SavedQuery{}); err != nil {
+		t.Fatalf("Failed to migrate saved queries after the backfill: %v", err)
+	}
+
+	var queries []models.SavedQuery
+	db.Order("id").Find(&queries)
+	if len(queries) != 3 || queries[0].FeedToken != "token-1" || queries[1].FeedToken != "token-2" || queries[2].FeedToken != "kept" {
+		t.Errorf("Expected the missing tokens filled and the others kept, got %+v", queries)
+	}
+	if err := db.Create(&models.SavedQuery{Name: "Copy", FeedToken: "kept"}).Error; err == nil {
+		t.Error("Expected a duplicate feed token to be rejected")
+	}
+
+	// Nothing is left to do the second time
+	if err := repo.EnsureFeedTokens(func() (string, error) { return "", fmt.Errorf("unexpected") }); err != nil {
+		t.Errorf("Expected no tokens generated on a second run, got %v", err)
+	}
+}
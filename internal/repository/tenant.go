@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/soarinferret/jats/internal/models"
+	"gorm.io/gorm"
+)
+
+// TenantRepository handles the tenant registry of a multi-tenant instance
+type TenantRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantRepository creates a new tenant repository
+func NewTenantRepository(db *gorm.DB) *TenantRepository {
+	return &TenantRepository{db: db}
+}
+
+// Create stores a new tenant
+func (r *TenantRepository) Create(tenant *models.Tenant) error {
+	if err := r.db.Create(tenant).Error; err != nil {
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+	return nil
+}
+
+// List retrieves all tenants ordered by slug
+func (r *TenantRepository) List() ([]*models.Tenant, error) {
+	var tenants []*models.Tenant
+	if err := r.db.Order("slug ASC").Find(&tenants).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	return tenants, nil
+}
+
+// GetBySlug retrieves a tenant by slug, or nil if none exists
+func (r *TenantRepository) GetBySlug(slug string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	err := r.db.Where("slug = ?", slug).First(&tenant).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	return &tenant, nil
+}
+
+// Delete removes a tenant from the registry by ID
+func (r *TenantRepository) Delete(id uint) error {
+	if err := r.db.Delete(&models.Tenant{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/soarinferret/jats/internal/api"
+	"github.com/soarinferret/jats/internal/openapi"
+)
+
+// publicOperations need no API key or session
+var publicOperations = []string{
+	"POST /api/v1/auth/register",
+	"POST /api/v1/auth/login",
+	"POST /api/v1/auth/logout",
+	"GET /api/v1/branding",
+	"GET /api/v1/feeds/saved-queries/{token}", // authenticated by the feed token
+	"POST /api/v1/inbound/{channel}",          // authenticated by the channel secret
+	"GET /api/v1/openapi.json",
+	"GET /api/v1/docs",
+}
+
+// multipartOperations take file uploads instead of JSON
+var multipartOperations = []string{
+	"POST /api/v1/tasks/{id}/attachments",
+	"POST /api/v1/capture/mobile",
+	"POST /api/v1/admin/email/simulate",
+}
+
+// buildOpenAPISpec describes the /api/v1 routes, documented by the doc
+// comments of their handlers
+func buildOpenAPISpec(routes gin.RoutesInfo) *openapi.Document {
+	var apiRoutes []openapi.Route
+	for _, route := range routes {
+		if strings.HasPrefix(route.Path, "/api/v1/") {
+			apiRoutes = append(apiRoutes, openapi.Route{Method: route.Method, Path: route.Path})
+		}
+	}
+
+	return openapi.Build(apiRoutes, api.HandlerDocs, openapi.Options{
+		Title:       "JATS API",
+		Description: "Tasks, time tracking and reporting. Authenticate with an API key in the X-API-Key header or as a bearer token.",
+		Version:     "v1",
+		Public:      publicOperations,
+		Multipart:   multipartOperations,
+	})
+}
@@ -3,55 +3,130 @@ package routes
 import (
 	"net/http"
 	"path/filepath"
+	"sync"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/soarinferret/jats/internal/api"
 	"github.com/soarinferret/jats/internal/frontend"
 	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/openapi"
 	"github.com/soarinferret/jats/internal/repository"
 	"github.com/soarinferret/jats/internal/services"
 )
 
-func SetupRoutes(taskService *services.TaskService, authService *services.AuthService, authRepo *repository.AuthRepository, reportService *services.ReportService) http.Handler {
+// Services are what the routes are wired to. EmailService, InboundService,
+// SyncService, PluginService and AccessLog are optional: without them their endpoints report
+// not found and requests are not logged.
+type Services struct {
+	TaskService      *services.TaskService
+	AuthService      *services.AuthService
+	AuthRepo         *repository.AuthRepository
+	ReportService    *services.ReportService
+	SettingsService  *services.SettingsService
+	ContactService   *services.ContactService
+	SpamService      *services.SpamService
+	EmailService     *services.EmailService
+	InboundService   *services.InboundService
+	RetentionService *services.RetentionService
+	PrivacyService   *services.PrivacyService
+	SyncService      *services.SyncService
+	// Mobile quick capture; defaults to one without configured places
+	CaptureService *services.CaptureService
+	AccessLog        *middleware.AccessLogger
+	// Where attachments are stored; defaults to ./attachments
+	AttachmentsDir string
+	// Stores uploaded attachments; defaults to plain storage in AttachmentsDir
+	StorageService *services.StorageService
+	// Tenant provisioning, only on the main instance of a multi-tenant jatsd
+	TenantService *services.TenantService
+	// Script plugins serving routes under /api/v1/plugins
+	PluginService *services.PluginService
+}
+
+func SetupRoutes(deps Services) http.Handler {
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
+	// The client address is resolved by middleware.RealClient, so gin must not
+	// trust forwarding headers itself
+	router.SetTrustedProxies(nil)
+	// Reject unknown fields in JSON bound by gin handlers, as ParseJSON does
+	binding.EnableDecoderDisallowUnknownFields = true
 
 	// Add Gin middleware
 	router.Use(gin.Recovery())
-	router.Use(func(c *gin.Context) {
-		// Add CORS headers
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-
-		c.Next()
-	})
+	if deps.AccessLog != nil {
+		router.Use(deps.AccessLog.Middleware())
+	}
 
 	// Initialize middleware
-	authMiddleware := middleware.NewGinAuthMiddleware(authService)
+	authMiddleware := middleware.NewGinAuthMiddleware(deps.AuthService)
 
 	// Initialize API handlers
-	taskHandlers := api.NewTaskHandlers(taskService)
-	timeHandlers := api.NewTimeHandlers(taskService)
-	commentHandlers := api.NewCommentHandlers(taskService)
-	subtaskHandlers := api.NewSubtaskHandlers(taskService)
-	tagHandlers := api.NewTagHandlers(taskService)
-	searchHandlers := api.NewSearchHandlers(taskService)
-	savedQueryHandlers := api.NewSavedQueryHandlers(taskService)
-	summaryHandlers := api.NewSummaryHandlers(taskService)
-	reportHandlers := api.NewReportHandlers(reportService)
-	authHandlers := api.NewAuthHandlers(authService)
-	ginAdminHandlers := api.NewGinAdminHandlers(authService, authRepo)
+	taskHandlers := api.NewTaskHandlers(deps.TaskService)
+	timeHandlers := api.NewTimeHandlers(deps.TaskService)
+	commentHandlers := api.NewCommentHandlers(deps.TaskService, deps.SettingsService)
+	subtaskHandlers := api.NewSubtaskHandlers(deps.TaskService)
+	tagHandlers := api.NewTagHandlers(deps.TaskService)
+	searchHandlers := api.NewSearchHandlers(deps.TaskService)
+	graphQLHandlers := api.NewGraphQLHandlers(deps.TaskService)
+	savedQueryHandlers := api.NewSavedQueryHandlers(deps.TaskService)
+	summaryHandlers := api.NewSummaryHandlers(deps.TaskService)
+	feedHandlers := api.NewFeedHandlers(deps.TaskService)
+	attachmentsDir := deps.AttachmentsDir
+	if attachmentsDir == "" {
+		attachmentsDir = "./attachments"
+	}
+	storageService := deps.StorageService
+	if storageService == nil {
+		storageService = services.NewStorageService(attachmentsDir)
+	}
+	attachmentHandlers := api.NewAttachmentHandlers(deps.TaskService, storageService, attachmentsDir)
+	settingsHandlers := api.NewSettingsHandlers(deps.SettingsService)
+	contactHandlers := api.NewContactHandlers(deps.ContactService)
+	milestoneHandlers := api.NewMilestoneHandlers(deps.TaskService)
+	projectHandlers := api.NewProjectHandlers(deps.TaskService)
+	assetHandlers := api.NewAssetHandlers(deps.TaskService)
+	quarantineHandlers := api.NewQuarantineHandlers(deps.SpamService)
+	emailHandlers := api.NewEmailHandlers(deps.EmailService)
+	captureService := deps.CaptureService
+	if captureService == nil {
+		captureService = services.NewCaptureService(deps.TaskService, storageService, nil)
+	}
+	captureHandlers := api.NewCaptureHandlers(captureService)
+	inboundHandlers := api.NewInboundHandlers(deps.InboundService)
+	retentionHandlers := api.NewRetentionHandlers(deps.RetentionService)
+	syncHandlers := api.NewSyncHandlers(deps.SyncService)
+	pluginHandlers := api.NewPluginHandlers(deps.PluginService)
+	debugHandlers := api.NewDebugHandlers()
+	reportHandlers := api.NewReportHandlers(deps.ReportService)
+	dateHandlers := api.NewDateHandlers()
+	activityHandlers := api.NewActivityHandlers(deps.TaskService)
+	eventHandlers := api.NewEventHandlers(deps.TaskService)
+	capacityHandlers := api.NewCapacityHandlers(deps.TaskService)
+	velocityHandlers := api.NewVelocityHandlers(deps.TaskService)
+	muteHandlers := api.NewMuteHandlers(deps.TaskService)
+	oooHandlers := api.NewOutOfOfficeHandlers(deps.TaskService)
+	scheduledActionHandlers := api.NewScheduledActionHandlers(deps.TaskService)
+	dashboardHandlers := api.NewDashboardHandlers(deps.TaskService, deps.AuthService)
+	authHandlers := api.NewAuthHandlers(deps.AuthService, deps.TaskService)
+	ginAdminHandlers := api.NewGinAdminHandlers(deps.AuthService, deps.AuthRepo)
+	tenantHandlers := api.NewTenantHandlers(deps.TenantService)
+	privacyHandlers := api.NewPrivacyHandlers(deps.PrivacyService)
+	// The OpenAPI spec describes the routes, so it is built on first request,
+	// once they are all registered
+	var specOnce sync.Once
+	var spec *openapi.Document
+	openAPIHandlers := api.NewOpenAPIHandlers(func() *openapi.Document {
+		specOnce.Do(func() { spec = buildOpenAPISpec(router.Routes()) })
+		return spec
+	})
 
 	// Initialize frontend handlers
-	frontendHandler := frontend.NewHandler(authService, taskService)
+	frontendHandler := frontend.NewHandler(deps.AuthService, deps.TaskService, deps.SettingsService, deps.ContactService, deps.SpamService, deps.RetentionService)
+	frontendHandler.Attachments = frontend.NewAttachmentHandler(deps.TaskService, attachmentsDir)
 
 	// Load frontend templates (skip in tests)
 	templatesDir := filepath.Join("frontend", "templates")
@@ -62,36 +137,85 @@ func SetupRoutes(taskService *services.TaskService, authService *services.AuthSe
 
 	// Frontend routes (public)
 	router.GET("/login", frontendHandler.Auth.LoginPageHandler)
-	router.POST("/login", frontendHandler.Auth.LoginHandler)
-	router.POST("/logout", frontendHandler.Auth.LogoutHandler)
+	router.POST("/login", middleware.MaxBodySize(middleware.FormBodyLimit), frontendHandler.Auth.LoginHandler)
+	router.POST("/logout", authMiddleware.RequireCSRF(), frontendHandler.Auth.LogoutHandler)
 
 	// Frontend routes (protected)
 	router.GET("/", authMiddleware.RequireAuth(), frontendHandler.App.AppHandler)
+	// Task short links check the session themselves, sending visitors who are
+	// not signed in to the login page and back
+	router.GET("/s/:code", frontendHandler.App.ShortLinkHandler)
+	// Email update tracking images are loaded by the recipient's mail client
+	router.GET("/r/:token", frontendHandler.App.ReceiptPixelHandler)
 
 	// App routes (protected)
-	appRoutes := router.Group("/app", authMiddleware.RequireAuth())
+	appRoutes := router.Group("/app", middleware.MaxBodySize(middleware.FormBodyLimit), authMiddleware.RequireAuth(), authMiddleware.RequireCSRF())
 	{
 		appRoutes.GET("/tasks", frontendHandler.Tasks.TaskListHandler)
+		appRoutes.GET("/tasks/overdue", frontendHandler.OverdueTasksHandler)
 		appRoutes.GET("/tasks/new", frontendHandler.Tasks.NewTaskFormHandler)
 		appRoutes.POST("/tasks", frontendHandler.Tasks.CreateTaskHandler)
+		appRoutes.POST("/tasks/quick", frontendHandler.Tasks.QuickAddTaskHandler)
 		appRoutes.GET("/tasks/:id/edit", frontendHandler.Tasks.EditTaskFormHandler)
 		appRoutes.PUT("/tasks/:id", frontendHandler.Tasks.UpdateTaskHandler)
 		appRoutes.POST("/tasks/:id/toggle-complete", frontendHandler.Tasks.TaskToggleCompleteHandler)
+		appRoutes.GET("/tasks/:id/card", frontendHandler.Tasks.TaskCardHandler)
 		appRoutes.GET("/tasks/:id/detail", frontendHandler.Tasks.TaskDetailHandler)
 		appRoutes.GET("/tasks/:id/subtasks", frontendHandler.Tasks.TaskSubtasksHandler)
+		appRoutes.GET("/tasks/:id/print", frontendHandler.Tasks.TaskPrintHandler)
+		appRoutes.GET("/tasks/:id/print/pdf", frontendHandler.Tasks.TaskPrintPDFHandler)
+		appRoutes.POST("/tasks/:id/review", frontendHandler.Tasks.ReviewTaskHandler)
+		appRoutes.POST("/tasks/:id/mute", frontendHandler.Tasks.MuteTaskHandler)
+		appRoutes.DELETE("/tasks/:id/mute", frontendHandler.Tasks.UnmuteTaskHandler)
+		appRoutes.POST("/tasks/:id/scheduled", frontendHandler.Tasks.ScheduleActionHandler)
+		appRoutes.DELETE("/tasks/:id/scheduled/:actionId", frontendHandler.Tasks.CancelScheduledActionHandler)
 
 		// Saved queries frontend routes
 		appRoutes.GET("/saved-queries", frontendHandler.Saved.SavedQueriesListHandler)
 		appRoutes.GET("/saved-queries/new", frontendHandler.Saved.NewSavedQueryFormHandler)
 		appRoutes.POST("/saved-queries", frontendHandler.Saved.CreateSavedQueryHandler)
 		appRoutes.GET("/saved-queries/:id/tasks", frontendHandler.SavedQueryTasksHandler)
+		appRoutes.POST("/saved-queries/:id/pin", frontendHandler.Saved.PinSavedQueryHandler)
 
 		// Report routes
 		appRoutes.GET("/reports", frontendHandler.Reports.ReportPageHandler)
 
+		// Configurable dashboard
+		appRoutes.GET("/dashboard", frontendHandler.Dashboard.DashboardPageHandler)
+		appRoutes.POST("/dashboard/widgets", frontendHandler.Dashboard.AddWidgetHandler)
+		appRoutes.POST("/dashboard/widgets/:index/move", frontendHandler.Dashboard.MoveWidgetHandler)
+		appRoutes.DELETE("/dashboard/widgets/:index", frontendHandler.Dashboard.RemoveWidgetHandler)
+		appRoutes.POST("/dashboard/reset", frontendHandler.Dashboard.ResetDashboardHandler)
+
+		// Kanban board
+		appRoutes.GET("/kanban", frontendHandler.Kanban.KanbanPageHandler)
+		appRoutes.GET("/kanban/ws", frontendHandler.Kanban.KanbanSocketHandler)
+
+		// Activity feed, timesheet and team availability
+		appRoutes.GET("/activity", frontendHandler.Activity.ActivityPageHandler)
+		appRoutes.GET("/timesheet", frontendHandler.Timesheet.TimesheetPageHandler)
+		appRoutes.GET("/team", frontendHandler.Team.TeamPageHandler)
+		appRoutes.POST("/team/out-of-office", frontendHandler.Team.CreateOutOfOfficeHandler)
+		appRoutes.DELETE("/team/out-of-office/:id", frontendHandler.Team.DeleteOutOfOfficeHandler)
+
+		// Contact directory
+		appRoutes.GET("/contacts", frontendHandler.Contacts.ContactsPageHandler)
+		appRoutes.GET("/contacts/:id", frontendHandler.Contacts.ContactDetailHandler)
+
+		// Asset inventory
+		appRoutes.GET("/assets", frontendHandler.Assets.AssetsPageHandler)
+		appRoutes.POST("/assets", frontendHandler.Assets.CreateAssetHandler)
+		appRoutes.GET("/assets/:id", frontendHandler.Assets.AssetDetailHandler)
+		appRoutes.PUT("/assets/:id", frontendHandler.Assets.UpdateAssetHandler)
+		appRoutes.DELETE("/assets/:id", frontendHandler.Assets.DeleteAssetHandler)
+		appRoutes.POST("/assets/:id/tasks", frontendHandler.Assets.LinkAssetTaskHandler)
+		appRoutes.DELETE("/assets/:id/tasks/:taskId", frontendHandler.Assets.UnlinkAssetTaskHandler)
+
 		// Comment routes
 		appRoutes.POST("/tasks/:id/comments", frontendHandler.Tasks.AddTaskCommentHandler)
+		appRoutes.POST("/tasks/:id/email-update", frontendHandler.Tasks.SendEmailUpdateHandler)
 		appRoutes.GET("/tasks/:id/timeline", frontendHandler.Tasks.TaskTimelineHandler)
+		appRoutes.GET("/tasks/:id/emails", frontendHandler.Tasks.TaskEmailsHandler)
 		
 		// Time entry routes
 		appRoutes.POST("/tasks/:id/time", frontendHandler.Tasks.AddTimeEntryHandler)
@@ -103,10 +227,37 @@ func SetupRoutes(taskService *services.TaskService, authService *services.AuthSe
 
 		// Attachment routes
 		appRoutes.GET("/attachments/:id", frontendHandler.Attachments.ServeAttachment)
+
+		// Admin routes
+		appRoutes.GET("/admin", frontendHandler.Admin.AdminPageHandler)
+		appRoutes.POST("/admin/branding", frontendHandler.Admin.UpdateBrandingHandler)
+		appRoutes.POST("/admin/canned-responses", frontendHandler.Admin.CreateCannedResponseHandler)
+		appRoutes.DELETE("/admin/canned-responses/:id", frontendHandler.Admin.DeleteCannedResponseHandler)
+		appRoutes.POST("/admin/rules", frontendHandler.Admin.CreateAutomationRuleHandler)
+		appRoutes.POST("/admin/rules/:id/toggle", frontendHandler.Admin.ToggleAutomationRuleHandler)
+		appRoutes.DELETE("/admin/rules/:id", frontendHandler.Admin.DeleteAutomationRuleHandler)
+		appRoutes.POST("/admin/query-webhooks", frontendHandler.Admin.CreateQueryWebhookHandler)
+		appRoutes.POST("/admin/query-webhooks/:id/toggle", frontendHandler.Admin.ToggleQueryWebhookHandler)
+		appRoutes.DELETE("/admin/query-webhooks/:id", frontendHandler.Admin.DeleteQueryWebhookHandler)
+		appRoutes.POST("/admin/quarantine/:id/release", frontendHandler.Admin.ReleaseQuarantinedHandler)
+		appRoutes.DELETE("/admin/quarantine/:id", frontendHandler.Admin.DeleteQuarantinedHandler)
+		appRoutes.POST("/admin/retention/run", frontendHandler.Admin.RunRetentionHandler)
+	}
+
+	// CSV import posts the whole file with each preview, so it gets the bulk
+	// body limit rather than the form limit
+	importRoutes := router.Group("/app/import", middleware.MaxBodySize(middleware.BulkBodyLimit), authMiddleware.RequireAuth(), authMiddleware.RequireCSRF())
+	{
+		importRoutes.GET("", frontendHandler.Import.ImportPageHandler)
+		importRoutes.POST("", frontendHandler.Import.ImportTasksHandler)
+		importRoutes.POST("/preview", frontendHandler.Import.ImportPreviewHandler)
+		importRoutes.POST("/errors", frontendHandler.Import.ImportErrorsHandler)
 	}
 
 	// API routes
-	api := router.Group("/api/v1")
+	// Browsers send the session cookie to the API too, so it needs the CSRF
+	// token like the frontend; requests without the cookie pass without one
+	api := router.Group("/api/v1", middleware.MaxBodySize(middleware.JSONBodyLimit), authMiddleware.RequireCSRF())
 	{
 		// Public authentication endpoints
 		auth := api.Group("/auth")
@@ -120,6 +271,7 @@ func SetupRoutes(taskService *services.TaskService, authService *services.AuthSe
 		authProtected := api.Group("/auth", authMiddleware.RequireAuth())
 		{
 			authProtected.GET("/profile", gin.WrapF(authHandlers.GetProfile))
+			authProtected.PATCH("/profile", gin.WrapF(authHandlers.UpdateProfile))
 			authProtected.POST("/totp/setup", gin.WrapF(authHandlers.SetupTOTP))
 			authProtected.POST("/totp/enable", gin.WrapF(authHandlers.EnableTOTP))
 			authProtected.DELETE("/totp/disable", gin.WrapF(authHandlers.DisableTOTP))
@@ -135,10 +287,16 @@ func SetupRoutes(taskService *services.TaskService, authService *services.AuthSe
 		{
 			tasks.GET("", gin.WrapF(taskHandlers.GetTasks))
 			tasks.POST("", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(taskHandlers.CreateTask))
+			tasks.POST("/quick", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(taskHandlers.QuickAddTask))
 			tasks.GET("/:id", gin.WrapF(taskHandlers.GetTask))
 			tasks.PUT("/:id", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(taskHandlers.UpdateTask))
 			tasks.PATCH("/:id", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(taskHandlers.PartialUpdateTask))
 			tasks.DELETE("/:id", authMiddleware.RequirePermission(models.PermissionDeleteTasks), gin.WrapF(taskHandlers.DeleteTask))
+			tasks.GET("/:id/status-history", gin.WrapF(taskHandlers.GetStatusHistory))
+			tasks.GET("/:id/reviews", gin.WrapF(taskHandlers.GetTaskReviews))
+			tasks.POST("/:id/review", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(taskHandlers.ReviewTask))
+			tasks.GET("/:id/job-sheet", gin.WrapF(taskHandlers.GetJobSheet))
+			tasks.GET("/:id/short-link", gin.WrapF(taskHandlers.GetShortLink))
 
 			// Time tracking endpoints
 			tasks.GET("/:id/time", authMiddleware.RequirePermission(models.PermissionReadTime), gin.WrapF(timeHandlers.GetTimeEntries))
@@ -151,6 +309,22 @@ func SetupRoutes(taskService *services.TaskService, authService *services.AuthSe
 			tasks.POST("/:id/comments", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(commentHandlers.CreateComment))
 			tasks.PUT("/:id/comments/:commentId", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(commentHandlers.UpdateComment))
 			tasks.DELETE("/:id/comments/:commentId", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(commentHandlers.DeleteComment))
+			tasks.GET("/:id/scheduled", gin.WrapF(scheduledActionHandlers.GetScheduledActions))
+			tasks.POST("/:id/scheduled", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(scheduledActionHandlers.CreateScheduledAction))
+			tasks.DELETE("/:id/scheduled/:actionId", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(scheduledActionHandlers.DeleteScheduledAction))
+			tasks.GET("/:id/emails", authMiddleware.RequirePermission(models.PermissionReadEmails), gin.WrapF(commentHandlers.GetTaskEmails))
+			tasks.POST("/:id/email-update", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(commentHandlers.SendEmailUpdate))
+			tasks.POST("/:id/email-update/preview", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(commentHandlers.PreviewEmailUpdate))
+
+			// Asset endpoints
+			tasks.GET("/:id/assets", gin.WrapF(assetHandlers.GetTaskAssets))
+			tasks.POST("/:id/assets", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(assetHandlers.LinkTaskAsset))
+			tasks.DELETE("/:id/assets/:assetId", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(assetHandlers.UnlinkTaskAsset))
+
+			// Dependency endpoints
+			tasks.GET("/:id/dependencies", gin.WrapF(taskHandlers.GetDependencies))
+			tasks.POST("/:id/dependencies", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(taskHandlers.AddDependency))
+			tasks.DELETE("/:id/dependencies/:otherId", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(taskHandlers.RemoveDependency))
 
 			// Subtask endpoints
 			tasks.GET("/:id/subtasks", gin.WrapF(subtaskHandlers.GetSubtasks))
@@ -162,6 +336,9 @@ func SetupRoutes(taskService *services.TaskService, authService *services.AuthSe
 			// Tag endpoints for specific tasks
 			tasks.POST("/:id/tags", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(tagHandlers.AddTaskTags))
 			tasks.DELETE("/:id/tags/:tag", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(tagHandlers.RemoveTaskTag))
+
+			// Attachment endpoints
+			tasks.GET("/:id/attachments", gin.WrapF(attachmentHandlers.GetTaskAttachments))
 		}
 
 		// Saved query endpoints
@@ -169,17 +346,88 @@ func SetupRoutes(taskService *services.TaskService, authService *services.AuthSe
 		{
 			savedQueries.GET("", gin.WrapF(savedQueryHandlers.GetSavedQueries))
 			savedQueries.POST("", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(savedQueryHandlers.CreateSavedQuery))
+			savedQueries.PUT("/order", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(savedQueryHandlers.ReorderSavedQueries))
 			savedQueries.GET("/:id", gin.WrapF(savedQueryHandlers.GetSavedQuery))
 			savedQueries.PUT("/:id", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(savedQueryHandlers.UpdateSavedQuery))
 			savedQueries.DELETE("/:id", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(savedQueryHandlers.DeleteSavedQuery))
 			savedQueries.GET("/:id/tasks", gin.WrapF(savedQueryHandlers.GetTasksBySavedQuery))
+			savedQueries.POST("/:id/feed-token", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(savedQueryHandlers.RegenerateFeedToken))
+			savedQueries.GET("/:id/schedule", gin.WrapF(savedQueryHandlers.GetSchedule))
+			savedQueries.PUT("/:id/schedule", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(savedQueryHandlers.PutSchedule))
+			savedQueries.DELETE("/:id/schedule", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(savedQueryHandlers.DeleteSchedule))
 		}
 
+		// Contact endpoints
+		contacts := api.Group("/contacts", authMiddleware.RequirePermission(models.PermissionReadTasks))
+		{
+			contacts.GET("", gin.WrapF(contactHandlers.GetContacts))
+			contacts.GET("/:id", gin.WrapF(contactHandlers.GetContact))
+		}
+
+		// Asset endpoints
+		assets := api.Group("/assets", authMiddleware.RequirePermission(models.PermissionReadTasks))
+		{
+			assets.GET("", gin.WrapF(assetHandlers.GetAssets))
+			assets.POST("", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(assetHandlers.CreateAsset))
+			assets.GET("/:id", gin.WrapF(assetHandlers.GetAsset))
+			assets.PUT("/:id", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(assetHandlers.UpdateAsset))
+			assets.DELETE("/:id", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(assetHandlers.DeleteAsset))
+		}
+
+		// Project endpoints
+		projects := api.Group("/projects", authMiddleware.RequirePermission(models.PermissionReadTasks))
+		{
+			projects.GET("", gin.WrapF(projectHandlers.GetProjects))
+			projects.POST("", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(projectHandlers.CreateProject))
+			projects.GET("/:id", gin.WrapF(projectHandlers.GetProject))
+			projects.PUT("/:id", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(projectHandlers.UpdateProject))
+			projects.DELETE("/:id", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(projectHandlers.DeleteProject))
+		}
+
+		// Milestone endpoints
+		milestones := api.Group("/milestones", authMiddleware.RequirePermission(models.PermissionReadTasks))
+		{
+			milestones.GET("", gin.WrapF(milestoneHandlers.GetMilestones))
+			milestones.POST("", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(milestoneHandlers.CreateMilestone))
+			milestones.GET("/:id", gin.WrapF(milestoneHandlers.GetMilestone))
+			milestones.PUT("/:id", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(milestoneHandlers.UpdateMilestone))
+			milestones.DELETE("/:id", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(milestoneHandlers.DeleteMilestone))
+			milestones.GET("/:id/tasks", gin.WrapF(milestoneHandlers.GetMilestoneTasks))
+			milestones.GET("/:id/progress", gin.WrapF(milestoneHandlers.GetMilestoneProgress))
+			milestones.GET("/:id/burndown", gin.WrapF(milestoneHandlers.GetMilestoneBurndown))
+		}
+
+		// Attachment downloads
+		api.GET("/attachments/:id/download", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(attachmentHandlers.DownloadAttachment))
+
+		// Canned response endpoints (managed under /admin)
+		api.GET("/canned-responses", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(settingsHandlers.GetCannedResponses))
+
+		// Public branding endpoint (used by the login page and clients)
+		api.GET("/branding", gin.WrapF(settingsHandlers.GetBranding))
+
+		// Public feed endpoints (authenticated by the per-query feed token)
+		api.GET("/feeds/saved-queries/:token", gin.WrapF(feedHandlers.GetSavedQueryFeed))
+
+		// Monitoring webhooks, authenticated by the channel's secret
+		api.POST("/inbound/:channel", gin.WrapF(inboundHandlers.Receive))
+
 		// General endpoints
 		api.GET("/time", authMiddleware.RequirePermission(models.PermissionReadTime), gin.WrapF(timeHandlers.GetAllTimeEntries))
+		api.GET("/time/timesheet", authMiddleware.RequirePermission(models.PermissionReadTime), gin.WrapF(timeHandlers.GetTimesheet))
+		api.PUT("/time/timesheet", authMiddleware.RequirePermission(models.PermissionWriteTime), gin.WrapF(timeHandlers.UpdateTimesheet))
 		api.GET("/tags", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(tagHandlers.GetTags))
+		api.GET("/tags/stats", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(tagHandlers.GetTagStats))
 		api.GET("/tags/:tag/tasks", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(tagHandlers.GetTasksByTag))
+		api.POST("/tags/:tag/apply", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(tagHandlers.ApplyTag))
+		api.POST("/tags/:tag/remove", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(tagHandlers.RemoveTag))
+		api.GET("/contexts", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(taskHandlers.GetContexts))
+		api.GET("/activity", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(activityHandlers.GetActivity))
+		api.GET("/events", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(eventHandlers.StreamEvents))
+		api.GET("/dates/parse", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(dateHandlers.ParseDate))
 		api.GET("/search", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(searchHandlers.Search))
+		api.POST("/graphql", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(graphQLHandlers.Query))
+		api.GET("/graphql/schema", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(graphQLHandlers.GetSchema))
 		api.GET("/kanban", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(searchHandlers.GetKanban))
 		api.GET("/kanban/:tag", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(searchHandlers.GetKanbanByTag))
 
@@ -190,8 +438,43 @@ func SetupRoutes(taskService *services.TaskService, authService *services.AuthSe
 		reports := api.Group("/reports", authMiddleware.RequirePermission(models.PermissionReadTasks))
 		{
 			reports.GET("/time-breakdown", gin.WrapF(reportHandlers.GetTimeBreakdownReport))
+			reports.GET("/standup", gin.WrapF(reportHandlers.GetStandupReport))
+			reports.GET("/capacity", gin.WrapF(capacityHandlers.GetCapacityPlan))
+			reports.GET("/velocity", gin.WrapF(velocityHandlers.GetVelocity))
+			reports.GET("/sizes", gin.WrapF(velocityHandlers.GetSizeBreakdown))
+			reports.GET("/dashboard", gin.WrapF(dashboardHandlers.GetDashboard))
+		}
+
+		// Per-user dashboard layout
+		dashboard := api.Group("/dashboard", authMiddleware.RequirePermission(models.PermissionReadTasks))
+		{
+			dashboard.GET("/layout", gin.WrapF(dashboardHandlers.GetLayout))
+			dashboard.PUT("/layout", gin.WrapF(dashboardHandlers.UpdateLayout))
+			dashboard.DELETE("/layout", gin.WrapF(dashboardHandlers.ResetLayout))
+		}
+
+		// Per-user notification mutes
+		mutes := api.Group("/mutes", authMiddleware.RequirePermission(models.PermissionReadTasks))
+		{
+			mutes.GET("", gin.WrapF(muteHandlers.GetMutes))
+			mutes.POST("", gin.WrapF(muteHandlers.CreateMute))
+			mutes.DELETE("/:id", gin.WrapF(muteHandlers.DeleteMute))
 		}
 
+		// Per-user vacation and holiday ranges, and who is away in a week
+		ooo := api.Group("/out-of-office", authMiddleware.RequirePermission(models.PermissionReadTasks))
+		{
+			ooo.GET("", gin.WrapF(oooHandlers.GetOutOfOffice))
+			ooo.GET("/team", gin.WrapF(oooHandlers.GetTeamOutOfOffice))
+			ooo.POST("", gin.WrapF(oooHandlers.CreateOutOfOffice))
+			ooo.DELETE("/:id", gin.WrapF(oooHandlers.DeleteOutOfOffice))
+		}
+
+		// Script plugin routes; plugins change tasks with the server's rights,
+		// so only a POST may change tasks, and it needs write permission
+		api.GET("/plugins/:plugin/*path", authMiddleware.RequirePermission(models.PermissionReadTasks), gin.WrapF(pluginHandlers.ServePlugin))
+		api.POST("/plugins/:plugin/*path", authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(pluginHandlers.ServePlugin))
+
 		// Admin endpoints (require admin permission)
 		admin := api.Group("/admin", authMiddleware.RequirePermission(models.PermissionAdmin))
 		{
@@ -202,8 +485,64 @@ func SetupRoutes(taskService *services.TaskService, authService *services.AuthSe
 			admin.PUT("/users/:id", ginAdminHandlers.UpdateUser)
 			admin.DELETE("/users/:id", ginAdminHandlers.DeleteUser)
 			admin.POST("/users/:id/reset-password", ginAdminHandlers.ResetUserPassword)
+			admin.GET("/users/:id/export", gin.WrapF(privacyHandlers.ExportUserData))
+			admin.POST("/users/:id/erase", gin.WrapF(privacyHandlers.EraseUser))
+
+			// Instance settings
+			admin.PUT("/settings/branding", gin.WrapF(settingsHandlers.UpdateBranding))
+			admin.POST("/canned-responses", gin.WrapF(settingsHandlers.CreateCannedResponse))
+			admin.PUT("/canned-responses/:id", gin.WrapF(settingsHandlers.UpdateCannedResponse))
+			admin.DELETE("/canned-responses/:id", gin.WrapF(settingsHandlers.DeleteCannedResponse))
+			admin.GET("/rules", gin.WrapF(settingsHandlers.GetAutomationRules))
+			admin.POST("/rules", gin.WrapF(settingsHandlers.CreateAutomationRule))
+			admin.PUT("/rules/:id", gin.WrapF(settingsHandlers.UpdateAutomationRule))
+			admin.DELETE("/rules/:id", gin.WrapF(settingsHandlers.DeleteAutomationRule))
+			admin.GET("/query-webhooks", gin.WrapF(savedQueryHandlers.GetWebhooks))
+			admin.POST("/query-webhooks", gin.WrapF(savedQueryHandlers.CreateWebhook))
+			admin.PUT("/query-webhooks/:id", gin.WrapF(savedQueryHandlers.UpdateWebhook))
+			admin.DELETE("/query-webhooks/:id", gin.WrapF(savedQueryHandlers.DeleteWebhook))
+
+			// Spam quarantine
+			admin.GET("/quarantine", gin.WrapF(quarantineHandlers.GetQuarantine))
+			admin.POST("/quarantine/:id/release", gin.WrapF(quarantineHandlers.ReleaseQuarantined))
+			admin.DELETE("/quarantine/:id", gin.WrapF(quarantineHandlers.DeleteQuarantined))
+
+			// Data retention
+			admin.GET("/retention", gin.WrapF(retentionHandlers.GetRetention))
+			admin.POST("/retention/run", gin.WrapF(retentionHandlers.RunRetention))
+
+			// External issue tracker sync
+			admin.GET("/sync", gin.WrapF(syncHandlers.GetTargets))
+			admin.POST("/sync/:target/run", gin.WrapF(syncHandlers.RunTarget))
+
+			// Loaded script plugins and their failures
+			admin.GET("/plugins", gin.WrapF(pluginHandlers.GetPlugins))
+
+			// Tenant provisioning in multi-tenant mode
+			if deps.TenantService != nil {
+				admin.GET("/tenants", gin.WrapF(tenantHandlers.GetTenants))
+				admin.POST("/tenants", gin.WrapF(tenantHandlers.CreateTenant))
+				admin.DELETE("/tenants/:slug", gin.WrapF(tenantHandlers.DeleteTenant))
+			}
+
+			// Runtime stats and pprof profiles for troubleshooting
+			admin.GET("/debug", gin.WrapF(debugHandlers.GetRuntimeStats))
+			admin.GET("/debug/pprof/:profile", gin.WrapF(debugHandlers.GetProfile))
 		}
 	}
 
+	// Bulk imports carry more than the API group's body limit allows
+	router.POST("/api/v1/tasks/bulk", middleware.MaxBodySize(middleware.BulkBodyLimit), authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(taskHandlers.CreateTasks))
+	router.POST("/api/v1/tasks/import", middleware.MaxBodySize(middleware.BulkBodyLimit), authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(taskHandlers.ImportTasks))
+	router.POST("/api/v1/time/bulk", middleware.MaxBodySize(middleware.BulkBodyLimit), authMiddleware.RequirePermission(models.PermissionWriteTime), gin.WrapF(timeHandlers.CreateTimeEntries))
+
+	// Raw messages with attachments, and uploaded files and photos, are larger than the API group allows
+	router.POST("/api/v1/capture/mobile", middleware.MaxBodySize(middleware.UploadBodyLimit), authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(captureHandlers.MobileCapture))
+	router.POST("/api/v1/tasks/:id/attachments", middleware.MaxBodySize(middleware.UploadBodyLimit), authMiddleware.RequirePermission(models.PermissionWriteTasks), gin.WrapF(attachmentHandlers.UploadAttachment))
+	router.POST("/api/v1/admin/email/simulate", middleware.MaxBodySize(middleware.UploadBodyLimit), authMiddleware.RequirePermission(models.PermissionAdmin), gin.WrapF(emailHandlers.SimulateInboundEmail))
+
+	router.GET("/api/v1/openapi.json", gin.WrapF(openAPIHandlers.GetSpec))
+	router.GET("/api/v1/docs", gin.WrapF(openAPIHandlers.GetDocs))
+
 	return router
 }
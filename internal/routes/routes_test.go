@@ -4,14 +4,25 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/soarinferret/jats/internal/api"
+	"github.com/soarinferret/jats/internal/config"
 	"github.com/soarinferret/jats/internal/middleware"
 	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/openapi"
 	"github.com/soarinferret/jats/internal/repository"
 	"github.com/soarinferret/jats/internal/services"
 	"gorm.io/driver/sqlite"
@@ -45,6 +56,29 @@ func setupTestAPI(t *testing.T) *TestData {
 		&models.Session{},
 		&models.APIKey{},
 		&models.LoginAttempt{},
+		&models.SavedQuery{},
+		&models.SavedQuerySchedule{},
+		&models.SavedQueryWebhook{},
+		&models.TaskStatusChange{},
+		&models.TaskReference{},
+		&models.Milestone{},
+		&models.BrandingSettings{},
+		&models.CannedResponse{},
+		&models.Contact{},
+		&models.QuarantinedEmail{},
+		&models.ExternalIssue{},
+		&models.ExternalComment{},
+		&models.NotificationMute{},
+		&models.OutOfOffice{},
+		&models.Asset{},
+		&models.Project{},
+		&models.TaskReview{},
+		&models.TaskDependency{},
+		&models.EmailReceipt{},
+		&models.ScheduledAction{},
+		&models.AutomationRule{},
+		&models.TaskAssignment{},
+		&models.EmailMessage{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate database: %v", err)
@@ -70,8 +104,52 @@ func setupTestAPI(t *testing.T) *TestData {
 		t.Fatalf("Failed to create API key: %v", err)
 	}
 
+	reportService := services.NewReportService(taskRepo)
+	settingsService := services.NewSettingsService(repository.NewSettingsRepository(db))
+	contactService := services.NewContactService(repository.NewContactRepository(db))
+	spamService := services.NewSpamService(nil, repository.NewQuarantineRepository(db), &config.SpamConfig{})
+	retentionService := services.NewRetentionService(taskRepo, authRepo, &config.RetentionConfig{})
+
+	emailService := services.NewEmailService(taskService, taskRepo, authRepo, services.NewStorageService(t.TempDir()), &config.Config{})
+	inboundService, err := services.NewInboundService(taskService, map[string]config.InboundChannelConfig{
+		"uptime": {
+			Secret:   "kuma-secret",
+			Name:     "{{.monitor.name}} is down",
+			Priority: "high",
+			Tags:     []string{"alert", "{{.monitor.type}}"},
+			Key:      "{{.monitor.id}}",
+			Resolve:  "{{eq .heartbeat.status 1.0}}",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create inbound service: %v", err)
+	}
+
+	pluginDir := t.TempDir()
+	echo := `if request { response = request.path == "/fail" ? {status: 409, body: "already done"} : {body: request} }`
+	if err := os.WriteFile(filepath.Join(pluginDir, "echo.tengo"), []byte(echo), 0o644); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+	pluginService, err := services.NewPluginService(taskService, pluginDir, time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create plugin service: %v", err)
+	}
+
 	// Setup routes
-	handler := SetupRoutes(taskService, authService, authRepo)
+	handler := SetupRoutes(Services{
+		TaskService:      taskService,
+		AuthService:      authService,
+		AuthRepo:         authRepo,
+		ReportService:    reportService,
+		SettingsService:  settingsService,
+		ContactService:   contactService,
+		SpamService:      spamService,
+		EmailService:     emailService,
+		InboundService:   inboundService,
+		RetentionService: retentionService,
+		AttachmentsDir:   t.TempDir(),
+		PluginService:    pluginService,
+	})
 
 	return &TestData{
 		Handler:     handler,
@@ -205,6 +283,141 @@ func TestCreateTask(t *testing.T) {
 	}
 }
 
+func TestQuickAddTask(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	reqBody, _ := json.Marshal(api.QuickAddRequest{Input: "Restart +docker container @client1 -p high -t 30m"})
+	req := httptest.NewRequest("POST", "/api/v1/tasks/quick", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(req, testData.APIKey)
+
+	w := httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var response api.APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	taskData, ok := response.Data.(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected task data to be a map")
+	}
+	if taskData["name"] != "Restart docker container" {
+		t.Errorf("Expected name %q, got %q", "Restart docker container", taskData["name"])
+	}
+	if taskData["priority"] != "high" {
+		t.Errorf("Expected priority high, got %v", taskData["priority"])
+	}
+	if taskData["logged_minutes"] != float64(30) {
+		t.Errorf("Expected 30 logged minutes, got %v", taskData["logged_minutes"])
+	}
+
+	// An unparseable line is rejected without creating a task
+	reqBody, _ = json.Marshal(api.QuickAddRequest{Input: "Broken -p critical"})
+	req = httptest.NewRequest("POST", "/api/v1/tasks/quick", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(req, testData.APIKey)
+
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d for invalid input, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestMilestoneEndpoints(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	reqBody, _ := json.Marshal(api.MilestoneRequest{Name: "v1.0", DueDate: "2030-01-31"})
+	req := httptest.NewRequest("POST", "/api/v1/milestones", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(req, testData.APIKey)
+
+	w := httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var response api.APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	milestoneID := uint(response.Data.(map[string]interface{})["id"].(float64))
+
+	onMilestone, _ := testData.TaskService.CreateTask("Release notes")
+	testData.TaskService.CreateTask("Unrelated task")
+
+	// Attach a task with PATCH
+	reqBody, _ = json.Marshal(map[string]interface{}{"milestone_id": milestoneID})
+	req = httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/tasks/%d", onMilestone.ID), bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(req, testData.APIKey)
+
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d attaching task, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	// Filter the task list by milestone
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/tasks?milestone=%d", milestoneID), nil)
+	addAuthHeader(req, testData.APIKey)
+
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+
+	var listResponse struct {
+		Data struct {
+			Items []models.Task `json:"items"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &listResponse); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(listResponse.Data.Items) != 1 || listResponse.Data.Items[0].ID != onMilestone.ID {
+		t.Errorf("Expected only task #%d on the milestone, got %+v", onMilestone.ID, listResponse.Data.Items)
+	}
+
+	// Progress counts the attached task
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/milestones/%d/progress", milestoneID), nil)
+	addAuthHeader(req, testData.APIKey)
+
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if total := response.Data.(map[string]interface{})["total_tasks"]; total != float64(1) {
+		t.Errorf("Expected 1 task on the milestone, got %v", total)
+	}
+
+	// Unknown milestones are rejected
+	reqBody, _ = json.Marshal(map[string]interface{}{"milestone_id": 999})
+	req = httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/tasks/%d", onMilestone.ID), bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(req, testData.APIKey)
+
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unknown milestone, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
 func TestGetTasks(t *testing.T) {
 	testData := setupTestAPI(t)
 
@@ -638,6 +851,33 @@ func TestTaskFiltering(t *testing.T) {
 		}
 	})
 
+	t.Run("Filter by all and excluded tags", func(t *testing.T) {
+		// Tasks tagged client1 and not urgent
+		req := httptest.NewRequest("GET", "/api/v1/tasks?all_tags=client1&exclude_tags=urgent", nil)
+		addAuthHeader(req, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+
+		var response api.APIResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		items := response.Data.(map[string]interface{})["items"].([]interface{})
+		if len(items) != 1 || items[0].(map[string]interface{})["name"] != "In Progress Task" {
+			t.Errorf("Expected only 'In Progress Task', got %v", items)
+		}
+
+		// all_tags requires every tag, unlike tags
+		req = httptest.NewRequest("GET", "/api/v1/tasks?all_tags=client1,client2", nil)
+		addAuthHeader(req, testData.APIKey)
+		w = httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if items := response.Data.(map[string]interface{})["items"].([]interface{}); len(items) != 0 {
+			t.Errorf("Expected no tasks with both client1 and client2, got %d", len(items))
+		}
+	})
+
 	t.Run("Combined filters", func(t *testing.T) {
 		// Filter by status=open AND priority=medium
 		req := httptest.NewRequest("GET", "/api/v1/tasks?status=open&priority=medium", nil)
@@ -781,8 +1021,6 @@ func TestTimeEntryEndpoints(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
 	}
 
-	// For now, the API returns empty array since repository method isn't fully implemented
-	// But we can verify the structure
 	var getResponse api.APIResponse
 	if err := json.Unmarshal(w.Body.Bytes(), &getResponse); err != nil {
 		t.Fatalf("Failed to unmarshal get response: %v", err)
@@ -791,6 +1029,9 @@ func TestTimeEntryEndpoints(t *testing.T) {
 	if !getResponse.Success {
 		t.Error("Expected success=true for get time entries")
 	}
+	if entries, ok := getResponse.Data.([]interface{}); !ok || len(entries) != 1 {
+		t.Errorf("Expected the logged time entry, got %v", getResponse.Data)
+	}
 
 	// Test validation for time entries
 	t.Run("Invalid time entry validation", func(t *testing.T) {
@@ -891,6 +1132,286 @@ func TestTagEndpoints(t *testing.T) {
 	}
 }
 
+func TestBulkTagEndpoints(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	for _, name := range []string{"Outage in region A", "Outage follow-up", "Unrelated"} {
+		if _, err := testData.TaskService.CreateTask(name); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	bulk := func(path string) (int, map[string]interface{}) {
+		req := httptest.NewRequest("POST", path, nil)
+		addAuthHeader(req, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+
+		var response api.APIResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		data, _ := response.Data.(map[string]interface{})
+		return w.Code, data
+	}
+
+	// Without a filter nothing is changed
+	if code, _ := bulk("/api/v1/tags/urgent/apply"); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d without a filter, got %d", http.StatusBadRequest, code)
+	}
+
+	code, data := bulk("/api/v1/tags/urgent/apply?search=outage&dry_run=true")
+	if code != http.StatusOK || data["affected"] != float64(2) || data["dry_run"] != true {
+		t.Fatalf("Expected dry run to report 2 tasks, got %d %v", code, data)
+	}
+	tasks, _ := testData.TaskService.GetTasks()
+	for _, task := range tasks {
+		if len(task.Tags) != 0 {
+			t.Fatalf("Expected dry run not to change tasks, got %v on %q", task.Tags, task.Name)
+		}
+	}
+
+	code, data = bulk("/api/v1/tags/urgent/apply?search=outage")
+	if code != http.StatusOK || data["affected"] != float64(2) {
+		t.Fatalf("Expected 2 tasks tagged, got %d %v", code, data)
+	}
+
+	// Applying again changes nothing
+	if _, data = bulk("/api/v1/tags/urgent/apply?search=outage"); data["affected"] != float64(0) || data["matched"] != float64(2) {
+		t.Errorf("Expected 2 matched and 0 affected, got %v", data)
+	}
+
+	if _, data = bulk("/api/v1/tags/urgent/remove?tags=urgent"); data["affected"] != float64(2) {
+		t.Errorf("Expected the tag removed from 2 tasks, got %v", data)
+	}
+}
+
+func TestDashboardEndpoints(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	if _, err := testData.TaskService.CreateTask("Dashboard task"); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	do := func(method, path, body string) (int, interface{}) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		addAuthHeader(req, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+
+		var response api.APIResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response.Data
+	}
+
+	// Users start with the default layout
+	code, data := do("GET", "/api/v1/dashboard/layout", "")
+	layout, _ := data.(map[string]interface{})
+	if code != http.StatusOK || len(layout["widgets"].([]interface{})) == 0 {
+		t.Fatalf("Expected the default layout, got %d %v", code, data)
+	}
+
+	if code, _ := do("PUT", "/api/v1/dashboard/layout", `{"widgets":[{"type":"pie"}]}`); code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d for an unknown widget type, got %d", http.StatusUnprocessableEntity, code)
+	}
+	if code, _ := do("PUT", "/api/v1/dashboard/layout", `{"widgets":[{"type":"query_chart"}]}`); code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d for a chart without a saved query, got %d", http.StatusUnprocessableEntity, code)
+	}
+
+	code, _ = do("PUT", "/api/v1/dashboard/layout", `{"widgets":[{"type":"task_count","status":"open"},{"type":"time_logged","width":5}]}`)
+	if code != http.StatusOK {
+		t.Fatalf("Expected status %d saving the layout, got %d", http.StatusOK, code)
+	}
+
+	code, data = do("GET", "/api/v1/reports/dashboard", "")
+	dashboard, _ := data.(map[string]interface{})
+	widgets, _ := dashboard["widgets"].([]interface{})
+	if code != http.StatusOK || len(widgets) != 2 {
+		t.Fatalf("Expected 2 widgets, got %d %v", code, data)
+	}
+	count := widgets[0].(map[string]interface{})
+	if count["count"] != float64(1) || count["title"] != "Open tasks" {
+		t.Errorf("Expected 1 open task titled 'Open tasks', got %v", count)
+	}
+	timeLogged := widgets[1].(map[string]interface{})
+	if timeLogged["widget"].(map[string]interface{})["width"] != float64(3) || timeLogged["time_logged"] == nil {
+		t.Errorf("Expected a full-width time widget, got %v", timeLogged)
+	}
+
+	if code, _ := do("DELETE", "/api/v1/dashboard/layout", ""); code != http.StatusOK {
+		t.Errorf("Expected status %d resetting the layout, got %d", http.StatusOK, code)
+	}
+	_, data = do("GET", "/api/v1/reports/dashboard", "")
+	if widgets := data.(map[string]interface{})["widgets"].([]interface{}); len(widgets) != len(services.DefaultDashboardLayout().Widgets) {
+		t.Errorf("Expected the default layout after reset, got %d widgets", len(widgets))
+	}
+}
+
+func TestBulkTimeEntryEndpoint(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	task, err := testData.TaskService.CreateTask("Imported task")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	post := func(body string) int {
+		req := httptest.NewRequest("POST", "/api/v1/time/bulk", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		addAuthHeader(req, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	body := fmt.Sprintf(`{"entries":[{"task_id":%d,"duration":30,"date":"2024-03-04"},{"task_id":%d,"duration":15,"description":"Review"}]}`, task.ID, task.ID)
+	if code := post(body); code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, code)
+	}
+
+	if code := post(`{"entries":[]}`); code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d for an empty batch, got %d", http.StatusUnprocessableEntity, code)
+	}
+	if code := post(fmt.Sprintf(`{"entries":[{"task_id":%d,"duration":10},{"task_id":9999,"duration":10}]}`, task.ID)); code != http.StatusNotFound {
+		t.Errorf("Expected status %d for an unknown task, got %d", http.StatusNotFound, code)
+	}
+
+	updated, err := testData.TaskService.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if updated.LoggedMinutes != 45 {
+		t.Errorf("Expected 45 logged minutes after the failed batch, got %d", updated.LoggedMinutes)
+	}
+}
+
+func TestBulkTaskEndpoint(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/tasks/bulk", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		addAuthHeader(req, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"tasks":[{"name":"Fix login","tags":["auth"],"priority":"high"},{"name":"Release notes","duration":30,"date":"2024-03-04","complete":true}]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []models.Task `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Data) != 2 || resp.Data[0].Name != "Fix login" || resp.Data[1].Status != models.TaskStatusResolved || resp.Data[1].LoggedMinutes != 30 {
+		t.Errorf("Unexpected tasks %+v", resp.Data)
+	}
+
+	if w := post(`{"tasks":[]}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d for an empty batch, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+	if w := post(`{"tasks":[{"name":"Valid"},{"name":"Bad","priority":"urgent"}]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid priority, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := post(`{"tasks":[{"name":"Valid"},{"name":"Bad","date":"someday"}]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid date, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	tasks, err := testData.TaskService.GetTasks()
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Errorf("Expected invalid batches to create nothing, got %d tasks", len(tasks))
+	}
+}
+
+func TestTaskImportEndpoint(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	post := func(body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/api/v1/tasks/import", bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		addAuthHeader(req, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(map[string]string{
+		"format":   "org",
+		"content":  "* Chores\n** TODO [#A] Renew passport\n   - [X] Book a photo\n** DONE Return books\n",
+		"headings": "projects",
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []models.Task `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Data) != 2 || resp.Data[0].Priority != models.TaskPriorityHigh || len(resp.Data[0].Subtasks) != 1 ||
+		resp.Data[1].Status != models.TaskStatusResolved || resp.Data[0].ProjectID == nil {
+		t.Errorf("Unexpected tasks %+v", resp.Data)
+	}
+
+	for _, body := range []map[string]string{
+		{"format": "txt", "content": "- [ ] x"},
+		{"format": "markdown", "content": "no checklist here"},
+		{"format": "markdown", "content": "- [ ] x", "headings": "folders"},
+	} {
+		if w := post(body); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%v: expected status %d, got %d", body, http.StatusUnprocessableEntity, w.Code)
+		}
+	}
+}
+
+func TestRequestBodyHardening(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	post := func(path string, body io.Reader, contentLength int64) int {
+		req := httptest.NewRequest("POST", path, body)
+		req.Header.Set("Content-Type", "application/json")
+		if contentLength >= 0 {
+			req.ContentLength = contentLength
+		}
+		addAuthHeader(req, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		body          io.Reader
+		contentLength int64
+		want          int
+	}{
+		{name: "valid", path: "/api/v1/tasks", body: strings.NewReader(`{"name":"Hardened"}`), contentLength: -1, want: http.StatusCreated},
+		{name: "unknown field", path: "/api/v1/tasks", body: strings.NewReader(`{"name":"Hardened","bogus":1}`), contentLength: -1, want: http.StatusBadRequest},
+		{name: "trailing data", path: "/api/v1/tasks", body: strings.NewReader(`{"name":"a"}{"name":"b"}`), contentLength: -1, want: http.StatusBadRequest},
+		{name: "empty body", path: "/api/v1/tasks", body: strings.NewReader(""), contentLength: -1, want: http.StatusBadRequest},
+		{name: "declared too large", path: "/api/v1/tasks", body: strings.NewReader(`{}`), contentLength: middleware.JSONBodyLimit + 1, want: http.StatusRequestEntityTooLarge},
+		{name: "streamed too large", path: "/api/v1/tasks", body: io.MultiReader(strings.NewReader(`{"name":"`), strings.NewReader(strings.Repeat("a", int(middleware.JSONBodyLimit))), strings.NewReader(`"}`)), contentLength: -1, want: http.StatusRequestEntityTooLarge},
+		{name: "bulk route allows more", path: "/api/v1/time/bulk", body: strings.NewReader(`{"entries":[]}`), contentLength: middleware.JSONBodyLimit + 1, want: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := post(tt.path, tt.body, tt.contentLength); code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, code)
+			}
+		})
+	}
+}
+
 func TestKanbanEndpoint(t *testing.T) {
 	testData := setupTestAPI(t)
 
@@ -989,15 +1510,280 @@ func TestKanbanEndpoint(t *testing.T) {
 	}
 }
 
-func TestCRUDOperationsConsistency(t *testing.T) {
+func TestKanbanFilters(t *testing.T) {
 	testData := setupTestAPI(t)
 
-	// Test full CRUD cycle with content verification
-	originalTask := api.TaskRequest{
-		Name:        "CRUD Test Task",
-		Description: "Testing complete CRUD operations",
-		Status:      models.TaskStatusOpen,
-		Priority:    models.TaskPriorityMedium,
+	tasks := []struct {
+		name   string
+		status models.TaskStatus
+		tags   []string
+	}{
+		{"Fix login page", models.TaskStatusOpen, []string{"client1", "web"}},
+		{"Login emails bounce", models.TaskStatusInProgress, []string{"client1"}},
+		{"Fix invoices", models.TaskStatusOpen, []string{"client2"}},
+		{"Internal cleanup", models.TaskStatusOpen, []string{"internal"}},
+	}
+	for _, data := range tasks {
+		task, _ := testData.TaskService.CreateTask(data.name)
+		task.Status = data.status
+		task.Tags = data.tags
+		testData.TaskService.UpdateTask(task)
+	}
+	query, _ := testData.TaskService.CreateSavedQuery(&models.SavedQuery{Name: "Clients", IncludedTags: []string{"client1", "client2"}})
+
+	getBoard := func(path string) (int, map[string]interface{}) {
+		req := newAuthenticatedRequest("GET", path, nil, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		var response api.APIResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		board, _ := response.Data.(map[string]interface{})
+		return w.Code, board
+	}
+
+	tests := []struct {
+		path       string
+		open       int
+		inProgress int
+	}{
+		{fmt.Sprintf("/api/v1/kanban?saved_query_id=%d", query.ID), 2, 1},
+		{fmt.Sprintf("/api/v1/kanban?saved_query_id=%d&search=login", query.ID), 1, 1},
+		{"/api/v1/kanban?tags=client2,internal", 2, 0},
+		{"/api/v1/kanban/client1?search=fix", 1, 0},
+	}
+	for _, tt := range tests {
+		code, board := getBoard(tt.path)
+		if code != http.StatusOK {
+			t.Errorf("%s: expected status 200, got %d", tt.path, code)
+			continue
+		}
+		statistics, _ := board["statistics"].(map[string]interface{})
+		if int(statistics["open"].(float64)) != tt.open || int(statistics["in-progress"].(float64)) != tt.inProgress ||
+			int(statistics["total"].(float64)) != tt.open+tt.inProgress {
+			t.Errorf("%s: unexpected statistics %v", tt.path, statistics)
+		}
+		// WIP counts cover the whole board regardless of the filter
+		wip, _ := board["wip"].(map[string]interface{})
+		if open, _ := wip["open"].(map[string]interface{}); int(open["count"].(float64)) != 3 {
+			t.Errorf("%s: expected WIP count of 3 open tasks, got %v", tt.path, wip["open"])
+		}
+	}
+
+	if _, board := getBoard(fmt.Sprintf("/api/v1/kanban?saved_query_id=%d", query.ID)); board["saved_query"] != "Clients" {
+		t.Errorf("Expected the saved query name on the board, got %v", board["saved_query"])
+	}
+	if code, _ := getBoard("/api/v1/kanban?saved_query_id=999"); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown saved query, got %d", code)
+	}
+}
+
+func TestTagStatsEndpoint(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	task, _ := testData.TaskService.CreateTask("Migrate mail")
+	task.Tags = []string{"client1"}
+	testData.TaskService.UpdateTask(task)
+	// Logging time also starts the task
+	testData.TaskService.AddTimeEntry(task.ID, &models.TimeEntry{Duration: 30})
+
+	req := newAuthenticatedRequest("GET", "/api/v1/tags/stats?since=today", nil, testData.APIKey)
+	w := httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data api.TagStatsResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(response.Data.Tags) != 1 || response.Data.Tags[0].InProgressTasks != 1 || response.Data.Tags[0].LoggedMinutes != 30 {
+		t.Errorf("Unexpected tag stats %+v", response.Data.Tags)
+	}
+
+	req = newAuthenticatedRequest("GET", "/api/v1/tags/stats?since=notadate", nil, testData.APIKey)
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid date, got %d", w.Code)
+	}
+}
+
+func TestSearchSnippets(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	printer, _ := testData.TaskService.CreateTask("Printer offline")
+	printer.Description = "The office printer shows a paper jam"
+	printer.Tags = []string{"hardware"}
+	testData.TaskService.UpdateTask(printer)
+	toner, _ := testData.TaskService.CreateTask("Order supplies")
+	toner.Description = "Toner for the printer"
+	testData.TaskService.UpdateTask(toner)
+	testData.TaskService.AddComment(toner.ID, &models.Comment{Content: "Vendor says the printer toner ships Monday"})
+
+	req := newAuthenticatedRequest("GET", "/api/v1/search?q=printer", nil, testData.APIKey)
+	w := httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data api.SearchResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	matchTypes := map[uint]string{}
+	for _, result := range response.Data.Results["tasks"] {
+		if result.Snippet == nil || len(result.Snippet.Highlights) == 0 {
+			t.Errorf("Expected a highlighted snippet for task %d", result.ID)
+		}
+		matchTypes[result.ID] = result.MatchType
+	}
+	if matchTypes[printer.ID] != "name" || matchTypes[toner.ID] != "description" {
+		t.Errorf("Unexpected match types %v", matchTypes)
+	}
+
+	comments := response.Data.Results["comments"]
+	if len(comments) != 1 || comments[0].TaskID == nil || *comments[0].TaskID != toner.ID || comments[0].Name != "Order supplies" {
+		t.Fatalf("Expected the comment on the supplies task, got %+v", comments)
+	}
+	if comments[0].Snippet == nil || comments[0].Snippet.Text != "Vendor says the printer toner ships Monday" {
+		t.Errorf("Unexpected comment snippet %+v", comments[0].Snippet)
+	}
+	if response.Data.Total != 3 {
+		t.Errorf("Expected 3 results, got %d", response.Data.Total)
+	}
+
+	// Comments are limited to tasks that pass the filters
+	req = newAuthenticatedRequest("GET", "/api/v1/search?q=printer&type=comment&tags=hardware", nil, testData.APIKey)
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	response.Data = api.SearchResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(response.Data.Results["comments"]) != 0 {
+		t.Errorf("Expected no comments outside the filtered tasks, got %+v", response.Data.Results["comments"])
+	}
+}
+
+func TestTaskSearchScope(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	named, _ := testData.TaskService.CreateTask("Toner order")
+	commented, _ := testData.TaskService.CreateTask("Printer offline")
+	testData.TaskService.AddComment(commented.ID, &models.Comment{Content: "Swapped the toner"})
+
+	search := func(url string) []uint {
+		t.Helper()
+		req := newAuthenticatedRequest("GET", url, nil, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d: %s", url, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data struct {
+				Items []models.Task `json:"items"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		var ids []uint
+		for _, task := range response.Data.Items {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	if ids := search("/api/v1/tasks?search=toner"); len(ids) != 2 {
+		t.Errorf("Expected comments to be searched by default, got tasks %v", ids)
+	}
+	if ids := search("/api/v1/tasks?search=toner&in=comments"); len(ids) != 1 || ids[0] != commented.ID {
+		t.Errorf("Expected only the commented task, got %v", ids)
+	}
+	if ids := search("/api/v1/tasks?search=toner&in=name,description"); len(ids) != 1 || ids[0] != named.ID {
+		t.Errorf("Expected only the named task, got %v", ids)
+	}
+
+	req := newAuthenticatedRequest("GET", "/api/v1/tasks?search=toner&in=subtasks", nil, testData.APIKey)
+	w := httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown search field, got %d", w.Code)
+	}
+}
+
+func TestTaskSearchQuerySyntax(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	create := func(name string, priority models.TaskPriority, tags ...string) *models.Task {
+		task, _ := testData.TaskService.CreateTask(name)
+		task.Priority = priority
+		task.Tags = tags
+		testData.TaskService.UpdateTask(task)
+		return task
+	}
+	jam := create("Printer paper jam", models.TaskPriorityHigh, "client1")
+	create("Printer paper order", models.TaskPriorityHigh, "client1", "internal")
+	create("Printer paper jam again", models.TaskPriorityLow, "client2")
+
+	get := func(url string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest("GET", url, nil, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/tasks?" + url.Values{"search": {`tag:client1 priority:high "paper jam" -tag:internal`}}.Encode())
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var list struct {
+		Data struct {
+			Items []models.Task `json:"items"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(list.Data.Items) != 1 || list.Data.Items[0].ID != jam.ID {
+		t.Errorf("Expected only the client1 jam, got %+v", list.Data.Items)
+	}
+
+	if w := get("/api/v1/tasks?" + url.Values{"search": {"status:waiting"}}.Encode()); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid status, got %d", w.Code)
+	}
+
+	// The search endpoint takes the same syntax and highlights every term
+	w = get("/api/v1/search?" + url.Values{"q": {"printer jam tag:client1 -tag:internal"}}.Encode())
+	var search struct {
+		Data api.SearchResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &search); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	tasks := search.Data.Results["tasks"]
+	if len(tasks) != 1 || tasks[0].ID != jam.ID || tasks[0].Snippet == nil || len(tasks[0].Snippet.Highlights) != 2 {
+		t.Errorf("Expected the client1 jam with two highlights, got %+v", tasks)
+	}
+}
+
+func TestCRUDOperationsConsistency(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	// Test full CRUD cycle with content verification
+	originalTask := api.TaskRequest{
+		Name:        "CRUD Test Task",
+		Description: "Testing complete CRUD operations",
+		Status:      models.TaskStatusOpen,
+		Priority:    models.TaskPriorityMedium,
 		Tags:        []string{"test", "crud", "api"},
 	}
 
@@ -1143,3 +1929,1600 @@ func TestCRUDOperationsConsistency(t *testing.T) {
 		t.Error("Expected success=false when accessing deleted task")
 	}
 }
+
+func TestAdminDebugEndpoints(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	_, adminKey, err := testData.AuthService.CreateAPIKey(testData.TestUser.ID, "Admin Key", models.AdminPermissions(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create admin API key: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		key  string
+		want int
+	}{
+		{name: "runtime stats", path: "/api/v1/admin/debug", key: adminKey, want: http.StatusOK},
+		{name: "heap profile", path: "/api/v1/admin/debug/pprof/heap", key: adminKey, want: http.StatusOK},
+		{name: "goroutine profile", path: "/api/v1/admin/debug/pprof/goroutine?debug=1", key: adminKey, want: http.StatusOK},
+		{name: "unknown profile", path: "/api/v1/admin/debug/pprof/cmdline", key: adminKey, want: http.StatusNotFound},
+		{name: "cpu profile too long", path: "/api/v1/admin/debug/pprof/profile?seconds=600", key: adminKey, want: http.StatusBadRequest},
+		{name: "requires admin", path: "/api/v1/admin/debug/pprof/heap", key: testData.APIKey, want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newAuthenticatedRequest("GET", tt.path, nil, tt.key)
+			w := httptest.NewRecorder()
+			testData.Handler.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			if tt.want == http.StatusOK && w.Body.Len() == 0 {
+				t.Error("Expected a non-empty response")
+			}
+		})
+	}
+}
+
+func TestEmailSimulateEndpoint(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	_, adminKey, err := testData.AuthService.CreateAPIKey(testData.TestUser.ID, "Admin Key", models.AdminPermissions(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create admin API key: %v", err)
+	}
+
+	message := "From: test@example.com\r\nSubject: VPN is down\r\nMessage-Id: <vpn@example.com>\r\n\r\nCannot connect since this morning.\r\n"
+	simulate := func(body, key string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest("POST", "/api/v1/admin/email/simulate", strings.NewReader(body), key)
+		req.Header.Set("Content-Type", "message/rfc822")
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := simulate(message, testData.APIKey); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 without admin permission, got %d", w.Code)
+	}
+	if w := simulate("", adminKey); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an empty body, got %d", w.Code)
+	}
+
+	w := simulate(message, adminKey)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data services.EmailSimulation `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.Outcome != services.EmailOutcomeCreateTask || response.Data.TaskName != "VPN is down" || response.Data.Sender != "testuser" {
+		t.Errorf("Expected testuser's message to create a task, got %+v", response.Data)
+	}
+
+	tasks, err := testData.TaskService.GetTasks()
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("Expected no task to be created, found %d", len(tasks))
+	}
+}
+
+func TestAttachmentUpload(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	task, err := testData.TaskService.CreateTask("Printer jams")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	upload := func(taskID uint, name string, data []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", name)
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		part.Write(data)
+		form.Close()
+
+		req := newAuthenticatedRequest("POST", fmt.Sprintf("/api/v1/tasks/%d/attachments", taskID), &body, testData.APIKey)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	w := upload(task.ID, "screenshot.png", png)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data models.Attachment `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.ID == 0 || response.Data.OriginalName != "screenshot.png" || response.Data.ContentType != "image/png" {
+		t.Errorf("Unexpected attachment %+v", response.Data)
+	}
+
+	stored, err := testData.TaskService.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload task: %v", err)
+	}
+	if len(stored.Attachments) != 1 || stored.Attachments[0].ID != response.Data.ID {
+		t.Errorf("Expected the upload to be attached to the task, got %+v", stored.Attachments)
+	}
+
+	if w := upload(task.ID, "empty.png", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an empty file, got %d", w.Code)
+	}
+	if w := upload(task.ID+100, "screenshot.png", png); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing task, got %d", w.Code)
+	}
+}
+
+func TestMobileCaptureEndpoint(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	capture := func(body io.Reader, contentType string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest("POST", "/api/v1/capture/mobile", body, testData.APIKey)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+	var response struct {
+		Data models.Task `json:"data"`
+	}
+
+	w := capture(strings.NewReader(`{"text": "Check leak +home", "latitude": 51.5007, "longitude": -0.1246, "location": "Westminster"}`), "application/json")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.Name != "Check leak home" || strings.Join(response.Data.Tags, ",") != "home,location:westminster" {
+		t.Errorf("Unexpected task %q with tags %v", response.Data.Name, response.Data.Tags)
+	}
+
+	// Shortcuts and Tasker send forms with the photo as a file
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("text", "Broken window")
+	form.WriteField("latitude", "51.5007")
+	form.WriteField("longitude", "-0.1246")
+	part, _ := form.CreateFormFile("photo", "window.jpg")
+	part.Write([]byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"))
+	form.Close()
+	w = capture(&body, form.FormDataContentType())
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data.Attachments) != 1 || response.Data.Attachments[0].ContentType != "image/jpeg" {
+		t.Errorf("Expected the photo to be attached, got %+v", response.Data.Attachments)
+	}
+	if strings.Join(response.Data.Tags, ",") != "location:51.50,-0.12" {
+		t.Errorf("Expected the rounded coordinates as the location, got %v", response.Data.Tags)
+	}
+
+	if w := capture(strings.NewReader(`{"text": ""}`), "application/json"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 without text, got %d", w.Code)
+	}
+	if w := capture(strings.NewReader(`{"text": "Lost", "latitude": 123, "longitude": 0}`), "application/json"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for an out of range latitude, got %d", w.Code)
+	}
+}
+
+func TestInboundWebhook(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	deliver := func(path, secret string, status int) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"heartbeat":{"status":%d},"monitor":{"id":4,"name":"API","type":"http"}}`, status)
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if secret != "" {
+			req.Header.Set("Authorization", "Bearer "+secret)
+		}
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+	action := func(w *httptest.ResponseRecorder) (string, models.Task) {
+		t.Helper()
+		var response struct {
+			Data services.InboundResult `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if response.Data.Task == nil {
+			return response.Data.Action, models.Task{}
+		}
+		return response.Data.Action, *response.Data.Task
+	}
+
+	if w := deliver("/api/v1/inbound/grafana", "kuma-secret", 0); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown channel, got %d", w.Code)
+	}
+	if w := deliver("/api/v1/inbound/uptime", "wrong", 0); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong secret, got %d", w.Code)
+	}
+	if w := deliver("/api/v1/inbound/uptime", "kuma-secret", 1); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 for a recovery without an alert, got %d", w.Code)
+	} else if got, _ := action(w); got != services.InboundActionIgnored {
+		t.Errorf("Expected the recovery to be ignored, got %q", got)
+	}
+
+	w := deliver("/api/v1/inbound/uptime", "kuma-secret", 0)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	_, task := action(w)
+	if task.Name != "API is down" || task.Priority != models.TaskPriorityHigh || strings.Join(task.Tags, ",") != "alert,http" {
+		t.Errorf("Expected a high priority task mapped from the payload, got %+v", task)
+	}
+
+	// A repeat leaves the task alone; the secret also works as a query parameter
+	w = deliver("/api/v1/inbound/uptime?secret=kuma-secret", "", 0)
+	if got, repeat := action(w); got != services.InboundActionUnchanged || repeat.ID != task.ID {
+		t.Errorf("Expected the repeat to leave task %d unchanged, got %q on %d", task.ID, got, repeat.ID)
+	}
+
+	w = deliver("/api/v1/inbound/uptime", "kuma-secret", 1)
+	if got, resolved := action(w); got != services.InboundActionResolved || resolved.Status != models.TaskStatusResolved {
+		t.Errorf("Expected the recovery to resolve the task, got %q %s", got, resolved.Status)
+	}
+
+	w = deliver("/api/v1/inbound/uptime", "kuma-secret", 0)
+	if got, reopened := action(w); got != services.InboundActionReopened || reopened.ID != task.ID || reopened.Status != models.TaskStatusOpen {
+		t.Errorf("Expected the task to be reopened, got %q %d %s", got, reopened.ID, reopened.Status)
+	}
+}
+
+func TestActivityLongPoll(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest("GET", path, nil, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("/api/v1/activity?after=yesterday"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid after timestamp, got %d", w.Code)
+	}
+	if w := get("/api/v1/activity?query=999"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown saved query, got %d", w.Code)
+	}
+
+	after := time.Now().Add(time.Hour).Format(time.RFC3339Nano)
+	start := time.Now()
+	w := get("/api/v1/activity?wait=1&after=" + url.QueryEscape(after))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected the request to wait for new activity, returned after %s", elapsed)
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	first, _ := testData.TaskService.CreateTask("First")
+	second, _ := testData.TaskService.CreateTask("Second")
+	if first == nil || second == nil {
+		t.Fatal("Failed to create tasks")
+	}
+
+	stream := func(path, lastID string) *httptest.ResponseRecorder {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		req := newAuthenticatedRequest("GET", path, nil, testData.APIKey).WithContext(ctx)
+		if lastID != "" {
+			req.Header.Set("Last-Event-ID", lastID)
+		}
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := stream("/api/v1/events?type=task.exploded", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown event type, got %d", w.Code)
+	}
+
+	w := stream("/api/v1/events", "1")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "id: 2\nevent: task.created\n") || !strings.Contains(body, `"name":"Second"`) {
+		t.Errorf("Expected the missed event for Second replayed, got:\n%s", body)
+	}
+	if strings.Contains(body, `"name":"First"`) {
+		t.Errorf("Expected the event before Last-Event-ID skipped, got:\n%s", body)
+	}
+
+	w = stream("/api/v1/events?type=task.deleted", "1")
+	if strings.Contains(w.Body.String(), "event:") {
+		t.Errorf("Expected the type filter to skip task.created, got:\n%s", w.Body.String())
+	}
+}
+
+func TestPluginRoutes(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	call := func(method, path, body, apiKey string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest(method, path, strings.NewReader(body), apiKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := call("POST", "/api/v1/plugins/echo/greet?name=Ada", "hello", testData.APIKey)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data services.PluginRequest `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	want := services.PluginRequest{Method: "POST", Path: "/greet", Query: map[string]string{"name": "Ada"}, Body: "hello", User: "testuser"}
+	if fmt.Sprint(response.Data) != fmt.Sprint(want) {
+		t.Errorf("Expected the plugin to see %+v, got %+v", want, response.Data)
+	}
+
+	if w := call("GET", "/api/v1/plugins/echo/fail", "", testData.APIKey); w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "already done") {
+		t.Errorf("Expected the plugin's 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := call("GET", "/api/v1/plugins/missing/greet", "", testData.APIKey); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown plugin, got %d", w.Code)
+	}
+	if w := call("GET", "/api/v1/plugins/echo/greet", "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials, got %d", w.Code)
+	}
+
+	_, readOnly, err := testData.AuthService.CreateAPIKey(testData.TestUser.ID, "Read only", []string{models.PermissionReadTasks}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create API key: %v", err)
+	}
+	if w := call("GET", "/api/v1/plugins/echo/greet", "", readOnly); w.Code != http.StatusOK {
+		t.Errorf("Expected a read-only key to GET plugin routes, got %d", w.Code)
+	}
+	if w := call("POST", "/api/v1/plugins/echo/greet", "", readOnly); w.Code != http.StatusForbidden {
+		t.Errorf("Expected a read-only key to be refused POST, got %d", w.Code)
+	}
+}
+
+func TestSavedQueryReorderEndpoint(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	first, _ := testData.TaskService.CreateSavedQuery(&models.SavedQuery{Name: "First"})
+	second, _ := testData.TaskService.CreateSavedQuery(&models.SavedQuery{Name: "Second"})
+
+	reorder := func(ids []uint) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string][]uint{"ids": ids})
+		req := newAuthenticatedRequest("PUT", "/api/v1/saved-queries/order", bytes.NewBuffer(body), testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := reorder([]uint{first.ID}); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for an incomplete order, got %d", w.Code)
+	}
+
+	w := reorder([]uint{second.ID, first.ID})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req := newAuthenticatedRequest("GET", "/api/v1/saved-queries?counts=true", nil, testData.APIKey)
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+
+	var response struct {
+		Data []models.SavedQuery `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data) != 2 || response.Data[0].ID != second.ID {
+		t.Fatalf("Expected Second to be listed first, got %+v", response.Data)
+	}
+	if response.Data[0].OpenCount == nil {
+		t.Error("Expected open counts when counts=true")
+	}
+}
+
+func TestLandingViewAndPinnedQueries(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	first, _ := testData.TaskService.CreateSavedQuery(&models.SavedQuery{Name: "First"})
+	second, _ := testData.TaskService.CreateSavedQuery(&models.SavedQuery{Name: "Second"})
+
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest("PATCH", "/api/v1/auth/profile", strings.NewReader(body), testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := patch(`{"landing_view":"calendar"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown landing view, got %d", w.Code)
+	}
+	if w := patch(`{"pinned_query_ids":[999]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for pinning an unknown saved query, got %d", w.Code)
+	}
+
+	// A rejected update saves none of its other preferences
+	if w := patch(`{"language":"de","weekly_capacity":"30h","landing_view":"calendar"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown landing view, got %d", w.Code)
+	}
+	if user, _ := testData.AuthService.GetUserByUsername(testData.TestUser.Username); user.Language == "de" || user.WeeklyCapacity != 0 {
+		t.Errorf("Expected a rejected update to leave the profile unchanged, got %q %d", user.Language, user.WeeklyCapacity)
+	}
+
+	w := patch(fmt.Sprintf(`{"landing_view":"kanban","landing_query_id":%d,"pinned_query_ids":[%d]}`, first.ID, second.ID))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var profile struct {
+		Data models.User `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &profile); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if profile.Data.LandingView != "kanban" || profile.Data.LandingQueryID == nil || *profile.Data.LandingQueryID != first.ID {
+		t.Errorf("Expected kanban on the first query, got %q %v", profile.Data.LandingView, profile.Data.LandingQueryID)
+	}
+
+	req := newAuthenticatedRequest("GET", "/api/v1/saved-queries", nil, testData.APIKey)
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	var response struct {
+		Data []models.SavedQuery `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data) != 2 || response.Data[0].ID != second.ID || !response.Data[0].Pinned || response.Data[1].Pinned {
+		t.Fatalf("Expected the pinned Second query first, got %+v", response.Data)
+	}
+
+	// Clearing the landing query keeps the view
+	if w := patch(`{"landing_query_id":0}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	user, err := testData.AuthService.GetUserByUsername(testData.TestUser.Username)
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+	if user.LandingView != "kanban" || user.LandingQueryID != nil {
+		t.Errorf("Expected kanban with no landing query, got %q %v", user.LandingView, user.LandingQueryID)
+	}
+}
+
+func TestSavedQueryUpdateAndDelete(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	query, _ := testData.TaskService.CreateSavedQuery(&models.SavedQuery{Name: "Backend", IncludedTags: []string{"backend"}})
+	path := fmt.Sprintf("/api/v1/saved-queries/%d", query.ID)
+
+	req := newAuthenticatedRequest("PUT", path, strings.NewReader(`{"name":"Servers","included_tags":["ops"]}`), testData.APIKey)
+	w := httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	updated, err := testData.TaskService.GetSavedQueryByID(query.ID)
+	if err != nil {
+		t.Fatalf("Failed to get saved query: %v", err)
+	}
+	if updated.Name != "Servers" || len(updated.IncludedTags) != 1 || updated.IncludedTags[0] != "ops" {
+		t.Errorf("Expected the query to be renamed with new tags, got %+v", updated)
+	}
+
+	req = newAuthenticatedRequest("DELETE", path, nil, testData.APIKey)
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := testData.TaskService.GetSavedQueryByID(query.ID); err == nil {
+		t.Error("Expected the saved query to be deleted")
+	}
+}
+
+func TestSavedQueryWebhookEndpoints(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	_, adminKey, err := testData.AuthService.CreateAPIKey(testData.TestUser.ID, "Admin Key", models.AdminPermissions(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create admin API key: %v", err)
+	}
+	query, _ := testData.TaskService.CreateSavedQuery(&models.SavedQuery{Name: "Backend", IncludedTags: []string{"backend"}})
+
+	body := fmt.Sprintf(`{"saved_query_id":%d,"url":"https://bi.example.com/ingest","cron":"0 6 * * *"}`, query.ID)
+	req := newAuthenticatedRequest("POST", "/api/v1/admin/query-webhooks", strings.NewReader(body), testData.APIKey)
+	w := httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected non-admins to get status 403, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = newAuthenticatedRequest("POST", "/api/v1/admin/query-webhooks", strings.NewReader(`{"saved_query_id":999,"url":"https://bi.example.com/ingest","cron":"0 6 * * *"}`), adminKey)
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422 for an unknown saved query, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = newAuthenticatedRequest("POST", "/api/v1/admin/query-webhooks", strings.NewReader(body), adminKey)
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data models.SavedQueryWebhook `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !created.Data.Enabled || created.Data.NextRunAt == nil {
+		t.Errorf("Expected an enabled webhook with a next run, got %+v", created.Data)
+	}
+
+	path := fmt.Sprintf("/api/v1/admin/query-webhooks/%d", created.Data.ID)
+	req = newAuthenticatedRequest("PUT", path, strings.NewReader(fmt.Sprintf(`{"saved_query_id":%d,"url":"https://bi.example.com/ingest","cron":"@hourly","enabled":false}`, query.ID)), adminKey)
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	updated, err := testData.TaskService.GetSavedQueryWebhook(created.Data.ID)
+	if err != nil || updated.Cron != "@hourly" || updated.Enabled {
+		t.Errorf("Expected a disabled hourly webhook, got %+v (%v)", updated, err)
+	}
+
+	req = newAuthenticatedRequest("DELETE", path, nil, adminKey)
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSavedQueryTagExpression(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	for name, tags := range map[string][]string{"Client one": {"client1"}, "Internal": {"client1", "internal"}} {
+		task, _ := testData.TaskService.CreateTask(name)
+		task.Tags = tags
+		testData.TaskService.UpdateTask(task)
+	}
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req := newAuthenticatedRequest(method, path, reader, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send("POST", "/api/v1/saved-queries", `{"name":"Broken","expression":"(client1 OR"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for an invalid expression, got %d", w.Code)
+	}
+	w := send("POST", "/api/v1/saved-queries", `{"name":"Clients","expression":"client1 and not internal"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data models.SavedQuery `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.Data.Expression != "client1 AND NOT internal" {
+		t.Errorf("Expected the canonical expression, got %q", created.Data.Expression)
+	}
+
+	// The TUI filters a saved query's tasks with the tag_expr parameter
+	w = send("GET", "/api/v1/tasks?tag_expr="+url.QueryEscape(created.Data.Expression), "")
+	var tasks struct {
+		Data struct {
+			Items []models.Task `json:"items"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &tasks)
+	if w.Code != http.StatusOK || len(tasks.Data.Items) != 1 || tasks.Data.Items[0].Name != "Client one" {
+		t.Errorf("Expected only Client one, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := send("GET", "/api/v1/tasks?tag_expr="+url.QueryEscape("NOT"), ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid tag_expr, got %d", w.Code)
+	}
+
+	// Omitting the expression keeps it, and an empty one clears it
+	path := fmt.Sprintf("/api/v1/saved-queries/%d", created.Data.ID)
+	send("PUT", path, `{"name":"Client work"}`)
+	if query, _ := testData.TaskService.GetSavedQueryByID(created.Data.ID); query.Expression != "client1 AND NOT internal" {
+		t.Errorf("Expected the expression to be kept, got %q", query.Expression)
+	}
+	send("PUT", path, `{"expression":""}`)
+	if query, _ := testData.TaskService.GetSavedQueryByID(created.Data.ID); query.Expression != "" {
+		t.Errorf("Expected the expression to be cleared, got %q", query.Expression)
+	}
+}
+
+func TestTimeEntryBillable(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	task, _ := testData.TaskService.CreateTask("Client work")
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req := newAuthenticatedRequest(method, path, reader, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	timePath := fmt.Sprintf("/api/v1/tasks/%d/time", task.ID)
+	if w := do("POST", timePath, `{"duration":60}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", timePath, `{"duration":15,"billable":false}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	for _, tt := range []struct {
+		path string
+		want []int
+	}{
+		{"/api/v1/time?since=today&billable=true", []int{60}},
+		{"/api/v1/time?since=today&billable=false", []int{15}},
+		{timePath + "?billable=false", []int{15}},
+		{timePath, []int{60, 15}},
+	} {
+		w := do("GET", tt.path, "")
+		var list struct {
+			Data []struct {
+				Duration int `json:"duration"`
+			} `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &list)
+		var got []int
+		for _, entry := range list.Data {
+			got = append(got, entry.Duration)
+		}
+		if w.Code != http.StatusOK || fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("GET %s: expected durations %v, got %d: %s", tt.path, tt.want, w.Code, w.Body.String())
+		}
+	}
+	if w := do("GET", "/api/v1/time?billable=maybe", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid billable filter, got %d", w.Code)
+	}
+
+	query, _ := testData.TaskService.CreateSavedQuery(&models.SavedQuery{Name: "All"})
+	today := time.Now().Format("2006-01-02")
+	w := do("GET", fmt.Sprintf("/api/v1/reports/time-breakdown?start_date=%s&end_date=%s&saved_query_ids=%d", today, today, query.ID), "")
+	var report struct {
+		Data struct {
+			Totals struct {
+				TotalTime       int `json:"total_time"`
+				BillableTime    int `json:"billable_time"`
+				NonBillableTime int `json:"non_billable_time"`
+			} `json:"totals"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &report)
+	if totals := report.Data.Totals; w.Code != http.StatusOK || totals.TotalTime != 75 || totals.BillableTime != 60 || totals.NonBillableTime != 15 {
+		t.Errorf("Expected 60 billable and 15 non-billable minutes in the report, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTimesheetEndpoints(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	task, _ := testData.TaskService.CreateTask("Client work")
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req := newAuthenticatedRequest(method, path, reader, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	body := fmt.Sprintf(`{"cells":[{"task_id":%d,"date":"2024-03-05","minutes":90},{"task_id":%d,"date":"2024-03-07","minutes":30}]}`, task.ID, task.ID)
+	if w := do("PUT", "/api/v1/time/timesheet", body); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := do("GET", "/api/v1/time/timesheet?week=2024-03-06", "")
+	var sheet struct {
+		Data struct {
+			Rows []struct {
+				TaskID  uint  `json:"task_id"`
+				Minutes []int `json:"minutes"`
+				Total   int   `json:"total"`
+			} `json:"rows"`
+			Total int `json:"total"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &sheet)
+	if w.Code != http.StatusOK || len(sheet.Data.Rows) != 1 || fmt.Sprint(sheet.Data.Rows[0].Minutes) != "[0 90 0 30 0 0 0]" || sheet.Data.Total != 120 {
+		t.Fatalf("Expected 90 minutes on Tuesday and 30 on Thursday, got %d: %s", w.Code, w.Body.String())
+	}
+
+	for _, tt := range []struct {
+		body string
+		want int
+	}{
+		{`{"cells":[]}`, http.StatusUnprocessableEntity},
+		{fmt.Sprintf(`{"cells":[{"task_id":%d,"date":"2024-03-05","minutes":-5}]}`, task.ID), http.StatusBadRequest},
+		{fmt.Sprintf(`{"cells":[{"task_id":%d,"date":"someday","minutes":5}]}`, task.ID), http.StatusBadRequest},
+		{`{"cells":[{"task_id":999,"date":"2024-03-05","minutes":5}]}`, http.StatusNotFound},
+	} {
+		if w := do("PUT", "/api/v1/time/timesheet", tt.body); w.Code != tt.want {
+			t.Errorf("PUT %s: expected %d, got %d: %s", tt.body, tt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestTimeEntryListUpdateDelete(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	task, _ := testData.TaskService.CreateTask("Timed task")
+	entry := &models.TimeEntry{Duration: 30, Description: "First pass"}
+	if err := testData.TaskService.AddTimeEntry(task.ID, entry); err != nil {
+		t.Fatalf("Failed to add time entry: %v", err)
+	}
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req := newAuthenticatedRequest(method, path, reader, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("GET", "/api/v1/time?since=today", "")
+	var list struct {
+		Data []struct {
+			ID       uint   `json:"id"`
+			TaskName string `json:"task_name"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if w.Code != http.StatusOK || len(list.Data) != 1 || list.Data[0].TaskName != "Timed task" {
+		t.Fatalf("Expected today's entry with its task name, got %d: %s", w.Code, w.Body.String())
+	}
+
+	path := fmt.Sprintf("/api/v1/tasks/%d/time/%d", task.ID, entry.ID)
+	if w := do("PUT", fmt.Sprintf("/api/v1/tasks/%d/time/999", task.ID), `{"duration":10}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown entry, got %d", w.Code)
+	}
+	if w := do("PUT", path, `{"duration":90,"description":"Second pass"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	entries, _ := testData.TaskService.GetTimeEntries(task.ID)
+	if len(entries) != 1 || entries[0].Duration != 90 || entries[0].Description != "Second pass" {
+		t.Errorf("Expected the entry to be updated, got %+v", entries)
+	}
+
+	if w := do("DELETE", path, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if entries, _ := testData.TaskService.GetTimeEntries(task.ID); len(entries) != 0 {
+		t.Errorf("Expected the entry to be deleted, got %d entries", len(entries))
+	}
+}
+
+func TestTaskDueDates(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req := newAuthenticatedRequest(method, path, reader, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+	type taskResponse struct {
+		ID      uint       `json:"id"`
+		DueDate *time.Time `json:"due_date"`
+		Overdue bool       `json:"overdue"`
+	}
+	create := func(body string) taskResponse {
+		w := do("POST", "/api/v1/tasks", body)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data taskResponse `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+	listIDs := func(query string) []uint {
+		w := do("GET", "/api/v1/tasks?"+query, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET ?%s: expected status 200, got %d: %s", query, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data struct {
+				Items []taskResponse `json:"items"`
+			} `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		var ids []uint
+		for _, item := range response.Data.Items {
+			ids = append(ids, item.ID)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+
+	late := create(`{"name":"Late report","due_date":"2020-01-15"}`)
+	if late.DueDate == nil || late.DueDate.Format("2006-01-02") != "2020-01-15" || !late.Overdue {
+		t.Fatalf("Expected an overdue task due 2020-01-15, got %+v", late)
+	}
+	later := create(`{"name":"Future report","due_date":"2999-06-01"}`)
+	undated := create(`{"name":"Whenever"}`)
+	if later.Overdue || undated.DueDate != nil {
+		t.Fatalf("Expected neither task to be overdue, got %+v and %+v", later, undated)
+	}
+	if w := do("POST", "/api/v1/tasks", `{"name":"Bad","due_date":"someday"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid due date, got %d", w.Code)
+	}
+
+	for _, tt := range []struct {
+		query string
+		want  []uint
+	}{
+		{"overdue=true", []uint{late.ID}},
+		{"due_before=2021-01-01", []uint{late.ID}},
+		{"due_after=2021-01-01", []uint{later.ID}},
+		{"due_after=2020-01-15&due_before=2999-06-01", []uint{late.ID, later.ID}},
+	} {
+		if got := listIDs(tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("GET ?%s: expected %v, got %v", tt.query, tt.want, got)
+		}
+	}
+	if w := do("GET", "/api/v1/tasks?due_before=someday", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid due_before, got %d", w.Code)
+	}
+
+	w := do("GET", "/api/v1/summary/tasks", "")
+	var summary struct {
+		Data struct {
+			OverdueTasks int `json:"overdue_tasks"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &summary)
+	if summary.Data.OverdueTasks != 1 {
+		t.Errorf("Expected 1 overdue task in the summary, got %s", w.Body.String())
+	}
+
+	// Resolving or clearing the due date stops a task being overdue
+	if w := do("PATCH", fmt.Sprintf("/api/v1/tasks/%d", late.ID), `{"due_date":null}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := listIDs("overdue=true"); len(got) != 0 {
+		t.Errorf("Expected no overdue tasks after clearing the due date, got %v", got)
+	}
+}
+
+func TestOutOfOfficeEndpoints(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req := newAuthenticatedRequest(method, path, reader, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	today := time.Now().Format("2006-01-02")
+	w := do("POST", "/api/v1/out-of-office", fmt.Sprintf(`{"start_date":%q,"note":"Public holiday"}`, today))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data models.OutOfOffice `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.Data.Username != "testuser" || !created.Data.EndDate.Equal(created.Data.StartDate) {
+		t.Errorf("Expected a one day range for testuser, got %+v", created.Data)
+	}
+
+	for _, body := range []string{`{"start_date":"someday"}`, `{"start_date":"2025-12-10","end_date":"2025-12-01"}`} {
+		if w := do("POST", "/api/v1/out-of-office", body); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("POST %s: expected status 422, got %d", body, w.Code)
+		}
+	}
+
+	for _, path := range []string{"/api/v1/out-of-office", "/api/v1/out-of-office/team?week=" + today} {
+		w := do("GET", path, "")
+		var listed struct {
+			Data []models.OutOfOffice `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &listed)
+		if w.Code != http.StatusOK || len(listed.Data) != 1 || listed.Data[0].ID != created.Data.ID {
+			t.Errorf("GET %s: expected the holiday, got %d: %s", path, w.Code, w.Body.String())
+		}
+	}
+
+	path := fmt.Sprintf("/api/v1/out-of-office/%d", created.Data.ID)
+	if w := do("DELETE", path, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("DELETE", path, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 deleting it again, got %d", w.Code)
+	}
+}
+
+func TestTaskJobSheetEndpoint(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	task, err := testData.TaskService.CreateTask("Service the generator")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	req := newAuthenticatedRequest("GET", fmt.Sprintf("/api/v1/tasks/%d/job-sheet", task.ID), nil, testData.APIKey)
+	w := httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/pdf" {
+		t.Errorf("Expected application/pdf, got %q", ct)
+	}
+	if !strings.HasPrefix(w.Body.String(), "%PDF-") || !strings.Contains(w.Body.String(), "/s/") {
+		t.Errorf("Expected a PDF with the task's short link")
+	}
+
+	req = newAuthenticatedRequest("GET", "/api/v1/tasks/9999/job-sheet", nil, testData.APIKey)
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing task, got %d", w.Code)
+	}
+}
+
+func TestTaskShortLinks(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	task, err := testData.TaskService.CreateTask("Inspect the fire panel")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	req := newAuthenticatedRequest("GET", fmt.Sprintf("/api/v1/tasks/%d/short-link", task.ID), nil, testData.APIKey)
+	w := httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	var link struct {
+		Data struct {
+			Code string `json:"code"`
+			URL  string `json:"url"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &link)
+	if w.Code != http.StatusOK || link.Data.Code == "" || !strings.HasSuffix(link.Data.URL, "/s/"+link.Data.Code) {
+		t.Fatalf("Expected a short link, got %d: %s", w.Code, w.Body.String())
+	}
+
+	shortPath := "/s/" + link.Data.Code
+
+	// Visitors who are not signed in are sent to log in and then come back
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, httptest.NewRequest("GET", shortPath, nil))
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/login?next="+url.QueryEscape(shortPath) {
+		t.Errorf("Expected a redirect to the login page, got %d to %q", w.Code, w.Header().Get("Location"))
+	}
+
+	result, err := testData.AuthService.Login(&services.LoginRequest{Username: "testuser", Password: "testpassword"})
+	if err != nil {
+		t.Fatalf("Failed to log in: %v", err)
+	}
+	visit := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.AddCookie(&http.Cookie{Name: "session_token", Value: result.Session.Token})
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := visit(shortPath); w.Code != http.StatusFound || w.Header().Get("Location") != fmt.Sprintf("/app?task=%d", task.ID) {
+		t.Errorf("Expected a redirect to the task, got %d to %q", w.Code, w.Header().Get("Location"))
+	}
+	if w := visit("/s/unknown1"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown code, got %d", w.Code)
+	}
+
+	// With path tenancy, /t/ belongs to tenants, and short links still reach the main instance
+	tenants := middleware.Tenants("path", "", testData.Handler, func(slug string) (http.Handler, bool) { return nil, false })
+	req = httptest.NewRequest("GET", shortPath, nil)
+	req.AddCookie(&http.Cookie{Name: "session_token", Value: result.Session.Token})
+	w = httptest.NewRecorder()
+	tenants.ServeHTTP(w, req)
+	if w.Code != http.StatusFound || w.Header().Get("Location") != fmt.Sprintf("/app?task=%d", task.ID) {
+		t.Errorf("Expected a redirect to the task with tenancy enabled, got %d to %q", w.Code, w.Header().Get("Location"))
+	}
+
+	// Once signed in, the login page continues to the short link, but never off-site
+	if w := visit("/login?next=" + url.QueryEscape(shortPath)); w.Header().Get("Location") != shortPath {
+		t.Errorf("Expected the login page to continue to the short link, got %q", w.Header().Get("Location"))
+	}
+	if w := visit("/login?next=//evil.example.com"); w.Header().Get("Location") != "/" {
+		t.Errorf("Expected an off-site next to be ignored, got %q", w.Header().Get("Location"))
+	}
+}
+
+func TestAssetEndpoints(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req := newAuthenticatedRequest(method, path, reader, testData.APIKey)
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("POST", "/api/v1/assets", `{"name":""}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 without a name, got %d", w.Code)
+	}
+	w := do("POST", "/api/v1/assets", `{"name":"UPS","serial":"APC-1","location":"Closet"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data models.Asset `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	assetPath := fmt.Sprintf("/api/v1/assets/%d", created.Data.ID)
+
+	if w := do("PUT", assetPath, `{"name":"UPS","serial":"APC-1","location":"Basement"}`); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Basement") {
+		t.Errorf("Expected the asset to be updated, got %d: %s", w.Code, w.Body.String())
+	}
+
+	task, _ := testData.TaskService.CreateTask("Replace UPS battery")
+	taskAssets := fmt.Sprintf("/api/v1/tasks/%d/assets", task.ID)
+	if w := do("POST", taskAssets, fmt.Sprintf(`{"asset_id":%d}`, created.Data.ID)); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 linking the task, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("POST", taskAssets, `{"asset_id":9999}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 linking a missing asset, got %d", w.Code)
+	}
+	if w := do("POST", "/api/v1/tasks/9999/assets", fmt.Sprintf(`{"asset_id":%d}`, created.Data.ID)); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 linking a missing task, got %d", w.Code)
+	}
+
+	w = do("GET", assetPath, "")
+	var detail struct {
+		Data models.Asset `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &detail)
+	if w.Code != http.StatusOK || len(detail.Data.Tasks) != 1 || detail.Data.Tasks[0].ID != task.ID {
+		t.Errorf("Expected the asset's task history, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("GET", taskAssets, ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"UPS"`) {
+		t.Errorf("Expected the task's assets, got %d: %s", w.Code, w.Body.String())
+	}
+
+	unlink := fmt.Sprintf("%s/%d", taskAssets, created.Data.ID)
+	if w := do("DELETE", unlink, ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 unlinking, got %d", w.Code)
+	}
+	if w := do("DELETE", unlink, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 unlinking again, got %d", w.Code)
+	}
+
+	if w := do("DELETE", assetPath, ""); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 deleting the asset, got %d", w.Code)
+	}
+	if w := do("GET", assetPath, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after deleting, got %d", w.Code)
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	w := httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 without authentication, got %d: %s", w.Code, w.Body.String())
+	}
+	var spec openapi.Document
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("Failed to decode the spec: %v", err)
+	}
+	if spec.OpenAPI != openapi.Version || len(spec.Servers) != 1 || spec.Servers[0].URL != "/" {
+		t.Errorf("Expected an OpenAPI %s document served from /, got %q and %+v", openapi.Version, spec.OpenAPI, spec.Servers)
+	}
+	if op := spec.Paths["/api/v1/tasks/{id}"]["get"]; op == nil || op.OperationID != "GetTask" {
+		t.Errorf("Expected GET /api/v1/tasks/{id} to be described, got %+v", op)
+	}
+
+	// Every API route is in the spec, documented by its handler's doc comment
+	operations := 0
+	for _, route := range testData.Handler.(*gin.Engine).Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/") {
+			continue
+		}
+		operations++
+		if _, ok := api.HandlerDocs[openapi.Key(route.Method, route.Path)]; !ok {
+			t.Errorf("%s %s has no doc comment saying which route it handles", route.Method, route.Path)
+		}
+	}
+	described := 0
+	for _, item := range spec.Paths {
+		described += len(item)
+	}
+	if described != operations {
+		t.Errorf("Expected all %d API routes in the spec, got %d", operations, described)
+	}
+
+	w = httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/docs", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "swagger-ui") || !strings.Contains(w.Body.String(), "/api/v1/openapi.json") {
+		t.Errorf("Expected Swagger UI for the spec, got %d", w.Code)
+	}
+}
+
+func TestTaskContexts(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest(method, path, strings.NewReader(body), testData.APIKey)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+	var created struct {
+		Data models.Task `json:"data"`
+	}
+
+	w := send("POST", "/api/v1/tasks", `{"name":"Swap failed disk","tags":["client1"],"context":"DataCenter"}`)
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if w.Code != http.StatusCreated || created.Data.Context != "@datacenter" || len(created.Data.Tags) != 1 {
+		t.Fatalf("Expected a task in @datacenter, got %d: %s", w.Code, w.Body.String())
+	}
+	disk := created.Data.ID
+	send("POST", "/api/v1/tasks", `{"name":"Call the printer vendor","context":"@phone"}`)
+	send("POST", "/api/v1/tasks", `{"name":"Write the report"}`)
+
+	list := func(context string) []string {
+		w := send("GET", "/api/v1/tasks?context="+url.QueryEscape(context), "")
+		var resp struct {
+			Data struct {
+				Items []models.Task `json:"items"`
+			} `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		var names []string
+		for _, task := range resp.Data.Items {
+			names = append(names, task.Name)
+		}
+		return names
+	}
+	if names := list("@datacenter"); len(names) != 1 || names[0] != "Swap failed disk" {
+		t.Errorf("Expected only the disk swap in @datacenter, got %v", names)
+	}
+	if names := list("none"); len(names) != 1 || names[0] != "Write the report" {
+		t.Errorf("Expected only the report without a context, got %v", names)
+	}
+
+	w = send("GET", "/api/v1/contexts", "")
+	var contexts struct {
+		Data []repository.ContextCount `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &contexts)
+	if w.Code != http.StatusOK || len(contexts.Data) != 2 || contexts.Data[0].Context != "@datacenter" || contexts.Data[0].OpenTasks != 1 {
+		t.Errorf("Expected @datacenter and @phone, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Contexts are not tags, and null clears one
+	if w := send("PATCH", fmt.Sprintf("/api/v1/tasks/%d", disk), `{"context":null}`); w.Code != http.StatusOK {
+		t.Fatalf("Failed to clear the context: %d %s", w.Code, w.Body.String())
+	}
+	task, _ := testData.TaskService.GetTask(disk)
+	if task.Context != "" || len(task.Tags) != 1 || task.Tags[0] != "client1" {
+		t.Errorf("Expected the context cleared and the tags kept, got %q and %v", task.Context, task.Tags)
+	}
+}
+
+func TestTaskSizesAndVelocity(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest(method, path, strings.NewReader(body), testData.APIKey)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+	var created struct {
+		Data models.Task `json:"data"`
+	}
+
+	w := send("POST", "/api/v1/tasks", `{"name":"Replace core switch","size":"m"}`)
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if w.Code != http.StatusCreated || created.Data.Size != models.TaskSizeM {
+		t.Fatalf("Expected a task of size M, got %d: %s", w.Code, w.Body.String())
+	}
+	sw := created.Data.ID
+	if w := send("POST", "/api/v1/tasks", `{"name":"Too big","size":"XXL"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected an invalid size to be rejected, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Story points may be sent as a number
+	w = send("POST", "/api/v1/tasks", `{"name":"Update firmware"}`)
+	json.Unmarshal(w.Body.Bytes(), &created)
+	firmware := created.Data.ID
+	if w := send("PATCH", fmt.Sprintf("/api/v1/tasks/%d", firmware), `{"size":5}`); w.Code != http.StatusOK {
+		t.Fatalf("Failed to size the task: %d %s", w.Code, w.Body.String())
+	}
+	if w := send("PATCH", fmt.Sprintf("/api/v1/tasks/%d", firmware), `{"size":500}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 500 points to be rejected, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = send("GET", "/api/v1/reports/sizes", "")
+	var sizes struct {
+		Data services.SizeBreakdown `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &sizes)
+	if w.Code != http.StatusOK || sizes.Data.OpenPoints != 8 || len(sizes.Data.Sizes) != 2 {
+		t.Errorf("Expected 8 open points over two sizes, got %d: %s", w.Code, w.Body.String())
+	}
+
+	send("PATCH", fmt.Sprintf("/api/v1/tasks/%d", sw), `{"status":"resolved"}`)
+	w = send("GET", "/api/v1/reports/velocity?weeks=4", "")
+	var velocity struct {
+		Data services.Velocity `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &velocity)
+	if w.Code != http.StatusOK || len(velocity.Data.Weeks) != 4 || velocity.Data.Weeks[3].Points != 3 {
+		t.Errorf("Expected 3 points this week, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := send("GET", "/api/v1/reports/velocity?weeks=0", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected invalid weeks to be rejected, got %d", w.Code)
+	}
+}
+
+func TestProjects(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest(method, path, strings.NewReader(body), testData.APIKey)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("POST", "/api/v1/projects", `{"name":"Acme","description":"Managed services for Acme"}`)
+	var project struct {
+		Data models.Project `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &project)
+	if w.Code != http.StatusCreated || project.Data.ID == 0 {
+		t.Fatalf("Failed to create project: %d %s", w.Code, w.Body.String())
+	}
+	acme := project.Data.ID
+	if w := send("POST", "/api/v1/projects", `{"name":"ACME"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected a duplicate name to be rejected, got %d", w.Code)
+	}
+
+	var created struct {
+		Data models.Task `json:"data"`
+	}
+	w = send("POST", "/api/v1/tasks", fmt.Sprintf(`{"name":"Replace Acme firewall","project_id":%d}`, acme))
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if w.Code != http.StatusCreated || created.Data.ProjectID == nil || *created.Data.ProjectID != acme {
+		t.Fatalf("Expected a task in the Acme project, got %d: %s", w.Code, w.Body.String())
+	}
+	firewall := created.Data.ID
+	w = send("POST", "/api/v1/tasks", `{"name":"Tidy the wiki"}`)
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.Data.ProjectID == nil || *created.Data.ProjectID == acme {
+		t.Errorf("Expected a task without a project in the default project, got %v", created.Data.ProjectID)
+	}
+	if w := send("POST", "/api/v1/tasks", `{"name":"Lost","project_id":999}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected an unknown project to be rejected, got %d", w.Code)
+	}
+
+	w = send("GET", fmt.Sprintf("/api/v1/tasks?project=%d", acme), "")
+	var list struct {
+		Data struct {
+			Items []models.Task `json:"items"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list.Data.Items) != 1 || list.Data.Items[0].ID != firewall {
+		t.Errorf("Expected only the firewall task in Acme, got %s", w.Body.String())
+	}
+
+	// Saved queries of a project are listed with those for every project
+	send("POST", "/api/v1/saved-queries", fmt.Sprintf(`{"name":"Acme","project_id":%d}`, acme))
+	send("POST", "/api/v1/saved-queries", `{"name":"Everything"}`)
+	w = send("GET", "/api/v1/projects", "")
+	var projects struct {
+		Data []models.Project `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &projects)
+	if len(projects.Data) != 2 || !projects.Data[0].IsDefault {
+		t.Fatalf("Expected the default project and Acme, got %s", w.Body.String())
+	}
+	defaultID := projects.Data[0].ID
+	w = send("GET", fmt.Sprintf("/api/v1/saved-queries?project=%d", defaultID), "")
+	var queries struct {
+		Data []models.SavedQuery `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &queries)
+	if len(queries.Data) != 1 || queries.Data[0].Name != "Everything" {
+		t.Errorf("Expected only the query for every project, got %s", w.Body.String())
+	}
+
+	// null moves a task back to the default project
+	if w := send("PATCH", fmt.Sprintf("/api/v1/tasks/%d", firewall), `{"project_id":null}`); w.Code != http.StatusOK {
+		t.Fatalf("Failed to move the task: %d %s", w.Code, w.Body.String())
+	}
+	task, _ := testData.TaskService.GetTask(firewall)
+	if task.ProjectID == nil || *task.ProjectID != defaultID {
+		t.Errorf("Expected the task in the default project, got %v", task.ProjectID)
+	}
+
+	if w := send("DELETE", fmt.Sprintf("/api/v1/projects/%d", defaultID), ""); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected the default project to be kept, got %d", w.Code)
+	}
+	if w := send("DELETE", fmt.Sprintf("/api/v1/projects/%d", acme), ""); w.Code != http.StatusNoContent {
+		t.Errorf("Failed to delete project: %d %s", w.Code, w.Body.String())
+	}
+	if w := send("GET", fmt.Sprintf("/api/v1/projects/%d", acme), ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected the deleted project to be gone, got %d", w.Code)
+	}
+}
+
+func TestTaskReviewGate(t *testing.T) {
+	testData := setupTestAPI(t)
+	if err := testData.TaskService.SetReviewPolicies(map[string]config.ReviewConfig{
+		"change": {Reviewers: []string{"testuser"}},
+		"audit":  {Reviewers: []string{"auditor"}},
+	}); err != nil {
+		t.Fatalf("Failed to set review policies: %v", err)
+	}
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest(method, path, strings.NewReader(body), testData.APIKey)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+	var resp struct {
+		Data models.Task `json:"data"`
+	}
+
+	w := send("POST", "/api/v1/tasks", `{"name":"Rotate VPN keys","tags":["change"]}`)
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	id := resp.Data.ID
+	w = send("PATCH", fmt.Sprintf("/api/v1/tasks/%d", id), `{"status":"resolved"}`)
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusOK || resp.Data.Status != models.TaskStatusPendingReview {
+		t.Fatalf("Expected the task pending review, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := send("POST", fmt.Sprintf("/api/v1/tasks/%d/review", id), `{"decision":"maybe"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected an unknown decision to be rejected, got %d", w.Code)
+	}
+	if w := send("POST", fmt.Sprintf("/api/v1/tasks/%d/review", id), `{"decision":"reopen"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected reopening without a comment to be rejected, got %d", w.Code)
+	}
+	w = send("POST", fmt.Sprintf("/api/v1/tasks/%d/review", id), `{"decision":"approve","comment":"Looks good"}`)
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusOK || resp.Data.Status != models.TaskStatusResolved {
+		t.Fatalf("Expected the approved task resolved, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = send("GET", fmt.Sprintf("/api/v1/tasks/%d/reviews", id), "")
+	var reviews struct {
+		Data []models.TaskReview `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &reviews)
+	if len(reviews.Data) != 1 || reviews.Data[0].Reviewer != "testuser" || reviews.Data[0].Decision != models.ReviewApproved {
+		t.Errorf("Expected testuser's approval, got %s", w.Body.String())
+	}
+
+	// Only the reviewers of a task's tags may review it
+	w = send("POST", "/api/v1/tasks", `{"name":"Quarterly access audit","tags":["audit"],"status":"resolved"}`)
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Data.Status != models.TaskStatusPendingReview {
+		t.Fatalf("Expected a task created resolved to wait for review, got %s", resp.Data.Status)
+	}
+	if w := send("POST", fmt.Sprintf("/api/v1/tasks/%d/review", resp.Data.ID), `{"decision":"approve"}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected a non-reviewer to be refused, got %d", w.Code)
+	}
+}
+
+func TestTaskDependencies(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest(method, path, strings.NewReader(body), testData.APIKey)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	blocker, _ := testData.TaskService.CreateTask("Approve budget")
+	task, _ := testData.TaskService.CreateTask("Buy laptops")
+
+	w := send("POST", fmt.Sprintf("/api/v1/tasks/%d/dependencies", task.ID), fmt.Sprintf(`{"blocked_by":%d}`, blocker.ID))
+	var deps struct {
+		Data api.TaskDependencies `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &deps)
+	if w.Code != http.StatusCreated || len(deps.Data.BlockedBy) != 1 || deps.Data.BlockedBy[0].ID != blocker.ID {
+		t.Fatalf("Failed to add dependency: %d %s", w.Code, w.Body.String())
+	}
+	if w := send("POST", fmt.Sprintf("/api/v1/tasks/%d/dependencies", blocker.ID), fmt.Sprintf(`{"blocked_by":%d}`, task.ID)); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected a cycle to be rejected, got %d", w.Code)
+	}
+	if w := send("POST", fmt.Sprintf("/api/v1/tasks/%d/dependencies", task.ID), `{"blocks":999}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected an unknown task to be rejected, got %d", w.Code)
+	}
+
+	w = send("GET", fmt.Sprintf("/api/v1/tasks/%d/dependencies", blocker.ID), "")
+	json.Unmarshal(w.Body.Bytes(), &deps)
+	if len(deps.Data.Blocks) != 1 || deps.Data.Blocks[0].ID != task.ID || len(deps.Data.BlockedBy) != 0 {
+		t.Errorf("Expected the blocker to block the task, got %s", w.Body.String())
+	}
+
+	if w := send("PATCH", fmt.Sprintf("/api/v1/tasks/%d", task.ID), `{"status":"resolved"}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected resolving a blocked task to conflict, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := send("DELETE", fmt.Sprintf("/api/v1/tasks/%d/dependencies/%d", task.ID, blocker.ID), ""); w.Code != http.StatusNoContent {
+		t.Fatalf("Failed to remove dependency: %d %s", w.Code, w.Body.String())
+	}
+	if w := send("PATCH", fmt.Sprintf("/api/v1/tasks/%d", task.ID), `{"status":"resolved"}`); w.Code != http.StatusOK {
+		t.Errorf("Expected the unblocked task to resolve, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGraphQL(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := newAuthenticatedRequest(method, path, strings.NewReader(body), testData.APIKey)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	printer, _ := testData.TaskService.CreateTask("Fix printer")
+	printer.Tags = []string{"hardware"}
+	testData.TaskService.UpdateTask(printer)
+	testData.TaskService.AddComment(printer.ID, &models.Comment{Content: "Toner is low"})
+	testData.TaskService.AddTimeEntry(printer.ID, &models.TimeEntry{Duration: 15})
+	testData.TaskService.CreateTask("Order chairs")
+
+	query := `{"query": "query ($tag: String!) { tasks(tags: [$tag]) { id name comments { content } timeEntries { duration billable } } tags { name count } }", "variables": {"tag": "hardware"}}`
+	w := send("POST", "/api/v1/graphql", query)
+	want := fmt.Sprintf(`{"data":{"tasks":[{"id":"%d","name":"Fix printer","comments":[{"content":"Toner is low"}],"timeEntries":[{"duration":15,"billable":true}]}],"tags":[{"name":"hardware","count":1}]}}`, printer.ID)
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != want {
+		t.Errorf("Unexpected response %d:\n%s\nwant\n%s", w.Code, w.Body.String(), want)
+	}
+
+	w = send("POST", "/api/v1/graphql", `{"query": "{ task(id: 999) { name } }"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"data":{"task":null}`) || !strings.Contains(w.Body.String(), "task not found") {
+		t.Errorf("Expected a null task with an error, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := send("POST", "/api/v1/graphql", `{"query": "{ tasks { secret } }"}`); w.Code != http.StatusBadRequest || strings.Contains(w.Body.String(), `"data"`) {
+		t.Errorf("Expected an invalid query to be rejected, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = send("GET", "/api/v1/graphql/schema", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "type Task {") {
+		t.Errorf("Expected the schema, got %d %s", w.Code, w.Body.String())
+	}
+
+	// Clients can discover the schema with introspection
+	w = send("POST", "/api/v1/graphql", `{"query": "{ __schema { queryType { name } } __type(name: \"TimeEntry\") { fields { name } } }"}`)
+	want = `{"data":{"__schema":{"queryType":{"name":"Query"}},"__type":{"fields":[{"name":"id"},{"name":"description"},{"name":"duration"},{"name":"createdBy"},{"name":"billable"},{"name":"createdAt"}]}}}`
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != want {
+		t.Errorf("Unexpected introspection response %d:\n%s\nwant\n%s", w.Code, w.Body.String(), want)
+	}
+
+	// Operations are picked by name, and fragments spread into selections
+	w = send("POST", "/api/v1/graphql", `{"query": "query Both { tags { ...TagFields } } query Named { task(id: \"`+fmt.Sprint(printer.ID)+`\") { name tags } } fragment TagFields on Tag { name tasks { name } }", "operationName": "Both"}`)
+	want = `{"data":{"tags":[{"name":"hardware","tasks":[{"name":"Fix printer"}]}]}}`
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != want {
+		t.Errorf("Unexpected response %d:\n%s\nwant\n%s", w.Code, w.Body.String(), want)
+	}
+}
+
+func TestAPIRequiresCSRFWithSessionCookie(t *testing.T) {
+	testData := setupTestAPI(t)
+
+	result, err := testData.AuthService.Login(&services.LoginRequest{Username: "testuser", Password: "testpassword"})
+	if err != nil {
+		t.Fatalf("Failed to log in: %v", err)
+	}
+	create := func(csrfToken string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/tasks", strings.NewReader(`{"name": "From the browser"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(&http.Cookie{Name: "session_token", Value: result.Session.Token})
+		if csrfToken != "" {
+			req.Header.Set(middleware.CSRFHeader, csrfToken)
+		}
+		w := httptest.NewRecorder()
+		testData.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := create(""); w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "CSRF_TOKEN_INVALID") {
+		t.Errorf("Expected a cookie request without the CSRF token to be rejected, got %d %s", w.Code, w.Body.String())
+	}
+	if w := create("forged"); w.Code != http.StatusForbidden {
+		t.Errorf("Expected a wrong CSRF token to be rejected, got %d", w.Code)
+	}
+	if w := create(middleware.CSRFToken(result.Session.Token)); w.Code != http.StatusCreated {
+		t.Errorf("Expected the session's CSRF token to be accepted, got %d %s", w.Code, w.Body.String())
+	}
+
+	// API keys need no CSRF token
+	req := newAuthenticatedRequest("POST", "/api/v1/tasks", strings.NewReader(`{"name": "From a script"}`), testData.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	testData.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected an API key request to pass without a CSRF token, got %d %s", w.Code, w.Body.String())
+	}
+}
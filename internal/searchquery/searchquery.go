@@ -0,0 +1,311 @@
+// Package searchquery parses the search syntax shared by the task list search
+// parameter, the web search box, the TUI's / search and the CLI, e.g.
+// `tag:client1 status:open priority:high "exact phrase" -tag:internal created<2025-09-01`.
+package searchquery
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+// Query is a parsed search
+type Query struct {
+	Terms        []string // words and phrases that must all appear
+	ExcludeTerms []string // words and phrases that must not appear
+
+	Tags        [][]string // each group needs one of its tags
+	ExcludeTags []string
+
+	Status          []models.TaskStatus // any of
+	ExcludeStatus   []models.TaskStatus
+	Priority        []models.TaskPriority // any of
+	ExcludePriority []models.TaskPriority
+
+	Milestone string   // milestone ID, or "none" for tasks without one
+	In        []string // fields the terms are searched in; empty means all
+
+	Dates []DateCondition
+}
+
+// DateCondition compares one of a task's dates with a day, e.g. created<2025-09-01
+type DateCondition struct {
+	Field string    // created, updated or resolved
+	Op    string    // <, <=, >, >=, = or != (from a negated =)
+	Day   time.Time // start of the day, local time
+}
+
+// dateFields maps the date fields of the syntax to the task's dates
+var dateFields = map[string]func(task *models.Task) *time.Time{
+	"created":  func(task *models.Task) *time.Time { return &task.CreatedAt },
+	"updated":  func(task *models.Task) *time.Time { return &task.UpdatedAt },
+	"resolved": func(task *models.Task) *time.Time { return task.ResolvedAt },
+}
+
+var comparisonPattern = regexp.MustCompile(`^([a-z_]+)(<=|>=|<|>|=)(.+)$`)
+
+// Parse parses a search. Supported syntax, all combined with AND:
+//
+//	word, "exact phrase"        text that must appear (see In)
+//	tag:client1                 tasks with the tag; tag:a,b for either
+//	status:open                 tasks in the status; status:open,in-progress for either
+//	priority:high               tasks with the priority
+//	milestone:3                 tasks on milestone 3; milestone:none for none
+//	in:comments                 search text only in these fields
+//	created<2025-09-01          created before the day; also <=, >, >=, = and
+//	                            the fields updated and resolved
+//
+// A leading "-" negates a word, phrase, tag, status, priority or date. Dates
+// accept the same formats as -d, e.g. created>-7d. Words with an unknown
+// key:value are searched as text, so URLs still work.
+func Parse(input string) (*Query, error) {
+	q := &Query{}
+	for _, token := range tokenize(input) {
+		if err := q.add(token); err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+// IsZero reports whether the query has no conditions at all
+func (q *Query) IsZero() bool {
+	return len(q.Terms) == 0 && len(q.ExcludeTerms) == 0 && len(q.Tags) == 0 && len(q.ExcludeTags) == 0 &&
+		len(q.Status) == 0 && len(q.ExcludeStatus) == 0 && len(q.Priority) == 0 && len(q.ExcludePriority) == 0 &&
+		q.Milestone == "" && len(q.Dates) == 0
+}
+
+// HasText reports whether the query has words or phrases to search for
+func (q *Query) HasText() bool {
+	return len(q.Terms) > 0 || len(q.ExcludeTerms) > 0
+}
+
+// MatchesFilters reports whether a task passes everything but the text terms,
+// which need the task's comments and time entries to check
+func (q *Query) MatchesFilters(task *models.Task) bool {
+	for _, group := range q.Tags {
+		if !slices.ContainsFunc(group, func(tag string) bool { return slices.Contains(task.Tags, tag) }) {
+			return false
+		}
+	}
+	for _, tag := range q.ExcludeTags {
+		if slices.Contains(task.Tags, tag) {
+			return false
+		}
+	}
+	if len(q.Status) > 0 && !slices.Contains(q.Status, task.Status) {
+		return false
+	}
+	if slices.Contains(q.ExcludeStatus, task.Status) {
+		return false
+	}
+	if len(q.Priority) > 0 && !slices.Contains(q.Priority, task.Priority) {
+		return false
+	}
+	if slices.Contains(q.ExcludePriority, task.Priority) {
+		return false
+	}
+
+	switch q.Milestone {
+	case "":
+	case "none":
+		if task.MilestoneID != nil {
+			return false
+		}
+	default:
+		if task.MilestoneID == nil || fmt.Sprint(*task.MilestoneID) != q.Milestone {
+			return false
+		}
+	}
+
+	for _, cond := range q.Dates {
+		if !cond.matches(task) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether the task's date meets the condition; tasks without
+// the date (e.g. never resolved) never do
+func (c DateCondition) matches(task *models.Task) bool {
+	date := dateFields[c.Field](task)
+	if date == nil {
+		return false
+	}
+	nextDay := c.Day.AddDate(0, 0, 1)
+	switch c.Op {
+	case "<":
+		return date.Before(c.Day)
+	case "<=":
+		return date.Before(nextDay)
+	case ">":
+		return !date.Before(nextDay)
+	case ">=":
+		return !date.Before(c.Day)
+	case "!=":
+		return date.Before(c.Day) || !date.Before(nextDay)
+	default:
+		return !date.Before(c.Day) && date.Before(nextDay)
+	}
+}
+
+// token is a word of the input, with quotes removed
+type token struct {
+	text    string
+	negated bool
+	quoted  bool // the whole word was a quoted phrase
+}
+
+// tokenize splits input on whitespace outside double quotes
+func tokenize(input string) []token {
+	var tokens []token
+	var current strings.Builder
+	inQuotes, quoted, negated := false, false, false
+
+	flush := func() {
+		text := current.String()
+		if !quoted && strings.HasPrefix(text, "-") && len(text) > 1 {
+			negated = true
+			text = text[1:]
+		}
+		if text != "" {
+			tokens = append(tokens, token{text: text, negated: negated, quoted: quoted})
+		}
+		current.Reset()
+		quoted, negated = false, false
+	}
+
+	for _, r := range input {
+		switch {
+		case r == '"':
+			// A quote at the start of a word (after an optional -) makes it a phrase
+			if !inQuotes && !quoted && (current.Len() == 0 || current.String() == "-") {
+				quoted = true
+				negated = current.Len() > 0
+				current.Reset()
+			}
+			inQuotes = !inQuotes
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n'):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// add applies one token to the query
+func (q *Query) add(t token) error {
+	if t.quoted {
+		q.addTerm(t.text, t.negated)
+		return nil
+	}
+
+	if m := comparisonPattern.FindStringSubmatch(t.text); m != nil {
+		field, op, value := m[1], m[2], m[3]
+		day, err := utils.ParseDate(value)
+		if _, ok := dateFields[field]; !ok {
+			// Only an unknown field compared with a date is a mistake; the rest is text
+			if err == nil && op != "=" {
+				return fmt.Errorf("unknown date field %q (use created, updated or resolved)", field)
+			}
+			q.addTerm(t.text, t.negated)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid date in %q: %w", t.text, err)
+		}
+		if t.negated {
+			op = negatedOps[op]
+		}
+		year, month, date := day.Date()
+		q.Dates = append(q.Dates, DateCondition{Field: field, Op: op, Day: time.Date(year, month, date, 0, 0, 0, 0, time.Local)})
+		return nil
+	}
+
+	key, value, _ := strings.Cut(t.text, ":")
+	values := splitValues(value)
+	if len(values) == 0 {
+		q.addTerm(t.text, t.negated)
+		return nil
+	}
+
+	switch strings.ToLower(key) {
+	case "tag", "tags":
+		if t.negated {
+			q.ExcludeTags = append(q.ExcludeTags, values...)
+		} else {
+			q.Tags = append(q.Tags, values)
+		}
+	case "status":
+		for _, v := range values {
+			status := models.TaskStatus(strings.ToLower(v))
+			switch status {
+			case models.TaskStatusOpen, models.TaskStatusInProgress, models.TaskStatusPendingReview, models.TaskStatusResolved, models.TaskStatusClosed:
+			default:
+				return fmt.Errorf("invalid status %q (use open, in-progress, pending-review, resolved or closed)", v)
+			}
+			if t.negated {
+				q.ExcludeStatus = append(q.ExcludeStatus, status)
+			} else {
+				q.Status = append(q.Status, status)
+			}
+		}
+	case "priority":
+		for _, v := range values {
+			priority := models.TaskPriority(strings.ToLower(v))
+			switch priority {
+			case models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh:
+			default:
+				return fmt.Errorf("invalid priority %q (use low, medium or high)", v)
+			}
+			if t.negated {
+				q.ExcludePriority = append(q.ExcludePriority, priority)
+			} else {
+				q.Priority = append(q.Priority, priority)
+			}
+		}
+	case "milestone":
+		if t.negated {
+			return fmt.Errorf("milestone cannot be negated")
+		}
+		q.Milestone = strings.ToLower(value)
+	case "in":
+		if t.negated {
+			return fmt.Errorf("in cannot be negated")
+		}
+		q.In = append(q.In, values...)
+	default:
+		q.addTerm(t.text, t.negated)
+	}
+	return nil
+}
+
+// negatedOps turns a comparison into its opposite, for -created<date
+var negatedOps = map[string]string{"<": ">=", "<=": ">", ">": "<=", ">=": "<", "=": "!="}
+
+func (q *Query) addTerm(text string, negated bool) {
+	if negated {
+		q.ExcludeTerms = append(q.ExcludeTerms, text)
+	} else {
+		q.Terms = append(q.Terms, text)
+	}
+}
+
+// splitValues splits a comma-separated value, dropping empty entries
+func splitValues(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
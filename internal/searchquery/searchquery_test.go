@@ -0,0 +1,97 @@
+package searchquery
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`tag:client1 status:open,in-progress priority:high "paper jam" -tag:internal -"out of scope" printer in:comments milestone:none`)
+	if err != nil {
+		t.Fatalf("Parse unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(q.Terms, []string{"paper jam", "printer"}) {
+		t.Errorf("Terms = %v, want [paper jam printer]", q.Terms)
+	}
+	if !reflect.DeepEqual(q.ExcludeTerms, []string{"out of scope"}) {
+		t.Errorf("ExcludeTerms = %v, want [out of scope]", q.ExcludeTerms)
+	}
+	if !reflect.DeepEqual(q.Tags, [][]string{{"client1"}}) || !reflect.DeepEqual(q.ExcludeTags, []string{"internal"}) {
+		t.Errorf("Tags = %v, ExcludeTags = %v", q.Tags, q.ExcludeTags)
+	}
+	if !reflect.DeepEqual(q.Status, []models.TaskStatus{models.TaskStatusOpen, models.TaskStatusInProgress}) {
+		t.Errorf("Status = %v", q.Status)
+	}
+	if !reflect.DeepEqual(q.Priority, []models.TaskPriority{models.TaskPriorityHigh}) {
+		t.Errorf("Priority = %v", q.Priority)
+	}
+	if q.Milestone != "none" || !reflect.DeepEqual(q.In, []string{"comments"}) {
+		t.Errorf("Milestone = %q, In = %v", q.Milestone, q.In)
+	}
+
+	// Unknown keys and non-date comparisons are plain text
+	q, _ = Parse("https://example.com timeout=30")
+	if !reflect.DeepEqual(q.Terms, []string{"https://example.com", "timeout=30"}) {
+		t.Errorf("Terms = %v, want the words unchanged", q.Terms)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, input := range []string{
+		"status:waiting",
+		"priority:urgent",
+		"-milestone:2",
+		"due<2025-09-01",
+		"created<notadate",
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected an error", input)
+		}
+	}
+}
+
+func TestMatchesFilters(t *testing.T) {
+	resolved := time.Date(2025, 8, 20, 15, 0, 0, 0, time.Local)
+	milestone := uint(3)
+	task := &models.Task{
+		Status:      models.TaskStatusResolved,
+		Priority:    models.TaskPriorityHigh,
+		Tags:        []string{"client1", "email"},
+		MilestoneID: &milestone,
+		CreatedAt:   time.Date(2025, 8, 1, 9, 0, 0, 0, time.Local),
+		ResolvedAt:  &resolved,
+	}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"tag:client1 tag:email", true},
+		{"tag:client2,email", true},
+		{"tag:client1 tag:client2", false},
+		{"-tag:email", false},
+		{"status:resolved,closed -priority:low", true},
+		{"-status:resolved", false},
+		{"milestone:3", true},
+		{"milestone:none", false},
+		{"created<2025-08-02 created>=2025-08-01", true},
+		{"created>2025-08-01", false},
+		{"resolved=2025-08-20", true},
+		{"-resolved=2025-08-20", false},
+		{"resolved<=2025-08-19", false},
+		{"updated>2025-01-01", false}, // zero UpdatedAt
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+		}
+		if got := q.MatchesFilters(task); got != tt.want {
+			t.Errorf("MatchesFilters(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package secrets encrypts sensitive values, such as TOTP secrets, before they
+// are stored in the database
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// prefix marks an encrypted value: "enc:v1:<key id>:<base64 nonce and ciphertext>"
+const prefix = "enc:v1:"
+
+var (
+	ErrNoKey         = errors.New("value is encrypted but no encryption key is configured")
+	ErrUnknownKey    = errors.New("value is encrypted with a key that is not configured")
+	ErrInvalidKey    = errors.New("encryption key must be 32 bytes, base64-encoded")
+	ErrInvalidCipher = errors.New("encrypted value is malformed")
+)
+
+// Keyring encrypts with its primary key and decrypts with any of its keys, so
+// values written before a key rotation stay readable until re-encrypted
+type Keyring struct {
+	primaryID string
+	keys      map[string]cipher.AEAD
+}
+
+// NewKeyring creates a keyring from base64-encoded AES-256 keys. previous are
+// keys retired by a rotation, kept only to decrypt.
+func NewKeyring(primary string, previous ...string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]cipher.AEAD)}
+	for i, encoded := range append([]string{primary}, previous...) {
+		id, aead, err := parseKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			k.primaryID = id
+		}
+		k.keys[id] = aead
+	}
+	return k, nil
+}
+
+// parseKey decodes a key and identifies it by a short hash, stored with the
+// values it encrypts
+func parseKey(encoded string) (string, cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != 32 {
+		return "", nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", nil, err
+	}
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4]), aead, nil
+}
+
+// Encrypt encrypts a value with the primary key. Empty values are left empty,
+// and a nil keyring stores values as they are.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if k == nil || plaintext == "" {
+		return plaintext, nil
+	}
+	aead := k.keys[k.primaryID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.primaryID))
+	return prefix + k.primaryID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value written by Encrypt. Values that were stored before
+// encryption was enabled are returned as they are.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return value, nil
+	}
+	if k == nil {
+		return "", ErrNoKey
+	}
+
+	id, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", ErrInvalidCipher
+	}
+	aead, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w (key %s)", ErrUnknownKey, id)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrInvalidCipher
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", ErrInvalidCipher
+	}
+	return string(plaintext), nil
+}
+
+// Current reports whether a stored value is encrypted with the primary key,
+// so rotation can tell which values still need re-encrypting
+func (k *Keyring) Current(value string) bool {
+	if value == "" {
+		return true
+	}
+	if k == nil {
+		return !strings.HasPrefix(value, prefix)
+	}
+	return strings.HasPrefix(value, prefix+k.primaryID+":")
+}
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+// SetDefault sets the keyring used for encrypted database columns; nil turns
+// encryption off for new values
+func SetDefault(k *Keyring) {
+	defaultKeyring.Store(k)
+}
+
+// Default returns the keyring used for encrypted database columns, nil when
+// encryption is off
+func Default() *Keyring {
+	return defaultKeyring.Load()
+}
@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newKey(t *testing.T) string {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestKeyring(t *testing.T) {
+	oldKey, newKeyValue := newKey(t), newKey(t)
+	old, err := NewKeyring(oldKey)
+	if err != nil {
+		t.Fatalf("Failed to create keyring: %v", err)
+	}
+
+	encrypted, err := old.Encrypt("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !strings.HasPrefix(encrypted, prefix) || strings.Contains(encrypted, "JBSWY3DPEHPK3PXP") {
+		t.Fatalf("Expected an encrypted value, got %q", encrypted)
+	}
+
+	// After a rotation the old key still decrypts, but the value is not current
+	rotated, err := NewKeyring(newKeyValue, oldKey)
+	if err != nil {
+		t.Fatalf("Failed to create keyring: %v", err)
+	}
+	if plaintext, err := rotated.Decrypt(encrypted); err != nil || plaintext != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Expected the old key to decrypt, got %q, %v", plaintext, err)
+	}
+	if rotated.Current(encrypted) {
+		t.Error("Expected a value under the old key not to be current")
+	}
+	reencrypted, _ := rotated.Encrypt("JBSWY3DPEHPK3PXP")
+	if !rotated.Current(reencrypted) {
+		t.Error("Expected a re-encrypted value to be current")
+	}
+
+	// Once the old key is dropped its values can no longer be read
+	fresh, _ := NewKeyring(newKeyValue)
+	if _, err := fresh.Decrypt(encrypted); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey, got %v", err)
+	}
+	var none *Keyring
+	if _, err := none.Decrypt(encrypted); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Expected ErrNoKey, got %v", err)
+	}
+
+	// Values stored before encryption was enabled read as they are
+	if plaintext, err := fresh.Decrypt("legacy"); err != nil || plaintext != "legacy" {
+		t.Errorf("Expected a plaintext value to pass through, got %q, %v", plaintext, err)
+	}
+
+	tampered := encrypted[:len(encrypted)-4] + "AAAA"
+	if _, err := old.Decrypt(tampered); !errors.Is(err, ErrInvalidCipher) {
+		t.Errorf("Expected a tampered value to be rejected, got %v", err)
+	}
+
+	for _, key := range []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := NewKeyring(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Key %q: expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+// ActivityType identifies what happened in an activity event
+type ActivityType string
+
+const (
+	ActivityTaskCreated   ActivityType = "created"
+	ActivityStatusChanged ActivityType = "status_changed"
+	ActivityCommented     ActivityType = "commented"
+	ActivityTimeLogged    ActivityType = "time_logged"
+	ActivityAssigned      ActivityType = "assigned"
+	ActivityReviewed      ActivityType = "reviewed"
+)
+
+// ActivityTypes lists every activity type, in display order
+var ActivityTypes = []ActivityType{ActivityTaskCreated, ActivityStatusChanged, ActivityCommented, ActivityTimeLogged, ActivityAssigned, ActivityReviewed}
+
+// activityExcerptLength caps how much of a note is repeated in the feed
+const activityExcerptLength = 140
+
+// ActivityEvent is a single entry in the global activity feed
+type ActivityEvent struct {
+	Type       ActivityType      `json:"type"`
+	TaskID     uint              `json:"task_id"`
+	TaskName   string            `json:"task_name"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Detail     string            `json:"detail,omitempty"`
+	FromStatus models.TaskStatus `json:"from_status,omitempty"`
+	ToStatus   models.TaskStatus `json:"to_status,omitempty"`
+	Minutes    int               `json:"minutes,omitempty"`
+}
+
+// ActivityFilter narrows the activity feed. Zero values match everything.
+type ActivityFilter struct {
+	Since time.Time
+	Until time.Time
+	// After keeps only events strictly later than this, for following the feed
+	After  time.Time
+	Types  []ActivityType
+	TaskID uint
+	Tag    string
+	// SavedQuery keeps only events on tasks matching the saved query
+	SavedQuery *models.SavedQuery
+	// Mention keeps only events that mention this username as @username: new
+	// tasks whose name or description does, notes and time entry descriptions,
+	// along with assignments to the user. Status changes and reviews never match.
+	Mention string
+}
+
+// DefaultActivityWindow is how far back the feed goes when no start date is given
+const DefaultActivityWindow = 7 * 24 * time.Hour
+
+// ParseActivityRange parses since/until date expressions (see utils.ParseDate) into
+// whole-day bounds, so until is inclusive. An empty since means the last week.
+func ParseActivityRange(since, until string) (time.Time, time.Time, error) {
+	var from, to time.Time
+	if strings.TrimSpace(since) == "" {
+		from = time.Now().Add(-DefaultActivityWindow)
+	} else {
+		parsed, err := utils.ParseDate(since)
+		if err != nil {
+			return from, to, err
+		}
+		from = startOfDay(parsed)
+	}
+
+	if strings.TrimSpace(until) != "" {
+		parsed, err := utils.ParseDate(until)
+		if err != nil {
+			return from, to, err
+		}
+		to = startOfDay(parsed).AddDate(0, 0, 1)
+	}
+
+	return from, to, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (f ActivityFilter) wants(activityType ActivityType) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if t == activityType {
+			return true
+		}
+	}
+	return false
+}
+
+// GetActivity returns what happened across all tasks in the filter's time
+// range, newest first. Events on deleted tasks are left out.
+func (s *TaskService) GetActivity(filter ActivityFilter) ([]ActivityEvent, error) {
+	var events []ActivityEvent
+
+	if filter.After.After(filter.Since) {
+		filter.Since = filter.After
+	}
+
+	if filter.wants(ActivityTaskCreated) {
+		tasks, err := s.repo.GetTasksCreatedBetween(filter.Since, filter.Until)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			events = append(events, ActivityEvent{Type: ActivityTaskCreated, TaskID: task.ID, Timestamp: task.CreatedAt})
+		}
+	}
+
+	if filter.wants(ActivityStatusChanged) && filter.Mention == "" {
+		changes, err := s.repo.GetStatusChangesBetween(filter.Since, filter.Until)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range changes {
+			events = append(events, ActivityEvent{
+				Type:       ActivityStatusChanged,
+				TaskID:     change.TaskID,
+				Timestamp:  change.ChangedAt,
+				Detail:     fmt.Sprintf("%s → %s", change.FromStatus, change.ToStatus),
+				FromStatus: change.FromStatus,
+				ToStatus:   change.ToStatus,
+			})
+		}
+	}
+
+	if filter.wants(ActivityCommented) {
+		comments, err := s.repo.GetCommentsBetween(filter.Since, filter.Until)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			if filter.Mention != "" && !utils.MentionsUser(comment.Content, filter.Mention) {
+				continue
+			}
+			events = append(events, ActivityEvent{
+				Type:      ActivityCommented,
+				TaskID:    comment.TaskID,
+				Timestamp: comment.CreatedAt,
+				Detail:    excerpt(comment.Content, activityExcerptLength),
+			})
+		}
+	}
+
+	if filter.wants(ActivityTimeLogged) {
+		entries, err := s.repo.GetTimeEntriesBetween(filter.Since, filter.Until)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if filter.Mention != "" && !utils.MentionsUser(entry.Description, filter.Mention) {
+				continue
+			}
+			events = append(events, ActivityEvent{
+				Type:      ActivityTimeLogged,
+				TaskID:    entry.TaskID,
+				Timestamp: entry.CreatedAt,
+				Detail:    entry.Description,
+				Minutes:   entry.Duration,
+			})
+		}
+	}
+
+	if filter.wants(ActivityAssigned) {
+		assignments, err := s.repo.GetTaskAssignmentsBetween(filter.Since, filter.Until)
+		if err != nil {
+			return nil, err
+		}
+		for _, assignment := range assignments {
+			if filter.Mention != "" && !strings.EqualFold(assignment.Assignee, filter.Mention) {
+				continue
+			}
+			events = append(events, ActivityEvent{
+				Type:      ActivityAssigned,
+				TaskID:    assignment.TaskID,
+				Timestamp: assignment.AssignedAt,
+				Detail:    fmt.Sprintf("Assigned to %s (%s on %s)", assignment.Assignee, strings.ReplaceAll(assignment.Policy, "_", " "), assignment.Tag),
+			})
+		}
+	}
+
+	if filter.wants(ActivityReviewed) && filter.Mention == "" {
+		reviews, err := s.repo.GetTaskReviewsBetween(filter.Since, filter.Until)
+		if err != nil {
+			return nil, err
+		}
+		for _, review := range reviews {
+			detail := "Approved by " + review.Reviewer
+			if review.Decision == models.ReviewReopened {
+				detail = "Reopened by " + review.Reviewer
+			}
+			if review.Comment != "" {
+				detail += ": " + excerpt(review.Comment, activityExcerptLength)
+			}
+			events = append(events, ActivityEvent{
+				Type:      ActivityReviewed,
+				TaskID:    review.TaskID,
+				Timestamp: review.ReviewedAt,
+				Detail:    detail,
+			})
+		}
+	}
+
+	// Look up the tasks involved to name them and apply the task and tag filters
+	var taskIDs []uint
+	seen := make(map[uint]bool)
+	for _, event := range events {
+		if !seen[event.TaskID] {
+			seen[event.TaskID] = true
+			taskIDs = append(taskIDs, event.TaskID)
+		}
+	}
+	tasks, err := s.repo.GetByIDs(taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	tasksByID := make(map[uint]*models.Task, len(tasks))
+	for _, task := range tasks {
+		tasksByID[task.ID] = task
+	}
+
+	filtered := events[:0]
+	for _, event := range events {
+		task, ok := tasksByID[event.TaskID]
+		if !ok {
+			continue
+		}
+		if filter.TaskID != 0 && task.ID != filter.TaskID {
+			continue
+		}
+		if filter.Tag != "" && !hasTag(task, filter.Tag) {
+			continue
+		}
+		if filter.SavedQuery != nil && !s.matchesSavedQuery(task, filter.SavedQuery) {
+			continue
+		}
+		if !filter.After.IsZero() && !event.Timestamp.After(filter.After) {
+			continue
+		}
+		if filter.Mention != "" && event.Type == ActivityTaskCreated && !utils.MentionsUser(task.Name+"\n"+task.Description, filter.Mention) {
+			continue
+		}
+		event.TaskName = task.Name
+		filtered = append(filtered, event)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Timestamp.After(filtered[j].Timestamp)
+	})
+
+	return filtered, nil
+}
+
+// SortByLastTouched orders tasks by the user's most recent comment, time entry
+// or status change on them. Tasks the user never touched follow, most recently
+// updated first.
+func (s *TaskService) SortByLastTouched(tasks []*models.Task, username string) error {
+	ids, err := s.repo.GetTaskIDsTouchedBy(username)
+	if err != nil {
+		return err
+	}
+
+	rank := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		rank[id] = i
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		ri, touchedI := rank[tasks[i].ID]
+		rj, touchedJ := rank[tasks[j].ID]
+		if touchedI != touchedJ {
+			return touchedI
+		}
+		if touchedI {
+			return ri < rj
+		}
+		return tasks[i].UpdatedAt.After(tasks[j].UpdatedAt)
+	})
+	return nil
+}
+
+func hasTag(task *models.Task, tag string) bool {
+	for _, t := range task.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// excerpt collapses whitespace and shortens s to at most max runes
+func excerpt(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
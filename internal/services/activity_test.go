@@ -0,0 +1,225 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_GetActivity(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	old, _ := service.CreateTaskWithDate("Old task", time.Now().AddDate(0, 0, -30))
+	task, _ := service.CreateTask("Fix login")
+	task.Tags = []string{"auth"}
+	if err := service.UpdateTask(task); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if err := service.AddTimeEntry(task.ID, &models.TimeEntry{Duration: 30, Description: "debugging"}); err != nil {
+		t.Fatalf("Failed to log time: %v", err)
+	}
+	if err := service.AddComment(task.ID, &models.Comment{Content: "Found   the\ncause"}); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+	deleted, _ := service.CreateTask("Deleted task")
+	if err := service.DeleteTask(deleted.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	since, until, err := ParseActivityRange("", "")
+	if err != nil {
+		t.Fatalf("Failed to parse range: %v", err)
+	}
+	events, err := service.GetActivity(ActivityFilter{Since: since, Until: until})
+	if err != nil {
+		t.Fatalf("Failed to get activity: %v", err)
+	}
+
+	counts := make(map[ActivityType]int)
+	for _, event := range events {
+		if event.TaskID == old.ID || event.TaskID == deleted.ID {
+			t.Errorf("Unexpected event for task #%d: %+v", event.TaskID, event)
+		}
+		counts[event.Type]++
+	}
+	if counts[ActivityTaskCreated] != 1 || counts[ActivityStatusChanged] != 1 || counts[ActivityCommented] != 1 || counts[ActivityTimeLogged] != 1 {
+		t.Errorf("Expected one event of each type, got %v", counts)
+	}
+
+	for i := 1; i < len(events); i++ {
+		if events[i].Timestamp.After(events[i-1].Timestamp) {
+			t.Errorf("Expected events newest first")
+		}
+	}
+
+	events, _ = service.GetActivity(ActivityFilter{Since: since, Types: []ActivityType{ActivityCommented}, Tag: "AUTH"})
+	if len(events) != 1 || events[0].Detail != "Found the cause" || events[0].TaskName != "Fix login" {
+		t.Errorf("Expected the note on 'Fix login', got %+v", events)
+	}
+
+	events, _ = service.GetActivity(ActivityFilter{Since: since, Tag: "other"})
+	if len(events) != 0 {
+		t.Errorf("Expected no events for an unused tag, got %d", len(events))
+	}
+}
+
+func TestTaskService_GetActivityFollow(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	since, _, _ := ParseActivityRange("", "")
+	support, _ := service.CreateTask("Printer on fire")
+	support.Tags = []string{"support"}
+	if err := service.UpdateTask(support); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	events, _ := service.GetActivity(ActivityFilter{Since: since})
+	if len(events) != 1 {
+		t.Fatalf("Expected the creation event, got %+v", events)
+	}
+	after := events[0].Timestamp
+
+	time.Sleep(10 * time.Millisecond)
+	other, _ := service.CreateTask("Write docs")
+	if err := service.AddComment(support.ID, &models.Comment{Content: "Extinguisher found"}); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+
+	events, _ = service.GetActivity(ActivityFilter{Since: since, After: after})
+	if len(events) != 2 {
+		t.Errorf("Expected only the two newer events, got %+v", events)
+	}
+
+	query := &models.SavedQuery{Name: "Support", IncludedTags: []string{"support"}}
+	events, _ = service.GetActivity(ActivityFilter{Since: since, After: after, SavedQuery: query})
+	if len(events) != 1 || events[0].Type != ActivityCommented || events[0].TaskID != support.ID {
+		t.Errorf("Expected only the note on the support task, got %+v", events)
+	}
+	for _, event := range events {
+		if event.TaskID == other.ID {
+			t.Errorf("Unexpected event on a task outside the saved query: %+v", event)
+		}
+	}
+}
+
+func TestTaskService_GetActivityMentions(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	since, _, _ := ParseActivityRange("", "")
+	assigned, _ := service.CreateTask("Renew certificates")
+	assigned.Description = "@Alice please handle this"
+	if err := service.UpdateTask(assigned); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	other, _ := service.CreateTask("Email alice@example.com")
+	if err := service.AddComment(other.ID, &models.Comment{Content: "cc @alice"}); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+	if err := service.AddComment(other.ID, &models.Comment{Content: "ask @alicia"}); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+	if err := service.AddTimeEntry(other.ID, &models.TimeEntry{Duration: 15, Description: "call with @alice"}); err != nil {
+		t.Fatalf("Failed to log time: %v", err)
+	}
+	db.Create(&models.TaskAssignment{TaskID: other.ID, Assignee: "Alice", Tag: "network", Policy: AssignmentRoundRobin, AssignedAt: time.Now()})
+	db.Create(&models.TaskAssignment{TaskID: other.ID, Assignee: "bob", Tag: "network", Policy: AssignmentRoundRobin, AssignedAt: time.Now()})
+
+	events, err := service.GetActivity(ActivityFilter{Since: since, Mention: "alice"})
+	if err != nil {
+		t.Fatalf("Failed to get activity: %v", err)
+	}
+	counts := make(map[ActivityType]int)
+	for _, event := range events {
+		counts[event.Type]++
+		if event.Type == ActivityTaskCreated && event.TaskID != assigned.ID {
+			t.Errorf("Unexpected creation event for task #%d", event.TaskID)
+		}
+	}
+	if len(events) != 4 || counts[ActivityTaskCreated] != 1 || counts[ActivityCommented] != 1 || counts[ActivityTimeLogged] != 1 || counts[ActivityAssigned] != 1 {
+		t.Errorf("Expected the new task, note and time entry mentioning @alice and the assignment to alice, got %+v", events)
+	}
+}
+
+func TestParseActivityRange(t *testing.T) {
+	since, until, err := ParseActivityRange("2025-03-10", "2025-03-12")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if since.Format("2006-01-02 15:04") != "2025-03-10 00:00" {
+		t.Errorf("Expected since at the start of the day, got %v", since)
+	}
+	if until.Format("2006-01-02 15:04") != "2025-03-13 00:00" {
+		t.Errorf("Expected until to include the whole end day, got %v", until)
+	}
+
+	if _, _, err := ParseActivityRange("whenever", ""); err == nil {
+		t.Error("Expected an error for an invalid date")
+	}
+}
+
+func TestTaskService_SortByLastTouched(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	commented, _ := service.CreateTask("Commented")
+	logged, _ := service.CreateTask("Logged")
+	moved, _ := service.CreateTask("Moved")
+	untouched, _ := service.CreateTask("Untouched")
+	others, _ := service.CreateTask("Someone else's")
+
+	if err := service.AddComment(commented.ID, &models.Comment{Content: "Looking", CreatedBy: "alice"}); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+	if err := service.AddTimeEntry(logged.ID, &models.TimeEntry{Duration: 15, CreatedBy: "alice"}); err != nil {
+		t.Fatalf("Failed to log time: %v", err)
+	}
+	moved.Status = models.TaskStatusResolved
+	moved.ChangedBy = "alice"
+	if err := service.UpdateTask(moved); err != nil {
+		t.Fatalf("Failed to resolve task: %v", err)
+	}
+	if err := service.AddComment(others.ID, &models.Comment{Content: "Mine", CreatedBy: "bob"}); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+
+	// Pin the interaction times: the comment is the latest, the status change the oldest
+	now := time.Now()
+	db.Model(&models.Comment{}).Where("task_id = ?", commented.ID).Update("updated_at", now.Add(-time.Minute))
+	db.Model(&models.TimeEntry{}).Where("task_id = ?", logged.ID).Update("updated_at", now.Add(-time.Hour))
+	db.Model(&models.TaskStatusChange{}).Where("task_id = ?", logged.ID).Update("changed_at", now.Add(-time.Hour))
+	db.Model(&models.TaskStatusChange{}).Where("task_id = ?", moved.ID).Update("changed_at", now.Add(-2*time.Hour))
+
+	// Logging time moved the task to in progress on alice's behalf
+	history, _ := service.GetStatusHistory(logged.ID)
+	if len(history) != 1 || history[0].ChangedBy != "alice" {
+		t.Errorf("Expected the automatic status change to be attributed to alice, got %+v", history)
+	}
+
+	tasks, err := service.GetTasks()
+	if err != nil {
+		t.Fatalf("Failed to get tasks: %v", err)
+	}
+	if err := service.SortByLastTouched(tasks, "alice"); err != nil {
+		t.Fatalf("SortByLastTouched failed: %v", err)
+	}
+
+	got := make([]uint, len(tasks))
+	for i, task := range tasks {
+		got[i] = task.ID
+	}
+	// Tasks alice never touched keep following, most recently updated first
+	want := []uint{commented.ID, logged.ID, moved.ID}
+	if len(got) != 5 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("Expected %v first, got %v", want, got)
+	}
+	for _, id := range got[3:] {
+		if id != untouched.ID && id != others.ID {
+			t.Errorf("Expected the untouched tasks last, got %v", got)
+		}
+	}
+}
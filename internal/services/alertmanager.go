@@ -0,0 +1,199 @@
+package services
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// AlertmanagerChannel is the inbound channel name of the Alertmanager receiver
+const AlertmanagerChannel = "alertmanager"
+
+// AlertmanagerWebhook is the payload Prometheus Alertmanager sends to webhook receivers
+type AlertmanagerWebhook struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int                 `json:"truncatedAlerts"`
+	Status            string              `json:"status"` // firing or resolved
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert is one alert of an Alertmanager group
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// SetAlertmanager enables the Alertmanager receiver; an empty secret leaves it disabled
+func (s *InboundService) SetAlertmanager(cfg config.AlertmanagerConfig) error {
+	for severity, mapping := range cfg.Severity {
+		switch models.TaskPriority(mapping.Priority) {
+		case "", models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh:
+		default:
+			return fmt.Errorf("alertmanager severity %q: priority must be low, medium or high", severity)
+		}
+	}
+	s.alertmanager = cfg
+	return nil
+}
+
+// AlertmanagerResult reports what a notification did to each of its alerts, in
+// the order Alertmanager listed them
+type AlertmanagerResult struct {
+	Alerts []*InboundResult `json:"alerts"`
+}
+
+// Created reports whether the notification opened any task
+func (r *AlertmanagerResult) Created() bool {
+	for _, alert := range r.Alerts {
+		if alert.Action == InboundActionCreated {
+			return true
+		}
+	}
+	return false
+}
+
+// ReceiveAlertmanager applies an Alertmanager notification alert by alert,
+// keyed on each alert's fingerprint: a new firing alert opens a task, a repeat
+// adds a comment, a resolved alert resolves its task and an alert firing again
+// reopens it
+func (s *InboundService) ReceiveAlertmanager(secret string, webhook *AlertmanagerWebhook) (*AlertmanagerResult, error) {
+	if s.alertmanager.Secret == "" {
+		return nil, ErrInboundChannelNotFound
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(s.alertmanager.Secret)) != 1 {
+		return nil, ErrInboundUnauthorized
+	}
+	for i, alert := range webhook.Alerts {
+		if alert.Fingerprint == "" {
+			return nil, fmt.Errorf("%w: alerts[%d]: fingerprint is required", ErrInvalidInboundPayload, i)
+		}
+	}
+
+	result := &AlertmanagerResult{Alerts: make([]*InboundResult, 0, len(webhook.Alerts))}
+	for i := range webhook.Alerts {
+		alertResult, err := s.receiveAlert(webhook, &webhook.Alerts[i])
+		if err != nil {
+			return nil, err
+		}
+		result.Alerts = append(result.Alerts, alertResult)
+	}
+	return result, nil
+}
+
+// receiveAlert applies one alert of a notification to the task its fingerprint opened
+func (s *InboundService) receiveAlert(webhook *AlertmanagerWebhook, alert *AlertmanagerAlert) (*InboundResult, error) {
+	inboundKey := AlertmanagerChannel + ":" + alert.Fingerprint
+	existing, _ := s.taskService.GetTaskByInboundKey(inboundKey)
+	closed := existing != nil && (existing.Status == models.TaskStatusResolved || existing.Status == models.TaskStatusClosed)
+
+	if alert.Status == "resolved" {
+		if existing == nil {
+			return &InboundResult{Action: InboundActionIgnored}, nil
+		}
+		if closed {
+			return &InboundResult{Action: InboundActionUnchanged, Task: existing}, nil
+		}
+		return s.setStatus(existing, models.TaskStatusResolved, "Alert resolved"+alert.since(alert.EndsAt), InboundActionResolved)
+	}
+
+	if existing != nil {
+		if closed {
+			return s.setStatus(existing, models.TaskStatusOpen, "Alert firing again"+alert.since(alert.StartsAt), InboundActionReopened)
+		}
+		if err := s.taskService.AddComment(existing.ID, &models.Comment{Content: "Still firing" + alert.since(alert.StartsAt), IsPrivate: true}); err != nil {
+			return nil, err
+		}
+		updated, err := s.taskService.GetTask(existing.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &InboundResult{Action: InboundActionCommented, Task: updated}, nil
+	}
+
+	mapping := s.alertmanager.Severity[alert.Labels["severity"]]
+	tags := slices.Clone(s.alertmanager.Tags)
+	for _, tag := range mapping.Tags {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	task, err := s.taskService.CreateTask(alert.title())
+	if err != nil {
+		return nil, err
+	}
+	task.Description = alert.description(webhook.ExternalURL)
+	task.Priority = models.TaskPriority(mapping.Priority)
+	task.Tags = tags
+	task.InboundKey = inboundKey
+	if err := s.taskService.UpdateTask(task); err != nil {
+		return nil, err
+	}
+	created, err := s.taskService.GetTask(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &InboundResult{Action: InboundActionCreated, Task: created}, nil
+}
+
+// title names the task after the alert's summary annotation or alert name
+func (a *AlertmanagerAlert) title() string {
+	if summary := a.Annotations["summary"]; summary != "" {
+		return summary
+	}
+	if name := a.Labels["alertname"]; name != "" {
+		return name
+	}
+	return "Alertmanager alert"
+}
+
+// description describes the alert and its labels, with links back to the
+// alert's source and Alertmanager
+func (a *AlertmanagerAlert) description(externalURL string) string {
+	var b strings.Builder
+	if description := a.Annotations["description"]; description != "" {
+		b.WriteString(description + "\n\n")
+	}
+
+	labels := make([]string, 0, len(a.Labels))
+	for name, value := range a.Labels {
+		labels = append(labels, name+"="+value)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		b.WriteString("- " + label + "\n")
+	}
+
+	if a.GeneratorURL != "" {
+		b.WriteString("\nSource: " + a.GeneratorURL + "\n")
+	}
+	if externalURL != "" {
+		b.WriteString("Alertmanager: " + externalURL + "\n")
+	}
+	return b.String()
+}
+
+// since formats a comment suffix for the time an alert started or ended
+func (a *AlertmanagerAlert) since(at time.Time) string {
+	if at.IsZero() {
+		return ""
+	}
+	return " at " + at.Local().Format("2006-01-02 15:04")
+}
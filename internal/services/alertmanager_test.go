@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func setupAlertmanager(t *testing.T) *InboundService {
+	db := setupTestDB(t)
+	taskService := NewTaskService(repository.NewTaskRepository(db), nil)
+	service, err := NewInboundService(taskService, nil)
+	if err != nil {
+		t.Fatalf("Failed to create inbound service: %v", err)
+	}
+	err = service.SetAlertmanager(config.AlertmanagerConfig{
+		Secret: "s",
+		Tags:   []string{"alert"},
+		Severity: map[string]config.AlertSeverityConfig{
+			"critical": {Priority: "high", Tags: []string{"oncall", "alert"}},
+			"warning":  {Priority: "low"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SetAlertmanager failed: %v", err)
+	}
+	return service
+}
+
+func alertmanagerWebhook(status string, alerts ...AlertmanagerAlert) *AlertmanagerWebhook {
+	for i := range alerts {
+		alerts[i].Status = status
+	}
+	return &AlertmanagerWebhook{Status: status, GroupKey: `{}:{alertname="DiskFull"}`, Alerts: alerts}
+}
+
+func TestInboundService_SetAlertmanager_Validation(t *testing.T) {
+	service, _ := NewInboundService(nil, nil)
+	err := service.SetAlertmanager(config.AlertmanagerConfig{
+		Secret:   "s",
+		Severity: map[string]config.AlertSeverityConfig{"critical": {Priority: "urgent"}},
+	})
+	if err == nil {
+		t.Error("Expected an error for an unknown severity priority")
+	}
+
+	if _, err := NewInboundService(nil, map[string]config.InboundChannelConfig{AlertmanagerChannel: {Secret: "s"}}); err == nil {
+		t.Error("Expected an error for a channel named after the Alertmanager receiver")
+	}
+}
+
+func TestInboundService_ReceiveAlertmanager_Auth(t *testing.T) {
+	service, _ := NewInboundService(nil, nil)
+	if _, err := service.ReceiveAlertmanager("", alertmanagerWebhook("firing")); !errors.Is(err, ErrInboundChannelNotFound) {
+		t.Errorf("Expected ErrInboundChannelNotFound without a secret configured, got %v", err)
+	}
+
+	service = setupAlertmanager(t)
+	if _, err := service.ReceiveAlertmanager("wrong", alertmanagerWebhook("firing")); !errors.Is(err, ErrInboundUnauthorized) {
+		t.Errorf("Expected ErrInboundUnauthorized, got %v", err)
+	}
+	_, err := service.ReceiveAlertmanager("s", alertmanagerWebhook("firing", AlertmanagerAlert{Labels: map[string]string{"alertname": "DiskFull"}}))
+	if !errors.Is(err, ErrInvalidInboundPayload) {
+		t.Errorf("Expected ErrInvalidInboundPayload for an alert without a fingerprint, got %v", err)
+	}
+}
+
+func TestInboundService_ReceiveAlertmanager_Lifecycle(t *testing.T) {
+	service := setupAlertmanager(t)
+
+	varAlert := AlertmanagerAlert{
+		Fingerprint: "a1",
+		Labels:      map[string]string{"alertname": "DiskFull", "severity": "critical", "mount": "/var"},
+		Annotations: map[string]string{"summary": "/var is full", "description": "98% used"},
+	}
+	home := AlertmanagerAlert{
+		Fingerprint: "a2",
+		Labels:      map[string]string{"alertname": "DiskFull", "severity": "warning", "mount": "/home"},
+	}
+
+	// Firing: each alert of the group opens its own task
+	result, err := service.ReceiveAlertmanager("s", alertmanagerWebhook("firing", varAlert, home))
+	if err != nil {
+		t.Fatalf("ReceiveAlertmanager failed: %v", err)
+	}
+	if len(result.Alerts) != 2 || !result.Created() {
+		t.Fatalf("Expected two created tasks, got %+v", result.Alerts)
+	}
+	varTask, homeTask := result.Alerts[0].Task, result.Alerts[1].Task
+	if varTask.ID == homeTask.ID {
+		t.Fatal("Expected alerts with different fingerprints to open different tasks")
+	}
+	if varTask.Name != "/var is full" || varTask.InboundKey != "alertmanager:a1" {
+		t.Errorf("Expected the task named from the summary and keyed by fingerprint, got %+v", varTask)
+	}
+	if homeTask.Name != "DiskFull" {
+		t.Errorf("Expected the task named after the alert without a summary, got %q", homeTask.Name)
+	}
+
+	// Severity mapping: priority plus the severity's tags after the shared ones
+	if varTask.Priority != models.TaskPriorityHigh || len(varTask.Tags) != 2 || varTask.Tags[0] != "alert" || varTask.Tags[1] != "oncall" {
+		t.Errorf("Expected a high priority task tagged alert and oncall, got %q %v", varTask.Priority, varTask.Tags)
+	}
+	if homeTask.Priority != models.TaskPriorityLow || len(homeTask.Tags) != 1 {
+		t.Errorf("Expected a low priority task tagged alert, got %q %v", homeTask.Priority, homeTask.Tags)
+	}
+
+	// Repeat firing: a comment on the open task
+	result, err = service.ReceiveAlertmanager("s", alertmanagerWebhook("firing", varAlert))
+	if err != nil {
+		t.Fatalf("ReceiveAlertmanager failed: %v", err)
+	}
+	if result.Alerts[0].Action != InboundActionCommented || result.Alerts[0].Task.ID != varTask.ID || len(result.Alerts[0].Task.Comments) != 1 {
+		t.Errorf("Expected a comment on the open task, got %q %+v", result.Alerts[0].Action, result.Alerts[0].Task)
+	}
+
+	// Resolve: only the resolved alert's task
+	result, err = service.ReceiveAlertmanager("s", alertmanagerWebhook("resolved", varAlert))
+	if err != nil {
+		t.Fatalf("ReceiveAlertmanager failed: %v", err)
+	}
+	if result.Alerts[0].Action != InboundActionResolved || result.Alerts[0].Task.Status != models.TaskStatusResolved {
+		t.Errorf("Expected the task to be resolved, got %q %+v", result.Alerts[0].Action, result.Alerts[0].Task)
+	}
+	if home, _ := service.taskService.GetTask(homeTask.ID); home.Status == models.TaskStatusResolved {
+		t.Error("Expected the other alert's task to stay open")
+	}
+
+	result, err = service.ReceiveAlertmanager("s", alertmanagerWebhook("resolved", varAlert))
+	if err != nil {
+		t.Fatalf("ReceiveAlertmanager failed: %v", err)
+	}
+	if result.Alerts[0].Action != InboundActionUnchanged {
+		t.Errorf("Expected a repeated resolve to leave the task unchanged, got %q", result.Alerts[0].Action)
+	}
+
+	// Re-fire after resolve: the same task reopens
+	result, err = service.ReceiveAlertmanager("s", alertmanagerWebhook("firing", varAlert))
+	if err != nil {
+		t.Fatalf("ReceiveAlertmanager failed: %v", err)
+	}
+	if result.Alerts[0].Action != InboundActionReopened || result.Alerts[0].Task.ID != varTask.ID || result.Alerts[0].Task.Status != models.TaskStatusOpen {
+		t.Errorf("Expected the resolved task to reopen, got %q %+v", result.Alerts[0].Action, result.Alerts[0].Task)
+	}
+
+	// A resolve for an alert that never fired opens nothing
+	result, err = service.ReceiveAlertmanager("s", alertmanagerWebhook("resolved", AlertmanagerAlert{Fingerprint: "unknown"}))
+	if err != nil {
+		t.Fatalf("ReceiveAlertmanager failed: %v", err)
+	}
+	if result.Alerts[0].Action != InboundActionIgnored || result.Created() {
+		t.Errorf("Expected an unknown resolved alert to be ignored, got %q", result.Alerts[0].Action)
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var (
+	ErrAssetNotFound     = errors.New("asset not found")
+	ErrAssetNameRequired = errors.New("asset name is required")
+	ErrAssetNotLinked    = errors.New("task is not linked to this asset")
+)
+
+// normalizeAsset trims an asset's fields and checks it has a name
+func normalizeAsset(asset *models.Asset) error {
+	asset.Name = strings.TrimSpace(asset.Name)
+	asset.Serial = strings.TrimSpace(asset.Serial)
+	asset.Location = strings.TrimSpace(asset.Location)
+	asset.Notes = strings.TrimSpace(asset.Notes)
+	if asset.Name == "" {
+		return ErrAssetNameRequired
+	}
+	return nil
+}
+
+// CreateAsset validates and stores a new asset
+func (s *TaskService) CreateAsset(asset *models.Asset) error {
+	if err := normalizeAsset(asset); err != nil {
+		return err
+	}
+	return s.repo.CreateAsset(asset)
+}
+
+// GetAssets returns all assets by name, with how many tasks each has
+func (s *TaskService) GetAssets() ([]*models.Asset, error) {
+	return s.repo.GetAssets()
+}
+
+// GetAsset returns an asset with its full task history, newest first, or
+// ErrAssetNotFound
+func (s *TaskService) GetAsset(id uint) (*models.Asset, error) {
+	asset, err := s.repo.GetAssetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if asset == nil {
+		return nil, ErrAssetNotFound
+	}
+	return asset, nil
+}
+
+// UpdateAsset validates and saves changes to an asset
+func (s *TaskService) UpdateAsset(asset *models.Asset) error {
+	if err := normalizeAsset(asset); err != nil {
+		return err
+	}
+	return s.repo.UpdateAsset(asset)
+}
+
+// DeleteAsset deletes an asset; its tasks are kept and unlinked
+func (s *TaskService) DeleteAsset(id uint) error {
+	if _, err := s.GetAsset(id); err != nil {
+		return err
+	}
+	return s.repo.DeleteAsset(id)
+}
+
+// LinkTaskAsset records that a task was done on an asset
+func (s *TaskService) LinkTaskAsset(taskID, assetID uint) error {
+	if _, err := s.GetAsset(assetID); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetByID(taskID); err != nil {
+		return err
+	}
+	return s.repo.LinkTaskAsset(taskID, assetID)
+}
+
+// UnlinkTaskAsset removes a task from an asset's history
+func (s *TaskService) UnlinkTaskAsset(taskID, assetID uint) error {
+	unlinked, err := s.repo.UnlinkTaskAsset(taskID, assetID)
+	if err != nil {
+		return err
+	}
+	if !unlinked {
+		return ErrAssetNotLinked
+	}
+	return nil
+}
+
+// GetTaskAssets returns the assets a task is linked to
+func (s *TaskService) GetTaskAssets(taskID uint) ([]*models.Asset, error) {
+	return s.repo.GetTaskAssets(taskID)
+}
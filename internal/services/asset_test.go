@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_Assets(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	if err := service.CreateAsset(&models.Asset{Name: "  "}); !errors.Is(err, ErrAssetNameRequired) {
+		t.Errorf("Expected ErrAssetNameRequired, got %v", err)
+	}
+
+	nas := &models.Asset{Name: " NAS ", Serial: "SN-123", Location: "Rack"}
+	if err := service.CreateAsset(nas); err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+	if nas.Name != "NAS" {
+		t.Errorf("Expected the name to be trimmed, got %q", nas.Name)
+	}
+	router := &models.Asset{Name: "Router"}
+	service.CreateAsset(router)
+
+	disks, _ := service.CreateTask("Replace failed disk")
+	scrub, _ := service.CreateTask("Run a scrub")
+
+	for _, taskID := range []uint{disks.ID, scrub.ID, scrub.ID} {
+		if err := service.LinkTaskAsset(taskID, nas.ID); err != nil {
+			t.Fatalf("Failed to link task %d: %v", taskID, err)
+		}
+	}
+	service.LinkTaskAsset(scrub.ID, router.ID)
+	if err := service.LinkTaskAsset(scrub.ID, 9999); !errors.Is(err, ErrAssetNotFound) {
+		t.Errorf("Expected ErrAssetNotFound linking to a missing asset, got %v", err)
+	}
+	if err := service.LinkTaskAsset(9999, nas.ID); err == nil {
+		t.Errorf("Expected an error linking a missing task")
+	}
+
+	asset, err := service.GetAsset(nas.ID)
+	if err != nil {
+		t.Fatalf("Failed to get asset: %v", err)
+	}
+	if len(asset.Tasks) != 2 || asset.Tasks[0].ID != scrub.ID {
+		t.Errorf("Expected both tasks, newest first, got %+v", asset.Tasks)
+	}
+
+	assets, _ := service.GetAssets()
+	if len(assets) != 2 || assets[0].Name != "NAS" || assets[0].TaskCount != 2 || assets[1].TaskCount != 1 {
+		t.Errorf("Expected assets by name with their task counts, got %+v", assets)
+	}
+	if linked, _ := service.GetTaskAssets(scrub.ID); len(linked) != 2 {
+		t.Errorf("Expected the scrub to be linked to 2 assets, got %d", len(linked))
+	}
+
+	// Deleted tasks drop out of the history
+	service.DeleteTask(disks.ID)
+	if asset, _ := service.GetAsset(nas.ID); len(asset.Tasks) != 1 {
+		t.Errorf("Expected the deleted task to be left out, got %d tasks", len(asset.Tasks))
+	}
+	if assets, _ := service.GetAssets(); assets[0].TaskCount != 1 {
+		t.Errorf("Expected the deleted task to be left out of the count, got %d", assets[0].TaskCount)
+	}
+
+	if err := service.UnlinkTaskAsset(scrub.ID, router.ID); err != nil {
+		t.Fatalf("Failed to unlink task: %v", err)
+	}
+	if err := service.UnlinkTaskAsset(scrub.ID, router.ID); !errors.Is(err, ErrAssetNotLinked) {
+		t.Errorf("Expected ErrAssetNotLinked unlinking twice, got %v", err)
+	}
+
+	if err := service.DeleteAsset(nas.ID); err != nil {
+		t.Fatalf("Failed to delete asset: %v", err)
+	}
+	if _, err := service.GetAsset(nas.ID); !errors.Is(err, ErrAssetNotFound) {
+		t.Errorf("Expected ErrAssetNotFound after deleting, got %v", err)
+	}
+	if linked, _ := service.GetTaskAssets(scrub.ID); len(linked) != 0 {
+		t.Errorf("Expected the task to be unlinked from the deleted asset, got %+v", linked)
+	}
+	if _, err := service.GetTask(scrub.ID); err != nil {
+		t.Errorf("Expected the task to be kept, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// Auto-assignment policies, see config.AssignmentConfig
+const (
+	AssignmentRoundRobin  = "round_robin"
+	AssignmentLeastLoaded = "least_loaded"
+)
+
+// assignmentPolicy is a validated config.AssignmentConfig
+type assignmentPolicy struct {
+	policy string
+	users  []string
+}
+
+// SetAssignmentPolicies configures auto-assignment, keyed by tag. Unassigned
+// tasks that arrived by email or inbound webhook are assigned by the policy of
+// their first tag that has one.
+func (s *TaskService) SetAssignmentPolicies(policies map[string]config.AssignmentConfig) error {
+	validated := make(map[string]assignmentPolicy, len(policies))
+	for tag, cfg := range policies {
+		if cfg.Policy != AssignmentRoundRobin && cfg.Policy != AssignmentLeastLoaded {
+			return fmt.Errorf("assignment policy for tag %q must be %s or %s", tag, AssignmentRoundRobin, AssignmentLeastLoaded)
+		}
+		var users []string
+		for _, user := range cfg.Users {
+			if user = strings.TrimSpace(user); user != "" && !slices.Contains(users, user) {
+				users = append(users, user)
+			}
+		}
+		if len(users) == 0 {
+			return fmt.Errorf("assignment policy for tag %q has no users", tag)
+		}
+		validated[strings.ToLower(tag)] = assignmentPolicy{policy: cfg.Policy, users: users}
+	}
+
+	s.assignment = validated
+	return nil
+}
+
+// autoAssign assigns an unassigned email or inbound webhook task by the policy
+// of its tags. Failures are logged rather than failing the change that led here.
+func (s *TaskService) autoAssign(task *models.Task) {
+	if len(s.assignment) == 0 || task.Assignee != "" || (task.EmailMessageID == "" && task.InboundKey == "") {
+		return
+	}
+
+	for _, tag := range task.Tags {
+		policy, ok := s.assignment[strings.ToLower(tag)]
+		if !ok {
+			continue
+		}
+
+		assignee, err := s.pickAssignee(strings.ToLower(tag), policy)
+		if err != nil {
+			log.Printf("Failed to auto-assign task %d: %v", task.ID, err)
+			return
+		}
+
+		task.Assignee = assignee
+		assignment := &models.TaskAssignment{
+			TaskID:     task.ID,
+			Assignee:   assignee,
+			Tag:        strings.ToLower(tag),
+			Policy:     policy.policy,
+			AssignedAt: time.Now(),
+		}
+		if err := s.repo.AssignTask(task, assignment); err != nil {
+			task.Assignee = ""
+			log.Printf("Failed to auto-assign task %d: %v", task.ID, err)
+		}
+		return
+	}
+}
+
+// pickAssignee chooses the next user of a tag's group, skipping users who are
+// out of office unless the whole group is
+func (s *TaskService) pickAssignee(tag string, policy assignmentPolicy) (string, error) {
+	away, err := awayUsernames(s.repo, time.Now())
+	if err != nil {
+		return "", err
+	}
+	available := slices.DeleteFunc(slices.Clone(policy.users), func(user string) bool { return away[user] })
+	if len(available) == 0 {
+		available = policy.users
+	}
+
+	if policy.policy == AssignmentLeastLoaded {
+		counts, err := s.repo.CountOpenTasksByAssignee(available)
+		if err != nil {
+			return "", err
+		}
+		// Ties go to the user listed first
+		best := available[0]
+		for _, user := range available[1:] {
+			if counts[user] < counts[best] {
+				best = user
+			}
+		}
+		return best, nil
+	}
+
+	// Round robin continues after whoever the tag's last assignment went to,
+	// so the rotation survives restarts
+	last, err := s.repo.GetLastTaskAssignment(tag)
+	if err != nil {
+		return "", err
+	}
+	next := 0
+	if last != nil {
+		next = slices.Index(policy.users, last.Assignee) + 1
+	}
+	for i := range policy.users {
+		if user := policy.users[(next+i)%len(policy.users)]; slices.Contains(available, user) {
+			return user, nil
+		}
+	}
+	return available[0], nil
+}
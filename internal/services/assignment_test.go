@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_AutoAssign(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+	if err := service.SetAssignmentPolicies(map[string]config.AssignmentConfig{
+		"support": {Policy: AssignmentRoundRobin, Users: []string{"alice", "bob"}},
+		"ops":     {Policy: AssignmentLeastLoaded, Users: []string{"carol", "dave"}},
+	}); err != nil {
+		t.Fatalf("Failed to set policies: %v", err)
+	}
+
+	arrive := func(name, tag string) *models.Task {
+		task, err := service.CreateTaskFromEmail(name, "<"+name+"@example.com>")
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		task.Tags = []string{tag}
+		if err := service.UpdateTask(task); err != nil {
+			t.Fatalf("Failed to tag task: %v", err)
+		}
+		return task
+	}
+
+	var got []string
+	for _, name := range []string{"one", "two", "three"} {
+		got = append(got, arrive(name, "Support").Assignee)
+	}
+	if got[0] != "alice" || got[1] != "bob" || got[2] != "alice" {
+		t.Errorf("Expected round robin alice, bob, alice, got %v", got)
+	}
+
+	// dave has less open work than carol
+	for _, assignee := range []string{"carol", "carol", "dave"} {
+		task, _ := service.CreateTask("Existing")
+		task.Assignee = assignee
+		service.UpdateTask(task)
+	}
+	if task := arrive("outage", "ops"); task.Assignee != "dave" {
+		t.Errorf("Expected the least loaded user dave, got %q", task.Assignee)
+	}
+
+	// Tasks created by hand are left alone
+	manual, _ := service.CreateTask("Manual")
+	manual.Tags = []string{"support"}
+	service.UpdateTask(manual)
+	if manual.Assignee != "" {
+		t.Errorf("Expected a manual task to stay unassigned, got %q", manual.Assignee)
+	}
+
+	events, err := service.GetActivity(ActivityFilter{Types: []ActivityType{ActivityAssigned}})
+	if err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	if len(events) != 4 || events[0].Detail != "Assigned to dave (least loaded on ops)" {
+		t.Errorf("Expected 4 assignments in the activity log, got %+v", events)
+	}
+}
+
+func TestTaskService_SetAssignmentPoliciesValidation(t *testing.T) {
+	service := NewTaskService(nil, nil)
+	for _, policies := range []map[string]config.AssignmentConfig{
+		{"support": {Policy: "random", Users: []string{"alice"}}},
+		{"support": {Policy: AssignmentRoundRobin, Users: []string{" "}}},
+	} {
+		if err := service.SetAssignmentPolicies(policies); err == nil {
+			t.Errorf("Expected %+v to be rejected", policies)
+		}
+	}
+}
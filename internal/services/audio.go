@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// transcodeTimeout bounds one ffmpeg run, so a malformed file cannot hold up
+// mail processing
+const transcodeTimeout = 2 * time.Minute
+
+// TranscodedAudioType is the content type of transcoded audio attachments
+const TranscodedAudioType = "audio/mp4"
+
+// AudioTranscoder converts audio attachments with ffmpeg into a format every
+// browser plays, so voicemail in formats such as AMR, GSM or WMA can be
+// listened to in the task timeline
+type AudioTranscoder struct {
+	ffmpegPath string
+}
+
+// NewAudioTranscoder creates a transcoder running the given ffmpeg binary
+func NewAudioTranscoder(ffmpegPath string) *AudioTranscoder {
+	return &AudioTranscoder{ffmpegPath: ffmpegPath}
+}
+
+// Transcode converts audio in any format ffmpeg reads to AAC in an MP4
+// container, with its loudness normalized to EBU R128 so quiet recordings are
+// audible and loud ones do not clip
+func (t *AudioTranscoder) Transcode(data []byte) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "jats-audio-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input")
+	output := filepath.Join(dir, "output.m4a")
+	if err := os.WriteFile(input, data, 0600); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), transcodeTimeout)
+	defer cancel()
+	// loudnorm upsamples to 192 kHz, so the rate is set back to a common one
+	cmd := exec.CommandContext(ctx, t.ffmpegPath, "-nostdin", "-hide_banner", "-loglevel", "error",
+		"-i", input, "-vn", "-af", "loudnorm", "-ar", "48000", "-c:a", "aac", "-b:a", "96k",
+		"-movflags", "+faststart", "-y", output)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return os.ReadFile(output)
+}
+
+// isAudioContentType reports whether a content type, which may carry
+// parameters such as a name, is audio
+func isAudioContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && strings.HasPrefix(mediaType, "audio/")
+}
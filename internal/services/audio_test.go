@@ -0,0 +1,61 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeFFmpeg writes a script standing in for ffmpeg that writes "transcoded"
+// to its output file, or fails when fail is set
+func fakeFFmpeg(t *testing.T, fail bool) string {
+	script := "#!/bin/sh\nfor arg; do out=\"$arg\"; done\nprintf transcoded > \"$out\"\n"
+	if fail {
+		script = "#!/bin/sh\necho 'Invalid data found when processing input' >&2\nexit 1\n"
+	}
+	path := filepath.Join(t.TempDir(), "ffmpeg")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake ffmpeg: %v", err)
+	}
+	return path
+}
+
+func TestStorageService_TranscodesAudio(t *testing.T) {
+	storage := NewStorageService(t.TempDir())
+	storage.SetAudioTranscoder(NewAudioTranscoder(fakeFFmpeg(t, false)))
+
+	attachment, err := storage.SaveAttachment("voicemail.amr", `audio/amr; name="voicemail.amr"`, []byte("#!AMR\n"))
+	if err != nil {
+		t.Fatalf("Failed to save attachment: %v", err)
+	}
+	if attachment.OriginalName != "voicemail.m4a" || attachment.ContentType != TranscodedAudioType {
+		t.Errorf("Expected a transcoded voicemail.m4a, got %s (%s)", attachment.OriginalName, attachment.ContentType)
+	}
+	if data, err := storage.GetAttachment(attachment.FilePath); err != nil || string(data) != "transcoded" {
+		t.Errorf("Expected the transcoded audio to be stored, got %q (%v)", data, err)
+	}
+
+	other, err := storage.SaveAttachment("notes.txt", "text/plain", []byte("hello"))
+	if err != nil {
+		t.Fatalf("Failed to save attachment: %v", err)
+	}
+	if data, _ := storage.GetAttachment(other.FilePath); string(data) != "hello" {
+		t.Errorf("Expected other attachments to be stored as received, got %q", data)
+	}
+}
+
+func TestStorageService_KeepsAudioFFmpegCannotRead(t *testing.T) {
+	storage := NewStorageService(t.TempDir())
+	storage.SetAudioTranscoder(NewAudioTranscoder(fakeFFmpeg(t, true)))
+
+	attachment, err := storage.SaveAttachment("voicemail.wav", "audio/wav", []byte("RIFF"))
+	if err != nil {
+		t.Fatalf("Failed to save attachment: %v", err)
+	}
+	if attachment.OriginalName != "voicemail.wav" || attachment.ContentType != "audio/wav" {
+		t.Errorf("Expected the audio to be kept as received, got %s (%s)", attachment.OriginalName, attachment.ContentType)
+	}
+	if data, _ := storage.GetAttachment(attachment.FilePath); string(data) != "RIFF" {
+		t.Errorf("Expected the original audio to be stored, got %q", data)
+	}
+}
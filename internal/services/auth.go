@@ -20,6 +20,8 @@ var (
 	ErrRateLimitExceeded  = errors.New("rate limit exceeded")
 	ErrInvalidAPIKey      = errors.New("invalid API key")
 	ErrSessionExpired     = errors.New("session expired")
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	ErrInvalidCapacity     = errors.New("weekly capacity must be between 0 and 168 hours")
 )
 
 // AuthService handles authentication business logic
@@ -33,7 +35,6 @@ type AuthConfig struct {
 	SessionDuration    time.Duration
 	MaxLoginAttempts   int
 	RateLimitWindow    time.Duration
-	CleanupInterval    time.Duration
 	APIKeyLength       int
 	RequireTOTP        bool
 }
@@ -44,7 +45,6 @@ func DefaultAuthConfig() *AuthConfig {
 		SessionDuration:   24 * time.Hour,
 		MaxLoginAttempts:  5,
 		RateLimitWindow:   15 * time.Minute,
-		CleanupInterval:   time.Hour,
 		APIKeyLength:      32,
 		RequireTOTP:       false,
 	}
@@ -56,15 +56,11 @@ func NewAuthService(authRepo *repository.AuthRepository, config *AuthConfig) *Au
 		config = DefaultAuthConfig()
 	}
 	
-	service := &AuthService{
+	// Expired sessions and old login attempts are cleaned up by the RetentionService
+	return &AuthService{
 		authRepo: authRepo,
 		config:   config,
 	}
-	
-	// Start cleanup routine
-	go service.startCleanupRoutine()
-	
-	return service
 }
 
 // RegisterUser registers a new user
@@ -433,24 +429,6 @@ func (s *AuthService) checkRateLimit(username, ipAddress string) error {
 	return nil
 }
 
-// startCleanupRoutine starts background cleanup tasks
-func (s *AuthService) startCleanupRoutine() {
-	ticker := time.NewTicker(s.config.CleanupInterval)
-	defer ticker.Stop()
-	
-	for range ticker.C {
-		// Clean up expired sessions
-		if err := s.authRepo.DeleteExpiredSessions(); err != nil {
-			fmt.Printf("Failed to cleanup expired sessions: %v\n", err)
-		}
-		
-		// Clean up old login attempts (older than 24 hours)
-		if err := s.authRepo.CleanupOldLoginAttempts(time.Now().Add(-24 * time.Hour)); err != nil {
-			fmt.Printf("Failed to cleanup old login attempts: %v\n", err)
-		}
-	}
-}
-
 // GetUserSessions returns all active sessions for a user
 func (s *AuthService) GetUserSessions(userID uint) ([]models.Session, error) {
 	return s.authRepo.GetUserSessions(userID)
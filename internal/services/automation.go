@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// defaultStaleMessage is the reminder note posted on stale tasks, with the number of idle days
+const defaultStaleMessage = "This task has had no activity for %d days."
+
+// defaultEscalateMessage is the note posted on escalated tasks, with the new priority and the number of days
+const defaultEscalateMessage = "Priority raised to %s: this task has been open for %d days."
+
+// escalatedPriority is the next priority step up; high is not raised further
+var escalatedPriority = map[models.TaskPriority]models.TaskPriority{
+	"":                        models.TaskPriorityMedium,
+	models.TaskPriorityLow:    models.TaskPriorityMedium,
+	models.TaskPriorityMedium: models.TaskPriorityHigh,
+}
+
+// AutomationService applies the configured housekeeping rules: it closes
+// resolved tasks nobody has touched for a while, raises the priority of open
+// tasks that have been waiting too long, and posts a reminder on open tasks
+// that have gone quiet
+type AutomationService struct {
+	taskService  *TaskService
+	notification *NotificationService
+	cfg          config.AutomationConfig
+}
+
+// NewAutomationService creates the rule runner; register its Run method with a
+// JobRunner. notification may be nil, in which case stale tasks only get the note.
+func NewAutomationService(taskService *TaskService, notification *NotificationService, cfg config.AutomationConfig) *AutomationService {
+	return &AutomationService{taskService: taskService, notification: notification, cfg: cfg}
+}
+
+// Enabled reports whether any rule is configured, counting tag overrides
+func (s *AutomationService) Enabled() bool {
+	if s.cfg.CloseResolvedDays > 0 || s.cfg.StaleOpenDays > 0 || s.cfg.EscalateDays > 0 {
+		return true
+	}
+	for _, override := range s.cfg.Tags {
+		if (override.CloseResolvedDays != nil && *override.CloseResolvedDays > 0) ||
+			(override.StaleOpenDays != nil && *override.StaleOpenDays > 0) ||
+			(override.EscalateDays != nil && *override.EscalateDays > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run applies the rules once. A task that fails is reported and the rest are still processed.
+func (s *AutomationService) Run(now time.Time) error {
+	tasks, err := s.taskService.GetTasks()
+	if err != nil {
+		return err
+	}
+	away, err := awayUsernames(s.taskService.repo, now)
+	if err != nil {
+		return err
+	}
+
+	var closed, escalated, nagged int
+	var errs []error
+	for _, task := range tasks {
+		idle := now.Sub(task.UpdatedAt)
+
+		switch task.Status {
+		case models.TaskStatusResolved:
+			days := s.daysFor(task, s.cfg.CloseResolvedDays, func(o config.AutomationTagConfig) *int { return o.CloseResolvedDays })
+			if days <= 0 || idle < time.Duration(days)*24*time.Hour {
+				continue
+			}
+			task.Status = models.TaskStatusClosed
+			if err := s.taskService.UpdateTask(task); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close task %d: %w", task.ID, err))
+				continue
+			}
+			closed++
+
+		case models.TaskStatusOpen, models.TaskStatusInProgress:
+			// Work waiting on someone out of office is left alone until they are back
+			if away[task.Assignee] {
+				continue
+			}
+			if s.dueForEscalation(task, now) {
+				// The escalation note is activity too, so no reminder this round
+				if err := s.escalate(task, now); err != nil {
+					errs = append(errs, fmt.Errorf("failed to escalate task %d: %w", task.ID, err))
+				} else {
+					escalated++
+				}
+				continue
+			}
+
+			days := s.daysFor(task, s.cfg.StaleOpenDays, func(o config.AutomationTagConfig) *int { return o.StaleOpenDays })
+			if days <= 0 || idle < time.Duration(days)*24*time.Hour {
+				continue
+			}
+			if err := s.nag(task, days); err != nil {
+				errs = append(errs, fmt.Errorf("failed to flag stale task %d: %w", task.ID, err))
+				continue
+			}
+			nagged++
+		}
+	}
+
+	if closed > 0 || escalated > 0 || nagged > 0 {
+		log.Printf("Automation closed %d resolved task(s), escalated %d task(s), and flagged %d stale task(s)", closed, escalated, nagged)
+	}
+	return errors.Join(errs...)
+}
+
+// nag posts the reminder note on a stale task, which also restarts its idle
+// clock, and notifies JATS users
+func (s *AutomationService) nag(task *models.Task, days int) error {
+	message := s.cfg.StaleMessage
+	if message == "" {
+		message = fmt.Sprintf(defaultStaleMessage, days)
+	}
+
+	note := &models.Comment{Content: message, IsPrivate: true}
+	if err := s.taskService.AddComment(task.ID, note); err != nil {
+		return err
+	}
+
+	if s.notification != nil {
+		go s.notification.NotifyTaskStale(task, note)
+	}
+	return nil
+}
+
+// dueForEscalation reports whether an open task should have its priority
+// raised. The clock starts when the task is created and restarts at every
+// escalation.
+func (s *AutomationService) dueForEscalation(task *models.Task, now time.Time) bool {
+	if _, ok := escalatedPriority[task.Priority]; !ok {
+		return false
+	}
+	days := s.daysFor(task, s.cfg.EscalateDays, func(o config.AutomationTagConfig) *int { return o.EscalateDays })
+	if days <= 0 {
+		return false
+	}
+	since := task.CreatedAt
+	if task.EscalatedAt != nil {
+		since = *task.EscalatedAt
+	}
+	return now.Sub(since) >= time.Duration(days)*24*time.Hour
+}
+
+// escalate raises a task's priority one step and posts the escalation note
+func (s *AutomationService) escalate(task *models.Task, now time.Time) error {
+	task.Priority = escalatedPriority[task.Priority]
+	task.EscalatedAt = &now
+	if err := s.taskService.UpdateTask(task); err != nil {
+		return err
+	}
+
+	message := s.cfg.EscalateMessage
+	if message == "" {
+		message = fmt.Sprintf(defaultEscalateMessage, task.Priority, int(now.Sub(task.CreatedAt).Hours()/24))
+	}
+	return s.taskService.AddComment(task.ID, &models.Comment{Content: message, IsPrivate: true})
+}
+
+// daysFor returns the day value of a rule for a task. When several of its tags
+// override the rule, an override of 0 disables it and otherwise the longest wins.
+func (s *AutomationService) daysFor(task *models.Task, global int, field func(config.AutomationTagConfig) *int) int {
+	days, overridden := 0, false
+	for _, tag := range task.Tags {
+		override, ok := s.cfg.Tags[tag]
+		if !ok || field(override) == nil {
+			continue
+		}
+		value := *field(override)
+		if value <= 0 {
+			return 0
+		}
+		days, overridden = max(days, value), true
+	}
+	if overridden {
+		return days
+	}
+	return global
+}
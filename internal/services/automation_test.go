@@ -0,0 +1,123 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestAutomationService_Run(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	taskService := NewTaskService(repo, nil)
+
+	create := func(name string, status models.TaskStatus, tags ...string) *models.Task {
+		task, err := taskService.CreateTask(name)
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		task.Status = status
+		task.Tags = tags
+		if err := taskService.UpdateTask(task); err != nil {
+			t.Fatalf("Failed to update task: %v", err)
+		}
+		return task
+	}
+
+	resolved := create("Resolved", models.TaskStatusResolved)
+	keep := create("Resolved, kept longer", models.TaskStatusResolved, "contract")
+	stale := create("Stale", models.TaskStatusOpen)
+	exempt := create("Waiting on vendor", models.TaskStatusInProgress, "vendor")
+
+	zero := 0
+	thirty := 30
+	automation := NewAutomationService(taskService, nil, config.AutomationConfig{
+		CloseResolvedDays: 7,
+		StaleOpenDays:     14,
+		Tags: map[string]config.AutomationTagConfig{
+			"contract": {CloseResolvedDays: &thirty},
+			"vendor":   {StaleOpenDays: &zero},
+		},
+	})
+	if !automation.Enabled() {
+		t.Fatal("Expected automation to be enabled")
+	}
+
+	// Fifteen days later every task is past the global limits
+	if err := automation.Run(time.Now().Add(15 * 24 * time.Hour)); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if task, _ := taskService.GetTask(resolved.ID); task.Status != models.TaskStatusClosed {
+		t.Errorf("Expected the resolved task to be closed, got %s", task.Status)
+	}
+	if task, _ := taskService.GetTask(keep.ID); task.Status != models.TaskStatusResolved {
+		t.Errorf("Expected the tag override to keep the task resolved, got %s", task.Status)
+	}
+	if task, _ := taskService.GetTask(stale.ID); len(task.Comments) != 1 || task.Comments[0].Content != "This task has had no activity for 14 days." {
+		t.Errorf("Expected a reminder note on the stale task, got %+v", task.Comments)
+	}
+	if task, _ := taskService.GetTask(exempt.ID); len(task.Comments) != 0 {
+		t.Errorf("Expected the tag override to disable the reminder, got %+v", task.Comments)
+	}
+}
+
+func TestAutomationService_Escalate(t *testing.T) {
+	db := setupTestDB(t)
+	taskService := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	support, _ := taskService.CreateTask("Customer cannot log in")
+	support.Priority = models.TaskPriorityMedium
+	support.Tags = []string{"support"}
+	if err := taskService.UpdateTask(support); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	other, _ := taskService.CreateTask("Tidy up the wiki")
+
+	seven := 7
+	automation := NewAutomationService(taskService, nil, config.AutomationConfig{
+		EscalateDays: 30,
+		Tags: map[string]config.AutomationTagConfig{
+			"support": {EscalateDays: &seven},
+		},
+	})
+	if !automation.Enabled() {
+		t.Fatal("Expected automation to be enabled")
+	}
+
+	start := time.Now()
+	if err := automation.Run(start.Add(8 * 24 * time.Hour)); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	task, _ := taskService.GetTask(support.ID)
+	if task.Priority != models.TaskPriorityHigh || task.EscalatedAt == nil {
+		t.Fatalf("Expected the support task to be escalated to high, got %q", task.Priority)
+	}
+	if len(task.Comments) != 1 || task.Comments[0].Content != "Priority raised to high: this task has been open for 8 days." {
+		t.Errorf("Expected an escalation note, got %+v", task.Comments)
+	}
+	if task, _ := taskService.GetTask(other.ID); task.Priority != "" {
+		t.Errorf("Expected the untagged task to wait for the global limit, got %q", task.Priority)
+	}
+
+	// High is as far as it goes; the untagged task steps up from unset
+	if err := automation.Run(start.Add(31 * 24 * time.Hour)); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if task, _ := taskService.GetTask(support.ID); len(task.Comments) != 1 {
+		t.Errorf("Expected no further escalation of a high task, got %+v", task.Comments)
+	}
+	if task, _ := taskService.GetTask(other.ID); task.Priority != models.TaskPriorityMedium {
+		t.Errorf("Expected the untagged task to be escalated to medium, got %q", task.Priority)
+	}
+}
+
+func TestAutomationService_Disabled(t *testing.T) {
+	automation := NewAutomationService(nil, nil, config.AutomationConfig{})
+	if automation.Enabled() {
+		t.Error("Expected automation without rules to be disabled")
+	}
+}
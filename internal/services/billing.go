@@ -0,0 +1,38 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// SetNonBillableTags sets the tags of internal work: time logged on tasks
+// with any of them is non-billable unless the entry says otherwise
+func (s *TaskService) SetNonBillableTags(tags []string) {
+	s.nonBillableTags = make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			s.nonBillableTags[tag] = true
+		}
+	}
+}
+
+// DefaultBillable reports whether time logged on a task is billable when the
+// entry does not say
+func (s *TaskService) DefaultBillable(task *models.Task) bool {
+	for _, tag := range task.Tags {
+		if s.nonBillableTags[strings.ToLower(tag)] {
+			return false
+		}
+	}
+	return true
+}
+
+// applyDefaultBillable fills in the billable flag of an entry that does not
+// set it from its task's tags
+func (s *TaskService) applyDefaultBillable(entry *models.TimeEntry, task *models.Task) {
+	if entry.Billable == nil {
+		billable := s.DefaultBillable(task)
+		entry.Billable = &billable
+	}
+}
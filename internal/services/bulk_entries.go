@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/quickadd"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+var (
+	ErrBulkTaskNotFound   = errors.New("task not found")
+	ErrInvalidDuration    = errors.New("duration must be greater than 0")
+	ErrEmptyComment       = errors.New("comment content is required")
+	ErrEmptyTaskName      = errors.New("task name is required")
+	ErrInvalidPriority    = errors.New("priority must be low, medium or high")
+	ErrInvalidDate        = errors.New("invalid date")
+	ErrTooManyBulkEntries = fmt.Errorf("at most %d entries can be created at once", MaxBulkEntries)
+)
+
+// MaxBulkEntries caps how many tasks, time entries or comments one bulk call creates
+const MaxBulkEntries = 1000
+
+// AddTimeEntries validates and inserts many time entries at once, for importers and
+// the bulk API. Entries without a CreatedAt are logged now. Either every entry is
+// created or none is; errors name the 1-based position of the offending entry.
+// Like AddTimeEntry, open tasks that receive time move to in-progress.
+func (s *TaskService) AddTimeEntries(entries []*models.TimeEntry) error {
+	if len(entries) > MaxBulkEntries {
+		return ErrTooManyBulkEntries
+	}
+
+	tasks, err := s.loadBulkTasks(len(entries), func(i int) uint { return entries[i].TaskID })
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i, entry := range entries {
+		if entry.Duration <= 0 {
+			return fmt.Errorf("entry %d: %w", i+1, ErrInvalidDuration)
+		}
+		if entry.SubtaskID != nil {
+			subtask, err := s.repo.GetSubtask(*entry.SubtaskID)
+			if err != nil || subtask.TaskID != entry.TaskID {
+				return fmt.Errorf("entry %d: %w", i+1, ErrSubtaskNotInTask)
+			}
+		}
+		if entry.CreatedAt.IsZero() {
+			entry.CreatedAt = now
+		}
+		entry.UpdatedAt = now
+		s.applyDefaultBillable(entry, tasks[entry.TaskID])
+	}
+
+	// Open tasks that receive time move to in-progress, saved with the entries
+	oldStatuses := make(map[uint]models.TaskStatus, len(tasks))
+	updated := make([]*models.Task, 0, len(tasks))
+	var changes []*models.TaskStatusChange
+	for _, task := range tasks {
+		oldStatuses[task.ID] = task.Status
+		if task.Status == models.TaskStatusOpen {
+			task.Status = models.TaskStatusInProgress
+			changes = append(changes, statusChange(task, models.TaskStatusOpen))
+		}
+		task.UpdatedAt = now
+		updated = append(updated, task)
+	}
+
+	if err := s.repo.CreateTimeEntries(entries, updated, changes); err != nil {
+		return err
+	}
+
+	for _, task := range updated {
+		s.publish(TaskEventUpdated, task)
+	}
+	if s.notification != nil {
+		for _, task := range updated {
+			if oldStatus := oldStatuses[task.ID]; oldStatus != task.Status {
+				go s.notification.NotifyStatusChanged(task, oldStatus, task.Status)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddComments validates and inserts many comments at once, keeping their CreatedAt
+// when set. It is meant for imports, so unlike AddComment no notifications are sent.
+func (s *TaskService) AddComments(comments []*models.Comment) error {
+	if len(comments) > MaxBulkEntries {
+		return ErrTooManyBulkEntries
+	}
+
+	tasks, err := s.loadBulkTasks(len(comments), func(i int) uint { return comments[i].TaskID })
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i, comment := range comments {
+		if strings.TrimSpace(comment.Content) == "" {
+			return fmt.Errorf("entry %d: %w", i+1, ErrEmptyComment)
+		}
+		if comment.CreatedAt.IsZero() {
+			comment.CreatedAt = now
+		}
+		comment.UpdatedAt = now
+	}
+
+	if err := s.repo.CreateComments(comments); err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		if err := s.syncReferences(comment.TaskID, &comment.ID, comment.Content); err != nil {
+			return err
+		}
+	}
+	for _, task := range tasks {
+		task.UpdatedAt = now
+		if err := s.repo.Update(task); err != nil {
+			return err
+		}
+	}
+	for _, comment := range comments {
+		task := tasks[comment.TaskID]
+		s.events.Publish(TaskEvent{Type: TaskEventCommented, TaskID: task.ID, Name: task.Name, Status: task.Status, CommentID: comment.ID})
+	}
+
+	return nil
+}
+
+// CreateQuickTasks creates a task for each parsed quick-add entry, in order, for
+// batch imports from the CLI. Every entry is validated before any task is
+// created; if creating one still fails, the tasks created so far are returned
+// with the error, which names the 1-based position of the entry.
+func (s *TaskService) CreateQuickTasks(entries []*quickadd.Task) ([]*models.Task, error) {
+	if len(entries) > MaxBulkEntries {
+		return nil, ErrTooManyBulkEntries
+	}
+
+	for i, entry := range entries {
+		if strings.TrimSpace(entry.Name) == "" {
+			return nil, fmt.Errorf("entry %d: %w", i+1, ErrEmptyTaskName)
+		}
+		switch entry.Priority {
+		case "", models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh:
+		default:
+			return nil, fmt.Errorf("entry %d: %w", i+1, ErrInvalidPriority)
+		}
+		if entry.Duration < 0 {
+			return nil, fmt.Errorf("entry %d: %w", i+1, ErrInvalidDuration)
+		}
+		if _, err := utils.ParseDate(entry.Date); err != nil {
+			return nil, fmt.Errorf("entry %d: %w: %v", i+1, ErrInvalidDate, err)
+		}
+		if _, err := ParseDueDate(entry.DueDate); err != nil {
+			return nil, fmt.Errorf("entry %d: %w: %v", i+1, ErrInvalidDate, err)
+		}
+	}
+
+	tasks := make([]*models.Task, 0, len(entries))
+	for i, entry := range entries {
+		task, err := s.CreateQuickTask(entry)
+		if err != nil {
+			return tasks, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// loadBulkTasks loads the distinct tasks referenced by n bulk entries, failing
+// on the first entry whose task does not exist
+func (s *TaskService) loadBulkTasks(n int, taskID func(i int) uint) (map[uint]*models.Task, error) {
+	tasks := make(map[uint]*models.Task)
+	for i := 0; i < n; i++ {
+		id := taskID(i)
+		if _, ok := tasks[id]; ok {
+			continue
+		}
+		task, err := s.repo.GetByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w: %d", i+1, ErrBulkTaskNotFound, id)
+		}
+		tasks[id] = task
+	}
+	return tasks, nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+	"gorm.io/gorm"
+)
+
+func TestTaskService_AddTimeEntries(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	first, _ := service.CreateTask("First")
+	second, _ := service.CreateTask("Second")
+	loggedAt := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	entries := []*models.TimeEntry{
+		{TaskID: first.ID, Duration: 30, Description: "Imported", CreatedAt: loggedAt},
+		{TaskID: first.ID, Duration: 15},
+		{TaskID: second.ID, Duration: 45},
+	}
+	if err := service.AddTimeEntries(entries); err != nil {
+		t.Fatalf("AddTimeEntries failed: %v", err)
+	}
+
+	for _, entry := range entries {
+		if entry.ID == 0 {
+			t.Error("Expected every entry to get an ID")
+		}
+	}
+
+	task, err := service.GetTask(first.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if task.LoggedMinutes != 45 {
+		t.Errorf("Expected 45 logged minutes, got %d", task.LoggedMinutes)
+	}
+	if task.Status != models.TaskStatusInProgress {
+		t.Errorf("Expected task to move to in-progress, got %s", task.Status)
+	}
+	if !task.TimeEntries[0].CreatedAt.Equal(loggedAt) {
+		t.Errorf("Expected the given date to be kept, got %v", task.TimeEntries[0].CreatedAt)
+	}
+}
+
+func TestTaskService_AddTimeEntriesIsAllOrNothing(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	task, _ := service.CreateTask("Task")
+
+	err := service.AddTimeEntries([]*models.TimeEntry{
+		{TaskID: task.ID, Duration: 30},
+		{TaskID: 9999, Duration: 15},
+	})
+	if !errors.Is(err, ErrBulkTaskNotFound) {
+		t.Fatalf("Expected ErrBulkTaskNotFound, got %v", err)
+	}
+
+	err = service.AddTimeEntries([]*models.TimeEntry{
+		{TaskID: task.ID, Duration: 30},
+		{TaskID: task.ID, Duration: 0},
+	})
+	if !errors.Is(err, ErrInvalidDuration) {
+		t.Fatalf("Expected ErrInvalidDuration, got %v", err)
+	}
+
+	// A task that cannot move to in-progress rolls back the whole import
+	db.Callback().Update().Before("gorm:update").Register("test:fail_update", func(tx *gorm.DB) {
+		if tx.Statement.Table == "tasks" {
+			tx.AddError(errors.New("update failed"))
+		}
+	})
+	if err := service.AddTimeEntries([]*models.TimeEntry{{TaskID: task.ID, Duration: 30}}); err == nil {
+		t.Fatal("Expected the import to fail when its task cannot be updated")
+	}
+	if history, _ := service.GetStatusHistory(task.ID); len(history) != 0 {
+		t.Errorf("Expected no status history after a failed import, got %d entries", len(history))
+	}
+
+	entries, err := repo.GetTimeEntries(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get time entries: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no entries after failed batches, got %d", len(entries))
+	}
+}
+
+func TestTaskService_AddComments(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	task, _ := service.CreateTask("Task")
+	other, _ := service.CreateTask("Other")
+
+	comments := []*models.Comment{
+		{TaskID: task.ID, Content: "First note"},
+		{TaskID: task.ID, Content: fmt.Sprintf("Related to #%d", other.ID)},
+	}
+	if err := service.AddComments(comments); err != nil {
+		t.Fatalf("AddComments failed: %v", err)
+	}
+
+	stored, err := repo.GetComments(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comments: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Errorf("Expected 2 comments, got %d", len(stored))
+	}
+
+	refs, err := service.GetTask(other.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if len(refs.ReferencedBy) != 1 {
+		t.Errorf("Expected the imported note's reference to be recorded, got %d", len(refs.ReferencedBy))
+	}
+
+	if err := service.AddComments([]*models.Comment{{TaskID: task.ID, Content: "  "}}); !errors.Is(err, ErrEmptyComment) {
+		t.Errorf("Expected ErrEmptyComment, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var ErrTagRequired = errors.New("tag is required")
+
+// BulkTagAction is the change a bulk tag operation makes
+type BulkTagAction string
+
+const (
+	BulkTagApply  BulkTagAction = "apply"
+	BulkTagRemove BulkTagAction = "remove"
+)
+
+// BulkTagResult reports what a bulk tag operation changed, or would change in a dry run
+type BulkTagResult struct {
+	Tag      string        `json:"tag"`
+	Action   BulkTagAction `json:"action"`
+	DryRun   bool          `json:"dry_run"`
+	Matched  int           `json:"matched"`  // tasks matching the filter
+	Affected int           `json:"affected"` // tasks that gained or lost the tag
+	TaskIDs  []uint        `json:"task_ids"` // IDs of the affected tasks
+}
+
+// BulkUpdateTag adds or removes a tag on each of the given tasks. Tasks that
+// already have (or lack) the tag are left alone. In a dry run nothing is saved.
+func (s *TaskService) BulkUpdateTag(tag string, action BulkTagAction, tasks []*models.Task, dryRun bool) (*BulkTagResult, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return nil, ErrTagRequired
+	}
+
+	result := &BulkTagResult{
+		Tag:     tag,
+		Action:  action,
+		DryRun:  dryRun,
+		Matched: len(tasks),
+		TaskIDs: []uint{},
+	}
+
+	for _, task := range tasks {
+		var newTags []string
+		changed := false
+
+		switch action {
+		case BulkTagApply:
+			if hasTag(task, tag) {
+				continue
+			}
+			newTags = append(append(newTags, task.Tags...), tag)
+			changed = true
+		case BulkTagRemove:
+			for _, t := range task.Tags {
+				if strings.EqualFold(t, tag) {
+					changed = true
+					continue
+				}
+				newTags = append(newTags, t)
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		if !dryRun {
+			task.Tags = newTags
+			if err := s.UpdateTask(task); err != nil {
+				return nil, err
+			}
+		}
+		result.Affected++
+		result.TaskIDs = append(result.TaskIDs, task.ID)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var (
+	ErrCannedResponseNotFound    = errors.New("canned response not found")
+	ErrInvalidCannedResponseName = errors.New("canned response name must contain only letters, numbers, dashes, and underscores")
+	ErrEmptyCannedResponse       = errors.New("canned response content is required")
+	ErrDuplicateCannedResponse   = errors.New("a canned response with this name already exists")
+)
+
+var (
+	cannedResponseNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	cannedPlaceholderPattern  = regexp.MustCompile(`\{\{\s*([a-z_]+\.[a-z_]+)\s*\}\}`)
+)
+
+// ListCannedResponses returns all canned responses ordered by name
+func (s *SettingsService) ListCannedResponses() ([]*models.CannedResponse, error) {
+	return s.repo.ListCannedResponses()
+}
+
+// GetCannedResponse returns a canned response by name
+func (s *SettingsService) GetCannedResponse(name string) (*models.CannedResponse, error) {
+	response, err := s.repo.GetCannedResponseByName(strings.ToLower(strings.TrimSpace(name)))
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		return nil, ErrCannedResponseNotFound
+	}
+	return response, nil
+}
+
+// SaveCannedResponse validates and creates or updates a canned response.
+// Names are stored lowercase so they can be typed case-insensitively from the CLI.
+func (s *SettingsService) SaveCannedResponse(response *models.CannedResponse) (*models.CannedResponse, error) {
+	response.Name = strings.ToLower(strings.TrimSpace(response.Name))
+	response.Content = strings.TrimSpace(response.Content)
+
+	if !cannedResponseNamePattern.MatchString(response.Name) {
+		return nil, ErrInvalidCannedResponseName
+	}
+	if response.Content == "" {
+		return nil, ErrEmptyCannedResponse
+	}
+
+	if response.ID != 0 {
+		existing, err := s.repo.GetCannedResponse(response.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, ErrCannedResponseNotFound
+		}
+		response.CreatedAt = existing.CreatedAt
+	}
+
+	sameName, err := s.repo.GetCannedResponseByName(response.Name)
+	if err != nil {
+		return nil, err
+	}
+	if sameName != nil && sameName.ID != response.ID {
+		return nil, ErrDuplicateCannedResponse
+	}
+
+	if err := s.repo.SaveCannedResponse(response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// DeleteCannedResponse deletes a canned response by ID
+func (s *SettingsService) DeleteCannedResponse(id uint) error {
+	existing, err := s.repo.GetCannedResponse(id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrCannedResponseNotFound
+	}
+	return s.repo.DeleteCannedResponse(id)
+}
+
+// RenderCannedResponse looks up a canned response by name and fills in its
+// placeholders for the given task
+func (s *SettingsService) RenderCannedResponse(name string, task *models.Task) (string, error) {
+	response, err := s.GetCannedResponse(name)
+	if err != nil {
+		return "", err
+	}
+	return s.ExpandCannedResponse(response.Content, task), nil
+}
+
+// ExpandCannedResponse replaces {{task.*}} and {{instance.name}} placeholders in
+// content. Unknown placeholders are left untouched so typos are easy to spot.
+func (s *SettingsService) ExpandCannedResponse(content string, task *models.Task) string {
+	values := map[string]string{
+		"instance.name": s.GetBranding().InstanceName,
+	}
+	if task != nil {
+		values["task.id"] = fmt.Sprintf("%d", task.ID)
+		values["task.name"] = task.Name
+		values["task.status"] = string(task.Status)
+		values["task.priority"] = string(task.Priority)
+	}
+
+	return cannedPlaceholderPattern.ReplaceAllStringFunc(content, func(match string) string {
+		key := cannedPlaceholderPattern.FindStringSubmatch(match)[1]
+		if value, ok := values[key]; ok {
+			return value
+		}
+		return match
+	})
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// CapacityTask is an open task counted against capacity
+type CapacityTask struct {
+	ID               uint                `json:"id"`
+	Name             string              `json:"name"`
+	Status           models.TaskStatus   `json:"status"`
+	Priority         models.TaskPriority `json:"priority,omitempty"`
+	EstimateMinutes  int                 `json:"estimate_minutes"`
+	LoggedMinutes    int                 `json:"logged_minutes"`
+	RemainingMinutes int                 `json:"remaining_minutes"`
+}
+
+// CapacityPlan compares the estimated work left on open tasks with a weekly capacity
+type CapacityPlan struct {
+	WeekStart        string         `json:"week_start"` // YYYY-MM-DD, the coming week starts today
+	WeekEnd          string         `json:"week_end"`   // YYYY-MM-DD, inclusive
+	CapacityMinutes  int            `json:"capacity_minutes"`
+	RemainingMinutes int            `json:"remaining_minutes"`
+	Utilization      float64        `json:"utilization"` // remaining / capacity, 0 when no capacity is set
+	Overcommitted    bool           `json:"overcommitted"`
+	OverByMinutes    int            `json:"over_by_minutes,omitempty"`
+	UnestimatedTasks int            `json:"unestimated_tasks"` // open tasks without subtask estimates
+	Tasks            []CapacityTask `json:"tasks"`
+}
+
+// GetCapacityPlan totals the remaining estimate (estimate minus logged time) of
+// every open and in-progress task and compares it with capacityMinutes for the
+// coming week. Tasks are not assigned to users, so all open work counts.
+func (s *TaskService) GetCapacityPlan(capacityMinutes int, now time.Time) (*CapacityPlan, error) {
+	tasks, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	start := startOfDay(now)
+	plan := &CapacityPlan{
+		WeekStart:       start.Format("2006-01-02"),
+		WeekEnd:         start.AddDate(0, 0, 6).Format("2006-01-02"),
+		CapacityMinutes: capacityMinutes,
+		Tasks:           []CapacityTask{},
+	}
+
+	for _, task := range tasks {
+		if task.Status != models.TaskStatusOpen && task.Status != models.TaskStatusInProgress {
+			continue
+		}
+		if task.EstimateMinutes == 0 {
+			plan.UnestimatedTasks++
+			continue
+		}
+
+		remaining := task.EstimateMinutes - task.LoggedMinutes
+		if remaining < 0 {
+			remaining = 0
+		}
+		plan.RemainingMinutes += remaining
+		plan.Tasks = append(plan.Tasks, CapacityTask{
+			ID:               task.ID,
+			Name:             task.Name,
+			Status:           task.Status,
+			Priority:         task.Priority,
+			EstimateMinutes:  task.EstimateMinutes,
+			LoggedMinutes:    task.LoggedMinutes,
+			RemainingMinutes: remaining,
+		})
+	}
+
+	sort.Slice(plan.Tasks, func(i, j int) bool {
+		return plan.Tasks[i].RemainingMinutes > plan.Tasks[j].RemainingMinutes
+	})
+
+	if capacityMinutes > 0 {
+		plan.Utilization = float64(plan.RemainingMinutes) / float64(capacityMinutes)
+		if plan.RemainingMinutes > capacityMinutes {
+			plan.Overcommitted = true
+			plan.OverByMinutes = plan.RemainingMinutes - capacityMinutes
+		}
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_GetCapacityPlan(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	task, _ := service.CreateTask("Migrate database")
+	if err := service.AddSubtask(task.ID, &models.Subtask{Name: "Plan", EstimateMinutes: 600}); err != nil {
+		t.Fatalf("Failed to add subtask: %v", err)
+	}
+	if err := service.AddTimeEntry(task.ID, &models.TimeEntry{Duration: 120}); err != nil {
+		t.Fatalf("Failed to log time: %v", err)
+	}
+	overrun, _ := service.CreateTask("Overrun task")
+	if err := service.AddSubtask(overrun.ID, &models.Subtask{Name: "Fix", EstimateMinutes: 30}); err != nil {
+		t.Fatalf("Failed to add subtask: %v", err)
+	}
+	if err := service.AddTimeEntry(overrun.ID, &models.TimeEntry{Duration: 60}); err != nil {
+		t.Fatalf("Failed to log time: %v", err)
+	}
+	service.CreateTask("Unestimated task")
+
+	plan, err := service.GetCapacityPlan(6*60, time.Date(2024, 3, 4, 9, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("Failed to get capacity plan: %v", err)
+	}
+
+	if plan.WeekStart != "2024-03-04" || plan.WeekEnd != "2024-03-10" {
+		t.Errorf("Expected week 2024-03-04 – 2024-03-10, got %s – %s", plan.WeekStart, plan.WeekEnd)
+	}
+	if plan.RemainingMinutes != 480 {
+		t.Errorf("Expected 480 remaining minutes, got %d", plan.RemainingMinutes)
+	}
+	if plan.UnestimatedTasks != 1 {
+		t.Errorf("Expected 1 unestimated task, got %d", plan.UnestimatedTasks)
+	}
+	if !plan.Overcommitted || plan.OverByMinutes != 120 {
+		t.Errorf("Expected overcommitment by 120 minutes, got %+v", plan)
+	}
+
+	plan, _ = service.GetCapacityPlan(0, time.Now())
+	if plan.Overcommitted || plan.Utilization != 0 {
+		t.Errorf("Expected no overcommitment without a capacity, got %+v", plan)
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/quickadd"
+)
+
+// defaultPlaceRadius is how close, in meters, a capture must be to a place
+// without a radius of its own
+const defaultPlaceRadius = 250
+
+// earthRadius is the mean radius of the Earth in meters
+const earthRadius = 6371000
+
+// LocationTagPrefix starts the tag naming where a mobile capture was sent from
+const LocationTagPrefix = "location:"
+
+var (
+	ErrInvalidCoordinates = errors.New("latitude and longitude must be given together and within range")
+	ErrInvalidPhoto       = errors.New("photo must be an image")
+	ErrInvalidCaptureText = errors.New("invalid capture text")
+)
+
+// MobileCapture is a note sent from a phone, for example by an iOS Shortcut
+// or a Tasker task
+type MobileCapture struct {
+	// The first line is a quick-add line naming the task; the rest becomes its description
+	Text      string
+	Latitude  *float64
+	Longitude *float64
+	// Place name reported by the phone, used instead of the configured places
+	Location string
+	// Optional photo, with its file name and content type
+	Photo            []byte
+	PhotoName        string
+	PhotoContentType string
+}
+
+// CaptureService turns mobile captures into tasks tagged with where they were
+// sent from
+type CaptureService struct {
+	taskService *TaskService
+	storage     *StorageService
+	places      map[string]config.CapturePlaceConfig
+}
+
+// NewCaptureService creates the capture service; places may be nil, in which
+// case captures without a location name are tagged with rounded coordinates
+func NewCaptureService(taskService *TaskService, storage *StorageService, places map[string]config.CapturePlaceConfig) *CaptureService {
+	return &CaptureService{taskService: taskService, storage: storage, places: places}
+}
+
+// Capture creates the task for a mobile capture and attaches its photo
+func (s *CaptureService) Capture(capture *MobileCapture) (*models.Task, error) {
+	if (capture.Latitude == nil) != (capture.Longitude == nil) ||
+		(capture.Latitude != nil && (math.Abs(*capture.Latitude) > 90 || math.Abs(*capture.Longitude) > 180)) {
+		return nil, ErrInvalidCoordinates
+	}
+	if len(capture.Photo) > 0 && !strings.HasPrefix(capture.PhotoContentType, "image/") {
+		return nil, ErrInvalidPhoto
+	}
+
+	firstLine, rest, _ := strings.Cut(strings.TrimSpace(capture.Text), "\n")
+	entry, err := quickadd.Parse(firstLine)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCaptureText, err)
+	}
+	if strings.TrimSpace(entry.Name) == "" {
+		return nil, ErrEmptyTaskName
+	}
+	if label := s.locationLabel(capture); label != "" {
+		entry.Tags = append(entry.Tags, LocationTagPrefix+label)
+	}
+
+	task, err := s.taskService.CreateQuickTask(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	description := strings.TrimSpace(rest)
+	if capture.Latitude != nil {
+		lat, lon := *capture.Latitude, *capture.Longitude
+		if description != "" {
+			description += "\n\n"
+		}
+		description += fmt.Sprintf("Captured at %.5f, %.5f: https://www.openstreetmap.org/?mlat=%.5f&mlon=%.5f#map=17/%.5f/%.5f",
+			lat, lon, lat, lon, lat, lon)
+	}
+	if description != "" {
+		task.Description = description
+		if err := s.taskService.UpdateTask(task); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(capture.Photo) > 0 {
+		attachment, err := s.storage.SaveAttachment(capture.PhotoName, capture.PhotoContentType, capture.Photo)
+		if err != nil {
+			return nil, err
+		}
+		attachment.TaskID = &task.ID
+		if err := s.taskService.AddAttachment(attachment); err != nil {
+			s.storage.DeleteAttachment(attachment.FilePath)
+			return nil, err
+		}
+	}
+
+	return s.taskService.GetTask(task.ID)
+}
+
+// locationLabel names where a capture was sent from: the place the phone
+// reported, else the nearest configured place in range, else the coordinates
+// rounded to about a kilometer. Captures without a location get no label.
+func (s *CaptureService) locationLabel(capture *MobileCapture) string {
+	if name := strings.Join(strings.Fields(strings.ToLower(capture.Location)), "-"); name != "" {
+		return name
+	}
+	if capture.Latitude == nil {
+		return ""
+	}
+
+	// Sorted so that overlapping places resolve the same way every time
+	names := make([]string, 0, len(s.places))
+	for name := range s.places {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nearest, nearestDistance := "", math.Inf(1)
+	for _, name := range names {
+		place := s.places[name]
+		radius := place.RadiusMeters
+		if radius <= 0 {
+			radius = defaultPlaceRadius
+		}
+		distance := haversine(*capture.Latitude, *capture.Longitude, place.Latitude, place.Longitude)
+		if distance <= radius && distance < nearestDistance {
+			nearest, nearestDistance = name, distance
+		}
+	}
+	if nearest != "" {
+		return nearest
+	}
+	return fmt.Sprintf("%.2f,%.2f", *capture.Latitude, *capture.Longitude)
+}
+
+// haversine returns the great-circle distance in meters between two points
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestCaptureService_Capture(t *testing.T) {
+	db := setupTestDB(t)
+	taskService := NewTaskService(repository.NewTaskRepository(db), nil)
+	service := NewCaptureService(taskService, NewStorageService(t.TempDir()), map[string]config.CapturePlaceConfig{
+		"office": {Latitude: 47.6205, Longitude: -122.3493},
+		"home":   {Latitude: 47.6097, Longitude: -122.3422, RadiusMeters: 100},
+	})
+	coordinates := func(lat, lon float64) (*float64, *float64) { return &lat, &lon }
+
+	// About 50 meters from the office
+	lat, lon := coordinates(47.6209, -122.3490)
+	task, err := service.Capture(&MobileCapture{
+		Text:     "Replace badge reader +facilities -p high\nThe one by the loading dock",
+		Latitude: lat, Longitude: lon,
+		Photo: []byte("\x89PNG\r\n\x1a\n"), PhotoName: "IMG_0001.png", PhotoContentType: "image/png",
+	})
+	if err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+	if task.Name != "Replace badge reader facilities" || task.Priority != "high" {
+		t.Errorf("Expected the first line to be parsed as a quick-add line, got %q (%s)", task.Name, task.Priority)
+	}
+	if strings.Join(task.Tags, ",") != "facilities,location:office" {
+		t.Errorf("Expected the office location tag, got %v", task.Tags)
+	}
+	if !strings.HasPrefix(task.Description, "The one by the loading dock\n\nCaptured at 47.62090, -122.34900") {
+		t.Errorf("Expected the rest of the text and the coordinates in the description, got %q", task.Description)
+	}
+	if len(task.Attachments) != 1 || task.Attachments[0].OriginalName != "IMG_0001.png" {
+		t.Errorf("Expected the photo to be attached, got %+v", task.Attachments)
+	}
+
+	// Outside every place, and a name reported by the phone wins over places
+	lat, lon = coordinates(40.7128, -74.0060)
+	if task, err := service.Capture(&MobileCapture{Text: "Call plumber", Latitude: lat, Longitude: lon}); err != nil || strings.Join(task.Tags, ",") != "location:40.71,-74.01" {
+		t.Errorf("Expected rounded coordinates as the location, got %v (%v)", task.Tags, err)
+	}
+	if task, err := service.Capture(&MobileCapture{Text: "Buy filters", Latitude: lat, Longitude: lon, Location: "Hardware Store"}); err != nil || strings.Join(task.Tags, ",") != "location:hardware-store" {
+		t.Errorf("Expected the reported place name as the location, got %v (%v)", task.Tags, err)
+	}
+	if task, err := service.Capture(&MobileCapture{Text: "No location"}); err != nil || len(task.Tags) != 0 {
+		t.Errorf("Expected no location tag without a location, got %v (%v)", task.Tags, err)
+	}
+
+	if _, err := service.Capture(&MobileCapture{Text: "Half a location", Latitude: lat}); !errors.Is(err, ErrInvalidCoordinates) {
+		t.Errorf("Expected ErrInvalidCoordinates for a missing longitude, got %v", err)
+	}
+	if _, err := service.Capture(&MobileCapture{Text: "Not a photo", Photo: []byte("MZ"), PhotoContentType: "application/x-msdownload"}); !errors.Is(err, ErrInvalidPhoto) {
+		t.Errorf("Expected ErrInvalidPhoto for a non-image, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+// ContactService maintains the directory of people who write in by email
+type ContactService struct {
+	repo *repository.ContactRepository
+}
+
+// NewContactService creates a new contact service
+func NewContactService(repo *repository.ContactRepository) *ContactService {
+	return &ContactService{repo: repo}
+}
+
+// RecordSender creates or updates the contact for an email sender and links it to a task.
+// Existing names and organizations are kept so manual edits are not overwritten.
+func (s *ContactService) RecordSender(name, email string, taskID uint) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil
+	}
+
+	contact, err := s.repo.GetByEmail(email)
+	if err != nil {
+		return err
+	}
+	if contact == nil {
+		contact = &models.Contact{Email: email}
+	}
+
+	if contact.Name == "" {
+		contact.Name = strings.TrimSpace(name)
+	}
+	if contact.Organization == "" {
+		contact.Organization = organizationFromEmail(email)
+	}
+
+	if err := s.repo.Save(contact); err != nil {
+		return err
+	}
+
+	return s.repo.LinkTask(contact, taskID)
+}
+
+// GetContacts returns all contacts with their task counts
+func (s *ContactService) GetContacts() ([]*models.Contact, error) {
+	return s.repo.List()
+}
+
+// GetContact returns a contact and its task history, or nil if it does not exist
+func (s *ContactService) GetContact(id uint) (*models.Contact, error) {
+	return s.repo.GetByID(id)
+}
+
+// organizationFromEmail guesses an organization from the sender's email domain
+func organizationFromEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 || at == len(email)-1 {
+		return ""
+	}
+	return email[at+1:]
+}
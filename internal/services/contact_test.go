@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestContactService_RecordSender(t *testing.T) {
+	db := setupTestDB(t)
+	taskService := NewTaskService(repository.NewTaskRepository(db), nil)
+	service := NewContactService(repository.NewContactRepository(db))
+
+	first, err := taskService.CreateTask("Printer broken")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	second, err := taskService.CreateTask("VPN access")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	if err := service.RecordSender("Jane Doe", "Jane@Example.com", first.ID); err != nil {
+		t.Fatalf("Failed to record sender: %v", err)
+	}
+	// Same sender again, different casing and no display name
+	if err := service.RecordSender("", "jane@example.com", second.ID); err != nil {
+		t.Fatalf("Failed to record sender: %v", err)
+	}
+	// Linking the same task twice must not duplicate it
+	if err := service.RecordSender("Jane Doe", "jane@example.com", second.ID); err != nil {
+		t.Fatalf("Failed to record sender: %v", err)
+	}
+
+	contacts, err := service.GetContacts()
+	if err != nil {
+		t.Fatalf("Failed to get contacts: %v", err)
+	}
+	if len(contacts) != 1 {
+		t.Fatalf("Expected 1 contact, got %d", len(contacts))
+	}
+
+	contact := contacts[0]
+	if contact.Email != "jane@example.com" {
+		t.Errorf("Expected normalized email, got %s", contact.Email)
+	}
+	if contact.Name != "Jane Doe" {
+		t.Errorf("Expected name Jane Doe, got %s", contact.Name)
+	}
+	if contact.Organization != "example.com" {
+		t.Errorf("Expected organization example.com, got %s", contact.Organization)
+	}
+	if contact.TaskCount != 2 {
+		t.Errorf("Expected 2 linked tasks, got %d", contact.TaskCount)
+	}
+
+	detail, err := service.GetContact(contact.ID)
+	if err != nil {
+		t.Fatalf("Failed to get contact: %v", err)
+	}
+	if len(detail.Tasks) != 2 {
+		t.Fatalf("Expected 2 tasks in history, got %d", len(detail.Tasks))
+	}
+
+	missing, err := service.GetContact(contact.ID + 100)
+	if err != nil {
+		t.Fatalf("Unexpected error for missing contact: %v", err)
+	}
+	if missing != nil {
+		t.Error("Expected nil for missing contact")
+	}
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+// NoContext is the context filter value that matches tasks without a context
+const NoContext = "none"
+
+// NormalizeContext turns a context as typed, such as "Home" or "@ data center",
+// into the stored form "@home" or "@data-center". Contexts say where a task
+// can be worked on, GTD style, and are kept apart from the client-oriented
+// tags. An empty context stays empty.
+func NormalizeContext(context string) string {
+	name := strings.Join(strings.Fields(strings.TrimLeft(strings.TrimSpace(context), "@")), "-")
+	if name == "" {
+		return ""
+	}
+	return "@" + strings.ToLower(name)
+}
+
+// MatchesContext reports whether a task's context passes a context filter:
+// empty matches every task, NoContext tasks without one, anything else is
+// normalized and compared
+func MatchesContext(taskContext, filter string) bool {
+	switch {
+	case filter == "":
+		return true
+	case strings.EqualFold(filter, NoContext):
+		return taskContext == ""
+	default:
+		return taskContext == NormalizeContext(filter)
+	}
+}
+
+// GetContexts returns the contexts in use with their open task counts, for
+// the quick context filters
+func (s *TaskService) GetContexts() ([]repository.ContextCount, error) {
+	return s.repo.GetContextCounts()
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestNormalizeContext(t *testing.T) {
+	tests := map[string]string{
+		"":                "",
+		"  ":              "",
+		"@":               "",
+		"home":            "@home",
+		"@Office":         "@office",
+		" @ data  center": "@data-center",
+		"@@phone":         "@phone",
+	}
+	for input, want := range tests {
+		if got := NormalizeContext(input); got != want {
+			t.Errorf("NormalizeContext(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	if !MatchesContext("@home", "") || !MatchesContext("@home", "Home") || MatchesContext("@home", "@office") {
+		t.Errorf("Expected contexts to match by their normalized form")
+	}
+	if !MatchesContext("", NoContext) || MatchesContext("@home", NoContext) {
+		t.Errorf("Expected %q to match only tasks without a context", NoContext)
+	}
+}
+
+func TestTaskService_GetContexts(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	create := func(name, context string, status models.TaskStatus) {
+		task, err := service.CreateTask(name)
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		task.Context = context
+		task.Status = status
+		if err := service.UpdateTask(task); err != nil {
+			t.Fatalf("Failed to update task: %v", err)
+		}
+	}
+	create("Swap disk", "Datacenter", models.TaskStatusOpen)
+	create("Rack server", "@datacenter", models.TaskStatusResolved)
+	create("Call vendor", "phone", models.TaskStatusInProgress)
+	create("Write report", "", models.TaskStatusOpen)
+
+	contexts, err := service.GetContexts()
+	if err != nil {
+		t.Fatalf("Failed to get contexts: %v", err)
+	}
+	want := []repository.ContextCount{{Context: "@datacenter", OpenTasks: 1}, {Context: "@phone", OpenTasks: 1}}
+	if len(contexts) != len(want) || contexts[0] != want[0] || contexts[1] != want[1] {
+		t.Errorf("Expected %+v, got %+v", want, contexts)
+	}
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/quickadd"
+)
+
+var (
+	ErrEmptyCSV          = errors.New("the CSV file has no rows below its header")
+	ErrCSVNameColumn     = errors.New("a column must be mapped to the task name")
+	ErrCSVColumnNotFound = errors.New("a mapped column is not in the CSV file")
+)
+
+// CSVTable is a CSV file read for importing tasks: its header row and the
+// rows below it
+type CSVTable struct {
+	Header []string
+	Rows   [][]string
+}
+
+// ReadCSVImport reads a CSV file whose first row names its columns. Commas,
+// semicolons and tabs are recognized as the delimiter, from the header row.
+func ReadCSVImport(data []byte) (*CSVTable, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = ','
+	for _, delimiter := range []rune{';', '\t'} {
+		if bytes.Count(header, []byte(string(delimiter))) > bytes.Count(header, []byte(string(reader.Comma))) {
+			reader.Comma = delimiter
+		}
+	}
+	// Spreadsheets leave trailing cells off short rows
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, ErrEmptyCSV
+	}
+	if len(records)-1 > MaxBulkEntries {
+		return nil, ErrTooManyBulkEntries
+	}
+
+	table := &CSVTable{Header: records[0]}
+	for i := range table.Header {
+		table.Header[i] = strings.TrimSpace(table.Header[i])
+	}
+	for _, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) != "" {
+			table.Rows = append(table.Rows, record)
+		}
+	}
+	if len(table.Rows) == 0 {
+		return nil, ErrEmptyCSV
+	}
+	return table, nil
+}
+
+// CSVImportMapping says how the columns of a CSV file become tasks. Columns
+// are indexes into the header; -1 leaves a field unset.
+type CSVImportMapping struct {
+	Name     int
+	Tags     int
+	Priority int
+	DueDate  int
+	Context  int
+	Size     int
+
+	// TagDelimiter splits the tags column into tags; empty means a comma
+	TagDelimiter string
+	// DueDateFormat is the Go layout of the due date column, such as
+	// 01/02/2006; empty accepts what due dates typed in JATS accept
+	DueDateFormat string
+	// Priorities maps values of the priority column, lower-cased, to
+	// priorities; an empty priority leaves the task without one. Values not
+	// mapped must be low, medium or high.
+	Priorities map[string]models.TaskPriority
+}
+
+// csvColumnNames are the header names each field is guessed from
+var csvColumnNames = []struct {
+	column func(m *CSVImportMapping) *int
+	names  []string
+}{
+	{func(m *CSVImportMapping) *int { return &m.Name }, []string{"name", "title", "task", "summary", "subject"}},
+	{func(m *CSVImportMapping) *int { return &m.Tags }, []string{"tags", "tag", "labels", "label"}},
+	{func(m *CSVImportMapping) *int { return &m.Priority }, []string{"priority", "importance"}},
+	{func(m *CSVImportMapping) *int { return &m.DueDate }, []string{"due date", "due", "due_date", "deadline"}},
+	{func(m *CSVImportMapping) *int { return &m.Context }, []string{"context"}},
+	{func(m *CSVImportMapping) *int { return &m.Size }, []string{"size", "points", "story points", "estimate"}},
+}
+
+// GuessCSVMapping maps the columns whose header names a task field, such as
+// Title or Due date
+func GuessCSVMapping(header []string) CSVImportMapping {
+	mapping := CSVImportMapping{Name: -1, Tags: -1, Priority: -1, DueDate: -1, Context: -1, Size: -1}
+	for _, field := range csvColumnNames {
+		column := field.column(&mapping)
+		for _, name := range field.names {
+			for i, h := range header {
+				if *column < 0 && strings.EqualFold(strings.TrimSpace(h), name) {
+					*column = i
+				}
+			}
+		}
+	}
+	return mapping
+}
+
+// CSVImportRow is a row of a CSV file mapped to a task, or the reason it
+// cannot be imported
+type CSVImportRow struct {
+	Line   int // line of the row in the file, counting the header as 1
+	Values []string
+	Task   *quickadd.Task
+	Error  string
+}
+
+// PriorityValues returns the distinct values of the mapped priority column
+// that are not already a priority, lower-cased, in order of appearance
+func (m CSVImportMapping) PriorityValues(table *CSVTable) []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, row := range table.Rows {
+		value := strings.ToLower(strings.TrimSpace(csvCell(row, m.Priority)))
+		switch models.TaskPriority(value) {
+		case "", models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh:
+			continue
+		}
+		if !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// MapCSVRows turns each row of a CSV file into a task, or says why it cannot
+func MapCSVRows(table *CSVTable, mapping CSVImportMapping) ([]CSVImportRow, error) {
+	if mapping.Name < 0 {
+		return nil, ErrCSVNameColumn
+	}
+	for _, column := range []int{mapping.Name, mapping.Tags, mapping.Priority, mapping.DueDate, mapping.Context, mapping.Size} {
+		if column >= len(table.Header) {
+			return nil, ErrCSVColumnNotFound
+		}
+	}
+
+	rows := make([]CSVImportRow, len(table.Rows))
+	for i, values := range table.Rows {
+		rows[i] = CSVImportRow{Line: i + 2, Values: values}
+		task, err := mapping.task(values)
+		if err != nil {
+			rows[i].Error = err.Error()
+			continue
+		}
+		rows[i].Task = task
+	}
+	return rows, nil
+}
+
+func (m CSVImportMapping) task(values []string) (*quickadd.Task, error) {
+	task := &quickadd.Task{Name: strings.TrimSpace(csvCell(values, m.Name))}
+	if task.Name == "" {
+		return nil, errors.New("the name is empty")
+	}
+
+	delimiter := m.TagDelimiter
+	if delimiter == "" {
+		delimiter = ","
+	}
+	if tags := strings.TrimSpace(csvCell(values, m.Tags)); tags != "" {
+		for _, tag := range strings.Split(tags, delimiter) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				task.Tags = append(task.Tags, tag)
+			}
+		}
+	}
+
+	priority := strings.ToLower(strings.TrimSpace(csvCell(values, m.Priority)))
+	if mapped, ok := m.Priorities[priority]; ok {
+		task.Priority = mapped
+	} else {
+		switch p := models.TaskPriority(priority); p {
+		case "", models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh:
+			task.Priority = p
+		default:
+			return nil, fmt.Errorf("priority %q is not mapped to low, medium or high", priority)
+		}
+	}
+
+	if due := strings.TrimSpace(csvCell(values, m.DueDate)); due != "" {
+		if m.DueDateFormat != "" {
+			parsed, err := time.Parse(m.DueDateFormat, due)
+			if err != nil {
+				return nil, fmt.Errorf("due date %q does not match %s", due, m.DueDateFormat)
+			}
+			due = parsed.Format(time.DateOnly)
+		} else if _, err := ParseDueDate(due); err != nil {
+			return nil, fmt.Errorf("invalid due date %q", due)
+		}
+		task.DueDate = due
+	}
+
+	task.Context = NormalizeContext(csvCell(values, m.Context))
+
+	size, err := ParseTaskSize(csvCell(values, m.Size))
+	if err != nil {
+		return nil, err
+	}
+	task.Size = size
+
+	return task, nil
+}
+
+// csvCell returns a cell of a row, empty for an unmapped column or a short row
+func csvCell(values []string, column int) string {
+	if column < 0 || column >= len(values) {
+		return ""
+	}
+	return values[column]
+}
+
+// ImportCSVRows creates the tasks of the rows that mapped, in order, through
+// the bulk task import. Rows that did not map are left as they are. If
+// creating a task fails, its row and those after it get the error and the
+// tasks created so far are returned.
+func (s *TaskService) ImportCSVRows(rows []CSVImportRow) ([]*models.Task, error) {
+	var entries []*quickadd.Task
+	var indexes []int
+	for i, row := range rows {
+		if row.Error == "" {
+			entries = append(entries, row.Task)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	tasks, err := s.CreateQuickTasks(entries)
+	if err != nil {
+		// The bulk error names the entry; the row's line says the same
+		message := err.Error()
+		if _, rest, ok := strings.Cut(message, ": "); ok && strings.HasPrefix(message, "entry ") {
+			message = rest
+		}
+		for n, i := range indexes[len(tasks):] {
+			rows[i].Error = message
+			if n > 0 {
+				rows[i].Error = "not imported after an earlier row failed"
+			}
+		}
+		return tasks, err
+	}
+	return tasks, nil
+}
+
+// CSVErrorReport returns the rows that failed as CSV, with their line and
+// error ahead of the original columns, so they can be fixed and imported again
+func CSVErrorReport(header []string, rows []CSVImportRow) []byte {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	w.Write(append([]string{"line", "error"}, header...))
+	for _, row := range rows {
+		if row.Error != "" {
+			w.Write(append([]string{strconv.Itoa(row.Line), row.Error}, row.Values...))
+		}
+	}
+	w.Flush()
+	return b.Bytes()
+}
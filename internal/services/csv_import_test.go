@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestReadCSVImport(t *testing.T) {
+	table, err := ReadCSVImport([]byte("\xef\xbb\xbfTitle;Labels;Due\nBuy milk;home|errand;03/04/2025\n;;\nCall Bob\n"))
+	if err != nil {
+		t.Fatalf("ReadCSVImport failed: %v", err)
+	}
+	if strings.Join(table.Header, ",") != "Title,Labels,Due" {
+		t.Errorf("Expected the BOM stripped and semicolons recognized, got header %q", table.Header)
+	}
+	if len(table.Rows) != 2 || table.Rows[1][0] != "Call Bob" {
+		t.Errorf("Expected blank rows skipped and short rows kept, got %q", table.Rows)
+	}
+
+	for _, data := range []string{"", "Title\n", "Title\n,\n"} {
+		if _, err := ReadCSVImport([]byte(data)); !errors.Is(err, ErrEmptyCSV) {
+			t.Errorf("%q: expected ErrEmptyCSV, got %v", data, err)
+		}
+	}
+	if _, err := ReadCSVImport([]byte("Title\n\"unterminated\n")); err == nil {
+		t.Error("Expected malformed CSV to fail")
+	}
+}
+
+func TestMapCSVRows(t *testing.T) {
+	table, err := ReadCSVImport([]byte("Title,Labels,Importance,Due,Notes\n" +
+		"Buy milk,home|errand,P1,03/04/2025,x\n" +
+		",home,,,\n" +
+		"Call Bob,,urgent,,\n" +
+		"Pay rent,,low,2025-13-45,\n"))
+	if err != nil {
+		t.Fatalf("ReadCSVImport failed: %v", err)
+	}
+
+	mapping := GuessCSVMapping(table.Header)
+	if mapping.Name != 0 || mapping.Tags != 1 || mapping.Priority != 2 || mapping.DueDate != 3 || mapping.Context != -1 {
+		t.Fatalf("Unexpected guessed mapping %+v", mapping)
+	}
+	if values := mapping.PriorityValues(table); strings.Join(values, ",") != "p1,urgent" {
+		t.Errorf("Expected priority values p1 and urgent, got %q", values)
+	}
+
+	mapping.TagDelimiter = "|"
+	mapping.DueDateFormat = "01/02/2006"
+	mapping.Priorities = map[string]models.TaskPriority{"p1": models.TaskPriorityHigh}
+	rows, err := MapCSVRows(table, mapping)
+	if err != nil {
+		t.Fatalf("MapCSVRows failed: %v", err)
+	}
+
+	task := rows[0].Task
+	if task == nil || task.Name != "Buy milk" || strings.Join(task.Tags, ",") != "home,errand" ||
+		task.Priority != models.TaskPriorityHigh || task.DueDate != "2025-03-04" {
+		t.Errorf("Unexpected first task %+v (error %q)", task, rows[0].Error)
+	}
+	for i, want := range []string{"", "the name is empty", `priority "urgent" is not mapped`, `does not match`} {
+		if !strings.Contains(rows[i].Error, want) || (want == "") != (rows[i].Error == "") {
+			t.Errorf("Row %d: expected error %q, got %q", i, want, rows[i].Error)
+		}
+		if rows[i].Line != i+2 {
+			t.Errorf("Row %d: expected line %d, got %d", i, i+2, rows[i].Line)
+		}
+	}
+
+	if _, err := MapCSVRows(table, CSVImportMapping{Name: -1}); !errors.Is(err, ErrCSVNameColumn) {
+		t.Errorf("Expected ErrCSVNameColumn, got %v", err)
+	}
+	if _, err := MapCSVRows(table, CSVImportMapping{Name: 0, Tags: 9}); !errors.Is(err, ErrCSVColumnNotFound) {
+		t.Errorf("Expected ErrCSVColumnNotFound, got %v", err)
+	}
+}
+
+func TestTaskService_ImportCSVRows(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	table, err := ReadCSVImport([]byte("name,tags,priority\nFirst,a,high\n,b,\nSecond,,\n"))
+	if err != nil {
+		t.Fatalf("ReadCSVImport failed: %v", err)
+	}
+	rows, err := MapCSVRows(table, GuessCSVMapping(table.Header))
+	if err != nil {
+		t.Fatalf("MapCSVRows failed: %v", err)
+	}
+
+	tasks, err := service.ImportCSVRows(rows)
+	if err != nil {
+		t.Fatalf("ImportCSVRows failed: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Name != "First" || tasks[0].Priority != models.TaskPriorityHigh || tasks[1].Name != "Second" {
+		t.Fatalf("Expected the two valid rows imported in order, got %+v", tasks)
+	}
+
+	report := string(CSVErrorReport(table.Header, rows))
+	if want := "line,error,name,tags,priority\n3,the name is empty,,b,\n"; report != want {
+		t.Errorf("Unexpected error report:\n%s\nwant:\n%s", report, want)
+	}
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var ErrInvalidDashboardLayout = errors.New("invalid dashboard layout")
+
+const (
+	// maxDashboardWidgets caps how many widgets a dashboard can have
+	maxDashboardWidgets = 24
+	// maxDashboardChartDays caps the range of a saved query chart
+	maxDashboardChartDays = 90
+	// maxDashboardActivity caps how many events an activity widget lists
+	maxDashboardActivity = 50
+)
+
+var dashboardStatuses = map[models.TaskStatus]bool{
+	models.TaskStatusOpen:          true,
+	models.TaskStatusInProgress:    true,
+	models.TaskStatusPendingReview: true,
+	models.TaskStatusResolved:      true,
+	models.TaskStatusClosed:        true,
+}
+
+// DefaultDashboardLayout is shown to users who have not customized their dashboard
+func DefaultDashboardLayout() *models.DashboardLayout {
+	return &models.DashboardLayout{Widgets: []models.DashboardWidget{
+		{Type: models.DashboardWidgetTaskCount, Title: "Open", Status: models.TaskStatusOpen, Width: 1},
+		{Type: models.DashboardWidgetTaskCount, Title: "In progress", Status: models.TaskStatusInProgress, Width: 1},
+		{Type: models.DashboardWidgetTimeLogged, Title: "My time", Width: 1},
+		{Type: models.DashboardWidgetActivity, Title: "Recent activity", Limit: 10, Width: 3},
+	}}
+}
+
+// NormalizeDashboardLayout validates a layout and fills in widget defaults
+func (s *TaskService) NormalizeDashboardLayout(layout *models.DashboardLayout) error {
+	if layout == nil {
+		return fmt.Errorf("%w: layout is required", ErrInvalidDashboardLayout)
+	}
+	if len(layout.Widgets) > maxDashboardWidgets {
+		return fmt.Errorf("%w: at most %d widgets are allowed", ErrInvalidDashboardLayout, maxDashboardWidgets)
+	}
+
+	for i := range layout.Widgets {
+		widget := &layout.Widgets[i]
+		widget.Title = strings.TrimSpace(widget.Title)
+		if widget.Width < 1 {
+			widget.Width = 1
+		}
+		if widget.Width > 3 {
+			widget.Width = 3
+		}
+
+		switch widget.Type {
+		case models.DashboardWidgetTaskCount:
+			if widget.Status != "" && !dashboardStatuses[widget.Status] {
+				return fmt.Errorf("%w: widget %d has unknown status %q", ErrInvalidDashboardLayout, i+1, widget.Status)
+			}
+		case models.DashboardWidgetQueryChart:
+			if widget.SavedQueryID == 0 {
+				return fmt.Errorf("%w: widget %d needs a saved_query_id", ErrInvalidDashboardLayout, i+1)
+			}
+			if widget.Days <= 0 {
+				widget.Days = 7
+			}
+			if widget.Days > maxDashboardChartDays {
+				widget.Days = maxDashboardChartDays
+			}
+		case models.DashboardWidgetActivity:
+			if widget.Limit <= 0 {
+				widget.Limit = 10
+			}
+			if widget.Limit > maxDashboardActivity {
+				widget.Limit = maxDashboardActivity
+			}
+		case models.DashboardWidgetTimeLogged:
+		default:
+			return fmt.Errorf("%w: widget %d has unknown type %q", ErrInvalidDashboardLayout, i+1, widget.Type)
+		}
+
+		if widget.SavedQueryID != 0 {
+			if _, err := s.repo.GetSavedQueryByID(widget.SavedQueryID); err != nil {
+				return fmt.Errorf("%w: widget %d refers to unknown saved query %d", ErrInvalidDashboardLayout, i+1, widget.SavedQueryID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DashboardChartPoint is the time logged on one day in a saved query chart
+type DashboardChartPoint struct {
+	Date    string `json:"date"`
+	Minutes int    `json:"minutes"`
+}
+
+// DashboardTimeLogged totals the time logged today and in the current week
+type DashboardTimeLogged struct {
+	TodayMinutes int `json:"today_minutes"`
+	WeekMinutes  int `json:"week_minutes"`
+}
+
+// DashboardWidgetData is a widget together with the data it displays. A widget
+// whose data cannot be loaded (e.g. its saved query was deleted) carries an Error.
+type DashboardWidgetData struct {
+	Widget     models.DashboardWidget `json:"widget"`
+	Title      string                 `json:"title"`
+	Count      *int                   `json:"count,omitempty"`
+	Chart      []DashboardChartPoint  `json:"chart,omitempty"`
+	Activity   []ActivityEvent        `json:"activity,omitempty"`
+	TimeLogged *DashboardTimeLogged   `json:"time_logged,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// Dashboard is a rendered layout
+type Dashboard struct {
+	Layout  *models.DashboardLayout `json:"layout"`
+	Widgets []DashboardWidgetData   `json:"widgets"`
+}
+
+// GetDashboard loads the data for every widget of a layout; nil uses the default layout
+func (s *TaskService) GetDashboard(layout *models.DashboardLayout, now time.Time) (*Dashboard, error) {
+	if layout == nil {
+		layout = DefaultDashboardLayout()
+	}
+
+	tasks, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &Dashboard{Layout: layout, Widgets: []DashboardWidgetData{}}
+	for _, widget := range layout.Widgets {
+		data := DashboardWidgetData{Widget: widget, Title: widget.Title}
+
+		var query *models.SavedQuery
+		if widget.SavedQueryID != 0 {
+			query, err = s.repo.GetSavedQueryByID(widget.SavedQueryID)
+			if err != nil {
+				data.Error = "Saved query not found"
+				dashboard.Widgets = append(dashboard.Widgets, data)
+				continue
+			}
+		}
+		if data.Title == "" {
+			data.Title = defaultWidgetTitle(widget, query)
+		}
+
+		switch widget.Type {
+		case models.DashboardWidgetTaskCount:
+			count := 0
+			for _, task := range tasks {
+				if widget.Status != "" && task.Status != widget.Status {
+					continue
+				}
+				if query != nil && !s.matchesSavedQuery(task, query) {
+					continue
+				}
+				count++
+			}
+			data.Count = &count
+
+		case models.DashboardWidgetQueryChart:
+			data.Chart, err = s.dashboardQueryChart(tasks, query, widget.Days, now)
+			if err != nil {
+				return nil, err
+			}
+
+		case models.DashboardWidgetActivity:
+			limit := widget.Limit
+			if limit <= 0 {
+				limit = 10
+			}
+			events, err := s.GetActivity(ActivityFilter{Since: now.Add(-DefaultActivityWindow), Until: now})
+			if err != nil {
+				return nil, err
+			}
+			if len(events) > limit {
+				events = events[:limit]
+			}
+			data.Activity = events
+
+		case models.DashboardWidgetTimeLogged:
+			data.TimeLogged, err = s.dashboardTimeLogged(now)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		dashboard.Widgets = append(dashboard.Widgets, data)
+	}
+
+	return dashboard, nil
+}
+
+func defaultWidgetTitle(widget models.DashboardWidget, query *models.SavedQuery) string {
+	switch widget.Type {
+	case models.DashboardWidgetTaskCount:
+		title := "Tasks"
+		if widget.Status != "" {
+			title = strings.ToUpper(string(widget.Status[:1])) + strings.ReplaceAll(string(widget.Status[1:]), "-", " ") + " tasks"
+		}
+		if query != nil {
+			title += " in " + query.Name
+		}
+		return title
+	case models.DashboardWidgetQueryChart:
+		if query != nil {
+			return query.Name
+		}
+		return "Saved query"
+	case models.DashboardWidgetActivity:
+		return "Recent activity"
+	case models.DashboardWidgetTimeLogged:
+		return "My time"
+	}
+	return string(widget.Type)
+}
+
+// dashboardQueryChart totals the time logged per day on tasks matching the query
+func (s *TaskService) dashboardQueryChart(tasks []*models.Task, query *models.SavedQuery, days int, now time.Time) ([]DashboardChartPoint, error) {
+	if days <= 0 {
+		days = 7
+	}
+	start := startOfDay(now).AddDate(0, 0, -(days - 1))
+
+	matching := make(map[uint]bool)
+	for _, task := range tasks {
+		if s.matchesSavedQuery(task, query) {
+			matching[task.ID] = true
+		}
+	}
+
+	entries, err := s.repo.GetTimeEntriesBetween(start, startOfDay(now).AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	perDay := make(map[string]int)
+	for _, entry := range entries {
+		if matching[entry.TaskID] {
+			perDay[entry.CreatedAt.Format("2006-01-02")] += entry.Duration
+		}
+	}
+
+	points := make([]DashboardChartPoint, 0, days)
+	for day := start; len(points) < days; day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		points = append(points, DashboardChartPoint{Date: date, Minutes: perDay[date]})
+	}
+	return points, nil
+}
+
+// dashboardTimeLogged totals time entries for today and the week so far (from Monday)
+func (s *TaskService) dashboardTimeLogged(now time.Time) (*DashboardTimeLogged, error) {
+	today := startOfDay(now)
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+
+	entries, err := s.repo.GetTimeEntriesBetween(weekStart, today.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	logged := &DashboardTimeLogged{}
+	for _, entry := range entries {
+		logged.WeekMinutes += entry.Duration
+		if !entry.CreatedAt.Before(today) {
+			logged.TodayMinutes += entry.Duration
+		}
+	}
+	return logged, nil
+}
+
+// SetDashboardLayout saves a user's dashboard layout; nil resets it to the default
+func (s *AuthService) SetDashboardLayout(userID uint, layout *models.DashboardLayout) (*models.User, error) {
+	user, err := s.authRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	user.DashboardLayout = layout
+	if err := s.authRepo.UpdateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var (
+	ErrDependencySelf     = errors.New("a task cannot block itself")
+	ErrDependencyCycle    = errors.New("the dependency would make the tasks block each other")
+	ErrDependencyNotFound = errors.New("the tasks do not depend on each other")
+	ErrOpenBlockers       = errors.New("task is blocked by open tasks")
+)
+
+// AddDependency records that one task blocks another. Dependencies may not
+// form a cycle, directly or through other tasks.
+func (s *TaskService) AddDependency(blockingTaskID, blockedTaskID uint) error {
+	if blockingTaskID == blockedTaskID {
+		return ErrDependencySelf
+	}
+	for _, id := range []uint{blockingTaskID, blockedTaskID} {
+		if _, err := s.repo.GetByID(id); err != nil {
+			return err
+		}
+	}
+
+	dependencies, err := s.repo.GetTaskDependencies()
+	if err != nil {
+		return err
+	}
+	if blocks(dependencies, blockedTaskID, blockingTaskID) {
+		return ErrDependencyCycle
+	}
+
+	return s.repo.AddTaskDependency(&models.TaskDependency{BlockingTaskID: blockingTaskID, BlockedTaskID: blockedTaskID})
+}
+
+// RemoveDependency removes the dependency between two tasks, whichever blocks
+// the other
+func (s *TaskService) RemoveDependency(taskID, otherTaskID uint) error {
+	removed, err := s.repo.RemoveTaskDependency(taskID, otherTaskID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrDependencyNotFound
+	}
+	return nil
+}
+
+// blocks reports whether a task blocks another, directly or through the tasks
+// in between
+func blocks(dependencies []*models.TaskDependency, from, to uint) bool {
+	blocked := make(map[uint][]uint)
+	for _, dependency := range dependencies {
+		blocked[dependency.BlockingTaskID] = append(blocked[dependency.BlockingTaskID], dependency.BlockedTaskID)
+	}
+
+	seen := map[uint]bool{from: true}
+	queue := []uint{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range blocked[id] {
+			if next == to {
+				return true
+			}
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// checkBlockers returns ErrOpenBlockers, naming them, if a task is blocked by
+// tasks that are neither resolved nor closed
+func (s *TaskService) checkBlockers(taskID uint) error {
+	blockers, err := s.repo.GetBlockingTasks(taskID)
+	if err != nil {
+		return err
+	}
+
+	var open []string
+	for _, blocker := range blockers {
+		if blocker.Status != models.TaskStatusResolved && blocker.Status != models.TaskStatusClosed {
+			open = append(open, fmt.Sprintf("#%d %s", blocker.ID, blocker.Name))
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("%w: %s", ErrOpenBlockers, strings.Join(open, ", "))
+	}
+	return nil
+}
+
+// resolving reports whether a status change resolves a task, directly or by
+// sending it for review
+func resolving(from, to models.TaskStatus) bool {
+	done := func(status models.TaskStatus) bool {
+		return status == models.TaskStatusResolved || status == models.TaskStatusPendingReview
+	}
+	return done(to) && !done(from)
+}
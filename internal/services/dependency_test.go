@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_Dependencies(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	order, _ := service.CreateTask("Order new firewall")
+	install, _ := service.CreateTask("Install firewall")
+	cutover, _ := service.CreateTask("Cut over traffic")
+
+	if err := service.AddDependency(order.ID, install.ID); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+	if err := service.AddDependency(install.ID, cutover.ID); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+	if err := service.AddDependency(order.ID, install.ID); err != nil {
+		t.Errorf("Expected adding a dependency twice to be a no-op, got %v", err)
+	}
+	if err := service.AddDependency(cutover.ID, order.ID); err != ErrDependencyCycle {
+		t.Errorf("Expected ErrDependencyCycle through the middle task, got %v", err)
+	}
+	if err := service.AddDependency(order.ID, order.ID); err != ErrDependencySelf {
+		t.Errorf("Expected ErrDependencySelf, got %v", err)
+	}
+
+	task, _ := service.GetTask(install.ID)
+	if len(task.BlockedBy) != 1 || task.BlockedBy[0].ID != order.ID || len(task.Blocks) != 1 || task.Blocks[0].ID != cutover.ID {
+		t.Errorf("Expected install blocked by order and blocking cutover, got %+v / %+v", task.BlockedBy, task.Blocks)
+	}
+
+	task.Status = models.TaskStatusResolved
+	err := service.UpdateTask(task)
+	if !errors.Is(err, ErrOpenBlockers) || !strings.Contains(err.Error(), "#1 Order new firewall") {
+		t.Fatalf("Expected ErrOpenBlockers naming the blocker, got %v", err)
+	}
+
+	// Closing is not resolving, and resolved blockers no longer block
+	order.Status = models.TaskStatusClosed
+	if err := service.UpdateTask(order); err != nil {
+		t.Fatalf("Failed to close blocker: %v", err)
+	}
+	task.Status = models.TaskStatusResolved
+	if err := service.UpdateTask(task); err != nil {
+		t.Errorf("Expected the task to resolve once its blocker closed, got %v", err)
+	}
+
+	if err := service.RemoveDependency(cutover.ID, install.ID); err != nil {
+		t.Errorf("Failed to remove dependency from the blocked side: %v", err)
+	}
+	if err := service.RemoveDependency(cutover.ID, install.ID); err != ErrDependencyNotFound {
+		t.Errorf("Expected ErrDependencyNotFound, got %v", err)
+	}
+}
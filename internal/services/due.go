@@ -0,0 +1,55 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+// ParseDueDate parses a due date such as "friday" or "2025-12-01" (see
+// utils.ParseDate) into midnight of that day. An empty value means no due date.
+func ParseDueDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := utils.ParseDate(value)
+	if err != nil {
+		return nil, err
+	}
+	due := startOfDay(parsed)
+	return &due, nil
+}
+
+// CountOverdue returns how many of the tasks are overdue at now
+func CountOverdue(tasks []*models.Task, now time.Time) int {
+	count := 0
+	for _, task := range tasks {
+		if task.IsOverdue(now) {
+			count++
+		}
+	}
+	return count
+}
+
+// GetOverdueTasks returns the open and in-progress tasks whose due day has
+// passed, longest overdue first
+func (s *TaskService) GetOverdueTasks() ([]*models.Task, error) {
+	tasks, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var overdue []*models.Task
+	for _, task := range tasks {
+		if task.IsOverdue(now) {
+			overdue = append(overdue, task)
+		}
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return overdue[i].DueDate.Before(*overdue[j].DueDate)
+	})
+	return overdue, nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_GetOverdueTasks(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	today := startOfDay(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+	lastWeek := today.AddDate(0, 0, -7)
+	tasks := []*models.Task{
+		{Name: "Due yesterday", Status: models.TaskStatusOpen, DueDate: &yesterday},
+		{Name: "Due last week", Status: models.TaskStatusInProgress, DueDate: &lastWeek},
+		{Name: "Due today", Status: models.TaskStatusOpen, DueDate: &today},
+		{Name: "Resolved late", Status: models.TaskStatusResolved, DueDate: &lastWeek},
+		{Name: "No due date", Status: models.TaskStatusOpen},
+	}
+	for _, task := range tasks {
+		if err := repo.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	overdue, err := service.GetOverdueTasks()
+	if err != nil {
+		t.Fatalf("Failed to get overdue tasks: %v", err)
+	}
+	if len(overdue) != 2 || overdue[0].Name != "Due last week" || overdue[1].Name != "Due yesterday" {
+		t.Fatalf("Expected the two overdue tasks, longest overdue first, got %+v", overdue)
+	}
+	if !overdue[0].Overdue {
+		t.Errorf("Expected loaded tasks to be flagged overdue")
+	}
+
+	// A task due today becomes overdue at midnight
+	if tasks[2].IsOverdue(today.Add(23*time.Hour)) || !tasks[2].IsOverdue(today.AddDate(0, 0, 1)) {
+		t.Errorf("Expected a task due today to be overdue from tomorrow")
+	}
+
+	if due, err := ParseDueDate(""); err != nil || due != nil {
+		t.Errorf("Expected an empty due date to mean none, got %v, %v", due, err)
+	}
+	if due, err := ParseDueDate("2025-12-15"); err != nil || !due.Equal(time.Date(2025, 12, 15, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected midnight on 2025-12-15, got %v, %v", due, err)
+	}
+	if _, err := ParseDueDate("someday"); err == nil {
+		t.Errorf("Expected an invalid due date to fail")
+	}
+}
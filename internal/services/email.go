@@ -1,6 +1,7 @@
 package services
 
 import (
+	"bytes"
 	"crypto/tls"
 	"encoding/base64"
 	"fmt"
@@ -24,11 +25,15 @@ type TaskServiceInterface interface {
 	CreateTaskFromEmail(name, emailMessageID string) (*models.Task, error)
 	AddComment(taskID uint, comment *models.Comment) error
 	AddAttachment(attachment *models.Attachment) error
+	RecordEmailReceipt(token, messageID string, receipt *models.EmailReceipt) (bool, error)
 }
 
 // TaskRepositoryInterface defines the interface for direct repository access needed by EmailService
 type TaskRepositoryInterface interface {
+	GetByID(id uint) (*models.Task, error)
 	GetByEmailMessageID(messageID string) (*models.Task, error)
+	GetCommentByEmailMessageID(messageID string) (*models.Comment, error)
+	SaveEmailMessage(message *models.EmailMessage) error
 }
 
 // AuthRepositoryInterface defines the interface for user validation
@@ -36,12 +41,19 @@ type AuthRepositoryInterface interface {
 	GetUserByEmail(email string) (*models.User, error)
 }
 
+// ContactRecorderInterface records email senders in the contact directory
+type ContactRecorderInterface interface {
+	RecordSender(name, email string, taskID uint) error
+}
+
 type EmailService struct {
 	taskService    TaskServiceInterface
 	taskRepository TaskRepositoryInterface
 	authRepository AuthRepositoryInterface
 	storageService *StorageService
 	config         *config.Config
+	contacts       ContactRecorderInterface
+	spam           *SpamService
 }
 
 func NewEmailService(taskService TaskServiceInterface, taskRepository TaskRepositoryInterface, authRepository AuthRepositoryInterface, storageService *StorageService, cfg *config.Config) *EmailService {
@@ -54,6 +66,67 @@ func NewEmailService(taskService TaskServiceInterface, taskRepository TaskReposi
 	}
 }
 
+// SetContactRecorder enables recording of email senders in the contact directory
+func (s *EmailService) SetContactRecorder(contacts ContactRecorderInterface) {
+	s.contacts = contacts
+}
+
+// recordContact links the sender of a message to a task; failures are logged, not fatal
+func (s *EmailService) recordContact(msg *imap.Message, taskID uint) {
+	if s.contacts == nil || msg.Envelope == nil || len(msg.Envelope.From) == 0 {
+		return
+	}
+
+	sender := msg.Envelope.From[0]
+	if err := s.contacts.RecordSender(sender.PersonalName, sender.Address(), taskID); err != nil {
+		fmt.Printf("Warning: Failed to record contact %s: %v\n", sender.Address(), err)
+	}
+}
+
+// recordEmailMessage keeps the raw exchange of an email processed onto a task
+func (s *EmailService) recordEmailMessage(msg *imap.Message, taskID uint, from, body string) {
+	if s.taskRepository == nil || msg.Envelope == nil || msg.Envelope.MessageId == "" {
+		return
+	}
+
+	receivedAt := msg.Envelope.Date
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+	now := time.Now()
+	message := &models.EmailMessage{
+		MessageID:   msg.Envelope.MessageId,
+		TaskID:      &taskID,
+		Direction:   models.EmailDirectionInbound,
+		Subject:     msg.Envelope.Subject,
+		From:        from,
+		To:          envelopeAddresses(msg.Envelope.To),
+		CC:          envelopeAddresses(msg.Envelope.Cc),
+		Body:        body,
+		Processed:   true,
+		ProcessedAt: &now,
+		ReceivedAt:  receivedAt,
+	}
+	if err := s.taskRepository.SaveEmailMessage(message); err != nil {
+		fmt.Printf("Warning: Failed to record email %s: %v\n", message.MessageID, err)
+	}
+}
+
+func envelopeAddresses(addresses []*imap.Address) []string {
+	var result []string
+	for _, address := range addresses {
+		result = append(result, address.Address())
+	}
+	return result
+}
+
+// SetSpamFilter enables spam checking of inbound email and lets the spam
+// service release quarantined messages through this service
+func (s *EmailService) SetSpamFilter(spam *SpamService) {
+	s.spam = spam
+	spam.SetReleaser(s)
+}
+
 func (s *EmailService) ConnectIMAP() (*client.Client, error) {
 	address := fmt.Sprintf("%s:%s", s.config.Email.IMAPHost, s.config.Email.IMAPPort)
 
@@ -124,7 +197,7 @@ func (s *EmailService) ProcessInbox() error {
 
 	var processedUIDs []uint32
 	for msg := range messages {
-		if err := s.processMessage(msg); err != nil {
+		if err := s.processMessage(msg, nil); err != nil {
 			// Log error but continue processing other messages
 			fmt.Printf("Error processing message: %v\n", err)
 		} else {
@@ -157,7 +230,9 @@ func (s *EmailService) markMessagesAsRead(c *client.Client, uids []uint32) error
 	return c.UidStore(seqset, item, flags, nil)
 }
 
-func (s *EmailService) processMessage(msg *imap.Message) error {
+// processMessage runs a message through the inbound pipeline. With a non-nil
+// sim it is a dry run: what would happen is recorded there instead of done.
+func (s *EmailService) processMessage(msg *imap.Message, sim *EmailSimulation) error {
 	if msg.Envelope == nil {
 		return nil
 	}
@@ -168,21 +243,156 @@ func (s *EmailService) processMessage(msg *imap.Message) error {
 		from = msg.Envelope.From[0].Address()
 	}
 
+	// Delivery reports and read receipts come from mail servers and
+	// customers rather than JATS users
+	if handled, err := s.processReceipt(msg, sim); handled || err != nil {
+		return err
+	}
+
 	// Validate that sender is a JATS user
 	user, err := s.authRepository.GetUserByEmail(from)
 	if err != nil || user == nil {
+		if sim != nil {
+			sim.Outcome = EmailOutcomeIgnored
+			sim.Reason = "sender is not a JATS user"
+			return nil
+		}
 		fmt.Printf("Ignoring email from non-JATS user: %s\n", from)
 		return nil // Silently ignore emails from non-users
 	}
+	if sim != nil {
+		sim.Sender = user.Username
+	}
+
+	if s.spam != nil && s.spam.Enabled() {
+		held, err := s.checkSpam(msg, from, sim)
+		if err != nil {
+			return err
+		}
+		if held {
+			return nil
+		}
+	}
+
+	return s.routeMessage(msg, subject, from, sim)
+}
 
+// routeMessage adds a message to the task it replies to, or creates a new task
+func (s *EmailService) routeMessage(msg *imap.Message, subject, from string, sim *EmailSimulation) error {
 	// Check if this is a reply to an existing task using In-Reply-To or References headers
 	taskID, isUpdate := s.findTaskByMessageID([]string{msg.Envelope.InReplyTo}, msg.Envelope.MessageId)
 
 	if isUpdate && taskID > 0 {
-		return s.updateExistingTask(taskID, subject, from, msg)
+		return s.updateExistingTask(taskID, subject, from, msg, sim)
 	} else {
-		return s.createNewTask(subject, from, msg)
+		return s.createNewTask(subject, from, msg, sim)
+	}
+}
+
+// checkSpam runs the spam filter on a message. It returns true when the message
+// was quarantined or discarded and must not reach the task stream.
+func (s *EmailService) checkSpam(msg *imap.Message, from string, sim *EmailSimulation) (bool, error) {
+	raw, err := bufferMessageBody(msg)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := s.spam.Check(raw, from)
+	if err != nil {
+		if s.spam.FailOpen() {
+			if sim != nil {
+				sim.Reason = fmt.Sprintf("spam check failed, accepted because the filter fails open: %v", err)
+				return false, nil
+			}
+			fmt.Printf("Warning: Spam check failed, accepting email from %s: %v\n", from, err)
+			return false, nil
+		}
+		if sim != nil {
+			sim.Outcome = EmailOutcomeDeferred
+			sim.Reason = fmt.Sprintf("spam check failed: %v", err)
+			return true, nil
+		}
+		// Leave the message unread so it is checked again on the next poll
+		return false, fmt.Errorf("spam check failed: %w", err)
+	}
+
+	if sim != nil {
+		sim.Spam = result
+		switch result.Verdict {
+		case SpamVerdictReject:
+			sim.Outcome = EmailOutcomeRejected
+			return true, nil
+		case SpamVerdictQuarantine:
+			sim.Outcome = EmailOutcomeQuarantined
+			return true, nil
+		}
+		return false, nil
+	}
+
+	switch result.Verdict {
+	case SpamVerdictReject:
+		fmt.Printf("Discarding spam from %s (score %.1f)\n", from, result.Score)
+		return true, nil
+	case SpamVerdictQuarantine:
+		receivedAt := msg.Envelope.Date
+		if receivedAt.IsZero() {
+			receivedAt = time.Now()
+		}
+		quarantined := &models.QuarantinedEmail{
+			MessageID:  msg.Envelope.MessageId,
+			InReplyTo:  msg.Envelope.InReplyTo,
+			Subject:    msg.Envelope.Subject,
+			FromName:   msg.Envelope.From[0].PersonalName,
+			From:       from,
+			Score:      result.Score,
+			Action:     result.Action,
+			Symbols:    result.Symbols,
+			RawMessage: raw,
+			ReceivedAt: receivedAt,
+		}
+		if err := s.spam.Quarantine(quarantined); err != nil {
+			return false, err
+		}
+		fmt.Printf("Quarantined email from %s (score %.1f)\n", from, result.Score)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// ReleaseQuarantined processes a message released from quarantine, bypassing the spam check
+func (s *EmailService) ReleaseQuarantined(email *models.QuarantinedEmail) error {
+	sender := &imap.Address{PersonalName: email.FromName}
+	sender.MailboxName, sender.HostName, _ = strings.Cut(email.From, "@")
+
+	msg := &imap.Message{
+		Envelope: &imap.Envelope{
+			Date:      email.ReceivedAt,
+			Subject:   email.Subject,
+			From:      []*imap.Address{sender},
+			MessageId: email.MessageID,
+			InReplyTo: email.InReplyTo,
+		},
+		Body: map[*imap.BodySectionName]imap.Literal{
+			&imap.BodySectionName{}: bytes.NewBuffer(email.RawMessage),
+		},
+	}
+
+	return s.routeMessage(msg, email.Subject, email.From, nil)
+}
+
+// bufferMessageBody reads the full message body into memory, replacing the
+// literal so the message can still be parsed afterwards
+func bufferMessageBody(msg *imap.Message) ([]byte, error) {
+	for section, literal := range msg.Body {
+		raw, err := io.ReadAll(literal)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message body: %w", err)
+		}
+		msg.Body[section] = bytes.NewBuffer(raw)
+		return raw, nil
 	}
+	return nil, fmt.Errorf("no body found in message")
 }
 
 func (s *EmailService) findTaskByMessageID(inReplyTo []string, messageID string) (uint, bool) {
@@ -199,29 +409,42 @@ func (s *EmailService) findTaskByMessageID(inReplyTo []string, messageID string)
 	return 0, false
 }
 
+// getTaskByMessageID finds the task a message ID belongs to: the email that
+// opened the task, or an email update sent from it
 func (s *EmailService) getTaskByMessageID(messageID string) uint {
-	task, err := s.taskRepository.GetByEmailMessageID(messageID)
-	if err != nil {
-		return 0
+	if task, err := s.taskRepository.GetByEmailMessageID(messageID); err == nil {
+		return task.ID
 	}
-	return task.ID
+	if comment, err := s.taskRepository.GetCommentByEmailMessageID(messageID); err == nil {
+		return comment.TaskID
+	}
+	return 0
 }
 
-func (s *EmailService) createNewTask(subject, from string, msg *imap.Message) error {
+func (s *EmailService) createNewTask(subject, from string, msg *imap.Message, sim *EmailSimulation) error {
 	// Extract task name from subject (remove "Re:", "Fwd:", etc.)
 	taskName := s.cleanSubject(subject)
 
 	// Extract body content and attachments
-	body, attachments, err := s.parseEmailContent(msg)
+	body, attachments, err := s.parseEmailContent(msg, sim != nil)
 	if err != nil {
 		return fmt.Errorf("failed to parse email content: %w", err)
 	}
 
+	if sim != nil {
+		sim.Outcome = EmailOutcomeCreateTask
+		sim.TaskName = taskName
+		sim.describe(body, attachments)
+		return nil
+	}
+
 	// Create task with email message ID for future reply linking
 	createdTask, err := s.taskService.CreateTaskFromEmail(taskName, msg.Envelope.MessageId)
 	if err != nil {
 		return fmt.Errorf("failed to create task: %w", err)
 	}
+	s.recordContact(msg, createdTask.ID)
+	s.recordEmailMessage(msg, createdTask.ID, from, body)
 
 	// Add initial comment with body content (now internal notes only)
 	var commentID *uint
@@ -252,13 +475,25 @@ func (s *EmailService) createNewTask(subject, from string, msg *imap.Message) er
 	return nil
 }
 
-func (s *EmailService) updateExistingTask(taskID uint, subject, from string, msg *imap.Message) error {
+func (s *EmailService) updateExistingTask(taskID uint, subject, from string, msg *imap.Message, sim *EmailSimulation) error {
 	// Extract body content and attachments
-	body, attachments, err := s.parseEmailContent(msg)
+	body, attachments, err := s.parseEmailContent(msg, sim != nil)
 	if err != nil {
 		return fmt.Errorf("failed to parse email content: %w", err)
 	}
 
+	if sim != nil {
+		sim.Outcome = EmailOutcomeComment
+		sim.TaskID = taskID
+		if task, err := s.taskRepository.GetByID(taskID); err == nil {
+			sim.TaskName = task.Name
+		}
+		sim.describe(body, attachments)
+		return nil
+	}
+	s.recordContact(msg, taskID)
+	s.recordEmailMessage(msg, taskID, from, body)
+
 	// Add comment to existing task from email body (internal notes only)
 	var commentID *uint
 	if body != "" {
@@ -301,7 +536,9 @@ func (s *EmailService) cleanSubject(subject string) string {
 	return cleaned
 }
 
-func (s *EmailService) parseEmailContent(msg *imap.Message) (body string, attachments []*models.Attachment, err error) {
+// parseEmailContent extracts a message's text body and attachments. A dry run
+// describes the attachments without saving them.
+func (s *EmailService) parseEmailContent(msg *imap.Message, dryRun bool) (body string, attachments []*models.Attachment, err error) {
 	// Get the full message body
 	var bodyReader io.Reader
 	for _, literal := range msg.Body {
@@ -329,7 +566,7 @@ func (s *EmailService) parseEmailContent(msg *imap.Message) (body string, attach
 	}
 
 	if strings.HasPrefix(mediaType, "multipart/") {
-		return s.parseMultipartMessage(mailMsg.Body, params["boundary"])
+		return s.parseMultipartMessage(mailMsg.Body, params["boundary"], dryRun)
 	} else {
 		// Single part message
 		body, err = s.readPlainBody(mailMsg.Body)
@@ -337,7 +574,7 @@ func (s *EmailService) parseEmailContent(msg *imap.Message) (body string, attach
 	}
 }
 
-func (s *EmailService) parseMultipartMessage(body io.Reader, boundary string) (string, []*models.Attachment, error) {
+func (s *EmailService) parseMultipartMessage(body io.Reader, boundary string, dryRun bool) (string, []*models.Attachment, error) {
 	var textBody string
 	var attachments []*models.Attachment
 
@@ -356,7 +593,7 @@ func (s *EmailService) parseMultipartMessage(body io.Reader, boundary string) (s
 
 		if strings.HasPrefix(disposition, "attachment") || strings.Contains(disposition, "filename") {
 			// This is an attachment
-			attachment, err := s.processAttachment(part)
+			attachment, err := s.processAttachment(part, dryRun)
 			if err != nil {
 				fmt.Printf("Error processing attachment: %v\n", err)
 				continue
@@ -385,7 +622,7 @@ func (s *EmailService) parseMultipartMessage(body io.Reader, boundary string) (s
 	return textBody, attachments, nil
 }
 
-func (s *EmailService) processAttachment(part *multipart.Part) (*models.Attachment, error) {
+func (s *EmailService) processAttachment(part *multipart.Part, dryRun bool) (*models.Attachment, error) {
 	// Get content transfer encoding
 	encoding := strings.ToLower(part.Header.Get("Content-Transfer-Encoding"))
 	
@@ -416,6 +653,11 @@ func (s *EmailService) processAttachment(part *multipart.Part) (*models.Attachme
 		contentType = "application/octet-stream"
 	}
 
+	// A dry run describes the attachment without saving it
+	if dryRun {
+		return &models.Attachment{OriginalName: filename, ContentType: contentType, Size: int64(len(data))}, nil
+	}
+
 	// Save attachment using storage service
 	return s.storageService.SaveAttachment(filename, contentType, data)
 }
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"github.com/emersion/go-imap"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// receiptReport is a delivery status notification (RFC 3464) or read receipt
+// (RFC 8098) about an email JATS sent
+type receiptReport struct {
+	// Receipt token of the original email, from the envelope ID or its
+	// X-JATS-Receipt header; empty when the report carries neither
+	Token string
+	// Message-ID of the original email
+	MessageID string
+	Receipts  []*models.EmailReceipt
+}
+
+// processReceipt records a delivery report or read receipt on the email
+// update it is about. It reports whether the message was such a report; these
+// never become tasks or notes.
+func (s *EmailService) processReceipt(msg *imap.Message, sim *EmailSimulation) (bool, error) {
+	raw, err := bufferMessageBody(msg)
+	if err != nil {
+		return false, nil
+	}
+	report, ok := parseReceiptReport(raw)
+	if !ok {
+		return false, nil
+	}
+
+	if sim != nil {
+		sim.Outcome = EmailOutcomeReceipt
+		sim.Reason = fmt.Sprintf("%d receipt event(s) for %s", len(report.Receipts), report.MessageID)
+		return true, nil
+	}
+
+	for _, receipt := range report.Receipts {
+		if _, err := s.taskService.RecordEmailReceipt(report.Token, report.MessageID, receipt); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// parseReceiptReport reads a multipart/report message; ok is false for any
+// other message
+func parseReceiptReport(raw []byte) (report *receiptReport, ok bool) {
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, false
+	}
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" {
+		return nil, false
+	}
+	reportType := strings.ToLower(params["report-type"])
+	if reportType != "delivery-status" && reportType != "disposition-notification" {
+		return nil, false
+	}
+
+	report = &receiptReport{}
+	mr := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch strings.ToLower(partType) {
+		case "message/delivery-status":
+			report.readDeliveryStatus(part)
+		case "message/disposition-notification":
+			report.readDisposition(part)
+		case "message/rfc822", "text/rfc822-headers", "message/rfc822-headers":
+			// The original email, or just its headers
+			if original, err := mail.ReadMessage(io.MultiReader(part, strings.NewReader("\r\n\r\n"))); err == nil {
+				if id := strings.TrimSpace(original.Header.Get("Message-Id")); id != "" {
+					report.MessageID = id
+				}
+				if report.Token == "" {
+					report.Token = strings.TrimSpace(original.Header.Get("X-JATS-Receipt"))
+				}
+			}
+		}
+	}
+
+	return report, true
+}
+
+// readDeliveryStatus reads the per-message fields and per-recipient results
+// of a delivery status notification
+func (r *receiptReport) readDeliveryStatus(body io.Reader) {
+	for i, fields := range readFieldGroups(body) {
+		if i == 0 {
+			if envelopeID := strings.TrimSpace(fields.Get("Original-Envelope-Id")); envelopeID != "" {
+				r.Token = envelopeID
+			}
+			continue
+		}
+
+		var event string
+		switch strings.ToLower(strings.TrimSpace(fields.Get("Action"))) {
+		case "delivered", "relayed", "expanded":
+			event = models.EmailReceiptDelivered
+		case "failed":
+			event = models.EmailReceiptFailed
+		default:
+			// delayed: the final result is still to come
+			continue
+		}
+		r.Receipts = append(r.Receipts, &models.EmailReceipt{
+			Event:     event,
+			Source:    models.EmailReceiptSourceDSN,
+			Recipient: reportAddress(fields.Get("Final-Recipient")),
+		})
+	}
+}
+
+// readDisposition reads a read receipt; only "displayed" counts as seen
+func (r *receiptReport) readDisposition(body io.Reader) {
+	groups := readFieldGroups(body)
+	if len(groups) == 0 {
+		return
+	}
+	fields := groups[0]
+	if id := strings.TrimSpace(fields.Get("Original-Message-Id")); id != "" {
+		r.MessageID = id
+	}
+	if !strings.HasSuffix(strings.ToLower(strings.TrimSpace(fields.Get("Disposition"))), "displayed") {
+		return
+	}
+	r.Receipts = append(r.Receipts, &models.EmailReceipt{
+		Event:     models.EmailReceiptSeen,
+		Source:    models.EmailReceiptSourceMDN,
+		Recipient: reportAddress(fields.Get("Final-Recipient")),
+	})
+}
+
+// readFieldGroups reads the blank-line separated groups of header fields
+// making up a report part
+func readFieldGroups(body io.Reader) []textproto.MIMEHeader {
+	reader := textproto.NewReader(bufio.NewReader(body))
+	var groups []textproto.MIMEHeader
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if len(fields) > 0 {
+			groups = append(groups, fields)
+		}
+		if err != nil {
+			return groups
+		}
+	}
+}
+
+// reportAddress returns the address of a field such as "rfc822; jo@example.com"
+func reportAddress(field string) string {
+	if _, address, ok := strings.Cut(field, ";"); ok {
+		return strings.TrimSpace(address)
+	}
+	return strings.TrimSpace(field)
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+
+	"github.com/emersion/go-imap"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// ErrInvalidEmail is returned when a simulated message cannot be parsed
+var ErrInvalidEmail = errors.New("invalid email message")
+
+// Outcomes of processing an inbound message
+const (
+	EmailOutcomeCreateTask  = "create_task" // a new task would be created
+	EmailOutcomeComment     = "comment"     // the message would be added to an existing task
+	EmailOutcomeIgnored     = "ignored"     // the sender is not a JATS user
+	EmailOutcomeQuarantined = "quarantined" // the spam filter would hold the message
+	EmailOutcomeRejected    = "rejected"    // the spam filter would discard the message
+	EmailOutcomeDeferred    = "deferred"    // the spam filter failed; the message would be retried
+	EmailOutcomeReceipt     = "receipt"     // a delivery report or read receipt for an email update
+)
+
+// EmailSimulation describes what inbound processing would do with a message
+type EmailSimulation struct {
+	Subject   string `json:"subject"`
+	From      string `json:"from"`
+	MessageID string `json:"message_id,omitempty"`
+	InReplyTo string `json:"in_reply_to,omitempty"`
+
+	Outcome string           `json:"outcome"`
+	Reason  string           `json:"reason,omitempty"`
+	Sender  string           `json:"sender,omitempty"` // username of the JATS user who sent it
+	Spam    *SpamCheckResult `json:"spam,omitempty"`
+
+	TaskID      uint                  `json:"task_id,omitempty"` // task a reply would be added to
+	TaskName    string                `json:"task_name,omitempty"`
+	Comment     string                `json:"comment,omitempty"` // internal note made from the body
+	Attachments []SimulatedAttachment `json:"attachments,omitempty"`
+}
+
+// SimulatedAttachment is an attachment that would be saved from a message
+type SimulatedAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	AttachedTo  string `json:"attached_to"` // comment, or task when the body is empty
+}
+
+// Simulate runs a raw RFC 822 message through the inbound pipeline (sender
+// check, spam filter, reply threading and content parsing) as a dry run that
+// creates nothing, for debugging how a customer's mail would be handled
+func (s *EmailService) Simulate(raw []byte) (*EmailSimulation, error) {
+	header, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEmail, err)
+	}
+
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(header.Header.Get("Subject"))
+	if err != nil {
+		subject = header.Header.Get("Subject")
+	}
+	sender, err := mail.ParseAddress(header.Header.Get("From"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid From header: %v", ErrInvalidEmail, err)
+	}
+
+	sim := &EmailSimulation{
+		Subject:   subject,
+		From:      sender.Address,
+		MessageID: strings.TrimSpace(header.Header.Get("Message-Id")),
+		InReplyTo: strings.TrimSpace(header.Header.Get("In-Reply-To")),
+	}
+
+	from := &imap.Address{PersonalName: sender.Name}
+	from.MailboxName, from.HostName, _ = strings.Cut(sender.Address, "@")
+	msg := &imap.Message{
+		Envelope: &imap.Envelope{
+			Subject:   subject,
+			From:      []*imap.Address{from},
+			MessageId: sim.MessageID,
+			InReplyTo: sim.InReplyTo,
+		},
+		Body: map[*imap.BodySectionName]imap.Literal{
+			&imap.BodySectionName{}: bytes.NewBuffer(raw),
+		},
+	}
+	if err := s.processMessage(msg, sim); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEmail, err)
+	}
+
+	return sim, nil
+}
+
+// describe records the internal note and attachments a message would add
+func (sim *EmailSimulation) describe(body string, attachments []*models.Attachment) {
+	sim.Comment = body
+	attachedTo := "comment"
+	if body == "" {
+		attachedTo = "task"
+	}
+	for _, attachment := range attachments {
+		sim.Attachments = append(sim.Attachments, SimulatedAttachment{
+			Filename:    attachment.OriginalName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+			AttachedTo:  attachedTo,
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+type noUsersAuthRepository struct{}
+
+func (noUsersAuthRepository) GetUserByEmail(email string) (*models.User, error) {
+	return nil, nil
+}
+
+const simulatedMessage = "From: Alice <alice@example.com>\r\n" +
+	"Subject: =?UTF-8?Q?Re:_Printer_=C3=BCber_broken?=\r\n" +
+	"Message-Id: <new@example.com>\r\n" +
+	"%s" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=b1\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"It is jammed again.\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"Content-Disposition: attachment; filename=\"log.txt\"\r\n" +
+	"\r\n" +
+	"paper jam\r\n" +
+	"--b1--\r\n"
+
+func TestEmailService_Simulate(t *testing.T) {
+	storageDir := t.TempDir()
+	taskService := &mockTaskService{}
+	taskRepo := newMockTaskRepository()
+	taskRepo.addTask("<original@example.com>", &models.Task{ID: 7, Name: "Printer"})
+	service := NewEmailService(taskService, taskRepo, &mockAuthRepository{}, NewStorageService(storageDir), &config.Config{})
+
+	sim, err := service.Simulate([]byte(fmt.Sprintf(simulatedMessage, "")))
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if sim.Outcome != EmailOutcomeCreateTask || sim.TaskName != "Printer über broken" {
+		t.Errorf("Expected a new task named from the decoded subject, got %q %q", sim.Outcome, sim.TaskName)
+	}
+	if sim.From != "alice@example.com" || strings.TrimSpace(sim.Comment) != "It is jammed again." {
+		t.Errorf("Unexpected sender or comment: %q %q", sim.From, sim.Comment)
+	}
+	if len(sim.Attachments) != 1 || sim.Attachments[0].Filename != "log.txt" || sim.Attachments[0].Size != 9 || sim.Attachments[0].AttachedTo != "comment" {
+		t.Errorf("Expected log.txt on the comment, got %+v", sim.Attachments)
+	}
+
+	reply := fmt.Sprintf(simulatedMessage, "In-Reply-To: <original@example.com>\r\n")
+	sim, err = service.Simulate([]byte(reply))
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if sim.Outcome != EmailOutcomeComment || sim.TaskID != 7 || sim.TaskName != "Printer" {
+		t.Errorf("Expected a comment on task 7, got %q %d %q", sim.Outcome, sim.TaskID, sim.TaskName)
+	}
+
+	// Nothing is created or stored
+	if len(taskService.createdTasks) != 0 || len(taskService.addedComments) != 0 {
+		t.Error("Expected no tasks or comments to be created")
+	}
+	if entries, _ := os.ReadDir(storageDir); len(entries) != 0 {
+		t.Errorf("Expected no attachments to be stored, found %d files", len(entries))
+	}
+
+	if _, err := service.Simulate([]byte("not an email")); err == nil {
+		t.Error("Expected an error for a message without headers")
+	}
+
+	service = NewEmailService(taskService, taskRepo, noUsersAuthRepository{}, nil, &config.Config{})
+	sim, err = service.Simulate([]byte(fmt.Sprintf(simulatedMessage, "")))
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if sim.Outcome != EmailOutcomeIgnored {
+		t.Errorf("Expected mail from a non-user to be ignored, got %q", sim.Outcome)
+	}
+}
+
+func TestEmailService_SimulateSpam(t *testing.T) {
+	server := newMockRspamd(t)
+	defer server.Close()
+
+	db := setupTestDB(t)
+	quarantine := repository.NewQuarantineRepository(db)
+	spam := NewSpamService(NewRspamdClient(server.URL, "secret", time.Second), quarantine,
+		&config.SpamConfig{QuarantineScore: 6, RejectScore: 15})
+	service := NewEmailService(&mockTaskService{}, newMockTaskRepository(), &mockAuthRepository{}, nil, &config.Config{})
+	service.SetSpamFilter(spam)
+
+	message := "From: sender@example.com\r\nSubject: Offer\r\n\r\ncheap pills\r\n"
+	sim, err := service.Simulate([]byte(message))
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if sim.Outcome != EmailOutcomeQuarantined || sim.Spam == nil || sim.Spam.Score != 8 {
+		t.Errorf("Expected the message to be quarantined, got %q %+v", sim.Outcome, sim.Spam)
+	}
+	if held, _ := quarantine.List(); len(held) != 0 {
+		t.Error("Expected nothing to be added to the quarantine")
+	}
+}
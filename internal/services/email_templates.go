@@ -0,0 +1,183 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/soarinferret/jats/internal/i18n"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+//go:embed templates/email/*.tmpl
+var defaultEmailTemplates embed.FS
+
+// Email template names
+const (
+	EmailTemplateTaskCreated = "task_created"
+	EmailTemplateTaskUpdated = "task_updated"
+)
+
+// EmailTemplateData holds the variables available to email templates
+type EmailTemplateData struct {
+	Task     *models.Task
+	Comment  *models.Comment
+	Actor    string             // Who made the change (username or email address), may be empty
+	Query    *models.SavedQuery // Saved query reports only
+	Tasks    []*models.Task     // Saved query reports only
+	Language string
+	Branding *models.BrandingSettings
+}
+
+// BrandingSource provides the branding applied to outgoing emails
+type BrandingSource interface {
+	GetBranding() *models.BrandingSettings
+}
+
+// RenderedEmail is the output of rendering an email template
+type RenderedEmail struct {
+	Subject string
+	Text    string
+	HTML    string
+	// Message-ID header, with angle brackets; empty leaves it to the mail server
+	MessageID string
+	// Tracking token asking for delivery status notifications and a read
+	// receipt, see models.EmailReceipt; empty asks for neither
+	ReceiptToken string
+}
+
+// EmailTemplates renders notification emails from template files.
+// Each template consists of <name>.subject.tmpl, <name>.txt.tmpl and <name>.html.tmpl.
+// Files in the override directory take precedence over the built-in defaults, and
+// a language-specific file (e.g. task_created.de.txt.tmpl) takes precedence over both.
+type EmailTemplates struct {
+	overrideDir string
+	branding    BrandingSource
+}
+
+// NewEmailTemplates creates a template renderer; overrideDir may be empty
+func NewEmailTemplates(overrideDir string) *EmailTemplates {
+	return &EmailTemplates{overrideDir: overrideDir}
+}
+
+// SetBrandingSource sets where instance branding for emails is read from
+func (e *EmailTemplates) SetBrandingSource(source BrandingSource) {
+	e.branding = source
+}
+
+// Render renders the subject, plain text and HTML parts of an email template
+func (e *EmailTemplates) Render(name string, data EmailTemplateData) (*RenderedEmail, error) {
+	loc := i18n.NewLocalizer(data.Language)
+	data.Language = loc.Language()
+
+	if data.Branding == nil {
+		if e.branding != nil {
+			data.Branding = e.branding.GetBranding()
+		} else {
+			data.Branding = models.DefaultBrandingSettings()
+		}
+	}
+
+	funcs := map[string]interface{}{
+		"t": loc.T,
+		"status": func(status models.TaskStatus) string {
+			return loc.Status(string(status))
+		},
+		"priority": func(priority models.TaskPriority) string {
+			return loc.Priority(string(priority))
+		},
+		"join": strings.Join,
+	}
+
+	subject, err := e.renderText(name, "subject", data, funcs)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := e.renderText(name, "txt", data, funcs)
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := e.renderHTML(name, data, funcs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RenderedEmail{
+		// Subjects must be a single line
+		Subject: strings.Join(strings.Fields(subject), " "),
+		Text:    strings.TrimSpace(text) + "\n",
+		HTML:    html,
+	}, nil
+}
+
+func (e *EmailTemplates) renderText(name, part string, data EmailTemplateData, funcs map[string]interface{}) (string, error) {
+	source, err := e.load(name, part, data.Language)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := texttemplate.New(name + "." + part).Funcs(funcs).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse email template %s.%s: %w", name, part, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render email template %s.%s: %w", name, part, err)
+	}
+	return buf.String(), nil
+}
+
+func (e *EmailTemplates) renderHTML(name string, data EmailTemplateData, funcs map[string]interface{}) (string, error) {
+	source, err := e.load(name, "html", data.Language)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := htmltemplate.New(name + ".html").Funcs(funcs).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse email template %s.html: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render email template %s.html: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// load finds the template source for a part, checking overrides before the embedded defaults
+func (e *EmailTemplates) load(name, part, lang string) (string, error) {
+	candidates := []string{
+		fmt.Sprintf("%s.%s.%s.tmpl", name, lang, part),
+		fmt.Sprintf("%s.%s.tmpl", name, part),
+	}
+
+	if e.overrideDir != "" {
+		for _, file := range candidates {
+			data, err := os.ReadFile(filepath.Join(e.overrideDir, file))
+			if err == nil {
+				return string(data), nil
+			}
+			if !os.IsNotExist(err) {
+				return "", fmt.Errorf("failed to read email template %s: %w", file, err)
+			}
+		}
+	}
+
+	for _, file := range candidates {
+		data, err := defaultEmailTemplates.ReadFile("templates/email/" + file)
+		if err == nil {
+			return string(data), nil
+		}
+	}
+
+	return "", fmt.Errorf("email template %s.%s not found", name, part)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+func TestEmailTemplates_RenderDefault(t *testing.T) {
+	templates := NewEmailTemplates("")
+
+	task := &models.Task{
+		Name:     "Restart <web> server",
+		Status:   models.TaskStatusOpen,
+		Priority: models.TaskPriorityHigh,
+		Tags:     []string{"ops", "urgent"},
+	}
+
+	email, err := templates.Render(EmailTemplateTaskCreated, EmailTemplateData{Task: task})
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	if email.Subject != "New Task: Restart <web> server" {
+		t.Errorf("Unexpected subject: %q", email.Subject)
+	}
+	if !strings.Contains(email.Text, "Tags: ops, urgent") {
+		t.Errorf("Expected tags in text body, got %q", email.Text)
+	}
+	if !strings.Contains(email.HTML, "Restart &lt;web&gt; server") {
+		t.Errorf("Expected escaped task name in HTML body, got %q", email.HTML)
+	}
+}
+
+func TestEmailTemplates_RenderTranslated(t *testing.T) {
+	templates := NewEmailTemplates("")
+
+	task := &models.Task{Name: "Backup prüfen", Status: models.TaskStatusInProgress}
+	email, err := templates.Render(EmailTemplateTaskCreated, EmailTemplateData{Task: task, Language: "de"})
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	if email.Subject != "Neue Aufgabe: Backup prüfen" {
+		t.Errorf("Unexpected subject: %q", email.Subject)
+	}
+	if !strings.Contains(email.Text, "Status: in Bearbeitung") {
+		t.Errorf("Expected translated status, got %q", email.Text)
+	}
+}
+
+func TestEmailTemplates_Override(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "task_updated.subject.tmpl"), []byte("[ACME] {{.Task.Name}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "task_updated.es.txt.tmpl"), []byte("{{.Actor}} dice: {{.Comment.Content}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	templates := NewEmailTemplates(dir)
+	data := EmailTemplateData{
+		Task:    &models.Task{Name: "Printer jam", Status: models.TaskStatusOpen},
+		Comment: &models.Comment{Content: "Fixed"},
+		Actor:   "alice@example.com",
+	}
+
+	email, err := templates.Render(EmailTemplateTaskUpdated, data)
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+	if email.Subject != "[ACME] Printer jam" {
+		t.Errorf("Expected overridden subject, got %q", email.Subject)
+	}
+	if !strings.Contains(email.Text, "New Comment (by alice@example.com):") {
+		t.Errorf("Expected default text body for English, got %q", email.Text)
+	}
+
+	data.Language = "es"
+	email, err = templates.Render(EmailTemplateTaskUpdated, data)
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+	if strings.TrimSpace(email.Text) != "alice@example.com dice: Fixed" {
+		t.Errorf("Expected language-specific override, got %q", email.Text)
+	}
+}
@@ -93,7 +93,7 @@ func TestEmailService_ProcessAttachment(t *testing.T) {
 	}
 
 	// Process the attachment
-	attachment, err := emailService.processAttachment(mockPart)
+	attachment, err := emailService.processAttachment(mockPart, false)
 	if err != nil {
 		t.Errorf("Failed to process attachment: %v", err)
 	}
@@ -153,7 +153,7 @@ func TestEmailService_ProcessAttachment_Base64Encoded(t *testing.T) {
 
 	// Parse the multipart message
 	boundary := writer.Boundary()
-	body, attachments, err := emailService.parseMultipartMessage(&buf, boundary)
+	body, attachments, err := emailService.parseMultipartMessage(&buf, boundary, false)
 	if err != nil {
 		t.Fatalf("Failed to parse multipart message: %v", err)
 	}
@@ -217,7 +217,7 @@ func TestEmailService_ParseMultipartMessage(t *testing.T) {
 	writer.Close()
 
 	// Parse the multipart message
-	body, attachments, err := emailService.parseMultipartMessage(&buf, writer.Boundary())
+	body, attachments, err := emailService.parseMultipartMessage(&buf, writer.Boundary(), false)
 	if err != nil {
 		t.Errorf("Failed to parse multipart message: %v", err)
 	}
@@ -339,8 +339,9 @@ func TestEmailService_ConnectIMAP_Configuration(t *testing.T) {
 }
 
 type mockTaskService struct {
-	createdTasks  []*models.Task
-	addedComments []*models.Comment
+	createdTasks     []*models.Task
+	addedComments    []*models.Comment
+	recordedReceipts []*models.EmailReceipt
 }
 
 func (m *mockTaskService) CreateTask(name string) (*models.Task, error) {
@@ -382,6 +383,11 @@ func (m *mockTaskService) AddAttachment(attachment *models.Attachment) error {
 	return nil
 }
 
+func (m *mockTaskService) RecordEmailReceipt(token, messageID string, receipt *models.EmailReceipt) (bool, error) {
+	m.recordedReceipts = append(m.recordedReceipts, receipt)
+	return true, nil
+}
+
 type mockTaskRepository struct {
 	tasks map[string]*models.Task
 }
@@ -399,6 +405,23 @@ func (m *mockTaskRepository) GetByEmailMessageID(messageID string) (*models.Task
 	return nil, fmt.Errorf("task not found")
 }
 
+func (m *mockTaskRepository) GetByID(id uint) (*models.Task, error) {
+	for _, task := range m.tasks {
+		if task.ID == id {
+			return task, nil
+		}
+	}
+	return nil, fmt.Errorf("task not found")
+}
+
+func (m *mockTaskRepository) GetCommentByEmailMessageID(messageID string) (*models.Comment, error) {
+	return nil, fmt.Errorf("comment not found")
+}
+
+func (m *mockTaskRepository) SaveEmailMessage(message *models.EmailMessage) error {
+	return nil
+}
+
 func (m *mockTaskRepository) addTask(messageID string, task *models.Task) {
 	m.tasks[messageID] = task
 }
@@ -429,7 +452,7 @@ func TestEmailService_CreateNewTask(t *testing.T) {
 	}
 
 	// Test task creation
-	err := emailService.createNewTask("Test Task Subject", "sender@example.com", msg)
+	err := emailService.createNewTask("Test Task Subject", "sender@example.com", msg, nil)
 	if err != nil {
 		t.Errorf("Failed to create new task: %v", err)
 	}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var (
+	ErrEmailSendingDisabled = errors.New("outgoing email is not configured")
+	ErrTaskNotFromEmail     = errors.New("task was not created from an email")
+	ErrNoEmailRecipients    = errors.New("at least one recipient is required")
+	ErrEmptyEmailUpdate     = errors.New("email update message is required")
+	ErrCommentNotOnTask     = errors.New("comment does not belong to this task")
+)
+
+// EmailSender sends a composed email and returns the Message-ID it was sent with
+type EmailSender interface {
+	SendMessage(recipients []string, email *RenderedEmail, inReplyTo string) (string, error)
+	// Sender returns the address emails are sent from
+	Sender() string
+}
+
+// SetEmailSender enables sending email updates from email-originated tasks
+func (s *TaskService) SetEmailSender(sender EmailSender) {
+	s.emailSender = sender
+}
+
+// CanSendEmailUpdates reports whether email updates can be sent at all
+func (s *TaskService) CanSendEmailUpdates() bool {
+	return s.emailSender != nil
+}
+
+// EmailUpdate is a message sent to the people on an email-originated task
+type EmailUpdate struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Message string   `json:"message"`
+	// Comments quoted below the message, in timeline order
+	CommentIDs []uint `json:"comment_ids,omitempty"`
+}
+
+// EmailUpdateRecipients returns the default recipients of an email update:
+// everyone who wrote to the task plus its subscribers
+func EmailUpdateRecipients(task *models.Task) []string {
+	seen := make(map[string]bool)
+	var recipients []string
+	add := func(address string) {
+		address = strings.TrimSpace(address)
+		if address == "" || seen[strings.ToLower(address)] {
+			return
+		}
+		seen[strings.ToLower(address)] = true
+		recipients = append(recipients, address)
+	}
+
+	for _, comment := range task.Comments {
+		add(comment.FromEmail)
+	}
+	for _, subscriber := range task.Subscribers {
+		add(subscriber.Email)
+	}
+	return recipients
+}
+
+// EmailUpdateSubject returns the default subject of an email update, a reply to the original thread
+func EmailUpdateSubject(task *models.Task) string {
+	if strings.HasPrefix(strings.ToLower(task.Name), "re:") {
+		return task.Name
+	}
+	return "Re: " + task.Name
+}
+
+// ComposeEmailUpdate renders the email for an update without sending it
+func (s *TaskService) ComposeEmailUpdate(taskID uint, update *EmailUpdate) (*RenderedEmail, error) {
+	task, err := s.repo.GetByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return composeEmailUpdate(task, update)
+}
+
+func composeEmailUpdate(task *models.Task, update *EmailUpdate) (*RenderedEmail, error) {
+	if task.EmailMessageID == "" {
+		return nil, ErrTaskNotFromEmail
+	}
+
+	update.Message = strings.TrimSpace(update.Message)
+	if update.Message == "" {
+		return nil, ErrEmptyEmailUpdate
+	}
+	if len(update.To) == 0 {
+		update.To = EmailUpdateRecipients(task)
+	}
+	if len(update.To) == 0 {
+		return nil, ErrNoEmailRecipients
+	}
+	for _, recipient := range update.To {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRecipient, recipient)
+		}
+	}
+	if strings.TrimSpace(update.Subject) == "" {
+		update.Subject = EmailUpdateSubject(task)
+	}
+
+	comments := make(map[uint]models.Comment, len(task.Comments))
+	for _, comment := range task.Comments {
+		comments[comment.ID] = comment
+	}
+
+	var body strings.Builder
+	body.WriteString(update.Message)
+	body.WriteString("\n")
+	for _, id := range update.CommentIDs {
+		comment, ok := comments[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrCommentNotOnTask, id)
+		}
+		author := comment.FromEmail
+		if author == "" {
+			author = "JATS"
+		}
+		body.WriteString(fmt.Sprintf("\nOn %s, %s wrote:\n", comment.CreatedAt.Format("Jan 2, 2006 15:04"), author))
+		for _, line := range strings.Split(strings.TrimSpace(comment.Content), "\n") {
+			body.WriteString("> " + line + "\n")
+		}
+	}
+
+	return &RenderedEmail{Subject: update.Subject, Text: body.String()}, nil
+}
+
+// SendEmailUpdate sends an update into the task's email thread and records
+// it as a public comment carrying the outgoing Message-ID, so replies to the
+// update are threaded back onto the task
+func (s *TaskService) SendEmailUpdate(taskID uint, update *EmailUpdate) (*models.Comment, error) {
+	if s.emailSender == nil {
+		return nil, ErrEmailSendingDisabled
+	}
+
+	task, err := s.repo.GetByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	email, err := composeEmailUpdate(task, update)
+	if err != nil {
+		return nil, err
+	}
+	if email.ReceiptToken, err = newReceiptToken(); err != nil {
+		return nil, err
+	}
+	s.addReceiptPixel(email)
+
+	messageID, err := s.emailSender.SendMessage(update.To, email, task.EmailMessageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send email update: %w", err)
+	}
+
+	comment := &models.Comment{
+		Content:        fmt.Sprintf("Email sent to %s\nSubject: %s\n\n%s", strings.Join(update.To, ", "), email.Subject, strings.TrimRight(email.Text, "\n")),
+		EmailMessageID: messageID,
+		TrackingToken:  email.ReceiptToken,
+	}
+	if err := s.AddComment(taskID, comment); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.repo.SaveEmailMessage(&models.EmailMessage{
+		MessageID:   messageID,
+		TaskID:      &taskID,
+		Direction:   models.EmailDirectionOutbound,
+		Subject:     email.Subject,
+		From:        s.emailSender.Sender(),
+		To:          update.To,
+		Body:        email.Text,
+		Processed:   true,
+		ProcessedAt: &now,
+		ReceivedAt:  now,
+	}); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
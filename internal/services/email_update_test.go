@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+type fakeEmailSender struct {
+	recipients []string
+	email      *RenderedEmail
+	inReplyTo  string
+}
+
+func (f *fakeEmailSender) SendMessage(recipients []string, email *RenderedEmail, inReplyTo string) (string, error) {
+	f.recipients = recipients
+	f.email = email
+	f.inReplyTo = inReplyTo
+	return "<update-1@jats.test>", nil
+}
+
+func (f *fakeEmailSender) Sender() string {
+	return "support@jats.test"
+}
+
+func TestTaskService_SendEmailUpdate(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	task, err := service.CreateTaskFromEmail("Printer is broken", "<original@customer.test>")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	question := &models.Comment{Content: "It prints blank pages.", FromEmail: "customer@customer.test"}
+	if err := service.AddComment(task.ID, question); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+	if err := repo.AddSubscriber(&models.TaskSubscriber{TaskID: task.ID, Email: "Customer@customer.test"}); err != nil {
+		t.Fatalf("Failed to add subscriber: %v", err)
+	}
+
+	update := &EmailUpdate{Message: "We replaced the toner.", CommentIDs: []uint{question.ID}}
+	if _, err := service.SendEmailUpdate(task.ID, update); !errors.Is(err, ErrEmailSendingDisabled) {
+		t.Fatalf("Expected ErrEmailSendingDisabled without a sender, got %v", err)
+	}
+
+	sender := &fakeEmailSender{}
+	service.SetEmailSender(sender)
+
+	comment, err := service.SendEmailUpdate(task.ID, update)
+	if err != nil {
+		t.Fatalf("Failed to send email update: %v", err)
+	}
+
+	if len(sender.recipients) != 1 || sender.recipients[0] != "customer@customer.test" {
+		t.Errorf("Expected the deduplicated customer as recipient, got %v", sender.recipients)
+	}
+	if sender.inReplyTo != "<original@customer.test>" {
+		t.Errorf("Expected the update to reply to the original email, got %q", sender.inReplyTo)
+	}
+	if sender.email.Subject != "Re: Printer is broken" {
+		t.Errorf("Unexpected subject %q", sender.email.Subject)
+	}
+	if !strings.Contains(sender.email.Text, "We replaced the toner.") || !strings.Contains(sender.email.Text, "> It prints blank pages.") {
+		t.Errorf("Expected message and quoted comment in body, got %q", sender.email.Text)
+	}
+
+	if comment.IsPrivate || comment.EmailMessageID != "<update-1@jats.test>" {
+		t.Errorf("Expected a public comment with the outgoing Message-ID, got %+v", comment)
+	}
+	found, err := repo.GetCommentByEmailMessageID("<update-1@jats.test>")
+	if err != nil || found.TaskID != task.ID {
+		t.Errorf("Expected the sent update to be found by its Message-ID, got %+v (%v)", found, err)
+	}
+
+	messages, err := repo.GetTaskEmailMessages(task.ID)
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("Expected the sent update in the task's emails, got %d (%v)", len(messages), err)
+	}
+	if messages[0].Direction != models.EmailDirectionOutbound || messages[0].From != "support@jats.test" {
+		t.Errorf("Expected an outbound email from the sender address, got %+v", messages[0])
+	}
+}
+
+func TestTaskService_SendEmailUpdate_Validation(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+	service.SetEmailSender(&fakeEmailSender{})
+
+	manual, _ := service.CreateTask("Manual task")
+	if _, err := service.SendEmailUpdate(manual.ID, &EmailUpdate{Message: "Hi", To: []string{"a@b.test"}}); !errors.Is(err, ErrTaskNotFromEmail) {
+		t.Errorf("Expected ErrTaskNotFromEmail, got %v", err)
+	}
+
+	task, _ := service.CreateTaskFromEmail("Question", "<q@customer.test>")
+	other, _ := service.CreateTaskFromEmail("Other", "<o@customer.test>")
+	otherComment := &models.Comment{Content: "elsewhere"}
+	service.AddComment(other.ID, otherComment)
+
+	tests := []struct {
+		name   string
+		update EmailUpdate
+		want   error
+	}{
+		{"empty message", EmailUpdate{To: []string{"a@b.test"}}, ErrEmptyEmailUpdate},
+		{"no recipients", EmailUpdate{Message: "Hi"}, ErrNoEmailRecipients},
+		{"invalid recipient", EmailUpdate{Message: "Hi", To: []string{"not an address"}}, ErrInvalidRecipient},
+		{"foreign comment", EmailUpdate{Message: "Hi", To: []string{"a@b.test"}, CommentIDs: []uint{otherComment.ID}}, ErrCommentNotOnTask},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.SendEmailUpdate(task.ID, &tt.update); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
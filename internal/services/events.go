@@ -0,0 +1,113 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// Task event types published on the event bus
+const (
+	TaskEventCreated   = "task.created"
+	TaskEventUpdated   = "task.updated"
+	TaskEventCommented = "task.commented"
+	TaskEventDeleted   = "task.deleted"
+)
+
+// TaskEventTypes lists every task event type
+var TaskEventTypes = []string{TaskEventCreated, TaskEventUpdated, TaskEventCommented, TaskEventDeleted}
+
+// eventBacklog is how many recent events the bus keeps for subscribers
+// resuming after a dropped connection
+const eventBacklog = 256
+
+// eventBuffer is how many events a subscriber may fall behind by before it
+// misses some
+const eventBuffer = 64
+
+// TaskEvent says a task changed, so clients showing it can refresh it. It
+// carries the task's ID, name and status but not its content, which clients
+// load through the API with their own permissions.
+type TaskEvent struct {
+	ID        uint64            `json:"id"` // increases by one with each event
+	Type      string            `json:"type"`
+	TaskID    uint              `json:"task_id"`
+	Name      string            `json:"name,omitempty"`
+	Status    models.TaskStatus `json:"status,omitempty"`
+	CommentID uint              `json:"comment_id,omitempty"`
+	At        time.Time         `json:"at"`
+}
+
+// EventBus fans task events out to subscribers, such as the event stream
+// API. Publishing never blocks: a subscriber that falls too far behind misses
+// events rather than holding up the change that made them.
+type EventBus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	backlog     []TaskEvent
+	subscribers map[chan TaskEvent]struct{}
+}
+
+// NewEventBus creates an event bus with no subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[chan TaskEvent]struct{})}
+}
+
+// Publish numbers an event and sends it to every subscriber
+func (b *EventBus) Publish(event TaskEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+
+	b.backlog = append(b.backlog, event)
+	if len(b.backlog) > eventBacklog {
+		b.backlog = b.backlog[len(b.backlog)-eventBacklog:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of the events published from now on, and the
+// events after the given ID that are still in the backlog, for a client
+// resuming a stream; 0 returns none. Cancel must be called when done.
+func (b *EventBus) Subscribe(after uint64) (events <-chan TaskEvent, missed []TaskEvent, cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if after > 0 {
+		for _, event := range b.backlog {
+			if event.ID > after {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	ch := make(chan TaskEvent, eventBuffer)
+	b.subscribers[ch] = struct{}{}
+	return ch, missed, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, ch)
+	}
+}
+
+// Events returns the bus task events are published on
+func (s *TaskService) Events() *EventBus {
+	return s.events
+}
+
+// publish sends an event about a task on the event bus
+func (s *TaskService) publish(eventType string, task *models.Task) {
+	s.events.Publish(TaskEvent{Type: eventType, TaskID: task.ID, Name: task.Name, Status: task.Status})
+}
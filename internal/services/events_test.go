@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestEventBus(t *testing.T) {
+	bus := NewEventBus()
+	bus.Publish(TaskEvent{Type: TaskEventCreated, TaskID: 1})
+
+	events, missed, cancel := bus.Subscribe(0)
+	if len(missed) != 0 {
+		t.Errorf("Expected no missed events without a last ID, got %+v", missed)
+	}
+
+	bus.Publish(TaskEvent{Type: TaskEventUpdated, TaskID: 1})
+	if event := <-events; event.ID != 2 || event.Type != TaskEventUpdated || event.At.IsZero() {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	cancel()
+	bus.Publish(TaskEvent{Type: TaskEventDeleted, TaskID: 1})
+	select {
+	case event := <-events:
+		t.Errorf("Expected no events after cancel, got %+v", event)
+	default:
+	}
+
+	_, missed, cancel = bus.Subscribe(1)
+	defer cancel()
+	if len(missed) != 2 || missed[0].ID != 2 || missed[1].Type != TaskEventDeleted {
+		t.Errorf("Expected the two events after ID 1 replayed, got %+v", missed)
+	}
+
+	// A subscriber that stops reading must not hold up publishing
+	for range eventBuffer + eventBacklog {
+		bus.Publish(TaskEvent{Type: TaskEventUpdated, TaskID: 2})
+	}
+	if _, missed, cancel := bus.Subscribe(1); len(missed) != eventBacklog {
+		t.Errorf("Expected the backlog capped at %d, got %d", eventBacklog, len(missed))
+	} else {
+		cancel()
+	}
+}
+
+func TestTaskService_PublishesEvents(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	events, _, cancel := service.Events().Subscribe(0)
+	defer cancel()
+
+	task, err := service.CreateTask("Watched")
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if event := <-events; event.Type != TaskEventCreated || event.TaskID != task.ID || event.Name != "Watched" {
+		t.Errorf("Expected a task.created event, got %+v", event)
+	}
+
+	comment := &models.Comment{Content: "Looking into it"}
+	if err := service.AddComment(task.ID, comment); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if event := <-events; event.Type != TaskEventCommented || event.CommentID != comment.ID {
+		t.Errorf("Expected a task.commented event, got %+v", event)
+	}
+
+	if err := service.DeleteTask(task.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if event := <-events; event.Type != TaskEventDeleted || event.TaskID != task.ID {
+		t.Errorf("Expected a task.deleted event, got %+v", event)
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var (
+	ErrInboundChannelNotFound = errors.New("inbound channel not found")
+	ErrInboundUnauthorized    = errors.New("invalid inbound channel secret")
+	ErrInvalidInboundPayload  = errors.New("invalid inbound payload")
+)
+
+// What an inbound delivery did
+const (
+	InboundActionCreated   = "created"   // opened a new task
+	InboundActionReopened  = "reopened"  // the alert fired again after its task was resolved
+	InboundActionResolved  = "resolved"  // the alert cleared and its task was resolved
+	InboundActionCommented = "commented" // a repeat of an alert was noted on its open task
+	InboundActionUnchanged = "unchanged" // a repeat of an alert whose task is still open, or already resolved
+	InboundActionIgnored   = "ignored"   // an alert cleared that never opened a task
+)
+
+// InboundResult reports what a webhook delivery did
+type InboundResult struct {
+	Action string       `json:"action"`
+	Task   *models.Task `json:"task,omitempty"`
+}
+
+// inboundChannel is a configured channel with its templates parsed
+type inboundChannel struct {
+	secret      string
+	name        *template.Template
+	description *template.Template
+	priority    *template.Template
+	tags        []*template.Template
+	key         *template.Template
+	resolve     *template.Template
+}
+
+// InboundService opens and resolves tasks from monitoring system webhooks
+// (Grafana alerts, Uptime Kuma and the like) on configured channels
+type InboundService struct {
+	taskService  *TaskService
+	channels     map[string]*inboundChannel
+	alertmanager config.AlertmanagerConfig
+}
+
+// NewInboundService parses the channels' templates, returning an error naming
+// the channel and field of the first that does not parse
+func NewInboundService(taskService *TaskService, channels map[string]config.InboundChannelConfig) (*InboundService, error) {
+	s := &InboundService{
+		taskService: taskService,
+		channels:    make(map[string]*inboundChannel, len(channels)),
+	}
+
+	for name, cfg := range channels {
+		if name == AlertmanagerChannel {
+			return nil, fmt.Errorf("inbound channel %q is reserved for the Alertmanager receiver, see [alertmanager]", name)
+		}
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("inbound channel %q: secret is required", name)
+		}
+
+		var err error
+		parse := func(field, text string) *template.Template {
+			if err != nil || text == "" {
+				return nil
+			}
+			var tmpl *template.Template
+			if tmpl, err = template.New(field).Parse(text); err != nil {
+				err = fmt.Errorf("inbound channel %q: invalid %s template: %w", name, field, err)
+			}
+			return tmpl
+		}
+
+		channel := &inboundChannel{
+			secret:      cfg.Secret,
+			name:        parse("name", cfg.Name),
+			description: parse("description", cfg.Description),
+			priority:    parse("priority", cfg.Priority),
+			key:         parse("key", cfg.Key),
+			resolve:     parse("resolve", cfg.Resolve),
+		}
+		for _, tag := range cfg.Tags {
+			channel.tags = append(channel.tags, parse("tags", tag))
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.channels[name] = channel
+	}
+
+	return s, nil
+}
+
+// Receive applies a webhook delivery on a channel: a new alert opens a task, a
+// repeat leaves it be, a cleared alert resolves it and a recurring one reopens it
+func (s *InboundService) Receive(channelName, secret string, payload map[string]interface{}) (*InboundResult, error) {
+	channel, ok := s.channels[channelName]
+	if !ok {
+		return nil, ErrInboundChannelNotFound
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(channel.secret)) != 1 {
+		return nil, ErrInboundUnauthorized
+	}
+
+	render := func(tmpl *template.Template) (string, error) {
+		if tmpl == nil {
+			return "", nil
+		}
+		var out strings.Builder
+		if err := tmpl.Execute(&out, payload); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidInboundPayload, err)
+		}
+		// Missing payload fields render as "<no value>"; treat them as empty
+		return strings.TrimSpace(strings.ReplaceAll(out.String(), "<no value>", "")), nil
+	}
+
+	alertKey, err := render(channel.key)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := render(channel.resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the task an earlier delivery of the same alert opened
+	var existing *models.Task
+	inboundKey := ""
+	if alertKey != "" {
+		inboundKey = channelName + ":" + alertKey
+		existing, _ = s.taskService.GetTaskByInboundKey(inboundKey)
+	}
+
+	if resolved == "true" {
+		if existing == nil {
+			return &InboundResult{Action: InboundActionIgnored}, nil
+		}
+		if existing.Status == models.TaskStatusResolved || existing.Status == models.TaskStatusClosed {
+			return &InboundResult{Action: InboundActionUnchanged, Task: existing}, nil
+		}
+		return s.setStatus(existing, models.TaskStatusResolved, fmt.Sprintf("Alert cleared (%s)", channelName), InboundActionResolved)
+	}
+
+	if existing != nil {
+		if existing.Status == models.TaskStatusResolved || existing.Status == models.TaskStatusClosed {
+			return s.setStatus(existing, models.TaskStatusOpen, fmt.Sprintf("Alert fired again (%s)", channelName), InboundActionReopened)
+		}
+		return &InboundResult{Action: InboundActionUnchanged, Task: existing}, nil
+	}
+
+	task, err := s.createTask(channelName, channel, render, inboundKey)
+	if err != nil {
+		return nil, err
+	}
+	return &InboundResult{Action: InboundActionCreated, Task: task}, nil
+}
+
+// GetTaskByInboundKey returns the most recent task a webhook alert opened
+func (s *TaskService) GetTaskByInboundKey(key string) (*models.Task, error) {
+	return s.repo.GetByInboundKey(key)
+}
+
+// createTask opens a task from a delivery's rendered fields
+func (s *InboundService) createTask(channelName string, channel *inboundChannel, render func(*template.Template) (string, error), inboundKey string) (*models.Task, error) {
+	name, err := render(channel.name)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = fmt.Sprintf("Alert from %s", channelName)
+	}
+	description, err := render(channel.description)
+	if err != nil {
+		return nil, err
+	}
+	priority, err := render(channel.priority)
+	if err != nil {
+		return nil, err
+	}
+	switch models.TaskPriority(priority) {
+	case "", models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh:
+	default:
+		return nil, fmt.Errorf("%w: priority %q must be low, medium or high", ErrInvalidInboundPayload, priority)
+	}
+	var tags []string
+	for _, tmpl := range channel.tags {
+		tag, err := render(tmpl)
+		if err != nil {
+			return nil, err
+		}
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	task, err := s.taskService.CreateTask(name)
+	if err != nil {
+		return nil, err
+	}
+	task.Description = description
+	task.Priority = models.TaskPriority(priority)
+	task.Tags = tags
+	task.InboundKey = inboundKey
+	if err := s.taskService.UpdateTask(task); err != nil {
+		return nil, err
+	}
+	return s.taskService.GetTask(task.ID)
+}
+
+// setStatus moves an alert's task to a status with a note explaining why
+func (s *InboundService) setStatus(task *models.Task, status models.TaskStatus, note, action string) (*InboundResult, error) {
+	task.Status = status
+	if err := s.taskService.UpdateTask(task); err != nil {
+		return nil, err
+	}
+	if err := s.taskService.AddComment(task.ID, &models.Comment{Content: note, IsPrivate: true}); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.taskService.GetTask(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &InboundResult{Action: action, Task: updated}, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestNewInboundService_Validation(t *testing.T) {
+	if _, err := NewInboundService(nil, map[string]config.InboundChannelConfig{"grafana": {Name: "{{.title}}"}}); err == nil {
+		t.Error("Expected an error for a channel without a secret")
+	}
+	if _, err := NewInboundService(nil, map[string]config.InboundChannelConfig{"grafana": {Secret: "s", Tags: []string{"{{.labels"}}}); err == nil {
+		t.Error("Expected an error for a tag template that does not parse")
+	}
+}
+
+func TestInboundService_Receive(t *testing.T) {
+	db := setupTestDB(t)
+	taskService := NewTaskService(repository.NewTaskRepository(db), nil)
+	service, err := NewInboundService(taskService, map[string]config.InboundChannelConfig{
+		"grafana": {
+			Secret:      "s",
+			Name:        "{{.title}}",
+			Description: "{{.message}}",
+			Priority:    "{{.commonLabels.priority}}",
+			Key:         "{{.groupKey}}",
+			Resolve:     `{{eq .status "resolved"}}`,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create inbound service: %v", err)
+	}
+
+	// Missing fields render empty, and the name falls back to the channel
+	result, err := service.Receive("grafana", "s", map[string]interface{}{"status": "firing"})
+	if err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if result.Action != InboundActionCreated || result.Task.Name != "Alert from grafana" || result.Task.InboundKey != "" {
+		t.Errorf("Expected an unkeyed task named after the channel, got %q %+v", result.Action, result.Task)
+	}
+
+	_, err = service.Receive("grafana", "s", map[string]interface{}{
+		"status":       "firing",
+		"commonLabels": map[string]interface{}{"priority": "urgent"},
+	})
+	if !errors.Is(err, ErrInvalidInboundPayload) {
+		t.Errorf("Expected ErrInvalidInboundPayload for an unknown priority, got %v", err)
+	}
+
+	payload := map[string]interface{}{"status": "firing", "title": "Disk full", "message": "/var at 98%", "groupKey": "disk"}
+	result, err = service.Receive("grafana", "s", payload)
+	if err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if result.Task.Description != "/var at 98%" || result.Task.InboundKey != "grafana:disk" {
+		t.Errorf("Expected the description and key from the payload, got %+v", result.Task)
+	}
+
+	payload["status"] = "resolved"
+	result, err = service.Receive("grafana", "s", payload)
+	if err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	if result.Action != InboundActionResolved || result.Task.ResolvedAt == nil || len(result.Task.Comments) != 1 {
+		t.Errorf("Expected the task to be resolved with a note, got %q %+v", result.Action, result.Task)
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job is a background task run periodically by the JobRunner
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(now time.Time) error
+
+	lastRun time.Time
+}
+
+// JobRunner runs background jobs on fixed intervals from a single ticker, so
+// scheduled work (standup emails, saved query reports) shares one loop
+type JobRunner struct {
+	mu   sync.Mutex
+	jobs []*Job
+	tick time.Duration
+}
+
+// NewJobRunner creates a runner that checks for due jobs once a minute
+func NewJobRunner() *JobRunner {
+	return &JobRunner{tick: time.Minute}
+}
+
+// Every registers a job to run at most once per interval
+func (r *JobRunner) Every(name string, interval time.Duration, run func(now time.Time) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = append(r.jobs, &Job{Name: name, Interval: interval, Run: run})
+}
+
+// RemovePrefix unregisters the jobs whose names start with prefix
+func (r *JobRunner) RemovePrefix(prefix string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = slices.DeleteFunc(r.jobs, func(job *Job) bool { return strings.HasPrefix(job.Name, prefix) })
+}
+
+// Start runs due jobs on every tick. It blocks, so run it in its own goroutine.
+func (r *JobRunner) Start() {
+	ticker := time.NewTicker(r.tick)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		r.runDue(now)
+	}
+}
+
+// runDue runs each job whose interval has elapsed since its last run. Jobs run
+// one after another; a failing job is logged and retried on its next interval.
+func (r *JobRunner) runDue(now time.Time) {
+	r.mu.Lock()
+	jobs := make([]*Job, len(r.jobs))
+	copy(jobs, r.jobs)
+	r.mu.Unlock()
+
+	for _, job := range jobs {
+		if !job.lastRun.IsZero() && now.Sub(job.lastRun) < job.Interval {
+			continue
+		}
+		job.lastRun = now
+		if err := job.Run(now); err != nil {
+			log.Printf("Job %s failed: %v", job.Name, err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/boombuler/barcode/qr"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/pdf"
+)
+
+// JobSheet is the printable form of a task for field technicians: its
+// description, checklist and public comments, with a QR code linking back to
+// the task
+type JobSheet struct {
+	Task      *models.Task
+	Checklist []models.Subtask
+	Comments  []models.Comment // public comments, oldest first
+	URL       string           // where the QR code leads
+	QR        [][]bool         // QR code modules by row, true for dark
+}
+
+// TaskAppPath is the app path that opens a task's detail panel
+func TaskAppPath(taskID uint) string {
+	return fmt.Sprintf("/app?task=%d", taskID)
+}
+
+// GetJobSheet builds the job sheet of a task. Its QR code holds the task's
+// short link on the server at baseURL, such as https://jats.example.com.
+func (s *TaskService) GetJobSheet(taskID uint, baseURL string) (*JobSheet, error) {
+	task, err := s.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	shortCode, err := s.TaskShortCode(taskID)
+	if err != nil {
+		return nil, err
+	}
+	url := baseURL + TaskShortPath(shortCode)
+	code, err := QRCode(url)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &JobSheet{Task: task, Checklist: task.Subtasks, URL: url, QR: code}
+	for _, comment := range task.Comments {
+		if !comment.IsPrivate {
+			sheet.Comments = append(sheet.Comments, comment)
+		}
+	}
+	sort.SliceStable(sheet.Comments, func(i, j int) bool {
+		return sheet.Comments[i].CreatedAt.Before(sheet.Comments[j].CreatedAt)
+	})
+	return sheet, nil
+}
+
+// QRCode encodes content as a QR code, returning its modules by row with true
+// for dark. It does not include the quiet zone.
+func QRCode(content string) ([][]bool, error) {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	bounds := code.Bounds()
+	modules := make([][]bool, bounds.Dy())
+	for y := range modules {
+		modules[y] = make([]bool, bounds.Dx())
+		for x := range modules[y] {
+			r, _, _, _ := code.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			modules[y][x] = r == 0
+		}
+	}
+	return modules, nil
+}
+
+// PDF renders the job sheet as an A4 document
+func (j *JobSheet) PDF() []byte {
+	doc := pdf.New()
+	doc.AddPage()
+
+	const qrSize = 90.0
+	width := pdf.PageWidth - 2*pdf.Margin
+	y := pdf.Margin
+
+	// The QR code sits in the top right corner, beside the title
+	if len(j.QR) > 0 {
+		module := qrSize / float64(len(j.QR))
+		left := pdf.PageWidth - pdf.Margin - qrSize
+		for row, modules := range j.QR {
+			for col, dark := range modules {
+				if dark {
+					doc.Rect(left+float64(col)*module, y+float64(row)*module, module, module)
+				}
+			}
+		}
+	}
+
+	// newLine moves down by height, continuing on a new page at the bottom
+	newLine := func(height float64) {
+		y += height
+		if y > pdf.PageHeight-pdf.Margin {
+			doc.AddPage()
+			y = pdf.Margin + height
+		}
+	}
+
+	titleWidth := width - qrSize - 20
+	for i, line := range pdf.Wrap(fmt.Sprintf("#%d %s", j.Task.ID, j.Task.Name), 18, titleWidth) {
+		if i > 0 {
+			y += 22
+		}
+		doc.Text(pdf.Margin, y+16, 18, true, line)
+	}
+	y += 40
+
+	details := fmt.Sprintf("Status: %s", j.Task.Status)
+	if j.Task.Priority != "" {
+		details += fmt.Sprintf("   Priority: %s", j.Task.Priority)
+	}
+	if j.Task.DueDate != nil {
+		details += "   Due: " + j.Task.DueDate.Format("Jan 2, 2006")
+	}
+	doc.Text(pdf.Margin, y, 10, false, details)
+	if len(j.Task.Tags) > 0 {
+		y += 14
+		doc.Text(pdf.Margin, y, 10, false, "Tags: "+strings.Join(j.Task.Tags, ", "))
+	}
+	y = max(y, pdf.Margin+qrSize) + 10
+
+	section := func(title string) {
+		newLine(24)
+		doc.Text(pdf.Margin, y, 13, true, title)
+		newLine(6)
+		doc.Line(pdf.Margin, pdf.Margin+width, y)
+		newLine(8)
+	}
+	paragraph := func(indent float64, size float64, text string) {
+		for _, line := range pdf.Wrap(text, size, width-indent) {
+			newLine(size + 4)
+			doc.Text(pdf.Margin+indent, y, size, false, line)
+		}
+	}
+
+	section("Description")
+	if strings.TrimSpace(j.Task.Description) == "" {
+		paragraph(0, 10, "No description.")
+	} else {
+		paragraph(0, 10, j.Task.Description)
+	}
+
+	if len(j.Checklist) > 0 {
+		section("Checklist")
+		for _, item := range j.Checklist {
+			newLine(16)
+			// An empty box to tick by hand, filled in for done items
+			if item.Completed {
+				doc.Rect(pdf.Margin, y-9, 10, 10)
+			} else {
+				doc.Box(pdf.Margin, y-9, 10, 10)
+			}
+			lines := pdf.Wrap(item.Name, 10, width-20)
+			doc.Text(pdf.Margin+18, y, 10, false, lines[0])
+			for _, line := range lines[1:] {
+				newLine(14)
+				doc.Text(pdf.Margin+18, y, 10, false, line)
+			}
+		}
+	}
+
+	if len(j.Comments) > 0 {
+		section("Comments")
+		for i, comment := range j.Comments {
+			if i > 0 {
+				newLine(6)
+			}
+			author := comment.CreatedBy
+			if author == "" {
+				author = comment.FromEmail
+			}
+			heading := comment.CreatedAt.Format("Jan 2, 2006 15:04")
+			if author != "" {
+				heading += " - " + author
+			}
+			newLine(14)
+			doc.Text(pdf.Margin, y, 9, true, heading)
+			paragraph(0, 10, comment.Content)
+		}
+	}
+
+	section("Notes")
+	for i := 0; i < 4; i++ {
+		newLine(24)
+		doc.Line(pdf.Margin, pdf.Margin+width, y)
+	}
+
+	newLine(24)
+	doc.Text(pdf.Margin, y, 8, false, j.URL)
+
+	return doc.Bytes()
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_GetJobSheet(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	task, err := service.CreateTask("Replace (boiler) valve")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	task.Description = "Shut off the mains first.\nThe valve is behind the panel."
+	if err := service.UpdateTask(task); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	service.AddSubtask(task.ID, &models.Subtask{Name: "Drain the system", Completed: true})
+	service.AddSubtask(task.ID, &models.Subtask{Name: "Fit the new valve"})
+	service.AddComment(task.ID, &models.Comment{Content: "Customer is home after 2pm", CreatedBy: "alice"})
+	service.AddComment(task.ID, &models.Comment{Content: "Quoted too low", CreatedBy: "bob", IsPrivate: true})
+
+	if _, err := service.GetJobSheet(9999, "http://example.com"); err == nil {
+		t.Errorf("Expected an error for a missing task")
+	}
+
+	sheet, err := service.GetJobSheet(task.ID, "https://jats.example.com")
+	if err != nil {
+		t.Fatalf("GetJobSheet failed: %v", err)
+	}
+	code, _ := service.TaskShortCode(task.ID)
+	url := "https://jats.example.com/s/" + code
+	if sheet.URL != url {
+		t.Errorf("Expected the QR code to hold the short link %q, got %q", url, sheet.URL)
+	}
+	if len(sheet.Checklist) != 2 {
+		t.Errorf("Expected 2 checklist items, got %d", len(sheet.Checklist))
+	}
+	if len(sheet.Comments) != 1 || sheet.Comments[0].Content != "Customer is home after 2pm" {
+		t.Errorf("Expected only the public comment, got %+v", sheet.Comments)
+	}
+	if len(sheet.QR) < 21 || len(sheet.QR[0]) != len(sheet.QR) {
+		t.Errorf("Expected a square QR code of at least 21 modules, got %d rows", len(sheet.QR))
+	}
+
+	doc := sheet.PDF()
+	if !bytes.HasPrefix(doc, []byte("%PDF-")) || !bytes.HasSuffix(doc, []byte("%%EOF\n")) {
+		t.Fatalf("Expected a complete PDF document")
+	}
+	for _, want := range []string{`Replace \(boiler\) valve`, "Fit the new valve", "Customer is home after 2pm", url} {
+		if !bytes.Contains(doc, []byte(want)) {
+			t.Errorf("Expected the PDF to contain %q", want)
+		}
+	}
+	if bytes.Contains(doc, []byte("Quoted too low")) {
+		t.Errorf("Expected the private comment to be left out of the PDF")
+	}
+}
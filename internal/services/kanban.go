@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// ErrWIPLimitReached is returned when moving a task into a column that is already at its WIP limit
+var ErrWIPLimitReached = errors.New("work-in-progress limit reached")
+
+// KanbanStatuses lists the kanban columns in board order
+var KanbanStatuses = []models.TaskStatus{
+	models.TaskStatusOpen,
+	models.TaskStatusInProgress,
+	models.TaskStatusPendingReview,
+	models.TaskStatusResolved,
+	models.TaskStatusClosed,
+}
+
+// WIPColumn describes the work-in-progress state of a single kanban column
+type WIPColumn struct {
+	Count    int  `json:"count"`
+	Limit    int  `json:"limit,omitempty"` // 0 means unlimited
+	Exceeded bool `json:"exceeded"`
+}
+
+// SetWIPLimits configures the per-status WIP limits. When enforce is true,
+// status changes into a column that is already full are rejected.
+func (s *TaskService) SetWIPLimits(limits map[string]int, enforce bool) error {
+	validated := make(map[models.TaskStatus]int, len(limits))
+	for status, limit := range limits {
+		if !isKanbanStatus(models.TaskStatus(status)) {
+			return fmt.Errorf("unknown status %q in WIP limits", status)
+		}
+		if limit < 0 {
+			return fmt.Errorf("WIP limit for %q cannot be negative", status)
+		}
+		validated[models.TaskStatus(status)] = limit
+	}
+
+	s.wipLimits = validated
+	s.enforceWIP = enforce
+	return nil
+}
+
+// WIPLimit returns the configured limit for a status, or 0 if unlimited
+func (s *TaskService) WIPLimit(status models.TaskStatus) int {
+	return s.wipLimits[status]
+}
+
+// WIPColumns counts tasks per kanban column and compares them against the configured limits
+func (s *TaskService) WIPColumns(tasks []*models.Task) map[models.TaskStatus]WIPColumn {
+	columns := make(map[models.TaskStatus]WIPColumn, len(KanbanStatuses))
+	for _, status := range KanbanStatuses {
+		columns[status] = WIPColumn{Limit: s.WIPLimit(status)}
+	}
+
+	for _, task := range tasks {
+		column := columns[task.Status]
+		column.Count++
+		columns[task.Status] = column
+	}
+
+	for status, column := range columns {
+		column.Exceeded = column.Limit > 0 && column.Count > column.Limit
+		columns[status] = column
+	}
+
+	return columns
+}
+
+// SetAgingDays sets after how many days in progress a task is considered aging (0 disables)
+func (s *TaskService) SetAgingDays(days int) {
+	if days < 0 {
+		days = 0
+	}
+	s.agingDays = days
+}
+
+// AgingDays returns the configured aging threshold in days
+func (s *TaskService) AgingDays() int {
+	return s.agingDays
+}
+
+// IsAging reports whether a task has been in progress for longer than the aging threshold
+func (s *TaskService) IsAging(task *models.Task) bool {
+	return s.agingDays > 0 && task.Status == models.TaskStatusInProgress && task.StatusAgeDays > s.agingDays
+}
+
+// checkWIPLimit returns ErrWIPLimitReached if enforcement is enabled and the target column is full
+func (s *TaskService) checkWIPLimit(status models.TaskStatus) error {
+	limit := s.WIPLimit(status)
+	if !s.enforceWIP || limit == 0 {
+		return nil
+	}
+
+	count, err := s.repo.CountByStatus(status)
+	if err != nil {
+		return err
+	}
+	if count >= int64(limit) {
+		return ErrWIPLimitReached
+	}
+	return nil
+}
+
+func isKanbanStatus(status models.TaskStatus) bool {
+	for _, s := range KanbanStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
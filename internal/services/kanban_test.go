@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_WIPLimits(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	if err := service.SetWIPLimits(map[string]int{"blocked": 2}, true); err == nil {
+		t.Error("Expected error for unknown status")
+	}
+	if err := service.SetWIPLimits(map[string]int{"in-progress": 1}, true); err != nil {
+		t.Fatalf("Failed to set WIP limits: %v", err)
+	}
+
+	first, _ := service.CreateTask("First")
+	second, _ := service.CreateTask("Second")
+
+	first.Status = models.TaskStatusInProgress
+	if err := service.UpdateTask(first); err != nil {
+		t.Fatalf("Expected first move to succeed, got %v", err)
+	}
+
+	second.Status = models.TaskStatusInProgress
+	if err := service.UpdateTask(second); err != ErrWIPLimitReached {
+		t.Errorf("Expected ErrWIPLimitReached, got %v", err)
+	}
+
+	// Updating a task that is already in the column is not a move
+	first.Name = "First (renamed)"
+	if err := service.UpdateTask(first); err != nil {
+		t.Errorf("Expected in-column update to succeed, got %v", err)
+	}
+
+	tasks, _ := service.GetTasks()
+	columns := service.WIPColumns(tasks)
+	if columns[models.TaskStatusInProgress].Count != 1 || columns[models.TaskStatusInProgress].Limit != 1 {
+		t.Errorf("Unexpected in-progress column: %+v", columns[models.TaskStatusInProgress])
+	}
+	if columns[models.TaskStatusInProgress].Exceeded {
+		t.Error("Expected column at its limit not to be marked exceeded")
+	}
+
+	// Without enforcement the move is allowed and the column reports the violation
+	if err := service.SetWIPLimits(map[string]int{"in-progress": 1}, false); err != nil {
+		t.Fatalf("Failed to set WIP limits: %v", err)
+	}
+	second, _ = service.GetTask(second.ID)
+	second.Status = models.TaskStatusInProgress
+	if err := service.UpdateTask(second); err != nil {
+		t.Fatalf("Expected unenforced move to succeed, got %v", err)
+	}
+
+	tasks, _ = service.GetTasks()
+	if !service.WIPColumns(tasks)[models.TaskStatusInProgress].Exceeded {
+		t.Error("Expected in-progress column to be marked exceeded")
+	}
+}
+
+func TestTaskService_StatusAging(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+	service.SetAgingDays(3)
+
+	task, err := service.CreateTask("Stuck task")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	task.Status = models.TaskStatusInProgress
+	if err := service.UpdateTask(task); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	history, err := service.GetStatusHistory(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get status history: %v", err)
+	}
+	if len(history) != 1 || history[0].FromStatus != models.TaskStatusOpen || history[0].ToStatus != models.TaskStatusInProgress {
+		t.Fatalf("Unexpected status history: %+v", history)
+	}
+
+	loaded, _ := service.GetTask(task.ID)
+	if loaded.StatusAgeDays != 0 || service.IsAging(loaded) {
+		t.Errorf("Expected fresh task not to be aging, got %d days", loaded.StatusAgeDays)
+	}
+
+	// Backdate the status change past the threshold
+	past := time.Now().Add(-(5*24 + 1) * time.Hour)
+	db.Model(&models.Task{}).Where("id = ?", task.ID).Update("status_changed_at", past)
+
+	loaded, _ = service.GetTask(task.ID)
+	if loaded.StatusAgeDays != 5 {
+		t.Errorf("Expected status age of 5 days, got %d", loaded.StatusAgeDays)
+	}
+	if !service.IsAging(loaded) {
+		t.Error("Expected task to be aging")
+	}
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var (
+	ErrMilestoneNotFound     = errors.New("milestone not found")
+	ErrMilestoneNameRequired = errors.New("milestone name is required")
+)
+
+// maxBurndownDays caps how many daily points a burndown chart has
+const maxBurndownDays = 366
+
+// MilestoneProgress summarizes how far along the tasks of a milestone are
+type MilestoneProgress struct {
+	Milestone       *models.Milestone `json:"milestone"`
+	TotalTasks      int               `json:"total_tasks"`
+	ResolvedTasks   int               `json:"resolved_tasks"`
+	OpenTasks       int               `json:"open_tasks"`
+	Percent         float64           `json:"percent"`
+	LoggedMinutes   int               `json:"logged_minutes"`
+	EstimateMinutes int               `json:"estimate_minutes"`
+	Overdue         bool              `json:"overdue"`
+}
+
+// BurndownPoint is the number of unresolved tasks at the end of a day
+type BurndownPoint struct {
+	Date      string   `json:"date"`
+	Remaining *int     `json:"remaining,omitempty"` // nil for days still to come
+	Ideal     *float64 `json:"ideal,omitempty"`     // straight line to zero on the due date
+}
+
+// MilestoneBurndown tracks the remaining tasks of a milestone day by day
+type MilestoneBurndown struct {
+	Milestone *models.Milestone `json:"milestone"`
+	Points    []BurndownPoint   `json:"points"`
+}
+
+// CreateMilestone validates and stores a new milestone
+func (s *TaskService) CreateMilestone(milestone *models.Milestone) error {
+	milestone.Name = strings.TrimSpace(milestone.Name)
+	if milestone.Name == "" {
+		return ErrMilestoneNameRequired
+	}
+	return s.repo.CreateMilestone(milestone)
+}
+
+// GetMilestones returns all milestones, soonest due first
+func (s *TaskService) GetMilestones() ([]*models.Milestone, error) {
+	return s.repo.GetMilestones()
+}
+
+// GetMilestone returns a milestone or ErrMilestoneNotFound
+func (s *TaskService) GetMilestone(id uint) (*models.Milestone, error) {
+	milestone, err := s.repo.GetMilestoneByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if milestone == nil {
+		return nil, ErrMilestoneNotFound
+	}
+	return milestone, nil
+}
+
+// UpdateMilestone validates and saves changes to a milestone
+func (s *TaskService) UpdateMilestone(milestone *models.Milestone) error {
+	milestone.Name = strings.TrimSpace(milestone.Name)
+	if milestone.Name == "" {
+		return ErrMilestoneNameRequired
+	}
+	return s.repo.UpdateMilestone(milestone)
+}
+
+// DeleteMilestone deletes a milestone; its tasks are kept and detached
+func (s *TaskService) DeleteMilestone(id uint) error {
+	if _, err := s.GetMilestone(id); err != nil {
+		return err
+	}
+	return s.repo.DeleteMilestone(id)
+}
+
+// SetTaskMilestone attaches a task to a milestone, or detaches it when milestoneID is nil
+func (s *TaskService) SetTaskMilestone(taskID uint, milestoneID *uint) (*models.Task, error) {
+	if milestoneID != nil {
+		if _, err := s.GetMilestone(*milestoneID); err != nil {
+			return nil, err
+		}
+	}
+
+	task, err := s.repo.GetByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	task.MilestoneID = milestoneID
+	if err := s.repo.Update(task); err != nil {
+		return nil, err
+	}
+	s.publish(TaskEventUpdated, task)
+	return task, nil
+}
+
+// GetMilestoneTasks returns the tasks attached to a milestone
+func (s *TaskService) GetMilestoneTasks(id uint) ([]*models.Task, error) {
+	if _, err := s.GetMilestone(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTasksByMilestone(id)
+}
+
+// GetMilestoneProgress counts resolved and open tasks and totals the time of a milestone
+func (s *TaskService) GetMilestoneProgress(id uint) (*MilestoneProgress, error) {
+	milestone, err := s.GetMilestone(id)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.GetTasksByMilestone(id)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &MilestoneProgress{Milestone: milestone, TotalTasks: len(tasks)}
+	for _, task := range tasks {
+		if isDone(task) {
+			progress.ResolvedTasks++
+		}
+		progress.LoggedMinutes += task.LoggedMinutes
+		progress.EstimateMinutes += task.EstimateMinutes
+	}
+	progress.OpenTasks = progress.TotalTasks - progress.ResolvedTasks
+	if progress.TotalTasks > 0 {
+		progress.Percent = float64(progress.ResolvedTasks) * 100 / float64(progress.TotalTasks)
+	}
+	progress.Overdue = progress.OpenTasks > 0 && milestone.IsOverdue(time.Now())
+
+	return progress, nil
+}
+
+// GetMilestoneBurndown returns the unresolved task count for each day from the
+// milestone's start until its due date, or until now when it has none
+func (s *TaskService) GetMilestoneBurndown(id uint, now time.Time) (*MilestoneBurndown, error) {
+	milestone, err := s.GetMilestone(id)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.GetTasksByMilestone(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start on the day the milestone or its earliest task was created
+	start := milestone.CreatedAt
+	for _, task := range tasks {
+		if task.CreatedAt.Before(start) {
+			start = task.CreatedAt
+		}
+	}
+	start = startOfDay(start)
+
+	today := startOfDay(now)
+	end := today
+	if milestone.DueDate != nil && startOfDay(*milestone.DueDate).After(end) {
+		end = startOfDay(*milestone.DueDate)
+	}
+	if days := int(end.Sub(start).Hours() / 24); days >= maxBurndownDays {
+		start = end.AddDate(0, 0, -(maxBurndownDays - 1))
+	}
+
+	burndown := &MilestoneBurndown{Milestone: milestone, Points: []BurndownPoint{}}
+
+	var idealDays float64
+	if milestone.DueDate != nil {
+		idealDays = startOfDay(*milestone.DueDate).Sub(start).Hours() / 24
+	}
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		point := BurndownPoint{Date: day.Format("2006-01-02")}
+		dayEnd := day.AddDate(0, 0, 1)
+
+		if !day.After(today) {
+			remaining := 0
+			for _, task := range tasks {
+				if !task.CreatedAt.Before(dayEnd) {
+					continue
+				}
+				if isDone(task) && task.ResolvedAt != nil && task.ResolvedAt.Before(dayEnd) {
+					continue
+				}
+				remaining++
+			}
+			point.Remaining = &remaining
+		}
+
+		if milestone.DueDate != nil {
+			ideal := float64(len(tasks))
+			if idealDays > 0 {
+				ideal -= float64(len(tasks)) * (day.Sub(start).Hours() / 24) / idealDays
+			}
+			if ideal < 0 {
+				ideal = 0
+			}
+			point.Ideal = &ideal
+		}
+
+		burndown.Points = append(burndown.Points, point)
+	}
+
+	return burndown, nil
+}
+
+func isDone(task *models.Task) bool {
+	return task.Status == models.TaskStatusResolved || task.Status == models.TaskStatusClosed
+}
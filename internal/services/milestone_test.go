@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_MilestoneProgressAndBurndown(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	if err := service.CreateMilestone(&models.Milestone{Name: "  "}); err != ErrMilestoneNameRequired {
+		t.Errorf("Expected ErrMilestoneNameRequired, got %v", err)
+	}
+
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.Local)
+	due := time.Date(2024, 3, 5, 0, 0, 0, 0, time.Local)
+	milestone := &models.Milestone{Name: "Launch", DueDate: &due, CreatedAt: start}
+	if err := service.CreateMilestone(milestone); err != nil {
+		t.Fatalf("Failed to create milestone: %v", err)
+	}
+
+	resolvedAt := time.Date(2024, 3, 2, 12, 0, 0, 0, time.Local)
+	tasks := []*models.Task{
+		{Name: "Done", Status: models.TaskStatusResolved, ResolvedAt: &resolvedAt, CreatedAt: start, MilestoneID: &milestone.ID},
+		{Name: "Pending", Status: models.TaskStatusOpen, CreatedAt: start, MilestoneID: &milestone.ID},
+		{Name: "Elsewhere", Status: models.TaskStatusOpen, CreatedAt: start},
+	}
+	for _, task := range tasks {
+		if err := repo.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+	if err := service.AddTimeEntry(tasks[0].ID, &models.TimeEntry{Duration: 45}); err != nil {
+		t.Fatalf("Failed to log time: %v", err)
+	}
+
+	progress, err := service.GetMilestoneProgress(milestone.ID)
+	if err != nil {
+		t.Fatalf("Failed to get progress: %v", err)
+	}
+	if progress.TotalTasks != 2 || progress.ResolvedTasks != 1 || progress.Percent != 50 || progress.LoggedMinutes != 45 {
+		t.Errorf("Unexpected progress: %+v", progress)
+	}
+	if !progress.Overdue {
+		t.Error("Expected milestone with open tasks past its due date to be overdue")
+	}
+
+	burndown, err := service.GetMilestoneBurndown(milestone.ID, time.Date(2024, 3, 3, 10, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("Failed to get burndown: %v", err)
+	}
+	if len(burndown.Points) != 5 {
+		t.Fatalf("Expected points from Mar 1 to the due date Mar 5, got %d", len(burndown.Points))
+	}
+	want := []int{2, 1, 1}
+	for i, remaining := range want {
+		if burndown.Points[i].Remaining == nil || *burndown.Points[i].Remaining != remaining {
+			t.Errorf("Point %s: expected %d remaining, got %v", burndown.Points[i].Date, remaining, burndown.Points[i].Remaining)
+		}
+	}
+	if burndown.Points[4].Remaining != nil {
+		t.Errorf("Expected no remaining count for future days")
+	}
+	if burndown.Points[4].Ideal == nil || *burndown.Points[4].Ideal != 0 {
+		t.Errorf("Expected the ideal line to reach zero on the due date")
+	}
+
+	// Deleting the milestone keeps its tasks
+	if err := service.DeleteMilestone(milestone.ID); err != nil {
+		t.Fatalf("Failed to delete milestone: %v", err)
+	}
+	task, err := service.GetTask(tasks[1].ID)
+	if err != nil || task.MilestoneID != nil {
+		t.Errorf("Expected task to survive and be detached, got %+v, %v", task, err)
+	}
+	if _, err := service.GetMilestoneProgress(milestone.ID); err != ErrMilestoneNotFound {
+		t.Errorf("Expected ErrMilestoneNotFound, got %v", err)
+	}
+}
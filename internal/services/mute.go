@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+var (
+	ErrMuteNotFound        = errors.New("mute not found")
+	ErrInvalidMuteDuration = errors.New("mute duration must be between 1 minute and 90 days")
+	ErrMuteTagRequired     = errors.New("a tag is required to mute a tag")
+)
+
+// maxMuteDuration bounds mutes so a forgotten one still runs out
+const maxMuteDuration = 90 * 24 * time.Hour
+
+// parseMuteDuration parses a mute duration such as "1h" or "168h"
+func parseMuteDuration(duration string) (time.Duration, error) {
+	minutes, err := utils.ParseDuration(strings.TrimSpace(duration))
+	if err != nil || minutes < 1 || time.Duration(minutes)*time.Minute > maxMuteDuration {
+		return 0, ErrInvalidMuteDuration
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// MuteTask suppresses a user's notifications about a task for the duration
+func (s *TaskService) MuteTask(userID, taskID uint, duration string) (*models.NotificationMute, error) {
+	d, err := parseMuteDuration(duration)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByID(taskID); err != nil {
+		return nil, err
+	}
+
+	mute := &models.NotificationMute{UserID: userID, TaskID: &taskID, Until: time.Now().Add(d)}
+	if err := s.repo.CreateMute(mute); err != nil {
+		return nil, err
+	}
+	return mute, nil
+}
+
+// MuteTag suppresses a user's notifications about every task with the tag for the duration
+func (s *TaskService) MuteTag(userID uint, tag, duration string) (*models.NotificationMute, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return nil, ErrMuteTagRequired
+	}
+	d, err := parseMuteDuration(duration)
+	if err != nil {
+		return nil, err
+	}
+
+	mute := &models.NotificationMute{UserID: userID, Tag: tag, Until: time.Now().Add(d)}
+	if err := s.repo.CreateMute(mute); err != nil {
+		return nil, err
+	}
+	return mute, nil
+}
+
+// GetMutes returns a user's mutes that are still in force
+func (s *TaskService) GetMutes(userID uint) ([]*models.NotificationMute, error) {
+	return s.repo.GetActiveMutes(userID, time.Now())
+}
+
+// ActiveMute returns the user's mute covering the task, or nil if it is not muted
+func (s *TaskService) ActiveMute(userID uint, task *models.Task) (*models.NotificationMute, error) {
+	mutes, err := s.repo.GetActiveMutes(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return coveringMute(mutes, task), nil
+}
+
+// Unmute ends one of a user's mutes early
+func (s *TaskService) Unmute(userID, muteID uint) error {
+	deleted, err := s.repo.DeleteMute(userID, muteID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrMuteNotFound
+	}
+	return nil
+}
+
+// UnmuteTask ends every mute of a user's that covers the task, including tag mutes
+func (s *TaskService) UnmuteTask(userID uint, task *models.Task) error {
+	mutes, err := s.repo.GetActiveMutes(userID, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, mute := range mutes {
+		if mute.Covers(task) {
+			if _, err := s.repo.DeleteMute(userID, mute.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ExpireMutes removes the mutes that have run out. It is run by the job runner.
+func (s *TaskService) ExpireMutes(now time.Time) error {
+	expired, err := s.repo.DeleteExpiredMutes(now)
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		log.Printf("Unmuted %d expired notification mute(s)", expired)
+	}
+	return nil
+}
+
+// coveringMute returns the longest-running mute that covers the task
+func coveringMute(mutes []*models.NotificationMute, task *models.Task) *models.NotificationMute {
+	var covering *models.NotificationMute
+	for _, mute := range mutes {
+		if mute.Covers(task) && (covering == nil || mute.Until.After(covering.Until)) {
+			covering = mute
+		}
+	}
+	return covering
+}
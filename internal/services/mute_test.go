@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_Mutes(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	noisy, _ := service.CreateTask("Noisy alert")
+	tagged, _ := service.CreateTask("Backup failed")
+	tagged.Tags = []string{"Monitoring"}
+	if err := service.UpdateTask(tagged); err != nil {
+		t.Fatalf("Failed to tag task: %v", err)
+	}
+	quiet, _ := service.CreateTask("Unrelated")
+
+	if _, err := service.MuteTask(1, noisy.ID, "4h"); err != nil {
+		t.Fatalf("Failed to mute task: %v", err)
+	}
+	if _, err := service.MuteTag(1, "monitoring", "24h"); err != nil {
+		t.Fatalf("Failed to mute tag: %v", err)
+	}
+
+	for _, tt := range []struct {
+		task  *models.Task
+		user  uint
+		muted bool
+	}{
+		{noisy, 1, true},
+		{tagged, 1, true},
+		{quiet, 1, false},
+		{noisy, 2, false},
+	} {
+		mute, err := service.ActiveMute(tt.user, tt.task)
+		if err != nil {
+			t.Fatalf("ActiveMute failed: %v", err)
+		}
+		if (mute != nil) != tt.muted {
+			t.Errorf("Task %q for user %d: expected muted=%t, got %+v", tt.task.Name, tt.user, tt.muted, mute)
+		}
+	}
+
+	if err := service.UnmuteTask(1, tagged); err != nil {
+		t.Fatalf("Failed to unmute task: %v", err)
+	}
+	if mute, _ := service.ActiveMute(1, tagged); mute != nil {
+		t.Errorf("Expected the tag mute to be lifted, got %+v", mute)
+	}
+
+	// The job runner removes mutes once they run out
+	if err := service.ExpireMutes(time.Now().Add(5 * time.Hour)); err != nil {
+		t.Fatalf("ExpireMutes failed: %v", err)
+	}
+	mutes, _ := repo.GetActiveMutes(0, time.Time{})
+	if len(mutes) != 0 {
+		t.Errorf("Expected expired mutes to be deleted, got %d", len(mutes))
+	}
+}
+
+func TestTaskService_MuteValidation(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+	task, _ := service.CreateTask("Task")
+
+	for _, duration := range []string{"", "0", "soon", "2400h"} {
+		if _, err := service.MuteTask(1, task.ID, duration); !errors.Is(err, ErrInvalidMuteDuration) {
+			t.Errorf("Duration %q: expected ErrInvalidMuteDuration, got %v", duration, err)
+		}
+	}
+	if _, err := service.MuteTag(1, " ", "1h"); !errors.Is(err, ErrMuteTagRequired) {
+		t.Errorf("Expected ErrMuteTagRequired, got %v", err)
+	}
+	if err := service.Unmute(1, 42); !errors.Is(err, ErrMuteNotFound) {
+		t.Errorf("Expected ErrMuteNotFound, got %v", err)
+	}
+}
@@ -2,7 +2,10 @@ package services
 
 import (
 	"fmt"
+	"slices"
+	"time"
 
+	"github.com/soarinferret/jats/internal/i18n"
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/repository"
 )
@@ -22,6 +25,18 @@ func NewNotificationService(taskRepo *repository.TaskRepository, authRepo *repos
 }
 
 func (n *NotificationService) NotifyTaskCreated(task *models.Task) error {
+	return n.notifyUsers(task, EmailTemplateTaskCreated, EmailTemplateData{Task: task})
+}
+
+// NotifyTaskStale tells JATS users about the reminder note posted on a task
+// that has had no activity for a while
+func (n *NotificationService) NotifyTaskStale(task *models.Task, note *models.Comment) error {
+	return n.notifyUsers(task, EmailTemplateTaskUpdated, EmailTemplateData{Task: task, Comment: note, Actor: "JATS"})
+}
+
+// notifyUsers emails a rendered template about a task to every active JATS user
+// who has not muted it
+func (n *NotificationService) notifyUsers(task *models.Task, templateName string, data EmailTemplateData) error {
 	// Get all JATS users for notifications
 	users, err := n.authRepo.GetAllUsers()
 	if err != nil {
@@ -32,24 +47,74 @@ func (n *NotificationService) NotifyTaskCreated(task *models.Task) error {
 		return nil
 	}
 
-	subject := fmt.Sprintf("New Task: %s", task.Name)
-	content := n.buildTaskCreatedContent(task)
+	// Users who muted the task, or one of its tags, are left out
+	mutes, err := n.taskRepo.GetActiveMutes(0, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get notification mutes: %w", err)
+	}
+	muted := make(map[uint]bool)
+	for _, mute := range mutes {
+		if mute.Covers(task) {
+			muted[mute.UserID] = true
+		}
+	}
+
+	var recipients []models.User
+	for _, user := range users {
+		if !muted[user.ID] {
+			recipients = append(recipients, user)
+		}
+	}
+	return n.sendToUsers(task, recipients, templateName, data)
+}
+
+// NotifyReviewRequested emails the reviewers of a task that is now pending
+// review. They are asked whether or not they muted the task.
+func (n *NotificationService) NotifyReviewRequested(task *models.Task, reviewers []string) error {
+	users, err := n.authRepo.GetAllUsers()
+	if err != nil {
+		return fmt.Errorf("failed to get JATS users: %w", err)
+	}
 
+	var recipients []models.User
+	for _, user := range users {
+		if slices.Contains(reviewers, user.Username) {
+			recipients = append(recipients, user)
+		}
+	}
+
+	note := &models.Comment{TaskID: task.ID, Content: "This task is waiting for your review: approve it to resolve it, or reopen it with a comment."}
+	return n.sendToUsers(task, recipients, EmailTemplateTaskUpdated, EmailTemplateData{Task: task, Comment: note, Actor: "JATS"})
+}
+
+// sendToUsers emails a rendered template about a task to the active users given
+func (n *NotificationService) sendToUsers(task *models.Task, users []models.User, templateName string, data EmailTemplateData) error {
+	// Group active users by preferred language so each group gets a translated email.
 	// Convert users to TaskSubscriber format for compatibility with SMTP service
-	var subs []models.TaskSubscriber
+	subsByLanguage := make(map[string][]models.TaskSubscriber)
 	for _, user := range users {
 		if user.IsActive { // Only notify active users
-			subs = append(subs, models.TaskSubscriber{
+			lang := i18n.Match(user.Language)
+			subsByLanguage[lang] = append(subsByLanguage[lang], models.TaskSubscriber{
 				Email: user.Email,
 			})
 		}
 	}
 
-	if len(subs) == 0 {
-		return nil
+	var sendErr error
+	for lang, subs := range subsByLanguage {
+		data.Language = lang
+		email, err := n.smtpService.Templates().Render(templateName, data)
+		if err != nil {
+			return fmt.Errorf("failed to render notification: %w", err)
+		}
+
+		if err := n.smtpService.SendRenderedNotification(task, subs, email); err != nil {
+			sendErr = err
+		}
 	}
 
-	return n.smtpService.SendTaskNotification(task, subs, subject, content)
+	return sendErr
 }
 
 func (n *NotificationService) NotifyTaskUpdated(task *models.Task) error {
@@ -70,56 +135,9 @@ func (n *NotificationService) NotifyStatusChanged(task *models.Task, oldStatus,
 	return nil
 }
 
-func (n *NotificationService) buildTaskCreatedContent(task *models.Task) string {
-	content := fmt.Sprintf("A new task has been created:\n\n")
-	content += fmt.Sprintf("Task: %s\n", task.Name)
-
-	if task.Description != "" {
-		content += fmt.Sprintf("Description: %s\n", task.Description)
-	}
-
-	content += fmt.Sprintf("Status: %s\n", task.Status)
-
-	if task.Priority != "" {
-		content += fmt.Sprintf("Priority: %s\n", task.Priority)
-	}
-
-	if len(task.Tags) > 0 {
-		content += fmt.Sprintf("Tags: %s\n", fmt.Sprintf("%v", task.Tags))
-	}
-
-	return content
-}
-
-func (n *NotificationService) buildTaskUpdatedContent(task *models.Task) string {
-	content := fmt.Sprintf("Task has been updated:\n\n")
-	content += fmt.Sprintf("Task: %s\n", task.Name)
-
-	if task.Description != "" {
-		content += fmt.Sprintf("Description: %s\n", task.Description)
-	}
-
-	content += fmt.Sprintf("Status: %s\n", task.Status)
-
-	if task.Priority != "" {
-		content += fmt.Sprintf("Priority: %s\n", task.Priority)
-	}
-
-	if len(task.Tags) > 0 {
-		content += fmt.Sprintf("Tags: %s\n", fmt.Sprintf("%v", task.Tags))
-	}
-
-	return content
-}
-
-func (n *NotificationService) buildStatusChangeContent(task *models.Task, oldStatus, newStatus models.TaskStatus) string {
-	content := fmt.Sprintf("Task status has changed:\n\n")
-	content += fmt.Sprintf("Task: %s\n", task.Name)
-	content += fmt.Sprintf("Status: %s → %s\n", oldStatus, newStatus)
-
-	if task.Priority != "" {
-		content += fmt.Sprintf("Priority: %s\n", task.Priority)
-	}
-
-	return content
+// NotifyRuleMatched tells JATS users that an automation rule asking for a
+// notification matched a task
+func (n *NotificationService) NotifyRuleMatched(task *models.Task, rule *models.AutomationRule) error {
+	note := &models.Comment{TaskID: task.ID, Content: fmt.Sprintf("Automation rule %q matched this task.", rule.Name)}
+	return n.notifyUsers(task, EmailTemplateTaskUpdated, EmailTemplateData{Task: task, Comment: note, Actor: "JATS"})
 }
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+var (
+	ErrOutOfOfficeNotFound     = errors.New("out-of-office range not found")
+	ErrInvalidOutOfOfficeRange = errors.New("out-of-office ranges must end on or after their start and last at most a year")
+)
+
+// maxOutOfOfficeDays bounds a range so a typo in the year does not take a user out for good
+const maxOutOfOfficeDays = 366
+
+// AddOutOfOffice marks a user as away from the start day through the end day
+func (s *TaskService) AddOutOfOffice(user *models.User, start, end time.Time, note string) (*models.OutOfOffice, error) {
+	start, end = startOfDay(start), startOfDay(end)
+	if end.Before(start) || end.After(start.AddDate(0, 0, maxOutOfOfficeDays-1)) {
+		return nil, ErrInvalidOutOfOfficeRange
+	}
+
+	ooo := &models.OutOfOffice{
+		UserID:    user.ID,
+		Username:  user.Username,
+		StartDate: start,
+		EndDate:   end,
+		Note:      strings.TrimSpace(note),
+	}
+	if err := s.repo.CreateOutOfOffice(ooo); err != nil {
+		return nil, err
+	}
+	return ooo, nil
+}
+
+// GetOutOfOffice returns a user's out-of-office ranges that have not ended yet
+func (s *TaskService) GetOutOfOffice(userID uint) ([]*models.OutOfOffice, error) {
+	return s.repo.GetOutOfOffice(userID, startOfDay(time.Now()), time.Date(9999, 1, 1, 0, 0, 0, 0, time.Local))
+}
+
+// GetTeamOutOfOffice returns everyone's out-of-office ranges overlapping the
+// week (Monday to Sunday) containing day
+func (s *TaskService) GetTeamOutOfOffice(day time.Time) ([]*models.OutOfOffice, error) {
+	weekStart := StartOfWeek(day)
+	return s.repo.GetOutOfOffice(0, weekStart, weekStart.AddDate(0, 0, 7))
+}
+
+// DeleteOutOfOffice removes one of a user's out-of-office ranges
+func (s *TaskService) DeleteOutOfOffice(userID, id uint) error {
+	deleted, err := s.repo.DeleteOutOfOffice(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrOutOfOfficeNotFound
+	}
+	return nil
+}
+
+// awayUsernames returns the usernames of the users who are out of office at now
+func awayUsernames(repo *repository.TaskRepository, now time.Time) (map[string]bool, error) {
+	ranges, err := repo.GetOutOfOffice(0, now, now.Add(time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	away := make(map[string]bool, len(ranges))
+	for _, ooo := range ranges {
+		if ooo.Covers(now) {
+			away[ooo.Username] = true
+		}
+	}
+	return away, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_OutOfOffice(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	alice := &models.User{ID: 1, Username: "alice"}
+	bob := &models.User{ID: 2, Username: "bob"}
+	today := startOfDay(time.Now())
+
+	if _, err := service.AddOutOfOffice(alice, today, today.AddDate(0, 0, -1), ""); !errors.Is(err, ErrInvalidOutOfOfficeRange) {
+		t.Errorf("Expected ErrInvalidOutOfOfficeRange for a range ending before it starts, got %v", err)
+	}
+	if _, err := service.AddOutOfOffice(alice, today, today.AddDate(2, 0, 0), ""); !errors.Is(err, ErrInvalidOutOfOfficeRange) {
+		t.Errorf("Expected ErrInvalidOutOfOfficeRange for a two year range, got %v", err)
+	}
+
+	vacation, err := service.AddOutOfOffice(alice, today.Add(10*time.Hour), today.AddDate(0, 0, 2), " Vacation ")
+	if err != nil {
+		t.Fatalf("Failed to add out-of-office range: %v", err)
+	}
+	if !vacation.StartDate.Equal(today) || vacation.Note != "Vacation" {
+		t.Errorf("Expected the range to start at midnight with a trimmed note, got %+v", vacation)
+	}
+	if !vacation.Covers(today.AddDate(0, 0, 2).Add(23*time.Hour)) || vacation.Covers(today.AddDate(0, 0, 3)) {
+		t.Errorf("Expected the range to last through the end of its last day")
+	}
+	if _, err := service.AddOutOfOffice(bob, today.AddDate(0, 0, -30), today.AddDate(0, 0, -20), "Past"); err != nil {
+		t.Fatalf("Failed to add out-of-office range: %v", err)
+	}
+
+	if mine, _ := service.GetOutOfOffice(alice.ID); len(mine) != 1 || mine[0].ID != vacation.ID {
+		t.Errorf("Expected alice's vacation, got %+v", mine)
+	}
+	if past, _ := service.GetOutOfOffice(bob.ID); len(past) != 0 {
+		t.Errorf("Expected bob's past range to be left out, got %+v", past)
+	}
+	if team, _ := service.GetTeamOutOfOffice(today); len(team) != 1 || team[0].Username != "alice" {
+		t.Errorf("Expected only alice to be away this week, got %+v", team)
+	}
+
+	// Auto-assignment skips alice while she is away
+	if err := service.SetAssignmentPolicies(map[string]config.AssignmentConfig{
+		"support": {Policy: AssignmentRoundRobin, Users: []string{"alice", "bob"}},
+		"ops":     {Policy: AssignmentLeastLoaded, Users: []string{"alice"}},
+	}); err != nil {
+		t.Fatalf("Failed to set policies: %v", err)
+	}
+	arrive := func(name, tag string) *models.Task {
+		task, err := service.CreateTaskFromEmail(name, "<"+name+"@example.com>")
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		task.Tags = []string{tag}
+		if err := service.UpdateTask(task); err != nil {
+			t.Fatalf("Failed to tag task: %v", err)
+		}
+		return task
+	}
+	if first, second := arrive("one", "support"), arrive("two", "support"); first.Assignee != "bob" || second.Assignee != "bob" {
+		t.Errorf("Expected bob to get both tasks, got %q and %q", first.Assignee, second.Assignee)
+	}
+	if task := arrive("outage", "ops"); task.Assignee != "alice" {
+		t.Errorf("Expected alice to be assigned when the whole group is away, got %q", task.Assignee)
+	}
+
+	// Automation leaves alice's tasks alone while she is away
+	waiting, _ := service.CreateTask("Waiting on alice")
+	waiting.Assignee = "alice"
+	service.UpdateTask(waiting)
+	automation := NewAutomationService(service, nil, config.AutomationConfig{StaleOpenDays: 1, EscalateDays: 1})
+	if err := automation.Run(today.AddDate(0, 0, 1).Add(12 * time.Hour)); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if task, _ := service.GetTask(waiting.ID); task.Priority != "" || len(task.Comments) != 0 {
+		t.Errorf("Expected alice's task to be left alone, got priority %q and %d notes", task.Priority, len(task.Comments))
+	}
+	if err := automation.Run(today.AddDate(0, 0, 3).Add(12 * time.Hour)); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if task, _ := service.GetTask(waiting.ID); task.Priority != models.TaskPriorityMedium {
+		t.Errorf("Expected alice's task to be escalated once she is back, got %q", task.Priority)
+	}
+
+	if err := service.DeleteOutOfOffice(bob.ID, vacation.ID); !errors.Is(err, ErrOutOfOfficeNotFound) {
+		t.Errorf("Expected ErrOutOfOfficeNotFound deleting someone else's range, got %v", err)
+	}
+	if err := service.DeleteOutOfOffice(alice.ID, vacation.ID); err != nil {
+		t.Fatalf("Failed to delete out-of-office range: %v", err)
+	}
+	if mine, _ := service.GetOutOfOffice(alice.ID); len(mine) != 0 {
+		t.Errorf("Expected the range to be deleted, got %+v", mine)
+	}
+}
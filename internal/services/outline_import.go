@@ -0,0 +1,110 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/outline"
+	"github.com/soarinferret/jats/internal/quickadd"
+)
+
+// ImportOutline creates the tasks of a todo file read by the outline
+// package, in order, with their subtasks. Headings become tags, such as
+// "home-improvements" for "Home Improvements"; with headingProjects the
+// outermost heading instead names the task's project, which is created if
+// no project has that name. Tasks are created like bulk tasks, so nothing is
+// created if one is invalid. If a later step fails, the tasks created so far
+// are returned with the error.
+func (s *TaskService) ImportOutline(items []*outline.Task, headingProjects bool) ([]*models.Task, error) {
+	if len(items) > MaxBulkEntries {
+		return nil, ErrTooManyBulkEntries
+	}
+
+	entries := make([]*quickadd.Task, len(items))
+	projectNames := make([]string, len(items))
+	for i, item := range items {
+		headings := item.Headings
+		if headingProjects && len(headings) > 0 {
+			projectNames[i], headings = strings.TrimSpace(headings[0]), headings[1:]
+		}
+		tags := slices.Clone(item.Tags)
+		for _, heading := range headings {
+			if tag := headingTag(heading); tag != "" && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		entries[i] = &quickadd.Task{
+			Name:     item.Name,
+			Tags:     tags,
+			Priority: item.Priority,
+			DueDate:  item.DueDate,
+			Complete: item.Done,
+		}
+	}
+
+	tasks, err := s.CreateQuickTasks(entries)
+	if err != nil {
+		return tasks, err
+	}
+
+	projects := make(map[string]*models.Project)
+	for i, task := range tasks {
+		item := items[i]
+		for _, subtask := range item.Subtasks {
+			if err := s.AddSubtask(task.ID, &models.Subtask{Name: subtask.Name, Completed: subtask.Done}); err != nil {
+				return tasks, fmt.Errorf("line %d: failed to add subtask: %w", item.Line, err)
+			}
+		}
+
+		if name := projectNames[i]; name != "" {
+			project, err := s.importProject(name, projects)
+			if err != nil {
+				return tasks, fmt.Errorf("line %d: project %q: %w", item.Line, name, err)
+			}
+			task.ProjectID = &project.ID
+			if err := s.UpdateTask(task); err != nil {
+				return tasks, fmt.Errorf("line %d: %w", item.Line, err)
+			}
+		}
+
+		if len(item.Subtasks) > 0 {
+			if tasks[i], err = s.GetTask(task.ID); err != nil {
+				return tasks, err
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// importProject returns the project with a name, ignoring case, creating it
+// the first time an import needs it
+func (s *TaskService) importProject(name string, projects map[string]*models.Project) (*models.Project, error) {
+	key := strings.ToLower(name)
+	if project, ok := projects[key]; ok {
+		return project, nil
+	}
+	project, err := s.repo.GetProjectByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		project = &models.Project{Name: name}
+		if err := s.CreateProject(project); err != nil {
+			return nil, err
+		}
+	}
+	projects[key] = project
+	return project, nil
+}
+
+// headingTag turns a heading into a tag: lower case, with words joined by
+// hyphens
+func headingTag(heading string) string {
+	words := strings.FieldsFunc(strings.ToLower(heading), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
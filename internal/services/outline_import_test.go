@@ -0,0 +1,79 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/outline"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_ImportOutline(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	items, err := outline.ParseMarkdown(strings.NewReader(`# Home Improvements
+## Garden
+- [ ] Fix the fence
+  - [x] Buy posts
+  - [ ] Dig holes
+- [x] Paint the shed
+`))
+	if err != nil {
+		t.Fatalf("ParseMarkdown failed: %v", err)
+	}
+
+	tasks, err := service.ImportOutline(items, false)
+	if err != nil {
+		t.Fatalf("ImportOutline failed: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %d", len(tasks))
+	}
+
+	fence := tasks[0]
+	if fence.Name != "Fix the fence" || strings.Join(fence.Tags, ",") != "home-improvements,garden" {
+		t.Errorf("Expected the headings as tags, got %q with %q", fence.Name, fence.Tags)
+	}
+	if len(fence.Subtasks) != 2 || fence.Subtasks[0].Name != "Buy posts" || !fence.Subtasks[0].Completed || fence.Subtasks[1].Completed {
+		t.Errorf("Unexpected subtasks %+v", fence.Subtasks)
+	}
+	if tasks[1].Status != models.TaskStatusResolved {
+		t.Errorf("Expected the checked item resolved, got %s", tasks[1].Status)
+	}
+
+	// The outermost heading names a project, made once and then reused
+	tasks, err = service.ImportOutline(items, true)
+	if err != nil {
+		t.Fatalf("ImportOutline with projects failed: %v", err)
+	}
+	project, err := repo.GetProjectByName("home improvements")
+	if err != nil || project == nil {
+		t.Fatalf("Expected the heading's project to be created, got %v", err)
+	}
+	for _, task := range tasks {
+		if task.ProjectID == nil || *task.ProjectID != project.ID {
+			t.Errorf("Expected task %q in project %d, got %v", task.Name, project.ID, task.ProjectID)
+		}
+		if strings.Join(task.Tags, ",") != "garden" {
+			t.Errorf("Expected only the inner heading as a tag, got %q", task.Tags)
+		}
+	}
+}
+
+func TestTaskService_ImportOutlineIsAllOrNothing(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	items := []*outline.Task{{Name: "Valid"}, {Name: "Bad deadline", DueDate: "2025-13-45"}}
+	if _, err := service.ImportOutline(items, false); err == nil {
+		t.Fatal("Expected an invalid deadline to fail the import")
+	}
+	tasks, _ := service.GetTasks()
+	if len(tasks) != 0 {
+		t.Errorf("Expected no tasks created, got %d", len(tasks))
+	}
+}
@@ -0,0 +1,468 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/d5/tengo/v2"
+	"github.com/d5/tengo/v2/stdlib"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var ErrPluginNotFound = errors.New("plugin not found")
+
+// pluginModules are the Tengo standard library modules plugins may import.
+// os, fmt and rand are left out, so scripts cannot reach the server's files,
+// processes or output.
+var pluginModules = []string{"text", "times", "math", "json", "enum", "base64", "hex"}
+
+// pluginMaxAllocs bounds the objects a plugin may allocate in one run, so a
+// runaway script fails instead of exhausting memory
+const pluginMaxAllocs = 1 << 20
+
+// pluginNamePattern is what plugin names, which appear in their route URLs,
+// are limited to
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// PluginRequest is a request to a plugin's routes, as its script sees it
+type PluginRequest struct {
+	Method string            `json:"method"`
+	Path   string            `json:"path"` // below /api/v1/plugins/{name}, e.g. "/hello"
+	Query  map[string]string `json:"query"`
+	Body   string            `json:"body"`
+	User   string            `json:"user"` // username of the caller
+}
+
+// PluginResponse is a plugin's answer to a request: the status code, and the
+// data of a success or the message of an error
+type PluginResponse struct {
+	Status int
+	Body   any
+}
+
+// PluginStatus describes a loaded plugin
+type PluginStatus struct {
+	Name        string     `json:"name"`
+	File        string     `json:"file"`
+	Runs        int        `json:"runs"`
+	Failures    int        `json:"failures"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+type plugin struct {
+	name     string
+	file     string
+	compiled *tengo.Compiled
+	// The script with a "jats" module that cannot change tasks, for GET requests
+	readOnly *tengo.Compiled
+
+	mu     sync.Mutex
+	status PluginStatus
+}
+
+// PluginService runs operator-written Tengo scripts on task events and on
+// requests to their routes, see config.PluginsConfig. Scripts act on tasks
+// through the "jats" module with the server's own rights.
+type PluginService struct {
+	taskService *TaskService
+	timeout     time.Duration
+	plugins     []*plugin // sorted by name
+
+	mu   sync.Mutex
+	stop func()
+}
+
+// NewPluginService compiles every *.tengo script in dir, each a plugin named
+// after its file. An empty dir loads no plugins.
+func NewPluginService(taskService *TaskService, dir string, timeout time.Duration) (*PluginService, error) {
+	s := &PluginService{taskService: taskService, timeout: timeout}
+	if dir == "" {
+		return s, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.tengo"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	for _, file := range files {
+		name := strings.ToLower(strings.TrimSuffix(filepath.Base(file), ".tengo"))
+		if !pluginNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("plugin %s: name must be letters, digits, dashes and underscores", file)
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", name, err)
+		}
+
+		p := &plugin{name: name, file: file, status: PluginStatus{Name: name, File: file}}
+		if p.compiled, err = s.compile(p, src, false); err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", name, err)
+		}
+		if p.readOnly, err = s.compile(p, src, true); err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", name, err)
+		}
+		s.plugins = append(s.plugins, p)
+	}
+	return s, nil
+}
+
+// compile compiles a plugin's script, with a "jats" module that cannot change
+// tasks if readOnly
+func (s *PluginService) compile(p *plugin, src []byte, readOnly bool) (*tengo.Compiled, error) {
+	script := tengo.NewScript(src)
+	modules := stdlib.GetModuleMap(pluginModules...)
+	modules.AddBuiltinModule("jats", s.module(p, readOnly))
+	script.SetImports(modules)
+	script.SetMaxAllocs(pluginMaxAllocs)
+	for _, variable := range []string{"event", "request", "response"} {
+		if err := script.Add(variable, nil); err != nil {
+			return nil, err
+		}
+	}
+	return script.Compile()
+}
+
+// Plugins returns the loaded plugins and how their runs went
+func (s *PluginService) Plugins() []PluginStatus {
+	statuses := make([]PluginStatus, 0, len(s.plugins))
+	for _, p := range s.plugins {
+		p.mu.Lock()
+		statuses = append(statuses, p.status)
+		p.mu.Unlock()
+	}
+	return statuses
+}
+
+// Start runs the plugins on each task event, one event at a time, until Stop
+// is called. Like other subscribers of the event bus, plugins that fall far
+// behind miss events. Changes plugins make are events too, so a hook should
+// check before it changes a task, or it may keep triggering itself.
+func (s *PluginService) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.plugins) == 0 || s.stop != nil {
+		return
+	}
+
+	events, _, cancel := s.taskService.Events().Subscribe(0)
+	done := make(chan struct{})
+	s.stop = func() {
+		cancel()
+		close(done)
+	}
+	go func() {
+		for {
+			select {
+			case event := <-events:
+				s.HandleEvent(event)
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops running the plugins on task events
+func (s *PluginService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		s.stop()
+		s.stop = nil
+	}
+}
+
+// HandleEvent runs every plugin on a task event. A failing plugin is logged
+// and does not stop the others.
+func (s *PluginService) HandleEvent(event TaskEvent) {
+	value, err := scriptValue(event)
+	if err != nil {
+		log.Printf("Failed to encode %s event for plugins: %v", event.Type, err)
+		return
+	}
+	for _, p := range s.plugins {
+		if _, err := s.run(p, p.compiled, "event", value); err != nil {
+			log.Printf("Plugin %q failed on %s of task %d: %v", p.name, event.Type, event.TaskID, err)
+		}
+	}
+}
+
+// Serve runs a plugin on a request to its routes. A script that sets no
+// response answers 404. Only POST requests may change tasks: in a GET, the
+// "jats" module's changing functions stop the script with an error.
+func (s *PluginService) Serve(name string, req *PluginRequest) (*PluginResponse, error) {
+	i := slices.IndexFunc(s.plugins, func(p *plugin) bool { return p.name == name })
+	if i < 0 {
+		return nil, ErrPluginNotFound
+	}
+	p := s.plugins[i]
+
+	value, err := scriptValue(req)
+	if err != nil {
+		return nil, err
+	}
+	script := p.compiled
+	if req.Method != http.MethodPost {
+		script = p.readOnly
+	}
+	compiled, err := s.run(p, script, "request", value)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", name, err)
+	}
+
+	resp := &PluginResponse{Status: http.StatusOK}
+	switch value := compiled.Get("response").Value().(type) {
+	case nil:
+		return &PluginResponse{Status: http.StatusNotFound, Body: "Not found"}, nil
+	case map[string]any:
+		if status, ok := value["status"].(int64); ok {
+			if status < 200 || status > 599 {
+				return nil, fmt.Errorf("plugin %s: response status %d is not valid", name, status)
+			}
+			resp.Status = int(status)
+		}
+		resp.Body = value["body"]
+	default:
+		resp.Body = value
+	}
+	return resp, nil
+}
+
+// run runs a plugin's compiled script with one of its input variables set
+func (s *PluginService) run(p *plugin, script *tengo.Compiled, variable string, value any) (*tengo.Compiled, error) {
+	compiled := script.Clone()
+	err := compiled.Set(variable, value)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		defer cancel()
+		err = compiled.RunContext(ctx)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status.Runs++
+	if err != nil {
+		now := time.Now()
+		p.status.Failures++
+		p.status.LastError = err.Error()
+		p.status.LastErrorAt = &now
+	}
+	return compiled, err
+}
+
+// module returns the "jats" module a plugin uses to read and change tasks.
+// Wrong arguments stop the script; failed changes return an error value
+// scripts can check with is_error. In a read-only module, the functions that
+// change tasks stop the script.
+func (s *PluginService) module(p *plugin, readOnly bool) map[string]tengo.Object {
+	module := map[string]tengo.Object{
+		"log": &tengo.UserFunction{Name: "log", Value: func(args ...tengo.Object) (tengo.Object, error) {
+			parts := make([]string, len(args))
+			for i, arg := range args {
+				parts[i], _ = tengo.ToString(arg)
+			}
+			log.Printf("Plugin %q: %s", p.name, strings.Join(parts, " "))
+			return tengo.UndefinedValue, nil
+		}},
+		"get_task": &tengo.UserFunction{Name: "get_task", Value: func(args ...tengo.Object) (tengo.Object, error) {
+			if len(args) != 1 {
+				return nil, tengo.ErrWrongNumArguments
+			}
+			id, err := taskIDArg(args[0])
+			if err != nil {
+				return nil, err
+			}
+			task, err := s.taskService.GetTask(id)
+			if err != nil {
+				return tengo.UndefinedValue, nil
+			}
+			return taskObject(task)
+		}},
+		"create_task": &tengo.UserFunction{Name: "create_task", Value: func(args ...tengo.Object) (tengo.Object, error) {
+			if len(args) != 1 {
+				return nil, tengo.ErrWrongNumArguments
+			}
+			name, err := stringArg(args[0], "name")
+			if err != nil {
+				return nil, err
+			}
+			task, err := s.taskService.CreateTask(name)
+			if err != nil {
+				return errorObject(err), nil
+			}
+			return taskObject(task)
+		}},
+		"add_comment": &tengo.UserFunction{Name: "add_comment", Value: func(args ...tengo.Object) (tengo.Object, error) {
+			// add_comment(task_id, text[, public]) posts a private note unless public is true
+			if len(args) < 2 || len(args) > 3 {
+				return nil, tengo.ErrWrongNumArguments
+			}
+			id, err := taskIDArg(args[0])
+			if err != nil {
+				return nil, err
+			}
+			text, err := stringArg(args[1], "text")
+			if err != nil {
+				return nil, err
+			}
+			comment := &models.Comment{Content: text, IsPrivate: len(args) < 3 || args[2].IsFalsy()}
+			if err := s.taskService.AddComment(id, comment); err != nil {
+				return errorObject(err), nil
+			}
+			return tengo.TrueValue, nil
+		}},
+		"add_tag": s.taskChange("add_tag", "tag", func(task *models.Task, tag string) (bool, error) {
+			if slices.ContainsFunc(task.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+				return false, nil
+			}
+			task.Tags = append(task.Tags, tag)
+			return true, nil
+		}),
+		"set_status": s.taskChange("set_status", "status", func(task *models.Task, status string) (bool, error) {
+			if !isKanbanStatus(models.TaskStatus(status)) {
+				return false, fmt.Errorf("%q is not a task status", status)
+			}
+			changed := task.Status != models.TaskStatus(status)
+			task.Status = models.TaskStatus(status)
+			return changed, nil
+		}),
+		"set_priority": s.taskChange("set_priority", "priority", func(task *models.Task, priority string) (bool, error) {
+			switch models.TaskPriority(priority) {
+			case "", models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh:
+			default:
+				return false, fmt.Errorf("%q is not a task priority", priority)
+			}
+			changed := task.Priority != models.TaskPriority(priority)
+			task.Priority = models.TaskPriority(priority)
+			return changed, nil
+		}),
+		"set_assignee": s.taskChange("set_assignee", "assignee", func(task *models.Task, assignee string) (bool, error) {
+			changed := task.Assignee != assignee
+			task.Assignee = assignee
+			return changed, nil
+		}),
+	}
+	if readOnly {
+		for name := range module {
+			if name != "log" && name != "get_task" {
+				module[name] = &tengo.UserFunction{Name: name, Value: func(args ...tengo.Object) (tengo.Object, error) {
+					return nil, fmt.Errorf("jats.%s changes tasks, which only POST requests and task events may do", name)
+				}}
+			}
+		}
+	}
+	return module
+}
+
+// taskChange returns a module function name(task_id, value) that applies
+// change to the task and saves it if anything changed, returning whether it did
+func (s *PluginService) taskChange(name, valueName string, change func(task *models.Task, value string) (bool, error)) *tengo.UserFunction {
+	return &tengo.UserFunction{Name: name, Value: func(args ...tengo.Object) (tengo.Object, error) {
+		if len(args) != 2 {
+			return nil, tengo.ErrWrongNumArguments
+		}
+		id, err := taskIDArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		value, err := stringArg(args[1], valueName)
+		if err != nil {
+			return nil, err
+		}
+
+		task, err := s.taskService.GetTask(id)
+		if err != nil {
+			return errorObject(err), nil
+		}
+		changed, err := change(task, value)
+		if err != nil {
+			return errorObject(err), nil
+		}
+		if !changed {
+			return tengo.FalseValue, nil
+		}
+		if err := s.taskService.UpdateTask(task); err != nil {
+			return errorObject(err), nil
+		}
+		return tengo.TrueValue, nil
+	}}
+}
+
+func taskIDArg(arg tengo.Object) (uint, error) {
+	id, ok := arg.(*tengo.Int)
+	if !ok || id.Value <= 0 {
+		return 0, tengo.ErrInvalidArgumentType{Name: "task_id", Expected: "int", Found: arg.TypeName()}
+	}
+	return uint(id.Value), nil
+}
+
+func stringArg(arg tengo.Object, name string) (string, error) {
+	value, ok := arg.(*tengo.String)
+	if !ok {
+		return "", tengo.ErrInvalidArgumentType{Name: name, Expected: "string", Found: arg.TypeName()}
+	}
+	return value.Value, nil
+}
+
+func errorObject(err error) tengo.Object {
+	return &tengo.Error{Value: &tengo.String{Value: err.Error()}}
+}
+
+func taskObject(task *models.Task) (tengo.Object, error) {
+	value, err := scriptValue(task)
+	if err != nil {
+		return nil, err
+	}
+	return tengo.FromInterface(value)
+}
+
+// scriptValue converts v to the maps, arrays, strings, numbers and bools
+// scripts work with, by way of its JSON encoding. Whole numbers stay ints, so
+// IDs compare equal to the ones scripts write.
+func scriptValue(v any) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return convertNumbers(value), nil
+}
+
+func convertNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, value := range v {
+			v[key] = convertNumbers(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = convertNumbers(value)
+		}
+	}
+	return v
+}
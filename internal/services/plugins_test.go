@@ -0,0 +1,224 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+// writePlugins writes scripts keyed by file name into a temporary directory
+func writePlugins(t *testing.T, scripts map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, src := range scripts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestPluginService_Load(t *testing.T) {
+	service, err := NewPluginService(nil, "", time.Second)
+	if err != nil || len(service.Plugins()) != 0 {
+		t.Fatalf("Expected no plugins without a directory, got %v, %v", service.Plugins(), err)
+	}
+
+	dir := writePlugins(t, map[string]string{
+		"b.tengo":   `x := 1`,
+		"a.tengo":   `y := 2`,
+		"notes.txt": `not a plugin`,
+	})
+	service, err = NewPluginService(nil, dir, time.Second)
+	if err != nil {
+		t.Fatalf("NewPluginService failed: %v", err)
+	}
+	plugins := service.Plugins()
+	if len(plugins) != 2 || plugins[0].Name != "a" || plugins[1].Name != "b" {
+		t.Errorf("Expected plugins a and b, got %+v", plugins)
+	}
+
+	dir = writePlugins(t, map[string]string{"broken.tengo": `x := `})
+	if _, err := NewPluginService(nil, dir, time.Second); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Expected a compile error naming the plugin, got %v", err)
+	}
+
+	dir = writePlugins(t, map[string]string{"files.tengo": `os := import("os")`})
+	if _, err := NewPluginService(nil, dir, time.Second); err == nil {
+		t.Error("Expected the os module to be unavailable to plugins")
+	}
+}
+
+func TestPluginService_Hooks(t *testing.T) {
+	db := setupTestDB(t)
+	taskService := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	dir := writePlugins(t, map[string]string{"triage.tengo": `
+jats := import("jats")
+text := import("text")
+
+if event && event.type == "task.created" {
+	task := jats.get_task(event.task_id)
+	if text.contains(text.to_lower(task.name), "outage") {
+		jats.set_priority(task.id, "high")
+		jats.add_tag(task.id, "incident")
+		jats.add_comment(task.id, "Flagged as an incident")
+	}
+}
+`})
+	service, err := NewPluginService(taskService, dir, time.Second)
+	if err != nil {
+		t.Fatalf("NewPluginService failed: %v", err)
+	}
+
+	outage, _ := taskService.CreateTask("Email outage")
+	service.HandleEvent(TaskEvent{Type: TaskEventCreated, TaskID: outage.ID})
+	quiet, _ := taskService.CreateTask("Order toner")
+	service.HandleEvent(TaskEvent{Type: TaskEventCreated, TaskID: quiet.ID})
+
+	task, err := taskService.GetTask(outage.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Priority != models.TaskPriorityHigh || !slices.Contains(task.Tags, "incident") {
+		t.Errorf("Expected the outage raised to high and tagged, got %q %v", task.Priority, task.Tags)
+	}
+	if len(task.Comments) != 1 || !task.Comments[0].IsPrivate || task.Comments[0].Content != "Flagged as an incident" {
+		t.Errorf("Expected a private note on the outage, got %+v", task.Comments)
+	}
+	if task, _ := taskService.GetTask(quiet.ID); task.Priority != "" || len(task.Tags) != 0 {
+		t.Errorf("Expected the other task untouched, got %q %v", task.Priority, task.Tags)
+	}
+	if status := service.Plugins()[0]; status.Runs != 2 || status.Failures != 0 {
+		t.Errorf("Expected two successful runs, got %+v", status)
+	}
+}
+
+func TestPluginService_StartStop(t *testing.T) {
+	db := setupTestDB(t)
+	taskService := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	// Adding a tag already there changes nothing, so the hook settles
+	dir := writePlugins(t, map[string]string{"tagger.tengo": `
+jats := import("jats")
+if event && event.type != "task.deleted" {
+	jats.add_tag(event.task_id, "seen")
+}
+`})
+	service, err := NewPluginService(taskService, dir, time.Second)
+	if err != nil {
+		t.Fatalf("NewPluginService failed: %v", err)
+	}
+	service.Start()
+	defer service.Stop()
+
+	task, _ := taskService.CreateTask("Watched")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		current, err := taskService.GetTask(task.ID)
+		if err == nil && slices.Contains(current.Tags, "seen") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the plugin to tag the new task")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPluginService_Serve(t *testing.T) {
+	db := setupTestDB(t)
+	taskService := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	dir := writePlugins(t, map[string]string{
+		"hello.tengo": `
+jats := import("jats")
+json := import("json")
+
+if request {
+	if request.method == "GET" && request.path == "/greet" {
+		response = {body: {greeting: "Hello, " + request.query.name, user: request.user}}
+	} else if request.path == "/tasks" {
+		input := json.decode(request.body)
+		if !input.name {
+			response = {status: 400, body: "name is required"}
+		} else {
+			task := jats.create_task(input.name)
+			response = {status: 201, body: {id: task.id}}
+		}
+	}
+}
+`,
+		"slow.tengo":   `if request { for {} }`,
+		"failed.tengo": `jats := import("jats"); if request { jats.get_task("one") }`,
+	})
+	service, err := NewPluginService(taskService, dir, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewPluginService failed: %v", err)
+	}
+
+	resp, err := service.Serve("hello", &PluginRequest{Method: "GET", Path: "/greet", Query: map[string]string{"name": "Ada"}, User: "ada"})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	if body, _ := resp.Body.(map[string]any); resp.Status != http.StatusOK || body["greeting"] != "Hello, Ada" || body["user"] != "ada" {
+		t.Errorf("Unexpected greeting %d %+v", resp.Status, resp.Body)
+	}
+
+	resp, err = service.Serve("hello", &PluginRequest{Method: "POST", Path: "/tasks", Body: `{"name": "From a plugin"}`})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	body, _ := resp.Body.(map[string]any)
+	id, _ := body["id"].(int64)
+	if resp.Status != http.StatusCreated || id == 0 {
+		t.Fatalf("Expected a created task ID, got %d %+v", resp.Status, resp.Body)
+	}
+	if task, err := taskService.GetTask(uint(id)); err != nil || task.Name != "From a plugin" {
+		t.Errorf("Expected the plugin to create the task, got %+v, %v", task, err)
+	}
+
+	resp, err = service.Serve("hello", &PluginRequest{Method: "POST", Path: "/tasks", Body: `{}`})
+	if err != nil || resp.Status != http.StatusBadRequest || resp.Body != "name is required" {
+		t.Errorf("Expected the plugin's 400, got %+v, %v", resp, err)
+	}
+
+	// A GET cannot change tasks, even on a path that would in a POST
+	if _, err := service.Serve("hello", &PluginRequest{Method: "GET", Path: "/tasks", Body: `{"name": "Sneaky"}`}); err == nil || !strings.Contains(err.Error(), "create_task") {
+		t.Errorf("Expected create_task to fail in a GET, got %v", err)
+	}
+	if tasks, _ := taskService.GetTasks(); len(tasks) != 1 {
+		t.Errorf("Expected only the POST to create a task, got %d tasks", len(tasks))
+	}
+
+	resp, err = service.Serve("hello", &PluginRequest{Method: "GET", Path: "/missing"})
+	if err != nil || resp.Status != http.StatusNotFound {
+		t.Errorf("Expected 404 when the plugin sets no response, got %+v, %v", resp, err)
+	}
+
+	if _, err := service.Serve("nope", &PluginRequest{Method: "GET", Path: "/"}); !errors.Is(err, ErrPluginNotFound) {
+		t.Errorf("Expected ErrPluginNotFound, got %v", err)
+	}
+
+	start := time.Now()
+	if _, err := service.Serve("slow", &PluginRequest{Method: "GET", Path: "/"}); err == nil || time.Since(start) > 5*time.Second {
+		t.Errorf("Expected a looping script stopped at the timeout, got %v after %s", err, time.Since(start))
+	}
+
+	if _, err := service.Serve("failed", &PluginRequest{Method: "GET", Path: "/"}); err == nil || !strings.Contains(err.Error(), "task_id") {
+		t.Errorf("Expected a wrong argument to fail the script, got %v", err)
+	}
+	for _, status := range service.Plugins() {
+		if status.Name == "failed" && (status.Failures != 1 || status.LastError == "" || status.LastErrorAt == nil) {
+			t.Errorf("Expected the failure recorded, got %+v", status)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/soarinferret/jats/internal/i18n"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// Views the web UI and TUI can open on
+const (
+	LandingViewTasks     = "tasks"
+	LandingViewKanban    = "kanban"
+	LandingViewReports   = "reports"
+	LandingViewDashboard = "dashboard"
+)
+
+// LandingViews are the valid landing views
+var LandingViews = []string{LandingViewTasks, LandingViewKanban, LandingViewReports, LandingViewDashboard}
+
+// ErrInvalidLandingView is returned for a landing view that is not one of LandingViews
+var ErrInvalidLandingView = errors.New("invalid landing view")
+
+// ProfileUpdate holds the preferences a profile update changes; nil fields
+// are left as they are
+type ProfileUpdate struct {
+	Language       *string
+	StandupEmail   *bool
+	WeeklyCapacity *int    // minutes; 0 clears it
+	LandingView    *string // one of LandingViews; "" means the task list
+	LandingQueryID *uint   // saved query to open on; 0 clears it
+	PinnedQueryIDs *[]uint
+}
+
+// UpdateProfile checks every preference in an update before saving them
+// together, so a rejected update leaves the profile as it was. Saved query IDs
+// are checked with ValidateSavedQueryIDs by the caller.
+func (s *AuthService) UpdateProfile(userID uint, update ProfileUpdate) (*models.User, error) {
+	if update.Language != nil && !i18n.IsSupported(*update.Language) {
+		return nil, ErrUnsupportedLanguage
+	}
+	if update.WeeklyCapacity != nil && (*update.WeeklyCapacity < 0 || *update.WeeklyCapacity > 7*24*60) {
+		return nil, ErrInvalidCapacity
+	}
+	if update.LandingView != nil && *update.LandingView != "" && !slices.Contains(LandingViews, *update.LandingView) {
+		return nil, ErrInvalidLandingView
+	}
+
+	user, err := s.authRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if update.Language != nil {
+		user.Language = i18n.Match(*update.Language)
+	}
+	if update.StandupEmail != nil {
+		user.StandupEmail = *update.StandupEmail
+	}
+	if update.WeeklyCapacity != nil {
+		user.WeeklyCapacity = *update.WeeklyCapacity
+	}
+	if update.LandingView != nil {
+		user.LandingView = *update.LandingView
+	}
+	if update.LandingQueryID != nil {
+		user.LandingQueryID = update.LandingQueryID
+		if *update.LandingQueryID == 0 {
+			user.LandingQueryID = nil
+		}
+	}
+	if update.PinnedQueryIDs != nil {
+		user.PinnedQueryIDs = uniqueIDs(*update.PinnedQueryIDs)
+	}
+	if err := s.authRepo.UpdateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return user, nil
+}
+
+// SetPinnedQueries sets the saved queries a user pins to the top of the sidebar
+func (s *AuthService) SetPinnedQueries(userID uint, queryIDs []uint) (*models.User, error) {
+	user, err := s.authRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	user.PinnedQueryIDs = uniqueIDs(queryIDs)
+	if err := s.authRepo.UpdateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return user, nil
+}
+
+// TogglePinnedQuery pins a saved query for a user, or unpins it if it is pinned
+func (s *AuthService) TogglePinnedQuery(userID, queryID uint) (*models.User, error) {
+	user, err := s.authRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if i := slices.Index(user.PinnedQueryIDs, queryID); i >= 0 {
+		return s.SetPinnedQueries(userID, slices.Delete(user.PinnedQueryIDs, i, i+1))
+	}
+	return s.SetPinnedQueries(userID, append(user.PinnedQueryIDs, queryID))
+}
+
+// ValidateSavedQueryIDs checks that every ID is a saved query, returning
+// ErrSavedQueryNotFound if one is not
+func (s *TaskService) ValidateSavedQueryIDs(ids []uint) error {
+	for _, id := range ids {
+		if _, err := s.repo.GetSavedQueryByID(id); err != nil {
+			return fmt.Errorf("%w: %d", ErrSavedQueryNotFound, id)
+		}
+	}
+	return nil
+}
+
+// PinSavedQueries marks the pinned saved queries and moves them to the front,
+// keeping the sidebar order within pinned and unpinned queries
+func PinSavedQueries(queries []*models.SavedQuery, pinnedIDs []uint) []*models.SavedQuery {
+	pinned := make([]*models.SavedQuery, 0, len(queries))
+	var rest []*models.SavedQuery
+	for _, query := range queries {
+		query.Pinned = slices.Contains(pinnedIDs, query.ID)
+		if query.Pinned {
+			pinned = append(pinned, query)
+		} else {
+			rest = append(rest, query)
+		}
+	}
+	return append(pinned, rest...)
+}
+
+// uniqueIDs drops repeated IDs, keeping the first of each
+func uniqueIDs(ids []uint) []uint {
+	var unique []uint
+	for _, id := range ids {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
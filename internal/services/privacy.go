@@ -0,0 +1,185 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+var (
+	ErrUnknownExportSection = errors.New("unknown export section")
+	ErrCannotEraseSelf      = errors.New("cannot erase your own user account")
+)
+
+// UserDataSections are the parts of a user data export, each written to the
+// archive as <section>.json
+var UserDataSections = []string{
+	"profile",           // the account itself
+	"sessions",          // sign-ins, including ended ones
+	"api_keys",          // key names, prefixes and permissions, never the keys
+	"login_attempts",    // sign-in attempts made with the username
+	"mutes",             // notification mutes
+	"out_of_office",     // vacation and holiday ranges
+	"tasks_assigned",    // tasks assigned to the user
+	"tasks_created",     // tasks opened by emails from the user's address
+	"time_entries",      // time logged by the user or on the tasks assigned to them
+	"comments",          // comments from the user's address and notes they wrote
+	"emails",            // emails from, to or copied to the user's address
+	"subscriptions",     // tasks the user's address is subscribed to
+	"scheduled_actions", // pending actions the user scheduled
+	"contact",           // the contact record for the user's address
+}
+
+// PrivacyService exports and erases the data attributable to a user, for
+// data subject requests
+type PrivacyService struct {
+	repo *repository.PrivacyRepository
+}
+
+// NewPrivacyService creates a new privacy service
+func NewPrivacyService(repo *repository.PrivacyRepository) *PrivacyService {
+	return &PrivacyService{repo: repo}
+}
+
+// ExportUserData writes a ZIP archive of a user's data to w. sections limits
+// the export to some of UserDataSections; empty exports them all. Tasks carry
+// no author, so tasks are attributed by assignee and by the email that opened them.
+func (s *PrivacyService) ExportUserData(userID uint, sections []string, w io.Writer) error {
+	if len(sections) == 0 {
+		sections = UserDataSections
+	}
+	for _, section := range sections {
+		if !slices.Contains(UserDataSections, section) {
+			return fmt.Errorf("%w: %s", ErrUnknownExportSection, section)
+		}
+	}
+
+	user, err := s.repo.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	// Collect everything before writing, so a failure leaves no partial archive
+	files := make(map[string]interface{}, len(sections))
+	for _, section := range sections {
+		data, err := s.collect(user, section)
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", section, err)
+		}
+		files[section] = data
+	}
+
+	archive := zip.NewWriter(w)
+	manifest := map[string]interface{}{
+		"user_id":     user.ID,
+		"username":    user.Username,
+		"email":       user.Email,
+		"exported_at": time.Now().UTC(),
+		"sections":    sections,
+	}
+	if err := writeJSONFile(archive, "manifest.json", manifest); err != nil {
+		return err
+	}
+	for _, section := range sections {
+		if err := writeJSONFile(archive, section+".json", files[section]); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// collect reads one export section for a user
+func (s *PrivacyService) collect(user *models.User, section string) (interface{}, error) {
+	switch section {
+	case "profile":
+		profile := *user
+		profile.HashedPassword = ""
+		profile.TOTPSecret = ""
+		return profile, nil
+	case "sessions":
+		return s.repo.GetSessions(user.ID)
+	case "api_keys":
+		return s.repo.GetAPIKeys(user.ID)
+	case "login_attempts":
+		return s.repo.GetLoginAttempts(user.Username)
+	case "mutes":
+		return s.repo.GetMutes(user.ID)
+	case "out_of_office":
+		return s.repo.GetOutOfOffice(user.ID)
+	case "tasks_assigned":
+		return s.repo.GetAssignedTasks(user.Username)
+	case "tasks_created":
+		return s.repo.GetTasksCreatedByEmail(user.Email)
+	case "time_entries":
+		tasks, err := s.repo.GetAssignedTasks(user.Username)
+		if err != nil {
+			return nil, err
+		}
+		taskIDs := make([]uint, len(tasks))
+		for i, task := range tasks {
+			taskIDs[i] = task.ID
+		}
+		return s.repo.GetTimeEntries(taskIDs, user.Username)
+	case "comments":
+		return s.repo.GetComments(user.Email, user.Username)
+	case "emails":
+		return s.repo.GetEmails(user.Email)
+	case "subscriptions":
+		return s.repo.GetSubscriptions(user.Email)
+	case "scheduled_actions":
+		return s.repo.GetScheduledActions(user.Username)
+	case "contact":
+		return s.repo.GetContact(user.Email)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownExportSection, section)
+}
+
+// writeJSONFile adds an indented JSON file to a ZIP archive
+func writeJSONFile(archive *zip.Writer, name string, data interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to export: %w", name, err)
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// ErasedUsername is the placeholder that replaces an erased user's username
+func ErasedUsername(userID uint) string {
+	return fmt.Sprintf("erased-user-%d", userID)
+}
+
+// ErasedEmail is the placeholder that replaces an erased user's email address
+func ErasedEmail(userID uint) string {
+	return fmt.Sprintf("erased-user-%d@erased.invalid", userID)
+}
+
+// EraseUser anonymizes a departing user on behalf of the admin actorID. The
+// account is deleted and its personal data removed, while the tasks, comments,
+// time entries and emails it was part of are kept, naming a placeholder
+// instead, so task history stays intact.
+func (s *PrivacyService) EraseUser(userID, actorID uint) error {
+	if userID == actorID {
+		return ErrCannotEraseSelf
+	}
+
+	user, err := s.repo.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	return s.repo.EraseUser(user, ErasedUsername(user.ID), ErasedEmail(user.ID))
+}
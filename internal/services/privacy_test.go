@@ -0,0 +1,157 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestPrivacyService(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&models.User{}, &models.Session{}, &models.APIKey{}, &models.LoginAttempt{}); err != nil {
+		t.Fatalf("Failed to migrate auth tables: %v", err)
+	}
+	taskRepo := repository.NewTaskRepository(db)
+	service := NewPrivacyService(repository.NewPrivacyRepository(db))
+
+	user := &models.User{Username: "alice", Email: "Alice@example.com", HashedPassword: "hash", IsActive: true}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	db.Create(&models.Session{UserID: user.ID, Token: "token", ExpiresAt: time.Now().Add(time.Hour)})
+	db.Create(&models.LoginAttempt{Username: "alice", IPAddress: "192.0.2.1", Success: true})
+
+	assigned := &models.Task{Name: "Assigned", Assignee: "alice"}
+	taskRepo.Create(assigned)
+	db.Create(&models.TimeEntry{TaskID: assigned.ID, Duration: 30})
+
+	emailed := &models.Task{Name: "Printer broken", EmailMessageID: "<1@example.com>"}
+	taskRepo.Create(emailed)
+	db.Create(&models.EmailMessage{MessageID: "<1@example.com>", TaskID: &emailed.ID, Subject: "Printer broken", From: "alice@example.com", ReceivedAt: time.Now()})
+	db.Create(&models.EmailMessage{MessageID: "<2@example.com>", TaskID: &emailed.ID, Direction: models.EmailDirectionOutbound, Subject: "Re: Printer broken",
+		From: "jats@example.com", To: []string{"alice@example.com"}, ReceivedAt: time.Now()})
+	db.Create(&models.Comment{TaskID: emailed.ID, Content: "Still broken", FromEmail: "alice@example.com"})
+	db.Create(&models.TaskSubscriber{TaskID: emailed.ID, Email: "alice@example.com"})
+	db.Create(&models.Contact{Name: "Alice", Email: "alice@example.com"})
+
+	unrelated := &models.Task{Name: "Unrelated"}
+	taskRepo.Create(unrelated)
+	// Notes and time the user logged on tasks not assigned to them
+	db.Create(&models.Comment{TaskID: unrelated.ID, Content: "Checked the cabling", CreatedBy: "alice", IsPrivate: true})
+	db.Create(&models.TimeEntry{TaskID: unrelated.ID, Duration: 15, CreatedBy: "alice"})
+	db.Create(&models.TimeEntry{TaskID: unrelated.ID, Duration: 45, CreatedBy: "bob"})
+	db.Create(&models.EmailMessage{MessageID: "<3@example.com>", Subject: "Other", From: "bob@example.com", ReceivedAt: time.Now()})
+
+	var archive bytes.Buffer
+	if err := service.ExportUserData(user.ID, nil, &archive); err != nil {
+		t.Fatalf("ExportUserData failed: %v", err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatalf("Export is not a ZIP: %v", err)
+	}
+	counts := make(map[string]int)
+	exported := make(map[string][]uint)
+	for _, file := range reader.File {
+		f, _ := file.Open()
+		var rows []struct {
+			ID uint `json:"id"`
+		}
+		if json.NewDecoder(f).Decode(&rows) == nil {
+			counts[file.Name] = len(rows)
+			for _, row := range rows {
+				exported[file.Name] = append(exported[file.Name], row.ID)
+			}
+		}
+		f.Close()
+	}
+	if len(reader.File) != len(UserDataSections)+1 {
+		t.Errorf("Expected a manifest and %d sections, got %d files", len(UserDataSections), len(reader.File))
+	}
+	for file, want := range map[string]int{
+		"sessions.json": 1, "login_attempts.json": 1, "tasks_assigned.json": 1, "tasks_created.json": 1,
+		"time_entries.json": 2, "comments.json": 2, "emails.json": 2, "subscriptions.json": 1,
+	} {
+		if counts[file] != want {
+			t.Errorf("Expected %d rows in %s, got %d", want, file, counts[file])
+		}
+	}
+
+	archive.Reset()
+	if err := service.ExportUserData(user.ID, []string{"passwords"}, &archive); !errors.Is(err, ErrUnknownExportSection) {
+		t.Errorf("Expected ErrUnknownExportSection, got %v", err)
+	}
+
+	if err := service.EraseUser(user.ID, user.ID); !errors.Is(err, ErrCannotEraseSelf) {
+		t.Errorf("Expected ErrCannotEraseSelf, got %v", err)
+	}
+	if err := service.EraseUser(user.ID, 99); err != nil {
+		t.Fatalf("EraseUser failed: %v", err)
+	}
+
+	var erased models.User
+	db.Unscoped().First(&erased, user.ID)
+	if erased.Username != ErasedUsername(user.ID) || erased.Email != ErasedEmail(user.ID) || erased.IsActive || !erased.DeletedAt.Valid {
+		t.Errorf("Expected the account to be anonymized and deleted, got %+v", erased)
+	}
+
+	var sessions, attempts, subscribers int64
+	db.Unscoped().Model(&models.Session{}).Count(&sessions)
+	db.Model(&models.LoginAttempt{}).Count(&attempts)
+	db.Model(&models.TaskSubscriber{}).Count(&subscribers)
+	if sessions+attempts+subscribers != 0 {
+		t.Errorf("Expected sessions, login attempts and subscriptions removed, got %d, %d and %d", sessions, attempts, subscribers)
+	}
+
+	// Task history is kept, naming the placeholder
+	task, _ := taskRepo.GetByID(assigned.ID)
+	if task.Assignee != ErasedUsername(user.ID) || task.LoggedMinutes != 30 {
+		t.Errorf("Expected the assigned task kept under the placeholder, got %q with %d minutes", task.Assignee, task.LoggedMinutes)
+	}
+	task, _ = taskRepo.GetByID(emailed.ID)
+	if len(task.Comments) != 1 || task.Comments[0].FromEmail != ErasedEmail(user.ID) {
+		t.Errorf("Expected the comment kept under the placeholder, got %+v", task.Comments)
+	}
+	var emails []models.EmailMessage
+	db.Order("id").Find(&emails)
+	if emails[0].From != ErasedEmail(user.ID) || emails[1].To[0] != ErasedEmail(user.ID) || emails[2].From != "bob@example.com" {
+		t.Errorf("Expected only alice's address replaced in emails, got %s, %v and %s", emails[0].From, emails[1].To, emails[2].From)
+	}
+
+	// Every row erasure anonymized was in the export
+	var commentIDs, entryIDs []uint
+	db.Model(&models.Comment{}).Where("from_email = ? OR created_by = ?", ErasedEmail(user.ID), ErasedUsername(user.ID)).Order("id").Pluck("id", &commentIDs)
+	db.Model(&models.TimeEntry{}).Where("created_by = ?", ErasedUsername(user.ID)).Pluck("id", &entryIDs)
+	if len(commentIDs) != 2 || !sameIDs(commentIDs, exported["comments.json"]) {
+		t.Errorf("Expected the anonymized comments %v to be the exported %v", commentIDs, exported["comments.json"])
+	}
+	for _, id := range entryIDs {
+		if !slices.Contains(exported["time_entries.json"], id) {
+			t.Errorf("Expected the anonymized time entry %d in the export %v", id, exported["time_entries.json"])
+		}
+	}
+	if len(entryIDs) != 1 {
+		t.Errorf("Expected one time entry anonymized, got %v", entryIDs)
+	}
+
+	var contact models.Contact
+	db.First(&contact)
+	if contact.Email != ErasedEmail(user.ID) || contact.Name != "" {
+		t.Errorf("Expected the contact anonymized, got %+v", contact)
+	}
+}
+
+// sameIDs reports whether two ID lists hold the same IDs in any order
+func sameIDs(a, b []uint) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var (
+	ErrProjectNotFound      = errors.New("project not found")
+	ErrProjectNameRequired  = errors.New("project name is required")
+	ErrProjectNameTaken     = errors.New("a project with this name already exists")
+	ErrDefaultProjectDelete = errors.New("the default project cannot be deleted")
+)
+
+// CreateProject validates and stores a new project
+func (s *TaskService) CreateProject(project *models.Project) error {
+	if err := s.validateProject(project); err != nil {
+		return err
+	}
+	// There is only ever one default project, see DefaultProject
+	project.IsDefault = false
+	return s.repo.CreateProject(project)
+}
+
+// GetProjects returns all projects, the default one first, with their open
+// task counts
+func (s *TaskService) GetProjects() ([]*models.Project, error) {
+	if _, err := s.DefaultProject(); err != nil {
+		return nil, err
+	}
+	return s.repo.GetProjects()
+}
+
+// GetProject returns a project or ErrProjectNotFound
+func (s *TaskService) GetProject(id uint) (*models.Project, error) {
+	project, err := s.repo.GetProjectByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, ErrProjectNotFound
+	}
+	return project, nil
+}
+
+// UpdateProject validates and saves changes to a project's name and description
+func (s *TaskService) UpdateProject(project *models.Project) error {
+	if err := s.validateProject(project); err != nil {
+		return err
+	}
+	return s.repo.UpdateProject(project)
+}
+
+// DeleteProject deletes a project, moving its tasks and saved queries to the
+// default project, which cannot itself be deleted
+func (s *TaskService) DeleteProject(id uint) error {
+	project, err := s.GetProject(id)
+	if err != nil {
+		return err
+	}
+	if project.IsDefault {
+		return ErrDefaultProjectDelete
+	}
+	defaultProject, err := s.DefaultProject()
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteProject(id, defaultProject.ID)
+}
+
+// DefaultProject returns the project tasks go to unless given another,
+// creating it if needed. Tasks from before projects existed are moved into it
+// at startup, by TaskRepository.EnsureDefaultProject.
+func (s *TaskService) DefaultProject() (*models.Project, error) {
+	project, err := s.repo.GetDefaultProject()
+	if err != nil || project != nil {
+		return project, err
+	}
+	project = &models.Project{Name: models.DefaultProjectName, IsDefault: true}
+	if err := s.repo.CreateProject(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// CheckProject returns ErrProjectNotFound unless a project exists; a nil ID,
+// meaning the default project, always does
+func (s *TaskService) CheckProject(id *uint) error {
+	if id == nil {
+		return nil
+	}
+	_, err := s.GetProject(*id)
+	return err
+}
+
+// assignDefaultProject puts a task without a project into the default project
+func (s *TaskService) assignDefaultProject(task *models.Task) error {
+	if task.ProjectID != nil {
+		return nil
+	}
+	project, err := s.DefaultProject()
+	if err != nil {
+		return err
+	}
+	task.ProjectID = &project.ID
+	return nil
+}
+
+// validateProject trims a project's name and checks it is set and not used by
+// another project
+func (s *TaskService) validateProject(project *models.Project) error {
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" {
+		return ErrProjectNameRequired
+	}
+	existing, err := s.repo.GetProjectByName(project.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != project.ID {
+		return ErrProjectNameTaken
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestEnsureDefaultProjectAdoptsExistingTasks(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+
+	// A task from before projects existed
+	legacy := &models.Task{Name: "Renew certificates", Status: models.TaskStatusOpen}
+	if err := repo.Create(legacy); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	// Reading the default project leaves the backfill to startup
+	created, err := NewTaskService(repo, nil).DefaultProject()
+	if err != nil {
+		t.Fatalf("Failed to create default project: %v", err)
+	}
+	if task, _ := repo.GetByID(legacy.ID); task.ProjectID != nil {
+		t.Errorf("Expected DefaultProject to leave existing tasks alone, got project %d", *task.ProjectID)
+	}
+
+	project, err := repo.EnsureDefaultProject()
+	if err != nil {
+		t.Fatalf("Failed to create default project: %v", err)
+	}
+	if project.ID != created.ID {
+		t.Errorf("Expected the project DefaultProject created, got %+v", project)
+	}
+	if !project.IsDefault || project.Name != models.DefaultProjectName {
+		t.Errorf("Expected the default project, got %+v", project)
+	}
+	task, _ := repo.GetByID(legacy.ID)
+	if task.ProjectID == nil || *task.ProjectID != project.ID {
+		t.Errorf("Expected the task in the default project, got %v", task.ProjectID)
+	}
+
+	// Running it again keeps the same project
+	again, err := repo.EnsureDefaultProject()
+	if err != nil || again.ID != project.ID {
+		t.Errorf("Expected the existing default project, got %+v, %v", again, err)
+	}
+}
+
+func TestTaskService_Projects(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	task, err := service.CreateTask("Patch web servers")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	defaultProject, err := service.DefaultProject()
+	if err != nil {
+		t.Fatalf("Failed to get default project: %v", err)
+	}
+	if task.ProjectID == nil || *task.ProjectID != defaultProject.ID {
+		t.Fatalf("Expected new tasks in the default project, got %v", task.ProjectID)
+	}
+
+	acme := &models.Project{Name: " Acme "}
+	if err := service.CreateProject(acme); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	if acme.Name != "Acme" || acme.IsDefault {
+		t.Errorf("Expected a trimmed, non-default project, got %+v", acme)
+	}
+	if err := service.CreateProject(&models.Project{Name: "acme"}); err != ErrProjectNameTaken {
+		t.Errorf("Expected ErrProjectNameTaken, got %v", err)
+	}
+	if err := service.CreateProject(&models.Project{Name: " "}); err != ErrProjectNameRequired {
+		t.Errorf("Expected ErrProjectNameRequired, got %v", err)
+	}
+
+	task.ProjectID = &acme.ID
+	if err := service.UpdateTask(task); err != nil {
+		t.Fatalf("Failed to move task: %v", err)
+	}
+	query, err := service.CreateSavedQuery(&models.SavedQuery{Name: "Acme work", ProjectID: &acme.ID})
+	if err != nil {
+		t.Fatalf("Failed to create saved query: %v", err)
+	}
+	global, _ := service.CreateSavedQuery(&models.SavedQuery{Name: "Everything"})
+	missing := uint(999)
+	if _, err := service.CreateSavedQuery(&models.SavedQuery{Name: "Nowhere", ProjectID: &missing}); err != ErrProjectNotFound {
+		t.Errorf("Expected ErrProjectNotFound, got %v", err)
+	}
+
+	other, _ := service.CreateTask("Order toner")
+	matches, _ := service.GetTasksBySavedQuery(query)
+	if len(matches) != 1 || matches[0].ID != task.ID {
+		t.Errorf("Expected only the Acme task to match, got %d tasks", len(matches))
+	}
+	if matches, _ := service.GetTasksBySavedQuery(global); len(matches) != 2 {
+		t.Errorf("Expected a query without a project to match every task, got %d", len(matches))
+	}
+
+	// The SQL counts agree with the in-memory match
+	if err := service.CountBySavedQueries([]*models.SavedQuery{query}); err != nil {
+		t.Fatalf("Failed to count: %v", err)
+	}
+	if query.OpenCount == nil || *query.OpenCount != 1 {
+		t.Errorf("Expected 1 open task counted for the Acme query, got %v", query.OpenCount)
+	}
+
+	queries, _ := service.GetProjectSavedQueries(defaultProject.ID)
+	if len(queries) != 1 || queries[0].ID != global.ID {
+		t.Errorf("Expected only the query for every project, got %d queries", len(queries))
+	}
+
+	projects, _ := service.GetProjects()
+	if len(projects) != 2 || !projects[0].IsDefault || projects[0].OpenTasks != 1 || projects[1].OpenTasks != 1 {
+		t.Errorf("Expected the default project first with one open task each, got %+v", projects)
+	}
+
+	if err := service.DeleteProject(defaultProject.ID); err != ErrDefaultProjectDelete {
+		t.Errorf("Expected ErrDefaultProjectDelete, got %v", err)
+	}
+	if err := service.DeleteProject(acme.ID); err != nil {
+		t.Fatalf("Failed to delete project: %v", err)
+	}
+	moved, _ := service.GetTask(task.ID)
+	if moved.ProjectID == nil || *moved.ProjectID != defaultProject.ID {
+		t.Errorf("Expected the task moved to the default project, got %v", moved.ProjectID)
+	}
+	movedQuery, _ := service.GetSavedQueryByID(query.ID)
+	if movedQuery.ProjectID == nil || *movedQuery.ProjectID != defaultProject.ID {
+		t.Errorf("Expected the saved query moved to the default project, got %v", movedQuery.ProjectID)
+	}
+	if unchanged, _ := service.GetTask(other.ID); *unchanged.ProjectID != defaultProject.ID {
+		t.Errorf("Expected the other task to stay in the default project")
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/quickadd"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+// CreateQuickTask creates a task from a parsed quick-add line, logging time and
+// resolving it when the line asked for it
+func (s *TaskService) CreateQuickTask(entry *quickadd.Task) (*models.Task, error) {
+	createdAt, err := utils.ParseDate(entry.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	dueDate, err := ParseDueDate(entry.DueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.CreateTaskWithDate(entry.Name, createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Priority != "" || len(entry.Tags) > 0 || dueDate != nil || entry.Context != "" || entry.Size != "" {
+		task.Priority = entry.Priority
+		task.Tags = entry.Tags
+		task.DueDate = dueDate
+		task.Context = entry.Context
+		task.Size = entry.Size
+		if err := s.UpdateTask(task); err != nil {
+			return nil, err
+		}
+	}
+
+	if entry.Duration > 0 {
+		timeEntry := &models.TimeEntry{
+			Duration:    entry.Duration,
+			Description: "Time logged during task creation",
+		}
+		if err := s.AddTimeEntryWithDate(task.ID, timeEntry, createdAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if entry.Complete {
+		// Reload, as logging time may have moved the task to in-progress
+		task, err = s.GetTask(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		task.Status = models.TaskStatusResolved
+		if err := s.UpdateTask(task); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetTask(task.ID)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"gorm.io/gorm"
+)
+
+// ReceiptPixelPath returns the path of the tracking image for a token
+func ReceiptPixelPath(token string) string {
+	return "/r/" + token
+}
+
+// SetTrackingURL sets the public URL of jatsd, so email updates embed an
+// image marking them as seen when opened; empty leaves the image out
+func (s *TaskService) SetTrackingURL(baseURL string) {
+	s.trackingURL = strings.TrimRight(baseURL, "/")
+}
+
+// RecordEmailReceipt records a delivery or read event for the public comment
+// sent with the given tracking token or, when there is none, the given
+// Message-ID. It reports whether the email was one of ours; events for other
+// emails are ignored.
+func (s *TaskService) RecordEmailReceipt(token, messageID string, receipt *models.EmailReceipt) (bool, error) {
+	var comment *models.Comment
+	var err error
+	if token != "" {
+		comment, err = s.repo.GetCommentByTrackingToken(token)
+	}
+	if comment == nil && messageID != "" {
+		comment, err = s.repo.GetCommentByEmailMessageID(messageID)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	receipt.CommentID = comment.ID
+	if receipt.OccurredAt.IsZero() {
+		receipt.OccurredAt = time.Now()
+	}
+	return true, s.repo.CreateEmailReceipt(receipt)
+}
+
+// RecordEmailOpen marks the comment sent with a tracking token as seen, when
+// the tracking image in its email is loaded
+func (s *TaskService) RecordEmailOpen(token string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	return s.RecordEmailReceipt(token, "", &models.EmailReceipt{
+		Event:  models.EmailReceiptSeen,
+		Source: models.EmailReceiptSourcePixel,
+	})
+}
+
+// fillReceipts sets how far each of a task's emailed comments got
+func (s *TaskService) fillReceipts(task *models.Task) error {
+	receipts, err := s.repo.GetTaskEmailReceipts(task.ID)
+	if err != nil {
+		return err
+	}
+
+	// A read beats a delivery, which beats a failure to another recipient
+	rank := map[string]int{models.EmailReceiptFailed: 1, models.EmailReceiptDelivered: 2, models.EmailReceiptSeen: 3}
+	best := make(map[uint]string)
+	for _, receipt := range receipts {
+		if rank[receipt.Event] > rank[best[receipt.CommentID]] {
+			best[receipt.CommentID] = receipt.Event
+		}
+	}
+	for i := range task.Comments {
+		task.Comments[i].Receipt = best[task.Comments[i].ID]
+	}
+	return nil
+}
+
+// newReceiptToken returns a random token for matching receipts to an email
+func newReceiptToken() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// addReceiptPixel gives a plain text email an HTML alternative carrying the
+// tracking image, when a tracking URL is set
+func (s *TaskService) addReceiptPixel(email *RenderedEmail) {
+	if s.trackingURL == "" || email.ReceiptToken == "" || email.HTML != "" {
+		return
+	}
+	email.HTML = fmt.Sprintf(`<div style="white-space: pre-wrap">%s</div><img src="%s" width="1" height="1" alt="">`,
+		html.EscapeString(email.Text), html.EscapeString(s.trackingURL+ReceiptPixelPath(email.ReceiptToken)))
+}
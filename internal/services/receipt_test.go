@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+const deliveryReport = "From: Mail Delivery System <mailer-daemon@mx.customer.test>\r\n" +
+	"To: support@jats.test\r\n" +
+	"Subject: Delivery Status Notification\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=delivery-status; boundary=r1\r\n" +
+	"\r\n" +
+	"--r1\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"Your message was delivered.\r\n" +
+	"--r1\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mx.customer.test\r\n" +
+	"Original-Envelope-Id: %s\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; customer@customer.test\r\n" +
+	"Action: delivered\r\n" +
+	"Status: 2.0.0\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; gone@customer.test\r\n" +
+	"Action: delayed\r\n" +
+	"Status: 4.0.0\r\n" +
+	"--r1\r\n" +
+	"Content-Type: text/rfc822-headers\r\n" +
+	"\r\n" +
+	"Message-Id: <update-1@jats.test>\r\n" +
+	"Subject: Re: Printer is broken\r\n" +
+	"--r1--\r\n"
+
+const readReceipt = "From: customer@customer.test\r\n" +
+	"Subject: Read: Re: Printer is broken\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=disposition-notification; boundary=r2\r\n" +
+	"\r\n" +
+	"--r2\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"Your message was displayed.\r\n" +
+	"--r2\r\n" +
+	"Content-Type: message/disposition-notification\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; customer@customer.test\r\n" +
+	"Original-Message-ID: <update-1@jats.test>\r\n" +
+	"Disposition: manual-action/MDN-sent-manually; displayed\r\n" +
+	"--r2--\r\n"
+
+func TestParseReceiptReport(t *testing.T) {
+	report, ok := parseReceiptReport([]byte(fmt.Sprintf(deliveryReport, "abc123")))
+	if !ok {
+		t.Fatal("Expected a delivery report")
+	}
+	if report.Token != "abc123" || report.MessageID != "<update-1@jats.test>" {
+		t.Errorf("Expected the envelope ID and original Message-ID, got %q %q", report.Token, report.MessageID)
+	}
+	// The delayed recipient has no final result yet
+	if len(report.Receipts) != 1 || report.Receipts[0].Event != models.EmailReceiptDelivered || report.Receipts[0].Recipient != "customer@customer.test" {
+		t.Errorf("Expected one delivery to the customer, got %+v", report.Receipts)
+	}
+
+	report, ok = parseReceiptReport([]byte(readReceipt))
+	if !ok {
+		t.Fatal("Expected a read receipt")
+	}
+	if report.MessageID != "<update-1@jats.test>" || len(report.Receipts) != 1 || report.Receipts[0].Event != models.EmailReceiptSeen || report.Receipts[0].Source != models.EmailReceiptSourceMDN {
+		t.Errorf("Expected the update seen, got %q %+v", report.MessageID, report.Receipts)
+	}
+
+	if _, ok := parseReceiptReport([]byte(fmt.Sprintf(simulatedMessage, ""))); ok {
+		t.Error("Expected an ordinary email not to be a report")
+	}
+}
+
+func TestTaskService_EmailReceipts(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+	sender := &fakeEmailSender{}
+	service.SetEmailSender(sender)
+	service.SetTrackingURL("https://jats.test/")
+
+	task, err := service.CreateTaskFromEmail("Printer is broken", "<original@customer.test>")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if err := repo.AddSubscriber(&models.TaskSubscriber{TaskID: task.ID, Email: "customer@customer.test"}); err != nil {
+		t.Fatalf("Failed to add subscriber: %v", err)
+	}
+	comment, err := service.SendEmailUpdate(task.ID, &EmailUpdate{Message: "We replaced the toner."})
+	if err != nil {
+		t.Fatalf("Failed to send email update: %v", err)
+	}
+
+	token := sender.email.ReceiptToken
+	if token == "" || comment.TrackingToken != token {
+		t.Fatalf("Expected the update sent and saved with a tracking token, got %q %q", token, comment.TrackingToken)
+	}
+	if !strings.Contains(sender.email.HTML, `src="https://jats.test/r/`+token+`"`) {
+		t.Errorf("Expected the tracking image in the HTML body, got %q", sender.email.HTML)
+	}
+
+	// Reports for other emails are ignored
+	if ours, err := service.RecordEmailReceipt("unknown", "<other@jats.test>", &models.EmailReceipt{Event: models.EmailReceiptDelivered}); ours || err != nil {
+		t.Errorf("Expected a receipt for another email to be ignored, got %v %v", ours, err)
+	}
+
+	if ours, err := service.RecordEmailReceipt(token, "", &models.EmailReceipt{Event: models.EmailReceiptDelivered, Source: models.EmailReceiptSourceDSN}); !ours || err != nil {
+		t.Fatalf("Failed to record delivery: %v %v", ours, err)
+	}
+	got, _ := service.GetTask(task.ID)
+	if len(got.Comments) != 1 || got.Comments[0].Receipt != models.EmailReceiptDelivered {
+		t.Errorf("Expected the comment delivered, got %+v", got.Comments)
+	}
+
+	if ours, err := service.RecordEmailOpen(token); !ours || err != nil {
+		t.Fatalf("Failed to record open: %v %v", ours, err)
+	}
+	got, _ = service.GetTask(task.ID)
+	if got.Comments[0].Receipt != models.EmailReceiptSeen {
+		t.Errorf("Expected the comment seen, got %q", got.Comments[0].Receipt)
+	}
+}
+
+func TestEmailService_SimulateReceipt(t *testing.T) {
+	taskService := &mockTaskService{}
+	// Reports come from mail servers, not JATS users
+	service := NewEmailService(taskService, newMockTaskRepository(), noUsersAuthRepository{}, nil, &config.Config{})
+
+	sim, err := service.Simulate([]byte(readReceipt))
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if sim.Outcome != EmailOutcomeReceipt {
+		t.Errorf("Expected a receipt, got %q", sim.Outcome)
+	}
+	if len(taskService.recordedReceipts) != 0 || len(taskService.createdTasks) != 0 {
+		t.Error("Expected nothing to be recorded on a dry run")
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/repository"
+	"github.com/soarinferret/jats/internal/tagexpr"
 )
 
 type ReportService struct {
@@ -31,6 +32,9 @@ type TimeBreakdownReport struct {
 type DailyTimeBreakdown struct {
 	Date       string                 `json:"date"`
 	TotalTime  int                    `json:"total_time"` // minutes
+	// Of the total, the minutes billed to clients and the rest
+	BillableTime    int               `json:"billable_time"`
+	NonBillableTime int               `json:"non_billable_time"`
 	QueryTimes []QueryTimeBreakdown   `json:"query_times"`
 	OtherTime  int                    `json:"other_time"`  // minutes - time not matching any query
 	OtherTags  []string               `json:"other_tags"`  // tags from tasks not matching any query
@@ -47,6 +51,8 @@ type QueryTimeBreakdown struct {
 // QueryTotals represents total time and percentages for each query
 type QueryTotals struct {
 	TotalTime   int                 `json:"total_time"` // minutes
+	BillableTime    int             `json:"billable_time"`
+	NonBillableTime int             `json:"non_billable_time"`
 	QueryTotals []QueryTotal        `json:"query_totals"`
 	OtherTotal  OtherTotal          `json:"other_total"` // time not matching any query
 }
@@ -66,8 +72,9 @@ type QueryTotal struct {
 	Percentage float64 `json:"percentage"`
 }
 
-// GenerateTimeBreakdownReport generates a time breakdown report
-func (s *ReportService) GenerateTimeBreakdownReport(startDate, endDate time.Time, savedQueryIDs []uint, excludedTags []string) (*TimeBreakdownReport, error) {
+// GenerateTimeBreakdownReport generates a time breakdown report. A non-nil
+// billable only counts billable or non-billable time.
+func (s *ReportService) GenerateTimeBreakdownReport(startDate, endDate time.Time, savedQueryIDs []uint, excludedTags []string, billable *bool) (*TimeBreakdownReport, error) {
 	// Get all saved queries
 	queries, err := s.getSavedQueriesByIDs(savedQueryIDs)
 	if err != nil {
@@ -87,7 +94,7 @@ func (s *ReportService) GenerateTimeBreakdownReport(startDate, endDate time.Time
 	}
 
 	// Generate daily breakdown
-	dailyData := s.generateDailyBreakdown(startDate, endDate, allTasks, queries, excludedTags)
+	dailyData := s.generateDailyBreakdown(startDate, endDate, allTasks, queries, excludedTags, billable)
 
 	// Calculate totals
 	totals := s.calculateTotals(dailyData, queries)
@@ -115,7 +122,7 @@ func (s *ReportService) getSavedQueriesByIDs(ids []uint) ([]*models.SavedQuery,
 }
 
 // generateDailyBreakdown generates daily time breakdown
-func (s *ReportService) generateDailyBreakdown(startDate, endDate time.Time, tasks []*models.Task, queries []*models.SavedQuery, excludedTags []string) []DailyTimeBreakdown {
+func (s *ReportService) generateDailyBreakdown(startDate, endDate time.Time, tasks []*models.Task, queries []*models.SavedQuery, excludedTags []string, billable *bool) []DailyTimeBreakdown {
 	dailyMap := make(map[string]*DailyTimeBreakdown)
 
 	// Normalize start and end dates to beginning and end of day to handle timezone issues
@@ -152,6 +159,10 @@ func (s *ReportService) generateDailyBreakdown(startDate, endDate time.Time, tas
 
 		// Process each time entry
 		for _, timeEntry := range task.TimeEntries {
+			if billable != nil && timeEntry.IsBillable() != *billable {
+				continue
+			}
+
 			// Normalize entry date for comparison (convert to UTC for consistency)
 			entryDateUTC := timeEntry.CreatedAt.UTC()
 
@@ -169,6 +180,11 @@ func (s *ReportService) generateDailyBreakdown(startDate, endDate time.Time, tas
 
 			// Add to total time
 			daily.TotalTime += timeEntry.Duration
+			if timeEntry.IsBillable() {
+				daily.BillableTime += timeEntry.Duration
+			} else {
+				daily.NonBillableTime += timeEntry.Duration
+			}
 
 			// Check if task matches any saved query
 			matchedAnyQuery := false
@@ -238,6 +254,8 @@ func (s *ReportService) calculateTotals(dailyData []DailyTimeBreakdown, queries
 	// Sum up daily data
 	for _, daily := range dailyData {
 		totals.TotalTime += daily.TotalTime
+		totals.BillableTime += daily.BillableTime
+		totals.NonBillableTime += daily.NonBillableTime
 		for i, queryTime := range daily.QueryTimes {
 			totals.QueryTotals[i].TotalTime += queryTime.Time
 		}
@@ -266,35 +284,7 @@ func (s *ReportService) calculateTotals(dailyData []DailyTimeBreakdown, queries
 
 // taskMatchesSavedQuery checks if a task matches a saved query's criteria
 func (s *ReportService) taskMatchesSavedQuery(task *models.Task, query *models.SavedQuery) bool {
-	// Check included tags
-	if len(query.IncludedTags) > 0 {
-		hasIncludedTag := false
-		for _, includedTag := range query.IncludedTags {
-			for _, taskTag := range task.Tags {
-				if taskTag == includedTag {
-					hasIncludedTag = true
-					break
-				}
-			}
-			if hasIncludedTag {
-				break
-			}
-		}
-		if !hasIncludedTag {
-			return false
-		}
-	}
-
-	// Check excluded tags
-	for _, excludedTag := range query.ExcludedTags {
-		for _, taskTag := range task.Tags {
-			if taskTag == excludedTag {
-				return false
-			}
-		}
-	}
-
-	return true
+	return MatchesSavedQuery(task, query)
 }
 
 // hasAnyTag checks if any tag from the task matches any excluded tag
@@ -326,7 +316,7 @@ func (s *ReportService) deduplicateTags(tags []string) []string {
 func (s *ReportService) excludeQueryFilterTags(taskTags []string, query *models.SavedQuery) []string {
 	var result []string
 
-	// Build a set of tags to exclude (query's included and excluded tags)
+	// Build a set of tags to exclude (every tag the query filters on)
 	excludeSet := make(map[string]bool)
 	for _, tag := range query.IncludedTags {
 		excludeSet[tag] = true
@@ -334,6 +324,11 @@ func (s *ReportService) excludeQueryFilterTags(taskTags []string, query *models.
 	for _, tag := range query.ExcludedTags {
 		excludeSet[tag] = true
 	}
+	if expr, err := tagexpr.Parse(query.Expression); err == nil {
+		for _, tag := range expr.Tags() {
+			excludeSet[tag] = true
+		}
+	}
 
 	// Only include tags that are not in the exclude set
 	for _, tag := range taskTags {
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+// RetentionPolicy is the effective retention configuration. Zero day values
+// mean the data is kept forever (or, for sessions, removed once expired).
+type RetentionPolicy struct {
+	LoginAttemptsHours  int `json:"login_attempts_hours"`
+	SessionDays         int `json:"session_days"`
+	ActivityDays        int `json:"activity_days"`
+	ArchiveResolvedDays int `json:"archive_resolved_days"`
+}
+
+// RetentionRun records what one janitor pass removed
+type RetentionRun struct {
+	StartedAt            time.Time `json:"started_at"`
+	FinishedAt           time.Time `json:"finished_at"`
+	LoginAttemptsDeleted int64     `json:"login_attempts_deleted"`
+	SessionsDeleted      int64     `json:"sessions_deleted"`
+	StatusChangesDeleted int64     `json:"status_changes_deleted"`
+	TasksArchived        int64     `json:"tasks_archived"`
+	Errors               []string  `json:"errors,omitempty"`
+}
+
+// RetentionService is the background janitor that deletes old login attempts,
+// sessions and status history and archives long-resolved tasks
+type RetentionService struct {
+	taskRepo *repository.TaskRepository
+	authRepo *repository.AuthRepository
+	policy   RetentionPolicy
+
+	mu      sync.Mutex
+	lastRun *RetentionRun
+}
+
+// NewRetentionService creates the janitor; register its Run method with a JobRunner
+func NewRetentionService(taskRepo *repository.TaskRepository, authRepo *repository.AuthRepository, cfg *config.RetentionConfig) *RetentionService {
+	policy := RetentionPolicy{LoginAttemptsHours: 24}
+	if cfg != nil {
+		policy = RetentionPolicy{
+			LoginAttemptsHours:  cfg.LoginAttemptsHours,
+			SessionDays:         max(cfg.SessionDays, 0),
+			ActivityDays:        max(cfg.ActivityDays, 0),
+			ArchiveResolvedDays: max(cfg.ArchiveResolvedDays, 0),
+		}
+	}
+	// Rate limiting counts recent failed attempts, so they must outlive its window
+	if policy.LoginAttemptsHours < 1 {
+		policy.LoginAttemptsHours = 1
+	}
+
+	return &RetentionService{taskRepo: taskRepo, authRepo: authRepo, policy: policy}
+}
+
+// Policy returns the retention settings being enforced
+func (s *RetentionService) Policy() RetentionPolicy {
+	return s.policy
+}
+
+// LastRun returns the result of the most recent pass, or nil if none has run yet
+func (s *RetentionService) LastRun() *RetentionRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastRun == nil {
+		return nil
+	}
+	run := *s.lastRun
+	return &run
+}
+
+// Run enforces the retention policy once. Each step runs even if an earlier one
+// failed; the failures are recorded in the run and returned together.
+func (s *RetentionService) Run(now time.Time) error {
+	run := &RetentionRun{StartedAt: now}
+	var errs []error
+	record := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+			run.Errors = append(run.Errors, err.Error())
+		}
+	}
+
+	var err error
+	run.LoginAttemptsDeleted, err = s.authRepo.CleanupOldLoginAttempts(now.Add(-time.Duration(s.policy.LoginAttemptsHours) * time.Hour))
+	record(err)
+
+	run.SessionsDeleted, err = s.authRepo.PurgeSessionsEndedBefore(now.AddDate(0, 0, -s.policy.SessionDays))
+	record(err)
+
+	if s.policy.ActivityDays > 0 {
+		run.StatusChangesDeleted, err = s.taskRepo.DeleteStatusChangesBefore(now.AddDate(0, 0, -s.policy.ActivityDays))
+		if err != nil {
+			record(fmt.Errorf("failed to delete status history: %w", err))
+		}
+	}
+
+	if s.policy.ArchiveResolvedDays > 0 {
+		run.TasksArchived, err = s.taskRepo.ArchiveResolvedBefore(now.AddDate(0, 0, -s.policy.ArchiveResolvedDays))
+		if err != nil {
+			record(fmt.Errorf("failed to archive resolved tasks: %w", err))
+		}
+	}
+
+	run.FinishedAt = time.Now()
+
+	s.mu.Lock()
+	s.lastRun = run
+	s.mu.Unlock()
+
+	return errors.Join(errs...)
+}
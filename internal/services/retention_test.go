@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestRetentionService_Run(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&models.User{}, &models.Session{}, &models.LoginAttempt{}); err != nil {
+		t.Fatalf("Failed to migrate auth tables: %v", err)
+	}
+	taskRepo := repository.NewTaskRepository(db)
+	authRepo := repository.NewAuthRepository(db)
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	db.Create(&models.LoginAttempt{Username: "old", IPAddress: "10.0.0.1", CreatedAt: now.Add(-30 * time.Hour)})
+	db.Create(&models.LoginAttempt{Username: "recent", IPAddress: "10.0.0.1", CreatedAt: now.Add(-time.Hour)})
+
+	db.Create(&models.Session{UserID: 1, Token: "old", ExpiresAt: daysAgo(40)})
+	db.Create(&models.Session{UserID: 1, Token: "recent", ExpiresAt: daysAgo(2)})
+	db.Create(&models.Session{UserID: 1, Token: "active", ExpiresAt: now.Add(time.Hour)})
+
+	db.Create(&models.TaskStatusChange{TaskID: 1, ToStatus: models.TaskStatusInProgress, ChangedAt: daysAgo(100)})
+	db.Create(&models.TaskStatusChange{TaskID: 1, ToStatus: models.TaskStatusResolved, ChangedAt: daysAgo(10)})
+
+	oldResolved, recentResolved := daysAgo(200), daysAgo(5)
+	db.Create(&models.Task{Name: "Old resolved", Status: models.TaskStatusClosed, ResolvedAt: &oldResolved})
+	db.Create(&models.Task{Name: "Recently resolved", Status: models.TaskStatusResolved, ResolvedAt: &recentResolved})
+	db.Create(&models.Task{Name: "Open", Status: models.TaskStatusOpen})
+
+	retention := NewRetentionService(taskRepo, authRepo, &config.RetentionConfig{
+		LoginAttemptsHours:  24,
+		SessionDays:         30,
+		ActivityDays:        90,
+		ArchiveResolvedDays: 180,
+	})
+	if retention.LastRun() != nil {
+		t.Error("Expected no last run before the first run")
+	}
+
+	if err := retention.Run(now); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	run := retention.LastRun()
+	if run == nil {
+		t.Fatal("Expected last run to be recorded")
+	}
+	if run.LoginAttemptsDeleted != 1 || run.SessionsDeleted != 1 || run.StatusChangesDeleted != 1 || run.TasksArchived != 1 {
+		t.Errorf("Unexpected run result: %+v", run)
+	}
+
+	var sessions int64
+	db.Unscoped().Model(&models.Session{}).Count(&sessions)
+	if sessions != 2 {
+		t.Errorf("Expected 2 sessions to remain, got %d", sessions)
+	}
+
+	tasks, err := taskRepo.GetAll()
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Errorf("Expected 2 tasks after archiving, got %d", len(tasks))
+	}
+}
+
+func TestRetentionService_Defaults(t *testing.T) {
+	retention := NewRetentionService(nil, nil, &config.RetentionConfig{LoginAttemptsHours: 0, ActivityDays: -5})
+
+	policy := retention.Policy()
+	if policy.LoginAttemptsHours != 1 {
+		t.Errorf("Expected login attempts to be kept at least 1 hour, got %d", policy.LoginAttemptsHours)
+	}
+	if policy.ActivityDays != 0 {
+		t.Errorf("Expected negative activity retention to mean keep forever, got %d", policy.ActivityDays)
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var (
+	ErrNotPendingReview      = errors.New("task is not pending review")
+	ErrNotReviewer           = errors.New("user is not a reviewer of this task")
+	ErrReviewCommentRequired = errors.New("a comment is required to reopen a task")
+)
+
+// SetReviewPolicies configures the review gate, keyed by tag. Tasks with one
+// of the tags move to pending-review instead of resolved, and wait for one of
+// the tags' reviewers to approve or reopen them.
+func (s *TaskService) SetReviewPolicies(policies map[string]config.ReviewConfig) error {
+	validated := make(map[string][]string, len(policies))
+	for tag, cfg := range policies {
+		var reviewers []string
+		for _, reviewer := range cfg.Reviewers {
+			if reviewer = strings.TrimSpace(reviewer); reviewer != "" && !slices.Contains(reviewers, reviewer) {
+				reviewers = append(reviewers, reviewer)
+			}
+		}
+		if len(reviewers) == 0 {
+			return fmt.Errorf("review policy for tag %q has no reviewers", tag)
+		}
+		validated[strings.ToLower(tag)] = reviewers
+	}
+
+	s.reviewers = validated
+	return nil
+}
+
+// Reviewers returns the users who may review a task, from all of its tags with
+// a review policy; none means the task resolves without review
+func (s *TaskService) Reviewers(task *models.Task) []string {
+	var reviewers []string
+	for _, tag := range task.Tags {
+		for _, reviewer := range s.reviewers[strings.ToLower(tag)] {
+			if !slices.Contains(reviewers, reviewer) {
+				reviewers = append(reviewers, reviewer)
+			}
+		}
+	}
+	return reviewers
+}
+
+// ReviewTask approves a task that is pending review, resolving it, or reopens
+// it. Reopening needs a comment, which is added to the task as a note by the
+// reviewer; so is an optional comment on approval.
+func (s *TaskService) ReviewTask(taskID uint, reviewer string, approve bool, comment string) (*models.Task, error) {
+	task, err := s.repo.GetByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != models.TaskStatusPendingReview {
+		return nil, ErrNotPendingReview
+	}
+	if !slices.ContainsFunc(s.Reviewers(task), func(r string) bool { return strings.EqualFold(r, reviewer) }) {
+		return nil, ErrNotReviewer
+	}
+	comment = strings.TrimSpace(comment)
+	if !approve && comment == "" {
+		return nil, ErrReviewCommentRequired
+	}
+
+	review := &models.TaskReview{
+		TaskID:     task.ID,
+		Reviewer:   reviewer,
+		Decision:   models.ReviewApproved,
+		Comment:    comment,
+		ReviewedAt: time.Now(),
+	}
+	task.Status = models.TaskStatusResolved
+	if !approve {
+		review.Decision = models.ReviewReopened
+		task.Status = models.TaskStatusOpen
+	}
+	task.ChangedBy = reviewer
+	if err := s.updateTask(task, true); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateTaskReview(review); err != nil {
+		return nil, err
+	}
+
+	if comment != "" {
+		note := &models.Comment{Content: comment, IsPrivate: true, CreatedBy: reviewer}
+		if err := s.AddComment(task.ID, note); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.GetByID(task.ID)
+}
+
+// GetTaskReviews returns the review decisions made on a task, oldest first
+func (s *TaskService) GetTaskReviews(taskID uint) ([]*models.TaskReview, error) {
+	return s.repo.GetTaskReviews(taskID)
+}
+
+// holdForReview moves a task being resolved to pending-review when one of its
+// tags has reviewers
+func (s *TaskService) holdForReview(task *models.Task, oldStatus models.TaskStatus) {
+	if task.Status == models.TaskStatusResolved && oldStatus != models.TaskStatusResolved && len(s.Reviewers(task)) > 0 {
+		task.Status = models.TaskStatusPendingReview
+	}
+}
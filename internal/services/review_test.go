@@ -0,0 +1,94 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_ReviewGate(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+	if err := service.SetReviewPolicies(map[string]config.ReviewConfig{
+		"Change": {Reviewers: []string{"alice", " bob ", "alice"}},
+	}); err != nil {
+		t.Fatalf("Failed to set policies: %v", err)
+	}
+
+	resolve := func(task *models.Task) {
+		task.Status = models.TaskStatusResolved
+		if err := service.UpdateTask(task); err != nil {
+			t.Fatalf("Failed to resolve task: %v", err)
+		}
+	}
+
+	// Tasks without a review tag resolve straight away
+	plain, _ := service.CreateTask("Order toner")
+	resolve(plain)
+	if plain.Status != models.TaskStatusResolved {
+		t.Errorf("Expected a task without review tags to resolve, got %s", plain.Status)
+	}
+
+	task, _ := service.CreateTask("Upgrade core switch")
+	task.Tags = []string{"change"}
+	resolve(task)
+	if task.Status != models.TaskStatusPendingReview || task.ResolvedAt != nil {
+		t.Fatalf("Expected the task to wait for review, got %s", task.Status)
+	}
+	if reviewers := service.Reviewers(task); len(reviewers) != 2 || reviewers[1] != "bob" {
+		t.Errorf("Expected reviewers alice and bob, got %v", reviewers)
+	}
+
+	if _, err := service.ReviewTask(task.ID, "carol", true, ""); err != ErrNotReviewer {
+		t.Errorf("Expected ErrNotReviewer, got %v", err)
+	}
+	if _, err := service.ReviewTask(task.ID, "bob", false, "  "); err != ErrReviewCommentRequired {
+		t.Errorf("Expected ErrReviewCommentRequired, got %v", err)
+	}
+	if _, err := service.ReviewTask(plain.ID, "bob", true, ""); err != ErrNotPendingReview {
+		t.Errorf("Expected ErrNotPendingReview, got %v", err)
+	}
+
+	reopened, err := service.ReviewTask(task.ID, "Bob", false, "Rollback plan is missing")
+	if err != nil {
+		t.Fatalf("Failed to reopen task: %v", err)
+	}
+	if reopened.Status != models.TaskStatusOpen {
+		t.Errorf("Expected the task reopened, got %s", reopened.Status)
+	}
+	if len(reopened.Comments) != 1 || reopened.Comments[0].CreatedBy != "Bob" {
+		t.Errorf("Expected the reviewer's comment as a note, got %+v", reopened.Comments)
+	}
+
+	resolve(reopened)
+	approved, err := service.ReviewTask(task.ID, "alice", true, "")
+	if err != nil {
+		t.Fatalf("Failed to approve task: %v", err)
+	}
+	if approved.Status != models.TaskStatusResolved || approved.ResolvedAt == nil {
+		t.Errorf("Expected the approved task resolved, got %s", approved.Status)
+	}
+
+	history, _ := service.GetStatusHistory(task.ID)
+	if last := history[len(history)-1]; last.FromStatus != models.TaskStatusPendingReview || last.ChangedBy != "alice" {
+		t.Errorf("Expected alice's approval in the status history, got %+v", last)
+	}
+
+	events, err := service.GetActivity(ActivityFilter{Types: []ActivityType{ActivityReviewed}})
+	if err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	if len(events) != 2 || events[0].Detail != "Approved by alice" || !strings.HasPrefix(events[1].Detail, "Reopened by Bob: Rollback") {
+		t.Errorf("Expected both reviews in the activity log, got %+v", events)
+	}
+}
+
+func TestTaskService_SetReviewPoliciesValidation(t *testing.T) {
+	service := NewTaskService(nil, nil)
+	if err := service.SetReviewPolicies(map[string]config.ReviewConfig{"change": {Reviewers: []string{" "}}}); err == nil {
+		t.Error("Expected an error for a tag without reviewers")
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var (
+	ErrAutomationRuleNotFound = errors.New("automation rule not found")
+	ErrInvalidRuleName        = errors.New("rule name is required")
+	ErrInvalidRuleTrigger     = errors.New("rule trigger must be created, tag_added, status_changed, or email_received")
+	ErrInvalidRuleCondition   = errors.New("rule priority or status is not valid")
+	ErrRuleSenderTrigger      = errors.New("a sender condition only applies to the email_received trigger")
+	ErrRuleNoActions          = errors.New("a rule needs at least one action")
+	ErrInvalidRuleWebhookURL  = errors.New("webhook URL must be an http(s) URL")
+)
+
+// ruleWebhookClient posts task payloads to rule webhooks
+var ruleWebhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// ListAutomationRules returns all automation rules in the order they are applied
+func (s *SettingsService) ListAutomationRules() ([]*models.AutomationRule, error) {
+	return s.repo.ListAutomationRules()
+}
+
+// GetAutomationRule returns an automation rule by ID
+func (s *SettingsService) GetAutomationRule(id uint) (*models.AutomationRule, error) {
+	rule, err := s.repo.GetAutomationRule(id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, ErrAutomationRuleNotFound
+	}
+	return rule, nil
+}
+
+// ActiveAutomationRules returns the enabled automation rules in the order they are applied
+func (s *SettingsService) ActiveAutomationRules() ([]*models.AutomationRule, error) {
+	rules, err := s.repo.ListAutomationRules()
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(rules, func(rule *models.AutomationRule) bool { return !rule.Enabled }), nil
+}
+
+// SaveAutomationRule validates and creates or updates an automation rule
+func (s *SettingsService) SaveAutomationRule(rule *models.AutomationRule) (*models.AutomationRule, error) {
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.Tag = strings.TrimSpace(rule.Tag)
+	rule.Sender = strings.TrimSpace(rule.Sender)
+	rule.SetAssignee = strings.TrimSpace(rule.SetAssignee)
+	rule.AddTag = strings.TrimSpace(rule.AddTag)
+	rule.WebhookURL = strings.TrimSpace(rule.WebhookURL)
+
+	if rule.Name == "" {
+		return nil, ErrInvalidRuleName
+	}
+	switch rule.Trigger {
+	case models.RuleTriggerCreated, models.RuleTriggerTagAdded, models.RuleTriggerStatusChanged, models.RuleTriggerEmailReceived:
+	default:
+		return nil, ErrInvalidRuleTrigger
+	}
+	switch rule.Priority {
+	case "", models.TaskPriorityLow, models.TaskPriorityMedium, models.TaskPriorityHigh:
+	default:
+		return nil, ErrInvalidRuleCondition
+	}
+	if rule.Status != "" && !isKanbanStatus(rule.Status) {
+		return nil, ErrInvalidRuleCondition
+	}
+	if rule.Sender != "" && rule.Trigger != models.RuleTriggerEmailReceived {
+		return nil, ErrRuleSenderTrigger
+	}
+	if rule.SetAssignee == "" && rule.AddTag == "" && !rule.Notify && rule.WebhookURL == "" {
+		return nil, ErrRuleNoActions
+	}
+	if rule.WebhookURL != "" && !strings.HasPrefix(rule.WebhookURL, "https://") && !strings.HasPrefix(rule.WebhookURL, "http://") {
+		return nil, ErrInvalidRuleWebhookURL
+	}
+
+	if rule.ID != 0 {
+		existing, err := s.repo.GetAutomationRule(rule.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, ErrAutomationRuleNotFound
+		}
+		rule.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.repo.SaveAutomationRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteAutomationRule deletes an automation rule by ID
+func (s *SettingsService) DeleteAutomationRule(id uint) error {
+	existing, err := s.repo.GetAutomationRule(id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrAutomationRuleNotFound
+	}
+	return s.repo.DeleteAutomationRule(id)
+}
+
+// RuleSource provides the automation rules applied on task events
+type RuleSource interface {
+	ActiveAutomationRules() ([]*models.AutomationRule, error)
+}
+
+// SetRuleSource enables automation rules on task events
+func (s *TaskService) SetRuleSource(rules RuleSource) {
+	s.rules = rules
+}
+
+// ruleEvent describes what happened to a task when rules are evaluated
+type ruleEvent struct {
+	Trigger string
+	Tag     string // The added tag, for tag_added
+	Sender  string // The email sender, for email_received
+}
+
+// matches reports whether a rule fires for an event on a task
+func (e ruleEvent) matches(rule *models.AutomationRule, task *models.Task) bool {
+	if rule.Trigger != e.Trigger {
+		return false
+	}
+	if rule.Tag != "" {
+		if e.Trigger == models.RuleTriggerTagAdded {
+			if !strings.EqualFold(rule.Tag, e.Tag) {
+				return false
+			}
+		} else if !slices.ContainsFunc(task.Tags, func(tag string) bool { return strings.EqualFold(tag, rule.Tag) }) {
+			return false
+		}
+	}
+	if rule.Priority != "" && task.Priority != rule.Priority {
+		return false
+	}
+	if rule.Status != "" && task.Status != rule.Status {
+		return false
+	}
+	if rule.Sender != "" && !strings.Contains(strings.ToLower(e.Sender), strings.ToLower(rule.Sender)) {
+		return false
+	}
+	return true
+}
+
+// applyRules runs the actions of every rule matching the event. Changes made
+// by rules do not fire further rules, and a failing rule is logged rather than
+// failing the change that triggered it.
+func (s *TaskService) applyRules(task *models.Task, event ruleEvent) {
+	if s.rules == nil {
+		return
+	}
+
+	rules, err := s.rules.ActiveAutomationRules()
+	if err != nil {
+		log.Printf("Failed to load automation rules: %v", err)
+		return
+	}
+
+	changed := false
+	var webhooks []*models.AutomationRule
+	for _, rule := range rules {
+		if !event.matches(rule, task) {
+			continue
+		}
+
+		if rule.SetAssignee != "" && task.Assignee != rule.SetAssignee {
+			task.Assignee = rule.SetAssignee
+			changed = true
+		}
+		if rule.AddTag != "" && !slices.ContainsFunc(task.Tags, func(tag string) bool { return strings.EqualFold(tag, rule.AddTag) }) {
+			task.Tags = append(task.Tags, rule.AddTag)
+			changed = true
+		}
+		if rule.Notify && s.notification != nil {
+			go s.notification.NotifyRuleMatched(task, rule)
+		}
+		if rule.WebhookURL != "" {
+			webhooks = append(webhooks, rule)
+		}
+	}
+
+	if changed {
+		if err := s.repo.Update(task); err != nil {
+			log.Printf("Failed to apply automation rules to task %d: %v", task.ID, err)
+		}
+	}
+
+	// Encode now, after every action applied, so the request sees a stable task
+	for _, rule := range webhooks {
+		raw, err := json.Marshal(map[string]any{
+			"rule":    rule.Name,
+			"trigger": event.Trigger,
+			"task":    task,
+		})
+		if err != nil {
+			log.Printf("Failed to encode webhook for rule %q: %v", rule.Name, err)
+			continue
+		}
+		go callRuleWebhook(rule, raw)
+	}
+}
+
+// callRuleWebhook posts the JSON payload of a matched rule to its webhook
+func callRuleWebhook(rule *models.AutomationRule, raw []byte) {
+	resp, err := ruleWebhookClient.Post(rule.WebhookURL, "application/json", bytes.NewReader(raw))
+	if err != nil {
+		log.Printf("Webhook for rule %q failed: %v", rule.Name, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("Webhook for rule %q failed: status %d", rule.Name, resp.StatusCode)
+	}
+}
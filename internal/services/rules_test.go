@@ -0,0 +1,102 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_AutomationRules(t *testing.T) {
+	db := setupTestDB(t)
+	settings := NewSettingsService(repository.NewSettingsRepository(db))
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+	service.SetRuleSource(settings)
+
+	hooked := make(chan map[string]any, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload)
+		hooked <- payload
+	}))
+	defer server.Close()
+
+	for _, rule := range []*models.AutomationRule{
+		{Name: "Triage", Enabled: true, Trigger: models.RuleTriggerCreated, AddTag: "triage"},
+		{Name: "Billing", Enabled: true, Trigger: models.RuleTriggerTagAdded, Tag: "billing", SetAssignee: "alice"},
+		{Name: "Customer", Enabled: true, Trigger: models.RuleTriggerEmailReceived, Sender: "@customer.com", AddTag: "customer"},
+		{Name: "Resolved", Enabled: true, Trigger: models.RuleTriggerStatusChanged, Status: models.TaskStatusResolved, WebhookURL: server.URL},
+		{Name: "Disabled", Enabled: false, Trigger: models.RuleTriggerCreated, SetAssignee: "bob"},
+	} {
+		if _, err := settings.SaveAutomationRule(rule); err != nil {
+			t.Fatalf("Failed to save rule %q: %v", rule.Name, err)
+		}
+	}
+
+	task, err := service.CreateTask("Invoice is wrong")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if len(task.Tags) != 1 || task.Tags[0] != "triage" || task.Assignee != "" {
+		t.Fatalf("Expected only the triage tag on creation, got tags %v and assignee %q", task.Tags, task.Assignee)
+	}
+
+	task.Tags = append(task.Tags, "Billing")
+	if err := service.UpdateTask(task); err != nil {
+		t.Fatalf("Failed to tag task: %v", err)
+	}
+	if reloaded, _ := service.GetTask(task.ID); reloaded.Assignee != "alice" {
+		t.Errorf("Expected the billing rule to assign alice, got %q", reloaded.Assignee)
+	}
+
+	if err := service.AddComment(task.ID, &models.Comment{Content: "Any news?", FromEmail: "someone@other.org"}); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+	if err := service.AddComment(task.ID, &models.Comment{Content: "Any news?", FromEmail: "jane@customer.com"}); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+	reloaded, _ := service.GetTask(task.ID)
+	if len(reloaded.Tags) != 3 || reloaded.Tags[2] != "customer" {
+		t.Errorf("Expected the customer tag once after the matching sender, got %v", reloaded.Tags)
+	}
+
+	reloaded.Status = models.TaskStatusResolved
+	if err := service.UpdateTask(reloaded); err != nil {
+		t.Fatalf("Failed to resolve task: %v", err)
+	}
+	select {
+	case payload := <-hooked:
+		if payload["rule"] != "Resolved" || payload["trigger"] != models.RuleTriggerStatusChanged {
+			t.Errorf("Unexpected webhook payload: %v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the webhook to be called")
+	}
+}
+
+func TestSettingsService_AutomationRuleValidation(t *testing.T) {
+	db := setupTestDB(t)
+	settings := NewSettingsService(repository.NewSettingsRepository(db))
+
+	for _, tt := range []struct {
+		rule *models.AutomationRule
+		err  error
+	}{
+		{&models.AutomationRule{Trigger: models.RuleTriggerCreated, Notify: true}, ErrInvalidRuleName},
+		{&models.AutomationRule{Name: "x", Trigger: "deleted", Notify: true}, ErrInvalidRuleTrigger},
+		{&models.AutomationRule{Name: "x", Trigger: models.RuleTriggerCreated, Priority: "urgent", Notify: true}, ErrInvalidRuleCondition},
+		{&models.AutomationRule{Name: "x", Trigger: models.RuleTriggerCreated, Sender: "@a.com", Notify: true}, ErrRuleSenderTrigger},
+		{&models.AutomationRule{Name: "x", Trigger: models.RuleTriggerCreated}, ErrRuleNoActions},
+		{&models.AutomationRule{Name: "x", Trigger: models.RuleTriggerCreated, WebhookURL: "ftp://a"}, ErrInvalidRuleWebhookURL},
+		{&models.AutomationRule{ID: 42, Name: "x", Trigger: models.RuleTriggerCreated, Notify: true}, ErrAutomationRuleNotFound},
+	} {
+		if _, err := settings.SaveAutomationRule(tt.rule); !errors.Is(err, tt.err) {
+			t.Errorf("Rule %+v: expected %v, got %v", tt.rule, tt.err, err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+var (
+	ErrSavedQueryNotFound         = errors.New("saved query not found")
+	ErrScheduleNotFound           = errors.New("saved query has no schedule")
+	ErrScheduleRecipientsRequired = errors.New("at least one recipient is required")
+	ErrInvalidRecipient           = errors.New("invalid recipient email address")
+	ErrInvalidCron                = errors.New("invalid cron expression")
+)
+
+// EmailTemplateSavedQueryReport is the email sent for scheduled saved query reports
+const EmailTemplateSavedQueryReport = "saved_query_report"
+
+// SetSavedQuerySchedule creates or replaces the email schedule of a saved query
+// and computes its next run
+func (s *TaskService) SetSavedQuerySchedule(queryID uint, schedule *models.SavedQuerySchedule, now time.Time) (*models.SavedQuerySchedule, error) {
+	if _, err := s.repo.GetSavedQueryByID(queryID); err != nil {
+		return nil, ErrSavedQueryNotFound
+	}
+
+	cron, err := utils.ParseCron(schedule.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCron, err)
+	}
+
+	var recipients []string
+	for _, recipient := range schedule.Recipients {
+		recipient = strings.TrimSpace(recipient)
+		if recipient == "" {
+			continue
+		}
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRecipient, recipient)
+		}
+		recipients = append(recipients, recipient)
+	}
+	if len(recipients) == 0 {
+		return nil, ErrScheduleRecipientsRequired
+	}
+
+	existing, err := s.repo.GetSavedQuerySchedule(queryID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		existing = &models.SavedQuerySchedule{SavedQueryID: queryID}
+	}
+
+	existing.Cron = strings.TrimSpace(schedule.Cron)
+	existing.Recipients = recipients
+	existing.Subject = strings.TrimSpace(schedule.Subject)
+	existing.Enabled = schedule.Enabled
+	existing.NextRunAt = nil
+	if next := cron.Next(now); !next.IsZero() {
+		existing.NextRunAt = &next
+	}
+
+	if err := s.repo.SaveSavedQuerySchedule(existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// GetSavedQuerySchedule returns the schedule of a saved query or ErrScheduleNotFound
+func (s *TaskService) GetSavedQuerySchedule(queryID uint) (*models.SavedQuerySchedule, error) {
+	schedule, err := s.repo.GetSavedQuerySchedule(queryID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// DeleteSavedQuerySchedule stops emailing a saved query
+func (s *TaskService) DeleteSavedQuerySchedule(queryID uint) error {
+	if _, err := s.GetSavedQuerySchedule(queryID); err != nil {
+		return err
+	}
+	return s.repo.DeleteSavedQuerySchedule(queryID)
+}
+
+// SavedQueryReporter emails scheduled saved query reports as rendered task tables
+type SavedQueryReporter struct {
+	tasks *TaskService
+	smtp  *SMTPService
+}
+
+// NewSavedQueryReporter creates a reporter; register its Run method with a JobRunner
+func NewSavedQueryReporter(tasks *TaskService, smtp *SMTPService) *SavedQueryReporter {
+	return &SavedQueryReporter{tasks: tasks, smtp: smtp}
+}
+
+// Run sends every schedule that is due and advances it to its next run. A failed
+// send is recorded in the schedule's LastError and not retried until the next run.
+func (r *SavedQueryReporter) Run(now time.Time) error {
+	schedules, err := r.tasks.repo.GetDueSavedQuerySchedules(now)
+	if err != nil {
+		return fmt.Errorf("failed to load due saved query schedules: %w", err)
+	}
+
+	for _, schedule := range schedules {
+		schedule.LastError = ""
+		if err := r.send(schedule); err != nil {
+			log.Printf("Saved query report %d failed: %v", schedule.SavedQueryID, err)
+			schedule.LastError = err.Error()
+		}
+
+		ranAt := now
+		schedule.LastRunAt = &ranAt
+		schedule.NextRunAt = nil
+		if cron, err := utils.ParseCron(schedule.Cron); err == nil {
+			if next := cron.Next(now); !next.IsZero() {
+				schedule.NextRunAt = &next
+			}
+		}
+
+		if err := r.tasks.repo.SaveSavedQuerySchedule(schedule); err != nil {
+			log.Printf("Saved query report %d: failed to save schedule: %v", schedule.SavedQueryID, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *SavedQueryReporter) send(schedule *models.SavedQuerySchedule) error {
+	email, err := r.Render(schedule)
+	if err != nil {
+		return err
+	}
+	return r.smtp.sendEmail(schedule.Recipients, email, "")
+}
+
+// Render renders the report email for a schedule with the saved query's current tasks
+func (r *SavedQueryReporter) Render(schedule *models.SavedQuerySchedule) (*RenderedEmail, error) {
+	query, err := r.tasks.GetSavedQueryByID(schedule.SavedQueryID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := r.tasks.GetTasksBySavedQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	email, err := r.smtp.Templates().Render(EmailTemplateSavedQueryReport, EmailTemplateData{
+		Query: query,
+		Tasks: tasks,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Subject != "" {
+		email.Subject = schedule.Subject
+	}
+	return email, nil
+}
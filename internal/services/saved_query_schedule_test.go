@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestSavedQuerySchedule(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	for _, task := range []*models.Task{
+		{Name: "Client bug", Status: models.TaskStatusOpen, Tags: []string{"acme"}},
+		{Name: "Internal chore", Status: models.TaskStatusOpen, Tags: []string{"ops"}},
+	} {
+		if err := repo.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+	query, err := service.CreateSavedQuery(&models.SavedQuery{Name: "Acme", IncludedTags: []string{"acme"}})
+	if err != nil {
+		t.Fatalf("Failed to create saved query: %v", err)
+	}
+
+	// Wednesday
+	now := time.Date(2024, 3, 6, 10, 0, 0, 0, time.Local)
+
+	if _, err := service.SetSavedQuerySchedule(query.ID, &models.SavedQuerySchedule{Cron: "every monday", Recipients: []string{"client@example.com"}}, now); !errors.Is(err, ErrInvalidCron) {
+		t.Errorf("Expected ErrInvalidCron, got %v", err)
+	}
+	if _, err := service.SetSavedQuerySchedule(query.ID, &models.SavedQuerySchedule{Cron: "@weekly"}, now); err != ErrScheduleRecipientsRequired {
+		t.Errorf("Expected ErrScheduleRecipientsRequired, got %v", err)
+	}
+	if _, err := service.SetSavedQuerySchedule(999, &models.SavedQuerySchedule{Cron: "@weekly", Recipients: []string{"client@example.com"}}, now); err != ErrSavedQueryNotFound {
+		t.Errorf("Expected ErrSavedQueryNotFound, got %v", err)
+	}
+
+	schedule, err := service.SetSavedQuerySchedule(query.ID, &models.SavedQuerySchedule{
+		Cron:       "0 8 * * mon",
+		Recipients: []string{" client@example.com "},
+		Enabled:    true,
+	}, now)
+	if err != nil {
+		t.Fatalf("Failed to set schedule: %v", err)
+	}
+	wantNext := time.Date(2024, 3, 11, 8, 0, 0, 0, time.Local)
+	if schedule.NextRunAt == nil || !schedule.NextRunAt.Equal(wantNext) {
+		t.Errorf("Expected next run %v, got %v", wantNext, schedule.NextRunAt)
+	}
+	if len(schedule.Recipients) != 1 || schedule.Recipients[0] != "client@example.com" {
+		t.Errorf("Expected trimmed recipient, got %v", schedule.Recipients)
+	}
+
+	// SMTP is not configured, so the send fails but the schedule still advances
+	reporter := NewSavedQueryReporter(service, NewSMTPService(&config.EmailConfig{}))
+
+	email, err := reporter.Render(schedule)
+	if err != nil {
+		t.Fatalf("Failed to render report: %v", err)
+	}
+	if email.Subject != "Report: Acme" {
+		t.Errorf("Expected subject 'Report: Acme', got %q", email.Subject)
+	}
+	if !strings.Contains(email.HTML, "Client bug") || strings.Contains(email.HTML, "Internal chore") {
+		t.Errorf("Expected only the matching task in the report, got:\n%s", email.HTML)
+	}
+
+	if err := reporter.Run(now); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	unchanged, _ := service.GetSavedQuerySchedule(query.ID)
+	if unchanged.LastRunAt != nil {
+		t.Errorf("Expected schedule not to run before it is due")
+	}
+
+	if err := reporter.Run(wantNext); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	ran, err := service.GetSavedQuerySchedule(query.ID)
+	if err != nil {
+		t.Fatalf("Failed to get schedule: %v", err)
+	}
+	if ran.LastRunAt == nil || !ran.LastRunAt.Equal(wantNext) {
+		t.Errorf("Expected last run %v, got %v", wantNext, ran.LastRunAt)
+	}
+	if ran.NextRunAt == nil || !ran.NextRunAt.Equal(wantNext.AddDate(0, 0, 7)) {
+		t.Errorf("Expected next run a week later, got %v", ran.NextRunAt)
+	}
+	if !strings.Contains(ran.LastError, "SMTP not configured") {
+		t.Errorf("Expected the send error to be recorded, got %q", ran.LastError)
+	}
+
+	if err := service.DeleteSavedQuerySchedule(query.ID); err != nil {
+		t.Fatalf("Failed to delete schedule: %v", err)
+	}
+	if _, err := service.GetSavedQuerySchedule(query.ID); err != ErrScheduleNotFound {
+		t.Errorf("Expected ErrScheduleNotFound after delete, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/utils"
+)
+
+var (
+	ErrSavedQueryWebhookNotFound   = errors.New("saved query webhook not found")
+	ErrInvalidSavedQueryWebhookURL = errors.New("webhook URL must be an http(s) URL")
+)
+
+// SavedQueryWebhookSignatureHeader carries the hex HMAC-SHA256 of the payload,
+// keyed with the webhook's secret, as "sha256=<hex>"
+const SavedQueryWebhookSignatureHeader = "X-JATS-Signature"
+
+// savedQueryWebhookTimeout bounds one export; result sets can be large, so it
+// is more generous than the automation rule webhook timeout
+const savedQueryWebhookTimeout = time.Minute
+
+// SavedQueryExport is the JSON payload posted to saved query webhooks
+type SavedQueryExport struct {
+	Query       *models.SavedQuery `json:"query"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	Count       int                `json:"count"`
+	Tasks       []*models.Task     `json:"tasks"`
+}
+
+// ListSavedQueryWebhooks returns every saved query webhook
+func (s *TaskService) ListSavedQueryWebhooks() ([]*models.SavedQueryWebhook, error) {
+	return s.repo.ListSavedQueryWebhooks()
+}
+
+// GetSavedQueryWebhook returns a saved query webhook or ErrSavedQueryWebhookNotFound
+func (s *TaskService) GetSavedQueryWebhook(id uint) (*models.SavedQueryWebhook, error) {
+	webhook, err := s.repo.GetSavedQueryWebhook(id)
+	if err != nil {
+		return nil, ErrSavedQueryWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// SaveSavedQueryWebhook creates a saved query webhook, or updates it when it
+// has an ID, and computes its next run
+func (s *TaskService) SaveSavedQueryWebhook(webhook *models.SavedQueryWebhook, now time.Time) (*models.SavedQueryWebhook, error) {
+	if webhook.ID != 0 {
+		existing, err := s.GetSavedQueryWebhook(webhook.ID)
+		if err != nil {
+			return nil, err
+		}
+		webhook.CreatedAt = existing.CreatedAt
+		webhook.LastRunAt = existing.LastRunAt
+		webhook.LastError = existing.LastError
+	}
+	if _, err := s.repo.GetSavedQueryByID(webhook.SavedQueryID); err != nil {
+		return nil, ErrSavedQueryNotFound
+	}
+
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	if !strings.HasPrefix(webhook.URL, "https://") && !strings.HasPrefix(webhook.URL, "http://") {
+		return nil, ErrInvalidSavedQueryWebhookURL
+	}
+	webhook.Cron = strings.TrimSpace(webhook.Cron)
+	cron, err := utils.ParseCron(webhook.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCron, err)
+	}
+	webhook.Secret = strings.TrimSpace(webhook.Secret)
+
+	webhook.NextRunAt = nil
+	if next := cron.Next(now); !next.IsZero() {
+		webhook.NextRunAt = &next
+	}
+
+	if err := s.repo.SaveSavedQueryWebhook(webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// DeleteSavedQueryWebhook stops posting a saved query to a webhook
+func (s *TaskService) DeleteSavedQueryWebhook(id uint) error {
+	if _, err := s.GetSavedQueryWebhook(id); err != nil {
+		return err
+	}
+	return s.repo.DeleteSavedQueryWebhook(id)
+}
+
+// SavedQueryExporter posts the full result set of saved queries to their
+// webhooks on schedule
+type SavedQueryExporter struct {
+	tasks  *TaskService
+	client *http.Client
+}
+
+// NewSavedQueryExporter creates an exporter; register its Run method with a JobRunner
+func NewSavedQueryExporter(tasks *TaskService) *SavedQueryExporter {
+	return &SavedQueryExporter{tasks: tasks, client: &http.Client{Timeout: savedQueryWebhookTimeout}}
+}
+
+// Run posts every webhook that is due and advances it to its next run. A
+// failed post is recorded in the webhook's LastError and not retried until the
+// next run.
+func (e *SavedQueryExporter) Run(now time.Time) error {
+	webhooks, err := e.tasks.repo.GetDueSavedQueryWebhooks(now)
+	if err != nil {
+		return fmt.Errorf("failed to load due saved query webhooks: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		webhook.LastError = ""
+		if err := e.Send(webhook, now); err != nil {
+			log.Printf("Saved query webhook %d failed: %v", webhook.ID, err)
+			webhook.LastError = err.Error()
+		}
+
+		ranAt := now
+		webhook.LastRunAt = &ranAt
+		webhook.NextRunAt = nil
+		if cron, err := utils.ParseCron(webhook.Cron); err == nil {
+			if next := cron.Next(now); !next.IsZero() {
+				webhook.NextRunAt = &next
+			}
+		}
+
+		if err := e.tasks.repo.SaveSavedQueryWebhook(webhook); err != nil {
+			log.Printf("Saved query webhook %d: failed to save webhook: %v", webhook.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Send posts the saved query's current tasks to a webhook. Any status other
+// than 2xx is an error.
+func (e *SavedQueryExporter) Send(webhook *models.SavedQueryWebhook, now time.Time) error {
+	query, err := e.tasks.GetSavedQueryByID(webhook.SavedQueryID)
+	if err != nil {
+		return ErrSavedQueryNotFound
+	}
+	tasks, err := e.tasks.GetTasksBySavedQuery(query)
+	if err != nil {
+		return err
+	}
+	if tasks == nil {
+		tasks = []*models.Task{}
+	}
+
+	raw, err := json.Marshal(SavedQueryExport{Query: query, GeneratedAt: now, Count: len(tasks), Tasks: tasks})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(webhook.Secret))
+		mac.Write(raw)
+		req.Header.Set(SavedQueryWebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestSavedQueryExporter(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	for _, task := range []*models.Task{
+		{Name: "Client bug", Status: models.TaskStatusOpen, Tags: []string{"acme"}},
+		{Name: "Client report", Status: models.TaskStatusClosed, Tags: []string{"acme"}},
+		{Name: "Internal chore", Status: models.TaskStatusOpen, Tags: []string{"ops"}},
+	} {
+		if err := repo.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+	query, err := service.CreateSavedQuery(&models.SavedQuery{Name: "Acme", IncludedTags: []string{"acme"}})
+	if err != nil {
+		t.Fatalf("Failed to create saved query: %v", err)
+	}
+
+	var received []byte
+	var signature string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SavedQueryWebhookSignatureHeader)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 3, 6, 10, 0, 0, 0, time.Local)
+
+	if _, err := service.SaveSavedQueryWebhook(&models.SavedQueryWebhook{SavedQueryID: query.ID, URL: "ftp://example.com", Cron: "@daily"}, now); err != ErrInvalidSavedQueryWebhookURL {
+		t.Errorf("Expected ErrInvalidSavedQueryWebhookURL, got %v", err)
+	}
+	if _, err := service.SaveSavedQueryWebhook(&models.SavedQueryWebhook{SavedQueryID: query.ID, URL: server.URL, Cron: "daily"}, now); !errors.Is(err, ErrInvalidCron) {
+		t.Errorf("Expected ErrInvalidCron, got %v", err)
+	}
+	if _, err := service.SaveSavedQueryWebhook(&models.SavedQueryWebhook{SavedQueryID: 999, URL: server.URL, Cron: "@daily"}, now); err != ErrSavedQueryNotFound {
+		t.Errorf("Expected ErrSavedQueryNotFound, got %v", err)
+	}
+
+	webhook, err := service.SaveSavedQueryWebhook(&models.SavedQueryWebhook{
+		SavedQueryID: query.ID,
+		URL:          " " + server.URL + " ",
+		Cron:         "0 6 * * *",
+		Secret:       "s3cret",
+		Enabled:      true,
+	}, now)
+	if err != nil {
+		t.Fatalf("Failed to save webhook: %v", err)
+	}
+	wantNext := time.Date(2024, 3, 7, 6, 0, 0, 0, time.Local)
+	if webhook.NextRunAt == nil || !webhook.NextRunAt.Equal(wantNext) {
+		t.Errorf("Expected next run %v, got %v", wantNext, webhook.NextRunAt)
+	}
+
+	exporter := NewSavedQueryExporter(service)
+	if err := exporter.Run(now); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if received != nil {
+		t.Fatalf("Expected webhook not to run before it is due")
+	}
+
+	if err := exporter.Run(wantNext); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	var payload SavedQueryExport
+	if err := json.Unmarshal(received, &payload); err != nil {
+		t.Fatalf("Failed to decode payload %q: %v", received, err)
+	}
+	if payload.Query.Name != "Acme" || payload.Count != 2 || len(payload.Tasks) != 2 {
+		t.Errorf("Expected both Acme tasks in the payload, got %+v", payload)
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(received)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("Expected signature %q, got %q", want, signature)
+	}
+
+	ran, _ := service.GetSavedQueryWebhook(webhook.ID)
+	if ran.LastRunAt == nil || !ran.LastRunAt.Equal(wantNext) || ran.LastError != "" {
+		t.Errorf("Expected a successful run at %v, got %v %q", wantNext, ran.LastRunAt, ran.LastError)
+	}
+	if ran.NextRunAt == nil || !ran.NextRunAt.Equal(wantNext.AddDate(0, 0, 1)) {
+		t.Errorf("Expected the next run a day later, got %v", ran.NextRunAt)
+	}
+
+	// A rejected post is recorded and the webhook still advances
+	status = http.StatusBadGateway
+	if err := exporter.Run(*ran.NextRunAt); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	failed, _ := service.GetSavedQueryWebhook(webhook.ID)
+	if failed.LastError != "status 502" {
+		t.Errorf("Expected the failed status to be recorded, got %q", failed.LastError)
+	}
+	if failed.NextRunAt == nil || !failed.NextRunAt.After(*ran.NextRunAt) {
+		t.Errorf("Expected the webhook to advance after a failure, got %v", failed.NextRunAt)
+	}
+
+	if err := service.DeleteSavedQuery(query.ID); err != nil {
+		t.Fatalf("Failed to delete saved query: %v", err)
+	}
+	if _, err := service.GetSavedQueryWebhook(webhook.ID); err != ErrSavedQueryWebhookNotFound {
+		t.Errorf("Expected the webhook to be deleted with its saved query, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var (
+	ErrScheduledActionNotFound = errors.New("scheduled action not found")
+	ErrScheduleInPast          = errors.New("scheduled time must be in the future")
+	ErrInvalidScheduledAction  = errors.New("a scheduled action needs a comment or a valid status")
+)
+
+// ScheduleAction defers a comment or status change on a task until action.RunAt
+func (s *TaskService) ScheduleAction(taskID uint, action *models.ScheduledAction) (*models.ScheduledAction, error) {
+	action.TaskID = taskID
+	action.Content = strings.TrimSpace(action.Content)
+
+	switch action.Kind {
+	case models.ScheduledActionComment:
+		if action.Content == "" {
+			return nil, ErrInvalidScheduledAction
+		}
+		action.Status = ""
+	case models.ScheduledActionStatus:
+		if !isKanbanStatus(action.Status) {
+			return nil, ErrInvalidScheduledAction
+		}
+		action.Content = ""
+	default:
+		return nil, ErrInvalidScheduledAction
+	}
+	if !action.RunAt.After(time.Now()) {
+		return nil, ErrScheduleInPast
+	}
+
+	if _, err := s.repo.GetByID(taskID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateScheduledAction(action); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+// GetScheduledActions returns a task's pending scheduled actions, soonest first
+func (s *TaskService) GetScheduledActions(taskID uint) ([]*models.ScheduledAction, error) {
+	return s.repo.GetScheduledActions(taskID)
+}
+
+// CancelScheduledAction removes a pending scheduled action from a task
+func (s *TaskService) CancelScheduledAction(taskID, actionID uint) error {
+	deleted, err := s.repo.DeleteScheduledAction(taskID, actionID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrScheduledActionNotFound
+	}
+	return nil
+}
+
+// RunScheduledActions runs the actions that are due. It is run by the job
+// runner. An action is removed once it has run, been skipped because a reply
+// arrived, or failed, so a failing action is reported once rather than retried.
+func (s *TaskService) RunScheduledActions(now time.Time) error {
+	actions, err := s.repo.GetDueScheduledActions(now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, action := range actions {
+		if err := s.runScheduledAction(action); err != nil {
+			errs = append(errs, fmt.Errorf("scheduled action %d on task %d: %w", action.ID, action.TaskID, err))
+		}
+		if _, err := s.repo.DeleteScheduledAction(action.TaskID, action.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *TaskService) runScheduledAction(action *models.ScheduledAction) error {
+	task, err := s.repo.GetByID(action.TaskID)
+	if err != nil {
+		return err
+	}
+
+	if action.UnlessReply && hasReplySince(task, action.CreatedAt) {
+		log.Printf("Skipping scheduled %s on task %d: a reply arrived", action.Kind, task.ID)
+		return nil
+	}
+
+	switch action.Kind {
+	case models.ScheduledActionComment:
+		return s.AddComment(task.ID, &models.Comment{Content: action.Content, IsPrivate: true})
+	case models.ScheduledActionStatus:
+		if task.Status == action.Status {
+			return nil
+		}
+		task.Status = action.Status
+		return s.UpdateTask(task)
+	}
+	return ErrInvalidScheduledAction
+}
+
+// hasReplySince reports whether an email reply was added to the task after since
+func hasReplySince(task *models.Task, since time.Time) bool {
+	for _, comment := range task.Comments {
+		if comment.FromEmail != "" && comment.CreatedAt.After(since) {
+			return true
+		}
+	}
+	return false
+}
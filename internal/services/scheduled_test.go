@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_RunScheduledActions(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	task, _ := service.CreateTask("Waiting on customer")
+	replied, _ := service.CreateTask("Customer replied")
+
+	runAt := time.Now().Add(time.Hour)
+	schedule := func(taskID uint, action *models.ScheduledAction) {
+		action.RunAt = runAt
+		if _, err := service.ScheduleAction(taskID, action); err != nil {
+			t.Fatalf("Failed to schedule action: %v", err)
+		}
+	}
+	schedule(task.ID, &models.ScheduledAction{Kind: models.ScheduledActionComment, Content: "Following up"})
+	schedule(task.ID, &models.ScheduledAction{Kind: models.ScheduledActionStatus, Status: models.TaskStatusClosed, UnlessReply: true})
+	schedule(replied.ID, &models.ScheduledAction{Kind: models.ScheduledActionStatus, Status: models.TaskStatusClosed, UnlessReply: true})
+
+	if err := service.AddComment(replied.ID, &models.Comment{Content: "Still broken", FromEmail: "customer@example.com"}); err != nil {
+		t.Fatalf("Failed to add reply: %v", err)
+	}
+
+	// Nothing is due yet
+	if err := service.RunScheduledActions(time.Now()); err != nil {
+		t.Fatalf("RunScheduledActions failed: %v", err)
+	}
+	if pending, _ := service.GetScheduledActions(task.ID); len(pending) != 2 {
+		t.Fatalf("Expected 2 pending actions, got %d", len(pending))
+	}
+
+	if err := service.RunScheduledActions(runAt.Add(time.Minute)); err != nil {
+		t.Fatalf("RunScheduledActions failed: %v", err)
+	}
+
+	updated, _ := service.GetTask(task.ID)
+	if updated.Status != models.TaskStatusClosed {
+		t.Errorf("Expected task to be closed, got %s", updated.Status)
+	}
+	if len(updated.Comments) != 1 || updated.Comments[0].Content != "Following up" {
+		t.Errorf("Expected the scheduled note to be posted, got %+v", updated.Comments)
+	}
+
+	untouched, _ := service.GetTask(replied.ID)
+	if untouched.Status != models.TaskStatusOpen {
+		t.Errorf("Expected the replied task to stay open, got %s", untouched.Status)
+	}
+
+	for _, id := range []uint{task.ID, replied.ID} {
+		if pending, _ := service.GetScheduledActions(id); len(pending) != 0 {
+			t.Errorf("Expected no pending actions on task %d, got %d", id, len(pending))
+		}
+	}
+}
+
+func TestTaskService_ScheduleAction_Validation(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+	task, _ := service.CreateTask("Task")
+	later := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		action models.ScheduledAction
+		want   error
+	}{
+		{"empty comment", models.ScheduledAction{Kind: models.ScheduledActionComment, RunAt: later}, ErrInvalidScheduledAction},
+		{"unknown status", models.ScheduledAction{Kind: models.ScheduledActionStatus, Status: "archived", RunAt: later}, ErrInvalidScheduledAction},
+		{"unknown kind", models.ScheduledAction{Kind: "email", Content: "hi", RunAt: later}, ErrInvalidScheduledAction},
+		{"in the past", models.ScheduledAction{Kind: models.ScheduledActionComment, Content: "hi", RunAt: time.Now().Add(-time.Hour)}, ErrScheduleInPast},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.ScheduleAction(task.ID, &tt.action); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := service.CancelScheduledAction(task.ID, 99); !errors.Is(err, ErrScheduledActionNotFound) {
+		t.Errorf("Expected ErrScheduledActionNotFound, got %v", err)
+	}
+}
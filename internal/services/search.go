@@ -0,0 +1,234 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/searchquery"
+)
+
+// Fields a task search can look in
+const (
+	SearchInName        = "name"
+	SearchInDescription = "description"
+	SearchInComments    = "comments"
+	SearchInTimeEntries = "time_entries" // time entry descriptions
+)
+
+// SearchFields are the fields a task search looks in by default: all of them
+var SearchFields = []string{SearchInName, SearchInDescription, SearchInComments, SearchInTimeEntries}
+
+var (
+	// ErrInvalidSearchField is returned for a search scope that is not one of SearchFields
+	ErrInvalidSearchField = errors.New("invalid search field")
+	// ErrInvalidSearch is returned for a search that does not parse
+	ErrInvalidSearch = errors.New("invalid search")
+)
+
+// searchSnippetContext is how many runes of text a snippet keeps either side
+// of the first match
+const searchSnippetContext = 40
+
+// SearchHighlight marks a match in a snippet, as rune offsets into its text
+// with End exclusive
+type SearchHighlight struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SearchSnippet is the part of a field around a search match
+type SearchSnippet struct {
+	Text       string            `json:"text"`
+	Highlights []SearchHighlight `json:"highlights"`
+}
+
+// HighlightMatches returns text with every case-insensitive match of the terms
+// highlighted, or nil when text matches none of them. Whitespace is collapsed.
+func HighlightMatches(text string, terms ...string) *SearchSnippet {
+	text = strings.Join(strings.Fields(text), " ")
+	haystack := lowerRunes(text)
+
+	var highlights []SearchHighlight
+	for _, term := range terms {
+		needle := lowerRunes(strings.Join(strings.Fields(term), " "))
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(haystack); i++ {
+			if runesEqual(haystack[i:i+len(needle)], needle) {
+				highlights = append(highlights, SearchHighlight{Start: i, End: i + len(needle)})
+				i += len(needle) - 1
+			}
+		}
+	}
+	if len(highlights) == 0 {
+		return nil
+	}
+
+	// Order the matches of all terms and merge those that overlap
+	slices.SortFunc(highlights, func(a, b SearchHighlight) int { return a.Start - b.Start })
+	merged := highlights[:1]
+	for _, h := range highlights[1:] {
+		last := &merged[len(merged)-1]
+		if h.Start < last.End {
+			last.End = max(last.End, h.End)
+			continue
+		}
+		merged = append(merged, h)
+	}
+	return &SearchSnippet{Text: text, Highlights: merged}
+}
+
+// MatchSnippet is HighlightMatches shortened to the text around the first
+// match, with cut ends marked by "…"
+func MatchSnippet(text string, terms ...string) *SearchSnippet {
+	full := HighlightMatches(text, terms...)
+	if full == nil {
+		return nil
+	}
+	runes := []rune(full.Text)
+	first := full.Highlights[0]
+	start := max(first.Start-searchSnippetContext, 0)
+	end := min(first.End+searchSnippetContext, len(runes))
+
+	snippet := &SearchSnippet{Text: string(runes[start:end])}
+	offset := -start
+	if start > 0 {
+		snippet.Text = "…" + snippet.Text
+		offset++
+	}
+	if end < len(runes) {
+		snippet.Text += "…"
+	}
+	for _, h := range full.Highlights {
+		if h.End > end {
+			break
+		}
+		snippet.Highlights = append(snippet.Highlights, SearchHighlight{Start: h.Start + offset, End: h.End + offset})
+	}
+	return snippet
+}
+
+// SearchComments returns the comments containing query, ignoring case
+func (s *TaskService) SearchComments(query string) ([]*models.Comment, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+	return s.repo.SearchComments(query)
+}
+
+// SearchTaskIDs returns the IDs of the tasks where query appears, ignoring
+// case, in any of the fields in; no fields means every one of SearchFields
+func (s *TaskService) SearchTaskIDs(query string, in []string) (map[uint]bool, error) {
+	if len(in) == 0 {
+		in = SearchFields
+	}
+	for _, field := range in {
+		if !slices.Contains(SearchFields, field) {
+			return nil, fmt.Errorf("%w %q: use %s", ErrInvalidSearchField, field, strings.Join(SearchFields, ", "))
+		}
+	}
+
+	ids, err := s.repo.SearchTaskIDs(query, in)
+	if err != nil {
+		return nil, err
+	}
+	matches := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		matches[id] = true
+	}
+	return matches, nil
+}
+
+// TaskSearch is a parsed search (see package searchquery) with the tasks
+// matching its text looked up
+type TaskSearch struct {
+	Query *searchquery.Query
+
+	in      []string      // fields the text is searched in; empty means all
+	include map[uint]bool // tasks containing every term; nil when there are none
+	exclude map[uint]bool // tasks containing an excluded term
+}
+
+// NewTaskSearch parses a search and looks up the tasks containing its words and
+// phrases, in the fields in unless the search has its own in:. Errors in the
+// search wrap ErrInvalidSearch.
+func (s *TaskService) NewTaskSearch(input string, in []string) (*TaskSearch, error) {
+	query, err := searchquery.Parse(input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSearch, err)
+	}
+	if len(query.In) > 0 {
+		in = query.In
+	}
+
+	search := &TaskSearch{Query: query, in: in, exclude: make(map[uint]bool)}
+	lookup := func(term string) (map[uint]bool, error) {
+		ids, err := s.SearchTaskIDs(term, in)
+		if errors.Is(err, ErrInvalidSearchField) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSearch, err)
+		}
+		return ids, err
+	}
+
+	for _, term := range query.Terms {
+		ids, err := lookup(term)
+		if err != nil {
+			return nil, err
+		}
+		if search.include == nil {
+			search.include = ids
+			continue
+		}
+		for id := range search.include {
+			if !ids[id] {
+				delete(search.include, id)
+			}
+		}
+	}
+	for _, term := range query.ExcludeTerms {
+		ids, err := lookup(term)
+		if err != nil {
+			return nil, err
+		}
+		for id := range ids {
+			search.exclude[id] = true
+		}
+	}
+	return search, nil
+}
+
+// Matches reports whether a task matches the search
+func (ts *TaskSearch) Matches(task *models.Task) bool {
+	if ts.include != nil && !ts.include[task.ID] {
+		return false
+	}
+	return !ts.exclude[task.ID] && ts.Query.MatchesFilters(task)
+}
+
+// SearchesIn reports whether the search looks for text in a field
+func (ts *TaskSearch) SearchesIn(field string) bool {
+	return len(ts.in) == 0 || slices.Contains(ts.in, field)
+}
+
+// lowerRunes lowercases s rune by rune, so offsets match the original
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
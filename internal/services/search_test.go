@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestMatchSnippet(t *testing.T) {
+	if MatchSnippet("Reset password", "printer") != nil {
+		t.Error("Expected no snippet without a match")
+	}
+	if MatchSnippet("Reset password", "  ") != nil {
+		t.Error("Expected no snippet for a blank query")
+	}
+
+	snippet := MatchSnippet("Printer  offline, printer\njammed", "PRINTER")
+	if snippet == nil || snippet.Text != "Printer offline, printer jammed" {
+		t.Fatalf("Unexpected snippet %+v", snippet)
+	}
+	want := []SearchHighlight{{Start: 0, End: 7}, {Start: 17, End: 24}}
+	if len(snippet.Highlights) != 2 || snippet.Highlights[0] != want[0] || snippet.Highlights[1] != want[1] {
+		t.Errorf("Expected highlights %v, got %v", want, snippet.Highlights)
+	}
+
+	long := strings.Repeat("lorem ipsum ", 10) + "the café printer is down " + strings.Repeat("dolor sit ", 10)
+	snippet = MatchSnippet(long, "café")
+	if !strings.HasPrefix(snippet.Text, "…") || !strings.HasSuffix(snippet.Text, "…") {
+		t.Errorf("Expected both ends to be cut, got %q", snippet.Text)
+	}
+	h := snippet.Highlights[0]
+	if got := string([]rune(snippet.Text)[h.Start:h.End]); got != "café" {
+		t.Errorf("Expected the highlight to cover the match, got %q", got)
+	}
+
+	if full := HighlightMatches(long, "café"); full.Text != strings.Join(strings.Fields(long), " ") {
+		t.Error("Expected HighlightMatches to keep the whole text")
+	}
+}
+
+func TestTaskService_SearchComments(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	task, err := service.CreateTask("Printer offline")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	for _, content := range []string{"Replaced the TONER", "100% done", "Called vendor"} {
+		if err := service.AddComment(task.ID, &models.Comment{Content: content}); err != nil {
+			t.Fatalf("Failed to add comment: %v", err)
+		}
+	}
+
+	comments, err := service.SearchComments("toner")
+	if err != nil {
+		t.Fatalf("Failed to search comments: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Content != "Replaced the TONER" {
+		t.Errorf("Expected the toner comment, got %v", comments)
+	}
+
+	// LIKE wildcards in the query match literally
+	comments, _ = service.SearchComments("%")
+	if len(comments) != 1 || comments[0].Content != "100% done" {
+		t.Errorf("Expected only the comment containing %%, got %v", comments)
+	}
+}
+
+func TestTaskService_SearchTaskIDs(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	named, _ := service.CreateTask("Toner order")
+	commented, _ := service.CreateTask("Printer offline")
+	service.AddComment(commented.ID, &models.Comment{Content: "Swapped the toner"})
+	logged, _ := service.CreateTask("Office visit")
+	service.AddTimeEntry(logged.ID, &models.TimeEntry{Duration: 15, Description: "Checked TONER levels"})
+	service.CreateTask("Reset password")
+
+	matches, err := service.SearchTaskIDs("toner", nil)
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+	if len(matches) != 3 || !matches[named.ID] || !matches[commented.ID] || !matches[logged.ID] {
+		t.Errorf("Expected every field to be searched by default, got %v", matches)
+	}
+
+	matches, _ = service.SearchTaskIDs("toner", []string{SearchInComments, SearchInTimeEntries})
+	if len(matches) != 2 || matches[named.ID] {
+		t.Errorf("Expected only the comment and time entry matches, got %v", matches)
+	}
+
+	if _, err := service.SearchTaskIDs("toner", []string{"subtasks"}); !errors.Is(err, ErrInvalidSearchField) {
+		t.Errorf("Expected ErrInvalidSearchField, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+var (
+	ErrInvalidInstanceName = errors.New("instance name is required")
+	ErrInvalidPrimaryColor = errors.New("primary color must be a hex color such as #2563eb")
+	ErrInvalidLogoURL      = errors.New("logo URL must be an http(s) URL or an absolute path")
+)
+
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// SettingsService manages instance-wide settings such as branding
+type SettingsService struct {
+	repo *repository.SettingsRepository
+
+	mu       sync.RWMutex
+	branding *models.BrandingSettings
+}
+
+// NewSettingsService creates a new settings service
+func NewSettingsService(repo *repository.SettingsRepository) *SettingsService {
+	return &SettingsService{repo: repo}
+}
+
+// GetBranding returns the current branding settings, falling back to the defaults.
+// The result is cached, so it is cheap to call on every page render.
+func (s *SettingsService) GetBranding() *models.BrandingSettings {
+	s.mu.RLock()
+	cached := s.branding
+	s.mu.RUnlock()
+	if cached != nil {
+		result := *cached
+		return &result
+	}
+
+	branding, err := s.repo.GetBranding()
+	if err != nil {
+		log.Printf("Failed to load branding settings: %v", err)
+		return models.DefaultBrandingSettings()
+	}
+	if branding == nil {
+		branding = models.DefaultBrandingSettings()
+	}
+
+	s.mu.Lock()
+	s.branding = branding
+	s.mu.Unlock()
+
+	result := *branding
+	return &result
+}
+
+// UpdateBranding validates and saves new branding settings
+func (s *SettingsService) UpdateBranding(settings *models.BrandingSettings) (*models.BrandingSettings, error) {
+	settings.InstanceName = strings.TrimSpace(settings.InstanceName)
+	settings.Tagline = strings.TrimSpace(settings.Tagline)
+	settings.LogoURL = strings.TrimSpace(settings.LogoURL)
+	settings.PrimaryColor = strings.TrimSpace(settings.PrimaryColor)
+	settings.FooterText = strings.TrimSpace(settings.FooterText)
+
+	if settings.InstanceName == "" {
+		return nil, ErrInvalidInstanceName
+	}
+	if settings.PrimaryColor == "" {
+		settings.PrimaryColor = models.DefaultBrandingSettings().PrimaryColor
+	}
+	if !hexColorPattern.MatchString(settings.PrimaryColor) {
+		return nil, ErrInvalidPrimaryColor
+	}
+	if settings.LogoURL != "" && !strings.HasPrefix(settings.LogoURL, "https://") &&
+		!strings.HasPrefix(settings.LogoURL, "http://") && !strings.HasPrefix(settings.LogoURL, "/") {
+		return nil, ErrInvalidLogoURL
+	}
+
+	settings.UpdatedAt = time.Now()
+	if err := s.repo.SaveBranding(settings); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	saved := *settings
+	s.branding = &saved
+	s.mu.Unlock()
+
+	return settings, nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestSettingsService_Branding(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewSettingsService(repository.NewSettingsRepository(db))
+
+	branding := service.GetBranding()
+	if branding.InstanceName != "JATS" {
+		t.Errorf("Expected default instance name JATS, got %s", branding.InstanceName)
+	}
+
+	_, err := service.UpdateBranding(&models.BrandingSettings{InstanceName: "Acme Helpdesk", PrimaryColor: "blue"})
+	if err != ErrInvalidPrimaryColor {
+		t.Errorf("Expected ErrInvalidPrimaryColor, got %v", err)
+	}
+
+	_, err = service.UpdateBranding(&models.BrandingSettings{InstanceName: "  "})
+	if err != ErrInvalidInstanceName {
+		t.Errorf("Expected ErrInvalidInstanceName, got %v", err)
+	}
+
+	_, err = service.UpdateBranding(&models.BrandingSettings{
+		InstanceName: "Acme Helpdesk",
+		PrimaryColor: "#ff6600",
+		LogoURL:      "https://example.com/logo.png",
+	})
+	if err != nil {
+		t.Fatalf("Failed to update branding: %v", err)
+	}
+
+	// A fresh service must read the persisted settings rather than the cache
+	reloaded := NewSettingsService(repository.NewSettingsRepository(db)).GetBranding()
+	if reloaded.InstanceName != "Acme Helpdesk" || reloaded.PrimaryColor != "#ff6600" {
+		t.Errorf("Branding was not persisted: %+v", reloaded)
+	}
+}
+
+func TestSettingsService_CannedResponses(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewSettingsService(repository.NewSettingsRepository(db))
+	taskService := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	if _, err := service.SaveCannedResponse(&models.CannedResponse{Name: "not valid", Content: "x"}); err != ErrInvalidCannedResponseName {
+		t.Errorf("Expected ErrInvalidCannedResponseName, got %v", err)
+	}
+	if _, err := service.SaveCannedResponse(&models.CannedResponse{Name: "thanks", Content: "  "}); err != ErrEmptyCannedResponse {
+		t.Errorf("Expected ErrEmptyCannedResponse, got %v", err)
+	}
+
+	saved, err := service.SaveCannedResponse(&models.CannedResponse{
+		Name:    "Thanks",
+		Content: "Thanks! Tracking as #{{task.id}} ({{ task.name }}) on {{instance.name}}. {{task.unknown}}",
+	})
+	if err != nil {
+		t.Fatalf("Failed to save canned response: %v", err)
+	}
+	if saved.Name != "thanks" {
+		t.Errorf("Expected name to be lowercased, got %s", saved.Name)
+	}
+
+	if _, err := service.SaveCannedResponse(&models.CannedResponse{Name: "thanks", Content: "dup"}); err != ErrDuplicateCannedResponse {
+		t.Errorf("Expected ErrDuplicateCannedResponse, got %v", err)
+	}
+
+	task, err := taskService.CreateTask("Printer broken")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	rendered, err := service.RenderCannedResponse("THANKS", task)
+	if err != nil {
+		t.Fatalf("Failed to render canned response: %v", err)
+	}
+	expected := fmt.Sprintf("Thanks! Tracking as #%d (Printer broken) on JATS. {{task.unknown}}", task.ID)
+	if rendered != expected {
+		t.Errorf("Expected %q, got %q", expected, rendered)
+	}
+
+	if _, err := service.RenderCannedResponse("missing", task); err != ErrCannedResponseNotFound {
+		t.Errorf("Expected ErrCannedResponseNotFound, got %v", err)
+	}
+
+	if err := service.DeleteCannedResponse(saved.ID); err != nil {
+		t.Fatalf("Failed to delete canned response: %v", err)
+	}
+	responses, err := service.ListCannedResponses()
+	if err != nil {
+		t.Fatalf("Failed to list canned responses: %v", err)
+	}
+	if len(responses) != 0 {
+		t.Errorf("Expected no canned responses after delete, got %d", len(responses))
+	}
+}
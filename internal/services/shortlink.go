@@ -0,0 +1,85 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// ErrShortCodeNotFound is returned for a short link code no task has
+var ErrShortCodeNotFound = errors.New("short link not found")
+
+// shortCodeAlphabet leaves out letters easily mistaken for digits when a code
+// is typed in from a printed label
+const shortCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+const shortCodeLength = 8
+
+// TaskShortPath is the path of a task's short link, which opens the task in the
+// app after signing in. It is not under /t/, where tenants live in path mode.
+func TaskShortPath(code string) string {
+	return "/s/" + code
+}
+
+// TaskShortCode returns the stable short link code of a task, assigning one the
+// first time it is asked for
+func (s *TaskService) TaskShortCode(taskID uint) (string, error) {
+	task, err := s.repo.GetByID(taskID)
+	if err != nil {
+		return "", err
+	}
+	if task.ShortCode != "" {
+		return task.ShortCode, nil
+	}
+
+	for {
+		code, err := generateShortCode()
+		if err != nil {
+			return "", err
+		}
+		if _, err := s.repo.GetByShortCode(code); err == nil {
+			continue // taken by another task
+		}
+		set, err := s.repo.SetShortCode(taskID, code)
+		if err != nil {
+			return "", err
+		}
+		if set {
+			return code, nil
+		}
+		// Assigned by a concurrent request in the meantime
+		task, err := s.repo.GetByID(taskID)
+		if err != nil {
+			return "", err
+		}
+		return task.ShortCode, nil
+	}
+}
+
+// GetTaskByShortCode returns the task a short link code belongs to. Codes are
+// matched case-insensitively since they may be typed in by hand.
+func (s *TaskService) GetTaskByShortCode(code string) (*models.Task, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return nil, ErrShortCodeNotFound
+	}
+	task, err := s.repo.GetByShortCode(code)
+	if err != nil {
+		return nil, ErrShortCodeNotFound
+	}
+	return task, nil
+}
+
+func generateShortCode() (string, error) {
+	bytes := make([]byte, shortCodeLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	code := make([]byte, shortCodeLength)
+	for i, b := range bytes {
+		code[i] = shortCodeAlphabet[int(b)%len(shortCodeAlphabet)]
+	}
+	return string(code), nil
+}
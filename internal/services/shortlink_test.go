@@ -0,0 +1,45 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_ShortCodes(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	first, _ := service.CreateTask("Generator service")
+	second, _ := service.CreateTask("Boiler service")
+
+	code, err := service.TaskShortCode(first.ID)
+	if err != nil {
+		t.Fatalf("TaskShortCode failed: %v", err)
+	}
+	if len(code) != shortCodeLength {
+		t.Errorf("Expected a %d character code, got %q", shortCodeLength, code)
+	}
+	if again, _ := service.TaskShortCode(first.ID); again != code {
+		t.Errorf("Expected the code to be stable, got %q then %q", code, again)
+	}
+	if other, _ := service.TaskShortCode(second.ID); other == code {
+		t.Errorf("Expected tasks to get different codes, both got %q", code)
+	}
+	if _, err := service.TaskShortCode(9999); err == nil {
+		t.Errorf("Expected an error for a missing task")
+	}
+
+	task, err := service.GetTaskByShortCode(" " + strings.ToUpper(code) + " ")
+	if err != nil || task.ID != first.ID {
+		t.Errorf("Expected the code to find task %d ignoring case, got %+v, %v", first.ID, task, err)
+	}
+	for _, unknown := range []string{"", "zzzzzzzz"} {
+		if _, err := service.GetTaskByShortCode(unknown); !errors.Is(err, ErrShortCodeNotFound) {
+			t.Errorf("GetTaskByShortCode(%q): expected ErrShortCodeNotFound, got %v", unknown, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+//go:embed templates/site/*.tmpl
+var siteTemplateFiles embed.FS
+
+var siteTemplates = template.Must(template.New("site").Funcs(template.FuncMap{
+	"minutes": func(minutes int) string {
+		return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+	},
+}).ParseFS(siteTemplateFiles, "templates/site/*.tmpl"))
+
+// unsafeFileChars matches the characters not kept in exported attachment names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SiteExport summarizes a static HTML archive written by ExportSite
+type SiteExport struct {
+	Tasks              int
+	Attachments        int
+	MissingAttachments int // attachments whose file was not found in storage
+}
+
+// siteProject is a project's section of the archive index
+type siteProject struct {
+	Name  string
+	Tasks []*models.Task
+}
+
+// siteAttachment is an attachment as linked from a task page; Path is empty
+// when the file was missing
+type siteAttachment struct {
+	Name        string
+	ContentType string
+	Path        string
+}
+
+// ExportSite writes every task, with its comments, attachments, time entries
+// and status history, into dir as a static HTML archive that can be browsed
+// offline, without JATS: index.html lists the tasks by project, linking to a
+// page per task under tasks/, and attachment files are copied from storage
+// into attachments/. Archived tasks are not included.
+func (s *TaskService) ExportSite(dir string, storage *StorageService) (*SiteExport, error) {
+	tasks, err := s.repo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	projects, err := s.GetProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	for _, sub := range []string{"tasks", "attachments"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create export directory: %w", err)
+		}
+	}
+
+	projectNames := make(map[uint]string, len(projects))
+	sections := make(map[uint]*siteProject, len(projects))
+	index := make([]*siteProject, 0, len(projects))
+	for _, project := range projects {
+		projectNames[project.ID] = project.Name
+		sections[project.ID] = &siteProject{Name: project.Name}
+		index = append(index, sections[project.ID])
+	}
+
+	summary := &SiteExport{}
+	for _, listed := range tasks {
+		task, err := s.GetTask(listed.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load task %d: %w", listed.ID, err)
+		}
+		history, err := s.GetStatusHistory(task.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load status history of task %d: %w", task.ID, err)
+		}
+
+		attachments, err := exportAttachments(task, dir, storage, summary)
+		if err != nil {
+			return nil, err
+		}
+
+		var projectName string
+		if task.ProjectID != nil {
+			projectName = projectNames[*task.ProjectID]
+			if section := sections[*task.ProjectID]; section != nil {
+				section.Tasks = append(section.Tasks, task)
+			}
+		}
+		sort.SliceStable(task.Comments, func(i, j int) bool {
+			return task.Comments[i].CreatedAt.Before(task.Comments[j].CreatedAt)
+		})
+
+		page := filepath.Join(dir, "tasks", fmt.Sprintf("%d.html", task.ID))
+		err = writeSitePage(page, "task", map[string]interface{}{
+			"Task":        task,
+			"Project":     projectName,
+			"Comments":    task.Comments,
+			"Attachments": attachments,
+			"History":     history,
+		})
+		if err != nil {
+			return nil, err
+		}
+		summary.Tasks++
+	}
+
+	// Projects without tasks are left out of the index, tasks newest first
+	var listed []*siteProject
+	for _, section := range index {
+		if len(section.Tasks) == 0 {
+			continue
+		}
+		sort.SliceStable(section.Tasks, func(i, j int) bool {
+			return section.Tasks[i].ID > section.Tasks[j].ID
+		})
+		listed = append(listed, section)
+	}
+	err = writeSitePage(filepath.Join(dir, "index.html"), "index", map[string]interface{}{
+		"Tasks":      tasks,
+		"Projects":   listed,
+		"ExportedAt": time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// exportAttachments copies the files attached to a task and its comments
+// into the archive, returning them as the task page links them
+func exportAttachments(task *models.Task, dir string, storage *StorageService, summary *SiteExport) ([]siteAttachment, error) {
+	all := append([]models.Attachment{}, task.Attachments...)
+	for _, comment := range task.Comments {
+		all = append(all, comment.Attachments...)
+	}
+
+	seen := make(map[uint]bool)
+	var attachments []siteAttachment
+	for _, attachment := range all {
+		if seen[attachment.ID] {
+			continue
+		}
+		seen[attachment.ID] = true
+
+		exported := siteAttachment{Name: attachment.OriginalName, ContentType: attachment.ContentType}
+		data, err := storage.GetAttachment(attachment.FilePath)
+		if err != nil {
+			summary.MissingAttachments++
+			attachments = append(attachments, exported)
+			continue
+		}
+		exported.Path = fmt.Sprintf("attachments/%d-%s", attachment.ID, unsafeFileChars.ReplaceAllString(filepath.Base(attachment.OriginalName), "_"))
+		if err := os.WriteFile(filepath.Join(dir, exported.Path), data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write attachment %d: %w", attachment.ID, err)
+		}
+		summary.Attachments++
+		attachments = append(attachments, exported)
+	}
+	return attachments, nil
+}
+
+// writeSitePage renders one page of the archive to path
+func writeSitePage(path, name string, data interface{}) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := siteTemplates.ExecuteTemplate(file, name, data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to render %s: %w", path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_ExportSite(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+	storage := NewStorageService(t.TempDir())
+
+	task, err := service.CreateTask("Replace <router>")
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task.Description = "Old one <b>died</b>"
+	if err := service.UpdateTask(task); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if err := service.AddComment(task.ID, &models.Comment{Content: "Ordered a new one"}); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	attachment, err := storage.SaveAttachment("../invoice 1.pdf", "application/pdf", []byte("%PDF"))
+	if err != nil {
+		t.Fatalf("SaveAttachment failed: %v", err)
+	}
+	attachment.TaskID = &task.ID
+	if err := db.Create(attachment).Error; err != nil {
+		t.Fatalf("Failed to store attachment: %v", err)
+	}
+	if err := db.Create(&models.Attachment{TaskID: &task.ID, FileName: "gone", OriginalName: "gone.txt", FilePath: "gone"}).Error; err != nil {
+		t.Fatalf("Failed to store attachment: %v", err)
+	}
+
+	dir := t.TempDir()
+	summary, err := service.ExportSite(dir, storage)
+	if err != nil {
+		t.Fatalf("ExportSite failed: %v", err)
+	}
+	if summary.Tasks != 1 || summary.Attachments != 1 || summary.MissingAttachments != 1 {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if !strings.Contains(string(index), `href="tasks/1.html"`) || !strings.Contains(string(index), "Replace &lt;router&gt;") {
+		t.Errorf("Expected the index to link the escaped task, got:\n%s", index)
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "tasks", "1.html"))
+	if err != nil {
+		t.Fatalf("Failed to read task page: %v", err)
+	}
+	for _, want := range []string{"Old one &lt;b&gt;died&lt;/b&gt;", "Ordered a new one", `href="../attachments/1-invoice_1.pdf"`, "gone.txt (file missing)"} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("Expected the task page to contain %q, got:\n%s", want, page)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "attachments", "1-invoice_1.pdf")); err != nil || string(data) != "%PDF" {
+		t.Errorf("Expected the attachment copied, got %q, %v", data, err)
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+var ErrInvalidSize = errors.New("size must be XS, S, M, L, XL or a number of story points up to 100")
+
+// maxSizePoints bounds story points so a typo such as 500 is caught
+const maxSizePoints = 100
+
+// ParseTaskSize reads a size as typed, such as "m" or "5", returning the
+// stored form. An empty size leaves the task unsized.
+func ParseTaskSize(value string) (models.TaskSize, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return "", nil
+	}
+	for _, size := range models.TaskSizes {
+		if models.TaskSize(value) == size {
+			return size, nil
+		}
+	}
+	points, err := strconv.Atoi(value)
+	if err != nil || points < 1 || points > maxSizePoints {
+		return "", ErrInvalidSize
+	}
+	return models.TaskSize(strconv.Itoa(points)), nil
+}
+
+// VelocityWeek is the sized work completed in one week
+type VelocityWeek struct {
+	WeekStart    string `json:"week_start"` // YYYY-MM-DD, a Monday
+	Points       int    `json:"points"`
+	Tasks        int    `json:"tasks"`         // tasks completed, sized or not
+	UnsizedTasks int    `json:"unsized_tasks"` // completed tasks without a size, which add no points
+}
+
+// Velocity is the story points completed per week, oldest week first
+type Velocity struct {
+	Weeks         []VelocityWeek `json:"weeks"`
+	AveragePoints float64        `json:"average_points"` // over the full weeks, leaving out the current one
+}
+
+// SizeCount is how much open work there is of one size
+type SizeCount struct {
+	Size   models.TaskSize `json:"size"`
+	Tasks  int             `json:"tasks"`
+	Points int             `json:"points"`
+}
+
+// SizeBreakdown is the open work of a set of tasks by size
+type SizeBreakdown struct {
+	Sizes        []SizeCount `json:"sizes"` // t-shirt sizes smallest first, then point sizes ascending
+	OpenPoints   int         `json:"open_points"`
+	UnsizedTasks int         `json:"unsized_tasks"`
+}
+
+// GetVelocity returns the points completed in each of the last weeks weeks,
+// the current one included, for the tasks of query or every task when nil
+func (s *TaskService) GetVelocity(query *models.SavedQuery, weeks int, now time.Time) (*Velocity, error) {
+	tasks, err := s.reportTasks(query)
+	if err != nil {
+		return nil, err
+	}
+	return velocityOf(tasks, weeks, now), nil
+}
+
+// GetSizeBreakdown returns the open and in-progress work of the tasks of
+// query, or every task when nil, by size
+func (s *TaskService) GetSizeBreakdown(query *models.SavedQuery) (*SizeBreakdown, error) {
+	tasks, err := s.reportTasks(query)
+	if err != nil {
+		return nil, err
+	}
+	return sizeBreakdownOf(tasks), nil
+}
+
+// reportTasks returns the tasks of a saved query, or every task when query is nil
+func (s *TaskService) reportTasks(query *models.SavedQuery) ([]*models.Task, error) {
+	if query != nil {
+		return s.GetTasksBySavedQuery(query)
+	}
+	return s.GetTasks()
+}
+
+func velocityOf(tasks []*models.Task, weeks int, now time.Time) *Velocity {
+	if weeks < 1 {
+		weeks = 1
+	}
+	first := StartOfWeek(now).AddDate(0, 0, -7*(weeks-1))
+	velocity := &Velocity{Weeks: make([]VelocityWeek, weeks)}
+	for i := range velocity.Weeks {
+		velocity.Weeks[i].WeekStart = first.AddDate(0, 0, 7*i).Format("2006-01-02")
+	}
+
+	for _, task := range tasks {
+		if task.ResolvedAt == nil || (task.Status != models.TaskStatusResolved && task.Status != models.TaskStatusClosed) {
+			continue
+		}
+		resolved := task.ResolvedAt.In(now.Location())
+		if resolved.Before(first) || resolved.After(now) {
+			continue
+		}
+		week := &velocity.Weeks[int(StartOfWeek(resolved).Sub(first).Hours()/24+0.5)/7]
+		week.Tasks++
+		if points := task.Size.Points(); points > 0 {
+			week.Points += points
+		} else {
+			week.UnsizedTasks++
+		}
+	}
+
+	if weeks > 1 {
+		total := 0
+		for _, week := range velocity.Weeks[:weeks-1] {
+			total += week.Points
+		}
+		velocity.AveragePoints = float64(total) / float64(weeks-1)
+	}
+	return velocity
+}
+
+func sizeBreakdownOf(tasks []*models.Task) *SizeBreakdown {
+	counts := make(map[models.TaskSize]*SizeCount)
+	breakdown := &SizeBreakdown{Sizes: []SizeCount{}}
+	for _, task := range tasks {
+		if task.Status != models.TaskStatusOpen && task.Status != models.TaskStatusInProgress {
+			continue
+		}
+		points := task.Size.Points()
+		if points == 0 {
+			breakdown.UnsizedTasks++
+			continue
+		}
+		count := counts[task.Size]
+		if count == nil {
+			count = &SizeCount{Size: task.Size}
+			counts[task.Size] = count
+		}
+		count.Tasks++
+		count.Points += points
+		breakdown.OpenPoints += points
+	}
+
+	for _, size := range models.TaskSizes {
+		if count := counts[size]; count != nil {
+			breakdown.Sizes = append(breakdown.Sizes, *count)
+			delete(counts, size)
+		}
+	}
+	for points := 1; points <= maxSizePoints && len(counts) > 0; points++ {
+		size := models.TaskSize(strconv.Itoa(points))
+		if count := counts[size]; count != nil {
+			breakdown.Sizes = append(breakdown.Sizes, *count)
+			delete(counts, size)
+		}
+	}
+	return breakdown
+}
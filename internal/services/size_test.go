@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestParseTaskSize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  models.TaskSize
+		err   bool
+	}{
+		{"", "", false},
+		{" m ", models.TaskSizeM, false},
+		{"xl", models.TaskSizeXL, false},
+		{"5", "5", false},
+		{"05", "5", false},
+		{"0", "", true},
+		{"101", "", true},
+		{"XXL", "", true},
+		{"2.5", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTaskSize(tt.input)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("ParseTaskSize(%q) = %q, %v; want %q, error %v", tt.input, got, err, tt.want, tt.err)
+		}
+	}
+
+	if models.TaskSizeL.Points() != 5 || models.TaskSize("13").Points() != 13 || models.TaskSize("").Points() != 0 {
+		t.Errorf("Unexpected points for sizes")
+	}
+}
+
+func TestVelocityAndSizeBreakdown(t *testing.T) {
+	// Wednesday; the week started on Monday the 13th
+	now := time.Date(2025, 10, 15, 12, 0, 0, 0, time.Local)
+	resolved := func(size models.TaskSize, at time.Time) *models.Task {
+		return &models.Task{Status: models.TaskStatusResolved, Size: size, ResolvedAt: &at}
+	}
+	tasks := []*models.Task{
+		resolved(models.TaskSizeM, now.AddDate(0, 0, -1)),               // this week
+		resolved("5", time.Date(2025, 10, 13, 0, 30, 0, 0, time.Local)), // this week, just after Monday midnight
+		resolved(models.TaskSizeXL, now.AddDate(0, 0, -7)),              // last week
+		resolved("", now.AddDate(0, 0, -8)),                             // last week, unsized
+		resolved(models.TaskSizeXS, now.AddDate(0, 0, -30)),             // too old
+		{Status: models.TaskStatusOpen, Size: models.TaskSizeL},
+		{Status: models.TaskStatusInProgress, Size: "3"},
+		{Status: models.TaskStatusOpen, Size: models.TaskSizeL},
+		{Status: models.TaskStatusOpen},
+	}
+
+	velocity := velocityOf(tasks, 3, now)
+	want := []VelocityWeek{
+		{WeekStart: "2025-09-29"},
+		{WeekStart: "2025-10-06", Points: 8, Tasks: 2, UnsizedTasks: 1},
+		{WeekStart: "2025-10-13", Points: 8, Tasks: 2},
+	}
+	if len(velocity.Weeks) != len(want) {
+		t.Fatalf("Expected %d weeks, got %+v", len(want), velocity.Weeks)
+	}
+	for i := range want {
+		if velocity.Weeks[i] != want[i] {
+			t.Errorf("Week %d: expected %+v, got %+v", i, want[i], velocity.Weeks[i])
+		}
+	}
+	if velocity.AveragePoints != 4 {
+		t.Errorf("Expected an average of 4 points over the full weeks, got %v", velocity.AveragePoints)
+	}
+
+	breakdown := sizeBreakdownOf(tasks)
+	wantSizes := []SizeCount{{Size: models.TaskSizeL, Tasks: 2, Points: 10}, {Size: "3", Tasks: 1, Points: 3}}
+	if len(breakdown.Sizes) != len(wantSizes) || breakdown.Sizes[0] != wantSizes[0] || breakdown.Sizes[1] != wantSizes[1] {
+		t.Errorf("Expected %+v, got %+v", wantSizes, breakdown.Sizes)
+	}
+	if breakdown.OpenPoints != 13 || breakdown.UnsizedTasks != 1 {
+		t.Errorf("Expected 13 open points and 1 unsized task, got %+v", breakdown)
+	}
+}
+
+func TestTaskService_UpdateTaskSize(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	task, err := service.CreateTask("Migrate mail server")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	task.Size = "xl"
+	if err := service.UpdateTask(task); err != nil {
+		t.Fatalf("Failed to size task: %v", err)
+	}
+	if saved, _ := service.GetTask(task.ID); saved.Size != models.TaskSizeXL {
+		t.Errorf("Expected size XL, got %q", saved.Size)
+	}
+
+	task.Size = "huge"
+	if err := service.UpdateTask(task); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("Expected ErrInvalidSize, got %v", err)
+	}
+}
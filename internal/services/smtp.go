@@ -1,25 +1,43 @@
 package services
 
 import (
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/hex"
 	"fmt"
+	"mime"
 	"net/mail"
 	"net/smtp"
 	"strings"
+	"time"
 
 	"github.com/soarinferret/jats/internal/config"
 	"github.com/soarinferret/jats/internal/models"
 )
 
 type SMTPService struct {
-	config *config.EmailConfig
+	config    *config.EmailConfig
+	templates *EmailTemplates
 }
 
 func NewSMTPService(config *config.EmailConfig) *SMTPService {
-	return &SMTPService{config: config}
+	return &SMTPService{
+		config:    config,
+		templates: NewEmailTemplates(config.TemplatesDir),
+	}
+}
+
+// Templates returns the email template renderer used by this service
+func (s *SMTPService) Templates() *EmailTemplates {
+	return s.templates
 }
 
 func (s *SMTPService) SendTaskNotification(task *models.Task, subscribers []models.TaskSubscriber, subject, content string) error {
+	return s.SendRenderedNotification(task, subscribers, &RenderedEmail{Subject: subject, Text: content})
+}
+
+// SendRenderedNotification sends a rendered (text and optional HTML) email to task subscribers
+func (s *SMTPService) SendRenderedNotification(task *models.Task, subscribers []models.TaskSubscriber, email *RenderedEmail) error {
 	if len(subscribers) == 0 {
 		return nil
 	}
@@ -30,7 +48,7 @@ func (s *SMTPService) SendTaskNotification(task *models.Task, subscribers []mode
 		recipients = append(recipients, subscriber.Email)
 	}
 
-	return s.sendEmail(recipients, subject, content, task.EmailMessageID)
+	return s.sendEmail(recipients, email, task.EmailMessageID)
 }
 
 func (s *SMTPService) SendTaskUpdate(task *models.Task, subscribers []models.TaskSubscriber, comment *models.Comment) error {
@@ -38,18 +56,47 @@ func (s *SMTPService) SendTaskUpdate(task *models.Task, subscribers []models.Tas
 		return nil
 	}
 
-	subject := fmt.Sprintf("Re: %s", task.Name)
-	content := s.buildTaskUpdateContent(task, comment)
+	email, err := s.templates.Render(EmailTemplateTaskUpdated, EmailTemplateData{
+		Task:    task,
+		Comment: comment,
+		Actor:   comment.FromEmail,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.SendRenderedNotification(task, subscribers, email)
+}
 
-	var recipients []string
-	for _, subscriber := range subscribers {
-		recipients = append(recipients, subscriber.Email)
+// SendMessage sends an email under a new Message-ID, threaded under inReplyTo
+// when it is set, and returns the Message-ID so replies can be matched to it
+func (s *SMTPService) SendMessage(recipients []string, email *RenderedEmail, inReplyTo string) (string, error) {
+	if email.MessageID == "" {
+		email.MessageID = newMessageID(s.config.FromEmail)
+	}
+	if err := s.sendEmail(recipients, email, inReplyTo); err != nil {
+		return "", err
 	}
+	return email.MessageID, nil
+}
 
-	return s.sendEmail(recipients, subject, content, task.EmailMessageID)
+// Sender returns the address emails are sent from
+func (s *SMTPService) Sender() string {
+	return s.config.FromEmail
 }
 
-func (s *SMTPService) sendEmail(recipients []string, subject, content, inReplyTo string) error {
+// newMessageID generates a unique Message-ID in the sender's domain
+func newMessageID(from string) string {
+	domain := "jats.local"
+	if _, host, ok := strings.Cut(from, "@"); ok && host != "" {
+		domain = host
+	}
+	random := make([]byte, 8)
+	rand.Read(random)
+	return fmt.Sprintf("<jats.%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(random), domain)
+}
+
+func (s *SMTPService) sendEmail(recipients []string, email *RenderedEmail, inReplyTo string) error {
 	if s.config.SMTPHost == "" || s.config.FromEmail == "" {
 		return fmt.Errorf("SMTP not configured")
 	}
@@ -62,23 +109,34 @@ func (s *SMTPService) sendEmail(recipients []string, subject, content, inReplyTo
 
 	// Build email message
 	from := mail.Address{Name: s.config.FromName, Address: s.config.FromEmail}
-	msg := s.buildMessage(from, recipients, subject, content, inReplyTo)
+	msg := s.buildMessage(from, recipients, email, inReplyTo)
 
 	// Send email
 	if s.config.SMTPUseTLS {
-		return s.sendWithTLS(auth, recipients, msg)
-	} else {
-		return smtp.SendMail(
-			fmt.Sprintf("%s:%s", s.config.SMTPHost, s.config.SMTPPort),
-			auth,
-			s.config.FromEmail,
-			recipients,
-			[]byte(msg),
-		)
+		return s.sendWithTLS(auth, recipients, msg, email.ReceiptToken)
 	}
+	return s.sendPlain(auth, recipients, msg, email.ReceiptToken)
 }
 
-func (s *SMTPService) sendWithTLS(auth smtp.Auth, recipients []string, msg string) error {
+// sendPlain sends over a plain connection, upgraded with STARTTLS when the
+// server offers it, as smtp.SendMail does
+func (s *SMTPService) sendPlain(auth smtp.Auth, recipients []string, msg, receiptToken string) error {
+	c, err := smtp.Dial(fmt.Sprintf("%s:%s", s.config.SMTPHost, s.config.SMTPPort))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: s.config.SMTPHost}); err != nil {
+			return err
+		}
+	}
+
+	return s.deliver(c, auth, recipients, msg, receiptToken)
+}
+
+func (s *SMTPService) sendWithTLS(auth smtp.Auth, recipients []string, msg, receiptToken string) error {
 	// Connect to the SMTP Server
 	servername := fmt.Sprintf("%s:%s", s.config.SMTPHost, s.config.SMTPPort)
 
@@ -100,21 +158,44 @@ func (s *SMTPService) sendWithTLS(auth smtp.Auth, recipients []string, msg strin
 		return err
 	}
 
+	return s.deliver(c, auth, recipients, msg, receiptToken)
+}
+
+// deliver sends a message over an open connection. With a receipt token, and
+// a server supporting DSN (RFC 3461), it asks for a delivery status
+// notification on success and failure, carrying the token as envelope ID.
+func (s *SMTPService) deliver(c *smtp.Client, auth smtp.Auth, recipients []string, msg, receiptToken string) error {
 	// Auth if configured
 	if auth != nil {
-		if err = c.Auth(auth); err != nil {
+		if err := c.Auth(auth); err != nil {
 			return err
 		}
 	}
 
+	dsn := false
+	if receiptToken != "" {
+		dsn, _ = c.Extension("DSN")
+	}
+
 	// Set sender
-	if err = c.Mail(s.config.FromEmail); err != nil {
+	var err error
+	if dsn {
+		err = smtpCommand(c, 250, "MAIL FROM:<%s> RET=HDRS ENVID=%s", s.config.FromEmail, receiptToken)
+	} else {
+		err = c.Mail(s.config.FromEmail)
+	}
+	if err != nil {
 		return err
 	}
 
 	// Set recipients
 	for _, recipient := range recipients {
-		if err = c.Rcpt(recipient); err != nil {
+		if dsn {
+			err = smtpCommand(c, 25, "RCPT TO:<%s> NOTIFY=SUCCESS,FAILURE", recipient)
+		} else {
+			err = c.Rcpt(recipient)
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -138,49 +219,70 @@ func (s *SMTPService) sendWithTLS(auth smtp.Auth, recipients []string, msg strin
 	return c.Quit()
 }
 
-func (s *SMTPService) buildMessage(from mail.Address, recipients []string, subject, content, inReplyTo string) string {
+// smtpCommand sends a command net/smtp has no method for, such as MAIL FROM
+// with DSN parameters, and checks the reply code
+func smtpCommand(c *smtp.Client, expectCode int, format string, args ...interface{}) error {
+	id, err := c.Text.Cmd(format, args...)
+	if err != nil {
+		return err
+	}
+	c.Text.StartResponse(id)
+	defer c.Text.EndResponse(id)
+	_, _, err = c.Text.ReadResponse(expectCode)
+	return err
+}
+
+func (s *SMTPService) buildMessage(from mail.Address, recipients []string, email *RenderedEmail, inReplyTo string) string {
 	var msg strings.Builder
 
 	// Headers
 	msg.WriteString(fmt.Sprintf("From: %s\r\n", from.String()))
 	msg.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(recipients, ", ")))
-	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject)))
 
+	if email.MessageID != "" {
+		msg.WriteString(fmt.Sprintf("Message-ID: %s\r\n", email.MessageID))
+	}
+	if email.ReceiptToken != "" {
+		// Asks the recipient's mail client for a read receipt (RFC 8098); the
+		// token is echoed back in the headers of delivery reports
+		msg.WriteString(fmt.Sprintf("Disposition-Notification-To: %s\r\n", from.String()))
+		msg.WriteString(fmt.Sprintf("X-JATS-Receipt: %s\r\n", email.ReceiptToken))
+	}
 	if inReplyTo != "" {
 		msg.WriteString(fmt.Sprintf("In-Reply-To: %s\r\n", inReplyTo))
 		msg.WriteString(fmt.Sprintf("References: %s\r\n", inReplyTo))
 	}
 
-	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
-	msg.WriteString("\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
 
-	// Body
-	msg.WriteString(content)
+	if email.HTML == "" {
+		msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		msg.WriteString("\r\n")
 
-	return msg.String()
-}
-
-func (s *SMTPService) buildTaskUpdateContent(task *models.Task, comment *models.Comment) string {
-	var content strings.Builder
-
-	content.WriteString(fmt.Sprintf("Task: %s\n", task.Name))
-	content.WriteString(fmt.Sprintf("Status: %s\n", task.Status))
-
-	if task.Priority != "" {
-		content.WriteString(fmt.Sprintf("Priority: %s\n", task.Priority))
+		// Body
+		msg.WriteString(email.Text)
+		return msg.String()
 	}
 
-	if len(task.Tags) > 0 {
-		content.WriteString(fmt.Sprintf("Tags: %s\n", strings.Join(task.Tags, ", ")))
-	}
+	// Text and HTML alternatives
+	boundary := fmt.Sprintf("jats-%d", time.Now().UnixNano())
+	msg.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q\r\n", boundary))
+	msg.WriteString("\r\n")
 
-	content.WriteString("\n")
+	msg.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(email.Text)
+	msg.WriteString("\r\n")
 
-	if comment != nil {
-		content.WriteString("New Comment:\n")
-		content.WriteString(comment.Content)
-		content.WriteString("\n")
-	}
+	msg.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+	msg.WriteString("Content-Type: text/html; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(email.HTML)
+	msg.WriteString("\r\n")
+
+	msg.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
 
-	return content.String()
+	return msg.String()
 }
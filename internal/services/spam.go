@@ -0,0 +1,224 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+var (
+	ErrQuarantinedEmailNotFound = errors.New("quarantined email not found")
+	ErrReleaseUnavailable       = errors.New("email processing is not enabled on this server")
+)
+
+// SpamVerdict is the outcome of a spam check
+type SpamVerdict string
+
+const (
+	SpamVerdictHam        SpamVerdict = "ham"
+	SpamVerdictQuarantine SpamVerdict = "quarantine"
+	SpamVerdictReject     SpamVerdict = "reject"
+)
+
+// SpamCheckResult holds the spam filter's assessment of a message
+type SpamCheckResult struct {
+	Score   float64     `json:"score"`
+	Action  string      `json:"action"`
+	Symbols []string    `json:"symbols"`
+	Verdict SpamVerdict `json:"verdict"`
+}
+
+// SpamChecker scores raw RFC 822 messages
+type SpamChecker interface {
+	Check(raw []byte, from string) (*SpamCheckResult, error)
+}
+
+// RspamdClient checks messages against an rspamd controller over HTTP
+type RspamdClient struct {
+	baseURL    string
+	password   string
+	httpClient *http.Client
+}
+
+// NewRspamdClient creates a new rspamd client
+func NewRspamdClient(baseURL, password string, timeout time.Duration) *RspamdClient {
+	return &RspamdClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		password:   password,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// rspamdResponse is the subset of the /checkv2 response that JATS uses
+type rspamdResponse struct {
+	Score   float64 `json:"score"`
+	Action  string  `json:"action"`
+	Symbols map[string]struct {
+		Score float64 `json:"score"`
+	} `json:"symbols"`
+}
+
+// Check posts the message to rspamd's /checkv2 endpoint
+func (c *RspamdClient) Check(raw []byte, from string) (*SpamCheckResult, error) {
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/checkv2", bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rspamd request: %w", err)
+	}
+	if from != "" {
+		req.Header.Set("From", from)
+	}
+	if c.password != "" {
+		req.Header.Set("Password", c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rspamd request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rspamd returned status %d", resp.StatusCode)
+	}
+
+	var result rspamdResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode rspamd response: %w", err)
+	}
+
+	// Report the matched rules that actually contributed to the score
+	var symbols []string
+	for name, symbol := range result.Symbols {
+		if symbol.Score > 0 {
+			symbols = append(symbols, name)
+		}
+	}
+	sort.Strings(symbols)
+
+	return &SpamCheckResult{
+		Score:   result.Score,
+		Action:  result.Action,
+		Symbols: symbols,
+	}, nil
+}
+
+// QuarantineReleaser re-processes a quarantined message as regular inbound email
+type QuarantineReleaser interface {
+	ReleaseQuarantined(email *models.QuarantinedEmail) error
+}
+
+// SpamService applies the configured spam thresholds to inbound email and
+// manages the quarantine list
+type SpamService struct {
+	checker         SpamChecker
+	repo            *repository.QuarantineRepository
+	quarantineScore float64
+	rejectScore     float64
+	failOpen        bool
+	releaser        QuarantineReleaser
+}
+
+// NewSpamService creates a new spam service. A nil checker disables filtering,
+// while the quarantine list stays available.
+func NewSpamService(checker SpamChecker, repo *repository.QuarantineRepository, cfg *config.SpamConfig) *SpamService {
+	return &SpamService{
+		checker:         checker,
+		repo:            repo,
+		quarantineScore: cfg.QuarantineScore,
+		rejectScore:     cfg.RejectScore,
+		failOpen:        cfg.FailOpen,
+	}
+}
+
+// Enabled reports whether inbound email is checked for spam
+func (s *SpamService) Enabled() bool {
+	return s.checker != nil
+}
+
+// FailOpen reports whether mail should be accepted when the spam filter is unreachable
+func (s *SpamService) FailOpen() bool {
+	return s.failOpen
+}
+
+// SetReleaser sets what processes quarantined messages when they are released
+func (s *SpamService) SetReleaser(releaser QuarantineReleaser) {
+	s.releaser = releaser
+}
+
+// Check scores a message and applies the quarantine and reject thresholds
+func (s *SpamService) Check(raw []byte, from string) (*SpamCheckResult, error) {
+	if s.checker == nil {
+		return &SpamCheckResult{Verdict: SpamVerdictHam}, nil
+	}
+
+	result, err := s.checker.Check(raw, from)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case s.rejectScore > 0 && result.Score >= s.rejectScore:
+		result.Verdict = SpamVerdictReject
+	case result.Score >= s.quarantineScore:
+		result.Verdict = SpamVerdictQuarantine
+	default:
+		result.Verdict = SpamVerdictHam
+	}
+
+	return result, nil
+}
+
+// Quarantine stores a message in the quarantine list
+func (s *SpamService) Quarantine(email *models.QuarantinedEmail) error {
+	if email.ReceivedAt.IsZero() {
+		email.ReceivedAt = time.Now()
+	}
+	return s.repo.Create(email)
+}
+
+// ListQuarantined returns quarantined messages, newest first
+func (s *SpamService) ListQuarantined() ([]*models.QuarantinedEmail, error) {
+	return s.repo.List()
+}
+
+// DeleteQuarantined permanently discards a quarantined message
+func (s *SpamService) DeleteQuarantined(id uint) error {
+	email, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if email == nil {
+		return ErrQuarantinedEmailNotFound
+	}
+	return s.repo.Delete(id)
+}
+
+// ReleaseQuarantined processes a quarantined message as normal inbound email
+// and removes it from the quarantine list
+func (s *SpamService) ReleaseQuarantined(id uint) error {
+	if s.releaser == nil {
+		return ErrReleaseUnavailable
+	}
+
+	email, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if email == nil {
+		return ErrQuarantinedEmailNotFound
+	}
+
+	if err := s.releaser.ReleaseQuarantined(email); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+type mockAuthRepository struct{}
+
+func (m *mockAuthRepository) GetUserByEmail(email string) (*models.User, error) {
+	return &models.User{Email: email}, nil
+}
+
+// newMockRspamd scores messages by keyword: "lottery" is rejected, "pills" quarantined
+func newMockRspamd(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/checkv2" || r.Header.Get("Password") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+
+		score, action := 1.0, "no action"
+		symbols := map[string]map[string]float64{"R_SPF_ALLOW": {"score": -0.2}}
+		switch {
+		case strings.Contains(string(body), "lottery"):
+			score, action = 20, "reject"
+			symbols["LOTTERY_SCAM"] = map[string]float64{"score": 19}
+		case strings.Contains(string(body), "pills"):
+			score, action = 8, "add header"
+			symbols["PILLS_SPAM"] = map[string]float64{"score": 7}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"score":   score,
+			"action":  action,
+			"symbols": symbols,
+		})
+	}))
+}
+
+func newTestSpamMessage(messageID, body string) *imap.Message {
+	return &imap.Message{
+		Envelope: &imap.Envelope{
+			Date:      time.Now(),
+			MessageId: messageID,
+			Subject:   "Hello",
+			From:      []*imap.Address{{PersonalName: "Sender", MailboxName: "sender", HostName: "example.com"}},
+		},
+		Body: map[*imap.BodySectionName]imap.Literal{
+			&imap.BodySectionName{}: strings.NewReader("Content-Type: text/plain\r\n\r\n" + body),
+		},
+	}
+}
+
+func TestSpamService_Check(t *testing.T) {
+	server := newMockRspamd(t)
+	defer server.Close()
+
+	db := setupTestDB(t)
+	spam := NewSpamService(NewRspamdClient(server.URL, "secret", time.Second), repository.NewQuarantineRepository(db),
+		&config.SpamConfig{QuarantineScore: 6, RejectScore: 15})
+
+	tests := []struct {
+		body    string
+		verdict SpamVerdict
+	}{
+		{"printer is broken", SpamVerdictHam},
+		{"cheap pills", SpamVerdictQuarantine},
+		{"you won the lottery", SpamVerdictReject},
+	}
+	for _, tt := range tests {
+		result, err := spam.Check([]byte(tt.body), "sender@example.com")
+		if err != nil {
+			t.Fatalf("Check(%q) failed: %v", tt.body, err)
+		}
+		if result.Verdict != tt.verdict {
+			t.Errorf("Check(%q) verdict = %s, want %s", tt.body, result.Verdict, tt.verdict)
+		}
+	}
+
+	// Only rules that add to the score are reported
+	result, _ := spam.Check([]byte("cheap pills"), "sender@example.com")
+	if len(result.Symbols) != 1 || result.Symbols[0] != "PILLS_SPAM" {
+		t.Errorf("Expected symbols [PILLS_SPAM], got %v", result.Symbols)
+	}
+
+	// Rspamd errors are reported so the message can be retried
+	badPassword := NewSpamService(NewRspamdClient(server.URL, "wrong", time.Second), repository.NewQuarantineRepository(db),
+		&config.SpamConfig{QuarantineScore: 6})
+	if _, err := badPassword.Check([]byte("hi"), ""); err == nil {
+		t.Error("Expected error when rspamd rejects the request")
+	}
+}
+
+func TestEmailService_SpamQuarantine(t *testing.T) {
+	server := newMockRspamd(t)
+	defer server.Close()
+
+	db := setupTestDB(t)
+	spam := NewSpamService(NewRspamdClient(server.URL, "secret", time.Second), repository.NewQuarantineRepository(db),
+		&config.SpamConfig{QuarantineScore: 6, RejectScore: 15})
+
+	mockTask := &mockTaskService{}
+	emailService := NewEmailService(mockTask, newMockTaskRepository(), &mockAuthRepository{}, NewStorageService(t.TempDir()), &config.Config{})
+	emailService.SetSpamFilter(spam)
+
+	for _, msg := range []*imap.Message{
+		newTestSpamMessage("ham@example.com", "printer is broken"),
+		newTestSpamMessage("spam@example.com", "cheap pills"),
+		newTestSpamMessage("scam@example.com", "you won the lottery"),
+	} {
+		if err := emailService.processMessage(msg, nil); err != nil {
+			t.Fatalf("Failed to process message: %v", err)
+		}
+	}
+
+	if len(mockTask.createdTasks) != 1 {
+		t.Fatalf("Expected only the ham message to create a task, got %d", len(mockTask.createdTasks))
+	}
+	if len(mockTask.addedComments) != 1 || mockTask.addedComments[0].Content != "printer is broken" {
+		t.Errorf("Expected the ham body to be parsed after the spam check, got %+v", mockTask.addedComments)
+	}
+
+	quarantined, err := spam.ListQuarantined()
+	if err != nil {
+		t.Fatalf("Failed to list quarantine: %v", err)
+	}
+	if len(quarantined) != 1 {
+		t.Fatalf("Expected 1 quarantined email, got %d", len(quarantined))
+	}
+	if quarantined[0].MessageID != "spam@example.com" || quarantined[0].Score != 8 {
+		t.Errorf("Unexpected quarantined email: %+v", quarantined[0])
+	}
+
+	if err := spam.ReleaseQuarantined(quarantined[0].ID); err != nil {
+		t.Fatalf("Failed to release email: %v", err)
+	}
+	if len(mockTask.createdTasks) != 2 || mockTask.createdTasks[1].EmailMessageID != "spam@example.com" {
+		t.Errorf("Expected released email to create a task, got %d tasks", len(mockTask.createdTasks))
+	}
+	if len(mockTask.addedComments) != 2 || mockTask.addedComments[1].Content != "cheap pills" {
+		t.Errorf("Expected released email body as a comment, got %+v", mockTask.addedComments)
+	}
+
+	if remaining, _ := spam.ListQuarantined(); len(remaining) != 0 {
+		t.Errorf("Expected quarantine to be empty after release, got %d", len(remaining))
+	}
+	if err := spam.ReleaseQuarantined(quarantined[0].ID); err != ErrQuarantinedEmailNotFound {
+		t.Errorf("Expected ErrQuarantinedEmailNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/i18n"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+// StandupBlockedTag marks open or in-progress tasks as blocked in standup reports
+const StandupBlockedTag = "blocked"
+
+// StandupTask is a task line in a standup report
+type StandupTask struct {
+	ID       uint                `json:"id"`
+	Name     string              `json:"name"`
+	Status   models.TaskStatus   `json:"status"`
+	Priority models.TaskPriority `json:"priority,omitempty"`
+	Tags     []string            `json:"tags,omitempty"`
+}
+
+// StandupReport summarizes what was resolved on the previous working day, what
+// is in progress and what is blocked. Tasks, time entries and status changes
+// are not attributed to users, so it covers the whole team.
+type StandupReport struct {
+	Date          string        `json:"date"`  // YYYY-MM-DD the standup is for
+	Since         string        `json:"since"` // YYYY-MM-DD of the previous working day
+	Resolved      []StandupTask `json:"resolved"`
+	InProgress    []StandupTask `json:"in_progress"`
+	Blocked       []StandupTask `json:"blocked"`
+	LoggedMinutes int           `json:"logged_minutes"` // time logged on the previous working day
+	Markdown      string        `json:"markdown"`
+}
+
+// previousWorkday returns the start of the working day before day, skipping weekends
+func previousWorkday(day time.Time) time.Time {
+	prev := startOfDay(day).AddDate(0, 0, -1)
+	for prev.Weekday() == time.Saturday || prev.Weekday() == time.Sunday {
+		prev = prev.AddDate(0, 0, -1)
+	}
+	return prev
+}
+
+// GenerateStandupReport builds the team's standup for the given day
+func (s *ReportService) GenerateStandupReport(date time.Time) (*StandupReport, error) {
+	day := startOfDay(date)
+	since := previousWorkday(day)
+
+	tasks, err := s.taskRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StandupReport{
+		Date:       day.Format("2006-01-02"),
+		Since:      since.Format("2006-01-02"),
+		Resolved:   []StandupTask{},
+		InProgress: []StandupTask{},
+		Blocked:    []StandupTask{},
+	}
+
+	for _, task := range tasks {
+		switch {
+		case task.ResolvedAt != nil && !task.ResolvedAt.Before(since) && task.ResolvedAt.Before(day) &&
+			(task.Status == models.TaskStatusResolved || task.Status == models.TaskStatusClosed):
+			report.Resolved = append(report.Resolved, newStandupTask(task))
+		case (task.Status == models.TaskStatusOpen || task.Status == models.TaskStatusInProgress) && hasTag(task, StandupBlockedTag):
+			report.Blocked = append(report.Blocked, newStandupTask(task))
+		case task.Status == models.TaskStatusInProgress:
+			report.InProgress = append(report.InProgress, newStandupTask(task))
+		}
+	}
+
+	entries, err := s.taskRepo.GetTimeEntriesBetween(since, day)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		report.LoggedMinutes += entry.Duration
+	}
+
+	for _, list := range [][]StandupTask{report.Resolved, report.InProgress, report.Blocked} {
+		sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+
+	report.Markdown = report.renderMarkdown(i18n.NewLocalizer())
+	return report, nil
+}
+
+func newStandupTask(task *models.Task) StandupTask {
+	return StandupTask{ID: task.ID, Name: task.Name, Status: task.Status, Priority: task.Priority, Tags: task.Tags}
+}
+
+// renderMarkdown renders the report in the localizer's language
+func (r *StandupReport) renderMarkdown(loc *i18n.Localizer) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n", loc.T("standup_subject", map[string]interface{}{"Date": r.Date}))
+
+	sections := []struct {
+		heading string
+		tasks   []StandupTask
+	}{
+		{loc.T("standup_resolved_since", map[string]interface{}{"Since": r.Since}), r.Resolved},
+		{loc.T("standup_in_progress"), r.InProgress},
+		{loc.T("standup_blocked"), r.Blocked},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n## %s\n\n", section.heading)
+		if len(section.tasks) == 0 {
+			fmt.Fprintf(&b, "_%s_\n", loc.T("standup_nothing"))
+			continue
+		}
+		for _, task := range section.tasks {
+			fmt.Fprintf(&b, "- #%d %s", task.ID, task.Name)
+			if task.Priority == models.TaskPriorityHigh {
+				fmt.Fprintf(&b, " (%s)", loc.Priority(string(task.Priority)))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if r.LoggedMinutes > 0 {
+		duration := fmt.Sprintf("%dh %dm", r.LoggedMinutes/60, r.LoggedMinutes%60)
+		fmt.Fprintf(&b, "\n%s\n", loc.T("standup_time_logged", map[string]interface{}{"Since": r.Since, "Duration": duration}))
+	}
+
+	return b.String()
+}
+
+// StandupMailer emails the team's standup report each weekday morning to users who opted in
+type StandupMailer struct {
+	reports  *ReportService
+	authRepo *repository.AuthRepository
+	smtp     *SMTPService
+	hour     int
+	lastSent string
+}
+
+// NewStandupMailer creates a mailer that sends at the given hour (server time)
+func NewStandupMailer(reports *ReportService, authRepo *repository.AuthRepository, smtp *SMTPService, hour int) *StandupMailer {
+	return &StandupMailer{
+		reports:  reports,
+		authRepo: authRepo,
+		smtp:     smtp,
+		hour:     hour,
+	}
+}
+
+// Run sends the day's emails once, on weekdays at or after the configured hour.
+// Register it with a JobRunner to check every minute.
+func (m *StandupMailer) Run(now time.Time) error {
+	today := now.Format("2006-01-02")
+	if m.lastSent == today || now.Hour() < m.hour ||
+		now.Weekday() == time.Saturday || now.Weekday() == time.Sunday {
+		return nil
+	}
+	m.lastSent = today
+
+	users, err := m.authRepo.GetAllUsers()
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	report, err := m.reports.GenerateStandupReport(now)
+	if err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	// Users who are out of office get no standup while they are away
+	away, err := awayUsernames(m.reports.taskRepo, now)
+	if err != nil {
+		return fmt.Errorf("failed to list out-of-office users: %w", err)
+	}
+
+	// Rendered once per language the recipients use
+	emails := make(map[string]*RenderedEmail)
+
+	for _, user := range users {
+		if !user.StandupEmail || !user.IsActive || user.Email == "" || away[user.Username] {
+			continue
+		}
+
+		loc := i18n.NewLocalizer(user.Language)
+		email, ok := emails[loc.Language()]
+		if !ok {
+			email = &RenderedEmail{
+				Subject: loc.T("standup_subject", map[string]interface{}{"Date": report.Date}),
+				Text:    report.renderMarkdown(loc),
+			}
+			emails[loc.Language()] = email
+		}
+
+		if err := m.smtp.sendEmail([]string{user.Email}, email, ""); err != nil {
+			log.Printf("Standup email: failed to send to %s: %v", user.Email, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/i18n"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestReportService_GenerateStandupReport(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewReportService(repo)
+
+	// Monday, so the previous working day is Friday
+	monday := time.Date(2024, 3, 4, 9, 0, 0, 0, time.Local)
+	friday := time.Date(2024, 3, 1, 15, 0, 0, 0, time.Local)
+	saturday := time.Date(2024, 3, 2, 10, 0, 0, 0, time.Local)
+
+	tasks := []*models.Task{
+		{Name: "Ship release", Status: models.TaskStatusResolved, ResolvedAt: &friday},
+		{Name: "Weekend fix", Status: models.TaskStatusClosed, ResolvedAt: &saturday},
+		{Name: "Write docs", Status: models.TaskStatusInProgress},
+		{Name: "Wait for vendor", Status: models.TaskStatusOpen, Tags: []string{"Blocked"}},
+		{Name: "Backlog item", Status: models.TaskStatusOpen},
+	}
+	for _, task := range tasks {
+		if err := repo.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	report, err := service.GenerateStandupReport(monday)
+	if err != nil {
+		t.Fatalf("Failed to generate report: %v", err)
+	}
+
+	if report.Date != "2024-03-04" || report.Since != "2024-03-01" {
+		t.Errorf("Expected standup for 2024-03-04 since 2024-03-01, got %s since %s", report.Date, report.Since)
+	}
+	if len(report.Resolved) != 2 {
+		t.Errorf("Expected 2 resolved tasks (Friday and the weekend), got %+v", report.Resolved)
+	}
+	if len(report.InProgress) != 1 || report.InProgress[0].Name != "Write docs" {
+		t.Errorf("Expected 'Write docs' in progress, got %+v", report.InProgress)
+	}
+	if len(report.Blocked) != 1 || report.Blocked[0].Name != "Wait for vendor" {
+		t.Errorf("Expected 'Wait for vendor' blocked, got %+v", report.Blocked)
+	}
+
+	for _, want := range []string{"# Standup 2024-03-04\n", "## Resolved since 2024-03-01", "- #1 Ship release", "## Blocked"} {
+		if !strings.Contains(report.Markdown, want) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", want, report.Markdown)
+		}
+	}
+	if strings.Contains(report.Markdown, "Backlog item") {
+		t.Errorf("Expected open tasks to be left out, got:\n%s", report.Markdown)
+	}
+
+	german := report.renderMarkdown(i18n.NewLocalizer("de"))
+	for _, want := range []string{"## Erledigt seit 2024-03-01", "## Blockiert"} {
+		if !strings.Contains(german, want) {
+			t.Errorf("Expected German markdown to contain %q, got:\n%s", want, german)
+		}
+	}
+}
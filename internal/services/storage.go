@@ -3,8 +3,10 @@ package services
 import (
 	"crypto/sha256"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/soarinferret/jats/internal/models"
@@ -12,6 +14,7 @@ import (
 
 type StorageService struct {
 	baseDir string
+	audio   *AudioTranscoder
 }
 
 func NewStorageService(baseDir string) *StorageService {
@@ -20,7 +23,22 @@ func NewStorageService(baseDir string) *StorageService {
 	return &StorageService{baseDir: baseDir}
 }
 
+// SetAudioTranscoder makes audio attachments be stored transcoded for playback
+// in the browser. A file ffmpeg cannot convert is stored as received.
+func (s *StorageService) SetAudioTranscoder(transcoder *AudioTranscoder) {
+	s.audio = transcoder
+}
+
 func (s *StorageService) SaveAttachment(filename string, contentType string, data []byte) (*models.Attachment, error) {
+	if s.audio != nil && isAudioContentType(contentType) {
+		if transcoded, err := s.audio.Transcode(data); err != nil {
+			log.Printf("Storing audio attachment %s as received: %v", filename, err)
+		} else {
+			data, contentType = transcoded, TranscodedAudioType
+			filename = strings.TrimSuffix(filename, filepath.Ext(filename)) + ".m4a"
+		}
+	}
+
 	// Generate unique filename to avoid conflicts
 	hash := sha256.Sum256(data)
 	now := time.Now()
@@ -44,6 +62,7 @@ func (s *StorageService) SaveAttachment(filename string, contentType string, dat
 		FileName:     uniqueFilename,
 		OriginalName: filename,
 		ContentType:  contentType,
+		Size:         int64(len(data)),
 		FilePath:     uniqueFilename, // Store relative path, not absolute
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+var ErrSyncTargetNotFound = errors.New("sync target not found")
+
+// defaultSyncInterval is how often a target syncs when its interval is not set
+const defaultSyncInterval = 5 * time.Minute
+
+// TrackerIssue is the part of an external issue kept in sync with its task
+type TrackerIssue struct {
+	Title       string
+	Description string
+	Closed      bool
+}
+
+// TrackerComment is a comment on an external issue
+type TrackerComment struct {
+	ID     string
+	Author string
+	Body   string
+}
+
+// IssueTracker creates and updates the issues mirroring tasks in an external tracker
+type IssueTracker interface {
+	// CreateIssue opens an issue and returns its key and web URL
+	CreateIssue(issue TrackerIssue) (key, url string, err error)
+	// UpdateIssue sets the issue's title and description and closes or reopens it
+	UpdateIssue(key string, issue TrackerIssue) error
+	GetIssue(key string) (*TrackerIssue, error)
+	GetComments(key string) ([]TrackerComment, error)
+	AddComment(key, body string) (id string, err error)
+}
+
+// SyncRun is the outcome of syncing a target
+type SyncRun struct {
+	Target         string    `json:"target"`
+	At             time.Time `json:"at"`
+	Created        int       `json:"created"`         // issues opened for tasks
+	Updated        int       `json:"updated"`         // issues updated from their tasks
+	TasksChanged   int       `json:"tasks_changed"`   // tasks closed or reopened from their issues
+	CommentsPushed int       `json:"comments_pushed"` // public comments copied to issues
+	CommentsPulled int       `json:"comments_pulled"` // issue comments copied to tasks
+	Errors         []string  `json:"errors,omitempty"`
+}
+
+// SyncTargetStatus describes a configured sync target
+type SyncTargetStatus struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	SavedQueryID uint     `json:"saved_query_id"`
+	Interval     string   `json:"interval"`
+	LastRun      *SyncRun `json:"last_run"`
+}
+
+type syncTarget struct {
+	name     string
+	kind     string
+	queryID  uint
+	interval time.Duration
+	tracker  IssueTracker
+	lastRun  *SyncRun
+}
+
+// SyncService mirrors the tasks of saved queries into external issue trackers
+// and copies changes made there back to the tasks
+type SyncService struct {
+	taskService *TaskService
+	repo        *repository.TaskRepository
+
+	mu      sync.Mutex
+	targets map[string]*syncTarget
+}
+
+// NewSyncService creates a sync service for the configured targets
+func NewSyncService(taskService *TaskService, repo *repository.TaskRepository, targets map[string]config.SyncTargetConfig) (*SyncService, error) {
+	s := &SyncService{
+		taskService: taskService,
+		repo:        repo,
+		targets:     make(map[string]*syncTarget),
+	}
+
+	for name, cfg := range targets {
+		if cfg.URL == "" || cfg.Token == "" || cfg.Project == "" {
+			return nil, fmt.Errorf("sync target %q: url, token and project are required", name)
+		}
+		if cfg.SavedQueryID == 0 {
+			return nil, fmt.Errorf("sync target %q: saved_query_id is required", name)
+		}
+
+		interval := defaultSyncInterval
+		if cfg.Interval != "" {
+			parsed, err := time.ParseDuration(cfg.Interval)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("sync target %q: invalid interval %q", name, cfg.Interval)
+			}
+			interval = parsed
+		}
+
+		var tracker IssueTracker
+		switch cfg.Type {
+		case "jira":
+			tracker = NewJiraTracker(cfg)
+		case "gitlab":
+			tracker = NewGitLabTracker(cfg)
+		default:
+			return nil, fmt.Errorf("sync target %q: unknown type %q (use jira or gitlab)", name, cfg.Type)
+		}
+		s.addTarget(name, cfg.Type, cfg.SavedQueryID, interval, tracker)
+	}
+
+	return s, nil
+}
+
+func (s *SyncService) addTarget(name, kind string, queryID uint, interval time.Duration, tracker IssueTracker) {
+	s.targets[name] = &syncTarget{name: name, kind: kind, queryID: queryID, interval: interval, tracker: tracker}
+}
+
+// Targets returns the configured targets and their last runs, by name
+func (s *SyncService) Targets() []SyncTargetStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]SyncTargetStatus, 0, len(s.targets))
+	for _, target := range s.targets {
+		statuses = append(statuses, SyncTargetStatus{
+			Name:         target.name,
+			Type:         target.kind,
+			SavedQueryID: target.queryID,
+			Interval:     target.interval.String(),
+			LastRun:      target.lastRun,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Run syncs every target whose interval has elapsed. Register it with a
+// JobRunner to check every minute.
+func (s *SyncService) Run(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, target := range s.targets {
+		if target.lastRun != nil && now.Sub(target.lastRun.At) < target.interval {
+			continue
+		}
+		run := s.sync(target, now)
+		for _, msg := range run.Errors {
+			log.Printf("Issue sync %s: %s", target.name, msg)
+		}
+	}
+	return nil
+}
+
+// SyncTarget syncs a target now, regardless of its interval
+func (s *SyncService) SyncTarget(name string, now time.Time) (*SyncRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target, ok := s.targets[name]
+	if !ok {
+		return nil, ErrSyncTargetNotFound
+	}
+	return s.sync(target, now), nil
+}
+
+// sync mirrors every task of the target's saved query. Failures are recorded
+// per task, so one bad task does not hold up the others.
+func (s *SyncService) sync(target *syncTarget, now time.Time) *SyncRun {
+	run := &SyncRun{Target: target.name, At: now}
+	target.lastRun = run
+
+	query, err := s.taskService.GetSavedQueryByID(target.queryID)
+	if err != nil {
+		run.Errors = append(run.Errors, fmt.Sprintf("saved query %d: %v", target.queryID, err))
+		return run
+	}
+	tasks, err := s.taskService.GetTasksBySavedQuery(query)
+	if err != nil {
+		run.Errors = append(run.Errors, fmt.Sprintf("failed to list tasks: %v", err))
+		return run
+	}
+
+	for _, task := range tasks {
+		if err := s.syncTask(target, task.ID, run); err != nil {
+			run.Errors = append(run.Errors, fmt.Sprintf("task #%d: %v", task.ID, err))
+		}
+	}
+	return run
+}
+
+// syncTask brings a task and its issue up to date. A change to the issue's
+// open or closed state since the last sync wins over the task's; otherwise
+// the task's state, title and description are pushed to the issue.
+func (s *SyncService) syncTask(target *syncTarget, taskID uint, run *SyncRun) error {
+	task, err := s.taskService.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.repo.GetExternalIssue(task.ID, target.name)
+	if err != nil {
+		return err
+	}
+
+	if link == nil {
+		// Tasks finished before they matched the query are not mirrored after the fact
+		if isDone(task) {
+			return nil
+		}
+		key, url, err := target.tracker.CreateIssue(trackerIssueFor(task))
+		if err != nil {
+			return fmt.Errorf("failed to create issue: %w", err)
+		}
+		link = &models.ExternalIssue{TaskID: task.ID, Target: target.name, Key: key, URL: url}
+		if err := s.repo.SaveExternalIssue(link); err != nil {
+			return err
+		}
+		run.Created++
+	} else {
+		remote, err := target.tracker.GetIssue(link.Key)
+		if err != nil {
+			return fmt.Errorf("failed to get issue %s: %w", link.Key, err)
+		}
+
+		switch {
+		case remote.Closed != link.Closed:
+			if remote.Closed != isDone(task) {
+				task.Status = models.TaskStatusOpen
+				if remote.Closed {
+					task.Status = models.TaskStatusClosed
+				}
+				if err := s.taskService.UpdateTask(task); err != nil {
+					return err
+				}
+				run.TasksChanged++
+			}
+		case isDone(task) != link.Closed || link.LastSyncedAt == nil || task.UpdatedAt.After(*link.LastSyncedAt):
+			if err := target.tracker.UpdateIssue(link.Key, trackerIssueFor(task)); err != nil {
+				return fmt.Errorf("failed to update issue %s: %w", link.Key, err)
+			}
+			run.Updated++
+		}
+	}
+
+	if err := s.syncComments(target, task, link, run); err != nil {
+		return err
+	}
+
+	// Stamped after the comments, whose copies touch the task's UpdatedAt
+	synced := time.Now()
+	link.Closed = isDone(task)
+	link.LastSyncedAt = &synced
+	return s.repo.SaveExternalIssue(link)
+}
+
+// syncComments copies new issue comments to the task and new public task
+// comments to the issue. Private comments never leave JATS.
+func (s *SyncService) syncComments(target *syncTarget, task *models.Task, link *models.ExternalIssue, run *SyncRun) error {
+	mirrored, err := s.repo.GetExternalComments(link.ID)
+	if err != nil {
+		return err
+	}
+	seenLocal := make(map[uint]bool)
+	seenRemote := make(map[string]bool)
+	for _, m := range mirrored {
+		seenLocal[m.CommentID] = true
+		seenRemote[m.ExternalID] = true
+	}
+
+	remoteComments, err := target.tracker.GetComments(link.Key)
+	if err != nil {
+		return fmt.Errorf("failed to get comments of %s: %w", link.Key, err)
+	}
+	for _, remote := range remoteComments {
+		if seenRemote[remote.ID] {
+			continue
+		}
+		comment := &models.Comment{Content: fmt.Sprintf("%s (%s %s):\n\n%s", remote.Author, target.name, link.Key, remote.Body)}
+		if err := s.taskService.AddComment(task.ID, comment); err != nil {
+			return err
+		}
+		if err := s.repo.AddExternalComment(&models.ExternalComment{ExternalIssueID: link.ID, CommentID: comment.ID, ExternalID: remote.ID}); err != nil {
+			return err
+		}
+		run.CommentsPulled++
+	}
+
+	for _, comment := range task.Comments {
+		if comment.IsPrivate || seenLocal[comment.ID] {
+			continue
+		}
+		id, err := target.tracker.AddComment(link.Key, comment.Content)
+		if err != nil {
+			return fmt.Errorf("failed to comment on %s: %w", link.Key, err)
+		}
+		if err := s.repo.AddExternalComment(&models.ExternalComment{ExternalIssueID: link.ID, CommentID: comment.ID, ExternalID: id}); err != nil {
+			return err
+		}
+		run.CommentsPushed++
+	}
+	return nil
+}
+
+func trackerIssueFor(task *models.Task) TrackerIssue {
+	return TrackerIssue{Title: task.Name, Description: task.Description, Closed: isDone(task)}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+// fakeTracker keeps issues in memory
+type fakeTracker struct {
+	issues   map[string]*TrackerIssue
+	comments map[string][]TrackerComment
+	nextID   int
+}
+
+func newFakeTracker() *fakeTracker {
+	return &fakeTracker{issues: make(map[string]*TrackerIssue), comments: make(map[string][]TrackerComment)}
+}
+
+func (f *fakeTracker) CreateIssue(issue TrackerIssue) (string, string, error) {
+	f.nextID++
+	key := "OPS-" + strconv.Itoa(f.nextID)
+	f.issues[key] = &issue
+	return key, "https://tracker.example/" + key, nil
+}
+
+func (f *fakeTracker) UpdateIssue(key string, issue TrackerIssue) error {
+	f.issues[key] = &issue
+	return nil
+}
+
+func (f *fakeTracker) GetIssue(key string) (*TrackerIssue, error) {
+	issue := *f.issues[key]
+	return &issue, nil
+}
+
+func (f *fakeTracker) GetComments(key string) ([]TrackerComment, error) {
+	return f.comments[key], nil
+}
+
+func (f *fakeTracker) AddComment(key, body string) (string, error) {
+	f.nextID++
+	id := strconv.Itoa(f.nextID)
+	f.comments[key] = append(f.comments[key], TrackerComment{ID: id, Author: "jats", Body: body})
+	return id, nil
+}
+
+func TestNewSyncService_Validation(t *testing.T) {
+	valid := config.SyncTargetConfig{Type: "gitlab", URL: "https://gitlab.example", Token: "t", Project: "ops/app", SavedQueryID: 1}
+
+	if _, err := NewSyncService(nil, nil, map[string]config.SyncTargetConfig{"gitlab": valid}); err != nil {
+		t.Fatalf("Expected a valid target, got %v", err)
+	}
+
+	invalid := []func(cfg *config.SyncTargetConfig){
+		func(cfg *config.SyncTargetConfig) { cfg.Type = "trello" },
+		func(cfg *config.SyncTargetConfig) { cfg.Token = "" },
+		func(cfg *config.SyncTargetConfig) { cfg.SavedQueryID = 0 },
+		func(cfg *config.SyncTargetConfig) { cfg.Interval = "soon" },
+	}
+	for i, mutate := range invalid {
+		cfg := valid
+		mutate(&cfg)
+		if _, err := NewSyncService(nil, nil, map[string]config.SyncTargetConfig{"gitlab": cfg}); err == nil {
+			t.Errorf("Case %d: expected a validation error for %+v", i, cfg)
+		}
+	}
+}
+
+func TestSyncService_TwoWaySync(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	taskService := NewTaskService(repo, nil)
+
+	query, err := taskService.CreateSavedQuery(&models.SavedQuery{Name: "Customer", IncludedTags: []string{"customer"}})
+	if err != nil {
+		t.Fatalf("Failed to create saved query: %v", err)
+	}
+
+	task := &models.Task{Name: "Broken export", Description: "CSV is empty", Status: models.TaskStatusOpen, Tags: []string{"customer"}}
+	repo.Create(task)
+	repo.Create(&models.Task{Name: "Internal chore", Status: models.TaskStatusOpen})
+	taskService.AddComment(task.ID, &models.Comment{Content: "Looking into it"})
+	taskService.AddComment(task.ID, &models.Comment{Content: "Customer is grumpy", IsPrivate: true})
+
+	tracker := newFakeTracker()
+	service, _ := NewSyncService(taskService, repo, nil)
+	service.addTarget("jira", "jira", query.ID, time.Minute, tracker)
+
+	sync := func() *SyncRun {
+		t.Helper()
+		run, err := service.SyncTarget("jira", time.Now())
+		if err != nil {
+			t.Fatalf("SyncTarget failed: %v", err)
+		}
+		if len(run.Errors) > 0 {
+			t.Fatalf("Sync reported errors: %v", run.Errors)
+		}
+		return run
+	}
+
+	// First sync opens an issue for the matching task and copies its public comment
+	run := sync()
+	if run.Created != 1 || run.CommentsPushed != 1 || len(tracker.issues) != 1 {
+		t.Fatalf("Expected one issue with one comment, got %+v and %d issues", run, len(tracker.issues))
+	}
+	if tracker.issues["OPS-1"].Title != "Broken export" {
+		t.Errorf("Expected the issue to mirror the task, got %+v", tracker.issues["OPS-1"])
+	}
+	loaded, _ := taskService.GetTask(task.ID)
+	if len(loaded.ExternalIssues) != 1 || loaded.ExternalIssues[0].Key != "OPS-1" {
+		t.Fatalf("Expected the external key on the task, got %+v", loaded.ExternalIssues)
+	}
+
+	// Nothing changed, nothing to do
+	if run := sync(); run.Updated != 0 || run.CommentsPushed != 0 || run.CommentsPulled != 0 {
+		t.Errorf("Expected an idempotent second sync, got %+v", run)
+	}
+
+	// A comment in the tracker is copied to the task once
+	tracker.comments["OPS-1"] = append(tracker.comments["OPS-1"], TrackerComment{ID: "c9", Author: "Dana", Body: "Fixed in 2.1"})
+	if run := sync(); run.CommentsPulled != 1 || run.CommentsPushed != 0 {
+		t.Errorf("Expected the tracker comment to be pulled without echoing it back, got %+v", run)
+	}
+	if run := sync(); run.CommentsPulled != 0 {
+		t.Errorf("Expected the tracker comment to be pulled only once, got %+v", run)
+	}
+
+	// Closing the issue closes the task
+	tracker.issues["OPS-1"].Closed = true
+	if run := sync(); run.TasksChanged != 1 {
+		t.Errorf("Expected the task to be closed from the tracker, got %+v", run)
+	}
+	loaded, _ = taskService.GetTask(task.ID)
+	if loaded.Status != models.TaskStatusClosed {
+		t.Errorf("Expected the task to be closed, got %s", loaded.Status)
+	}
+
+	// Reopening and renaming the task reopens and renames the issue
+	loaded.Status = models.TaskStatusOpen
+	loaded.Name = "Broken CSV export"
+	if err := taskService.UpdateTask(loaded); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if run := sync(); run.Updated != 1 {
+		t.Errorf("Expected the issue to be updated, got %+v", run)
+	}
+	if issue := tracker.issues["OPS-1"]; issue.Closed || issue.Title != "Broken CSV export" {
+		t.Errorf("Expected the issue reopened and renamed, got %+v", issue)
+	}
+
+	if _, err := service.SyncTarget("gitlab", time.Now()); err != ErrSyncTargetNotFound {
+		t.Errorf("Expected ErrSyncTargetNotFound, got %v", err)
+	}
+}
+
+func TestGitLabTracker(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath()+" "+body["state_event"])
+
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/issues"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"iid": 7, "web_url": "https://gitlab.example/ops/app/-/issues/7"})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/notes"):
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 1, "body": "closed", "system": true},
+				{"id": 2, "body": "On it", "author": map[string]string{"name": "Dana"}},
+			})
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{"iid": 7, "title": "Bug", "state": "closed"})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 3})
+		}
+	}))
+	defer server.Close()
+
+	tracker := NewGitLabTracker(config.SyncTargetConfig{URL: server.URL, Token: "secret", Project: "ops/app"})
+
+	key, url, err := tracker.CreateIssue(TrackerIssue{Title: "Bug"})
+	if err != nil || key != "7" || !strings.HasSuffix(url, "/issues/7") {
+		t.Fatalf("Expected issue 7, got %q %q %v", key, url, err)
+	}
+	if err := tracker.UpdateIssue(key, TrackerIssue{Title: "Bug", Closed: true}); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	issue, err := tracker.GetIssue(key)
+	if err != nil || !issue.Closed {
+		t.Errorf("Expected a closed issue, got %+v %v", issue, err)
+	}
+	comments, err := tracker.GetComments(key)
+	if err != nil || len(comments) != 1 || comments[0].Author != "Dana" {
+		t.Errorf("Expected only the user note, got %+v %v", comments, err)
+	}
+
+	want := []string{
+		"POST /api/v4/projects/ops%2Fapp/issues ",
+		"PUT /api/v4/projects/ops%2Fapp/issues/7 close",
+		"GET /api/v4/projects/ops%2Fapp/issues/7 ",
+		"GET /api/v4/projects/ops%2Fapp/issues/7/notes ",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected requests:\n%s", strings.Join(requests, "\n"))
+	}
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/config"
+)
+
+// trackerClient sends JSON requests to an issue tracker's REST API
+type trackerClient struct {
+	baseURL    string
+	authorize  func(req *http.Request)
+	httpClient *http.Client
+}
+
+func newTrackerClient(baseURL string, authorize func(req *http.Request)) *trackerClient {
+	return &trackerClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		authorize:  authorize,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends body as JSON and decodes the response into out; either may be nil
+func (c *trackerClient) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.authorize(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s returned status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// JiraTracker mirrors tasks as issues in a Jira project through the REST API v2
+type JiraTracker struct {
+	client           *trackerClient
+	baseURL          string
+	project          string
+	issueType        string
+	closeTransition  string
+	reopenTransition string
+}
+
+// NewJiraTracker creates a Jira tracker. With a username it authenticates with
+// basic auth (Jira Cloud API tokens), otherwise with the token as a bearer token.
+func NewJiraTracker(cfg config.SyncTargetConfig) *JiraTracker {
+	t := &JiraTracker{
+		baseURL:          strings.TrimRight(cfg.URL, "/"),
+		project:          cfg.Project,
+		issueType:        cfg.IssueType,
+		closeTransition:  cfg.CloseTransition,
+		reopenTransition: cfg.ReopenTransition,
+	}
+	if t.issueType == "" {
+		t.issueType = "Task"
+	}
+	if t.closeTransition == "" {
+		t.closeTransition = "Done"
+	}
+	if t.reopenTransition == "" {
+		t.reopenTransition = "To Do"
+	}
+	t.client = newTrackerClient(cfg.URL, func(req *http.Request) {
+		if cfg.Username != "" {
+			req.SetBasicAuth(cfg.Username, cfg.Token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+cfg.Token)
+		}
+	})
+	return t
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		Status      struct {
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// CreateIssue creates an issue of the configured type in the project
+func (t *JiraTracker) CreateIssue(issue TrackerIssue) (string, string, error) {
+	body := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": t.project},
+			"issuetype":   map[string]string{"name": t.issueType},
+			"summary":     issue.Title,
+			"description": issue.Description,
+		},
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := t.client.do(http.MethodPost, "/rest/api/2/issue", body, &created); err != nil {
+		return "", "", err
+	}
+	return created.Key, t.baseURL + "/browse/" + created.Key, nil
+}
+
+// UpdateIssue sets the summary and description, and moves the issue through
+// the close or reopen transition when its status category disagrees
+func (t *JiraTracker) UpdateIssue(key string, issue TrackerIssue) error {
+	body := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"summary":     issue.Title,
+			"description": issue.Description,
+		},
+	}
+	if err := t.client.do(http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(key), body, nil); err != nil {
+		return err
+	}
+
+	current, err := t.GetIssue(key)
+	if err != nil {
+		return err
+	}
+	if current.Closed == issue.Closed {
+		return nil
+	}
+
+	name := t.reopenTransition
+	if issue.Closed {
+		name = t.closeTransition
+	}
+	return t.transition(key, name)
+}
+
+// transition applies the named workflow transition to an issue
+func (t *JiraTracker) transition(key, name string) error {
+	var available struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	if err := t.client.do(http.MethodGet, path, nil, &available); err != nil {
+		return err
+	}
+
+	for _, transition := range available.Transitions {
+		if strings.EqualFold(transition.Name, name) {
+			body := map[string]interface{}{"transition": map[string]string{"id": transition.ID}}
+			return t.client.do(http.MethodPost, path, body, nil)
+		}
+	}
+	return fmt.Errorf("issue %s has no %q transition", key, name)
+}
+
+// GetIssue returns the issue; it is closed when its status is in the done category
+func (t *JiraTracker) GetIssue(key string) (*TrackerIssue, error) {
+	var issue jiraIssue
+	if err := t.client.do(http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(key)+"?fields=summary,description,status", nil, &issue); err != nil {
+		return nil, err
+	}
+	return &TrackerIssue{
+		Title:       issue.Fields.Summary,
+		Description: issue.Fields.Description,
+		Closed:      issue.Fields.Status.StatusCategory.Key == "done",
+	}, nil
+}
+
+func (t *JiraTracker) GetComments(key string) ([]TrackerComment, error) {
+	var result struct {
+		Comments []struct {
+			ID     string `json:"id"`
+			Body   string `json:"body"`
+			Author struct {
+				DisplayName string `json:"displayName"`
+			} `json:"author"`
+		} `json:"comments"`
+	}
+	if err := t.client.do(http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(key)+"/comment?maxResults=1000", nil, &result); err != nil {
+		return nil, err
+	}
+
+	comments := make([]TrackerComment, 0, len(result.Comments))
+	for _, c := range result.Comments {
+		comments = append(comments, TrackerComment{ID: c.ID, Author: c.Author.DisplayName, Body: c.Body})
+	}
+	return comments, nil
+}
+
+func (t *JiraTracker) AddComment(key, body string) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := t.client.do(http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/comment", map[string]string{"body": body}, &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// GitLabTracker mirrors tasks as issues of a GitLab project through the REST API v4.
+// Issue keys are the project-scoped issue IIDs.
+type GitLabTracker struct {
+	client  *trackerClient
+	project string // API path of the project, /projects/<id or escaped path>
+}
+
+// NewGitLabTracker creates a GitLab tracker authenticated with a personal,
+// project or group access token
+func NewGitLabTracker(cfg config.SyncTargetConfig) *GitLabTracker {
+	return &GitLabTracker{
+		client: newTrackerClient(strings.TrimRight(cfg.URL, "/")+"/api/v4", func(req *http.Request) {
+			req.Header.Set("PRIVATE-TOKEN", cfg.Token)
+		}),
+		project: "/projects/" + url.PathEscape(cfg.Project),
+	}
+}
+
+type gitlabIssue struct {
+	IID         int    `json:"iid"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	WebURL      string `json:"web_url"`
+}
+
+func (t *GitLabTracker) issuePath(key string) string {
+	return t.project + "/issues/" + url.PathEscape(key)
+}
+
+func (t *GitLabTracker) CreateIssue(issue TrackerIssue) (string, string, error) {
+	var created gitlabIssue
+	body := map[string]string{"title": issue.Title, "description": issue.Description}
+	if err := t.client.do(http.MethodPost, t.project+"/issues", body, &created); err != nil {
+		return "", "", err
+	}
+	return strconv.Itoa(created.IID), created.WebURL, nil
+}
+
+func (t *GitLabTracker) UpdateIssue(key string, issue TrackerIssue) error {
+	body := map[string]string{"title": issue.Title, "description": issue.Description, "state_event": "reopen"}
+	if issue.Closed {
+		body["state_event"] = "close"
+	}
+	return t.client.do(http.MethodPut, t.issuePath(key), body, nil)
+}
+
+func (t *GitLabTracker) GetIssue(key string) (*TrackerIssue, error) {
+	var issue gitlabIssue
+	if err := t.client.do(http.MethodGet, t.issuePath(key), nil, &issue); err != nil {
+		return nil, err
+	}
+	return &TrackerIssue{Title: issue.Title, Description: issue.Description, Closed: issue.State == "closed"}, nil
+}
+
+// GetComments returns the issue's notes, leaving out system notes such as "closed"
+func (t *GitLabTracker) GetComments(key string) ([]TrackerComment, error) {
+	var notes []struct {
+		ID     int    `json:"id"`
+		Body   string `json:"body"`
+		System bool   `json:"system"`
+		Author struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	}
+	if err := t.client.do(http.MethodGet, t.issuePath(key)+"/notes?sort=asc&per_page=100", nil, &notes); err != nil {
+		return nil, err
+	}
+
+	comments := make([]TrackerComment, 0, len(notes))
+	for _, note := range notes {
+		if note.System {
+			continue
+		}
+		comments = append(comments, TrackerComment{ID: strconv.Itoa(note.ID), Author: note.Author.Name, Body: note.Body})
+	}
+	return comments, nil
+}
+
+func (t *GitLabTracker) AddComment(key, body string) (string, error) {
+	var created struct {
+		ID int `json:"id"`
+	}
+	if err := t.client.do(http.MethodPost, t.issuePath(key)+"/notes", map[string]string{"body": body}, &created); err != nil {
+		return "", err
+	}
+	return strconv.Itoa(created.ID), nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// TagStats is the workload on a tag: tasks still to do, and what was resolved
+// and logged in a period
+type TagStats struct {
+	Tag             string `json:"tag"`
+	OpenTasks       int    `json:"open_tasks"`
+	InProgressTasks int    `json:"in_progress_tasks"`
+	ResolvedTasks   int    `json:"resolved_tasks"` // resolved in the period and not reopened
+	LoggedMinutes   int    `json:"logged_minutes"` // logged in the period
+}
+
+// GetTagStats returns the stats of every tag in use, sorted by tag. The period
+// runs from since up to until; a zero until means up to now.
+func (s *TaskService) GetTagStats(since, until time.Time) ([]TagStats, error) {
+	tasks, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	inPeriod := func(t time.Time) bool {
+		return !t.Before(since) && (until.IsZero() || t.Before(until))
+	}
+
+	byTag := make(map[string]*TagStats)
+	for _, task := range tasks {
+		resolved := task.ResolvedAt != nil && inPeriod(*task.ResolvedAt) &&
+			(task.Status == models.TaskStatusResolved || task.Status == models.TaskStatusClosed)
+		logged := 0
+		for _, entry := range task.TimeEntries {
+			if inPeriod(entry.CreatedAt) {
+				logged += entry.Duration
+			}
+		}
+
+		for _, tag := range task.Tags {
+			stats, ok := byTag[tag]
+			if !ok {
+				stats = &TagStats{Tag: tag}
+				byTag[tag] = stats
+			}
+			switch task.Status {
+			case models.TaskStatusOpen:
+				stats.OpenTasks++
+			case models.TaskStatusInProgress:
+				stats.InProgressTasks++
+			}
+			if resolved {
+				stats.ResolvedTasks++
+			}
+			stats.LoggedMinutes += logged
+		}
+	}
+
+	result := make([]TagStats, 0, len(byTag))
+	for _, stats := range byTag {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Tag < result[j].Tag })
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_GetTagStats(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	create := func(name string, status models.TaskStatus, tags ...string) *models.Task {
+		task, err := service.CreateTask(name)
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		task.Tags = tags
+		task.Status = status
+		if err := service.UpdateTask(task); err != nil {
+			t.Fatalf("Failed to update task: %v", err)
+		}
+		return task
+	}
+	create("Fix invoices", models.TaskStatusOpen, "client1")
+	working := create("Migrate mail", models.TaskStatusInProgress, "client1", "email")
+	create("Reset password", models.TaskStatusResolved, "client1")
+	create("Clean desk", models.TaskStatusOpen)
+
+	if err := service.AddTimeEntry(working.ID, &models.TimeEntry{Duration: 45}); err != nil {
+		t.Fatalf("Failed to log time: %v", err)
+	}
+	if err := service.AddTimeEntryWithDate(working.ID, &models.TimeEntry{Duration: 60}, time.Now().AddDate(0, 0, -30)); err != nil {
+		t.Fatalf("Failed to log time: %v", err)
+	}
+
+	since, until, _ := ParseActivityRange("", "")
+	stats, err := service.GetTagStats(since, until)
+	if err != nil {
+		t.Fatalf("Failed to get tag stats: %v", err)
+	}
+	if len(stats) != 2 || stats[0].Tag != "client1" || stats[1].Tag != "email" {
+		t.Fatalf("Expected stats for client1 and email, got %+v", stats)
+	}
+	want := TagStats{Tag: "client1", OpenTasks: 1, InProgressTasks: 1, ResolvedTasks: 1, LoggedMinutes: 45}
+	if stats[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, stats[0])
+	}
+	if stats[1].InProgressTasks != 1 || stats[1].LoggedMinutes != 45 {
+		t.Errorf("Unexpected email stats %+v", stats[1])
+	}
+
+	// Nothing was resolved or logged in a period before today
+	stats, _ = service.GetTagStats(since.AddDate(0, 0, -7), since)
+	if stats[0].ResolvedTasks != 0 || stats[0].LoggedMinutes != 0 || stats[0].OpenTasks != 1 {
+		t.Errorf("Expected only open counts in an earlier period, got %+v", stats[0])
+	}
+}
@@ -1,21 +1,56 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/soarinferret/jats/internal/models"
 	"github.com/soarinferret/jats/internal/repository"
+	"github.com/soarinferret/jats/internal/tagexpr"
+	"github.com/soarinferret/jats/internal/utils"
 )
 
 type TaskService struct {
 	repo         *repository.TaskRepository
 	notification *NotificationService
+
+	// Kanban WIP limits, see SetWIPLimits
+	wipLimits  map[models.TaskStatus]int
+	enforceWIP bool
+	agingDays  int
+
+	// Sends email updates from email-originated tasks, see SetEmailSender
+	emailSender EmailSender
+
+	// Applies admin-defined automation rules on task events, see SetRuleSource
+	rules RuleSource
+
+	// Auto-assignment policies keyed by lowercase tag, see SetAssignmentPolicies
+	assignment map[string]assignmentPolicy
+
+	// Lowercase tags whose time is non-billable by default, see SetNonBillableTags
+	nonBillableTags map[string]bool
+
+	// Reviewers keyed by lowercase tag, see SetReviewPolicies
+	reviewers map[string][]string
+
+	// Public URL for the email read-receipt image, see SetTrackingURL
+	trackingURL string
+
+	// Task changes for live updates, see Events
+	events *EventBus
 }
 
 func NewTaskService(repo *repository.TaskRepository, notification *NotificationService) *TaskService {
 	return &TaskService{
 		repo:         repo,
 		notification: notification,
+		events:       NewEventBus(),
 	}
 }
 
@@ -25,10 +60,14 @@ func (s *TaskService) CreateTask(name string) (*models.Task, error) {
 
 func (s *TaskService) CreateTaskWithDate(name string, createdAt time.Time) (*models.Task, error) {
 	task := &models.Task{
-		Name:      name,
-		Status:    models.TaskStatusOpen,
-		CreatedAt: createdAt,
-		UpdatedAt: time.Now(),
+		Name:            name,
+		Status:          models.TaskStatusOpen,
+		StatusChangedAt: &createdAt,
+		CreatedAt:       createdAt,
+		UpdatedAt:       time.Now(),
+	}
+	if err := s.assignDefaultProject(task); err != nil {
+		return nil, err
 	}
 
 	err := s.repo.Create(task)
@@ -36,6 +75,9 @@ func (s *TaskService) CreateTaskWithDate(name string, createdAt time.Time) (*mod
 		return nil, err
 	}
 
+	s.applyRules(task, ruleEvent{Trigger: models.RuleTriggerCreated})
+	s.publish(TaskEventCreated, task)
+
 	// Notify subscribers (if any exist from email creation)
 	if s.notification != nil {
 		go s.notification.NotifyTaskCreated(task)
@@ -45,12 +87,17 @@ func (s *TaskService) CreateTaskWithDate(name string, createdAt time.Time) (*mod
 }
 
 func (s *TaskService) CreateTaskFromEmail(name, emailMessageID string) (*models.Task, error) {
+	now := time.Now()
 	task := &models.Task{
-		Name:           name,
-		Status:         models.TaskStatusOpen,
-		EmailMessageID: emailMessageID,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
+		Name:            name,
+		Status:          models.TaskStatusOpen,
+		StatusChangedAt: &now,
+		EmailMessageID:  emailMessageID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.assignDefaultProject(task); err != nil {
+		return nil, err
 	}
 
 	err := s.repo.Create(task)
@@ -58,6 +105,9 @@ func (s *TaskService) CreateTaskFromEmail(name, emailMessageID string) (*models.
 		return nil, err
 	}
 
+	s.applyRules(task, ruleEvent{Trigger: models.RuleTriggerCreated})
+	s.publish(TaskEventCreated, task)
+
 	// Notify subscribers (if any exist from email creation)
 	if s.notification != nil {
 		go s.notification.NotifyTaskCreated(task)
@@ -67,7 +117,30 @@ func (s *TaskService) CreateTaskFromEmail(name, emailMessageID string) (*models.
 }
 
 func (s *TaskService) GetTask(id uint) (*models.Task, error) {
-	return s.repo.GetByID(id)
+	task, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.References, err = s.repo.GetReferencedTasks(id); err != nil {
+		return nil, err
+	}
+	if task.ReferencedBy, err = s.repo.GetReferencingTasks(id); err != nil {
+		return nil, err
+	}
+	if task.ExternalIssues, err = s.repo.GetExternalIssues(id); err != nil {
+		return nil, err
+	}
+	if task.Blocks, err = s.repo.GetBlockedTasks(id); err != nil {
+		return nil, err
+	}
+	if task.BlockedBy, err = s.repo.GetBlockingTasks(id); err != nil {
+		return nil, err
+	}
+	if err := s.fillReceipts(task); err != nil {
+		return nil, err
+	}
+	return task, nil
 }
 
 func (s *TaskService) GetTasks() ([]*models.Task, error) {
@@ -75,6 +148,12 @@ func (s *TaskService) GetTasks() ([]*models.Task, error) {
 }
 
 func (s *TaskService) UpdateTask(task *models.Task) error {
+	return s.updateTask(task, false)
+}
+
+// updateTask saves changes to a task; unless approving a review, resolving a
+// task that needs review leaves it pending review instead
+func (s *TaskService) updateTask(task *models.Task, approving bool) error {
 	// Get current task for status comparison
 	currentTask, err := s.repo.GetByID(task.ID)
 	if err != nil {
@@ -82,7 +161,31 @@ func (s *TaskService) UpdateTask(task *models.Task) error {
 	}
 
 	oldStatus := currentTask.Status
+	if !approving {
+		s.holdForReview(task, oldStatus)
+	}
+	if resolving(oldStatus, task.Status) {
+		if err := s.checkBlockers(task.ID); err != nil {
+			return err
+		}
+	}
+	var change *models.TaskStatusChange
+	if task.Status != oldStatus {
+		if err := s.checkWIPLimit(task.Status); err != nil {
+			return err
+		}
+		change = statusChange(task, oldStatus)
+	}
+
+	task.Context = NormalizeContext(task.Context)
+	if task.Size, err = ParseTaskSize(string(task.Size)); err != nil {
+		return err
+	}
+	if err := s.assignDefaultProject(task); err != nil {
+		return err
+	}
 	task.UpdatedAt = time.Now()
+	task.Overdue = task.IsOverdue(task.UpdatedAt)
 
 	// Update resolved timestamp if status changed to resolved
 	if task.Status == models.TaskStatusResolved && oldStatus != models.TaskStatusResolved {
@@ -90,15 +193,35 @@ func (s *TaskService) UpdateTask(task *models.Task) error {
 		task.ResolvedAt = &now
 	}
 
-	err = s.repo.Update(task)
+	err = s.repo.UpdateWithStatusChange(task, change)
 	if err != nil {
 		return err
 	}
 
+	if task.Description != currentTask.Description {
+		if err := s.syncReferences(task.ID, nil, task.Description); err != nil {
+			return err
+		}
+	}
+
+	if oldStatus != task.Status {
+		s.applyRules(task, ruleEvent{Trigger: models.RuleTriggerStatusChanged})
+	}
+	for _, tag := range slices.Clone(task.Tags) {
+		if !slices.Contains(currentTask.Tags, tag) {
+			s.applyRules(task, ruleEvent{Trigger: models.RuleTriggerTagAdded, Tag: tag})
+		}
+	}
+	s.autoAssign(task)
+	s.publish(TaskEventUpdated, task)
+
 	// Send notifications
 	if s.notification != nil {
 		if oldStatus != task.Status {
 			go s.notification.NotifyStatusChanged(task, oldStatus, task.Status)
+			if task.Status == models.TaskStatusPendingReview {
+				go s.notification.NotifyReviewRequested(task, s.Reviewers(task))
+			}
 		} else {
 			go s.notification.NotifyTaskUpdated(task)
 		}
@@ -107,45 +230,102 @@ func (s *TaskService) UpdateTask(task *models.Task) error {
 	return nil
 }
 
+// statusChange stamps when a task entered its new status and returns the status
+// history row to save along with it
+func statusChange(task *models.Task, from models.TaskStatus) *models.TaskStatusChange {
+	now := time.Now()
+	task.StatusChangedAt = &now
+	task.StatusAgeDays = 0
+	return &models.TaskStatusChange{
+		TaskID:     task.ID,
+		FromStatus: from,
+		ToStatus:   task.Status,
+		ChangedAt:  now,
+		ChangedBy:  task.ChangedBy,
+	}
+}
+
+// syncReferences stores the #ID mentions in a task's description (commentID nil) or one of its notes
+func (s *TaskService) syncReferences(taskID uint, commentID *uint, text string) error {
+	var targets []uint
+	for _, id := range utils.ExtractTaskReferences(text) {
+		if id != taskID {
+			targets = append(targets, id)
+		}
+	}
+	return s.repo.ReplaceTaskReferences(taskID, commentID, targets)
+}
+
+func (s *TaskService) GetStatusHistory(taskID uint) ([]*models.TaskStatusChange, error) {
+	return s.repo.GetStatusChanges(taskID)
+}
+
+// GetTaskEmails returns the emails received and sent on a task, oldest first
+func (s *TaskService) GetTaskEmails(taskID uint) ([]*models.EmailMessage, error) {
+	return s.repo.GetTaskEmailMessages(taskID)
+}
+
 func (s *TaskService) DeleteTask(id uint) error {
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.events.Publish(TaskEvent{Type: TaskEventDeleted, TaskID: id})
+	return nil
 }
 
 func (s *TaskService) AddTimeEntry(taskID uint, entry *models.TimeEntry) error {
 	return s.AddTimeEntryWithDate(taskID, entry, time.Now())
 }
 
+// ErrSubtaskNotInTask is returned when a time entry references a subtask of another task
+var ErrSubtaskNotInTask = errors.New("subtask does not belong to task")
+
 func (s *TaskService) AddTimeEntryWithDate(taskID uint, entry *models.TimeEntry, createdAt time.Time) error {
-	entry.TaskID = taskID
-	entry.CreatedAt = createdAt
-	entry.UpdatedAt = time.Now()
-	
-	err := s.repo.AddTimeEntry(entry)
-	if err != nil {
-		return err
+	if entry.SubtaskID != nil {
+		subtask, err := s.repo.GetSubtask(*entry.SubtaskID)
+		if err != nil || subtask.TaskID != taskID {
+			return ErrSubtaskNotInTask
+		}
 	}
-	
-	// Get task to check current status and update
+
+	// Get task to default the billable flag and update its status
 	task, err := s.repo.GetByID(taskID)
 	if err != nil {
 		return err
 	}
+
+	entry.TaskID = taskID
+	entry.CreatedAt = createdAt
+	entry.UpdatedAt = time.Now()
+	s.applyDefaultBillable(entry, task)
+
+	if err := s.repo.AddTimeEntry(entry); err != nil {
+		return err
+	}
 	
 	// If task status is open, change it to in-progress
 	// Do not change status if it's already resolved or closed
 	oldStatus := task.Status
+	var change *models.TaskStatusChange
 	if task.Status == models.TaskStatusOpen {
 		task.Status = models.TaskStatusInProgress
+		task.ChangedBy = entry.CreatedBy
+		change = statusChange(task, oldStatus)
 	}
 	
 	// Update task's updated_at timestamp for proper sorting
 	task.UpdatedAt = time.Now()
 	
-	err = s.repo.Update(task)
+	err = s.repo.UpdateWithStatusChange(task, change)
 	if err != nil {
 		return err
 	}
 	
+	if oldStatus != task.Status {
+		s.applyRules(task, ruleEvent{Trigger: models.RuleTriggerStatusChanged})
+	}
+	s.publish(TaskEventUpdated, task)
+
 	// Send status change notification if status changed
 	if s.notification != nil && oldStatus != task.Status {
 		go s.notification.NotifyStatusChanged(task, oldStatus, task.Status)
@@ -154,6 +334,20 @@ func (s *TaskService) AddTimeEntryWithDate(taskID uint, entry *models.TimeEntry,
 	return nil
 }
 
+// GetCommentsForTasks returns the comments of several tasks in one query,
+// oldest first, keyed by task
+func (s *TaskService) GetCommentsForTasks(taskIDs []uint) (map[uint][]*models.Comment, error) {
+	comments, err := s.repo.GetCommentsByTaskIDs(taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	byTask := make(map[uint][]*models.Comment, len(taskIDs))
+	for _, comment := range comments {
+		byTask[comment.TaskID] = append(byTask[comment.TaskID], comment)
+	}
+	return byTask, nil
+}
+
 func (s *TaskService) AddComment(taskID uint, comment *models.Comment) error {
 	comment.TaskID = taskID
 	comment.CreatedAt = time.Now()
@@ -164,6 +358,10 @@ func (s *TaskService) AddComment(taskID uint, comment *models.Comment) error {
 		return err
 	}
 
+	if err := s.syncReferences(taskID, &comment.ID, comment.Content); err != nil {
+		return err
+	}
+
 	// Get task for notifications and update its timestamp
 	task, err := s.repo.GetByID(taskID)
 	if err != nil {
@@ -177,6 +375,12 @@ func (s *TaskService) AddComment(taskID uint, comment *models.Comment) error {
 		return err
 	}
 
+	if comment.FromEmail != "" {
+		s.applyRules(task, ruleEvent{Trigger: models.RuleTriggerEmailReceived, Sender: comment.FromEmail})
+		s.autoAssign(task)
+	}
+	s.events.Publish(TaskEvent{Type: TaskEventCommented, TaskID: task.ID, Name: task.Name, Status: task.Status, CommentID: comment.ID})
+
 	// Send notifications
 	if s.notification != nil {
 		go s.notification.NotifyCommentAdded(task, comment)
@@ -187,10 +391,30 @@ func (s *TaskService) AddComment(taskID uint, comment *models.Comment) error {
 
 
 func (s *TaskService) CreateSavedQuery(query *models.SavedQuery) (*models.SavedQuery, error) {
+	if err := normalizeSavedQueryExpression(query); err != nil {
+		return nil, err
+	}
+	if err := s.CheckProject(query.ProjectID); err != nil {
+		return nil, err
+	}
 	query.CreatedAt = time.Now()
 	query.UpdatedAt = time.Now()
+
+	// Feed URLs are secrets, so the token is always the server's
+	token, err := GenerateFeedToken()
+	if err != nil {
+		return nil, err
+	}
+	query.FeedToken = token
+
+	// New queries go to the end of the list
+	position, err := s.repo.NextSavedQueryPosition()
+	if err != nil {
+		return nil, err
+	}
+	query.Position = position
 	
-	err := s.repo.CreateSavedQuery(query)
+	err = s.repo.CreateSavedQuery(query)
 	if err != nil {
 		return nil, err
 	}
@@ -202,11 +426,33 @@ func (s *TaskService) GetSavedQueries() ([]*models.SavedQuery, error) {
 	return s.repo.GetSavedQueries()
 }
 
+// GetProjectSavedQueries returns the saved queries of a project and those for
+// every project, in sidebar order
+func (s *TaskService) GetProjectSavedQueries(projectID uint) ([]*models.SavedQuery, error) {
+	queries, err := s.repo.GetSavedQueries()
+	if err != nil {
+		return nil, err
+	}
+	matching := make([]*models.SavedQuery, 0, len(queries))
+	for _, query := range queries {
+		if query.ProjectID == nil || *query.ProjectID == projectID {
+			matching = append(matching, query)
+		}
+	}
+	return matching, nil
+}
+
 func (s *TaskService) GetSavedQueryByID(id uint) (*models.SavedQuery, error) {
 	return s.repo.GetSavedQueryByID(id)
 }
 
 func (s *TaskService) UpdateSavedQuery(query *models.SavedQuery) (*models.SavedQuery, error) {
+	if err := normalizeSavedQueryExpression(query); err != nil {
+		return nil, err
+	}
+	if err := s.CheckProject(query.ProjectID); err != nil {
+		return nil, err
+	}
 	query.UpdatedAt = time.Now()
 	
 	err := s.repo.UpdateSavedQuery(query)
@@ -221,6 +467,131 @@ func (s *TaskService) DeleteSavedQuery(id uint) error {
 	return s.repo.DeleteSavedQuery(id)
 }
 
+// ErrInvalidTagExpression is returned when a saved query's tag expression does not parse
+var ErrInvalidTagExpression = errors.New("invalid tag expression")
+
+// ErrInvalidSavedQueryOrder is returned when a reorder does not list every saved query exactly once
+var ErrInvalidSavedQueryOrder = errors.New("order must list every saved query exactly once")
+
+// ReorderSavedQueries sets the saved query order to ids, which must name every
+// saved query exactly once
+func (s *TaskService) ReorderSavedQueries(ids []uint) ([]*models.SavedQuery, error) {
+	queries, err := s.repo.GetSavedQueries()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) != len(queries) {
+		return nil, ErrInvalidSavedQueryOrder
+	}
+
+	known := make(map[uint]bool, len(queries))
+	for _, query := range queries {
+		known[query.ID] = true
+	}
+	for _, id := range ids {
+		if !known[id] {
+			return nil, ErrInvalidSavedQueryOrder
+		}
+		delete(known, id)
+	}
+
+	if err := s.repo.SetSavedQueryPositions(ids); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSavedQueries()
+}
+
+// CountBySavedQueries sets OpenCount, InProgressCount and WeekMinutes on each
+// query: its open and in-progress tasks, the ones in progress, and the time
+// logged on its tasks since Monday
+func (s *TaskService) CountBySavedQueries(queries []*models.SavedQuery) error {
+	today := startOfDay(time.Now())
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+
+	metrics, err := s.repo.GetSavedQueryMetrics(queries, weekStart)
+	if err != nil {
+		return err
+	}
+	for _, query := range queries {
+		m := metrics[query.ID]
+		query.OpenCount, query.InProgressCount, query.WeekMinutes = &m.Open, &m.InProgress, &m.WeekMinutes
+	}
+	return nil
+}
+
+// ErrInvalidFeedToken is returned when a feed token does not match any saved query
+var ErrInvalidFeedToken = errors.New("invalid feed token")
+
+// SavedQueryChange is a single entry in a saved query's changelog feed
+type SavedQueryChange struct {
+	Task      *models.Task
+	Kind      string // "created" or "resolved"
+	Timestamp time.Time
+}
+
+func (s *TaskService) GetSavedQueryByFeedToken(token string) (*models.SavedQuery, error) {
+	if token == "" {
+		return nil, ErrInvalidFeedToken
+	}
+
+	query, err := s.repo.GetSavedQueryByFeedToken(token)
+	if err != nil {
+		return nil, ErrInvalidFeedToken
+	}
+
+	return query, nil
+}
+
+// RegenerateFeedToken issues a new feed token for a saved query, invalidating the old feed URL
+func (s *TaskService) RegenerateFeedToken(id uint) (*models.SavedQuery, error) {
+	query, err := s.repo.GetSavedQueryByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := GenerateFeedToken()
+	if err != nil {
+		return nil, err
+	}
+
+	query.FeedToken = token
+	return s.UpdateSavedQuery(query)
+}
+
+// GetSavedQueryChanges returns tasks matching the saved query that were created or
+// resolved since the given time, newest first
+func (s *TaskService) GetSavedQueryChanges(query *models.SavedQuery, since time.Time) ([]SavedQueryChange, error) {
+	tasks, err := s.GetTasksBySavedQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []SavedQueryChange
+	for _, task := range tasks {
+		if !task.CreatedAt.Before(since) {
+			changes = append(changes, SavedQueryChange{Task: task, Kind: "created", Timestamp: task.CreatedAt})
+		}
+		if task.ResolvedAt != nil && !task.ResolvedAt.Before(since) {
+			changes = append(changes, SavedQueryChange{Task: task, Kind: "resolved", Timestamp: *task.ResolvedAt})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Timestamp.After(changes[j].Timestamp)
+	})
+
+	return changes, nil
+}
+
+// GenerateFeedToken returns a new random saved query feed token
+func GenerateFeedToken() (string, error) {
+	bytes := make([]byte, 20)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
 func (s *TaskService) GetTasksBySavedQuery(query *models.SavedQuery) ([]*models.Task, error) {
 	tasks, err := s.repo.GetAll()
 	if err != nil {
@@ -238,35 +609,43 @@ func (s *TaskService) GetTasksBySavedQuery(query *models.SavedQuery) ([]*models.
 }
 
 func (s *TaskService) matchesSavedQuery(task *models.Task, query *models.SavedQuery) bool {
-	if len(query.IncludedTags) > 0 {
-		hasIncluded := false
-		for _, includedTag := range query.IncludedTags {
-			for _, taskTag := range task.Tags {
-				if taskTag == includedTag {
-					hasIncluded = true
-					break
-				}
-			}
-			if hasIncluded {
-				break
-			}
-		}
-		if !hasIncluded {
+	return MatchesSavedQuery(task, query)
+}
+
+// MatchesSavedQuery reports whether a task is in a saved query's project, if
+// it has one, has one of its included tags, none of its excluded tags, and
+// satisfies its tag expression. A query whose expression does not parse
+// matches nothing.
+func MatchesSavedQuery(task *models.Task, query *models.SavedQuery) bool {
+	if query.ProjectID != nil && (task.ProjectID == nil || *task.ProjectID != *query.ProjectID) {
+		return false
+	}
+	if len(query.IncludedTags) > 0 && !slices.ContainsFunc(query.IncludedTags, func(tag string) bool {
+		return slices.Contains(task.Tags, tag)
+	}) {
+		return false
+	}
+	for _, tag := range query.ExcludedTags {
+		if slices.Contains(task.Tags, tag) {
 			return false
 		}
 	}
-	
-	if len(query.ExcludedTags) > 0 {
-		for _, excludedTag := range query.ExcludedTags {
-			for _, taskTag := range task.Tags {
-				if taskTag == excludedTag {
-					return false
-				}
-			}
-		}
+	if query.Expression == "" {
+		return true
 	}
-	
-	return true
+	expr, err := tagexpr.Parse(query.Expression)
+	return err == nil && expr.Matches(task.Tags)
+}
+
+// normalizeSavedQueryExpression checks a saved query's tag expression and
+// stores it in canonical form
+func normalizeSavedQueryExpression(query *models.SavedQuery) error {
+	expr, err := tagexpr.Parse(query.Expression)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTagExpression, err)
+	}
+	query.Expression = expr.String()
+	return nil
 }
 
 func (s *TaskService) AddSubtask(taskID uint, subtask *models.Subtask) error {
@@ -285,7 +664,11 @@ func (s *TaskService) AddSubtask(taskID uint, subtask *models.Subtask) error {
 		return err
 	}
 	task.UpdatedAt = time.Now()
-	return s.repo.Update(task)
+	if err := s.repo.Update(task); err != nil {
+		return err
+	}
+	s.publish(TaskEventUpdated, task)
+	return nil
 }
 
 func (s *TaskService) GetSubtask(subtaskID uint) (*models.Subtask, error) {
@@ -306,7 +689,11 @@ func (s *TaskService) UpdateSubtask(taskID uint, subtask *models.Subtask) error
 		return err
 	}
 	task.UpdatedAt = time.Now()
-	return s.repo.Update(task)
+	if err := s.repo.Update(task); err != nil {
+		return err
+	}
+	s.publish(TaskEventUpdated, task)
+	return nil
 }
 
 func (s *TaskService) ToggleSubtask(taskID uint, subtaskID uint) error {
@@ -321,17 +708,30 @@ func (s *TaskService) ToggleSubtask(taskID uint, subtaskID uint) error {
 		return err
 	}
 	task.UpdatedAt = time.Now()
-	return s.repo.Update(task)
+	if err := s.repo.Update(task); err != nil {
+		return err
+	}
+	s.publish(TaskEventUpdated, task)
+	return nil
 }
 
 func (s *TaskService) DeleteSubtask(taskID uint, subtaskID uint) error {
-	return s.repo.DeleteSubtask(subtaskID)
+	if err := s.repo.DeleteSubtask(subtaskID); err != nil {
+		return err
+	}
+	s.events.Publish(TaskEvent{Type: TaskEventUpdated, TaskID: taskID})
+	return nil
 }
 
 func (s *TaskService) GetAttachment(attachmentID uint) (*models.Attachment, error) {
 	return s.repo.GetAttachment(attachmentID)
 }
 
+// GetTaskAttachments returns the files attached to a task or to any of its comments
+func (s *TaskService) GetTaskAttachments(taskID uint) ([]models.Attachment, error) {
+	return s.repo.GetAttachmentsByTask(taskID)
+}
+
 func (s *TaskService) AddAttachment(attachment *models.Attachment) error {
 	attachment.CreatedAt = time.Now()
 	attachment.UpdatedAt = time.Now()
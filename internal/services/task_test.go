@@ -1,9 +1,15 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/quickadd"
 	"github.com/soarinferret/jats/internal/repository"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -22,6 +28,30 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		&models.Comment{},
 		&models.TaskSubscriber{},
 		&models.Attachment{},
+		&models.SavedQuery{},
+		&models.SavedQuerySchedule{},
+		&models.SavedQueryWebhook{},
+		&models.TaskStatusChange{},
+		&models.TaskReference{},
+		&models.Milestone{},
+		&models.BrandingSettings{},
+		&models.CannedResponse{},
+		&models.Contact{},
+		&models.QuarantinedEmail{},
+		&models.ExternalIssue{},
+		&models.ExternalComment{},
+		&models.NotificationMute{},
+		&models.OutOfOffice{},
+		&models.Asset{},
+		&models.Project{},
+		&models.TaskReview{},
+		&models.TaskDependency{},
+		&models.EmailReceipt{},
+		&models.ScheduledAction{},
+		&models.AutomationRule{},
+		&models.TaskAssignment{},
+		&models.Tenant{},
+		&models.EmailMessage{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate database: %v", err)
@@ -183,6 +213,37 @@ func TestTaskService_UpdateTask_StatusToResolved(t *testing.T) {
 	}
 }
 
+func TestTaskService_UpdateTask_FailedUpdateRecordsNoHistory(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	task, err := service.CreateTask("Task that fails to save")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	// Fail every task update from here on
+	db.Callback().Update().Before("gorm:update").Register("test:fail_update", func(tx *gorm.DB) {
+		if tx.Statement.Table == "tasks" {
+			tx.AddError(fmt.Errorf("update failed"))
+		}
+	})
+
+	task.Status = models.TaskStatusInProgress
+	if err := service.UpdateTask(task); err == nil {
+		t.Fatal("Expected the update to fail")
+	}
+
+	history, err := service.GetStatusHistory(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get status history: %v", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("Expected no status history for a failed update, got %d entries", len(history))
+	}
+}
+
 func TestTaskService_DeleteTask(t *testing.T) {
 	db := setupTestDB(t)
 	repo := repository.NewTaskRepository(db)
@@ -242,6 +303,66 @@ func TestTaskService_AddTimeEntry(t *testing.T) {
 	}
 }
 
+func TestTaskService_SubtaskTimeRollup(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	task, err := service.CreateTask("Task with estimates")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	other, err := service.CreateTask("Unrelated task")
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	subtask := &models.Subtask{Name: "Write docs", EstimateMinutes: 90}
+	if err := service.AddSubtask(task.ID, subtask); err != nil {
+		t.Fatalf("Failed to add subtask: %v", err)
+	}
+
+	if err := service.AddTimeEntry(task.ID, &models.TimeEntry{Duration: 30, SubtaskID: &subtask.ID}); err != nil {
+		t.Fatalf("Failed to add subtask time entry: %v", err)
+	}
+	if err := service.AddTimeEntry(task.ID, &models.TimeEntry{Duration: 20}); err != nil {
+		t.Fatalf("Failed to add task time entry: %v", err)
+	}
+
+	// Time cannot be booked against another task's subtask
+	err = service.AddTimeEntry(other.ID, &models.TimeEntry{Duration: 10, SubtaskID: &subtask.ID})
+	if err != ErrSubtaskNotInTask {
+		t.Errorf("Expected ErrSubtaskNotInTask, got %v", err)
+	}
+
+	loaded, err := service.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+
+	if loaded.EstimateMinutes != 90 {
+		t.Errorf("Expected estimate 90, got %d", loaded.EstimateMinutes)
+	}
+	if loaded.LoggedMinutes != 50 {
+		t.Errorf("Expected logged 50, got %d", loaded.LoggedMinutes)
+	}
+	if loaded.Subtasks[0].LoggedMinutes != 30 {
+		t.Errorf("Expected subtask logged 30, got %d", loaded.Subtasks[0].LoggedMinutes)
+	}
+
+	// Deleting the subtask keeps its time on the parent task
+	if err := service.DeleteSubtask(task.ID, subtask.ID); err != nil {
+		t.Fatalf("Failed to delete subtask: %v", err)
+	}
+	loaded, err = service.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if loaded.LoggedMinutes != 50 || loaded.EstimateMinutes != 0 {
+		t.Errorf("Expected logged 50 and estimate 0 after delete, got %d and %d", loaded.LoggedMinutes, loaded.EstimateMinutes)
+	}
+}
+
 func TestTaskService_AddComment(t *testing.T) {
 	db := setupTestDB(t)
 	repo := repository.NewTaskRepository(db)
@@ -355,3 +476,324 @@ func TestTaskService_WithNotifications(t *testing.T) {
 		t.Errorf("Expected new status %s, got %s", task.Status, mockNotifier.lastNewStatus)
 	}
 }
+
+func TestTaskService_SavedQueryFeed(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	query, err := service.CreateSavedQuery(&models.SavedQuery{Name: "Backend", IncludedTags: []string{"backend"}, FeedToken: "chosen-by-client"})
+	if err != nil {
+		t.Fatalf("Failed to create saved query: %v", err)
+	}
+	if query.FeedToken == "" || query.FeedToken == "chosen-by-client" {
+		t.Fatalf("Expected a feed token generated by the server, got %q", query.FeedToken)
+	}
+
+	found, err := service.GetSavedQueryByFeedToken(query.FeedToken)
+	if err != nil || found.ID != query.ID {
+		t.Fatalf("Expected to find saved query by feed token, got %v", err)
+	}
+	if _, err := service.GetSavedQueryByFeedToken(""); err != ErrInvalidFeedToken {
+		t.Errorf("Expected ErrInvalidFeedToken for empty token, got %v", err)
+	}
+
+	// Old task outside the window, resolved recently
+	old, _ := service.CreateTaskWithDate("Old task", time.Now().AddDate(0, 0, -30))
+	old.Tags = []string{"backend"}
+	old.Status = models.TaskStatusResolved
+	if err := service.UpdateTask(old); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	// New task matching the query
+	fresh, _ := service.CreateTask("Fresh task")
+	fresh.Tags = []string{"backend"}
+	if err := service.UpdateTask(fresh); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	// New task not matching the query
+	other, _ := service.CreateTask("Other task")
+	other.Tags = []string{"frontend"}
+	service.UpdateTask(other)
+
+	changes, err := service.GetSavedQueryChanges(query, time.Now().AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatalf("Failed to get changes: %v", err)
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %d", len(changes))
+	}
+
+	kinds := map[string]uint{}
+	for _, change := range changes {
+		kinds[change.Kind] = change.Task.ID
+	}
+	if kinds["created"] != fresh.ID {
+		t.Errorf("Expected created entry for task %d, got %d", fresh.ID, kinds["created"])
+	}
+	if kinds["resolved"] != old.ID {
+		t.Errorf("Expected resolved entry for task %d, got %d", old.ID, kinds["resolved"])
+	}
+
+	previousToken := query.FeedToken
+	regenerated, err := service.RegenerateFeedToken(query.ID)
+	if err != nil {
+		t.Fatalf("Failed to regenerate feed token: %v", err)
+	}
+	if regenerated.FeedToken == previousToken {
+		t.Error("Expected feed token to change")
+	}
+	if _, err := service.GetSavedQueryByFeedToken(previousToken); err == nil {
+		t.Error("Expected old feed token to be rejected")
+	}
+}
+
+func TestTaskService_ReorderSavedQueries(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	var ids []uint
+	for _, name := range []string{"Zeta", "Alpha", "Mid"} {
+		query, err := service.CreateSavedQuery(&models.SavedQuery{Name: name, IncludedTags: []string{"backend"}})
+		if err != nil {
+			t.Fatalf("Failed to create saved query: %v", err)
+		}
+		ids = append(ids, query.ID)
+	}
+
+	// New queries are listed in creation order, not by name
+	queries, err := service.GetSavedQueries()
+	if err != nil {
+		t.Fatalf("Failed to get saved queries: %v", err)
+	}
+	if len(queries) != 3 || queries[0].Name != "Zeta" || queries[2].Name != "Mid" {
+		t.Fatalf("Expected queries in creation order, got %v", queries)
+	}
+
+	if _, err := service.ReorderSavedQueries([]uint{ids[0], ids[1]}); err != ErrInvalidSavedQueryOrder {
+		t.Errorf("Expected ErrInvalidSavedQueryOrder for a partial order, got %v", err)
+	}
+	if _, err := service.ReorderSavedQueries([]uint{ids[0], ids[0], ids[1]}); err != ErrInvalidSavedQueryOrder {
+		t.Errorf("Expected ErrInvalidSavedQueryOrder for a duplicate ID, got %v", err)
+	}
+
+	queries, err = service.ReorderSavedQueries([]uint{ids[2], ids[0], ids[1]})
+	if err != nil {
+		t.Fatalf("Failed to reorder saved queries: %v", err)
+	}
+	if queries[0].Name != "Mid" || queries[1].Name != "Zeta" || queries[2].Name != "Alpha" {
+		t.Errorf("Expected Mid, Zeta, Alpha, got %s, %s, %s", queries[0].Name, queries[1].Name, queries[2].Name)
+	}
+
+	open, _ := service.CreateTask("Open backend task")
+	open.Tags = []string{"backend"}
+	service.UpdateTask(open)
+	resolved, _ := service.CreateTask("Resolved backend task")
+	resolved.Tags = []string{"backend"}
+	resolved.Status = models.TaskStatusResolved
+	service.UpdateTask(resolved)
+
+	started, _ := service.CreateTask("Backend task in progress")
+	started.Tags = []string{"backend", "blocked_on_vendor"}
+	started.Status = models.TaskStatusInProgress
+	service.UpdateTask(started)
+	service.AddTimeEntry(started.ID, &models.TimeEntry{Duration: 45})
+	service.AddTimeEntry(resolved.ID, &models.TimeEntry{Duration: 30})
+
+	if err := service.CountBySavedQueries(queries); err != nil {
+		t.Fatalf("Failed to count tasks: %v", err)
+	}
+	if queries[0].OpenCount == nil || *queries[0].OpenCount != 2 {
+		t.Errorf("Expected 2 open tasks, got %v", queries[0].OpenCount)
+	}
+	if *queries[0].InProgressCount != 1 || *queries[0].WeekMinutes != 75 {
+		t.Errorf("Expected 1 task in progress and 75 minutes this week, got %d and %d", *queries[0].InProgressCount, *queries[0].WeekMinutes)
+	}
+
+	// Tags with LIKE wildcards are matched literally
+	vendor, _ := service.CreateSavedQuery(&models.SavedQuery{Name: "Vendor", IncludedTags: []string{"blocked_on_vendor"}})
+	wildcard, _ := service.CreateSavedQuery(&models.SavedQuery{Name: "Wildcard", IncludedTags: []string{"blocked%"}})
+	notVendor, _ := service.CreateSavedQuery(&models.SavedQuery{Name: "Not vendor", IncludedTags: []string{"backend"}, ExcludedTags: []string{"blocked_on_vendor"}})
+	extra := []*models.SavedQuery{vendor, wildcard, notVendor}
+	if err := service.CountBySavedQueries(extra); err != nil {
+		t.Fatalf("Failed to count tasks: %v", err)
+	}
+	if *vendor.OpenCount != 1 || *wildcard.OpenCount != 0 || *notVendor.OpenCount != 1 || *notVendor.WeekMinutes != 30 {
+		t.Errorf("Expected 1, 0 and 1 open tasks and 30 minutes, got %d, %d, %d and %d",
+			*vendor.OpenCount, *wildcard.OpenCount, *notVendor.OpenCount, *notVendor.WeekMinutes)
+	}
+}
+
+func TestTaskService_SavedQueryExpression(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	for name, tags := range map[string][]string{
+		"Client one":          {"client1"},
+		"Client two":          {"client2", "urgent"},
+		"Internal client one": {"client1", "internal"},
+		"Other":               {"client3"},
+		"Untagged":            nil,
+	} {
+		task, _ := service.CreateTask(name)
+		task.Tags = tags
+		service.UpdateTask(task)
+	}
+
+	query, err := service.CreateSavedQuery(&models.SavedQuery{Name: "Clients", Expression: "(client1 or client2) and not internal"})
+	if err != nil {
+		t.Fatalf("Failed to create saved query: %v", err)
+	}
+	if query.Expression != "(client1 OR client2) AND NOT internal" {
+		t.Errorf("Expected the expression in canonical form, got %q", query.Expression)
+	}
+
+	tasks, err := service.GetTasksBySavedQuery(query)
+	if err != nil {
+		t.Fatalf("Failed to get tasks: %v", err)
+	}
+	var names []string
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ", ") != "Client one, Client two" {
+		t.Errorf("Expected Client one and Client two, got %v", names)
+	}
+
+	// The SQL counts agree with the matching in Go, including for untagged tasks
+	notInternal, _ := service.CreateSavedQuery(&models.SavedQuery{Name: "Not internal", Expression: "NOT internal"})
+	if err := service.CountBySavedQueries([]*models.SavedQuery{query, notInternal}); err != nil {
+		t.Fatalf("Failed to count tasks: %v", err)
+	}
+	if *query.OpenCount != 2 || *notInternal.OpenCount != 4 {
+		t.Errorf("Expected 2 and 4 open tasks, got %d and %d", *query.OpenCount, *notInternal.OpenCount)
+	}
+
+	if _, err := service.CreateSavedQuery(&models.SavedQuery{Name: "Broken", Expression: "(client1 OR"}); !errors.Is(err, ErrInvalidTagExpression) {
+		t.Errorf("Expected ErrInvalidTagExpression, got %v", err)
+	}
+	query.Expression = "AND"
+	if _, err := service.UpdateSavedQuery(query); !errors.Is(err, ErrInvalidTagExpression) {
+		t.Errorf("Expected ErrInvalidTagExpression on update, got %v", err)
+	}
+}
+
+func TestTaskService_GetTaskAttachments(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	task, _ := service.CreateTask("Task with files")
+	other, _ := service.CreateTask("Other task")
+
+	comment := &models.Comment{Content: "See attached"}
+	if err := service.AddComment(task.ID, comment); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+
+	attachments := []*models.Attachment{
+		{TaskID: &task.ID, FileName: "a.txt", OriginalName: "a.txt", FilePath: "a.txt", Size: 10},
+		{CommentID: &comment.ID, FileName: "b.pdf", OriginalName: "b.pdf", FilePath: "b.pdf", Size: 20},
+		{TaskID: &other.ID, FileName: "c.png", OriginalName: "c.png", FilePath: "c.png", Size: 30},
+	}
+	for _, attachment := range attachments {
+		if err := service.AddAttachment(attachment); err != nil {
+			t.Fatalf("Failed to add attachment: %v", err)
+		}
+	}
+
+	result, err := service.GetTaskAttachments(task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get attachments: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 attachments (task and comment), got %d", len(result))
+	}
+	names := map[string]bool{result[0].OriginalName: true, result[1].OriginalName: true}
+	if !names["a.txt"] || !names["b.pdf"] {
+		t.Errorf("Expected a.txt and b.pdf, got %v", names)
+	}
+}
+
+func TestTaskService_CreateQuickTask(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	entry, err := quickadd.Parse("@client1 restart +docker container -t 45m -p high -d -1d -c")
+	if err != nil {
+		t.Fatalf("Failed to parse quick-add line: %v", err)
+	}
+
+	task, err := service.CreateQuickTask(entry)
+	if err != nil {
+		t.Fatalf("Failed to create quick task: %v", err)
+	}
+
+	if task.Name != "restart docker container" {
+		t.Errorf("Expected name 'restart docker container', got %q", task.Name)
+	}
+	if task.Priority != models.TaskPriorityHigh {
+		t.Errorf("Expected high priority, got %q", task.Priority)
+	}
+	if len(task.Tags) != 2 {
+		t.Errorf("Expected 2 tags, got %v", task.Tags)
+	}
+	if task.Status != models.TaskStatusResolved {
+		t.Errorf("Expected resolved status, got %q", task.Status)
+	}
+	if len(task.TimeEntries) != 1 || task.TimeEntries[0].Duration != 45 {
+		t.Errorf("Expected one 45 minute time entry, got %+v", task.TimeEntries)
+	}
+	if want := time.Now().AddDate(0, 0, -1).Format("2006-01-02"); task.CreatedAt.Format("2006-01-02") != want {
+		t.Errorf("Expected creation date %s, got %s", want, task.CreatedAt.Format("2006-01-02"))
+	}
+}
+
+func TestTaskService_References(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	target, _ := service.CreateTask("Database migration")
+	other, _ := service.CreateTask("Release notes")
+	source, _ := service.CreateTask("Deploy")
+
+	source.Description = fmt.Sprintf("Needs #%d first, then #%d. Ignore #%d and #9999.", target.ID, target.ID, source.ID)
+	if err := service.UpdateTask(source); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if err := service.AddComment(other.ID, &models.Comment{Content: fmt.Sprintf("Mention #%d here", target.ID)}); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+
+	loaded, err := service.GetTask(source.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if len(loaded.References) != 1 || loaded.References[0].ID != target.ID {
+		t.Errorf("Expected a single reference to #%d, got %+v", target.ID, loaded.References)
+	}
+
+	loaded, err = service.GetTask(target.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if len(loaded.ReferencedBy) != 2 || loaded.ReferencedBy[0].ID != other.ID || loaded.ReferencedBy[1].ID != source.ID {
+		t.Errorf("Expected backlinks from #%d and #%d, got %+v", other.ID, source.ID, loaded.ReferencedBy)
+	}
+	if loaded.ReferencedBy[1].Name != "Deploy" {
+		t.Errorf("Expected backlink name 'Deploy', got %q", loaded.ReferencedBy[1].Name)
+	}
+
+	// Editing the description replaces its references but keeps those from notes
+	source.Description = "No longer blocked"
+	if err := service.UpdateTask(source); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	loaded, _ = service.GetTask(target.ID)
+	if len(loaded.ReferencedBy) != 1 || loaded.ReferencedBy[0].ID != other.ID {
+		t.Errorf("Expected only the backlink from #%d, got %+v", other.ID, loaded.ReferencedBy)
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+var (
+	ErrTenantNotFound    = errors.New("tenant not found")
+	ErrInvalidTenantSlug = errors.New("tenant slug must be 2-32 lowercase letters, numbers, and dashes, not starting or ending with a dash")
+	ErrInvalidTenantName = errors.New("tenant name is required")
+	ErrDuplicateTenant   = errors.New("a tenant with this slug already exists")
+)
+
+// TenantAdminUsername is the initial admin user of a new tenant
+const TenantAdminUsername = "jats-admin"
+
+var tenantSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,30}[a-z0-9]$`)
+
+// TenantHost runs the instances of tenants, see TenantService.SetHost
+type TenantHost interface {
+	// Provision prepares a new tenant's database and returns the password of
+	// its initial admin user
+	Provision(tenant *models.Tenant) (adminPassword string, err error)
+	// Evict stops serving a deleted tenant
+	Evict(tenant *models.Tenant)
+}
+
+// TenantService manages the tenants of a multi-tenant instance
+type TenantService struct {
+	repo *repository.TenantRepository
+	host TenantHost
+}
+
+// NewTenantService creates a new tenant service
+func NewTenantService(repo *repository.TenantRepository) *TenantService {
+	return &TenantService{repo: repo}
+}
+
+// SetHost sets what provisions and serves tenant instances
+func (s *TenantService) SetHost(host TenantHost) {
+	s.host = host
+}
+
+// ListTenants returns all tenants ordered by slug
+func (s *TenantService) ListTenants() ([]*models.Tenant, error) {
+	return s.repo.List()
+}
+
+// GetTenant returns a tenant by slug
+func (s *TenantService) GetTenant(slug string) (*models.Tenant, error) {
+	tenant, err := s.repo.GetBySlug(strings.ToLower(slug))
+	if err != nil {
+		return nil, err
+	}
+	if tenant == nil {
+		return nil, ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+// CreateTenant validates, records and provisions a new tenant. It returns the
+// password of the tenant's initial admin user, which is not stored anywhere.
+func (s *TenantService) CreateTenant(slug, name string) (*models.Tenant, string, error) {
+	tenant := &models.Tenant{
+		Slug:      strings.ToLower(strings.TrimSpace(slug)),
+		Name:      strings.TrimSpace(name),
+		CreatedAt: time.Now(),
+	}
+	if !tenantSlugPattern.MatchString(tenant.Slug) {
+		return nil, "", ErrInvalidTenantSlug
+	}
+	if tenant.Name == "" {
+		return nil, "", ErrInvalidTenantName
+	}
+
+	existing, err := s.repo.GetBySlug(tenant.Slug)
+	if err != nil {
+		return nil, "", err
+	}
+	if existing != nil {
+		return nil, "", ErrDuplicateTenant
+	}
+
+	if err := s.repo.Create(tenant); err != nil {
+		return nil, "", err
+	}
+
+	password := ""
+	if s.host != nil {
+		if password, err = s.host.Provision(tenant); err != nil {
+			// Leave no half-made tenant behind
+			s.repo.Delete(tenant.ID)
+			return nil, "", fmt.Errorf("failed to provision tenant: %w", err)
+		}
+	}
+	return tenant, password, nil
+}
+
+// DeleteTenant stops serving a tenant and removes it from the registry. Its
+// database is kept, so an operator can archive or restore it.
+func (s *TenantService) DeleteTenant(slug string) error {
+	tenant, err := s.GetTenant(slug)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(tenant.ID); err != nil {
+		return err
+	}
+	if s.host != nil {
+		s.host.Evict(tenant)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+// fakeTenantHost records what the tenant service asks of it
+type fakeTenantHost struct {
+	provisioned []string
+	evicted     []string
+	fail        bool
+}
+
+func (h *fakeTenantHost) Provision(tenant *models.Tenant) (string, error) {
+	if h.fail {
+		return "", errors.New("disk full")
+	}
+	h.provisioned = append(h.provisioned, tenant.Slug)
+	return "secret", nil
+}
+
+func (h *fakeTenantHost) Evict(tenant *models.Tenant) {
+	h.evicted = append(h.evicted, tenant.Slug)
+}
+
+func TestTenantService(t *testing.T) {
+	db := setupTestDB(t)
+	host := &fakeTenantHost{}
+	service := NewTenantService(repository.NewTenantRepository(db))
+	service.SetHost(host)
+
+	tenant, password, err := service.CreateTenant(" Acme ", "Acme Corp")
+	if err != nil {
+		t.Fatalf("Failed to create tenant: %v", err)
+	}
+	if tenant.Slug != "acme" || password != "secret" || len(host.provisioned) != 1 {
+		t.Errorf("Expected tenant acme to be provisioned, got %+v with password %q", tenant, password)
+	}
+
+	for _, tt := range []struct {
+		slug, name string
+		err        error
+	}{
+		{"acme", "Again", ErrDuplicateTenant},
+		{"a", "Too short", ErrInvalidTenantSlug},
+		{"-acme", "Leading dash", ErrInvalidTenantSlug},
+		{"acme.corp", "Dot", ErrInvalidTenantSlug},
+		{"globex", " ", ErrInvalidTenantName},
+	} {
+		if _, _, err := service.CreateTenant(tt.slug, tt.name); !errors.Is(err, tt.err) {
+			t.Errorf("Slug %q: expected %v, got %v", tt.slug, tt.err, err)
+		}
+	}
+
+	// A tenant that fails to provision is not kept
+	host.fail = true
+	if _, _, err := service.CreateTenant("initech", "Initech"); err == nil {
+		t.Error("Expected provisioning failure to be returned")
+	}
+	if _, err := service.GetTenant("initech"); !errors.Is(err, ErrTenantNotFound) {
+		t.Errorf("Expected the failed tenant to be removed, got %v", err)
+	}
+
+	if err := service.DeleteTenant("acme"); err != nil {
+		t.Fatalf("Failed to delete tenant: %v", err)
+	}
+	if len(host.evicted) != 1 {
+		t.Errorf("Expected the deleted tenant to be evicted, got %v", host.evicted)
+	}
+	if tenants, _ := service.ListTenants(); len(tenants) != 0 {
+		t.Errorf("Expected no tenants left, got %d", len(tenants))
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// ErrTimeEntryNotFound is returned when a time entry does not exist or belongs to another task
+var ErrTimeEntryNotFound = errors.New("time entry not found")
+
+// TimeEntryWithTask is a time entry listed across tasks, with the name of its task
+type TimeEntryWithTask struct {
+	models.TimeEntry
+	TaskName string `json:"task_name"`
+}
+
+// TimeEntryUpdate holds the fields of a time entry to change; nil fields are kept
+type TimeEntryUpdate struct {
+	Duration    *int
+	Description *string
+	Date        *time.Time // moves the entry to another day, keeping its time of day
+	Billable    *bool
+}
+
+// GetTimeEntries returns the time logged on a task, oldest first
+func (s *TaskService) GetTimeEntries(taskID uint) ([]*models.TimeEntry, error) {
+	return s.repo.GetTimeEntries(taskID)
+}
+
+// ListTimeEntries returns the time logged on any task in [since, until), newest
+// first. A zero since or until leaves that end open; a non-nil billable only
+// returns billable or non-billable time.
+func (s *TaskService) ListTimeEntries(since, until time.Time, billable *bool) ([]*TimeEntryWithTask, error) {
+	entries, err := s.repo.GetTimeEntriesBetween(since, until)
+	if err != nil {
+		return nil, err
+	}
+	if billable != nil {
+		entries = FilterBillable(entries, *billable)
+	}
+
+	var taskIDs []uint
+	seen := make(map[uint]bool)
+	for _, entry := range entries {
+		if !seen[entry.TaskID] {
+			seen[entry.TaskID] = true
+			taskIDs = append(taskIDs, entry.TaskID)
+		}
+	}
+	tasks, err := s.repo.GetByIDs(taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(tasks))
+	for _, task := range tasks {
+		names[task.ID] = task.Name
+	}
+
+	items := make([]*TimeEntryWithTask, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, &TimeEntryWithTask{TimeEntry: *entry, TaskName: names[entry.TaskID]})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	return items, nil
+}
+
+// FilterBillable keeps the billable or the non-billable entries, in place
+func FilterBillable(entries []*models.TimeEntry, billable bool) []*models.TimeEntry {
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.IsBillable() == billable {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// getTaskTimeEntry returns a time entry, checking that it belongs to taskID
+func (s *TaskService) getTaskTimeEntry(taskID, entryID uint) (*models.TimeEntry, error) {
+	entry, err := s.repo.GetTimeEntry(entryID)
+	if err != nil || entry.TaskID != taskID {
+		return nil, ErrTimeEntryNotFound
+	}
+	return entry, nil
+}
+
+// UpdateTimeEntry changes the duration, description or day of a time entry
+func (s *TaskService) UpdateTimeEntry(taskID, entryID uint, update TimeEntryUpdate) (*models.TimeEntry, error) {
+	entry, err := s.getTaskTimeEntry(taskID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Duration != nil {
+		if *update.Duration <= 0 {
+			return nil, ErrInvalidDuration
+		}
+		entry.Duration = *update.Duration
+	}
+	if update.Description != nil {
+		entry.Description = *update.Description
+	}
+	if update.Billable != nil {
+		billable := *update.Billable
+		entry.Billable = &billable
+	}
+	if update.Date != nil {
+		date := *update.Date
+		created := entry.CreatedAt.In(date.Location())
+		entry.CreatedAt = time.Date(date.Year(), date.Month(), date.Day(),
+			created.Hour(), created.Minute(), created.Second(), created.Nanosecond(), date.Location())
+	}
+	entry.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateTimeEntry(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteTimeEntry removes a time entry from a task
+func (s *TaskService) DeleteTimeEntry(taskID, entryID uint) error {
+	if _, err := s.getTaskTimeEntry(taskID, entryID); err != nil {
+		return err
+	}
+	return s.repo.DeleteTimeEntry(entryID)
+}
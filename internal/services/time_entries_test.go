@@ -0,0 +1,132 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_TimeEntries(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewTaskService(repository.NewTaskRepository(db), nil)
+
+	task, _ := service.CreateTask("Write report")
+	other, _ := service.CreateTask("Review PR")
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	old := &models.TimeEntry{Duration: 30, Description: "Outline"}
+	if err := service.AddTimeEntryWithDate(task.ID, old, yesterday); err != nil {
+		t.Fatalf("Failed to add time entry: %v", err)
+	}
+	recent := &models.TimeEntry{Duration: 45, Description: "Review"}
+	if err := service.AddTimeEntry(other.ID, recent); err != nil {
+		t.Fatalf("Failed to add time entry: %v", err)
+	}
+
+	entries, err := service.ListTimeEntries(startOfDay(yesterday), time.Time{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to list time entries: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != recent.ID || entries[0].TaskName != "Review PR" {
+		t.Fatalf("Expected the newest entry first with its task name, got %+v", entries)
+	}
+
+	// Entries are only reachable through their own task
+	if _, err := service.UpdateTimeEntry(other.ID, old.ID, TimeEntryUpdate{}); err != ErrTimeEntryNotFound {
+		t.Errorf("Expected ErrTimeEntryNotFound for another task's entry, got %v", err)
+	}
+
+	zero := 0
+	if _, err := service.UpdateTimeEntry(task.ID, old.ID, TimeEntryUpdate{Duration: &zero}); err != ErrInvalidDuration {
+		t.Errorf("Expected ErrInvalidDuration, got %v", err)
+	}
+
+	duration, description, today := 60, "Outline and draft", time.Now()
+	updated, err := service.UpdateTimeEntry(task.ID, old.ID, TimeEntryUpdate{Duration: &duration, Description: &description, Date: &today})
+	if err != nil {
+		t.Fatalf("Failed to update time entry: %v", err)
+	}
+	if updated.Duration != 60 || updated.Description != description {
+		t.Errorf("Expected the duration and description to change, got %+v", updated)
+	}
+	if !startOfDay(updated.CreatedAt).Equal(startOfDay(today)) {
+		t.Errorf("Expected the entry to move to today, got %s", updated.CreatedAt)
+	}
+
+	if err := service.DeleteTimeEntry(task.ID, old.ID); err != nil {
+		t.Fatalf("Failed to delete time entry: %v", err)
+	}
+	remaining, _ := service.GetTimeEntries(task.ID)
+	if len(remaining) != 0 {
+		t.Errorf("Expected no time entries left on the task, got %d", len(remaining))
+	}
+}
+
+func TestTaskService_BillableTime(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+	service.SetNonBillableTags([]string{" Internal "})
+
+	client := &models.Task{Name: "Client bug", Status: models.TaskStatusOpen, Tags: []string{"acme"}}
+	internal := &models.Task{Name: "Team meeting", Status: models.TaskStatusOpen, Tags: []string{"internal"}}
+	for _, task := range []*models.Task{client, internal} {
+		if err := repo.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	billed := &models.TimeEntry{Duration: 60}
+	unbilled := &models.TimeEntry{Duration: 30}
+	nonBillable := false
+	overridden := &models.TimeEntry{Duration: 15, Billable: &nonBillable}
+	for _, add := range []struct {
+		taskID uint
+		entry  *models.TimeEntry
+	}{{client.ID, billed}, {internal.ID, unbilled}, {client.ID, overridden}} {
+		if err := service.AddTimeEntry(add.taskID, add.entry); err != nil {
+			t.Fatalf("Failed to add time entry: %v", err)
+		}
+	}
+
+	// Stored flags survive a reload, including an explicit false
+	for _, tt := range []struct {
+		entry *models.TimeEntry
+		want  bool
+	}{{billed, true}, {unbilled, false}, {overridden, false}} {
+		stored, err := repo.GetTimeEntry(tt.entry.ID)
+		if err != nil {
+			t.Fatalf("Failed to get time entry: %v", err)
+		}
+		if stored.IsBillable() != tt.want {
+			t.Errorf("Expected entry of %d minutes to be billable=%t", tt.entry.Duration, tt.want)
+		}
+	}
+
+	billable := true
+	entries, err := service.ListTimeEntries(time.Time{}, time.Time{}, &billable)
+	if err != nil {
+		t.Fatalf("Failed to list time entries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != billed.ID {
+		t.Errorf("Expected only the billable entry, got %+v", entries)
+	}
+
+	updated, err := service.UpdateTimeEntry(client.ID, overridden.ID, TimeEntryUpdate{Billable: &billable})
+	if err != nil {
+		t.Fatalf("Failed to update time entry: %v", err)
+	}
+	if !updated.IsBillable() {
+		t.Error("Expected the entry to become billable")
+	}
+
+	bulk := []*models.TimeEntry{{TaskID: internal.ID, Duration: 10}, {TaskID: client.ID, Duration: 20}}
+	if err := service.AddTimeEntries(bulk); err != nil {
+		t.Fatalf("Failed to add time entries: %v", err)
+	}
+	if stored, err := repo.GetTimeEntry(bulk[0].ID); err != nil || stored.IsBillable() || !bulk[1].IsBillable() {
+		t.Errorf("Expected bulk entries to default from their task's tags")
+	}
+}
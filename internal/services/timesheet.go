@@ -0,0 +1,252 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// minutesPerDay caps the time one timesheet cell can hold
+const minutesPerDay = 24 * 60
+
+var ErrInvalidTimesheetMinutes = fmt.Errorf("minutes must be between 0 and %d", minutesPerDay)
+
+// Timesheet is the time a user logged in one week, as a grid of tasks by days
+type Timesheet struct {
+	WeekStart time.Time       `json:"week_start"`
+	Days      []time.Time     `json:"days"`
+	Rows      []*TimesheetRow `json:"rows"`
+	Totals    []int           `json:"totals"` // minutes per day
+	Total     int             `json:"total"`
+}
+
+// TimesheetRow is the time logged on one task, in minutes per day of the week
+type TimesheetRow struct {
+	TaskID   uint   `json:"task_id"`
+	TaskName string `json:"task_name"`
+	Minutes  []int  `json:"minutes"`
+	Total    int    `json:"total"`
+}
+
+// TimesheetCell sets the total time a user logged on a task in one day
+type TimesheetCell struct {
+	TaskID  uint
+	Date    time.Time
+	Minutes int
+}
+
+// StartOfWeek returns midnight on the Monday of t's week
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
+
+// GetTimesheet returns the time a user logged in the week containing
+// weekStart, one row per task ordered by task name
+func (s *TaskService) GetTimesheet(username string, weekStart time.Time) (*Timesheet, error) {
+	weekStart = StartOfWeek(weekStart.In(time.Local))
+	sheet := &Timesheet{WeekStart: weekStart, Rows: []*TimesheetRow{}, Totals: make([]int, 7)}
+	dayIndex := make(map[string]int, 7)
+	for i := 0; i < 7; i++ {
+		day := weekStart.AddDate(0, 0, i)
+		sheet.Days = append(sheet.Days, day)
+		dayIndex[day.Format(time.DateOnly)] = i
+	}
+
+	entries, err := s.repo.GetTimeEntriesBetween(weekStart, weekStart.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make(map[uint]*TimesheetRow)
+	var taskIDs []uint
+	for _, entry := range entries {
+		day, ok := dayIndex[entry.CreatedAt.In(time.Local).Format(time.DateOnly)]
+		if !ok || entry.CreatedBy != username {
+			continue
+		}
+		row, ok := rows[entry.TaskID]
+		if !ok {
+			row = &TimesheetRow{TaskID: entry.TaskID, Minutes: make([]int, 7)}
+			rows[entry.TaskID] = row
+			taskIDs = append(taskIDs, entry.TaskID)
+			sheet.Rows = append(sheet.Rows, row)
+		}
+		row.Minutes[day] += entry.Duration
+		row.Total += entry.Duration
+		sheet.Totals[day] += entry.Duration
+		sheet.Total += entry.Duration
+	}
+
+	tasks, err := s.repo.GetByIDs(taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		rows[task.ID].TaskName = task.Name
+	}
+	sort.SliceStable(sheet.Rows, func(i, j int) bool {
+		if sheet.Rows[i].TaskName != sheet.Rows[j].TaskName {
+			return sheet.Rows[i].TaskName < sheet.Rows[j].TaskName
+		}
+		return sheet.Rows[i].TaskID < sheet.Rows[j].TaskID
+	})
+	return sheet, nil
+}
+
+// SetTimesheetCells sets the total time a user logged on tasks on given days,
+// creating, growing, shrinking or deleting their entries to match. An empty
+// cell gets one new entry; more time is added to the cell's newest entry and
+// less is taken off its newest entries first, deleting those it uses up.
+// Other users' entries are left alone. Either every cell is saved or none is;
+// errors name the 1-based position of the offending cell. Like AddTimeEntry,
+// open tasks that receive time move to in-progress.
+func (s *TaskService) SetTimesheetCells(username string, cells []TimesheetCell) error {
+	if len(cells) > MaxBulkEntries {
+		return ErrTooManyBulkEntries
+	}
+	if len(cells) == 0 {
+		return nil
+	}
+
+	tasks, err := s.loadBulkTasks(len(cells), func(i int) uint { return cells[i].TaskID })
+	if err != nil {
+		return err
+	}
+
+	type cellKey struct {
+		taskID uint
+		day    string
+	}
+	dayKey := func(t time.Time) string { return t.In(time.Local).Format(time.DateOnly) }
+
+	// A later cell for the same task and day replaces an earlier one
+	targets := make(map[cellKey]int)
+	days := make(map[cellKey]time.Time)
+	var order []cellKey
+	var since, until time.Time
+	for i, cell := range cells {
+		if cell.Minutes < 0 || cell.Minutes > minutesPerDay {
+			return fmt.Errorf("cell %d: %w", i+1, ErrInvalidTimesheetMinutes)
+		}
+		day := startOfDay(cell.Date.In(time.Local))
+		key := cellKey{cell.TaskID, dayKey(day)}
+		if _, ok := targets[key]; !ok {
+			order = append(order, key)
+		}
+		targets[key] = cell.Minutes
+		days[key] = day
+		if since.IsZero() || day.Before(since) {
+			since = day
+		}
+		if next := day.AddDate(0, 0, 1); next.After(until) {
+			until = next
+		}
+	}
+
+	entries, err := s.repo.GetTimeEntriesBetween(since, until)
+	if err != nil {
+		return err
+	}
+	existing := make(map[cellKey][]*models.TimeEntry)
+	for _, entry := range entries {
+		key := cellKey{entry.TaskID, dayKey(entry.CreatedAt)}
+		if _, ok := targets[key]; ok && entry.CreatedBy == username {
+			existing[key] = append(existing[key], entry)
+		}
+	}
+
+	now := time.Now()
+	var created, updated []*models.TimeEntry
+	var deleted []uint
+	changed := make(map[uint]bool)
+	grown := make(map[uint]bool)
+	for _, key := range order {
+		current := existing[key]
+		sort.SliceStable(current, func(i, j int) bool {
+			if !current[i].CreatedAt.Equal(current[j].CreatedAt) {
+				return current[i].CreatedAt.Before(current[j].CreatedAt)
+			}
+			return current[i].ID < current[j].ID
+		})
+		logged := 0
+		for _, entry := range current {
+			logged += entry.Duration
+		}
+
+		delta := targets[key] - logged
+		switch {
+		case delta == 0:
+			continue
+		case len(current) == 0:
+			day := days[key]
+			entry := &models.TimeEntry{
+				TaskID:    key.taskID,
+				Duration:  delta,
+				CreatedBy: username,
+				CreatedAt: time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.Local),
+				UpdatedAt: now,
+			}
+			s.applyDefaultBillable(entry, tasks[key.taskID])
+			created = append(created, entry)
+			grown[key.taskID] = true
+		case delta > 0:
+			newest := current[len(current)-1]
+			newest.Duration += delta
+			newest.UpdatedAt = now
+			updated = append(updated, newest)
+			grown[key.taskID] = true
+		default:
+			for i := len(current) - 1; i >= 0 && delta < 0; i-- {
+				entry := current[i]
+				if entry.Duration <= -delta {
+					delta += entry.Duration
+					deleted = append(deleted, entry.ID)
+					continue
+				}
+				entry.Duration += delta
+				entry.UpdatedAt = now
+				updated = append(updated, entry)
+				delta = 0
+			}
+		}
+		changed[key.taskID] = true
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	// Open tasks that receive time move to in-progress, saved with the entries
+	oldStatuses := make(map[uint]models.TaskStatus, len(changed))
+	touched := make([]*models.Task, 0, len(changed))
+	var changes []*models.TaskStatusChange
+	for id := range changed {
+		task := tasks[id]
+		oldStatuses[id] = task.Status
+		if grown[id] && task.Status == models.TaskStatusOpen {
+			task.Status = models.TaskStatusInProgress
+			changes = append(changes, statusChange(task, models.TaskStatusOpen))
+		}
+		task.UpdatedAt = now
+		touched = append(touched, task)
+	}
+
+	if err := s.repo.SaveTimesheet(created, updated, deleted, touched, changes); err != nil {
+		return err
+	}
+
+	for _, task := range touched {
+		s.publish(TaskEventUpdated, task)
+	}
+	if s.notification != nil {
+		for _, task := range touched {
+			if oldStatus := oldStatuses[task.ID]; oldStatus != task.Status {
+				go s.notification.NotifyStatusChanged(task, oldStatus, task.Status)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+	"github.com/soarinferret/jats/internal/repository"
+)
+
+func TestTaskService_Timesheet(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repository.NewTaskRepository(db)
+	service := NewTaskService(repo, nil)
+
+	alpha := &models.Task{Name: "Alpha", Status: models.TaskStatusOpen}
+	beta := &models.Task{Name: "Beta", Status: models.TaskStatusInProgress}
+	for _, task := range []*models.Task{beta, alpha} {
+		if err := repo.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	// Wednesday; the week starts on Monday the 4th
+	wednesday := time.Date(2024, 3, 6, 9, 0, 0, 0, time.Local)
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)
+	if got := StartOfWeek(wednesday); !got.Equal(monday) {
+		t.Fatalf("Expected the week to start %v, got %v", monday, got)
+	}
+	if got := StartOfWeek(time.Date(2024, 3, 10, 23, 0, 0, 0, time.Local)); !got.Equal(monday) {
+		t.Errorf("Expected Sunday to belong to the week starting %v, got %v", monday, got)
+	}
+
+	for _, entry := range []*models.TimeEntry{
+		{TaskID: beta.ID, Duration: 30, CreatedBy: "alice", CreatedAt: wednesday},
+		{TaskID: beta.ID, Duration: 45, CreatedBy: "alice", CreatedAt: wednesday.Add(time.Hour)},
+		{TaskID: beta.ID, Duration: 60, CreatedBy: "bob", CreatedAt: wednesday},
+		{TaskID: beta.ID, Duration: 90, CreatedBy: "alice", CreatedAt: monday.AddDate(0, 0, -1)},
+	} {
+		if err := repo.CreateTimeEntries([]*models.TimeEntry{entry}, nil, nil); err != nil {
+			t.Fatalf("Failed to create time entry: %v", err)
+		}
+	}
+
+	err := service.SetTimesheetCells("alice", []TimesheetCell{
+		{TaskID: alpha.ID, Date: monday, Minutes: 120},
+		{TaskID: beta.ID, Date: wednesday, Minutes: 20},
+	})
+	if err != nil {
+		t.Fatalf("Failed to set timesheet cells: %v", err)
+	}
+
+	sheet, err := service.GetTimesheet("alice", wednesday)
+	if err != nil {
+		t.Fatalf("Failed to get timesheet: %v", err)
+	}
+	if !sheet.WeekStart.Equal(monday) || len(sheet.Days) != 7 {
+		t.Fatalf("Expected a week from %v, got %v with %d days", monday, sheet.WeekStart, len(sheet.Days))
+	}
+	if len(sheet.Rows) != 2 || sheet.Rows[0].TaskName != "Alpha" || sheet.Rows[1].TaskName != "Beta" {
+		t.Fatalf("Expected rows for Alpha and Beta, got %+v", sheet.Rows)
+	}
+	if got := sheet.Rows[0].Minutes[0]; got != 120 {
+		t.Errorf("Expected 120 minutes on Alpha on Monday, got %d", got)
+	}
+	if got := sheet.Rows[1].Minutes[2]; got != 20 {
+		t.Errorf("Expected Beta's Wednesday to shrink to 20 minutes, got %d", got)
+	}
+	if sheet.Totals[2] != 20 || sheet.Total != 140 {
+		t.Errorf("Expected totals of 20 on Wednesday and 140 overall, got %v and %d", sheet.Totals, sheet.Total)
+	}
+
+	// The newest entry was used up and the older one shrunk; bob's is untouched
+	entries, _ := repo.GetTimeEntries(beta.ID)
+	byUser := map[string][]int{}
+	for _, entry := range entries {
+		if entry.CreatedAt.After(monday) {
+			byUser[entry.CreatedBy] = append(byUser[entry.CreatedBy], entry.Duration)
+		}
+	}
+	if len(byUser["alice"]) != 1 || byUser["alice"][0] != 20 || len(byUser["bob"]) != 1 || byUser["bob"][0] != 60 {
+		t.Errorf("Expected alice's entries to shrink to one of 20 and bob's to stay at 60, got %v", byUser)
+	}
+
+	// Time on an open task starts it
+	updated, _ := service.GetTask(alpha.ID)
+	if updated.Status != models.TaskStatusInProgress {
+		t.Errorf("Expected Alpha to move to in-progress, got %s", updated.Status)
+	}
+
+	// Growing a cell adds to its newest entry and clearing it deletes them all
+	if err := service.SetTimesheetCells("alice", []TimesheetCell{{TaskID: alpha.ID, Date: monday, Minutes: 150}}); err != nil {
+		t.Fatalf("Failed to grow cell: %v", err)
+	}
+	if entries, _ := repo.GetTimeEntries(alpha.ID); len(entries) != 1 || entries[0].Duration != 150 {
+		t.Errorf("Expected one entry of 150 minutes on Alpha, got %+v", entries)
+	}
+	if err := service.SetTimesheetCells("alice", []TimesheetCell{{TaskID: alpha.ID, Date: monday, Minutes: 0}}); err != nil {
+		t.Fatalf("Failed to clear cell: %v", err)
+	}
+	if entries, _ := repo.GetTimeEntries(alpha.ID); len(entries) != 0 {
+		t.Errorf("Expected Alpha's entries to be deleted, got %+v", entries)
+	}
+
+	// Invalid cells save nothing
+	err = service.SetTimesheetCells("alice", []TimesheetCell{
+		{TaskID: alpha.ID, Date: monday, Minutes: 60},
+		{TaskID: alpha.ID, Date: wednesday, Minutes: 25 * 60},
+	})
+	if !errors.Is(err, ErrInvalidTimesheetMinutes) {
+		t.Errorf("Expected ErrInvalidTimesheetMinutes, got %v", err)
+	}
+	if err := service.SetTimesheetCells("alice", []TimesheetCell{{TaskID: 999, Date: monday, Minutes: 60}}); !errors.Is(err, ErrBulkTaskNotFound) {
+		t.Errorf("Expected ErrBulkTaskNotFound, got %v", err)
+	}
+	if entries, _ := repo.GetTimeEntries(alpha.ID); len(entries) != 0 {
+		t.Errorf("Expected no entries after failed saves, got %+v", entries)
+	}
+}
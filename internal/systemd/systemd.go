@@ -0,0 +1,131 @@
+// Package systemd lets jatsd run as a systemd service: it takes over the
+// listening sockets of a socket-activated unit and reports readiness, shutdown
+// and watchdog pings through sd_notify. Outside systemd every function is a
+// no-op.
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// listenFDsStart is the first file descriptor passed by socket activation
+const listenFDsStart = 3
+
+// Notification states understood by systemd, see sd_notify(3)
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+	Watchdog = "WATCHDOG=1"
+)
+
+// Listeners returns the sockets passed to the process by systemd socket
+// activation, or none when it was not socket activated. The environment
+// variables are cleared so child processes do not take the sockets too.
+func Listeners() ([]net.Listener, error) {
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+	defer os.Unsetenv("LISTEN_FDNAMES")
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil, nil
+	}
+
+	listeners := make([]net.Listener, 0, count)
+	for fd := listenFDsStart; fd < listenFDsStart+count; fd++ {
+		file := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		listener, err := net.FileListener(file)
+		// FileListener duplicates the descriptor, so the original is closed either way
+		file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("socket activation: file descriptor %d is not a listening socket: %w", fd, err)
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// Notify sends a state such as Ready to the service manager. It reports false
+// when the process is not run by systemd with a notify socket.
+func Notify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	// A leading @ names a socket in the abstract namespace
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WatchdogInterval returns how often the service manager expects a Watchdog
+// ping, or 0 when the unit has no WatchdogSec
+func WatchdogInterval() (time.Duration, error) {
+	usec := os.Getenv("WATCHDOG_USEC")
+	if usec == "" {
+		return 0, nil
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, nil
+	}
+
+	micros, err := strconv.ParseInt(usec, 10, 64)
+	if err != nil || micros <= 0 {
+		return 0, errors.New("invalid WATCHDOG_USEC")
+	}
+	return time.Duration(micros) * time.Microsecond, nil
+}
+
+// RunWatchdog pings the service manager at half the watchdog interval for as
+// long as healthy reports no error, so a hung or broken server is restarted.
+// It returns at once when the unit has no watchdog, and otherwise when stop is
+// closed.
+func RunWatchdog(healthy func() error, stop <-chan struct{}) error {
+	interval, err := WatchdogInterval()
+	if err != nil || interval == 0 {
+		return err
+	}
+
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			if err := healthy(); err != nil {
+				// Missing pings let systemd restart the service
+				log.Printf("Skipping watchdog ping: %v", err)
+				continue
+			}
+			if _, err := Notify(Watchdog); err != nil {
+				return err
+			}
+		}
+	}
+}
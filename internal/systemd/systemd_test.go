@@ -0,0 +1,69 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if sent, err := Notify(Ready); sent || err != nil {
+		t.Errorf("Expected no notification outside systemd, got %t, %v", sent, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	t.Setenv("NOTIFY_SOCKET", path)
+	if sent, err := Notify(Ready); !sent || err != nil {
+		t.Fatalf("Expected the notification to be sent, got %t, %v", sent, err)
+	}
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Failed to read notification: %v", err)
+	}
+	if string(buf[:n]) != Ready {
+		t.Errorf("Expected %q, got %q", Ready, buf[:n])
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "")
+	if interval, err := WatchdogInterval(); interval != 0 || err != nil {
+		t.Errorf("Expected no watchdog, got %s, %v", interval, err)
+	}
+
+	t.Setenv("WATCHDOG_USEC", "30000000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+	if interval, err := WatchdogInterval(); interval != 30*time.Second || err != nil {
+		t.Errorf("Expected 30s, got %s, %v", interval, err)
+	}
+
+	// The watchdog belongs to another process, e.g. a parent shell
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	if interval, _ := WatchdogInterval(); interval != 0 {
+		t.Errorf("Expected another process's watchdog to be ignored, got %s", interval)
+	}
+}
+
+func TestListenersWithoutActivation(t *testing.T) {
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	t.Setenv("LISTEN_FDS", "1")
+	listeners, err := Listeners()
+	if err != nil || len(listeners) != 0 {
+		t.Errorf("Expected no sockets meant for another process, got %v, %v", listeners, err)
+	}
+	if os.Getenv("LISTEN_FDS") != "" {
+		t.Error("Expected the activation variables to be cleared")
+	}
+}
@@ -0,0 +1,306 @@
+// Package tagexpr parses the boolean tag expressions saved queries filter
+// tasks with, e.g. `(client1 OR client2) AND NOT internal`.
+package tagexpr
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Expr is a parsed tag expression
+type Expr interface {
+	// Matches reports whether a task with the tags satisfies the expression
+	Matches(tags []string) bool
+	// Condition returns the expression as an SQL condition, given the
+	// condition that a task has a tag. The tag condition must not be NULL.
+	Condition(hasTag func(tag string) (string, []interface{})) (string, []interface{})
+	// String returns the expression in canonical form, which parses back to
+	// the same expression
+	String() string
+	// Tags returns the tags the expression mentions, in order of appearance
+	Tags() []string
+}
+
+// Parse parses a tag expression. Supported syntax:
+//
+//	client1              tasks with the tag
+//	"on hold"            quotes a tag containing spaces or parentheses, or named AND, OR or NOT
+//	a AND b, a b         tasks with both; adjacent terms are ANDed
+//	a OR b               tasks with either
+//	NOT a                tasks without the tag
+//	(a OR b) AND NOT c   parentheses group terms
+//
+// NOT binds tightest, then AND, then OR. Keywords are case-insensitive. An
+// empty expression matches every task.
+func Parse(input string) (Expr, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return all{}, nil
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+// token is a keyword, parenthesis or tag
+type token struct {
+	kind  string // "AND", "OR", "NOT", "(", ")" or "tag"
+	value string
+}
+
+func (t token) String() string {
+	if t.kind == "tag" {
+		return fmt.Sprintf("tag %q", t.value)
+	}
+	return fmt.Sprintf("%q", t.kind)
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		switch c := input[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, token{kind: string(c)})
+			i++
+		case c == '"':
+			end := strings.IndexByte(input[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty quoted tag")
+			}
+			tokens = append(tokens, token{kind: "tag", value: input[i+1 : i+1+end]})
+			i += end + 2
+		default:
+			end := strings.IndexAny(input[i:], " \t\n\r()\"")
+			if end < 0 {
+				end = len(input) - i
+			}
+			word := input[i : i+end]
+			if keyword := strings.ToUpper(word); keyword == "AND" || keyword == "OR" || keyword == "NOT" {
+				tokens = append(tokens, token{kind: keyword})
+			} else {
+				tokens = append(tokens, token{kind: "tag", value: word})
+			}
+			i += end
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos].kind
+	}
+	return ""
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	terms := []Expr{}
+	for {
+		term, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if p.peek() != "OR" {
+			break
+		}
+		p.pos++
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return or(terms), nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	terms := []Expr{}
+	for {
+		term, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if p.peek() == "AND" {
+			p.pos++
+		} else if next := p.peek(); next != "tag" && next != "NOT" && next != "(" {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return and(terms), nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.peek() == "NOT" {
+		p.pos++
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return not{operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("expression ends early")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case "tag":
+		return tag(t.value), nil
+	case "(":
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return expr, nil
+	default:
+		return nil, fmt.Errorf("unexpected %s", t)
+	}
+}
+
+// all is the empty expression
+type all struct{}
+
+func (all) Matches([]string) bool { return true }
+func (all) Condition(func(string) (string, []interface{})) (string, []interface{}) {
+	return "1 = 1", nil
+}
+func (all) String() string { return "" }
+func (all) Tags() []string { return nil }
+
+type tag string
+
+func (t tag) Matches(tags []string) bool { return slices.Contains(tags, string(t)) }
+func (t tag) Condition(hasTag func(string) (string, []interface{})) (string, []interface{}) {
+	return hasTag(string(t))
+}
+func (t tag) String() string {
+	if strings.ContainsAny(string(t), " \t\n\r()") {
+		return `"` + string(t) + `"`
+	}
+	switch strings.ToUpper(string(t)) {
+	case "AND", "OR", "NOT":
+		return `"` + string(t) + `"`
+	}
+	return string(t)
+}
+func (t tag) Tags() []string { return []string{string(t)} }
+
+type not struct{ operand Expr }
+
+func (n not) Matches(tags []string) bool { return !n.operand.Matches(tags) }
+func (n not) Condition(hasTag func(string) (string, []interface{})) (string, []interface{}) {
+	condition, args := n.operand.Condition(hasTag)
+	return "NOT (" + condition + ")", args
+}
+func (n not) String() string {
+	if _, ok := n.operand.(tag); ok {
+		return "NOT " + n.operand.String()
+	}
+	if _, ok := n.operand.(not); ok {
+		return "NOT " + n.operand.String()
+	}
+	return "NOT (" + n.operand.String() + ")"
+}
+func (n not) Tags() []string { return n.operand.Tags() }
+
+type and []Expr
+
+func (a and) Matches(tags []string) bool {
+	for _, term := range a {
+		if !term.Matches(tags) {
+			return false
+		}
+	}
+	return true
+}
+func (a and) Condition(hasTag func(string) (string, []interface{})) (string, []interface{}) {
+	return join(a, " AND ", hasTag)
+}
+func (a and) String() string {
+	parts := make([]string, len(a))
+	for i, term := range a {
+		parts[i] = term.String()
+		if _, ok := term.(or); ok {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " AND ")
+}
+func (a and) Tags() []string { return tagsOf(a) }
+
+type or []Expr
+
+func (o or) Matches(tags []string) bool {
+	for _, term := range o {
+		if term.Matches(tags) {
+			return true
+		}
+	}
+	return false
+}
+func (o or) Condition(hasTag func(string) (string, []interface{})) (string, []interface{}) {
+	return join(o, " OR ", hasTag)
+}
+func (o or) String() string {
+	parts := make([]string, len(o))
+	for i, term := range o {
+		parts[i] = term.String()
+	}
+	return strings.Join(parts, " OR ")
+}
+func (o or) Tags() []string { return tagsOf(o) }
+
+func join(terms []Expr, operator string, hasTag func(string) (string, []interface{})) (string, []interface{}) {
+	conditions := make([]string, len(terms))
+	var args []interface{}
+	for i, term := range terms {
+		condition, termArgs := term.Condition(hasTag)
+		conditions[i] = "(" + condition + ")"
+		args = append(args, termArgs...)
+	}
+	return strings.Join(conditions, operator), args
+}
+
+func tagsOf(terms []Expr) []string {
+	var tags []string
+	for _, term := range terms {
+		for _, t := range term.Tags() {
+			if !slices.Contains(tags, t) {
+				tags = append(tags, t)
+			}
+		}
+	}
+	return tags
+}
@@ -0,0 +1,99 @@
+package tagexpr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAndMatch(t *testing.T) {
+	tests := []struct {
+		input     string
+		canonical string
+		matches   [][]string
+		rejects   [][]string
+	}{
+		{
+			input:     "(client1 OR client2) AND NOT internal",
+			canonical: "(client1 OR client2) AND NOT internal",
+			matches:   [][]string{{"client1"}, {"client2", "urgent"}},
+			rejects:   [][]string{{"client1", "internal"}, {"urgent"}, nil},
+		},
+		{
+			// AND binds tighter than OR, and adjacent terms are ANDed
+			input:     "a b or c",
+			canonical: "a AND b OR c",
+			matches:   [][]string{{"a", "b"}, {"c"}},
+			rejects:   [][]string{{"a"}, {"b"}},
+		},
+		{
+			input:     `not ("on hold" or waiting)`,
+			canonical: `NOT ("on hold" OR waiting)`,
+			matches:   [][]string{{"on"}, nil},
+			rejects:   [][]string{{"on hold"}, {"waiting"}},
+		},
+		{
+			input:     `"OR" AND NOT NOT x`,
+			canonical: `"OR" AND NOT NOT x`,
+			matches:   [][]string{{"OR", "x"}},
+			rejects:   [][]string{{"OR"}},
+		},
+		{
+			input:     "  ",
+			canonical: "",
+			matches:   [][]string{nil, {"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse unexpected error: %v", err)
+			}
+			if got := expr.String(); got != tt.canonical {
+				t.Errorf("String() = %q, want %q", got, tt.canonical)
+			}
+			if reparsed, err := Parse(expr.String()); err != nil || reparsed.String() != tt.canonical {
+				t.Errorf("Canonical form does not parse back: %v", err)
+			}
+			for _, tags := range tt.matches {
+				if !expr.Matches(tags) {
+					t.Errorf("Expected a match for %v", tags)
+				}
+			}
+			for _, tags := range tt.rejects {
+				if expr.Matches(tags) {
+					t.Errorf("Expected no match for %v", tags)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{"(a OR b", "a OR", "AND a", "a)", `"unterminated`, `""`, "NOT"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected an error", input)
+		}
+	}
+}
+
+func TestCondition(t *testing.T) {
+	expr, err := Parse("(client1 OR client2) AND NOT internal")
+	if err != nil {
+		t.Fatalf("Parse unexpected error: %v", err)
+	}
+	condition, args := expr.Condition(func(tag string) (string, []interface{}) {
+		return "has(?)", []interface{}{tag}
+	})
+	if want := "((has(?)) OR (has(?))) AND (NOT (has(?)))"; condition != want {
+		t.Errorf("Condition = %q, want %q", condition, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"client1", "client2", "internal"}) {
+		t.Errorf("Args = %v", args)
+	}
+	if tags := strings.Join(expr.Tags(), ","); tags != "client1,client2,internal" {
+		t.Errorf("Tags = %s", tags)
+	}
+}
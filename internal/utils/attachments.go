@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// inlineContentTypes are safe for browsers to render directly. Anything else,
+// notably HTML and SVG, is always served as a download.
+var inlineContentTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp", "video/", "audio/", "application/pdf", "text/plain"}
+
+// ServeAttachment streams an attachment file with Content-Type, Content-Disposition,
+// ETag, and HTTP range support without reading it into memory. When inline is true,
+// previewable types are shown in the browser; everything else is sent as a download.
+// It returns an error satisfying os.IsNotExist when the file is missing on disk.
+func ServeAttachment(w http.ResponseWriter, r *http.Request, filePath string, attachment *models.Attachment, inline bool) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	disposition := "attachment"
+	if inline && isInlineContentType(contentType) {
+		disposition = "inline"
+	}
+	if value := mime.FormatMediaType(disposition, map[string]string{"filename": attachment.OriginalName}); value != "" {
+		disposition = value
+	}
+
+	// Stored files are never modified, so size and mtime identify the content
+	w.Header().Set("ETag", fmt.Sprintf(`"%d-%x-%x"`, attachment.ID, info.Size(), info.ModTime().UnixNano()))
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", disposition)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+
+	// ServeContent handles Range, If-Range, If-None-Match, and HEAD requests
+	http.ServeContent(w, r, attachment.OriginalName, info.ModTime(), file)
+	return nil
+}
+
+func isInlineContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range inlineContentTypes {
+		if mediaType == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed)) {
+			return true
+		}
+	}
+	return false
+}
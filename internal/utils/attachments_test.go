@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+func TestServeAttachment(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "stored.mp4")
+	if err := os.WriteFile(filePath, []byte("0123456789"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	video := &models.Attachment{ID: 7, OriginalName: "clip.mp4", ContentType: "video/mp4"}
+
+	serve := func(attachment *models.Attachment, inline bool, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/app/attachments/7", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		if err := ServeAttachment(rec, req, filePath, attachment, inline); err != nil {
+			t.Fatalf("ServeAttachment failed: %v", err)
+		}
+		return rec
+	}
+
+	rec := serve(video, true, nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != "0123456789" {
+		t.Fatalf("Expected full content, got %d %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "video/mp4" {
+		t.Errorf("Expected video/mp4, got %s", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `inline; filename=clip.mp4` {
+		t.Errorf("Unexpected Content-Disposition %q", got)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected an ETag header")
+	}
+
+	rec = serve(video, true, map[string]string{"Range": "bytes=2-5"})
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "2345" {
+		t.Errorf("Expected 206 with bytes 2-5, got %d %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Range"); got != "bytes 2-5/10" {
+		t.Errorf("Unexpected Content-Range %q", got)
+	}
+
+	rec = serve(video, true, map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for matching ETag, got %d", rec.Code)
+	}
+
+	// Active content is never rendered inline
+	page := &models.Attachment{ID: 8, OriginalName: "page.html", ContentType: "text/html; charset=utf-8"}
+	rec = serve(page, true, nil)
+	if got := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(got, "attachment;") {
+		t.Errorf("Expected HTML to be served as attachment, got %q", got)
+	}
+
+	// Forced downloads use attachment even for previewable types
+	rec = serve(video, false, nil)
+	if got := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(got, "attachment;") {
+		t.Errorf("Expected attachment disposition, got %q", got)
+	}
+
+	err := ServeAttachment(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), filepath.Join(dir, "missing"), video, true)
+	if !os.IsNotExist(err) {
+		t.Errorf("Expected not-exist error for missing file, got %v", err)
+	}
+}
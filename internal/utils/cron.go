@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed five-field cron expression:
+// minute hour day-of-month month day-of-week
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64 // bit i set when value i matches
+	domAny, dowAny                bool   // field was "*", see matchesDay
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// ParseCron parses a standard five-field cron expression such as "0 8 * * mon"
+// or a macro (@hourly, @daily, @weekly, @monthly, @yearly). Fields accept *,
+// lists (1,15), ranges (1-5), steps (*/15, 0-30/10) and month and weekday names.
+// Day of week 7 is Sunday, like 0.
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(strings.ToLower(expr))
+	if macro, ok := cronMacros[expr]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields (minute hour day month weekday)", expr)
+	}
+
+	schedule := &CronSchedule{
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}
+
+	var err error
+	if schedule.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute: %w", err)
+	}
+	if schedule.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour: %w", err)
+	}
+	if schedule.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day of month: %w", err)
+	}
+	if schedule.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("invalid month: %w", err)
+	}
+	if schedule.dow, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, fmt.Errorf("invalid day of week: %w", err)
+	}
+	// Sunday can be written as 0 or 7
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+
+	return schedule, nil
+}
+
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = min, max
+		case strings.Contains(rangePart, "-"):
+			loStr, hiStr, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseCronValue(loStr, names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseCronValue(hiStr, names); err != nil {
+				return 0, err
+			}
+		default:
+			value, err := parseCronValue(rangePart, names)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = value, value
+			// "5/15" means from 5 to the end in steps of 15
+			if hasStep {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, names map[string]int) (int, error) {
+	if value, ok := names[s]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return value, nil
+}
+
+// matchesDay applies cron's day rule: when both day of month and day of week
+// are restricted, a day matching either one matches
+func (c *CronSchedule) matchesDay(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowMatch
+	case c.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// Next returns the first time after the given time that matches the schedule,
+// in the location of after. It returns the zero time if nothing matches within
+// five years (e.g. "0 0 30 2 *").
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	// Wednesday
+	from := time.Date(2024, 3, 6, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 3, 6, 10, 45, 0, 0, time.UTC)},
+		{"0 8 * * mon", time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2024, 3, 7, 9, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2024, 3, 7, 10, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 29 feb *", time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 3, 6, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		// Day of month and day of week both restricted: either matches
+		{"0 0 15 * fri", time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("ParseCron(%q).Next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "0 0 * * funday"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) expected an error", expr)
+		}
+	}
+}
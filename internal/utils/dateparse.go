@@ -11,26 +11,68 @@ import (
 var (
 	relativeDatePattern = regexp.MustCompile(`^([+-]?)(\d+)([dwmy])$`)
 	absoluteDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	futureDatePattern   = regexp.MustCompile(`^in (\d+|an?) (day|week|month|year)s?$`)
+	pastDatePattern     = regexp.MustCompile(`^(\d+|an?) (day|week|month|year)s? ago$`)
+	weekdayDatePattern  = regexp.MustCompile(`^(?:(next|last|this) )?([a-z]+)$`)
 )
 
-// ParseDate parses both relative dates (like "-1d", "+2w") and absolute dates (like "2025-12-01")
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var naturalUnits = map[string]string{
+	"week": "w", "month": "m", "year": "y",
+}
+
+// maxNaturalDateWords is the longest natural-language date, e.g. "in 2 weeks"
+const maxNaturalDateWords = 3
+
+// ParseDate parses relative dates (like "-1d", "+2w"), absolute dates (like "2025-12-01")
+// and natural-language dates (like "tomorrow", "next friday", "in 2 weeks", "eom").
 // Returns a time.Time with the current time but the specified date
 func ParseDate(dateStr string) (time.Time, error) {
+	return parseDateAt(dateStr, time.Now())
+}
+
+// ParseDatePrefix parses the longest run of leading words that forms a date, so
+// multi-word dates can be used in free-form input such as "-d next friday".
+// Returns the date and the number of words it consumed.
+func ParseDatePrefix(words []string) (time.Time, int, error) {
+	if len(words) == 0 {
+		return time.Time{}, 0, fmt.Errorf("missing date")
+	}
+
 	now := time.Now()
-	
+	for n := min(len(words), maxNaturalDateWords); n > 0; n-- {
+		if parsed, err := parseDateAt(strings.Join(words[:n], " "), now); err == nil {
+			return parsed, n, nil
+		}
+	}
+
+	_, err := parseDateAt(words[0], now)
+	return time.Time{}, 0, err
+}
+
+func parseDateAt(dateStr string, now time.Time) (time.Time, error) {
 	if dateStr == "" {
 		return now, nil
 	}
-	
+
 	dateStr = strings.TrimSpace(dateStr)
-	
+
 	// Check if it's an absolute date (YYYY-MM-DD format)
 	if absoluteDatePattern.MatchString(dateStr) {
 		parsedDate, err := time.Parse("2006-01-02", dateStr)
 		if err != nil {
 			return time.Time{}, fmt.Errorf("invalid date format: %s", dateStr)
 		}
-		
+
 		// Combine the parsed date with current time
 		return time.Date(
 			parsedDate.Year(), parsedDate.Month(), parsedDate.Day(),
@@ -38,43 +80,126 @@ func ParseDate(dateStr string) (time.Time, error) {
 			now.Location(),
 		), nil
 	}
-	
+
+	// Check if it's a natural-language date
+	if parsed, ok := parseNaturalDate(dateStr, now); ok {
+		return parsed, nil
+	}
+
 	// Check if it's a relative date
 	matches := relativeDatePattern.FindStringSubmatch(dateStr)
 	if len(matches) != 4 {
-		return time.Time{}, fmt.Errorf("invalid date format: %s (expected formats: YYYY-MM-DD, ±Nd, ±Nw, ±Nm, ±Ny)", dateStr)
+		return time.Time{}, fmt.Errorf("invalid date format: %s (expected formats: YYYY-MM-DD, ±Nd, ±Nw, ±Nm, ±Ny, today, tomorrow, next friday, in 2 weeks, eom)", dateStr)
 	}
-	
+
 	sign := matches[1]
 	amountStr := matches[2]
 	unit := matches[3]
-	
+
 	amount, err := strconv.Atoi(amountStr)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("invalid amount in date: %s", dateStr)
 	}
-	
+
 	// Handle sign (default to negative if no sign provided for backward compatibility)
 	if sign == "+" {
 		// amount stays positive
 	} else if sign == "-" || sign == "" {
 		amount = -amount
 	}
-	
+
 	// Apply the relative offset
-	var resultTime time.Time
 	switch unit {
-	case "d":
-		resultTime = now.AddDate(0, 0, amount)
+	case "d", "w", "m", "y":
+		return addDateUnit(now, unit, amount), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid time unit: %s (expected d, w, m, y)", unit)
+	}
+}
+
+// parseNaturalDate handles English phrases such as "tomorrow", "last monday",
+// "next week", "in 3 days", "2 weeks ago" and "eom". Input is matched case-insensitively.
+func parseNaturalDate(dateStr string, now time.Time) (time.Time, bool) {
+	phrase := strings.ToLower(strings.Join(strings.Fields(dateStr), " "))
+
+	switch phrase {
+	case "today", "now":
+		return now, true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), true
+	case "yesterday":
+		return now.AddDate(0, 0, -1), true
+	case "eow", "end of week":
+		// The working week ends on Friday; at the weekend that is the coming Friday
+		return now.AddDate(0, 0, (int(time.Friday)-int(now.Weekday())+7)%7), true
+	case "eom", "end of month":
+		return now.AddDate(0, 1, -now.Day()), true
+	case "eoy", "end of year":
+		return time.Date(now.Year(), time.December, 31,
+			now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), now.Location()), true
+	}
+
+	if matches := futureDatePattern.FindStringSubmatch(phrase); matches != nil {
+		return addDateUnit(now, matches[2][:1], naturalAmount(matches[1])), true
+	}
+	if matches := pastDatePattern.FindStringSubmatch(phrase); matches != nil {
+		return addDateUnit(now, matches[2][:1], -naturalAmount(matches[1])), true
+	}
+
+	if matches := weekdayDatePattern.FindStringSubmatch(phrase); matches != nil {
+		if unit, ok := naturalUnits[matches[2]]; ok && matches[1] != "this" && matches[1] != "" {
+			if matches[1] == "last" {
+				return addDateUnit(now, unit, -1), true
+			}
+			return addDateUnit(now, unit, 1), true
+		}
+
+		weekday, ok := weekdayNames[matches[2]]
+		if !ok {
+			return time.Time{}, false
+		}
+		diff := int(weekday) - int(now.Weekday())
+		switch matches[1] {
+		case "next":
+			// Always a future day, even when today is that weekday
+			if diff <= 0 {
+				diff += 7
+			}
+		case "last":
+			if diff >= 0 {
+				diff -= 7
+			}
+		default:
+			// A bare weekday is the upcoming one, including today
+			if diff < 0 {
+				diff += 7
+			}
+		}
+		return now.AddDate(0, 0, diff), true
+	}
+
+	return time.Time{}, false
+}
+
+// naturalAmount converts "a"/"an" or a number into an integer amount
+func naturalAmount(s string) int {
+	if s == "a" || s == "an" {
+		return 1
+	}
+	amount, _ := strconv.Atoi(s)
+	return amount
+}
+
+// addDateUnit offsets t by amount days (d), weeks (w), months (m) or years (y)
+func addDateUnit(t time.Time, unit string, amount int) time.Time {
+	switch unit {
 	case "w":
-		resultTime = now.AddDate(0, 0, amount*7)
+		return t.AddDate(0, 0, amount*7)
 	case "m":
-		resultTime = now.AddDate(0, amount, 0)
+		return t.AddDate(0, amount, 0)
 	case "y":
-		resultTime = now.AddDate(amount, 0, 0)
+		return t.AddDate(amount, 0, 0)
 	default:
-		return time.Time{}, fmt.Errorf("invalid time unit: %s (expected d, w, m, y)", unit)
+		return t.AddDate(0, 0, amount)
 	}
-	
-	return resultTime, nil
-}
\ No newline at end of file
+}
@@ -7,7 +7,7 @@ import (
 
 func TestParseDate(t *testing.T) {
 	now := time.Now()
-	
+
 	tests := []struct {
 		name     string
 		input    string
@@ -57,45 +57,45 @@ func TestParseDate(t *testing.T) {
 			dayDiff:  -7,
 		},
 		{
-			name:     "invalid format",
-			input:    "invalid",
-			wantErr:  true,
+			name:    "invalid format",
+			input:   "invalid",
+			wantErr: true,
 		},
 		{
-			name:     "invalid absolute date",
-			input:    "2025-13-01",
-			wantErr:  true,
+			name:    "invalid absolute date",
+			input:   "2025-13-01",
+			wantErr: true,
 		},
 	}
-	
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result, err := ParseDate(tt.input)
-			
+
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("ParseDate(%q) expected error, got nil", tt.input)
 				}
 				return
 			}
-			
+
 			if err != nil {
 				t.Errorf("ParseDate(%q) unexpected error: %v", tt.input, err)
 				return
 			}
-			
+
 			if tt.checkDay {
 				expected := now.AddDate(0, 0, tt.dayDiff)
 				if result.Year() != expected.Year() || result.Month() != expected.Month() || result.Day() != expected.Day() {
 					t.Errorf("ParseDate(%q) = %v, want date %v", tt.input, result.Format("2006-01-02"), expected.Format("2006-01-02"))
 				}
-				
+
 				// Check that time components are close to current time (within 1 second)
 				if abs(result.Hour()-now.Hour()) > 1 {
 					t.Errorf("ParseDate(%q) time should preserve current hour, got %d, want ~%d", tt.input, result.Hour(), now.Hour())
 				}
 			}
-			
+
 			if tt.input == "2025-12-01" {
 				if result.Year() != 2025 || result.Month() != 12 || result.Day() != 1 {
 					t.Errorf("ParseDate(%q) = %v, want 2025-12-01", tt.input, result.Format("2006-01-02"))
@@ -114,4 +114,86 @@ func abs(x int) int {
 		return -x
 	}
 	return x
-}
\ No newline at end of file
+}
+
+func TestParseDate_NaturalLanguage(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 10, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"today", "2025-10-15"},
+		{"Tomorrow", "2025-10-16"},
+		{"yesterday", "2025-10-14"},
+		{"friday", "2025-10-17"},
+		{"wednesday", "2025-10-15"},
+		{"monday", "2025-10-20"},
+		{"next friday", "2025-10-17"},
+		{"next wed", "2025-10-22"},
+		{"last friday", "2025-10-10"},
+		{"last wednesday", "2025-10-08"},
+		{"next week", "2025-10-22"},
+		{"last month", "2025-09-15"},
+		{"in 2 weeks", "2025-10-29"},
+		{"in a day", "2025-10-16"},
+		{"in  3  months", "2026-01-15"},
+		{"3 days ago", "2025-10-12"},
+		{"eow", "2025-10-17"},
+		{"eom", "2025-10-31"},
+		{"end of year", "2025-12-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseDateAt(tt.input, now)
+			if err != nil {
+				t.Fatalf("parseDateAt(%q) unexpected error: %v", tt.input, err)
+			}
+			if got := result.Format("2006-01-02"); got != tt.want {
+				t.Errorf("parseDateAt(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+			if result.Hour() != now.Hour() || result.Minute() != now.Minute() {
+				t.Errorf("parseDateAt(%q) should preserve the time of day, got %s", tt.input, result.Format("15:04"))
+			}
+		})
+	}
+
+	for _, input := range []string{"next fortnight", "in weeks", "someday", "this week"} {
+		if _, err := parseDateAt(input, now); err == nil {
+			t.Errorf("parseDateAt(%q) expected error, got nil", input)
+		}
+	}
+
+	// End of month must not overflow into the next month
+	jan31 := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+	if result, _ := parseDateAt("eom", jan31); result.Format("2006-01-02") != "2025-01-31" {
+		t.Errorf("eom on Jan 31 = %s, want 2025-01-31", result.Format("2006-01-02"))
+	}
+}
+
+func TestParseDatePrefix(t *testing.T) {
+	tests := []struct {
+		words    []string
+		consumed int
+		wantErr  bool
+	}{
+		{[]string{"tomorrow", "+urgent"}, 1, false},
+		{[]string{"next", "friday", "deploy"}, 2, false},
+		{[]string{"in", "2", "weeks"}, 3, false},
+		{[]string{"-1d", "in", "review"}, 1, false},
+		{[]string{"soon"}, 0, true},
+		{nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		_, consumed, err := ParseDatePrefix(tt.words)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDatePrefix(%v) error = %v, wantErr %v", tt.words, err, tt.wantErr)
+		}
+		if consumed != tt.consumed {
+			t.Errorf("ParseDatePrefix(%v) consumed %d words, want %d", tt.words, consumed, tt.consumed)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses a duration such as "30m", "1h", "2h30m", "1.5h" or a bare
+// number of minutes, and returns it in whole minutes
+func ParseDuration(duration string) (int, error) {
+	// Try parsing as Go duration first
+	if d, err := time.ParseDuration(duration); err == nil {
+		return int(d.Minutes()), nil
+	}
+
+	// Handle formats like "1.5h" or just numbers (assume minutes)
+	if strings.Contains(duration, ".") {
+		// Try parsing as decimal hours
+		if strings.HasSuffix(duration, "h") {
+			hourStr := strings.TrimSuffix(duration, "h")
+			if hours, err := strconv.ParseFloat(hourStr, 64); err == nil {
+				return int(hours * 60), nil
+			}
+		}
+	}
+
+	// If just a number, assume minutes
+	if num, err := strconv.Atoi(duration); err == nil {
+		return num, nil
+	}
+
+	return 0, fmt.Errorf("invalid duration format: %s (examples: 30m, 1h, 2h30m, 1.5h, or just 30 for minutes)", duration)
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// taskReferencePattern matches "#123" as a word of its own, but not HTML
+// entities like "&#39;", URL fragments like "/#12" or "##12"
+var taskReferencePattern = regexp.MustCompile(`(^|[^\w&/#])#(\d+)\b`)
+
+// ExtractTaskReferences returns the task IDs mentioned as #ID in text, in order
+// of first appearance and without duplicates
+func ExtractTaskReferences(text string) []uint {
+	var ids []uint
+	seen := make(map[uint]bool)
+	for _, match := range taskReferencePattern.FindAllStringSubmatch(text, -1) {
+		id, err := strconv.ParseUint(match[2], 10, 32)
+		if err != nil || id == 0 || seen[uint(id)] {
+			continue
+		}
+		seen[uint(id)] = true
+		ids = append(ids, uint(id))
+	}
+	return ids
+}
+
+// MentionsUser reports whether text mentions username as @username, ignoring
+// case. Addresses such as "bob@example.com" and longer names such as
+// "@bobby" do not count.
+func MentionsUser(text, username string) bool {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return false
+	}
+	pattern := regexp.MustCompile(`(?i)(^|[^\w@.])@` + regexp.QuoteMeta(username) + `($|[^\w-])`)
+	return pattern.MatchString(text)
+}
+
+// LinkTaskReferences rewrites each #ID mention in text with the result of link(id).
+// Matches are the same as ExtractTaskReferences.
+func LinkTaskReferences(text string, link func(id uint) string) string {
+	return taskReferencePattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := taskReferencePattern.FindStringSubmatch(match)
+		id, err := strconv.ParseUint(parts[2], 10, 32)
+		if err != nil || id == 0 {
+			return match
+		}
+		return parts[1] + link(uint(id))
+	})
+}
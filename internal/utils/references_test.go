@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractTaskReferences(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []uint
+	}{
+		{"See #12 and #7, also (#12)", []uint{12, 7}},
+		{"#3 at the start", []uint{3}},
+		{"#1,#2", []uint{1, 2}},
+		{"It&#39;s fine", nil},
+		{"https://example.com/#42 and ##5 and issue#9", nil},
+		{"#0 is not a task", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := ExtractTaskReferences(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExtractTaskReferences(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLinkTaskReferences(t *testing.T) {
+	got := LinkTaskReferences("Blocked by #12, see /#3", func(id uint) string {
+		return "<task>"
+	})
+	if want := "Blocked by <task>, see /#3"; got != want {
+		t.Errorf("LinkTaskReferences = %q, want %q", got, want)
+	}
+}
+
+func TestMentionsUser(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"@bob can you look?", true},
+		{"Thanks, @Bob.", true},
+		{"(@bob)", true},
+		{"mail bob@example.com", false},
+		{"@bobby", false},
+		{"@bob-smith", false},
+		{"bob", false},
+	}
+
+	for _, tt := range tests {
+		if got := MentionsUser(tt.input, "bob"); got != tt.want {
+			t.Errorf("MentionsUser(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
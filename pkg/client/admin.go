@@ -0,0 +1,305 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// The methods in this file need an admin user or an API key with
+// PermissionAdmin, except ListCannedResponses.
+
+// CreateUserRequest creates a user
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	IsActive bool   `json:"is_active"`
+}
+
+// UpdateUserRequest changes a user; nil fields are left unchanged
+type UpdateUserRequest struct {
+	Username *string `json:"username,omitempty"`
+	Email    *string `json:"email,omitempty"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+// CannedResponseRequest creates or updates a canned response
+type CannedResponseRequest struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// AutomationRuleRequest creates or updates an automation rule: when Trigger
+// fires on a task meeting the conditions, the actions are applied. Empty
+// conditions match anything and empty actions are skipped.
+type AutomationRuleRequest struct {
+	Name    string `json:"name"`
+	Enabled *bool  `json:"enabled,omitempty"` // defaults to true
+	Trigger string `json:"trigger"`           // created, tag_added, status_changed or email_received
+
+	// Conditions
+	Tag      string       `json:"tag,omitempty"`
+	Priority TaskPriority `json:"priority,omitempty"`
+	Status   TaskStatus   `json:"status,omitempty"`
+	Sender   string       `json:"sender,omitempty"`
+
+	// Actions
+	SetAssignee string `json:"set_assignee,omitempty"`
+	AddTag      string `json:"add_tag,omitempty"`
+	Notify      bool   `json:"notify"`
+	WebhookURL  string `json:"webhook_url,omitempty"`
+}
+
+// ProvisionedTenant is a new tenant with the credentials of its initial
+// admin user, which are only ever returned once
+type ProvisionedTenant struct {
+	Tenant        *Tenant `json:"tenant"`
+	AdminUsername string  `json:"admin_username"`
+	AdminPassword string  `json:"admin_password"`
+}
+
+// PluginStatus is a script plugin loaded by the server and how its runs went.
+// Plugin routes are under /api/v1/plugins/{name} and can be called with Do.
+type PluginStatus struct {
+	Name        string     `json:"name"`
+	File        string     `json:"file"`
+	Runs        int        `json:"runs"`
+	Failures    int        `json:"failures"`
+	LastError   string     `json:"last_error"`
+	LastErrorAt *time.Time `json:"last_error_at"`
+}
+
+// EmailSimulation describes what the server would do with an inbound email
+type EmailSimulation struct {
+	Subject   string `json:"subject"`
+	From      string `json:"from"`
+	MessageID string `json:"message_id"`
+	InReplyTo string `json:"in_reply_to"`
+	Outcome   string `json:"outcome"`
+	Reason    string `json:"reason"`
+	Sender    string `json:"sender"`
+	Spam      *struct {
+		Score   float64  `json:"score"`
+		Action  string   `json:"action"`
+		Symbols []string `json:"symbols"`
+		Verdict string   `json:"verdict"`
+	} `json:"spam"`
+	TaskID      uint   `json:"task_id"`
+	TaskName    string `json:"task_name"`
+	Comment     string `json:"comment"`
+	Attachments []struct {
+		Filename    string `json:"filename"`
+		ContentType string `json:"content_type"`
+		Size        int64  `json:"size"`
+		AttachedTo  string `json:"attached_to"`
+	} `json:"attachments"`
+}
+
+// ListUsers returns every user
+func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
+	var users []User
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/admin/users", nil, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetUser returns a user
+func (c *Client) GetUser(ctx context.Context, userID uint) (*User, error) {
+	var user User
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/admin/users/%d", userID), nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CreateUser creates a user
+func (c *Client) CreateUser(ctx context.Context, req *CreateUserRequest) (*User, error) {
+	var user User
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/admin/users", req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser changes a user
+func (c *Client) UpdateUser(ctx context.Context, userID uint, req *UpdateUserRequest) (*User, error) {
+	var user User
+	if err := c.Do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/admin/users/%d", userID), req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteUser deletes a user
+func (c *Client) DeleteUser(ctx context.Context, userID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/admin/users/%d", userID), nil, nil)
+}
+
+// ResetUserPassword sets a new password for a user
+func (c *Client) ResetUserPassword(ctx context.Context, userID uint, newPassword string) error {
+	req := map[string]string{"new_password": newPassword}
+	return c.Do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/admin/users/%d/reset-password", userID), req, nil)
+}
+
+// ListCannedResponses returns the canned responses comments and emails can
+// use; any user allowed to read tasks can list them
+func (c *Client) ListCannedResponses(ctx context.Context) ([]CannedResponse, error) {
+	var responses []CannedResponse
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/canned-responses", nil, &responses); err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+// CreateCannedResponse creates a canned response
+func (c *Client) CreateCannedResponse(ctx context.Context, req *CannedResponseRequest) (*CannedResponse, error) {
+	var response CannedResponse
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/admin/canned-responses", req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateCannedResponse changes a canned response
+func (c *Client) UpdateCannedResponse(ctx context.Context, responseID uint, req *CannedResponseRequest) (*CannedResponse, error) {
+	var response CannedResponse
+	if err := c.Do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/admin/canned-responses/%d", responseID), req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteCannedResponse deletes a canned response
+func (c *Client) DeleteCannedResponse(ctx context.Context, responseID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/admin/canned-responses/%d", responseID), nil, nil)
+}
+
+// ListAutomationRules returns the automation rules
+func (c *Client) ListAutomationRules(ctx context.Context) ([]AutomationRule, error) {
+	var rules []AutomationRule
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/admin/rules", nil, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CreateAutomationRule creates an automation rule
+func (c *Client) CreateAutomationRule(ctx context.Context, req *AutomationRuleRequest) (*AutomationRule, error) {
+	var rule AutomationRule
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/admin/rules", req, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateAutomationRule replaces an automation rule
+func (c *Client) UpdateAutomationRule(ctx context.Context, ruleID uint, req *AutomationRuleRequest) (*AutomationRule, error) {
+	var rule AutomationRule
+	if err := c.Do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/admin/rules/%d", ruleID), req, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DeleteAutomationRule deletes an automation rule
+func (c *Client) DeleteAutomationRule(ctx context.Context, ruleID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/admin/rules/%d", ruleID), nil, nil)
+}
+
+// ListQuarantine returns the inbound emails held back as spam
+func (c *Client) ListQuarantine(ctx context.Context) ([]QuarantinedEmail, error) {
+	var emails []QuarantinedEmail
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/admin/quarantine", nil, &emails); err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+// ReleaseQuarantined processes a quarantined email as if it had not been
+// held back
+func (c *Client) ReleaseQuarantined(ctx context.Context, emailID uint) error {
+	return c.Do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/admin/quarantine/%d/release", emailID), nil, nil)
+}
+
+// DeleteQuarantined discards a quarantined email
+func (c *Client) DeleteQuarantined(ctx context.Context, emailID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/admin/quarantine/%d", emailID), nil, nil)
+}
+
+// ListTenants returns the tenants of a multi-tenant server
+func (c *Client) ListTenants(ctx context.Context) ([]Tenant, error) {
+	var tenants []Tenant
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/admin/tenants", nil, &tenants); err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// CreateTenant provisions a tenant with its own database and admin user
+func (c *Client) CreateTenant(ctx context.Context, slug, name string) (*ProvisionedTenant, error) {
+	req := map[string]string{"slug": slug, "name": name}
+	var tenant ProvisionedTenant
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/admin/tenants", req, &tenant); err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// DeleteTenant removes a tenant
+func (c *Client) DeleteTenant(ctx context.Context, slug string) error {
+	return c.Do(ctx, http.MethodDelete, "/api/v1/admin/tenants/"+url.PathEscape(slug), nil, nil)
+}
+
+// ListPlugins returns the server's script plugins
+func (c *Client) ListPlugins(ctx context.Context) ([]PluginStatus, error) {
+	var plugins []PluginStatus
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/admin/plugins", nil, &plugins); err != nil {
+		return nil, err
+	}
+	return plugins, nil
+}
+
+// SimulateInboundEmail runs a raw RFC 822 message through the server's
+// inbound email processing without creating anything
+func (c *Client) SimulateInboundEmail(ctx context.Context, raw []byte) (*EmailSimulation, error) {
+	respBody, err := c.read(ctx, &request{
+		method:      http.MethodPost,
+		path:        "/api/v1/admin/email/simulate",
+		contentType: "message/rfc822",
+		body:        raw,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var simulation EmailSimulation
+	if err := decodeData(respBody, &simulation); err != nil {
+		return nil, err
+	}
+	return &simulation, nil
+}
+
+// DownloadProfile writes a runtime profile of the server, such as "heap" or
+// "goroutine", to w in pprof format. For "profile" and "trace", seconds is
+// how long to record for; 0 uses the server's default.
+func (c *Client) DownloadProfile(ctx context.Context, profile string, seconds int, w io.Writer) error {
+	path := "/api/v1/admin/debug/pprof/" + url.PathEscape(profile)
+	if seconds > 0 {
+		path += "?seconds=" + strconv.Itoa(seconds)
+	}
+	download, err := c.open(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer download.Body.Close()
+
+	if _, err := io.Copy(w, download.Body); err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+	return nil
+}
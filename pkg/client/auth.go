@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// sessionCookie is the cookie the server returns the session token in
+const sessionCookie = "session_token"
+
+// LoginResult is a signed in user and their session
+type LoginResult struct {
+	User    *User
+	Session *Session
+	Token   string // the session token, which the client now sends
+}
+
+// Login signs in with a username and password, and a two-factor code for an
+// account that has it enabled, and makes the client use the new session.
+// It returns ErrTOTPRequired when a code is needed but was not given.
+func (c *Client) Login(ctx context.Context, username, password, totpCode string) (*LoginResult, error) {
+	body, err := json.Marshal(map[string]string{
+		"username":  username,
+		"password":  password,
+		"totp_code": totpCode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	resp, err := c.send(ctx, &request{
+		method:      http.MethodPost,
+		path:        "/api/v1/auth/login",
+		contentType: "application/json",
+		body:        body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var data struct {
+		RequiresTOTP bool     `json:"requires_totp"`
+		User         *User    `json:"user"`
+		Session      *Session `json:"session"`
+	}
+	var envelope envelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if err := json.Unmarshal(envelope.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if data.RequiresTOTP {
+		return nil, ErrTOTPRequired
+	}
+
+	result := &LoginResult{User: data.User, Session: data.Session}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == sessionCookie {
+			result.Token = cookie.Value
+		}
+	}
+	if result.Token == "" {
+		return nil, fmt.Errorf("login succeeded but no session token found")
+	}
+	c.SetToken(result.Token)
+	return result, nil
+}
+
+// Logout ends the client's session
+func (c *Client) Logout(ctx context.Context) error {
+	err := c.Do(ctx, http.MethodPost, "/api/v1/auth/logout", nil, nil)
+	if err == nil {
+		c.SetToken("")
+	}
+	return err
+}
+
+// GetProfile returns the signed in user
+func (c *Client) GetProfile(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/auth/profile", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// NewAPIKey is a created API key and the key itself, which is only ever
+// returned once
+type NewAPIKey struct {
+	APIKey *APIKey `json:"api_key"`
+	Key    string  `json:"key"`
+}
+
+// CreateAPIKey creates an API key for the signed in user with the given
+// permissions, such as PermissionReadTasks, expiring at expiresAt unless it
+// is nil
+func (c *Client) CreateAPIKey(ctx context.Context, name string, permissions []string, expiresAt *time.Time) (*NewAPIKey, error) {
+	req := struct {
+		Name        string     `json:"name"`
+		Permissions []string   `json:"permissions"`
+		ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	}{name, permissions, expiresAt}
+
+	var key NewAPIKey
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/auth/api-keys", req, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListAPIKeys returns the signed in user's API keys
+func (c *Client) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var keys []APIKey
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/auth/api-keys", nil, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// DeleteAPIKey revokes one of the signed in user's API keys
+func (c *Client) DeleteAPIKey(ctx context.Context, keyID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/auth/api-keys?id=%d", keyID), nil, nil)
+}
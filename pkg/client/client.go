@@ -0,0 +1,347 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds a call, retries included, unless WithTimeout says
+// otherwise. Downloads and event streams are not bounded by it.
+const DefaultTimeout = 30 * time.Second
+
+// Default retry policy, see WithRetries
+const (
+	DefaultRetries   = 2
+	DefaultRetryWait = 500 * time.Millisecond
+)
+
+// maxRetryWait caps how long a Retry-After header can make a call wait
+const maxRetryWait = 30 * time.Second
+
+// maxErrorBody caps how much of an error response is read
+const maxErrorBody = 1 << 20
+
+// Client calls the JATS REST API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	timeout    time.Duration
+	retries    int
+	retryWait  time.Duration
+	userAgent  string
+
+	mu    sync.RWMutex
+	token string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithToken authenticates requests with an API key or session token
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithHTTPClient sends requests through httpClient, e.g. one with a custom
+// transport. Its Timeout should be 0, or it also cuts off downloads and
+// event streams; use WithTimeout instead.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout bounds each call, retries included; 0 leaves calls bounded
+// only by their context
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetries sets how many times a failed request is retried, waiting wait
+// before the first retry and twice as long before each one after, or as long
+// as the server asks with Retry-After. Requests that are safe to repeat are
+// retried after network errors and 429, 502, 503 and 504 responses; POST and
+// PATCH requests only when the server rejected them with 429 and a
+// Retry-After header, as they may otherwise have been applied already.
+func WithRetries(retries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.retryWait = wait
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New creates a client for the JATS server at baseURL, such as
+// "https://jats.example.com"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{},
+		timeout:    DefaultTimeout,
+		retries:    DefaultRetries,
+		retryWait:  DefaultRetryWait,
+		userAgent:  "jats-go-client",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// BaseURL returns the URL of the server the client calls
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Token returns the API key or session token requests are sent with
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// SetToken changes the API key or session token requests are sent with
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+// envelope is the wrapper the API puts around every JSON response
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Message string          `json:"message"`
+}
+
+// Do sends a request to an API path, such as "/api/v1/tasks", with body, if
+// not nil, as JSON, and decodes the data of the response into out, if not
+// nil. It is the way to reach endpoints the client has no method for.
+func (c *Client) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	respBody, err := c.Raw(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	return decodeData(respBody, out)
+}
+
+// Raw sends a request like Do but returns the whole response body, envelope
+// included
+func (c *Client) Raw(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
+	req := &request{method: method, path: path}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		req.body = data
+		req.contentType = "application/json"
+	}
+	return c.read(ctx, req)
+}
+
+// decodeData decodes the data of an API response into out
+func decodeData(respBody []byte, out interface{}) error {
+	if out == nil || len(bytes.TrimSpace(respBody)) == 0 {
+		return nil
+	}
+	var resp envelope
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(resp.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// request is a single API call, sent again as is when retried
+type request struct {
+	method      string
+	path        string // including any query string
+	contentType string
+	body        []byte
+	header      http.Header
+	wait        time.Duration // how long the server may hold the request before answering
+}
+
+// read sends a request within the client's timeout and reads the response
+func (c *Client) read(ctx context.Context, req *request) ([]byte, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout+req.wait)
+		defer cancel()
+	}
+
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return respBody, nil
+}
+
+// send sends a request, retrying it as the retry policy allows, and returns
+// the response for the caller to read and close. Error responses are returned
+// as an *APIError.
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		var body io.Reader
+		if req.body != nil {
+			body = bytes.NewReader(req.body)
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, req.method, c.baseURL+req.path, body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		for name, values := range req.header {
+			httpReq.Header[name] = values
+		}
+		if req.contentType != "" {
+			httpReq.Header.Set("Content-Type", req.contentType)
+		}
+		if httpReq.Header.Get("Accept") == "" {
+			httpReq.Header.Set("Accept", "application/json")
+		}
+		if c.userAgent != "" {
+			httpReq.Header.Set("User-Agent", c.userAgent)
+		}
+		if token := c.Token(); token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			if attempt < c.retries && ctx.Err() == nil && idempotent(req.method) {
+				if err := sleep(ctx, c.backoff(attempt)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+
+		if attempt < c.retries {
+			if wait, ok := c.retryAfter(req.method, resp, attempt); ok {
+				io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+				resp.Body.Close()
+				if err := sleep(ctx, wait); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+
+		if resp.StatusCode >= 400 {
+			defer resp.Body.Close()
+			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+			return nil, parseError(resp.StatusCode, respBody)
+		}
+		return resp, nil
+	}
+}
+
+// retryAfter says whether a response should be retried, and how long to wait
+// first
+func (c *Client) retryAfter(method string, resp *http.Response, attempt int) (time.Duration, bool) {
+	wait, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		if !idempotent(method) && !hasRetryAfter {
+			return 0, false
+		}
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if !idempotent(method) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if !hasRetryAfter {
+		wait = c.backoff(attempt)
+	}
+	return wait, true
+}
+
+// backoff is how long to wait before retry number attempt+1: the retry wait
+// doubled for each earlier retry, give or take a little so that clients
+// failing together do not retry together
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.retryWait << attempt
+	if wait <= 0 || wait > maxRetryWait {
+		wait = maxRetryWait
+	}
+	jitter := time.Duration(rand.Int63n(int64(wait)/5 + 1))
+	return wait - wait/10 + jitter
+}
+
+// parseRetryAfter reads a Retry-After header, given in seconds or as a date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		wait = time.Until(at)
+	} else {
+		return 0, false
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > maxRetryWait {
+		wait = maxRetryWait
+	}
+	return wait, true
+}
+
+// idempotent says whether a request with method can be sent again without
+// risk of applying it twice
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// sleep waits for d, or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,306 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sendData writes a successful API response
+func sendData(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": data})
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return New(server.URL, WithToken("secret"), WithRetries(2, time.Millisecond))
+}
+
+func TestClient_DecodesData(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/tasks/7" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		sendData(w, http.StatusOK, map[string]interface{}{"id": 7, "name": "Replace toner", "status": "open"})
+	})
+
+	task, err := c.GetTask(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if task.ID != 7 || task.Name != "Replace toner" || task.Status != TaskStatusOpen {
+		t.Errorf("task = %+v", task)
+	}
+}
+
+func TestClient_ListTasksPagination(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("status"); got != "open,in-progress" {
+			t.Errorf("status = %q", got)
+		}
+		if got := r.URL.Query().Get("tags"); got != "office" {
+			t.Errorf("tags = %q", got)
+		}
+		sendData(w, http.StatusOK, map[string]interface{}{
+			"items":      []map[string]interface{}{{"id": 1}, {"id": 2}},
+			"pagination": map[string]int{"total": 12, "limit": 2, "offset": 0, "pages": 6},
+		})
+	})
+
+	tasks, pagination, err := c.ListTasks(context.Background(), &TaskFilters{
+		Status: []TaskStatus{TaskStatusOpen, TaskStatusInProgress},
+		Tags:   []string{"office"},
+		Limit:  2,
+	})
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(tasks) != 2 || pagination.Total != 12 || pagination.Pages != 6 {
+		t.Errorf("tasks = %d, pagination = %+v", len(tasks), pagination)
+	}
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		sentinel error
+		code     string
+		message  string
+	}{
+		{"envelope", http.StatusNotFound, `{"success":false,"error":{"code":"NOT_FOUND","message":"Task not found"}}`, ErrNotFound, "NOT_FOUND", "Task not found"},
+		{"validation", http.StatusUnprocessableEntity, `{"success":false,"error":{"code":"VALIDATION_ERROR","message":"Validation failed","details":["name is required"]}}`, ErrBadRequest, "VALIDATION_ERROR", "Validation failed"},
+		{"plain error", http.StatusForbidden, `{"error":"Insufficient permissions"}`, ErrForbidden, "", "Insufficient permissions"},
+		{"text", http.StatusUnauthorized, "Unauthorized\n", ErrUnauthorized, "", "Unauthorized"},
+		{"empty", http.StatusConflict, "", ErrConflict, "", "Conflict"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			})
+
+			_, err := c.GetTask(context.Background(), 1)
+			if !errors.Is(err, tt.sentinel) {
+				t.Fatalf("err = %v, want %v", err, tt.sentinel)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %T, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Code != tt.code || apiErr.Message != tt.message {
+				t.Errorf("APIError = %+v", apiErr)
+			}
+		})
+	}
+}
+
+func TestClient_ValidationDetails(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"success":false,"error":{"code":"VALIDATION_ERROR","message":"Validation failed","details":["name is required"]}}`)
+	})
+
+	_, err := c.CreateTask(context.Background(), &TaskRequest{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v", err)
+	}
+	var details []string
+	if err := json.Unmarshal(apiErr.Details, &details); err != nil || len(details) != 1 || details[0] != "name is required" {
+		t.Errorf("details = %s", apiErr.Details)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		status     int
+		retryAfter string
+		wantCalls  int32
+		wantErr    error
+	}{
+		{"GET retried until it succeeds", http.MethodGet, http.StatusServiceUnavailable, "", 3, nil},
+		{"GET gives up after the retries", http.MethodGet, http.StatusBadGateway, "", 3, ErrServer},
+		{"POST not retried after a server error", http.MethodPost, http.StatusServiceUnavailable, "", 1, ErrServer},
+		{"POST not retried when rate limited without Retry-After", http.MethodPost, http.StatusTooManyRequests, "", 1, ErrRateLimited},
+		{"POST retried when asked to with Retry-After", http.MethodPost, http.StatusTooManyRequests, "0", 3, nil},
+		{"client errors not retried", http.MethodGet, http.StatusNotFound, "", 1, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if r.Method == http.MethodPost && string(body) != `{"name":"x"}` {
+					t.Errorf("attempt %d body = %q", atomic.LoadInt32(&calls)+1, body)
+				}
+				// The last attempt succeeds, unless it is a permanent error
+				if atomic.AddInt32(&calls, 1) < 3 || tt.wantErr != nil {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.status)
+					return
+				}
+				sendData(w, http.StatusOK, map[string]interface{}{"id": 1})
+			})
+
+			var body interface{}
+			if tt.method == http.MethodPost {
+				body = map[string]string{"name": "x"}
+			}
+			err := c.Do(context.Background(), tt.method, "/api/v1/tasks", body, nil)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClient_ContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.GetTask(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithTimeout(50*time.Millisecond), WithRetries(0, 0))
+	_, err := c.ListProjects(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+}
+
+func TestClient_UploadAttachment(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/tasks/3/attachments" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("FormFile: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		if header.Filename != "notes.txt" || string(data) != "hello" {
+			t.Errorf("file = %q %q", header.Filename, data)
+		}
+		sendData(w, http.StatusCreated, map[string]interface{}{"id": 9, "original_name": header.Filename})
+	})
+
+	attachment, err := c.UploadAttachment(context.Background(), 3, "notes.txt", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("UploadAttachment: %v", err)
+	}
+	if attachment.ID != 9 {
+		t.Errorf("attachment = %+v", attachment)
+	}
+}
+
+func TestClient_Login(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/auth/login":
+			var req map[string]string
+			json.NewDecoder(r.Body).Decode(&req)
+			if req["totp_code"] == "" {
+				sendData(w, http.StatusOK, map[string]bool{"requires_totp": true})
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "session_token", Value: "session-123"})
+			sendData(w, http.StatusOK, map[string]interface{}{
+				"user":    map[string]interface{}{"id": 1, "username": req["username"]},
+				"session": map[string]interface{}{"id": 4},
+			})
+		case "/api/v1/auth/profile":
+			if got := r.Header.Get("Authorization"); got != "Bearer session-123" {
+				t.Errorf("Authorization = %q", got)
+			}
+			sendData(w, http.StatusOK, map[string]interface{}{"id": 1, "username": "alice"})
+		}
+	})
+
+	ctx := context.Background()
+	if _, err := c.Login(ctx, "alice", "pw", ""); !errors.Is(err, ErrTOTPRequired) {
+		t.Fatalf("err = %v, want ErrTOTPRequired", err)
+	}
+	result, err := c.Login(ctx, "alice", "pw", "123456")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if result.Token != "session-123" || result.User.Username != "alice" || c.Token() != "session-123" {
+		t.Errorf("result = %+v, token = %q", result, c.Token())
+	}
+	if _, err := c.GetProfile(ctx); err != nil {
+		t.Fatalf("GetProfile: %v", err)
+	}
+}
+
+func TestClient_StreamEvents(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Last-Event-ID"); got != "4" {
+			t.Errorf("Last-Event-ID = %q", got)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "retry: 3000\n\n")
+		io.WriteString(w, "id: 5\nevent: task.updated\ndata: {\"id\":5,\"type\":\"task.updated\",\"task_id\":2}\n\n")
+		io.WriteString(w, ": keep-alive\n\n")
+		io.WriteString(w, "id: 6\nevent: task.deleted\ndata: {\"id\":6,\"type\":\"task.deleted\",\"task_id\":3}\n\n")
+	})
+
+	var events []TaskEvent
+	lastID, err := c.StreamEvents(context.Background(), 4, func(event TaskEvent) {
+		events = append(events, event)
+	})
+	if err != nil {
+		t.Fatalf("StreamEvents: %v", err)
+	}
+	if lastID != 6 || len(events) != 2 || events[0].TaskID != 2 || events[1].Type != "task.deleted" {
+		t.Errorf("lastID = %d, events = %+v", lastID, events)
+	}
+}
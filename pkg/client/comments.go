@@ -0,0 +1,139 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+)
+
+// CommentRequest adds or edits a comment. Canned names a canned response to
+// use instead of Content.
+type CommentRequest struct {
+	Content   string `json:"content"`
+	Canned    string `json:"canned,omitempty"`
+	IsPrivate bool   `json:"is_private,omitempty"`
+	FromEmail string `json:"from_email,omitempty"`
+}
+
+// ListComments returns the comments on a task
+func (c *Client) ListComments(ctx context.Context, taskID uint) ([]Comment, error) {
+	var comments []Comment
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/tasks/%d/comments", taskID), nil, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// AddComment comments on a task
+func (c *Client) AddComment(ctx context.Context, taskID uint, req *CommentRequest) (*Comment, error) {
+	var comment Comment
+	if err := c.Do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/tasks/%d/comments", taskID), req, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// UpdateComment edits a comment on a task
+func (c *Client) UpdateComment(ctx context.Context, taskID, commentID uint, req *CommentRequest) (*Comment, error) {
+	var comment Comment
+	if err := c.Do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/tasks/%d/comments/%d", taskID, commentID), req, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// DeleteComment deletes a comment on a task
+func (c *Client) DeleteComment(ctx context.Context, taskID, commentID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/tasks/%d/comments/%d", taskID, commentID), nil, nil)
+}
+
+// ListAttachments returns the files attached to a task
+func (c *Client) ListAttachments(ctx context.Context, taskID uint) ([]Attachment, error) {
+	var attachments []Attachment
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/tasks/%d/attachments", taskID), nil, &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// UploadAttachment attaches the contents of r to a task as filename. The file
+// is read into memory first, so the upload can be retried.
+func (c *Client) UploadAttachment(ctx context.Context, taskID uint, filename string, r io.Reader) (*Attachment, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	respBody, err := c.read(ctx, &request{
+		method:      http.MethodPost,
+		path:        fmt.Sprintf("/api/v1/tasks/%d/attachments", taskID),
+		contentType: form.FormDataContentType(),
+		body:        body.Bytes(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var attachment Attachment
+	if err := decodeData(respBody, &attachment); err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// Download is a file being downloaded from the API. Body must be closed.
+type Download struct {
+	Body        io.ReadCloser
+	Filename    string // as the server names it, empty if it did not
+	ContentType string
+	Size        int64 // -1 if unknown
+}
+
+// OpenAttachment starts downloading an attached file, for callers that want
+// to stream it or report progress. It is not bounded by the client's timeout.
+func (c *Client) OpenAttachment(ctx context.Context, attachmentID uint) (*Download, error) {
+	return c.open(ctx, fmt.Sprintf("/api/v1/attachments/%d/download", attachmentID))
+}
+
+// DownloadAttachment writes an attached file to w and returns its name
+func (c *Client) DownloadAttachment(ctx context.Context, attachmentID uint, w io.Writer) (string, error) {
+	download, err := c.OpenAttachment(ctx, attachmentID)
+	if err != nil {
+		return "", err
+	}
+	defer download.Body.Close()
+
+	if _, err := io.Copy(w, download.Body); err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+	return download.Filename, nil
+}
+
+// open sends a GET request for a file and returns the response for reading
+func (c *Client) open(ctx context.Context, path string) (*Download, error) {
+	resp, err := c.send(ctx, &request{method: http.MethodGet, path: path, header: http.Header{"Accept": {"*/*"}}})
+	if err != nil {
+		return nil, err
+	}
+
+	download := &Download{
+		Body:        resp.Body,
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		download.Filename = params["filename"]
+	}
+	return download, nil
+}
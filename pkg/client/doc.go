@@ -0,0 +1,25 @@
+// Package client is a Go client for the JATS REST API, for programs that
+// integrate with a JATS server. The jats command line tool is built on it.
+//
+//	c := client.New("https://jats.example.com", client.WithToken(os.Getenv("JATS_TOKEN")))
+//	task, err := c.CreateTask(ctx, &client.TaskRequest{Name: "Replace printer toner", Tags: []string{"office"}})
+//	if err != nil {
+//		return err
+//	}
+//	_, err = c.AddComment(ctx, task.ID, &client.CommentRequest{Content: "Ordered, arriving Tuesday"})
+//
+// Every method takes a context, and is also bounded by the client's timeout
+// (see WithTimeout), except downloads and the event stream. Requests that
+// fail with a network error or a temporary server error are retried as
+// WithRetries describes.
+//
+// Error responses are returned as an *APIError, which matches the error of
+// its status, such as ErrNotFound, with errors.Is:
+//
+//	task, err := c.GetTask(ctx, id)
+//	if errors.Is(err, client.ErrNotFound) {
+//		...
+//	}
+//
+// Endpoints without a method of their own can be called with Do.
+package client
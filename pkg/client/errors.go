@@ -0,0 +1,102 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Errors an *APIError matches with errors.Is, by the status of the response
+var (
+	ErrBadRequest      = errors.New("bad request")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrPayloadTooLarge = errors.New("payload too large")
+	ErrRateLimited     = errors.New("rate limited")
+	ErrServer          = errors.New("server error")
+)
+
+// ErrTOTPRequired is returned by Login for an account with two-factor
+// authentication when no code was given
+var ErrTOTPRequired = errors.New("two-factor authentication code required")
+
+// APIError is an error response from the API. Use errors.Is with ErrNotFound
+// and the like to tell kinds of errors apart, or errors.As to get the code.
+type APIError struct {
+	StatusCode int
+	Code       string // e.g. "NOT_FOUND" or "VALIDATION_ERROR", empty if the server sent none
+	Message    string
+	Details    json.RawMessage // more about the error, such as the failed validations, if any
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("API error (%d %s): %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
+}
+
+// Unwrap returns the error matching the status of the response
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case e.StatusCode == http.StatusRequestEntityTooLarge:
+		return ErrPayloadTooLarge
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrServer
+	case e.StatusCode >= 400:
+		return ErrBadRequest
+	}
+	return nil
+}
+
+// parseError reads an error response: the API's {"error": {...}} envelope,
+// the {"error": "..."} some handlers send, or plain text
+func parseError(status int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: status}
+
+	var resp struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+	}
+	if json.Unmarshal(body, &resp) == nil && len(resp.Error) > 0 {
+		var detailed struct {
+			Code    string          `json:"code"`
+			Message string          `json:"message"`
+			Details json.RawMessage `json:"details"`
+		}
+		var message string
+		if json.Unmarshal(resp.Error, &detailed) == nil {
+			apiErr.Code = detailed.Code
+			apiErr.Message = detailed.Message
+			if string(detailed.Details) != "null" {
+				apiErr.Details = detailed.Details
+			}
+		} else if json.Unmarshal(resp.Error, &message) == nil {
+			apiErr.Message = message
+		}
+		if apiErr.Message == "" {
+			apiErr.Message = resp.Message
+		}
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+	}
+	return apiErr
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"time"
+
+	"github.com/soarinferret/jats/internal/models"
+)
+
+// The API's resources, as the server defines them
+type (
+	Task             = models.Task
+	TaskStatus       = models.TaskStatus
+	TaskPriority     = models.TaskPriority
+	TaskSize         = models.TaskSize
+	TaskLink         = models.TaskLink
+	TaskStatusChange = models.TaskStatusChange
+	TaskReview       = models.TaskReview
+	Subtask          = models.Subtask
+	TimeEntry        = models.TimeEntry
+	Comment          = models.Comment
+	Attachment       = models.Attachment
+	SavedQuery       = models.SavedQuery
+	Project          = models.Project
+	Milestone        = models.Milestone
+	User             = models.User
+	Session          = models.Session
+	APIKey           = models.APIKey
+	CannedResponse   = models.CannedResponse
+	AutomationRule   = models.AutomationRule
+	QuarantinedEmail = models.QuarantinedEmail
+	Tenant           = models.Tenant
+)
+
+// Task statuses
+const (
+	TaskStatusOpen          = models.TaskStatusOpen
+	TaskStatusInProgress    = models.TaskStatusInProgress
+	TaskStatusPendingReview = models.TaskStatusPendingReview
+	TaskStatusResolved      = models.TaskStatusResolved
+	TaskStatusClosed        = models.TaskStatusClosed
+)
+
+// Task priorities
+const (
+	TaskPriorityLow    = models.TaskPriorityLow
+	TaskPriorityMedium = models.TaskPriorityMedium
+	TaskPriorityHigh   = models.TaskPriorityHigh
+)
+
+// Permissions an API key can be given
+const (
+	PermissionReadTasks   = models.PermissionReadTasks
+	PermissionWriteTasks  = models.PermissionWriteTasks
+	PermissionDeleteTasks = models.PermissionDeleteTasks
+	PermissionReadTime    = models.PermissionReadTime
+	PermissionWriteTime   = models.PermissionWriteTime
+	PermissionReadEmails  = models.PermissionReadEmails
+	PermissionAdmin       = models.PermissionAdmin
+)
+
+// Pagination describes the page of a paginated list
+type Pagination struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Pages  int `json:"pages"`
+}
+
+// page is the data of a paginated response
+type page[T any] struct {
+	Items      []T        `json:"items"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// MilestoneProgress is a milestone with how far along its tasks are
+type MilestoneProgress struct {
+	Milestone       *Milestone `json:"milestone"`
+	TotalTasks      int        `json:"total_tasks"`
+	ResolvedTasks   int        `json:"resolved_tasks"`
+	OpenTasks       int        `json:"open_tasks"`
+	Percent         float64    `json:"percent"`
+	LoggedMinutes   int        `json:"logged_minutes"`
+	EstimateMinutes int        `json:"estimate_minutes"`
+	Overdue         bool       `json:"overdue"`
+}
+
+// TagInfo is a tag in use and how many tasks have it
+type TagInfo struct {
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	LastUsed string `json:"last_used"`
+}
+
+// ContextCount is a task context in use, such as "@home", and its open task
+// count
+type ContextCount struct {
+	Context   string `json:"context"`
+	OpenTasks int    `json:"open_tasks"`
+}
+
+// Dependencies are the tasks a task blocks and the tasks blocking it
+type Dependencies struct {
+	Blocks    []TaskLink `json:"blocks"`
+	BlockedBy []TaskLink `json:"blocked_by"`
+}
+
+// SearchHighlight is the byte range of a match in a SearchSnippet
+type SearchHighlight struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SearchSnippet is the part of a field around a search match
+type SearchSnippet struct {
+	Text       string            `json:"text"`
+	Highlights []SearchHighlight `json:"highlights"`
+}
+
+// SearchResult is a task or comment matching a search
+type SearchResult struct {
+	ID        uint           `json:"id"`
+	Type      string         `json:"type"` // "task" or "comment"
+	Name      string         `json:"name,omitempty"`
+	Content   string         `json:"content,omitempty"`
+	TaskID    *uint          `json:"task_id,omitempty"`
+	MatchType string         `json:"match_type"`
+	Score     float64        `json:"score"`
+	Snippet   *SearchSnippet `json:"snippet,omitempty"`
+}
+
+// SearchResults are the matches of a search by type: "tasks" and "comments"
+type SearchResults struct {
+	Query   string                    `json:"query"`
+	Results map[string][]SearchResult `json:"results"`
+	Total   int                       `json:"total"`
+}
+
+// ActivityEvent is an entry in the activity feed
+type ActivityEvent struct {
+	Type       string     `json:"type"`
+	TaskID     uint       `json:"task_id"`
+	TaskName   string     `json:"task_name"`
+	Timestamp  time.Time  `json:"timestamp"`
+	Detail     string     `json:"detail,omitempty"`
+	FromStatus TaskStatus `json:"from_status,omitempty"`
+	ToStatus   TaskStatus `json:"to_status,omitempty"`
+	Minutes    int        `json:"minutes,omitempty"`
+}
+
+// TaskEvent is a task change from the live event stream
+type TaskEvent struct {
+	ID        uint64     `json:"id"`
+	Type      string     `json:"type"` // task.created, task.updated, task.commented or task.deleted
+	TaskID    uint       `json:"task_id"`
+	Name      string     `json:"name,omitempty"`
+	Status    TaskStatus `json:"status,omitempty"`
+	CommentID uint       `json:"comment_id,omitempty"`
+	At        time.Time  `json:"at"`
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ProjectRequest creates or renames a project
+type ProjectRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// MilestoneRequest creates or updates a milestone
+type MilestoneRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	DueDate     string `json:"due_date,omitempty"` // e.g. "2024-06-30" or "end of month"; empty for none
+}
+
+// ListProjects returns the projects, each with its open task count
+func (c *Client) ListProjects(ctx context.Context) ([]Project, error) {
+	var projects []Project
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/projects", nil, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// GetProject returns a project
+func (c *Client) GetProject(ctx context.Context, projectID uint) (*Project, error) {
+	var project Project
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/projects/%d", projectID), nil, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// CreateProject creates a project
+func (c *Client) CreateProject(ctx context.Context, req *ProjectRequest) (*Project, error) {
+	var project Project
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/projects", req, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// UpdateProject renames a project or changes its description
+func (c *Client) UpdateProject(ctx context.Context, projectID uint, req *ProjectRequest) (*Project, error) {
+	var project Project
+	if err := c.Do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/projects/%d", projectID), req, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// DeleteProject deletes a project; the default project cannot be deleted
+func (c *Client) DeleteProject(ctx context.Context, projectID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/projects/%d", projectID), nil, nil)
+}
+
+// ListMilestones returns the milestones with their progress
+func (c *Client) ListMilestones(ctx context.Context) ([]MilestoneProgress, error) {
+	var milestones []MilestoneProgress
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/milestones", nil, &milestones); err != nil {
+		return nil, err
+	}
+	return milestones, nil
+}
+
+// GetMilestone returns a milestone
+func (c *Client) GetMilestone(ctx context.Context, milestoneID uint) (*Milestone, error) {
+	var milestone Milestone
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/milestones/%d", milestoneID), nil, &milestone); err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+// GetMilestoneProgress returns how far along the tasks of a milestone are
+func (c *Client) GetMilestoneProgress(ctx context.Context, milestoneID uint) (*MilestoneProgress, error) {
+	var progress MilestoneProgress
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/milestones/%d/progress", milestoneID), nil, &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// MilestoneTasks returns the tasks of a milestone
+func (c *Client) MilestoneTasks(ctx context.Context, milestoneID uint) ([]*Task, error) {
+	var tasks []*Task
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/milestones/%d/tasks", milestoneID), nil, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// CreateMilestone creates a milestone
+func (c *Client) CreateMilestone(ctx context.Context, req *MilestoneRequest) (*Milestone, error) {
+	var milestone Milestone
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/milestones", req, &milestone); err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+// UpdateMilestone changes a milestone
+func (c *Client) UpdateMilestone(ctx context.Context, milestoneID uint, req *MilestoneRequest) (*Milestone, error) {
+	var milestone Milestone
+	if err := c.Do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/milestones/%d", milestoneID), req, &milestone); err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+// DeleteMilestone deletes a milestone, leaving its tasks without one
+func (c *Client) DeleteMilestone(ctx context.Context, milestoneID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/milestones/%d", milestoneID), nil, nil)
+}
+
+// ListTags returns the tags in use with how many tasks have each
+func (c *Client) ListTags(ctx context.Context) ([]TagInfo, error) {
+	var resp struct {
+		Tags []TagInfo `json:"tags"`
+	}
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/tags", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tags, nil
+}
+
+// ListContexts returns the task contexts in use with their open task counts
+func (c *Client) ListContexts(ctx context.Context) ([]ContextCount, error) {
+	var contexts []ContextCount
+	if err := c.Do(ctx, http.MethodGet, "/api/v1/contexts", nil, &contexts); err != nil {
+		return nil, err
+	}
+	return contexts, nil
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SavedQueryRequest creates or updates a saved query. Updating replaces the
+// tags and expression; an empty expression clears it.
+type SavedQueryRequest struct {
+	Name         string   `json:"name"`
+	IncludedTags []string `json:"included_tags"`
+	ExcludedTags []string `json:"excluded_tags"`
+	Expression   string   `json:"expression"`
+	ProjectID    *uint    `json:"project_id,omitempty"` // nil for every project when creating, unchanged when updating
+}
+
+// ListSavedQueries returns the saved queries, with the number of tasks each
+// matches if counts is set
+func (c *Client) ListSavedQueries(ctx context.Context, counts bool) ([]SavedQuery, error) {
+	query := url.Values{}
+	if counts {
+		query.Set("counts", "true")
+	}
+	var queries []SavedQuery
+	if err := c.Do(ctx, http.MethodGet, withQuery("/api/v1/saved-queries", query), nil, &queries); err != nil {
+		return nil, err
+	}
+	return queries, nil
+}
+
+// GetSavedQuery returns a saved query
+func (c *Client) GetSavedQuery(ctx context.Context, queryID uint) (*SavedQuery, error) {
+	var query SavedQuery
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/saved-queries/%d", queryID), nil, &query); err != nil {
+		return nil, err
+	}
+	return &query, nil
+}
+
+// CreateSavedQuery saves a query
+func (c *Client) CreateSavedQuery(ctx context.Context, req *SavedQueryRequest) (*SavedQuery, error) {
+	var query SavedQuery
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/saved-queries", req, &query); err != nil {
+		return nil, err
+	}
+	return &query, nil
+}
+
+// UpdateSavedQuery changes a saved query
+func (c *Client) UpdateSavedQuery(ctx context.Context, queryID uint, req *SavedQueryRequest) (*SavedQuery, error) {
+	var query SavedQuery
+	if err := c.Do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/saved-queries/%d", queryID), req, &query); err != nil {
+		return nil, err
+	}
+	return &query, nil
+}
+
+// DeleteSavedQuery deletes a saved query
+func (c *Client) DeleteSavedQuery(ctx context.Context, queryID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/saved-queries/%d", queryID), nil, nil)
+}
+
+// SavedQueryTasks returns the tasks a saved query matches
+func (c *Client) SavedQueryTasks(ctx context.Context, queryID uint) ([]*Task, error) {
+	var tasks []*Task
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/saved-queries/%d/tasks", queryID), nil, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Search finds the tasks and comments matching a search, such as
+// "printer status:open"
+func (c *Client) Search(ctx context.Context, q string) (*SearchResults, error) {
+	var results SearchResults
+	if err := c.Do(ctx, http.MethodGet, withQuery("/api/v1/search", url.Values{"q": {q}}), nil, &results); err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
+
+// ActivityFilters narrows the activity feed. Zero values match everything.
+type ActivityFilters struct {
+	Since   string // e.g. "2024-06-01" or "monday"
+	Until   string
+	Types   []string // e.g. "commented", "time_logged"
+	TaskID  uint
+	Tag     string
+	QueryID uint   // only tasks matching this saved query
+	Mention string // only events mentioning this username
+	Limit   int
+	Offset  int
+	// After keeps only events newer than this. With Wait, the server holds
+	// the request until one arrives or Wait passes, for following the feed.
+	After time.Time
+	Wait  time.Duration
+}
+
+// ListActivity returns a page of the activity feed, newest first
+func (c *Client) ListActivity(ctx context.Context, filters *ActivityFilters) ([]ActivityEvent, *Pagination, error) {
+	query := url.Values{}
+	req := &request{method: http.MethodGet}
+	if filters != nil {
+		setString(query, "since", filters.Since)
+		setString(query, "until", filters.Until)
+		setList(query, "type", filters.Types)
+		setString(query, "tag", filters.Tag)
+		setString(query, "mention", filters.Mention)
+		if filters.TaskID > 0 {
+			query.Set("task_id", strconv.FormatUint(uint64(filters.TaskID), 10))
+		}
+		if filters.QueryID > 0 {
+			query.Set("query", strconv.FormatUint(uint64(filters.QueryID), 10))
+		}
+		if filters.Limit > 0 {
+			query.Set("limit", strconv.Itoa(filters.Limit))
+		}
+		if filters.Offset > 0 {
+			query.Set("offset", strconv.Itoa(filters.Offset))
+		}
+		if !filters.After.IsZero() {
+			query.Set("after", filters.After.Format(time.RFC3339Nano))
+		}
+		if filters.Wait > 0 {
+			query.Set("wait", strconv.Itoa(int(filters.Wait/time.Second)))
+			req.wait = filters.Wait
+		}
+	}
+	req.path = withQuery("/api/v1/activity", query)
+
+	respBody, err := c.read(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	var resp page[ActivityEvent]
+	if err := decodeData(respBody, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Items, &resp.Pagination, nil
+}
+
+// StreamEvents follows the live task event stream, calling handle with each
+// event, until ctx is done or the connection drops. A lastID other than 0
+// resumes after that event, getting the recent ones missed. It returns the
+// ID of the last event handled, to resume from. The stream is not bounded by
+// the client's timeout.
+func (c *Client) StreamEvents(ctx context.Context, lastID uint64, handle func(TaskEvent)) (uint64, error) {
+	header := http.Header{"Accept": {"text/event-stream"}}
+	if lastID > 0 {
+		header.Set("Last-Event-ID", strconv.FormatUint(lastID, 10))
+	}
+	resp, err := c.send(ctx, &request{method: http.MethodGet, path: "/api/v1/events", header: header})
+	if err != nil {
+		return lastID, err
+	}
+	defer resp.Body.Close()
+
+	// Events are "field: value" lines ended by a blank line; only data is
+	// needed, as it repeats the event's ID and type
+	var data strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			if value, ok := strings.CutPrefix(line, "data:"); ok {
+				data.WriteString(strings.TrimPrefix(value, " "))
+			}
+			continue
+		}
+		if data.Len() == 0 {
+			continue
+		}
+		var event TaskEvent
+		if err := json.Unmarshal([]byte(data.String()), &event); err == nil {
+			lastID = event.ID
+			handle(event)
+		}
+		data.Reset()
+	}
+	if ctx.Err() != nil {
+		return lastID, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return lastID, fmt.Errorf("event stream failed: %w", err)
+	}
+	return lastID, nil
+}
@@ -0,0 +1,330 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TaskFilters narrows a task list. Zero values match everything.
+type TaskFilters struct {
+	Status      []TaskStatus
+	Priority    []TaskPriority
+	Tags        []string // any of these tags
+	AllTags     []string // every one of these tags
+	ExcludeTags []string // none of these tags
+	TagExpr     string   // boolean tag expression, e.g. (a OR b) AND NOT c
+	Search      string
+	In          []string // fields to search: name, description, comments, time_entries; default all
+	Milestone   string   // milestone ID or "none"
+	Context     string   // context such as "@home", or "none"
+	Project     uint     // 0 for every project
+	DueBefore   string   // due on or before this date
+	DueAfter    string   // due on or after this date
+	Overdue     bool
+	Sort        string // e.g. "recently_touched" for the user's latest interactions first
+	Limit       int    // the server's default page size if 0
+	Offset      int
+}
+
+// query encodes the filters as query parameters
+func (f *TaskFilters) query() url.Values {
+	query := url.Values{}
+	if f == nil {
+		return query
+	}
+	if len(f.Status) > 0 {
+		statuses := make([]string, len(f.Status))
+		for i, status := range f.Status {
+			statuses[i] = string(status)
+		}
+		query.Set("status", strings.Join(statuses, ","))
+	}
+	if len(f.Priority) > 0 {
+		priorities := make([]string, len(f.Priority))
+		for i, priority := range f.Priority {
+			priorities[i] = string(priority)
+		}
+		query.Set("priority", strings.Join(priorities, ","))
+	}
+	setList(query, "tags", f.Tags)
+	setList(query, "all_tags", f.AllTags)
+	setList(query, "exclude_tags", f.ExcludeTags)
+	setList(query, "in", f.In)
+	setString(query, "tag_expr", f.TagExpr)
+	setString(query, "search", f.Search)
+	setString(query, "milestone", f.Milestone)
+	setString(query, "context", f.Context)
+	setString(query, "due_before", f.DueBefore)
+	setString(query, "due_after", f.DueAfter)
+	setString(query, "sort", f.Sort)
+	if f.Project > 0 {
+		query.Set("project", strconv.FormatUint(uint64(f.Project), 10))
+	}
+	if f.Overdue {
+		query.Set("overdue", "true")
+	}
+	if f.Limit > 0 {
+		query.Set("limit", strconv.Itoa(f.Limit))
+	}
+	if f.Offset > 0 {
+		query.Set("offset", strconv.Itoa(f.Offset))
+	}
+	return query
+}
+
+func setString(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func setList(query url.Values, key string, values []string) {
+	if len(values) > 0 {
+		query.Set(key, strings.Join(values, ","))
+	}
+}
+
+// withQuery appends query parameters, if any, to a path
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+// TaskRequest creates or updates a task. For an update, nil pointer fields
+// are left unchanged and empty ones cleared.
+type TaskRequest struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Status      TaskStatus   `json:"status,omitempty"`
+	Priority    TaskPriority `json:"priority,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Date        string       `json:"date,omitempty"` // creation date, e.g. "yesterday"
+	MilestoneID *uint        `json:"milestone_id,omitempty"`
+	ProjectID   *uint        `json:"project_id,omitempty"` // new tasks default to the default project
+	Assignee    *string      `json:"assignee,omitempty"`
+	Context     *string      `json:"context,omitempty"`  // e.g. "@home"
+	Size        *string      `json:"size,omitempty"`     // XS-XL or story points
+	DueDate     *string      `json:"due_date,omitempty"` // e.g. "2024-06-01" or "friday"
+}
+
+// SubtaskRequest creates or updates a subtask
+type SubtaskRequest struct {
+	Name            string `json:"name"`
+	Completed       bool   `json:"completed,omitempty"`
+	EstimateMinutes int    `json:"estimate_minutes,omitempty"`
+}
+
+// TimeEntryRequest logs or updates time spent on a task
+type TimeEntryRequest struct {
+	Duration    int    `json:"duration"` // minutes
+	Description string `json:"description,omitempty"`
+	Date        string `json:"date,omitempty"`
+	SubtaskID   *uint  `json:"subtask_id,omitempty"`
+	// nil leaves it to the server: the task's tags when logging, unchanged when updating
+	Billable *bool `json:"billable,omitempty"`
+}
+
+// ListTasks returns a page of the tasks matching filters, which may be nil
+func (c *Client) ListTasks(ctx context.Context, filters *TaskFilters) ([]*Task, *Pagination, error) {
+	var resp page[*Task]
+	if err := c.Do(ctx, http.MethodGet, withQuery("/api/v1/tasks", filters.query()), nil, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Items, &resp.Pagination, nil
+}
+
+// GetTask returns a task with its subtasks, time entries, comments and
+// attachments
+func (c *Client) GetTask(ctx context.Context, taskID uint) (*Task, error) {
+	var task Task
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/tasks/%d", taskID), nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// CreateTask creates a task
+func (c *Client) CreateTask(ctx context.Context, req *TaskRequest) (*Task, error) {
+	var task Task
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/tasks", req, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// QuickAddTask creates a task from a quick-add line such as
+// "Fix login +auth -p high -t 30m", parsed by the server
+func (c *Client) QuickAddTask(ctx context.Context, input string) (*Task, error) {
+	var task Task
+	if err := c.Do(ctx, http.MethodPost, "/api/v1/tasks/quick", map[string]string{"input": input}, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// UpdateTask replaces the fields of a task
+func (c *Client) UpdateTask(ctx context.Context, taskID uint, req *TaskRequest) (*Task, error) {
+	var task Task
+	if err := c.Do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/tasks/%d", taskID), req, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// PatchTask changes only the given fields of a task, such as
+// {"status": "resolved"}
+func (c *Client) PatchTask(ctx context.Context, taskID uint, fields map[string]interface{}) (*Task, error) {
+	var task Task
+	if err := c.Do(ctx, http.MethodPatch, fmt.Sprintf("/api/v1/tasks/%d", taskID), fields, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteTask deletes a task
+func (c *Client) DeleteTask(ctx context.Context, taskID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/tasks/%d", taskID), nil, nil)
+}
+
+// GetStatusHistory returns the status changes of a task, oldest first
+func (c *Client) GetStatusHistory(ctx context.Context, taskID uint) ([]TaskStatusChange, error) {
+	var history []TaskStatusChange
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/tasks/%d/status-history", taskID), nil, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// ReviewTask approves a task pending review, or reopens it with a comment;
+// decision is "approve" or "reopen"
+func (c *Client) ReviewTask(ctx context.Context, taskID uint, decision, comment string) (*Task, error) {
+	req := map[string]string{"decision": decision, "comment": comment}
+	var task Task
+	if err := c.Do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/tasks/%d/review", taskID), req, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// GetDependencies returns the tasks a task blocks and is blocked by
+func (c *Client) GetDependencies(ctx context.Context, taskID uint) (*Dependencies, error) {
+	var deps Dependencies
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/tasks/%d/dependencies", taskID), nil, &deps); err != nil {
+		return nil, err
+	}
+	return &deps, nil
+}
+
+// AddBlocker records that blockerID blocks taskID, which cannot be resolved
+// until blockerID is
+func (c *Client) AddBlocker(ctx context.Context, taskID, blockerID uint) (*Dependencies, error) {
+	req := map[string]uint{"blocked_by": blockerID}
+	var deps Dependencies
+	if err := c.Do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/tasks/%d/dependencies", taskID), req, &deps); err != nil {
+		return nil, err
+	}
+	return &deps, nil
+}
+
+// RemoveDependency removes the dependency between two tasks, whichever way
+// it goes
+func (c *Client) RemoveDependency(ctx context.Context, taskID, otherID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/tasks/%d/dependencies/%d", taskID, otherID), nil, nil)
+}
+
+// AddTags adds tags to a task
+func (c *Client) AddTags(ctx context.Context, taskID uint, tags ...string) (*Task, error) {
+	var task Task
+	if err := c.Do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/tasks/%d/tags", taskID), map[string][]string{"tags": tags}, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// RemoveTag removes a tag from a task
+func (c *Client) RemoveTag(ctx context.Context, taskID uint, tag string) (*Task, error) {
+	var task Task
+	if err := c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/tasks/%d/tags/%s", taskID, url.PathEscape(tag)), nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ListSubtasks returns the subtasks of a task
+func (c *Client) ListSubtasks(ctx context.Context, taskID uint) ([]Subtask, error) {
+	var subtasks []Subtask
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/tasks/%d/subtasks", taskID), nil, &subtasks); err != nil {
+		return nil, err
+	}
+	return subtasks, nil
+}
+
+// CreateSubtask adds a subtask to a task
+func (c *Client) CreateSubtask(ctx context.Context, taskID uint, req *SubtaskRequest) (*Subtask, error) {
+	var subtask Subtask
+	if err := c.Do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/tasks/%d/subtasks", taskID), req, &subtask); err != nil {
+		return nil, err
+	}
+	return &subtask, nil
+}
+
+// UpdateSubtask changes a subtask
+func (c *Client) UpdateSubtask(ctx context.Context, taskID, subtaskID uint, req *SubtaskRequest) (*Subtask, error) {
+	var subtask Subtask
+	if err := c.Do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/tasks/%d/subtasks/%d", taskID, subtaskID), req, &subtask); err != nil {
+		return nil, err
+	}
+	return &subtask, nil
+}
+
+// ToggleSubtask marks a subtask done, or not done again
+func (c *Client) ToggleSubtask(ctx context.Context, taskID, subtaskID uint) (*Subtask, error) {
+	var subtask Subtask
+	if err := c.Do(ctx, http.MethodPatch, fmt.Sprintf("/api/v1/tasks/%d/subtasks/%d/toggle", taskID, subtaskID), nil, &subtask); err != nil {
+		return nil, err
+	}
+	return &subtask, nil
+}
+
+// DeleteSubtask deletes a subtask
+func (c *Client) DeleteSubtask(ctx context.Context, taskID, subtaskID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/tasks/%d/subtasks/%d", taskID, subtaskID), nil, nil)
+}
+
+// ListTimeEntries returns the time logged on a task
+func (c *Client) ListTimeEntries(ctx context.Context, taskID uint) ([]TimeEntry, error) {
+	var entries []TimeEntry
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/tasks/%d/time", taskID), nil, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// LogTime logs time spent on a task
+func (c *Client) LogTime(ctx context.Context, taskID uint, req *TimeEntryRequest) (*TimeEntry, error) {
+	var entry TimeEntry
+	if err := c.Do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/tasks/%d/time", taskID), req, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// UpdateTimeEntry changes a time entry of a task
+func (c *Client) UpdateTimeEntry(ctx context.Context, taskID, entryID uint, req *TimeEntryRequest) (*TimeEntry, error) {
+	var entry TimeEntry
+	if err := c.Do(ctx, http.MethodPut, fmt.Sprintf("/api/v1/tasks/%d/time/%d", taskID, entryID), req, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// DeleteTimeEntry deletes a time entry of a task
+func (c *Client) DeleteTimeEntry(ctx context.Context, taskID, entryID uint) error {
+	return c.Do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/tasks/%d/time/%d", taskID, entryID), nil, nil)
+}